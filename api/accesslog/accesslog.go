@@ -0,0 +1,105 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package accesslog logs a sample of the node's API requests, with who made
+// them and how they were handled.
+package accesslog
+
+import (
+	"math/rand"
+	"net"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/ava-labs/avalanchego/api/server"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+// maxPeekedBodySize is the maximum size of a request body that is parsed for
+// the name of the JSON-RPC method it calls.
+const maxPeekedBodySize = 64 * 1024
+
+var (
+	_ server.Wrapper = (*accessLogger)(nil)
+)
+
+type accessLogger struct {
+	log    logging.Logger
+	config Config
+
+	// sample returns true if a request should be logged.
+	sample func() bool
+}
+
+// New returns a wrapper that logs a sample of the requests handled by the
+// wrapped handler to [log], once they are handled.
+func New(log logging.Logger, config Config) (server.Wrapper, error) {
+	if err := config.Verify(); err != nil {
+		return nil, err
+	}
+	return &accessLogger{
+		log:    log,
+		config: config,
+		sample: func() bool {
+			return rand.Float64()*100 < config.Percentage // #nosec G404
+		},
+	}, nil
+}
+
+func (a *accessLogger) WrapHandler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if a.isExcluded(r.URL.Path) || !a.sample() {
+			h.ServeHTTP(w, r)
+			return
+		}
+
+		rpcMethod := peekRPCMethod(r)
+		recorder := server.NewStatusRecorder(w)
+		start := time.Now()
+		h.ServeHTTP(recorder, r)
+		latency := time.Since(start)
+
+		sourceIP, _, err := net.SplitHostPort(r.RemoteAddr)
+		if err != nil {
+			sourceIP = r.RemoteAddr
+		}
+		a.log.Info("API request",
+			zap.String("path", r.URL.Path),
+			zap.String("method", r.Method),
+			zap.String("rpcMethod", rpcMethod),
+			zap.Int("status", recorder.StatusCode()),
+			zap.Duration("latency", latency),
+			zap.String("sourceIP", sourceIP),
+		)
+	})
+}
+
+func (a *accessLogger) isExcluded(requestPath string) bool {
+	requestPath = path.Clean(requestPath)
+	for _, excluded := range a.config.ExcludedPaths {
+		excluded = path.Clean(excluded)
+		if requestPath == excluded || strings.HasPrefix(requestPath, strings.TrimSuffix(excluded, "/")+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// peekRPCMethod returns the name of the JSON-RPC method called by [r], or an
+// empty string if [r] doesn't call a single JSON-RPC method. The body of [r]
+// isn't consumed.
+func peekRPCMethod(r *http.Request) string {
+	if r.Method != http.MethodPost {
+		return ""
+	}
+	body, ok := server.PeekBody(r, maxPeekedBodySize)
+	if !ok {
+		return ""
+	}
+	method, _ := server.RPCMethod(body)
+	return method
+}
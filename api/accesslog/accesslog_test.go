@@ -0,0 +1,143 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package accesslog
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+type nopCloser struct {
+	*bytes.Buffer
+}
+
+func (nopCloser) Close() error {
+	return nil
+}
+
+// newTestAccessLogger returns an access logger whose entries are written as
+// JSON to the returned buffer.
+func newTestAccessLogger(t *testing.T, config Config) (*accessLogger, *bytes.Buffer) {
+	buf := &bytes.Buffer{}
+	log := logging.NewLogger("", logging.NewWrappedCore(logging.Verbo, nopCloser{buf}, logging.JSON.FileEncoder()))
+	a, err := New(log, config)
+	require.NoError(t, err)
+	return a.(*accessLogger), buf
+}
+
+func readEntries(t *testing.T, buf *bytes.Buffer) []map[string]interface{} {
+	entries := []map[string]interface{}{}
+	if buf.Len() == 0 {
+		return entries
+	}
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		entry := map[string]interface{}{}
+		require.NoError(t, json.Unmarshal([]byte(line), &entry))
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func TestAccessLog(t *testing.T) {
+	require := require.New(t)
+
+	a, buf := newTestAccessLogger(t, Config{
+		Enabled:       true,
+		Percentage:    100,
+		ExcludedPaths: []string{"/ext/health"},
+	})
+	handler := a.WrapHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The wrapped handler sees the full body.
+		body, err := io.ReadAll(r.Body)
+		require.NoError(err)
+		require.Equal(`{"method":"avm.issueTx"}`, string(body))
+		w.WriteHeader(http.StatusAccepted)
+	}))
+
+	r := httptest.NewRequest(http.MethodPost, "/ext/bc/X", strings.NewReader(`{"method":"avm.issueTx"}`))
+	r.RemoteAddr = "10.0.0.1:1000"
+	handler.ServeHTTP(httptest.NewRecorder(), r)
+
+	entries := readEntries(t, buf)
+	require.Len(entries, 1)
+	entry := entries[0]
+	require.Equal("/ext/bc/X", entry["path"])
+	require.Equal(http.MethodPost, entry["method"])
+	require.Equal("avm.issueTx", entry["rpcMethod"])
+	require.Equal(float64(http.StatusAccepted), entry["status"])
+	require.Equal("10.0.0.1", entry["sourceIP"])
+	require.Contains(entry, "latency")
+
+	// Requests to excluded paths aren't logged.
+	r = httptest.NewRequest(http.MethodPost, "/ext/health/liveness", strings.NewReader(`{"method":"avm.issueTx"}`))
+	handler.ServeHTTP(httptest.NewRecorder(), r)
+	require.Len(readEntries(t, buf), 1)
+}
+
+func TestAccessLogSampling(t *testing.T) {
+	require := require.New(t)
+
+	a, buf := newTestAccessLogger(t, Config{
+		Enabled: true,
+	})
+	handler := a.WrapHandler(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	for i := 0; i < 10; i++ {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/ext/info", nil))
+	}
+	require.Empty(readEntries(t, buf))
+}
+
+func TestConfigVerify(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      Config
+		expectedErr error
+	}{
+		{
+			name: "disabled",
+			config: Config{
+				Percentage: -1,
+			},
+		},
+		{
+			name: "valid",
+			config: Config{
+				Enabled:       true,
+				Percentage:    10,
+				ExcludedPaths: []string{"/ext/health"},
+			},
+		},
+		{
+			name: "invalid percentage",
+			config: Config{
+				Enabled:    true,
+				Percentage: 101,
+			},
+			expectedErr: errInvalidPercentage,
+		},
+		{
+			name: "relative excluded path",
+			config: Config{
+				Enabled:       true,
+				Percentage:    10,
+				ExcludedPaths: []string{"ext/health"},
+			},
+			expectedErr: errInvalidExcludedPath,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.ErrorIs(t, test.config.Verify(), test.expectedErr)
+		})
+	}
+}
@@ -0,0 +1,43 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package accesslog
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	errInvalidPercentage   = errors.New("percentage must be in [0, 100]")
+	errInvalidExcludedPath = errors.New("excluded path must be an absolute path")
+)
+
+type Config struct {
+	// Enabled is true if API requests are logged.
+	Enabled bool `json:"enabled"`
+
+	// Percentage of the requests, in [0, 100], that are logged.
+	Percentage float64 `json:"percentage"`
+
+	// ExcludedPaths are the base paths, such as "/ext/health", of the APIs
+	// whose requests are never logged.
+	ExcludedPaths []string `json:"excludedPaths"`
+}
+
+func (c Config) Verify() error {
+	if !c.Enabled {
+		return nil
+	}
+
+	if c.Percentage < 0 || c.Percentage > 100 {
+		return fmt.Errorf("%w: %f", errInvalidPercentage, c.Percentage)
+	}
+	for _, path := range c.ExcludedPaths {
+		if !strings.HasPrefix(path, "/") {
+			return fmt.Errorf("%w: %q", errInvalidExcludedPath, path)
+		}
+	}
+	return nil
+}
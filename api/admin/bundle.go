@@ -0,0 +1,489 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package admin
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	stdjson "encoding/json"
+
+	"github.com/prometheus/common/expfmt"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
+	"github.com/ava-labs/avalanchego/utils/json"
+	"github.com/ava-labs/avalanchego/utils/perms"
+	"github.com/ava-labs/avalanchego/utils/units"
+	"github.com/ava-labs/avalanchego/version"
+)
+
+const (
+	// Prefix of the name of the files that support bundles are written to
+	supportBundleFilePrefix = "support-bundle-"
+
+	// maxLogBytes is the maximum number of bytes included from the end of each
+	// log file
+	maxLogBytes = 4 * units.MiB
+
+	redacted = "[redacted]"
+)
+
+var (
+	errNotAvailable = errors.New("not available")
+
+	// Values of config fields whose name contains one of these words are
+	// redacted from support bundles...
+	sensitiveWords = map[string]struct{}{
+		"content":  {},
+		"key":      {},
+		"password": {},
+		"secret":   {},
+		"seed":     {},
+		"token":    {},
+	}
+	// ...unless the name ends with one of these words, as the field then
+	// refers to where the secret is stored.
+	referenceWords = map[string]struct{}{
+		"dir":  {},
+		"file": {},
+		"path": {},
+	}
+)
+
+// bundleManifest describes a support bundle
+type bundleManifest struct {
+	Version   string    `json:"version"`
+	CreatedAt time.Time `json:"createdAt"`
+	// Errors maps the sections of the bundle that couldn't be collected to
+	// the reason why
+	Errors map[string]string `json:"errors,omitempty"`
+}
+
+type bundleConfig struct {
+	Config        interface{} `json:"config"`
+	ProvidedFlags interface{} `json:"providedFlags"`
+}
+
+type bundleHealth struct {
+	Readiness interface{} `json:"readiness"`
+	Health    interface{} `json:"health"`
+	Liveness  interface{} `json:"liveness"`
+	History   interface{} `json:"history"`
+}
+
+// ChainState is the state of a chain included in support bundles
+type ChainState struct {
+	ID           ids.ID   `json:"id"`
+	Name         string   `json:"name"`
+	Aliases      []string `json:"aliases"`
+	SubnetID     ids.ID   `json:"subnetID"`
+	State        string   `json:"state"`
+	Bootstrapped bool     `json:"bootstrapped"`
+	// LastAccepted is only reported for linear chains
+	LastAccepted       *ids.ID      `json:"lastAccepted,omitempty"`
+	LastAcceptedHeight *json.Uint64 `json:"lastAcceptedHeight,omitempty"`
+}
+
+type bundleDB struct {
+	Path        string      `json:"path"`
+	SizeOnDisk  uint64      `json:"sizeOnDisk"`
+	Health      interface{} `json:"health"`
+	HealthError string      `json:"healthError,omitempty"`
+}
+
+// bundleWriter writes the sections of a support bundle into a tar archive.
+// Sections that can't be collected are recorded in the manifest rather than
+// failing the whole bundle.
+type bundleWriter struct {
+	tw       *tar.Writer
+	now      time.Time
+	manifest bundleManifest
+}
+
+func (b *bundleWriter) addFile(name string, contents []byte) error {
+	if err := b.tw.WriteHeader(&tar.Header{
+		Name:    name,
+		Mode:    perms.ReadWrite,
+		Size:    int64(len(contents)),
+		ModTime: b.now,
+	}); err != nil {
+		return err
+	}
+	_, err := b.tw.Write(contents)
+	return err
+}
+
+// addJSON adds [name] to the bundle, or records why it couldn't be collected.
+// Only errors writing the archive are returned.
+func (b *bundleWriter) addJSON(name string, collect func() (interface{}, error)) error {
+	v, err := collect()
+	if err != nil {
+		b.manifest.Errors[name] = err.Error()
+		return nil
+	}
+	contents, err := stdjson.MarshalIndent(v, "", "  ")
+	if err != nil {
+		b.manifest.Errors[name] = err.Error()
+		return nil
+	}
+	return b.addFile(name, contents)
+}
+
+// writeSupportBundle writes a gzipped tar archive describing the node into a
+// new file in [service.ProfileDir] and returns the path of the file.
+func (service *Admin) writeSupportBundle(ctx context.Context) (string, error) {
+	if err := os.MkdirAll(service.ProfileDir, perms.ReadWriteExecute); err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	bundlePath := filepath.Join(
+		service.ProfileDir,
+		fmt.Sprintf("%s%d.tar.gz", supportBundleFilePrefix, now.Unix()),
+	)
+	file, err := perms.Create(bundlePath, perms.ReadWrite)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	zw := gzip.NewWriter(file)
+	b := &bundleWriter{
+		tw:  tar.NewWriter(zw),
+		now: now,
+		manifest: bundleManifest{
+			Version:   version.CurrentApp.String(),
+			CreatedAt: now,
+			Errors:    make(map[string]string),
+		},
+	}
+	if err := service.writeSupportBundleSections(ctx, b); err != nil {
+		return "", err
+	}
+	if err := b.addJSON("manifest.json", func() (interface{}, error) {
+		return b.manifest, nil
+	}); err != nil {
+		return "", err
+	}
+	if err := b.tw.Close(); err != nil {
+		return "", err
+	}
+	if err := zw.Close(); err != nil {
+		return "", err
+	}
+	return bundlePath, file.Close()
+}
+
+func (service *Admin) writeSupportBundleSections(ctx context.Context, b *bundleWriter) error {
+	if err := b.addJSON("config.json", service.bundleConfig); err != nil {
+		return err
+	}
+	if err := b.addJSON("health.json", service.bundleHealth); err != nil {
+		return err
+	}
+	if err := b.addJSON("peers.json", service.bundlePeers); err != nil {
+		return err
+	}
+	if err := b.addJSON("chains.json", func() (interface{}, error) {
+		return service.bundleChains(ctx), nil
+	}); err != nil {
+		return err
+	}
+	if err := b.addJSON("db.json", func() (interface{}, error) {
+		return service.bundleDB(ctx)
+	}); err != nil {
+		return err
+	}
+	if err := service.addBundleMetrics(b); err != nil {
+		return err
+	}
+	return service.addBundleLogs(b)
+}
+
+func (service *Admin) bundleConfig() (interface{}, error) {
+	config, err := redact(service.NodeConfig)
+	if err != nil {
+		return nil, err
+	}
+	providedFlags, err := redact(service.ProvidedFlags)
+	return bundleConfig{
+		Config:        config,
+		ProvidedFlags: providedFlags,
+	}, err
+}
+
+func (service *Admin) bundleHealth() (interface{}, error) {
+	if service.Health == nil {
+		return nil, errNotAvailable
+	}
+	readiness, _ := service.Health.Readiness()
+	health, _ := service.Health.Health()
+	liveness, _ := service.Health.Liveness()
+	return bundleHealth{
+		Readiness: readiness,
+		Health:    health,
+		Liveness:  liveness,
+		History:   service.Health.History(),
+	}, nil
+}
+
+func (service *Admin) bundlePeers() (interface{}, error) {
+	if service.Network == nil {
+		return nil, errNotAvailable
+	}
+	return service.Network.PeerInfo(nil), nil
+}
+
+func (service *Admin) bundleChains(ctx context.Context) []ChainState {
+	service.chainsLock.RLock()
+	defer service.chainsLock.RUnlock()
+
+	states := make([]ChainState, 0, len(service.chains))
+	for chainID, chain := range service.chains {
+		engine := chain.engine
+		snowCtx := engine.Context()
+		state := ChainState{
+			ID:       chainID,
+			Name:     chain.name,
+			SubnetID: snowCtx.SubnetID,
+			State:    snowCtx.GetState().String(),
+		}
+		if service.ChainManager != nil {
+			state.Aliases, _ = service.ChainManager.Aliases(chainID)
+			state.Bootstrapped = service.ChainManager.IsBootstrapped(chainID)
+		}
+		if vm, ok := engine.GetVM().(block.ChainVM); ok {
+			snowCtx.Lock.Lock()
+			if lastAcceptedID, err := vm.LastAccepted(ctx); err == nil {
+				state.LastAccepted = &lastAcceptedID
+				if blk, err := vm.GetBlock(ctx, lastAcceptedID); err == nil {
+					height := json.Uint64(blk.Height())
+					state.LastAcceptedHeight = &height
+				}
+			}
+			snowCtx.Lock.Unlock()
+		}
+		states = append(states, state)
+	}
+	sort.Slice(states, func(i, j int) bool {
+		return states[i].Name < states[j].Name
+	})
+	return states
+}
+
+func (service *Admin) bundleDB(ctx context.Context) (interface{}, error) {
+	if service.DB == nil {
+		return nil, errNotAvailable
+	}
+
+	db := bundleDB{
+		Path: service.DBDir,
+	}
+	if len(service.DBDir) > 0 {
+		err := filepath.WalkDir(service.DBDir, func(_ string, d fs.DirEntry, err error) error {
+			if err != nil || !d.Type().IsRegular() {
+				return err
+			}
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			db.SizeOnDisk += uint64(info.Size())
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	health, err := service.DB.HealthCheck(ctx)
+	db.Health = health
+	if err != nil {
+		db.HealthError = err.Error()
+	}
+	return db, nil
+}
+
+func (service *Admin) addBundleMetrics(b *bundleWriter) error {
+	const name = "metrics.txt"
+	if service.MetricsGatherer == nil {
+		b.manifest.Errors[name] = errNotAvailable.Error()
+		return nil
+	}
+
+	// Gathering may partially fail, in which case the metrics that were
+	// gathered are still included.
+	metricFamilies, err := service.MetricsGatherer.Gather()
+	if err != nil {
+		b.manifest.Errors[name] = err.Error()
+	}
+	var metrics bytes.Buffer
+	for _, metricFamily := range metricFamilies {
+		if _, err := expfmt.MetricFamilyToText(&metrics, metricFamily); err != nil {
+			b.manifest.Errors[name] = err.Error()
+			return nil
+		}
+	}
+	return b.addFile(name, metrics.Bytes())
+}
+
+// addBundleLogs adds the end of every uncompressed log file to the bundle.
+func (service *Admin) addBundleLogs(b *bundleWriter) error {
+	const section = "logs"
+	if len(service.LogDir) == 0 {
+		b.manifest.Errors[section] = errNotAvailable.Error()
+		return nil
+	}
+
+	entries, err := os.ReadDir(service.LogDir)
+	if err != nil {
+		b.manifest.Errors[section] = err.Error()
+		return nil
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		if !entry.Type().IsRegular() || filepath.Ext(name) != ".log" {
+			continue
+		}
+		if err := service.addBundleLog(b, section, name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (service *Admin) addBundleLog(b *bundleWriter, section string, name string) error {
+	bundleName := path.Join(section, name)
+	file, err := os.Open(filepath.Join(service.LogDir, name))
+	if err != nil {
+		b.manifest.Errors[bundleName] = err.Error()
+		return nil
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		b.manifest.Errors[bundleName] = err.Error()
+		return nil
+	}
+	// The file may grow while it is being read, so only the bytes that existed
+	// when it was opened are included.
+	size := info.Size()
+	if size > maxLogBytes {
+		if _, err := file.Seek(size-maxLogBytes, io.SeekStart); err != nil {
+			b.manifest.Errors[bundleName] = err.Error()
+			return nil
+		}
+		size = maxLogBytes
+	}
+	// The file may also be truncated while it is being read, such as when it
+	// is rotated, so the bytes are read before the size of the entry is
+	// written.
+	contents, err := io.ReadAll(io.LimitReader(file, size))
+	if err != nil {
+		b.manifest.Errors[bundleName] = err.Error()
+		return nil
+	}
+	return b.addFile(bundleName, contents)
+}
+
+// redact returns the JSON representation of [v] with the values of sensitive
+// fields replaced.
+func redact(v interface{}) (interface{}, error) {
+	b, err := stdjson.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var parsed interface{}
+	if err := stdjson.Unmarshal(b, &parsed); err != nil {
+		return nil, err
+	}
+	return redactValue(parsed), nil
+}
+
+func redactValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			// Flags, such as whether a secret is required, are never
+			// sensitive.
+			if _, isBool := value.(bool); !isBool && value != nil && isSensitive(key) {
+				v[key] = redacted
+				continue
+			}
+			v[key] = redactValue(value)
+		}
+	case []interface{}:
+		for i, value := range v {
+			v[i] = redactValue(value)
+		}
+	}
+	return v
+}
+
+// isSensitive returns true if the value of the config field [name] may contain
+// a secret. Both kebab-case flag names and camelCase field names are supported.
+func isSensitive(name string) bool {
+	words := splitWords(name)
+	if len(words) == 0 {
+		return false
+	}
+	if _, ok := referenceWords[words[len(words)-1]]; ok {
+		return false
+	}
+	for _, word := range words {
+		if _, ok := sensitiveWords[word]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// splitWords returns the lowercase words of [name], which are delimited by
+// non-alphanumeric characters and by the start of an uppercase word.
+func splitWords(name string) []string {
+	var (
+		words []string
+		word  strings.Builder
+	)
+	flush := func() {
+		if word.Len() > 0 {
+			words = append(words, word.String())
+			word.Reset()
+		}
+	}
+	prevLower := false
+	for _, r := range name {
+		switch {
+		case !unicode.IsLetter(r) && !unicode.IsDigit(r):
+			flush()
+			prevLower = false
+		case unicode.IsUpper(r):
+			if prevLower {
+				flush()
+			}
+			word.WriteRune(unicode.ToLower(r))
+			prevLower = false
+		default:
+			word.WriteRune(r)
+			prevLower = true
+		}
+	}
+	flush()
+	return words
+}
@@ -0,0 +1,173 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package admin
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	stdjson "encoding/json"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/api/health"
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+// readBundle returns the contents of the files in the support bundle at
+// [path].
+func readBundle(t *testing.T, path string) map[string][]byte {
+	require := require.New(t)
+
+	file, err := os.Open(path)
+	require.NoError(err)
+	defer file.Close()
+
+	zr, err := gzip.NewReader(file)
+	require.NoError(err)
+
+	files := make(map[string][]byte)
+	tr := tar.NewReader(zr)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return files
+		}
+		require.NoError(err)
+
+		contents, err := io.ReadAll(tr)
+		require.NoError(err)
+		files[header.Name] = contents
+	}
+}
+
+func TestSupportBundle(t *testing.T) {
+	require := require.New(t)
+
+	logDir := t.TempDir()
+	require.NoError(os.WriteFile(filepath.Join(logDir, "main.log"), []byte("started\n"), 0o600))
+	require.NoError(os.WriteFile(filepath.Join(logDir, "main.log.1.gz"), []byte{0}, 0o600))
+
+	registry := prometheus.NewRegistry()
+	counter := prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "test_counter",
+		Help: "counter used by the test",
+	})
+	require.NoError(registry.Register(counter))
+
+	healthReporter, err := health.New(logging.NoLog{}, prometheus.NewRegistry())
+	require.NoError(err)
+
+	admin := &Admin{
+		Config: Config{
+			Log:        logging.NoLog{},
+			ProfileDir: t.TempDir(),
+			NodeConfig: map[string]interface{}{
+				"apiRequireAuthToken": true,
+				"stakingKeyPath":      "/staking.key",
+			},
+			ProvidedFlags: map[string]interface{}{
+				"api-auth-password":            "hunter2",
+				"staking-tls-key-file-content": "c2VjcmV0",
+				"network-id":                   "local",
+			},
+			Health:          healthReporter,
+			MetricsGatherer: registry,
+			DB:              memdb.New(),
+			LogDir:          logDir,
+		},
+		chains: make(map[ids.ID]registeredChain),
+	}
+
+	lastAccepted := &snowman.TestBlock{HeightV: 7}
+	lastAccepted.IDV = ids.GenerateTestID()
+	vm := &block.TestVM{
+		TestVM: common.TestVM{T: t},
+		LastAcceptedF: func(context.Context) (ids.ID, error) {
+			return lastAccepted.IDV, nil
+		},
+		GetBlockF: func(context.Context, ids.ID) (snowman.Block, error) {
+			return lastAccepted, nil
+		},
+	}
+	ctx := snow.DefaultConsensusContextTest()
+	ctx.ChainID = ids.GenerateTestID()
+	ctx.SetState(snow.NormalOp)
+	admin.RegisterChain("C", &common.EngineTest{
+		T: t,
+		ContextF: func() *snow.ConsensusContext {
+			return ctx
+		},
+		GetVMF: func() common.VM {
+			return vm
+		},
+	})
+
+	reply := &SupportBundleReply{}
+	require.NoError(admin.SupportBundle(&http.Request{}, nil, reply))
+	require.True(strings.HasPrefix(reply.Path, admin.ProfileDir))
+
+	files := readBundle(t, reply.Path)
+
+	config := string(files["config.json"])
+	require.NotContains(config, "hunter2")
+	require.NotContains(config, "c2VjcmV0")
+	require.Contains(config, "/staking.key")
+	require.Contains(config, "local")
+
+	require.Contains(string(files["metrics.txt"]), "test_counter 0")
+	require.Equal("started\n", string(files["logs/main.log"]))
+	require.NotContains(files, "logs/main.log.1.gz")
+	require.Contains(files, "health.json")
+	require.Contains(files, "db.json")
+
+	var chainStates []ChainState
+	require.NoError(stdjson.Unmarshal(files["chains.json"], &chainStates))
+	require.Len(chainStates, 1)
+	require.Equal(ctx.ChainID, chainStates[0].ID)
+	require.Equal("C", chainStates[0].Name)
+	require.Equal(snow.State(snow.NormalOp).String(), chainStates[0].State)
+	require.Equal(lastAccepted.IDV, *chainStates[0].LastAccepted)
+	require.EqualValues(7, *chainStates[0].LastAcceptedHeight)
+
+	// The network wasn't provided, so the peers couldn't be collected.
+	manifest := bundleManifest{}
+	require.NoError(stdjson.Unmarshal(files["manifest.json"], &manifest))
+	require.Contains(manifest.Errors, "peers.json")
+	require.NotContains(files, "peers.json")
+}
+
+func TestIsSensitive(t *testing.T) {
+	tests := map[string]bool{
+		"api-auth-password":            true,
+		"api-auth-password-file":       false,
+		"staking-tls-key-file-content": true,
+		"staking-tls-key-file":         false,
+		"staking-seed":                 true,
+		"stakingKeyPath":               false,
+		"stakingSigningKey":            true,
+		"api-keystore-enabled":         false,
+		"network-id":                   false,
+	}
+	for name, expected := range tests {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, expected, isSensitive(name))
+		})
+	}
+}
@@ -6,10 +6,13 @@ package admin
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/ava-labs/avalanchego/api"
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
 	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/maintenance"
 	"github.com/ava-labs/avalanchego/utils/rpc"
 )
 
@@ -26,9 +29,19 @@ type Client interface {
 	GetChainAliases(ctx context.Context, chainID string, options ...rpc.Option) ([]string, error)
 	Stacktrace(context.Context, ...rpc.Option) error
 	LoadVMs(context.Context, ...rpc.Option) (map[ids.ID][]string, map[ids.ID]string, error)
+	InstallVM(ctx context.Context, args *InstallVMArgs, options ...rpc.Option) error
+	UpgradeVMPlugin(ctx context.Context, chain string, plugin string, options ...rpc.Option) error
 	SetLoggerLevel(ctx context.Context, loggerName, logLevel, displayLevel string, options ...rpc.Option) error
 	GetLoggerLevel(ctx context.Context, loggerName string, options ...rpc.Option) (map[string]LogAndDisplayLevels, error)
 	GetConfig(ctx context.Context, options ...rpc.Option) (interface{}, error)
+	GetConfigOverrides(ctx context.Context, options ...rpc.Option) ([]ConfigOverride, error)
+	SupportBundle(ctx context.Context, options ...rpc.Option) (string, error)
+	Incidents(ctx context.Context, chain string, options ...rpc.Option) ([]common.Incident, error)
+	ScheduleMaintenance(ctx context.Context, window maintenance.Window, options ...rpc.Option) error
+	CancelMaintenance(ctx context.Context, start time.Time, options ...rpc.Option) error
+	GetMaintenanceWindows(ctx context.Context, options ...rpc.Option) ([]maintenance.Window, error)
+	SetAllowedOrigins(ctx context.Context, allowedOrigins []string, options ...rpc.Option) error
+	GetAllowedOrigins(ctx context.Context, options ...rpc.Option) ([]string, error)
 }
 
 // Client implementation for the Avalanche Platform Info API Endpoint
@@ -91,6 +104,17 @@ func (c *client) LoadVMs(ctx context.Context, options ...rpc.Option) (map[ids.ID
 	return res.NewVMs, res.FailedVMs, err
 }
 
+func (c *client) InstallVM(ctx context.Context, args *InstallVMArgs, options ...rpc.Option) error {
+	return c.requester.SendRequest(ctx, "admin.installVM", args, &api.EmptyReply{}, options...)
+}
+
+func (c *client) UpgradeVMPlugin(ctx context.Context, chain string, plugin string, options ...rpc.Option) error {
+	return c.requester.SendRequest(ctx, "admin.upgradeVMPlugin", &UpgradeVMPluginArgs{
+		Chain:  chain,
+		Plugin: plugin,
+	}, &api.EmptyReply{}, options...)
+}
+
 func (c *client) SetLoggerLevel(
 	ctx context.Context,
 	loggerName,
@@ -139,3 +163,53 @@ func (c *client) GetConfig(ctx context.Context, options ...rpc.Option) (interfac
 	err := c.requester.SendRequest(ctx, "admin.getConfig", struct{}{}, &res, options...)
 	return res, err
 }
+
+func (c *client) GetConfigOverrides(ctx context.Context, options ...rpc.Option) ([]ConfigOverride, error) {
+	res := &GetConfigOverridesReply{}
+	err := c.requester.SendRequest(ctx, "admin.getConfigOverrides", struct{}{}, res, options...)
+	return res.Overrides, err
+}
+
+func (c *client) SupportBundle(ctx context.Context, options ...rpc.Option) (string, error) {
+	res := &SupportBundleReply{}
+	err := c.requester.SendRequest(ctx, "admin.supportBundle", struct{}{}, res, options...)
+	return res.Path, err
+}
+
+func (c *client) Incidents(ctx context.Context, chain string, options ...rpc.Option) ([]common.Incident, error) {
+	res := &IncidentsReply{}
+	err := c.requester.SendRequest(ctx, "admin.incidents", &IncidentsArgs{
+		Chain: chain,
+	}, res, options...)
+	return res.Incidents, err
+}
+
+func (c *client) ScheduleMaintenance(ctx context.Context, window maintenance.Window, options ...rpc.Option) error {
+	return c.requester.SendRequest(ctx, "admin.scheduleMaintenance", &ScheduleMaintenanceArgs{
+		Window: window,
+	}, &api.EmptyReply{}, options...)
+}
+
+func (c *client) CancelMaintenance(ctx context.Context, start time.Time, options ...rpc.Option) error {
+	return c.requester.SendRequest(ctx, "admin.cancelMaintenance", &CancelMaintenanceArgs{
+		Start: start,
+	}, &api.EmptyReply{}, options...)
+}
+
+func (c *client) GetMaintenanceWindows(ctx context.Context, options ...rpc.Option) ([]maintenance.Window, error) {
+	res := &GetMaintenanceWindowsReply{}
+	err := c.requester.SendRequest(ctx, "admin.getMaintenanceWindows", struct{}{}, res, options...)
+	return res.Windows, err
+}
+
+func (c *client) SetAllowedOrigins(ctx context.Context, allowedOrigins []string, options ...rpc.Option) error {
+	return c.requester.SendRequest(ctx, "admin.setAllowedOrigins", &SetAllowedOriginsArgs{
+		AllowedOrigins: allowedOrigins,
+	}, &api.EmptyReply{}, options...)
+}
+
+func (c *client) GetAllowedOrigins(ctx context.Context, options ...rpc.Option) ([]string, error) {
+	res := &GetAllowedOriginsReply{}
+	err := c.requester.SendRequest(ctx, "admin.getAllowedOrigins", struct{}{}, res, options...)
+	return res.AllowedOrigins, err
+}
@@ -67,6 +67,12 @@ func (mc *mockClient) SendRequest(_ context.Context, _ string, _ interface{}, re
 	case *interface{}:
 		response := mc.response.(*interface{})
 		*p = *response
+	case *SupportBundleReply:
+		response := mc.response.(*SupportBundleReply)
+		*p = *response
+	case *IncidentsReply:
+		response := mc.response.(*IncidentsReply)
+		*p = *response
 	default:
 		panic("illegal type")
 	}
@@ -4,23 +4,39 @@
 package admin
 
 import (
+	"context"
+	"encoding/hex"
 	"errors"
+	"fmt"
+	"io"
 	"net/http"
+	"net/url"
+	"os"
 	"path"
+	"path/filepath"
+	"sync"
+	"time"
 
 	"github.com/gorilla/rpc/v2"
 
+	"github.com/prometheus/client_golang/prometheus"
+
 	"go.uber.org/zap"
 
 	"github.com/ava-labs/avalanchego/api"
+	"github.com/ava-labs/avalanchego/api/health"
 	"github.com/ava-labs/avalanchego/api/server"
 	"github.com/ava-labs/avalanchego/chains"
+	"github.com/ava-labs/avalanchego/database"
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/network"
 	"github.com/ava-labs/avalanchego/snow/engine/common"
 	"github.com/ava-labs/avalanchego/utils"
 	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/hashing"
 	"github.com/ava-labs/avalanchego/utils/json"
 	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/maintenance"
 	"github.com/ava-labs/avalanchego/utils/perms"
 	"github.com/ava-labs/avalanchego/utils/profiler"
 	"github.com/ava-labs/avalanchego/vms"
@@ -32,11 +48,23 @@ const (
 
 	// Name of file that stacktraces are written to
 	stacktraceFile = "stacktrace.txt"
+
+	// Maximum amount of time spent downloading a plugin binary
+	installVMDownloadTimeout = 10 * time.Minute
 )
 
 var (
 	errAliasTooLong = errors.New("alias length is too long")
 	errNoLogLevel   = errors.New("need to specify either displayLevel or logLevel")
+	errNoScheduler  = errors.New("maintenance scheduling is not enabled")
+	errBadPlugin    = errors.New("plugin must be the name of a file in the plugin directory")
+	errBadSource    = errors.New("exactly one of url and path must be provided")
+	errBadScheme    = errors.New("url must use http or https")
+	errBadChecksum  = errors.New("sha256 must be a hex encoded SHA256 hash")
+	errDownload     = errors.New("failed to download plugin")
+	errNoCORS       = errors.New("allowed origins can't be updated")
+
+	_ chains.Registrant = (*Admin)(nil)
 )
 
 type Config struct {
@@ -48,12 +76,42 @@ type Config struct {
 	HTTPServer   server.PathAdderWithReadLock
 	VMRegistry   registry.VMRegistry
 	VMManager    vms.Manager
+	// Directory the plugins chains can be upgraded to are read from
+	PluginDir string
+
+	// Sources of the support bundle
+	ProvidedFlags   map[string]interface{}
+	ConfigOverrides []ConfigOverride
+	Health          health.Reporter
+	Network         network.Network
+	MetricsGatherer prometheus.Gatherer
+	DB              database.Database
+	DBDir           string
+	LogDir          string
+
+	// Records the staleness incidents of the chains
+	Incidents common.IncidentLog
+
+	// Schedules the maintenance windows of the node
+	Maintenance maintenance.Scheduler
+
+	// Updates the origins the APIs can be requested from
+	CORS server.CORS
 }
 
 // Admin is the API service for node admin management
 type Admin struct {
 	Config
 	profiler profiler.Profiler
+
+	chainsLock sync.RWMutex
+	// Chain ID --> chain
+	chains map[ids.ID]registeredChain
+}
+
+type registeredChain struct {
+	name   string
+	engine common.Engine
 }
 
 // NewService returns a new admin API service.
@@ -63,13 +121,29 @@ func NewService(config Config) (*common.HTTPHandler, error) {
 	codec := json.NewCodec()
 	newServer.RegisterCodec(codec, "application/json")
 	newServer.RegisterCodec(codec, "application/json;charset=UTF-8")
-	if err := newServer.RegisterService(&Admin{
+	admin := &Admin{
 		Config:   config,
 		profiler: profiler.New(config.ProfileDir),
-	}, "admin"); err != nil {
+		chains:   make(map[ids.ID]registeredChain),
+	}
+	if err := newServer.RegisterService(admin, "admin"); err != nil {
 		return nil, err
 	}
-	return &common.HTTPHandler{Handler: newServer}, nil
+	config.ChainManager.AddRegistrant(admin)
+	return &common.HTTPHandler{
+		Handler: newServer,
+		OpenAPI: json.OpenAPIPathItem("Admin API", "admin", admin),
+	}, nil
+}
+
+func (service *Admin) RegisterChain(chainName string, engine common.Engine) {
+	service.chainsLock.Lock()
+	defer service.chainsLock.Unlock()
+
+	service.chains[engine.Context().ChainID] = registeredChain{
+		name:   chainName,
+		engine: engine,
+	}
 }
 
 // StartCPUProfiler starts a cpu profile writing to the specified file
@@ -286,6 +360,54 @@ func (service *Admin) GetConfig(_ *http.Request, _ *struct{}, reply *interface{}
 	return nil
 }
 
+// Sources a config value can be provided by
+const (
+	ConfigSourceFlag = "flag"
+	ConfigSourceEnv  = "env"
+	// The config file, or the content provided in place of the config file
+	ConfigSourceFile = "file"
+)
+
+// ConfigOverride is a config value that differs from its default
+type ConfigOverride struct {
+	// Key is the name of the flag the value is provided for
+	Key string `json:"key"`
+	// Value is the provided value. Values that may contain secrets are
+	// redacted.
+	Value interface{} `json:"value"`
+	// Default is the value used if the flag isn't provided
+	Default string `json:"default"`
+	// Source is where the value was provided
+	Source string `json:"source"`
+}
+
+// GetConfigOverridesReply is the response from GetConfigOverrides
+type GetConfigOverridesReply struct {
+	// Sorted by key
+	Overrides []ConfigOverride `json:"overrides"`
+}
+
+// GetConfigOverrides returns the config values the node was started with that
+// differ from their defaults, along with where each of them was provided.
+func (service *Admin) GetConfigOverrides(_ *http.Request, _ *struct{}, reply *GetConfigOverridesReply) error {
+	service.Log.Debug("Admin: GetConfigOverrides called")
+
+	reply.Overrides = make([]ConfigOverride, len(service.ConfigOverrides))
+	for i, override := range service.ConfigOverrides {
+		if _, isBool := override.Value.(bool); !isBool && isSensitive(override.Key) {
+			override.Value = redacted
+		} else {
+			value, err := redact(override.Value)
+			if err != nil {
+				return err
+			}
+			override.Value = value
+		}
+		reply.Overrides[i] = override
+	}
+	return nil
+}
+
 // LoadVMsReply contains the response metadata for LoadVMs
 type LoadVMsReply struct {
 	// VMs and their aliases which were successfully loaded
@@ -314,3 +436,276 @@ func (service *Admin) LoadVMs(r *http.Request, _ *struct{}, reply *LoadVMsReply)
 	reply.NewVMs, err = ids.GetRelevantAliases(service.VMManager, loadedVMs)
 	return err
 }
+
+// InstallVMArgs are the arguments for calling InstallVM
+type InstallVMArgs struct {
+	// ID or alias of the VM
+	VM string `json:"vm"`
+	// The plugin binary is downloaded from URL, or read from Path on the
+	// node's file system
+	URL  string `json:"url"`
+	Path string `json:"path"`
+	// Hex encoded SHA256 hash of the plugin binary
+	SHA256 string `json:"sha256"`
+}
+
+// InstallVM installs a plugin binary into the plugin directory, after
+// verifying its checksum, and registers the VM so that chains of the VM can be
+// created without restarting the node.
+func (service *Admin) InstallVM(r *http.Request, args *InstallVMArgs, _ *api.EmptyReply) error {
+	service.Log.Debug("Admin: InstallVM called",
+		logging.UserString("vm", args.VM),
+		logging.UserString("url", args.URL),
+		logging.UserString("path", args.Path),
+	)
+
+	vmID, err := service.VMManager.Lookup(args.VM)
+	if err != nil {
+		vmID, err = ids.FromString(args.VM)
+		if err != nil {
+			return err
+		}
+	}
+	checksumBytes, err := hex.DecodeString(args.SHA256)
+	if err != nil || len(checksumBytes) != hashing.HashLen {
+		return errBadChecksum
+	}
+	var checksum hashing.Hash256
+	copy(checksum[:], checksumBytes)
+
+	ctx := r.Context()
+	var plugin io.ReadCloser
+	switch {
+	case args.URL != "" && args.Path == "":
+		// The plugin is read by the registry before it grabs its lock, so a
+		// slow download only delays this call.
+		downloadCtx, cancel := context.WithTimeout(ctx, installVMDownloadTimeout)
+		defer cancel()
+		plugin, err = download(downloadCtx, args.URL)
+	case args.Path != "" && args.URL == "":
+		plugin, err = os.Open(args.Path)
+	default:
+		err = errBadSource
+	}
+	if err != nil {
+		return err
+	}
+	defer plugin.Close()
+
+	return service.VMRegistry.InstallWithReadLock(ctx, vmID, plugin, checksum)
+}
+
+// download returns the body of the response to a GET request to [rawURL].
+func download(ctx context.Context, rawURL string) (io.ReadCloser, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, errBadScheme
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		_ = resp.Body.Close()
+		return nil, fmt.Errorf("%w: %s", errDownload, resp.Status)
+	}
+	return resp.Body, nil
+}
+
+// UpgradeVMPluginArgs are the arguments for calling UpgradeVMPlugin
+type UpgradeVMPluginArgs struct {
+	Chain string `json:"chain"`
+	// Name of the new plugin binary in the plugin directory
+	Plugin string `json:"plugin"`
+}
+
+// UpgradeVMPlugin replaces the plugin running the VM of a chain with another
+// plugin binary from the plugin directory, without restarting the node. The
+// chain is paused while the new plugin is launched and brought to the state
+// of the previous one. If that fails, the previous plugin is launched again.
+func (service *Admin) UpgradeVMPlugin(r *http.Request, args *UpgradeVMPluginArgs, _ *api.EmptyReply) error {
+	service.Log.Debug("Admin: UpgradeVMPlugin called",
+		logging.UserString("chain", args.Chain),
+		logging.UserString("plugin", args.Plugin),
+	)
+
+	// Only the binaries in the plugin directory can be launched.
+	if args.Plugin == "" || args.Plugin == "." || args.Plugin == ".." || filepath.Base(args.Plugin) != args.Plugin {
+		return errBadPlugin
+	}
+	chainID, err := service.ChainManager.Lookup(args.Chain)
+	if err != nil {
+		return err
+	}
+	pluginPath := filepath.Join(service.PluginDir, args.Plugin)
+	if _, err := os.Stat(pluginPath); err != nil {
+		return err
+	}
+	return service.ChainManager.UpgradeVM(r.Context(), chainID, pluginPath)
+}
+
+// SupportBundleReply is the response from SupportBundle
+type SupportBundleReply struct {
+	// Path of the support bundle on the node's file system
+	Path string `json:"path"`
+}
+
+// SupportBundle writes a gzipped tar archive, meant to be shared when asking
+// for support, into the profile directory. The archive contains the node's
+// config with secrets redacted, the end of the log files, the current results
+// and recent transitions of the health checks, a snapshot of the metrics, the
+// connected peers, the state of the chains, and database statistics.
+func (service *Admin) SupportBundle(r *http.Request, _ *struct{}, reply *SupportBundleReply) error {
+	service.Log.Debug("Admin: SupportBundle called")
+
+	var err error
+	reply.Path, err = service.writeSupportBundle(r.Context())
+	return err
+}
+
+// IncidentsArgs are the arguments for calling Incidents
+type IncidentsArgs struct {
+	// If provided, only the incidents of this chain are returned
+	Chain string `json:"chain"`
+}
+
+// IncidentsReply is the response from Incidents
+type IncidentsReply struct {
+	// From the oldest to the most recent
+	Incidents []common.Incident `json:"incidents"`
+}
+
+// Incidents returns the most recent incidents of the chains, such as chains
+// that became stale and were resynced.
+func (service *Admin) Incidents(_ *http.Request, args *IncidentsArgs, reply *IncidentsReply) error {
+	service.Log.Debug("Admin: Incidents called",
+		logging.UserString("chain", args.Chain),
+	)
+
+	if service.Config.Incidents == nil {
+		reply.Incidents = []common.Incident{}
+		return nil
+	}
+	incidents := service.Config.Incidents.Incidents()
+	if args.Chain == "" {
+		reply.Incidents = incidents
+		return nil
+	}
+
+	chainID, err := service.ChainManager.Lookup(args.Chain)
+	if err != nil {
+		return err
+	}
+	reply.Incidents = make([]common.Incident, 0, len(incidents))
+	for _, incident := range incidents {
+		if incident.ChainID == chainID {
+			reply.Incidents = append(reply.Incidents, incident)
+		}
+	}
+	return nil
+}
+
+// ScheduleMaintenanceArgs are the arguments for calling ScheduleMaintenance
+type ScheduleMaintenanceArgs struct {
+	maintenance.Window
+}
+
+// ScheduleMaintenance schedules a window during which the node is expected to
+// be taken down for planned maintenance. When the window begins, the node
+// stops serving API requests, other than the admin, health, info and metrics
+// APIs, and reports itself as unhealthy.
+func (service *Admin) ScheduleMaintenance(_ *http.Request, args *ScheduleMaintenanceArgs, _ *api.EmptyReply) error {
+	service.Log.Debug("Admin: ScheduleMaintenance called",
+		zap.Time("start", args.Start),
+		zap.Duration("duration", args.Duration),
+		logging.UserString("reason", args.Reason),
+	)
+
+	if service.Maintenance == nil {
+		return errNoScheduler
+	}
+	return service.Maintenance.Schedule(args.Window)
+}
+
+// CancelMaintenanceArgs are the arguments for calling CancelMaintenance
+type CancelMaintenanceArgs struct {
+	// Start time of the window to cancel
+	Start time.Time `json:"start"`
+}
+
+// CancelMaintenance cancels a maintenance window. If the window is in
+// progress, the node resumes serving requests.
+func (service *Admin) CancelMaintenance(_ *http.Request, args *CancelMaintenanceArgs, _ *api.EmptyReply) error {
+	service.Log.Debug("Admin: CancelMaintenance called",
+		zap.Time("start", args.Start),
+	)
+
+	if service.Maintenance == nil {
+		return errNoScheduler
+	}
+	return service.Maintenance.Cancel(args.Start)
+}
+
+// GetMaintenanceWindowsReply is the response from GetMaintenanceWindows
+type GetMaintenanceWindowsReply struct {
+	// Windows that aren't over, by start time
+	Windows []maintenance.Window `json:"windows"`
+}
+
+// GetMaintenanceWindows returns the maintenance windows that aren't over.
+func (service *Admin) GetMaintenanceWindows(_ *http.Request, _ *struct{}, reply *GetMaintenanceWindowsReply) error {
+	service.Log.Debug("Admin: GetMaintenanceWindows called")
+
+	if service.Maintenance == nil {
+		reply.Windows = []maintenance.Window{}
+		return nil
+	}
+	reply.Windows = service.Maintenance.Windows()
+	return nil
+}
+
+// SetAllowedOriginsArgs are the arguments for calling SetAllowedOrigins
+type SetAllowedOriginsArgs struct {
+	// Origins the APIs can be requested from, such as
+	// "https://*.avax.network". "*" allows every origin.
+	AllowedOrigins []string `json:"allowedOrigins"`
+}
+
+// SetAllowedOrigins replaces the origins the APIs can be requested from by
+// browsers, without restarting the node. The origins aren't persisted, so the
+// configured origins are allowed again after the node restarts.
+func (service *Admin) SetAllowedOrigins(_ *http.Request, args *SetAllowedOriginsArgs, _ *api.EmptyReply) error {
+	service.Log.Debug("Admin: SetAllowedOrigins called",
+		logging.UserStrings("allowedOrigins", args.AllowedOrigins),
+	)
+
+	if service.CORS == nil {
+		return errNoCORS
+	}
+	service.CORS.SetAllowedOrigins(args.AllowedOrigins)
+	return nil
+}
+
+// GetAllowedOriginsReply is the response from GetAllowedOrigins
+type GetAllowedOriginsReply struct {
+	AllowedOrigins []string `json:"allowedOrigins"`
+}
+
+// GetAllowedOrigins returns the origins the APIs can be requested from by
+// browsers.
+func (service *Admin) GetAllowedOrigins(_ *http.Request, _ *struct{}, reply *GetAllowedOriginsReply) error {
+	service.Log.Debug("Admin: GetAllowedOrigins called")
+
+	if service.CORS == nil {
+		return errNoCORS
+	}
+	reply.AllowedOrigins = service.CORS.AllowedOrigins()
+	return nil
+}
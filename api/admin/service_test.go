@@ -4,16 +4,28 @@
 package admin
 
 import (
+	"context"
+	"encoding/hex"
 	"errors"
+	"io"
 	"net/http"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 
 	"github.com/stretchr/testify/require"
 
+	"github.com/ava-labs/avalanchego/api/server"
+	"github.com/ava-labs/avalanchego/chains"
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/utils/hashing"
 	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/maintenance"
+	"github.com/ava-labs/avalanchego/utils/perms"
 	"github.com/ava-labs/avalanchego/vms"
 	"github.com/ava-labs/avalanchego/vms/registry"
 )
@@ -121,3 +133,252 @@ func TestLoadVMsGetAliasesFails(t *testing.T) {
 
 	require.Equal(t, err, errOops)
 }
+
+func TestIncidents(t *testing.T) {
+	require := require.New(t)
+
+	incidents := common.NewIncidentLog()
+	admin := &Admin{Config: Config{
+		Log:          logging.NoLog{},
+		ChainManager: chains.MockManager{},
+		Incidents:    incidents,
+	}}
+
+	chainID := ids.GenerateTestID()
+	stale := common.Incident{
+		ChainID: chainID,
+		Kind:    common.IncidentStale,
+	}
+	otherStale := common.Incident{
+		ChainID: ids.GenerateTestID(),
+		Kind:    common.IncidentStale,
+	}
+	resync := common.Incident{
+		ChainID: chainID,
+		Kind:    common.IncidentResync,
+	}
+	incidents.Record(stale)
+	incidents.Record(otherStale)
+	incidents.Record(resync)
+
+	reply := IncidentsReply{}
+	require.NoError(admin.Incidents(&http.Request{}, &IncidentsArgs{}, &reply))
+	require.Equal([]common.Incident{stale, otherStale, resync}, reply.Incidents)
+
+	reply = IncidentsReply{}
+	require.NoError(admin.Incidents(&http.Request{}, &IncidentsArgs{Chain: chainID.String()}, &reply))
+	require.Equal([]common.Incident{stale, resync}, reply.Incidents)
+}
+
+func TestMaintenance(t *testing.T) {
+	require := require.New(t)
+
+	admin := &Admin{Config: Config{
+		Log: logging.NoLog{},
+	}}
+	window := maintenance.Window{
+		Start:    time.Now().Add(time.Hour).Truncate(time.Second),
+		Duration: time.Minute,
+		Reason:   "upgrade",
+	}
+	err := admin.ScheduleMaintenance(&http.Request{}, &ScheduleMaintenanceArgs{Window: window}, nil)
+	require.ErrorIs(err, errNoScheduler)
+
+	scheduler, err := maintenance.NewScheduler(logging.NoLog{}, maintenance.Config{})
+	require.NoError(err)
+	admin.Maintenance = scheduler
+
+	require.NoError(admin.ScheduleMaintenance(&http.Request{}, &ScheduleMaintenanceArgs{Window: window}, nil))
+	reply := GetMaintenanceWindowsReply{}
+	require.NoError(admin.GetMaintenanceWindows(&http.Request{}, nil, &reply))
+	require.Equal([]maintenance.Window{window}, reply.Windows)
+
+	require.NoError(admin.CancelMaintenance(&http.Request{}, &CancelMaintenanceArgs{Start: window.Start}, nil))
+	reply = GetMaintenanceWindowsReply{}
+	require.NoError(admin.GetMaintenanceWindows(&http.Request{}, nil, &reply))
+	require.Empty(reply.Windows)
+}
+
+func TestGetConfigOverridesRedactsSecrets(t *testing.T) {
+	require := require.New(t)
+
+	admin := &Admin{Config: Config{
+		Log: logging.NoLog{},
+		ConfigOverrides: []ConfigOverride{
+			{
+				Key:     "http-port",
+				Value:   9652,
+				Default: "9650",
+				Source:  ConfigSourceFlag,
+			},
+			{
+				Key:     "staking-tls-key-file-content",
+				Value:   "c2VjcmV0",
+				Default: "",
+				Source:  ConfigSourceEnv,
+			},
+			{
+				Key:     "staking-ephemeral-cert-enabled",
+				Value:   true,
+				Default: "false",
+				Source:  ConfigSourceFile,
+			},
+		},
+	}}
+
+	reply := &GetConfigOverridesReply{}
+	require.NoError(admin.GetConfigOverrides(nil, nil, reply))
+	require.Equal([]ConfigOverride{
+		{
+			Key:     "http-port",
+			Value:   float64(9652),
+			Default: "9650",
+			Source:  ConfigSourceFlag,
+		},
+		{
+			Key:     "staking-tls-key-file-content",
+			Value:   redacted,
+			Default: "",
+			Source:  ConfigSourceEnv,
+		},
+		{
+			Key:     "staking-ephemeral-cert-enabled",
+			Value:   true,
+			Default: "false",
+			Source:  ConfigSourceFile,
+		},
+	}, reply.Overrides)
+
+	// The node's config isn't modified.
+	require.Equal("c2VjcmV0", admin.ConfigOverrides[1].Value)
+}
+
+func TestUpgradeVMPluginOnlyLaunchesPluginDir(t *testing.T) {
+	require := require.New(t)
+
+	admin := &Admin{Config: Config{
+		Log:          logging.NoLog{},
+		ChainManager: chains.MockManager{},
+		PluginDir:    t.TempDir(),
+	}}
+	for _, plugin := range []string{"", ".", "..", "../plugin", "/bin/sh", "dir/plugin"} {
+		err := admin.UpgradeVMPlugin(&http.Request{}, &UpgradeVMPluginArgs{
+			Chain:  "X",
+			Plugin: plugin,
+		}, nil)
+		require.ErrorIs(err, errBadPlugin, plugin)
+	}
+
+	err := admin.UpgradeVMPlugin(&http.Request{}, &UpgradeVMPluginArgs{
+		Chain:  "X",
+		Plugin: "missing",
+	}, nil)
+	require.ErrorIs(err, os.ErrNotExist)
+}
+
+func TestInstallVM(t *testing.T) {
+	require := require.New(t)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockVMManager := vms.NewMockManager(ctrl)
+	mockVMRegistry := registry.NewMockVMRegistry(ctrl)
+	admin := &Admin{Config: Config{
+		Log:        logging.NoLog{},
+		VMManager:  mockVMManager,
+		VMRegistry: mockVMRegistry,
+	}}
+
+	vmID := ids.GenerateTestID()
+	mockVMManager.EXPECT().Lookup("myvm").Return(vmID, nil).AnyTimes()
+
+	plugin := []byte("plugin")
+	pluginPath := filepath.Join(t.TempDir(), "plugin")
+	require.NoError(os.WriteFile(pluginPath, plugin, perms.ReadWrite))
+	checksum := hashing.ComputeHash256Array(plugin)
+	hexChecksum := hex.EncodeToString(checksum[:])
+
+	tests := []struct {
+		name        string
+		args        InstallVMArgs
+		expectedErr error
+	}{
+		{
+			name: "bad checksum",
+			args: InstallVMArgs{
+				VM:     "myvm",
+				Path:   pluginPath,
+				SHA256: "1234",
+			},
+			expectedErr: errBadChecksum,
+		},
+		{
+			name: "no source",
+			args: InstallVMArgs{
+				VM:     "myvm",
+				SHA256: hexChecksum,
+			},
+			expectedErr: errBadSource,
+		},
+		{
+			name: "both sources",
+			args: InstallVMArgs{
+				VM:     "myvm",
+				URL:    "https://example.com/plugin",
+				Path:   pluginPath,
+				SHA256: hexChecksum,
+			},
+			expectedErr: errBadSource,
+		},
+		{
+			name: "bad scheme",
+			args: InstallVMArgs{
+				VM:     "myvm",
+				URL:    "file://" + pluginPath,
+				SHA256: hexChecksum,
+			},
+			expectedErr: errBadScheme,
+		},
+	}
+	for _, test := range tests {
+		err := admin.InstallVM(&http.Request{}, &test.args, nil)
+		require.ErrorIs(err, test.expectedErr, test.name)
+	}
+
+	mockVMRegistry.EXPECT().
+		InstallWithReadLock(gomock.Any(), vmID, gomock.Any(), checksum).
+		DoAndReturn(func(_ context.Context, _ ids.ID, r io.Reader, _ hashing.Hash256) error {
+			installed, err := io.ReadAll(r)
+			require.NoError(err)
+			require.Equal(plugin, installed)
+			return nil
+		})
+	require.NoError(admin.InstallVM(&http.Request{}, &InstallVMArgs{
+		VM:     "myvm",
+		Path:   pluginPath,
+		SHA256: hexChecksum,
+	}, nil))
+}
+
+func TestAllowedOrigins(t *testing.T) {
+	require := require.New(t)
+	ctrl := gomock.NewController(t)
+
+	admin := &Admin{Config: Config{
+		Log: logging.NoLog{},
+	}}
+	err := admin.SetAllowedOrigins(&http.Request{}, &SetAllowedOriginsArgs{AllowedOrigins: []string{"*"}}, nil)
+	require.ErrorIs(err, errNoCORS)
+
+	cors := server.NewMockServer(ctrl)
+	admin.CORS = cors
+	origins := []string{"https://*.avax.network"}
+	cors.EXPECT().SetAllowedOrigins(origins)
+	cors.EXPECT().AllowedOrigins().Return(origins)
+
+	require.NoError(admin.SetAllowedOrigins(&http.Request{}, &SetAllowedOriginsArgs{AllowedOrigins: origins}, nil))
+	reply := GetAllowedOriginsReply{}
+	require.NoError(admin.GetAllowedOrigins(&http.Request{}, nil, &reply))
+	require.Equal(origins, reply.AllowedOrigins)
+}
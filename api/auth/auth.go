@@ -52,6 +52,7 @@ var (
 	errNoPassword                  = errors.New("no password")
 	errNoEndpoints                 = errors.New("must name at least one endpoint")
 	errTooManyEndpoints            = fmt.Errorf("can only name at most %d endpoints", maxEndpoints)
+	errUnknownScope                = errors.New("unknown scope")
 
 	_ Auth = (*auth)(nil)
 )
@@ -62,6 +63,12 @@ type Auth interface {
 	// If one of the elements of [endpoints] is "*", all APIs are accessible.
 	NewToken(pw string, duration time.Duration, endpoints []string) (string, error)
 
+	// Create and return a new token that allows access to each API endpoint
+	// for [duration] such that the API's path ends with an element of
+	// [endpoints], or the API is in one of [scopes]. Either [endpoints] or
+	// [scopes] may be empty, but not both.
+	NewScopedToken(pw string, duration time.Duration, endpoints, scopes []string) (string, error)
+
 	// Revokes [token]; it will not be accepted as authorization for future API
 	// calls. If the token is invalid, this is a no-op.  If a token is revoked
 	// and then the password is changed, and then changed back to the current
@@ -94,6 +101,9 @@ type auth struct {
 
 	log      logging.Logger
 	endpoint string
+	// Resolves the aliases of the requested URLs, so that scopes cover the
+	// URLs of their APIs under any alias
+	resolvePath PathResolver
 
 	lock sync.RWMutex
 	// Can be changed via API call.
@@ -102,33 +112,51 @@ type auth struct {
 	revoked map[string]struct{}
 }
 
-func New(log logging.Logger, endpoint, pw string) (Auth, error) {
+// PathResolver returns the URL path that [urlPath] routes to, with the aliases
+// it's under resolved.
+type PathResolver func(urlPath string) string
+
+// New returns an auth wrapper protected by [pw]. If non-nil, [resolvePath]
+// resolves the aliases of the requested URLs before they are checked against
+// the scopes of tokens.
+func New(log logging.Logger, endpoint, pw string, resolvePath PathResolver) (Auth, error) {
 	a := &auth{
-		log:      log,
-		endpoint: endpoint,
-		revoked:  make(map[string]struct{}),
+		log:         log,
+		endpoint:    endpoint,
+		resolvePath: resolvePath,
+		revoked:     make(map[string]struct{}),
 	}
 	return a, a.password.Set(pw)
 }
 
-func NewFromHash(log logging.Logger, endpoint string, pw password.Hash) Auth {
+func NewFromHash(log logging.Logger, endpoint string, pw password.Hash, resolvePath PathResolver) Auth {
 	return &auth{
-		log:      log,
-		endpoint: endpoint,
-		password: pw,
-		revoked:  make(map[string]struct{}),
+		log:         log,
+		endpoint:    endpoint,
+		resolvePath: resolvePath,
+		password:    pw,
+		revoked:     make(map[string]struct{}),
 	}
 }
 
 func (a *auth) NewToken(pw string, duration time.Duration, endpoints []string) (string, error) {
+	return a.NewScopedToken(pw, duration, endpoints, nil)
+}
+
+func (a *auth) NewScopedToken(pw string, duration time.Duration, endpoints, scopes []string) (string, error) {
 	if pw == "" {
 		return "", errNoPassword
 	}
-	if l := len(endpoints); l == 0 {
+	if l := len(endpoints) + len(scopes); l == 0 {
 		return "", errNoEndpoints
 	} else if l > maxEndpoints {
 		return "", errTooManyEndpoints
 	}
+	for _, scope := range scopes {
+		if _, ok := scopePaths[scope]; !ok {
+			return "", fmt.Errorf("%w: %q", errUnknownScope, scope)
+		}
+	}
 
 	a.lock.RLock()
 	defer a.lock.RUnlock()
@@ -161,6 +189,7 @@ func (a *auth) NewToken(pw string, duration time.Duration, endpoints []string) (
 		claims.Endpoints = []string{"*"}
 	} else {
 		claims.Endpoints = endpoints
+		claims.Scopes = scopes
 	}
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, &claims)
 	return token.SignedString(a.password.Password[:]) // Sign the token and return its string repr.
@@ -227,6 +256,17 @@ func (a *auth) AuthenticateToken(tokenStr, url string) error {
 			return nil
 		}
 	}
+	if len(claims.Scopes) == 0 {
+		return errTokenInsufficientPermission
+	}
+	if a.resolvePath != nil {
+		url = a.resolvePath(url)
+	}
+	for _, scope := range claims.Scopes {
+		if inScope(scope, url) {
+			return nil
+		}
+	}
 	return errTokenInsufficientPermission
 }
 
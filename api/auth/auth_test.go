@@ -38,7 +38,7 @@ func init() {
 var dummyHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
 
 func TestNewTokenWrongPassword(t *testing.T) {
-	auth := NewFromHash(logging.NoLog{}, "auth", hashedPassword)
+	auth := NewFromHash(logging.NoLog{}, "auth", hashedPassword, nil)
 
 	_, err := auth.NewToken("", defaultTokenLifespan, []string{"endpoint1, endpoint2"})
 	require.Error(t, err, "should have failed because password is wrong")
@@ -48,7 +48,7 @@ func TestNewTokenWrongPassword(t *testing.T) {
 }
 
 func TestNewTokenHappyPath(t *testing.T) {
-	auth := NewFromHash(logging.NoLog{}, "auth", hashedPassword).(*auth)
+	auth := NewFromHash(logging.NoLog{}, "auth", hashedPassword, nil).(*auth)
 
 	now := time.Now()
 	auth.clock.Set(now)
@@ -75,7 +75,7 @@ func TestNewTokenHappyPath(t *testing.T) {
 }
 
 func TestTokenHasWrongSig(t *testing.T) {
-	auth := NewFromHash(logging.NoLog{}, "auth", hashedPassword).(*auth)
+	auth := NewFromHash(logging.NoLog{}, "auth", hashedPassword, nil).(*auth)
 
 	// Make a token
 	endpoints := []string{"endpoint1", "endpoint2", "endpoint3"}
@@ -100,7 +100,7 @@ func TestTokenHasWrongSig(t *testing.T) {
 }
 
 func TestChangePassword(t *testing.T) {
-	auth := NewFromHash(logging.NoLog{}, "auth", hashedPassword).(*auth)
+	auth := NewFromHash(logging.NoLog{}, "auth", hashedPassword, nil).(*auth)
 
 	password2 := "fejhkefjhefjhefhje" // #nosec G101
 	var err error
@@ -128,7 +128,7 @@ func TestChangePassword(t *testing.T) {
 }
 
 func TestRevokeToken(t *testing.T) {
-	auth := NewFromHash(logging.NoLog{}, "auth", hashedPassword).(*auth)
+	auth := NewFromHash(logging.NoLog{}, "auth", hashedPassword, nil).(*auth)
 
 	// Make a token
 	endpoints := []string{"/ext/info", "/ext/bc/X", "/ext/metrics"}
@@ -141,7 +141,7 @@ func TestRevokeToken(t *testing.T) {
 }
 
 func TestWrapHandlerHappyPath(t *testing.T) {
-	auth := NewFromHash(logging.NoLog{}, "auth", hashedPassword)
+	auth := NewFromHash(logging.NoLog{}, "auth", hashedPassword, nil)
 
 	// Make a token
 	endpoints := []string{"/ext/info", "/ext/bc/X", "/ext/metrics"}
@@ -160,7 +160,7 @@ func TestWrapHandlerHappyPath(t *testing.T) {
 }
 
 func TestWrapHandlerRevokedToken(t *testing.T) {
-	auth := NewFromHash(logging.NoLog{}, "auth", hashedPassword)
+	auth := NewFromHash(logging.NoLog{}, "auth", hashedPassword, nil)
 
 	// Make a token
 	endpoints := []string{"/ext/info", "/ext/bc/X", "/ext/metrics"}
@@ -184,7 +184,7 @@ func TestWrapHandlerRevokedToken(t *testing.T) {
 }
 
 func TestWrapHandlerExpiredToken(t *testing.T) {
-	auth := NewFromHash(logging.NoLog{}, "auth", hashedPassword).(*auth)
+	auth := NewFromHash(logging.NoLog{}, "auth", hashedPassword, nil).(*auth)
 
 	auth.clock.Set(time.Now().Add(-2 * defaultTokenLifespan))
 
@@ -207,7 +207,7 @@ func TestWrapHandlerExpiredToken(t *testing.T) {
 }
 
 func TestWrapHandlerNoAuthToken(t *testing.T) {
-	auth := NewFromHash(logging.NoLog{}, "auth", hashedPassword)
+	auth := NewFromHash(logging.NoLog{}, "auth", hashedPassword, nil)
 
 	endpoints := []string{"/ext/info", "/ext/bc/X", "/ext/metrics"}
 	wrappedHandler := auth.WrapHandler(dummyHandler)
@@ -222,7 +222,7 @@ func TestWrapHandlerNoAuthToken(t *testing.T) {
 }
 
 func TestWrapHandlerUnauthorizedEndpoint(t *testing.T) {
-	auth := NewFromHash(logging.NoLog{}, "auth", hashedPassword)
+	auth := NewFromHash(logging.NoLog{}, "auth", hashedPassword, nil)
 
 	// Make a token
 	endpoints := []string{"/ext/info"}
@@ -244,7 +244,7 @@ func TestWrapHandlerUnauthorizedEndpoint(t *testing.T) {
 }
 
 func TestWrapHandlerAuthEndpoint(t *testing.T) {
-	auth := NewFromHash(logging.NoLog{}, "auth", hashedPassword)
+	auth := NewFromHash(logging.NoLog{}, "auth", hashedPassword, nil)
 
 	// Make a token
 	endpoints := []string{"/ext/info", "/ext/bc/X", "/ext/metrics", "", "/foo", "/ext/info/foo"}
@@ -260,7 +260,7 @@ func TestWrapHandlerAuthEndpoint(t *testing.T) {
 }
 
 func TestWrapHandlerAccessAll(t *testing.T) {
-	auth := NewFromHash(logging.NoLog{}, "auth", hashedPassword)
+	auth := NewFromHash(logging.NoLog{}, "auth", hashedPassword, nil)
 
 	// Make a token that allows access to all endpoints
 	endpoints := []string{"/ext/info", "/ext/bc/X", "/ext/metrics", "", "/foo", "/ext/foo/info"}
@@ -277,6 +277,60 @@ func TestWrapHandlerAccessAll(t *testing.T) {
 	}
 }
 
+func TestWrapHandlerScopes(t *testing.T) {
+	// /ext/X is an alias of /ext/bc/X
+	resolvePath := func(urlPath string) string {
+		if urlPath == "/ext/X" || strings.HasPrefix(urlPath, "/ext/X/") {
+			return "/ext/bc/X" + strings.TrimPrefix(urlPath, "/ext/X")
+		}
+		return urlPath
+	}
+	auth := NewFromHash(logging.NoLog{}, "auth", hashedPassword, resolvePath)
+
+	// Make a token that allows access to the APIs of the chains and to the
+	// info API
+	tokenStr, err := auth.NewScopedToken(testPassword, defaultTokenLifespan, []string{"/ext/info"}, []string{ChainsScope})
+	require.NoError(t, err)
+
+	tests := []struct {
+		endpoint     string
+		expectedCode int
+	}{
+		{"/ext/bc/X", http.StatusOK},
+		{"/ext/bc/C/rpc", http.StatusOK},
+		{"/ext/bc/C/avax", http.StatusOK},
+		{"/ext/X", http.StatusOK},
+		{"/ext/X/events", http.StatusOK},
+		{"/ext/Xfoo", http.StatusUnauthorized},
+		{"/ext/info", http.StatusOK},
+		{"/ext/admin", http.StatusUnauthorized},
+		{"/ext/keystore", http.StatusUnauthorized},
+		{"/ext/bcfoo", http.StatusUnauthorized},
+		{"/ext/bc/C/../../admin", http.StatusUnauthorized},
+	}
+	wrappedHandler := auth.WrapHandler(dummyHandler)
+	for _, test := range tests {
+		req := httptest.NewRequest(http.MethodPost, fmt.Sprintf("http://127.0.0.1:9650%s", test.endpoint), strings.NewReader(""))
+		req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", tokenStr))
+		rr := httptest.NewRecorder()
+		wrappedHandler.ServeHTTP(rr, req)
+		require.Equal(t, test.expectedCode, rr.Code, test.endpoint)
+	}
+}
+
+func TestNewScopedTokenInvalidScopes(t *testing.T) {
+	auth := NewFromHash(logging.NoLog{}, "auth", hashedPassword, nil)
+
+	_, err := auth.NewScopedToken(testPassword, defaultTokenLifespan, nil, []string{"foo"})
+	require.ErrorIs(t, err, errUnknownScope)
+
+	_, err = auth.NewScopedToken(testPassword, defaultTokenLifespan, nil, nil)
+	require.ErrorIs(t, err, errNoEndpoints)
+
+	_, err = auth.NewScopedToken(testPassword, defaultTokenLifespan, nil, []string{AdminScope, KeystoreScope})
+	require.NoError(t, err)
+}
+
 func TestWriteUnauthorizedResponse(t *testing.T) {
 	rr := httptest.NewRecorder()
 	writeUnauthorizedResponse(rr, errors.New("example err"))
@@ -285,7 +339,7 @@ func TestWriteUnauthorizedResponse(t *testing.T) {
 }
 
 func TestWrapHandlerMutatedRevokedToken(t *testing.T) {
-	auth := NewFromHash(logging.NoLog{}, "auth", hashedPassword)
+	auth := NewFromHash(logging.NoLog{}, "auth", hashedPassword, nil)
 
 	// Make a token
 	endpoints := []string{"/ext/info", "/ext/bc/X", "/ext/metrics"}
@@ -309,7 +363,7 @@ func TestWrapHandlerMutatedRevokedToken(t *testing.T) {
 }
 
 func TestWrapHandlerInvalidSigningMethod(t *testing.T) {
-	auth := NewFromHash(logging.NoLog{}, "auth", hashedPassword).(*auth)
+	auth := NewFromHash(logging.NoLog{}, "auth", hashedPassword, nil).(*auth)
 
 	// Make a token
 	endpoints := []string{"/ext/info", "/ext/bc/X", "/ext/metrics"}
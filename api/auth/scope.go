@@ -0,0 +1,41 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package auth
+
+import (
+	"path"
+	"strings"
+)
+
+// Scopes a token can be bound to. A token bound to a scope allows access to
+// every API in the scope, including the APIs added after the token is created.
+const (
+	// AdminScope is the admin API.
+	AdminScope = "admin"
+	// KeystoreScope is the keystore API.
+	KeystoreScope = "keystore"
+	// ChainsScope is the APIs of every chain, such as their RPC endpoints,
+	// under any of their aliases.
+	ChainsScope = "chains"
+	// EventsScope is the event hub, which publishes the events of every chain.
+	EventsScope = "events"
+)
+
+// scopePaths maps each scope to the base path of its APIs.
+var scopePaths = map[string]string{
+	AdminScope:    "/ext/admin",
+	KeystoreScope: "/ext/keystore",
+	ChainsScope:   "/ext/bc",
+	EventsScope:   "/ext/events",
+}
+
+// inScope returns true if [urlPath] is the path of an API in [scope].
+func inScope(scope, urlPath string) bool {
+	basePath, ok := scopePaths[scope]
+	if !ok {
+		return false
+	}
+	urlPath = path.Clean(urlPath)
+	return urlPath == basePath || strings.HasPrefix(urlPath, basePath+"/")
+}
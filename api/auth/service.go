@@ -26,6 +26,11 @@ type NewTokenArgs struct {
 	// allows access to all API endpoints. [Endpoints] must have between 1 and
 	// [maxEndpoints] elements
 	Endpoints []string `json:"endpoints"`
+	// Scopes whose APIs may be accessed with this token, e.g. if scopes is
+	// ["chains"] then the token holder can hit the APIs of every chain. The
+	// scopes are "admin", "keystore", "chains" and "events". [Endpoints] and
+	// [Scopes] must have between 1 and [maxEndpoints] elements in total
+	Scopes []string `json:"scopes"`
 }
 
 type Token struct {
@@ -36,7 +41,7 @@ func (s *Service) NewToken(_ *http.Request, args *NewTokenArgs, reply *Token) er
 	s.auth.log.Debug("Auth: NewToken called")
 
 	var err error
-	reply.Token, err = s.auth.NewToken(args.Password.Password, defaultTokenLifespan, args.Endpoints)
+	reply.Token, err = s.auth.NewScopedToken(args.Password.Password, defaultTokenLifespan, args.Endpoints, args.Scopes)
 	return err
 }
 
@@ -75,6 +75,13 @@ type GetBlockResponse struct {
 	// If GetBlockResponse.Encoding is formatting.JSON, GetBlockResponse.Block
 	// is the actual block returned as a JSON.
 	Encoding formatting.Encoding `json:"encoding"`
+	// Final is true if the block was decided, so that the response can be
+	// cached.
+	Final bool `json:"-"`
+}
+
+func (r *GetBlockResponse) IsFinal() bool {
+	return r.Final
 }
 
 // FormattedBlock defines a JSON formatted struct containing a block in Hex
@@ -97,6 +104,13 @@ type GetTxReply struct {
 	// returned as JSON to the caller.
 	Tx       interface{}         `json:"tx"`
 	Encoding formatting.Encoding `json:"encoding"`
+	// Final is true if the tx was decided, so that the response can be
+	// cached.
+	Final bool `json:"-"`
+}
+
+func (r *GetTxReply) IsFinal() bool {
+	return r.Final
 }
 
 // FormattedTx defines a JSON formatted struct containing a Tx as a string
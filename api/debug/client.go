@@ -0,0 +1,61 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package debug
+
+import (
+	"context"
+
+	stdjson "encoding/json"
+
+	"github.com/ava-labs/avalanchego/utils/formatting"
+	"github.com/ava-labs/avalanchego/utils/rpc"
+)
+
+var _ Client = (*client)(nil)
+
+// Client interface for the Avalanche Debug API Endpoint
+type Client interface {
+	DecodeBlock(ctx context.Context, chain string, blkBytes []byte, options ...rpc.Option) (*DecodeBlockReply, error)
+	DecodeTx(ctx context.Context, chain string, txBytes []byte, options ...rpc.Option) (stdjson.RawMessage, error)
+}
+
+// Client implementation for the Avalanche Debug API Endpoint
+type client struct {
+	requester rpc.EndpointRequester
+}
+
+// NewClient returns a new Debug API Client
+func NewClient(uri string) Client {
+	return &client{requester: rpc.NewEndpointRequester(
+		uri + "/ext/debug",
+	)}
+}
+
+func (c *client) DecodeBlock(ctx context.Context, chain string, blkBytes []byte, options ...rpc.Option) (*DecodeBlockReply, error) {
+	blkStr, err := formatting.Encode(formatting.Hex, blkBytes)
+	if err != nil {
+		return nil, err
+	}
+	res := &DecodeBlockReply{}
+	err = c.requester.SendRequest(ctx, "debug.decodeBlock", &DecodeBlockArgs{
+		Chain:    chain,
+		Block:    blkStr,
+		Encoding: formatting.Hex,
+	}, res, options...)
+	return res, err
+}
+
+func (c *client) DecodeTx(ctx context.Context, chain string, txBytes []byte, options ...rpc.Option) (stdjson.RawMessage, error) {
+	txStr, err := formatting.Encode(formatting.Hex, txBytes)
+	if err != nil {
+		return nil, err
+	}
+	res := &DecodeTxReply{}
+	err = c.requester.SendRequest(ctx, "debug.decodeTx", &DecodeTxArgs{
+		Chain:    chain,
+		Tx:       txStr,
+		Encoding: formatting.Hex,
+	}, res, options...)
+	return res.Decoded, err
+}
@@ -0,0 +1,189 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package debug exposes an API for inspecting the node's chains, such as
+// decoding raw blocks and transactions without issuing them.
+package debug
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+
+	stdjson "encoding/json"
+
+	"github.com/gorilla/rpc/v2"
+
+	"go.uber.org/zap"
+
+	"github.com/ava-labs/avalanchego/chains"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/engine/avalanche/vertex"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
+	"github.com/ava-labs/avalanchego/utils/formatting"
+	"github.com/ava-labs/avalanchego/utils/json"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+var (
+	errUnknownChain    = errors.New("unknown chain")
+	errNotSnowmanChain = errors.New("chain doesn't run snowman")
+	errNotDAGChain     = errors.New("chain doesn't run avalanche")
+
+	_ chains.Registrant = (*Debug)(nil)
+)
+
+// Debug is the API service for inspecting the node's chains
+type Debug struct {
+	log     logging.Logger
+	aliaser ids.AliaserReader
+
+	lock sync.RWMutex
+	// Chain ID --> engine of the chain
+	engines map[ids.ID]common.Engine
+}
+
+// New returns a new debug API service that resolves chain aliases using
+// [aliaser]. Chains must be registered with the returned service before they
+// can be inspected.
+func New(log logging.Logger, aliaser ids.AliaserReader) *Debug {
+	return &Debug{
+		log:     log,
+		aliaser: aliaser,
+		engines: make(map[ids.ID]common.Engine),
+	}
+}
+
+// Handler returns the HTTP handler that serves the debug API.
+func (d *Debug) Handler() (*common.HTTPHandler, error) {
+	newServer := rpc.NewServer()
+	codec := json.NewCodec()
+	newServer.RegisterCodec(codec, "application/json")
+	newServer.RegisterCodec(codec, "application/json;charset=UTF-8")
+	if err := newServer.RegisterService(d, "debug"); err != nil {
+		return nil, err
+	}
+	return &common.HTTPHandler{Handler: newServer}, nil
+}
+
+func (d *Debug) RegisterChain(_ string, engine common.Engine) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	d.engines[engine.Context().ChainID] = engine
+}
+
+// DecodeBlockArgs are the arguments for DecodeBlock
+type DecodeBlockArgs struct {
+	// Chain is the ID or an alias of the chain the block belongs to
+	Chain    string              `json:"chain"`
+	Block    string              `json:"block"`
+	Encoding formatting.Encoding `json:"encoding"`
+}
+
+// DecodeBlockReply is the response from DecodeBlock
+type DecodeBlockReply struct {
+	// Decoded is the block as described by the chain's VM
+	Decoded stdjson.RawMessage `json:"decoded"`
+}
+
+// DecodeBlock describes the provided block using the VM of the specified
+// snowman chain. The block isn't verified, cached, or issued.
+func (d *Debug) DecodeBlock(_ *http.Request, args *DecodeBlockArgs, reply *DecodeBlockReply) error {
+	d.log.Debug("Debug: DecodeBlock called",
+		zap.String("chain", args.Chain),
+	)
+
+	blkBytes, err := formatting.Decode(args.Encoding, args.Block)
+	if err != nil {
+		return fmt.Errorf("couldn't decode block bytes: %w", err)
+	}
+	engine, err := d.getEngine(args.Chain)
+	if err != nil {
+		return err
+	}
+	vm, ok := engine.GetVM().(block.ChainVM)
+	if !ok {
+		return fmt.Errorf("%w: %q", errNotSnowmanChain, args.Chain)
+	}
+	// Parsing a block caches it, so blocks can only be described by VMs that
+	// support decoding.
+	decoder, ok := vm.(block.Decoder)
+	if !ok {
+		return block.ErrDecoderNotImplemented
+	}
+
+	ctx := engine.Context()
+	ctx.Lock.Lock()
+	defer ctx.Lock.Unlock()
+
+	reply.Decoded, err = decoder.DecodeBlock(context.TODO(), blkBytes)
+	return err
+}
+
+// DecodeTxArgs are the arguments for DecodeTx
+type DecodeTxArgs struct {
+	// Chain is the ID or an alias of the chain the transaction belongs to
+	Chain    string              `json:"chain"`
+	Tx       string              `json:"tx"`
+	Encoding formatting.Encoding `json:"encoding"`
+}
+
+// DecodeTxReply is the response from DecodeTx
+type DecodeTxReply struct {
+	// Decoded is the transaction as described by the chain's VM
+	Decoded stdjson.RawMessage `json:"decoded"`
+}
+
+// DecodeTx describes the provided transaction using the VM of the specified
+// DAG chain. The transaction isn't verified, persisted, or issued.
+func (d *Debug) DecodeTx(_ *http.Request, args *DecodeTxArgs, reply *DecodeTxReply) error {
+	d.log.Debug("Debug: DecodeTx called",
+		zap.String("chain", args.Chain),
+	)
+
+	txBytes, err := formatting.Decode(args.Encoding, args.Tx)
+	if err != nil {
+		return fmt.Errorf("couldn't decode tx bytes: %w", err)
+	}
+	engine, err := d.getEngine(args.Chain)
+	if err != nil {
+		return err
+	}
+	vm, ok := engine.GetVM().(vertex.DAGVM)
+	if !ok {
+		return fmt.Errorf("%w: %q", errNotDAGChain, args.Chain)
+	}
+	// Parsing a transaction may persist it, so transactions can only be
+	// described by VMs that support decoding.
+	decoder, ok := vm.(vertex.Decoder)
+	if !ok {
+		return vertex.ErrDecoderNotImplemented
+	}
+
+	ctx := engine.Context()
+	ctx.Lock.Lock()
+	defer ctx.Lock.Unlock()
+
+	reply.Decoded, err = decoder.DecodeTx(context.TODO(), txBytes)
+	return err
+}
+
+func (d *Debug) getEngine(chain string) (common.Engine, error) {
+	chainID, err := d.aliaser.Lookup(chain)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %q", errUnknownChain, chain)
+	}
+
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+
+	engine, ok := d.engines[chainID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", errUnknownChain, chain)
+	}
+	return engine, nil
+}
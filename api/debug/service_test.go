@@ -0,0 +1,128 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package debug
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/engine/avalanche/vertex"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
+	"github.com/ava-labs/avalanchego/utils/formatting"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+type decoderVM struct {
+	*block.TestVM
+	*block.TestDecoder
+}
+
+func newTestDebug(t *testing.T, vm common.VM) (*Debug, ids.ID) {
+	ctx := snow.DefaultConsensusContextTest()
+	ctx.ChainID = ids.GenerateTestID()
+
+	aliaser := ids.NewAliaser()
+	require.NoError(t, aliaser.Alias(ctx.ChainID, ctx.ChainID.String()))
+	require.NoError(t, aliaser.Alias(ctx.ChainID, "X"))
+
+	d := New(logging.NoLog{}, aliaser)
+	d.RegisterChain("X", &common.EngineTest{
+		T: t,
+		ContextF: func() *snow.ConsensusContext {
+			return ctx
+		},
+		GetVMF: func() common.VM {
+			return vm
+		},
+	})
+	return d, ctx.ChainID
+}
+
+func encode(t *testing.T, b []byte) string {
+	str, err := formatting.Encode(formatting.Hex, b)
+	require.NoError(t, err)
+	return str
+}
+
+func TestDecodeBlock(t *testing.T) {
+	require := require.New(t)
+
+	blkBytes := []byte{1, 2, 3}
+	// The block is only decoded, so it isn't parsed by the VM, which would
+	// cache it.
+	vm := &decoderVM{
+		TestVM: &block.TestVM{
+			TestVM:         common.TestVM{T: t},
+			CantParseBlock: true,
+		},
+		TestDecoder: &block.TestDecoder{
+			T: t,
+			DecodeBlockF: func(_ context.Context, b []byte) ([]byte, error) {
+				require.Equal(blkBytes, b)
+				return []byte(`{"txs":[]}`), nil
+			},
+		},
+	}
+	d, _ := newTestDebug(t, vm)
+
+	reply := &DecodeBlockReply{}
+	require.NoError(d.DecodeBlock(nil, &DecodeBlockArgs{
+		Chain: "X",
+		Block: encode(t, blkBytes),
+	}, reply))
+	require.JSONEq(`{"txs":[]}`, string(reply.Decoded))
+}
+
+func TestDecodeBlockRequiresDecoder(t *testing.T) {
+	require := require.New(t)
+
+	d, chainID := newTestDebug(t, &block.TestVM{})
+
+	err := d.DecodeBlock(nil, &DecodeBlockArgs{
+		Chain: chainID.String(),
+		Block: encode(t, []byte{1}),
+	}, &DecodeBlockReply{})
+	require.ErrorIs(err, block.ErrDecoderNotImplemented)
+}
+
+func TestDecodeUnknownChain(t *testing.T) {
+	require := require.New(t)
+
+	d, _ := newTestDebug(t, &block.TestVM{})
+
+	err := d.DecodeBlock(nil, &DecodeBlockArgs{
+		Chain: "P",
+		Block: encode(t, []byte{1}),
+	}, &DecodeBlockReply{})
+	require.ErrorIs(err, errUnknownChain)
+}
+
+func TestDecodeTxRequiresDAGChain(t *testing.T) {
+	require := require.New(t)
+
+	d, _ := newTestDebug(t, &block.TestVM{})
+
+	err := d.DecodeTx(nil, &DecodeTxArgs{
+		Chain: "X",
+		Tx:    encode(t, []byte{1}),
+	}, &DecodeTxReply{})
+	require.ErrorIs(err, errNotDAGChain)
+}
+
+func TestDecodeTxRequiresDecoder(t *testing.T) {
+	require := require.New(t)
+
+	d, _ := newTestDebug(t, &vertex.TestVM{})
+
+	err := d.DecodeTx(nil, &DecodeTxArgs{
+		Chain: "X",
+		Tx:    encode(t, []byte{1}),
+	}, &DecodeTxReply{})
+	require.ErrorIs(err, vertex.ErrDecoderNotImplemented)
+}
@@ -14,6 +14,30 @@ import (
 	"github.com/ava-labs/avalanchego/utils/logging"
 )
 
+// getOperation is the OpenAPI operation object of the GET requests served by
+// the handlers returned by NewGetHandler.
+var getOperation = stdjson.RawMessage(`{"summary":"Report the result of the health checks","responses":{"200":{"description":"The checks are passing","content":{"application/json":{}}},"503":{"description":"A check is failing","content":{"application/json":{}}}}}`)
+
+// GetOpenAPI returns the OpenAPI path item object, encoded as JSON, of the
+// handlers returned by NewGetHandler.
+func GetOpenAPI() []byte {
+	pathItem, _ := stdjson.Marshal(map[string]stdjson.RawMessage{
+		"get": getOperation,
+	})
+	return pathItem
+}
+
+// GetAndPostOpenAPI returns the OpenAPI path item object, encoded as JSON, of
+// the handler returned by NewGetAndPostHandler.
+func GetAndPostOpenAPI() []byte {
+	pathItem := make(map[string]stdjson.RawMessage)
+	// The path item is built from valid JSON.
+	_ = stdjson.Unmarshal(json.OpenAPIPathItem("Health API", "health", &Service{}), &pathItem)
+	pathItem["get"] = getOperation
+	b, _ := stdjson.Marshal(pathItem)
+	return b
+}
+
 // NewGetAndPostHandler returns a health handler that supports GET and jsonrpc
 // POST requests.
 func NewGetAndPostHandler(log logging.Logger, reporter Reporter) (http.Handler, error) {
@@ -60,8 +84,9 @@ func NewGetHandler(reporter func() (map[string]Result, bool)) http.Handler {
 		// The encoder will call write on the writer, which will write the
 		// header with a 200.
 		_ = stdjson.NewEncoder(w).Encode(APIHealthReply{
-			Checks:  checks,
-			Healthy: healthy,
+			Checks:     checks,
+			Healthy:    healthy,
+			RootCauses: RootCauses(checks),
 		})
 	})
 }
@@ -5,6 +5,7 @@ package health
 
 import (
 	"context"
+	"sort"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -27,10 +28,19 @@ type Health interface {
 }
 
 // Registerer defines how to register new components to check the health of.
+//
+// A check may declare the names of the checks it depends on. If a check and
+// one of its dependencies are both failing, the check is reported as degraded
+// by the root cause of the failure rather than as an independent failure.
 type Registerer interface {
-	RegisterReadinessCheck(name string, checker Checker) error
-	RegisterHealthCheck(name string, checker Checker) error
-	RegisterLivenessCheck(name string, checker Checker) error
+	// RegisterReadinessCheck registers a monotonic readiness check: once the
+	// check passes, it is reported as passing forever.
+	RegisterReadinessCheck(name string, checker Checker, dependencies ...string) error
+	// RegisterNonMonotonicReadinessCheck registers a readiness check that is
+	// reported as failing whenever it fails.
+	RegisterNonMonotonicReadinessCheck(name string, checker Checker, dependencies ...string) error
+	RegisterHealthCheck(name string, checker Checker, dependencies ...string) error
+	RegisterLivenessCheck(name string, checker Checker, dependencies ...string) error
 }
 
 // Reporter returns the current health status.
@@ -38,6 +48,10 @@ type Reporter interface {
 	Readiness() (map[string]Result, bool)
 	Health() (map[string]Result, bool)
 	Liveness() (map[string]Result, bool)
+
+	// History returns the most recent transitions of every kind of check,
+	// ordered by time.
+	History() []Transition
 }
 
 type health struct {
@@ -67,22 +81,27 @@ func New(log logging.Logger, registerer prometheus.Registerer) (Health, error) {
 	}, err
 }
 
-func (h *health) RegisterReadinessCheck(name string, checker Checker) error {
-	return h.readiness.RegisterMonotonicCheck(name, checker)
+func (h *health) RegisterReadinessCheck(name string, checker Checker, dependencies ...string) error {
+	return h.readiness.RegisterMonotonicCheck(name, checker, dependencies...)
+}
+
+func (h *health) RegisterNonMonotonicReadinessCheck(name string, checker Checker, dependencies ...string) error {
+	return h.readiness.RegisterCheck(name, checker, dependencies...)
 }
 
-func (h *health) RegisterHealthCheck(name string, checker Checker) error {
-	return h.health.RegisterCheck(name, checker)
+func (h *health) RegisterHealthCheck(name string, checker Checker, dependencies ...string) error {
+	return h.health.RegisterCheck(name, checker, dependencies...)
 }
 
-func (h *health) RegisterLivenessCheck(name string, checker Checker) error {
-	return h.liveness.RegisterCheck(name, checker)
+func (h *health) RegisterLivenessCheck(name string, checker Checker, dependencies ...string) error {
+	return h.liveness.RegisterCheck(name, checker, dependencies...)
 }
 
 func (h *health) Readiness() (map[string]Result, bool) {
 	results, healthy := h.readiness.Results()
 	if !healthy {
 		h.log.Warn("failing readiness check",
+			zap.Strings("rootCauses", RootCauses(results)),
 			zap.Reflect("reason", results),
 		)
 	}
@@ -93,6 +112,7 @@ func (h *health) Health() (map[string]Result, bool) {
 	results, healthy := h.health.Results()
 	if !healthy {
 		h.log.Warn("failing health check",
+			zap.Strings("rootCauses", RootCauses(results)),
 			zap.Reflect("reason", results),
 		)
 	}
@@ -103,12 +123,23 @@ func (h *health) Liveness() (map[string]Result, bool) {
 	results, healthy := h.liveness.Results()
 	if !healthy {
 		h.log.Warn("failing liveness check",
+			zap.Strings("rootCauses", RootCauses(results)),
 			zap.Reflect("reason", results),
 		)
 	}
 	return results, healthy
 }
 
+func (h *health) History() []Transition {
+	history := h.readiness.History()
+	history = append(history, h.health.History()...)
+	history = append(history, h.liveness.History()...)
+	sort.SliceStable(history, func(i, j int) bool {
+		return history[i].Timestamp.Before(history[j].Timestamp)
+	})
+	return history
+}
+
 func (h *health) Start(ctx context.Context, freq time.Duration) {
 	h.readiness.Start(ctx, freq)
 	h.health.Start(ctx, freq)
@@ -248,6 +248,47 @@ func TestPassingThenFailingChecks(t *testing.T) {
 	}
 }
 
+func TestNonMonotonicReadinessCheck(t *testing.T) {
+	require := require.New(t)
+
+	var (
+		shouldCheckErr utils.AtomicBool
+		checkErr       = errors.New("not ready")
+	)
+	check := CheckerFunc(func(context.Context) (interface{}, error) {
+		if shouldCheckErr.GetValue() {
+			return checkErr.Error(), checkErr
+		}
+		return "", nil
+	})
+
+	h, err := New(logging.NoLog{}, prometheus.NewRegistry())
+	require.NoError(err)
+
+	err = h.RegisterNonMonotonicReadinessCheck("check", check)
+	require.NoError(err)
+
+	h.Start(context.Background(), checkFreq)
+	defer h.Stop()
+
+	awaitReadiness(h)
+
+	shouldCheckErr.SetValue(true)
+
+	// Unlike monotonic checks, the check reports not ready again.
+	for {
+		_, readiness := h.Readiness()
+		if !readiness {
+			break
+		}
+		time.Sleep(awaitFreq)
+	}
+
+	shouldCheckErr.SetValue(false)
+
+	awaitReadiness(h)
+}
+
 func TestDeadlockRegression(t *testing.T) {
 	require := require.New(t)
 
@@ -274,3 +315,144 @@ func TestDeadlockRegression(t *testing.T) {
 
 	awaitHealthy(h, true)
 }
+
+func TestDependencyRootCauses(t *testing.T) {
+	require := require.New(t)
+
+	var (
+		diskErr  utils.AtomicBool
+		checkErr = errors.New("unhealthy")
+	)
+	disk := CheckerFunc(func(context.Context) (interface{}, error) {
+		if diskErr.GetValue() {
+			return nil, checkErr
+		}
+		return nil, nil
+	})
+	dependent := func(dependency string, h Reporter) Checker {
+		return CheckerFunc(func(context.Context) (interface{}, error) {
+			results, _ := h.Health()
+			if results[dependency].Error != nil {
+				return nil, checkErr
+			}
+			return nil, nil
+		})
+	}
+
+	h, err := New(logging.NoLog{}, prometheus.NewRegistry())
+	require.NoError(err)
+
+	require.NoError(h.RegisterHealthCheck("disk", disk))
+	require.NoError(h.RegisterHealthCheck("network", dependent("disk", h), "disk"))
+	require.NoError(h.RegisterHealthCheck("chain", dependent("network", h), "network"))
+
+	h.Start(context.Background(), checkFreq)
+	defer h.Stop()
+
+	awaitHealthy(h, true)
+
+	diskErr.SetValue(true)
+	for {
+		results, _ := h.Health()
+		if results["chain"].Error != nil {
+			break
+		}
+		time.Sleep(awaitFreq)
+	}
+
+	results, healthy := h.Health()
+	require.False(healthy)
+	require.Empty(results["disk"].DegradedBy)
+	require.Equal([]string{"disk"}, results["network"].DegradedBy)
+	require.Equal([]string{"disk"}, results["chain"].DegradedBy)
+	require.Equal([]string{"disk"}, RootCauses(results))
+}
+
+func TestDependencyOnPassingCheck(t *testing.T) {
+	require := require.New(t)
+
+	checkErr := errors.New("unhealthy")
+	h, err := New(logging.NoLog{}, prometheus.NewRegistry())
+	require.NoError(err)
+
+	require.NoError(h.RegisterHealthCheck("passing", CheckerFunc(func(context.Context) (interface{}, error) {
+		return nil, nil
+	})))
+	require.NoError(h.RegisterHealthCheck("failing", CheckerFunc(func(context.Context) (interface{}, error) {
+		return nil, checkErr
+	}), "passing", "unregistered"))
+
+	h.Start(context.Background(), checkFreq)
+	defer h.Stop()
+
+	for {
+		results, _ := h.Health()
+		if results["passing"].Error == nil {
+			break
+		}
+		time.Sleep(awaitFreq)
+	}
+
+	results, healthy := h.Health()
+	require.False(healthy)
+	require.Empty(results["failing"].DegradedBy)
+	require.Equal([]string{"failing"}, RootCauses(results))
+}
+
+func TestCyclicDependencies(t *testing.T) {
+	require := require.New(t)
+
+	check := CheckerFunc(func(context.Context) (interface{}, error) {
+		return nil, nil
+	})
+
+	h, err := New(logging.NoLog{}, prometheus.NewRegistry())
+	require.NoError(err)
+
+	require.NoError(h.RegisterHealthCheck("a", check, "b"))
+	require.NoError(h.RegisterHealthCheck("b", check, "c"))
+
+	err = h.RegisterHealthCheck("c", check, "a")
+	require.ErrorIs(err, errCyclicDependencies)
+
+	err = h.RegisterHealthCheck("d", check, "d")
+	require.ErrorIs(err, errCyclicDependencies)
+}
+
+func TestHistory(t *testing.T) {
+	require := require.New(t)
+
+	var (
+		shouldCheckErr utils.AtomicBool
+		checkErr       = errors.New("unhealthy")
+	)
+	check := CheckerFunc(func(context.Context) (interface{}, error) {
+		if shouldCheckErr.GetValue() {
+			return nil, checkErr
+		}
+		return nil, nil
+	})
+
+	h, err := New(logging.NoLog{}, prometheus.NewRegistry())
+	require.NoError(err)
+
+	err = h.RegisterHealthCheck("check", check)
+	require.NoError(err)
+
+	h.Start(context.Background(), checkFreq)
+	defer h.Stop()
+
+	awaitHealthy(h, true)
+	shouldCheckErr.SetValue(true)
+	awaitHealthy(h, false)
+
+	history := h.History()
+	require.Len(history, 2)
+	require.Equal("health", history[0].Kind)
+	require.Equal("check", history[0].Name)
+	require.Nil(history[0].Error)
+	require.Equal("check", history[1].Name)
+	require.NotNil(history[1].Error)
+	require.Equal(checkErr.Error(), *history[1].Error)
+	require.True(history[0].Timestamp.Before(history[1].Timestamp))
+}
@@ -0,0 +1,26 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package health
+
+import "time"
+
+// maxHistorySize is the maximum number of transitions each kind of check
+// remembers.
+const maxHistorySize = 128
+
+// Transition records a check starting or stopping to pass.
+type Transition struct {
+	// Kind of the check, either readiness, health, or liveness.
+	Kind string `json:"kind"`
+
+	// Name the check was registered with.
+	Name string `json:"name"`
+
+	// Error is the string representation of the error the check started
+	// failing with. The value is nil if the check started passing.
+	Error *string `json:"error,omitempty"`
+
+	// Timestamp of the first result after the transition.
+	Timestamp time.Time `json:"timestamp"`
+}
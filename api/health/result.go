@@ -4,6 +4,7 @@
 package health
 
 import (
+	"sort"
 	"time"
 )
 
@@ -37,4 +38,22 @@ type Result struct {
 
 	// TimeOfFirstFailure of the HealthCheck,
 	TimeOfFirstFailure *time.Time `json:"timeOfFirstFailure,omitempty"`
+
+	// DegradedBy is the set of failing checks this HealthCheck depends on that
+	// are the root cause of its failure. The value is nil if the check passed
+	// or if none of its dependencies are failing.
+	DegradedBy []string `json:"degradedBy,omitempty"`
+}
+
+// RootCauses returns the sorted names of the failing checks in [results] that
+// aren't degraded by a failing dependency.
+func RootCauses(results map[string]Result) []string {
+	var rootCauses []string
+	for name, result := range results {
+		if result.Error != nil && len(result.DegradedBy) == 0 {
+			rootCauses = append(rootCauses, name)
+		}
+	}
+	sort.Strings(rootCauses)
+	return rootCauses
 }
@@ -18,12 +18,16 @@ type Service struct {
 type APIHealthReply struct {
 	Checks  map[string]Result `json:"checks"`
 	Healthy bool              `json:"healthy"`
+	// RootCauses are the failing checks that aren't explained by the failure
+	// of one of their dependencies.
+	RootCauses []string `json:"rootCauses,omitempty"`
 }
 
 // Readiness returns if the node has finished initialization
 func (s *Service) Readiness(_ *http.Request, _ *struct{}, reply *APIHealthReply) error {
 	s.log.Debug("Health.readiness called")
 	reply.Checks, reply.Healthy = s.health.Readiness()
+	reply.RootCauses = RootCauses(reply.Checks)
 	return nil
 }
 
@@ -31,6 +35,7 @@ func (s *Service) Readiness(_ *http.Request, _ *struct{}, reply *APIHealthReply)
 func (s *Service) Health(_ *http.Request, _ *struct{}, reply *APIHealthReply) error {
 	s.log.Debug("Health.health called")
 	reply.Checks, reply.Healthy = s.health.Health()
+	reply.RootCauses = RootCauses(reply.Checks)
 	return nil
 }
 
@@ -38,5 +43,6 @@ func (s *Service) Health(_ *http.Request, _ *struct{}, reply *APIHealthReply) er
 func (s *Service) Liveness(_ *http.Request, _ *struct{}, reply *APIHealthReply) error {
 	s.log.Debug("Health.liveness called")
 	reply.Checks, reply.Healthy = s.health.Liveness()
+	reply.RootCauses = RootCauses(reply.Checks)
 	return nil
 }
@@ -7,6 +7,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sort"
 	"sync"
 	"time"
 
@@ -15,15 +16,25 @@ import (
 	"github.com/ava-labs/avalanchego/utils"
 )
 
-var errDuplicateCheck = errors.New("duplicated check")
+var (
+	errDuplicateCheck     = errors.New("duplicated check")
+	errCyclicDependencies = errors.New("cyclic check dependencies")
+)
 
 type worker struct {
+	kind       string
 	metrics    *metrics
 	checksLock sync.RWMutex
 	checks     map[string]Checker
+	// dependencies maps each check to the checks it depends on. Dependencies
+	// aren't required to be registered.
+	dependencies map[string][]string
 
 	resultsLock sync.RWMutex
 	results     map[string]Result
+	// history contains the most recent transitions of the checks, from the
+	// oldest to the newest.
+	history []Transition
 
 	startOnce sync.Once
 	closeOnce sync.Once
@@ -33,25 +44,35 @@ type worker struct {
 func newWorker(namespace string, registerer prometheus.Registerer) (*worker, error) {
 	metrics, err := newMetrics(namespace, registerer)
 	return &worker{
-		metrics: metrics,
-		checks:  make(map[string]Checker),
-		results: make(map[string]Result),
-		closer:  make(chan struct{}),
+		kind:         namespace,
+		metrics:      metrics,
+		checks:       make(map[string]Checker),
+		dependencies: make(map[string][]string),
+		results:      make(map[string]Result),
+		closer:       make(chan struct{}),
 	}, err
 }
 
-func (w *worker) RegisterCheck(name string, checker Checker) error {
+func (w *worker) RegisterCheck(name string, checker Checker, dependencies ...string) error {
 	w.checksLock.Lock()
 	defer w.checksLock.Unlock()
 
 	if _, ok := w.checks[name]; ok {
 		return fmt.Errorf("%w: %q", errDuplicateCheck, name)
 	}
+	for _, dependency := range dependencies {
+		if w.dependsOn(dependency, name, make(map[string]struct{})) {
+			return fmt.Errorf("%w: %q depends on %q", errCyclicDependencies, dependency, name)
+		}
+	}
 
 	w.resultsLock.Lock()
 	defer w.resultsLock.Unlock()
 
 	w.checks[name] = checker
+	if len(dependencies) > 0 {
+		w.dependencies[name] = dependencies
+	}
 	w.results[name] = notYetRunResult
 
 	// Whenever a new check is added - it is failing
@@ -59,7 +80,7 @@ func (w *worker) RegisterCheck(name string, checker Checker) error {
 	return nil
 }
 
-func (w *worker) RegisterMonotonicCheck(name string, checker Checker) error {
+func (w *worker) RegisterMonotonicCheck(name string, checker Checker, dependencies ...string) error {
 	var result utils.AtomicInterface
 	return w.RegisterCheck(name, CheckerFunc(func(ctx context.Context) (interface{}, error) {
 		details := result.GetValue()
@@ -72,22 +93,107 @@ func (w *worker) RegisterMonotonicCheck(name string, checker Checker) error {
 			result.SetValue(details)
 		}
 		return details, err
-	}))
+	}), dependencies...)
+}
+
+// dependsOn returns true if [name] transitively depends on [target].
+//
+// Assumes [w.checksLock] is held.
+func (w *worker) dependsOn(name, target string, visited map[string]struct{}) bool {
+	if name == target {
+		return true
+	}
+	if _, ok := visited[name]; ok {
+		return false
+	}
+	visited[name] = struct{}{}
+
+	for _, dependency := range w.dependencies[name] {
+		if w.dependsOn(dependency, target, visited) {
+			return true
+		}
+	}
+	return false
 }
 
+// Results returns the latest result of every check. Failing checks that
+// depend on another failing check are marked as degraded by the root causes
+// of the failure.
 func (w *worker) Results() (map[string]Result, bool) {
+	w.checksLock.RLock()
+	defer w.checksLock.RUnlock()
+
 	w.resultsLock.RLock()
 	defer w.resultsLock.RUnlock()
 
 	results := make(map[string]Result, len(w.results))
 	healthy := true
 	for name, result := range w.results {
+		if result.Error != nil {
+			rootCauses := make(map[string]struct{})
+			w.rootCauses(name, rootCauses, make(map[string]struct{}))
+			if len(rootCauses) > 0 {
+				result.DegradedBy = make([]string, 0, len(rootCauses))
+				for rootCause := range rootCauses {
+					result.DegradedBy = append(result.DegradedBy, rootCause)
+				}
+				sort.Strings(result.DegradedBy)
+			}
+		}
 		results[name] = result
 		healthy = healthy && result.Error == nil
 	}
 	return results, healthy
 }
 
+// rootCauses adds to [rootCauses] the failing checks that [name] transitively
+// depends on which don't have any failing dependencies themselves.
+//
+// Assumes [w.checksLock] and [w.resultsLock] are held.
+func (w *worker) rootCauses(name string, rootCauses, visited map[string]struct{}) {
+	for _, dependency := range w.dependencies[name] {
+		if _, ok := visited[dependency]; ok {
+			continue
+		}
+		visited[dependency] = struct{}{}
+
+		result, ok := w.results[dependency]
+		if !ok || result.Error == nil {
+			continue
+		}
+
+		if w.hasFailingDependency(dependency) {
+			w.rootCauses(dependency, rootCauses, visited)
+		} else {
+			rootCauses[dependency] = struct{}{}
+		}
+	}
+}
+
+// hasFailingDependency returns true if any direct dependency of [name] is
+// currently failing.
+//
+// Assumes [w.checksLock] and [w.resultsLock] are held.
+func (w *worker) hasFailingDependency(name string) bool {
+	for _, dependency := range w.dependencies[name] {
+		if result, ok := w.results[dependency]; ok && result.Error != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// History returns the most recent transitions of the checks, from the oldest
+// to the newest.
+func (w *worker) History() []Transition {
+	w.resultsLock.RLock()
+	defer w.resultsLock.RUnlock()
+
+	history := make([]Transition, len(w.history))
+	copy(history, w.history)
+	return history
+}
+
 func (w *worker) Start(ctx context.Context, freq time.Duration) {
 	w.startOnce.Do(func() {
 		go func() {
@@ -171,4 +277,24 @@ func (w *worker) runCheck(ctx context.Context, wg *sync.WaitGroup, name string,
 		w.metrics.failingChecks.Dec()
 	}
 	w.results[name] = result
+
+	// The first result is always recorded, so that checks that fail from the
+	// start show up in the history.
+	isFirstResult := prevResult.Timestamp.IsZero()
+	if isFirstResult || (prevResult.Error == nil) != (result.Error == nil) {
+		w.recordTransition(name, result)
+	}
+}
+
+// Assumes [w.resultsLock] is held.
+func (w *worker) recordTransition(name string, result Result) {
+	if len(w.history) >= maxHistorySize {
+		w.history = w.history[1:]
+	}
+	w.history = append(w.history, Transition{
+		Kind:      w.kind,
+		Name:      name,
+		Error:     result.Error,
+		Timestamp: result.Timestamp,
+	})
 }
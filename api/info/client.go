@@ -5,8 +5,11 @@ package info
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/attestation"
+	"github.com/ava-labs/avalanchego/utils/formatting"
 	"github.com/ava-labs/avalanchego/utils/rpc"
 	"github.com/ava-labs/avalanchego/vms/platformvm/signer"
 )
@@ -26,6 +29,9 @@ type Client interface {
 	GetTxFee(context.Context, ...rpc.Option) (*GetTxFeeResponse, error)
 	Uptime(context.Context, ...rpc.Option) (*UptimeResponse, error)
 	GetVMs(context.Context, ...rpc.Option) (map[ids.ID][]string, error)
+	GetVMDetails(context.Context, ...rpc.Option) ([]VMDetails, error)
+	GetVMVersionStake(context.Context, ids.ID, ...rpc.Option) (*GetVMVersionStakeReply, error)
+	GetAttestation(context.Context, []byte, ...rpc.Option) (ids.NodeID, *attestation.Evidence, error)
 }
 
 // Client implementation for an Info API Client
@@ -109,3 +115,39 @@ func (c *client) GetVMs(ctx context.Context, options ...rpc.Option) (map[ids.ID]
 	err := c.requester.SendRequest(ctx, "info.getVMs", struct{}{}, res, options...)
 	return res.VMs, err
 }
+
+func (c *client) GetVMDetails(ctx context.Context, options ...rpc.Option) ([]VMDetails, error) {
+	res := &GetVMDetailsReply{}
+	err := c.requester.SendRequest(ctx, "info.getVMDetails", struct{}{}, res, options...)
+	return res.VMs, err
+}
+
+func (c *client) GetVMVersionStake(ctx context.Context, subnetID ids.ID, options ...rpc.Option) (*GetVMVersionStakeReply, error) {
+	res := &GetVMVersionStakeReply{}
+	err := c.requester.SendRequest(ctx, "info.getVMVersionStake", &GetVMVersionStakeArgs{
+		SubnetID: subnetID,
+	}, res, options...)
+	return res, err
+}
+
+func (c *client) GetAttestation(ctx context.Context, nonce []byte, options ...rpc.Option) (ids.NodeID, *attestation.Evidence, error) {
+	nonceStr, err := formatting.Encode(formatting.Hex, nonce)
+	if err != nil {
+		return ids.EmptyNodeID, nil, err
+	}
+	res := &GetAttestationReply{}
+	err = c.requester.SendRequest(ctx, "info.getAttestation", &GetAttestationArgs{
+		Nonce: nonceStr,
+	}, res, options...)
+	if err != nil {
+		return ids.EmptyNodeID, nil, err
+	}
+	report, err := formatting.Decode(formatting.Hex, res.Report)
+	if err != nil {
+		return ids.EmptyNodeID, nil, fmt.Errorf("couldn't decode report: %w", err)
+	}
+	return res.NodeID, &attestation.Evidence{
+		Type:   res.Type,
+		Report: report,
+	}, nil
+}
@@ -4,12 +4,15 @@
 package info
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"net/http"
+	"sort"
 
 	"github.com/gorilla/rpc/v2"
 
+	"github.com/ava-labs/avalanchego/api/pagination"
 	"github.com/ava-labs/avalanchego/chains"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/network"
@@ -17,7 +20,9 @@ import (
 	"github.com/ava-labs/avalanchego/snow/engine/common"
 	"github.com/ava-labs/avalanchego/snow/networking/benchlist"
 	"github.com/ava-labs/avalanchego/snow/validators"
+	"github.com/ava-labs/avalanchego/utils/attestation"
 	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/formatting"
 	"github.com/ava-labs/avalanchego/utils/ips"
 	"github.com/ava-labs/avalanchego/utils/json"
 	"github.com/ava-labs/avalanchego/utils/logging"
@@ -26,9 +31,21 @@ import (
 	"github.com/ava-labs/avalanchego/vms/platformvm/signer"
 )
 
+const peersEndpoint = "info.peers"
+
+// MaxPageSizeBounds bounds the number of peers info.peers returns per page.
+var MaxPageSizeBounds = pagination.Limits{
+	peersEndpoint: pagination.DefaultMaxPageSize,
+}
+
 var (
 	errNoChainProvided = errors.New("argument 'chain' not given")
 	errNotValidator    = errors.New("this is not a validator node")
+
+	errVMVersionsUnavailable = errors.New("VM version telemetry is disabled, or the subnet has no validators")
+	errAttestationDisabled   = errors.New("attestation is disabled")
+	errMissingNonce          = errors.New("argument 'nonce' not given")
+	errNonceTooLong          = errors.New("nonce too long")
 )
 
 // Info is the API service for unprivileged info on a node
@@ -58,6 +75,11 @@ type Parameters struct {
 	AddSubnetValidatorFee         uint64
 	AddSubnetDelegatorFee         uint64
 	VMManager                     vms.Manager
+	// Overrides the maximum page size of the paginated API endpoints
+	MaxPageSizes pagination.Limits
+	// Produces the attestation evidence of the node. Nil if attestation is
+	// disabled.
+	Attester attestation.Attester
 }
 
 // NewService returns a new admin API service
@@ -87,7 +109,10 @@ func NewService(
 	}, "info"); err != nil {
 		return nil, err
 	}
-	return &common.HTTPHandler{Handler: newServer}, nil
+	return &common.HTTPHandler{
+		Handler: newServer,
+		OpenAPI: json.PaginatedOpenAPIPathItem("Info API", "info", &Info{}, parameters.MaxPageSizes.Effective(MaxPageSizeBounds)),
+	}, nil
 }
 
 // GetNodeVersionReply are the results from calling GetNodeVersion
@@ -192,6 +217,11 @@ func (service *Info) GetBlockchainID(_ *http.Request, args *GetBlockchainIDArgs,
 // PeersArgs are the arguments for calling Peers
 type PeersArgs struct {
 	NodeIDs []ids.NodeID `json:"nodeIDs"`
+	// Limit is the maximum number of peers to return. If neither [Limit] nor
+	// [Cursor] is provided, all the peers are returned.
+	Limit json.Uint32 `json:"limit"`
+	// Cursor is the value returned by the previous call to Peers
+	Cursor string `json:"cursor"`
 }
 
 type Peer struct {
@@ -206,6 +236,9 @@ type PeersReply struct {
 	NumPeers json.Uint64 `json:"numPeers"`
 	// Each element is a peer
 	Peers []Peer `json:"peers"`
+	// Cursor to provide to fetch the next page. Empty if there are no more
+	// peers.
+	Cursor string `json:"cursor,omitempty"`
 }
 
 // Peers returns the list of current validators
@@ -213,6 +246,18 @@ func (service *Info) Peers(_ *http.Request, args *PeersArgs, reply *PeersReply)
 	service.log.Debug("Info: Peers called")
 
 	peers := service.networking.PeerInfo(args.NodeIDs)
+	if args.Limit != 0 || args.Cursor != "" {
+		pageSize := pagination.PageSize(
+			uint64(args.Limit),
+			service.MaxPageSizes.MaxPageSize(peersEndpoint, MaxPageSizeBounds[peersEndpoint]),
+		)
+		var err error
+		peers, reply.Cursor, err = paginatePeers(peers, args.Cursor, pageSize)
+		if err != nil {
+			return err
+		}
+	}
+
 	peerInfo := make([]Peer, len(peers))
 	for i, peer := range peers {
 		peerInfo[i] = Peer{
@@ -226,6 +271,37 @@ func (service *Info) Peers(_ *http.Request, args *PeersArgs, reply *PeersReply)
 	return nil
 }
 
+// paginatePeers returns at most [pageSize] of [peers], ordered by node ID,
+// starting after [cursor]. If there are more peers left, the cursor of the
+// next page is returned.
+func paginatePeers(peers []peer.Info, cursor string, pageSize uint64) ([]peer.Info, string, error) {
+	sort.Slice(peers, func(i, j int) bool {
+		return bytes.Compare(peers[i].ID[:], peers[j].ID[:]) < 0
+	})
+
+	if cursor != "" {
+		cursorBytes, err := pagination.DecodeCursor(cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		start, err := ids.ToNodeID(cursorBytes)
+		if err != nil {
+			return nil, "", fmt.Errorf("couldn't parse cursor: %w", err)
+		}
+		i := sort.Search(len(peers), func(i int) bool {
+			return bytes.Compare(peers[i].ID[:], start[:]) > 0
+		})
+		peers = peers[i:]
+	}
+
+	if uint64(len(peers)) <= pageSize {
+		return peers, "", nil
+	}
+	peers = peers[:pageSize]
+	lastID := peers[len(peers)-1].ID
+	return peers, pagination.EncodeCursor(lastID[:]), nil
+}
+
 // IsBootstrappedArgs are the arguments for calling IsBootstrapped
 type IsBootstrappedArgs struct {
 	// Alias of the chain
@@ -285,6 +361,53 @@ func (service *Info) Uptime(_ *http.Request, _ *struct{}, reply *UptimeResponse)
 	return nil
 }
 
+// GetVMVersionStakeArgs are the arguments for calling GetVMVersionStake
+type GetVMVersionStakeArgs struct {
+	SubnetID ids.ID `json:"subnetID"`
+}
+
+// VMVersionStake is the stake of the validators reporting a version of a VM
+type VMVersionStake struct {
+	Weight json.Uint64 `json:"weight"`
+	// Percentage of the stake of the subnet
+	Percentage json.Float64 `json:"percentage"`
+}
+
+// GetVMVersionStakeReply are the results from calling GetVMVersionStake
+type GetVMVersionStakeReply struct {
+	// TotalWeight is the stake of all the validators of the subnet. Stake of
+	// validators that aren't connected, or didn't report their VM versions,
+	// isn't attributed to any version.
+	TotalWeight json.Uint64 `json:"totalWeight"`
+	// VM ID -> version -> stake reporting the version
+	VMs map[ids.ID]map[string]VMVersionStake `json:"vms"`
+}
+
+// GetVMVersionStake returns the share of the stake of a subnet that runs each
+// version of a VM, as reported by the validators this node is connected to.
+func (service *Info) GetVMVersionStake(_ *http.Request, args *GetVMVersionStakeArgs, reply *GetVMVersionStakeReply) error {
+	service.log.Debug("Info: GetVMVersionStake called")
+
+	result, ok := service.networking.VMVersions(args.SubnetID)
+	if !ok {
+		return errVMVersionsUnavailable
+	}
+
+	reply.TotalWeight = json.Uint64(result.TotalWeight)
+	reply.VMs = make(map[ids.ID]map[string]VMVersionStake, len(result.Weights))
+	for vmID, versionWeights := range result.Weights {
+		versions := make(map[string]VMVersionStake, len(versionWeights))
+		for version, weight := range versionWeights {
+			versions[version] = VMVersionStake{
+				Weight:     json.Uint64(weight),
+				Percentage: json.Float64(100 * float64(weight) / float64(result.TotalWeight)),
+			}
+		}
+		reply.VMs[vmID] = versions
+	}
+	return nil
+}
+
 type GetTxFeeResponse struct {
 	TxFee json.Uint64 `json:"txFee"`
 	// TODO: remove [CreationTxFee] after enough time for dependencies to update
@@ -332,3 +455,113 @@ func (service *Info) GetVMs(_ *http.Request, _ *struct{}, reply *GetVMsReply) er
 	reply.VMs, err = ids.GetRelevantAliases(service.VMManager, vmIDs)
 	return err
 }
+
+// VMDetails describes a VM installed on the node
+type VMDetails struct {
+	ID      ids.ID   `json:"id"`
+	Aliases []string `json:"aliases"`
+	// Version reported by the VM. Empty if the VM didn't report one.
+	Version string `json:"version,omitempty"`
+	// Path of the plugin binary. Empty if the VM is built into the node.
+	Path string `json:"path,omitempty"`
+	// Version of the rpcchainvm protocol spoken by the plugin. 0 if the VM is
+	// built into the node, or if the plugin doesn't report it.
+	ProtocolVersion json.Uint32 `json:"protocolVersion"`
+	// Names of the optional interfaces the VM supports
+	Interfaces []string `json:"interfaces"`
+}
+
+// GetVMDetailsReply contains the response metadata for GetVMDetails
+type GetVMDetailsReply struct {
+	VMs []VMDetails `json:"vms"`
+}
+
+// GetVMDetails describes the virtual machines installed on the node, sorted by
+// ID
+func (service *Info) GetVMDetails(_ *http.Request, _ *struct{}, reply *GetVMDetailsReply) error {
+	service.log.Debug("Info: GetVMDetails called")
+
+	vmIDs, err := service.VMManager.ListFactories()
+	if err != nil {
+		return err
+	}
+	ids.SortIDs(vmIDs)
+
+	aliases, err := ids.GetRelevantAliases(service.VMManager, vmIDs)
+	if err != nil {
+		return err
+	}
+	descriptions, err := service.VMManager.Descriptions()
+	if err != nil {
+		return err
+	}
+
+	reply.VMs = make([]VMDetails, len(vmIDs))
+	for i, vmID := range vmIDs {
+		description := descriptions[vmID]
+		interfaces := description.Interfaces
+		if interfaces == nil {
+			interfaces = []string{}
+		}
+		reply.VMs[i] = VMDetails{
+			ID:              vmID,
+			Aliases:         aliases[vmID],
+			Version:         description.Version,
+			Path:            description.Path,
+			ProtocolVersion: json.Uint32(description.ProtocolVersion),
+			Interfaces:      interfaces,
+		}
+	}
+	return nil
+}
+
+// GetAttestationArgs are the arguments for calling GetAttestation
+type GetAttestationArgs struct {
+	// Hex encoded nonce chosen by the verifier
+	Nonce string `json:"nonce"`
+}
+
+// GetAttestationReply are the results from calling GetAttestation
+type GetAttestationReply struct {
+	NodeID ids.NodeID `json:"nodeID"`
+	// Type of the evidence, either attestation.TypeSEVSNP or
+	// attestation.TypeTDX
+	Type string `json:"type"`
+	// Hex encoded attestation report, whose report data binds it to [NodeID]
+	// and to the nonce
+	Report string `json:"report"`
+}
+
+// GetAttestation returns hardware attestation evidence that this node runs in
+// a confidential VM. The evidence is bound to the node ID and to the nonce, so
+// that it can be verified with attestation.Verify. SEV-SNP evidence is verified
+// by attestation.SEVSNPVerifier, and TDX evidence is verified off-node.
+func (service *Info) GetAttestation(_ *http.Request, args *GetAttestationArgs, reply *GetAttestationReply) error {
+	service.log.Debug("Info: GetAttestation called")
+
+	if service.Attester == nil {
+		return errAttestationDisabled
+	}
+	nonce, err := formatting.Decode(formatting.Hex, args.Nonce)
+	if err != nil {
+		return fmt.Errorf("couldn't decode nonce: %w", err)
+	}
+	switch {
+	case len(nonce) == 0:
+		return errMissingNonce
+	case len(nonce) > attestation.MaxNonceLen:
+		return fmt.Errorf("%w: %d > %d", errNonceTooLong, len(nonce), attestation.MaxNonceLen)
+	}
+
+	evidence, err := service.Attester.Attest(attestation.ReportData(service.NodeID, nonce))
+	if err != nil {
+		return fmt.Errorf("couldn't attest: %w", err)
+	}
+	reply.Report, err = formatting.Encode(formatting.Hex, evidence.Report)
+	if err != nil {
+		return fmt.Errorf("couldn't encode report: %w", err)
+	}
+	reply.NodeID = service.NodeID
+	reply.Type = evidence.Type
+	return nil
+}
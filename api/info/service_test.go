@@ -12,6 +12,9 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/network/peer"
+	"github.com/ava-labs/avalanchego/utils/attestation"
+	"github.com/ava-labs/avalanchego/utils/formatting"
 	"github.com/ava-labs/avalanchego/utils/logging"
 	"github.com/ava-labs/avalanchego/vms"
 )
@@ -107,3 +110,124 @@ func TestGetVMsGetAliasesFails(t *testing.T) {
 
 	require.Equal(t, err, errOops)
 }
+
+func TestPaginatePeers(t *testing.T) {
+	require := require.New(t)
+
+	nodeIDs := []ids.NodeID{
+		{3},
+		{1},
+		{2},
+	}
+	peers := make([]peer.Info, len(nodeIDs))
+	for i, nodeID := range nodeIDs {
+		peers[i].ID = nodeID
+	}
+
+	page, cursor, err := paginatePeers(peers, "", 2)
+	require.NoError(err)
+	require.Len(page, 2)
+	require.Equal(ids.NodeID{1}, page[0].ID)
+	require.Equal(ids.NodeID{2}, page[1].ID)
+	require.NotEmpty(cursor)
+
+	page, cursor, err = paginatePeers(peers, cursor, 2)
+	require.NoError(err)
+	require.Len(page, 1)
+	require.Equal(ids.NodeID{3}, page[0].ID)
+	require.Empty(cursor)
+
+	_, _, err = paginatePeers(peers, "not a cursor!", 2)
+	require.Error(err)
+}
+
+type testAttester struct {
+	reportData [attestation.ReportDataLen]byte
+}
+
+func (a *testAttester) Attest(reportData [attestation.ReportDataLen]byte) (*attestation.Evidence, error) {
+	a.reportData = reportData
+	return &attestation.Evidence{
+		Type:   attestation.TypeSEVSNP,
+		Report: reportData[:],
+	}, nil
+}
+
+func TestGetAttestation(t *testing.T) {
+	require := require.New(t)
+
+	service := Info{
+		Parameters: Parameters{
+			NodeID: ids.GenerateTestNodeID(),
+		},
+		log: logging.NoLog{},
+	}
+	nonce := []byte{1, 2, 3}
+	nonceStr, err := formatting.Encode(formatting.Hex, nonce)
+	require.NoError(err)
+
+	reply := GetAttestationReply{}
+	err = service.GetAttestation(nil, &GetAttestationArgs{Nonce: nonceStr}, &reply)
+	require.ErrorIs(err, errAttestationDisabled)
+
+	attester := &testAttester{}
+	service.Attester = attester
+	require.NoError(service.GetAttestation(nil, &GetAttestationArgs{Nonce: nonceStr}, &reply))
+	require.Equal(attestation.ReportData(service.NodeID, nonce), attester.reportData)
+	require.Equal(service.NodeID, reply.NodeID)
+	require.Equal(attestation.TypeSEVSNP, reply.Type)
+	report, err := formatting.Decode(formatting.Hex, reply.Report)
+	require.NoError(err)
+	require.Equal(attester.reportData[:], report)
+
+	emptyNonceStr, err := formatting.Encode(formatting.Hex, nil)
+	require.NoError(err)
+	err = service.GetAttestation(nil, &GetAttestationArgs{Nonce: emptyNonceStr}, &reply)
+	require.ErrorIs(err, errMissingNonce)
+
+	longNonceStr, err := formatting.Encode(formatting.Hex, make([]byte, attestation.MaxNonceLen+1))
+	require.NoError(err)
+	err = service.GetAttestation(nil, &GetAttestationArgs{Nonce: longNonceStr}, &reply)
+	require.ErrorIs(err, errNonceTooLong)
+}
+
+func TestGetVMDetails(t *testing.T) {
+	require := require.New(t)
+
+	resources := initGetVMsTest(t)
+	defer resources.ctrl.Finish()
+
+	builtinID := ids.ID{1}
+	pluginID := ids.ID{2}
+
+	resources.mockLog.EXPECT().Debug(gomock.Any()).Times(1)
+	resources.mockVMManager.EXPECT().ListFactories().Times(1).Return([]ids.ID{pluginID, builtinID}, nil)
+	resources.mockVMManager.EXPECT().Aliases(builtinID).Times(1).Return([]string{builtinID.String(), "builtin"}, nil)
+	resources.mockVMManager.EXPECT().Aliases(pluginID).Times(1).Return([]string{pluginID.String()}, nil)
+	resources.mockVMManager.EXPECT().Descriptions().Times(1).Return(map[ids.ID]vms.Description{
+		pluginID: {
+			Version:         "v1.2.3",
+			Path:            "/plugins/vm",
+			ProtocolVersion: 20,
+			Interfaces:      []string{"batchedChainVM"},
+		},
+	}, nil)
+
+	reply := GetVMDetailsReply{}
+	require.NoError(resources.info.GetVMDetails(nil, nil, &reply))
+	require.Equal([]VMDetails{
+		{
+			ID:         builtinID,
+			Aliases:    []string{"builtin"},
+			Interfaces: []string{},
+		},
+		{
+			ID:              pluginID,
+			Aliases:         []string{},
+			Version:         "v1.2.3",
+			Path:            "/plugins/vm",
+			ProtocolVersion: 20,
+			Interfaces:      []string{"batchedChainVM"},
+		},
+	}, reply.VMs)
+}
@@ -23,12 +23,16 @@ var _ keystorepb.KeystoreServer = (*Server)(nil)
 type Server struct {
 	keystorepb.UnsafeKeystoreServer
 	ks keystore.BlockchainKeystore
+	// If true, the databases are served on unix domain sockets
+	unixSockets bool
 }
 
-// NewServer returns a keystore connected to a remote keystore
-func NewServer(ks keystore.BlockchainKeystore) *Server {
+// NewServer returns a keystore connected to a remote keystore. The databases
+// of the keystore are served on unix domain sockets if [unixSockets] is true.
+func NewServer(ks keystore.BlockchainKeystore, unixSockets bool) *Server {
 	return &Server{
-		ks: ks,
+		ks:          ks,
+		unixSockets: unixSockets,
 	}
 }
 
@@ -44,7 +48,7 @@ func (s *Server) GetDatabase(
 	closer := dbCloser{Database: db}
 
 	// start the db server
-	serverListener, err := grpcutils.NewListener()
+	serverListener, err := grpcutils.NewLocalListener(s.unixSockets)
 	if err != nil {
 		return nil, err
 	}
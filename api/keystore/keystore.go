@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"net/http"
 	"sync"
+	"time"
 
 	"github.com/gorilla/rpc/v2"
 
@@ -20,6 +21,7 @@ import (
 	"github.com/ava-labs/avalanchego/utils/json"
 	"github.com/ava-labs/avalanchego/utils/logging"
 	"github.com/ava-labs/avalanchego/utils/password"
+	"github.com/ava-labs/avalanchego/utils/timer/mockable"
 )
 
 const (
@@ -41,6 +43,9 @@ type Keystore interface {
 	// Create the API endpoint for this keystore.
 	CreateHandler() (http.Handler, error)
 
+	// Create the API endpoint for the sessions of this keystore.
+	CreateSessionHandler() (http.Handler, error)
+
 	// NewBlockchainKeyStore returns this keystore limiting the functionality to
 	// a single blockchain database.
 	NewBlockchainKeyStore(blockchainID ids.ID) BlockchainKeystore
@@ -73,6 +78,15 @@ type Keystore interface {
 	// with encrypted database values.
 	ExportUser(username, pw string) ([]byte, error)
 
+	// OpenSession loads a serialized encoding of a user's information, as
+	// returned by ExportUser, into memory for [ttl]. Returns the name the
+	// session can be accessed with in place of a username, and when the
+	// session expires. The password is integrity checked.
+	OpenSession(pw string, user []byte, ttl time.Duration) (string, time.Time, error)
+
+	// CloseSession ends the session before it expires.
+	CloseSession(session, pw string) error
+
 	// Get the password that is used by [username]. If [username] doesn't exist,
 	// no error is returned and a nil password hash is returned.
 	getPassword(username string) (*password.Hash, error)
@@ -90,13 +104,18 @@ type user struct {
 }
 
 type keystore struct {
-	lock sync.Mutex
-	log  logging.Logger
+	lock  sync.Mutex
+	log   logging.Logger
+	clock mockable.Clock
 
 	// Key: username
 	// Value: The hash of that user's password
 	usernameToPassword map[string]*password.Hash
 
+	// Key: session name
+	// Value: The user loaded by that session
+	sessions map[string]*session
+
 	// Used to persist users and their data
 	userDB database.Database
 	bcDB   database.Database
@@ -114,6 +133,7 @@ func New(log logging.Logger, dbManager manager.Manager) Keystore {
 	return &keystore{
 		log:                log,
 		usernameToPassword: make(map[string]*password.Hash),
+		sessions:           make(map[string]*session),
 		userDB:             prefixdb.New(usersPrefix, currentDB.Database),
 		bcDB:               prefixdb.New(bcsPrefix, currentDB.Database),
 	}
@@ -153,6 +173,17 @@ func (ks *keystore) GetRawDatabase(bID ids.ID, username, pw string) (database.Da
 	ks.lock.Lock()
 	defer ks.lock.Unlock()
 
+	s, err := ks.getSession(username)
+	if err != nil {
+		return nil, err
+	}
+	if s != nil {
+		if !s.Check(pw) {
+			return nil, fmt.Errorf("incorrect password for session %q", username)
+		}
+		return prefixdb.NewNested(bID[:], s.db), nil
+	}
+
 	passwordHash, err := ks.getPassword(username)
 	if err != nil {
 		return nil, err
@@ -181,7 +212,7 @@ func (ks *keystore) CreateUser(username, pw string) error {
 	if err != nil {
 		return err
 	}
-	if passwordHash != nil {
+	if _, isSession := ks.sessions[username]; passwordHash != nil || isSession {
 		return fmt.Errorf("user already exists: %s", username)
 	}
 
@@ -289,7 +320,7 @@ func (ks *keystore) ImportUser(username, pw string, userBytes []byte) error {
 	if err != nil {
 		return err
 	}
-	if passwordHash != nil {
+	if _, isSession := ks.sessions[username]; passwordHash != nil || isSession {
 		return fmt.Errorf("user already exists: %s", username)
 	}
 
@@ -0,0 +1,159 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package keystore
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/rpc/v2"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/json"
+	"github.com/ava-labs/avalanchego/utils/password"
+)
+
+const (
+	// DefaultSessionTTL is the duration of a session if none is requested
+	DefaultSessionTTL = 15 * time.Minute
+
+	// MaxSessionTTL is the maximum duration of a session
+	MaxSessionTTL = time.Hour
+
+	// maxSessions is the maximum number of sessions that can be open at once
+	maxSessions = 1024
+)
+
+var (
+	errInvalidSessionTTL = fmt.Errorf("session TTL must be in (0, %s]", MaxSessionTTL)
+	errTooManySessions   = fmt.Errorf("can't open more than %d sessions", maxSessions)
+	errSessionExpired    = errors.New("session expired")
+)
+
+// session is a keystore user whose data is only held in memory, until the
+// session expires or is closed.
+type session struct {
+	password.Hash
+	// The user's data, laid out as in the persisted blockchain databases
+	db         database.Database
+	expiration time.Time
+}
+
+// OpenSession loads the serialized user [userBytes], as returned by
+// ExportUser, into memory for [ttl]. The returned session ID can be used in
+// place of a username, along with the user's password, to access the user's
+// data from every blockchain. Data written during the session is discarded
+// when the session ends, and nothing is written to the keystore's database.
+func (ks *keystore) OpenSession(pw string, userBytes []byte, ttl time.Duration) (string, time.Time, error) {
+	if ttl <= 0 || ttl > MaxSessionTTL {
+		return "", time.Time{}, errInvalidSessionTTL
+	}
+
+	userData := user{}
+	if _, err := c.Unmarshal(userBytes, &userData); err != nil {
+		return "", time.Time{}, err
+	}
+	if !userData.Hash.Check(pw) {
+		return "", time.Time{}, errors.New("incorrect password for session")
+	}
+
+	db := memdb.New()
+	for _, kvp := range userData.Data {
+		if err := db.Put(kvp.Key, kvp.Value); err != nil {
+			return "", time.Time{}, fmt.Errorf("error on database put: %w", err)
+		}
+	}
+
+	ks.lock.Lock()
+	defer ks.lock.Unlock()
+
+	ks.removeExpiredSessions()
+	if len(ks.sessions) >= maxSessions {
+		return "", time.Time{}, errTooManySessions
+	}
+
+	var sessionID ids.ID
+	if _, err := rand.Read(sessionID[:]); err != nil {
+		return "", time.Time{}, err
+	}
+	sessionName := sessionID.String()
+	passwordHash, err := ks.getPassword(sessionName)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	if passwordHash != nil {
+		return "", time.Time{}, fmt.Errorf("user already exists: %s", sessionName)
+	}
+
+	expiration := ks.clock.Time().Add(ttl)
+	ks.sessions[sessionName] = &session{
+		Hash:       userData.Hash,
+		db:         db,
+		expiration: expiration,
+	}
+	return sessionName, expiration, nil
+}
+
+// CloseSession ends the session [sessionName] and discards its data.
+func (ks *keystore) CloseSession(sessionName, pw string) error {
+	ks.lock.Lock()
+	defer ks.lock.Unlock()
+
+	s, err := ks.getSession(sessionName)
+	if err != nil {
+		return err
+	}
+	if s == nil {
+		return fmt.Errorf("session doesn't exist: %s", sessionName)
+	}
+	if !s.Check(pw) {
+		return fmt.Errorf("incorrect password for session %q", sessionName)
+	}
+	delete(ks.sessions, sessionName)
+	return s.db.Close()
+}
+
+// getSession returns the session named [sessionName]. If no such session is
+// open, no error is returned and a nil session is returned.
+//
+// Assumes [ks.lock] is held.
+func (ks *keystore) getSession(sessionName string) (*session, error) {
+	s, ok := ks.sessions[sessionName]
+	if !ok {
+		return nil, nil
+	}
+	if !ks.clock.Time().Before(s.expiration) {
+		delete(ks.sessions, sessionName)
+		_ = s.db.Close()
+		return nil, fmt.Errorf("%w: %s", errSessionExpired, sessionName)
+	}
+	return s, nil
+}
+
+// Assumes [ks.lock] is held.
+func (ks *keystore) removeExpiredSessions() {
+	now := ks.clock.Time()
+	for sessionName, s := range ks.sessions {
+		if !now.Before(s.expiration) {
+			delete(ks.sessions, sessionName)
+			_ = s.db.Close()
+		}
+	}
+}
+
+func (ks *keystore) CreateSessionHandler() (http.Handler, error) {
+	newServer := rpc.NewServer()
+	codec := json.NewCodec()
+	newServer.RegisterCodec(codec, "application/json")
+	newServer.RegisterCodec(codec, "application/json;charset=UTF-8")
+	if err := newServer.RegisterService(&sessionService{ks: ks}, "session"); err != nil {
+		return nil, err
+	}
+	return newServer, nil
+}
@@ -0,0 +1,65 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package keystore
+
+import (
+	"context"
+	"time"
+
+	"github.com/ava-labs/avalanchego/api"
+	"github.com/ava-labs/avalanchego/utils/formatting"
+	"github.com/ava-labs/avalanchego/utils/json"
+	"github.com/ava-labs/avalanchego/utils/rpc"
+)
+
+var _ SessionClient = (*sessionClient)(nil)
+
+// SessionClient interface for Avalanche Keystore Session API Endpoint
+type SessionClient interface {
+	// Open loads [exportedUser] into memory for [ttl]. Returns the name to use
+	// in place of a username while the session is open, and when the session
+	// expires.
+	Open(ctx context.Context, password string, exportedUser []byte, ttl time.Duration, options ...rpc.Option) (string, time.Time, error)
+	// Close ends the given session
+	Close(ctx context.Context, session string, password string, options ...rpc.Option) error
+}
+
+// SessionClient implementation for Avalanche Keystore Session API Endpoint
+type sessionClient struct {
+	requester rpc.EndpointRequester
+}
+
+func NewSessionClient(uri string) SessionClient {
+	return &sessionClient{requester: rpc.NewEndpointRequester(
+		uri + "/ext/keystore/session",
+	)}
+}
+
+func (c *sessionClient) Open(
+	ctx context.Context,
+	password string,
+	exportedUser []byte,
+	ttl time.Duration,
+	options ...rpc.Option,
+) (string, time.Time, error) {
+	userStr, err := formatting.Encode(formatting.Hex, exportedUser)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	res := &OpenSessionReply{}
+	err = c.requester.SendRequest(ctx, "session.open", &OpenSessionArgs{
+		Password: password,
+		User:     userStr,
+		Encoding: formatting.Hex,
+		TTL:      json.Uint64(ttl / time.Second),
+	}, res, options...)
+	return res.Session, res.Expiration, err
+}
+
+func (c *sessionClient) Close(ctx context.Context, session string, password string, options ...rpc.Option) error {
+	return c.requester.SendRequest(ctx, "session.close", &CloseSessionArgs{
+		Session:  session,
+		Password: password,
+	}, &api.EmptyReply{}, options...)
+}
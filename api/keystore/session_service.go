@@ -0,0 +1,73 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package keystore
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/ava-labs/avalanchego/api"
+	"github.com/ava-labs/avalanchego/utils/formatting"
+	"github.com/ava-labs/avalanchego/utils/json"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+type sessionService struct {
+	ks *keystore
+}
+
+type OpenSessionArgs struct {
+	// The password of the user being loaded
+	Password string `json:"password"`
+	// The string representation of the user, as returned by ExportUser
+	User string `json:"user"`
+	// The encoding of [User] ("hex")
+	Encoding formatting.Encoding `json:"encoding"`
+	// The duration of the session, in seconds. If 0, DefaultSessionTTL is used.
+	TTL json.Uint64 `json:"ttl"`
+}
+
+type OpenSessionReply struct {
+	// The name to use in place of a username while the session is open
+	Session    string    `json:"session"`
+	Expiration time.Time `json:"expiration"`
+}
+
+// Open loads an exported user into memory for a limited time. The session can
+// be used in place of a username by the APIs that sign transactions, without
+// the user ever being written to the node's database.
+func (s *sessionService) Open(_ *http.Request, args *OpenSessionArgs, reply *OpenSessionReply) error {
+	s.ks.log.Debug("Keystore: OpenSession called")
+
+	user, err := formatting.Decode(args.Encoding, args.User)
+	if err != nil {
+		return fmt.Errorf("couldn't decode 'user' to bytes: %w", err)
+	}
+
+	ttl := DefaultSessionTTL
+	if args.TTL != 0 {
+		if uint64(args.TTL) > uint64(MaxSessionTTL/time.Second) {
+			return errInvalidSessionTTL
+		}
+		ttl = time.Duration(args.TTL) * time.Second
+	}
+
+	reply.Session, reply.Expiration, err = s.ks.OpenSession(args.Password, user, ttl)
+	return err
+}
+
+type CloseSessionArgs struct {
+	Session  string `json:"session"`
+	Password string `json:"password"`
+}
+
+// Close ends a session before it expires
+func (s *sessionService) Close(_ *http.Request, args *CloseSessionArgs, _ *api.EmptyReply) error {
+	s.ks.log.Debug("Keystore: CloseSession called",
+		logging.UserString("session", args.Session),
+	)
+
+	return s.ks.CloseSession(args.Session, args.Password)
+}
@@ -0,0 +1,136 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package keystore
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/api"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/formatting"
+)
+
+// exportTestUser returns the serialized user "bob", whose database for
+// [bID] maps "hello" to "world".
+func exportTestUser(t *testing.T, bID ids.ID) []byte {
+	require := require.New(t)
+
+	ks, err := CreateTestKeystore()
+	require.NoError(err)
+	require.NoError(ks.CreateUser("bob", strongPassword))
+
+	db, err := ks.GetDatabase(bID, "bob", strongPassword)
+	require.NoError(err)
+	require.NoError(db.Put([]byte("hello"), []byte("world")))
+
+	userBytes, err := ks.ExportUser("bob", strongPassword)
+	require.NoError(err)
+	return userBytes
+}
+
+func TestSession(t *testing.T) {
+	require := require.New(t)
+
+	bID := ids.GenerateTestID()
+	userBytes := exportTestUser(t, bID)
+
+	ksIntf, err := CreateTestKeystore()
+	require.NoError(err)
+	ks := ksIntf.(*keystore)
+	ks.clock.Set(time.Now())
+
+	_, _, err = ks.OpenSession("wrong password", userBytes, time.Minute)
+	require.Error(err)
+
+	session, expiration, err := ks.OpenSession(strongPassword, userBytes, time.Minute)
+	require.NoError(err)
+	require.Equal(ks.clock.Time().Add(time.Minute), expiration)
+
+	_, err = ks.GetDatabase(bID, session, "wrong password")
+	require.Error(err)
+
+	db, err := ks.GetDatabase(bID, session, strongPassword)
+	require.NoError(err)
+	val, err := db.Get([]byte("hello"))
+	require.NoError(err)
+	require.Equal([]byte("world"), val)
+	require.NoError(db.Put([]byte("new"), []byte("key")))
+
+	// Sessions are neither users nor persisted
+	users, err := ks.ListUsers()
+	require.NoError(err)
+	require.Empty(users)
+	it := ks.bcDB.NewIterator()
+	require.False(it.Next())
+	it.Release()
+
+	err = ks.CreateUser(session, strongPassword)
+	require.Error(err)
+
+	require.NoError(ks.CloseSession(session, strongPassword))
+	_, err = ks.GetDatabase(bID, session, strongPassword)
+	require.Error(err)
+}
+
+func TestSessionExpiration(t *testing.T) {
+	require := require.New(t)
+
+	bID := ids.GenerateTestID()
+	userBytes := exportTestUser(t, bID)
+
+	ksIntf, err := CreateTestKeystore()
+	require.NoError(err)
+	ks := ksIntf.(*keystore)
+
+	now := time.Now()
+	ks.clock.Set(now)
+
+	session, _, err := ks.OpenSession(strongPassword, userBytes, time.Minute)
+	require.NoError(err)
+
+	_, err = ks.GetDatabase(bID, session, strongPassword)
+	require.NoError(err)
+
+	ks.clock.Set(now.Add(time.Minute))
+	_, err = ks.GetDatabase(bID, session, strongPassword)
+	require.ErrorIs(err, errSessionExpired)
+	require.Empty(ks.sessions)
+}
+
+func TestSessionServiceTTL(t *testing.T) {
+	require := require.New(t)
+
+	userBytes := exportTestUser(t, ids.GenerateTestID())
+	userStr, err := formatting.Encode(formatting.Hex, userBytes)
+	require.NoError(err)
+
+	ksIntf, err := CreateTestKeystore()
+	require.NoError(err)
+	ks := ksIntf.(*keystore)
+	ks.clock.Set(time.Now())
+	s := sessionService{ks: ks}
+
+	err = s.Open(nil, &OpenSessionArgs{
+		Password: strongPassword,
+		User:     userStr,
+		TTL:      2 * 60 * 60,
+	}, &OpenSessionReply{})
+	require.ErrorIs(err, errInvalidSessionTTL)
+
+	reply := &OpenSessionReply{}
+	require.NoError(s.Open(nil, &OpenSessionArgs{
+		Password: strongPassword,
+		User:     userStr,
+	}, reply))
+	require.Equal(ks.clock.Time().Add(DefaultSessionTTL), reply.Expiration)
+
+	require.NoError(s.Close(nil, &CloseSessionArgs{
+		Session:  reply.Session,
+		Password: strongPassword,
+	}, &api.EmptyReply{}))
+	require.Empty(ks.sessions)
+}
@@ -0,0 +1,215 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package metrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	dto "github.com/prometheus/client_model/go"
+
+	"go.uber.org/zap"
+
+	"github.com/ava-labs/avalanchego/api/health"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+var (
+	errMetricsQuarantined = errors.New("metrics exceeded their namespace's series limit and were quarantined")
+
+	_ CardinalityGuard = (*cardinalityGuard)(nil)
+)
+
+// CardinalityGuard is a MultiGatherer that limits the number of series each
+// registered namespace may report.
+//
+// Once a namespace reports more series than allowed, its metrics with the most
+// series are quarantined until the namespace is back under the limit.
+// Quarantined metrics are dropped from the calls to Gather and are reported
+// through the health check. A quarantined metric is released once the
+// namespace can report it without exceeding the limit.
+type CardinalityGuard interface {
+	MultiGatherer
+	health.Checker
+}
+
+type cardinalityGuard struct {
+	log logging.Logger
+	// maxSeries is the maximum number of series each namespace may report. If
+	// 0, the number of series isn't limited.
+	maxSeries int
+
+	lock      sync.Mutex
+	gatherers map[string]prometheus.Gatherer
+	// namespace -> metric name -> number of series the metric had when it was
+	// quarantined
+	quarantined map[string]map[string]int
+}
+
+func NewCardinalityGuard(log logging.Logger, maxSeriesPerNamespace int) CardinalityGuard {
+	return &cardinalityGuard{
+		log:         log,
+		maxSeries:   maxSeriesPerNamespace,
+		gatherers:   make(map[string]prometheus.Gatherer),
+		quarantined: make(map[string]map[string]int),
+	}
+}
+
+func (g *cardinalityGuard) Gather() ([]*dto.MetricFamily, error) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	var results []*dto.MetricFamily
+	for namespace, gatherer := range g.gatherers {
+		metrics, err := gatherNamespace(namespace, gatherer)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, g.guard(namespace, metrics)...)
+	}
+	// Because we overwrite every metric's name, we are guaranteed that there
+	// are no metrics with nil names.
+	sortMetrics(results)
+	return results, nil
+}
+
+func (g *cardinalityGuard) Register(namespace string, gatherer prometheus.Gatherer) error {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	if _, exists := g.gatherers[namespace]; exists {
+		return errDuplicatedPrefix
+	}
+
+	g.gatherers[namespace] = gatherer
+	return nil
+}
+
+// HealthCheck reports unhealthy if any metric has been quarantined.
+func (g *cardinalityGuard) HealthCheck(context.Context) (interface{}, error) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	if len(g.quarantined) == 0 {
+		return nil, nil
+	}
+
+	details := make(map[string]map[string]int, len(g.quarantined))
+	numQuarantined := 0
+	for namespace, metrics := range g.quarantined {
+		namespaceDetails := make(map[string]int, len(metrics))
+		for name, numSeries := range metrics {
+			namespaceDetails[name] = numSeries
+		}
+		details[namespace] = namespaceDetails
+		numQuarantined += len(metrics)
+	}
+	return details, fmt.Errorf("%w: %d metrics quarantined", errMetricsQuarantined, numQuarantined)
+}
+
+// guard removes the quarantined metrics from [metrics], releases the
+// quarantined metrics that fit under the limit again and quarantines more
+// metrics if [namespace] is still reporting too many series.
+//
+// Assumes [g.lock] is held.
+func (g *cardinalityGuard) guard(namespace string, metrics []*dto.MetricFamily) []*dto.MetricFamily {
+	if g.maxSeries == 0 {
+		return metrics
+	}
+
+	quarantined := g.quarantined[namespace]
+	allowed := metrics[:0]
+	var stillQuarantined []*dto.MetricFamily
+	numSeries := 0
+	for _, metric := range metrics {
+		if _, ok := quarantined[metric.GetName()]; ok {
+			stillQuarantined = append(stillQuarantined, metric)
+			continue
+		}
+		allowed = append(allowed, metric)
+		numSeries += len(metric.Metric)
+	}
+
+	// Release the quarantined metrics with the fewest series first, so that
+	// as many metrics as possible are reported.
+	sort.SliceStable(stillQuarantined, func(i, j int) bool {
+		return len(stillQuarantined[i].Metric) < len(stillQuarantined[j].Metric)
+	})
+	reported := make(map[string]struct{}, len(stillQuarantined))
+	for _, metric := range stillQuarantined {
+		name := metric.GetName()
+		reported[name] = struct{}{}
+		metricSeries := len(metric.Metric)
+		if numSeries+metricSeries > g.maxSeries {
+			continue
+		}
+		g.release(namespace, name, metricSeries)
+		allowed = append(allowed, metric)
+		numSeries += metricSeries
+	}
+	// Metrics that are no longer reported can't exceed the limit.
+	for name := range g.quarantined[namespace] {
+		if _, ok := reported[name]; !ok {
+			g.release(namespace, name, 0)
+		}
+	}
+
+	if numSeries <= g.maxSeries {
+		return allowed
+	}
+	quarantined = g.quarantined[namespace]
+
+	if quarantined == nil {
+		quarantined = make(map[string]int)
+		g.quarantined[namespace] = quarantined
+	}
+
+	// Quarantine the metrics with the most series first, as they are the most
+	// likely to be using unbounded label values.
+	sort.SliceStable(allowed, func(i, j int) bool {
+		return len(allowed[i].Metric) > len(allowed[j].Metric)
+	})
+	for numSeries > g.maxSeries {
+		metric := allowed[0]
+		allowed = allowed[1:]
+
+		name := metric.GetName()
+		metricSeries := len(metric.Metric)
+		quarantined[name] = metricSeries
+		numSeries -= metricSeries
+
+		g.log.Warn("quarantining metric",
+			zap.String("reason", "namespace exceeded its series limit"),
+			zap.String("namespace", namespace),
+			zap.String("metric", name),
+			zap.Int("numSeries", metricSeries),
+			zap.Int("maxSeries", g.maxSeries),
+		)
+	}
+	return allowed
+}
+
+// release releases the quarantined metric [name] of [namespace], which now
+// reports [numSeries] series.
+//
+// Assumes [g.lock] is held.
+func (g *cardinalityGuard) release(namespace, name string, numSeries int) {
+	delete(g.quarantined[namespace], name)
+	if len(g.quarantined[namespace]) == 0 {
+		delete(g.quarantined, namespace)
+	}
+
+	g.log.Info("releasing quarantined metric",
+		zap.String("reason", "namespace is back under its series limit"),
+		zap.String("namespace", namespace),
+		zap.String("metric", name),
+		zap.Int("numSeries", numSeries),
+		zap.Int("maxSeries", g.maxSeries),
+	)
+}
@@ -0,0 +1,146 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package metrics
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	dto "github.com/prometheus/client_model/go"
+
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+func newTestMetricFamily(name string, numSeries int) *dto.MetricFamily {
+	return &dto.MetricFamily{
+		Name:   &name,
+		Metric: make([]*dto.Metric, numSeries),
+	}
+}
+
+// seriesGatherer returns new metric families with the given number of series
+// on every call to Gather, like a prometheus.Registry would.
+type seriesGatherer map[string]int
+
+func (g seriesGatherer) Gather() ([]*dto.MetricFamily, error) {
+	mfs := make([]*dto.MetricFamily, 0, len(g))
+	for name, numSeries := range g {
+		mfs = append(mfs, newTestMetricFamily(name, numSeries))
+	}
+	return mfs, nil
+}
+
+func TestCardinalityGuardDuplicatedPrefix(t *testing.T) {
+	require := require.New(t)
+
+	g := NewCardinalityGuard(logging.NoLog{}, 1)
+	og := NewOptionalGatherer()
+
+	require.NoError(g.Register("", og))
+	require.ErrorIs(g.Register("", og), errDuplicatedPrefix)
+}
+
+func TestCardinalityGuardQuarantine(t *testing.T) {
+	require := require.New(t)
+
+	g := NewCardinalityGuard(logging.NoLog{}, 5)
+
+	require.NoError(g.Register("small", seriesGatherer{
+		"a": 2,
+		"b": 3,
+	}))
+
+	big := seriesGatherer{}
+	require.NoError(g.Register("big", big))
+
+	// Namespaces under the limit are reported as is.
+	mfs, err := g.Gather()
+	require.NoError(err)
+	require.Len(mfs, 2)
+
+	_, err = g.HealthCheck(context.Background())
+	require.NoError(err)
+
+	big["bounded"] = 1
+	big["unbounded"] = 10
+	mfs, err = g.Gather()
+	require.NoError(err)
+	require.Len(mfs, 3)
+	require.Equal("big_bounded", mfs[0].GetName())
+	require.Equal("small_a", mfs[1].GetName())
+	require.Equal("small_b", mfs[2].GetName())
+
+	details, err := g.HealthCheck(context.Background())
+	require.ErrorIs(err, errMetricsQuarantined)
+	require.Equal(
+		map[string]map[string]int{
+			"big": {"big_unbounded": 10},
+		},
+		details,
+	)
+
+	// Quarantined metrics stay quarantined while they don't fit under the
+	// limit.
+	big["unbounded"] = 5
+	mfs, err = g.Gather()
+	require.NoError(err)
+	require.Len(mfs, 3)
+
+	_, err = g.HealthCheck(context.Background())
+	require.ErrorIs(err, errMetricsQuarantined)
+
+	// Quarantined metrics are released once they fit under the limit.
+	big["unbounded"] = 4
+	mfs, err = g.Gather()
+	require.NoError(err)
+	require.Len(mfs, 4)
+	require.Equal("big_unbounded", mfs[1].GetName())
+
+	_, err = g.HealthCheck(context.Background())
+	require.NoError(err)
+}
+
+func TestCardinalityGuardReleaseUnreported(t *testing.T) {
+	require := require.New(t)
+
+	g := NewCardinalityGuard(logging.NoLog{}, 5)
+
+	gatherer := seriesGatherer{
+		"unbounded": 10,
+	}
+	require.NoError(g.Register("", gatherer))
+
+	mfs, err := g.Gather()
+	require.NoError(err)
+	require.Empty(mfs)
+
+	_, err = g.HealthCheck(context.Background())
+	require.ErrorIs(err, errMetricsQuarantined)
+
+	// Metrics that are no longer reported are released.
+	delete(gatherer, "unbounded")
+	_, err = g.Gather()
+	require.NoError(err)
+
+	_, err = g.HealthCheck(context.Background())
+	require.NoError(err)
+}
+
+func TestCardinalityGuardDisabled(t *testing.T) {
+	require := require.New(t)
+
+	g := NewCardinalityGuard(logging.NoLog{}, 0)
+	require.NoError(g.Register("", seriesGatherer{
+		hello: 1000,
+	}))
+
+	mfs, err := g.Gather()
+	require.NoError(err)
+	require.Len(mfs, 1)
+
+	_, err = g.HealthCheck(context.Background())
+	require.NoError(err)
+}
@@ -47,24 +47,11 @@ func (g *multiGatherer) Gather() ([]*dto.MetricFamily, error) {
 
 	var results []*dto.MetricFamily
 	for namespace, gatherer := range g.gatherers {
-		metrics, err := gatherer.Gather()
+		metrics, err := gatherNamespace(namespace, gatherer)
 		if err != nil {
 			return nil, err
 		}
-		for _, metric := range metrics {
-			var name string
-			if metric.Name != nil {
-				if len(namespace) > 0 {
-					name = fmt.Sprintf("%s_%s", namespace, *metric.Name)
-				} else {
-					name = *metric.Name
-				}
-			} else {
-				name = namespace
-			}
-			metric.Name = &name
-			results = append(results, metric)
-		}
+		results = append(results, metrics...)
 	}
 	// Because we overwrite every metric's name, we are guaranteed that there
 	// are no metrics with nil names.
@@ -72,6 +59,29 @@ func (g *multiGatherer) Gather() ([]*dto.MetricFamily, error) {
 	return results, nil
 }
 
+// gatherNamespace returns the metrics of [gatherer] with [namespace] added to
+// their names.
+func gatherNamespace(namespace string, gatherer prometheus.Gatherer) ([]*dto.MetricFamily, error) {
+	metrics, err := gatherer.Gather()
+	if err != nil {
+		return nil, err
+	}
+	for _, metric := range metrics {
+		var name string
+		if metric.Name != nil {
+			if len(namespace) > 0 {
+				name = fmt.Sprintf("%s_%s", namespace, *metric.Name)
+			} else {
+				name = *metric.Name
+			}
+		} else {
+			name = namespace
+		}
+		metric.Name = &name
+	}
+	return metrics, nil
+}
+
 func (g *multiGatherer) Register(namespace string, gatherer prometheus.Gatherer) error {
 	g.lock.Lock()
 	defer g.lock.Unlock()
@@ -0,0 +1,115 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package pagination provides the shared helpers used by the JSON APIs to
+// bound the number of items returned by a single call.
+package pagination
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+)
+
+// DefaultMaxPageSize is the maximum page size of endpoints that don't specify
+// their own.
+const DefaultMaxPageSize uint64 = 1024
+
+var (
+	ErrPageSizeTooLarge = errors.New("page size exceeds the maximum page size")
+
+	errZeroMaxPageSize     = errors.New("max page size must be positive")
+	errMaxPageSizeTooLarge = errors.New("max page size exceeds the largest page size served by the endpoint")
+)
+
+// Limits maps the name of an endpoint, such as "avm.getUTXOs", to the maximum
+// number of items the endpoint returns per page. Endpoints that aren't present
+// use their default maximum page size.
+//
+// The APIs export the largest page sizes their endpoints can serve as Limits,
+// named MaxPageSizeBounds. The endpoints serve these page sizes unless the
+// node's config lowers them.
+type Limits map[string]uint64
+
+// Verify returns an error if a maximum page size is 0 or exceeds the bound of
+// its endpoint in [bounds].
+func (l Limits) Verify(bounds Limits) error {
+	for endpoint, maxPageSize := range l {
+		if maxPageSize == 0 {
+			return fmt.Errorf("%w: %q", errZeroMaxPageSize, endpoint)
+		}
+		if bound, ok := bounds[endpoint]; ok && maxPageSize > bound {
+			return fmt.Errorf("%w: %q can't exceed %d", errMaxPageSizeTooLarge, endpoint, bound)
+		}
+	}
+	return nil
+}
+
+// Override returns the limits of [l], replaced by the limits of [overrides].
+func (l Limits) Override(overrides Limits) Limits {
+	limits := make(Limits, len(l)+len(overrides))
+	for endpoint, maxPageSize := range l {
+		limits[endpoint] = maxPageSize
+	}
+	for endpoint, maxPageSize := range overrides {
+		limits[endpoint] = maxPageSize
+	}
+	return limits
+}
+
+// MaxPageSize returns the maximum page size of [endpoint]. If it hasn't been
+// overridden, [defaultMaxPageSize] is returned.
+func (l Limits) MaxPageSize(endpoint string, defaultMaxPageSize uint64) uint64 {
+	if maxPageSize, ok := l[endpoint]; ok {
+		return maxPageSize
+	}
+	return defaultMaxPageSize
+}
+
+// Effective returns the maximum page size of each endpoint of [defaults]: its
+// limit in [l] if it has one, or its limit in [defaults] otherwise.
+func (l Limits) Effective(defaults Limits) Limits {
+	limits := make(Limits, len(defaults))
+	for endpoint, defaultMaxPageSize := range defaults {
+		limits[endpoint] = l.MaxPageSize(endpoint, defaultMaxPageSize)
+	}
+	return limits
+}
+
+// PageSize returns the number of items that should be returned when
+// [requested] items were asked for. If [requested] is 0 or larger than
+// [maxPageSize], [maxPageSize] is returned.
+func PageSize(requested, maxPageSize uint64) uint64 {
+	if requested == 0 || requested > maxPageSize {
+		return maxPageSize
+	}
+	return requested
+}
+
+// VerifyPageSize returns an error if [requested] exceeds [maxPageSize]. It is
+// used by the endpoints that reject oversized pages rather than returning
+// smaller ones.
+func VerifyPageSize(requested, maxPageSize uint64) error {
+	if requested > maxPageSize {
+		return fmt.Errorf("%w: %d > %d", ErrPageSizeTooLarge, requested, maxPageSize)
+	}
+	return nil
+}
+
+// EncodeCursor returns the opaque string representation of the position
+// [cursor] that a paginated endpoint returns to its callers.
+//
+// An empty cursor is encoded as the empty string, which signals that there
+// are no more items.
+func EncodeCursor(cursor []byte) string {
+	return base64.RawURLEncoding.EncodeToString(cursor)
+}
+
+// DecodeCursor parses a cursor that was previously returned by EncodeCursor.
+func DecodeCursor(cursor string) ([]byte, error) {
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't decode cursor %q: %w", cursor, err)
+	}
+	return b, nil
+}
@@ -0,0 +1,95 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package pagination
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLimits(t *testing.T) {
+	require := require.New(t)
+
+	limits := Limits{
+		"avm.getUTXOs": 10,
+	}
+	require.NoError(limits.Verify(nil))
+	require.NoError(limits.Verify(Limits{"avm.getUTXOs": 10}))
+	require.ErrorIs(limits.Verify(Limits{"avm.getUTXOs": 9}), errMaxPageSizeTooLarge)
+	require.Equal(uint64(10), limits.MaxPageSize("avm.getUTXOs", DefaultMaxPageSize))
+	require.Equal(DefaultMaxPageSize, limits.MaxPageSize("platform.getUTXOs", DefaultMaxPageSize))
+
+	var nilLimits Limits
+	require.Equal(uint64(5), nilLimits.MaxPageSize("avm.getUTXOs", 5))
+
+	limits["info.peers"] = 0
+	require.ErrorIs(limits.Verify(nil), errZeroMaxPageSize)
+}
+
+func TestLimitsOverride(t *testing.T) {
+	require := require.New(t)
+
+	limits := Limits{
+		"avm.getUTXOs":      10,
+		"avm.getAddressTxs": 20,
+	}
+	overridden := limits.Override(Limits{"avm.getUTXOs": 5})
+	require.Equal(Limits{
+		"avm.getUTXOs":      5,
+		"avm.getAddressTxs": 20,
+	}, overridden)
+	require.Equal(uint64(10), limits["avm.getUTXOs"])
+
+	var nilLimits Limits
+	require.Empty(nilLimits.Override(nil))
+}
+
+func TestLimitsEffective(t *testing.T) {
+	require := require.New(t)
+
+	limits := Limits{
+		"avm.getUTXOs": 10,
+		"info.peers":   20,
+	}
+	defaults := Limits{
+		"avm.getUTXOs":      1024,
+		"avm.getAddressTxs": 1024,
+	}
+	require.Equal(Limits{
+		"avm.getUTXOs":      10,
+		"avm.getAddressTxs": 1024,
+	}, limits.Effective(defaults))
+}
+
+func TestPageSize(t *testing.T) {
+	require := require.New(t)
+
+	require.Equal(uint64(10), PageSize(0, 10))
+	require.Equal(uint64(5), PageSize(5, 10))
+	require.Equal(uint64(10), PageSize(10, 10))
+	require.Equal(uint64(10), PageSize(11, 10))
+}
+
+func TestVerifyPageSize(t *testing.T) {
+	require := require.New(t)
+
+	require.NoError(VerifyPageSize(0, 10))
+	require.NoError(VerifyPageSize(10, 10))
+	require.ErrorIs(VerifyPageSize(11, 10), ErrPageSizeTooLarge)
+}
+
+func TestCursor(t *testing.T) {
+	require := require.New(t)
+
+	require.Empty(EncodeCursor(nil))
+
+	cursor := []byte{0, 1, 2, 0xff}
+	decoded, err := DecodeCursor(EncodeCursor(cursor))
+	require.NoError(err)
+	require.Equal(cursor, decoded)
+
+	_, err = DecodeCursor("not a cursor!")
+	require.Error(err)
+}
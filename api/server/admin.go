@@ -0,0 +1,177 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+)
+
+var (
+	// DefaultAdminPaths are the base paths of the privileged APIs of the node
+	DefaultAdminPaths = []string{
+		"/ext/admin",
+		"/ext/debug",
+		"/ext/keystore",
+	}
+
+	errNoAdminPaths        = errors.New("admin listener must serve at least one path")
+	errInvalidAdminPath    = errors.New("admin path must be an absolute path")
+	errAdminListenerNotSet = errors.New("admin listener isn't configured")
+	errNotSocket           = errors.New("file exists and isn't a unix socket")
+)
+
+// AdminListenerConfig describes an API listener that exclusively serves the
+// privileged APIs of the node, such as the admin API. Once the listener is
+// configured, the privileged APIs aren't served by any other listener and the
+// listener doesn't serve any other API.
+type AdminListenerConfig struct {
+	// Address the listener binds to. Ignored if [Socket] is set.
+	Host string `json:"host"`
+	Port uint16 `json:"port"`
+	// Path of the unix socket the listener binds to
+	Socket string `json:"socket"`
+
+	// Base paths of the privileged APIs
+	Paths []string `json:"paths"`
+}
+
+// Enabled returns true if the privileged APIs are served by a separate
+// listener.
+func (c *AdminListenerConfig) Enabled() bool {
+	return c.Socket != "" || c.Port != 0
+}
+
+func (c *AdminListenerConfig) Verify() error {
+	if !c.Enabled() {
+		return nil
+	}
+	if len(c.Paths) == 0 {
+		return errNoAdminPaths
+	}
+	for _, p := range c.Paths {
+		if !strings.HasPrefix(p, "/") {
+			return fmt.Errorf("%w: %q", errInvalidAdminPath, p)
+		}
+	}
+	return nil
+}
+
+func (s *server) ConfigureAdminListener(config AdminListenerConfig) error {
+	if err := config.Verify(); err != nil {
+		return err
+	}
+	s.adminConfig = config
+	return nil
+}
+
+func (s *server) DispatchAdmin() error {
+	listener, err := s.listenAdmin()
+	if err != nil {
+		return err
+	}
+	return s.serve(listener, s.adminConfig.Host, "admin HTTP API server listening", s.adminHandler(), true)
+}
+
+func (s *server) DispatchAdminTLS(certBytes, keyBytes []byte) error {
+	config, err := s.newTLSConfig(certBytes, keyBytes)
+	if err != nil {
+		return err
+	}
+	listener, err := s.listenAdmin()
+	if err != nil {
+		return err
+	}
+	return s.serve(tls.NewListener(listener, config), s.adminConfig.Host, "admin HTTPS API server listening", s.adminHandler(), false)
+}
+
+// listenAdmin binds the admin listener to its unix socket, if set, or to its
+// address.
+func (s *server) listenAdmin() (net.Listener, error) {
+	if !s.adminConfig.Enabled() {
+		return nil, errAdminListenerNotSet
+	}
+	if s.adminConfig.Socket == "" {
+		return net.Listen("tcp", fmt.Sprintf("%s:%d", s.adminConfig.Host, s.adminConfig.Port))
+	}
+
+	// The socket of a previous run of the node is removed, as it would
+	// prevent binding the socket.
+	if info, err := os.Lstat(s.adminConfig.Socket); err == nil {
+		if info.Mode()&os.ModeSocket == 0 {
+			return nil, fmt.Errorf("%w: %s", errNotSocket, s.adminConfig.Socket)
+		}
+		if err := os.Remove(s.adminConfig.Socket); err != nil {
+			return nil, err
+		}
+	}
+	listener, err := net.Listen("unix", s.adminConfig.Socket)
+	if err != nil {
+		return nil, err
+	}
+	// Only the user running the node can connect to the socket.
+	if err := os.Chmod(s.adminConfig.Socket, 0o600); err != nil {
+		_ = listener.Close()
+		return nil, err
+	}
+	return listener, nil
+}
+
+// adminHandler returns a handler that only serves the requests to the
+// privileged APIs and to the OpenAPI document, which describes them.
+func (s *server) adminHandler() http.Handler {
+	handler := s.handler
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !s.isAdminListenerPath(r.URL.Path) {
+			http.NotFound(w, r)
+			return
+		}
+		handler.ServeHTTP(w, withServedPaths(r, s.isAdminListenerPath))
+	})
+}
+
+// publicHandler returns a handler that serves the requests to every API other
+// than the privileged APIs, if they are served by the admin listener.
+func (s *server) publicHandler() http.Handler {
+	if !s.adminConfig.Enabled() {
+		return s.handler
+	}
+	handler := s.handler
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.isAdminPath(r.URL.Path) {
+			http.NotFound(w, r)
+			return
+		}
+		handler.ServeHTTP(w, withServedPaths(r, s.isPublicPath))
+	})
+}
+
+// isAdminListenerPath returns true if [requestPath] is served by the admin
+// listener.
+func (s *server) isAdminListenerPath(requestPath string) bool {
+	return s.isAdminPath(requestPath) || path.Clean(requestPath) == openAPIURL
+}
+
+// isPublicPath returns true if [requestPath] isn't the path of a privileged
+// API.
+func (s *server) isPublicPath(requestPath string) bool {
+	return !s.isAdminPath(requestPath)
+}
+
+// isAdminPath returns true if [requestPath] is the path of a privileged API.
+func (s *server) isAdminPath(requestPath string) bool {
+	requestPath = path.Clean(requestPath)
+	for _, adminPath := range s.adminConfig.Paths {
+		if hasURLPrefix(requestPath, strings.TrimSuffix(path.Clean(adminPath), "/")) {
+			return true
+		}
+	}
+	return false
+}
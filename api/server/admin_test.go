@@ -0,0 +1,153 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+func newAdminTestServer(t *testing.T, config AdminListenerConfig) *server {
+	require := require.New(t)
+
+	s := New().(*server)
+	require.NoError(s.ConfigureAdminListener(config))
+	s.Initialize(logging.NoLog{}, nil, "127.0.0.1", 0, nil, time.Second, ids.EmptyNodeID, false, nil)
+	require.NoError(s.router.AddRouter(baseURL+"/admin", "", &testHandler{}))
+	require.NoError(s.router.AddRouter(baseURL+"/info", "", &testHandler{}))
+	return s
+}
+
+func TestAdminHandlers(t *testing.T) {
+	require := require.New(t)
+
+	s := newAdminTestServer(t, AdminListenerConfig{
+		Host:  "127.0.0.1",
+		Port:  9652,
+		Paths: DefaultAdminPaths,
+	})
+
+	request := func(handler http.Handler, path string) int {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, path, nil))
+		return w.Code
+	}
+
+	// The privileged APIs are only served by the admin listener.
+	public := s.publicHandler()
+	require.Equal(http.StatusNotFound, request(public, "/ext/admin"))
+	require.Equal(http.StatusNotFound, request(public, "/ext/./admin"))
+	require.Equal(http.StatusOK, request(public, "/ext/info"))
+
+	admin := s.adminHandler()
+	require.Equal(http.StatusOK, request(admin, "/ext/admin"))
+	require.Equal(http.StatusNotFound, request(admin, "/ext/info"))
+}
+
+func TestAdminHandlersWithoutAdminListener(t *testing.T) {
+	require := require.New(t)
+
+	s := newAdminTestServer(t, AdminListenerConfig{
+		Paths: DefaultAdminPaths,
+	})
+
+	w := httptest.NewRecorder()
+	s.publicHandler().ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/ext/admin", nil))
+	require.Equal(http.StatusOK, w.Code)
+}
+
+func TestDispatchAdminSocket(t *testing.T) {
+	require := require.New(t)
+
+	socket := filepath.Join(t.TempDir(), "admin.sock")
+	// A socket left by a previous run doesn't prevent the listener from
+	// binding the socket.
+	stale, err := net.Listen("unix", socket)
+	require.NoError(err)
+	if unixListener, ok := stale.(*net.UnixListener); ok {
+		unixListener.SetUnlinkOnClose(false)
+	}
+	require.NoError(stale.Close())
+
+	s := newAdminTestServer(t, AdminListenerConfig{
+		Socket: socket,
+		Paths:  DefaultAdminPaths,
+	})
+	go func() {
+		_ = s.DispatchAdmin()
+	}()
+	defer func() {
+		require.NoError(s.Shutdown())
+	}()
+
+	client := &http.Client{
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				return (&net.Dialer{}).DialContext(ctx, "unix", socket)
+			},
+		},
+	}
+	require.Eventually(func() bool {
+		resp, err := client.Post("http://admin/ext/admin", "application/json", nil)
+		if err != nil {
+			return false
+		}
+		_ = resp.Body.Close()
+		return resp.StatusCode == http.StatusOK
+	}, 5*time.Second, 10*time.Millisecond)
+
+	info, err := os.Stat(socket)
+	require.NoError(err)
+	require.Equal(os.FileMode(0o600), info.Mode().Perm())
+}
+
+func TestAdminListenerConfigVerify(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      AdminListenerConfig
+		expectedErr error
+	}{
+		{
+			name: "disabled",
+		},
+		{
+			name: "socket",
+			config: AdminListenerConfig{
+				Socket: "/tmp/admin.sock",
+				Paths:  DefaultAdminPaths,
+			},
+		},
+		{
+			name: "no paths",
+			config: AdminListenerConfig{
+				Port: 9652,
+			},
+			expectedErr: errNoAdminPaths,
+		},
+		{
+			name: "relative path",
+			config: AdminListenerConfig{
+				Port:  9652,
+				Paths: []string{"ext/admin"},
+			},
+			expectedErr: errInvalidAdminPath,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.ErrorIs(t, test.config.Verify(), test.expectedErr)
+		})
+	}
+}
@@ -0,0 +1,238 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// JSON-RPC 2.0 error codes
+const (
+	jsonRPCParseError     = -32700
+	jsonRPCInvalidRequest = -32600
+	jsonRPCInternalError  = -32603
+)
+
+var (
+	errInvalidBatchPath           = errors.New("batch path must be an absolute path")
+	errInvalidMaxBatchSize        = errors.New("max batch size must be > 0")
+	errInvalidMaxBatchConcurrency = errors.New("max batch concurrency must be > 0")
+)
+
+// BatchConfig configures the splitting of JSON-RPC batch requests, for the APIs
+// that only handle single JSON-RPC requests.
+type BatchConfig struct {
+	// Base paths of the APIs whose batch requests are split into single
+	// requests. The requests of each batch are handled concurrently and their
+	// responses are returned as a batch response.
+	Paths []string `json:"paths"`
+	// Max number of requests in a batch
+	MaxSize int `json:"maxSize"`
+	// Max number of requests of a batch that are handled concurrently
+	MaxConcurrency int `json:"maxConcurrency"`
+}
+
+// Enabled returns true if batch requests are split for some APIs.
+func (c *BatchConfig) Enabled() bool {
+	return len(c.Paths) > 0
+}
+
+func (c *BatchConfig) Verify() error {
+	if !c.Enabled() {
+		return nil
+	}
+	for _, p := range c.Paths {
+		if !strings.HasPrefix(p, "/") {
+			return fmt.Errorf("%w: %q", errInvalidBatchPath, p)
+		}
+	}
+	switch {
+	case c.MaxSize <= 0:
+		return errInvalidMaxBatchSize
+	case c.MaxConcurrency <= 0:
+		return errInvalidMaxBatchConcurrency
+	default:
+		return nil
+	}
+}
+
+func (s *server) ConfigureBatches(config BatchConfig) error {
+	if err := config.Verify(); err != nil {
+		return err
+	}
+	s.batchConfig = config
+	return nil
+}
+
+type jsonRPCError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type jsonRPCErrorResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	Error   jsonRPCError    `json:"error"`
+	ID      json.RawMessage `json:"id"`
+}
+
+func newJSONRPCError(id json.RawMessage, code int, message string) json.RawMessage {
+	if len(id) == 0 {
+		id = json.RawMessage("null")
+	}
+	response, _ := json.Marshal(jsonRPCErrorResponse{
+		JSONRPC: "2.0",
+		Error: jsonRPCError{
+			Code:    code,
+			Message: message,
+		},
+		ID: id,
+	})
+	return response
+}
+
+// batchHandler returns a handler that splits the JSON-RPC batch requests to
+// the APIs under the paths of [s.batchConfig] into single requests to
+// [handler]. Other requests are passed to [handler] unchanged.
+func (s *server) batchHandler(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || !s.isBatchPath(r.URL.Path) {
+			handler.ServeHTTP(w, r)
+			return
+		}
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		trimmed := bytes.TrimSpace(body)
+		if len(trimmed) == 0 || trimmed[0] != '[' {
+			r.Body = io.NopCloser(bytes.NewReader(body))
+			handler.ServeHTTP(w, r)
+			return
+		}
+
+		var requests []json.RawMessage
+		switch err := json.Unmarshal(trimmed, &requests); {
+		case err != nil:
+			writeJSON(w, newJSONRPCError(nil, jsonRPCParseError, "parse error"))
+			return
+		case len(requests) == 0:
+			writeJSON(w, newJSONRPCError(nil, jsonRPCInvalidRequest, "empty batch"))
+			return
+		case len(requests) > s.batchConfig.MaxSize:
+			writeJSON(w, newJSONRPCError(nil, jsonRPCInvalidRequest, fmt.Sprintf("batch of %d requests exceeds the max size of %d", len(requests), s.batchConfig.MaxSize)))
+			return
+		}
+
+		responses := make([]json.RawMessage, len(requests))
+		sem := make(chan struct{}, s.batchConfig.MaxConcurrency)
+		wg := sync.WaitGroup{}
+		wg.Add(len(requests))
+		for i, request := range requests {
+			sem <- struct{}{}
+			go func(i int, request json.RawMessage) {
+				defer func() {
+					<-sem
+					wg.Done()
+				}()
+				responses[i] = handleBatchedRequest(handler, r, request)
+			}(i, request)
+		}
+		wg.Wait()
+
+		// Notifications don't have responses.
+		batchResponse := make([]json.RawMessage, 0, len(responses))
+		for _, response := range responses {
+			if response != nil {
+				batchResponse = append(batchResponse, response)
+			}
+		}
+		if len(batchResponse) == 0 {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		response, err := json.Marshal(batchResponse)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		writeJSON(w, response)
+	})
+}
+
+// handleBatchedRequest passes [request], one of the requests of the batch
+// request [r], to [handler] and returns its response. Returns nil if
+// [request] is a notification.
+func handleBatchedRequest(handler http.Handler, r *http.Request, request json.RawMessage) json.RawMessage {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(request, &fields); err != nil {
+		return newJSONRPCError(nil, jsonRPCInvalidRequest, "invalid request")
+	}
+	id, hasID := fields["id"]
+
+	single := r.Clone(r.Context())
+	single.Body = io.NopCloser(bytes.NewReader(request))
+	single.ContentLength = int64(len(request))
+	single.Header.Del("Content-Length")
+	// The batch response is encoded as a whole, so the single responses
+	// mustn't be.
+	single.Header.Del("Accept-Encoding")
+
+	recorder := &responseBuffer{
+		header:     make(http.Header),
+		statusCode: http.StatusOK,
+	}
+	handler.ServeHTTP(recorder, single)
+	if !hasID {
+		return nil
+	}
+
+	response := bytes.TrimSpace(recorder.body.Bytes())
+	if !json.Valid(response) || len(response) == 0 || response[0] != '{' {
+		message := strings.TrimSpace(recorder.body.String())
+		if message == "" {
+			message = http.StatusText(recorder.statusCode)
+		}
+		return newJSONRPCError(id, jsonRPCInternalError, message)
+	}
+	return response
+}
+
+// isBatchPath returns true if the batch requests to [requestPath] are split.
+func (s *server) isBatchPath(requestPath string) bool {
+	_, ok := s.matchPath(s.batchConfig.Paths, requestPath)
+	return ok
+}
+
+func writeJSON(w http.ResponseWriter, body []byte) {
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(body)
+}
+
+// responseBuffer records a response in memory.
+type responseBuffer struct {
+	header     http.Header
+	statusCode int
+	body       bytes.Buffer
+}
+
+func (r *responseBuffer) Header() http.Header {
+	return r.header
+}
+
+func (r *responseBuffer) WriteHeader(statusCode int) {
+	r.statusCode = statusCode
+}
+
+func (r *responseBuffer) Write(b []byte) (int, error) {
+	return r.body.Write(b)
+}
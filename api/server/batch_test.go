@@ -0,0 +1,205 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+// singleRPCHandler only handles single JSON-RPC requests, to which it replies
+// with the name of the called method.
+type singleRPCHandler struct{}
+
+func (singleRPCHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		Method string          `json:"method"`
+		ID     json.RawMessage `json:"id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "batches aren't supported", http.StatusBadRequest)
+		return
+	}
+	if request.Method == "fail" {
+		http.Error(w, "failed", http.StatusInternalServerError)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"result":  request.Method,
+		"id":      request.ID,
+	})
+}
+
+func newBatchTestServer(t *testing.T) *server {
+	require := require.New(t)
+
+	s := New().(*server)
+	require.NoError(s.ConfigureBatches(BatchConfig{
+		Paths:          []string{"/ext/info", "/ext/bc/X"},
+		MaxSize:        3,
+		MaxConcurrency: 2,
+	}))
+	s.Initialize(logging.NoLog{}, nil, "127.0.0.1", 0, nil, time.Second, ids.EmptyNodeID, false, nil)
+	require.NoError(s.router.AddRouter(baseURL+"/info", "", singleRPCHandler{}))
+	require.NoError(s.router.AddRouter(baseURL+"/health", "", singleRPCHandler{}))
+
+	xChainID := ids.GenerateTestID()
+	require.NoError(s.router.AddRouter(fmt.Sprintf("%s/%s", chainBaseURL, xChainID), "", singleRPCHandler{}))
+	require.NoError(s.AddAliases(fmt.Sprintf("bc/%s", xChainID), "X", "bc/X"))
+	return s
+}
+
+func TestBatchHandler(t *testing.T) {
+	tests := []struct {
+		name             string
+		path             string
+		body             string
+		expectedStatus   int
+		expectedResponse string
+	}{
+		{
+			name:             "single request",
+			path:             "/ext/info",
+			body:             `{"jsonrpc":"2.0","method":"info.getNodeID","id":1}`,
+			expectedStatus:   http.StatusOK,
+			expectedResponse: `{"jsonrpc":"2.0","result":"info.getNodeID","id":1}`,
+		},
+		{
+			name:           "batch request",
+			path:           "/ext/info",
+			body:           `[{"jsonrpc":"2.0","method":"info.getNodeID","id":1},{"jsonrpc":"2.0","method":"info.notify"},{"jsonrpc":"2.0","method":"fail","id":"a"}]`,
+			expectedStatus: http.StatusOK,
+			expectedResponse: `[
+				{"jsonrpc":"2.0","result":"info.getNodeID","id":1},
+				{"jsonrpc":"2.0","error":{"code":-32603,"message":"failed"},"id":"a"}
+			]`,
+		},
+		{
+			name:           "invalid request in batch",
+			path:           "/ext/info",
+			body:           `[1,{"jsonrpc":"2.0","method":"info.getNodeID","id":2}]`,
+			expectedStatus: http.StatusOK,
+			expectedResponse: `[
+				{"jsonrpc":"2.0","error":{"code":-32600,"message":"invalid request"},"id":null},
+				{"jsonrpc":"2.0","result":"info.getNodeID","id":2}
+			]`,
+		},
+		{
+			name:           "notifications only",
+			path:           "/ext/info",
+			body:           `[{"jsonrpc":"2.0","method":"info.notify"}]`,
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:             "empty batch",
+			path:             "/ext/info",
+			body:             `[]`,
+			expectedStatus:   http.StatusOK,
+			expectedResponse: `{"jsonrpc":"2.0","error":{"code":-32600,"message":"empty batch"},"id":null}`,
+		},
+		{
+			name:             "invalid batch",
+			path:             "/ext/info",
+			body:             `[{"jsonrpc":"2.0"`,
+			expectedStatus:   http.StatusOK,
+			expectedResponse: `{"jsonrpc":"2.0","error":{"code":-32700,"message":"parse error"},"id":null}`,
+		},
+		{
+			name:             "batch too large",
+			path:             "/ext/info",
+			body:             `[{"id":1},{"id":2},{"id":3},{"id":4}]`,
+			expectedStatus:   http.StatusOK,
+			expectedResponse: `{"jsonrpc":"2.0","error":{"code":-32600,"message":"batch of 4 requests exceeds the max size of 3"},"id":null}`,
+		},
+		{
+			name:           "batch to an alias of a split path",
+			path:           "/ext/X",
+			body:           `[{"jsonrpc":"2.0","method":"avm.getTx","id":1}]`,
+			expectedStatus: http.StatusOK,
+			expectedResponse: `[
+				{"jsonrpc":"2.0","result":"avm.getTx","id":1}
+			]`,
+		},
+		{
+			name:           "batch to a path that isn't split",
+			path:           "/ext/health",
+			body:           `[{"jsonrpc":"2.0","method":"health.health","id":1}]`,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require := require.New(t)
+
+			s := newBatchTestServer(t)
+			w := httptest.NewRecorder()
+			s.handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, test.path, strings.NewReader(test.body)))
+			require.Equal(test.expectedStatus, w.Code)
+			if test.expectedResponse != "" {
+				require.JSONEq(test.expectedResponse, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestBatchConfigVerify(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      BatchConfig
+		expectedErr error
+	}{
+		{
+			name: "disabled",
+		},
+		{
+			name: "valid",
+			config: BatchConfig{
+				Paths:          []string{"/ext/info"},
+				MaxSize:        100,
+				MaxConcurrency: 8,
+			},
+		},
+		{
+			name: "relative path",
+			config: BatchConfig{
+				Paths:          []string{"ext/info"},
+				MaxSize:        100,
+				MaxConcurrency: 8,
+			},
+			expectedErr: errInvalidBatchPath,
+		},
+		{
+			name: "no max size",
+			config: BatchConfig{
+				Paths:          []string{"/ext/info"},
+				MaxConcurrency: 8,
+			},
+			expectedErr: errInvalidMaxBatchSize,
+		},
+		{
+			name: "no max concurrency",
+			config: BatchConfig{
+				Paths:   []string{"/ext/info"},
+				MaxSize: 100,
+			},
+			expectedErr: errInvalidMaxBatchConcurrency,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.ErrorIs(t, test.config.Verify(), test.expectedErr)
+		})
+	}
+}
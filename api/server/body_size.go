@@ -0,0 +1,75 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+var (
+	errInvalidBodySizeLimitPath = errors.New("body size limit path must be an absolute path")
+	errInvalidMaxBodySize       = errors.New("max body size must be > 0")
+)
+
+// BodySizeLimit limits the size of the bodies of the requests to the APIs
+// under a base path.
+type BodySizeLimit struct {
+	// Base path of the limited APIs, such as "/ext/bc/X". The requests to the
+	// path and to its subpaths are limited. The limits of a chain's API apply
+	// to the requests made through any of the chain's aliases.
+	Path string `json:"path"`
+	// Max size of the body of a request, in bytes
+	MaxBytes int64 `json:"maxBytes"`
+}
+
+func (l *BodySizeLimit) Verify() error {
+	switch {
+	case !strings.HasPrefix(l.Path, "/"):
+		return fmt.Errorf("%w: %q", errInvalidBodySizeLimitPath, l.Path)
+	case l.MaxBytes <= 0:
+		return errInvalidMaxBodySize
+	default:
+		return nil
+	}
+}
+
+func (s *server) LimitBodySize(defaultMaxBytes int64, limits []BodySizeLimit) error {
+	if defaultMaxBytes <= 0 && len(limits) == 0 {
+		return nil
+	}
+
+	paths := make([]string, len(limits))
+	for i, limit := range limits {
+		if err := limit.Verify(); err != nil {
+			return err
+		}
+		paths[i] = limit.Path
+	}
+
+	handler := s.handler
+	s.handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		maxBytes := defaultMaxBytes
+		if i, ok := s.matchPath(paths, r.URL.Path); ok {
+			maxBytes = limits[i].MaxBytes
+		}
+		if maxBytes <= 0 {
+			handler.ServeHTTP(w, r)
+			return
+		}
+
+		// Requests that declare a body over the limit are rejected before
+		// their body is read. The bodies of other requests fail to be read
+		// past the limit.
+		if r.ContentLength > maxBytes {
+			http.Error(w, http.StatusText(http.StatusRequestEntityTooLarge), http.StatusRequestEntityTooLarge)
+			return
+		}
+		r.Body = http.MaxBytesReader(w, r.Body, maxBytes)
+		handler.ServeHTTP(w, r)
+	})
+	return nil
+}
@@ -0,0 +1,113 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+type readBodyHandler struct{}
+
+func (readBodyHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if _, err := io.ReadAll(r.Body); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+	}
+}
+
+func TestLimitBodySize(t *testing.T) {
+	require := require.New(t)
+
+	s := New().(*server)
+	s.Initialize(logging.NoLog{}, nil, "127.0.0.1", 0, nil, time.Second, ids.EmptyNodeID, false, nil)
+
+	xChainID := ids.GenerateTestID()
+	url := fmt.Sprintf("%s/%s", chainBaseURL, xChainID)
+	require.NoError(s.router.AddRouter(url, "", readBodyHandler{}))
+	s.registerChainRoute(url, xChainID, ids.Empty)
+	require.NoError(s.AddAliases(fmt.Sprintf("bc/%s", xChainID), "X", "bc/X"))
+	require.NoError(s.router.AddRouter(baseURL+"/info", "", readBodyHandler{}))
+
+	require.NoError(s.LimitBodySize(10, []BodySizeLimit{
+		{
+			Path:     "/ext/bc/X",
+			MaxBytes: 20,
+		},
+	}))
+
+	request := func(path string, size int, declared bool) int {
+		var body io.Reader = bytes.NewReader(make([]byte, size))
+		if !declared {
+			// Hide the length of the body from the request.
+			body = io.MultiReader(body)
+		}
+		r := httptest.NewRequest(http.MethodPost, path, body)
+		if !declared {
+			r.ContentLength = -1
+		}
+		w := httptest.NewRecorder()
+		s.handler.ServeHTTP(w, r)
+		return w.Code
+	}
+
+	// The default limit applies to paths without their own limit.
+	require.Equal(http.StatusOK, request("/ext/info", 10, true))
+	require.Equal(http.StatusRequestEntityTooLarge, request("/ext/info", 11, true))
+	require.Equal(http.StatusBadRequest, request("/ext/info", 11, false))
+
+	// The limit of a path applies to the requests made through the chain's ID
+	// and through any of its aliases.
+	require.Equal(http.StatusOK, request("/ext/bc/X", 20, true))
+	require.Equal(http.StatusOK, request(fmt.Sprintf("/ext/bc/%s", xChainID), 20, false))
+	require.Equal(http.StatusOK, request("/ext/X", 20, true))
+	require.Equal(http.StatusRequestEntityTooLarge, request("/ext/bc/X", 21, true))
+	require.Equal(http.StatusBadRequest, request(fmt.Sprintf("/ext/bc/%s", xChainID), 21, false))
+	require.Equal(http.StatusRequestEntityTooLarge, request("/ext/X", 21, true))
+}
+
+func TestBodySizeLimitVerify(t *testing.T) {
+	tests := []struct {
+		name        string
+		limit       BodySizeLimit
+		expectedErr error
+	}{
+		{
+			name: "valid",
+			limit: BodySizeLimit{
+				Path:     "/ext/bc/X",
+				MaxBytes: 1024,
+			},
+		},
+		{
+			name: "relative path",
+			limit: BodySizeLimit{
+				Path:     "ext/bc/X",
+				MaxBytes: 1024,
+			},
+			expectedErr: errInvalidBodySizeLimitPath,
+		},
+		{
+			name: "no max size",
+			limit: BodySizeLimit{
+				Path: "/ext/bc/X",
+			},
+			expectedErr: errInvalidMaxBodySize,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.ErrorIs(t, test.limit.Verify(), test.expectedErr)
+		})
+	}
+}
@@ -0,0 +1,110 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/rs/cors"
+
+	"go.uber.org/zap"
+)
+
+// CORS updates the origins that the APIs can be requested from by browsers.
+type CORS interface {
+	// AllowedOrigins returns the origins the APIs can be requested from.
+	AllowedOrigins() []string
+	// SetAllowedOrigins replaces the origins the APIs can be requested from.
+	// The requests being handled aren't affected.
+	SetAllowedOrigins(allowedOrigins []string)
+	// CheckOrigin returns true if the Origin header of [r] is missing or is
+	// one of the allowed origins. Used by the handlers that the cors handler
+	// doesn't protect, such as WebSocket upgrades.
+	CheckOrigin(r *http.Request) bool
+}
+
+// corsState is the cors handler of a set of allowed origins.
+type corsState struct {
+	allowedOrigins []string
+	handler        http.Handler
+	// Matches the origins as the cors handler does
+	allowAll bool
+	origins  map[string]struct{}
+	// Origins that contain a wildcard, split around it
+	wildcards [][2]string
+}
+
+// allows returns true if [origin] is one of the allowed origins. Origins are
+// matched case-insensitively and may contain a single wildcard.
+func (c *corsState) allows(origin string) bool {
+	if c.allowAll {
+		return true
+	}
+	origin = strings.ToLower(origin)
+	if _, ok := c.origins[origin]; ok {
+		return true
+	}
+	for _, w := range c.wildcards {
+		prefix, suffix := w[0], w[1]
+		if len(origin) >= len(prefix)+len(suffix) && strings.HasPrefix(origin, prefix) && strings.HasSuffix(origin, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *server) AllowedOrigins() []string {
+	state := s.cors.Load().(*corsState)
+	origins := make([]string, len(state.allowedOrigins))
+	copy(origins, state.allowedOrigins)
+	return origins
+}
+
+func (s *server) SetAllowedOrigins(allowedOrigins []string) {
+	origins := make([]string, len(allowedOrigins))
+	copy(origins, allowedOrigins)
+	state := &corsState{
+		allowedOrigins: origins,
+		handler: cors.New(cors.Options{
+			AllowedOrigins:   origins,
+			AllowCredentials: true,
+		}).Handler(http.HandlerFunc(s.serveRouter)),
+		// The cors handler allows every origin if none are given
+		allowAll: len(origins) == 0,
+		origins:  make(map[string]struct{}, len(origins)),
+	}
+	for _, origin := range origins {
+		origin = strings.ToLower(origin)
+		switch i := strings.IndexByte(origin, '*'); {
+		case origin == "*":
+			state.allowAll = true
+		case i >= 0:
+			state.wildcards = append(state.wildcards, [2]string{origin[:i], origin[i+1:]})
+		default:
+			state.origins[origin] = struct{}{}
+		}
+	}
+	s.cors.Store(state)
+	s.log.Info("API allowed origins set",
+		zap.Strings("allowedOrigins", origins),
+	)
+}
+
+func (s *server) CheckOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	return origin == "" || s.cors.Load().(*corsState).allows(origin)
+}
+
+// serveCORS serves [r] with the cors handler of the current allowed origins.
+func (s *server) serveCORS(w http.ResponseWriter, r *http.Request) {
+	s.cors.Load().(*corsState).handler.ServeHTTP(w, r)
+}
+
+// serveRouter serves [r] with the handler behind the cors handler. It's looked
+// up on each request, as it's wrapped by the limits that are configured after
+// the server is initialized.
+func (s *server) serveRouter(w http.ResponseWriter, r *http.Request) {
+	s.routerHandler.ServeHTTP(w, r)
+}
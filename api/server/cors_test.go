@@ -0,0 +1,97 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+func TestSetAllowedOrigins(t *testing.T) {
+	require := require.New(t)
+
+	s := New().(*server)
+	s.Initialize(logging.NoLog{}, nil, "127.0.0.1", 0, []string{"https://a.example"}, time.Second, ids.EmptyNodeID, false, nil)
+	require.NoError(s.router.AddRouter(baseURL+"/info", "", &testHandler{}))
+
+	allowedOrigin := func(origin string) string {
+		r := httptest.NewRequest(http.MethodPost, "/ext/info", nil)
+		r.Header.Set("Origin", origin)
+		w := httptest.NewRecorder()
+		s.handler.ServeHTTP(w, r)
+		return w.Header().Get("Access-Control-Allow-Origin")
+	}
+
+	require.Equal([]string{"https://a.example"}, s.AllowedOrigins())
+	require.Equal("https://a.example", allowedOrigin("https://a.example"))
+	require.Empty(allowedOrigin("https://b.example"))
+
+	s.SetAllowedOrigins([]string{"https://b.example"})
+	require.Equal([]string{"https://b.example"}, s.AllowedOrigins())
+	require.Empty(allowedOrigin("https://a.example"))
+	require.Equal("https://b.example", allowedOrigin("https://b.example"))
+}
+
+func TestCheckOrigin(t *testing.T) {
+	tests := []struct {
+		allowedOrigins []string
+		origin         string
+		expected       bool
+	}{
+		{
+			allowedOrigins: []string{"https://a.example"},
+			origin:         "",
+			expected:       true,
+		},
+		{
+			allowedOrigins: []string{"https://a.example"},
+			origin:         "https://A.example",
+			expected:       true,
+		},
+		{
+			allowedOrigins: []string{"https://a.example"},
+			origin:         "https://b.example",
+			expected:       false,
+		},
+		{
+			allowedOrigins: []string{"https://*.example"},
+			origin:         "https://b.example",
+			expected:       true,
+		},
+		{
+			allowedOrigins: []string{"https://*.example"},
+			origin:         "http://b.example",
+			expected:       false,
+		},
+		{
+			allowedOrigins: []string{"https://a.example", "*"},
+			origin:         "https://b.example",
+			expected:       true,
+		},
+		{
+			allowedOrigins: nil,
+			origin:         "https://b.example",
+			expected:       true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.origin, func(t *testing.T) {
+			s := New().(*server)
+			s.Initialize(logging.NoLog{}, nil, "127.0.0.1", 0, test.allowedOrigins, time.Second, ids.EmptyNodeID, false, nil)
+
+			r := httptest.NewRequest(http.MethodGet, "/ext/events", nil)
+			if test.origin != "" {
+				r.Header.Set("Origin", test.origin)
+			}
+			require.Equal(t, test.expected, s.CheckOrigin(r))
+		})
+	}
+}
@@ -0,0 +1,282 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"go.uber.org/zap"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/units"
+)
+
+// Topics of the events published by the event hub
+const (
+	// AcceptedTopic events are published when the consensus of a chain accepts
+	// a container, such as a block or a vertex.
+	AcceptedTopic = "accepted"
+	// DecisionTopic events are published when a chain accepts a decision, such
+	// as a block or a transaction of a DAG.
+	DecisionTopic = "decision"
+)
+
+const (
+	// Name of the acceptors the event hub registers for each chain
+	eventsAcceptorName = "events"
+
+	// Time allowed to write a message to a client
+	eventsWriteWait = 10 * time.Second
+	// Time allowed to read the next pong message from a client
+	eventsPongWait = 60 * time.Second
+	// Send pings to clients with this period. Must be less than
+	// [eventsPongWait].
+	eventsPingPeriod = (eventsPongWait * 9) / 10
+	// Max size of the messages read from a client
+	eventsMaxMessageSize = 10 * units.KiB
+)
+
+var (
+	errInvalidMaxEventConnections   = errors.New("max event connections must be > 0")
+	errInvalidMaxPendingEvents      = errors.New("max pending events must be > 0")
+	errInvalidMaxEventSubscriptions = errors.New("max event subscriptions must be > 0")
+	errNoEventTopics                = errors.New("no topics")
+	errUnknownEventTopic            = errors.New("unknown topic")
+	errUnknownEventChain            = errors.New("unknown chain")
+	errTooManyEventSubscriptions    = errors.New("too many subscriptions")
+	errInvalidEventCommand          = errors.New("invalid command")
+
+	eventTopics = map[string]struct{}{
+		AcceptedTopic: {},
+		DecisionTopic: {},
+	}
+)
+
+// EventsConfig configures the event hub.
+type EventsConfig struct {
+	// Max number of clients connected to the hub at once
+	MaxConnections int `json:"maxConnections"`
+	// Max number of events pending to be sent to a client. Clients that fall
+	// further behind are disconnected.
+	MaxPendingEvents int `json:"maxPendingEvents"`
+	// Max number of (chain, topic) pairs a client is subscribed to
+	MaxSubscriptions int `json:"maxSubscriptions"`
+}
+
+func (c *EventsConfig) Verify() error {
+	switch {
+	case c.MaxConnections <= 0:
+		return errInvalidMaxEventConnections
+	case c.MaxPendingEvents <= 0:
+		return errInvalidMaxPendingEvents
+	case c.MaxSubscriptions <= 0:
+		return errInvalidMaxEventSubscriptions
+	default:
+		return nil
+	}
+}
+
+// Event is sent to the clients subscribed to its chain and topic.
+type Event struct {
+	ChainID     ids.ID `json:"chainID"`
+	Topic       string `json:"topic"`
+	ContainerID ids.ID `json:"containerID"`
+}
+
+// EventSubscription is a set of topics of a set of chains.
+type EventSubscription struct {
+	// IDs or aliases of the chains. If empty, the subscription applies to
+	// every chain.
+	Chains []string `json:"chains"`
+	Topics []string `json:"topics"`
+}
+
+// EventCommand is sent by clients to update their subscriptions.
+type EventCommand struct {
+	Subscribe   *EventSubscription `json:"subscribe,omitempty"`
+	Unsubscribe *EventSubscription `json:"unsubscribe,omitempty"`
+}
+
+type eventError struct {
+	Error string `json:"error"`
+}
+
+// EventHub publishes the events of the chains to the WebSocket clients
+// subscribed to them.
+type EventHub struct {
+	log                logging.Logger
+	config             EventsConfig
+	aliaser            ids.AliaserReader
+	decisionAcceptors  snow.AcceptorGroup
+	consensusAcceptors snow.AcceptorGroup
+	upgrader           websocket.Upgrader
+
+	lock   sync.RWMutex
+	conns  map[*eventConn]struct{}
+	closed bool
+}
+
+// NewEventHub returns a hub that publishes the containers accepted by the
+// chains registered to it. Clients refer to chains through [aliaser]. Browsers
+// may only connect from the origins allowed by [cors].
+func NewEventHub(
+	log logging.Logger,
+	config EventsConfig,
+	aliaser ids.AliaserReader,
+	cors CORS,
+	decisionAcceptors snow.AcceptorGroup,
+	consensusAcceptors snow.AcceptorGroup,
+) (*EventHub, error) {
+	if err := config.Verify(); err != nil {
+		return nil, err
+	}
+	return &EventHub{
+		log:                log,
+		config:             config,
+		aliaser:            aliaser,
+		decisionAcceptors:  decisionAcceptors,
+		consensusAcceptors: consensusAcceptors,
+		conns:              make(map[*eventConn]struct{}),
+		upgrader: websocket.Upgrader{
+			ReadBufferSize:  units.KiB,
+			WriteBufferSize: units.KiB,
+			CheckOrigin:     cors.CheckOrigin,
+		},
+	}, nil
+}
+
+func (h *EventHub) RegisterChain(chainName string, engine common.Engine) {
+	chainID := engine.Context().ChainID
+	if err := h.decisionAcceptors.RegisterAcceptor(chainID, eventsAcceptorName, eventAcceptor{hub: h, topic: DecisionTopic}, false); err != nil {
+		h.log.Error("failed to register events acceptor",
+			zap.String("chainName", chainName),
+			zap.Error(err),
+		)
+		return
+	}
+	if err := h.consensusAcceptors.RegisterAcceptor(chainID, eventsAcceptorName, eventAcceptor{hub: h, topic: AcceptedTopic}, false); err != nil {
+		h.log.Error("failed to register events acceptor",
+			zap.String("chainName", chainName),
+			zap.Error(err),
+		)
+	}
+}
+
+func (h *EventHub) DeregisterChain(chainID ids.ID) {
+	// The acceptors may not have been registered, so the errors are dropped.
+	_ = h.decisionAcceptors.DeregisterAcceptor(chainID, eventsAcceptorName)
+	_ = h.consensusAcceptors.DeregisterAcceptor(chainID, eventsAcceptorName)
+}
+
+// Publish sends [event] to the clients subscribed to it. Clients that have too
+// many pending events are disconnected.
+func (h *EventHub) Publish(event Event) {
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+
+	for conn := range h.conns {
+		if !conn.isSubscribed(event.ChainID, event.Topic) {
+			continue
+		}
+		if !conn.send(event) {
+			h.log.Debug("disconnecting event client",
+				zap.String("reason", "too many pending events"),
+				zap.String("remoteAddr", conn.conn.RemoteAddr().String()),
+			)
+			conn.close()
+		}
+	}
+}
+
+func (h *EventHub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.lock.RLock()
+	full := h.closed || len(h.conns) >= h.config.MaxConnections
+	h.lock.RUnlock()
+	if full {
+		http.Error(w, "too many event clients", http.StatusServiceUnavailable)
+		return
+	}
+
+	wsConn, err := h.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		h.log.Debug("failed to upgrade",
+			zap.Error(err),
+		)
+		return
+	}
+	conn := &eventConn{
+		hub:           h,
+		conn:          wsConn,
+		sendCh:        make(chan interface{}, h.config.MaxPendingEvents),
+		closing:       make(chan struct{}),
+		subscriptions: make(map[ids.ID]map[string]struct{}),
+	}
+
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	// The hub may have filled up or closed while the connection was upgraded.
+	if h.closed || len(h.conns) >= h.config.MaxConnections {
+		_ = wsConn.Close()
+		return
+	}
+	h.conns[conn] = struct{}{}
+	go conn.writePump()
+	go conn.readPump()
+}
+
+// Close disconnects all the clients of the hub. Clients that connect after
+// Close is called are rejected.
+func (h *EventHub) Close() {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	h.closed = true
+	for conn := range h.conns {
+		conn.close()
+	}
+}
+
+func (h *EventHub) removeConn(conn *eventConn) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	delete(h.conns, conn)
+}
+
+// resolveChain returns the ID of the chain referred to by [chain], which may
+// be either its ID or one of its aliases.
+func (h *EventHub) resolveChain(chain string) (ids.ID, error) {
+	if chainID, err := h.aliaser.Lookup(chain); err == nil {
+		return chainID, nil
+	}
+	chainID, err := ids.FromString(chain)
+	if err != nil {
+		return ids.Empty, fmt.Errorf("%w: %q", errUnknownEventChain, chain)
+	}
+	return chainID, nil
+}
+
+type eventAcceptor struct {
+	hub   *EventHub
+	topic string
+}
+
+func (a eventAcceptor) Accept(ctx *snow.ConsensusContext, containerID ids.ID, _ []byte) error {
+	a.hub.Publish(Event{
+		ChainID:     ctx.ChainID,
+		Topic:       a.topic,
+		ContainerID: containerID,
+	})
+	return nil
+}
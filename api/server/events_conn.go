@@ -0,0 +1,254 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"go.uber.org/zap"
+
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+// eventConn is the WebSocket connection of a client of the event hub.
+type eventConn struct {
+	hub  *EventHub
+	conn *websocket.Conn
+
+	// Buffered channel of outbound messages
+	sendCh chan interface{}
+
+	closeOnce sync.Once
+	// closed when the connection is closed
+	closing chan struct{}
+
+	lock sync.RWMutex
+	// Chain ID -> topics the client is subscribed to. The topics of
+	// [ids.Empty] apply to every chain.
+	subscriptions map[ids.ID]map[string]struct{}
+	// Number of (chain, topic) pairs in [subscriptions]
+	numSubscriptions int
+}
+
+func (c *eventConn) isSubscribed(chainID ids.ID, topic string) bool {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	if _, ok := c.subscriptions[chainID][topic]; ok {
+		return true
+	}
+	_, ok := c.subscriptions[ids.Empty][topic]
+	return ok
+}
+
+// send queues [msg] to be written to the client. Returns false if the client
+// has too many pending messages.
+func (c *eventConn) send(msg interface{}) bool {
+	select {
+	case <-c.closing:
+		return true
+	default:
+	}
+	select {
+	case c.sendCh <- msg:
+		return true
+	default:
+		return false
+	}
+}
+
+func (c *eventConn) close() {
+	c.closeOnce.Do(func() {
+		close(c.closing)
+		// Closing the connection unblocks the pumps, which remove the
+		// connection from the hub.
+		_ = c.conn.Close()
+	})
+}
+
+// readPump reads the commands of the client. At most one readPump runs per
+// connection.
+func (c *eventConn) readPump() {
+	defer func() {
+		c.close()
+		c.hub.removeConn(c)
+	}()
+
+	c.conn.SetReadLimit(eventsMaxMessageSize)
+	// SetReadDeadline returns an error if the connection is corrupted
+	if err := c.conn.SetReadDeadline(time.Now().Add(eventsPongWait)); err != nil {
+		return
+	}
+	c.conn.SetPongHandler(func(string) error {
+		return c.conn.SetReadDeadline(time.Now().Add(eventsPongWait))
+	})
+
+	for {
+		_, msg, err := c.conn.ReadMessage()
+		if err != nil {
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
+				c.hub.log.Debug("unexpected close of event client",
+					zap.Error(err),
+				)
+			}
+			return
+		}
+		// Invalid commands are reported to the client without closing the
+		// connection.
+		if err := c.handleCommand(msg); err != nil {
+			c.send(&eventError{
+				Error: err.Error(),
+			})
+		}
+	}
+}
+
+// writePump writes the queued messages and the pings to the client. At most
+// one writePump runs per connection.
+func (c *eventConn) writePump() {
+	ticker := time.NewTicker(eventsPingPeriod)
+	defer func() {
+		ticker.Stop()
+		c.close()
+	}()
+
+	for {
+		select {
+		case msg := <-c.sendCh:
+			if err := c.conn.SetWriteDeadline(time.Now().Add(eventsWriteWait)); err != nil {
+				return
+			}
+			if err := c.conn.WriteJSON(msg); err != nil {
+				return
+			}
+		case <-ticker.C:
+			if err := c.conn.SetWriteDeadline(time.Now().Add(eventsWriteWait)); err != nil {
+				return
+			}
+			if err := c.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		case <-c.closing:
+			return
+		}
+	}
+}
+
+func (c *eventConn) handleCommand(msg []byte) error {
+	cmd := EventCommand{}
+	if err := json.Unmarshal(msg, &cmd); err != nil {
+		return fmt.Errorf("%w: %s", errInvalidEventCommand, err)
+	}
+	switch {
+	case cmd.Subscribe != nil:
+		return c.subscribe(cmd.Subscribe)
+	case cmd.Unsubscribe != nil:
+		return c.unsubscribe(cmd.Unsubscribe)
+	default:
+		return errInvalidEventCommand
+	}
+}
+
+func (c *eventConn) subscribe(subscription *EventSubscription) error {
+	chainIDs, topics, err := c.parseSubscription(subscription)
+	if err != nil {
+		return err
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	numSubscriptions := c.numSubscriptions
+	for _, chainID := range chainIDs {
+		for _, topic := range topics {
+			if _, ok := c.subscriptions[chainID][topic]; !ok {
+				numSubscriptions++
+			}
+		}
+	}
+	if numSubscriptions > c.hub.config.MaxSubscriptions {
+		return fmt.Errorf("%w: %d > %d", errTooManyEventSubscriptions, numSubscriptions, c.hub.config.MaxSubscriptions)
+	}
+
+	for _, chainID := range chainIDs {
+		chainTopics, ok := c.subscriptions[chainID]
+		if !ok {
+			chainTopics = make(map[string]struct{}, len(topics))
+			c.subscriptions[chainID] = chainTopics
+		}
+		for _, topic := range topics {
+			chainTopics[topic] = struct{}{}
+		}
+	}
+	c.numSubscriptions = numSubscriptions
+	return nil
+}
+
+func (c *eventConn) unsubscribe(subscription *EventSubscription) error {
+	chainIDs, topics, err := c.parseSubscription(subscription)
+	if err != nil {
+		return err
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	for _, chainID := range chainIDs {
+		chainTopics := c.subscriptions[chainID]
+		for _, topic := range topics {
+			if _, ok := chainTopics[topic]; ok {
+				delete(chainTopics, topic)
+				c.numSubscriptions--
+			}
+		}
+		if len(chainTopics) == 0 {
+			delete(c.subscriptions, chainID)
+		}
+	}
+	return nil
+}
+
+// parseSubscription verifies the topics of [subscription] and returns the IDs
+// of its chains and its topics, without duplicates.
+func (c *eventConn) parseSubscription(subscription *EventSubscription) ([]ids.ID, []string, error) {
+	if len(subscription.Topics) == 0 {
+		return nil, nil, errNoEventTopics
+	}
+	topicSet := make(map[string]struct{}, len(subscription.Topics))
+	topics := make([]string, 0, len(subscription.Topics))
+	for _, topic := range subscription.Topics {
+		if _, ok := eventTopics[topic]; !ok {
+			return nil, nil, fmt.Errorf("%w: %q", errUnknownEventTopic, topic)
+		}
+		if _, ok := topicSet[topic]; ok {
+			continue
+		}
+		topicSet[topic] = struct{}{}
+		topics = append(topics, topic)
+	}
+
+	if len(subscription.Chains) == 0 {
+		return []ids.ID{ids.Empty}, topics, nil
+	}
+	// A chain may be referred to by both its ID and its aliases
+	chainIDSet := ids.NewSet(len(subscription.Chains))
+	chainIDs := make([]ids.ID, 0, len(subscription.Chains))
+	for _, chain := range subscription.Chains {
+		chainID, err := c.hub.resolveChain(chain)
+		if err != nil {
+			return nil, nil, err
+		}
+		if chainIDSet.Contains(chainID) {
+			continue
+		}
+		chainIDSet.Add(chainID)
+		chainIDs = append(chainIDs, chainID)
+	}
+	return chainIDs, topics, nil
+}
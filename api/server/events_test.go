@@ -0,0 +1,317 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+func newTestEventHub(t *testing.T, config EventsConfig) (*EventHub, *snow.ConsensusContext, string) {
+	require := require.New(t)
+
+	ctx := snow.DefaultConsensusContextTest()
+	ctx.ChainID = ids.GenerateTestID()
+	aliaser := ids.NewAliaser()
+	require.NoError(aliaser.Alias(ctx.ChainID, "X"))
+
+	s := New()
+	s.Initialize(logging.NoLog{}, nil, "127.0.0.1", 0, []string{"https://a.example"}, time.Second, ids.EmptyNodeID, false, nil)
+
+	hub, err := NewEventHub(
+		logging.NoLog{},
+		config,
+		aliaser,
+		s,
+		snow.NewAcceptorGroup(logging.NoLog{}),
+		snow.NewAcceptorGroup(logging.NoLog{}),
+	)
+	require.NoError(err)
+	hub.RegisterChain("X", &common.EngineTest{
+		ContextF: func() *snow.ConsensusContext {
+			return ctx
+		},
+	})
+
+	srv := httptest.NewServer(hub)
+	t.Cleanup(func() {
+		hub.Close()
+		srv.Close()
+	})
+	return hub, ctx, "ws" + strings.TrimPrefix(srv.URL, "http")
+}
+
+func dialEventHub(t *testing.T, url string) *websocket.Conn {
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = conn.Close()
+	})
+	return conn
+}
+
+// subscribe subscribes [conn] and waits for the hub to handle the
+// subscription, by waiting for the error of an invalid command sent after it.
+func subscribe(t *testing.T, conn *websocket.Conn, cmd EventCommand) {
+	require := require.New(t)
+
+	require.NoError(conn.WriteJSON(cmd))
+	require.NoError(conn.WriteJSON(EventCommand{}))
+	reply := eventError{}
+	require.NoError(conn.ReadJSON(&reply))
+	require.Equal(errInvalidEventCommand.Error(), reply.Error)
+}
+
+func TestEventHubPublish(t *testing.T) {
+	require := require.New(t)
+
+	hub, ctx, url := newTestEventHub(t, EventsConfig{
+		MaxConnections:   2,
+		MaxPendingEvents: 16,
+		MaxSubscriptions: 4,
+	})
+
+	subscribed := dialEventHub(t, url)
+	subscribe(t, subscribed, EventCommand{
+		Subscribe: &EventSubscription{
+			Chains: []string{"X"},
+			Topics: []string{AcceptedTopic},
+		},
+	})
+	everything := dialEventHub(t, url)
+	subscribe(t, everything, EventCommand{
+		Subscribe: &EventSubscription{
+			Topics: []string{AcceptedTopic, DecisionTopic},
+		},
+	})
+
+	// The hub is full.
+	_, resp, err := websocket.DefaultDialer.Dial(url, nil)
+	require.ErrorIs(err, websocket.ErrBadHandshake)
+	require.Equal(http.StatusServiceUnavailable, resp.StatusCode)
+	_ = resp.Body.Close()
+
+	// Accepted decisions are only sent to the clients subscribed to them.
+	decisionID := ids.GenerateTestID()
+	require.NoError(hub.decisionAcceptors.Accept(ctx, decisionID, nil))
+	acceptedID := ids.GenerateTestID()
+	require.NoError(hub.consensusAcceptors.Accept(ctx, acceptedID, nil))
+
+	event := Event{}
+	require.NoError(subscribed.ReadJSON(&event))
+	require.Equal(Event{
+		ChainID:     ctx.ChainID,
+		Topic:       AcceptedTopic,
+		ContainerID: acceptedID,
+	}, event)
+
+	require.NoError(everything.ReadJSON(&event))
+	require.Equal(decisionID, event.ContainerID)
+	require.NoError(everything.ReadJSON(&event))
+	require.Equal(acceptedID, event.ContainerID)
+
+	// Events aren't published after the chain is deregistered.
+	hub.DeregisterChain(ctx.ChainID)
+	require.NoError(hub.consensusAcceptors.Accept(ctx, ids.GenerateTestID(), nil))
+	subscribe(t, subscribed, EventCommand{
+		Unsubscribe: &EventSubscription{
+			Chains: []string{"X"},
+			Topics: []string{AcceptedTopic},
+		},
+	})
+}
+
+func TestEventHubInvalidSubscriptions(t *testing.T) {
+	require := require.New(t)
+
+	_, _, url := newTestEventHub(t, EventsConfig{
+		MaxConnections:   1,
+		MaxPendingEvents: 16,
+		MaxSubscriptions: 1,
+	})
+	conn := dialEventHub(t, url)
+
+	tests := []struct {
+		subscription EventSubscription
+		expectedErr  error
+	}{
+		{
+			subscription: EventSubscription{
+				Chains: []string{"X"},
+			},
+			expectedErr: errNoEventTopics,
+		},
+		{
+			subscription: EventSubscription{
+				Topics: []string{"blocks"},
+			},
+			expectedErr: errUnknownEventTopic,
+		},
+		{
+			subscription: EventSubscription{
+				Chains: []string{"Y"},
+				Topics: []string{AcceptedTopic},
+			},
+			expectedErr: errUnknownEventChain,
+		},
+		{
+			subscription: EventSubscription{
+				Chains: []string{"X"},
+				Topics: []string{AcceptedTopic, DecisionTopic},
+			},
+			expectedErr: errTooManyEventSubscriptions,
+		},
+	}
+	for _, test := range tests {
+		subscription := test.subscription
+		require.NoError(conn.WriteJSON(EventCommand{
+			Subscribe: &subscription,
+		}))
+		reply := eventError{}
+		require.NoError(conn.ReadJSON(&reply))
+		require.True(strings.HasPrefix(reply.Error, test.expectedErr.Error()), reply.Error)
+	}
+}
+
+func TestEventHubDuplicateSubscriptions(t *testing.T) {
+	require := require.New(t)
+
+	hub, ctx, url := newTestEventHub(t, EventsConfig{
+		MaxConnections:   1,
+		MaxPendingEvents: 16,
+		MaxSubscriptions: 1,
+	})
+	conn := dialEventHub(t, url)
+
+	// The chain is referred to by both its alias and its ID, and the topic is
+	// repeated, but the subscription is a single (chain, topic) pair.
+	subscription := EventSubscription{
+		Chains: []string{"X", ctx.ChainID.String()},
+		Topics: []string{AcceptedTopic, AcceptedTopic},
+	}
+	subscribe(t, conn, EventCommand{
+		Subscribe: &subscription,
+	})
+	subscribe(t, conn, EventCommand{
+		Unsubscribe: &subscription,
+	})
+
+	hub.lock.RLock()
+	defer hub.lock.RUnlock()
+	for eventConn := range hub.conns {
+		eventConn.lock.RLock()
+		require.Zero(eventConn.numSubscriptions)
+		eventConn.lock.RUnlock()
+	}
+}
+
+func TestEventHubCheckOrigin(t *testing.T) {
+	require := require.New(t)
+
+	_, _, url := newTestEventHub(t, EventsConfig{
+		MaxConnections:   1,
+		MaxPendingEvents: 16,
+		MaxSubscriptions: 1,
+	})
+
+	header := http.Header{}
+	header.Set("Origin", "https://b.example")
+	_, resp, err := websocket.DefaultDialer.Dial(url, header)
+	require.ErrorIs(err, websocket.ErrBadHandshake)
+	require.Equal(http.StatusForbidden, resp.StatusCode)
+	_ = resp.Body.Close()
+
+	header.Set("Origin", "https://a.example")
+	conn, _, err := websocket.DefaultDialer.Dial(url, header)
+	require.NoError(err)
+	require.NoError(conn.Close())
+}
+
+func TestEventHubDisconnectsSlowClients(t *testing.T) {
+	require := require.New(t)
+
+	hub, ctx, url := newTestEventHub(t, EventsConfig{
+		MaxConnections:   1,
+		MaxPendingEvents: 1,
+		MaxSubscriptions: 1,
+	})
+	conn := dialEventHub(t, url)
+	subscribe(t, conn, EventCommand{
+		Subscribe: &EventSubscription{
+			Topics: []string{AcceptedTopic},
+		},
+	})
+
+	// Publish events until the client, which isn't reading them, falls behind.
+	require.Eventually(func() bool {
+		for i := 0; i < 1000; i++ {
+			hub.Publish(Event{
+				ChainID: ctx.ChainID,
+				Topic:   AcceptedTopic,
+			})
+		}
+		hub.lock.RLock()
+		defer hub.lock.RUnlock()
+		return len(hub.conns) == 0
+	}, 10*time.Second, time.Millisecond)
+}
+
+func TestEventsConfigVerify(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      EventsConfig
+		expectedErr error
+	}{
+		{
+			name: "valid",
+			config: EventsConfig{
+				MaxConnections:   1,
+				MaxPendingEvents: 1,
+				MaxSubscriptions: 1,
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "no connections",
+			config: EventsConfig{
+				MaxPendingEvents: 1,
+				MaxSubscriptions: 1,
+			},
+			expectedErr: errInvalidMaxEventConnections,
+		},
+		{
+			name: "no pending events",
+			config: EventsConfig{
+				MaxConnections:   1,
+				MaxSubscriptions: 1,
+			},
+			expectedErr: errInvalidMaxPendingEvents,
+		},
+		{
+			name: "no subscriptions",
+			config: EventsConfig{
+				MaxConnections:   1,
+				MaxPendingEvents: 1,
+			},
+			expectedErr: errInvalidMaxEventSubscriptions,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.ErrorIs(t, test.config.Verify(), test.expectedErr)
+		})
+	}
+}
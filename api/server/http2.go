@@ -0,0 +1,98 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+)
+
+const (
+	minHTTP2MaxReadFrameSize = 1 << 14
+	maxHTTP2MaxReadFrameSize = 1<<24 - 1
+)
+
+var (
+	errH2CWithoutHTTP2             = errors.New("h2c requires HTTP/2 to be enabled")
+	errInvalidMaxConcurrentStreams = errors.New("max concurrent streams must be > 0")
+	errInvalidMaxReadFrameSize     = errors.New("max read frame size must be in [16KiB, 16MiB)")
+	errInvalidHTTP2IdleTimeout     = errors.New("HTTP/2 idle timeout must be >= 0")
+)
+
+// HTTP2Config configures the HTTP/2 support of the API listeners. HTTP/2 is
+// negotiated with the clients of the TLS listeners. Clients of the plaintext
+// listeners can only use HTTP/2 without prior negotiation (h2c), if enabled.
+type HTTP2Config struct {
+	Enabled bool `json:"enabled"`
+	// If true, the plaintext listeners accept HTTP/2 without TLS
+	H2CEnabled bool `json:"h2cEnabled"`
+	// Max number of concurrent streams of each client connection
+	MaxConcurrentStreams uint32 `json:"maxConcurrentStreams"`
+	// Max size of the frames read from client connections
+	MaxReadFrameSize uint32 `json:"maxReadFrameSize"`
+	// Duration after which idle client connections are closed. If 0, idle
+	// connections aren't closed.
+	IdleTimeout time.Duration `json:"idleTimeout"`
+}
+
+func (c *HTTP2Config) Verify() error {
+	switch {
+	case !c.Enabled && c.H2CEnabled:
+		return errH2CWithoutHTTP2
+	case !c.Enabled:
+		return nil
+	case c.MaxConcurrentStreams == 0:
+		return errInvalidMaxConcurrentStreams
+	case c.MaxReadFrameSize < minHTTP2MaxReadFrameSize || c.MaxReadFrameSize > maxHTTP2MaxReadFrameSize:
+		return errInvalidMaxReadFrameSize
+	case c.IdleTimeout < 0:
+		return errInvalidHTTP2IdleTimeout
+	default:
+		return nil
+	}
+}
+
+func (s *server) ConfigureHTTP2(config HTTP2Config) error {
+	if err := config.Verify(); err != nil {
+		return err
+	}
+	s.http2Config = config
+	return nil
+}
+
+// nextProtos returns the protocols negotiated with the clients of the TLS
+// listeners, in order of preference.
+func (s *server) nextProtos() []string {
+	if !s.http2Config.Enabled {
+		return nil
+	}
+	return []string{http2.NextProtoTLS, "http/1.1"}
+}
+
+// configureHTTP2 enables HTTP/2 on [srv], if configured. If [plaintext], the
+// handler of [srv] is wrapped to accept h2c, if configured.
+func (s *server) configureHTTP2(srv *http.Server, plaintext bool) error {
+	if !s.http2Config.Enabled {
+		return nil
+	}
+
+	// Each http.Server tracks its own HTTP/2 connections, so the HTTP/2
+	// settings can't be shared between listeners.
+	http2Server := &http2.Server{
+		MaxConcurrentStreams: s.http2Config.MaxConcurrentStreams,
+		MaxReadFrameSize:     s.http2Config.MaxReadFrameSize,
+		IdleTimeout:          s.http2Config.IdleTimeout,
+	}
+	if err := http2.ConfigureServer(srv, http2Server); err != nil {
+		return err
+	}
+	if plaintext && s.http2Config.H2CEnabled {
+		srv.Handler = h2c.NewHandler(srv.Handler, http2Server)
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"crypto/tls"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"golang.org/x/net/http2"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+func TestHTTP2ConfigVerify(t *testing.T) {
+	valid := HTTP2Config{
+		Enabled:              true,
+		H2CEnabled:           true,
+		MaxConcurrentStreams: 250,
+		MaxReadFrameSize:     1 << 20,
+	}
+	tests := []struct {
+		name        string
+		config      func() HTTP2Config
+		expectedErr error
+	}{
+		{
+			name:   "disabled",
+			config: func() HTTP2Config { return HTTP2Config{} },
+		},
+		{
+			name:   "valid",
+			config: func() HTTP2Config { return valid },
+		},
+		{
+			name: "h2c without HTTP/2",
+			config: func() HTTP2Config {
+				return HTTP2Config{H2CEnabled: true}
+			},
+			expectedErr: errH2CWithoutHTTP2,
+		},
+		{
+			name: "no concurrent streams",
+			config: func() HTTP2Config {
+				config := valid
+				config.MaxConcurrentStreams = 0
+				return config
+			},
+			expectedErr: errInvalidMaxConcurrentStreams,
+		},
+		{
+			name: "frame size too small",
+			config: func() HTTP2Config {
+				config := valid
+				config.MaxReadFrameSize = 1 << 13
+				return config
+			},
+			expectedErr: errInvalidMaxReadFrameSize,
+		},
+		{
+			name: "frame size too large",
+			config: func() HTTP2Config {
+				config := valid
+				config.MaxReadFrameSize = 1 << 24
+				return config
+			},
+			expectedErr: errInvalidMaxReadFrameSize,
+		},
+		{
+			name: "negative idle timeout",
+			config: func() HTTP2Config {
+				config := valid
+				config.IdleTimeout = -time.Second
+				return config
+			},
+			expectedErr: errInvalidHTTP2IdleTimeout,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			config := test.config()
+			require.ErrorIs(t, config.Verify(), test.expectedErr)
+		})
+	}
+}
+
+func TestServeH2C(t *testing.T) {
+	require := require.New(t)
+
+	s := New().(*server)
+	require.NoError(s.ConfigureHTTP2(HTTP2Config{
+		Enabled:              true,
+		H2CEnabled:           true,
+		MaxConcurrentStreams: 250,
+		MaxReadFrameSize:     1 << 20,
+	}))
+	s.Initialize(logging.NoLog{}, nil, "127.0.0.1", 0, nil, time.Second, ids.EmptyNodeID, false, nil)
+
+	protoMajor := 0
+	require.NoError(s.router.AddRouter(baseURL+"/info", "", http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		protoMajor = r.ProtoMajor
+	})))
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(err)
+	go func() {
+		_ = s.serve(listener, "127.0.0.1", "test listener", s.handler, true)
+	}()
+	defer func() {
+		require.NoError(s.Shutdown())
+	}()
+
+	client := &http.Client{
+		Transport: &http2.Transport{
+			AllowHTTP: true,
+			DialTLS: func(network, addr string, _ *tls.Config) (net.Conn, error) {
+				return net.Dial(network, addr)
+			},
+		},
+	}
+	resp, err := client.Get("http://" + listener.Addr().String() + "/ext/info")
+	require.NoError(err)
+	require.NoError(resp.Body.Close())
+	require.Equal(http.StatusOK, resp.StatusCode)
+	require.Equal(2, resp.ProtoMajor)
+	require.Equal(2, protoMajor)
+}
@@ -0,0 +1,89 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"net/http"
+	"path"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+)
+
+// unknownBase labels the requests to paths that aren't served under any
+// route, so that they don't add a label value each.
+const unknownBase = "unknown"
+
+type requestMetrics struct {
+	requests *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+	inFlight *prometheus.GaugeVec
+}
+
+func newRequestMetrics(namespace string, registerer prometheus.Registerer) (*requestMetrics, error) {
+	m := &requestMetrics{
+		requests: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Name:      "http_requests",
+				Help:      "number of API requests handled, by base path and status code",
+			},
+			[]string{"base", "code"},
+		),
+		duration: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Namespace: namespace,
+				Name:      "http_request_duration_seconds",
+				Help:      "duration of the handling of API requests, by base path",
+				Buckets:   prometheus.DefBuckets,
+			},
+			[]string{"base"},
+		),
+		inFlight: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Name:      "http_requests_in_flight",
+				Help:      "number of API requests being handled, by base path",
+			},
+			[]string{"base"},
+		),
+	}
+
+	errs := wrappers.Errs{}
+	errs.Add(
+		registerer.Register(m.requests),
+		registerer.Register(m.duration),
+		registerer.Register(m.inFlight),
+	)
+	return m, errs.Err
+}
+
+func (s *server) MeasureRequests(namespace string, registerer prometheus.Registerer) error {
+	m, err := newRequestMetrics(namespace, registerer)
+	if err != nil {
+		return err
+	}
+
+	handler := s.handler
+	s.handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		base, ok := s.router.currentBase(path.Clean(r.URL.Path))
+		if !ok {
+			base = unknownBase
+		}
+
+		inFlight := m.inFlight.WithLabelValues(base)
+		inFlight.Inc()
+		defer inFlight.Dec()
+
+		recorder := NewStatusRecorder(w)
+		start := time.Now()
+		handler.ServeHTTP(recorder, r)
+		m.duration.WithLabelValues(base).Observe(time.Since(start).Seconds())
+		m.requests.WithLabelValues(base, strconv.Itoa(recorder.StatusCode())).Inc()
+	})
+	return nil
+}
@@ -0,0 +1,84 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	dto "github.com/prometheus/client_model/go"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+func TestMeasureRequests(t *testing.T) {
+	require := require.New(t)
+
+	s := New().(*server)
+	s.Initialize(logging.NoLog{}, nil, "127.0.0.1", 0, nil, time.Second, ids.EmptyNodeID, false, nil)
+
+	cChainID := ids.GenerateTestID()
+	url := fmt.Sprintf("%s/%s", chainBaseURL, cChainID)
+	require.NoError(s.router.AddRouter(url, "/rpc", &testHandler{}))
+	require.NoError(s.AddAliases(fmt.Sprintf("bc/%s", cChainID), "bc/C"))
+	require.NoError(s.router.AddRouter(baseURL+"/info", "", http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	})))
+
+	registry := prometheus.NewRegistry()
+	require.NoError(s.MeasureRequests("api", registry))
+
+	for _, path := range []string{
+		"/ext/bc/C/rpc",
+		fmt.Sprintf("/ext/bc/%s/rpc", cChainID),
+		"/ext/bc/C/unknown",
+		"/ext/info",
+		"/unknown",
+	} {
+		s.handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, path, nil))
+	}
+
+	families, err := registry.Gather()
+	require.NoError(err)
+	counts := map[string]float64{}
+	for _, family := range families {
+		switch family.GetName() {
+		case "api_http_requests":
+			for _, metric := range family.GetMetric() {
+				counts[labels(metric)] = metric.GetCounter().GetValue()
+			}
+		case "api_http_requests_in_flight":
+			for _, metric := range family.GetMetric() {
+				require.Zero(metric.GetGauge().GetValue())
+			}
+		}
+	}
+
+	// The requests made through a chain's ID and its alias share a label.
+	require.Equal(map[string]float64{
+		"base=/ext/bc/C,code=200": 2,
+		"base=/ext/bc/C,code=404": 1,
+		"base=/ext/info,code=400": 1,
+		"base=unknown,code=404":   1,
+	}, counts)
+}
+
+func labels(metric *dto.Metric) string {
+	s := ""
+	for i, label := range metric.GetLabel() {
+		if i > 0 {
+			s += ","
+		}
+		s += label.GetName() + "=" + label.GetValue()
+	}
+	return s
+}
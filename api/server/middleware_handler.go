@@ -19,5 +19,10 @@ func (mh middlewareHandler) ServeHTTP(writer http.ResponseWriter, request *http.
 	if mh.after != nil {
 		defer mh.after()
 	}
+	// The request may have timed out while waiting for [before] to return, in
+	// which case the response has already been written.
+	if request.Context().Err() != nil {
+		return
+	}
 	mh.handler.ServeHTTP(writer, request)
 }
@@ -8,15 +8,19 @@
 package server
 
 import (
+	context "context"
+	http "net/http"
 	reflect "reflect"
 	sync "sync"
 	time "time"
 
+	pagination "github.com/ava-labs/avalanchego/api/pagination"
 	ids "github.com/ava-labs/avalanchego/ids"
 	common "github.com/ava-labs/avalanchego/snow/engine/common"
 	trace "github.com/ava-labs/avalanchego/trace"
 	logging "github.com/ava-labs/avalanchego/utils/logging"
 	gomock "github.com/golang/mock/gomock"
+	prometheus "github.com/prometheus/client_golang/prometheus"
 )
 
 // MockServer is a mock of Server interface.
@@ -80,6 +84,25 @@ func (mr *MockServerMockRecorder) AddAliasesWithReadLock(arg0 interface{}, arg1
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddAliasesWithReadLock", reflect.TypeOf((*MockServer)(nil).AddAliasesWithReadLock), varargs...)
 }
 
+// AddRetiredAliases mocks base method.
+func (m *MockServer) AddRetiredAliases(arg0 string, arg1 ...string) error {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0}
+	for _, a := range arg1 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "AddRetiredAliases", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddRetiredAliases indicates an expected call of AddRetiredAliases.
+func (mr *MockServerMockRecorder) AddRetiredAliases(arg0 interface{}, arg1 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0}, arg1...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddRetiredAliases", reflect.TypeOf((*MockServer)(nil).AddRetiredAliases), varargs...)
+}
+
 // AddRoute mocks base method.
 func (m *MockServer) AddRoute(arg0 *common.HTTPHandler, arg1 *sync.RWMutex, arg2, arg3 string) error {
 	m.ctrl.T.Helper()
@@ -108,6 +131,144 @@ func (mr *MockServerMockRecorder) AddRouteWithReadLock(arg0, arg1, arg2, arg3 in
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddRouteWithReadLock", reflect.TypeOf((*MockServer)(nil).AddRouteWithReadLock), arg0, arg1, arg2, arg3)
 }
 
+// AllowedOrigins mocks base method.
+func (m *MockServer) AllowedOrigins() []string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AllowedOrigins")
+	ret0, _ := ret[0].([]string)
+	return ret0
+}
+
+// AllowedOrigins indicates an expected call of AllowedOrigins.
+func (mr *MockServerMockRecorder) AllowedOrigins() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AllowedOrigins", reflect.TypeOf((*MockServer)(nil).AllowedOrigins))
+}
+
+// CheckOrigin mocks base method.
+func (m *MockServer) CheckOrigin(arg0 *http.Request) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckOrigin", arg0)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// CheckOrigin indicates an expected call of CheckOrigin.
+func (mr *MockServerMockRecorder) CheckOrigin(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckOrigin", reflect.TypeOf((*MockServer)(nil).CheckOrigin), arg0)
+}
+
+// ConfigureAdminListener mocks base method.
+func (m *MockServer) ConfigureAdminListener(arg0 AdminListenerConfig) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConfigureAdminListener", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ConfigureAdminListener indicates an expected call of ConfigureAdminListener.
+func (mr *MockServerMockRecorder) ConfigureAdminListener(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConfigureAdminListener", reflect.TypeOf((*MockServer)(nil).ConfigureAdminListener), arg0)
+}
+
+// ConfigureBatches mocks base method.
+func (m *MockServer) ConfigureBatches(arg0 BatchConfig) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConfigureBatches", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ConfigureBatches indicates an expected call of ConfigureBatches.
+func (mr *MockServerMockRecorder) ConfigureBatches(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConfigureBatches", reflect.TypeOf((*MockServer)(nil).ConfigureBatches), arg0)
+}
+
+// ConfigureHTTP2 mocks base method.
+func (m *MockServer) ConfigureHTTP2(arg0 HTTP2Config) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConfigureHTTP2", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ConfigureHTTP2 indicates an expected call of ConfigureHTTP2.
+func (mr *MockServerMockRecorder) ConfigureHTTP2(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConfigureHTTP2", reflect.TypeOf((*MockServer)(nil).ConfigureHTTP2), arg0)
+}
+
+// ConfigureMaxPageSizes mocks base method.
+func (m *MockServer) ConfigureMaxPageSizes(arg0 pagination.Limits) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConfigureMaxPageSizes", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ConfigureMaxPageSizes indicates an expected call of ConfigureMaxPageSizes.
+func (mr *MockServerMockRecorder) ConfigureMaxPageSizes(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConfigureMaxPageSizes", reflect.TypeOf((*MockServer)(nil).ConfigureMaxPageSizes), arg0)
+}
+
+// ConfigureResponseCache mocks base method.
+func (m *MockServer) ConfigureResponseCache(arg0 ResponseCacheConfig) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConfigureResponseCache", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ConfigureResponseCache indicates an expected call of ConfigureResponseCache.
+func (mr *MockServerMockRecorder) ConfigureResponseCache(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConfigureResponseCache", reflect.TypeOf((*MockServer)(nil).ConfigureResponseCache), arg0)
+}
+
+// ConfigureTLS mocks base method.
+func (m *MockServer) ConfigureTLS(arg0 TLSConfig) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConfigureTLS", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ConfigureTLS indicates an expected call of ConfigureTLS.
+func (mr *MockServerMockRecorder) ConfigureTLS(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConfigureTLS", reflect.TypeOf((*MockServer)(nil).ConfigureTLS), arg0)
+}
+
+// ConfigureTimeouts mocks base method.
+func (m *MockServer) ConfigureTimeouts(arg0 time.Duration, arg1 []RouteTimeout) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConfigureTimeouts", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ConfigureTimeouts indicates an expected call of ConfigureTimeouts.
+func (mr *MockServerMockRecorder) ConfigureTimeouts(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConfigureTimeouts", reflect.TypeOf((*MockServer)(nil).ConfigureTimeouts), arg0, arg1)
+}
+
+// DeregisterChain mocks base method.
+func (m *MockServer) DeregisterChain(arg0 ids.ID) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "DeregisterChain", arg0)
+}
+
+// DeregisterChain indicates an expected call of DeregisterChain.
+func (mr *MockServerMockRecorder) DeregisterChain(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeregisterChain", reflect.TypeOf((*MockServer)(nil).DeregisterChain), arg0)
+}
+
 // Dispatch mocks base method.
 func (m *MockServer) Dispatch() error {
 	m.ctrl.T.Helper()
@@ -136,6 +297,77 @@ func (mr *MockServerMockRecorder) DispatchTLS(arg0, arg1 interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DispatchTLS", reflect.TypeOf((*MockServer)(nil).DispatchTLS), arg0, arg1)
 }
 
+// DispatchAdmin mocks base method.
+func (m *MockServer) DispatchAdmin() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DispatchAdmin")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DispatchAdmin indicates an expected call of DispatchAdmin.
+func (mr *MockServerMockRecorder) DispatchAdmin() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DispatchAdmin", reflect.TypeOf((*MockServer)(nil).DispatchAdmin))
+}
+
+// DispatchAdminTLS mocks base method.
+func (m *MockServer) DispatchAdminTLS(arg0, arg1 []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DispatchAdminTLS", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DispatchAdminTLS indicates an expected call of DispatchAdminTLS.
+func (mr *MockServerMockRecorder) DispatchAdminTLS(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DispatchAdminTLS", reflect.TypeOf((*MockServer)(nil).DispatchAdminTLS), arg0, arg1)
+}
+
+// DispatchScoped mocks base method.
+func (m *MockServer) DispatchScoped(arg0 ScopeConfig) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DispatchScoped", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DispatchScoped indicates an expected call of DispatchScoped.
+func (mr *MockServerMockRecorder) DispatchScoped(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DispatchScoped", reflect.TypeOf((*MockServer)(nil).DispatchScoped), arg0)
+}
+
+// DispatchScopedTLS mocks base method.
+func (m *MockServer) DispatchScopedTLS(arg0 ScopeConfig, arg1, arg2 []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DispatchScopedTLS", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DispatchScopedTLS indicates an expected call of DispatchScopedTLS.
+func (mr *MockServerMockRecorder) DispatchScopedTLS(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DispatchScopedTLS", reflect.TypeOf((*MockServer)(nil).DispatchScopedTLS), arg0, arg1, arg2)
+}
+
+// HealthCheck mocks base method.
+func (m *MockServer) HealthCheck(arg0 context.Context) (interface{}, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HealthCheck", arg0)
+	ret0, _ := ret[0].(interface{})
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HealthCheck indicates an expected call of HealthCheck.
+func (mr *MockServerMockRecorder) HealthCheck(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HealthCheck", reflect.TypeOf((*MockServer)(nil).HealthCheck), arg0)
+}
+
 // Initialize mocks base method.
 func (m *MockServer) Initialize(arg0 logging.Logger, arg1 logging.Factory, arg2 string, arg3 uint16, arg4 []string, arg5 time.Duration, arg6 ids.NodeID, arg7 bool, arg8 trace.Tracer, arg9 ...Wrapper) {
 	m.ctrl.T.Helper()
@@ -153,6 +385,62 @@ func (mr *MockServerMockRecorder) Initialize(arg0, arg1, arg2, arg3, arg4, arg5,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Initialize", reflect.TypeOf((*MockServer)(nil).Initialize), varargs...)
 }
 
+// LimitBodySize mocks base method.
+func (m *MockServer) LimitBodySize(arg0 int64, arg1 []BodySizeLimit) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LimitBodySize", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LimitBodySize indicates an expected call of LimitBodySize.
+func (mr *MockServerMockRecorder) LimitBodySize(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LimitBodySize", reflect.TypeOf((*MockServer)(nil).LimitBodySize), arg0, arg1)
+}
+
+// LimitRate mocks base method.
+func (m *MockServer) LimitRate(arg0 string, arg1 prometheus.Registerer, arg2 []RateLimit) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LimitRate", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LimitRate indicates an expected call of LimitRate.
+func (mr *MockServerMockRecorder) LimitRate(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LimitRate", reflect.TypeOf((*MockServer)(nil).LimitRate), arg0, arg1, arg2)
+}
+
+// MeasureRequests mocks base method.
+func (m *MockServer) MeasureRequests(arg0 string, arg1 prometheus.Registerer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MeasureRequests", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MeasureRequests indicates an expected call of MeasureRequests.
+func (mr *MockServerMockRecorder) MeasureRequests(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MeasureRequests", reflect.TypeOf((*MockServer)(nil).MeasureRequests), arg0, arg1)
+}
+
+// OpenAPIHandler mocks base method.
+func (m *MockServer) OpenAPIHandler(arg0 string) http.Handler {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OpenAPIHandler", arg0)
+	ret0, _ := ret[0].(http.Handler)
+	return ret0
+}
+
+// OpenAPIHandler indicates an expected call of OpenAPIHandler.
+func (mr *MockServerMockRecorder) OpenAPIHandler(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OpenAPIHandler", reflect.TypeOf((*MockServer)(nil).OpenAPIHandler), arg0)
+}
+
 // RegisterChain mocks base method.
 func (m *MockServer) RegisterChain(arg0 string, arg1 common.Engine) {
 	m.ctrl.T.Helper()
@@ -165,6 +453,44 @@ func (mr *MockServerMockRecorder) RegisterChain(arg0, arg1 interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterChain", reflect.TypeOf((*MockServer)(nil).RegisterChain), arg0, arg1)
 }
 
+// RegisterChainReplicas mocks base method.
+func (m *MockServer) RegisterChainReplicas(arg0 ids.ID, arg1 Replicas) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RegisterChainReplicas", arg0, arg1)
+}
+
+// RegisterChainReplicas indicates an expected call of RegisterChainReplicas.
+func (mr *MockServerMockRecorder) RegisterChainReplicas(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterChainReplicas", reflect.TypeOf((*MockServer)(nil).RegisterChainReplicas), arg0, arg1)
+}
+
+// ResolvePath mocks base method.
+func (m *MockServer) ResolvePath(arg0 string) string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResolvePath", arg0)
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// ResolvePath indicates an expected call of ResolvePath.
+func (mr *MockServerMockRecorder) ResolvePath(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolvePath", reflect.TypeOf((*MockServer)(nil).ResolvePath), arg0)
+}
+
+// SetAllowedOrigins mocks base method.
+func (m *MockServer) SetAllowedOrigins(arg0 []string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetAllowedOrigins", arg0)
+}
+
+// SetAllowedOrigins indicates an expected call of SetAllowedOrigins.
+func (mr *MockServerMockRecorder) SetAllowedOrigins(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAllowedOrigins", reflect.TypeOf((*MockServer)(nil).SetAllowedOrigins), arg0)
+}
+
 // Shutdown mocks base method.
 func (m *MockServer) Shutdown() error {
 	m.ctrl.T.Helper()
@@ -0,0 +1,157 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+
+	"go.uber.org/zap"
+
+	"github.com/ava-labs/avalanchego/utils/constants"
+)
+
+const (
+	openAPIVersion = "3.0.3"
+
+	// OpenAPIEndpoint is the endpoint the OpenAPI document is served under.
+	OpenAPIEndpoint = "openapi.json"
+)
+
+var (
+	// vmBaseURL is the prefix of the URLs of every VM's static API.
+	vmBaseURL = path.Join(baseURL, constants.VMAliasPrefix)
+	// openAPIURL is the URL of the OpenAPI document.
+	openAPIURL = path.Join(baseURL, OpenAPIEndpoint)
+)
+
+var errInvalidOpenAPIPathItem = errors.New("OpenAPI path item must be a JSON object")
+
+// servedPathsKey is the context key of the function that reports the paths
+// served by the listener a request was received on.
+type servedPathsKey struct{}
+
+type openAPIInfo struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+type openAPIDocument struct {
+	OpenAPI string                     `json:"openapi"`
+	Info    openAPIInfo                `json:"info"`
+	Paths   map[string]json.RawMessage `json:"paths"`
+}
+
+// AddSpec registers [pathItem], an OpenAPI path item object, as the
+// description of the handler of [base] + [endpoint]. The path item is also
+// used for the URLs of the aliases of [base].
+func (r *router) AddSpec(base, endpoint string, pathItem []byte) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(pathItem, &fields); err != nil || fields == nil {
+		return fmt.Errorf("%w: %s", errInvalidOpenAPIPathItem, base+endpoint)
+	}
+
+	r.routeLock.Lock()
+	defer r.routeLock.Unlock()
+
+	r.specs[base+endpoint] = pathItem
+	return nil
+}
+
+// openAPIPaths returns the OpenAPI path item of every URL that is routed to a
+// handler, including the URLs of the aliases of the routes.
+func (r *router) openAPIPaths() map[string]json.RawMessage {
+	r.routeLock.Lock()
+	defer r.routeLock.Unlock()
+
+	paths := make(map[string]json.RawMessage)
+	for base, endpoints := range r.routes {
+		original := r.original(base)
+		for endpoint := range endpoints {
+			paths[base+endpoint] = r.openAPIPathItem(original, endpoint)
+		}
+	}
+	return paths
+}
+
+// openAPIPathItem returns the path item of [base] + [endpoint]. The path items
+// of the APIs of chains and VMs without a summary are summarized with their
+// chain or VM.
+//
+// Assumes [r.routeLock] is held.
+func (r *router) openAPIPathItem(base, endpoint string) json.RawMessage {
+	fields := make(map[string]json.RawMessage)
+	if spec, ok := r.specs[base+endpoint]; ok {
+		// The path item was verified when it was added.
+		_ = json.Unmarshal(spec, &fields)
+	}
+	if _, ok := fields["summary"]; !ok {
+		switch path.Dir(base) {
+		case chainBaseURL:
+			fields["summary"], _ = json.Marshal(fmt.Sprintf("API of chain %s", path.Base(base)))
+		case vmBaseURL:
+			fields["summary"], _ = json.Marshal(fmt.Sprintf("Static API of VM %s", path.Base(base)))
+		}
+	}
+	pathItem, _ := json.Marshal(fields)
+	return pathItem
+}
+
+// OpenAPIHandler serves the OpenAPI document of the APIs of the server. Only
+// the paths served by the listener the document is requested from are
+// described.
+func (s *server) OpenAPIHandler(version string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+		paths := s.router.openAPIPaths()
+		if served, ok := r.Context().Value(servedPathsKey{}).(func(string) bool); ok {
+			for url := range paths {
+				if !served(url) {
+					delete(paths, url)
+				}
+			}
+		}
+		doc, err := json.Marshal(openAPIDocument{
+			OpenAPI: openAPIVersion,
+			Info: openAPIInfo{
+				Title:   "AvalancheGo API",
+				Version: version,
+			},
+			Paths: paths,
+		})
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		writeJSON(w, doc)
+	})
+}
+
+// withServedPaths returns [r] annotated with [served], which reports the paths
+// served by the listener [r] was received on.
+func withServedPaths(r *http.Request, served func(requestPath string) bool) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), servedPathsKey{}, served))
+}
+
+// addSpec registers [pathItem] as the description of [url] + [endpoint], if
+// it isn't empty. Invalid path items are ignored.
+func (s *server) addSpec(url, endpoint string, pathItem []byte) {
+	if len(pathItem) == 0 {
+		return
+	}
+	if err := s.router.AddSpec(url, endpoint, pathItem); err != nil {
+		s.log.Warn("not describing route",
+			zap.String("url", url),
+			zap.String("endpoint", endpoint),
+			zap.Error(err),
+		)
+	}
+}
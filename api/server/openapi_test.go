@@ -0,0 +1,115 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+func TestOpenAPIHandler(t *testing.T) {
+	require := require.New(t)
+
+	s := New().(*server)
+	s.Initialize(logging.NoLog{}, nil, "127.0.0.1", 0, nil, time.Second, ids.EmptyNodeID, false, nil)
+
+	infoSpec := `{"post":{"summary":"Info API"}}`
+	require.NoError(s.AddRoute(&common.HTTPHandler{
+		LockOptions: common.NoLock,
+		Handler:     http.NotFoundHandler(),
+		OpenAPI:     []byte(infoSpec),
+	}, &sync.RWMutex{}, "info", ""))
+
+	cChainID := ids.GenerateTestID()
+	url := fmt.Sprintf("%s/%s", chainBaseURL, cChainID)
+	rpcSpec := `{"summary":"C-chain RPC"}`
+	require.NoError(s.router.AddRouter(url, "/rpc", http.NotFoundHandler()))
+	require.NoError(s.router.AddSpec(url, "/rpc", []byte(rpcSpec)))
+	require.NoError(s.router.AddRouter(url, "/ws", http.NotFoundHandler()))
+	require.NoError(s.AddAliases(fmt.Sprintf("bc/%s", cChainID), "bc/C"))
+
+	// Path items must be JSON objects.
+	require.ErrorIs(s.router.AddSpec(url, "/ws", []byte(`[]`)), errInvalidOpenAPIPathItem)
+
+	handler := s.OpenAPIHandler("v1.0.0")
+	getDocument := func() openAPIDocument {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ext/openapi.json", nil))
+		require.Equal(http.StatusOK, w.Code)
+
+		doc := openAPIDocument{}
+		require.NoError(json.Unmarshal(w.Body.Bytes(), &doc))
+		return doc
+	}
+
+	doc := getDocument()
+	require.Equal(openAPIVersion, doc.OpenAPI)
+	require.Equal("v1.0.0", doc.Info.Version)
+	require.Len(doc.Paths, 5)
+	require.JSONEq(infoSpec, string(doc.Paths["/ext/info"]))
+
+	// The routes of chains are also described under their aliases, and are
+	// summarized if they don't have a summary.
+	require.JSONEq(rpcSpec, string(doc.Paths[url+"/rpc"]))
+	require.JSONEq(rpcSpec, string(doc.Paths["/ext/bc/C/rpc"]))
+	wsSpec := fmt.Sprintf(`{"summary":"API of chain %s"}`, cChainID)
+	require.JSONEq(wsSpec, string(doc.Paths[url+"/ws"]))
+	require.JSONEq(wsSpec, string(doc.Paths["/ext/bc/C/ws"]))
+
+	// Removed routes aren't described.
+	s.router.RemoveRouter(url)
+	doc = getDocument()
+	require.Len(doc.Paths, 1)
+	require.Contains(doc.Paths, "/ext/info")
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/ext/openapi.json", nil))
+	require.Equal(http.StatusMethodNotAllowed, w.Code)
+}
+
+func TestOpenAPIHandlerFiltersListenerPaths(t *testing.T) {
+	require := require.New(t)
+
+	s := newAdminTestServer(t, AdminListenerConfig{
+		Host:  "127.0.0.1",
+		Port:  9652,
+		Paths: DefaultAdminPaths,
+	})
+	require.NoError(s.AddRoute(&common.HTTPHandler{
+		LockOptions: common.NoLock,
+		Handler:     s.OpenAPIHandler("v1.0.0"),
+	}, &sync.RWMutex{}, OpenAPIEndpoint, ""))
+
+	getPaths := func(handler http.Handler) map[string]json.RawMessage {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ext/openapi.json", nil))
+		require.Equal(http.StatusOK, w.Code)
+
+		doc := openAPIDocument{}
+		require.NoError(json.Unmarshal(w.Body.Bytes(), &doc))
+		return doc.Paths
+	}
+
+	// Each listener only describes the paths it serves.
+	publicPaths := getPaths(s.publicHandler())
+	require.Contains(publicPaths, "/ext/info")
+	require.Contains(publicPaths, "/ext/openapi.json")
+	require.NotContains(publicPaths, "/ext/admin")
+
+	adminPaths := getPaths(s.adminHandler())
+	require.Contains(adminPaths, "/ext/admin")
+	require.Contains(adminPaths, "/ext/openapi.json")
+	require.NotContains(adminPaths, "/ext/info")
+}
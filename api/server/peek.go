@@ -0,0 +1,47 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+)
+
+// PeekBody reads the body of [r] without consuming it, so that the handler
+// of [r] still sees the full body. Returns false if the body is larger than
+// [maxSize] bytes or couldn't be read.
+func PeekBody(r *http.Request, maxSize int64) ([]byte, bool) {
+	if r.Body == nil || r.Body == http.NoBody {
+		return nil, true
+	}
+	if r.ContentLength > maxSize {
+		return nil, false
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxSize+1))
+	// Whatever was read must be returned to the request, even on error, so
+	// that the wrapped handler sees the full body.
+	r.Body = struct {
+		io.Reader
+		io.Closer
+	}{
+		Reader: io.MultiReader(bytes.NewReader(body), r.Body),
+		Closer: r.Body,
+	}
+	return body, err == nil && int64(len(body)) <= maxSize
+}
+
+// RPCMethod returns the name of the JSON-RPC method called by the request
+// [body]. Returns false if [body] isn't a single JSON-RPC request.
+func RPCMethod(body []byte) (string, bool) {
+	var request struct {
+		Method string `json:"method"`
+	}
+	if err := json.Unmarshal(body, &request); err != nil || request.Method == "" {
+		return "", false
+	}
+	return request.Method, true
+}
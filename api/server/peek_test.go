@@ -0,0 +1,56 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPeekBody(t *testing.T) {
+	require := require.New(t)
+
+	const body = `{"jsonrpc":"2.0","method":"info.getNodeID","id":1}`
+	r := httptest.NewRequest("POST", "/ext/info", strings.NewReader(body))
+	peeked, ok := PeekBody(r, int64(len(body)))
+	require.True(ok)
+	require.Equal(body, string(peeked))
+
+	method, ok := RPCMethod(peeked)
+	require.True(ok)
+	require.Equal("info.getNodeID", method)
+
+	// The body is still readable by the handler.
+	read, err := io.ReadAll(r.Body)
+	require.NoError(err)
+	require.Equal(body, string(read))
+}
+
+func TestPeekBodyTooLarge(t *testing.T) {
+	require := require.New(t)
+
+	const body = `{"jsonrpc":"2.0","method":"info.getNodeID","id":1}`
+	r := httptest.NewRequest("POST", "/ext/info", io.NopCloser(strings.NewReader(body)))
+	r.ContentLength = -1
+	_, ok := PeekBody(r, int64(len(body)-1))
+	require.False(ok)
+
+	// Whatever was read is returned to the request.
+	read, err := io.ReadAll(r.Body)
+	require.NoError(err)
+	require.Equal(body, string(read))
+}
+
+func TestRPCMethodNotSingleRequest(t *testing.T) {
+	require := require.New(t)
+
+	_, ok := RPCMethod([]byte(`[{"jsonrpc":"2.0","method":"info.getNodeID","id":1}]`))
+	require.False(ok)
+	_, ok = RPCMethod(nil)
+	require.False(ok)
+}
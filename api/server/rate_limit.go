@@ -0,0 +1,254 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"golang.org/x/time/rate"
+
+	"github.com/ava-labs/avalanchego/cache"
+)
+
+// maxRateLimitedClients is the max number of clients whose rate is tracked for
+// each limit. The least recently seen clients are forgotten first.
+const maxRateLimitedClients = 16_384
+
+var (
+	errInvalidRateLimitPath = errors.New("rate limit path must be an absolute path")
+	errInvalidRequestRate   = errors.New("requests per second must be > 0")
+	errInvalidBurst         = errors.New("burst must be > 0")
+)
+
+// RateLimit limits the rate of the requests of each client IP to the APIs
+// under a base path.
+type RateLimit struct {
+	// Base path of the limited APIs, such as "/ext/bc/C/rpc". The requests to
+	// the path and to its subpaths are limited. The limits of a chain's API
+	// apply to the requests made through any of the chain's aliases.
+	Path string `json:"path"`
+	// Number of requests per second a client can make on average. Each
+	// request of a JSON-RPC batch request counts as a request.
+	RequestsPerSecond float64 `json:"requestsPerSecond"`
+	// Max number of requests a client can make at once. Batch requests of more
+	// requests are always rejected.
+	Burst int `json:"burst"`
+}
+
+func (l *RateLimit) Verify() error {
+	switch {
+	case !strings.HasPrefix(l.Path, "/"):
+		return fmt.Errorf("%w: %q", errInvalidRateLimitPath, l.Path)
+	case l.RequestsPerSecond <= 0:
+		return errInvalidRequestRate
+	case l.Burst <= 0:
+		return errInvalidBurst
+	default:
+		return nil
+	}
+}
+
+// rateLimiter tracks the token bucket of each client of an API.
+type rateLimiter struct {
+	limit RateLimit
+
+	lock sync.Mutex
+	// Client IP -> the client's *rate.Limiter
+	clients cache.LRU
+}
+
+// allow returns true if the client [ip] can make [n] requests now. Otherwise,
+// returns the delay after which the client can make them, or 0 if the client
+// can never make [n] requests at once.
+func (l *rateLimiter) allow(ip string, n int) (bool, time.Duration) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	var limiter *rate.Limiter
+	if limiterIntf, ok := l.clients.Get(ip); ok {
+		limiter = limiterIntf.(*rate.Limiter)
+	} else {
+		limiter = rate.NewLimiter(rate.Limit(l.limit.RequestsPerSecond), l.limit.Burst)
+		l.clients.Put(ip, limiter)
+	}
+
+	reservation := limiter.ReserveN(time.Now(), n)
+	if !reservation.OK() {
+		return false, 0
+	}
+	delay := reservation.Delay()
+	if delay == 0 {
+		return true, 0
+	}
+	// The requests are rejected, so they don't consume tokens.
+	reservation.Cancel()
+	return false, delay
+}
+
+// numRequests returns the number of JSON-RPC requests in [r], so that each
+// request of a batch request counts against the rate limit. Requests that
+// aren't batch requests count as a single request.
+func numRequests(r *http.Request) (int, error) {
+	if r.Method != http.MethodPost || r.Body == nil {
+		return 1, nil
+	}
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return 0, err
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	trimmed := bytes.TrimSpace(body)
+	if len(trimmed) == 0 || trimmed[0] != '[' {
+		return 1, nil
+	}
+	var requests []json.RawMessage
+	if err := json.Unmarshal(trimmed, &requests); err != nil || len(requests) == 0 {
+		return 1, nil
+	}
+	return len(requests), nil
+}
+
+func (s *server) LimitRate(namespace string, registerer prometheus.Registerer, limits []RateLimit) error {
+	if len(limits) == 0 {
+		return nil
+	}
+
+	limiters := make([]*rateLimiter, len(limits))
+	for i, limit := range limits {
+		if err := limit.Verify(); err != nil {
+			return err
+		}
+		limiters[i] = &rateLimiter{
+			limit: limit,
+			clients: cache.LRU{
+				Size: maxRateLimitedClients,
+			},
+		}
+	}
+	rejected := prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "rate_limited_requests",
+			Help:      "number of API requests rejected for exceeding the rate limit of their path",
+		},
+		[]string{"path"},
+	)
+	if err := registerer.Register(rejected); err != nil {
+		return err
+	}
+
+	// The rate is limited behind the cors handler, so that the rejections can
+	// be read by browsers.
+	handler := s.routerHandler
+	s.routerHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		limiter := s.rateLimiter(limiters, r.URL.Path)
+		if limiter == nil {
+			handler.ServeHTTP(w, r)
+			return
+		}
+
+		ip, _, err := net.SplitHostPort(r.RemoteAddr)
+		if err != nil {
+			ip = r.RemoteAddr
+		}
+		n, err := numRequests(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if allowed, delay := limiter.allow(ip, n); !allowed {
+			rejected.WithLabelValues(limiter.limit.Path).Inc()
+			if delay > 0 {
+				retryAfter := int(math.Ceil(delay.Seconds()))
+				w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+			}
+			http.Error(w, http.StatusText(http.StatusTooManyRequests), http.StatusTooManyRequests)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	})
+	return nil
+}
+
+// rateLimiter returns the limiter of the longest path of [limiters] that
+// [requestPath] is under, or nil if the request isn't limited.
+func (s *server) rateLimiter(limiters []*rateLimiter, requestPath string) *rateLimiter {
+	paths := make([]string, len(limiters))
+	for i, limiter := range limiters {
+		paths[i] = limiter.limit.Path
+	}
+	i, ok := s.matchPath(paths, requestPath)
+	if !ok {
+		return nil
+	}
+	return limiters[i]
+}
+
+// matchPath returns the index of the longest path of [paths] that
+// [requestPath] is under. Returns false if [requestPath] isn't under any of
+// [paths]. The aliases in [paths] and [requestPath] are resolved, so that a
+// path matches the requests made through any of its aliases.
+func (s *server) matchPath(paths []string, requestPath string) (int, bool) {
+	requestPath = s.resolvePath(path.Clean(requestPath))
+
+	var (
+		matched    int
+		matchedLen = -1
+	)
+	for i, p := range paths {
+		p = s.resolvePath(path.Clean(p))
+		if !hasURLPrefix(requestPath, strings.TrimSuffix(p, "/")) {
+			continue
+		}
+		if len(p) > matchedLen {
+			matched = i
+			matchedLen = len(p)
+		}
+	}
+	return matched, matchedLen >= 0
+}
+
+func (s *server) ResolvePath(urlPath string) string {
+	return s.resolvePath(path.Clean(urlPath))
+}
+
+// resolvePath replaces the longest alias that prefixes [p], such as "/ext/X"
+// or "/ext/bc/X", with the URL it's an alias of. Paths that aren't under an
+// alias are returned unchanged.
+func (s *server) resolvePath(p string) string {
+	s.scopeLock.RLock()
+	defer s.scopeLock.RUnlock()
+
+	// Aliases may be aliases of aliases, so they are resolved until the path
+	// isn't under an alias.
+	visited := make(map[string]bool)
+	for {
+		var matched string
+		for alias := range s.aliasedURLs {
+			if len(alias) > len(matched) && hasURLPrefix(p, alias) {
+				matched = alias
+			}
+		}
+		if matched == "" || visited[matched] {
+			return p
+		}
+		visited[matched] = true
+		p = s.aliasedURLs[matched] + strings.TrimPrefix(p, matched)
+	}
+}
@@ -0,0 +1,163 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+func TestLimitRate(t *testing.T) {
+	require := require.New(t)
+
+	s := New().(*server)
+	s.Initialize(logging.NoLog{}, nil, "127.0.0.1", 0, nil, time.Second, ids.EmptyNodeID, false, nil)
+
+	cChainID := ids.GenerateTestID()
+	url := fmt.Sprintf("%s/%s", chainBaseURL, cChainID)
+	require.NoError(s.router.AddRouter(url, "/rpc", &testHandler{}))
+	require.NoError(s.router.AddRouter(url, "/ws", &testHandler{}))
+	s.registerChainRoute(url, cChainID, ids.Empty)
+	require.NoError(s.AddAliases(fmt.Sprintf("bc/%s", cChainID), "C", "bc/C"))
+	require.NoError(s.router.AddRouter(baseURL+"/info", "", &testHandler{}))
+	s.SetAllowedOrigins([]string{"https://example.com"})
+
+	require.NoError(s.LimitRate("api", prometheus.NewRegistry(), []RateLimit{
+		{
+			Path:              "/ext/bc/C",
+			RequestsPerSecond: 0.001,
+			Burst:             3,
+		},
+		{
+			Path:              "/ext/bc/C/rpc",
+			RequestsPerSecond: 0.001,
+			Burst:             1,
+		},
+	}))
+
+	request := func(path, remoteAddr string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodPost, path, nil)
+		r.RemoteAddr = remoteAddr
+		r.Header.Set("Origin", "https://example.com")
+		w := httptest.NewRecorder()
+		s.handler.ServeHTTP(w, r)
+		return w
+	}
+
+	// The longest matching path applies, including to the requests made
+	// through the chain's ID or its other aliases.
+	require.Equal(http.StatusOK, request("/ext/bc/C/rpc", "10.0.0.1:1000").Code)
+	w := request(fmt.Sprintf("/ext/bc/%s/rpc", cChainID), "10.0.0.1:1001")
+	require.Equal(http.StatusTooManyRequests, w.Code)
+	require.NotEmpty(w.Header().Get("Retry-After"))
+	require.Equal(http.StatusTooManyRequests, request("/ext/C/rpc", "10.0.0.1:1002").Code)
+
+	// Rejections carry the cors headers, so that browsers can read them.
+	require.Equal("https://example.com", w.Header().Get("Access-Control-Allow-Origin"))
+
+	// Other clients have their own limits.
+	require.Equal(http.StatusOK, request("/ext/bc/C/rpc", "10.0.0.2:1000").Code)
+
+	// The shorter path has its own limit.
+	for i := 0; i < 3; i++ {
+		require.Equal(http.StatusOK, request("/ext/bc/C/ws", "10.0.0.1:1000").Code)
+	}
+	require.Equal(http.StatusTooManyRequests, request("/ext/bc/C/ws", "10.0.0.1:1000").Code)
+
+	// Paths without limits aren't limited.
+	for i := 0; i < 5; i++ {
+		require.Equal(http.StatusOK, request("/ext/info", "10.0.0.1:1000").Code)
+	}
+}
+
+func TestLimitRateBatch(t *testing.T) {
+	require := require.New(t)
+
+	s := New().(*server)
+	s.Initialize(logging.NoLog{}, nil, "127.0.0.1", 0, nil, time.Second, ids.EmptyNodeID, false, nil)
+	require.NoError(s.router.AddRouter(baseURL+"/info", "", &testHandler{}))
+	require.NoError(s.LimitRate("api", prometheus.NewRegistry(), []RateLimit{
+		{
+			Path:              "/ext/info",
+			RequestsPerSecond: 0.001,
+			Burst:             3,
+		},
+	}))
+
+	request := func(body string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodPost, "/ext/info", strings.NewReader(body))
+		w := httptest.NewRecorder()
+		s.handler.ServeHTTP(w, r)
+		return w
+	}
+
+	// Batches larger than the burst are always rejected.
+	w := request(`[{"id":1},{"id":2},{"id":3},{"id":4}]`)
+	require.Equal(http.StatusTooManyRequests, w.Code)
+	require.Empty(w.Header().Get("Retry-After"))
+
+	// Each request of a batch counts against the limit.
+	require.Equal(http.StatusOK, request(`[{"id":1},{"id":2}]`).Code)
+	require.Equal(http.StatusTooManyRequests, request(`[{"id":1},{"id":2}]`).Code)
+	require.Equal(http.StatusOK, request(`{"id":1}`).Code)
+	require.Equal(http.StatusTooManyRequests, request(`{"id":1}`).Code)
+}
+
+func TestRateLimitVerify(t *testing.T) {
+	tests := []struct {
+		name        string
+		limit       RateLimit
+		expectedErr error
+	}{
+		{
+			name: "valid",
+			limit: RateLimit{
+				Path:              "/ext/bc/C/rpc",
+				RequestsPerSecond: 10,
+				Burst:             20,
+			},
+		},
+		{
+			name: "relative path",
+			limit: RateLimit{
+				Path:              "ext/bc/C/rpc",
+				RequestsPerSecond: 10,
+				Burst:             20,
+			},
+			expectedErr: errInvalidRateLimitPath,
+		},
+		{
+			name: "no rate",
+			limit: RateLimit{
+				Path:  "/ext/bc/C/rpc",
+				Burst: 20,
+			},
+			expectedErr: errInvalidRequestRate,
+		},
+		{
+			name: "no burst",
+			limit: RateLimit{
+				Path:              "/ext/bc/C/rpc",
+				RequestsPerSecond: 10,
+			},
+			expectedErr: errInvalidBurst,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.ErrorIs(t, test.limit.Verify(), test.expectedErr)
+		})
+	}
+}
@@ -0,0 +1,38 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/snow"
+)
+
+var errChainsNotServing = errors.New("chains not serving API requests")
+
+// isServing returns true if the API endpoints of the chain described by [ctx]
+// serve requests. Otherwise, [rejectMiddleware] rejects the requests.
+func isServing(ctx *snow.ConsensusContext) bool {
+	return ctx.GetState() == snow.NormalOp
+}
+
+func (s *server) HealthCheck(context.Context) (interface{}, error) {
+	s.chainHandlersLock.Lock()
+	defer s.chainHandlersLock.Unlock()
+
+	states := make(map[string]string, len(s.chainContexts))
+	notServing := 0
+	for chainID, ctx := range s.chainContexts {
+		states[chainID.String()] = ctx.GetState().String()
+		if !isServing(ctx) {
+			notServing++
+		}
+	}
+	if notServing > 0 {
+		return states, fmt.Errorf("%w: %d of %d", errChainsNotServing, notServing, len(states))
+	}
+	return states, nil
+}
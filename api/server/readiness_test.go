@@ -0,0 +1,55 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+func TestHealthCheck(t *testing.T) {
+	require := require.New(t)
+
+	s := New().(*server)
+	s.Initialize(logging.NoLog{}, nil, "127.0.0.1", 0, nil, time.Second, ids.EmptyNodeID, false, nil)
+
+	// Without chains, there are no endpoints that reject requests.
+	details, err := s.HealthCheck(context.Background())
+	require.NoError(err)
+	require.Empty(details)
+
+	bootstrapped := snow.DefaultConsensusContextTest()
+	bootstrapped.ChainID = ids.GenerateTestID()
+	bootstrapped.SetState(snow.NormalOp)
+	s.chainContexts[bootstrapped.ChainID] = bootstrapped
+
+	bootstrapping := snow.DefaultConsensusContextTest()
+	bootstrapping.ChainID = ids.GenerateTestID()
+	bootstrapping.SetState(snow.Bootstrapping)
+	s.chainContexts[bootstrapping.ChainID] = bootstrapping
+
+	details, err = s.HealthCheck(context.Background())
+	require.ErrorIs(err, errChainsNotServing)
+	require.Equal(map[string]string{
+		bootstrapped.ChainID.String():  snow.State(snow.NormalOp).String(),
+		bootstrapping.ChainID.String(): snow.State(snow.Bootstrapping).String(),
+	}, details)
+
+	bootstrapping.SetState(snow.NormalOp)
+	_, err = s.HealthCheck(context.Background())
+	require.NoError(err)
+
+	// Deregistered chains aren't checked.
+	bootstrapping.SetState(snow.Bootstrapping)
+	s.DeregisterChain(bootstrapping.ChainID)
+	_, err = s.HealthCheck(context.Background())
+	require.NoError(err)
+}
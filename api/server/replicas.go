@@ -0,0 +1,28 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"net/http"
+)
+
+// Replicas are read-only instances of a chain's VM that serve a share of the
+// requests to the chain's API.
+type Replicas interface {
+	// Route returns the handler of the replica that serves [r], sent to the
+	// chain's route [extension], or false if the chain's VM serves [r].
+	Route(extension string, r *http.Request) (http.Handler, bool)
+}
+
+// replicaMiddleware wraps the handler of a chain's route so that the requests
+// [replicas] pick are served by a replica.
+func replicaMiddleware(handler http.Handler, replicas Replicas, extension string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if replicaHandler, ok := replicas.Route(extension, r); ok {
+			replicaHandler.ServeHTTP(w, r)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	})
+}
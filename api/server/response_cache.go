@@ -0,0 +1,238 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ava-labs/avalanchego/utils/hashing"
+	"github.com/ava-labs/avalanchego/utils/linkedhashmap"
+	"github.com/ava-labs/avalanchego/utils/timer/mockable"
+)
+
+var errInvalidResponseCacheTTL = errors.New("response cache TTL must be > 0")
+
+// ResponseCacheConfig configures the cache of the responses to the JSON-RPC
+// methods that chains mark as cacheable in their handlers.
+type ResponseCacheConfig struct {
+	// Max number of bytes of cached responses. If 0, responses aren't cached.
+	MaxBytes int `json:"maxBytes"`
+	// Duration a response is served from the cache
+	TTL time.Duration `json:"ttl"`
+}
+
+// Enabled returns true if responses are cached.
+func (c *ResponseCacheConfig) Enabled() bool {
+	return c.MaxBytes > 0
+}
+
+func (c *ResponseCacheConfig) Verify() error {
+	if c.Enabled() && c.TTL <= 0 {
+		return errInvalidResponseCacheTTL
+	}
+	return nil
+}
+
+func (s *server) ConfigureResponseCache(config ResponseCacheConfig) error {
+	if err := config.Verify(); err != nil {
+		return err
+	}
+	if config.Enabled() {
+		s.responseCache = newResponseCache(config)
+	}
+	return nil
+}
+
+type jsonRPCRequest struct {
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+	ID     json.RawMessage `json:"id"`
+}
+
+type jsonRPCResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	Result  json.RawMessage `json:"result"`
+	Error   json.RawMessage `json:"error,omitempty"`
+	ID      json.RawMessage `json:"id"`
+}
+
+type cachedResponse struct {
+	result json.RawMessage
+	expiry time.Time
+}
+
+// responseCache is an LRU cache of the results of JSON-RPC requests, bounded
+// by the number of bytes of the results.
+type responseCache struct {
+	config ResponseCacheConfig
+	clock  mockable.Clock
+
+	lock    sync.Mutex
+	entries linkedhashmap.LinkedHashmap[hashing.Hash256, *cachedResponse]
+	bytes   int
+}
+
+func newResponseCache(config ResponseCacheConfig) *responseCache {
+	return &responseCache{
+		config:  config,
+		entries: linkedhashmap.New[hashing.Hash256, *cachedResponse](),
+	}
+}
+
+func (c *responseCache) get(key hashing.Hash256) (json.RawMessage, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	response, ok := c.entries.Get(key)
+	if !ok {
+		return nil, false
+	}
+	if !c.clock.Time().Before(response.expiry) {
+		c.evict(key, response)
+		return nil, false
+	}
+	// Mark the response as the most recently used.
+	c.entries.Put(key, response)
+	return response.result, true
+}
+
+func (c *responseCache) put(key hashing.Hash256, result json.RawMessage) {
+	size := len(key) + len(result)
+	if size > c.config.MaxBytes {
+		return
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if response, ok := c.entries.Get(key); ok {
+		c.evict(key, response)
+	}
+	for c.bytes+size > c.config.MaxBytes {
+		oldestKey, oldest, _ := c.entries.Oldest()
+		c.evict(oldestKey, oldest)
+	}
+	c.entries.Put(key, &cachedResponse{
+		result: result,
+		expiry: c.clock.Time().Add(c.config.TTL),
+	})
+	c.bytes += size
+}
+
+func (c *responseCache) evict(key hashing.Hash256, response *cachedResponse) {
+	c.entries.Delete(key)
+	c.bytes -= len(key) + len(response.result)
+}
+
+// cacheMiddleware wraps the handler of [route]. The successful responses to
+// the JSON-RPC [methods] are cached and served to later requests with the same
+// method and params, regardless of their IDs. Responses marked as
+// "Cache-Control: no-store", such as the responses about processing blocks,
+// aren't cached.
+func (s *server) cacheMiddleware(handler http.Handler, route string, methods []string) http.Handler {
+	if s.responseCache == nil || len(methods) == 0 {
+		return handler
+	}
+	cacheable := make(map[string]struct{}, len(methods))
+	for _, method := range methods {
+		cacheable[method] = struct{}{}
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			handler.ServeHTTP(w, r)
+			return
+		}
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		// Notifications don't have responses to cache.
+		request := jsonRPCRequest{}
+		if err := json.Unmarshal(body, &request); err != nil || len(request.ID) == 0 {
+			handler.ServeHTTP(w, r)
+			return
+		}
+		if _, ok := cacheable[request.Method]; !ok {
+			handler.ServeHTTP(w, r)
+			return
+		}
+		key := responseCacheKey(route, request)
+		if result, ok := s.responseCache.get(key); ok {
+			writeJSONRPCResult(w, result, request.ID)
+			return
+		}
+
+		recorder := &responseBuffer{
+			header:     w.Header(),
+			statusCode: http.StatusOK,
+		}
+		handler.ServeHTTP(recorder, r)
+		if recorder.statusCode != http.StatusOK {
+			w.WriteHeader(recorder.statusCode)
+			_, _ = w.Write(recorder.body.Bytes())
+			return
+		}
+
+		// Errors, such as unknown IDs, may not be returned by later requests,
+		// so they aren't cached.
+		response := jsonRPCResponse{}
+		if err := json.Unmarshal(recorder.body.Bytes(), &response); err == nil && len(response.Result) > 0 && isJSONNull(response.Error) && !isNoStore(recorder.header) {
+			s.responseCache.put(key, response.Result)
+		}
+		_, _ = w.Write(recorder.body.Bytes())
+	})
+}
+
+// responseCacheKey returns the key of the responses to [request] to [route].
+// Insignificant whitespace in the params of the request is ignored.
+func responseCacheKey(route string, request jsonRPCRequest) hashing.Hash256 {
+	params := bytes.Buffer{}
+	if err := json.Compact(&params, request.Params); err != nil {
+		params.Reset()
+		params.Write(request.Params)
+	}
+	key := make([]byte, 0, len(route)+len(request.Method)+params.Len()+2)
+	key = append(key, route...)
+	key = append(key, 0)
+	key = append(key, request.Method...)
+	key = append(key, 0)
+	key = append(key, params.Bytes()...)
+	return hashing.ComputeHash256Array(key)
+}
+
+func writeJSONRPCResult(w http.ResponseWriter, result, id json.RawMessage) {
+	response, err := json.Marshal(jsonRPCResponse{
+		JSONRPC: "2.0",
+		Result:  result,
+		ID:      id,
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, response)
+}
+
+func isNoStore(header http.Header) bool {
+	for _, directive := range strings.Split(header.Get("Cache-Control"), ",") {
+		if strings.TrimSpace(directive) == "no-store" {
+			return true
+		}
+	}
+	return false
+}
+
+func isJSONNull(msg json.RawMessage) bool {
+	return len(msg) == 0 || bytes.Equal(msg, []byte("null"))
+}
@@ -0,0 +1,168 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/utils/hashing"
+)
+
+// countingHandler responds to JSON-RPC requests with the number of requests it
+// handled. Requests to the "test.fail" method get an error, and the responses
+// to the "test.processing" method are marked as uncacheable.
+type countingHandler struct {
+	count int
+}
+
+func (h *countingHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.count++
+
+	request := jsonRPCRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	if request.Method == "test.fail" {
+		writeJSON(w, newJSONRPCError(request.ID, jsonRPCInternalError, "failed"))
+		return
+	}
+	if request.Method == "test.processing" {
+		w.Header().Set("Cache-Control", "no-store")
+	}
+	writeJSONRPCResult(w, json.RawMessage(fmt.Sprint(h.count)), request.ID)
+}
+
+func TestCacheMiddleware(t *testing.T) {
+	require := require.New(t)
+
+	s := New().(*server)
+	require.NoError(s.ConfigureResponseCache(ResponseCacheConfig{
+		MaxBytes: 1024,
+		TTL:      time.Minute,
+	}))
+	now := time.Now()
+	s.responseCache.clock.Set(now)
+
+	handler := &countingHandler{}
+	h := s.cacheMiddleware(handler, "/ext/bc/X", []string{"test.get", "test.fail", "test.processing"})
+
+	request := func(body string) jsonRPCResponse {
+		r := httptest.NewRequest(http.MethodPost, "/ext/bc/X", strings.NewReader(body))
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		require.Equal(http.StatusOK, w.Code)
+
+		response := jsonRPCResponse{}
+		require.NoError(json.Unmarshal(w.Body.Bytes(), &response))
+		return response
+	}
+
+	response := request(`{"jsonrpc":"2.0","method":"test.get","params":[{"id":"a"}],"id":1}`)
+	require.Equal(json.RawMessage("1"), response.Result)
+	require.Equal(json.RawMessage("1"), response.ID)
+
+	// Requests with the same method and params are served from the cache,
+	// with their own ID.
+	response = request(`{"jsonrpc":"2.0","method":"test.get","params":[ {"id": "a"} ],"id":"two"}`)
+	require.Equal(json.RawMessage("1"), response.Result)
+	require.Equal(json.RawMessage(`"two"`), response.ID)
+	require.Equal(1, handler.count)
+
+	// Requests with other params aren't.
+	response = request(`{"jsonrpc":"2.0","method":"test.get","params":[{"id":"b"}],"id":3}`)
+	require.Equal(json.RawMessage("2"), response.Result)
+
+	// Neither are the requests to other methods nor the errors.
+	request(`{"jsonrpc":"2.0","method":"test.other","params":[{"id":"a"}],"id":4}`)
+	request(`{"jsonrpc":"2.0","method":"test.other","params":[{"id":"a"}],"id":5}`)
+	response = request(`{"jsonrpc":"2.0","method":"test.fail","params":[{"id":"a"}],"id":6}`)
+	require.NotEmpty(response.Error)
+	request(`{"jsonrpc":"2.0","method":"test.fail","params":[{"id":"a"}],"id":7}`)
+	require.Equal(6, handler.count)
+
+	// Neither are the responses marked as uncacheable.
+	response = request(`{"jsonrpc":"2.0","method":"test.processing","params":[{"id":"a"}],"id":8}`)
+	require.Equal(json.RawMessage("7"), response.Result)
+	response = request(`{"jsonrpc":"2.0","method":"test.processing","params":[{"id":"a"}],"id":9}`)
+	require.Equal(json.RawMessage("8"), response.Result)
+
+	// Cached responses expire.
+	s.responseCache.clock.Set(now.Add(time.Minute))
+	response = request(`{"jsonrpc":"2.0","method":"test.get","params":[{"id":"a"}],"id":10}`)
+	require.Equal(json.RawMessage("9"), response.Result)
+}
+
+func TestResponseCacheEviction(t *testing.T) {
+	require := require.New(t)
+
+	entrySize := hashing.HashLen + 1
+	cache := newResponseCache(ResponseCacheConfig{
+		MaxBytes: 2 * entrySize,
+		TTL:      time.Minute,
+	})
+	keys := []hashing.Hash256{{1}, {2}, {3}}
+
+	cache.put(keys[0], json.RawMessage("1"))
+	cache.put(keys[1], json.RawMessage("2"))
+	// Mark the first response as the most recently used.
+	_, ok := cache.get(keys[0])
+	require.True(ok)
+
+	// The least recently used response is evicted.
+	cache.put(keys[2], json.RawMessage("3"))
+	_, ok = cache.get(keys[1])
+	require.False(ok)
+	result, ok := cache.get(keys[0])
+	require.True(ok)
+	require.Equal(json.RawMessage("1"), result)
+	require.Equal(2*entrySize, cache.bytes)
+
+	// Responses larger than the cache aren't cached.
+	cache.put(keys[1], make(json.RawMessage, 2*entrySize))
+	_, ok = cache.get(keys[1])
+	require.False(ok)
+}
+
+func TestResponseCacheConfigVerify(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      ResponseCacheConfig
+		expectedErr error
+	}{
+		{
+			name:        "disabled",
+			config:      ResponseCacheConfig{},
+			expectedErr: nil,
+		},
+		{
+			name: "valid",
+			config: ResponseCacheConfig{
+				MaxBytes: 1,
+				TTL:      time.Second,
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "no TTL",
+			config: ResponseCacheConfig{
+				MaxBytes: 1,
+			},
+			expectedErr: errInvalidResponseCacheTTL,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.ErrorIs(t, test.config.Verify(), test.expectedErr)
+		})
+	}
+}
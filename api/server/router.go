@@ -4,14 +4,23 @@
 package server
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
+	"path"
+	"sort"
+	"strings"
 	"sync"
 
 	"github.com/gorilla/mux"
+
+	"github.com/ava-labs/avalanchego/utils/constants"
 )
 
+// chainBaseURL is the prefix of the URLs of every chain's API.
+var chainBaseURL = path.Join(baseURL, constants.ChainAliasPrefix)
+
 var (
 	errUnknownBaseURL  = errors.New("unknown base url")
 	errUnknownEndpoint = errors.New("unknown endpoint")
@@ -25,14 +34,65 @@ type router struct {
 	reservedRoutes map[string]bool                    // Reserves routes so that there can't be alias that conflict
 	aliases        map[string][]string                // Maps a route to a set of reserved routes
 	routes         map[string]map[string]http.Handler // Maps routes to a handler
+	retiredAliases map[string]string                  // Maps a retired alias to the route it used to alias
+	specs          map[string]json.RawMessage         // Maps a URL to the OpenAPI path item of its handler
 }
 
 func newRouter() *router {
-	return &router{
+	r := &router{
 		router:         mux.NewRouter(),
 		reservedRoutes: make(map[string]bool),
 		aliases:        make(map[string][]string),
 		routes:         make(map[string]map[string]http.Handler),
+		retiredAliases: make(map[string]string),
+		specs:          make(map[string]json.RawMessage),
+	}
+	r.router.NotFoundHandler = http.HandlerFunc(r.notFound)
+	return r
+}
+
+// RemoveRouter removes the routes under [base] and under its aliases. The
+// aliases stay reserved, so that the routes added under [base] afterwards are
+// also routed to by its aliases.
+func (r *router) RemoveRouter(base string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.routeLock.Lock()
+	defer r.routeLock.Unlock()
+
+	r.removeRoutes(base)
+
+	// Routes can't be removed from a mux.Router, so the remaining routes are
+	// added to a new one.
+	urls := make([]string, 0, len(r.routes))
+	handlers := make(map[string]http.Handler, len(r.routes))
+	for base, endpoints := range r.routes {
+		for endpoint, handler := range endpoints {
+			url := base + endpoint
+			urls = append(urls, url)
+			handlers[url] = handler
+		}
+	}
+	sort.Strings(urls)
+
+	router := mux.NewRouter()
+	router.NotFoundHandler = http.HandlerFunc(r.notFound)
+	for _, url := range urls {
+		router.Handle(url, handlers[url]).Name(url)
+	}
+	r.router = router
+}
+
+// removeRoutes removes the routes under [base] and under its aliases.
+//
+// Assumes [r.lock] and [r.routeLock] are held.
+func (r *router) removeRoutes(base string) {
+	for endpoint := range r.routes[base] {
+		delete(r.specs, base+endpoint)
+	}
+	delete(r.routes, base)
+	for _, alias := range r.aliases[base] {
+		r.removeRoutes(alias)
 	}
 }
 
@@ -113,8 +173,8 @@ func (r *router) AddAlias(base string, aliases ...string) error {
 	defer r.routeLock.Unlock()
 
 	for _, alias := range aliases {
-		if r.reservedRoutes[alias] {
-			return fmt.Errorf("couldn't alias to %s as that route is either already aliased or already maps to a handler", alias)
+		if _, retired := r.retiredAliases[alias]; retired || r.reservedRoutes[alias] {
+			return fmt.Errorf("couldn't alias to %s as that route is either retired, already aliased, or already maps to a handler", alias)
 		}
 	}
 
@@ -136,3 +196,167 @@ func (r *router) AddAlias(base string, aliases ...string) error {
 	}
 	return err
 }
+
+// AddRetiredAlias redirects requests to any of [aliases] to the current URL of
+// [base]. The current URL of [base] is its first alias under the same parent
+// path, or [base] itself if it has no such alias.
+func (r *router) AddRetiredAlias(base string, aliases ...string) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.routeLock.Lock()
+	defer r.routeLock.Unlock()
+
+	for _, alias := range aliases {
+		if _, exists := r.retiredAliases[alias]; exists || r.reservedRoutes[alias] || r.routes[alias] != nil {
+			return fmt.Errorf("couldn't retire %s as that route is either already retired, aliased, or maps to a handler", alias)
+		}
+	}
+
+	for _, alias := range aliases {
+		r.retiredAliases[alias] = base
+	}
+	return nil
+}
+
+// routingError is the body of responses to requests that don't match any
+// route.
+type routingError struct {
+	Error string `json:"error"`
+	// ChainID and Alias describe the chain whose API was requested, if the
+	// requested URL was under a known chain.
+	ChainID string `json:"chainID,omitempty"`
+	// Alias is the chain's current alias, which is the chain's ID if it
+	// doesn't have any aliases.
+	Alias string `json:"alias,omitempty"`
+}
+
+// notFound handles requests that don't match any route.
+//
+// Assumes [r.lock] is read locked.
+func (r *router) notFound(w http.ResponseWriter, request *http.Request) {
+	url := request.URL.Path
+	if retired, base, ok := r.matchRetiredAlias(url); ok {
+		target := r.currentURL(base) + strings.TrimPrefix(url, retired)
+		if request.URL.RawQuery != "" {
+			target += "?" + request.URL.RawQuery
+		}
+		http.Redirect(w, request, target, http.StatusPermanentRedirect)
+		return
+	}
+
+	reply := routingError{
+		Error: errUnknownBaseURL.Error(),
+	}
+	if base, ok := r.matchBase(url); ok {
+		reply.Error = errUnknownEndpoint.Error()
+		base = r.original(base)
+		if path.Dir(base) == chainBaseURL {
+			reply.ChainID = path.Base(base)
+			reply.Alias = path.Base(r.currentURL(base))
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusNotFound)
+	_ = json.NewEncoder(w).Encode(reply)
+}
+
+// matchRetiredAlias returns the longest retired alias that prefixes [url] and
+// the route it used to alias.
+//
+// Assumes [r.lock] is read locked.
+func (r *router) matchRetiredAlias(url string) (string, string, bool) {
+	var (
+		matched string
+		base    string
+	)
+	for alias, aliasedBase := range r.retiredAliases {
+		if len(alias) > len(matched) && hasURLPrefix(url, alias) {
+			matched = alias
+			base = aliasedBase
+		}
+	}
+	return matched, base, matched != ""
+}
+
+// currentBase returns the current URL of the route that [url] is served under,
+// so that the requests made through any alias of a route share its URL.
+func (r *router) currentBase(url string) (string, bool) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	base, ok := r.matchBase(url)
+	if !ok {
+		return "", false
+	}
+	return r.currentURL(r.original(base)), true
+}
+
+// matchBase returns the longest route that prefixes [url].
+//
+// Assumes [r.lock] is read locked.
+func (r *router) matchBase(url string) (string, bool) {
+	var matched string
+	for base := range r.routes {
+		if len(base) > len(matched) && hasURLPrefix(url, base) {
+			matched = base
+		}
+	}
+	return matched, matched != ""
+}
+
+// original returns the route that [route] is an alias of, or [route] if it
+// isn't an alias.
+//
+// Assumes [r.lock] is read locked.
+func (r *router) original(route string) string {
+	// Routes may be aliases of aliases, so the aliases are followed until a
+	// route that isn't an alias is found.
+	visited := make(map[string]bool)
+	for r.reservedRoutes[route] && !visited[route] {
+		visited[route] = true
+		base, ok := r.aliasOf(route)
+		if !ok {
+			break
+		}
+		route = base
+	}
+	return route
+}
+
+// aliasOf returns the route that [alias] was registered as an alias of.
+//
+// Assumes [r.lock] is read locked.
+func (r *router) aliasOf(alias string) (string, bool) {
+	for base, aliases := range r.aliases {
+		for _, a := range aliases {
+			if a == alias {
+				return base, true
+			}
+		}
+	}
+	return "", false
+}
+
+// currentURL returns the URL that requests to [base] should be sent to, which
+// is the first alias of [base] under the same parent path as [base], such as
+// /ext/bc/X for a chain, or [base] itself if it has no such alias. Aliases
+// under other paths, such as /ext/X, are shorthands rather than the canonical
+// URL.
+//
+// Assumes [r.lock] is read locked.
+func (r *router) currentURL(base string) string {
+	parent := path.Dir(base)
+	for _, alias := range r.aliases[base] {
+		if path.Dir(alias) == parent {
+			return alias
+		}
+	}
+	return base
+}
+
+// hasURLPrefix returns true if [prefix] is [url] or one of [url]'s parent
+// paths.
+func hasURLPrefix(url, prefix string) bool {
+	return url == prefix || strings.HasPrefix(url, prefix+"/")
+}
@@ -4,8 +4,14 @@
 package server
 
 import (
+	"encoding/json"
 	"net/http"
+	"net/http/httptest"
 	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/ids"
 )
 
 type testHandler struct{ called bool }
@@ -75,3 +81,84 @@ func TestBlock(t *testing.T) {
 		t.Fatalf("Permanently locked %s", "1")
 	}
 }
+
+func TestRetiredAliasRedirect(t *testing.T) {
+	require := require.New(t)
+
+	r := newRouter()
+	chainID := ids.GenerateTestID()
+	base := "/ext/bc/" + chainID.String()
+	require.NoError(r.AddRouter(base, "/rpc", &testHandler{}))
+	require.NoError(r.AddAlias(base, "/ext/X", "/ext/bc/X"))
+	require.NoError(r.AddRetiredAlias(base, "/ext/bc/old"))
+
+	// Retired aliases can't be reused, and active routes can't be retired.
+	require.Error(r.AddRetiredAlias(base, "/ext/bc/old"))
+	require.Error(r.AddRetiredAlias(base, "/ext/bc/X"))
+	require.Error(r.AddAlias(base, "/ext/bc/old"))
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/ext/bc/old/rpc?verbose=true", nil))
+	require.Equal(http.StatusPermanentRedirect, w.Code)
+	require.Equal("/ext/bc/X/rpc?verbose=true", w.Header().Get("Location"))
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/ext/bc/oldest/rpc", nil))
+	require.Equal(http.StatusNotFound, w.Code)
+}
+
+func TestRoutingErrors(t *testing.T) {
+	require := require.New(t)
+
+	r := newRouter()
+	chainID := ids.GenerateTestID()
+	base := "/ext/bc/" + chainID.String()
+	require.NoError(r.AddRouter(base, "/rpc", &testHandler{}))
+	require.NoError(r.AddAlias(base, "/ext/X", "/ext/avm", "/ext/bc/X", "/ext/bc/avm"))
+	require.NoError(r.AddRouter("/ext/info", "", &testHandler{}))
+
+	tests := []struct {
+		url      string
+		expected routingError
+	}{
+		{
+			url: "/ext/bc/avm/wallet",
+			expected: routingError{
+				Error:   errUnknownEndpoint.Error(),
+				ChainID: chainID.String(),
+				Alias:   "X",
+			},
+		},
+		{
+			url: base + "/events",
+			expected: routingError{
+				Error:   errUnknownEndpoint.Error(),
+				ChainID: chainID.String(),
+				Alias:   "X",
+			},
+		},
+		{
+			url: "/ext/info/peers",
+			expected: routingError{
+				Error: errUnknownEndpoint.Error(),
+			},
+		},
+		{
+			url: "/ext/bc/Y/rpc",
+			expected: routingError{
+				Error: errUnknownBaseURL.Error(),
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.url, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, test.url, nil))
+			require.Equal(http.StatusNotFound, w.Code)
+
+			var reply routingError
+			require.NoError(json.Unmarshal(w.Body.Bytes(), &reply))
+			require.Equal(test.expected, reply)
+		})
+	}
+}
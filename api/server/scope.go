@@ -0,0 +1,122 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"errors"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+var errEmptyScope = errors.New("scope must include at least one chain or subnet")
+
+// ScopeConfig describes an API listener that only serves the APIs of a set of
+// chains. Every other API, including the APIs of the node, isn't served by the
+// listener.
+type ScopeConfig struct {
+	// Address the listener binds to
+	Host string `json:"host"`
+	Port uint16 `json:"port"`
+
+	// IDs or aliases of the chains whose APIs are served
+	Chains []string `json:"chains"`
+	// IDs of the subnets whose chains' APIs are served
+	Subnets []ids.ID `json:"subnets"`
+}
+
+func (c *ScopeConfig) Verify() error {
+	if len(c.Chains) == 0 && len(c.Subnets) == 0 {
+		return errEmptyScope
+	}
+	return nil
+}
+
+// chainRoute is the chain whose APIs are served under a route.
+type chainRoute struct {
+	chainID  ids.ID
+	subnetID ids.ID
+}
+
+// registerChainRoute records that [url] serves the APIs of the chain of
+// [chainID], which is validated by [subnetID].
+func (s *server) registerChainRoute(url string, chainID, subnetID ids.ID) {
+	s.scopeLock.Lock()
+	defer s.scopeLock.Unlock()
+
+	s.chainRoutes[url] = chainRoute{
+		chainID:  chainID,
+		subnetID: subnetID,
+	}
+}
+
+// registerAliases records that each of [aliases] routes to [url].
+func (s *server) registerAliases(url string, aliases []string) {
+	s.scopeLock.Lock()
+	defer s.scopeLock.Unlock()
+
+	for _, alias := range aliases {
+		s.aliasedURLs[alias] = url
+	}
+}
+
+// resolveURL returns the URL [url] routes to.
+//
+// Assumes [s.scopeLock] is held.
+func (s *server) resolveURL(url string) string {
+	if aliased, ok := s.aliasedURLs[url]; ok {
+		return aliased
+	}
+	return url
+}
+
+// scopedHandler returns a handler that only serves the requests to the APIs
+// of the chains in the scope of [config].
+func (s *server) scopedHandler(config ScopeConfig) http.Handler {
+	chainURLs := make([]string, len(config.Chains))
+	for i, chain := range config.Chains {
+		chainURLs[i] = path.Join(chainBaseURL, chain)
+	}
+	subnetIDs := ids.Set{}
+	subnetIDs.Add(config.Subnets...)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !s.inScope(r.URL.Path, chainURLs, subnetIDs) {
+			http.NotFound(w, r)
+			return
+		}
+		s.handler.ServeHTTP(w, r)
+	})
+}
+
+// inScope returns true if [requestPath] is a route of the API of one of the
+// chains of [chainURLs], or of a chain of one of [subnetIDs].
+func (s *server) inScope(requestPath string, chainURLs []string, subnetIDs ids.Set) bool {
+	requestPath = path.Clean(requestPath)
+	prefix := chainBaseURL + "/"
+	if !strings.HasPrefix(requestPath, prefix) {
+		return false
+	}
+	chain, _, _ := strings.Cut(strings.TrimPrefix(requestPath, prefix), "/")
+
+	s.scopeLock.RLock()
+	defer s.scopeLock.RUnlock()
+
+	url := s.resolveURL(path.Join(chainBaseURL, chain))
+	route, ok := s.chainRoutes[url]
+	if !ok {
+		return false
+	}
+	if subnetIDs.Contains(route.subnetID) {
+		return true
+	}
+	for _, chainURL := range chainURLs {
+		if s.resolveURL(chainURL) == url {
+			return true
+		}
+	}
+	return false
+}
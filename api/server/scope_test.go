@@ -0,0 +1,82 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+func TestScopedHandler(t *testing.T) {
+	require := require.New(t)
+
+	s := New().(*server)
+	s.Initialize(logging.NoLog{}, nil, "127.0.0.1", 0, nil, time.Second, ids.EmptyNodeID, false, nil)
+
+	var (
+		cChainID     = ids.GenerateTestID()
+		subnetChain  = ids.GenerateTestID()
+		otherChainID = ids.GenerateTestID()
+		subnetID     = ids.GenerateTestID()
+	)
+	for _, chain := range []struct {
+		chainID  ids.ID
+		subnetID ids.ID
+	}{
+		{cChainID, ids.Empty},
+		{subnetChain, subnetID},
+		{otherChainID, ids.Empty},
+	} {
+		url := fmt.Sprintf("%s/%s", chainBaseURL, chain.chainID)
+		require.NoError(s.router.AddRouter(url, "/rpc", &testHandler{}))
+		s.registerChainRoute(url, chain.chainID, chain.subnetID)
+	}
+	require.NoError(s.AddAliases(fmt.Sprintf("bc/%s", cChainID), "bc/C"))
+	require.NoError(s.router.AddRouter(baseURL+"/info", "", &testHandler{}))
+
+	handler := s.scopedHandler(ScopeConfig{
+		Chains:  []string{"C"},
+		Subnets: []ids.ID{subnetID},
+	})
+	tests := []struct {
+		path         string
+		expectedCode int
+	}{
+		{"/ext/bc/C/rpc", http.StatusOK},
+		{fmt.Sprintf("/ext/bc/%s/rpc", cChainID), http.StatusOK},
+		{fmt.Sprintf("/ext/bc/%s/rpc", subnetChain), http.StatusOK},
+		{fmt.Sprintf("/ext/bc/%s/rpc", otherChainID), http.StatusNotFound},
+		{"/ext/bc/X/rpc", http.StatusNotFound},
+		{"/ext/info", http.StatusNotFound},
+		{"/ext/bc/C/../../info", http.StatusNotFound},
+	}
+	for _, test := range tests {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, test.path, nil))
+		require.Equal(test.expectedCode, w.Code, test.path)
+	}
+
+	// The unscoped handler serves every route.
+	w := httptest.NewRecorder()
+	s.handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/ext/info", nil))
+	require.Equal(http.StatusOK, w.Code)
+}
+
+func TestScopeConfigVerify(t *testing.T) {
+	require := require.New(t)
+
+	config := ScopeConfig{}
+	require.ErrorIs(config.Verify(), errEmptyScope)
+
+	config.Chains = []string{"C"}
+	require.NoError(config.Verify())
+}
@@ -5,30 +5,35 @@ package server
 
 import (
 	"context"
-	"crypto/tls"
 	"errors"
 	"fmt"
+	"io"
 	"net"
 	"net/http"
 	"net/url"
 	"path"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/NYTimes/gziphandler"
 
-	"github.com/rs/cors"
+	"github.com/prometheus/client_golang/prometheus"
 
 	"go.uber.org/zap"
 
 	"github.com/ava-labs/avalanchego/api"
+	"github.com/ava-labs/avalanchego/api/pagination"
+	"github.com/ava-labs/avalanchego/api/statediff"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/snow"
 	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
 	"github.com/ava-labs/avalanchego/trace"
 	"github.com/ava-labs/avalanchego/utils/constants"
 	"github.com/ava-labs/avalanchego/utils/ips"
 	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
 )
 
 const (
@@ -65,6 +70,26 @@ type PathAdderWithReadLock interface {
 type Server interface {
 	PathAdder
 	PathAdderWithReadLock
+	CORS
+	// AddRetiredAliases permanently redirects requests to any of [aliases] to
+	// the current URL of [endpoint]
+	AddRetiredAliases(endpoint string, aliases ...string) error
+	// ConfigureBatches configures the splitting of JSON-RPC batch requests.
+	// Must be called before Initialize.
+	ConfigureBatches(config BatchConfig) error
+	// ConfigureResponseCache configures the cache of the responses to the
+	// JSON-RPC methods that chains mark as cacheable. Must be called before
+	// the chains are registered.
+	ConfigureResponseCache(config ResponseCacheConfig) error
+	// ConfigureTimeouts bounds the duration of the requests to the APIs of the
+	// chains. Requests to the paths in [timeouts] use the longest matching
+	// timeout, and other requests use [defaultTimeout]. A timeout of 0 disables
+	// timing out requests. Must be called before the chains are registered.
+	ConfigureTimeouts(defaultTimeout time.Duration, timeouts []RouteTimeout) error
+	// ConfigureMaxPageSizes lowers the maximum page sizes of the endpoints the
+	// server adds to the chains' APIs. Must be called before the chains are
+	// registered.
+	ConfigureMaxPageSizes(limits pagination.Limits) error
 	// Initialize creates the API server at the provided host and port
 	Initialize(log logging.Logger,
 		factory logging.Factory,
@@ -77,10 +102,55 @@ type Server interface {
 		tracer trace.Tracer,
 		wrappers ...Wrapper,
 	)
+	// LimitRate limits the rate of the requests of each client to the APIs
+	// under the paths of [limits]. The rejected requests are reported under
+	// [namespace]. Must be called after Initialize and before the server is
+	// dispatched.
+	LimitRate(namespace string, registerer prometheus.Registerer, limits []RateLimit) error
+	// LimitBodySize limits the size of the bodies of the requests to the APIs
+	// under the paths of [limits], and to other APIs to [defaultMaxBytes]. If
+	// [defaultMaxBytes] is 0, the other APIs aren't limited. Must be called
+	// after Initialize and before the server is dispatched.
+	LimitBodySize(defaultMaxBytes int64, limits []BodySizeLimit) error
+	// MeasureRequests reports the number, duration and status of the requests
+	// to each API under [namespace]. Must be called after Initialize and before
+	// the server is dispatched.
+	MeasureRequests(namespace string, registerer prometheus.Registerer) error
+	// OpenAPIHandler returns a handler that serves the OpenAPI document of the
+	// APIs of the server, including the routes of the chains and their
+	// aliases. The document reports [version] as the version of the APIs.
+	OpenAPIHandler(version string) http.Handler
+	// ResolvePath returns [urlPath] with the longest alias it's under, such as
+	// /ext/X, replaced by the URL the alias routes to, such as
+	// /ext/bc/[chainID]. Paths that aren't under an alias are returned
+	// cleaned.
+	ResolvePath(urlPath string) string
+	// ConfigureHTTP2 configures the HTTP/2 support of the API listeners. Must
+	// be called before the server is dispatched.
+	ConfigureHTTP2(config HTTP2Config) error
+	// ConfigureTLS configures client certificate authentication and
+	// certificate reloading of the API listeners that use TLS. Must be called
+	// before the server is dispatched.
+	ConfigureTLS(config TLSConfig) error
 	// Dispatch starts the API server
 	Dispatch() error
 	// DispatchTLS starts the API server with the provided TLS certificate
 	DispatchTLS(certBytes, keyBytes []byte) error
+	// ConfigureAdminListener configures the listener that exclusively serves
+	// the privileged APIs. Must be called before the server is dispatched.
+	ConfigureAdminListener(config AdminListenerConfig) error
+	// DispatchAdmin starts the listener that exclusively serves the privileged
+	// APIs
+	DispatchAdmin() error
+	// DispatchAdminTLS starts the listener that exclusively serves the
+	// privileged APIs with the provided TLS certificate
+	DispatchAdminTLS(certBytes, keyBytes []byte) error
+	// DispatchScoped starts a listener that only serves the APIs of the chains
+	// in the scope of [config]
+	DispatchScoped(config ScopeConfig) error
+	// DispatchScopedTLS starts a listener that only serves the APIs of the
+	// chains in the scope of [config] with the provided TLS certificate
+	DispatchScopedTLS(config ScopeConfig, certBytes, keyBytes []byte) error
 	// RegisterChain registers the API endpoints associated with this chain. That is,
 	// add <route, handler> pairs to server so that API calls can be made to the VM.
 	// This method runs in a goroutine to avoid a deadlock in the event that the caller
@@ -89,6 +159,18 @@ type Server interface {
 	// and at the same time the server's lock is held due to an API call and is trying
 	// to grab the P-Chain's lock.
 	RegisterChain(chainName string, engine common.Engine)
+	// RegisterChainReplicas registers the read replicas of the chain [chainID].
+	// Must be called before the chain is registered.
+	RegisterChainReplicas(chainID ids.ID, replicas Replicas)
+	// HealthCheck reports whether the API endpoints of every registered chain
+	// serve requests, rather than rejecting them because the chain isn't done
+	// bootstrapping. The results map the ID of each chain to its state.
+	HealthCheck(context.Context) (interface{}, error)
+	// DeregisterChain removes the API endpoints of the chain [chainID], which
+	// stopped, and closes the handlers of the endpoints. The aliases of the
+	// chain's endpoints are kept, so that they route to the endpoints of the
+	// chain if it is registered again.
+	DeregisterChain(chainID ids.ID)
 	// Shutdown this server
 	Shutdown() error
 }
@@ -100,6 +182,10 @@ type server struct {
 	factory logging.Factory
 	// points the the router handlers
 	handler http.Handler
+	// handles the requests that passed the cors handler
+	routerHandler http.Handler
+	// *corsState of the current allowed origins
+	cors atomic.Value
 	// Listens for HTTP traffic on this address
 	listenHost string
 	listenPort uint16
@@ -112,12 +198,53 @@ type server struct {
 	// Maps endpoints to handlers
 	router *router
 
-	srv *http.Server
+	// Tracks the chain served under each route, to filter the requests of
+	// scoped listeners
+	scopeLock sync.RWMutex
+	// Chain route URL -> the chain it serves
+	chainRoutes map[string]chainRoute
+	// Alias URL -> the URL it routes to
+	aliasedURLs map[string]string
+
+	replicasLock sync.Mutex
+	// Chain ID -> the chain's read replicas
+	replicas map[ids.ID]Replicas
+
+	chainHandlersLock sync.Mutex
+	// Chain ID -> the handlers of the chain's endpoints, which are closed when
+	// the chain is deregistered
+	chainHandlers map[ids.ID][]http.Handler
+	// Chain ID -> the context of the chain, whose state determines whether the
+	// chain's endpoints serve requests
+	chainContexts map[ids.ID]*snow.ConsensusContext
+
+	http2Config HTTP2Config
+	tlsConfig   TLSConfig
+	adminConfig AdminListenerConfig
+	batchConfig BatchConfig
+
+	responseCache *responseCache
+
+	defaultTimeout time.Duration
+	timeouts       []RouteTimeout
+	timeoutPaths   []string
+
+	maxPageSizes pagination.Limits
+
+	srvLock sync.Mutex
+	srvs    []*http.Server
 }
 
 // New returns an instance of a Server.
 func New() Server {
-	return &server{}
+	return &server{
+		chainRoutes: make(map[string]chainRoute),
+		aliasedURLs: make(map[string]string),
+		replicas:    make(map[ids.ID]Replicas),
+
+		chainHandlers: make(map[ids.ID][]http.Handler),
+		chainContexts: make(map[ids.ID]*snow.ConsensusContext),
+	}
 }
 
 func (s *server) Initialize(
@@ -141,15 +268,16 @@ func (s *server) Initialize(
 	s.tracer = tracer
 	s.router = newRouter()
 
-	s.log.Info("API created",
-		zap.Strings("allowedOrigins", allowedOrigins),
-	)
+	s.log.Info("API created")
 
-	corsHandler := cors.New(cors.Options{
-		AllowedOrigins:   allowedOrigins,
-		AllowCredentials: true,
-	}).Handler(s.router)
-	gzipHandler := gziphandler.GzipHandler(corsHandler)
+	s.routerHandler = s.router
+	if s.batchConfig.Enabled() {
+		s.routerHandler = s.batchHandler(s.routerHandler)
+	}
+	// The allowed origins can be updated, so the cors handler is looked up on
+	// each request.
+	s.SetAllowedOrigins(allowedOrigins)
+	gzipHandler := gziphandler.GzipHandler(http.HandlerFunc(s.serveCORS))
 	s.handler = http.HandlerFunc(
 		func(w http.ResponseWriter, r *http.Request) {
 			// Attach this node's ID as a header
@@ -169,66 +297,122 @@ func (s *server) Dispatch() error {
 	if err != nil {
 		return err
 	}
-
-	ipPort, err := ips.ToIPPort(listener.Addr().String())
-	if err != nil {
-		s.log.Info("HTTP API server listening",
-			zap.String("address", listenAddress),
-		)
-	} else {
-		s.log.Info("HTTP API server listening",
-			zap.String("host", s.listenHost),
-			zap.Uint16("port", ipPort.Port),
-		)
-	}
-
-	s.srv = &http.Server{
-		Handler:           s.handler,
-		ReadHeaderTimeout: readHeaderTimeout,
-	}
-	return s.srv.Serve(listener)
+	return s.serve(listener, s.listenHost, "HTTP API server listening", s.publicHandler(), true)
 }
 
 func (s *server) DispatchTLS(certBytes, keyBytes []byte) error {
 	listenAddress := fmt.Sprintf("%s:%d", s.listenHost, s.listenPort)
-	cert, err := tls.X509KeyPair(certBytes, keyBytes)
+	listener, err := s.listenTLS(listenAddress, certBytes, keyBytes)
 	if err != nil {
 		return err
 	}
-	config := &tls.Config{
-		MinVersion:   tls.VersionTLS12,
-		Certificates: []tls.Certificate{cert},
+	return s.serve(listener, s.listenHost, "HTTPS API server listening", s.publicHandler(), false)
+}
+
+func (s *server) DispatchScoped(config ScopeConfig) error {
+	listenAddress := fmt.Sprintf("%s:%d", config.Host, config.Port)
+	listener, err := net.Listen("tcp", listenAddress)
+	if err != nil {
+		return err
 	}
+	return s.serve(listener, config.Host, "scoped HTTP API server listening", s.scopedHandler(config), true)
+}
 
-	listener, err := tls.Listen("tcp", listenAddress, config)
+func (s *server) DispatchScopedTLS(config ScopeConfig, certBytes, keyBytes []byte) error {
+	listenAddress := fmt.Sprintf("%s:%d", config.Host, config.Port)
+	listener, err := s.listenTLS(listenAddress, certBytes, keyBytes)
 	if err != nil {
 		return err
 	}
+	return s.serve(listener, config.Host, "scoped HTTPS API server listening", s.scopedHandler(config), false)
+}
 
+// serve serves [handler] over [listener] until the server is shutdown.
+// [plaintext] is true if [listener] doesn't use TLS.
+func (s *server) serve(listener net.Listener, host string, msg string, handler http.Handler, plaintext bool) error {
 	ipPort, err := ips.ToIPPort(listener.Addr().String())
 	if err != nil {
-		s.log.Info("HTTPS API server listening",
-			zap.String("address", listenAddress),
+		s.log.Info(msg,
+			zap.String("address", listener.Addr().String()),
 		)
 	} else {
-		s.log.Info("HTTPS API server listening",
-			zap.String("host", s.listenHost),
+		s.log.Info(msg,
+			zap.String("host", host),
 			zap.Uint16("port", ipPort.Port),
 		)
 	}
 
-	s.srv = &http.Server{
-		Addr:              listenAddress,
-		Handler:           s.handler,
+	srv := &http.Server{
+		Handler:           handler,
 		ReadHeaderTimeout: readHeaderTimeout,
 	}
-	return s.srv.Serve(listener)
+	if err := s.configureHTTP2(srv, plaintext); err != nil {
+		_ = listener.Close()
+		return err
+	}
+	s.srvLock.Lock()
+	s.srvs = append(s.srvs, srv)
+	s.srvLock.Unlock()
+	return srv.Serve(listener)
+}
+
+func (s *server) ConfigureMaxPageSizes(limits pagination.Limits) error {
+	if err := limits.Verify(statediff.MaxPageSizeBounds); err != nil {
+		return err
+	}
+	s.maxPageSizes = limits
+	return nil
 }
 
 func (s *server) RegisterChain(chainName string, engine common.Engine) {
 	go s.registerChain(chainName, engine)
 }
 
+func (s *server) RegisterChainReplicas(chainID ids.ID, replicas Replicas) {
+	s.replicasLock.Lock()
+	defer s.replicasLock.Unlock()
+
+	s.replicas[chainID] = replicas
+}
+
+func (s *server) DeregisterChain(chainID ids.ID) {
+	url := path.Join(chainBaseURL, chainID.String())
+	s.log.Info("removing chain routes",
+		zap.Stringer("chainID", chainID),
+		zap.String("url", url),
+	)
+	s.router.RemoveRouter(url)
+
+	s.scopeLock.Lock()
+	delete(s.chainRoutes, url)
+	s.scopeLock.Unlock()
+
+	s.replicasLock.Lock()
+	delete(s.replicas, chainID)
+	s.replicasLock.Unlock()
+
+	s.chainHandlersLock.Lock()
+	handlers := s.chainHandlers[chainID]
+	delete(s.chainHandlers, chainID)
+	delete(s.chainContexts, chainID)
+	s.chainHandlersLock.Unlock()
+
+	// Handlers that talk to a VM over a connection, such as the handlers of
+	// plugins, close their connection.
+	for _, handler := range handlers {
+		closer, ok := handler.(io.Closer)
+		if !ok {
+			continue
+		}
+		if err := closer.Close(); err != nil {
+			s.log.Debug("failed to close chain handler",
+				zap.Stringer("chainID", chainID),
+				zap.Error(err),
+			)
+		}
+	}
+}
+
 func (s *server) registerChain(chainName string, engine common.Engine) {
 	var (
 		handlers map[string]*common.HTTPHandler
@@ -247,11 +431,36 @@ func (s *server) registerChain(chainName string, engine common.Engine) {
 		return
 	}
 
+	// Chains whose VM exports state diffs serve them, unless the VM serves its
+	// own handler on the endpoint
+	if vm, ok := engine.GetVM().(block.StateDiffer); ok {
+		if _, ok := handlers[statediff.Endpoint]; !ok {
+			if handlers == nil {
+				handlers = make(map[string]*common.HTTPHandler, 1)
+			}
+			maxHeights := s.maxPageSizes.MaxPageSize(
+				statediff.GetStateDiffEndpoint,
+				statediff.MaxPageSizeBounds[statediff.GetStateDiffEndpoint],
+			)
+			handlers[statediff.Endpoint] = statediff.NewHandler(s.log, vm, maxHeights)
+		}
+	}
+
 	s.log.Verbo("about to add API endpoints",
 		zap.Stringer("chainID", ctx.ChainID),
 	)
 	// all subroutes to a chain begin with "bc/<the chain's ID>"
 	defaultEndpoint := path.Join(constants.ChainAliasPrefix, ctx.ChainID.String())
+	s.registerChainRoute(fmt.Sprintf("%s/%s", baseURL, defaultEndpoint), ctx.ChainID, ctx.SubnetID)
+
+	s.chainHandlersLock.Lock()
+	for _, handler := range handlers {
+		s.chainHandlers[ctx.ChainID] = append(s.chainHandlers[ctx.ChainID], handler.Handler)
+	}
+	if len(handlers) > 0 {
+		s.chainContexts[ctx.ChainID] = ctx
+	}
+	s.chainHandlersLock.Unlock()
 
 	// Register each endpoint
 	for extension, handler := range handlers {
@@ -281,8 +490,10 @@ func (s *server) addChainRoute(chainName string, handler *common.HTTPHandler, ct
 	)
 	if s.tracingEnabled {
 		handler = &common.HTTPHandler{
-			LockOptions: handler.LockOptions,
-			Handler:     api.TraceHandler(handler.Handler, chainName, s.tracer),
+			LockOptions:   handler.LockOptions,
+			Handler:       api.TraceHandler(handler.Handler, chainName, s.tracer),
+			CachedMethods: handler.CachedMethods,
+			OpenAPI:       handler.OpenAPI,
 		}
 	}
 	// Apply middleware to grab/release chain's lock before/after calling API method
@@ -296,9 +507,25 @@ func (s *server) addChainRoute(chainName string, handler *common.HTTPHandler, ct
 	if err != nil {
 		return err
 	}
+	// Apply middleware to serve read-only calls from the chain's read replicas
+	s.replicasLock.Lock()
+	replicas, ok := s.replicas[ctx.ChainID]
+	s.replicasLock.Unlock()
+	if ok {
+		h = replicaMiddleware(h, replicas, endpoint)
+	}
+	// Apply middleware to serve the responses to cacheable calls from the cache
+	h = s.cacheMiddleware(h, url+endpoint, handler.CachedMethods)
 	// Apply middleware to reject calls to the handler before the chain finishes bootstrapping
 	h = rejectMiddleware(h, ctx)
-	return s.router.AddRouter(url, endpoint, h)
+	// Apply middleware to time out calls that take too long, such as calls
+	// waiting for the chain's lock
+	h = s.timeoutMiddleware(h)
+	if err := s.router.AddRouter(url, endpoint, h); err != nil {
+		return err
+	}
+	s.addSpec(url, endpoint, handler.OpenAPI)
+	return nil
 }
 
 func (s *server) AddRoute(handler *common.HTTPHandler, lock *sync.RWMutex, base, endpoint string) error {
@@ -322,6 +549,7 @@ func (s *server) addRoute(handler *common.HTTPHandler, lock *sync.RWMutex, base,
 		handler = &common.HTTPHandler{
 			LockOptions: handler.LockOptions,
 			Handler:     api.TraceHandler(handler.Handler, url, s.tracer),
+			OpenAPI:     handler.OpenAPI,
 		}
 	}
 
@@ -336,7 +564,11 @@ func (s *server) addRoute(handler *common.HTTPHandler, lock *sync.RWMutex, base,
 	if err != nil {
 		return err
 	}
-	return s.router.AddRouter(url, endpoint, h)
+	if err := s.router.AddRouter(url, endpoint, h); err != nil {
+		return err
+	}
+	s.addSpec(url, endpoint, handler.OpenAPI)
+	return nil
 }
 
 // Wraps a handler by grabbing and releasing a lock before calling the handler.
@@ -383,7 +615,7 @@ func lockMiddleware(
 // not done state-syncing/bootstrapping, writes back an error.
 func rejectMiddleware(handler http.Handler, ctx *snow.ConsensusContext) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { // If chain isn't done bootstrapping, ignore API calls
-		if ctx.GetState() != snow.NormalOp {
+		if !isServing(ctx) {
 			w.WriteHeader(http.StatusServiceUnavailable)
 			// Doesn't matter if there's an error while writing. They'll get the StatusServiceUnavailable code.
 			_, _ = w.Write([]byte("API call rejected because chain is not done bootstrapping"))
@@ -399,6 +631,7 @@ func (s *server) AddAliases(endpoint string, aliases ...string) error {
 	for i, alias := range aliases {
 		endpoints[i] = fmt.Sprintf("%s/%s", baseURL, alias)
 	}
+	s.registerAliases(url, endpoints)
 	return s.router.AddAlias(url, endpoints...)
 }
 
@@ -412,18 +645,30 @@ func (s *server) AddAliasesWithReadLock(endpoint string, aliases ...string) erro
 	return s.AddAliases(endpoint, aliases...)
 }
 
-func (s *server) Shutdown() error {
-	if s.srv == nil {
-		return nil
+func (s *server) AddRetiredAliases(endpoint string, aliases ...string) error {
+	url := fmt.Sprintf("%s/%s", baseURL, endpoint)
+	endpoints := make([]string, len(aliases))
+	for i, alias := range aliases {
+		endpoints[i] = fmt.Sprintf("%s/%s", baseURL, alias)
 	}
+	return s.router.AddRetiredAlias(url, endpoints...)
+}
+
+func (s *server) Shutdown() error {
+	s.srvLock.Lock()
+	defer s.srvLock.Unlock()
 
 	ctx, cancel := context.WithTimeout(context.Background(), s.shutdownTimeout)
-	err := s.srv.Shutdown(ctx)
-	cancel()
+	defer cancel()
 
-	// If shutdown times out, make sure the server is still shutdown.
-	_ = s.srv.Close()
-	return err
+	errs := wrappers.Errs{}
+	for _, srv := range s.srvs {
+		errs.Add(srv.Shutdown(ctx))
+
+		// If shutdown times out, make sure the server is still shutdown.
+		_ = srv.Close()
+	}
+	return errs.Err
 }
 
 type readPathAdder struct {
@@ -0,0 +1,143 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/api/pagination"
+	"github.com/ava-labs/avalanchego/api/statediff"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+type testCloserHandler struct {
+	testHandler
+	closed bool
+}
+
+func (t *testCloserHandler) Close() error {
+	t.closed = true
+	return nil
+}
+
+func TestDeregisterChain(t *testing.T) {
+	require := require.New(t)
+
+	s := New().(*server)
+	s.Initialize(logging.NoLog{}, nil, "127.0.0.1", 0, nil, time.Second, ids.EmptyNodeID, false, nil)
+
+	chainID := ids.GenerateTestID()
+	url := fmt.Sprintf("%s/%s", chainBaseURL, chainID)
+	handler := &testCloserHandler{}
+	require.NoError(s.router.AddRouter(url, "/rpc", handler))
+	s.registerChainRoute(url, chainID, ids.Empty)
+	s.chainHandlers[chainID] = []http.Handler{handler}
+	require.NoError(s.AddAliases(fmt.Sprintf("bc/%s", chainID), "bc/C"))
+	require.NoError(s.router.AddRouter(baseURL+"/info", "", &testHandler{}))
+
+	request := func(path string) int {
+		w := httptest.NewRecorder()
+		s.router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, path, nil))
+		return w.Code
+	}
+	require.Equal(http.StatusOK, request(fmt.Sprintf("/ext/bc/%s/rpc", chainID)))
+	require.Equal(http.StatusOK, request("/ext/bc/C/rpc"))
+
+	s.DeregisterChain(chainID)
+	require.True(handler.closed)
+	require.Equal(http.StatusNotFound, request(fmt.Sprintf("/ext/bc/%s/rpc", chainID)))
+	require.Equal(http.StatusNotFound, request("/ext/bc/C/rpc"))
+	require.Equal(http.StatusOK, request("/ext/info"))
+	require.NotContains(s.chainRoutes, url)
+
+	// The routes of the chain are served under its aliases again after the
+	// chain is registered again.
+	require.NoError(s.router.AddRouter(url, "/rpc", &testHandler{}))
+	require.Equal(http.StatusOK, request(fmt.Sprintf("/ext/bc/%s/rpc", chainID)))
+	require.Equal(http.StatusOK, request("/ext/bc/C/rpc"))
+}
+
+type testStateDifferVM struct {
+	common.TestVM
+	block.TestStateDiffer
+}
+
+func TestRegisterChainStateDiff(t *testing.T) {
+	require := require.New(t)
+
+	s := New().(*server)
+	require.NoError(s.ConfigureMaxPageSizes(pagination.Limits{
+		statediff.GetStateDiffEndpoint: 2,
+	}))
+	s.Initialize(logging.NoLog{}, nil, "127.0.0.1", 0, nil, time.Second, ids.EmptyNodeID, false, nil)
+
+	ctx := snow.DefaultConsensusContextTest()
+	ctx.ChainID = ids.GenerateTestID()
+	ctx.SetState(snow.NormalOp)
+
+	vm := &testStateDifferVM{}
+	vm.CreateHandlersF = func(context.Context) (map[string]*common.HTTPHandler, error) {
+		return nil, nil
+	}
+	vm.GetStateDiffF = func(_ context.Context, startHeight, endHeight uint64, f func([]byte, []byte) error) error {
+		// The diff is exported while holding the chain's read lock.
+		require.False(ctx.Lock.TryLock())
+		require.True(ctx.Lock.TryRLock())
+		ctx.Lock.RUnlock()
+
+		require.EqualValues(1, startHeight)
+		require.EqualValues(3, endHeight)
+		return f([]byte{1}, nil)
+	}
+	s.registerChain("P", &common.EngineTest{
+		ContextF: func() *snow.ConsensusContext {
+			return ctx
+		},
+		GetVMF: func() common.VM {
+			return vm
+		},
+	})
+
+	request := func(query string) int {
+		w := httptest.NewRecorder()
+		url := fmt.Sprintf("/ext/bc/%s%s?%s", ctx.ChainID, statediff.Endpoint, query)
+		s.router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, url, nil))
+		return w.Code
+	}
+	require.Equal(http.StatusOK, request("startHeight=1&endHeight=3"))
+	// The diffs are bounded by the configured max page size.
+	require.Equal(http.StatusBadRequest, request("startHeight=0&endHeight=3"))
+
+	// Chains whose VM doesn't export state diffs don't serve them.
+	otherCtx := snow.DefaultConsensusContextTest()
+	otherCtx.ChainID = ids.GenerateTestID()
+	otherCtx.SetState(snow.NormalOp)
+	s.registerChain("X", &common.EngineTest{
+		ContextF: func() *snow.ConsensusContext {
+			return otherCtx
+		},
+		GetVMF: func() common.VM {
+			return &common.TestVM{
+				CreateHandlersF: func(context.Context) (map[string]*common.HTTPHandler, error) {
+					return nil, nil
+				},
+			}
+		},
+	})
+	w := httptest.NewRecorder()
+	url := fmt.Sprintf("/ext/bc/%s%s?startHeight=1&endHeight=3", otherCtx.ChainID, statediff.Endpoint)
+	s.router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, url, nil))
+	require.Equal(http.StatusNotFound, w.Code)
+}
@@ -0,0 +1,75 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"bufio"
+	"errors"
+	"net"
+	"net/http"
+)
+
+var (
+	ErrNotHijacker = errors.New("response writer doesn't support hijacking")
+
+	_ http.Flusher  = (*StatusRecorder)(nil)
+	_ http.Hijacker = (*StatusRecorder)(nil)
+)
+
+// StatusRecorder records the status code of a response. Flushing and
+// hijacking are passed through, so that streamed responses and websockets are
+// still supported.
+type StatusRecorder struct {
+	http.ResponseWriter
+
+	statusCode  int
+	wroteHeader bool
+}
+
+// NewStatusRecorder returns a recorder of the response written to [w].
+func NewStatusRecorder(w http.ResponseWriter) *StatusRecorder {
+	return &StatusRecorder{
+		ResponseWriter: w,
+		statusCode:     http.StatusOK,
+	}
+}
+
+// StatusCode returns the status code of the response. Hijacked connections
+// are reported as [http.StatusSwitchingProtocols].
+func (r *StatusRecorder) StatusCode() int {
+	return r.statusCode
+}
+
+func (r *StatusRecorder) WriteHeader(statusCode int) {
+	if !r.wroteHeader {
+		r.statusCode = statusCode
+		r.wroteHeader = true
+	}
+	r.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (r *StatusRecorder) Write(b []byte) (int, error) {
+	r.wroteHeader = true
+	return r.ResponseWriter.Write(b)
+}
+
+func (r *StatusRecorder) Flush() {
+	if flusher, ok := r.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (r *StatusRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := r.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, ErrNotHijacker
+	}
+	conn, rw, err := hijacker.Hijack()
+	if err == nil && !r.wroteHeader {
+		// The connection is taken over, such as by a websocket upgrade.
+		r.statusCode = http.StatusSwitchingProtocols
+		r.wroteHeader = true
+	}
+	return conn, rw, err
+}
@@ -0,0 +1,247 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const timeoutMessage = "API call timed out"
+
+var (
+	errInvalidRouteTimeoutPath = errors.New("route timeout path must be an absolute path")
+	errInvalidRouteTimeout     = errors.New("route timeout must be > 0")
+)
+
+// RouteTimeout bounds the duration of the requests to the APIs of the chains
+// under a base path.
+type RouteTimeout struct {
+	// Base path of the APIs, such as "/ext/bc/C/rpc". The requests to the path
+	// and to its subpaths are timed out. The timeouts of a chain's API apply to
+	// the requests made through any of the chain's aliases.
+	Path string
+	// Max duration of a request, including the time spent waiting for the
+	// chain's lock. Encoded in JSON as a duration string, such as "30s".
+	Timeout time.Duration
+}
+
+type routeTimeoutJSON struct {
+	Path    string `json:"path"`
+	Timeout string `json:"timeout"`
+}
+
+func (t RouteTimeout) MarshalJSON() ([]byte, error) {
+	return json.Marshal(routeTimeoutJSON{
+		Path:    t.Path,
+		Timeout: t.Timeout.String(),
+	})
+}
+
+func (t *RouteTimeout) UnmarshalJSON(b []byte) error {
+	var j routeTimeoutJSON
+	if err := json.Unmarshal(b, &j); err != nil {
+		return err
+	}
+	timeout, err := time.ParseDuration(j.Timeout)
+	if err != nil {
+		return fmt.Errorf("invalid timeout of %q: %w", j.Path, err)
+	}
+	t.Path = j.Path
+	t.Timeout = timeout
+	return nil
+}
+
+func (t *RouteTimeout) Verify() error {
+	switch {
+	case !strings.HasPrefix(t.Path, "/"):
+		return fmt.Errorf("%w: %q", errInvalidRouteTimeoutPath, t.Path)
+	case t.Timeout <= 0:
+		return errInvalidRouteTimeout
+	default:
+		return nil
+	}
+}
+
+func (s *server) ConfigureTimeouts(defaultTimeout time.Duration, timeouts []RouteTimeout) error {
+	paths := make([]string, len(timeouts))
+	for i, timeout := range timeouts {
+		if err := timeout.Verify(); err != nil {
+			return err
+		}
+		paths[i] = timeout.Path
+	}
+	s.defaultTimeout = defaultTimeout
+	s.timeouts = timeouts
+	s.timeoutPaths = paths
+	return nil
+}
+
+// timeout returns the max duration of a request to [requestPath], or 0 if the
+// request isn't timed out.
+func (s *server) timeout(requestPath string) time.Duration {
+	if i, ok := s.matchPath(s.timeoutPaths, requestPath); ok {
+		return s.timeouts[i].Timeout
+	}
+	return s.defaultTimeout
+}
+
+// timeoutMiddleware wraps a chain's handler. Requests whose response hasn't
+// started when their timeout expires get a 503 response and their context is
+// cancelled, which stops [lockMiddleware] from calling the handler if the
+// request is still waiting for the chain's lock. Once the handler writes or
+// flushes its response, which is passed through as it's written, the request
+// is no longer timed out, so that streamed responses aren't cut off. WebSocket
+// upgrades are never timed out.
+func (s *server) timeoutMiddleware(handler http.Handler) http.Handler {
+	if s.defaultTimeout <= 0 && len(s.timeouts) == 0 {
+		return handler
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		timeout := s.timeout(r.URL.Path)
+		if timeout <= 0 || r.Header.Get("Upgrade") != "" {
+			handler.ServeHTTP(w, r)
+			return
+		}
+
+		ctx, cancel := context.WithCancel(r.Context())
+		defer cancel()
+
+		tw := &timeoutWriter{
+			w:      w,
+			header: make(http.Header),
+		}
+		done := make(chan struct{})
+		panicked := make(chan interface{}, 1)
+		go func() {
+			defer func() {
+				if p := recover(); p != nil {
+					panicked <- p
+				}
+				tw.finish()
+				close(done)
+			}()
+			handler.ServeHTTP(tw, r.WithContext(ctx))
+		}()
+
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+
+		select {
+		case <-done:
+		case <-timer.C:
+			if tw.timeOut() {
+				cancel()
+				return
+			}
+			// The response started before the timeout expired, so the
+			// handler finishes it.
+			<-done
+		}
+		select {
+		case p := <-panicked:
+			panic(p)
+		default:
+		}
+	})
+}
+
+// timeoutWriter passes the response of a handler through to [w], unless the
+// request timed out before the handler started its response. The handler may
+// still be running once the request timed out, so it writes its headers to its
+// own map, which is copied to [w] when the response starts.
+type timeoutWriter struct {
+	w      http.ResponseWriter
+	header http.Header
+
+	lock sync.Mutex
+	// True once the handler started its response
+	started bool
+	// True once the handler returned
+	finished bool
+	// True if the request timed out before the handler started its response
+	timedOut bool
+}
+
+func (tw *timeoutWriter) Header() http.Header {
+	return tw.header
+}
+
+func (tw *timeoutWriter) WriteHeader(statusCode int) {
+	tw.lock.Lock()
+	defer tw.lock.Unlock()
+
+	tw.start(statusCode)
+}
+
+func (tw *timeoutWriter) Write(b []byte) (int, error) {
+	tw.lock.Lock()
+	defer tw.lock.Unlock()
+
+	if tw.timedOut {
+		return 0, http.ErrHandlerTimeout
+	}
+	tw.start(http.StatusOK)
+	return tw.w.Write(b)
+}
+
+// Flush implements http.Flusher, so that the responses of handlers that stream
+// their response are flushed as they are written.
+func (tw *timeoutWriter) Flush() {
+	tw.lock.Lock()
+	defer tw.lock.Unlock()
+
+	if tw.timedOut {
+		return
+	}
+	tw.start(http.StatusOK)
+	if flusher, ok := tw.w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// start writes the header of the response, if the response hasn't started.
+//
+// Assumes [tw.lock] is held.
+func (tw *timeoutWriter) start(statusCode int) {
+	if tw.timedOut || tw.started {
+		return
+	}
+	tw.started = true
+	header := tw.w.Header()
+	for key, values := range tw.header {
+		header[key] = values
+	}
+	tw.w.WriteHeader(statusCode)
+}
+
+// finish records that the handler returned, and starts the response if the
+// handler didn't write it.
+func (tw *timeoutWriter) finish() {
+	tw.lock.Lock()
+	defer tw.lock.Unlock()
+
+	tw.start(http.StatusOK)
+	tw.finished = true
+}
+
+// timeOut responds to the request with a 503 and returns true, unless the
+// handler already started its response or returned.
+func (tw *timeoutWriter) timeOut() bool {
+	tw.lock.Lock()
+	defer tw.lock.Unlock()
+
+	if tw.started || tw.finished {
+		return false
+	}
+	tw.timedOut = true
+	http.Error(tw.w, timeoutMessage, http.StatusServiceUnavailable)
+	return true
+}
@@ -0,0 +1,172 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+func TestTimeoutMiddleware(t *testing.T) {
+	require := require.New(t)
+
+	s := New().(*server)
+	require.NoError(s.ConfigureTimeouts(time.Hour, []RouteTimeout{
+		{
+			Path:    "/ext/bc/C/rpc",
+			Timeout: 10 * time.Millisecond,
+		},
+	}))
+	s.Initialize(logging.NoLog{}, nil, "127.0.0.1", 0, nil, time.Second, ids.EmptyNodeID, false, nil)
+
+	cChainID := ids.GenerateTestID()
+	url := fmt.Sprintf("%s/%s", chainBaseURL, cChainID)
+	s.registerChainRoute(url, cChainID, ids.Empty)
+	require.NoError(s.AddAliases(fmt.Sprintf("bc/%s", cChainID), "C", "bc/C"))
+
+	var lock sync.RWMutex
+	h, err := lockMiddleware(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}), common.WriteLock, false, nil, &lock)
+	require.NoError(err)
+	h = s.timeoutMiddleware(h)
+
+	request := func(path string, upgrade bool) int {
+		r := httptest.NewRequest(http.MethodPost, path, nil)
+		if upgrade {
+			r.Header.Set("Upgrade", "websocket")
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w.Code
+	}
+
+	require.Equal(http.StatusOK, request("/ext/bc/C/rpc", false))
+
+	// Requests waiting for the chain's lock for longer than their timeout are
+	// rejected, including when they are made through the chain's ID or its
+	// other aliases.
+	lock.Lock()
+	require.Equal(http.StatusServiceUnavailable, request("/ext/bc/C/rpc", false))
+	require.Equal(http.StatusServiceUnavailable, request(fmt.Sprintf("%s/rpc", url), false))
+	require.Equal(http.StatusServiceUnavailable, request("/ext/C/rpc", false))
+	lock.Unlock()
+
+	// WebSocket upgrades aren't timed out.
+	require.Equal(http.StatusOK, request("/ext/bc/C/rpc", true))
+}
+
+func TestTimeoutMiddlewareStreamedResponse(t *testing.T) {
+	require := require.New(t)
+
+	s := New().(*server)
+	require.NoError(s.ConfigureTimeouts(10*time.Millisecond, nil))
+	s.Initialize(logging.NoLog{}, nil, "127.0.0.1", 0, nil, time.Second, ids.EmptyNodeID, false, nil)
+
+	var ctxErr error
+	h := s.timeoutMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		_, _ = w.Write([]byte("a"))
+		w.(http.Flusher).Flush()
+
+		// The response started, so it isn't cut off once the timeout expires.
+		time.Sleep(50 * time.Millisecond)
+		_, _ = w.Write([]byte("b"))
+		ctxErr = r.Context().Err()
+	}))
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/ext/bc/C/rpc", nil))
+	require.Equal(http.StatusOK, w.Code)
+	require.Equal("ab", w.Body.String())
+	require.Equal("text/plain", w.Header().Get("Content-Type"))
+	require.True(w.Flushed)
+	require.NoError(ctxErr)
+}
+
+func TestLockMiddlewareSkipsTimedOutRequests(t *testing.T) {
+	require := require.New(t)
+
+	var (
+		lock   sync.RWMutex
+		called bool
+	)
+	h, err := lockMiddleware(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		called = true
+	}), common.WriteLock, false, nil, &lock)
+	require.NoError(err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	r := httptest.NewRequest(http.MethodPost, "/", nil).WithContext(ctx)
+	h.ServeHTTP(httptest.NewRecorder(), r)
+	require.False(called)
+
+	// The lock is released after the request is skipped.
+	require.True(lock.TryLock())
+}
+
+func TestRouteTimeoutVerify(t *testing.T) {
+	tests := []struct {
+		name        string
+		timeout     RouteTimeout
+		expectedErr error
+	}{
+		{
+			name: "valid",
+			timeout: RouteTimeout{
+				Path:    "/ext/bc/C/rpc",
+				Timeout: time.Second,
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "relative path",
+			timeout: RouteTimeout{
+				Path:    "ext/bc/C/rpc",
+				Timeout: time.Second,
+			},
+			expectedErr: errInvalidRouteTimeoutPath,
+		},
+		{
+			name: "zero timeout",
+			timeout: RouteTimeout{
+				Path: "/ext/bc/C/rpc",
+			},
+			expectedErr: errInvalidRouteTimeout,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.ErrorIs(t, test.timeout.Verify(), test.expectedErr)
+		})
+	}
+}
+
+func TestRouteTimeoutJSON(t *testing.T) {
+	require := require.New(t)
+
+	timeout := RouteTimeout{}
+	require.NoError(json.Unmarshal([]byte(`{"path":"/ext/bc/C/rpc","timeout":"30s"}`), &timeout))
+	require.Equal(RouteTimeout{
+		Path:    "/ext/bc/C/rpc",
+		Timeout: 30 * time.Second,
+	}, timeout)
+
+	timeoutJSON, err := json.Marshal(timeout)
+	require.NoError(err)
+	require.JSONEq(`{"path":"/ext/bc/C/rpc","timeout":"30s"}`, string(timeoutJSON))
+
+	require.Error(json.Unmarshal([]byte(`{"path":"/ext/bc/C/rpc","timeout":30000000000}`), &timeout))
+}
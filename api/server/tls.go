@@ -0,0 +1,190 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/timer/mockable"
+)
+
+var (
+	errInvalidClientCAs           = errors.New("client CAs must contain a PEM encoded certificate")
+	errIncompleteCertFiles        = errors.New("both the certificate and key files must be set to reload them")
+	errInvalidCertReloadFrequency = errors.New("certificate reload frequency must be >= 0")
+)
+
+// TLSConfig configures the API listeners that use TLS.
+type TLSConfig struct {
+	// PEM encoded certificates of the CAs of the clients. If set, clients must
+	// present a certificate signed by one of the CAs.
+	ClientCAs []byte `json:"-"`
+	// Files the certificate and key of the listeners are reloaded from when
+	// they change, so that they can be rotated without restarting the node. If
+	// empty, the certificate the listeners are dispatched with is served until
+	// shutdown.
+	CertFile string `json:"certFile"`
+	KeyFile  string `json:"keyFile"`
+	// Min duration between checks of whether the certificate and key files
+	// changed. If 0, the files aren't reloaded.
+	ReloadFrequency time.Duration `json:"reloadFrequency"`
+}
+
+func (c *TLSConfig) Verify() error {
+	switch {
+	case len(c.ClientCAs) > 0 && !x509.NewCertPool().AppendCertsFromPEM(c.ClientCAs):
+		return errInvalidClientCAs
+	case (c.CertFile == "") != (c.KeyFile == ""):
+		return errIncompleteCertFiles
+	case c.ReloadFrequency < 0:
+		return errInvalidCertReloadFrequency
+	default:
+		return nil
+	}
+}
+
+func (s *server) ConfigureTLS(config TLSConfig) error {
+	if err := config.Verify(); err != nil {
+		return err
+	}
+	s.tlsConfig = config
+	return nil
+}
+
+func (s *server) listenTLS(listenAddress string, certBytes, keyBytes []byte) (net.Listener, error) {
+	config, err := s.newTLSConfig(certBytes, keyBytes)
+	if err != nil {
+		return nil, err
+	}
+	return tls.Listen("tcp", listenAddress, config)
+}
+
+// newTLSConfig returns the config of a listener that serves the key pair
+// [certBytes] and [keyBytes], as configured by the TLSConfig of the server.
+func (s *server) newTLSConfig(certBytes, keyBytes []byte) (*tls.Config, error) {
+	cert, err := tls.X509KeyPair(certBytes, keyBytes)
+	if err != nil {
+		return nil, err
+	}
+	config := &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{cert},
+		NextProtos:   s.nextProtos(),
+	}
+	if s.tlsConfig.CertFile != "" && s.tlsConfig.ReloadFrequency > 0 {
+		reloader := newCertReloader(s.log, s.tlsConfig, &cert)
+		config.Certificates = nil
+		config.GetCertificate = reloader.GetCertificate
+	}
+	if len(s.tlsConfig.ClientCAs) > 0 {
+		config.ClientCAs = x509.NewCertPool()
+		config.ClientCAs.AppendCertsFromPEM(s.tlsConfig.ClientCAs)
+		config.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return config, nil
+}
+
+// certReloader serves the certificate in the certificate and key files of a
+// TLSConfig, which it reloads when the files change.
+type certReloader struct {
+	log       logging.Logger
+	certFile  string
+	keyFile   string
+	frequency time.Duration
+	clock     mockable.Clock
+
+	lock sync.Mutex
+	cert *tls.Certificate
+	// Last time the files were checked for changes
+	lastCheck time.Time
+	// Latest modification time of the files the certificate was loaded from
+	modTime time.Time
+}
+
+func newCertReloader(log logging.Logger, config TLSConfig, cert *tls.Certificate) *certReloader {
+	r := &certReloader{
+		log:       log,
+		certFile:  config.CertFile,
+		keyFile:   config.KeyFile,
+		frequency: config.ReloadFrequency,
+		cert:      cert,
+	}
+	r.lastCheck = r.clock.Time()
+	// If the files can't be read, the certificate is loaded from them once
+	// they can be.
+	r.modTime, _ = r.filesModTime()
+	return r
+}
+
+func (r *certReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	now := r.clock.Time()
+	if now.Sub(r.lastCheck) >= r.frequency {
+		r.lastCheck = now
+		if err := r.reload(); err != nil {
+			// The files may be in the middle of being replaced, so the
+			// current certificate is served until they can be loaded.
+			r.log.Warn("failed to reload the API TLS certificate",
+				zap.String("certFile", r.certFile),
+				zap.String("keyFile", r.keyFile),
+				zap.Error(err),
+			)
+		}
+	}
+	return r.cert, nil
+}
+
+// reload loads the certificate from the files if they changed since it was
+// last loaded.
+//
+// Assumes [r.lock] is held.
+func (r *certReloader) reload() error {
+	modTime, err := r.filesModTime()
+	if err != nil {
+		return err
+	}
+	if !modTime.After(r.modTime) {
+		return nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return err
+	}
+	r.cert = &cert
+	r.modTime = modTime
+	r.log.Info("reloaded the API TLS certificate",
+		zap.String("certFile", r.certFile),
+		zap.String("keyFile", r.keyFile),
+	)
+	return nil
+}
+
+// filesModTime returns the latest modification time of the certificate and
+// key files.
+func (r *certReloader) filesModTime() (time.Time, error) {
+	certInfo, err := os.Stat(r.certFile)
+	if err != nil {
+		return time.Time{}, err
+	}
+	keyInfo, err := os.Stat(r.keyFile)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if keyInfo.ModTime().After(certInfo.ModTime()) {
+		return keyInfo.ModTime(), nil
+	}
+	return certInfo.ModTime(), nil
+}
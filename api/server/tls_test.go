@@ -0,0 +1,173 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"crypto/tls"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/staking"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+func TestTLSConfigVerify(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      TLSConfig
+		expectedErr error
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name: "reloaded files",
+			config: TLSConfig{
+				CertFile:        "cert.pem",
+				KeyFile:         "key.pem",
+				ReloadFrequency: time.Minute,
+			},
+		},
+		{
+			name: "invalid client CAs",
+			config: TLSConfig{
+				ClientCAs: []byte("not a certificate"),
+			},
+			expectedErr: errInvalidClientCAs,
+		},
+		{
+			name: "missing key file",
+			config: TLSConfig{
+				CertFile:        "cert.pem",
+				ReloadFrequency: time.Minute,
+			},
+			expectedErr: errIncompleteCertFiles,
+		},
+		{
+			name: "negative reload frequency",
+			config: TLSConfig{
+				CertFile:        "cert.pem",
+				KeyFile:         "key.pem",
+				ReloadFrequency: -time.Minute,
+			},
+			expectedErr: errInvalidCertReloadFrequency,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.ErrorIs(t, test.config.Verify(), test.expectedErr)
+		})
+	}
+}
+
+func TestCertReloader(t *testing.T) {
+	require := require.New(t)
+
+	dir := t.TempDir()
+	config := TLSConfig{
+		CertFile:        filepath.Join(dir, "cert.pem"),
+		KeyFile:         filepath.Join(dir, "key.pem"),
+		ReloadFrequency: time.Minute,
+	}
+	writeCert := func(modTime time.Time) []byte {
+		certBytes, keyBytes, err := staking.NewCertAndKeyBytes()
+		require.NoError(err)
+		require.NoError(os.WriteFile(config.CertFile, certBytes, 0o600))
+		require.NoError(os.WriteFile(config.KeyFile, keyBytes, 0o600))
+		require.NoError(os.Chtimes(config.CertFile, modTime, modTime))
+		require.NoError(os.Chtimes(config.KeyFile, modTime, modTime))
+		cert, err := tls.X509KeyPair(certBytes, keyBytes)
+		require.NoError(err)
+		return cert.Certificate[0]
+	}
+
+	start := time.Now()
+	firstLeaf := writeCert(start)
+	firstCert, err := tls.LoadX509KeyPair(config.CertFile, config.KeyFile)
+	require.NoError(err)
+
+	reloader := newCertReloader(logging.NoLog{}, config, &firstCert)
+	reloader.clock.Set(start)
+	reloader.lastCheck = start
+
+	cert, err := reloader.GetCertificate(nil)
+	require.NoError(err)
+	require.Equal(firstLeaf, cert.Certificate[0])
+
+	// The files aren't checked again until the reload frequency elapses.
+	secondLeaf := writeCert(start.Add(time.Second))
+	reloader.clock.Set(start.Add(time.Second))
+	cert, err = reloader.GetCertificate(nil)
+	require.NoError(err)
+	require.Equal(firstLeaf, cert.Certificate[0])
+
+	reloader.clock.Set(start.Add(time.Minute))
+	cert, err = reloader.GetCertificate(nil)
+	require.NoError(err)
+	require.Equal(secondLeaf, cert.Certificate[0])
+
+	// The current certificate is served while the files can't be loaded.
+	require.NoError(os.WriteFile(config.KeyFile, []byte("invalid key"), 0o600))
+	modTime := start.Add(2 * time.Second)
+	require.NoError(os.Chtimes(config.KeyFile, modTime, modTime))
+	reloader.clock.Set(start.Add(2 * time.Minute))
+	cert, err = reloader.GetCertificate(nil)
+	require.NoError(err)
+	require.Equal(secondLeaf, cert.Certificate[0])
+}
+
+func TestClientCertificateAuth(t *testing.T) {
+	require := require.New(t)
+
+	serverCert, serverKey, err := staking.NewCertAndKeyBytes()
+	require.NoError(err)
+	clientCert, clientKey, err := staking.NewCertAndKeyBytes()
+	require.NoError(err)
+
+	s := New().(*server)
+	require.NoError(s.ConfigureTLS(TLSConfig{
+		ClientCAs: clientCert,
+	}))
+	s.Initialize(logging.NoLog{}, nil, "127.0.0.1", 0, nil, time.Second, ids.EmptyNodeID, false, nil)
+	require.NoError(s.router.AddRouter(baseURL+"/info", "", &testHandler{}))
+
+	listener, err := s.listenTLS("127.0.0.1:0", serverCert, serverKey)
+	require.NoError(err)
+	go func() {
+		_ = s.serve(listener, "127.0.0.1", "test listener", s.handler, false)
+	}()
+	defer func() {
+		require.NoError(s.Shutdown())
+	}()
+
+	get := func(certificates ...tls.Certificate) error {
+		client := &http.Client{
+			Transport: &http.Transport{
+				TLSClientConfig: &tls.Config{
+					Certificates:       certificates,
+					InsecureSkipVerify: true, // #nosec G402
+				},
+			},
+		}
+		resp, err := client.Get("https://" + listener.Addr().String() + "/ext/info")
+		if err != nil {
+			return err
+		}
+		require.Equal(http.StatusOK, resp.StatusCode)
+		return resp.Body.Close()
+	}
+
+	// Clients without a certificate signed by the CAs are rejected.
+	require.Error(get())
+
+	cert, err := tls.X509KeyPair(clientCert, clientKey)
+	require.NoError(err)
+	require.NoError(get(cert))
+}
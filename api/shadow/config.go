@@ -0,0 +1,61 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package shadow
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+var (
+	errInvalidUpstream      = errors.New("upstream must be an absolute http or https URL")
+	errInvalidPercentage    = errors.New("percentage must be in [0, 100]")
+	errInvalidTimeout       = errors.New("timeout must be positive")
+	errInvalidMaxConcurrent = errors.New("max concurrent must be positive")
+)
+
+type Config struct {
+	// Upstream is the base URL, such as "http://10.0.0.2:9650", that shadowed
+	// requests are sent to. If empty, no requests are shadowed.
+	Upstream string `json:"upstream"`
+
+	// Percentage of the read-only requests, in [0, 100], that are shadowed.
+	Percentage float64 `json:"percentage"`
+
+	// Timeout is the maximum duration to wait for the upstream to respond.
+	Timeout time.Duration `json:"timeout"`
+
+	// MaxConcurrent is the maximum number of shadowed requests that may be
+	// outstanding. Requests that would exceed the limit aren't shadowed.
+	MaxConcurrent int `json:"maxConcurrent"`
+}
+
+// Enabled returns true if requests should be shadowed.
+func (c Config) Enabled() bool {
+	return c.Upstream != ""
+}
+
+func (c Config) Verify() error {
+	if !c.Enabled() {
+		return nil
+	}
+
+	u, err := url.Parse(c.Upstream)
+	switch {
+	case err != nil:
+		return fmt.Errorf("%w: %s", errInvalidUpstream, err)
+	case u.Scheme != "http" && u.Scheme != "https", u.Host == "":
+		return fmt.Errorf("%w: %q", errInvalidUpstream, c.Upstream)
+	case c.Percentage < 0 || c.Percentage > 100:
+		return fmt.Errorf("%w: %f", errInvalidPercentage, c.Percentage)
+	case c.Timeout <= 0:
+		return errInvalidTimeout
+	case c.MaxConcurrent <= 0:
+		return errInvalidMaxConcurrent
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,34 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package shadow
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	resultMatch    = "match"
+	resultMismatch = "mismatch"
+	resultError    = "error"
+	resultDropped  = "dropped"
+)
+
+type metrics struct {
+	// results counts the shadowed requests by their outcome
+	results *prometheus.CounterVec
+}
+
+func newMetrics(namespace string, registerer prometheus.Registerer) (*metrics, error) {
+	m := &metrics{
+		results: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Name:      "requests",
+				Help:      "number of read-only requests that were selected to be shadowed, by the result of comparing the responses",
+			},
+			[]string{"result"},
+		),
+	}
+	return m, registerer.Register(m.results)
+}
@@ -0,0 +1,389 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package shadow duplicates a sample of the node's read-only API requests to a
+// secondary upstream, such as a node running a release candidate, and reports
+// any differences between the two responses.
+package shadow
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"go.uber.org/zap"
+
+	"github.com/ava-labs/avalanchego/api/server"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+const (
+	// maxBodySize is the maximum size of a request or response body that will
+	// be shadowed.
+	maxBodySize = 1024 * 1024
+	// maxLoggedBodySize is the maximum number of bytes of each differing
+	// response that is logged.
+	maxLoggedBodySize = 1024
+)
+
+var (
+	// Routes that handle credentials are never shadowed, even if the requested
+	// method looks read-only.
+	excludedPathPrefixes = []string{
+		"/ext/admin",
+		"/ext/auth",
+		"/ext/keystore",
+	}
+
+	// Prefixes of the part of a JSON-RPC method's name after its namespace,
+	// such as "getBalance" in "avm.getBalance" or "eth_getBalance", that
+	// signal the method doesn't modify any state.
+	readOnlyMethodPrefixes = []string{
+		"get",
+		"is",
+	}
+
+	// Read-only JSON-RPC methods that don't follow the naming convention.
+	readOnlyMethods = map[string]struct{}{
+		"health.health":    {},
+		"health.liveness":  {},
+		"health.readiness": {},
+		"info.peers":       {},
+		"info.uptime":      {},
+		"eth_blockNumber":  {},
+		"eth_call":         {},
+		"eth_chainId":      {},
+		"eth_estimateGas":  {},
+		"eth_gasPrice":     {},
+		"net_version":      {},
+	}
+
+	_ server.Wrapper = (*shadower)(nil)
+)
+
+type shadower struct {
+	log     logging.Logger
+	config  Config
+	client  *http.Client
+	metrics *metrics
+
+	// sample returns true if a read-only request should be shadowed.
+	sample func() bool
+	// outstanding limits the number of concurrently shadowed requests
+	outstanding chan struct{}
+}
+
+// New returns a wrapper that shadows a sample of the read-only requests
+// handled by the wrapped handler to [config.Upstream].
+//
+// The wrapped handler's response is always returned to the client unmodified.
+// The shadowed request is sent after the wrapped handler has responded.
+func New(
+	log logging.Logger,
+	namespace string,
+	registerer prometheus.Registerer,
+	config Config,
+) (server.Wrapper, error) {
+	if err := config.Verify(); err != nil {
+		return nil, err
+	}
+	m, err := newMetrics(namespace, registerer)
+	if err != nil {
+		return nil, err
+	}
+	return &shadower{
+		log:    log,
+		config: config,
+		client: &http.Client{
+			Timeout: config.Timeout,
+		},
+		metrics: m,
+		sample: func() bool {
+			return rand.Float64()*100 < config.Percentage // #nosec G404
+		},
+		outstanding: make(chan struct{}, config.MaxConcurrent),
+	}, nil
+}
+
+func (s *shadower) WrapHandler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !s.sample() || isExcluded(r) {
+			h.ServeHTTP(w, r)
+			return
+		}
+
+		body, ok := server.PeekBody(r, maxBodySize)
+		if !ok {
+			h.ServeHTTP(w, r)
+			return
+		}
+		rpcMethod, ok := readOnlyMethod(r.Method, body)
+		if !ok {
+			h.ServeHTTP(w, r)
+			return
+		}
+
+		select {
+		case s.outstanding <- struct{}{}:
+		default:
+			s.metrics.results.WithLabelValues(resultDropped).Inc()
+			h.ServeHTTP(w, r)
+			return
+		}
+
+		recorder := &responseRecorder{
+			StatusRecorder: server.NewStatusRecorder(w),
+		}
+		h.ServeHTTP(recorder, r)
+		// The headers must be read before returning, as the server may reuse
+		// them once the request is finished.
+		recorder.contentEncoding = w.Header().Get("Content-Encoding")
+
+		req := &shadowRequest{
+			method:      r.Method,
+			requestURI:  r.URL.RequestURI(),
+			contentType: r.Header.Get("Content-Type"),
+			rpcMethod:   rpcMethod,
+			body:        body,
+		}
+		go func() {
+			defer func() {
+				<-s.outstanding
+			}()
+
+			s.shadow(req, recorder)
+		}()
+	})
+}
+
+type shadowRequest struct {
+	method      string
+	requestURI  string
+	contentType string
+	rpcMethod   string
+	body        []byte
+}
+
+// shadow sends [req] to the upstream and compares the upstream's response to
+// the response returned by this node.
+func (s *shadower) shadow(req *shadowRequest, primary *responseRecorder) {
+	if primary.truncated {
+		// The response is too large to be compared, so the request isn't
+		// counted as being shadowed.
+		return
+	}
+	primaryBody, err := primary.decodedBody()
+	if err != nil {
+		s.log.Debug("failed to decode response",
+			zap.String("path", req.requestURI),
+			zap.Error(err),
+		)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.config.Timeout)
+	defer cancel()
+
+	shadowStatus, shadowBody, err := s.send(ctx, req)
+	if err != nil {
+		s.metrics.results.WithLabelValues(resultError).Inc()
+		s.log.Debug("failed to shadow request",
+			zap.String("upstream", s.config.Upstream),
+			zap.String("path", req.requestURI),
+			zap.String("rpcMethod", req.rpcMethod),
+			zap.Error(err),
+		)
+		return
+	}
+
+	if primary.StatusCode() == shadowStatus && bodiesEqual(primaryBody, shadowBody) {
+		s.metrics.results.WithLabelValues(resultMatch).Inc()
+		return
+	}
+
+	s.metrics.results.WithLabelValues(resultMismatch).Inc()
+	s.log.Warn("shadowed response differs",
+		zap.String("upstream", s.config.Upstream),
+		zap.String("method", req.method),
+		zap.String("path", req.requestURI),
+		zap.String("rpcMethod", req.rpcMethod),
+		zap.Int("statusCode", primary.StatusCode()),
+		zap.Int("shadowStatusCode", shadowStatus),
+		zap.String("response", truncate(primaryBody)),
+		zap.String("shadowResponse", truncate(shadowBody)),
+	)
+}
+
+// send issues [req] to the upstream and returns the status code and body of
+// the upstream's response.
+func (s *shadower) send(ctx context.Context, req *shadowRequest) (int, []byte, error) {
+	httpReq, err := http.NewRequestWithContext(
+		ctx,
+		req.method,
+		strings.TrimSuffix(s.config.Upstream, "/")+req.requestURI,
+		bytes.NewReader(req.body),
+	)
+	if err != nil {
+		return 0, nil, err
+	}
+	if req.contentType != "" {
+		httpReq.Header.Set("Content-Type", req.contentType)
+	}
+
+	resp, err := s.client.Do(httpReq)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxBodySize))
+	return resp.StatusCode, body, err
+}
+
+// isExcluded returns true if [r] is never shadowed. Requests to upgrade the
+// connection, such as WebSocket handshakes, are excluded as the upgraded
+// connection may carry requests that modify state.
+func isExcluded(r *http.Request) bool {
+	if r.Header.Get("Upgrade") != "" {
+		return true
+	}
+	for _, prefix := range excludedPathPrefixes {
+		if strings.HasPrefix(r.URL.Path, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsReadOnlyRequest returns true if [r] doesn't modify any state, by the same
+// rules used to pick the requests to shadow. The body of [r] isn't consumed.
+func IsReadOnlyRequest(r *http.Request) bool {
+	if isExcluded(r) {
+		return false
+	}
+	body, ok := server.PeekBody(r, maxBodySize)
+	if !ok {
+		return false
+	}
+	_, ok = readOnlyMethod(r.Method, body)
+	return ok
+}
+
+// readOnlyMethod returns the name of the JSON-RPC method being called, if any,
+// and true if the request doesn't modify any state.
+//
+// Batched JSON-RPC requests are never considered read-only.
+func readOnlyMethod(httpMethod string, body []byte) (string, bool) {
+	switch httpMethod {
+	case http.MethodGet, http.MethodHead:
+		return "", true
+	case http.MethodPost:
+	default:
+		return "", false
+	}
+
+	method, ok := server.RPCMethod(body)
+	if !ok {
+		return "", false
+	}
+	return method, isReadOnlyMethod(method)
+}
+
+func isReadOnlyMethod(method string) bool {
+	if _, ok := readOnlyMethods[method]; ok {
+		return true
+	}
+
+	name := method
+	if i := strings.LastIndexAny(method, "._"); i >= 0 {
+		name = method[i+1:]
+	}
+	for _, prefix := range readOnlyMethodPrefixes {
+		// Require the prefix to be followed by an upper case letter so that
+		// methods such as "issueTx" aren't treated as read-only.
+		if len(name) > len(prefix) &&
+			strings.HasPrefix(name, prefix) &&
+			name[len(prefix)] >= 'A' && name[len(prefix)] <= 'Z' {
+			return true
+		}
+	}
+	return false
+}
+
+// bodiesEqual compares two responses semantically if both are JSON and byte by
+// byte otherwise.
+func bodiesEqual(a, b []byte) bool {
+	var aJSON, bJSON interface{}
+	if json.Unmarshal(a, &aJSON) == nil && json.Unmarshal(b, &bJSON) == nil {
+		return reflect.DeepEqual(aJSON, bJSON)
+	}
+	return bytes.Equal(a, b)
+}
+
+func truncate(b []byte) string {
+	if len(b) > maxLoggedBodySize {
+		b = b[:maxLoggedBodySize]
+	}
+	return string(b)
+}
+
+// responseRecorder forwards a response to the client while keeping a copy of
+// its status code and body.
+type responseRecorder struct {
+	*server.StatusRecorder
+
+	body bytes.Buffer
+	// truncated is true if the body was larger than [maxBodySize]
+	truncated bool
+	// contentEncoding is the encoding applied to the body by the wrapped
+	// handler
+	contentEncoding string
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	if !r.truncated {
+		if r.body.Len()+len(b) > maxBodySize {
+			r.truncated = true
+			r.body.Reset()
+		} else {
+			_, _ = r.body.Write(b)
+		}
+	}
+	return r.StatusRecorder.Write(b)
+}
+
+func (r *responseRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, rw, err := r.StatusRecorder.Hijack()
+	if err == nil {
+		// The response is no longer written through the recorder, so it
+		// can't be compared.
+		r.truncated = true
+		r.body.Reset()
+	}
+	return conn, rw, err
+}
+
+// decodedBody returns the recorded body after removing any content encoding
+// applied by the wrapped handler.
+func (r *responseRecorder) decodedBody() ([]byte, error) {
+	if r.contentEncoding != "gzip" {
+		return r.body.Bytes(), nil
+	}
+
+	reader, err := gzip.NewReader(bytes.NewReader(r.body.Bytes()))
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	return io.ReadAll(io.LimitReader(reader, maxBodySize))
+}
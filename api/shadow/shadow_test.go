@@ -0,0 +1,225 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package shadow
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/NYTimes/gziphandler"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	dto "github.com/prometheus/client_model/go"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+func newTestShadower(t *testing.T, upstream string) *shadower {
+	s, err := New(
+		logging.NoLog{},
+		"",
+		prometheus.NewRegistry(),
+		Config{
+			Upstream:      upstream,
+			Percentage:    100,
+			Timeout:       time.Second,
+			MaxConcurrent: 1,
+		},
+	)
+	require.NoError(t, err)
+	return s.(*shadower)
+}
+
+func numResults(t *testing.T, s *shadower, result string) float64 {
+	metric := &dto.Metric{}
+	require.NoError(t, s.metrics.results.WithLabelValues(result).Write(metric))
+	return metric.GetCounter().GetValue()
+}
+
+func echoHandler(prefix string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		_, _ = w.Write([]byte(prefix + string(body)))
+	})
+}
+
+func TestShadowCompare(t *testing.T) {
+	tests := []struct {
+		name           string
+		upstreamPrefix string
+		expectedResult string
+	}{
+		{
+			name:           "match",
+			upstreamPrefix: "",
+			expectedResult: resultMatch,
+		},
+		{
+			name:           "mismatch",
+			upstreamPrefix: "rc:",
+			expectedResult: resultMismatch,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require := require.New(t)
+
+			upstream := httptest.NewServer(echoHandler(test.upstreamPrefix))
+			defer upstream.Close()
+
+			s := newTestShadower(t, upstream.URL)
+			handler := s.WrapHandler(echoHandler(""))
+
+			const body = `{"jsonrpc":"2.0","id":1,"method":"avm.getBalance","params":{}}`
+			req := httptest.NewRequest(http.MethodPost, "/ext/bc/X", strings.NewReader(body))
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			// The client always receives the primary response.
+			require.Equal(body, w.Body.String())
+			require.Eventually(func() bool {
+				return numResults(t, s, test.expectedResult) == 1
+			}, time.Second, time.Millisecond)
+		})
+	}
+}
+
+func TestShadowGzippedResponse(t *testing.T) {
+	require := require.New(t)
+
+	upstream := httptest.NewServer(echoHandler(""))
+	defer upstream.Close()
+
+	s := newTestShadower(t, upstream.URL)
+	handler := s.WrapHandler(gziphandler.GzipHandler(echoHandler("")))
+
+	body := `{"jsonrpc":"2.0","id":1,"method":"info.getNodeID","params":{},"padding":"` + strings.Repeat("a", 2048) + `"}`
+	req := httptest.NewRequest(http.MethodPost, "/ext/info", strings.NewReader(body))
+	req.Header.Set("Accept-Encoding", "gzip")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	require.Equal("gzip", w.Header().Get("Content-Encoding"))
+	require.Eventually(func() bool {
+		return numResults(t, s, resultMatch) == 1
+	}, time.Second, time.Millisecond)
+}
+
+func TestShadowSkipsWrites(t *testing.T) {
+	require := require.New(t)
+
+	var numUpstreamRequests int64
+	upstream := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		atomic.AddInt64(&numUpstreamRequests, 1)
+	}))
+	defer upstream.Close()
+
+	s := newTestShadower(t, upstream.URL)
+	handler := s.WrapHandler(echoHandler(""))
+
+	requests := []*http.Request{
+		httptest.NewRequest(http.MethodPost, "/ext/bc/X", strings.NewReader(`{"method":"avm.issueTx"}`)),
+		httptest.NewRequest(http.MethodPost, "/ext/bc/X", strings.NewReader(`[{"method":"avm.getBalance"}]`)),
+		httptest.NewRequest(http.MethodPost, "/ext/keystore", strings.NewReader(`{"method":"keystore.getUser"}`)),
+		httptest.NewRequest(http.MethodPut, "/ext/bc/X", strings.NewReader(`{"method":"avm.getBalance"}`)),
+		newUpgradeRequest(),
+	}
+	for _, req := range requests {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+	}
+
+	require.Zero(atomic.LoadInt64(&numUpstreamRequests))
+	require.Zero(numResults(t, s, resultMatch))
+	require.Zero(numResults(t, s, resultMismatch))
+}
+
+func newUpgradeRequest() *http.Request {
+	req := httptest.NewRequest(http.MethodGet, "/ext/bc/C/ws", nil)
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", "websocket")
+	return req
+}
+
+func TestIsReadOnlyRequestUpgrade(t *testing.T) {
+	require.False(t, IsReadOnlyRequest(newUpgradeRequest()))
+}
+
+func TestShadowHijack(t *testing.T) {
+	require := require.New(t)
+
+	var numUpstreamRequests int64
+	upstream := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		atomic.AddInt64(&numUpstreamRequests, 1)
+	}))
+	defer upstream.Close()
+
+	s := newTestShadower(t, upstream.URL)
+	server := httptest.NewServer(s.WrapHandler(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		conn, rw, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		defer conn.Close()
+
+		_, _ = rw.WriteString("HTTP/1.1 200 OK\r\nContent-Length: 8\r\nConnection: close\r\n\r\nhijacked")
+		_ = rw.Flush()
+	})))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/ext/bc/X")
+	require.NoError(err)
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(err)
+	require.Equal("hijacked", string(body))
+
+	// The hijacked response can't be compared, so it isn't shadowed.
+	require.Zero(atomic.LoadInt64(&numUpstreamRequests))
+}
+
+func TestIsReadOnlyMethod(t *testing.T) {
+	require := require.New(t)
+
+	require.True(isReadOnlyMethod("avm.getBalance"))
+	require.True(isReadOnlyMethod("info.isBootstrapped"))
+	require.True(isReadOnlyMethod("eth_getBalance"))
+	require.True(isReadOnlyMethod("health.health"))
+	require.False(isReadOnlyMethod("avm.issueTx"))
+	require.False(isReadOnlyMethod("platform.addValidator"))
+	require.False(isReadOnlyMethod("eth_sendRawTransaction"))
+	require.False(isReadOnlyMethod("avm.get"))
+}
+
+func TestConfigVerify(t *testing.T) {
+	require := require.New(t)
+
+	config := Config{}
+	require.NoError(config.Verify())
+
+	config = Config{
+		Upstream:      "http://127.0.0.1:9650",
+		Percentage:    10,
+		Timeout:       time.Second,
+		MaxConcurrent: 1,
+	}
+	require.NoError(config.Verify())
+
+	config.Percentage = 101
+	require.ErrorIs(config.Verify(), errInvalidPercentage)
+
+	config.Percentage = 10
+	config.Upstream = "127.0.0.1:9650"
+	require.ErrorIs(config.Verify(), errInvalidUpstream)
+}
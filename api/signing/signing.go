@@ -0,0 +1,362 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package signing signs the responses to designated API methods with the BLS
+// key of the node. A light client that keeps a signed response can prove which
+// node served it, and at which P-chain height, which holds RPC providers
+// accountable for the responses they serve.
+package signing
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"go.uber.org/zap"
+
+	"github.com/ava-labs/avalanchego/api/server"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/snow/validators"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/crypto/bls"
+	"github.com/ava-labs/avalanchego/utils/formatting"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/timer/mockable"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+)
+
+const (
+	// Headers of a signed response
+	SignatureHeader    = "Avalanche-Response-Signature"
+	NodeIDHeader       = "Avalanche-Response-Node-Id"
+	PublicKeyHeader    = "Avalanche-Response-Public-Key"
+	PChainHeightHeader = "Avalanche-Response-P-Chain-Height"
+	TimestampHeader    = "Avalanche-Response-Timestamp"
+
+	// maxRequestSize is the maximum size of a request body whose response
+	// will be signed.
+	maxRequestSize = 1024 * 1024
+
+	// digestPrefix separates signed responses from any other message signed
+	// by the BLS key of the node.
+	digestPrefix = "avalanche signed api response"
+)
+
+var (
+	// DefaultMethods are the JSON-RPC methods whose responses are signed by
+	// default.
+	DefaultMethods = []string{
+		"platform.getBlock",
+		"platform.getTx",
+		"platform.getHeight",
+		"platform.getCurrentValidators",
+		"platform.getValidatorsAt",
+		"avm.getTx",
+		"eth_getBlockByHash",
+		"eth_getBlockByNumber",
+		"eth_getTransactionByHash",
+		"eth_getTransactionReceipt",
+	}
+
+	errMissingHeader    = errors.New("missing header")
+	errInvalidSignature = errors.New("invalid signature")
+
+	_ Signer = (*signer)(nil)
+)
+
+// Signer signs the responses to the designated JSON-RPC methods served by the
+// wrapped handlers.
+//
+// The signed digest commits to the P-chain height, so the signer must be
+// registered with the chain manager to learn the validator state of the
+// Primary Network.
+type Signer interface {
+	server.Wrapper
+
+	RegisterChain(name string, engine common.Engine)
+}
+
+type signer struct {
+	log       logging.Logger
+	networkID uint32
+	nodeID    ids.NodeID
+	sk        *bls.SecretKey
+	pkBytes   []byte
+	methods   map[string]struct{}
+	clock     mockable.Clock
+
+	stateLock sync.RWMutex
+	// state is the validator state used to report the P-chain height. Nil
+	// until a chain with a validator state has been registered.
+	state validators.State
+}
+
+// New returns a signer that signs the responses to [methods] with [sk].
+func New(
+	log logging.Logger,
+	networkID uint32,
+	nodeID ids.NodeID,
+	sk *bls.SecretKey,
+	methods []string,
+) Signer {
+	s := &signer{
+		log:       log,
+		networkID: networkID,
+		nodeID:    nodeID,
+		sk:        sk,
+		pkBytes:   bls.PublicKeyToBytes(bls.PublicFromSecretKey(sk)),
+		methods:   make(map[string]struct{}, len(methods)),
+	}
+	for _, method := range methods {
+		s.methods[method] = struct{}{}
+	}
+	return s
+}
+
+func (s *signer) RegisterChain(_ string, engine common.Engine) {
+	ctx := engine.Context()
+	// The validator state given to the P-chain isn't locked, so it can't be
+	// used outside of the P-chain. Every other chain is given a locked view
+	// of the same state.
+	if ctx.ChainID == constants.PlatformChainID || ctx.ValidatorState == nil {
+		return
+	}
+
+	s.stateLock.Lock()
+	defer s.stateLock.Unlock()
+
+	if s.state == nil {
+		s.state = ctx.ValidatorState
+	}
+}
+
+func (s *signer) currentHeight(ctx context.Context) (uint64, bool) {
+	s.stateLock.RLock()
+	state := s.state
+	s.stateLock.RUnlock()
+
+	if state == nil {
+		return 0, false
+	}
+	height, err := state.GetCurrentHeight(ctx)
+	if err != nil {
+		s.log.Debug("failed to get the P-chain height",
+			zap.Error(err),
+		)
+		return 0, false
+	}
+	return height, true
+}
+
+func (s *signer) WrapHandler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			h.ServeHTTP(w, r)
+			return
+		}
+		body, ok := server.PeekBody(r, maxRequestSize)
+		if !ok || !s.signed(body) {
+			h.ServeHTTP(w, r)
+			return
+		}
+		height, ok := s.currentHeight(r.Context())
+		if !ok {
+			// Without the P-chain height, the signature couldn't be checked
+			// against the validator set, so the response is left unsigned.
+			h.ServeHTTP(w, r)
+			return
+		}
+
+		// The signature covers the bytes sent to the client, which must not
+		// be compressed so that the client can verify them as received.
+		r.Header.Del("Accept-Encoding")
+		recorder := &responseRecorder{
+			header:     w.Header(),
+			statusCode: http.StatusOK,
+		}
+		h.ServeHTTP(recorder, r)
+
+		timestamp := s.clock.Unix()
+		digest := Digest(s.networkID, r.URL.Path, height, timestamp, body, recorder.body.Bytes())
+		sig := bls.Sign(s.sk, digest[:])
+		if err := setSignatureHeaders(w.Header(), s.nodeID, s.pkBytes, sig, height, timestamp); err != nil {
+			s.log.Debug("failed to sign response",
+				zap.String("path", r.URL.Path),
+				zap.Error(err),
+			)
+		}
+
+		w.WriteHeader(recorder.statusCode)
+		_, _ = w.Write(recorder.body.Bytes())
+	})
+}
+
+// signed returns true if [body] is a single JSON-RPC request to one of the
+// designated methods.
+func (s *signer) signed(body []byte) bool {
+	method, ok := server.RPCMethod(body)
+	if !ok {
+		return false
+	}
+	_, ok = s.methods[method]
+	return ok
+}
+
+// Digest returns the digest signed for the response [responseBody] to the
+// request [requestBody] sent to [path], served by a node of [networkID] at
+// unix time [timestamp] when the P-chain was at [pChainHeight].
+func Digest(
+	networkID uint32,
+	path string,
+	pChainHeight uint64,
+	timestamp uint64,
+	requestBody []byte,
+	responseBody []byte,
+) [sha256.Size]byte {
+	requestHash := sha256.Sum256(requestBody)
+	responseHash := sha256.Sum256(responseBody)
+
+	p := wrappers.Packer{
+		MaxSize: len(digestPrefix) + len(path) + 2*sha256.Size + 64,
+	}
+	p.PackFixedBytes([]byte(digestPrefix))
+	p.PackInt(networkID)
+	p.PackBytes([]byte(path))
+	p.PackLong(pChainHeight)
+	p.PackLong(timestamp)
+	p.PackFixedBytes(requestHash[:])
+	p.PackFixedBytes(responseHash[:])
+	return sha256.Sum256(p.Bytes)
+}
+
+func setSignatureHeaders(
+	header http.Header,
+	nodeID ids.NodeID,
+	pkBytes []byte,
+	sig *bls.Signature,
+	height uint64,
+	timestamp uint64,
+) error {
+	sigStr, err := formatting.Encode(formatting.Hex, bls.SignatureToBytes(sig))
+	if err != nil {
+		return err
+	}
+	pkStr, err := formatting.Encode(formatting.Hex, pkBytes)
+	if err != nil {
+		return err
+	}
+	header.Set(SignatureHeader, sigStr)
+	header.Set(NodeIDHeader, nodeID.String())
+	header.Set(PublicKeyHeader, pkStr)
+	header.Set(PChainHeightHeader, strconv.FormatUint(height, 10))
+	header.Set(TimestampHeader, strconv.FormatUint(timestamp, 10))
+	return nil
+}
+
+// Response describes who signed a response.
+type Response struct {
+	NodeID       ids.NodeID
+	PublicKey    *bls.PublicKey
+	PChainHeight uint64
+	Timestamp    uint64
+}
+
+// Verify checks the signature in [header] of the response [responseBody] to
+// the request [requestBody] sent to [path] on a node of [networkID].
+//
+// Verify only checks that the response was signed by the returned public key.
+// To hold the node accountable, the caller must also check that the public key
+// was registered for the returned node ID as of the returned P-chain height.
+func Verify(
+	networkID uint32,
+	path string,
+	header http.Header,
+	requestBody []byte,
+	responseBody []byte,
+) (*Response, error) {
+	values := make(map[string]string, 5)
+	for _, key := range []string{
+		SignatureHeader,
+		NodeIDHeader,
+		PublicKeyHeader,
+		PChainHeightHeader,
+		TimestampHeader,
+	} {
+		value := header.Get(key)
+		if value == "" {
+			return nil, fmt.Errorf("%w: %s", errMissingHeader, key)
+		}
+		values[key] = value
+	}
+
+	sigBytes, err := formatting.Decode(formatting.Hex, values[SignatureHeader])
+	if err != nil {
+		return nil, fmt.Errorf("couldn't decode %s: %w", SignatureHeader, err)
+	}
+	sig, err := bls.SignatureFromBytes(sigBytes)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't parse %s: %w", SignatureHeader, err)
+	}
+	nodeID, err := ids.NodeIDFromString(values[NodeIDHeader])
+	if err != nil {
+		return nil, fmt.Errorf("couldn't parse %s: %w", NodeIDHeader, err)
+	}
+	pkBytes, err := formatting.Decode(formatting.Hex, values[PublicKeyHeader])
+	if err != nil {
+		return nil, fmt.Errorf("couldn't decode %s: %w", PublicKeyHeader, err)
+	}
+	pk, err := bls.PublicKeyFromBytes(pkBytes)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't parse %s: %w", PublicKeyHeader, err)
+	}
+	height, err := strconv.ParseUint(values[PChainHeightHeader], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't parse %s: %w", PChainHeightHeader, err)
+	}
+	timestamp, err := strconv.ParseUint(values[TimestampHeader], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't parse %s: %w", TimestampHeader, err)
+	}
+
+	digest := Digest(networkID, path, height, timestamp, requestBody, responseBody)
+	if !bls.Verify(pk, sig, digest[:]) {
+		return nil, errInvalidSignature
+	}
+	return &Response{
+		NodeID:       nodeID,
+		PublicKey:    pk,
+		PChainHeight: height,
+		Timestamp:    timestamp,
+	}, nil
+}
+
+// responseRecorder buffers a response so that it can be signed before being
+// sent to the client.
+type responseRecorder struct {
+	header      http.Header
+	statusCode  int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func (r *responseRecorder) Header() http.Header {
+	return r.header
+}
+
+func (r *responseRecorder) WriteHeader(statusCode int) {
+	if !r.wroteHeader {
+		r.statusCode = statusCode
+		r.wroteHeader = true
+	}
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	r.wroteHeader = true
+	return r.body.Write(b)
+}
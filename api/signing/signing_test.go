@@ -0,0 +1,176 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package signing
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/snow/validators"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/crypto/bls"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+const (
+	testNetworkID = 12345
+	testHeight    = 42
+	testPath      = "/ext/bc/P"
+	testResponse  = `{"jsonrpc":"2.0","result":{"height":"42"},"id":1}`
+)
+
+func newTestEngine(chainID ids.ID, state validators.State) common.Engine {
+	ctx := snow.DefaultConsensusContextTest()
+	ctx.ChainID = chainID
+	ctx.ValidatorState = state
+	return &common.EngineTest{
+		ContextF: func() *snow.ConsensusContext {
+			return ctx
+		},
+	}
+}
+
+func newTestSigner(t *testing.T) (*signer, *bls.SecretKey) {
+	sk, err := bls.NewSecretKey()
+	require.NoError(t, err)
+	s := New(
+		logging.NoLog{},
+		testNetworkID,
+		ids.GenerateTestNodeID(),
+		sk,
+		[]string{"platform.getHeight"},
+	)
+	return s.(*signer), sk
+}
+
+// serve sends [body] to the handler wrapped by [s] and returns the response,
+// along with the encodings accepted by the request seen by the wrapped
+// handler.
+func serve(t *testing.T, s *signer, body string) (*httptest.ResponseRecorder, string) {
+	var acceptEncoding string
+	handler := s.WrapHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The wrapped handler must see the whole request.
+		received, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		require.Equal(t, body, string(received))
+		acceptEncoding = r.Header.Get("Accept-Encoding")
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(testResponse))
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, testPath, strings.NewReader(body))
+	req.Header.Set("Accept-Encoding", "gzip")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	return w, acceptEncoding
+}
+
+func TestSignResponse(t *testing.T) {
+	require := require.New(t)
+
+	s, sk := newTestSigner(t)
+	now := time.Unix(1_000_000, 0)
+	s.clock.Set(now)
+	s.RegisterChain("C", newTestEngine(ids.GenerateTestID(), &validators.TestState{
+		GetCurrentHeightF: func(context.Context) (uint64, error) {
+			return testHeight, nil
+		},
+	}))
+
+	body := `{"jsonrpc":"2.0","method":"platform.getHeight","params":{},"id":1}`
+	w, acceptEncoding := serve(t, s, body)
+	require.Equal(http.StatusOK, w.Code)
+	require.Empty(acceptEncoding)
+	require.Equal("application/json", w.Header().Get("Content-Type"))
+	require.Equal(testResponse, w.Body.String())
+
+	resp, err := Verify(testNetworkID, testPath, w.Header(), []byte(body), w.Body.Bytes())
+	require.NoError(err)
+	require.Equal(s.nodeID, resp.NodeID)
+	require.Equal(bls.PublicKeyToBytes(bls.PublicFromSecretKey(sk)), bls.PublicKeyToBytes(resp.PublicKey))
+	require.EqualValues(testHeight, resp.PChainHeight)
+	require.EqualValues(now.Unix(), resp.Timestamp)
+
+	// The signature doesn't cover a different response, request, path or
+	// network.
+	_, err = Verify(testNetworkID, testPath, w.Header(), []byte(body), []byte(`{}`))
+	require.ErrorIs(err, errInvalidSignature)
+	_, err = Verify(testNetworkID, testPath, w.Header(), []byte(`{}`), w.Body.Bytes())
+	require.ErrorIs(err, errInvalidSignature)
+	_, err = Verify(testNetworkID, "/ext/bc/X", w.Header(), []byte(body), w.Body.Bytes())
+	require.ErrorIs(err, errInvalidSignature)
+	_, err = Verify(constants.MainnetID, testPath, w.Header(), []byte(body), w.Body.Bytes())
+	require.ErrorIs(err, errInvalidSignature)
+
+	// Nor a different height.
+	header := w.Header().Clone()
+	header.Set(PChainHeightHeader, "43")
+	_, err = Verify(testNetworkID, testPath, header, []byte(body), w.Body.Bytes())
+	require.ErrorIs(err, errInvalidSignature)
+
+	header.Del(PChainHeightHeader)
+	_, err = Verify(testNetworkID, testPath, header, []byte(body), w.Body.Bytes())
+	require.ErrorIs(err, errMissingHeader)
+}
+
+func TestResponseNotSigned(t *testing.T) {
+	s, _ := newTestSigner(t)
+
+	tests := []struct {
+		name     string
+		register func()
+		body     string
+	}{
+		{
+			name:     "no validator state",
+			register: func() {},
+			body:     `{"jsonrpc":"2.0","method":"platform.getHeight","params":{},"id":1}`,
+		},
+		{
+			name: "platform chain",
+			register: func() {
+				s.RegisterChain("P", newTestEngine(constants.PlatformChainID, &validators.TestState{}))
+			},
+			body: `{"jsonrpc":"2.0","method":"platform.getHeight","params":{},"id":1}`,
+		},
+		{
+			name: "method not designated",
+			register: func() {
+				s.RegisterChain("X", newTestEngine(ids.GenerateTestID(), &validators.TestState{
+					GetCurrentHeightF: func(context.Context) (uint64, error) {
+						return testHeight, nil
+					},
+				}))
+			},
+			body: `{"jsonrpc":"2.0","method":"platform.issueTx","params":{},"id":1}`,
+		},
+		{
+			name:     "batch",
+			register: func() {},
+			body:     `[{"jsonrpc":"2.0","method":"platform.getHeight","params":{},"id":1}]`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require := require.New(t)
+
+			test.register()
+			w, acceptEncoding := serve(t, s, test.body)
+			require.Equal(http.StatusOK, w.Code)
+			require.Equal("gzip", acceptEncoding)
+			require.Empty(w.Header().Get(SignatureHeader))
+		})
+	}
+}
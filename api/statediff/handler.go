@@ -0,0 +1,175 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package statediff
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	stdjson "encoding/json"
+
+	"go.uber.org/zap"
+
+	"github.com/ava-labs/avalanchego/api/pagination"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
+	"github.com/ava-labs/avalanchego/utils/formatting"
+	"github.com/ava-labs/avalanchego/utils/json"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+const (
+	// Endpoint is the extension of a chain's API the state diff is served on
+	Endpoint = "/statediff"
+
+	// GetStateDiffEndpoint is the name of the state diff endpoint in the max
+	// page sizes of the node's APIs. Its page size is the number of heights a
+	// diff spans.
+	GetStateDiffEndpoint = "statediff.getStateDiff"
+)
+
+var (
+	// MaxPageSizeBounds bounds the number of heights a state diff spans.
+	MaxPageSizeBounds = pagination.Limits{
+		GetStateDiffEndpoint: pagination.DefaultMaxPageSize,
+	}
+
+	errInvalidHeightRange = errors.New("start height is greater than end height")
+)
+
+// Entry is a line of the state diff stream
+type Entry struct {
+	Key string `json:"key"`
+	// Value is nil if the key was deleted
+	Value *string `json:"value"`
+}
+
+// Summary is the last line of a complete state diff stream
+type Summary struct {
+	NumEntries json.Uint64 `json:"numEntries"`
+	// Digest of the entries of the stream, as computed by
+	// block.StateDiffDigest
+	Digest string `json:"digest"`
+}
+
+// Error is the last line of a state diff stream that failed after entries
+// were sent
+type Error struct {
+	Error string `json:"error"`
+}
+
+type handler struct {
+	log        logging.Logger
+	vm         block.StateDiffer
+	maxHeights uint64
+}
+
+// NewHandler returns the HTTP handler that streams the state diff of [vm]
+// between two accepted heights that are at most [maxHeights] apart.
+//
+// The heights are given by the [startHeight] and [endHeight] query
+// parameters. The response is a stream of newline delimited JSON objects: an
+// Entry per key, in increasing key order, followed by a Summary, or by an
+// Error if the export failed. A stream that doesn't end with a summary is
+// incomplete.
+func NewHandler(log logging.Logger, vm block.StateDiffer, maxHeights uint64) *common.HTTPHandler {
+	return &common.HTTPHandler{
+		// Blocks can't be accepted during the export, which is bounded by
+		// [maxHeights].
+		LockOptions: common.ReadLock,
+		Handler: &handler{
+			log:        log,
+			vm:         vm,
+			maxHeights: maxHeights,
+		},
+	}
+}
+
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	startHeight, err := strconv.ParseUint(query.Get("startHeight"), 10, 64)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid start height: %s", err), http.StatusBadRequest)
+		return
+	}
+	endHeight, err := strconv.ParseUint(query.Get("endHeight"), 10, 64)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid end height: %s", err), http.StatusBadRequest)
+		return
+	}
+	if startHeight > endHeight {
+		http.Error(w, errInvalidHeightRange.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := pagination.VerifyPageSize(endHeight-startHeight, h.maxHeights); err != nil {
+		http.Error(w, fmt.Sprintf("invalid height range: %s", err), http.StatusBadRequest)
+		return
+	}
+	h.log.Debug("streaming state diff",
+		zap.Uint64("startHeight", startHeight),
+		zap.Uint64("endHeight", endHeight),
+	)
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+
+	var (
+		encoder = stdjson.NewEncoder(w)
+		digest  = block.NewStateDiffDigest()
+		// The VM may fail before sending any entry, in which case the error
+		// is reported with a status code.
+		started bool
+	)
+	err = h.vm.GetStateDiff(r.Context(), startHeight, endHeight, func(key []byte, value []byte) error {
+		if err := digest.Add(key, value); err != nil {
+			return err
+		}
+		entry := Entry{}
+		entry.Key, err = formatting.Encode(formatting.HexNC, key)
+		if err != nil {
+			return err
+		}
+		if value != nil {
+			encodedValue, err := formatting.Encode(formatting.HexNC, value)
+			if err != nil {
+				return err
+			}
+			entry.Value = &encodedValue
+		}
+		started = true
+		return encoder.Encode(entry)
+	})
+	switch {
+	case err == nil:
+	case errors.Is(err, block.ErrStateDifferNotImplemented) && !started:
+		http.Error(w, err.Error(), http.StatusNotImplemented)
+		return
+	case !started:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	default:
+		h.log.Debug("failed to export state diff",
+			zap.Uint64("startHeight", startHeight),
+			zap.Uint64("endHeight", endHeight),
+			zap.Error(err),
+		)
+		_ = encoder.Encode(Error{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	encodedDigest, err := formatting.Encode(formatting.HexNC, digest.Sum())
+	if err != nil {
+		_ = encoder.Encode(Error{
+			Error: err.Error(),
+		})
+		return
+	}
+	_ = encoder.Encode(Summary{
+		NumEntries: json.Uint64(digest.NumEntries()),
+		Digest:     encodedDigest,
+	})
+}
@@ -0,0 +1,92 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package statediff
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
+	"github.com/ava-labs/avalanchego/utils/formatting"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+const testMaxHeights = 2
+
+func serveStateDiff(vm block.StateDiffer, query string) *httptest.ResponseRecorder {
+	handler := NewHandler(logging.NoLog{}, vm, testMaxHeights)
+	w := httptest.NewRecorder()
+	handler.Handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, Endpoint+"?"+query, nil))
+	return w
+}
+
+func TestStateDiff(t *testing.T) {
+	require := require.New(t)
+
+	vm := &block.TestStateDiffer{
+		T: t,
+		GetStateDiffF: func(_ context.Context, startHeight, endHeight uint64, f func([]byte, []byte) error) error {
+			require.EqualValues(1, startHeight)
+			require.EqualValues(3, endHeight)
+			if err := f([]byte{1}, []byte{0xab}); err != nil {
+				return err
+			}
+			return f([]byte{2}, nil)
+		},
+	}
+
+	digest := block.NewStateDiffDigest()
+	require.NoError(digest.Add([]byte{1}, []byte{0xab}))
+	require.NoError(digest.Add([]byte{2}, nil))
+	encodedDigest, err := formatting.Encode(formatting.HexNC, digest.Sum())
+	require.NoError(err)
+
+	w := serveStateDiff(vm, "startHeight=1&endHeight=3")
+	require.Equal(http.StatusOK, w.Code)
+	lines := strings.Split(strings.TrimSpace(w.Body.String()), "\n")
+	require.Len(lines, 3)
+	require.JSONEq(`{"key":"0x01","value":"0xab"}`, lines[0])
+	require.JSONEq(`{"key":"0x02","value":null}`, lines[1])
+	require.JSONEq(`{"numEntries":"2","digest":"`+encodedDigest+`"}`, lines[2])
+}
+
+func TestStateDiffErrors(t *testing.T) {
+	require := require.New(t)
+
+	errFailed := errors.New("failed")
+	vm := &block.TestStateDiffer{
+		T: t,
+		GetStateDiffF: func(_ context.Context, _, _ uint64, f func([]byte, []byte) error) error {
+			if err := f([]byte{1}, nil); err != nil {
+				return err
+			}
+			return errFailed
+		},
+	}
+
+	require.Equal(http.StatusBadRequest, serveStateDiff(vm, "startHeight=3&endHeight=1").Code)
+	require.Equal(http.StatusBadRequest, serveStateDiff(vm, "endHeight=1").Code)
+	// Diffs can't span more than [testMaxHeights] heights.
+	require.Equal(http.StatusBadRequest, serveStateDiff(vm, "startHeight=0&endHeight=3").Code)
+
+	// Errors that happen after entries were sent end the stream.
+	w := serveStateDiff(vm, "startHeight=0&endHeight=1")
+	require.Equal(http.StatusOK, w.Code)
+	lines := strings.Split(strings.TrimSpace(w.Body.String()), "\n")
+	require.Len(lines, 2)
+	require.JSONEq(`{"error":"failed"}`, lines[1])
+
+	// VMs that wrap a VM that doesn't export state diffs report it before
+	// sending any entry.
+	vm.GetStateDiffF = func(context.Context, uint64, uint64, func([]byte, []byte) error) error {
+		return block.ErrStateDifferNotImplemented
+	}
+	require.Equal(http.StatusNotImplemented, serveStateDiff(vm, "startHeight=0&endHeight=1").Code)
+}
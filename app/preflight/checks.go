@@ -0,0 +1,311 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package preflight
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/ava-labs/avalanchego/database/leveldb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/clockskew"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/perms"
+	"github.com/ava-labs/avalanchego/utils/units"
+	"github.com/ava-labs/avalanchego/version"
+)
+
+const (
+	// Amount of data written to measure the throughput of the database's disk
+	diskSampleSize = 16 * units.MiB
+	diskWriteSize  = units.MiB
+	// Throughput, in bytes per second, below which the database's disk is
+	// considered unusable
+	minDiskThroughput = 5 * units.MiB
+)
+
+var (
+	errPortInUse        = errors.New("port is unavailable")
+	errClockBehind      = errors.New("clock is behind")
+	errClockSkewed      = errors.New("clock is skewed")
+	errKeyAccessible    = errors.New("staking key is accessible by other users")
+	errDatabaseLocked   = errors.New("database is in use by another process")
+	errSlowDisk         = errors.New("disk is too slow")
+	errNotExecutable    = errors.New("plugin isn't executable")
+	errMissingPlugins   = errors.New("no plugins are installed")
+	errMissingPlugin    = errors.New("plugin isn't installed")
+	errUnreadablePlugin = errors.New("plugin directory can't be read")
+
+	// builtinVMs are run by the node itself, so they don't have plugins.
+	builtinVMs = ids.Set{
+		constants.PlatformVMID: struct{}{},
+		constants.AVMID:        struct{}{},
+		constants.EVMID:        struct{}{},
+	}
+)
+
+// checkPorts verifies that the node can listen on its ports.
+func (c *checker) checkPorts() {
+	if port := c.config.StakingPort; port != 0 {
+		if err := listen(fmt.Sprintf(":%d", port)); err != nil {
+			c.fail(
+				"stakingPort",
+				fmt.Errorf("%w: %d: %s", errPortInUse, port, err),
+				fmt.Sprintf("Stop the process listening on port %d or set --staking-port to a free port", port),
+			)
+		}
+	}
+	if port := c.config.HTTPPort; port != 0 {
+		address := net.JoinHostPort(c.config.HTTPHost, fmt.Sprint(port))
+		if err := listen(address); err != nil {
+			c.fail(
+				"httpPort",
+				fmt.Errorf("%w: %s: %s", errPortInUse, address, err),
+				fmt.Sprintf("Stop the process listening on %s, set --http-port to a free port or set --http-host to an address of this host", address),
+			)
+		}
+	}
+}
+
+func listen(address string) error {
+	listener, err := net.Listen(constants.NetworkType, address)
+	if err != nil {
+		return err
+	}
+	return listener.Close()
+}
+
+// checkClock verifies that the clock is plausibly correct. The clock must be
+// past the last network upgrade whose time is known to have passed when this
+// version was released and, if an NTP server is configured, within the
+// critical skew threshold of the NTP server's clock.
+func (c *checker) checkClock() {
+	now := time.Now()
+	upgradeTime := version.GetApricotPhase5Time(c.config.NetworkID)
+	if now.Before(upgradeTime) {
+		c.fail(
+			"clock",
+			fmt.Errorf("%w: the time is %s, which is before %s", errClockBehind, now.UTC(), upgradeTime.UTC()),
+			"Synchronize the system clock, for example by enabling NTP",
+		)
+		return
+	}
+
+	config := c.config.ClockSkewConfig
+	if config.NTPServer == "" {
+		return
+	}
+	skew, err := clockskew.QueryNTP(config.NTPServer, config.NTPTimeout)
+	if err != nil {
+		// The NTP server being unreachable doesn't prevent the node from
+		// running, so it isn't reported as a failure.
+		c.log.Warn("couldn't query NTP server",
+			zap.String("server", config.NTPServer),
+			zap.Error(err),
+		)
+		return
+	}
+	if skew >= config.CriticalThreshold || -skew >= config.CriticalThreshold {
+		c.fail(
+			"clock",
+			fmt.Errorf("%w: the clock is %s off %s", errClockSkewed, skew, config.NTPServer),
+			"Synchronize the system clock, for example by enabling NTP",
+		)
+	}
+}
+
+// checkStakingKeys verifies that the staking keys can't be accessed by users
+// other than the owner of the files and their group.
+func (c *checker) checkStakingKeys() {
+	if runtime.GOOS == "windows" {
+		// File modes don't reflect the permissions of files on windows.
+		return
+	}
+	for _, path := range c.config.StakingKeyPaths {
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		if mode := info.Mode().Perm(); mode&0o007 != 0 {
+			c.fail(
+				"stakingKeyPermissions",
+				fmt.Errorf("%w: %s has mode %s", errKeyAccessible, path, mode),
+				fmt.Sprintf("Run: chmod o-rwx %s", path),
+			)
+		}
+	}
+}
+
+// checkDatabaseLock verifies that no other process has opened the database.
+func (c *checker) checkDatabaseLock() {
+	if c.config.DatabaseName != leveldb.Name {
+		return
+	}
+
+	// The node opens a database in a directory per database version.
+	lockPaths, err := filepath.Glob(filepath.Join(c.config.DatabasePath, "*", "LOCK"))
+	if err != nil {
+		return
+	}
+	for _, lockPath := range lockPaths {
+		locked, err := isLocked(lockPath)
+		if err != nil {
+			c.log.Debug("couldn't check database lock",
+				zap.String("path", lockPath),
+				zap.Error(err),
+			)
+			continue
+		}
+		if locked {
+			dir := filepath.Dir(lockPath)
+			c.fail(
+				"databaseLock",
+				fmt.Errorf("%w: %s", errDatabaseLocked, dir),
+				"Stop the other node using the database or set --db-dir to a different directory",
+			)
+		}
+	}
+}
+
+// checkDisk verifies that the database's disk is writable and fast enough for
+// the node to keep up with the network.
+func (c *checker) checkDisk() {
+	if c.config.DatabaseName != leveldb.Name {
+		return
+	}
+
+	throughput, err := measureDiskThroughput(c.config.DatabasePath)
+	if err != nil {
+		c.fail(
+			"disk",
+			fmt.Errorf("couldn't write to %s: %w", c.config.DatabasePath, err),
+			"Make sure the database directory is writable by the user running the node and that its disk isn't full",
+		)
+		return
+	}
+	if throughput < minDiskThroughput {
+		c.fail(
+			"disk",
+			fmt.Errorf("%w: wrote to %s at %.2f MiB/s, below the minimum of %d MiB/s",
+				errSlowDisk,
+				c.config.DatabasePath,
+				throughput/units.MiB,
+				minDiskThroughput/units.MiB,
+			),
+			"Set --db-dir to a directory on a faster disk",
+		)
+	}
+}
+
+// measureDiskThroughput returns the throughput, in bytes per second, of
+// synchronously writing a file to [dir].
+func measureDiskThroughput(dir string) (float64, error) {
+	if err := os.MkdirAll(dir, perms.ReadWriteExecute); err != nil {
+		return 0, err
+	}
+	file, err := os.CreateTemp(dir, ".preflight-*")
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		_ = file.Close()
+		_ = os.Remove(file.Name())
+	}()
+
+	chunk := make([]byte, diskWriteSize)
+	start := time.Now()
+	for written := 0; written < diskSampleSize; written += len(chunk) {
+		if _, err := file.Write(chunk); err != nil {
+			return 0, err
+		}
+	}
+	if err := file.Sync(); err != nil {
+		return 0, err
+	}
+	return diskSampleSize / time.Since(start).Seconds(), nil
+}
+
+// checkPlugins verifies that the plugins can be launched, that each VM the
+// node is configured to run has a plugin and that a node tracking subnets has
+// plugins to run their chains with.
+func (c *checker) checkPlugins() {
+	entries, err := os.ReadDir(c.config.PluginDir)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		c.fail(
+			"plugins",
+			fmt.Errorf("%w: %s", errUnreadablePlugin, err),
+			"Make sure the plugin directory is readable by the user running the node",
+		)
+		return
+	}
+
+	plugins := make(map[string]struct{}, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		// Plugins are named after their VM, with an optional extension.
+		name := entry.Name()
+		plugins[strings.TrimSuffix(name, filepath.Ext(name))] = struct{}{}
+
+		if runtime.GOOS == "windows" {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		if info.Mode()&0o111 == 0 {
+			path := filepath.Join(c.config.PluginDir, entry.Name())
+			c.fail(
+				"plugins",
+				fmt.Errorf("%w: %s", errNotExecutable, path),
+				fmt.Sprintf("Run: chmod +x %s", path),
+			)
+		}
+	}
+
+	for vmID, aliases := range c.config.VMAliases {
+		if builtinVMs.Contains(vmID) || hasPlugin(plugins, c.config.ExternalVMs, vmID, aliases) {
+			continue
+		}
+		c.fail(
+			"plugins",
+			fmt.Errorf("%w: VM %s in %s", errMissingPlugin, vmID, c.config.PluginDir),
+			fmt.Sprintf("Install the plugin of VM %s in %s, named after its ID or one of its aliases", vmID, c.config.PluginDir),
+		)
+	}
+
+	if len(plugins) == 0 && c.config.WhitelistedSubnets.Len() > 0 && len(c.config.ExternalVMs) == 0 {
+		c.fail(
+			"plugins",
+			fmt.Errorf("%w in %s, but %d subnets are tracked", errMissingPlugins, c.config.PluginDir, c.config.WhitelistedSubnets.Len()),
+			"Install the plugins of the VMs the tracked subnets run, or set --plugin-dir to the directory they're installed in",
+		)
+	}
+}
+
+// hasPlugin returns true if the VM of [vmID] is run by one of [plugins] or is
+// an external VM, either of which is named after [vmID] or one of [aliases].
+func hasPlugin(plugins map[string]struct{}, externalVMs map[string]string, vmID ids.ID, aliases []string) bool {
+	names := append([]string{vmID.String()}, aliases...)
+	for _, name := range names {
+		if _, ok := plugins[name]; ok {
+			return true
+		}
+		if _, ok := externalVMs[name]; ok {
+			return true
+		}
+	}
+	return false
+}
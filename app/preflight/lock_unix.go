@@ -0,0 +1,33 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+//go:build !windows
+// +build !windows
+
+package preflight
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+// isLocked returns true if another process holds the lock on the file at
+// [path], as taken by leveldb when it opens a database.
+func isLocked(path string) (bool, error) {
+	file, err := os.OpenFile(path, os.O_RDWR, 0)
+	if err != nil {
+		return false, err
+	}
+	defer file.Close()
+
+	fd := int(file.Fd())
+	err = syscall.Flock(fd, syscall.LOCK_EX|syscall.LOCK_NB)
+	if errors.Is(err, syscall.EWOULDBLOCK) {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return false, syscall.Flock(fd, syscall.LOCK_UN)
+}
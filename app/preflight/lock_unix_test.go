@@ -0,0 +1,43 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+//go:build !windows
+// +build !windows
+
+package preflight
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/database/leveldb"
+	"github.com/ava-labs/avalanchego/utils/constants"
+)
+
+func TestDatabaseLocked(t *testing.T) {
+	require := require.New(t)
+
+	dir := t.TempDir()
+	versionDir := filepath.Join(dir, "v1.4.5")
+	require.NoError(os.MkdirAll(versionDir, 0o750))
+	lock, err := os.Create(filepath.Join(versionDir, "LOCK"))
+	require.NoError(err)
+	defer lock.Close()
+
+	config := Config{
+		NetworkID:    constants.LocalID,
+		DatabaseName: leveldb.Name,
+		DatabasePath: dir,
+	}
+	require.Empty(runChecks(config))
+
+	require.NoError(syscall.Flock(int(lock.Fd()), syscall.LOCK_EX|syscall.LOCK_NB))
+	failures := runChecks(config)
+	require.Len(failures, 1)
+	require.Equal("databaseLock", failures[0].Check)
+	require.ErrorIs(failures[0].Err, errDatabaseLocked)
+}
@@ -0,0 +1,13 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+//go:build windows
+// +build windows
+
+package preflight
+
+// isLocked always returns false on windows, where the lock on a database is
+// reported when the node opens it.
+func isLocked(string) (bool, error) {
+	return false, nil
+}
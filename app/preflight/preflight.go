@@ -0,0 +1,113 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package preflight verifies the environment of the node before its services
+// are started, so that a misconfigured host is reported immediately with a way
+// to fix it rather than by an unrelated error once the node is running.
+package preflight
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/clockskew"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+var errFailed = errors.New("preflight checks failed")
+
+type Config struct {
+	NetworkID uint32
+
+	// Port the node listens for its peers on. Not checked if 0.
+	StakingPort uint16
+	// Address the API server listens on. Not checked if [HTTPPort] is 0.
+	HTTPHost string
+	HTTPPort uint16
+
+	// Files holding the staking keys. Files that don't exist aren't checked.
+	StakingKeyPaths []string
+
+	// Type and directory of the node's database. The disk and the lock of the
+	// database are only checked if the database is stored on disk.
+	DatabaseName string
+	DatabasePath string
+
+	PluginDir          string
+	WhitelistedSubnets ids.Set
+	// VM ID -> aliases of the VMs the node is configured to run. Each of them
+	// must have a plugin named after its ID or one of its aliases, unless it's
+	// an external VM.
+	VMAliases   map[ids.ID][]string
+	ExternalVMs map[string]string
+
+	ClockSkewConfig clockskew.Config
+}
+
+// Failure is a preflight check that didn't pass.
+type Failure struct {
+	// Check is the name of the check that failed.
+	Check string
+	// Err describes why the check failed.
+	Err error
+	// Remediation tells the operator how to fix the failure.
+	Remediation string
+}
+
+func (f *Failure) Error() string {
+	return fmt.Sprintf("%s: %s. %s", f.Check, f.Err, f.Remediation)
+}
+
+type checker struct {
+	log      logging.Logger
+	config   Config
+	failures []*Failure
+}
+
+func (c *checker) fail(check string, err error, remediation string) {
+	c.failures = append(c.failures, &Failure{
+		Check:       check,
+		Err:         err,
+		Remediation: remediation,
+	})
+}
+
+// Run verifies the environment of the node. Each failed check is logged along
+// with how to fix it. Returns an error if any check failed.
+func Run(log logging.Logger, config Config) error {
+	c := &checker{
+		log:    log,
+		config: config,
+	}
+
+	start := time.Now()
+	c.checkPorts()
+	c.checkClock()
+	c.checkStakingKeys()
+	c.checkDatabaseLock()
+	c.checkDisk()
+	c.checkPlugins()
+
+	if len(c.failures) == 0 {
+		log.Info("preflight checks passed",
+			zap.Duration("duration", time.Since(start)),
+		)
+		return nil
+	}
+
+	checks := make([]string, len(c.failures))
+	for i, failure := range c.failures {
+		log.Error("preflight check failed",
+			zap.String("check", failure.Check),
+			zap.Error(failure.Err),
+			zap.String("remediation", failure.Remediation),
+		)
+		checks[i] = failure.Check
+	}
+	return fmt.Errorf("%w: %s", errFailed, strings.Join(checks, ", "))
+}
@@ -0,0 +1,208 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package preflight
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/database/leveldb"
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+func runChecks(config Config) []*Failure {
+	c := &checker{
+		log:    logging.NoLog{},
+		config: config,
+	}
+	c.checkPorts()
+	c.checkClock()
+	c.checkStakingKeys()
+	c.checkDatabaseLock()
+	c.checkDisk()
+	c.checkPlugins()
+	return c.failures
+}
+
+func TestRunPasses(t *testing.T) {
+	dir := t.TempDir()
+	config := Config{
+		NetworkID:    constants.LocalID,
+		DatabaseName: leveldb.Name,
+		DatabasePath: filepath.Join(dir, "db"),
+		PluginDir:    filepath.Join(dir, "plugins"),
+	}
+	require.NoError(t, Run(logging.NoLog{}, config))
+
+	// The file written to measure the disk's throughput is removed.
+	entries, err := os.ReadDir(config.DatabasePath)
+	require.NoError(t, err)
+	require.Empty(t, entries)
+}
+
+func TestPortInUse(t *testing.T) {
+	require := require.New(t)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(err)
+	defer listener.Close()
+
+	failures := runChecks(Config{
+		NetworkID:    constants.LocalID,
+		DatabaseName: memdb.Name,
+		HTTPHost:     "127.0.0.1",
+		HTTPPort:     uint16(listener.Addr().(*net.TCPAddr).Port),
+	})
+	require.Len(failures, 1)
+	require.Equal("httpPort", failures[0].Check)
+	require.ErrorIs(failures[0].Err, errPortInUse)
+}
+
+func TestStakingKeyPermissions(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("file modes aren't checked on windows")
+	}
+	require := require.New(t)
+
+	dir := t.TempDir()
+	privateKey := filepath.Join(dir, "private.key")
+	require.NoError(os.WriteFile(privateKey, nil, 0o640))
+	publicKey := filepath.Join(dir, "public.key")
+	require.NoError(os.WriteFile(publicKey, nil, 0o644))
+
+	failures := runChecks(Config{
+		NetworkID:    constants.LocalID,
+		DatabaseName: memdb.Name,
+		StakingKeyPaths: []string{
+			privateKey,
+			publicKey,
+			filepath.Join(dir, "missing.key"),
+		},
+	})
+	require.Len(failures, 1)
+	require.Equal("stakingKeyPermissions", failures[0].Check)
+	require.ErrorIs(failures[0].Err, errKeyAccessible)
+	require.Contains(failures[0].Err.Error(), publicKey)
+}
+
+func TestPlugins(t *testing.T) {
+	vmID := ids.GenerateTestID()
+	tests := []struct {
+		name        string
+		plugins     map[string]os.FileMode
+		subnets     bool
+		vmAliases   map[ids.ID][]string
+		externalVMs bool
+		expectedErr error
+	}{
+		{
+			name: "no plugins or subnets",
+		},
+		{
+			name: "executable plugin",
+			plugins: map[string]os.FileMode{
+				"vm": 0o750,
+			},
+			subnets: true,
+		},
+		{
+			name: "non-executable plugin",
+			plugins: map[string]os.FileMode{
+				"vm": 0o640,
+			},
+			expectedErr: errNotExecutable,
+		},
+		{
+			name:        "subnets without plugins",
+			subnets:     true,
+			expectedErr: errMissingPlugins,
+		},
+		{
+			name:        "subnets with external VMs",
+			subnets:     true,
+			externalVMs: true,
+		},
+		{
+			name: "configured VM with plugin named after its ID",
+			plugins: map[string]os.FileMode{
+				vmID.String() + ".exe": 0o750,
+			},
+			vmAliases: map[ids.ID][]string{
+				vmID: {"vm"},
+			},
+		},
+		{
+			name: "configured VM with plugin named after an alias",
+			plugins: map[string]os.FileMode{
+				"vm": 0o750,
+			},
+			vmAliases: map[ids.ID][]string{
+				vmID: {"other", "vm"},
+			},
+		},
+		{
+			name: "configured VM without plugin",
+			plugins: map[string]os.FileMode{
+				"other": 0o750,
+			},
+			vmAliases: map[ids.ID][]string{
+				vmID: {"vm"},
+			},
+			expectedErr: errMissingPlugin,
+		},
+		{
+			name: "configured external VM",
+			vmAliases: map[ids.ID][]string{
+				vmID: {"vm"},
+			},
+			externalVMs: true,
+		},
+		{
+			name: "configured builtin VM",
+			vmAliases: map[ids.ID][]string{
+				constants.EVMID: {"coreth"},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require := require.New(t)
+
+			dir := t.TempDir()
+			for name, mode := range test.plugins {
+				require.NoError(os.WriteFile(filepath.Join(dir, name), nil, mode))
+			}
+			config := Config{
+				NetworkID:    constants.LocalID,
+				DatabaseName: memdb.Name,
+				PluginDir:    dir,
+				VMAliases:    test.vmAliases,
+			}
+			if test.subnets {
+				config.WhitelistedSubnets.Add(ids.GenerateTestID())
+			}
+			if test.externalVMs {
+				config.ExternalVMs = map[string]string{
+					"vm": "127.0.0.1:9000",
+				}
+			}
+
+			failures := runChecks(config)
+			if test.expectedErr == nil || runtime.GOOS == "windows" && test.expectedErr == errNotExecutable {
+				require.Empty(failures)
+				return
+			}
+			require.Len(failures, 1)
+			require.ErrorIs(failures[0].Err, test.expectedErr)
+		})
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/ava-labs/avalanchego/app"
+	"github.com/ava-labs/avalanchego/app/preflight"
 	"github.com/ava-labs/avalanchego/nat"
 	"github.com/ava-labs/avalanchego/node"
 	"github.com/ava-labs/avalanchego/utils/constants"
@@ -68,6 +69,29 @@ func (p *process) Start() error {
 		return err
 	}
 
+	if p.config.PreflightChecksEnabled {
+		if err := preflight.Run(log, p.preflightConfig()); err != nil {
+			log.Fatal("node environment is misconfigured",
+				zap.Error(err),
+			)
+			log.Stop()
+			logFactory.Close()
+			return err
+		}
+	}
+
+	if p.config.DatabaseConfig.MigrateDryRun {
+		err := node.ReportPendingMigrations(p.config.DatabaseConfig, log)
+		if err != nil {
+			log.Fatal("failed to report pending migrations",
+				zap.Error(err),
+			)
+		}
+		log.Stop()
+		logFactory.Close()
+		return err
+	}
+
 	// update fd limit
 	fdLimit := p.config.FdLimit
 	if err := ulimit.Set(fdLimit, log); err != nil {
@@ -168,6 +192,26 @@ func (p *process) Start() error {
 	return nil
 }
 
+func (p *process) preflightConfig() preflight.Config {
+	return preflight.Config{
+		NetworkID:   p.config.NetworkID,
+		StakingPort: p.config.IPPort.IPPort().Port,
+		HTTPHost:    p.config.HTTPHost,
+		HTTPPort:    p.config.HTTPPort,
+		StakingKeyPaths: []string{
+			p.config.StakingKeyPath,
+			p.config.StakingSignerPath,
+		},
+		DatabaseName:       p.config.DatabaseConfig.Name,
+		DatabasePath:       p.config.DatabaseConfig.Path,
+		PluginDir:          p.config.PluginDir,
+		WhitelistedSubnets: p.config.WhitelistedSubnets,
+		VMAliases:          p.config.VMAliases,
+		ExternalVMs:        p.config.ExternalVMs,
+		ClockSkewConfig:    p.config.ClockSkewConfig,
+	}
+}
+
 // Stop attempts to shutdown the currently running node. This function will
 // return immediately.
 func (p *process) Stop() error {
@@ -25,6 +25,16 @@ type LRU struct {
 	entryMap  map[interface{}]*list.Element
 	entryList *list.List
 	Size      int
+
+	// SizeF, if non-nil, returns the size, in bytes, of an entry. It is used
+	// to report the number of bytes held by the cache.
+	SizeF func(key, value interface{}) int
+	// OnEvict, if non-nil, is called with each entry that is removed from the
+	// cache to make room for other entries. It is called while the cache is
+	// locked, so it must not call the cache.
+	OnEvict func(key, value interface{})
+
+	bytes int
 }
 
 func (c *LRU) Put(key, value interface{}) {
@@ -55,6 +65,26 @@ func (c *LRU) Flush() {
 	c.flush()
 }
 
+// Len returns the number of entries in the cache.
+func (c *LRU) Len() int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.entryList == nil {
+		return 0
+	}
+	return c.entryList.Len()
+}
+
+// Bytes returns the number of bytes held by the cache, as reported by
+// [SizeF]. Returns 0 if [SizeF] is nil.
+func (c *LRU) Bytes() int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.bytes
+}
+
 func (c *LRU) init() {
 	if c.entryMap == nil {
 		c.entryMap = make(map[interface{}]*list.Element, minCacheSize)
@@ -74,7 +104,24 @@ func (c *LRU) resize() {
 
 		val := e.Value.(*entry)
 		delete(c.entryMap, val.Key)
+		c.evicted(val)
+	}
+}
+
+// evicted is called with each entry that is removed to make room for other
+// entries.
+func (c *LRU) evicted(e *entry) {
+	c.bytes -= c.size(e)
+	if c.OnEvict != nil {
+		c.OnEvict(e.Key, e.Value)
+	}
+}
+
+func (c *LRU) size(e *entry) int {
+	if c.SizeF == nil {
+		return 0
 	}
+	return c.SizeF(e.Key, e.Value)
 }
 
 func (c *LRU) put(key, value interface{}) {
@@ -88,20 +135,26 @@ func (c *LRU) put(key, value interface{}) {
 
 			val := e.Value.(*entry)
 			delete(c.entryMap, val.Key)
+			c.evicted(val)
 			val.Key = key
 			val.Value = value
+			c.bytes += c.size(val)
 		} else {
-			e = c.entryList.PushBack(&entry{
+			val := &entry{
 				Key:   key,
 				Value: value,
-			})
+			}
+			e = c.entryList.PushBack(val)
+			c.bytes += c.size(val)
 		}
 		c.entryMap[key] = e
 	} else {
 		c.entryList.MoveToBack(e)
 
 		val := e.Value.(*entry)
+		c.bytes -= c.size(val)
 		val.Value = value
+		c.bytes += c.size(val)
 	}
 }
 
@@ -125,6 +178,7 @@ func (c *LRU) evict(key interface{}) {
 	if e, ok := c.entryMap[key]; ok {
 		c.entryList.Remove(e)
 		delete(c.entryMap, key)
+		c.bytes -= c.size(e.Value.(*entry))
 	}
 }
 
@@ -133,4 +187,5 @@ func (c *LRU) flush() {
 
 	c.entryMap = make(map[interface{}]*list.Element, minCacheSize)
 	c.entryList = list.New()
+	c.bytes = 0
 }
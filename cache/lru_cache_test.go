@@ -6,6 +6,8 @@ package cache
 import (
 	"testing"
 
+	"github.com/stretchr/testify/require"
+
 	"github.com/ava-labs/avalanchego/ids"
 )
 
@@ -60,3 +62,47 @@ func TestLRUResize(t *testing.T) {
 		t.Fatalf("Retrieved wrong value")
 	}
 }
+
+func TestLRUSize(t *testing.T) {
+	require := require.New(t)
+
+	var evicted []interface{}
+	cache := &LRU{
+		Size: 2,
+		SizeF: func(_, value interface{}) int {
+			return value.(int)
+		},
+		OnEvict: func(key, _ interface{}) {
+			evicted = append(evicted, key)
+		},
+	}
+	require.Zero(cache.Len())
+
+	id1 := ids.ID{1}
+	id2 := ids.ID{2}
+	id3 := ids.ID{3}
+
+	cache.Put(id1, 1)
+	cache.Put(id2, 2)
+	require.Equal(2, cache.Len())
+	require.Equal(3, cache.Bytes())
+
+	// Replacing a value updates the size of the cache.
+	cache.Put(id1, 4)
+	require.Equal(6, cache.Bytes())
+
+	// Making room for an entry evicts the least recently used one.
+	cache.Put(id3, 8)
+	require.Equal([]interface{}{id2}, evicted)
+	require.Equal(2, cache.Len())
+	require.Equal(12, cache.Bytes())
+
+	// Entries that are explicitly removed aren't reported as evicted.
+	cache.Evict(id1)
+	require.Equal([]interface{}{id2}, evicted)
+	require.Equal(8, cache.Bytes())
+
+	cache.Flush()
+	require.Zero(cache.Len())
+	require.Zero(cache.Bytes())
+}
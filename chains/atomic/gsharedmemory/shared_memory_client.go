@@ -5,12 +5,17 @@ package gsharedmemory
 
 import (
 	"context"
+	"io"
 
 	stdatomic "sync/atomic"
 
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
 	"github.com/ava-labs/avalanchego/chains/atomic"
 	"github.com/ava-labs/avalanchego/database"
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils"
 	"github.com/ava-labs/avalanchego/utils/units"
 
 	sharedmemorypb "github.com/ava-labs/avalanchego/proto/pb/sharedmemory"
@@ -31,6 +36,8 @@ type Client struct {
 	client sharedmemorypb.SharedMemoryClient
 
 	uniqueID int64
+	// true once the server reported that it doesn't serve ApplyStream
+	applyStreamUnsupported utils.AtomicBool
 }
 
 // NewClient returns shared memory connected to remote shared memory
@@ -152,6 +159,49 @@ func (c *Client) Indexed(
 }
 
 func (c *Client) Apply(requests map[ids.ID]*atomic.Requests, batch ...database.Batch) error {
+	if !c.applyStreamUnsupported.GetValue() {
+		err := c.applyStream(requests, batch)
+		if status.Code(err) != codes.Unimplemented {
+			return err
+		}
+		// The server predates ApplyStream, so the requests are sent through
+		// separate calls.
+		c.applyStreamUnsupported.SetValue(true)
+	}
+	return c.chunkApply(requests, batch, func(req *sharedmemorypb.ApplyRequest) error {
+		_, err := c.client.Apply(context.Background(), req)
+		return err
+	})
+}
+
+// applyStream sends [requests] and [batch] through an ApplyStream stream. If
+// any of them fails to be sent, the stream is cancelled so that nothing is
+// applied.
+func (c *Client) applyStream(requests map[ids.ID]*atomic.Requests, batch []database.Batch) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stream, err := c.client.ApplyStream(ctx)
+	if err != nil {
+		return err
+	}
+	err = c.chunkApply(requests, batch, stream.Send)
+	// If the server ended the stream, the reason is reported by CloseAndRecv.
+	if err != nil && err != io.EOF {
+		return err
+	}
+	_, err = stream.CloseAndRecv()
+	return err
+}
+
+// chunkApply splits [requests] and [batch] into ApplyRequests of at most
+// [maxBatchSize] bytes and passes them to [send] in order. Only the last request
+// doesn't continue.
+func (c *Client) chunkApply(
+	requests map[ids.ID]*atomic.Requests,
+	batch []database.Batch,
+	send func(*sharedmemorypb.ApplyRequest) error,
+) error {
 	req := &sharedmemorypb.ApplyRequest{
 		Continues: true,
 		Id:        stdatomic.AddInt64(&c.uniqueID, 1),
@@ -175,7 +225,7 @@ func (c *Client) Apply(requests map[ids.ID]*atomic.Requests, batch ...database.B
 			if newSize := sizeChange + currentSize; newSize > maxBatchSize {
 				currentSize = 0
 
-				if _, err := c.client.Apply(context.Background(), req); err != nil {
+				if err := send(req); err != nil {
 					return err
 				}
 
@@ -198,7 +248,7 @@ func (c *Client) Apply(requests map[ids.ID]*atomic.Requests, batch ...database.B
 			if newSize := sizeChange + currentSize; newSize > maxBatchSize {
 				currentSize = 0
 
-				if _, err := c.client.Apply(context.Background(), req); err != nil {
+				if err := send(req); err != nil {
 					return err
 				}
 
@@ -221,7 +271,7 @@ func (c *Client) Apply(requests map[ids.ID]*atomic.Requests, batch ...database.B
 	for i, batches := range batchGroups {
 		req.Batches = batches
 		req.Continues = i < len(batchGroups)-1
-		if _, err := c.client.Apply(context.Background(), req); err != nil {
+		if err := send(req); err != nil {
 			return err
 		}
 		req.Requests = nil
@@ -229,7 +279,7 @@ func (c *Client) Apply(requests map[ids.ID]*atomic.Requests, batch ...database.B
 
 	if len(batchGroups) == 0 {
 		req.Continues = false
-		if _, err := c.client.Apply(context.Background(), req); err != nil {
+		if err := send(req); err != nil {
 			return err
 		}
 	}
@@ -5,6 +5,7 @@ package gsharedmemory
 
 import (
 	"context"
+	"io"
 	"sync"
 
 	"github.com/ava-labs/avalanchego/chains/atomic"
@@ -238,15 +239,48 @@ func (s *Server) Apply(
 	}
 
 	delete(s.apply, req.Id)
+	return &sharedmemorypb.ApplyResponse{}, s.applyAll(apply)
+}
+
+// ApplyStream assembles the requests and batches sent through [stream] and
+// applies them atomically once the client closes the stream. If the stream
+// fails, nothing is applied.
+func (s *Server) ApplyStream(stream sharedmemorypb.SharedMemory_ApplyStreamServer) error {
+	apply := &applyRequest{
+		requests: make(map[ids.ID]*atomic.Requests),
+		batches:  make(map[int64]database.Batch),
+	}
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := parseRequests(apply.requests, req.Requests); err != nil {
+			return err
+		}
+		if err := s.parseBatches(apply.batches, req.Batches); err != nil {
+			return err
+		}
+	}
 
+	if err := s.applyAll(apply); err != nil {
+		return err
+	}
+	return stream.SendAndClose(&sharedmemorypb.ApplyResponse{})
+}
+
+func (s *Server) applyAll(apply *applyRequest) error {
 	batches := make([]database.Batch, len(apply.batches))
 	i := 0
 	for _, batch := range apply.batches {
 		batches[i] = batch
 		i++
 	}
-
-	return &sharedmemorypb.ApplyResponse{}, s.sm.Apply(apply.requests, batches...)
+	return s.sm.Apply(apply.requests, batches...)
 }
 
 func parseRequests(
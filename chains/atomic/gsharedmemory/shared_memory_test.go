@@ -5,6 +5,7 @@ package gsharedmemory
 
 import (
 	"context"
+	"errors"
 	"io"
 	"net"
 	"testing"
@@ -29,7 +30,39 @@ const (
 	bufSize = units.MiB
 )
 
+var errTest = errors.New("non-nil error")
+
+// legacyServer is a Server that predates ApplyStream.
+type legacyServer struct {
+	sharedmemorypb.UnimplementedSharedMemoryServer
+	server *Server
+}
+
+func (s *legacyServer) Get(ctx context.Context, req *sharedmemorypb.GetRequest) (*sharedmemorypb.GetResponse, error) {
+	return s.server.Get(ctx, req)
+}
+
+func (s *legacyServer) Indexed(ctx context.Context, req *sharedmemorypb.IndexedRequest) (*sharedmemorypb.IndexedResponse, error) {
+	return s.server.Indexed(ctx, req)
+}
+
+func (s *legacyServer) Apply(ctx context.Context, req *sharedmemorypb.ApplyRequest) (*sharedmemorypb.ApplyResponse, error) {
+	return s.server.Apply(ctx, req)
+}
+
 func TestInterface(t *testing.T) {
+	testInterface(t, func(server *Server) sharedmemorypb.SharedMemoryServer {
+		return server
+	})
+}
+
+func TestInterfaceLegacyServer(t *testing.T) {
+	testInterface(t, func(server *Server) sharedmemorypb.SharedMemoryServer {
+		return &legacyServer{server: server}
+	})
+}
+
+func testInterface(t *testing.T, wrap func(*Server) sharedmemorypb.SharedMemoryServer) {
 	require := require.New(t)
 
 	chainID0 := ids.GenerateTestID()
@@ -42,8 +75,8 @@ func TestInterface(t *testing.T) {
 
 		m := atomic.NewMemory(memoryDB)
 
-		sm0, conn0 := wrapSharedMemory(t, m.NewSharedMemory(chainID0), baseDB)
-		sm1, conn1 := wrapSharedMemory(t, m.NewSharedMemory(chainID1), baseDB)
+		sm0, conn0 := wrapSharedMemory(t, wrap(NewServer(m.NewSharedMemory(chainID0), baseDB)))
+		sm1, conn1 := wrapSharedMemory(t, wrap(NewServer(m.NewSharedMemory(chainID1), baseDB)))
 
 		test(t, chainID0, chainID1, sm0, sm1, testDB)
 
@@ -55,13 +88,13 @@ func TestInterface(t *testing.T) {
 	}
 }
 
-func wrapSharedMemory(t *testing.T, sm atomic.SharedMemory, db database.Database) (atomic.SharedMemory, io.Closer) {
+func wrapSharedMemory(t *testing.T, sm sharedmemorypb.SharedMemoryServer) (atomic.SharedMemory, io.Closer) {
 	listener := bufconn.Listen(bufSize)
 	serverCloser := grpcutils.ServerCloser{}
 
 	serverFunc := func(opts []grpc.ServerOption) *grpc.Server {
 		server := grpcutils.NewDefaultServer(opts)
-		sharedmemorypb.RegisterSharedMemoryServer(server, NewServer(sm, db))
+		sharedmemorypb.RegisterSharedMemoryServer(server, sm)
 		serverCloser.Add(server)
 		return server
 	}
@@ -84,3 +117,82 @@ func wrapSharedMemory(t *testing.T, sm atomic.SharedMemory, db database.Database
 	rpcsm := NewClient(sharedmemorypb.NewSharedMemoryClient(conn))
 	return rpcsm, conn
 }
+
+// applyStream is an ApplyStream stream that sends [requests] and then fails
+// with [err].
+type applyStream struct {
+	grpc.ServerStream
+	requests []*sharedmemorypb.ApplyRequest
+	err      error
+}
+
+func (s *applyStream) Recv() (*sharedmemorypb.ApplyRequest, error) {
+	if len(s.requests) == 0 {
+		return nil, s.err
+	}
+	req := s.requests[0]
+	s.requests = s.requests[1:]
+	return req, nil
+}
+
+func (*applyStream) SendAndClose(*sharedmemorypb.ApplyResponse) error {
+	return nil
+}
+
+func TestApplyStreamFailureAppliesNothing(t *testing.T) {
+	require := require.New(t)
+
+	chainID0 := ids.GenerateTestID()
+	chainID1 := ids.GenerateTestID()
+
+	baseDB := memdb.New()
+	m := atomic.NewMemory(prefixdb.New([]byte{0}, baseDB))
+	sm0 := m.NewSharedMemory(chainID0)
+	sm1 := m.NewSharedMemory(chainID1)
+	server := NewServer(sm0, baseDB)
+
+	requests := []*sharedmemorypb.ApplyRequest{
+		{
+			Requests: []*sharedmemorypb.AtomicRequest{{
+				PeerChainId: chainID1[:],
+				PutRequests: []*sharedmemorypb.Element{{
+					Key:   []byte("key"),
+					Value: []byte("value"),
+				}},
+			}},
+			Batches: []*sharedmemorypb.Batch{{
+				Id: 1,
+				Puts: []*sharedmemorypb.BatchPut{{
+					Key:   []byte("batchKey"),
+					Value: []byte("batchValue"),
+				}},
+			}},
+		},
+	}
+
+	err := server.ApplyStream(&applyStream{
+		requests: requests,
+		err:      errTest,
+	})
+	require.ErrorIs(err, errTest)
+
+	_, err = sm1.Get(chainID0, [][]byte{[]byte("key")})
+	require.ErrorIs(err, database.ErrNotFound)
+	has, err := baseDB.Has([]byte("batchKey"))
+	require.NoError(err)
+	require.False(has)
+
+	// Once the stream is closed by the client, everything is applied.
+	err = server.ApplyStream(&applyStream{
+		requests: requests,
+		err:      io.EOF,
+	})
+	require.NoError(err)
+
+	values, err := sm1.Get(chainID0, [][]byte{[]byte("key")})
+	require.NoError(err)
+	require.Equal([][]byte{[]byte("value")}, values)
+	has, err = baseDB.Has([]byte("batchKey"))
+	require.NoError(err)
+	require.True(has)
+}
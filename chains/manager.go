@@ -9,6 +9,9 @@ import (
 	"crypto/tls"
 	"errors"
 	"fmt"
+	"math"
+	"os"
+	"path/filepath"
 	"sync"
 	"time"
 
@@ -16,11 +19,16 @@ import (
 
 	"go.uber.org/zap"
 
+	"golang.org/x/time/rate"
+
 	"github.com/ava-labs/avalanchego/api/health"
 	"github.com/ava-labs/avalanchego/api/keystore"
 	"github.com/ava-labs/avalanchego/api/metrics"
 	"github.com/ava-labs/avalanchego/api/server"
 	"github.com/ava-labs/avalanchego/chains/atomic"
+	"github.com/ava-labs/avalanchego/chains/quota"
+	"github.com/ava-labs/avalanchego/chains/replicas"
+	"github.com/ava-labs/avalanchego/database"
 	"github.com/ava-labs/avalanchego/database/prefixdb"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/message"
@@ -30,10 +38,12 @@ import (
 	"github.com/ava-labs/avalanchego/snow/engine/avalanche/state"
 	"github.com/ava-labs/avalanchego/snow/engine/avalanche/vertex"
 	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/snow/engine/common/decisionlog"
 	"github.com/ava-labs/avalanchego/snow/engine/common/queue"
 	"github.com/ava-labs/avalanchego/snow/engine/common/tracker"
 	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
 	"github.com/ava-labs/avalanchego/snow/engine/snowman/syncer"
+	"github.com/ava-labs/avalanchego/snow/networking/appmux"
 	"github.com/ava-labs/avalanchego/snow/networking/handler"
 	"github.com/ava-labs/avalanchego/snow/networking/router"
 	"github.com/ava-labs/avalanchego/snow/networking/sender"
@@ -41,11 +51,17 @@ import (
 	"github.com/ava-labs/avalanchego/snow/validators"
 	"github.com/ava-labs/avalanchego/trace"
 	"github.com/ava-labs/avalanchego/utils/buffer"
+	"github.com/ava-labs/avalanchego/utils/clockskew"
 	"github.com/ava-labs/avalanchego/utils/constants"
 	"github.com/ava-labs/avalanchego/utils/crypto/bls"
+	"github.com/ava-labs/avalanchego/utils/jsonschema"
 	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/perms"
 	"github.com/ava-labs/avalanchego/version"
 	"github.com/ava-labs/avalanchego/vms"
+	"github.com/ava-labs/avalanchego/vms/auditvm"
+	"github.com/ava-labs/avalanchego/vms/components/aggregator"
+	"github.com/ava-labs/avalanchego/vms/components/blob"
 	"github.com/ava-labs/avalanchego/vms/metervm"
 	"github.com/ava-labs/avalanchego/vms/proposervm"
 	"github.com/ava-labs/avalanchego/vms/tracedvm"
@@ -64,16 +80,14 @@ import (
 	snowgetter "github.com/ava-labs/avalanchego/snow/engine/snowman/getter"
 )
 
-const (
-	defaultChannelSize = 1
-	initialQueueSize   = 3
-)
+const initialQueueSize = 3
 
 var (
 	errUnknownChainID   = errors.New("unknown chain ID")
 	errUnknownVMType    = errors.New("the vm should have type avalanche.DAGVM or snowman.ChainVM")
 	errCreatePlatformVM = errors.New("attempted to create a chain running the PlatformVM")
 	errNotBootstrapped  = errors.New("subnets not bootstrapped")
+	errNotUpgradable    = errors.New("the chain's VM can't be upgraded")
 
 	_ Manager = (*manager)(nil)
 )
@@ -112,6 +126,10 @@ type Manager interface {
 	// Returns true iff the chain with the given ID exists and is finished bootstrapping
 	IsBootstrapped(ids.ID) bool
 
+	// Replaces the plugin running the VM of the chain with the plugin at
+	// [path], without stopping the chain
+	UpgradeVM(ctx context.Context, chainID ids.ID, path string) error
+
 	// Starts the chain creator with the initial platform chain parameters, must
 	// be called once.
 	StartChainCreator(platformChain ChainParameters)
@@ -140,14 +158,22 @@ type chain struct {
 	Engine  common.Engine
 	Handler handler.Handler
 	Beacons validators.Set
+	// Nil if the chain's VM can't be upgraded
+	UpgradableVM common.UpgradableVM
 }
 
 // ChainConfig is configuration settings for the current execution.
 // [Config] is the user-provided config blob for the chain.
 // [Upgrade] is a chain-specific blob for coordinating upgrades.
+// [GRPC] tunes the gRPC connections between the node and the chain's VM, if
+// the VM talks to the node over gRPC.
+// [Cache] sizes the caches of the chain's blocks that the node keeps in front
+// of the chain's VM, if the VM talks to the node over gRPC.
 type ChainConfig struct {
 	Config  []byte
 	Upgrade []byte
+	GRPC    []byte
+	Cache   []byte
 }
 
 type ManagerConfig struct {
@@ -182,6 +208,32 @@ type ManagerConfig struct {
 	RetryBootstrapWarnFrequency int                     // Max number of times to retry bootstrap before warning the node operator
 	SubnetConfigs               map[ids.ID]SubnetConfig // ID -> SubnetConfig
 	ChainConfigs                map[string]ChainConfig  // alias -> ChainConfig
+	// Chain alias -> ID or alias of the VM that re-verifies every block
+	// accepted by the chain
+	ChainAuditVMs map[string]string
+	// Max size of the AppResponses delivered to a chain's VM, unless
+	// overridden in ChainAppResponseMaxSizes. If 0, the size isn't limited.
+	AppResponseMaxSize int
+	// Chain alias -> max size of the AppResponses delivered to the chain's VM
+	ChainAppResponseMaxSizes map[string]int
+	// Number of notifications a chain's VM can queue for the consensus engine,
+	// unless overridden in ChainToEngineChannelSizes
+	ToEngineChannelSize int
+	// Chain alias -> number of notifications the chain's VM can queue
+	ChainToEngineChannelSizes map[string]int
+	// Bytes per second the blob services of all the chains upload and
+	// download. If 0, the bandwidth isn't capped.
+	BlobUploadBandwidth   uint64
+	BlobDownloadBandwidth uint64
+	// Paces the blocks the chains' VMs are asked to build, unless overridden
+	// in ChainBuildPacing
+	BuildPacing block.BuildPacing
+	// Chain alias -> pacing of the blocks the chain's VM is asked to build
+	ChainBuildPacing map[string]block.BuildPacing
+	// Chain alias -> read replicas of the chain's VM
+	ChainReadReplicas map[string]replicas.Config
+	// Estimates the skew of the local clock. May be nil.
+	ClockSkew clockskew.Estimator
 	// ShutdownNodeFunc allows the chain manager to issue a request to shutdown the node
 	ShutdownNodeFunc func(exitCode int)
 	MeterVMEnabled   bool // Should each VM be wrapped with a MeterVM
@@ -207,6 +259,38 @@ type ManagerConfig struct {
 	ResourceTracker timetracker.ResourceTracker
 
 	StateSyncBeacons []ids.NodeID
+
+	// Each chain is given the directory [ChainDataDir]/[chainID] to persist
+	// data outside of its databases.
+	ChainDataDir string
+	// Limits the size of each chain's data directory.
+	ChainDataDirQuota quota.Config
+
+	// Names of the node level health checks that every chain's health check
+	// depends on.
+	ChainHealthDependencies []string
+
+	// If non-zero, a snowman chain is reported as stale if it hasn't accepted
+	// a block for this long while a majority of its stake has sent later
+	// blocks.
+	StaleChainTimeout time.Duration
+	// If true, stale snowman chains, other than the P-chain, are bootstrapped
+	// again.
+	StaleChainResyncEnabled bool
+	// Records the staleness incidents of the chains.
+	Incidents common.IncidentLog
+
+	// If non-zero, the polls of snowman chains that haven't finished after
+	// this long are hedged by querying up to [HedgeBudget] backup validators.
+	HedgeDelay  time.Duration
+	HedgeBudget int
+
+	// If non-empty, each snowman chain records the polls, votes and decisions
+	// of its consensus engine in [DecisionLogDir]/[chainID].
+	DecisionLogDir string
+	// Size above which the oldest entries of a chain's decision log are
+	// deleted.
+	DecisionLogMaxSize uint64
 }
 
 type manager struct {
@@ -233,9 +317,17 @@ type manager struct {
 	// Key: Chain's ID
 	// Value: The chain
 	chains map[ids.ID]handler.Handler
+	// Key: Chain's ID
+	// Value: The VM of the chain, if it can be upgraded
+	upgradableVMs map[ids.ID]common.UpgradableVM
 
 	// snowman++ related interface to allow validators retrieval
 	validatorState validators.State
+
+	// Cap the bandwidth of the blob services of all the chains. nil if the
+	// bandwidth isn't capped.
+	blobUploadLimiter   *rate.Limiter
+	blobDownloadLimiter *rate.Limiter
 }
 
 // New returns a new Manager
@@ -245,12 +337,28 @@ func New(config *ManagerConfig) Manager {
 		ManagerConfig:          *config,
 		subnets:                make(map[ids.ID]Subnet),
 		chains:                 make(map[ids.ID]handler.Handler),
+		upgradableVMs:          make(map[ids.ID]common.UpgradableVM),
 		chainsQueue:            buffer.NewUnboundedBlockingDeque[ChainParameters](initialQueueSize),
 		unblockChainCreatorCh:  make(chan struct{}),
 		chainCreatorShutdownCh: make(chan struct{}),
+		blobUploadLimiter:      newBandwidthLimiter(config.BlobUploadBandwidth),
+		blobDownloadLimiter:    newBandwidthLimiter(config.BlobDownloadBandwidth),
 	}
 }
 
+// newBandwidthLimiter returns a limiter of [bytesPerSecond], or nil if
+// [bytesPerSecond] is 0.
+func newBandwidthLimiter(bytesPerSecond uint64) *rate.Limiter {
+	if bytesPerSecond == 0 {
+		return nil
+	}
+	burst := bytesPerSecond
+	if burst > math.MaxInt32 {
+		burst = math.MaxInt32
+	}
+	return rate.NewLimiter(rate.Limit(bytesPerSecond), int(burst))
+}
+
 // Router that this chain manager is using to route consensus messages to chains
 func (m *manager) Router() router.Router {
 	return m.ManagerConfig.Router
@@ -340,6 +448,7 @@ func (m *manager) createChain(chainParams ChainParameters) {
 			health.CheckerFunc(func(context.Context) (interface{}, error) {
 				return nil, healthCheckErr
 			}),
+			m.ChainHealthDependencies...,
 		)
 		if err != nil {
 			m.Log.Error("failed to register failing health check",
@@ -355,6 +464,9 @@ func (m *manager) createChain(chainParams ChainParameters) {
 
 	m.chainsLock.Lock()
 	m.chains[chainParams.ID] = chain.Handler
+	if chain.UpgradableVM != nil {
+		m.upgradableVMs[chainParams.ID] = chain.UpgradableVM
+	}
 	m.chainsLock.Unlock()
 
 	// Associate the newly created chain with its default alias
@@ -390,6 +502,12 @@ func (m *manager) createChain(chainParams ChainParameters) {
 	// Tell the chain to start processing messages.
 	// If the X, P, or C Chain panics, do not attempt to recover
 	chain.Handler.Start(context.TODO(), !m.CriticalChains.Contains(chainParams.ID))
+
+	// Notify those that registered to be notified when the chain stops
+	go func() {
+		<-chain.Handler.Stopped()
+		m.notifyDeregistrants(chainParams.ID)
+	}()
 }
 
 // Create a chain
@@ -417,6 +535,11 @@ func (m *manager) buildChain(chainParams ChainParameters, sb Subnet) (*chain, er
 		return nil, fmt.Errorf("error while registering vm's metrics %w", err)
 	}
 
+	chainDataDir := filepath.Join(m.ChainDataDir, chainParams.ID.String())
+	if err := os.MkdirAll(chainDataDir, perms.ReadWriteExecute); err != nil {
+		return nil, fmt.Errorf("error while creating chain data directory %w", err)
+	}
+
 	ctx := &snow.ConsensusContext{
 		Context: &snow.Context{
 			NetworkID: m.NetworkID,
@@ -433,6 +556,7 @@ func (m *manager) buildChain(chainParams ChainParameters, sb Subnet) (*chain, er
 			BCLookup:     m,
 			SNLookup:     m,
 			Metrics:      vmMetrics,
+			ChainDataDir: chainDataDir,
 
 			ValidatorState:    m.validatorState,
 			StakingCertLeaf:   m.StakingCert.Leaf,
@@ -466,24 +590,31 @@ func (m *manager) buildChain(chainParams ChainParameters, sb Subnet) (*chain, er
 	}
 	// TODO: Shutdown VM if an error occurs
 
-	fxs := make([]*common.Fx, len(chainParams.FxIDs))
-	for i, fxID := range chainParams.FxIDs {
-		// Get a factory for the fx we want to use on our chain
-		fxFactory, err := m.VMManager.GetFactory(fxID)
+	if err := m.validateChainConfig(ctx, vm); err != nil {
+		return nil, err
+	}
+	if err := m.configureGRPC(ctx, vm); err != nil {
+		return nil, err
+	}
+	if err := m.configureCaches(ctx, vm); err != nil {
+		return nil, err
+	}
+	if tracedVM, ok := vm.(common.TraceableVM); ok && m.TracingEnabled {
+		tracedVM.SetTracer(m.Tracer)
+	}
+	if pacedVM, ok := vm.(block.BuildPacingVM); ok {
+		pacing, err := m.getBuildPacing(ctx.ChainID)
 		if err != nil {
-			return nil, fmt.Errorf("error while getting fxFactory: %w", err)
+			return nil, err
 		}
-
-		fx, err := fxFactory.New(ctx.Context)
-		if err != nil {
-			return nil, fmt.Errorf("error while creating fx: %w", err)
+		if pacing != (block.BuildPacing{}) {
+			pacedVM.SetBuildPacing(pacing)
 		}
+	}
 
-		// Create the fx
-		fxs[i] = &common.Fx{
-			ID: fxID,
-			Fx: fx,
-		}
+	fxs, err := m.createFxs(ctx.Context, chainParams.FxIDs)
+	if err != nil {
+		return nil, err
 	}
 
 	consensusParams := m.ConsensusParams
@@ -549,13 +680,213 @@ func (m *manager) buildChain(chainParams ChainParameters, sb Subnet) (*chain, er
 	}
 
 	// Register the chain with the timeout manager
-	if err := m.TimeoutManager.RegisterChain(ctx); err != nil {
+	if err := m.TimeoutManager.RegisterChain(ctx, vdrs); err != nil {
 		return nil, err
 	}
 
+	if err := m.registerDataDirQuota(ctx, chain); err != nil {
+		return nil, err
+	}
+	chain.UpgradableVM, _ = vm.(common.UpgradableVM)
+	if failableVM, ok := vm.(common.FailableVM); ok {
+		failableVM.SetOnFailed(func(err error) {
+			chain.Handler.StopWithError(context.TODO(), err)
+		})
+	}
+
+	if _, ok := vm.(block.ChainVM); ok {
+		if err := m.registerReadReplicas(ctx, chainParams, vmFactory, chain); err != nil {
+			return nil, err
+		}
+	}
+
 	return chain, nil
 }
 
+// createFxs returns new instances of the fxs [fxIDs].
+func (m *manager) createFxs(ctx *snow.Context, fxIDs []ids.ID) ([]*common.Fx, error) {
+	fxs := make([]*common.Fx, len(fxIDs))
+	for i, fxID := range fxIDs {
+		// Get a factory for the fx we want to use on our chain
+		fxFactory, err := m.VMManager.GetFactory(fxID)
+		if err != nil {
+			return nil, fmt.Errorf("error while getting fxFactory: %w", err)
+		}
+
+		fx, err := fxFactory.New(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("error while creating fx: %w", err)
+		}
+
+		// Create the fx
+		fxs[i] = &common.Fx{
+			ID: fxID,
+			Fx: fx,
+		}
+	}
+	return fxs, nil
+}
+
+// validateChainConfig returns an error if the chain config of the chain
+// doesn't match the schema of the chain configs of [vm].
+func (m *manager) validateChainConfig(ctx *snow.ConsensusContext, vm interface{}) error {
+	schemaVM, ok := vm.(common.ConfigSchemaVM)
+	if !ok {
+		return nil
+	}
+
+	chainConfig, err := m.getChainConfig(ctx.ChainID)
+	if err != nil {
+		return fmt.Errorf("error while fetching chain config: %w", err)
+	}
+	if len(chainConfig.Config) == 0 {
+		return nil
+	}
+
+	schemaBytes, err := schemaVM.ConfigSchema(context.TODO())
+	if err != nil {
+		return fmt.Errorf("couldn't get chain config schema: %w", err)
+	}
+	if len(schemaBytes) == 0 {
+		return nil
+	}
+	schema, err := jsonschema.Parse(schemaBytes)
+	if err != nil {
+		return fmt.Errorf("invalid chain config schema: %w", err)
+	}
+	if err := schema.Validate(chainConfig.Config); err != nil {
+		return fmt.Errorf("invalid config of chain %s: %w", ctx.ChainID, err)
+	}
+	return nil
+}
+
+// configureGRPC gives the gRPC config of the chain to [vm], if it talks to the
+// node over gRPC.
+func (m *manager) configureGRPC(ctx *snow.ConsensusContext, vm interface{}) error {
+	grpcVM, ok := vm.(common.GRPCConfigurableVM)
+	if !ok {
+		return nil
+	}
+
+	chainConfig, err := m.getChainConfig(ctx.ChainID)
+	if err != nil {
+		return fmt.Errorf("error while fetching chain config: %w", err)
+	}
+	if len(chainConfig.GRPC) == 0 {
+		return nil
+	}
+	if err := grpcVM.SetGRPCConfig(chainConfig.GRPC); err != nil {
+		return fmt.Errorf("invalid gRPC config of chain %s: %w", ctx.ChainID, err)
+	}
+	return nil
+}
+
+// configureCaches gives the cache config of the chain to [vm], if it caches
+// the chain's blocks in front of a plugin.
+func (m *manager) configureCaches(ctx *snow.ConsensusContext, vm interface{}) error {
+	cacheVM, ok := vm.(common.CacheConfigurableVM)
+	if !ok {
+		return nil
+	}
+
+	chainConfig, err := m.getChainConfig(ctx.ChainID)
+	if err != nil {
+		return fmt.Errorf("error while fetching chain config: %w", err)
+	}
+	if len(chainConfig.Cache) == 0 {
+		return nil
+	}
+	if err := cacheVM.SetCacheConfig(chainConfig.Cache); err != nil {
+		return fmt.Errorf("invalid cache config of chain %s: %w", ctx.ChainID, err)
+	}
+	return nil
+}
+
+// registerDataDirQuota registers a health check that enforces the configured
+// quota on the chain's data directory.
+func (m *manager) registerDataDirQuota(ctx *snow.ConsensusContext, chain *chain) error {
+	if !m.ChainDataDirQuota.Enabled() {
+		return nil
+	}
+
+	checker, err := quota.NewChecker(
+		ctx.Log,
+		ctx.ChainDataDir,
+		m.ChainDataDirQuota,
+		ctx.Registerer,
+		func(err error) {
+			chain.Handler.StopWithError(context.TODO(), err)
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("couldn't create data directory quota for chain %s: %w", chain.Name, err)
+	}
+
+	checkName := fmt.Sprintf("%s-data-dir", chain.Name)
+	if err := m.Health.RegisterHealthCheck(checkName, checker); err != nil {
+		return fmt.Errorf("couldn't add data directory health check for chain %s: %w", chain.Name, err)
+	}
+	return nil
+}
+
+// registerReadReplicas starts the configured read replicas of the chain's VM
+// and registers them with the API server.
+func (m *manager) registerReadReplicas(
+	ctx *snow.ConsensusContext,
+	chainParams ChainParameters,
+	vmFactory vms.Factory,
+	chain *chain,
+) error {
+	config, err := m.getReadReplicas(ctx.ChainID)
+	if err != nil {
+		return err
+	}
+	if !config.Enabled() {
+		return nil
+	}
+
+	chainConfig, err := m.getChainConfig(ctx.ChainID)
+	if err != nil {
+		return fmt.Errorf("error while fetching chain config: %w", err)
+	}
+	set, err := replicas.New(ctx.Log, config, replicas.Chain{
+		Ctx:          ctx,
+		DBManager:    m.DBManager.NewPrefixDBManager(ctx.ChainID[:]).NewPrefixDBManager([]byte("vm")),
+		GenesisBytes: chainParams.GenesisData,
+		UpgradeBytes: chainConfig.Upgrade,
+		ConfigBytes:  chainConfig.Config,
+		NewVM: func(replicaCtx *snow.Context) (common.VM, []*common.Fx, error) {
+			vm, err := vmFactory.New(replicaCtx)
+			if err != nil {
+				return nil, nil, err
+			}
+			chainVM, ok := vm.(block.ChainVM)
+			if !ok {
+				return nil, nil, fmt.Errorf("expected block.ChainVM but got %T", vm)
+			}
+			fxs, err := m.createFxs(replicaCtx, chainParams.FxIDs)
+			if err != nil {
+				return nil, nil, err
+			}
+			return chainVM, fxs, nil
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("couldn't create read replicas for chain %s: %w", chain.Name, err)
+	}
+	if err := m.ConsensusAcceptorGroup.RegisterAcceptor(ctx.ChainID, "readReplicas", set, false); err != nil {
+		return fmt.Errorf("couldn't register read replicas for chain %s: %w", chain.Name, err)
+	}
+	m.Server.RegisterChainReplicas(ctx.ChainID, set)
+	go set.Dispatch(chain.Handler.Stopped())
+
+	m.Log.Info("serving read-only API requests from read replicas",
+		zap.Stringer("chainID", ctx.ChainID),
+		zap.Int("replicas", config.Replicas),
+	)
+	return nil
+}
+
 func (m *manager) AddRegistrant(r Registrant) {
 	m.registrants = append(m.registrants, r)
 }
@@ -598,7 +929,11 @@ func (m *manager) createAvalancheChain(
 
 	// The channel through which a VM may send messages to the consensus engine
 	// VM uses this channel to notify engine that a block is ready to be made
-	msgChan := make(chan common.Message, defaultChannelSize)
+	channelSize, err := m.getToEngineChannelSize(ctx.ChainID)
+	if err != nil {
+		return nil, err
+	}
+	msgChan := make(chan common.Message, channelSize)
 
 	gossipConfig := m.GossipConfig
 	// short circuit it before reading from subnetConfigs
@@ -653,6 +988,11 @@ func (m *manager) createAvalancheChain(
 		},
 	)
 
+	appMux := appmux.New(ctx.Log, messageSender)
+	if err := m.createServices(ctx.Context, appMux, vm, prefixdb.New([]byte("blob"), db.Database)); err != nil {
+		return nil, err
+	}
+
 	err = vm.Initialize(
 		context.TODO(),
 		ctx.Context,
@@ -662,7 +1002,7 @@ func (m *manager) createAvalancheChain(
 		chainConfig.Config,
 		msgChan,
 		fxs,
-		messageSender,
+		appMux.VMSender(),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("error during vm's Initialize: %w", err)
@@ -673,6 +1013,11 @@ func (m *manager) createAvalancheChain(
 		sampleK = int(bootstrapWeight)
 	}
 
+	maxAppResponseSize, err := m.getAppResponseMaxSize(ctx.ChainID)
+	if err != nil {
+		return nil, err
+	}
+
 	// Asynchronously passes messages from the network to the consensus engine
 	handler, err := handler.New(
 		ctx,
@@ -681,6 +1026,8 @@ func (m *manager) createAvalancheChain(
 		sb.afterBootstrapped(),
 		m.ConsensusGossipFrequency,
 		m.ResourceTracker,
+		maxAppResponseSize,
+		appMux,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("error initializing network handler: %w", err)
@@ -769,7 +1116,7 @@ func (m *manager) createAvalancheChain(
 	// Register health check for this chain
 	chainAlias := m.PrimaryAliasOrDefault(ctx.ChainID)
 
-	if err := m.Health.RegisterHealthCheck(chainAlias, handler); err != nil {
+	if err := m.Health.RegisterHealthCheck(chainAlias, handler, m.ChainHealthDependencies...); err != nil {
 		return nil, fmt.Errorf("couldn't add health check for chain %s: %w", chainAlias, err)
 	}
 
@@ -812,7 +1159,11 @@ func (m *manager) createSnowmanChain(
 
 	// The channel through which a VM may send messages to the consensus engine
 	// VM uses this channel to notify engine that a block is ready to be made
-	msgChan := make(chan common.Message, defaultChannelSize)
+	channelSize, err := m.getToEngineChannelSize(ctx.ChainID)
+	if err != nil {
+		return nil, err
+	}
+	msgChan := make(chan common.Message, channelSize)
 
 	gossipConfig := m.GossipConfig
 	// short circuit it before reading from subnetConfigs
@@ -896,6 +1247,18 @@ func (m *manager) createSnowmanChain(
 		zap.Duration("minBlockDelay", minBlockDelay),
 	)
 
+	auditor, err := m.createAuditor(ctx.Context)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't create auditor: %w", err)
+	}
+	if auditor != nil {
+		m.Log.Warn("auditing accepted blocks",
+			zap.Stringer("chainID", ctx.ChainID),
+		)
+		auditorDBManager := prefixDBManager.NewPrefixDBManager([]byte("audit"))
+		vm = auditvm.NewBlockVM(vm, auditor, auditorDBManager)
+	}
+
 	chainAlias := m.PrimaryAliasOrDefault(ctx.ChainID)
 	if m.TracingEnabled {
 		vm = tracedvm.NewBlockVM(vm, chainAlias, m.Tracer)
@@ -906,6 +1269,7 @@ func (m *manager) createSnowmanChain(
 		m.ApricotPhase4Time,
 		m.ApricotPhase4MinPChainHeight,
 		minBlockDelay,
+		m.ClockSkew,
 	)
 
 	if m.MeterVMEnabled {
@@ -915,6 +1279,11 @@ func (m *manager) createSnowmanChain(
 		vm = tracedvm.NewBlockVM(vm, "proposervm", m.Tracer)
 	}
 
+	appMux := appmux.New(ctx.Log, messageSender)
+	if err := m.createServices(ctx.Context, appMux, vm, prefixdb.New([]byte("blob"), db.Database)); err != nil {
+		return nil, err
+	}
+
 	if err := vm.Initialize(
 		context.TODO(),
 		ctx.Context,
@@ -924,7 +1293,7 @@ func (m *manager) createSnowmanChain(
 		chainConfig.Config,
 		msgChan,
 		fxs,
-		messageSender,
+		appMux.VMSender(),
 	); err != nil {
 		return nil, err
 	}
@@ -934,6 +1303,11 @@ func (m *manager) createSnowmanChain(
 		sampleK = int(bootstrapWeight)
 	}
 
+	maxAppResponseSize, err := m.getAppResponseMaxSize(ctx.ChainID)
+	if err != nil {
+		return nil, err
+	}
+
 	// Asynchronously passes messages from the network to the consensus engine
 	handler, err := handler.New(
 		ctx,
@@ -942,6 +1316,8 @@ func (m *manager) createSnowmanChain(
 		sb.afterBootstrapped(),
 		m.ConsensusGossipFrequency,
 		m.ResourceTracker,
+		maxAppResponseSize,
+		appMux,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("couldn't initialize message handler: %w", err)
@@ -989,6 +1365,29 @@ func (m *manager) createSnowmanChain(
 		Validators:    vdrs,
 		Params:        consensusParams,
 		Consensus:     consensus,
+		Timer:         handler.ConsensusTimer(),
+		StaleTimeout:  m.StaleChainTimeout,
+		Incidents:     m.Incidents,
+		HedgeDelay:    m.HedgeDelay,
+		HedgeBudget:   m.HedgeBudget,
+	}
+	engineConfig.BuildPacing, err = m.getBuildPacing(ctx.ChainID)
+	if err != nil {
+		return nil, err
+	}
+	if m.DecisionLogDir != "" {
+		decisionLogDir := filepath.Join(m.DecisionLogDir, ctx.ChainID.String())
+		engineConfig.Decisions, err = decisionlog.New(decisionLogDir, m.DecisionLogMaxSize)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't open decision log: %w", err)
+		}
+	}
+	// The P-chain can't be bootstrapped again, as the other chains depend on
+	// its validator set.
+	if m.StaleChainResyncEnabled && ctx.ChainID != constants.PlatformChainID {
+		engineConfig.Resync = func(ctx context.Context, startReqID uint32) error {
+			return handler.Bootstrapper().Start(ctx, startReqID)
+		}
 	}
 	engine, err := smeng.New(engineConfig)
 	if err != nil {
@@ -1046,7 +1445,7 @@ func (m *manager) createSnowmanChain(
 	handler.SetStateSyncer(stateSyncer)
 
 	// Register health checks
-	if err := m.Health.RegisterHealthCheck(chainAlias, handler); err != nil {
+	if err := m.Health.RegisterHealthCheck(chainAlias, handler, m.ChainHealthDependencies...); err != nil {
 		return nil, fmt.Errorf("couldn't add health check for chain %s: %w", chainAlias, err)
 	}
 
@@ -1068,6 +1467,20 @@ func (m *manager) SubnetID(chainID ids.ID) (ids.ID, error) {
 	return chain.Context().SubnetID, nil
 }
 
+func (m *manager) UpgradeVM(ctx context.Context, chainID ids.ID, path string) error {
+	m.chainsLock.Lock()
+	_, exists := m.chains[chainID]
+	vm, upgradable := m.upgradableVMs[chainID]
+	m.chainsLock.Unlock()
+	if !exists {
+		return errUnknownChainID
+	}
+	if !upgradable {
+		return errNotUpgradable
+	}
+	return vm.UpgradePlugin(ctx, path)
+}
+
 func (m *manager) IsBootstrapped(id ids.ID) bool {
 	m.chainsLock.Lock()
 	chain, exists := m.chains[id]
@@ -1175,6 +1588,165 @@ func (m *manager) notifyRegistrants(name string, engine common.Engine) {
 	}
 }
 
+// Notify deregistrants [those who want to know about the stop of chains] that
+// the specified chain has stopped
+func (m *manager) notifyDeregistrants(chainID ids.ID) {
+	for _, registrant := range m.registrants {
+		if deregistrant, ok := registrant.(Deregistrant); ok {
+			deregistrant.DeregisterChain(chainID)
+		}
+	}
+}
+
+// getAppResponseMaxSize returns the max size of the AppResponses delivered to
+// the VM of [chainID]. The size registered for the chain's ID takes precedence
+// over the sizes registered for its aliases.
+func (m *manager) getAppResponseMaxSize(chainID ids.ID) (int, error) {
+	if maxSize, ok := m.ChainAppResponseMaxSizes[chainID.String()]; ok {
+		return maxSize, nil
+	}
+	aliases, err := m.Aliases(chainID)
+	if err != nil {
+		return 0, err
+	}
+	for _, alias := range aliases {
+		if maxSize, ok := m.ChainAppResponseMaxSizes[alias]; ok {
+			return maxSize, nil
+		}
+	}
+	return m.AppResponseMaxSize, nil
+}
+
+// getToEngineChannelSize returns the number of notifications the VM of
+// [chainID] can queue for the consensus engine. The size registered for the
+// chain's ID takes precedence over the sizes registered for its aliases.
+func (m *manager) getToEngineChannelSize(chainID ids.ID) (int, error) {
+	if size, ok := m.ChainToEngineChannelSizes[chainID.String()]; ok {
+		return size, nil
+	}
+	aliases, err := m.Aliases(chainID)
+	if err != nil {
+		return 0, err
+	}
+	for _, alias := range aliases {
+		if size, ok := m.ChainToEngineChannelSizes[alias]; ok {
+			return size, nil
+		}
+	}
+	return m.ToEngineChannelSize, nil
+}
+
+// getBuildPacing returns the pacing of the blocks the VM of [chainID] is asked
+// to build. The pacing registered for the chain's ID takes precedence over
+// the pacing registered for its aliases.
+func (m *manager) getBuildPacing(chainID ids.ID) (block.BuildPacing, error) {
+	if pacing, ok := m.ChainBuildPacing[chainID.String()]; ok {
+		return pacing, nil
+	}
+	aliases, err := m.Aliases(chainID)
+	if err != nil {
+		return block.BuildPacing{}, err
+	}
+	for _, alias := range aliases {
+		if pacing, ok := m.ChainBuildPacing[alias]; ok {
+			return pacing, nil
+		}
+	}
+	return m.BuildPacing, nil
+}
+
+// getReadReplicas returns the read replicas of the VM of [chainID]. The
+// replicas registered for the chain's ID take precedence over the replicas
+// registered for its aliases.
+func (m *manager) getReadReplicas(chainID ids.ID) (replicas.Config, error) {
+	if config, ok := m.ChainReadReplicas[chainID.String()]; ok {
+		return config, nil
+	}
+	aliases, err := m.Aliases(chainID)
+	if err != nil {
+		return replicas.Config{}, err
+	}
+	for _, alias := range aliases {
+		if config, ok := m.ChainReadReplicas[alias]; ok {
+			return config, nil
+		}
+	}
+	return replicas.Config{}, nil
+}
+
+// createServices registers the node's services of the chain of [ctx] with
+// [mux], and gives them to the chain's [vm] through [ctx]. The services store
+// their data in [db].
+func (m *manager) createServices(ctx *snow.Context, mux *appmux.Mux, vm common.VM, db database.Database) error {
+	verifier, _ := vm.(block.MessageVerifier)
+	signatureAggregator, err := aggregator.NewService(
+		ctx.Log,
+		mux,
+		ctx.StakingBLSKey,
+		func(ctx context.Context, msg []byte) error {
+			if verifier == nil {
+				return block.ErrMessageVerifierNotImplemented
+			}
+			return verifier.VerifyMessage(ctx, msg)
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("couldn't create signature aggregator: %w", err)
+	}
+	ctx.SignatureAggregator = signatureAggregator
+
+	blobs, err := blob.NewService(
+		ctx.Log,
+		mux,
+		blob.NewStore(db),
+		m.blobUploadLimiter,
+		m.blobDownloadLimiter,
+	)
+	if err != nil {
+		return fmt.Errorf("couldn't create blob service: %w", err)
+	}
+	ctx.Blobs = blobs
+	return nil
+}
+
+// createAuditor returns a new instance of the VM that audits the chain, or nil
+// if the chain isn't audited.
+func (m *manager) createAuditor(ctx *snow.Context) (block.ChainVM, error) {
+	vmAlias, ok := m.ChainAuditVMs[ctx.ChainID.String()]
+	if !ok {
+		aliases, err := m.Aliases(ctx.ChainID)
+		if err != nil {
+			return nil, err
+		}
+		for _, alias := range aliases {
+			if vmAlias, ok = m.ChainAuditVMs[alias]; ok {
+				break
+			}
+		}
+	}
+	if !ok {
+		return nil, nil
+	}
+
+	vmID, err := m.VMManager.Lookup(vmAlias)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't find audit vm %q: %w", vmAlias, err)
+	}
+	vmFactory, err := m.VMManager.GetFactory(vmID)
+	if err != nil {
+		return nil, fmt.Errorf("error while getting audit vmFactory: %w", err)
+	}
+	vm, err := vmFactory.New(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error while creating audit vm: %w", err)
+	}
+	chainVM, ok := vm.(block.ChainVM)
+	if !ok {
+		return nil, fmt.Errorf("audit vm %q is a %T rather than a snowman vm", vmAlias, vm)
+	}
+	return chainVM, nil
+}
+
 // getChainConfig returns value of a entry by looking at ID key and alias key
 // it first searches ID key, then falls back to it's corresponding primary alias
 func (m *manager) getChainConfig(id ids.ID) (ChainConfig, error) {
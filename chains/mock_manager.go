@@ -4,6 +4,8 @@
 package chains
 
 import (
+	"context"
+
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/snow/networking/router"
 )
@@ -42,6 +44,10 @@ func (mm MockManager) Alias(ids.ID, string) error {
 
 func (mm MockManager) RemoveAliases(ids.ID) {}
 
+func (mm MockManager) WatchAliases(func(ids.AliasUpdate)) (func(), error) {
+	return func() {}, nil
+}
+
 func (mm MockManager) Shutdown() {}
 
 func (mm MockManager) StartChainCreator(ChainParameters) {}
@@ -50,6 +56,10 @@ func (mm MockManager) SubnetID(ids.ID) (ids.ID, error) {
 	return ids.ID{}, nil
 }
 
+func (mm MockManager) UpgradeVM(context.Context, ids.ID, string) error {
+	return nil
+}
+
 func (mm MockManager) IsBootstrapped(ids.ID) bool {
 	return false
 }
@@ -0,0 +1,195 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package quota
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"go.uber.org/zap"
+
+	"github.com/ava-labs/avalanchego/api/health"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/storage"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+)
+
+var (
+	_ health.Checker = (*Checker)(nil)
+
+	ErrQuotaExceeded = errors.New("chain data directory exceeded its quota")
+
+	errApproachingQuota  = errors.New("chain data directory is approaching its quota")
+	errUnknownPolicy     = errors.New("unknown quota policy")
+	errInvalidWarnRatio  = errors.New("warning ratio must be in the range (0, 1]")
+	errMissingOnExceeded = errors.New("halt policy requires an exceeded callback")
+	errQuotaDisabled     = errors.New("quota is disabled")
+)
+
+// Policy describes the action taken when a chain exceeds its quota.
+type Policy string
+
+const (
+	// Alert reports the chain as unhealthy but otherwise lets it continue
+	// running.
+	Alert Policy = "alert"
+	// Halt stops the chain the first time it is found to exceed its quota.
+	Halt Policy = "halt"
+)
+
+func (p Policy) Verify() error {
+	switch p {
+	case Alert, Halt:
+		return nil
+	default:
+		return fmt.Errorf("%w: %q", errUnknownPolicy, p)
+	}
+}
+
+type Config struct {
+	// MaxSize is the maximum number of bytes each chain's data directory may
+	// use. If 0, no quota is enforced.
+	MaxSize uint64 `json:"maxSize"`
+
+	// WarningRatio is the fraction of [MaxSize] above which the chain is
+	// reported as approaching its quota.
+	WarningRatio float64 `json:"warningRatio"`
+
+	// Policy is the action taken once [MaxSize] is exceeded.
+	Policy Policy `json:"policy"`
+}
+
+func (c *Config) Enabled() bool {
+	return c.MaxSize != 0
+}
+
+func (c *Config) Verify() error {
+	if !c.Enabled() {
+		return nil
+	}
+	if c.WarningRatio <= 0 || c.WarningRatio > 1 {
+		return fmt.Errorf("%w: %f", errInvalidWarnRatio, c.WarningRatio)
+	}
+	return c.Policy.Verify()
+}
+
+// Checker measures the size of a chain's data directory every time its health
+// is checked and enforces the configured policy.
+type Checker struct {
+	log    logging.Logger
+	dir    string
+	config Config
+	// onExceeded is called at most once, the first time the quota is found to
+	// be exceeded under the [Halt] policy.
+	onExceeded func(error)
+
+	usage prometheus.Gauge
+	quota prometheus.Gauge
+
+	lock   sync.Mutex
+	halted bool
+}
+
+// NewChecker returns a checker for the data directory [dir]. The usage
+// metrics are registered to [registerer].
+func NewChecker(
+	log logging.Logger,
+	dir string,
+	config Config,
+	registerer prometheus.Registerer,
+	onExceeded func(error),
+) (*Checker, error) {
+	if !config.Enabled() {
+		return nil, errQuotaDisabled
+	}
+	if err := config.Verify(); err != nil {
+		return nil, err
+	}
+	if config.Policy == Halt && onExceeded == nil {
+		return nil, errMissingOnExceeded
+	}
+
+	c := &Checker{
+		log:        log,
+		dir:        dir,
+		config:     config,
+		onExceeded: onExceeded,
+		usage: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "data_dir_usage",
+			Help: "Number of bytes used by the chain's data directory",
+		}),
+		quota: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "data_dir_quota",
+			Help: "Maximum number of bytes the chain's data directory may use",
+		}),
+	}
+	c.quota.Set(float64(config.MaxSize))
+
+	errs := wrappers.Errs{}
+	errs.Add(
+		registerer.Register(c.usage),
+		registerer.Register(c.quota),
+	)
+	return c, errs.Err
+}
+
+func (c *Checker) HealthCheck(context.Context) (interface{}, error) {
+	size, err := storage.DirSize(c.dir)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't measure chain data directory: %w", err)
+	}
+	c.usage.Set(float64(size))
+
+	details := map[string]interface{}{
+		"usedBytes":  size,
+		"quotaBytes": c.config.MaxSize,
+	}
+	if size > c.config.MaxSize {
+		err := fmt.Errorf("%w: using %d bytes out of %d",
+			ErrQuotaExceeded,
+			size,
+			c.config.MaxSize,
+		)
+		c.log.Error("chain data directory exceeded quota",
+			zap.String("path", c.dir),
+			zap.Uint64("usedBytes", size),
+			zap.Uint64("quotaBytes", c.config.MaxSize),
+			zap.String("policy", string(c.config.Policy)),
+		)
+		if c.config.Policy == Halt {
+			c.halt(err)
+		}
+		return details, err
+	}
+
+	warningSize := uint64(float64(c.config.MaxSize) * c.config.WarningRatio)
+	if size >= warningSize {
+		c.log.Warn("chain data directory approaching quota",
+			zap.String("path", c.dir),
+			zap.Uint64("usedBytes", size),
+			zap.Uint64("quotaBytes", c.config.MaxSize),
+		)
+		return details, fmt.Errorf("%w: using %d bytes out of %d",
+			errApproachingQuota,
+			size,
+			c.config.MaxSize,
+		)
+	}
+	return details, nil
+}
+
+func (c *Checker) halt(err error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.halted {
+		return
+	}
+	c.halted = true
+	c.onExceeded(err)
+}
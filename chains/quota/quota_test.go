@@ -0,0 +1,139 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package quota
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+func TestConfigVerify(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      Config
+		expectedErr error
+	}{
+		{
+			name:   "disabled",
+			config: Config{},
+		},
+		{
+			name: "valid",
+			config: Config{
+				MaxSize:      1,
+				WarningRatio: 1,
+				Policy:       Halt,
+			},
+		},
+		{
+			name: "zero warning ratio",
+			config: Config{
+				MaxSize: 1,
+				Policy:  Alert,
+			},
+			expectedErr: errInvalidWarnRatio,
+		},
+		{
+			name: "unknown policy",
+			config: Config{
+				MaxSize:      1,
+				WarningRatio: .5,
+				Policy:       "shutdown",
+			},
+			expectedErr: errUnknownPolicy,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.config.Verify()
+			require.ErrorIs(t, err, test.expectedErr)
+		})
+	}
+}
+
+func TestCheckerAlert(t *testing.T) {
+	require := require.New(t)
+
+	dir := t.TempDir()
+	checker, err := NewChecker(
+		logging.NoLog{},
+		dir,
+		Config{
+			MaxSize:      10,
+			WarningRatio: .5,
+			Policy:       Alert,
+		},
+		prometheus.NewRegistry(),
+		nil,
+	)
+	require.NoError(err)
+
+	_, err = checker.HealthCheck(context.Background())
+	require.NoError(err)
+
+	require.NoError(os.WriteFile(filepath.Join(dir, "a"), make([]byte, 5), 0o600))
+	_, err = checker.HealthCheck(context.Background())
+	require.ErrorIs(err, errApproachingQuota)
+
+	require.NoError(os.WriteFile(filepath.Join(dir, "b"), make([]byte, 6), 0o600))
+	_, err = checker.HealthCheck(context.Background())
+	require.ErrorIs(err, ErrQuotaExceeded)
+}
+
+func TestCheckerHaltOnce(t *testing.T) {
+	require := require.New(t)
+
+	dir := t.TempDir()
+	halted := 0
+	checker, err := NewChecker(
+		logging.NoLog{},
+		dir,
+		Config{
+			MaxSize:      1,
+			WarningRatio: 1,
+			Policy:       Halt,
+		},
+		prometheus.NewRegistry(),
+		func(err error) {
+			require.ErrorIs(err, ErrQuotaExceeded)
+			halted++
+		},
+	)
+	require.NoError(err)
+
+	require.NoError(os.WriteFile(filepath.Join(dir, "a"), make([]byte, 2), 0o600))
+	for i := 0; i < 2; i++ {
+		_, err = checker.HealthCheck(context.Background())
+		require.ErrorIs(err, ErrQuotaExceeded)
+	}
+	require.Equal(1, halted)
+}
+
+func TestNewCheckerErrors(t *testing.T) {
+	require := require.New(t)
+
+	_, err := NewChecker(logging.NoLog{}, "", Config{}, prometheus.NewRegistry(), nil)
+	require.ErrorIs(err, errQuotaDisabled)
+
+	_, err = NewChecker(
+		logging.NoLog{},
+		"",
+		Config{
+			MaxSize:      1,
+			WarningRatio: 1,
+			Policy:       Halt,
+		},
+		prometheus.NewRegistry(),
+		nil,
+	)
+	require.ErrorIs(err, errMissingOnExceeded)
+}
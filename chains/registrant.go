@@ -4,6 +4,7 @@
 package chains
 
 import (
+	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/snow/engine/common"
 )
 
@@ -14,3 +15,11 @@ type Registrant interface {
 	// [engine] should be an avalanche.Engine or snowman.Engine
 	RegisterChain(name string, engine common.Engine)
 }
+
+// Deregistrant is a Registrant that is notified when a chain stops
+type Deregistrant interface {
+	Registrant
+
+	// Called after the chain [chainID] stopped processing messages
+	DeregisterChain(chainID ids.ID)
+}
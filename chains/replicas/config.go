@@ -0,0 +1,42 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package replicas
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	errNegativeReplicas       = errors.New("number of read replicas must be non-negative")
+	errNonPositiveRefreshTime = errors.New("read replica refresh interval must be positive")
+)
+
+type Config struct {
+	// Replicas is the number of read-only instances of the chain's VM that
+	// serve read-only API requests alongside the chain's VM. If 0, the chain
+	// has no replicas.
+	Replicas int `json:"replicas"`
+
+	// RefreshInterval is how often the replicas that couldn't be created are
+	// created again. Replicas are otherwise replaced as soon as the chain
+	// accepts a block, and don't serve requests until they are replaced.
+	RefreshInterval time.Duration `json:"refreshInterval"`
+}
+
+func (c *Config) Enabled() bool {
+	return c.Replicas != 0
+}
+
+func (c *Config) Verify() error {
+	switch {
+	case c.Replicas < 0:
+		return fmt.Errorf("%w: %d", errNegativeReplicas, c.Replicas)
+	case c.Enabled() && c.RefreshInterval <= 0:
+		return fmt.Errorf("%w: %s", errNonPositiveRefreshTime, c.RefreshInterval)
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,385 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package replicas runs read-only instances of a chain's VM that serve a share
+// of the read-only API requests to the chain, so that the work of serving them
+// is spread across cores rather than contending for the lock of the chain's
+// VM.
+package replicas
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/ava-labs/avalanchego/api/metrics"
+	"github.com/ava-labs/avalanchego/api/server"
+	"github.com/ava-labs/avalanchego/api/shadow"
+	"github.com/ava-labs/avalanchego/database/manager"
+	"github.com/ava-labs/avalanchego/database/readonlydb"
+	"github.com/ava-labs/avalanchego/database/versiondb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+var (
+	errUnknownLockOption = errors.New("invalid lock options")
+
+	_ server.Replicas = (*Set)(nil)
+	_ snow.Acceptor   = (*Set)(nil)
+)
+
+// Chain describes the chain whose VM is replicated.
+type Chain struct {
+	Ctx *snow.ConsensusContext
+	// Databases of the chain's VM. Replicas are given read-only handles on
+	// them.
+	DBManager    manager.Manager
+	GenesisBytes []byte
+	UpgradeBytes []byte
+	ConfigBytes  []byte
+	// NewVM returns a new, uninitialized, instance of the chain's VM along
+	// with the fxs to initialize it with.
+	NewVM func(replicaCtx *snow.Context) (common.VM, []*common.Fx, error)
+}
+
+type replica struct {
+	vm  common.VM
+	ctx *snow.Context
+	// Extension -> handler of the replica, which grabs the replica's lock as
+	// specified by the VM
+	handlers map[string]http.Handler
+	// Requests being served by the replica
+	inflight sync.WaitGroup
+	// Number of blocks the chain had accepted when the replica was created
+	accepted uint64
+}
+
+// Set is the replicas of a chain.
+//
+// Replicas are created once the chain finishes bootstrapping, and are replaced
+// by new replicas, initialized from the chain's current state, when the chain
+// accepts a block. Replicas that were created before the chain accepted its
+// last block hold state that may be stale, so they aren't routed requests.
+type Set struct {
+	log    logging.Logger
+	config Config
+	chain  Chain
+
+	// Number of blocks the chain accepted since the set was created
+	accepted uint64
+	// Signaled when the chain accepts a block, so that the replicas are
+	// refreshed.
+	accepts chan struct{}
+
+	lock     sync.RWMutex
+	replicas []*replica
+	// Number of requests routed, used to pick the instance that serves the next
+	// request in a round-robin fashion
+	routed uint64
+}
+
+func New(log logging.Logger, config Config, chain Chain) (*Set, error) {
+	if err := config.Verify(); err != nil {
+		return nil, err
+	}
+	return &Set{
+		log:     log,
+		config:  config,
+		chain:   chain,
+		accepts: make(chan struct{}, 1),
+	}, nil
+}
+
+// Accept implements snow.Acceptor. It's called with the chain's lock held,
+// before the accepted block is committed, so the replicas stop serving
+// requests before the chain's state changes.
+func (s *Set) Accept(*snow.ConsensusContext, ids.ID, []byte) error {
+	atomic.AddUint64(&s.accepted, 1)
+	select {
+	case s.accepts <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Route returns the handler of a replica if [r] is read-only and it's the
+// turn of a replica to serve a request. The chain's VM takes its turn along
+// with the replicas, and serves the turns of the replicas that are stale.
+//
+// Websocket requests are always served by the chain's VM, as they outlive the
+// replicas.
+func (s *Set) Route(extension string, r *http.Request) (http.Handler, bool) {
+	if r.Header.Get("Upgrade") != "" || !shadow.IsReadOnlyRequest(r) {
+		return nil, false
+	}
+
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if len(s.replicas) == 0 {
+		return nil, false
+	}
+	turn := atomic.AddUint64(&s.routed, 1) % uint64(len(s.replicas)+1)
+	if turn == 0 {
+		return nil, false
+	}
+	rep := s.replicas[turn-1]
+	if rep.accepted != atomic.LoadUint64(&s.accepted) {
+		return nil, false
+	}
+	h, ok := rep.handlers[extension]
+	if !ok {
+		return nil, false
+	}
+
+	rep.inflight.Add(1)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer rep.inflight.Done()
+		h.ServeHTTP(w, r)
+	}), true
+}
+
+// Dispatch maintains the replicas until [stopped] is closed, at which point
+// the replicas are shut down. The replicas are refreshed when the chain
+// accepts a block, and periodically so that replicas that couldn't be created
+// are created again.
+func (s *Set) Dispatch(stopped <-chan struct{}) {
+	ticker := time.NewTicker(s.config.RefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.accepts:
+			s.refresh()
+		case <-ticker.C:
+			s.refresh()
+		case <-stopped:
+			s.lock.Lock()
+			replicas := s.replicas
+			s.replicas = nil
+			s.lock.Unlock()
+
+			for _, rep := range replicas {
+				s.shutdown(rep)
+			}
+			return
+		}
+	}
+}
+
+// refresh replaces the replicas that were created before the chain accepted
+// its last block, and creates the missing replicas.
+func (s *Set) refresh() {
+	if s.chain.Ctx.GetState() != snow.NormalOp {
+		return
+	}
+
+	// Grabbing the chain's lock ensures that the blocks accepted so far are
+	// committed before the new replicas are initialized. If the chain accepts
+	// a block while they are initialized, they are stale and are replaced by
+	// the following refresh.
+	s.chain.Ctx.Lock.RLock()
+	accepted := atomic.LoadUint64(&s.accepted)
+	s.chain.Ctx.Lock.RUnlock()
+
+	refreshed := false
+	for i := 0; i < s.config.Replicas; i++ {
+		s.lock.RLock()
+		current := i < len(s.replicas) && s.replicas[i].accepted == accepted
+		s.lock.RUnlock()
+		if current {
+			continue
+		}
+
+		rep, err := s.create(i, accepted)
+		if err != nil {
+			s.log.Warn("failed to create read replica",
+				zap.Stringer("chainID", s.chain.Ctx.ChainID),
+				zap.Int("replica", i),
+				zap.Error(err),
+			)
+			continue
+		}
+
+		s.lock.Lock()
+		var old *replica
+		if i < len(s.replicas) {
+			old = s.replicas[i]
+			s.replicas[i] = rep
+		} else {
+			s.replicas = append(s.replicas, rep)
+		}
+		s.lock.Unlock()
+
+		if old != nil {
+			s.shutdown(old)
+		}
+		refreshed = true
+	}
+	if refreshed {
+		s.log.Debug("refreshed read replicas",
+			zap.Stringer("chainID", s.chain.Ctx.ChainID),
+		)
+	}
+}
+
+func (s *Set) create(i int, accepted uint64) (*replica, error) {
+	chainCtx := s.chain.Ctx.Context
+	replicaCtx := &snow.Context{
+		NetworkID:   chainCtx.NetworkID,
+		SubnetID:    chainCtx.SubnetID,
+		ChainID:     chainCtx.ChainID,
+		NodeID:      chainCtx.NodeID,
+		XChainID:    chainCtx.XChainID,
+		AVAXAssetID: chainCtx.AVAXAssetID,
+		Log:         chainCtx.Log,
+		Keystore:    chainCtx.Keystore,
+		// The replica must not modify the shared memory, as the chain's VM
+		// applies the changes of accepted blocks.
+		SharedMemory:      &readOnlySharedMemory{SharedMemory: chainCtx.SharedMemory},
+		BCLookup:          chainCtx.BCLookup,
+		SNLookup:          chainCtx.SNLookup,
+		Metrics:           metrics.NewOptionalGatherer(),
+		ChainDataDir:      filepath.Join(chainCtx.ChainDataDir, fmt.Sprintf("replica%d", i)),
+		ValidatorState:    chainCtx.ValidatorState,
+		StakingLeafSigner: chainCtx.StakingLeafSigner,
+		StakingCertLeaf:   chainCtx.StakingCertLeaf,
+		StakingBLSKey:     chainCtx.StakingBLSKey,
+	}
+
+	vm, err := s.initialize(replicaCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	replicaCtx.Lock.Lock()
+	vmHandlers, err := vm.CreateHandlers(context.TODO())
+	replicaCtx.Lock.Unlock()
+	if err != nil {
+		s.shutdown(&replica{
+			vm:  vm,
+			ctx: replicaCtx,
+		})
+		return nil, fmt.Errorf("couldn't create handlers: %w", err)
+	}
+
+	handlers := make(map[string]http.Handler, len(vmHandlers))
+	for extension, handler := range vmHandlers {
+		h, err := lockHandler(handler, &replicaCtx.Lock)
+		if err != nil {
+			s.log.Warn("couldn't add route to read replica",
+				zap.String("extension", extension),
+				zap.Error(err),
+			)
+			continue
+		}
+		handlers[extension] = h
+	}
+	return &replica{
+		vm:       vm,
+		ctx:      replicaCtx,
+		handlers: handlers,
+		accepted: accepted,
+	}, nil
+}
+
+// initialize returns a new instance of the chain's VM, initialized from the
+// chain's current state.
+//
+// VMs may write to their databases while they are initialized, such as to
+// persist their config. The replica's writes are kept in memory, over a
+// read-only view of the chain's databases, and are dropped when the replica is
+// shut down.
+func (s *Set) initialize(replicaCtx *snow.Context) (common.VM, error) {
+	vm, fxs, err := s.chain.NewVM(replicaCtx)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't create vm: %w", err)
+	}
+
+	dbs := s.chain.DBManager.GetDatabases()
+	readOnlyDBs := make([]*manager.VersionedDatabase, len(dbs))
+	for i, db := range dbs {
+		readOnlyDBs[i] = &manager.VersionedDatabase{
+			Database: versiondb.New(readonlydb.New(db.Database)),
+			Version:  db.Version,
+		}
+	}
+	dbManager, err := manager.NewManagerFromDBs(readOnlyDBs)
+	if err != nil {
+		return nil, err
+	}
+
+	replicaCtx.Lock.Lock()
+	defer replicaCtx.Lock.Unlock()
+
+	// The replica never builds blocks, so the messages it sends to the
+	// consensus engine are dropped once the channel is full.
+	toEngine := make(chan common.Message, 1)
+	if err := vm.Initialize(
+		context.TODO(),
+		replicaCtx,
+		dbManager,
+		s.chain.GenesisBytes,
+		s.chain.UpgradeBytes,
+		s.chain.ConfigBytes,
+		toEngine,
+		fxs,
+		noOpAppSender{},
+	); err != nil {
+		return nil, fmt.Errorf("couldn't initialize vm: %w", err)
+	}
+	if err := vm.SetState(context.TODO(), snow.NormalOp); err != nil {
+		_ = vm.Shutdown(context.TODO())
+		return nil, fmt.Errorf("couldn't start vm: %w", err)
+	}
+	return vm, nil
+}
+
+// shutdown shuts down [rep] once it stopped serving requests. Assumes [rep]
+// can no longer be routed requests.
+func (s *Set) shutdown(rep *replica) {
+	rep.inflight.Wait()
+
+	rep.ctx.Lock.Lock()
+	defer rep.ctx.Lock.Unlock()
+
+	if err := rep.vm.Shutdown(context.TODO()); err != nil {
+		s.log.Warn("failed to shut down read replica",
+			zap.Stringer("chainID", s.chain.Ctx.ChainID),
+			zap.Error(err),
+		)
+	}
+}
+
+// lockHandler wraps [handler] so that it grabs [lock] as specified by its lock
+// options.
+func lockHandler(handler *common.HTTPHandler, lock *sync.RWMutex) (http.Handler, error) {
+	switch handler.LockOptions {
+	case common.WriteLock:
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			lock.Lock()
+			defer lock.Unlock()
+			handler.Handler.ServeHTTP(w, r)
+		}), nil
+	case common.ReadLock:
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			lock.RLock()
+			defer lock.RUnlock()
+			handler.Handler.ServeHTTP(w, r)
+		}), nil
+	case common.NoLock:
+		return handler.Handler, nil
+	default:
+		return nil, errUnknownLockOption
+	}
+}
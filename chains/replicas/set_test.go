@@ -0,0 +1,168 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package replicas
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/database/manager"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/version"
+)
+
+// newTestSet returns a set whose replicas respond to requests with the number
+// of the replica, counting from 1, and the number of replicas that were shut
+// down.
+func newTestSet(t *testing.T, config Config) (*Set, *int) {
+	ctx := snow.DefaultConsensusContextTest()
+	ctx.SetState(snow.NormalOp)
+
+	var (
+		created  int
+		shutdown int
+	)
+	dbManager := manager.NewMemDB(version.CurrentDatabase)
+	s, err := New(logging.NoLog{}, config, Chain{
+		Ctx:       ctx,
+		DBManager: dbManager,
+		NewVM: func(*snow.Context) (common.VM, []*common.Fx, error) {
+			created++
+			id := created
+			vm := &common.TestVM{}
+			vm.InitializeF = func(_ context.Context, _ *snow.Context, db manager.Manager, _, _, _ []byte, _ chan<- common.Message, _ []*common.Fx, _ common.AppSender) error {
+				// The replica's writes are visible to the replica, but aren't
+				// written to the chain's database.
+				replicaDB := db.Current().Database
+				require.NoError(t, replicaDB.Put([]byte("key"), []byte("value")))
+				has, err := replicaDB.Has([]byte("key"))
+				require.NoError(t, err)
+				require.True(t, has)
+				has, err = dbManager.Current().Database.Has([]byte("key"))
+				require.NoError(t, err)
+				require.False(t, has)
+				return nil
+			}
+			vm.SetStateF = func(context.Context, snow.State) error {
+				return nil
+			}
+			vm.ShutdownF = func(context.Context) error {
+				shutdown++
+				return nil
+			}
+			vm.CreateHandlersF = func(context.Context) (map[string]*common.HTTPHandler, error) {
+				return map[string]*common.HTTPHandler{
+					"/rpc": {
+						LockOptions: common.ReadLock,
+						Handler: http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+							_, _ = w.Write([]byte{byte('0' + id)})
+						}),
+					},
+				}, nil
+			}
+			return vm, nil, nil
+		},
+	})
+	require.NoError(t, err)
+	return s, &shutdown
+}
+
+func newRequest(method string) *http.Request {
+	body := `{"jsonrpc":"2.0","id":1,"method":"` + method + `"}`
+	return httptest.NewRequest(http.MethodPost, "/ext/bc/chain/rpc", strings.NewReader(body))
+}
+
+// serve returns the response of the replica that [r] is routed to, or "" if
+// [r] is served by the chain's VM.
+func serve(s *Set, extension string, r *http.Request) string {
+	h, ok := s.Route(extension, r)
+	if !ok {
+		return ""
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	return w.Body.String()
+}
+
+func TestRouteRoundRobin(t *testing.T) {
+	require := require.New(t)
+
+	s, _ := newTestSet(t, Config{
+		Replicas:        2,
+		RefreshInterval: time.Second,
+	})
+
+	// Requests are served by the chain's VM until the replicas are created.
+	require.Empty(serve(s, "/rpc", newRequest("test.getBalance")))
+
+	s.refresh()
+
+	require.Equal("1", serve(s, "/rpc", newRequest("test.getBalance")))
+	require.Equal("2", serve(s, "/rpc", newRequest("test.getBalance")))
+	require.Empty(serve(s, "/rpc", newRequest("test.getBalance")))
+	require.Equal("1", serve(s, "/rpc", newRequest("test.getBalance")))
+
+	// Requests that may modify the chain's state and requests to routes the
+	// replicas don't serve are served by the chain's VM.
+	require.Empty(serve(s, "/rpc", newRequest("test.issueTx")))
+	require.Empty(serve(s, "/ws", newRequest("test.getBalance")))
+	wsRequest := httptest.NewRequest(http.MethodGet, "/ext/bc/chain/rpc", nil)
+	wsRequest.Header.Set("Upgrade", "websocket")
+	require.Empty(serve(s, "/rpc", wsRequest))
+}
+
+func TestRefreshAfterAccept(t *testing.T) {
+	require := require.New(t)
+
+	s, shutdown := newTestSet(t, Config{
+		Replicas:        1,
+		RefreshInterval: time.Second,
+	})
+
+	s.refresh()
+	require.Equal("1", serve(s, "/rpc", newRequest("test.getBalance")))
+	require.Empty(serve(s, "/rpc", newRequest("test.getBalance")))
+
+	// Nothing was accepted, so the replica is kept.
+	s.refresh()
+	require.Zero(*shutdown)
+
+	// Once a block is accepted, the replica is stale and the chain's VM
+	// serves its turns until it's replaced.
+	require.NoError(s.Accept(s.chain.Ctx, ids.GenerateTestID(), nil))
+	require.Empty(serve(s, "/rpc", newRequest("test.getBalance")))
+	require.Empty(serve(s, "/rpc", newRequest("test.getBalance")))
+
+	s.refresh()
+	require.Equal(1, *shutdown)
+	require.Equal("2", serve(s, "/rpc", newRequest("test.getBalance")))
+
+	stopped := make(chan struct{})
+	close(stopped)
+	s.Dispatch(stopped)
+	require.Equal(2, *shutdown)
+	require.Empty(serve(s, "/rpc", newRequest("test.getBalance")))
+}
+
+func TestNoReplicasBeforeBootstrapped(t *testing.T) {
+	require := require.New(t)
+
+	s, _ := newTestSet(t, Config{
+		Replicas:        1,
+		RefreshInterval: time.Second,
+	})
+	s.chain.Ctx.SetState(snow.Bootstrapping)
+
+	s.refresh()
+	require.Empty(serve(s, "/rpc", newRequest("test.getBalance")))
+}
@@ -0,0 +1,27 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package replicas
+
+import (
+	"errors"
+
+	"github.com/ava-labs/avalanchego/chains/atomic"
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+var (
+	errReadOnlySharedMemory = errors.New("shared memory is read-only")
+
+	_ atomic.SharedMemory = (*readOnlySharedMemory)(nil)
+)
+
+// readOnlySharedMemory reads from the shared memory without ever modifying it.
+type readOnlySharedMemory struct {
+	atomic.SharedMemory
+}
+
+func (*readOnlySharedMemory) Apply(map[ids.ID]*atomic.Requests, ...database.Batch) error {
+	return errReadOnlySharedMemory
+}
@@ -5,6 +5,7 @@ package config
 
 import (
 	"crypto/tls"
+	"crypto/x509"
 	"encoding/base64"
 	"encoding/json"
 	"errors"
@@ -19,10 +20,19 @@ import (
 
 	"github.com/spf13/viper"
 
+	"github.com/ava-labs/avalanchego/api/accesslog"
+	"github.com/ava-labs/avalanchego/api/info"
+	"github.com/ava-labs/avalanchego/api/pagination"
+	"github.com/ava-labs/avalanchego/api/server"
+	"github.com/ava-labs/avalanchego/api/shadow"
+	"github.com/ava-labs/avalanchego/api/statediff"
 	"github.com/ava-labs/avalanchego/app/runner"
 	"github.com/ava-labs/avalanchego/chains"
+	"github.com/ava-labs/avalanchego/chains/quota"
+	"github.com/ava-labs/avalanchego/chains/replicas"
 	"github.com/ava-labs/avalanchego/genesis"
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/indexer"
 	"github.com/ava-labs/avalanchego/ipcs"
 	"github.com/ava-labs/avalanchego/nat"
 	"github.com/ava-labs/avalanchego/network"
@@ -31,31 +41,41 @@ import (
 	"github.com/ava-labs/avalanchego/node"
 	"github.com/ava-labs/avalanchego/snow/consensus/avalanche"
 	"github.com/ava-labs/avalanchego/snow/consensus/snowball"
+	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
 	"github.com/ava-labs/avalanchego/snow/networking/benchlist"
 	"github.com/ava-labs/avalanchego/snow/networking/router"
 	"github.com/ava-labs/avalanchego/snow/networking/sender"
 	"github.com/ava-labs/avalanchego/snow/networking/tracker"
 	"github.com/ava-labs/avalanchego/staking"
 	"github.com/ava-labs/avalanchego/trace"
+	"github.com/ava-labs/avalanchego/utils/clockskew"
 	"github.com/ava-labs/avalanchego/utils/constants"
 	"github.com/ava-labs/avalanchego/utils/crypto/bls"
 	"github.com/ava-labs/avalanchego/utils/dynamicip"
+	"github.com/ava-labs/avalanchego/utils/gc"
 	"github.com/ava-labs/avalanchego/utils/ips"
 	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/maintenance"
 	"github.com/ava-labs/avalanchego/utils/password"
 	"github.com/ava-labs/avalanchego/utils/perms"
 	"github.com/ava-labs/avalanchego/utils/profiler"
+	"github.com/ava-labs/avalanchego/utils/resource"
 	"github.com/ava-labs/avalanchego/utils/storage"
 	"github.com/ava-labs/avalanchego/utils/timer"
 	"github.com/ava-labs/avalanchego/vms"
+	"github.com/ava-labs/avalanchego/vms/avm"
+	"github.com/ava-labs/avalanchego/vms/platformvm"
 	"github.com/ava-labs/avalanchego/vms/platformvm/reward"
 	"github.com/ava-labs/avalanchego/vms/proposervm"
+	"github.com/ava-labs/avalanchego/vms/rpcchainvm"
 )
 
 const (
 	pluginsDirName       = "plugins"
 	chainConfigFileName  = "config"
 	chainUpgradeFileName = "upgrade"
+	chainGRPCFileName    = "grpc"
+	chainCacheFileName   = "cache"
 	subnetConfigFileExt  = ".json"
 )
 
@@ -64,6 +84,7 @@ var (
 
 	errInvalidStakerWeights          = errors.New("staking weights must be positive")
 	errStakingDisableOnPublicNetwork = errors.New("staking disabled on public network")
+	errStakingSeedOnPublicNetwork    = errors.New("staking seed used on public network")
 	errAuthPasswordTooWeak           = errors.New("API auth password is not strong enough")
 	errInvalidUptimeRequirement      = errors.New("uptime requirement must be in the range [0, 1]")
 	errMinValidatorStakeAboveMax     = errors.New("minimum validator stake can't be greater than maximum validator stake")
@@ -77,8 +98,25 @@ var (
 	errStakingKeyContentUnset        = fmt.Errorf("%s key not set but %s set", StakingTLSKeyContentKey, StakingCertContentKey)
 	errStakingCertContentUnset       = fmt.Errorf("%s key set but %s not set", StakingTLSKeyContentKey, StakingCertContentKey)
 	errTracingEndpointEmpty          = fmt.Errorf("%s cannot be empty", TracingEndpointKey)
+	errUnknownPluginService          = errors.New("unknown plugin service")
 )
 
+// NodeConfigOption provides the parts of the node config that can't be set by
+// flags, such as the services implemented by the program running the node.
+type NodeConfigOption func(*node.Config)
+
+// WithPluginService provides the plugin service created by [factory] under
+// [name], so that chains can be configured to serve it to their plugins with
+// the ChainPluginServicesKey flag.
+func WithPluginService(name string, factory rpcchainvm.PluginServiceFactory) NodeConfigOption {
+	return func(config *node.Config) {
+		if config.PluginServices == nil {
+			config.PluginServices = rpcchainvm.PluginServices{}
+		}
+		config.PluginServices[name] = factory
+	}
+}
+
 func GetRunnerConfig(v *viper.Viper) (runner.Config, error) {
 	config := runner.Config{
 		DisplayVersionAndExit: v.GetBool(VersionKey),
@@ -210,9 +248,10 @@ func getIPCConfig(v *viper.Viper) node.IPCConfig {
 
 func getHTTPConfig(v *viper.Viper) (node.HTTPConfig, error) {
 	var (
-		httpsKey  []byte
-		httpsCert []byte
-		err       error
+		httpsKey    []byte
+		httpsCert   []byte
+		httpsConfig server.TLSConfig
+		err         error
 	)
 	switch {
 	case v.IsSet(HTTPSKeyContentKey):
@@ -226,6 +265,7 @@ func getHTTPConfig(v *viper.Viper) (node.HTTPConfig, error) {
 		if httpsKey, err = os.ReadFile(filepath.Clean(httpsKeyFilepath)); err != nil {
 			return node.HTTPConfig{}, err
 		}
+		httpsConfig.KeyFile = filepath.Clean(httpsKeyFilepath)
 	}
 
 	switch {
@@ -240,6 +280,32 @@ func getHTTPConfig(v *viper.Viper) (node.HTTPConfig, error) {
 		if httpsCert, err = os.ReadFile(filepath.Clean(httpsCertFilepath)); err != nil {
 			return node.HTTPConfig{}, err
 		}
+		httpsConfig.CertFile = filepath.Clean(httpsCertFilepath)
+	}
+	// The certificate is only reloaded if both the certificate and the key
+	// are read from files.
+	if httpsConfig.CertFile == "" || httpsConfig.KeyFile == "" {
+		httpsConfig.CertFile = ""
+		httpsConfig.KeyFile = ""
+	} else {
+		httpsConfig.ReloadFrequency = v.GetDuration(HTTPSReloadFrequencyKey)
+	}
+
+	switch {
+	case v.IsSet(HTTPSClientCAContentKey):
+		rawContent := v.GetString(HTTPSClientCAContentKey)
+		httpsConfig.ClientCAs, err = base64.StdEncoding.DecodeString(rawContent)
+		if err != nil {
+			return node.HTTPConfig{}, fmt.Errorf("unable to decode base64 content: %w", err)
+		}
+	case v.IsSet(HTTPSClientCAFileKey):
+		httpsClientCAFilepath := GetExpandedArg(v, HTTPSClientCAFileKey)
+		if httpsConfig.ClientCAs, err = os.ReadFile(filepath.Clean(httpsClientCAFilepath)); err != nil {
+			return node.HTTPConfig{}, err
+		}
+	}
+	if err := httpsConfig.Verify(); err != nil {
+		return node.HTTPConfig{}, fmt.Errorf("invalid HTTPs config: %w", err)
 	}
 
 	config := node.HTTPConfig{
@@ -248,21 +314,76 @@ func getHTTPConfig(v *viper.Viper) (node.HTTPConfig, error) {
 				IndexAPIEnabled:      v.GetBool(IndexEnabledKey),
 				IndexAllowIncomplete: v.GetBool(IndexAllowIncompleteKey),
 			},
-			AdminAPIEnabled:    v.GetBool(AdminAPIEnabledKey),
-			InfoAPIEnabled:     v.GetBool(InfoAPIEnabledKey),
-			KeystoreAPIEnabled: v.GetBool(KeystoreAPIEnabledKey),
-			MetricsAPIEnabled:  v.GetBool(MetricsAPIEnabledKey),
-			HealthAPIEnabled:   v.GetBool(HealthAPIEnabledKey),
+			AdminAPIEnabled:            v.GetBool(AdminAPIEnabledKey),
+			DebugAPIEnabled:            v.GetBool(DebugAPIEnabledKey),
+			EventsAPIEnabled:           v.GetBool(EventsAPIEnabledKey),
+			OpenAPIEnabled:             v.GetBool(OpenAPIEnabledKey),
+			InfoAPIEnabled:             v.GetBool(InfoAPIEnabledKey),
+			InfoAPIAttestationEnabled:  v.GetBool(InfoAPIAttestationEnabledKey),
+			InfoAPIAttestationTSMDir:   GetExpandedArg(v, InfoAPIAttestationTSMDirKey),
+			KeystoreAPIEnabled:         v.GetBool(KeystoreAPIEnabledKey),
+			KeystoreSessionsAPIEnabled: v.GetBool(KeystoreSessionsAPIEnabledKey),
+			MetricsAPIEnabled:          v.GetBool(MetricsAPIEnabledKey),
+			HealthAPIEnabled:           v.GetBool(HealthAPIEnabledKey),
+
+			EventsAPIConfig: server.EventsConfig{
+				MaxConnections:   v.GetInt(EventsAPIMaxConnectionsKey),
+				MaxPendingEvents: v.GetInt(EventsAPIMaxPendingEventsKey),
+				MaxSubscriptions: v.GetInt(EventsAPIMaxSubscriptionsKey),
+			},
+
+			ResponseSigningEnabled: v.GetBool(APIResponseSigningEnabledKey),
+			ResponseSigningMethods: v.GetStringSlice(APIResponseSigningMethodsKey),
+
+			MetricsMaxSeriesPerNamespace: v.GetInt(MetricsMaxSeriesPerNamespaceKey),
 		},
 		HTTPHost:          v.GetString(HTTPHostKey),
 		HTTPPort:          uint16(v.GetUint(HTTPPortKey)),
 		HTTPSEnabled:      v.GetBool(HTTPSEnabledKey),
 		HTTPSKey:          httpsKey,
 		HTTPSCert:         httpsCert,
+		HTTPSConfig:       httpsConfig,
 		APIAllowedOrigins: v.GetStringSlice(HTTPAllowedOrigins),
 
 		ShutdownTimeout: v.GetDuration(HTTPShutdownTimeoutKey),
 		ShutdownWait:    v.GetDuration(HTTPShutdownWaitKey),
+
+		ShadowConfig: shadow.Config{
+			Upstream:      v.GetString(HTTPShadowUpstreamKey),
+			Percentage:    v.GetFloat64(HTTPShadowPercentageKey),
+			Timeout:       v.GetDuration(HTTPShadowTimeoutKey),
+			MaxConcurrent: v.GetInt(HTTPShadowMaxConcurrentKey),
+		},
+
+		AccessLogConfig: accesslog.Config{
+			Enabled:       v.GetBool(HTTPAccessLogEnabledKey),
+			Percentage:    v.GetFloat64(HTTPAccessLogPercentageKey),
+			ExcludedPaths: v.GetStringSlice(HTTPAccessLogExcludedPathsKey),
+		},
+
+		HTTP2Config: server.HTTP2Config{
+			Enabled:              v.GetBool(HTTP2EnabledKey),
+			H2CEnabled:           v.GetBool(HTTP2H2CEnabledKey),
+			MaxConcurrentStreams: v.GetUint32(HTTP2MaxConcurrentStreamsKey),
+			MaxReadFrameSize:     v.GetUint32(HTTP2MaxReadFrameSizeKey),
+			IdleTimeout:          v.GetDuration(HTTP2IdleTimeoutKey),
+		},
+	}
+	if err := config.ShadowConfig.Verify(); err != nil {
+		return node.HTTPConfig{}, fmt.Errorf("invalid request shadowing config: %w", err)
+	}
+	if err := config.AccessLogConfig.Verify(); err != nil {
+		return node.HTTPConfig{}, fmt.Errorf("invalid access log config: %w", err)
+	}
+	if err := config.HTTP2Config.Verify(); err != nil {
+		return node.HTTPConfig{}, fmt.Errorf("invalid HTTP/2 config: %w", err)
+	}
+	if config.MetricsMaxSeriesPerNamespace < 0 {
+		return node.HTTPConfig{}, fmt.Errorf("%q must be non-negative", MetricsMaxSeriesPerNamespaceKey)
+	}
+	config.MaxPageSizes, err = getMaxPageSizes(v)
+	if err != nil {
+		return node.HTTPConfig{}, err
 	}
 
 	config.APIAuthConfig, err = getAPIAuthConfig(v)
@@ -270,9 +391,340 @@ func getHTTPConfig(v *viper.Viper) (node.HTTPConfig, error) {
 		return node.HTTPConfig{}, err
 	}
 	config.IPCConfig = getIPCConfig(v)
+
+	config.HTTPScopedListeners, err = getHTTPScopedListeners(v, config.HTTPHost, config.HTTPPort)
+	if err != nil {
+		return node.HTTPConfig{}, err
+	}
+	config.HTTPAdminListener, err = getHTTPAdminListener(v, config.HTTPPort, config.HTTPScopedListeners)
+	if err != nil {
+		return node.HTTPConfig{}, err
+	}
+	config.HTTPBatchConfig = server.BatchConfig{
+		Paths:          v.GetStringSlice(HTTPBatchPathsKey),
+		MaxSize:        v.GetInt(HTTPBatchMaxSizeKey),
+		MaxConcurrency: v.GetInt(HTTPBatchMaxConcurrencyKey),
+	}
+	if err := config.HTTPBatchConfig.Verify(); err != nil {
+		return node.HTTPConfig{}, fmt.Errorf("invalid batch config: %w", err)
+	}
+	config.HTTPRateLimits, err = getHTTPRateLimits(v)
+	if err != nil {
+		return node.HTTPConfig{}, err
+	}
+	config.HTTPMaxRequestBodySize = v.GetInt64(HTTPMaxRequestBodySizeKey)
+	if config.HTTPMaxRequestBodySize < 0 {
+		return node.HTTPConfig{}, fmt.Errorf("%q must be non-negative", HTTPMaxRequestBodySizeKey)
+	}
+	config.HTTPRequestBodySizeLimits, err = getHTTPRequestBodySizeLimits(v)
+	if err != nil {
+		return node.HTTPConfig{}, err
+	}
+	if config.EventsAPIEnabled {
+		if err := config.EventsAPIConfig.Verify(); err != nil {
+			return node.HTTPConfig{}, fmt.Errorf("invalid events API config: %w", err)
+		}
+	}
+	config.HTTPResponseCacheConfig = server.ResponseCacheConfig{
+		MaxBytes: v.GetInt(HTTPResponseCacheSizeKey),
+		TTL:      v.GetDuration(HTTPResponseCacheTTLKey),
+	}
+	if config.HTTPResponseCacheConfig.MaxBytes < 0 {
+		return node.HTTPConfig{}, fmt.Errorf("%q must be non-negative", HTTPResponseCacheSizeKey)
+	}
+	if err := config.HTTPResponseCacheConfig.Verify(); err != nil {
+		return node.HTTPConfig{}, fmt.Errorf("invalid response cache config: %w", err)
+	}
+	config.HTTPChainRequestTimeout = v.GetDuration(HTTPChainRequestTimeoutKey)
+	if config.HTTPChainRequestTimeout < 0 {
+		return node.HTTPConfig{}, fmt.Errorf("%q must be non-negative", HTTPChainRequestTimeoutKey)
+	}
+	config.HTTPChainRequestTimeouts, err = getHTTPChainRequestTimeouts(v)
+	if err != nil {
+		return node.HTTPConfig{}, err
+	}
 	return config, nil
 }
 
+func getHTTPChainRequestTimeouts(v *viper.Viper) ([]server.RouteTimeout, error) {
+	timeouts := []server.RouteTimeout{}
+	if err := json.Unmarshal([]byte(v.GetString(HTTPChainRequestTimeoutsKey)), &timeouts); err != nil {
+		return nil, fmt.Errorf("couldn't parse %q: %w", HTTPChainRequestTimeoutsKey, err)
+	}
+	for i, timeout := range timeouts {
+		if err := timeout.Verify(); err != nil {
+			return nil, fmt.Errorf("%q: invalid timeout %d: %w", HTTPChainRequestTimeoutsKey, i, err)
+		}
+	}
+	return timeouts, nil
+}
+
+func getHTTPRequestBodySizeLimits(v *viper.Viper) ([]server.BodySizeLimit, error) {
+	limits := []server.BodySizeLimit{}
+	if err := json.Unmarshal([]byte(v.GetString(HTTPRequestBodySizeLimitsKey)), &limits); err != nil {
+		return nil, fmt.Errorf("couldn't parse %q: %w", HTTPRequestBodySizeLimitsKey, err)
+	}
+	for i, limit := range limits {
+		if err := limit.Verify(); err != nil {
+			return nil, fmt.Errorf("%q: invalid body size limit %d: %w", HTTPRequestBodySizeLimitsKey, i, err)
+		}
+	}
+	return limits, nil
+}
+
+func getHTTPRateLimits(v *viper.Viper) ([]server.RateLimit, error) {
+	limits := []server.RateLimit{}
+	if err := json.Unmarshal([]byte(v.GetString(HTTPRateLimitsKey)), &limits); err != nil {
+		return nil, fmt.Errorf("couldn't parse %q: %w", HTTPRateLimitsKey, err)
+	}
+	for i, limit := range limits {
+		if err := limit.Verify(); err != nil {
+			return nil, fmt.Errorf("%q: invalid rate limit %d: %w", HTTPRateLimitsKey, i, err)
+		}
+	}
+	return limits, nil
+}
+
+func getHTTPAdminListener(v *viper.Viper, httpPort uint16, scopedListeners []server.ScopeConfig) (server.AdminListenerConfig, error) {
+	config := server.AdminListenerConfig{
+		Host:   v.GetString(HTTPAdminHostKey),
+		Port:   uint16(v.GetUint(HTTPAdminPortKey)),
+		Socket: GetExpandedArg(v, HTTPAdminSocketKey),
+		Paths:  v.GetStringSlice(HTTPAdminPathsKey),
+	}
+	if err := config.Verify(); err != nil {
+		return server.AdminListenerConfig{}, fmt.Errorf("invalid admin listener config: %w", err)
+	}
+	if config.Socket != "" || config.Port == 0 {
+		return config, nil
+	}
+	if config.Port == httpPort {
+		return server.AdminListenerConfig{}, fmt.Errorf("%q must differ from %q", HTTPAdminPortKey, HTTPPortKey)
+	}
+	for i, listener := range scopedListeners {
+		if listener.Port == config.Port {
+			return server.AdminListenerConfig{}, fmt.Errorf("%q is already used by scoped listener %d", HTTPAdminPortKey, i)
+		}
+	}
+	return config, nil
+}
+
+func getHTTPScopedListeners(v *viper.Viper, httpHost string, httpPort uint16) ([]server.ScopeConfig, error) {
+	listeners := []server.ScopeConfig{}
+	if err := json.Unmarshal([]byte(v.GetString(HTTPScopedListenersKey)), &listeners); err != nil {
+		return nil, fmt.Errorf("couldn't parse %q: %w", HTTPScopedListenersKey, err)
+	}
+	for i, listener := range listeners {
+		if err := listener.Verify(); err != nil {
+			return nil, fmt.Errorf("%q: invalid listener %d: %w", HTTPScopedListenersKey, i, err)
+		}
+		if listenersConflict(listener.Host, listener.Port, httpHost, httpPort) {
+			return nil, fmt.Errorf("%q: address %s of listener %d is already used by %q",
+				HTTPScopedListenersKey,
+				net.JoinHostPort(listener.Host, fmt.Sprint(listener.Port)),
+				i,
+				HTTPPortKey,
+			)
+		}
+		for j, other := range listeners[:i] {
+			if listenersConflict(listener.Host, listener.Port, other.Host, other.Port) {
+				return nil, fmt.Errorf("%q: address %s of listener %d is already used by listener %d",
+					HTTPScopedListenersKey,
+					net.JoinHostPort(listener.Host, fmt.Sprint(listener.Port)),
+					i,
+					j,
+				)
+			}
+		}
+	}
+	return listeners, nil
+}
+
+// listenersConflict returns true if listeners bound to [host1]:[port1] and to
+// [host2]:[port2] can't both be bound. Port 0 is picked by the OS when the
+// listener is bound, so it never conflicts, and an unspecified host binds
+// every address of its port.
+func listenersConflict(host1 string, port1 uint16, host2 string, port2 uint16) bool {
+	if port1 == 0 || port2 == 0 || port1 != port2 {
+		return false
+	}
+	return host1 == host2 || isUnspecifiedHost(host1) || isUnspecifiedHost(host2)
+}
+
+func isUnspecifiedHost(host string) bool {
+	if host == "" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsUnspecified()
+}
+
+func getChainAuditVMs(v *viper.Viper) (map[string]string, error) {
+	auditVMs := map[string]string{}
+	if err := json.Unmarshal([]byte(v.GetString(ChainAuditVMsKey)), &auditVMs); err != nil {
+		return nil, fmt.Errorf("couldn't parse %q: %w", ChainAuditVMsKey, err)
+	}
+	return auditVMs, nil
+}
+
+func getChainAppResponseMaxSizes(v *viper.Viper) (map[string]int, error) {
+	maxSizes := map[string]int{}
+	if err := json.Unmarshal([]byte(v.GetString(ChainAppResponseMaxSizesKey)), &maxSizes); err != nil {
+		return nil, fmt.Errorf("couldn't parse %q: %w", ChainAppResponseMaxSizesKey, err)
+	}
+	for chain, maxSize := range maxSizes {
+		if maxSize < 0 {
+			return nil, fmt.Errorf("%q: max size of chain %q must be >= 0", ChainAppResponseMaxSizesKey, chain)
+		}
+	}
+	return maxSizes, nil
+}
+
+func getChainToEngineChannelSizes(v *viper.Viper) (map[string]int, error) {
+	sizes := map[string]int{}
+	if err := json.Unmarshal([]byte(v.GetString(ChainToEngineChannelSizesKey)), &sizes); err != nil {
+		return nil, fmt.Errorf("couldn't parse %q: %w", ChainToEngineChannelSizesKey, err)
+	}
+	for chain, size := range sizes {
+		if size <= 0 {
+			return nil, fmt.Errorf("%q: queue size of chain %q must be > 0", ChainToEngineChannelSizesKey, chain)
+		}
+	}
+	return sizes, nil
+}
+
+func getChainBuildPacing(v *viper.Viper) (map[string]block.BuildPacing, error) {
+	pacing := map[string]block.BuildPacing{}
+	if err := json.Unmarshal([]byte(v.GetString(ChainBuildPacingKey)), &pacing); err != nil {
+		return nil, fmt.Errorf("couldn't parse %q: %w", ChainBuildPacingKey, err)
+	}
+	for chain, p := range pacing {
+		if err := p.Verify(); err != nil {
+			return nil, fmt.Errorf("%q: invalid pacing of chain %q: %w", ChainBuildPacingKey, chain, err)
+		}
+	}
+	return pacing, nil
+}
+
+func getChainReadReplicas(v *viper.Viper) (map[string]replicas.Config, error) {
+	configs := map[string]replicas.Config{}
+	if err := json.Unmarshal([]byte(v.GetString(ChainReadReplicasKey)), &configs); err != nil {
+		return nil, fmt.Errorf("couldn't parse %q: %w", ChainReadReplicasKey, err)
+	}
+	for chain, config := range configs {
+		if err := config.Verify(); err != nil {
+			return nil, fmt.Errorf("%q: invalid read replicas of chain %q: %w", ChainReadReplicasKey, chain, err)
+		}
+	}
+	return configs, nil
+}
+
+func getChainGCConfigs(v *viper.Viper) (gc.ChainConfigs, error) {
+	configs := gc.ChainConfigs{}
+	if err := json.Unmarshal([]byte(v.GetString(ChainGCConfigsKey)), &configs); err != nil {
+		return nil, fmt.Errorf("couldn't parse %q: %w", ChainGCConfigsKey, err)
+	}
+	for chain, config := range configs {
+		if err := config.Verify(); err != nil {
+			return nil, fmt.Errorf("%q: config of chain %q: %w", ChainGCConfigsKey, chain, err)
+		}
+	}
+	return configs, nil
+}
+
+// getChainPluginServices returns the plugin services served to each chain's
+// plugin. Every service must be one of the provided [services].
+func getChainPluginServices(v *viper.Viper, services rpcchainvm.PluginServices) (rpcchainvm.ChainPluginServices, error) {
+	chainServices := rpcchainvm.ChainPluginServices{}
+	if err := json.Unmarshal([]byte(v.GetString(ChainPluginServicesKey)), &chainServices); err != nil {
+		return nil, fmt.Errorf("couldn't parse %q: %w", ChainPluginServicesKey, err)
+	}
+	for chain, names := range chainServices {
+		for _, name := range names {
+			if _, ok := services[name]; !ok {
+				return nil, fmt.Errorf("%w %q of chain %q", errUnknownPluginService, name, chain)
+			}
+		}
+	}
+	return chainServices, nil
+}
+
+func getChainResourceLimits(v *viper.Viper) (resource.ChainLimits, error) {
+	limits := resource.ChainLimits{}
+	if err := json.Unmarshal([]byte(v.GetString(ChainResourceLimitsKey)), &limits); err != nil {
+		return nil, fmt.Errorf("couldn't parse %q: %w", ChainResourceLimitsKey, err)
+	}
+	for chain, chainLimits := range limits {
+		if err := chainLimits.Verify(); err != nil {
+			return nil, fmt.Errorf("%q: limits of chain %q: %w", ChainResourceLimitsKey, chain, err)
+		}
+	}
+	return limits, nil
+}
+
+// getExternalVMs returns the addresses of the external VMs, and the TLS config
+// the node connects to them with.
+func getExternalVMs(v *viper.Viper) (map[string]string, *tls.Config, error) {
+	externalVMs := map[string]string{}
+	if err := json.Unmarshal([]byte(v.GetString(ExternalVMsKey)), &externalVMs); err != nil {
+		return nil, nil, fmt.Errorf("couldn't parse %q: %w", ExternalVMsKey, err)
+	}
+	if len(externalVMs) == 0 {
+		return nil, nil, nil
+	}
+
+	certPath := GetExpandedArg(v, ExternalVMTLSCertFileKey)
+	keyPath := GetExpandedArg(v, ExternalVMTLSKeyFileKey)
+	caPath := GetExpandedArg(v, ExternalVMTLSCAFileKey)
+	if certPath == "" || keyPath == "" || caPath == "" {
+		return nil, nil, fmt.Errorf("%s, %s and %s must be set to connect to external VMs",
+			ExternalVMTLSCertFileKey,
+			ExternalVMTLSKeyFileKey,
+			ExternalVMTLSCAFileKey,
+		)
+	}
+	cert, err := tls.LoadX509KeyPair(certPath, keyPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("couldn't read external VM certificate: %w", err)
+	}
+	caBytes, err := os.ReadFile(caPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("couldn't read external VM certificate authorities: %w", err)
+	}
+	certPool := x509.NewCertPool()
+	if !certPool.AppendCertsFromPEM(caBytes) {
+		return nil, nil, fmt.Errorf("%s holds no PEM certificate", ExternalVMTLSCAFileKey)
+	}
+
+	// The config is used both to dial the VMs and to serve them.
+	return externalVMs, &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		RootCAs:      certPool,
+		ClientCAs:    certPool,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		MinVersion:   tls.VersionTLS12,
+	}, nil
+}
+
+// maxPageSizeBounds are the largest page sizes that the paginated endpoints
+// of the node can serve.
+var maxPageSizeBounds = indexer.MaxPageSizeBounds.
+	Override(info.MaxPageSizeBounds).
+	Override(avm.MaxPageSizeBounds).
+	Override(platformvm.MaxPageSizeBounds).
+	Override(statediff.MaxPageSizeBounds)
+
+func getMaxPageSizes(v *viper.Viper) (pagination.Limits, error) {
+	limits := pagination.Limits{}
+	if err := json.Unmarshal([]byte(v.GetString(APIMaxPageSizesKey)), &limits); err != nil {
+		return nil, fmt.Errorf("couldn't parse %q: %w", APIMaxPageSizesKey, err)
+	}
+	if err := limits.Verify(maxPageSizeBounds); err != nil {
+		return nil, fmt.Errorf("invalid %q: %w", APIMaxPageSizesKey, err)
+	}
+	return limits, nil
+}
+
 func getRouterHealthConfig(v *viper.Viper, halflife time.Duration) (router.HealthConfig, error) {
 	config := router.HealthConfig{
 		MaxDropRate:            v.GetFloat64(RouterHealthMaxDropRateKey),
@@ -377,6 +829,7 @@ func getNetworkConfig(v *viper.Viper, halflife time.Duration) (network.Config, e
 			MaxTimeSinceMsgReceived:      v.GetDuration(NetworkHealthMaxTimeSinceMsgReceivedKey),
 			MaxPortionSendQueueBytesFull: v.GetFloat64(NetworkHealthMaxPortionSendQueueFillKey),
 			MinConnectedPeers:            v.GetUint(NetworkHealthMinPeersKey),
+			MinConnectedStake:            v.GetFloat64(NetworkHealthMinConnStakeKey),
 			MaxSendFailRate:              v.GetFloat64(NetworkHealthMaxSendFailRateKey),
 			SendFailRateHalflife:         halflife,
 		},
@@ -406,14 +859,24 @@ func getNetworkConfig(v *viper.Viper, halflife time.Duration) (network.Config, e
 			InitialReconnectDelay: v.GetDuration(NetworkInitialReconnectDelayKey),
 		},
 
+		ConnectivityConfig: network.ConnectivityConfig{
+			TargetConnectedStake:   v.GetFloat64(NetworkTargetConnStakeKey),
+			ConnectivityRepairFreq: v.GetDuration(NetworkConnRepairFreqKey),
+			MaxRepairDialsPerSec:   v.GetFloat64(NetworkMaxRepairDialsPerSecKey),
+		},
+
 		MaxClockDifference:           v.GetDuration(NetworkMaxClockDifferenceKey),
 		CompressionEnabled:           v.GetBool(NetworkCompressionEnabledKey),
 		PingFrequency:                v.GetDuration(NetworkPingFrequencyKey),
 		AllowPrivateIPs:              v.GetBool(NetworkAllowPrivateIPsKey),
+		NetworkName:                  v.GetString(NetworkPartitionNameKey),
+		VMVersionTelemetryEnabled:    v.GetBool(NetworkVMVersionTelemetryKey),
 		UptimeMetricFreq:             v.GetDuration(UptimeMetricFreqKey),
 		MaximumInboundMessageTimeout: v.GetDuration(NetworkMaximumInboundTimeoutKey),
 
 		RequireValidatorToConnect: v.GetBool(NetworkRequireValidatorToConnectKey),
+		PeerPolicyAddr:            v.GetString(NetworkPeerPolicyAddrKey),
+		PeerPolicyTimeout:         v.GetDuration(NetworkPeerPolicyTimeoutKey),
 		PeerReadBufferSize:        int(v.GetUint(NetworkPeerReadBufferSizeKey)),
 		PeerWriteBufferSize:       int(v.GetUint(NetworkPeerWriteBufferSizeKey)),
 	}
@@ -427,6 +890,8 @@ func getNetworkConfig(v *viper.Viper, halflife time.Duration) (network.Config, e
 		return network.Config{}, fmt.Errorf("%s must be in [0,1]", NetworkHealthMaxSendFailRateKey)
 	case config.HealthConfig.MaxPortionSendQueueBytesFull < 0 || config.HealthConfig.MaxPortionSendQueueBytesFull > 1:
 		return network.Config{}, fmt.Errorf("%s must be in [0,1]", NetworkHealthMaxPortionSendQueueFillKey)
+	case config.HealthConfig.MinConnectedStake < 0 || config.HealthConfig.MinConnectedStake > 1:
+		return network.Config{}, fmt.Errorf("%s must be in [0,1]", NetworkHealthMinConnStakeKey)
 	case config.DialerConfig.ConnectionTimeout < 0:
 		return network.Config{}, fmt.Errorf("%q must be >= 0", OutboundConnectionTimeoutKey)
 	case config.PeerListGossipFreq < 0:
@@ -437,6 +902,14 @@ func getNetworkConfig(v *viper.Viper, halflife time.Duration) (network.Config, e
 		return network.Config{}, fmt.Errorf("%s must be >= 0", NetworkInitialReconnectDelayKey)
 	case config.MaxReconnectDelay < config.InitialReconnectDelay:
 		return network.Config{}, fmt.Errorf("%s must be >= %s", NetworkMaxReconnectDelayKey, NetworkInitialReconnectDelayKey)
+	case config.TargetConnectedStake < 0 || config.TargetConnectedStake > 1:
+		return network.Config{}, fmt.Errorf("%s must be in [0,1]", NetworkTargetConnStakeKey)
+	case config.PeerPolicyTimeout <= 0:
+		return network.Config{}, fmt.Errorf("%s must be > 0", NetworkPeerPolicyTimeoutKey)
+	case config.ConnectivityRepairFreq <= 0:
+		return network.Config{}, fmt.Errorf("%s must be > 0", NetworkConnRepairFreqKey)
+	case config.TargetConnectedStake > 0 && config.MaxRepairDialsPerSec <= 0:
+		return network.Config{}, fmt.Errorf("%s must be > 0", NetworkMaxRepairDialsPerSecKey)
 	case config.PingPongTimeout < 0:
 		return network.Config{}, fmt.Errorf("%s must be >= 0", NetworkPingTimeoutKey)
 	case config.PingFrequency < 0:
@@ -643,6 +1116,20 @@ func getProfilerConfig(v *viper.Viper) (profiler.Config, error) {
 	return config, nil
 }
 
+func getMaintenanceConfig(v *viper.Viper) (maintenance.Config, error) {
+	config := maintenance.Config{
+		Windows:        []maintenance.Window{},
+		AnnounceBefore: v.GetDuration(MaintenanceAnnounceBeforeKey),
+	}
+	if err := json.Unmarshal([]byte(v.GetString(MaintenanceWindowsKey)), &config.Windows); err != nil {
+		return maintenance.Config{}, fmt.Errorf("couldn't parse %q: %w", MaintenanceWindowsKey, err)
+	}
+	if err := config.Verify(); err != nil {
+		return maintenance.Config{}, fmt.Errorf("invalid maintenance config: %w", err)
+	}
+	return config, nil
+}
+
 func getStakingTLSCertFromFlag(v *viper.Viper) (tls.Certificate, error) {
 	stakingKeyRawContent := v.GetString(StakingTLSKeyContentKey)
 	stakingKeyContent, err := base64.StdEncoding.DecodeString(stakingKeyRawContent)
@@ -772,6 +1259,22 @@ func getStakingSigner(v *viper.Viper) (*bls.SecretKey, error) {
 	return key, nil
 }
 
+// getSeededStakingKeys derives the staking TLS certificate and the staking
+// signer from the configured seed, so that test networks have stable node IDs
+// without persisting any keys.
+func getSeededStakingKeys(v *viper.Viper) (tls.Certificate, *bls.SecretKey, error) {
+	seed := []byte(v.GetString(StakingSeedKey))
+	cert, err := staking.NewTLSCertFromSeed(seed)
+	if err != nil {
+		return tls.Certificate{}, nil, fmt.Errorf("couldn't derive staking key/cert from seed: %w", err)
+	}
+	key, err := bls.NewSecretKeyFromSeed(seed)
+	if err != nil {
+		return tls.Certificate{}, nil, fmt.Errorf("couldn't derive signing key from seed: %w", err)
+	}
+	return *cert, key, nil
+}
+
 func getStakingConfig(v *viper.Viper, networkID uint32) (node.StakingConfig, error) {
 	config := node.StakingConfig{
 		EnableStaking:         v.GetBool(StakingEnabledKey),
@@ -789,13 +1292,23 @@ func getStakingConfig(v *viper.Viper, networkID uint32) (node.StakingConfig, err
 	}
 
 	var err error
-	config.StakingTLSCert, err = getStakingTLSCert(v)
-	if err != nil {
-		return node.StakingConfig{}, err
-	}
-	config.StakingSigningKey, err = getStakingSigner(v)
-	if err != nil {
-		return node.StakingConfig{}, err
+	if v.IsSet(StakingSeedKey) {
+		if networkID == constants.MainnetID || networkID == constants.FujiID {
+			return node.StakingConfig{}, errStakingSeedOnPublicNetwork
+		}
+		config.StakingTLSCert, config.StakingSigningKey, err = getSeededStakingKeys(v)
+		if err != nil {
+			return node.StakingConfig{}, err
+		}
+	} else {
+		config.StakingTLSCert, err = getStakingTLSCert(v)
+		if err != nil {
+			return node.StakingConfig{}, err
+		}
+		config.StakingSigningKey, err = getStakingSigner(v)
+		if err != nil {
+			return node.StakingConfig{}, err
+		}
 	}
 	if networkID != constants.MainnetID && networkID != constants.FujiID {
 		config.UptimeRequirement = v.GetFloat64(UptimeRequirementKey)
@@ -911,7 +1424,8 @@ func getDatabaseConfig(v *viper.Viper, networkID uint32) (node.DatabaseConfig, e
 			GetExpandedArg(v, DBPathKey),
 			constants.NetworkName(networkID),
 		),
-		Config: configBytes,
+		Config:        configBytes,
+		MigrateDryRun: v.GetBool(MigrateDryRunKey),
 	}, nil
 }
 
@@ -959,12 +1473,11 @@ func getChainAliases(v *viper.Viper) (map[ids.ID][]string, error) {
 	return getAliases(v, "chain aliases", ChainAliasesContentKey, ChainAliasesFileKey)
 }
 
-func getVMManager(v *viper.Viper) (vms.Manager, error) {
-	vmAliases, err := getVMAliases(v)
-	if err != nil {
-		return nil, err
-	}
+func getChainRetiredAliases(v *viper.Viper) (map[ids.ID][]string, error) {
+	return getAliases(v, "chain retired aliases", ChainRetiredAliasesContentKey, ChainRetiredAliasesFileKey)
+}
 
+func getVMManager(vmAliases map[ids.ID][]string) (vms.Manager, error) {
 	manager := vms.NewManager()
 	for vmID, aliases := range vmAliases {
 		for _, alias := range aliases {
@@ -976,6 +1489,32 @@ func getVMManager(v *viper.Viper) (vms.Manager, error) {
 	return manager, nil
 }
 
+// getConfiguredVMAliases returns the aliases given by [manager] to the VMs
+// aliased in [vmAliases] or in [profile].
+func getConfiguredVMAliases(vmAliases map[ids.ID][]string, profile Profile, manager vms.Manager) (map[ids.ID][]string, error) {
+	vmIDs := ids.Set{}
+	for vmID := range vmAliases {
+		vmIDs.Add(vmID)
+	}
+	for vmIDStr := range profile.VMAliases {
+		vmID, err := ids.FromString(vmIDStr)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't parse vmID %q: %w", vmIDStr, err)
+		}
+		vmIDs.Add(vmID)
+	}
+
+	configuredAliases := make(map[ids.ID][]string, vmIDs.Len())
+	for vmID := range vmIDs {
+		aliases, err := manager.Aliases(vmID)
+		if err != nil {
+			return nil, err
+		}
+		configuredAliases[vmID] = aliases
+	}
+	return configuredAliases, nil
+}
+
 // getPathFromDirKey reads flag value from viper instance and then checks the folder existence
 func getPathFromDirKey(v *viper.Viper, configKey string) (string, error) {
 	configDir := GetExpandedArg(v, configKey)
@@ -1059,9 +1598,23 @@ func readChainConfigPath(chainConfigPath string) (map[string]chains.ChainConfig,
 			return chainConfigMap, err
 		}
 
+		// chainconfigdir/chainId/grpc.*
+		grpcData, err := storage.ReadFileWithName(chainDir, chainGRPCFileName)
+		if err != nil {
+			return chainConfigMap, err
+		}
+
+		// chainconfigdir/chainId/cache.*
+		cacheData, err := storage.ReadFileWithName(chainDir, chainCacheFileName)
+		if err != nil {
+			return chainConfigMap, err
+		}
+
 		chainConfigMap[dirInfo.Name()] = chains.ChainConfig{
 			Config:  configData,
 			Upgrade: upgradeData,
+			GRPC:    grpcData,
+			Cache:   cacheData,
 		}
 	}
 	return chainConfigMap, nil
@@ -1192,6 +1745,33 @@ func getDiskSpaceConfig(v *viper.Viper) (requiredAvailableDiskSpace uint64, warn
 	}
 }
 
+func getClockSkewConfig(v *viper.Viper) (clockskew.Config, error) {
+	config := clockskew.Config{
+		WarnThreshold:     v.GetDuration(ClockSkewWarnThresholdKey),
+		CriticalThreshold: v.GetDuration(ClockSkewCriticalThresholdKey),
+		MinPeers:          int(v.GetUint(ClockSkewMinPeersKey)),
+		NTPServer:         v.GetString(ClockSkewNTPServerKey),
+		NTPFrequency:      v.GetDuration(ClockSkewNTPFrequencyKey),
+		NTPTimeout:        v.GetDuration(ClockSkewNTPTimeoutKey),
+	}
+	if err := config.Verify(); err != nil {
+		return clockskew.Config{}, fmt.Errorf("invalid clock skew config: %w", err)
+	}
+	return config, nil
+}
+
+func getChainDataDirQuotaConfig(v *viper.Viper) (quota.Config, error) {
+	config := quota.Config{
+		MaxSize:      v.GetUint64(ChainDataDirQuotaKey),
+		WarningRatio: v.GetFloat64(ChainDataDirQuotaWarningRatioKey),
+		Policy:       quota.Policy(v.GetString(ChainDataDirQuotaPolicyKey)),
+	}
+	if err := config.Verify(); err != nil {
+		return quota.Config{}, fmt.Errorf("invalid chain data directory quota: %w", err)
+	}
+	return config, nil
+}
+
 func getDiskTargeterConfig(v *viper.Viper) (tracker.TargeterConfig, error) {
 	vdrAlloc := v.GetFloat64(DiskVdrAllocKey)
 	maxNonVdrUsage := v.GetFloat64(DiskMaxNonVdrUsageKey)
@@ -1243,8 +1823,11 @@ func getTraceConfig(v *viper.Viper) (trace.Config, error) {
 	}, nil
 }
 
-func GetNodeConfig(v *viper.Viper, buildDir string) (node.Config, error) {
+func GetNodeConfig(v *viper.Viper, buildDir string, opts ...NodeConfigOption) (node.Config, error) {
 	nodeConfig := node.Config{}
+	for _, opt := range opts {
+		opt(&nodeConfig)
+	}
 
 	// Plugin directory defaults to [buildDir]/[pluginsDirName]
 	nodeConfig.PluginDir = filepath.Join(buildDir, pluginsDirName)
@@ -1266,8 +1849,17 @@ func GetNodeConfig(v *viper.Viper, buildDir string) (node.Config, error) {
 	}
 
 	nodeConfig.UseCurrentHeight = v.GetBool(ProposerVMUseCurrentHeightKey)
+	nodeConfig.ValidatorSnapshotEpochLength = v.GetUint64(ValidatorSnapshotEpochLengthKey)
+	nodeConfig.PlatformStateDiffsEnabled = v.GetBool(PlatformStateDiffsEnabledKey)
+
+	// Profiles
+	profile, profileNames, err := getProfile(v)
+	if err != nil {
+		return node.Config{}, fmt.Errorf("couldn't apply profiles: %w", err)
+	}
+	nodeConfig.Profiles = profileNames
+	applyProfileDefaults(v, profile)
 
-	var err error
 	// Logging
 	nodeConfig.LoggingConfig, err = getLoggingConfig(v)
 	if err != nil {
@@ -1303,6 +1895,7 @@ func GetNodeConfig(v *viper.Viper, buildDir string) (node.Config, error) {
 	if err != nil {
 		return node.Config{}, err
 	}
+	nodeConfig.WhitelistedSubnets.Add(profile.TrackedSubnets...)
 
 	// HTTP APIs
 	nodeConfig.HTTPConfig, err = getHTTPConfig(v)
@@ -1330,6 +1923,7 @@ func GetNodeConfig(v *viper.Viper, buildDir string) (node.Config, error) {
 
 	// Metrics
 	nodeConfig.MeterVMEnabled = v.GetBool(MeterVMsEnabledKey)
+	nodeConfig.PeerQueryLatencyMetricsSize = int(v.GetUint(PeerQueryLatencyMetricsSizeKey))
 
 	// Adaptive Timeout Config
 	nodeConfig.AdaptiveTimeoutConfig, err = getAdaptiveTimeoutConfig(v)
@@ -1353,6 +1947,56 @@ func GetNodeConfig(v *viper.Viper, buildDir string) (node.Config, error) {
 
 	// File Descriptor Limit
 	nodeConfig.FdLimit = v.GetUint64(FdLimitKey)
+	nodeConfig.PreflightChecksEnabled = v.GetBool(PreflightChecksEnabledKey)
+
+	// Garbage Collection
+	nodeConfig.GCConfig = gc.Config{
+		GCPercent:   v.GetInt(GCPercentKey),
+		MemoryLimit: v.GetUint64(GCMemoryLimitKey),
+	}
+	if err := nodeConfig.GCConfig.Verify(); err != nil {
+		return node.Config{}, fmt.Errorf("%s: %w", GCPercentKey, err)
+	}
+	nodeConfig.GCBallastSize = v.GetUint64(GCBallastSizeKey)
+	nodeConfig.ChainGCConfigs, err = getChainGCConfigs(v)
+	if err != nil {
+		return node.Config{}, err
+	}
+	nodeConfig.PluginMultiplexEnabled = v.GetBool(PluginMultiplexKey)
+	nodeConfig.PluginUnixSocketsEnabled = v.GetBool(PluginUnixSocketsKey)
+	nodeConfig.PluginMTLSEnabled = v.GetBool(PluginMTLSKey)
+	nodeConfig.PluginDBIteratorPrefetch = int(v.GetUint(PluginDBIteratorPrefetchKey))
+	nodeConfig.PluginDBMaxBatchBytes = int(v.GetUint(PluginDBMaxBatchBytesKey))
+	nodeConfig.PluginDBChecksumsEnabled = v.GetBool(PluginDBChecksumsKey)
+	nodeConfig.PluginCgroupRoot = GetExpandedArg(v, PluginCgroupRootKey)
+	nodeConfig.ChainResourceLimits, err = getChainResourceLimits(v)
+	if err != nil {
+		return node.Config{}, err
+	}
+	if len(nodeConfig.ChainResourceLimits) > 0 && nodeConfig.PluginCgroupRoot == "" {
+		return node.Config{}, fmt.Errorf("%s must be set to limit the resources of chains", PluginCgroupRootKey)
+	}
+	nodeConfig.ChainPluginServices, err = getChainPluginServices(v, nodeConfig.PluginServices)
+	if err != nil {
+		return node.Config{}, err
+	}
+	nodeConfig.PluginShutdownDrainTimeout = v.GetDuration(PluginShutdownDrainTimeoutKey)
+	if nodeConfig.PluginShutdownDrainTimeout < 0 {
+		return node.Config{}, fmt.Errorf("%s must be >= 0", PluginShutdownDrainTimeoutKey)
+	}
+	nodeConfig.PluginRPCRetry = rpcchainvm.RetryConfig{
+		MaxAttempts:    int(v.GetUint(PluginRPCRetryMaxAttemptsKey)),
+		InitialBackoff: v.GetDuration(PluginRPCRetryInitialBackoffKey),
+		MaxBackoff:     v.GetDuration(PluginRPCRetryMaxBackoffKey),
+	}
+	if err := nodeConfig.PluginRPCRetry.Verify(); err != nil {
+		return node.Config{}, fmt.Errorf("invalid plugin rpc retry config: %w", err)
+	}
+	nodeConfig.ExternalVMs, nodeConfig.ExternalVMTLSConfig, err = getExternalVMs(v)
+	if err != nil {
+		return node.Config{}, err
+	}
+	nodeConfig.ExternalVMListenHost = v.GetString(ExternalVMListenHostKey)
 
 	// Tx Fee
 	nodeConfig.TxFeeConfig = getTxFeeConfig(v, nodeConfig.NetworkID)
@@ -1380,6 +2024,9 @@ func GetNodeConfig(v *viper.Viper, buildDir string) (node.Config, error) {
 	if err != nil {
 		return node.Config{}, fmt.Errorf("couldn't read subnet configs: %w", err)
 	}
+	if err := applyProfileSubnetConfigs(v, profile, nodeConfig.WhitelistedSubnets, subnetConfigs); err != nil {
+		return node.Config{}, fmt.Errorf("couldn't apply profiles: %w", err)
+	}
 	nodeConfig.SubnetConfigs = subnetConfigs
 
 	// Node health
@@ -1403,8 +2050,25 @@ func GetNodeConfig(v *viper.Viper, buildDir string) (node.Config, error) {
 		return node.Config{}, err
 	}
 
+	// Maintenance
+	nodeConfig.MaintenanceConfig, err = getMaintenanceConfig(v)
+	if err != nil {
+		return node.Config{}, err
+	}
+
 	// VM Aliases
-	nodeConfig.VMManager, err = getVMManager(v)
+	vmAliases, err := getVMAliases(v)
+	if err != nil {
+		return node.Config{}, err
+	}
+	nodeConfig.VMManager, err = getVMManager(vmAliases)
+	if err != nil {
+		return node.Config{}, err
+	}
+	if err := applyProfileVMAliases(profile, nodeConfig.VMManager); err != nil {
+		return node.Config{}, fmt.Errorf("couldn't apply profiles: %w", err)
+	}
+	nodeConfig.VMAliases, err = getConfiguredVMAliases(vmAliases, profile, nodeConfig.VMManager)
 	if err != nil {
 		return node.Config{}, err
 	}
@@ -1413,6 +2077,56 @@ func GetNodeConfig(v *viper.Viper, buildDir string) (node.Config, error) {
 	if err != nil {
 		return node.Config{}, err
 	}
+	nodeConfig.ChainRetiredAliases, err = getChainRetiredAliases(v)
+	if err != nil {
+		return node.Config{}, err
+	}
+
+	// Chain auditing
+	nodeConfig.ChainAuditVMs, err = getChainAuditVMs(v)
+	if err != nil {
+		return node.Config{}, err
+	}
+
+	// AppResponses
+	nodeConfig.AppResponseMaxSize = int(v.GetUint(AppResponseMaxSizeKey))
+	nodeConfig.ChainAppResponseMaxSizes, err = getChainAppResponseMaxSizes(v)
+	if err != nil {
+		return node.Config{}, err
+	}
+
+	// VM notifications
+	nodeConfig.ToEngineChannelSize = int(v.GetUint(ToEngineChannelSizeKey))
+	if nodeConfig.ToEngineChannelSize == 0 {
+		return node.Config{}, fmt.Errorf("%q must be > 0", ToEngineChannelSizeKey)
+	}
+	nodeConfig.ChainToEngineChannelSizes, err = getChainToEngineChannelSizes(v)
+	if err != nil {
+		return node.Config{}, err
+	}
+
+	// Blob transfers
+	nodeConfig.BlobUploadBandwidth = v.GetUint64(BlobUploadBandwidthKey)
+	nodeConfig.BlobDownloadBandwidth = v.GetUint64(BlobDownloadBandwidthKey)
+
+	// Block building pacing
+	nodeConfig.BuildPacing = block.BuildPacing{
+		MinInterval:   v.GetDuration(BuildPacingMinIntervalKey),
+		MinPendingTxs: uint32(v.GetUint(BuildPacingMinPendingTxsKey)),
+	}
+	if err := nodeConfig.BuildPacing.Verify(); err != nil {
+		return node.Config{}, fmt.Errorf("%q: %w", BuildPacingMinIntervalKey, err)
+	}
+	nodeConfig.ChainBuildPacing, err = getChainBuildPacing(v)
+	if err != nil {
+		return node.Config{}, err
+	}
+
+	// Read replicas
+	nodeConfig.ChainReadReplicas, err = getChainReadReplicas(v)
+	if err != nil {
+		return node.Config{}, err
+	}
 
 	nodeConfig.SystemTrackerFrequency = v.GetDuration(SystemTrackerFrequencyKey)
 	nodeConfig.SystemTrackerProcessingHalflife = v.GetDuration(SystemTrackerProcessingHalflifeKey)
@@ -1424,6 +2138,45 @@ func GetNodeConfig(v *viper.Viper, buildDir string) (node.Config, error) {
 		return node.Config{}, err
 	}
 
+	// Clock skew
+	nodeConfig.ClockSkewConfig, err = getClockSkewConfig(v)
+	if err != nil {
+		return node.Config{}, err
+	}
+
+	// Stale chains
+	nodeConfig.StaleChainTimeout = v.GetDuration(StaleChainTimeoutKey)
+	if nodeConfig.StaleChainTimeout < 0 {
+		return node.Config{}, fmt.Errorf("%s must be >= 0", StaleChainTimeoutKey)
+	}
+	nodeConfig.StaleChainResyncEnabled = v.GetBool(StaleChainResyncEnabledKey)
+
+	// Decision logs
+	if v.GetBool(DecisionLogEnabledKey) {
+		nodeConfig.DecisionLogDir = GetExpandedArg(v, DecisionLogDirKey)
+	}
+	nodeConfig.DecisionLogMaxSize = v.GetUint64(DecisionLogMaxSizeKey)
+	if nodeConfig.DecisionLogMaxSize == 0 {
+		return node.Config{}, fmt.Errorf("%s must be > 0", DecisionLogMaxSizeKey)
+	}
+
+	// Hedged queries
+	nodeConfig.ConsensusHedgeDelay = v.GetDuration(ConsensusHedgeDelayKey)
+	if nodeConfig.ConsensusHedgeDelay < 0 {
+		return node.Config{}, fmt.Errorf("%s must be >= 0", ConsensusHedgeDelayKey)
+	}
+	nodeConfig.ConsensusHedgeBudget = v.GetInt(ConsensusHedgeBudgetKey)
+	if nodeConfig.ConsensusHedgeBudget < 0 {
+		return node.Config{}, fmt.Errorf("%s must be >= 0", ConsensusHedgeBudgetKey)
+	}
+
+	// Chain data
+	nodeConfig.ChainDataDir = GetExpandedArg(v, ChainDataDirKey)
+	nodeConfig.ChainDataDirQuota, err = getChainDataDirQuotaConfig(v)
+	if err != nil {
+		return node.Config{}, err
+	}
+
 	nodeConfig.CPUTargeterConfig, err = getCPUTargeterConfig(v)
 	if err != nil {
 		return node.Config{}, err
@@ -1440,6 +2193,7 @@ func GetNodeConfig(v *viper.Viper, buildDir string) (node.Config, error) {
 	}
 
 	nodeConfig.ProvidedFlags = providedFlags(v)
+	nodeConfig.ConfigOverrides = getConfigOverrides(v)
 	return nodeConfig, nil
 }
 
@@ -1456,7 +2210,7 @@ func providedFlags(v *viper.Viper) map[string]interface{} {
 	settings := v.AllSettings()
 	customSettings := make(map[string]interface{}, len(settings))
 	for key, val := range settings {
-		if v.IsSet(key) {
+		if key != commandLineFlagsKey && v.IsSet(key) {
 			customSettings[key] = val
 		}
 	}
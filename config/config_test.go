@@ -17,8 +17,12 @@ import (
 
 	"github.com/stretchr/testify/require"
 
+	"github.com/ava-labs/avalanchego/api/admin"
 	"github.com/ava-labs/avalanchego/chains"
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/node"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/vms/rpcchainvm"
 )
 
 func TestGetChainConfigsFromFiles(t *testing.T) {
@@ -578,6 +582,154 @@ func TestGetSubnetConfigsFromFlags(t *testing.T) {
 	}
 }
 
+func TestComposeProfiles(t *testing.T) {
+	subnetID, err := ids.FromString("2Ctt6eGAeo4MLqTmGa7AdRecuVMPGWEX9wSsCLBYrLhX4a394i")
+	require.NoError(t, err)
+	vmID := ids.ID{'v', 'm'}
+
+	tests := map[string]struct {
+		givenJSON   string
+		names       []string
+		expectedErr error
+		testF       func(*require.Assertions, Profile)
+	}{
+		"include": {
+			givenJSON: fmt.Sprintf(`{
+				"base": {"apis": {"admin": false}, "chainDataDirQuota": {"maxSize": 1024, "warningRatio": 0.5, "policy": "alert"}},
+				"subnet": {"include": ["base"], "trackedSubnets": ["%s"], "vmAliases": {"%s": ["plugin-v1"]}},
+				"debug": {"include": ["base"], "apis": {"debug": true}, "trackedSubnets": ["%s"]}
+			}`, subnetID, vmID, subnetID),
+			names: []string{"subnet", "debug"},
+			testF: func(require *require.Assertions, profile Profile) {
+				require.Equal([]ids.ID{subnetID}, profile.TrackedSubnets)
+				require.Equal(map[string][]string{vmID.String(): {"plugin-v1"}}, profile.VMAliases)
+				require.Equal(map[string]bool{"admin": false, "debug": true}, profile.APIs)
+				require.EqualValues(1024, profile.ChainDataDirQuota.MaxSize)
+			},
+		},
+		"unknown profile": {
+			givenJSON:   `{"base": {}}`,
+			names:       []string{"subnet"},
+			expectedErr: errUnknownProfile,
+		},
+		"cycle": {
+			givenJSON:   `{"a": {"include": ["b"]}, "b": {"include": ["a"]}}`,
+			names:       []string{"a"},
+			expectedErr: errProfileCycle,
+		},
+		"conflicting subnet configs": {
+			givenJSON: fmt.Sprintf(`{
+				"a": {"subnetConfigs": {"%s": {"validatorOnly": true}}},
+				"b": {"subnetConfigs": {"%s": {"validatorOnly": false}}}
+			}`, subnetID, subnetID),
+			names:       []string{"a", "b"},
+			expectedErr: errProfileConflict,
+		},
+		"equal subnet configs": {
+			givenJSON: fmt.Sprintf(`{
+				"a": {"subnetConfigs": {"%s": {"validatorOnly": true}}},
+				"b": {"subnetConfigs": {"%s": { "validatorOnly" : true }}}
+			}`, subnetID, subnetID),
+			names: []string{"a", "b"},
+			testF: func(require *require.Assertions, profile Profile) {
+				require.Len(profile.SubnetConfigs, 1)
+			},
+		},
+		"conflicting apis": {
+			givenJSON:   `{"a": {"apis": {"admin": true}}, "b": {"apis": {"admin": false}}}`,
+			names:       []string{"a", "b"},
+			expectedErr: errProfileConflict,
+		},
+		"unknown api": {
+			givenJSON:   `{"a": {"apis": {"wallet": true}}}`,
+			names:       []string{"a"},
+			expectedErr: errUnknownAPI,
+		},
+		"primary network": {
+			givenJSON:   `{"a": {"trackedSubnets": ["11111111111111111111111111111111LpoYY"]}}`,
+			names:       []string{"a"},
+			expectedErr: errCannotWhitelistPrimaryNetwork,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			require := require.New(t)
+
+			v := setupViperFlags()
+			v.Set(ProfilesContentKey, base64.StdEncoding.EncodeToString([]byte(test.givenJSON)))
+			profiles, err := getProfiles(v)
+			require.NoError(err)
+
+			profile, err := composeProfiles(profiles, test.names)
+			require.ErrorIs(err, test.expectedErr)
+			if test.testF != nil {
+				test.testF(require, profile)
+			}
+		})
+	}
+}
+
+func TestApplyProfile(t *testing.T) {
+	require := require.New(t)
+
+	subnetID, err := ids.FromString("2Ctt6eGAeo4MLqTmGa7AdRecuVMPGWEX9wSsCLBYrLhX4a394i")
+	require.NoError(err)
+	profile := Profile{
+		TrackedSubnets: []ids.ID{subnetID},
+		SubnetConfigs: map[string]json.RawMessage{
+			subnetID.String(): []byte(`{"validatorOnly": true}`),
+		},
+		APIs: map[string]bool{
+			"admin": true,
+			"info":  false,
+		},
+	}
+
+	v := setupViperFlags()
+	// Explicitly provided settings take precedence over the profile.
+	v.Set(InfoAPIEnabledKey, true)
+	applyProfileDefaults(v, profile)
+	require.True(v.GetBool(AdminAPIEnabledKey))
+	require.True(v.GetBool(InfoAPIEnabledKey))
+
+	subnetConfigs := make(map[ids.ID]chains.SubnetConfig)
+	err = applyProfileSubnetConfigs(v, profile, ids.Set{}, subnetConfigs)
+	require.ErrorIs(err, errUntrackedSubnet)
+
+	trackedSubnets := ids.Set{}
+	trackedSubnets.Add(profile.TrackedSubnets...)
+	require.NoError(applyProfileSubnetConfigs(v, profile, trackedSubnets, subnetConfigs))
+	require.True(subnetConfigs[subnetID].ValidatorOnly)
+	require.Equal(20, subnetConfigs[subnetID].ConsensusParameters.K)
+}
+
+func TestGetConfiguredVMAliases(t *testing.T) {
+	require := require.New(t)
+
+	fileVMID := ids.GenerateTestID()
+	profileVMID := ids.GenerateTestID()
+	vmAliases := map[ids.ID][]string{
+		fileVMID: {"file"},
+	}
+	profile := Profile{
+		VMAliases: map[string][]string{
+			fileVMID.String():    {"file", "shared"},
+			profileVMID.String(): {"profile"},
+		},
+	}
+
+	manager, err := getVMManager(vmAliases)
+	require.NoError(err)
+	require.NoError(applyProfileVMAliases(profile, manager))
+
+	configuredAliases, err := getConfiguredVMAliases(vmAliases, profile, manager)
+	require.NoError(err)
+	require.Equal(map[ids.ID][]string{
+		fileVMID:    {"file", "shared"},
+		profileVMID: {"profile"},
+	}, configuredAliases)
+}
+
 func TestCalcMinConnectedStake(t *testing.T) {
 	v := setupViperFlags()
 	defaultParams := getConsensusConfig(v)
@@ -600,6 +752,54 @@ func setupFile(t *testing.T, path string, fileName string, value string) {
 	require.NoError(t, os.WriteFile(filePath, []byte(value), 0o600))
 }
 
+func TestGetConfigOverrides(t *testing.T) {
+	require := require.New(t)
+
+	configFilePath := filepath.Join(t.TempDir(), "config.json")
+	setupFile(t, filepath.Dir(configFilePath), filepath.Base(configFilePath), `{
+		"http-port": 9652,
+		"log-level": "info",
+		"staking-port": 9653
+	}`)
+	t.Setenv("AVAGO_HTTP_HOST", "0.0.0.0")
+	t.Setenv("AVAGO_STAKING_PORT", "9654")
+
+	v, err := BuildViper(BuildFlagSet(), []string{
+		"--" + ConfigFileKey + "=" + configFilePath,
+		"--" + StakingPortKey + "=9655",
+	})
+	require.NoError(err)
+
+	require.Equal([]admin.ConfigOverride{
+		{
+			Key:     ConfigFileKey,
+			Value:   configFilePath,
+			Default: "",
+			Source:  admin.ConfigSourceFlag,
+		},
+		{
+			Key:     HTTPHostKey,
+			Value:   "0.0.0.0",
+			Default: "127.0.0.1",
+			Source:  admin.ConfigSourceEnv,
+		},
+		{
+			Key:     HTTPPortKey,
+			Value:   float64(9652),
+			Default: "9650",
+			Source:  admin.ConfigSourceFile,
+		},
+		{
+			Key:     StakingPortKey,
+			Value:   "9655",
+			Default: "9651",
+			Source:  admin.ConfigSourceFlag,
+		},
+	}, getConfigOverrides(v))
+	// The recorded command line flags aren't reported as provided flags.
+	require.NotContains(providedFlags(v), commandLineFlagsKey)
+}
+
 func setupViperFlags() *viper.Viper {
 	v := viper.New()
 	fs := BuildFlagSet()
@@ -629,3 +829,174 @@ func setupViper(configFilePath string) *viper.Viper {
 	}
 	return v
 }
+
+func TestGetChainPluginServices(t *testing.T) {
+	services := rpcchainvm.PluginServices{
+		"signer": func(*snow.Context) (rpcchainvm.PluginService, error) {
+			return rpcchainvm.PluginService{}, nil
+		},
+	}
+	tests := []struct {
+		name        string
+		flag        string
+		expectedErr error
+	}{
+		{
+			name: "provided service",
+			flag: `{"C":["signer"]}`,
+		},
+		{
+			name:        "unknown service",
+			flag:        `{"C":["signer","unknown"]}`,
+			expectedErr: errUnknownPluginService,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require := require.New(t)
+
+			v := setupViperFlags()
+			v.Set(ChainPluginServicesKey, test.flag)
+			chainServices, err := getChainPluginServices(v, services)
+			require.ErrorIs(err, test.expectedErr)
+			if test.expectedErr == nil {
+				require.Equal(rpcchainvm.ChainPluginServices{"C": {"signer"}}, chainServices)
+			}
+		})
+	}
+}
+
+func TestWithPluginService(t *testing.T) {
+	nodeConfig := node.Config{}
+	WithPluginService("signer", func(*snow.Context) (rpcchainvm.PluginService, error) {
+		return rpcchainvm.PluginService{}, nil
+	})(&nodeConfig)
+	require.Contains(t, nodeConfig.PluginServices, "signer")
+}
+
+func TestGetMaxPageSizes(t *testing.T) {
+	tests := []struct {
+		name      string
+		flag      string
+		expectErr bool
+	}{
+		{
+			name: "within bound",
+			flag: `{"index.getContainerRange":1024,"info.peers":512,"avm.getUTXOs":1024,"platform.getUTXOs":256}`,
+		},
+		{
+			name:      "exceeds bound",
+			flag:      `{"index.getContainerRange":1025}`,
+			expectErr: true,
+		},
+		{
+			name:      "exceeds avm bound",
+			flag:      `{"avm.getUTXOs":18446744073709551615}`,
+			expectErr: true,
+		},
+		{
+			name:      "exceeds platform bound",
+			flag:      `{"platform.getUTXOs":1025}`,
+			expectErr: true,
+		},
+		{
+			name:      "exceeds info bound",
+			flag:      `{"info.peers":4096}`,
+			expectErr: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			v := setupViperFlags()
+			v.Set(APIMaxPageSizesKey, test.flag)
+			_, err := getMaxPageSizes(v)
+			if test.expectErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestGetHTTPScopedListeners(t *testing.T) {
+	tests := []struct {
+		name      string
+		flag      string
+		expectErr bool
+	}{
+		{
+			name: "distinct ports",
+			flag: `[{"port":9660,"chains":["X"]},{"port":9670,"chains":["P"]}]`,
+		},
+		{
+			name:      "http port",
+			flag:      `[{"port":9650,"chains":["X"]}]`,
+			expectErr: true,
+		},
+		{
+			name: "http port on another host",
+			flag: `[{"host":"10.0.0.1","port":9650,"chains":["X"]}]`,
+		},
+		{
+			name:      "same port",
+			flag:      `[{"host":"10.0.0.1","port":9660,"chains":["X"]},{"host":"10.0.0.1","port":9660,"chains":["P"]}]`,
+			expectErr: true,
+		},
+		{
+			name: "same port on different hosts",
+			flag: `[{"host":"10.0.0.1","port":9660,"chains":["X"]},{"host":"10.0.0.2","port":9660,"chains":["P"]}]`,
+		},
+		{
+			name:      "same port on an unspecified host",
+			flag:      `[{"host":"10.0.0.1","port":9660,"chains":["X"]},{"host":"0.0.0.0","port":9660,"chains":["P"]}]`,
+			expectErr: true,
+		},
+		{
+			name: "ephemeral ports",
+			flag: `[{"port":0,"chains":["X"]},{"port":0,"chains":["P"]}]`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			v := setupViperFlags()
+			v.Set(HTTPScopedListenersKey, test.flag)
+			_, err := getHTTPScopedListeners(v, "127.0.0.1", 9650)
+			if test.expectErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestGetChainGCConfigs(t *testing.T) {
+	tests := []struct {
+		name      string
+		flag      string
+		expectErr bool
+	}{
+		{
+			name: "gc off with memory limit",
+			flag: `{"X":{"gcPercent":-1,"memoryLimit":1073741824}}`,
+		},
+		{
+			name:      "gc off without memory limit",
+			flag:      `{"X":{"gcPercent":-1}}`,
+			expectErr: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			v := setupViperFlags()
+			v.Set(ChainGCConfigsKey, test.flag)
+			_, err := getChainGCConfigs(v)
+			if test.expectErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
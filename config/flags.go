@@ -10,16 +10,21 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"strings"
 	"time"
 
 	"github.com/kardianos/osext"
 
 	"github.com/spf13/viper"
 
+	"github.com/ava-labs/avalanchego/api/server"
+	"github.com/ava-labs/avalanchego/api/signing"
+	"github.com/ava-labs/avalanchego/chains/quota"
 	"github.com/ava-labs/avalanchego/database/leveldb"
 	"github.com/ava-labs/avalanchego/database/memdb"
 	"github.com/ava-labs/avalanchego/genesis"
 	"github.com/ava-labs/avalanchego/trace"
+	"github.com/ava-labs/avalanchego/utils/attestation"
 	"github.com/ava-labs/avalanchego/utils/constants"
 	"github.com/ava-labs/avalanchego/utils/ulimit"
 	"github.com/ava-labs/avalanchego/utils/units"
@@ -35,20 +40,24 @@ const (
 
 var (
 	// [defaultUnexpandedDataDir] will be expanded when reading the flags
-	defaultDataDir              = filepath.Join("$HOME", ".avalanchego")
-	defaultDBDir                = filepath.Join(defaultUnexpandedDataDir, "db")
-	defaultLogDir               = filepath.Join(defaultUnexpandedDataDir, "logs")
-	defaultProfileDir           = filepath.Join(defaultUnexpandedDataDir, "profiles")
-	defaultStakingPath          = filepath.Join(defaultUnexpandedDataDir, "staking")
-	defaultStakingTLSKeyPath    = filepath.Join(defaultStakingPath, "staker.key")
-	defaultStakingCertPath      = filepath.Join(defaultStakingPath, "staker.crt")
-	defaultStakingSignerKeyPath = filepath.Join(defaultStakingPath, "signer.key")
-	defaultConfigDir            = filepath.Join(defaultUnexpandedDataDir, "configs")
-	defaultChainConfigDir       = filepath.Join(defaultConfigDir, "chains")
-	defaultVMConfigDir          = filepath.Join(defaultConfigDir, "vms")
-	defaultVMAliasFilePath      = filepath.Join(defaultVMConfigDir, "aliases.json")
-	defaultChainAliasFilePath   = filepath.Join(defaultChainConfigDir, "aliases.json")
-	defaultSubnetConfigDir      = filepath.Join(defaultConfigDir, "subnets")
+	defaultDataDir                   = filepath.Join("$HOME", ".avalanchego")
+	defaultDBDir                     = filepath.Join(defaultUnexpandedDataDir, "db")
+	defaultChainDataDir              = filepath.Join(defaultUnexpandedDataDir, "chainData")
+	defaultDecisionLogDir            = filepath.Join(defaultUnexpandedDataDir, "decisions")
+	defaultLogDir                    = filepath.Join(defaultUnexpandedDataDir, "logs")
+	defaultProfileDir                = filepath.Join(defaultUnexpandedDataDir, "profiles")
+	defaultStakingPath               = filepath.Join(defaultUnexpandedDataDir, "staking")
+	defaultStakingTLSKeyPath         = filepath.Join(defaultStakingPath, "staker.key")
+	defaultStakingCertPath           = filepath.Join(defaultStakingPath, "staker.crt")
+	defaultStakingSignerKeyPath      = filepath.Join(defaultStakingPath, "signer.key")
+	defaultConfigDir                 = filepath.Join(defaultUnexpandedDataDir, "configs")
+	defaultChainConfigDir            = filepath.Join(defaultConfigDir, "chains")
+	defaultVMConfigDir               = filepath.Join(defaultConfigDir, "vms")
+	defaultVMAliasFilePath           = filepath.Join(defaultVMConfigDir, "aliases.json")
+	defaultChainAliasFilePath        = filepath.Join(defaultChainConfigDir, "aliases.json")
+	defaultChainRetiredAliasFilePath = filepath.Join(defaultChainConfigDir, "retired_aliases.json")
+	defaultSubnetConfigDir           = filepath.Join(defaultConfigDir, "subnets")
+	defaultProfilesFilePath          = filepath.Join(defaultConfigDir, "profiles.json")
 
 	// Places to look for the build directory
 	defaultBuildDirs = []string{}
@@ -87,6 +96,29 @@ func addNodeFlags(fs *flag.FlagSet) {
 	fs.String(DataDirKey, defaultDataDir, "Sets the base data directory where default sub-directories will be placed unless otherwise specified.")
 	// System
 	fs.Uint64(FdLimitKey, ulimit.DefaultFDLimit, "Attempts to raise the process file descriptor limit to at least this value and error if the value is above the system max")
+	fs.Bool(PreflightChecksEnabledKey, true, "If true, the node verifies its environment before starting, such as that its ports are free, its clock is set, its database isn't in use and its disk is fast enough, and exits with instructions to fix any issue found")
+	fs.Int(GCPercentKey, 0, "Garbage collection target percentage of the node process, as in GOGC. If 0, the runtime's default is used. If negative, garbage collection only runs when the memory limit is reached, which must then be set")
+	fs.Uint64(GCMemoryLimitKey, 0, "Soft memory limit of the node process in bytes, as in GOMEMLIMIT. If 0, no limit is set")
+	fs.Uint64(GCBallastSizeKey, 0, "Size in bytes of a memory ballast allocated by the node process to reduce the frequency of garbage collection. The ballast doesn't consume physical memory")
+	fs.String(ChainGCConfigsKey, "{}", `Garbage collection configs applied to the plugin processes of chains, as a JSON map from chain ID or alias to config. Example: {"C":{"gcPercent":200,"memoryLimit":8589934592}}`)
+	fs.Bool(PluginMultiplexKey, false, "If true, plugins connect to the database and services of their chain over a single connection instead of one connection per server. Requires plugins that support multiplexing")
+	fs.Bool(PluginUnixSocketsKey, false, "If true, the node and its plugins communicate over unix domain sockets rather than TCP loopback")
+	fs.Bool(PluginMTLSKey, false, "If true, the node and its plugins authenticate each other with ephemeral TLS certificates, so that no other process can connect to them. Requires plugins that support mutual TLS")
+	fs.Uint(PluginDBIteratorPrefetchKey, 4, "Number of pages of elements plugins fetch ahead when iterating over their databases. The elements are streamed to the plugin rather than fetched with a request per page. If 0, each page is fetched with a separate request")
+	fs.Uint(PluginDBMaxBatchBytesKey, 128*units.KiB, "Max size, in bytes, of the elements plugins send in a single request when writing a batch to their databases. Larger values are split across requests. If 0, values are never split and plugins use their default size")
+	fs.Bool(PluginDBChecksumsKey, false, "If true, plugins send and request a checksum with each value written to and read from their databases, so that values corrupted in transit are detected")
+	fs.String(PluginCgroupRootKey, "", fmt.Sprintf("Cgroup v2 directory delegated to the node, with the cpu and memory controllers enabled, under which the plugin processes of the chains with limits in %s are placed. Only supported on linux", ChainResourceLimitsKey))
+	fs.String(ChainResourceLimitsKey, "{}", fmt.Sprintf(`Resource limits of the plugin processes of chains, as a JSON map from chain ID or alias to limits. The CPU limit is a number of cores, and the memory limit a number of bytes. Requires %s. Example: {"C":{"cpu":2,"memory":8589934592}}`, PluginCgroupRootKey))
+	fs.String(ChainPluginServicesKey, "{}", `Additional gRPC services the node serves to the plugins of chains, as a JSON map from chain ID or alias to the names of the services. The services must be provided by the program running the node, or the config is rejected. Example: {"C":["signer"]}`)
+	fs.Duration(PluginShutdownDrainTimeoutKey, 5*time.Second, "Max duration the pending requests of a plugin to the databases and services of the node are given to finish when the plugin shuts down, before the plugin is killed. If 0, the pending requests are cancelled")
+	fs.Uint(PluginRPCRetryMaxAttemptsKey, 3, "Max number of times the node calls a plugin to get or parse a block or to gather its metrics, if the plugin is unreachable. If <= 1, the calls aren't retried")
+	fs.Duration(PluginRPCRetryInitialBackoffKey, 100*time.Millisecond, "Delay before the first retry of a call to a plugin. The delay doubles with each retry of the call")
+	fs.Duration(PluginRPCRetryMaxBackoffKey, time.Second, "Max delay before a retry of a call to a plugin")
+	fs.String(ExternalVMsKey, "{}", `VMs that run outside of the node, such as in other containers or on other hosts, rather than as plugins launched by the node. Specified as a JSON map from vmID or alias to the address the VM serves at. An external VM takes precedence over a plugin of the same VM. Example: {"subnetevm":"10.0.0.2:9000"}`)
+	fs.String(ExternalVMListenHostKey, "127.0.0.1", "Host the servers that external VMs connect back to listen on. It must be reachable by the external VMs")
+	fs.String(ExternalVMTLSCertFileKey, "", fmt.Sprintf("Path to the PEM certificate the node authenticates to external VMs with. Required if %s is set", ExternalVMsKey))
+	fs.String(ExternalVMTLSKeyFileKey, "", fmt.Sprintf("Path to the PEM private key of %s", ExternalVMTLSCertFileKey))
+	fs.String(ExternalVMTLSCAFileKey, "", fmt.Sprintf("Path to the PEM certificate authorities the certificates of external VMs are verified against. Required if %s is set", ExternalVMsKey))
 
 	// Config File
 	fs.String(ConfigFileKey, "", fmt.Sprintf("Specifies a config file. Ignored if %s is specified", ConfigContentKey))
@@ -117,6 +149,7 @@ func addNodeFlags(fs *flag.FlagSet) {
 	fs.String(DBPathKey, defaultDBDir, "Path to database directory")
 	fs.String(DBConfigFileKey, "", fmt.Sprintf("Path to database config file. Ignored if %s is specified", DBConfigContentKey))
 	fs.String(DBConfigContentKey, "", "Specifies base64 encoded database config content")
+	fs.Bool(MigrateDryRunKey, false, "If true, the node reports the migrations pending for its database and exits without applying them")
 
 	// Logging
 	fs.String(LogsDirKey, defaultLogDir, "Logging directory for Avalanche")
@@ -168,8 +201,18 @@ func addNodeFlags(fs *flag.FlagSet) {
 
 	fs.Bool(NetworkCompressionEnabledKey, true, "If true, compress certain outbound messages. This node will be able to parse compressed inbound messages regardless of this flag's value")
 	fs.Duration(NetworkMaxClockDifferenceKey, time.Minute, "Max allowed clock difference value between this node and peers")
+	fs.Duration(ClockSkewWarnThresholdKey, 2*time.Second, "Estimated skew of the local clock at which a warning is reported by the clock skew health check")
+	fs.Duration(ClockSkewCriticalThresholdKey, 5*time.Second, "Estimated skew of the local clock at which the clock skew health check fails")
+	fs.Uint(ClockSkewMinPeersKey, 5, "Number of peers whose reported times are required to estimate the skew of the local clock from peers")
+	fs.String(ClockSkewNTPServerKey, "", "Address of an NTP server the local clock is compared against, e.g. pool.ntp.org. If specified, the skew is estimated from the NTP server rather than from peers. If empty, no NTP server is queried")
+	fs.Duration(ClockSkewNTPFrequencyKey, 10*time.Minute, fmt.Sprintf("Frequency of querying %s", ClockSkewNTPServerKey))
+	fs.Duration(ClockSkewNTPTimeoutKey, 5*time.Second, fmt.Sprintf("Timeout of querying %s", ClockSkewNTPServerKey))
 	fs.Bool(NetworkAllowPrivateIPsKey, true, "Allows the node to initiate outbound connection attempts to peers with private IPs")
+	fs.String(NetworkPartitionNameKey, "", "Name of the network sent in the peer handshake. This node only connects to peers sending the same name, which partitions networks that share a network ID. If empty, this node only connects to peers that don't send a name")
+	fs.Bool(NetworkVMVersionTelemetryKey, false, "If true, this node reports the versions of its VMs to its peers, and reports the share of the stake of each subnet running each version of a VM, as reported by its peers")
 	fs.Bool(NetworkRequireValidatorToConnectKey, false, "If true, this node will only maintain a connection with another node if this node is a validator, the other node is a validator, or the other node is a beacon")
+	fs.String(NetworkPeerPolicyAddrKey, "", "Address of a gRPC peer policy server that is consulted before connecting to a peer and once its handshake is complete. Either a unix domain socket, as unix://<path>, or a TLS server authenticated by the root certificates of the system. If empty, no peer policy is consulted")
+	fs.Duration(NetworkPeerPolicyTimeoutKey, 5*time.Second, "Maximum amount of time the peer policy server may take to decide whether to connect to a peer. Connections are dropped if it takes longer")
 	fs.Uint(NetworkPeerReadBufferSizeKey, 8*units.KiB, "Size, in bytes, of the buffer that we read peer messages into (there is one buffer per peer)")
 	fs.Uint(NetworkPeerWriteBufferSizeKey, 8*units.KiB, "Size, in bytes, of the buffer that we write peer messages into (there is one buffer per peer)")
 
@@ -216,21 +259,64 @@ func addNodeFlags(fs *flag.FlagSet) {
 	fs.String(HTTPSKeyContentKey, "", "Specifies base64 encoded TLS private key for the HTTPs server")
 	fs.String(HTTPSCertFileKey, "", fmt.Sprintf("TLS certificate file for the HTTPs server. Ignored if %s is specified", HTTPSCertContentKey))
 	fs.String(HTTPSCertContentKey, "", "Specifies base64 encoded TLS certificate for the HTTPs server")
+	fs.String(HTTPSClientCAFileKey, "", fmt.Sprintf("File of the PEM encoded certificates of the CAs of the HTTPs clients. If set, clients must present a certificate signed by one of the CAs. Ignored if %s is specified", HTTPSClientCAContentKey))
+	fs.String(HTTPSClientCAContentKey, "", "Specifies base64 encoded PEM certificates of the CAs of the HTTPs clients")
+	fs.Duration(HTTPSReloadFrequencyKey, time.Minute, fmt.Sprintf("Minimum duration between checks of whether %s and %s changed. Changed files are reloaded without restarting the node. Ignored if %s or %s is specified. If 0, the files aren't reloaded", HTTPSCertFileKey, HTTPSKeyFileKey, HTTPSCertContentKey, HTTPSKeyContentKey))
 	fs.String(HTTPAllowedOrigins, "*", "Origins to allow on the HTTP port. Defaults to * which allows all origins. Example: https://*.avax.network https://*.avax-test.network")
 	fs.Duration(HTTPShutdownWaitKey, 0, "Duration to wait after receiving SIGTERM or SIGINT before initiating shutdown. The /health endpoint will return unhealthy during this duration")
 	fs.Duration(HTTPShutdownTimeoutKey, 10*time.Second, "Maximum duration to wait for existing connections to complete during node shutdown")
+	fs.String(HTTPShadowUpstreamKey, "", "Base URL of an upstream, such as a node running a release candidate, that read-only API requests are duplicated to. Differences between the responses are logged and reported in metrics. If empty, requests aren't duplicated")
+	fs.Float64(HTTPShadowPercentageKey, 1, fmt.Sprintf("Percentage of read-only API requests, in [0, 100], that are duplicated to --%s", HTTPShadowUpstreamKey))
+	fs.Duration(HTTPShadowTimeoutKey, 10*time.Second, fmt.Sprintf("Maximum duration to wait for --%s to respond to a duplicated request", HTTPShadowUpstreamKey))
+	fs.Int(HTTPShadowMaxConcurrentKey, 64, fmt.Sprintf("Maximum number of outstanding requests duplicated to --%s", HTTPShadowUpstreamKey))
+	fs.Bool(HTTPAccessLogEnabledKey, false, "If true, API requests are logged to the access log, with their path, method, status, latency and source IP")
+	fs.Float64(HTTPAccessLogPercentageKey, 100, fmt.Sprintf("Percentage of API requests, in [0, 100], that are logged if %s is set", HTTPAccessLogEnabledKey))
+	fs.String(HTTPAccessLogExcludedPathsKey, "/ext/health /ext/metrics", "Space separated base paths of the APIs whose requests aren't logged to the access log")
+	fs.String(HTTPRateLimitsKey, "[]", `Limits of the rate of the API requests of each client IP, as a JSON list. Each limit applies to the requests to a base path, through any of its aliases, and to its subpaths, and the longest matching path applies. Each request of a JSON-RPC batch request counts against the limit. Clients over their limit get a 429 response with a Retry-After header. Example: [{"path":"/ext/bc/C/rpc","requestsPerSecond":10,"burst":20}]`)
+	fs.Int64(HTTPMaxRequestBodySizeKey, 16*units.MiB, fmt.Sprintf("Maximum size, in bytes, of the body of an API request to a path without a limit in %s. Larger requests get a 413 response. If 0, the bodies of these requests aren't limited", HTTPRequestBodySizeLimitsKey))
+	fs.String(HTTPRequestBodySizeLimitsKey, "[]", fmt.Sprintf(`Maximum sizes, in bytes, of the bodies of the API requests to some paths, as a JSON list. Each limit applies to the requests to a base path and its subpaths instead of %s, and the longest matching path applies. Example: [{"path":"/ext/bc/X","maxBytes":67108864}]`, HTTPMaxRequestBodySizeKey))
+	fs.Duration(HTTPChainRequestTimeoutKey, 0, fmt.Sprintf("Maximum duration of a request to a chain's API without a timeout in %s, including the time spent waiting for the chain's lock. Requests whose response didn't start before they time out get a 503 response. If 0, these requests aren't timed out", HTTPChainRequestTimeoutsKey))
+	fs.String(HTTPChainRequestTimeoutsKey, "[]", fmt.Sprintf(`Maximum durations of the requests to the APIs of chains under some paths, as a JSON list. Each timeout applies to the requests to a base path, through any of its aliases, and to its subpaths instead of %s, and the longest matching path applies. Responses that started before their timeout expired, such as streamed responses, aren't timed out. Example: [{"path":"/ext/bc/C/rpc","timeout":"30s"}]`, HTTPChainRequestTimeoutKey))
+	fs.Int(HTTPResponseCacheSizeKey, 32*units.MiB, "Maximum size, in bytes, of the cached responses to the API methods that chains mark as cacheable, such as fetching transactions by ID. If 0, responses aren't cached")
+	fs.Duration(HTTPResponseCacheTTLKey, 10*time.Minute, "Duration a cached API response is served for")
+	fs.Bool(HTTP2EnabledKey, true, fmt.Sprintf("If true, HTTP/2 is negotiated with the clients of the API listeners that use TLS, which requires %s", HTTPSEnabledKey))
+	fs.Bool(HTTP2H2CEnabledKey, false, fmt.Sprintf("If true, the API listeners that don't use TLS accept HTTP/2 without TLS (h2c). Requires %s", HTTP2EnabledKey))
+	fs.Uint(HTTP2MaxConcurrentStreamsKey, 250, "Maximum number of concurrent streams of each HTTP/2 client connection")
+	fs.Uint(HTTP2MaxReadFrameSizeKey, 1<<20, "Maximum size, in bytes, of the frames read from HTTP/2 client connections. Must be in [16KiB, 16MiB)")
+	fs.Duration(HTTP2IdleTimeoutKey, 0, "Duration after which idle HTTP/2 client connections are closed. If 0, idle connections aren't closed")
+	fs.String(HTTPScopedListenersKey, "[]", fmt.Sprintf(`Additional API listeners that only serve the APIs of some chains. Each listener serves the APIs of the chains it lists by blockchainID or alias, and of the chains of the subnets it lists. Listeners use TLS if %s is set. Specified as a JSON list. Example: [{"host":"0.0.0.0","port":9660,"chains":["C"],"subnets":["2bRCr6B4MiEfSjidDwxDpdCyviwnfUVqB2HGwhm947w9YYqb7r"]}]`, HTTPSEnabledKey))
+	fs.String(HTTPAdminHostKey, "127.0.0.1", fmt.Sprintf("Address of the listener that exclusively serves the APIs in %s. Ignored if %s is set", HTTPAdminPathsKey, HTTPAdminSocketKey))
+	fs.Uint(HTTPAdminPortKey, 0, fmt.Sprintf("Port of the listener that exclusively serves the APIs in %s. The listener uses TLS if %s is set. If 0 and %s isn't set, the APIs are served by the HTTP server", HTTPAdminPathsKey, HTTPSEnabledKey, HTTPAdminSocketKey))
+	fs.String(HTTPAdminSocketKey, "", fmt.Sprintf("Path of a unix socket that exclusively serves the APIs in %s, without TLS. Only the user running the node can connect to the socket", HTTPAdminPathsKey))
+	fs.String(HTTPAdminPathsKey, strings.Join(server.DefaultAdminPaths, " "), fmt.Sprintf("Space separated base paths of the privileged APIs. If %s or %s is set, the APIs are only served by the admin listener", HTTPAdminPortKey, HTTPAdminSocketKey))
+	fs.String(HTTPBatchPathsKey, "", "Space separated base paths of the APIs whose JSON-RPC batch requests are split into single requests, for the APIs that only handle single requests. Example: /ext/info /ext/bc/X")
+	fs.Int(HTTPBatchMaxSizeKey, 100, fmt.Sprintf("Maximum number of requests in a JSON-RPC batch request to the APIs in %s", HTTPBatchPathsKey))
+	fs.Int(HTTPBatchMaxConcurrencyKey, 8, fmt.Sprintf("Maximum number of requests of a JSON-RPC batch request to the APIs in %s that are handled concurrently", HTTPBatchPathsKey))
 	fs.Bool(APIAuthRequiredKey, false, "Require authorization token to call HTTP APIs")
 	fs.String(APIAuthPasswordFileKey, "",
 		fmt.Sprintf("Password file used to initially create/validate API authorization tokens. Ignored if %s is specified. Leading and trailing whitespace is removed from the password. Can be changed via API call",
 			APIAuthPasswordKey))
 	fs.String(APIAuthPasswordKey, "", "Specifies password for API authorization tokens")
+	fs.Bool(APIResponseSigningEnabledKey, false, fmt.Sprintf("If true, the responses to the JSON-RPC methods in --%s are signed with the node's BLS key over the request, the response and the current P-chain height. The signature is returned in the response headers", APIResponseSigningMethodsKey))
+	fs.String(APIResponseSigningMethodsKey, strings.Join(signing.DefaultMethods, " "), fmt.Sprintf("Space separated JSON-RPC methods whose responses are signed. Ignored if %s is false", APIResponseSigningEnabledKey))
 
 	// Enable/Disable APIs
 	fs.Bool(AdminAPIEnabledKey, false, "If true, this node exposes the Admin API")
+	fs.Bool(DebugAPIEnabledKey, false, "If true, this node exposes the Debug API, which decodes raw blocks and transactions of its chains")
+	fs.Bool(EventsAPIEnabledKey, false, "If true, this node exposes the event hub at /ext/events, which publishes the containers accepted by its chains to WebSocket clients")
+	fs.Int(EventsAPIMaxConnectionsKey, 1024, "Maximum number of clients connected to the event hub at once")
+	fs.Int(EventsAPIMaxPendingEventsKey, 1024, "Maximum number of events pending to be sent to a client of the event hub. Clients that fall further behind are disconnected")
+	fs.Int(EventsAPIMaxSubscriptionsKey, 64, "Maximum number of (chain, topic) pairs a client of the event hub is subscribed to")
+	fs.Bool(OpenAPIEnabledKey, true, "If true, this node serves the OpenAPI document of its APIs at /ext/openapi.json")
 	fs.Bool(InfoAPIEnabledKey, true, "If true, this node exposes the Info API")
+	fs.Bool(InfoAPIAttestationEnabledKey, false, "If true, the Info API returns hardware attestation evidence bound to the node ID. Requires the node to run in an AMD SEV-SNP or Intel TDX confidential VM")
+	fs.String(InfoAPIAttestationTSMDirKey, attestation.DefaultTSMDir, fmt.Sprintf("Directory of the configfs-tsm interface used to request attestation reports. Ignored if %s is false", InfoAPIAttestationEnabledKey))
 	fs.Bool(KeystoreAPIEnabledKey, true, "If true, this node exposes the Keystore API")
+	fs.Bool(KeystoreSessionsAPIEnabledKey, false, "If true, this node exposes the Keystore Session API, which holds exported keystore users in memory for a limited time so they can sign transactions without being stored by the node")
 	fs.Bool(MetricsAPIEnabledKey, true, "If true, this node exposes the Metrics API")
 	fs.Bool(HealthAPIEnabledKey, true, "If true, this node exposes the Health API")
+	fs.String(APIMaxPageSizesKey, "{}", "JSON map overriding the maximum number of items returned per page by the node's paginated API endpoints, which can't exceed the page sizes the endpoints serve by default, e.g. {\"platform.getUTXOs\":512}")
+	fs.Int(MetricsMaxSeriesPerNamespaceKey, 50_000, "Maximum number of series each metrics namespace, such as a chain, may report. Once exceeded, the namespace's metrics with the most series are dropped and the node reports unhealthy until they fit under the limit again. If 0, the number of series isn't limited")
 	fs.Bool(IpcAPIEnabledKey, false, "If true, IPCs can be opened")
 
 	// Health Checks
@@ -241,6 +327,7 @@ func addNodeFlags(fs *flag.FlagSet) {
 	fs.Duration(NetworkHealthMaxTimeSinceMsgReceivedKey, time.Minute, "Network layer returns unhealthy if haven't received a message for at least this much time")
 	fs.Float64(NetworkHealthMaxPortionSendQueueFillKey, 0.9, "Network layer returns unhealthy if more than this portion of the pending send queue is full")
 	fs.Uint(NetworkHealthMinPeersKey, 1, "Network layer returns unhealthy if connected to less than this many peers")
+	fs.Float64(NetworkHealthMinConnStakeKey, 0, "Network layer returns unhealthy if connected to less than this portion of the stake of the primary network or of any tracked subnet")
 	fs.Float64(NetworkHealthMaxSendFailRateKey, .9, "Network layer reports unhealthy if more than this portion of attempted message sends fail")
 	// Router Health
 	fs.Float64(RouterHealthMaxDropRateKey, 1, "Node reports unhealthy if the router drops more than this portion of messages")
@@ -258,6 +345,7 @@ func addNodeFlags(fs *flag.FlagSet) {
 	fs.Bool(StakingEphemeralSignerEnabledKey, false, "If true, the node uses an ephemeral staking signer key")
 	fs.String(StakingSignerKeyPathKey, defaultStakingSignerKeyPath, fmt.Sprintf("Path to the signer private key for staking. Ignored if %s is specified", StakingSignerKeyContentKey))
 	fs.String(StakingSignerKeyContentKey, "", "Specifies base64 encoded signer private key for staking")
+	fs.String(StakingSeedKey, "", "If set, the staking TLS key and certificate and the staking signer key are derived from this seed, giving the node a reproducible node ID. Only allowed on test networks. Overrides all other staking key flags")
 
 	fs.Uint64(StakingDisabledWeightKey, 100, "Weight to provide to each peer when staking is disabled")
 	// Uptime Requirement
@@ -312,8 +400,15 @@ func addNodeFlags(fs *flag.FlagSet) {
 	// ProposerVM
 	fs.Bool(ProposerVMUseCurrentHeightKey, false, "Have the ProposerVM always report the last accepted P-chain block height")
 
+	// Validator sets
+	fs.Uint64(ValidatorSnapshotEpochLengthKey, 1024, "Number of P-chain blocks in an epoch. The validator sets of the tracked subnets are snapshotted at the first height of each epoch, so that historical validator sets are retrieved without reconstructing them from the current validator set. If 0, validator sets aren't snapshotted")
+
+	// State diffs
+	fs.Bool(PlatformStateDiffsEnabledKey, false, "Record the changes made to the P-chain's database at each height, so that the P-chain serves the state diff between two heights from the height the recording started at")
+
 	// Metrics
 	fs.Bool(MeterVMsEnabledKey, true, "Enable Meter VMs to track VM performance with more granularity")
+	fs.Uint(PeerQueryLatencyMetricsSizeKey, 0, "Number of validators of each chain, by decreasing stake, whose consensus query latencies are recorded in a histogram labeled by peer. The latencies of the other peers are recorded under the label \"other\". If 0, per peer query latencies aren't recorded")
 	fs.Duration(UptimeMetricFreqKey, 30*time.Second, "Frequency of renewing this node's average uptime metric")
 
 	// IPC
@@ -330,22 +425,80 @@ func addNodeFlags(fs *flag.FlagSet) {
 	fs.String(SubnetConfigDirKey, defaultSubnetConfigDir, fmt.Sprintf("Subnet specific configurations parent directory. Ignored if %s is specified", SubnetConfigContentKey))
 	fs.String(SubnetConfigContentKey, "", "Specifies base64 encoded subnets configurations")
 
+	// Chain Data
+	fs.String(ChainDataDirKey, defaultChainDataDir, "Parent directory of the directories chains may use to persist data outside of their databases")
+	fs.Uint64(ChainDataDirQuotaKey, 0, "Maximum number of bytes each chain's data directory may use. If 0, no quota is enforced")
+	fs.Float64(ChainDataDirQuotaWarningRatioKey, 0.9, fmt.Sprintf("Fraction of %s above which a chain is reported as approaching its quota", ChainDataDirQuotaKey))
+	fs.String(ChainDataDirQuotaPolicyKey, string(quota.Alert), fmt.Sprintf("Action taken when a chain exceeds its data directory quota. Must be one of {%s, %s}", quota.Alert, quota.Halt))
+
 	// Profiles
 	fs.String(ProfileDirKey, defaultProfileDir, "Path to the profile directory")
 	fs.Bool(ProfileContinuousEnabledKey, false, "Whether the app should continuously produce performance profiles")
 	fs.Duration(ProfileContinuousFreqKey, 15*time.Minute, "How frequently to rotate performance profiles")
 	fs.Int(ProfileContinuousMaxFilesKey, 5, "Maximum number of historical profiles to keep")
 
+	// Maintenance
+	fs.String(MaintenanceWindowsKey, "[]", `Windows during which the node is expected to be taken down for planned maintenance. During a window, the node stops serving API requests other than the admin, health, info and metrics APIs, reports itself as unhealthy, flushes its databases and pauses continuous profiling. More windows can be scheduled through the admin API. Specified as a JSON list, with durations in nanoseconds. Example: [{"start":"2022-10-01T08:00:00Z","duration":1800000000000,"reason":"upgrade"}]`)
+	fs.Duration(MaintenanceAnnounceBeforeKey, 10*time.Minute, "How long before a maintenance window begins that the window is announced to peers")
+
 	// Aliasing
 	fs.String(VMAliasesFileKey, defaultVMAliasFilePath, fmt.Sprintf("Specifies a JSON file that maps vmIDs with custom aliases. Ignored if %s is specified", VMAliasesContentKey))
 	fs.String(VMAliasesContentKey, "", "Specifies base64 encoded maps vmIDs with custom aliases")
 	fs.String(ChainAliasesFileKey, defaultChainAliasFilePath, fmt.Sprintf("Specifies a JSON file that maps blockchainIDs with custom aliases. Ignored if %s is specified", ChainConfigContentKey))
 	fs.String(ChainAliasesContentKey, "", "Specifies base64 encoded map from blockchainID to custom aliases")
+	fs.String(ChainRetiredAliasesFileKey, defaultChainRetiredAliasFilePath, fmt.Sprintf("Specifies a JSON file that maps blockchainIDs with aliases that are no longer used. API requests to a retired alias are permanently redirected to the chain's current endpoint. Ignored if %s is specified", ChainRetiredAliasesContentKey))
+	fs.String(ChainRetiredAliasesContentKey, "", "Specifies base64 encoded map from blockchainID to retired aliases")
+
+	// AppResponses
+	fs.Uint(AppResponseMaxSizeKey, 0, "Max size, in bytes, of the AppResponses delivered to a chain's VM. Larger responses are dropped and the request is reported to the VM as failed. If 0, the size isn't limited beyond the max message size")
+	fs.String(ChainAppResponseMaxSizesKey, "{}", fmt.Sprintf(`Overrides %s for specific chains. Specified as a JSON map from blockchainID or alias to max size. Example: {"C":1048576}`, AppResponseMaxSizeKey))
+
+	// VM notifications
+	fs.Uint(ToEngineChannelSizeKey, 1, "Number of notifications a chain's VM can queue for the consensus engine. Notifications sent while the queue is full are dropped, unless they are redundant with a queued notification")
+	fs.String(ChainToEngineChannelSizesKey, "{}", fmt.Sprintf(`Overrides %s for specific chains. Specified as a JSON map from blockchainID or alias to queue size. Example: {"C":4}`, ToEngineChannelSizeKey))
+
+	// Blob transfers
+	fs.Uint64(BlobUploadBandwidthKey, 0, "Max number of bytes per second the blob services of all the chains upload to other nodes. Requests above the limit are refused, so that the requester asks another node. If 0, uploads aren't capped")
+	fs.Uint64(BlobDownloadBandwidthKey, 0, "Max number of bytes per second the blob services of all the chains download from other nodes. If 0, downloads aren't capped")
+
+	// Block building pacing
+	fs.Duration(BuildPacingMinIntervalKey, 0, "Min time between two requests of the consensus engine to build a block. PendingTxs notifications sent by the VM in the meantime are coalesced. If 0, blocks are built as soon as the VM has pending txs")
+	fs.Uint(BuildPacingMinPendingTxsKey, 0, fmt.Sprintf("Hint given to VMs of the number of txs to batch before notifying the consensus engine, unless %s has elapsed since the last block was built. Only VMs that support build pacing use this hint", BuildPacingMinIntervalKey))
+	fs.String(ChainBuildPacingKey, "{}", fmt.Sprintf(`Overrides %s and %s for specific chains. Specified as a JSON map from blockchainID or alias to pacing. The min interval is in nanoseconds. Example: {"C":{"minInterval":500000000,"minPendingTxs":16}}`, BuildPacingMinIntervalKey, BuildPacingMinPendingTxsKey))
+
+	// Read replicas
+	fs.String(ChainReadReplicasKey, "{}", `Read-only instances of the VMs of specific chains that serve a share of the read-only API requests to the chains. Only supported by snowman chains. Specified as a JSON map from blockchainID or alias to replicas. Replicas are replaced when the chain accepts a block, and only serve requests once they reflect the chain's last accepted block. The refresh interval, after which replicas that couldn't be created are created again, is in nanoseconds. Example: {"C":{"replicas":2,"refreshInterval":1000000000}}`)
+
+	// Profiles
+	fs.String(ProfilesKey, "", "Comma separated list of the profiles the node runs with. A profile bundles the tracked subnets, subnet configs, VM aliases, chain data directory quota, and enabled APIs needed to run a set of subnets. Explicitly provided settings take precedence over profiles")
+	fs.String(ProfilesFileKey, defaultProfilesFilePath, fmt.Sprintf("Specifies a JSON file that maps profile names to profiles. Ignored if %s is specified", ProfilesContentKey))
+	fs.String(ProfilesContentKey, "", "Specifies base64 encoded map from profile names to profiles")
+
+	// Stale chains
+	fs.Duration(StaleChainTimeoutKey, 5*time.Minute, "Duration after which a chain that hasn't accepted a block, while a majority of its stake accepted later blocks, is reported as stale. If 0, staleness isn't detected")
+	fs.Bool(StaleChainResyncEnabledKey, false, "If true, stale chains other than the P-chain are bootstrapped again")
+
+	// Decision logs
+	fs.Bool(DecisionLogEnabledKey, false, "If true, each chain appends the polls, votes and accept/reject decisions of its consensus engine to a log on disk, for analysis after an incident")
+	fs.String(DecisionLogDirKey, defaultDecisionLogDir, "Parent directory of the chains' decision logs")
+	fs.Uint64(DecisionLogMaxSizeKey, 64*units.MiB, "Size, in bytes, above which the oldest entries of a chain's decision log are deleted")
+
+	// Hedged queries
+	fs.Duration(ConsensusHedgeDelayKey, 0, fmt.Sprintf("Duration after which a poll that hasn't finished is sent again to up to %s backup validators sampled by stake, each of which takes a vote of a sampled validator that hasn't answered if it answers first. If 0, queries aren't hedged", ConsensusHedgeBudgetKey))
+	fs.Int(ConsensusHedgeBudgetKey, 2, "Maximum number of backup validators queried for each hedged poll")
+
+	// Auditing
+	fs.String(ChainAuditVMsKey, "{}", `Debug mode that re-verifies every block accepted by a chain with a second instance of a VM, such as a different build of the chain's VM, and reports any divergence through the chain's health check. Specified as a JSON map from blockchainID or alias to vmID or alias. Example: {"C":"evm-rc"}`)
 
 	// Delays
 	fs.Duration(NetworkInitialReconnectDelayKey, time.Second, "Initial delay duration must be waited before attempting to reconnect a peer")
 	fs.Duration(NetworkMaxReconnectDelayKey, time.Hour, "Maximum delay duration must be waited before attempting to reconnect a peer")
 
+	// Connectivity repair
+	fs.Float64(NetworkTargetConnStakeKey, 0.8, "Portion of the stake of the primary network and of each tracked subnet this node attempts to stay connected to by redialing disconnected validators before their reconnect delay expires. If 0, connectivity isn't repaired")
+	fs.Duration(NetworkConnRepairFreqKey, 30*time.Second, "Frequency to report the connected stake and to check whether it is below the target")
+	fs.Float64(NetworkMaxRepairDialsPerSecKey, 1, "Maximum number of validators redialed per second to repair connectivity")
+
 	// System resource trackers
 	fs.Duration(SystemTrackerFrequencyKey, 500*time.Millisecond, "Frequency to check the real system usage of tracked processes. More frequent checks --> usage metrics are more accurate, but more expensive to track")
 	fs.Duration(SystemTrackerProcessingHalflifeKey, 15*time.Second, "Halflife to use for the processing requests tracker. Larger halflife --> usage metrics change more slowly")
@@ -37,6 +37,7 @@ const (
 	DBPathKey                                          = "db-dir"
 	DBConfigFileKey                                    = "db-config-file"
 	DBConfigContentKey                                 = "db-config-file-content"
+	MigrateDryRunKey                                   = "migrate-dry-run"
 	PublicIPKey                                        = "public-ip"
 	PublicIPResolutionFreqKey                          = "public-ip-resolution-frequency"
 	PublicIPResolutionServiceKey                       = "public-ip-resolution-service"
@@ -51,12 +52,44 @@ const (
 	HTTPSKeyContentKey                                 = "http-tls-key-file-content"
 	HTTPSCertFileKey                                   = "http-tls-cert-file"
 	HTTPSCertContentKey                                = "http-tls-cert-file-content"
+	HTTPSClientCAFileKey                               = "http-tls-client-ca-file"
+	HTTPSClientCAContentKey                            = "http-tls-client-ca-file-content"
+	HTTPSReloadFrequencyKey                            = "http-tls-reload-frequency"
 	HTTPAllowedOrigins                                 = "http-allowed-origins"
 	HTTPShutdownTimeoutKey                             = "http-shutdown-timeout"
 	HTTPShutdownWaitKey                                = "http-shutdown-wait"
+	HTTPShadowUpstreamKey                              = "http-shadow-upstream"
+	HTTPShadowPercentageKey                            = "http-shadow-percentage"
+	HTTPShadowTimeoutKey                               = "http-shadow-timeout"
+	HTTPShadowMaxConcurrentKey                         = "http-shadow-max-concurrent"
+	HTTPAccessLogEnabledKey                            = "http-access-log-enabled"
+	HTTPAccessLogPercentageKey                         = "http-access-log-percentage"
+	HTTPAccessLogExcludedPathsKey                      = "http-access-log-excluded-paths"
+	HTTPScopedListenersKey                             = "http-scoped-listeners"
+	HTTPAdminHostKey                                   = "http-admin-host"
+	HTTPAdminPortKey                                   = "http-admin-port"
+	HTTPAdminSocketKey                                 = "http-admin-socket"
+	HTTPAdminPathsKey                                  = "http-admin-paths"
+	HTTPBatchPathsKey                                  = "http-batch-paths"
+	HTTPBatchMaxSizeKey                                = "http-batch-max-size"
+	HTTPBatchMaxConcurrencyKey                         = "http-batch-max-concurrency"
+	HTTPRateLimitsKey                                  = "http-rate-limits"
+	HTTPMaxRequestBodySizeKey                          = "http-max-request-body-size"
+	HTTPRequestBodySizeLimitsKey                       = "http-request-body-size-limits"
+	HTTPChainRequestTimeoutKey                         = "http-chain-request-timeout"
+	HTTPChainRequestTimeoutsKey                        = "http-chain-request-timeouts"
+	HTTPResponseCacheSizeKey                           = "http-response-cache-size"
+	HTTPResponseCacheTTLKey                            = "http-response-cache-ttl"
+	HTTP2EnabledKey                                    = "http2-enabled"
+	HTTP2H2CEnabledKey                                 = "http2-h2c-enabled"
+	HTTP2MaxConcurrentStreamsKey                       = "http2-max-concurrent-streams"
+	HTTP2MaxReadFrameSizeKey                           = "http2-max-read-frame-size"
+	HTTP2IdleTimeoutKey                                = "http2-idle-timeout"
 	APIAuthRequiredKey                                 = "api-auth-required"
 	APIAuthPasswordKey                                 = "api-auth-password"
 	APIAuthPasswordFileKey                             = "api-auth-password-file"
+	APIResponseSigningEnabledKey                       = "api-response-signing-enabled"
+	APIResponseSigningMethodsKey                       = "api-response-signing-methods"
 	StateSyncIPsKey                                    = "state-sync-ips"
 	StateSyncIDsKey                                    = "state-sync-ids"
 	BootstrapIPsKey                                    = "bootstrap-ips"
@@ -71,6 +104,7 @@ const (
 	StakingEphemeralSignerEnabledKey                   = "staking-ephemeral-signer-enabled"
 	StakingSignerKeyPathKey                            = "staking-signer-key-file"
 	StakingSignerKeyContentKey                         = "staking-signer-key-file-content"
+	StakingSeedKey                                     = "staking-seed"
 	StakingDisabledWeightKey                           = "staking-disabled-weight"
 	NetworkInitialTimeoutKey                           = "network-initial-timeout"
 	NetworkMinimumTimeoutKey                           = "network-minimum-timeout"
@@ -79,6 +113,7 @@ const (
 	NetworkTimeoutHalflifeKey                          = "network-timeout-halflife"
 	NetworkTimeoutCoefficientKey                       = "network-timeout-coefficient"
 	NetworkHealthMinPeersKey                           = "network-health-min-conn-peers"
+	NetworkHealthMinConnStakeKey                       = "network-health-min-conn-stake"
 	NetworkHealthMaxTimeSinceMsgReceivedKey            = "network-health-max-time-since-msg-received"
 	NetworkHealthMaxTimeSinceMsgSentKey                = "network-health-max-time-since-msg-sent"
 	NetworkHealthMaxPortionSendQueueFillKey            = "network-health-max-portion-send-queue-full"
@@ -94,10 +129,23 @@ const (
 	NetworkPingTimeoutKey                              = "network-ping-timeout"
 	NetworkPingFrequencyKey                            = "network-ping-frequency"
 	NetworkMaxReconnectDelayKey                        = "network-max-reconnect-delay"
+	NetworkTargetConnStakeKey                          = "network-target-conn-stake"
+	NetworkConnRepairFreqKey                           = "network-conn-repair-frequency"
+	NetworkMaxRepairDialsPerSecKey                     = "network-max-repair-dials-per-sec"
 	NetworkCompressionEnabledKey                       = "network-compression-enabled"
 	NetworkMaxClockDifferenceKey                       = "network-max-clock-difference"
+	ClockSkewWarnThresholdKey                          = "clock-skew-warn-threshold"
+	ClockSkewCriticalThresholdKey                      = "clock-skew-critical-threshold"
+	ClockSkewMinPeersKey                               = "clock-skew-min-peers"
+	ClockSkewNTPServerKey                              = "clock-skew-ntp-server"
+	ClockSkewNTPFrequencyKey                           = "clock-skew-ntp-frequency"
+	ClockSkewNTPTimeoutKey                             = "clock-skew-ntp-timeout"
 	NetworkAllowPrivateIPsKey                          = "network-allow-private-ips"
+	NetworkPartitionNameKey                            = "network-partition-name"
+	NetworkVMVersionTelemetryKey                       = "network-vm-version-telemetry-enabled"
 	NetworkRequireValidatorToConnectKey                = "network-require-validator-to-connect"
+	NetworkPeerPolicyAddrKey                           = "network-peer-policy-addr"
+	NetworkPeerPolicyTimeoutKey                        = "network-peer-policy-timeout"
 	NetworkPeerReadBufferSizeKey                       = "network-peer-read-buffer-size"
 	NetworkPeerWriteBufferSizeKey                      = "network-peer-write-buffer-size"
 	NetworkTLSKeyLogFileKey                            = "network-tls-key-log-file-unsafe"
@@ -128,14 +176,26 @@ const (
 	SnowMixedQueryNumPushNonVdrKey                     = "snow-mixed-query-num-push-non-vdr"
 	WhitelistedSubnetsKey                              = "whitelisted-subnets"
 	AdminAPIEnabledKey                                 = "api-admin-enabled"
+	DebugAPIEnabledKey                                 = "api-debug-enabled"
+	EventsAPIEnabledKey                                = "api-events-enabled"
+	OpenAPIEnabledKey                                  = "api-openapi-enabled"
+	EventsAPIMaxConnectionsKey                         = "api-events-max-connections"
+	EventsAPIMaxPendingEventsKey                       = "api-events-max-pending-events"
+	EventsAPIMaxSubscriptionsKey                       = "api-events-max-subscriptions"
 	InfoAPIEnabledKey                                  = "api-info-enabled"
+	InfoAPIAttestationEnabledKey                       = "api-info-attestation-enabled"
+	InfoAPIAttestationTSMDirKey                        = "api-info-attestation-tsm-dir"
 	KeystoreAPIEnabledKey                              = "api-keystore-enabled"
+	KeystoreSessionsAPIEnabledKey                      = "api-keystore-sessions-enabled"
 	MetricsAPIEnabledKey                               = "api-metrics-enabled"
+	MetricsMaxSeriesPerNamespaceKey                    = "api-metrics-max-series-per-namespace"
+	APIMaxPageSizesKey                                 = "api-max-page-sizes"
 	HealthAPIEnabledKey                                = "api-health-enabled"
 	IpcAPIEnabledKey                                   = "api-ipcs-enabled"
 	IpcsChainIDsKey                                    = "ipcs-chain-ids"
 	IpcsPathKey                                        = "ipcs-path"
 	MeterVMsEnabledKey                                 = "meter-vms-enabled"
+	PeerQueryLatencyMetricsSizeKey                     = "peer-query-latency-metrics-size"
 	ConsensusGossipFrequencyKey                        = "consensus-gossip-frequency"
 	ConsensusGossipAcceptedFrontierValidatorSizeKey    = "consensus-accepted-frontier-gossip-validator-size"
 	ConsensusGossipAcceptedFrontierNonValidatorSizeKey = "consensus-accepted-frontier-gossip-non-validator-size"
@@ -148,7 +208,32 @@ const (
 	AppGossipPeerSizeKey                               = "consensus-app-gossip-peer-size"
 	ConsensusShutdownTimeoutKey                        = "consensus-shutdown-timeout"
 	ProposerVMUseCurrentHeightKey                      = "proposervm-use-current-height"
+	ValidatorSnapshotEpochLengthKey                    = "validator-snapshot-epoch-length"
+	PlatformStateDiffsEnabledKey                       = "platform-state-diffs-enabled"
 	FdLimitKey                                         = "fd-limit"
+	PreflightChecksEnabledKey                          = "preflight-checks-enabled"
+	GCPercentKey                                       = "gc-percent"
+	GCMemoryLimitKey                                   = "gc-memory-limit"
+	GCBallastSizeKey                                   = "gc-ballast-size"
+	ChainGCConfigsKey                                  = "chain-gc-configs"
+	PluginMultiplexKey                                 = "plugin-multiplex-enabled"
+	PluginUnixSocketsKey                               = "plugin-unix-sockets-enabled"
+	PluginMTLSKey                                      = "plugin-mtls-enabled"
+	PluginDBIteratorPrefetchKey                        = "plugin-db-iterator-prefetch"
+	PluginDBMaxBatchBytesKey                           = "plugin-db-max-batch-bytes"
+	PluginDBChecksumsKey                               = "plugin-db-checksums-enabled"
+	PluginShutdownDrainTimeoutKey                      = "plugin-shutdown-drain-timeout"
+	PluginRPCRetryMaxAttemptsKey                       = "plugin-rpc-retry-max-attempts"
+	PluginRPCRetryInitialBackoffKey                    = "plugin-rpc-retry-initial-backoff"
+	PluginRPCRetryMaxBackoffKey                        = "plugin-rpc-retry-max-backoff"
+	PluginCgroupRootKey                                = "plugin-cgroup-root"
+	ChainResourceLimitsKey                             = "chain-resource-limits"
+	ChainPluginServicesKey                             = "chain-plugin-services"
+	ExternalVMsKey                                     = "external-vms"
+	ExternalVMListenHostKey                            = "external-vm-listen-host"
+	ExternalVMTLSCertFileKey                           = "external-vm-tls-cert-file"
+	ExternalVMTLSKeyFileKey                            = "external-vm-tls-key-file"
+	ExternalVMTLSCAFileKey                             = "external-vm-tls-ca-file"
 	IndexEnabledKey                                    = "index-enabled"
 	IndexAllowIncompleteKey                            = "index-allow-incomplete"
 	RouterHealthMaxDropRateKey                         = "router-health-max-drop-rate"
@@ -165,11 +250,17 @@ const (
 	ChainConfigDirKey                                  = "chain-config-dir"
 	ChainConfigContentKey                              = "chain-config-content"
 	SubnetConfigDirKey                                 = "subnet-config-dir"
+	ChainDataDirKey                                    = "chain-data-dir"
+	ChainDataDirQuotaKey                               = "chain-data-dir-quota"
+	ChainDataDirQuotaWarningRatioKey                   = "chain-data-dir-quota-warning-ratio"
+	ChainDataDirQuotaPolicyKey                         = "chain-data-dir-quota-policy"
 	SubnetConfigContentKey                             = "subnet-config-content"
 	ProfileDirKey                                      = "profile-dir"
 	ProfileContinuousEnabledKey                        = "profile-continuous-enabled"
 	ProfileContinuousFreqKey                           = "profile-continuous-freq"
 	ProfileContinuousMaxFilesKey                       = "profile-continuous-max-files"
+	MaintenanceWindowsKey                              = "maintenance-windows"
+	MaintenanceAnnounceBeforeKey                       = "maintenance-announce-before"
 	InboundThrottlerAtLargeAllocSizeKey                = "throttler-inbound-at-large-alloc-size"
 	InboundThrottlerVdrAllocSizeKey                    = "throttler-inbound-validator-alloc-size"
 	InboundThrottlerNodeMaxAtLargeBytesKey             = "throttler-inbound-node-max-at-large-bytes"
@@ -198,6 +289,29 @@ const (
 	VMAliasesContentKey                                = "vm-aliases-file-content"
 	ChainAliasesFileKey                                = "chain-aliases-file"
 	ChainAliasesContentKey                             = "chain-aliases-file-content"
+	ChainRetiredAliasesFileKey                         = "chain-retired-aliases-file"
+	ChainRetiredAliasesContentKey                      = "chain-retired-aliases-file-content"
+	ChainAuditVMsKey                                   = "chain-audit-vms"
+	AppResponseMaxSizeKey                              = "app-response-max-size"
+	ChainAppResponseMaxSizesKey                        = "chain-app-response-max-sizes"
+	ToEngineChannelSizeKey                             = "to-engine-channel-size"
+	ChainToEngineChannelSizesKey                       = "chain-to-engine-channel-sizes"
+	BlobUploadBandwidthKey                             = "blob-upload-bandwidth"
+	BlobDownloadBandwidthKey                           = "blob-download-bandwidth"
+	BuildPacingMinIntervalKey                          = "build-pacing-min-interval"
+	BuildPacingMinPendingTxsKey                        = "build-pacing-min-pending-txs"
+	ChainBuildPacingKey                                = "chain-build-pacing"
+	ChainReadReplicasKey                               = "chain-read-replicas"
+	StaleChainTimeoutKey                               = "stale-chain-timeout"
+	StaleChainResyncEnabledKey                         = "stale-chain-resync-enabled"
+	DecisionLogEnabledKey                              = "decision-log-enabled"
+	DecisionLogDirKey                                  = "decision-log-dir"
+	DecisionLogMaxSizeKey                              = "decision-log-max-size"
+	ConsensusHedgeDelayKey                             = "consensus-hedge-delay"
+	ConsensusHedgeBudgetKey                            = "consensus-hedge-budget"
+	ProfilesKey                                        = "profiles"
+	ProfilesFileKey                                    = "profiles-file"
+	ProfilesContentKey                                 = "profiles-file-content"
 	TracingEnabledKey                                  = "tracing-enabled"
 	TracingEndpointKey                                 = "tracing-endpoint"
 	TracingInsecureKey                                 = "tracing-insecure"
@@ -0,0 +1,62 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package config
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/viper"
+
+	"github.com/ava-labs/avalanchego/api/admin"
+)
+
+// getConfigOverrides returns the provided flags whose values differ from their
+// defaults, sorted by name.
+func getConfigOverrides(v *viper.Viper) []admin.ConfigOverride {
+	commandLineFlags := make(map[string]struct{})
+	for _, name := range v.GetStringSlice(commandLineFlagsKey) {
+		commandLineFlags[name] = struct{}{}
+	}
+
+	overrides := []admin.ConfigOverride{}
+	// Flags are visited in lexicographical order.
+	BuildFlagSet().VisitAll(func(f *flag.Flag) {
+		if !v.IsSet(f.Name) {
+			return
+		}
+		value := v.Get(f.Name)
+		if fmt.Sprint(value) == f.DefValue {
+			return
+		}
+		overrides = append(overrides, admin.ConfigOverride{
+			Key:     f.Name,
+			Value:   value,
+			Default: f.DefValue,
+			Source:  configSource(f.Name, commandLineFlags),
+		})
+	})
+	return overrides
+}
+
+// configSource returns where the value of the flag [key] was provided.
+// Command line flags take precedence over environment variables, which take
+// precedence over the config file.
+func configSource(key string, commandLineFlags map[string]struct{}) string {
+	if _, ok := commandLineFlags[key]; ok {
+		return admin.ConfigSourceFlag
+	}
+	if _, ok := os.LookupEnv(envVarName(key)); ok {
+		return admin.ConfigSourceEnv
+	}
+	return admin.ConfigSourceFile
+}
+
+// envVarName returns the environment variable the value of the flag [key] is
+// read from.
+func envVarName(key string) string {
+	return strings.ToUpper(envPrefix + "_" + strings.ReplaceAll(key, "-", "_"))
+}
@@ -0,0 +1,284 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package config
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/viper"
+
+	"github.com/ava-labs/avalanchego/chains"
+	"github.com/ava-labs/avalanchego/chains/quota"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/storage"
+	"github.com/ava-labs/avalanchego/vms"
+)
+
+var (
+	errUnknownProfile  = errors.New("unknown profile")
+	errProfileCycle    = errors.New("profile includes itself")
+	errProfileConflict = errors.New("conflicting profiles")
+	errUnknownAPI      = errors.New("unknown API")
+	errUntrackedSubnet = errors.New("subnet isn't tracked")
+
+	// Name of each API that profiles can expose -> key enabling the API
+	profileAPIKeys = map[string]string{
+		"admin":            AdminAPIEnabledKey,
+		"debug":            DebugAPIEnabledKey,
+		"health":           HealthAPIEnabledKey,
+		"index":            IndexEnabledKey,
+		"info":             InfoAPIEnabledKey,
+		"ipcs":             IpcAPIEnabledKey,
+		"keystore":         KeystoreAPIEnabledKey,
+		"keystoreSessions": KeystoreSessionsAPIEnabledKey,
+		"metrics":          MetricsAPIEnabledKey,
+	}
+)
+
+// Profile bundles the settings a node needs to run a set of subnets. Profiles
+// can include other profiles, and several profiles can be applied to the same
+// node. Settings that are explicitly provided to the node take precedence over
+// the settings of its profiles.
+type Profile struct {
+	// Names of the profiles whose settings are part of this profile
+	Include []string `json:"include"`
+
+	// Subnets tracked by the node
+	TrackedSubnets []ids.ID `json:"trackedSubnets"`
+	// Subnet ID -> config of the subnet, formatted as in the subnet config
+	// directory
+	SubnetConfigs map[string]json.RawMessage `json:"subnetConfigs"`
+	// VM ID -> aliases of the VM. Aliasing a VM with the name of a binary in
+	// the plugin directory selects the plugin that runs the VM.
+	VMAliases map[string][]string `json:"vmAliases"`
+	// Limits the size of each chain's data directory
+	ChainDataDirQuota *quota.Config `json:"chainDataDirQuota"`
+	// API name -> whether the API is enabled
+	APIs map[string]bool `json:"apis"`
+}
+
+// getProfile returns the composition of the profiles the node is configured
+// to run with. If no profile is selected, the returned profile is empty.
+func getProfile(v *viper.Viper) (Profile, []string, error) {
+	var names []string
+	for _, name := range strings.Split(v.GetString(ProfilesKey), ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return Profile{}, nil, nil
+	}
+
+	profiles, err := getProfiles(v)
+	if err != nil {
+		return Profile{}, nil, err
+	}
+	profile, err := composeProfiles(profiles, names)
+	return profile, names, err
+}
+
+// getProfiles returns the profiles defined in the profiles file or content.
+func getProfiles(v *viper.Viper) (map[string]Profile, error) {
+	var profilesBytes []byte
+	if v.IsSet(ProfilesContentKey) {
+		var err error
+		profilesBytes, err = base64.StdEncoding.DecodeString(v.GetString(ProfilesContentKey))
+		if err != nil {
+			return nil, fmt.Errorf("unable to decode base64 content for profiles: %w", err)
+		}
+	} else {
+		profilesFilePath := filepath.Clean(GetExpandedArg(v, ProfilesFileKey))
+		exists, err := storage.FileExists(profilesFilePath)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			return nil, fmt.Errorf("profiles file does not exist in %v", profilesFilePath)
+		}
+		profilesBytes, err = os.ReadFile(profilesFilePath)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	profiles := make(map[string]Profile)
+	if err := json.Unmarshal(profilesBytes, &profiles); err != nil {
+		return nil, fmt.Errorf("problem unmarshaling profiles: %w", err)
+	}
+	return profiles, nil
+}
+
+// composeProfiles merges the profiles [names], along with the profiles they
+// include. Profiles that configure the same setting differently conflict.
+func composeProfiles(profiles map[string]Profile, names []string) (Profile, error) {
+	composed := Profile{
+		SubnetConfigs: make(map[string]json.RawMessage),
+		VMAliases:     make(map[string][]string),
+		APIs:          make(map[string]bool),
+	}
+	var (
+		trackedSubnets = ids.Set{}
+		vmAliases      = make(map[string]map[string]struct{})
+		applied        = make(map[string]bool)
+		including      = make(map[string]bool)
+	)
+
+	var apply func(name string) error
+	apply = func(name string) error {
+		if including[name] {
+			return fmt.Errorf("%w: %q", errProfileCycle, name)
+		}
+		if applied[name] {
+			return nil
+		}
+		profile, ok := profiles[name]
+		if !ok {
+			return fmt.Errorf("%w: %q", errUnknownProfile, name)
+		}
+
+		including[name] = true
+		for _, includedName := range profile.Include {
+			if err := apply(includedName); err != nil {
+				return err
+			}
+		}
+		delete(including, name)
+		applied[name] = true
+
+		for _, subnetID := range profile.TrackedSubnets {
+			if subnetID == constants.PrimaryNetworkID {
+				return fmt.Errorf("profile %q: %w", name, errCannotWhitelistPrimaryNetwork)
+			}
+			if !trackedSubnets.Contains(subnetID) {
+				trackedSubnets.Add(subnetID)
+				composed.TrackedSubnets = append(composed.TrackedSubnets, subnetID)
+			}
+		}
+		for subnetID, config := range profile.SubnetConfigs {
+			if _, err := ids.FromString(subnetID); err != nil {
+				return fmt.Errorf("profile %q: couldn't parse subnetID %q: %w", name, subnetID, err)
+			}
+			compacted := &bytes.Buffer{}
+			if err := json.Compact(compacted, config); err != nil {
+				return fmt.Errorf("profile %q: invalid config for subnet %s: %w", name, subnetID, err)
+			}
+			if previous, ok := composed.SubnetConfigs[subnetID]; ok && !bytes.Equal(previous, compacted.Bytes()) {
+				return fmt.Errorf("%w: profile %q reconfigures subnet %s", errProfileConflict, name, subnetID)
+			}
+			composed.SubnetConfigs[subnetID] = compacted.Bytes()
+		}
+		for vmID, aliases := range profile.VMAliases {
+			if _, err := ids.FromString(vmID); err != nil {
+				return fmt.Errorf("profile %q: couldn't parse vmID %q: %w", name, vmID, err)
+			}
+			vmAliasSet, ok := vmAliases[vmID]
+			if !ok {
+				vmAliasSet = make(map[string]struct{})
+				vmAliases[vmID] = vmAliasSet
+			}
+			for _, alias := range aliases {
+				if _, ok := vmAliasSet[alias]; !ok {
+					vmAliasSet[alias] = struct{}{}
+					composed.VMAliases[vmID] = append(composed.VMAliases[vmID], alias)
+				}
+			}
+		}
+		if profile.ChainDataDirQuota != nil {
+			if err := profile.ChainDataDirQuota.Verify(); err != nil {
+				return fmt.Errorf("profile %q: invalid chain data directory quota: %w", name, err)
+			}
+			if composed.ChainDataDirQuota != nil && *composed.ChainDataDirQuota != *profile.ChainDataDirQuota {
+				return fmt.Errorf("%w: profile %q changes the chain data directory quota", errProfileConflict, name)
+			}
+			composed.ChainDataDirQuota = profile.ChainDataDirQuota
+		}
+		for api, enabled := range profile.APIs {
+			if _, ok := profileAPIKeys[api]; !ok {
+				return fmt.Errorf("profile %q: %w: %q", name, errUnknownAPI, api)
+			}
+			if previous, ok := composed.APIs[api]; ok && previous != enabled {
+				return fmt.Errorf("%w: profile %q changes whether the %s API is enabled", errProfileConflict, name, api)
+			}
+			composed.APIs[api] = enabled
+		}
+		return nil
+	}
+
+	for _, name := range names {
+		if err := apply(name); err != nil {
+			return Profile{}, err
+		}
+	}
+	return composed, nil
+}
+
+// applyProfileDefaults uses the node wide settings of [profile] in place of
+// the defaults of the corresponding flags.
+func applyProfileDefaults(v *viper.Viper, profile Profile) {
+	for api, enabled := range profile.APIs {
+		v.SetDefault(profileAPIKeys[api], enabled)
+	}
+	if profile.ChainDataDirQuota != nil {
+		v.SetDefault(ChainDataDirQuotaKey, profile.ChainDataDirQuota.MaxSize)
+		v.SetDefault(ChainDataDirQuotaWarningRatioKey, profile.ChainDataDirQuota.WarningRatio)
+		v.SetDefault(ChainDataDirQuotaPolicyKey, string(profile.ChainDataDirQuota.Policy))
+	}
+}
+
+// applyProfileSubnetConfigs adds the subnet configs of [profile] to
+// [subnetConfigs], unless the subnet is already configured.
+func applyProfileSubnetConfigs(
+	v *viper.Viper,
+	profile Profile,
+	trackedSubnets ids.Set,
+	subnetConfigs map[ids.ID]chains.SubnetConfig,
+) error {
+	for subnetIDStr, configBytes := range profile.SubnetConfigs {
+		subnetID, err := ids.FromString(subnetIDStr)
+		if err != nil {
+			return fmt.Errorf("couldn't parse subnetID %q: %w", subnetIDStr, err)
+		}
+		if !trackedSubnets.Contains(subnetID) {
+			return fmt.Errorf("%w: profiles configure subnet %s", errUntrackedSubnet, subnetID)
+		}
+		if _, ok := subnetConfigs[subnetID]; ok {
+			continue
+		}
+		config, err := parseSubnetConfigs(configBytes, getDefaultSubnetConfig(v))
+		if err != nil {
+			return fmt.Errorf("invalid config for subnet %s: %w", subnetID, err)
+		}
+		subnetConfigs[subnetID] = config
+	}
+	return nil
+}
+
+// applyProfileVMAliases registers the VM aliases of [profile] in [manager].
+func applyProfileVMAliases(profile Profile, manager vms.Manager) error {
+	for vmIDStr, aliases := range profile.VMAliases {
+		vmID, err := ids.FromString(vmIDStr)
+		if err != nil {
+			return fmt.Errorf("couldn't parse vmID %q: %w", vmIDStr, err)
+		}
+		for _, alias := range aliases {
+			if aliasedID, err := manager.Lookup(alias); err == nil && aliasedID == vmID {
+				// The alias was also provided in the VM aliases file.
+				continue
+			}
+			if err := manager.Alias(vmID, alias); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -11,9 +11,19 @@ import (
 	"io"
 	"strings"
 
+	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
 )
 
+const (
+	// Prefix of the environment variables config values are read from
+	envPrefix = "avago"
+
+	// Key the names of the flags provided on the command line are recorded
+	// under. It isn't a flag, so it's never reported as a provided flag.
+	commandLineFlagsKey = "command-line-flags"
+)
+
 // BuildViper returns the viper environment from parsing config file from
 // default search paths and any parsed command line flags
 func BuildViper(fs *flag.FlagSet, args []string) (*viper.Viper, error) {
@@ -28,11 +38,20 @@ func BuildViper(fs *flag.FlagSet, args []string) (*viper.Viper, error) {
 	v := viper.New()
 	v.AutomaticEnv()
 	v.SetEnvKeyReplacer(strings.NewReplacer("-", "_"))
-	v.SetEnvPrefix("avago")
+	v.SetEnvPrefix(envPrefix)
 	if err := v.BindPFlags(pfs); err != nil {
 		return nil, err
 	}
 
+	// Viper doesn't report where a value was provided, so the flags provided
+	// on the command line are recorded to report the source of each config
+	// value.
+	commandLineFlags := []string{}
+	pfs.Visit(func(f *pflag.Flag) {
+		commandLineFlags = append(commandLineFlags, f.Name)
+	})
+	v.SetDefault(commandLineFlagsKey, commandLineFlags)
+
 	// load node configs from flags or file, depending on which flags are set
 	switch {
 	case v.IsSet(ConfigContentKey):
@@ -12,8 +12,10 @@ import (
 )
 
 var (
-	_ database.Database = (*Database)(nil)
-	_ database.Batch    = (*batch)(nil)
+	_ database.Database    = (*Database)(nil)
+	_ database.Flusher     = (*Database)(nil)
+	_ database.Snapshotter = (*Database)(nil)
+	_ database.Batch       = (*batch)(nil)
 )
 
 // CorruptableDB is a wrapper around Database
@@ -75,6 +77,31 @@ func (db *Database) Close() error {
 	return db.handleError(db.Database.Close())
 }
 
+// Flush flushes the wrapped database if it buffers writes in memory.
+func (db *Database) Flush() error {
+	if err := db.corrupted(); err != nil {
+		return err
+	}
+	flusher, ok := db.Database.(database.Flusher)
+	if !ok {
+		return nil
+	}
+	return db.handleError(flusher.Flush())
+}
+
+// NewSnapshot returns a snapshot of the wrapped database if it supports them.
+func (db *Database) NewSnapshot() (database.Snapshot, error) {
+	if err := db.corrupted(); err != nil {
+		return nil, err
+	}
+	snapshotter, ok := db.Database.(database.Snapshotter)
+	if !ok {
+		return nil, database.ErrSnapshotsNotSupported
+	}
+	snapshot, err := snapshotter.NewSnapshot()
+	return snapshot, db.handleError(err)
+}
+
 func (db *Database) HealthCheck(ctx context.Context) (interface{}, error) {
 	if err := db.corrupted(); err != nil {
 		return nil, err
@@ -12,6 +12,7 @@ import (
 
 	"github.com/ava-labs/avalanchego/database"
 	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/database/nodb"
 )
 
 func TestInterface(t *testing.T) {
@@ -22,6 +23,14 @@ func TestInterface(t *testing.T) {
 	}
 }
 
+func TestSnapshotInterface(t *testing.T) {
+	for _, test := range database.SnapshotTests {
+		baseDB := memdb.New()
+		db := New(baseDB)
+		test(t, db)
+	}
+}
+
 func FuzzInterface(f *testing.F) {
 	for _, test := range database.FuzzTests {
 		baseDB := memdb.New()
@@ -75,3 +84,9 @@ func TestCorruption(t *testing.T) {
 		})
 	}
 }
+
+func TestSnapshotsNotSupported(t *testing.T) {
+	db := New(&nodb.Database{})
+	_, err := db.NewSnapshot()
+	require.ErrorIs(t, err, database.ErrSnapshotsNotSupported)
+}
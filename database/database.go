@@ -81,6 +81,36 @@ type Compacter interface {
 	Compact(start []byte, limit []byte) error
 }
 
+// Flusher is implemented by the data stores that buffer writes in memory.
+type Flusher interface {
+	// Flush persists the writes buffered in memory, so that they don't have
+	// to be recovered from the write-ahead log when the data store is next
+	// opened.
+	Flush() error
+}
+
+// Snapshot is a read-only view of a data store's contents at the time the
+// snapshot was created. Writes made to the data store afterwards aren't
+// visible through the snapshot.
+type Snapshot interface {
+	KeyValueReader
+	Iteratee
+
+	// Release frees the resources held by the snapshot. Reads from the
+	// snapshot after it was released fail with [ErrClosed]. Iterators created
+	// before the snapshot was released remain usable until they're released.
+	Release()
+}
+
+// Snapshotter is implemented by the data stores that can provide consistent
+// views of their contents.
+type Snapshotter interface {
+	// NewSnapshot returns a view of the data store's current contents.
+	// Returns [ErrSnapshotsNotSupported] if the data store that backs this
+	// data store can't provide snapshots.
+	NewSnapshot() (Snapshot, error)
+}
+
 // Database contains all the methods required to allow handling different
 // key-value data stores backing the database.
 type Database interface {
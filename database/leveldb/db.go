@@ -64,9 +64,12 @@ const (
 )
 
 var (
-	_ database.Database = (*Database)(nil)
-	_ database.Batch    = (*batch)(nil)
-	_ database.Iterator = (*iter)(nil)
+	_ database.Database    = (*Database)(nil)
+	_ database.Flusher     = (*Database)(nil)
+	_ database.Snapshotter = (*Database)(nil)
+	_ database.Snapshot    = (*snapshot)(nil)
+	_ database.Batch       = (*batch)(nil)
+	_ database.Iterator    = (*iter)(nil)
 )
 
 // Database is a persistent key-value store. Apart from basic data storage
@@ -350,6 +353,31 @@ func (db *Database) Compact(start []byte, limit []byte) error {
 	return updateError(db.DB.CompactRange(util.Range{Start: start, Limit: limit}))
 }
 
+// Flush writes the memtable to disk. Opening a transaction is the only way
+// goleveldb exposes to flush the memtable.
+func (db *Database) Flush() error {
+	tx, err := db.DB.OpenTransaction()
+	if err != nil {
+		return updateError(err)
+	}
+	tx.Discard()
+	return nil
+}
+
+// NewSnapshot returns a view of the database's current contents. The
+// snapshot prevents the data it references from being compacted away, so it
+// should be released as soon as it's no longer needed.
+func (db *Database) NewSnapshot() (database.Snapshot, error) {
+	snap, err := db.DB.GetSnapshot()
+	if err != nil {
+		return nil, updateError(err)
+	}
+	return &snapshot{
+		db:   db,
+		snap: snap,
+	}, nil
+}
+
 func (db *Database) Close() error {
 	db.closed.SetValue(true)
 	db.closeOnce.Do(func() {
@@ -437,6 +465,53 @@ func (r *replayer) Delete(key []byte) {
 	r.err = r.writerDeleter.Delete(key)
 }
 
+// snapshot is a wrapper around a levelDB snapshot to implement the database
+// interfaces.
+type snapshot struct {
+	db   *Database
+	snap *leveldb.Snapshot
+}
+
+// Has returns if the key was set in the database when the snapshot was taken
+func (s *snapshot) Has(key []byte) (bool, error) {
+	has, err := s.snap.Has(key, nil)
+	return has, updateError(err)
+}
+
+// Get returns the value the key mapped to in the database when the snapshot
+// was taken
+func (s *snapshot) Get(key []byte) ([]byte, error) {
+	value, err := s.snap.Get(key, nil)
+	return value, updateError(err)
+}
+
+func (s *snapshot) NewIterator() database.Iterator {
+	return s.NewIteratorWithStartAndPrefix(nil, nil)
+}
+
+func (s *snapshot) NewIteratorWithStart(start []byte) database.Iterator {
+	return s.NewIteratorWithStartAndPrefix(start, nil)
+}
+
+func (s *snapshot) NewIteratorWithPrefix(prefix []byte) database.Iterator {
+	return s.NewIteratorWithStartAndPrefix(nil, prefix)
+}
+
+func (s *snapshot) NewIteratorWithStartAndPrefix(start, prefix []byte) database.Iterator {
+	iterRange := util.BytesPrefix(prefix)
+	if bytes.Compare(start, prefix) == 1 {
+		iterRange.Start = start
+	}
+	return &iter{
+		db:       s.db,
+		Iterator: s.snap.NewIterator(iterRange, nil),
+	}
+}
+
+func (s *snapshot) Release() {
+	s.snap.Release()
+}
+
 type iter struct {
 	db *Database
 	iterator.Iterator
@@ -482,7 +557,7 @@ func (it *iter) Value() []byte {
 
 func updateError(err error) error {
 	switch err {
-	case leveldb.ErrClosed:
+	case leveldb.ErrClosed, leveldb.ErrSnapshotReleased:
 		return database.ErrClosed
 	case leveldb.ErrNotFound:
 		return database.ErrNotFound
@@ -32,6 +32,20 @@ func TestInterface(t *testing.T) {
 	}
 }
 
+func TestSnapshotInterface(t *testing.T) {
+	for _, test := range database.SnapshotTests {
+		folder := t.TempDir()
+		db, err := New(folder, nil, logging.NoLog{}, "", prometheus.NewRegistry())
+		if err != nil {
+			t.Fatalf("leveldb.New(%q, logging.NoLog{}) errored with %s", folder, err)
+		}
+
+		test(t, db)
+
+		_ = db.Close()
+	}
+}
+
 func FuzzInterface(f *testing.F) {
 	for _, test := range database.FuzzTests {
 		folder := f.TempDir()
@@ -69,3 +83,35 @@ func BenchmarkInterface(b *testing.B) {
 		}
 	}
 }
+
+func TestFlush(t *testing.T) {
+	require := require.New(t)
+
+	db, err := New(t.TempDir(), nil, logging.NoLog{}, "", prometheus.NewRegistry())
+	require.NoError(err)
+	defer db.Close()
+
+	levelDB := db.(*Database)
+	numTables := func() string {
+		tables, err := levelDB.DB.GetProperty("leveldb.num-files-at-level0")
+		require.NoError(err)
+		return tables
+	}
+
+	require.NoError(db.Put([]byte("key"), []byte("value")))
+	require.Equal("0", numTables())
+
+	// The memtable is written to a table.
+	require.NoError(levelDB.Flush())
+	require.Equal("1", numTables())
+
+	value, err := db.Get([]byte("key"))
+	require.NoError(err)
+	require.Equal([]byte("value"), value)
+
+	// The database remains writable.
+	require.NoError(db.Put([]byte("key"), []byte("value2")))
+
+	require.NoError(db.Close())
+	require.ErrorIs(levelDB.Flush(), database.ErrClosed)
+}
@@ -23,9 +23,11 @@ const (
 )
 
 var (
-	_ database.Database = (*Database)(nil)
-	_ database.Batch    = (*batch)(nil)
-	_ database.Iterator = (*iterator)(nil)
+	_ database.Database    = (*Database)(nil)
+	_ database.Snapshotter = (*Database)(nil)
+	_ database.Snapshot    = (*snapshot)(nil)
+	_ database.Batch       = (*batch)(nil)
+	_ database.Iterator    = (*iterator)(nil)
 )
 
 // Database is an ephemeral key-value store that implements the Database
@@ -154,6 +156,22 @@ func (db *Database) NewIteratorWithStartAndPrefix(start, prefix []byte) database
 	}
 }
 
+// NewSnapshot returns a copy of the database's current contents. Values are
+// never modified in place, so they're shared with the copy.
+func (db *Database) NewSnapshot() (database.Snapshot, error) {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.db == nil {
+		return nil, database.ErrClosed
+	}
+	snapshotDB := NewWithSize(len(db.db))
+	for key, value := range db.db {
+		snapshotDB.db[key] = value
+	}
+	return &snapshot{Database: snapshotDB}, nil
+}
+
 func (db *Database) Compact(_, _ []byte) error {
 	db.lock.RLock()
 	defer db.lock.RUnlock()
@@ -245,6 +263,15 @@ func (b *batch) Inner() database.Batch {
 	return b
 }
 
+// snapshot is a read-only copy of a database.
+type snapshot struct {
+	*Database
+}
+
+func (s *snapshot) Release() {
+	_ = s.Database.Close()
+}
+
 type iterator struct {
 	db          *Database
 	initialized bool
@@ -13,9 +13,10 @@ import (
 )
 
 var (
-	_ database.Database = (*Database)(nil)
-	_ database.Batch    = (*batch)(nil)
-	_ database.Iterator = (*iterator)(nil)
+	_ database.Database    = (*Database)(nil)
+	_ database.Snapshotter = (*Database)(nil)
+	_ database.Batch       = (*batch)(nil)
+	_ database.Iterator    = (*iterator)(nil)
 )
 
 // Database tracks the amount of time each operation takes and how many bytes
@@ -116,6 +117,16 @@ func (db *Database) NewIteratorWithStartAndPrefix(
 	return it
 }
 
+// NewSnapshot returns a snapshot of the wrapped database if it supports them.
+// Reads from the snapshot aren't metered.
+func (db *Database) NewSnapshot() (database.Snapshot, error) {
+	snapshotter, ok := db.db.(database.Snapshotter)
+	if !ok {
+		return nil, database.ErrSnapshotsNotSupported
+	}
+	return snapshotter.NewSnapshot()
+}
+
 func (db *Database) Compact(start, limit []byte) error {
 	startTime := db.clock.Time()
 	err := db.db.Compact(start, limit)
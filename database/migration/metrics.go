@@ -0,0 +1,44 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package migration
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+)
+
+type metrics struct {
+	pending  prometheus.Gauge
+	applied  prometheus.Counter
+	duration *prometheus.GaugeVec
+}
+
+func newMetrics(namespace string, reg prometheus.Registerer) (*metrics, error) {
+	m := &metrics{
+		pending: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "pending",
+			Help:      "Number of migrations that haven't been applied to the database yet",
+		}),
+		applied: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "applied",
+			Help:      "Number of migrations applied to the database since the node started",
+		}),
+		duration: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "duration_seconds",
+			Help:      "Time it took to apply each migration applied since the node started",
+		}, []string{"migration"}),
+	}
+
+	errs := wrappers.Errs{}
+	errs.Add(
+		reg.Register(m.pending),
+		reg.Register(m.applied),
+		reg.Register(m.duration),
+	)
+	return m, errs.Err
+}
@@ -0,0 +1,178 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package migration upgrades the storage layout of a database by applying an
+// ordered list of migrations. Every applied migration is recorded in the
+// database, so each migration is applied exactly once, and a migration
+// interrupted by a crash is applied again on the next start.
+package migration
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"go.uber.org/zap"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/prefixdb"
+	"github.com/ava-labs/avalanchego/database/versiondb"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+)
+
+var (
+	recordsPrefix = []byte("migrations")
+
+	errUnorderedMigrations = errors.New("migrations must have unique, increasing versions")
+	errMissingMigrate      = errors.New("migration doesn't define how to migrate")
+	errUnknownMigration    = errors.New("database was migrated by a newer node")
+)
+
+// Migration is a change of the storage layout of a database.
+type Migration struct {
+	// Version orders the migrations. Once released, a version must never be
+	// reused.
+	Version uint64
+	// Name describes the migration in logs and metrics.
+	Name string
+	// Migrate applies the migration to [db]. Once Migrate returns, its writes
+	// are committed atomically with the record that the migration was
+	// applied. Large migrations may call [db.Commit] to bound their memory
+	// usage, in which case they must be idempotent, as they're applied again
+	// from the start if the node crashes before they return.
+	Migrate func(db *versiondb.Database) error
+}
+
+// Migrator applies migrations to a database.
+type Migrator struct {
+	log        logging.Logger
+	db         database.Database
+	records    database.Database
+	migrations []*Migration
+	metrics    *metrics
+}
+
+// New returns a Migrator that applies [migrations] to [db]. [migrations] must
+// be sorted by version.
+func New(
+	log logging.Logger,
+	namespace string,
+	registerer prometheus.Registerer,
+	db database.Database,
+	migrations []*Migration,
+) (*Migrator, error) {
+	for i, migration := range migrations {
+		if migration.Migrate == nil {
+			return nil, fmt.Errorf("%w: %d (%s)", errMissingMigrate, migration.Version, migration.Name)
+		}
+		if i > 0 && migration.Version <= migrations[i-1].Version {
+			return nil, fmt.Errorf("%w: %d follows %d", errUnorderedMigrations, migration.Version, migrations[i-1].Version)
+		}
+	}
+
+	metrics, err := newMetrics(namespace, registerer)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{
+		log:        log,
+		db:         db,
+		records:    prefixdb.New(recordsPrefix, db),
+		migrations: migrations,
+		metrics:    metrics,
+	}, nil
+}
+
+// Pending returns the migrations that weren't applied to the database yet, in
+// the order they'll be applied. Returns an error if the database was migrated
+// by a node that knows of migrations this node doesn't.
+func (m *Migrator) Pending() ([]*Migration, error) {
+	known := make(map[uint64]struct{}, len(m.migrations))
+	for _, migration := range m.migrations {
+		known[migration.Version] = struct{}{}
+	}
+
+	applied := make(map[uint64]struct{})
+	it := m.records.NewIterator()
+	defer it.Release()
+	for it.Next() {
+		version, err := database.ParseUInt64(it.Key())
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := known[version]; !ok {
+			return nil, fmt.Errorf("%w: unknown migration %d (%s)", errUnknownMigration, version, it.Value())
+		}
+		applied[version] = struct{}{}
+	}
+	if err := it.Error(); err != nil {
+		return nil, err
+	}
+
+	var pending []*Migration
+	for _, migration := range m.migrations {
+		if _, ok := applied[migration.Version]; !ok {
+			pending = append(pending, migration)
+		}
+	}
+	return pending, nil
+}
+
+// Run applies the pending migrations in order.
+func (m *Migrator) Run() error {
+	pending, err := m.Pending()
+	if err != nil {
+		return err
+	}
+	m.metrics.pending.Set(float64(len(pending)))
+	if len(pending) == 0 {
+		return nil
+	}
+
+	m.log.Info("migrating database",
+		zap.Int("numMigrations", len(pending)),
+	)
+	for _, migration := range pending {
+		if err := m.apply(migration); err != nil {
+			return fmt.Errorf("failed to apply migration %d (%s): %w", migration.Version, migration.Name, err)
+		}
+		m.metrics.pending.Dec()
+	}
+	return nil
+}
+
+func (m *Migrator) apply(migration *Migration) error {
+	m.log.Info("applying migration",
+		zap.Uint64("version", migration.Version),
+		zap.String("name", migration.Name),
+	)
+
+	start := time.Now()
+	vdb := versiondb.New(m.db)
+	if err := migration.Migrate(vdb); err != nil {
+		vdb.Abort()
+		return err
+	}
+
+	errs := wrappers.Errs{}
+	errs.Add(
+		prefixdb.New(recordsPrefix, vdb).Put(database.PackUInt64(migration.Version), []byte(migration.Name)),
+		vdb.Commit(),
+	)
+	if errs.Errored() {
+		return errs.Err
+	}
+	duration := time.Since(start)
+
+	m.metrics.applied.Inc()
+	m.metrics.duration.WithLabelValues(migration.Name).Set(duration.Seconds())
+	m.log.Info("applied migration",
+		zap.Uint64("version", migration.Version),
+		zap.String("name", migration.Name),
+		zap.Duration("duration", duration),
+	)
+	return nil
+}
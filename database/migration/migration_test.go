@@ -0,0 +1,175 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package migration
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/database/versiondb"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+var errTest = errors.New("non-nil error")
+
+// putMigration returns a migration that puts [key] and counts how many times
+// it was applied in [runs].
+func putMigration(version uint64, key string, runs map[uint64]int) *Migration {
+	return &Migration{
+		Version: version,
+		Name:    key,
+		Migrate: func(db *versiondb.Database) error {
+			runs[version]++
+			return db.Put([]byte(key), []byte(key))
+		},
+	}
+}
+
+func newMigrator(t *testing.T, db database.Database, migrations []*Migration) *Migrator {
+	m, err := New(logging.NoLog{}, "", prometheus.NewRegistry(), db, migrations)
+	require.NoError(t, err)
+	return m
+}
+
+func TestRunAppliesOnce(t *testing.T) {
+	require := require.New(t)
+
+	db := memdb.New()
+	runs := make(map[uint64]int)
+	migrations := []*Migration{
+		putMigration(1, "a", runs),
+		putMigration(3, "b", runs),
+	}
+
+	m := newMigrator(t, db, migrations)
+	pending, err := m.Pending()
+	require.NoError(err)
+	require.Equal(migrations, pending)
+
+	require.NoError(m.Run())
+	for _, key := range []string{"a", "b"} {
+		has, err := db.Has([]byte(key))
+		require.NoError(err)
+		require.True(has)
+	}
+
+	// Restarting the node must not apply the migrations again, but must apply
+	// the ones added since.
+	migrations = append(migrations, putMigration(4, "c", runs))
+	m = newMigrator(t, db, migrations)
+	pending, err = m.Pending()
+	require.NoError(err)
+	require.Equal(migrations[2:], pending)
+
+	require.NoError(m.Run())
+	require.Equal(map[uint64]int{1: 1, 3: 1, 4: 1}, runs)
+}
+
+func TestRunFailedMigrationIsAtomic(t *testing.T) {
+	require := require.New(t)
+
+	db := memdb.New()
+	runs := make(map[uint64]int)
+	failing := &Migration{
+		Version: 2,
+		Name:    "failing",
+		Migrate: func(db *versiondb.Database) error {
+			if err := db.Put([]byte("partial"), nil); err != nil {
+				return err
+			}
+			return errTest
+		},
+	}
+	m := newMigrator(t, db, []*Migration{
+		putMigration(1, "a", runs),
+		failing,
+		putMigration(3, "b", runs),
+	})
+	require.ErrorIs(m.Run(), errTest)
+
+	// The migrations before the failure are kept, the failed migration left
+	// nothing behind and the following ones weren't applied.
+	has, err := db.Has([]byte("a"))
+	require.NoError(err)
+	require.True(has)
+	has, err = db.Has([]byte("partial"))
+	require.NoError(err)
+	require.False(has)
+	has, err = db.Has([]byte("b"))
+	require.NoError(err)
+	require.False(has)
+
+	failing.Migrate = func(db *versiondb.Database) error {
+		return db.Put([]byte("fixed"), nil)
+	}
+	pending, err := m.Pending()
+	require.NoError(err)
+	require.Len(pending, 2)
+	require.NoError(m.Run())
+	require.Equal(map[uint64]int{1: 1, 3: 1}, runs)
+}
+
+func TestUnknownMigration(t *testing.T) {
+	require := require.New(t)
+
+	db := memdb.New()
+	runs := make(map[uint64]int)
+	require.NoError(newMigrator(t, db, []*Migration{
+		putMigration(1, "a", runs),
+		putMigration(2, "b", runs),
+	}).Run())
+
+	// A node that doesn't know of migration 2 must refuse the database.
+	m := newMigrator(t, db, []*Migration{
+		putMigration(1, "a", runs),
+	})
+	_, err := m.Pending()
+	require.ErrorIs(err, errUnknownMigration)
+	require.ErrorIs(m.Run(), errUnknownMigration)
+}
+
+func TestNewInvalidMigrations(t *testing.T) {
+	runs := make(map[uint64]int)
+	tests := []struct {
+		name       string
+		migrations []*Migration
+		err        error
+	}{
+		{
+			name: "duplicate version",
+			migrations: []*Migration{
+				putMigration(1, "a", runs),
+				putMigration(1, "b", runs),
+			},
+			err: errUnorderedMigrations,
+		},
+		{
+			name: "decreasing version",
+			migrations: []*Migration{
+				putMigration(2, "a", runs),
+				putMigration(1, "b", runs),
+			},
+			err: errUnorderedMigrations,
+		},
+		{
+			name: "missing migrate",
+			migrations: []*Migration{
+				{Version: 1},
+			},
+			err: errMissingMigrate,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := New(logging.NoLog{}, "", prometheus.NewRegistry(), memdb.New(), test.migrations)
+			require.ErrorIs(t, err, test.err)
+		})
+	}
+}
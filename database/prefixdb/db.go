@@ -18,9 +18,11 @@ const (
 )
 
 var (
-	_ database.Database = (*Database)(nil)
-	_ database.Batch    = (*batch)(nil)
-	_ database.Iterator = (*iterator)(nil)
+	_ database.Database    = (*Database)(nil)
+	_ database.Snapshotter = (*Database)(nil)
+	_ database.Snapshot    = (*snapshot)(nil)
+	_ database.Batch       = (*batch)(nil)
+	_ database.Iterator    = (*iterator)(nil)
 )
 
 // Database partitions a database into a sub-database by prefixing all keys with
@@ -168,6 +170,29 @@ func (db *Database) NewIteratorWithStartAndPrefix(start, prefix []byte) database
 	return it
 }
 
+// NewSnapshot returns a snapshot of this database's keys if the underlying
+// database supports snapshots.
+func (db *Database) NewSnapshot() (database.Snapshot, error) {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.closed {
+		return nil, database.ErrClosed
+	}
+	snapshotter, ok := db.db.(database.Snapshotter)
+	if !ok {
+		return nil, database.ErrSnapshotsNotSupported
+	}
+	inner, err := snapshotter.NewSnapshot()
+	if err != nil {
+		return nil, err
+	}
+	return &snapshot{
+		Snapshot: inner,
+		db:       db,
+	}, nil
+}
+
 func (db *Database) Compact(start, limit []byte) error {
 	db.lock.RLock()
 	defer db.lock.RUnlock()
@@ -313,6 +338,63 @@ func (b *batch) Replay(w database.KeyValueWriterDeleter) error {
 	return nil
 }
 
+// snapshot is a snapshot of the underlying database that only exposes the
+// keys of [db].
+type snapshot struct {
+	database.Snapshot
+	db *Database
+}
+
+// [key] may be modified after this method returns.
+func (s *snapshot) Has(key []byte) (bool, error) {
+	if s.db.isClosed() {
+		return false, database.ErrClosed
+	}
+	prefixedKey := s.db.prefix(key)
+	has, err := s.Snapshot.Has(prefixedKey)
+	s.db.bufferPool.Put(prefixedKey)
+	return has, err
+}
+
+// [key] may be modified after this method returns.
+func (s *snapshot) Get(key []byte) ([]byte, error) {
+	if s.db.isClosed() {
+		return nil, database.ErrClosed
+	}
+	prefixedKey := s.db.prefix(key)
+	val, err := s.Snapshot.Get(prefixedKey)
+	s.db.bufferPool.Put(prefixedKey)
+	return val, err
+}
+
+func (s *snapshot) NewIterator() database.Iterator {
+	return s.NewIteratorWithStartAndPrefix(nil, nil)
+}
+
+func (s *snapshot) NewIteratorWithStart(start []byte) database.Iterator {
+	return s.NewIteratorWithStartAndPrefix(start, nil)
+}
+
+func (s *snapshot) NewIteratorWithPrefix(prefix []byte) database.Iterator {
+	return s.NewIteratorWithStartAndPrefix(nil, prefix)
+}
+
+// It is safe to modify [start] and [prefix] after this method returns.
+func (s *snapshot) NewIteratorWithStartAndPrefix(start, prefix []byte) database.Iterator {
+	if s.db.isClosed() {
+		return &nodb.Iterator{Err: database.ErrClosed}
+	}
+	prefixedStart := s.db.prefix(start)
+	prefixedPrefix := s.db.prefix(prefix)
+	it := &iterator{
+		Iterator: s.Snapshot.NewIteratorWithStartAndPrefix(prefixedStart, prefixedPrefix),
+		db:       s.db,
+	}
+	s.db.bufferPool.Put(prefixedStart)
+	s.db.bufferPool.Put(prefixedPrefix)
+	return it
+}
+
 type iterator struct {
 	database.Iterator
 	db *Database
@@ -0,0 +1,168 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package readonlydb
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/nodb"
+)
+
+var (
+	// ErrReadOnly is returned when writing to a read-only database.
+	ErrReadOnly = errors.New("database is read-only")
+
+	_ database.Database = (*Database)(nil)
+	_ database.Batch    = (*batch)(nil)
+)
+
+// Database is a read-only handle on a database that may be written to through
+// other handles.
+//
+// Closing the handle doesn't close the wrapped database.
+type Database struct {
+	lock   sync.RWMutex
+	closed bool
+	db     database.Database
+}
+
+// New returns a read-only handle on [db]
+func New(db database.Database) *Database {
+	return &Database{db: db}
+}
+
+func (db *Database) Has(key []byte) (bool, error) {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.closed {
+		return false, database.ErrClosed
+	}
+	return db.db.Has(key)
+}
+
+func (db *Database) Get(key []byte) ([]byte, error) {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.closed {
+		return nil, database.ErrClosed
+	}
+	return db.db.Get(key)
+}
+
+// Put returns ErrReadOnly
+func (db *Database) Put(_, _ []byte) error {
+	return db.writeErr()
+}
+
+// Delete returns ErrReadOnly
+func (db *Database) Delete([]byte) error {
+	return db.writeErr()
+}
+
+// NewBatch returns a batch that can't be written
+func (db *Database) NewBatch() database.Batch {
+	return &batch{db: db}
+}
+
+func (db *Database) NewIterator() database.Iterator {
+	return db.NewIteratorWithStartAndPrefix(nil, nil)
+}
+
+func (db *Database) NewIteratorWithStart(start []byte) database.Iterator {
+	return db.NewIteratorWithStartAndPrefix(start, nil)
+}
+
+func (db *Database) NewIteratorWithPrefix(prefix []byte) database.Iterator {
+	return db.NewIteratorWithStartAndPrefix(nil, prefix)
+}
+
+func (db *Database) NewIteratorWithStartAndPrefix(start, prefix []byte) database.Iterator {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.closed {
+		return &nodb.Iterator{Err: database.ErrClosed}
+	}
+	return db.db.NewIteratorWithStartAndPrefix(start, prefix)
+}
+
+// Compact does nothing, as compacting the wrapped database is left to the
+// handles that write to it.
+func (db *Database) Compact(_, _ []byte) error {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.closed {
+		return database.ErrClosed
+	}
+	return nil
+}
+
+// Close closes the handle, but not the wrapped database.
+func (db *Database) Close() error {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	if db.closed {
+		return database.ErrClosed
+	}
+	db.closed = true
+	return nil
+}
+
+func (db *Database) HealthCheck(ctx context.Context) (interface{}, error) {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.closed {
+		return nil, database.ErrClosed
+	}
+	return db.db.HealthCheck(ctx)
+}
+
+func (db *Database) writeErr() error {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.closed {
+		return database.ErrClosed
+	}
+	return ErrReadOnly
+}
+
+// batch rejects all writes
+type batch struct {
+	db *Database
+}
+
+func (b *batch) Put(_, _ []byte) error {
+	return b.db.writeErr()
+}
+
+func (b *batch) Delete([]byte) error {
+	return b.db.writeErr()
+}
+
+func (*batch) Size() int {
+	return 0
+}
+
+func (b *batch) Write() error {
+	return b.db.writeErr()
+}
+
+func (*batch) Reset() {}
+
+func (*batch) Replay(database.KeyValueWriterDeleter) error {
+	return nil
+}
+
+func (b *batch) Inner() database.Batch {
+	return b
+}
@@ -0,0 +1,85 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package readonlydb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/memdb"
+)
+
+func TestReadsPassThrough(t *testing.T) {
+	require := require.New(t)
+
+	baseDB := memdb.New()
+	db := New(baseDB)
+
+	key := []byte("hello")
+	value := []byte("world")
+	_, err := db.Get(key)
+	require.ErrorIs(err, database.ErrNotFound)
+
+	// Writes made through other handles are visible.
+	require.NoError(baseDB.Put(key, value))
+
+	has, err := db.Has(key)
+	require.NoError(err)
+	require.True(has)
+
+	got, err := db.Get(key)
+	require.NoError(err)
+	require.Equal(value, got)
+
+	it := db.NewIterator()
+	require.True(it.Next())
+	require.Equal(key, it.Key())
+	require.Equal(value, it.Value())
+	require.False(it.Next())
+	require.NoError(it.Error())
+	it.Release()
+}
+
+func TestWritesRejected(t *testing.T) {
+	require := require.New(t)
+
+	baseDB := memdb.New()
+	db := New(baseDB)
+
+	key := []byte("hello")
+	value := []byte("world")
+	require.ErrorIs(db.Put(key, value), ErrReadOnly)
+	require.ErrorIs(db.Delete(key), ErrReadOnly)
+
+	batch := db.NewBatch()
+	require.ErrorIs(batch.Put(key, value), ErrReadOnly)
+	require.ErrorIs(batch.Delete(key), ErrReadOnly)
+	require.ErrorIs(batch.Write(), ErrReadOnly)
+
+	has, err := baseDB.Has(key)
+	require.NoError(err)
+	require.False(has)
+}
+
+func TestCloseKeepsWrappedDatabaseOpen(t *testing.T) {
+	require := require.New(t)
+
+	baseDB := memdb.New()
+	db := New(baseDB)
+
+	require.NoError(db.Close())
+	require.ErrorIs(db.Close(), database.ErrClosed)
+
+	_, err := db.Get([]byte("hello"))
+	require.ErrorIs(err, database.ErrClosed)
+	require.ErrorIs(db.Put([]byte("hello"), nil), database.ErrClosed)
+
+	it := db.NewIterator()
+	require.False(it.Next())
+	require.ErrorIs(it.Error(), database.ErrClosed)
+
+	require.NoError(baseDB.Put([]byte("hello"), []byte("world")))
+}
@@ -0,0 +1,44 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpcdb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var crc32c = crc32.MakeTable(crc32.Castagnoli)
+
+// NewChecksumMismatchCounter returns a counter, registered with [registerer],
+// for the values whose checksum doesn't match.
+func NewChecksumMismatchCounter(registerer prometheus.Registerer) (prometheus.Counter, error) {
+	counter := prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "db_checksum_mismatches",
+		Help: "Number of database values received whose checksum didn't match them",
+	})
+	return counter, registerer.Register(counter)
+}
+
+// checksum returns the checksum of [value], as sent alongside it.
+func checksum(value []byte) []byte {
+	b := make([]byte, crc32.Size)
+	binary.BigEndian.PutUint32(b, crc32.Checksum(value, crc32c))
+	return b
+}
+
+// verifyChecksum returns [ErrChecksumMismatch] if [value] doesn't match
+// [sum]. Values sent without a checksum are never rejected. Mismatches are
+// counted in [mismatches], if it isn't nil.
+func verifyChecksum(value, sum []byte, mismatches prometheus.Counter) error {
+	if len(sum) == 0 || bytes.Equal(checksum(value), sum) {
+		return nil
+	}
+	if mismatches != nil {
+		mismatches.Inc()
+	}
+	return ErrChecksumMismatch
+}
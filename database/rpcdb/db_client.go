@@ -6,13 +6,17 @@ package rpcdb
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"sync/atomic"
 
+	"github.com/prometheus/client_golang/prometheus"
+
 	"google.golang.org/protobuf/types/known/emptypb"
 
 	"github.com/ava-labs/avalanchego/database"
 	"github.com/ava-labs/avalanchego/database/nodb"
 	"github.com/ava-labs/avalanchego/utils"
+	"github.com/ava-labs/avalanchego/utils/math"
 	"github.com/ava-labs/avalanchego/utils/units"
 	"github.com/ava-labs/avalanchego/utils/wrappers"
 
@@ -28,14 +32,40 @@ const (
 )
 
 var (
-	_ database.Database = (*DatabaseClient)(nil)
-	_ database.Batch    = (*batch)(nil)
-	_ database.Iterator = (*iterator)(nil)
+	errKeyTooLarge = errors.New("key is larger than the max batch size")
+
+	_ database.Database    = (*DatabaseClient)(nil)
+	_ database.Snapshotter = (*DatabaseClient)(nil)
+	_ database.Batch       = (*batch)(nil)
+	_ database.Iterator    = (*iterator)(nil)
 )
 
+// ClientConfig tunes the requests a DatabaseClient makes. The zero value is
+// supported by every remote database instance.
+type ClientConfig struct {
+	// Number of pages of elements streamed iterators fetch ahead of their
+	// consumer. The remote database instance must serve IteratorStream, unless
+	// this is 0, in which case iterators fetch each page with a separate
+	// request.
+	IteratorPrefetch int
+	// Max size, in bytes, of the elements sent in a single request when
+	// writing a batch. Values larger than this are split across requests,
+	// which the remote database instance must support. If 0, batches are sent
+	// in chunks of [maxBatchSize] bytes and values are never split.
+	MaxBatchSize int
+	// If true, values are sent and requested along with their checksum, so
+	// that values corrupted in transit are detected. Values received from
+	// remote database instances that don't send checksums aren't verified.
+	Checksums bool
+	// Incremented for each value received that doesn't match its checksum.
+	// Optional.
+	ChecksumMismatches prometheus.Counter
+}
+
 // DatabaseClient is an implementation of database that talks over RPC.
 type DatabaseClient struct {
 	client rpcdbpb.DatabaseClient
+	config ClientConfig
 
 	closed     utils.AtomicBool
 	batchIndex int64
@@ -46,10 +76,34 @@ func NewClient(client rpcdbpb.DatabaseClient) *DatabaseClient {
 	return &DatabaseClient{client: client}
 }
 
+// NewClientWithConfig returns a database instance connected to a remote
+// database instance that makes its requests according to [config].
+func NewClientWithConfig(client rpcdbpb.DatabaseClient, config ClientConfig) *DatabaseClient {
+	return &DatabaseClient{
+		client: client,
+		config: config,
+	}
+}
+
+// checksum returns the checksum to send [value] with, if any.
+func (db *DatabaseClient) checksum(value []byte) []byte {
+	if !db.config.Checksums {
+		return nil
+	}
+	return checksum(value)
+}
+
 // Has attempts to return if the database has a key with the provided value.
 func (db *DatabaseClient) Has(key []byte) (bool, error) {
+	return db.has(0, key)
+}
+
+// has reads from the snapshot with ID [snapshotID], or from the database if
+// [snapshotID] is 0.
+func (db *DatabaseClient) has(snapshotID uint64, key []byte) (bool, error) {
 	resp, err := db.client.Has(context.Background(), &rpcdbpb.HasRequest{
-		Key: key,
+		Key:        key,
+		SnapshotId: snapshotID,
 	})
 	if err != nil {
 		return false, err
@@ -59,20 +113,35 @@ func (db *DatabaseClient) Has(key []byte) (bool, error) {
 
 // Get attempts to return the value that was mapped to the key that was provided
 func (db *DatabaseClient) Get(key []byte) ([]byte, error) {
+	return db.get(0, key)
+}
+
+// get reads from the snapshot with ID [snapshotID], or from the database if
+// [snapshotID] is 0.
+func (db *DatabaseClient) get(snapshotID uint64, key []byte) ([]byte, error) {
 	resp, err := db.client.Get(context.Background(), &rpcdbpb.GetRequest{
-		Key: key,
+		Key:        key,
+		Checksum:   db.config.Checksums,
+		SnapshotId: snapshotID,
 	})
 	if err != nil {
 		return nil, err
 	}
-	return resp.Value, errCodeToError[resp.Err]
+	if err := errCodeToError[resp.Err]; err != nil {
+		return nil, err
+	}
+	if err := verifyChecksum(resp.Value, resp.Checksum, db.config.ChecksumMismatches); err != nil {
+		return nil, err
+	}
+	return resp.Value, nil
 }
 
 // Put attempts to set the value this key maps to
 func (db *DatabaseClient) Put(key, value []byte) error {
 	resp, err := db.client.Put(context.Background(), &rpcdbpb.PutRequest{
-		Key:   key,
-		Value: value,
+		Key:      key,
+		Value:    value,
+		Checksum: db.checksum(value),
 	})
 	if err != nil {
 		return err
@@ -110,9 +179,20 @@ func (db *DatabaseClient) NewIteratorWithPrefix(prefix []byte) database.Iterator
 
 // NewIteratorWithStartAndPrefix returns a new empty iterator
 func (db *DatabaseClient) NewIteratorWithStartAndPrefix(start, prefix []byte) database.Iterator {
+	return db.newIterator(0, start, prefix)
+}
+
+// newIterator iterates over the snapshot with ID [snapshotID], or over the
+// database if [snapshotID] is 0.
+func (db *DatabaseClient) newIterator(snapshotID uint64, start, prefix []byte) database.Iterator {
+	if db.config.IteratorPrefetch > 0 {
+		return db.newStreamIterator(snapshotID, start, prefix)
+	}
+
 	resp, err := db.client.NewIteratorWithStartAndPrefix(context.Background(), &rpcdbpb.NewIteratorWithStartAndPrefixRequest{
-		Start:  start,
-		Prefix: prefix,
+		Start:      start,
+		Prefix:     prefix,
+		SnapshotId: snapshotID,
 	})
 	if err != nil {
 		return &nodb.Iterator{Err: err}
@@ -182,7 +262,16 @@ func (b *batch) Size() int {
 	return b.size
 }
 
+// Write sends the writes of the batch in requests of up to the max batch size.
+// The remote database instance only writes them once the last request is
+// received, so either all or none of the writes are applied.
 func (b *batch) Write() error {
+	maxSize := b.db.config.MaxBatchSize
+	splitValues := maxSize > 0
+	if !splitValues {
+		maxSize = maxBatchSize
+	}
+
 	request := &rpcdbpb.WriteBatchRequest{
 		Id:        atomic.AddInt64(&b.db.batchIndex, 1),
 		Continues: true,
@@ -198,18 +287,35 @@ func (b *batch) Write() error {
 		keySet[key] = struct{}{}
 
 		sizeChange := baseElementSize + len(kv.key) + len(kv.value)
-		if newSize := currentSize + sizeChange; newSize > maxBatchSize {
-			resp, err := b.db.client.WriteBatch(context.Background(), request)
-			if err != nil {
-				return err
-			}
-			if err := errCodeToError[resp.Err]; err != nil {
+		if newSize := currentSize + sizeChange; newSize > maxSize && currentSize > 0 {
+			if err := b.send(request); err != nil {
 				return err
 			}
 			currentSize = 0
 			request.Deletes = request.Deletes[:0]
 			request.Puts = request.Puts[:0]
 		}
+
+		value := kv.value
+		if splitValues && sizeChange > maxSize {
+			if baseElementSize+len(kv.key) > maxSize {
+				b.abort(request.Id)
+				return errKeyTooLarge
+			}
+
+			// Send the start of the value in pieces so that the rest of it
+			// fits in the request that carries the put.
+			for baseElementSize+len(kv.key)+len(value) > maxSize {
+				pieceSize := math.Min(len(value), maxSize-baseElementSize)
+				request.PartialValue = value[:pieceSize]
+				if err := b.send(request); err != nil {
+					return err
+				}
+				value = value[pieceSize:]
+			}
+			request.PartialValue = nil
+			sizeChange = baseElementSize + len(kv.key) + len(value)
+		}
 		currentSize += sizeChange
 
 		if kv.delete {
@@ -219,7 +325,9 @@ func (b *batch) Write() error {
 		} else {
 			request.Puts = append(request.Puts, &rpcdbpb.PutRequest{
 				Key:   kv.key,
-				Value: kv.value,
+				Value: value,
+				// The checksum is of the whole value, even if it was split.
+				Checksum: b.db.checksum(kv.value),
 			})
 		}
 	}
@@ -232,6 +340,30 @@ func (b *batch) Write() error {
 	return errCodeToError[resp.Err]
 }
 
+// send sends a request of the batch that isn't the last one. If it fails, the
+// writes the remote database instance received for the batch are discarded.
+func (b *batch) send(request *rpcdbpb.WriteBatchRequest) error {
+	resp, err := b.db.client.WriteBatch(context.Background(), request)
+	if err == nil {
+		err = errCodeToError[resp.Err]
+	}
+	if err != nil {
+		b.abort(request.Id)
+	}
+	return err
+}
+
+// abort discards the writes the remote database instance received for the
+// batch with ID [id]. Remote database instances that don't support aborting a
+// batch keep them until they are shut down.
+func (b *batch) abort(id int64) {
+	_, _ = b.db.client.WriteBatch(context.Background(), &rpcdbpb.WriteBatchRequest{
+		Id:        id,
+		Continues: true,
+		Abort:     true,
+	})
+}
+
 func (b *batch) Reset() {
 	if cap(b.writes) > len(b.writes)*database.MaxExcessCapacityFactor {
 		b.writes = make([]keyValue, 0, cap(b.writes)/database.CapacityReductionFactor)
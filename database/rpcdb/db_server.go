@@ -8,28 +8,44 @@ import (
 	"encoding/json"
 	"errors"
 	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
 
 	"google.golang.org/protobuf/types/known/emptypb"
 
 	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/nodb"
 
 	rpcdbpb "github.com/ava-labs/avalanchego/proto/pb/rpcdb"
 )
 
+// DefaultSnapshotTTL is the time snapshots are held for if the client doesn't
+// release them.
+const DefaultSnapshotTTL = 5 * time.Minute
+
 var errUnknownIterator = errors.New("unknown iterator")
 
+// view is the managed database, or one of its snapshots, that reads are served
+// from.
+type view interface {
+	database.KeyValueReader
+	database.Iteratee
+}
+
 // DatabaseServer is a database that is managed over RPC.
 type DatabaseServer struct {
 	rpcdbpb.UnsafeDatabaseServer
 
-	db database.Database
+	db     database.Database
+	config ServerConfig
 
 	// batchLock protects [batches] from concurrent modifications. Note that
 	// [batchLock] does not protect the actual Batch. Batches are documented as
 	// not being safe for concurrent use. Therefore, it is up to the client to
 	// respect this invariant.
 	batchLock sync.Mutex
-	batches   map[int64]database.Batch
+	batches   map[int64]*pendingBatch
 
 	// iteratorLock protects [nextIteratorID] and [iterators] from concurrent
 	// modifications. Similarly to [batchLock], [iteratorLock] does not protect
@@ -39,20 +55,73 @@ type DatabaseServer struct {
 	iteratorLock   sync.RWMutex
 	nextIteratorID uint64
 	iterators      map[uint64]database.Iterator
+
+	// snapshotLock protects [nextSnapshotID] and [snapshots]. Snapshots are
+	// safe for concurrent use.
+	snapshotLock   sync.RWMutex
+	nextSnapshotID uint64
+	snapshots      map[uint64]*heldSnapshot
+}
+
+// heldSnapshot is a snapshot held until the client releases it or it expires.
+type heldSnapshot struct {
+	snapshot database.Snapshot
+	expiry   *time.Timer
+}
+
+// ServerConfig tunes a DatabaseServer.
+type ServerConfig struct {
+	// Incremented for each value received that doesn't match its checksum.
+	// Optional.
+	ChecksumMismatches prometheus.Counter
+	// Snapshots that the client doesn't release are released [SnapshotTTL]
+	// after they're created. Defaults to [DefaultSnapshotTTL] if 0.
+	SnapshotTTL time.Duration
 }
 
 // NewServer returns a database instance that is managed remotely
 func NewServer(db database.Database) *DatabaseServer {
+	return NewServerWithConfig(db, ServerConfig{})
+}
+
+// NewServerWithConfig returns a database instance that is managed remotely
+// according to [config].
+func NewServerWithConfig(db database.Database, config ServerConfig) *DatabaseServer {
+	if config.SnapshotTTL == 0 {
+		config.SnapshotTTL = DefaultSnapshotTTL
+	}
 	return &DatabaseServer{
 		db:        db,
-		batches:   make(map[int64]database.Batch),
+		config:    config,
+		batches:   make(map[int64]*pendingBatch),
 		iterators: make(map[uint64]database.Iterator),
+		// The ID 0 refers to the managed database itself.
+		nextSnapshotID: 1,
+		snapshots:      make(map[uint64]*heldSnapshot),
+	}
+}
+
+// view returns the snapshot with ID [id], or the managed database if [id] is
+// 0. Reads from a snapshot that doesn't exist, because it was released or
+// expired, fail with [database.ErrClosed].
+func (db *DatabaseServer) view(id uint64) view {
+	if id == 0 {
+		return db.db
+	}
+
+	db.snapshotLock.RLock()
+	defer db.snapshotLock.RUnlock()
+
+	held, exists := db.snapshots[id]
+	if !exists {
+		return &nodb.Database{}
 	}
+	return held.snapshot
 }
 
 // Has delegates the Has call to the managed database and returns the result
 func (db *DatabaseServer) Has(_ context.Context, req *rpcdbpb.HasRequest) (*rpcdbpb.HasResponse, error) {
-	has, err := db.db.Has(req.Key)
+	has, err := db.view(req.SnapshotId).Has(req.Key)
 	return &rpcdbpb.HasResponse{
 		Has: has,
 		Err: errorToErrCode[err],
@@ -61,16 +130,23 @@ func (db *DatabaseServer) Has(_ context.Context, req *rpcdbpb.HasRequest) (*rpcd
 
 // Get delegates the Get call to the managed database and returns the result
 func (db *DatabaseServer) Get(_ context.Context, req *rpcdbpb.GetRequest) (*rpcdbpb.GetResponse, error) {
-	value, err := db.db.Get(req.Key)
-	return &rpcdbpb.GetResponse{
+	value, err := db.view(req.SnapshotId).Get(req.Key)
+	resp := &rpcdbpb.GetResponse{
 		Value: value,
 		Err:   errorToErrCode[err],
-	}, errorToRPCError(err)
+	}
+	if err == nil && req.Checksum {
+		resp.Checksum = checksum(value)
+	}
+	return resp, errorToRPCError(err)
 }
 
 // Put delegates the Put call to the managed database and returns the result
 func (db *DatabaseServer) Put(_ context.Context, req *rpcdbpb.PutRequest) (*rpcdbpb.PutResponse, error) {
-	err := db.db.Put(req.Key, req.Value)
+	err := verifyChecksum(req.Value, req.Checksum, db.config.ChecksumMismatches)
+	if err == nil {
+		err = db.db.Put(req.Key, req.Value)
+	}
 	return &rpcdbpb.PutResponse{Err: errorToErrCode[err]}, errorToRPCError(err)
 }
 
@@ -88,12 +164,67 @@ func (db *DatabaseServer) Compact(_ context.Context, req *rpcdbpb.CompactRequest
 	return &rpcdbpb.CompactResponse{Err: errorToErrCode[err]}, errorToRPCError(err)
 }
 
-// Close delegates the Close call to the managed database and returns the result
+// Close releases the snapshots that are still held and delegates the Close call
+// to the managed database and returns the result
 func (db *DatabaseServer) Close(context.Context, *rpcdbpb.CloseRequest) (*rpcdbpb.CloseResponse, error) {
+	db.snapshotLock.Lock()
+	for id, held := range db.snapshots {
+		held.expiry.Stop()
+		held.snapshot.Release()
+		delete(db.snapshots, id)
+	}
+	db.snapshotLock.Unlock()
+
 	err := db.db.Close()
 	return &rpcdbpb.CloseResponse{Err: errorToErrCode[err]}, errorToRPCError(err)
 }
 
+// NewSnapshot pins a view of the managed database's current contents and
+// returns its ID. The snapshot is released once it expires if the client
+// doesn't release it before.
+func (db *DatabaseServer) NewSnapshot(context.Context, *emptypb.Empty) (*rpcdbpb.NewSnapshotResponse, error) {
+	snapshotter, ok := db.db.(database.Snapshotter)
+	if !ok {
+		err := database.ErrSnapshotsNotSupported
+		return &rpcdbpb.NewSnapshotResponse{Err: errorToErrCode[err]}, errorToRPCError(err)
+	}
+	snapshot, err := snapshotter.NewSnapshot()
+	if err != nil {
+		return &rpcdbpb.NewSnapshotResponse{Err: errorToErrCode[err]}, errorToRPCError(err)
+	}
+
+	db.snapshotLock.Lock()
+	defer db.snapshotLock.Unlock()
+
+	id := db.nextSnapshotID
+	db.snapshots[id] = &heldSnapshot{
+		snapshot: snapshot,
+		expiry: time.AfterFunc(db.config.SnapshotTTL, func() {
+			db.releaseSnapshot(id)
+		}),
+	}
+	db.nextSnapshotID++
+	return &rpcdbpb.NewSnapshotResponse{Id: id}, nil
+}
+
+// ReleaseSnapshot releases the snapshot with the requested ID
+func (db *DatabaseServer) ReleaseSnapshot(_ context.Context, req *rpcdbpb.ReleaseSnapshotRequest) (*rpcdbpb.ReleaseSnapshotResponse, error) {
+	db.releaseSnapshot(req.Id)
+	return &rpcdbpb.ReleaseSnapshotResponse{}, nil
+}
+
+func (db *DatabaseServer) releaseSnapshot(id uint64) {
+	db.snapshotLock.Lock()
+	held, exists := db.snapshots[id]
+	delete(db.snapshots, id)
+	db.snapshotLock.Unlock()
+
+	if exists {
+		held.expiry.Stop()
+		held.snapshot.Release()
+	}
+}
+
 // HealthCheck performs a heath check against the underlying database.
 func (db *DatabaseServer) HealthCheck(ctx context.Context, _ *emptypb.Empty) (*rpcdbpb.HealthCheckResponse, error) {
 	health, err := db.db.HealthCheck(ctx)
@@ -107,30 +238,58 @@ func (db *DatabaseServer) HealthCheck(ctx context.Context, _ *emptypb.Empty) (*r
 	}, err
 }
 
+// pendingBatch is a batch whose last request hasn't been received yet.
+type pendingBatch struct {
+	batch database.Batch
+	// Pieces of the value of the next put received so far
+	partialValue []byte
+}
+
 // WriteBatch takes in a set of key-value pairs and atomically writes them to
 // the internal database
 func (db *DatabaseServer) WriteBatch(_ context.Context, req *rpcdbpb.WriteBatchRequest) (*rpcdbpb.WriteBatchResponse, error) {
+	if req.Abort {
+		db.removeBatch(req.Id)
+		return &rpcdbpb.WriteBatchResponse{}, nil
+	}
+
 	db.batchLock.Lock()
-	batch, exists := db.batches[req.Id]
+	pending, exists := db.batches[req.Id]
 	if !exists {
-		batch = db.db.NewBatch()
-		db.batches[req.Id] = batch
+		pending = &pendingBatch{
+			batch: db.db.NewBatch(),
+		}
+		db.batches[req.Id] = pending
 	}
 	db.batchLock.Unlock()
 
-	for _, put := range req.Puts {
-		if err := batch.Put(put.Key, put.Value); err != nil {
+	if len(req.PartialValue) > 0 {
+		pending.partialValue = append(pending.partialValue, req.PartialValue...)
+		return &rpcdbpb.WriteBatchResponse{}, nil
+	}
+
+	for i, put := range req.Puts {
+		value := put.Value
+		if i == 0 && pending.partialValue != nil {
+			value = append(pending.partialValue, value...)
+			pending.partialValue = nil
+		}
+		err := verifyChecksum(value, put.Checksum, db.config.ChecksumMismatches)
+		if err == nil {
+			err = pending.batch.Put(put.Key, value)
+		}
+		if err != nil {
 			// Because we are reporting an error, we free the allocated batch.
-			delete(db.batches, req.Id)
+			db.removeBatch(req.Id)
 
 			return &rpcdbpb.WriteBatchResponse{Err: errorToErrCode[err]}, errorToRPCError(err)
 		}
 	}
 
 	for _, del := range req.Deletes {
-		if err := batch.Delete(del.Key); err != nil {
+		if err := pending.batch.Delete(del.Key); err != nil {
 			// Because we are reporting an error, we free the allocated batch.
-			delete(db.batches, req.Id)
+			db.removeBatch(req.Id)
 
 			return &rpcdbpb.WriteBatchResponse{Err: errorToErrCode[err]}, errorToRPCError(err)
 		}
@@ -140,18 +299,22 @@ func (db *DatabaseServer) WriteBatch(_ context.Context, req *rpcdbpb.WriteBatchR
 		return &rpcdbpb.WriteBatchResponse{}, nil
 	}
 
-	db.batchLock.Lock()
-	delete(db.batches, req.Id)
-	db.batchLock.Unlock()
+	db.removeBatch(req.Id)
 
-	err := batch.Write()
+	err := pending.batch.Write()
 	return &rpcdbpb.WriteBatchResponse{Err: errorToErrCode[err]}, errorToRPCError(err)
 }
 
+func (db *DatabaseServer) removeBatch(id int64) {
+	db.batchLock.Lock()
+	delete(db.batches, id)
+	db.batchLock.Unlock()
+}
+
 // NewIteratorWithStartAndPrefix allocates an iterator and returns the iterator
 // ID
 func (db *DatabaseServer) NewIteratorWithStartAndPrefix(_ context.Context, req *rpcdbpb.NewIteratorWithStartAndPrefixRequest) (*rpcdbpb.NewIteratorWithStartAndPrefixResponse, error) {
-	it := db.db.NewIteratorWithStartAndPrefix(req.Start, req.Prefix)
+	it := db.view(req.SnapshotId).NewIteratorWithStartAndPrefix(req.Start, req.Prefix)
 
 	db.iteratorLock.Lock()
 	defer db.iteratorLock.Unlock()
@@ -214,3 +377,44 @@ func (db *DatabaseServer) IteratorRelease(_ context.Context, req *rpcdbpb.Iterat
 	it.Release()
 	return &rpcdbpb.IteratorReleaseResponse{Err: errorToErrCode[err]}, errorToRPCError(err)
 }
+
+// IteratorStream iterates over the managed database and streams the elements
+// in pages. The client controls how far ahead of it the iteration runs through
+// the flow control of the stream.
+func (db *DatabaseServer) IteratorStream(req *rpcdbpb.IteratorStreamRequest, stream rpcdbpb.Database_IteratorStreamServer) error {
+	it := db.view(req.SnapshotId).NewIteratorWithStartAndPrefix(req.Start, req.Prefix)
+	defer it.Release()
+
+	// Report that the iterator was created, so that the client knows the
+	// elements written after this point may not be iterated over.
+	if err := stream.Send(&rpcdbpb.IteratorStreamResponse{}); err != nil {
+		return err
+	}
+
+	for {
+		size := 0
+		data := []*rpcdbpb.PutRequest(nil)
+		for size < maxBatchSize && it.Next() {
+			key := it.Key()
+			value := it.Value()
+			size += len(key) + len(value)
+
+			data = append(data, &rpcdbpb.PutRequest{
+				Key:   key,
+				Value: value,
+			})
+		}
+		if len(data) == 0 {
+			break
+		}
+		if err := stream.Send(&rpcdbpb.IteratorStreamResponse{Data: data}); err != nil {
+			return err
+		}
+	}
+
+	err := it.Error()
+	if rpcErr := errorToRPCError(err); rpcErr != nil {
+		return rpcErr
+	}
+	return stream.Send(&rpcdbpb.IteratorStreamResponse{Err: errorToErrCode[err]})
+}
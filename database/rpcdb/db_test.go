@@ -5,17 +5,27 @@ package rpcdb
 
 import (
 	"context"
+	"errors"
 	"net"
 	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	dto "github.com/prometheus/client_model/go"
 
 	"github.com/stretchr/testify/require"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/test/bufconn"
 
+	"google.golang.org/protobuf/types/known/emptypb"
+
 	"github.com/ava-labs/avalanchego/database"
 	"github.com/ava-labs/avalanchego/database/corruptabledb"
 	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/database/nodb"
+	"github.com/ava-labs/avalanchego/utils/units"
 	"github.com/ava-labs/avalanchego/vms/rpcchainvm/grpcutils"
 
 	rpcdbpb "github.com/ava-labs/avalanchego/proto/pb/rpcdb"
@@ -25,23 +35,31 @@ const (
 	bufSize = 1024 * 1024
 )
 
+var errTest = errors.New("non-nil error")
+
 type testDatabase struct {
-	client  *DatabaseClient
-	server  *memdb.Database
-	closeFn func()
+	client    *DatabaseClient
+	server    *memdb.Database
+	rpcServer *DatabaseServer
+	closeFn   func()
 }
 
 func setupDB(t testing.TB) *testDatabase {
+	return setupDBWithConfig(t, ClientConfig{})
+}
+
+func setupDBWithConfig(t testing.TB, config ClientConfig) *testDatabase {
 	db := &testDatabase{
 		server: memdb.New(),
 	}
+	db.rpcServer = NewServer(db.server)
 
 	listener := bufconn.Listen(bufSize)
 	serverCloser := grpcutils.ServerCloser{}
 
 	serverFunc := func(opts []grpc.ServerOption) *grpc.Server {
 		server := grpc.NewServer(opts...)
-		rpcdbpb.RegisterDatabaseServer(server, NewServer(db.server))
+		rpcdbpb.RegisterDatabaseServer(server, db.rpcServer)
 		serverCloser.Add(server)
 		return server
 	}
@@ -61,7 +79,7 @@ func setupDB(t testing.TB) *testDatabase {
 		t.Fatalf("Failed to dial: %s", err)
 	}
 
-	db.client = NewClient(rpcdbpb.NewDatabaseClient(conn))
+	db.client = NewClientWithConfig(rpcdbpb.NewDatabaseClient(conn), config)
 	db.closeFn = func() {
 		serverCloser.Stop()
 		_ = conn.Close()
@@ -79,6 +97,101 @@ func TestInterface(t *testing.T) {
 	}
 }
 
+func TestInterfaceStreamingIterators(t *testing.T) {
+	for _, test := range database.Tests {
+		db := setupDBWithConfig(t, ClientConfig{
+			IteratorPrefetch: 2,
+		})
+		test(t, db.client)
+
+		db.closeFn()
+	}
+}
+
+func TestInterfaceSplitValues(t *testing.T) {
+	for _, test := range database.Tests {
+		db := setupDBWithConfig(t, ClientConfig{
+			MaxBatchSize: 8 * units.KiB,
+		})
+		test(t, db.client)
+
+		db.closeFn()
+	}
+}
+
+func TestSnapshotInterface(t *testing.T) {
+	for _, test := range database.SnapshotTests {
+		db := setupDB(t)
+		test(t, db.client)
+
+		db.closeFn()
+	}
+}
+
+func TestSnapshotInterfaceStreamingIterators(t *testing.T) {
+	for _, test := range database.SnapshotTests {
+		db := setupDBWithConfig(t, ClientConfig{
+			IteratorPrefetch: 2,
+		})
+		test(t, db.client)
+
+		db.closeFn()
+	}
+}
+
+func TestSnapshotsNotSupported(t *testing.T) {
+	require := require.New(t)
+
+	server := NewServer(&nodb.Database{})
+	resp, err := server.NewSnapshot(context.Background(), &emptypb.Empty{})
+	require.NoError(err)
+	require.ErrorIs(errCodeToError[resp.Err], database.ErrSnapshotsNotSupported)
+}
+
+func TestCloseReleasesSnapshots(t *testing.T) {
+	require := require.New(t)
+
+	db := setupDB(t)
+	defer db.closeFn()
+
+	key := []byte("key")
+	require.NoError(db.client.Put(key, []byte("value")))
+
+	snapshot, err := db.client.NewSnapshot()
+	require.NoError(err)
+	require.NoError(db.client.Close())
+
+	_, err = snapshot.Get(key)
+	require.ErrorIs(err, database.ErrClosed)
+}
+
+func TestSnapshotExpiry(t *testing.T) {
+	require := require.New(t)
+
+	key := []byte("key")
+	db := memdb.New()
+	require.NoError(db.Put(key, []byte("value")))
+
+	server := NewServerWithConfig(db, ServerConfig{
+		SnapshotTTL: time.Millisecond,
+	})
+	resp, err := server.NewSnapshot(context.Background(), &emptypb.Empty{})
+	require.NoError(err)
+	require.Zero(resp.Err)
+
+	require.Eventually(func() bool {
+		getResp, err := server.Get(context.Background(), &rpcdbpb.GetRequest{
+			Key:        key,
+			SnapshotId: resp.Id,
+		})
+		return err == nil && errCodeToError[getResp.Err] == database.ErrClosed
+	}, time.Second, time.Millisecond)
+
+	server.snapshotLock.RLock()
+	defer server.snapshotLock.RUnlock()
+	require.Empty(server.snapshots)
+}
+
 func FuzzInterface(f *testing.F) {
 	for _, test := range database.FuzzTests {
 		db := setupDB(f)
@@ -99,6 +212,252 @@ func BenchmarkInterface(b *testing.B) {
 	}
 }
 
+func TestStreamingIteratorPrefetch(t *testing.T) {
+	require := require.New(t)
+
+	db := setupDBWithConfig(t, ClientConfig{
+		IteratorPrefetch: 1,
+	})
+	defer db.closeFn()
+
+	// Write enough elements to fill several pages.
+	const numElements = 64
+	value := make([]byte, maxBatchSize/16)
+	for i := 0; i < numElements; i++ {
+		require.NoError(db.server.Put([]byte{byte(i)}, value))
+	}
+
+	it := db.client.NewIterator()
+	// Writes made once the iterator is created aren't iterated over.
+	require.NoError(db.server.Put([]byte{numElements}, value))
+
+	count := 0
+	for it.Next() {
+		require.Equal([]byte{byte(count)}, it.Key())
+		require.Equal(value, it.Value())
+		count++
+	}
+	require.NoError(it.Error())
+	require.Equal(numElements, count)
+	it.Release()
+
+	// Releasing an iterator before reaching its end stops the stream.
+	it = db.client.NewIterator()
+	require.True(it.Next())
+	it.Release()
+}
+
+func TestInterfaceChecksums(t *testing.T) {
+	for _, test := range database.Tests {
+		db := setupDBWithConfig(t, ClientConfig{
+			MaxBatchSize: 8 * units.KiB,
+			Checksums:    true,
+		})
+		test(t, db.client)
+
+		db.closeFn()
+	}
+}
+
+func counterValue(t *testing.T, counter prometheus.Counter) float64 {
+	metric := &dto.Metric{}
+	require.NoError(t, counter.Write(metric))
+	return metric.Counter.GetValue()
+}
+
+// corruptingClient flips the first bit of the values it reads.
+type corruptingClient struct {
+	rpcdbpb.DatabaseClient
+}
+
+func (c *corruptingClient) Get(
+	ctx context.Context,
+	req *rpcdbpb.GetRequest,
+	opts ...grpc.CallOption,
+) (*rpcdbpb.GetResponse, error) {
+	resp, err := c.DatabaseClient.Get(ctx, req, opts...)
+	if err == nil && len(resp.Value) > 0 {
+		resp.Value[0] ^= 1
+	}
+	return resp, err
+}
+
+func TestClientDetectsCorruptedValues(t *testing.T) {
+	require := require.New(t)
+
+	db := setupDB(t)
+	defer db.closeFn()
+
+	key := []byte("key")
+	require.NoError(db.server.Put(key, []byte("value")))
+
+	mismatches, err := NewChecksumMismatchCounter(prometheus.NewRegistry())
+	require.NoError(err)
+	client := NewClientWithConfig(
+		&corruptingClient{
+			DatabaseClient: db.client.client,
+		},
+		ClientConfig{
+			Checksums:          true,
+			ChecksumMismatches: mismatches,
+		},
+	)
+	_, err = client.Get(key)
+	require.ErrorIs(err, ErrChecksumMismatch)
+	require.Equal(float64(1), counterValue(t, mismatches))
+
+	// Without checksums, the corruption goes unnoticed.
+	client = NewClient(&corruptingClient{
+		DatabaseClient: db.client.client,
+	})
+	value, err := client.Get(key)
+	require.NoError(err)
+	require.NotEqual([]byte("value"), value)
+}
+
+func TestServerRejectsCorruptedValues(t *testing.T) {
+	require := require.New(t)
+
+	mismatches, err := NewChecksumMismatchCounter(prometheus.NewRegistry())
+	require.NoError(err)
+	memDB := memdb.New()
+	server := NewServerWithConfig(memDB, ServerConfig{
+		ChecksumMismatches: mismatches,
+	})
+
+	key := []byte("key")
+	resp, err := server.Put(context.Background(), &rpcdbpb.PutRequest{
+		Key:      key,
+		Value:    []byte("corrupted"),
+		Checksum: checksum([]byte("value")),
+	})
+	require.NoError(err)
+	require.Equal(ErrChecksumMismatch, errCodeToError[resp.Err])
+
+	batchResp, err := server.WriteBatch(context.Background(), &rpcdbpb.WriteBatchRequest{
+		Puts: []*rpcdbpb.PutRequest{
+			{
+				Key:      key,
+				Value:    []byte("corrupted"),
+				Checksum: checksum([]byte("value")),
+			},
+		},
+	})
+	require.NoError(err)
+	require.Equal(ErrChecksumMismatch, errCodeToError[batchResp.Err])
+	require.Empty(server.batches)
+
+	has, err := memDB.Has(key)
+	require.NoError(err)
+	require.False(has)
+	require.Equal(float64(2), counterValue(t, mismatches))
+}
+
+func TestBatchSplitsLargeValues(t *testing.T) {
+	require := require.New(t)
+
+	db := setupDBWithConfig(t, ClientConfig{
+		MaxBatchSize: 64,
+	})
+	defer db.closeFn()
+
+	largeValue := make([]byte, 1000)
+	for i := range largeValue {
+		largeValue[i] = byte(i)
+	}
+
+	batch := db.client.NewBatch()
+	for i := 0; i < 32; i++ {
+		require.NoError(batch.Put([]byte{byte(i)}, []byte{byte(i)}))
+	}
+	require.NoError(batch.Put([]byte("large"), largeValue))
+	require.NoError(batch.Put([]byte("last"), []byte("value")))
+	require.NoError(batch.Write())
+
+	for i := 0; i < 32; i++ {
+		value, err := db.server.Get([]byte{byte(i)})
+		require.NoError(err)
+		require.Equal([]byte{byte(i)}, value)
+	}
+	value, err := db.server.Get([]byte("large"))
+	require.NoError(err)
+	require.Equal(largeValue, value)
+	value, err = db.server.Get([]byte("last"))
+	require.NoError(err)
+	require.Equal([]byte("value"), value)
+	require.Empty(db.rpcServer.batches)
+}
+
+func TestBatchKeyTooLarge(t *testing.T) {
+	require := require.New(t)
+
+	db := setupDBWithConfig(t, ClientConfig{
+		MaxBatchSize: 64,
+	})
+	defer db.closeFn()
+
+	batch := db.client.NewBatch()
+	require.NoError(batch.Put([]byte("small"), []byte("value")))
+	require.NoError(batch.Put(make([]byte, 64), []byte("value")))
+	require.ErrorIs(batch.Write(), errKeyTooLarge)
+
+	has, err := db.server.Has([]byte("small"))
+	require.NoError(err)
+	require.False(has)
+	require.Empty(db.rpcServer.batches)
+}
+
+// failingWriteBatchClient fails the WriteBatch requests, other than those
+// aborting a batch, once [remaining] requests succeeded.
+type failingWriteBatchClient struct {
+	rpcdbpb.DatabaseClient
+
+	remaining int
+}
+
+func (c *failingWriteBatchClient) WriteBatch(
+	ctx context.Context,
+	req *rpcdbpb.WriteBatchRequest,
+	opts ...grpc.CallOption,
+) (*rpcdbpb.WriteBatchResponse, error) {
+	if !req.Abort {
+		if c.remaining == 0 {
+			return nil, errTest
+		}
+		c.remaining--
+	}
+	return c.DatabaseClient.WriteBatch(ctx, req, opts...)
+}
+
+func TestBatchAbortedOnFailure(t *testing.T) {
+	require := require.New(t)
+
+	db := setupDBWithConfig(t, ClientConfig{})
+	defer db.closeFn()
+
+	client := NewClientWithConfig(
+		&failingWriteBatchClient{
+			DatabaseClient: db.client.client,
+			remaining:      2,
+		},
+		ClientConfig{
+			MaxBatchSize: 64,
+		},
+	)
+
+	batch := client.NewBatch()
+	for i := 0; i < 32; i++ {
+		require.NoError(batch.Put([]byte{byte(i)}, []byte{byte(i)}))
+	}
+	require.ErrorIs(batch.Write(), errTest)
+
+	// None of the writes sent before the failure are applied.
+	it := db.server.NewIterator()
+	require.False(it.Next())
+	it.Release()
+	require.Empty(db.rpcServer.batches)
+}
+
 func TestHealthCheck(t *testing.T) {
 	require := require.New(t)
 
@@ -4,17 +4,27 @@
 package rpcdb
 
 import (
+	"errors"
+
 	"github.com/ava-labs/avalanchego/database"
 )
 
 var (
+	// ErrChecksumMismatch is returned when a value doesn't match the checksum
+	// it was sent with.
+	ErrChecksumMismatch = errors.New("checksum mismatch")
+
 	errCodeToError = map[uint32]error{
 		1: database.ErrClosed,
 		2: database.ErrNotFound,
+		3: ErrChecksumMismatch,
+		4: database.ErrSnapshotsNotSupported,
 	}
 	errorToErrCode = map[error]uint32{
-		database.ErrClosed:   1,
-		database.ErrNotFound: 2,
+		database.ErrClosed:                1,
+		database.ErrNotFound:              2,
+		ErrChecksumMismatch:               3,
+		database.ErrSnapshotsNotSupported: 4,
 	}
 )
 
@@ -0,0 +1,82 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpcdb
+
+import (
+	"context"
+	"sync"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/ava-labs/avalanchego/database"
+
+	rpcdbpb "github.com/ava-labs/avalanchego/proto/pb/rpcdb"
+)
+
+var _ database.Snapshot = (*snapshot)(nil)
+
+// snapshot is a snapshot pinned by the remote database instance.
+type snapshot struct {
+	db          *DatabaseClient
+	id          uint64
+	releaseOnce sync.Once
+}
+
+// NewSnapshot pins a view of the remote database instance's current contents.
+func (db *DatabaseClient) NewSnapshot() (database.Snapshot, error) {
+	resp, err := db.client.NewSnapshot(context.Background(), &emptypb.Empty{})
+	if status.Code(err) == codes.Unimplemented {
+		// The remote database instance predates snapshots.
+		return nil, database.ErrSnapshotsNotSupported
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := errCodeToError[resp.Err]; err != nil {
+		return nil, err
+	}
+	return &snapshot{
+		db: db,
+		id: resp.Id,
+	}, nil
+}
+
+func (s *snapshot) Has(key []byte) (bool, error) {
+	return s.db.has(s.id, key)
+}
+
+func (s *snapshot) Get(key []byte) ([]byte, error) {
+	return s.db.get(s.id, key)
+}
+
+func (s *snapshot) NewIterator() database.Iterator {
+	return s.NewIteratorWithStartAndPrefix(nil, nil)
+}
+
+func (s *snapshot) NewIteratorWithStart(start []byte) database.Iterator {
+	return s.NewIteratorWithStartAndPrefix(start, nil)
+}
+
+func (s *snapshot) NewIteratorWithPrefix(prefix []byte) database.Iterator {
+	return s.NewIteratorWithStartAndPrefix(nil, prefix)
+}
+
+func (s *snapshot) NewIteratorWithStartAndPrefix(start, prefix []byte) database.Iterator {
+	return s.db.newIterator(s.id, start, prefix)
+}
+
+// Release releases the snapshot held by the remote database instance. The
+// snapshot is only released once.
+func (s *snapshot) Release() {
+	s.releaseOnce.Do(func() {
+		// A snapshot that can't be released is released once the remote
+		// database instance is closed, so the error is dropped.
+		_, _ = s.db.client.ReleaseSnapshot(context.Background(), &rpcdbpb.ReleaseSnapshotRequest{
+			Id: s.id,
+		})
+	})
+}
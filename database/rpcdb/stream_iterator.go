@@ -0,0 +1,154 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpcdb
+
+import (
+	"context"
+	"io"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/nodb"
+
+	rpcdbpb "github.com/ava-labs/avalanchego/proto/pb/rpcdb"
+)
+
+var _ database.Iterator = (*streamIterator)(nil)
+
+// iteratorPage is a page of elements streamed by the server, or the error
+// that ended the iteration.
+type iteratorPage struct {
+	data []*rpcdbpb.PutRequest
+	err  error
+}
+
+// streamIterator iterates over the elements streamed by the server. The pages
+// are fetched in the background, up to the prefetch window of the database
+// ahead of the consumer.
+type streamIterator struct {
+	db     *DatabaseClient
+	cancel context.CancelFunc
+
+	// Closed once the stream ends
+	pages chan iteratorPage
+
+	data []*rpcdbpb.PutRequest
+	err  error
+}
+
+func (db *DatabaseClient) newStreamIterator(snapshotID uint64, start, prefix []byte) database.Iterator {
+	ctx, cancel := context.WithCancel(context.Background())
+	stream, err := db.client.IteratorStream(ctx, &rpcdbpb.IteratorStreamRequest{
+		Start:      start,
+		Prefix:     prefix,
+		SnapshotId: snapshotID,
+	})
+	if err != nil {
+		cancel()
+		return &nodb.Iterator{Err: err}
+	}
+	// Wait for the iterator to be created, so that the writes made once this
+	// function returns aren't iterated over.
+	if _, err := stream.Recv(); err != nil {
+		cancel()
+		return &nodb.Iterator{Err: err}
+	}
+
+	it := &streamIterator{
+		db:     db,
+		cancel: cancel,
+		pages:  make(chan iteratorPage, db.config.IteratorPrefetch),
+	}
+	go it.fetch(ctx, stream)
+	return it
+}
+
+// fetch receives the pages of [stream] until it ends or [ctx] is cancelled.
+func (it *streamIterator) fetch(ctx context.Context, stream rpcdbpb.Database_IteratorStreamClient) {
+	defer close(it.pages)
+
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			return
+		}
+		page := iteratorPage{
+			err: err,
+		}
+		if err == nil {
+			page.data = resp.Data
+			page.err = errCodeToError[resp.Err]
+		}
+		if len(page.data) == 0 && page.err == nil {
+			continue
+		}
+
+		select {
+		case it.pages <- page:
+		case <-ctx.Done():
+			return
+		}
+		if page.err != nil {
+			return
+		}
+	}
+}
+
+// Next attempts to move the iterator to the next element and returns if this
+// succeeded
+func (it *streamIterator) Next() bool {
+	if it.db.closed.GetValue() {
+		it.data = nil
+		if it.err == nil {
+			it.err = database.ErrClosed
+		}
+		return false
+	}
+	if len(it.data) > 1 {
+		it.data[0] = nil
+		it.data = it.data[1:]
+		return true
+	}
+
+	it.data = nil
+	if it.err != nil {
+		return false
+	}
+	page, ok := <-it.pages
+	if !ok {
+		return false
+	}
+	if page.err != nil {
+		it.err = page.err
+		return false
+	}
+	it.data = page.data
+	return true
+}
+
+// Error returns any that occurred while iterating
+func (it *streamIterator) Error() error {
+	return it.err
+}
+
+// Key returns the key of the current element
+func (it *streamIterator) Key() []byte {
+	if len(it.data) == 0 {
+		return nil
+	}
+	return it.data[0].Key
+}
+
+// Value returns the value of the current element
+func (it *streamIterator) Value() []byte {
+	if len(it.data) == 0 {
+		return nil
+	}
+	return it.data[0].Value
+}
+
+// Release frees any resources held by the iterator
+func (it *streamIterator) Release() {
+	it.cancel()
+	it.data = nil
+}
@@ -0,0 +1,50 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package snapshots
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/nodb"
+	"github.com/ava-labs/avalanchego/database/readonlydb"
+)
+
+var _ database.Database = (*snapshotDatabase)(nil)
+
+// snapshotDatabase exposes a snapshot as a database. It's only used through a
+// readonlydb.Database, so it's never written to.
+type snapshotDatabase struct {
+	database.Snapshot
+}
+
+// NewDatabase returns a read-only database that reads from [snapshot]. Closing
+// the database doesn't release [snapshot].
+func NewDatabase(snapshot database.Snapshot) database.Database {
+	return readonlydb.New(&snapshotDatabase{Snapshot: snapshot})
+}
+
+func (*snapshotDatabase) Put(_, _ []byte) error {
+	return readonlydb.ErrReadOnly
+}
+
+func (*snapshotDatabase) Delete([]byte) error {
+	return readonlydb.ErrReadOnly
+}
+
+func (*snapshotDatabase) NewBatch() database.Batch {
+	return &nodb.Batch{}
+}
+
+func (*snapshotDatabase) Compact(_, _ []byte) error {
+	return nil
+}
+
+func (*snapshotDatabase) Close() error {
+	return nil
+}
+
+func (*snapshotDatabase) HealthCheck(context.Context) (interface{}, error) {
+	return nil, nil
+}
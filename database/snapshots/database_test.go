@@ -0,0 +1,43 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package snapshots
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/database/readonlydb"
+)
+
+func TestNewDatabase(t *testing.T) {
+	require := require.New(t)
+
+	db := memdb.New()
+	r := NewRegistry(db, time.Hour, 1)
+	defer r.Close()
+
+	key := []byte("key")
+	require.NoError(db.Put(key, []byte("old")))
+
+	id, _, err := r.Create()
+	require.NoError(err)
+	snapshot, err := r.Get(id)
+	require.NoError(err)
+	snapshotDB := NewDatabase(snapshot)
+
+	require.NoError(db.Put(key, []byte("new")))
+
+	value, err := snapshotDB.Get(key)
+	require.NoError(err)
+	require.Equal([]byte("old"), value)
+	require.ErrorIs(snapshotDB.Put(key, nil), readonlydb.ErrReadOnly)
+
+	r.Release(id)
+	_, err = snapshotDB.Get(key)
+	require.ErrorIs(err, database.ErrClosed)
+}
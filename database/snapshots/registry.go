@@ -0,0 +1,139 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package snapshots hands out short-lived snapshots of a database addressed by
+// ID, so that a query an API serves across several calls, such as paging
+// through UTXOs, observes a consistent view of the database.
+package snapshots
+
+import (
+	"crypto/rand"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+var (
+	// ErrUnknownSnapshot is returned when a snapshot was never created, has
+	// expired or was released.
+	ErrUnknownSnapshot = errors.New("unknown snapshot")
+
+	errTooManySnapshots = errors.New("too many snapshots")
+)
+
+// Registry holds the snapshots of a database until they expire.
+type Registry struct {
+	db           database.Database
+	ttl          time.Duration
+	maxSnapshots int
+
+	lock      sync.Mutex
+	closed    bool
+	snapshots map[ids.ID]*entry
+}
+
+type entry struct {
+	snapshot database.Snapshot
+	timer    *time.Timer
+}
+
+// NewRegistry returns a registry of snapshots of [db]. Snapshots are released
+// [ttl] after they're created. At most [maxSnapshots] snapshots are held at
+// once.
+func NewRegistry(db database.Database, ttl time.Duration, maxSnapshots int) *Registry {
+	return &Registry{
+		db:           db,
+		ttl:          ttl,
+		maxSnapshots: maxSnapshots,
+		snapshots:    make(map[ids.ID]*entry),
+	}
+}
+
+// Create pins a snapshot of the database's current contents. Returns the ID
+// of the snapshot and the time it expires at.
+func (r *Registry) Create() (ids.ID, time.Time, error) {
+	snapshotter, ok := r.db.(database.Snapshotter)
+	if !ok {
+		return ids.Empty, time.Time{}, database.ErrSnapshotsNotSupported
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if r.closed {
+		return ids.Empty, time.Time{}, database.ErrClosed
+	}
+	if len(r.snapshots) >= r.maxSnapshots {
+		return ids.Empty, time.Time{}, errTooManySnapshots
+	}
+
+	var id ids.ID
+	if _, err := rand.Read(id[:]); err != nil {
+		return ids.Empty, time.Time{}, err
+	}
+	snapshot, err := snapshotter.NewSnapshot()
+	if err != nil {
+		return ids.Empty, time.Time{}, err
+	}
+
+	expiry := time.Now().Add(r.ttl)
+	r.snapshots[id] = &entry{
+		snapshot: snapshot,
+		timer: time.AfterFunc(r.ttl, func() {
+			r.Release(id)
+		}),
+	}
+	return id, expiry, nil
+}
+
+// Get returns the snapshot with ID [id]. Reads from the snapshot fail with
+// [database.ErrClosed] once it expires.
+func (r *Registry) Get(id ids.ID) (database.Snapshot, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	e, ok := r.snapshots[id]
+	if !ok {
+		return nil, ErrUnknownSnapshot
+	}
+	return e.snapshot, nil
+}
+
+// Release releases the snapshot with ID [id] before it expires.
+func (r *Registry) Release(id ids.ID) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	e, ok := r.snapshots[id]
+	if !ok {
+		return
+	}
+	delete(r.snapshots, id)
+	e.timer.Stop()
+	e.snapshot.Release()
+}
+
+// Len returns the number of snapshots held.
+func (r *Registry) Len() int {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	return len(r.snapshots)
+}
+
+// Close releases every snapshot held. Snapshots can't be created once the
+// registry is closed.
+func (r *Registry) Close() {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.closed = true
+	for id, e := range r.snapshots {
+		delete(r.snapshots, id)
+		e.timer.Stop()
+		e.snapshot.Release()
+	}
+}
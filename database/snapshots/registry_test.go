@@ -0,0 +1,107 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package snapshots
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/database/nodb"
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+func TestRegistry(t *testing.T) {
+	require := require.New(t)
+
+	db := memdb.New()
+	r := NewRegistry(db, time.Hour, 2)
+	defer r.Close()
+
+	key := []byte("key")
+	require.NoError(db.Put(key, []byte("old")))
+
+	id, expiry, err := r.Create()
+	require.NoError(err)
+	require.True(expiry.After(time.Now()))
+
+	require.NoError(db.Put(key, []byte("new")))
+
+	snapshot, err := r.Get(id)
+	require.NoError(err)
+	value, err := snapshot.Get(key)
+	require.NoError(err)
+	require.Equal([]byte("old"), value)
+
+	r.Release(id)
+	_, err = r.Get(id)
+	require.ErrorIs(err, ErrUnknownSnapshot)
+	_, err = snapshot.Get(key)
+	require.ErrorIs(err, database.ErrClosed)
+
+	_, err = r.Get(ids.GenerateTestID())
+	require.ErrorIs(err, ErrUnknownSnapshot)
+}
+
+func TestRegistryExpiry(t *testing.T) {
+	require := require.New(t)
+
+	r := NewRegistry(memdb.New(), time.Millisecond, 1)
+	defer r.Close()
+
+	id, _, err := r.Create()
+	require.NoError(err)
+
+	require.Eventually(func() bool {
+		return r.Len() == 0
+	}, time.Second, time.Millisecond)
+
+	_, err = r.Get(id)
+	require.ErrorIs(err, ErrUnknownSnapshot)
+}
+
+func TestRegistryMaxSnapshots(t *testing.T) {
+	require := require.New(t)
+
+	r := NewRegistry(memdb.New(), time.Hour, 1)
+	defer r.Close()
+
+	id, _, err := r.Create()
+	require.NoError(err)
+
+	_, _, err = r.Create()
+	require.ErrorIs(err, errTooManySnapshots)
+
+	r.Release(id)
+	_, _, err = r.Create()
+	require.NoError(err)
+}
+
+func TestRegistryClose(t *testing.T) {
+	require := require.New(t)
+
+	r := NewRegistry(memdb.New(), time.Hour, 1)
+
+	id, _, err := r.Create()
+	require.NoError(err)
+	snapshot, err := r.Get(id)
+	require.NoError(err)
+
+	r.Close()
+	require.Zero(r.Len())
+	_, err = snapshot.Has([]byte("key"))
+	require.ErrorIs(err, database.ErrClosed)
+
+	_, _, err = r.Create()
+	require.ErrorIs(err, database.ErrClosed)
+}
+
+func TestRegistrySnapshotsNotSupported(t *testing.T) {
+	r := NewRegistry(&nodb.Database{}, time.Hour, 1)
+	_, _, err := r.Create()
+	require.ErrorIs(t, err, database.ErrSnapshotsNotSupported)
+}
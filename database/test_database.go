@@ -52,6 +52,13 @@ var Tests = []func(t *testing.T, db Database){
 	TestPutGetEmpty,
 }
 
+// SnapshotTests is a list of the tests of databases that implement Snapshotter
+var SnapshotTests = []func(t *testing.T, db Database){
+	TestSnapshot,
+	TestSnapshotIterator,
+	TestSnapshotRelease,
+}
+
 var FuzzTests = []func(*testing.F, Database){
 	FuzzKeyValue,
 }
@@ -1355,6 +1362,114 @@ func TestPutGetEmpty(t *testing.T, db Database) {
 	require.Empty(value) // May be nil or empty byte slice.
 }
 
+// TestSnapshot tests that a snapshot doesn't observe the writes made after it
+// was created.
+func TestSnapshot(t *testing.T, db Database) {
+	require := require.New(t)
+
+	require.Implements((*Snapshotter)(nil), db)
+	snapshotter := db.(Snapshotter)
+
+	key1 := []byte("snapshot1")
+	key2 := []byte("snapshot2")
+	value1 := []byte("value1")
+	value2 := []byte("value2")
+
+	require.NoError(db.Put(key1, value1))
+
+	snapshot, err := snapshotter.NewSnapshot()
+	require.NoError(err)
+	defer snapshot.Release()
+
+	require.NoError(db.Put(key1, value2))
+	require.NoError(db.Put(key2, value2))
+
+	value, err := snapshot.Get(key1)
+	require.NoError(err)
+	require.Equal(value1, value)
+
+	has, err := snapshot.Has(key2)
+	require.NoError(err)
+	require.False(has)
+
+	_, err = snapshot.Get(key2)
+	require.ErrorIs(err, ErrNotFound)
+
+	value, err = db.Get(key1)
+	require.NoError(err)
+	require.Equal(value2, value)
+}
+
+// TestSnapshotIterator tests that iterating over a snapshot returns the
+// elements of the database when the snapshot was created.
+func TestSnapshotIterator(t *testing.T, db Database) {
+	require := require.New(t)
+
+	require.Implements((*Snapshotter)(nil), db)
+	snapshotter := db.(Snapshotter)
+
+	prefix := []byte("snapshotit")
+	key1 := []byte("snapshotit1")
+	key2 := []byte("snapshotit2")
+	key3 := []byte("snapshotit3")
+	value1 := []byte("value1")
+	value2 := []byte("value2")
+	value3 := []byte("value3")
+
+	require.NoError(db.Put(key1, value1))
+	require.NoError(db.Put(key2, value2))
+
+	snapshot, err := snapshotter.NewSnapshot()
+	require.NoError(err)
+	defer snapshot.Release()
+
+	require.NoError(db.Delete(key1))
+	require.NoError(db.Put(key2, value3))
+	require.NoError(db.Put(key3, value3))
+
+	iterator := snapshot.NewIteratorWithPrefix(prefix)
+	defer iterator.Release()
+
+	require.True(iterator.Next())
+	require.Equal(key1, iterator.Key())
+	require.Equal(value1, iterator.Value())
+	require.True(iterator.Next())
+	require.Equal(key2, iterator.Key())
+	require.Equal(value2, iterator.Value())
+	require.False(iterator.Next())
+	require.NoError(iterator.Error())
+
+	startIterator := snapshot.NewIteratorWithStartAndPrefix(key2, prefix)
+	defer startIterator.Release()
+
+	require.True(startIterator.Next())
+	require.Equal(key2, startIterator.Key())
+	require.Equal(value2, startIterator.Value())
+	require.False(startIterator.Next())
+	require.NoError(startIterator.Error())
+}
+
+// TestSnapshotRelease tests that reading from a released snapshot fails.
+func TestSnapshotRelease(t *testing.T, db Database) {
+	require := require.New(t)
+
+	require.Implements((*Snapshotter)(nil), db)
+	snapshotter := db.(Snapshotter)
+
+	key := []byte("snapshotrelease")
+	require.NoError(db.Put(key, []byte("value")))
+
+	snapshot, err := snapshotter.NewSnapshot()
+	require.NoError(err)
+	snapshot.Release()
+
+	_, err = snapshot.Get(key)
+	require.ErrorIs(err, ErrClosed)
+
+	_, err = snapshot.Has(key)
+	require.ErrorIs(err, ErrClosed)
+}
+
 func FuzzKeyValue(f *testing.F, db Database) {
 	f.Fuzz(func(t *testing.T, key []byte, value []byte) {
 		require := require.New(t)
@@ -22,11 +22,28 @@ type AliaserWriter interface {
 	RemoveAliases(id ID)
 }
 
+// AliasUpdate is a change of the aliases of an ID.
+type AliasUpdate struct {
+	ID ID
+	// Aliases are the aliases that were given to, or removed from, [ID].
+	Aliases []string
+	// Removed is true if [Aliases] were removed.
+	Removed bool
+}
+
+// AliasWatcher allows one to be notified of the changes of the aliases.
+type AliasWatcher interface {
+	// WatchAliases calls [f] with every change of the aliases made after it
+	// returns, until the returned function is called.
+	WatchAliases(f func(AliasUpdate)) (stop func(), err error)
+}
+
 // Aliaser allows one to give an ID aliases and lookup the aliases given to an
 // ID.
 type Aliaser interface {
 	AliaserReader
 	AliaserWriter
+	AliasWatcher
 	PrimaryAliasOrDefault(id ID) string
 }
 
@@ -34,12 +51,16 @@ type aliaser struct {
 	lock    sync.RWMutex
 	dealias map[string]ID
 	aliases map[ID][]string
+
+	nextWatcherID int
+	watchers      map[int]func(AliasUpdate)
 }
 
 func NewAliaser() Aliaser {
 	return &aliaser{
-		dealias: make(map[string]ID),
-		aliases: make(map[ID][]string),
+		dealias:  make(map[string]ID),
+		aliases:  make(map[ID][]string),
+		watchers: make(map[int]func(AliasUpdate)),
 	}
 }
 
@@ -94,6 +115,10 @@ func (a *aliaser) Alias(id ID, alias string) error {
 
 	a.dealias[alias] = id
 	a.aliases[id] = append(a.aliases[id], alias)
+	a.notify(AliasUpdate{
+		ID:      id,
+		Aliases: []string{alias},
+	})
 	return nil
 }
 
@@ -107,6 +132,37 @@ func (a *aliaser) RemoveAliases(id ID) {
 	for _, alias := range aliases {
 		delete(a.dealias, alias)
 	}
+	if len(aliases) > 0 {
+		a.notify(AliasUpdate{
+			ID:      id,
+			Aliases: aliases,
+			Removed: true,
+		})
+	}
+}
+
+// WatchAliases calls [f] while the aliases are locked, so [f] must not access
+// them.
+func (a *aliaser) WatchAliases(f func(AliasUpdate)) (func(), error) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	watcherID := a.nextWatcherID
+	a.nextWatcherID++
+	a.watchers[watcherID] = f
+	return func() {
+		a.lock.Lock()
+		defer a.lock.Unlock()
+
+		delete(a.watchers, watcherID)
+	}, nil
+}
+
+// notify assumes [a.lock] is held.
+func (a *aliaser) notify(update AliasUpdate) {
+	for _, f := range a.watchers {
+		f(update)
+	}
 }
 
 // GetRelevantAliases returns the aliases with the redundant identity alias
@@ -17,6 +17,11 @@ func TestAliaser(t *testing.T) {
 	}
 }
 
+func TestAliaserWatch(t *testing.T) {
+	aliaser := NewAliaser()
+	AliaserWatchTest(require.New(t), aliaser, aliaser)
+}
+
 func TestPrimaryAliasOrDefaultTest(t *testing.T) {
 	require := require.New(t)
 	aliaser := NewAliaser()
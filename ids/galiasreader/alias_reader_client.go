@@ -5,13 +5,21 @@ package galiasreader
 
 import (
 	"context"
+	"errors"
+
+	"google.golang.org/protobuf/types/known/emptypb"
 
 	"github.com/ava-labs/avalanchego/ids"
 
 	aliasreaderpb "github.com/ava-labs/avalanchego/proto/pb/aliasreader"
 )
 
-var _ ids.AliaserReader = (*Client)(nil)
+var (
+	errNotWatching = errors.New("server isn't watching the aliases")
+
+	_ ids.AliaserReader = (*Client)(nil)
+	_ ids.AliasWatcher  = (*Client)(nil)
+)
 
 // Client implements alias lookups that talk over RPC.
 type Client struct {
@@ -53,3 +61,50 @@ func (c *Client) Aliases(id ids.ID) ([]string, error) {
 	}
 	return resp.Aliases, nil
 }
+
+// WatchAliases calls [f] with every change of the aliases pushed by the
+// server, until the returned function is called or the connection to the
+// server is lost. [f] isn't called concurrently.
+func (c *Client) WatchAliases(f func(ids.AliasUpdate)) (func(), error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	stream, err := c.client.Watch(ctx, &emptypb.Empty{})
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	// The header is sent once the server watches the aliases, so no change
+	// made after this call returns is missed.
+	md, err := stream.Header()
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	if len(md.Get(watchingHeader)) == 0 {
+		// The stream ended without headers, Recv reports why.
+		_, err := stream.Recv()
+		cancel()
+		if err == nil {
+			err = errNotWatching
+		}
+		return nil, err
+	}
+
+	go func() {
+		for {
+			msg, err := stream.Recv()
+			if err != nil {
+				return
+			}
+			id, err := ids.ToID(msg.Id)
+			if err != nil {
+				continue
+			}
+			f(ids.AliasUpdate{
+				ID:      id,
+				Aliases: msg.Aliases,
+				Removed: msg.Removed,
+			})
+		}
+	}()
+	return cancel, nil
+}
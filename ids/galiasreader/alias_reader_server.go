@@ -5,12 +5,22 @@ package galiasreader
 
 import (
 	"context"
+	"sync"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"google.golang.org/protobuf/types/known/emptypb"
 
 	"github.com/ava-labs/avalanchego/ids"
 
 	aliasreaderpb "github.com/ava-labs/avalanchego/proto/pb/aliasreader"
 )
 
+// watchingHeader is sent by Watch once the aliases are being watched.
+const watchingHeader = "watching"
+
 var _ aliasreaderpb.AliasReaderServer = (*Server)(nil)
 
 // Server enables alias lookups over RPC.
@@ -64,3 +74,61 @@ func (s *Server) Aliases(
 		Aliases: aliases,
 	}, err
 }
+
+// Watch streams the changes of the aliases until the client cancels the call.
+// The header of the stream is sent once the changes are watched, so that the
+// client knows which changes it will be sent.
+func (s *Server) Watch(_ *emptypb.Empty, stream aliasreaderpb.AliasReader_WatchServer) error {
+	watcher, ok := s.aliaser.(ids.AliasWatcher)
+	if !ok {
+		return status.Error(codes.Unimplemented, "aliases can't be watched")
+	}
+
+	// Updates are queued so that the aliaser isn't blocked by the client.
+	var (
+		lock    sync.Mutex
+		updates []ids.AliasUpdate
+		pending = make(chan struct{}, 1)
+	)
+	stop, err := watcher.WatchAliases(func(update ids.AliasUpdate) {
+		lock.Lock()
+		updates = append(updates, update)
+		lock.Unlock()
+
+		select {
+		case pending <- struct{}{}:
+		default:
+		}
+	})
+	if err != nil {
+		return err
+	}
+	defer stop()
+
+	if err := stream.SendHeader(metadata.Pairs(watchingHeader, "true")); err != nil {
+		return err
+	}
+	for {
+		select {
+		case <-pending:
+		case <-stream.Context().Done():
+			return nil
+		}
+
+		lock.Lock()
+		toSend := updates
+		updates = nil
+		lock.Unlock()
+
+		for _, update := range toSend {
+			err := stream.Send(&aliasreaderpb.AliasUpdate{
+				Id:      update.ID[:],
+				Aliases: update.Aliases,
+				Removed: update.Removed,
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
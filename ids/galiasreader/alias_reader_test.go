@@ -11,6 +11,8 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"google.golang.org/grpc/test/bufconn"
 
 	"github.com/ava-labs/avalanchego/ids"
@@ -27,35 +29,67 @@ func TestInterface(t *testing.T) {
 	require := require.New(t)
 
 	for _, test := range ids.AliasTests {
-		listener := bufconn.Listen(bufSize)
-		serverCloser := grpcutils.ServerCloser{}
 		w := ids.NewAliaser()
+		r, closeFn := setupClient(require, w)
+		test(require, r, w)
+		closeFn()
+	}
+}
 
-		serverFunc := func(opts []grpc.ServerOption) *grpc.Server {
-			server := grpc.NewServer(opts...)
-			aliasreaderpb.RegisterAliasReaderServer(server, NewServer(w))
-			serverCloser.Add(server)
-			return server
-		}
+func TestWatchAliases(t *testing.T) {
+	require := require.New(t)
 
-		go grpcutils.Serve(listener, serverFunc)
+	w := ids.NewAliaser()
+	r, closeFn := setupClient(require, w)
+	defer closeFn()
 
-		dialer := grpc.WithContextDialer(
-			func(context.Context, string) (net.Conn, error) {
-				return listener.Dial()
-			},
-		)
+	ids.AliaserWatchTest(require, r, w)
+}
 
-		dopts := grpcutils.DefaultDialOptions
-		dopts = append(dopts, dialer)
-		conn, err := grpcutils.Dial("", dopts...)
-		require.NoError(err)
+// readerOnly hides the [ids.AliasWatcher] implementation of the wrapped
+// aliaser.
+type readerOnly struct {
+	ids.AliaserReader
+}
 
-		r := NewClient(aliasreaderpb.NewAliasReaderClient(conn))
-		test(require, r, w)
+func TestWatchAliasesUnsupported(t *testing.T) {
+	require := require.New(t)
+
+	r, closeFn := setupClient(require, readerOnly{ids.NewAliaser()})
+	defer closeFn()
+
+	_, err := r.WatchAliases(func(ids.AliasUpdate) {})
+	require.Equal(codes.Unimplemented, status.Code(err))
+}
+
+func setupClient(require *require.Assertions, reader ids.AliaserReader) (*Client, func()) {
+	listener := bufconn.Listen(bufSize)
+	serverCloser := grpcutils.ServerCloser{}
+
+	serverFunc := func(opts []grpc.ServerOption) *grpc.Server {
+		server := grpc.NewServer(opts...)
+		aliasreaderpb.RegisterAliasReaderServer(server, NewServer(reader))
+		serverCloser.Add(server)
+		return server
+	}
+
+	go grpcutils.Serve(listener, serverFunc)
+
+	dialer := grpc.WithContextDialer(
+		func(context.Context, string) (net.Conn, error) {
+			return listener.Dial()
+		},
+	)
+
+	dopts := grpcutils.DefaultDialOptions
+	dopts = append(dopts, dialer)
+	conn, err := grpcutils.Dial("", dopts...)
+	require.NoError(err)
 
+	closeFn := func() {
 		serverCloser.Stop()
 		_ = conn.Close()
 		_ = listener.Close()
 	}
+	return NewClient(aliasreaderpb.NewAliasReaderClient(conn)), closeFn
 }
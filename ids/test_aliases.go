@@ -3,7 +3,11 @@
 
 package ids
 
-import "github.com/stretchr/testify/require"
+import (
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
 
 var AliasTests = []func(require *require.Assertions, r AliaserReader, w AliaserWriter){
 	AliaserLookupErrorTest,
@@ -104,3 +108,44 @@ func AliaserRemoveAliasTest(require *require.Assertions, r AliaserReader, w Alia
 	err = w.Alias(id1, "Dark Night Rises")
 	require.NoError(err)
 }
+
+// AliaserWatchTest verifies that the changes made through [w] are reported to
+// the watchers of [watcher], in order, until they stop watching.
+func AliaserWatchTest(require *require.Assertions, watcher AliasWatcher, w AliaserWriter) {
+	id := ID{'D', 'i', 'c', 'k', ' ', 'G', 'r', 'a', 'y', 's', 'o', 'n'}
+	updates := make(chan AliasUpdate, 4)
+	stop, err := watcher.WatchAliases(func(update AliasUpdate) {
+		updates <- update
+	})
+	require.NoError(err)
+
+	require.NoError(w.Alias(id, "Robin"))
+	require.NoError(w.Alias(id, "Nightwing"))
+	w.RemoveAliases(id)
+
+	expected := []AliasUpdate{
+		{ID: id, Aliases: []string{"Robin"}},
+		{ID: id, Aliases: []string{"Nightwing"}},
+		{ID: id, Aliases: []string{"Robin", "Nightwing"}, Removed: true},
+	}
+	for _, update := range expected {
+		select {
+		case received := <-updates:
+			require.Equal(update, received)
+		case <-time.After(5 * time.Second):
+			require.FailNow("timed out waiting for an alias update")
+		}
+	}
+
+	stop()
+	// Removing the aliases of an ID without aliases isn't a change.
+	w.RemoveAliases(id)
+	require.NoError(w.Alias(id, "Red Hood"))
+	select {
+	case update := <-updates:
+		// Updates that were in flight when the watcher stopped may still be
+		// delivered over RPC, but nothing made after is.
+		require.FailNow("received an update after stopping", "%v", update)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
@@ -13,6 +13,7 @@ import (
 
 	"go.uber.org/zap"
 
+	"github.com/ava-labs/avalanchego/api/pagination"
 	"github.com/ava-labs/avalanchego/api/server"
 	"github.com/ava-labs/avalanchego/chains"
 	"github.com/ava-labs/avalanchego/codec"
@@ -65,6 +66,9 @@ type Config struct {
 	ConsensusAcceptorGroup snow.AcceptorGroup
 	APIServer              server.PathAdder
 	ShutdownF              func()
+	// Overrides the maximum page size of the paginated API endpoints. The
+	// maximum page size can't exceed [MaxFetchedByRange].
+	MaxPageSizes pagination.Limits
 }
 
 // Indexer causes accepted containers for a given chain
@@ -92,6 +96,7 @@ func NewIndexer(config Config) (Indexer, error) {
 		blockIndices:           map[ids.ID]Index{},
 		pathAdder:              config.APIServer,
 		shutdownF:              config.ShutdownF,
+		maxPageSizes:           config.MaxPageSizes,
 	}
 
 	if err := indexer.codec.RegisterCodec(
@@ -125,6 +130,9 @@ type indexer struct {
 	// Used to add API endpoint for new indices
 	pathAdder server.PathAdder
 
+	// Maximum page sizes of the paginated API endpoints
+	maxPageSizes pagination.Limits
+
 	// If true, allow running in such a way that could allow the creation
 	// of an index which could be missing accepted containers.
 	allowIncompleteIndex bool
@@ -348,11 +356,15 @@ func (i *indexer) registerChainHelper(
 	codec := json.NewCodec()
 	apiServer.RegisterCodec(codec, "application/json")
 	apiServer.RegisterCodec(codec, "application/json;charset=UTF-8")
-	if err := apiServer.RegisterService(&service{Index: index}, "index"); err != nil {
+	if err := apiServer.RegisterService(&service{Index: index, maxPageSizes: i.maxPageSizes}, "index"); err != nil {
 		_ = index.Close()
 		return nil, err
 	}
-	handler := &common.HTTPHandler{LockOptions: common.NoLock, Handler: apiServer}
+	handler := &common.HTTPHandler{
+		LockOptions: common.NoLock,
+		Handler:     apiServer,
+		OpenAPI:     json.PaginatedOpenAPIPathItem("Index API", "index", &service{}, i.maxPageSizes.Effective(MaxPageSizeBounds)),
+	}
 	if err := i.pathAdder.AddRoute(handler, &sync.RWMutex{}, "index/"+name, "/"+endpoint); err != nil {
 		_ = index.Close()
 		return nil, err
@@ -8,14 +8,26 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/ava-labs/avalanchego/api/pagination"
 	"github.com/ava-labs/avalanchego/database"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/utils/formatting"
 	"github.com/ava-labs/avalanchego/utils/json"
 )
 
+// GetContainerRangeEndpoint is the name of the GetContainerRange endpoint in
+// the node's max page sizes.
+const GetContainerRangeEndpoint = "index.getContainerRange"
+
+// MaxPageSizeBounds bounds the containers fetched by range to
+// MaxFetchedByRange.
+var MaxPageSizeBounds = pagination.Limits{
+	GetContainerRangeEndpoint: MaxFetchedByRange,
+}
+
 type service struct {
 	Index
+	maxPageSizes pagination.Limits
 }
 
 type FormattedContainer struct {
@@ -89,10 +101,15 @@ type GetContainerRangeResponse struct {
 // GetContainerRange returns the transactions at index [startIndex], [startIndex+1], ... , [startIndex+n-1]
 // If [n] == 0, returns an empty response (i.e. null).
 // If [startIndex] > the last accepted index, returns an error (unless the above apply.)
-// If [n] > [MaxFetchedByRange], returns an error.
+// If [n] exceeds the maximum page size, returns an error.
 // If we run out of transactions, returns the ones fetched before running out.
 func (s *service) GetContainerRange(_ *http.Request, args *GetContainerRangeArgs, reply *GetContainerRangeResponse) error {
-	containers, err := s.Index.GetContainerRange(uint64(args.StartIndex), uint64(args.NumToFetch))
+	numToFetch := uint64(args.NumToFetch)
+	maxPageSize := s.maxPageSizes.MaxPageSize(GetContainerRangeEndpoint, MaxPageSizeBounds[GetContainerRangeEndpoint])
+	if err := pagination.VerifyPageSize(numToFetch, maxPageSize); err != nil {
+		return err
+	}
+	containers, err := s.Index.GetContainerRange(uint64(args.StartIndex), numToFetch)
 	if err != nil {
 		return err
 	}
@@ -290,6 +290,26 @@ func InboundAppResponse(
 	}
 }
 
+func InboundAppError(
+	chainID ids.ID,
+	requestID uint32,
+	errorCode int32,
+	errorMessage string,
+	nodeID ids.NodeID,
+) InboundMessage {
+	return &inboundMessage{
+		nodeID: nodeID,
+		op:     AppErrorOp,
+		message: &p2p.AppError{
+			ChainId:      chainID[:],
+			RequestId:    requestID,
+			ErrorCode:    errorCode,
+			ErrorMessage: errorMessage,
+		},
+		expiration: mockable.MaxTime,
+	}
+}
+
 func encodeIDs(ids []ids.ID, result [][]byte) {
 	for i, id := range ids {
 		copy := id
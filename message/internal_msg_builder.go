@@ -248,8 +248,10 @@ func InternalQueryFailed(
 }
 
 type AppRequestFailed struct {
-	ChainID   ids.ID
-	RequestID uint32
+	ChainID      ids.ID
+	RequestID    uint32
+	ErrorCode    int32
+	ErrorMessage string
 }
 
 func (m *AppRequestFailed) GetChainId() []byte {
@@ -264,13 +266,17 @@ func InternalAppRequestFailed(
 	nodeID ids.NodeID,
 	chainID ids.ID,
 	requestID uint32,
+	errorCode int32,
+	errorMessage string,
 ) InboundMessage {
 	return &inboundMessage{
 		nodeID: nodeID,
 		op:     AppRequestFailedOp,
 		message: &AppRequestFailed{
-			ChainID:   chainID,
-			RequestID: requestID,
+			ChainID:      chainID,
+			RequestID:    requestID,
+			ErrorCode:    errorCode,
+			ErrorMessage: errorMessage,
 		},
 		expiration: mockable.MaxTime,
 	}
@@ -557,6 +557,23 @@ func TestMessage(t *testing.T) {
 			bypassThrottling: true,
 			bytesSaved:       true,
 		},
+		{
+			desc: "app_error message with no compression",
+			op:   AppErrorOp,
+			msg: &p2ppb.Message{
+				Message: &p2ppb.Message_AppError{
+					AppError: &p2ppb.AppError{
+						ChainId:      testID[:],
+						RequestId:    1,
+						ErrorCode:    1,
+						ErrorMessage: "error",
+					},
+				},
+			},
+			gzipCompress:     false,
+			bypassThrottling: true,
+			bytesSaved:       false,
+		},
 		{
 			desc: "app_gossip message with no compression",
 			op:   AppGossipOp,
@@ -52,6 +52,7 @@ const (
 	AppRequestFailedOp
 	AppResponseOp
 	AppGossipOp
+	AppErrorOp
 	// Cross chain:
 	CrossChainAppRequestOp
 	CrossChainAppRequestFailedOp
@@ -93,6 +94,7 @@ var (
 		PutOp,
 		ChitsOp,
 		AppResponseOp,
+		AppErrorOp,
 	}
 	// AppGossip is the only message that is sent unrequested without the
 	// expectation of a response
@@ -162,6 +164,7 @@ var (
 		AppRequestFailedOp,
 		AppGossipOp,
 		AppResponseOp,
+		AppErrorOp,
 		// Cross chain
 		CrossChainAppRequestOp,
 		CrossChainAppRequestFailedOp,
@@ -177,6 +180,7 @@ var (
 		GetFailedOp:                     PutOp,
 		QueryFailedOp:                   ChitsOp,
 		AppRequestFailedOp:              AppResponseOp,
+		AppErrorOp:                      AppResponseOp,
 		CrossChainAppRequestFailedOp:    CrossChainAppResponseOp,
 	}
 	UnrequestedOps = map[Op]struct{}{
@@ -263,6 +267,8 @@ func (op Op) String() string {
 		return "app_response"
 	case AppGossipOp:
 		return "app_gossip"
+	case AppErrorOp:
+		return "app_error"
 	// Cross chain
 	case CrossChainAppRequestOp:
 		return "cross_chain_app_request"
@@ -337,6 +343,8 @@ func Unwrap(m *p2ppb.Message) (interface{}, error) {
 		return msg.AppResponse, nil
 	case *p2ppb.Message_AppGossip:
 		return msg.AppGossip, nil
+	case *p2ppb.Message_AppError:
+		return msg.AppError, nil
 	default:
 		return nil, fmt.Errorf("%w: %T", errUnknownMessageType, msg)
 	}
@@ -388,6 +396,8 @@ func ToOp(m *p2ppb.Message) (Op, error) {
 		return AppResponseOp, nil
 	case *p2ppb.Message_AppGossip:
 		return AppGossipOp, nil
+	case *p2ppb.Message_AppError:
+		return AppErrorOp, nil
 	default:
 		return 0, fmt.Errorf("%w: %T", errUnknownMessageType, msg)
 	}
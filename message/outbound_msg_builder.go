@@ -20,12 +20,14 @@ var _ OutboundMsgBuilder = (*outMsgBuilder)(nil)
 type OutboundMsgBuilder interface {
 	Version(
 		networkID uint32,
+		networkName string,
 		myTime uint64,
 		ip ips.IPPort,
 		myVersion string,
 		myVersionTime uint64,
 		sig []byte,
 		trackedSubnets []ids.ID,
+		vmVersions map[ids.ID]string,
 	) (OutboundMessage, error)
 
 	PeerList(
@@ -35,7 +37,15 @@ type OutboundMsgBuilder interface {
 
 	Ping() (OutboundMessage, error)
 
-	Pong(uptimePercentage uint8) (OutboundMessage, error)
+	// Pong announces the maintenance window from [maintenanceStart] to
+	// [maintenanceEnd], unless they are zero.
+	Pong(
+		uptimePercentage uint8,
+		vmVersions map[ids.ID]string,
+		myTime time.Time,
+		maintenanceStart time.Time,
+		maintenanceEnd time.Time,
+	) (OutboundMessage, error)
 
 	GetStateSummaryFrontier(
 		chainID ids.ID,
@@ -146,6 +156,13 @@ type OutboundMsgBuilder interface {
 		msg []byte,
 	) (OutboundMessage, error)
 
+	AppError(
+		chainID ids.ID,
+		requestID uint32,
+		errorCode int32,
+		errorMessage string,
+	) (OutboundMessage, error)
+
 	AppGossip(
 		chainID ids.ID,
 		msg []byte,
@@ -179,13 +196,26 @@ func (b *outMsgBuilder) Ping() (OutboundMessage, error) {
 	)
 }
 
-func (b *outMsgBuilder) Pong(uptimePercentage uint8) (OutboundMessage, error) {
+func (b *outMsgBuilder) Pong(
+	uptimePercentage uint8,
+	vmVersions map[ids.ID]string,
+	myTime time.Time,
+	maintenanceStart time.Time,
+	maintenanceEnd time.Time,
+) (OutboundMessage, error) {
+	pong := &p2ppb.Pong{
+		UptimePct:  uint32(uptimePercentage),
+		VmVersions: encodeVMVersions(vmVersions),
+		MyTimeMs:   uint64(myTime.UnixMilli()),
+	}
+	if !maintenanceStart.IsZero() && !maintenanceEnd.IsZero() {
+		pong.MaintenanceStartMs = uint64(maintenanceStart.UnixMilli())
+		pong.MaintenanceEndMs = uint64(maintenanceEnd.UnixMilli())
+	}
 	return b.builder.createOutbound(
 		&p2ppb.Message{
 			Message: &p2ppb.Message_Pong{
-				Pong: &p2ppb.Pong{
-					UptimePct: uint32(uptimePercentage),
-				},
+				Pong: pong,
 			},
 		},
 		false,
@@ -195,12 +225,14 @@ func (b *outMsgBuilder) Pong(uptimePercentage uint8) (OutboundMessage, error) {
 
 func (b *outMsgBuilder) Version(
 	networkID uint32,
+	networkName string,
 	myTime uint64,
 	ip ips.IPPort,
 	myVersion string,
 	myVersionTime uint64,
 	sig []byte,
 	trackedSubnets []ids.ID,
+	vmVersions map[ids.ID]string,
 ) (OutboundMessage, error) {
 	subnetIDBytes := make([][]byte, len(trackedSubnets))
 	encodeIDs(trackedSubnets, subnetIDBytes)
@@ -216,6 +248,8 @@ func (b *outMsgBuilder) Version(
 					MyVersionTime:  myVersionTime,
 					Sig:            sig,
 					TrackedSubnets: subnetIDBytes,
+					NetworkName:    networkName,
+					VmVersions:     encodeVMVersions(vmVersions),
 				},
 			},
 		},
@@ -608,6 +642,23 @@ func (b *outMsgBuilder) AppResponse(chainID ids.ID, requestID uint32, msg []byte
 	)
 }
 
+func (b *outMsgBuilder) AppError(chainID ids.ID, requestID uint32, errorCode int32, errorMessage string) (OutboundMessage, error) {
+	return b.builder.createOutbound(
+		&p2ppb.Message{
+			Message: &p2ppb.Message_AppError{
+				AppError: &p2ppb.AppError{
+					ChainId:      chainID[:],
+					RequestId:    requestID,
+					ErrorCode:    errorCode,
+					ErrorMessage: errorMessage,
+				},
+			},
+		},
+		b.compress,
+		false,
+	)
+}
+
 func (b *outMsgBuilder) AppGossip(chainID ids.ID, msg []byte) (OutboundMessage, error) {
 	return b.builder.createOutbound(
 		&p2ppb.Message{
@@ -622,3 +673,18 @@ func (b *outMsgBuilder) AppGossip(chainID ids.ID, msg []byte) (OutboundMessage,
 		false,
 	)
 }
+
+func encodeVMVersions(vmVersions map[ids.ID]string) []*p2ppb.VmVersion {
+	if len(vmVersions) == 0 {
+		return nil
+	}
+	result := make([]*p2ppb.VmVersion, 0, len(vmVersions))
+	for vmID, version := range vmVersions {
+		vmID := vmID
+		result = append(result, &p2ppb.VmVersion{
+			VmId:    vmID[:],
+			Version: version,
+		})
+	}
+	return result
+}
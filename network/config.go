@@ -10,11 +10,14 @@ import (
 
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/network/dialer"
+	"github.com/ava-labs/avalanchego/network/policy"
 	"github.com/ava-labs/avalanchego/network/throttling"
 	"github.com/ava-labs/avalanchego/snow/networking/tracker"
 	"github.com/ava-labs/avalanchego/snow/uptime"
 	"github.com/ava-labs/avalanchego/snow/validators"
+	"github.com/ava-labs/avalanchego/utils/clockskew"
 	"github.com/ava-labs/avalanchego/utils/ips"
+	"github.com/ava-labs/avalanchego/utils/maintenance"
 )
 
 // HealthConfig describes parameters for network layer health checks.
@@ -23,6 +26,11 @@ type HealthConfig struct {
 	// be connected to to be considered healthy.
 	MinConnectedPeers uint `json:"minConnectedPeers"`
 
+	// MinConnectedStake is the minimum portion, in [0, 1], of the stake of
+	// each tracked subnet that the network should be connected to to be
+	// considered healthy. This node's own stake is counted as connected.
+	MinConnectedStake float64 `json:"minConnectedStake"`
+
 	// MaxTimeSinceMsgReceived is the maximum amount of time since the network
 	// last received a message to be considered healthy.
 	MaxTimeSinceMsgReceived time.Duration `json:"maxTimeSinceMsgReceived"`
@@ -90,6 +98,24 @@ type DelayConfig struct {
 	MaxReconnectDelay time.Duration `json:"maxReconnectDelay"`
 }
 
+type ConnectivityConfig struct {
+	// TargetConnectedStake is the portion, in [0, 1], of the stake of each
+	// tracked subnet that the network attempts to stay connected to. While the
+	// network is connected to less, the reconnection backoff of the subnet's
+	// disconnected validators is skipped, starting with the heaviest. If 0,
+	// the reconnection backoff is never skipped.
+	TargetConnectedStake float64 `json:"targetConnectedStake"`
+
+	// ConnectivityRepairFreq is how often the connected stake of each tracked
+	// subnet is reported and compared against the target. If 0, the connected
+	// stake is neither reported nor repaired.
+	ConnectivityRepairFreq time.Duration `json:"connectivityRepairFreq"`
+
+	// MaxRepairDialsPerSec is the maximum rate at which the reconnection
+	// backoff of validators is skipped.
+	MaxRepairDialsPerSec float64 `json:"maxRepairDialsPerSec"`
+}
+
 type ThrottlerConfig struct {
 	InboundConnUpgradeThrottlerConfig throttling.InboundConnUpgradeThrottlerConfig `json:"inboundConnUpgradeThrottlerConfig"`
 	InboundMsgThrottlerConfig         throttling.InboundMsgThrottlerConfig         `json:"inboundMsgThrottlerConfig"`
@@ -102,6 +128,7 @@ type Config struct {
 	PeerListGossipConfig `json:"peerListGossipConfig"`
 	TimeoutConfig        `json:"timeoutConfigs"`
 	DelayConfig          `json:"delayConfig"`
+	ConnectivityConfig   `json:"connectivityConfig"`
 	ThrottlerConfig      ThrottlerConfig `json:"throttlerConfig"`
 
 	DialerConfig dialer.Config `json:"dialerConfig"`
@@ -117,6 +144,18 @@ type Config struct {
 	PingFrequency      time.Duration     `json:"pingFrequency"`
 	AllowPrivateIPs    bool              `json:"allowPrivateIPs"`
 
+	// NetworkName is sent in the handshake, and peers only connect if their
+	// network names match. This partitions networks that share a network ID.
+	NetworkName string `json:"networkName"`
+
+	// VMVersionTelemetryEnabled reports the versions of the VMs of this node
+	// to peers in the handshake and in pongs.
+	VMVersionTelemetryEnabled bool `json:"vmVersionTelemetryEnabled"`
+
+	// VMVersions returns the versions of the VMs of this node, keyed by VM ID.
+	// Only used if [VMVersionTelemetryEnabled] is true.
+	VMVersions func() map[ids.ID]string `json:"-"`
+
 	// CompressionEnabled will compress available outbound messages when set to
 	// true.
 	CompressionEnabled bool `json:"compressionEnabled"`
@@ -147,6 +186,25 @@ type Config struct {
 	// the network negatively.
 	RequireValidatorToConnect bool `json:"requireValidatorToConnect"`
 
+	// ClockSkew, if non-nil, is given the times reported by peers.
+	ClockSkew clockskew.Estimator `json:"-"`
+
+	// Maintenance, if non-nil, reports the maintenance window of this node to
+	// announce to peers.
+	Maintenance maintenance.Announcer `json:"-"`
+
+	// PeerPolicy, if non-nil, is consulted before the TLS handshake of a
+	// connection, and again once the peer's handshake is complete.
+	PeerPolicy policy.Policy `json:"-"`
+
+	// PeerPolicyAddr is the address of the gRPC server [PeerPolicy] talks to,
+	// if any.
+	PeerPolicyAddr string `json:"peerPolicyAddr"`
+
+	// PeerPolicyTimeout is the maximum amount of time [PeerPolicy] may take to
+	// make a decision. Connections are dropped if it takes longer.
+	PeerPolicyTimeout time.Duration `json:"peerPolicyTimeout"`
+
 	// MaximumInboundMessageTimeout is the maximum deadline duration in a
 	// message. Messages sent by clients setting values higher than this value
 	// will be reset to this value.
@@ -0,0 +1,156 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package network
+
+import (
+	"sort"
+
+	"go.uber.org/zap"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/validators"
+	"github.com/ava-labs/avalanchego/utils/constants"
+)
+
+// trackedSubnetIDs returns the subnets whose connectivity is maintained by this
+// node.
+func (n *network) trackedSubnetIDs() []ids.ID {
+	subnetIDs := make([]ids.ID, 1, n.config.WhitelistedSubnets.Len()+1)
+	subnetIDs[0] = constants.PrimaryNetworkID
+	for subnetID := range n.config.WhitelistedSubnets {
+		if subnetID != constants.PrimaryNetworkID {
+			subnetIDs = append(subnetIDs, subnetID)
+		}
+	}
+	return subnetIDs
+}
+
+// isConnectedTo returns true if [nodeID] is this node or a connected peer that
+// tracks [subnetID].
+//
+// Assumes [n.peersLock] is held.
+func (n *network) isConnectedTo(nodeID ids.NodeID, subnetID ids.ID) bool {
+	if nodeID == n.config.MyNodeID {
+		return true
+	}
+	peer, connected := n.connectedPeers.GetByID(nodeID)
+	if !connected {
+		return false
+	}
+	trackedSubnets := peer.TrackedSubnets()
+	return subnetID == constants.PrimaryNetworkID || trackedSubnets.Contains(subnetID)
+}
+
+// connectedStake returns the portion, in [0, 1], of the stake of [subnetID]
+// that this node is connected to. If the subnet has no stake, it is considered
+// fully connected.
+//
+// Assumes [n.peersLock] is held.
+func (n *network) connectedStake(subnetID ids.ID) float64 {
+	vdrs, ok := n.config.Validators.GetValidators(subnetID)
+	if !ok {
+		return 1
+	}
+	totalWeight := vdrs.Weight()
+	if totalWeight == 0 {
+		return 1
+	}
+
+	connectedWeight := uint64(0)
+	for _, vdr := range vdrs.List() {
+		if n.isConnectedTo(vdr.ID(), subnetID) {
+			// The sum can't overflow, as it is bounded by [totalWeight].
+			connectedWeight += vdr.Weight()
+		}
+	}
+	return float64(connectedWeight) / float64(totalWeight)
+}
+
+// repairConnectivity reports the connected stake of every tracked subnet and
+// skips the reconnection backoff of disconnected validators of the subnets
+// whose connected stake is below the target.
+func (n *network) repairConnectivity() {
+	n.peersLock.Lock()
+	defer n.peersLock.Unlock()
+
+	// The connected stake of every subnet is reported, even once the repair
+	// dialing rate limit has been reached.
+	repairing := n.config.TargetConnectedStake > 0
+	for _, subnetID := range n.trackedSubnetIDs() {
+		connectedStake := n.connectedStake(subnetID)
+		n.metrics.connectedStake.WithLabelValues(subnetID.String()).Set(connectedStake)
+		if !repairing || connectedStake >= n.config.TargetConnectedStake {
+			continue
+		}
+		// Stop repairing once the repair dialing rate limit has been reached.
+		repairing = n.repairSubnet(subnetID, connectedStake)
+	}
+}
+
+// repairSubnet redials the heaviest disconnected validators of [subnetID] until
+// the stake being dialed is expected to reach the target. Validators whose IPs
+// aren't known can't be redialed, and validators whose backoff is shorter than
+// the repair frequency aren't.
+//
+// Returns false if the repair dialing rate limit has been reached.
+//
+// Assumes [n.peersLock] is held.
+func (n *network) repairSubnet(subnetID ids.ID, connectedStake float64) bool {
+	vdrs, ok := n.config.Validators.GetValidators(subnetID)
+	if !ok {
+		return true
+	}
+
+	var candidates []validators.Validator
+	for _, vdr := range vdrs.List() {
+		nodeID := vdr.ID()
+		tracked, isTracked := n.trackedIPs[nodeID]
+		if !isTracked {
+			continue
+		}
+		if tracked.getDelay() <= n.config.ConnectivityRepairFreq {
+			// The validator will be redialed before the next repair anyway.
+			continue
+		}
+		if _, connecting := n.connectingPeers.GetByID(nodeID); connecting {
+			continue
+		}
+		if n.isConnectedTo(nodeID, subnetID) {
+			continue
+		}
+		candidates = append(candidates, vdr)
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].Weight() > candidates[j].Weight()
+	})
+
+	totalWeight := float64(vdrs.Weight())
+	expectedStake := connectedStake
+	for _, vdr := range candidates {
+		if expectedStake >= n.config.TargetConnectedStake {
+			return true
+		}
+		if !n.repairDialLimiter.Allow() {
+			return false
+		}
+
+		nodeID := vdr.ID()
+		n.peerConfig.Log.Debug("skipping reconnection backoff",
+			zap.String("reason", "connected stake is below target"),
+			zap.Stringer("subnetID", subnetID),
+			zap.Stringer("nodeID", nodeID),
+			zap.Float64("connectedStake", connectedStake),
+		)
+
+		tracked := n.trackedIPs[nodeID]
+		tracked.stopTracking()
+		tracked = newTrackedIP(tracked.ip)
+		n.trackedIPs[nodeID] = tracked
+		n.dial(n.onCloseCtx, nodeID, tracked)
+		n.metrics.repairDials.Inc()
+
+		expectedStake += float64(vdr.Weight()) / totalWeight
+	}
+	return true
+}
@@ -16,6 +16,8 @@ type metrics struct {
 	numTracked                prometheus.Gauge
 	numPeers                  prometheus.Gauge
 	numSubnetPeers            *prometheus.GaugeVec
+	connectedStake            *prometheus.GaugeVec
+	repairDials               prometheus.Counter
 	timeSinceLastMsgSent      prometheus.Gauge
 	timeSinceLastMsgReceived  prometheus.Gauge
 	sendQueuePortionFull      prometheus.Gauge
@@ -25,6 +27,7 @@ type metrics struct {
 	acceptFailed              prometheus.Counter
 	inboundConnRateLimited    prometheus.Counter
 	inboundConnAllowed        prometheus.Counter
+	peerPolicyDenied          prometheus.Counter
 	nodeUptimeWeightedAverage prometheus.Gauge
 	nodeUptimeRewardingStake  prometheus.Gauge
 }
@@ -49,6 +52,19 @@ func newMetrics(namespace string, registerer prometheus.Registerer, initialSubne
 			},
 			[]string{"subnetID"},
 		),
+		connectedStake: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Name:      "connected_stake",
+				Help:      "Portion of a tracked subnet's stake that this node is connected to",
+			},
+			[]string{"subnetID"},
+		),
+		repairDials: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "repair_dials",
+			Help:      "Times this node skipped the reconnection backoff of a validator to repair its connected stake",
+		}),
 		timeSinceLastMsgReceived: prometheus.NewGauge(prometheus.GaugeOpts{
 			Namespace: namespace,
 			Name:      "time_since_last_msg_received",
@@ -94,6 +110,11 @@ func newMetrics(namespace string, registerer prometheus.Registerer, initialSubne
 			Name:      "inbound_conn_throttler_rate_limited",
 			Help:      "Times this node rejected an inbound connection due to rate-limiting",
 		}),
+		peerPolicyDenied: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "peer_policy_denied",
+			Help:      "Times this node dropped a connection because of the peer policy",
+		}),
 		nodeUptimeWeightedAverage: prometheus.NewGauge(prometheus.GaugeOpts{
 			Namespace: namespace,
 			Name:      "node_uptime_weighted_average",
@@ -111,6 +132,8 @@ func newMetrics(namespace string, registerer prometheus.Registerer, initialSubne
 		registerer.Register(m.numTracked),
 		registerer.Register(m.numPeers),
 		registerer.Register(m.numSubnetPeers),
+		registerer.Register(m.connectedStake),
+		registerer.Register(m.repairDials),
 		registerer.Register(m.timeSinceLastMsgReceived),
 		registerer.Register(m.timeSinceLastMsgSent),
 		registerer.Register(m.sendQueuePortionFull),
@@ -120,6 +143,7 @@ func newMetrics(namespace string, registerer prometheus.Registerer, initialSubne
 		registerer.Register(m.acceptFailed),
 		registerer.Register(m.inboundConnAllowed),
 		registerer.Register(m.inboundConnRateLimited),
+		registerer.Register(m.peerPolicyDenied),
 		registerer.Register(m.nodeUptimeWeightedAverage),
 		registerer.Register(m.nodeUptimeRewardingStake),
 	)
@@ -19,11 +19,14 @@ import (
 
 	"go.uber.org/zap"
 
+	"golang.org/x/time/rate"
+
 	"github.com/ava-labs/avalanchego/api/health"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/message"
 	"github.com/ava-labs/avalanchego/network/dialer"
 	"github.com/ava-labs/avalanchego/network/peer"
+	"github.com/ava-labs/avalanchego/network/policy"
 	"github.com/ava-labs/avalanchego/network/throttling"
 	"github.com/ava-labs/avalanchego/snow/engine/common"
 	"github.com/ava-labs/avalanchego/snow/networking/router"
@@ -38,6 +41,7 @@ import (
 
 const (
 	ConnectedPeersKey           = "connectedPeers"
+	ConnectedStakeKey           = "connectedStake"
 	TimeSinceLastMsgReceivedKey = "timeSinceLastMsgReceived"
 	TimeSinceLastMsgSentKey     = "timeSinceLastMsgSent"
 	SendFailRateKey             = "sendFailRate"
@@ -85,6 +89,11 @@ type Network interface {
 	PeerInfo(nodeIDs []ids.NodeID) []peer.Info
 
 	NodeUptime() (UptimeResult, bool)
+
+	// VMVersions returns the stake of the validators of [subnetID] by the
+	// versions of the VMs they reported running. Returns false if VM version
+	// telemetry is disabled, or if the subnet has no validators.
+	VMVersions(subnetID ids.ID) (VMVersionsResult, bool)
 }
 
 type UptimeResult struct {
@@ -92,6 +101,14 @@ type UptimeResult struct {
 	RewardingStakePercentage  float64
 }
 
+type VMVersionsResult struct {
+	// TotalWeight is the stake of all the validators of the subnet, including
+	// the validators that didn't report their VM versions.
+	TotalWeight uint64
+	// Weights maps a VM ID to the stake reporting each version of the VM.
+	Weights map[ids.ID]map[string]uint64
+}
+
 type network struct {
 	config     *Config
 	peerConfig *peer.Config
@@ -122,6 +139,10 @@ type network struct {
 
 	sendFailRateCalculator math.Averager
 
+	// Limits the rate at which the reconnection backoff of validators is
+	// skipped to repair the connected stake.
+	repairDialLimiter *rate.Limiter
+
 	peersLock sync.RWMutex
 	// trackedIPs contains the set of IPs that we are currently attempting to
 	// connect to. An entry is added to this set when we first start attempting
@@ -132,6 +153,10 @@ type network struct {
 	connectingPeers    peer.Set
 	connectedPeers     peer.Set
 	closing            bool
+	// handshakingPeers contains the connection information of the peers in
+	// [connectingPeers], which [config.PeerPolicy] is consulted with once
+	// their handshake is complete.
+	handshakingPeers map[ids.NodeID]policy.Peer
 
 	// router is notified about all peer [Connected] and [Disconnected] events
 	// as well as all non-handshake peer messages.
@@ -213,9 +238,11 @@ func NewNetwork(
 		MySubnets:            config.WhitelistedSubnets,
 		Beacons:              config.Beacons,
 		NetworkID:            config.NetworkID,
+		NetworkName:          config.NetworkName,
 		PingFrequency:        config.PingFrequency,
 		PongTimeout:          config.PingPongTimeout,
 		MaxClockDifference:   config.MaxClockDifference,
+		ClockSkew:            config.ClockSkew,
 		ResourceTracker:      config.ResourceTracker,
 	}
 
@@ -242,10 +269,16 @@ func NewNetwork(
 			time.Now(),
 		)),
 
-		trackedIPs:      make(map[ids.NodeID]*trackedIP),
-		connectingPeers: peer.NewSet(),
-		connectedPeers:  peer.NewSet(),
-		router:          router,
+		repairDialLimiter: rate.NewLimiter(
+			rate.Limit(config.MaxRepairDialsPerSec),
+			int(gomath.Ceil(config.MaxRepairDialsPerSec))+1,
+		),
+
+		trackedIPs:       make(map[ids.NodeID]*trackedIP),
+		connectingPeers:  peer.NewSet(),
+		connectedPeers:   peer.NewSet(),
+		handshakingPeers: make(map[ids.NodeID]policy.Peer),
+		router:           router,
 	}
 	n.peerConfig.Network = n
 	return n, nil
@@ -278,6 +311,10 @@ func (n *network) Gossip(
 func (n *network) HealthCheck(context.Context) (interface{}, error) {
 	n.peersLock.RLock()
 	connectedTo := n.connectedPeers.Len()
+	connectedStake := make(map[string]float64)
+	for _, subnetID := range n.trackedSubnetIDs() {
+		connectedStake[subnetID.String()] = n.connectedStake(subnetID)
+	}
 	n.peersLock.RUnlock()
 
 	sendFailRate := n.sendFailRateCalculator.Read()
@@ -287,7 +324,18 @@ func (n *network) HealthCheck(context.Context) (interface{}, error) {
 	healthy := isConnected
 	details := map[string]interface{}{
 		ConnectedPeersKey: connectedTo,
+		ConnectedStakeKey: connectedStake,
+	}
+
+	// Make sure we're connected to at least the minimum stake of each subnet
+	var weaklyConnectedSubnets []string
+	for subnetID, stake := range connectedStake {
+		if stake < n.config.HealthConfig.MinConnectedStake {
+			weaklyConnectedSubnets = append(weaklyConnectedSubnets, fmt.Sprintf("%s (%g)", subnetID, stake))
+		}
 	}
+	isStakeConnected := len(weaklyConnectedSubnets) == 0
+	healthy = healthy && isStakeConnected
 
 	// Make sure we've received an incoming message within the threshold
 	now := n.peerConfig.Clock.Time()
@@ -319,6 +367,9 @@ func (n *network) HealthCheck(context.Context) (interface{}, error) {
 		if !isConnected {
 			errorReasons = append(errorReasons, fmt.Sprintf("not connected to a minimum of %d peer(s) only %d", n.config.HealthConfig.MinConnectedPeers, connectedTo))
 		}
+		if !isStakeConnected {
+			errorReasons = append(errorReasons, fmt.Sprintf("not connected to a minimum of %g of the stake of subnet(s) %s", n.config.HealthConfig.MinConnectedStake, strings.Join(weaklyConnectedSubnets, ", ")))
+		}
 		if !wasMsgReceivedRecently {
 			errorReasons = append(errorReasons, fmt.Sprintf("no messages from network received in %s > %s", timeSinceLastMsgReceived, n.config.HealthConfig.MaxTimeSinceMsgReceived))
 		}
@@ -354,6 +405,7 @@ func (n *network) Connected(nodeID ids.NodeID) {
 		delete(n.trackedIPs, nodeID)
 	}
 	n.connectingPeers.Remove(nodeID)
+	delete(n.handshakingPeers, nodeID)
 	n.connectedPeers.Add(peer)
 	n.peersLock.Unlock()
 
@@ -376,6 +428,54 @@ func (n *network) AllowConnection(nodeID ids.NodeID) bool {
 		n.WantsConnection(nodeID)
 }
 
+// AllowHandshake returns true if [config.PeerPolicy] allows the handshake of
+// [nodeID] to be finished.
+func (n *network) AllowHandshake(
+	nodeID ids.NodeID,
+	ip ips.IPPort,
+	version *version.Application,
+	subnets []ids.ID,
+) bool {
+	if n.config.PeerPolicy == nil {
+		return true
+	}
+
+	n.peersLock.RLock()
+	p, ok := n.handshakingPeers[nodeID]
+	n.peersLock.RUnlock()
+	if !ok {
+		return false
+	}
+
+	p.HandshakeComplete = true
+	p.IP = ip
+	p.Version = version
+	p.TrackedSubnets = subnets
+	return n.allowedByPolicy(p)
+}
+
+func (n *network) allowedByPolicy(p policy.Peer) bool {
+	if n.config.PeerPolicy == nil {
+		return true
+	}
+
+	ctx, cancel := context.WithTimeout(n.onCloseCtx, n.config.PeerPolicyTimeout)
+	defer cancel()
+
+	allowed, err := n.config.PeerPolicy.Allow(ctx, p)
+	if err != nil {
+		n.peerConfig.Log.Warn("failed to consult the peer policy",
+			zap.Stringer("nodeID", p.NodeID),
+			zap.Error(err),
+		)
+		allowed = false
+	}
+	if !allowed {
+		n.metrics.peerPolicyDenied.Inc()
+	}
+	return allowed
+}
+
 func (n *network) Track(claimedIPPort ips.ClaimedIPPort) bool {
 	nodeID := ids.NodeIDFromCert(claimedIPPort.Cert)
 
@@ -468,12 +568,14 @@ func (n *network) Version() (message.OutboundMessage, error) {
 	}
 	return n.peerConfig.MessageCreator.Version(
 		n.peerConfig.NetworkID,
+		n.peerConfig.NetworkName,
 		n.peerConfig.Clock.Unix(),
 		mySignedIP.IP.IP,
 		n.peerConfig.VersionCompatibility.Version().String(),
 		mySignedIP.IP.Timestamp,
 		mySignedIP.Signature,
 		n.peerConfig.MySubnets.List(),
+		n.myVMVersions(),
 	)
 }
 
@@ -489,7 +591,30 @@ func (n *network) Pong(nodeID ids.NodeID) (message.OutboundMessage, error) {
 	}
 
 	uptimePercentInt := uint8(uptimePercentFloat * 100)
-	return n.peerConfig.MessageCreator.Pong(uptimePercentInt)
+
+	var maintenanceStart, maintenanceEnd time.Time
+	if n.config.Maintenance != nil {
+		if window, ok := n.config.Maintenance.Announced(); ok {
+			maintenanceStart = window.Start
+			maintenanceEnd = window.End()
+		}
+	}
+	return n.peerConfig.MessageCreator.Pong(
+		uptimePercentInt,
+		n.myVMVersions(),
+		n.peerConfig.Clock.Time(),
+		maintenanceStart,
+		maintenanceEnd,
+	)
+}
+
+// myVMVersions returns the versions of the VMs of this node to report to
+// peers, or nil if VM version telemetry is disabled.
+func (n *network) myVMVersions() map[ids.ID]string {
+	if !n.config.VMVersionTelemetryEnabled || n.config.VMVersions == nil {
+		return nil
+	}
+	return n.config.VMVersions()
 }
 
 // Dispatch starts accepting connections from other nodes attempting to connect
@@ -539,7 +664,7 @@ func (n *network) Dispatch() error {
 		n.metrics.inboundConnAllowed.Inc()
 
 		go func() {
-			if err := n.upgrade(conn, n.serverUpgrader); err != nil {
+			if err := n.upgrade(conn, n.serverUpgrader, ids.EmptyNodeID); err != nil {
 				n.peerConfig.Log.Verbo("failed to upgrade inbound connection",
 					zap.Error(err),
 				)
@@ -747,6 +872,7 @@ func (n *network) disconnectedFromConnecting(nodeID ids.NodeID) {
 	defer n.peersLock.Unlock()
 
 	n.connectingPeers.Remove(nodeID)
+	delete(n.handshakingPeers, nodeID)
 
 	// The peer that is disconnecting from us didn't finish the handshake
 	tracked, ok := n.trackedIPs[nodeID]
@@ -895,7 +1021,7 @@ func (n *network) dial(ctx context.Context, nodeID ids.NodeID, ip *trackedIP) {
 				continue
 			}
 
-			err = n.upgrade(conn, n.clientUpgrader)
+			err = n.upgrade(conn, n.clientUpgrader, nodeID)
 			if err != nil {
 				n.peerConfig.Log.Verbo(
 					"failed to upgrade, attempting again",
@@ -917,7 +1043,37 @@ func (n *network) dial(ctx context.Context, nodeID ids.NodeID, ip *trackedIP) {
 // If the connection is desired by the node, then the resulting upgraded
 // connection will be used to create a new peer. Otherwise the connection will
 // be immediately closed.
-func (n *network) upgrade(conn net.Conn, upgrader peer.Upgrader) error {
+// upgrade performs the TLS handshake of [conn] and starts a peer over it.
+// [expectedNodeID] is the node [conn] was dialed to, or empty if [conn] is
+// inbound.
+func (n *network) upgrade(conn net.Conn, upgrader peer.Upgrader, expectedNodeID ids.NodeID) error {
+	// The peer policy is consulted before any resources are spent on the TLS
+	// handshake.
+	remoteAddr := conn.RemoteAddr().String()
+	remoteIP, err := ips.ToIPPort(remoteAddr)
+	if err != nil {
+		_ = conn.Close()
+		n.peerConfig.Log.Verbo("failed to parse remote address",
+			zap.String("peerIP", remoteAddr),
+			zap.Error(err),
+		)
+		return err
+	}
+	policyPeer := policy.Peer{
+		NodeID:   expectedNodeID,
+		Inbound:  upgrader == n.serverUpgrader,
+		RemoteIP: remoteIP,
+	}
+	if !n.allowedByPolicy(policyPeer) {
+		_ = conn.Close()
+		n.peerConfig.Log.Verbo(
+			"dropping connection",
+			zap.String("reason", "denied by the peer policy"),
+			zap.Stringer("peerIP", remoteIP),
+		)
+		return nil
+	}
+
 	upgradeTimeout := n.peerConfig.Clock.Time().Add(n.config.ReadHandshakeTimeout)
 	if err := conn.SetReadDeadline(upgradeTimeout); err != nil {
 		_ = conn.Close()
@@ -962,6 +1118,10 @@ func (n *network) upgrade(conn net.Conn, upgrader peer.Upgrader) error {
 		return nil
 	}
 
+	// The peer policy is consulted again with the authenticated node ID once
+	// the handshake of the peer is complete.
+	policyPeer.NodeID = nodeID
+
 	n.peersLock.Lock()
 	defer n.peersLock.Unlock()
 
@@ -1015,6 +1175,7 @@ func (n *network) upgrade(conn net.Conn, upgrader peer.Upgrader) error {
 		),
 	)
 	n.connectingPeers.Add(peer)
+	n.handshakingPeers[nodeID] = policyPeer
 	return nil
 }
 
@@ -1108,6 +1269,46 @@ func (n *network) NodeUptime() (UptimeResult, bool) {
 	}, true
 }
 
+func (n *network) VMVersions(subnetID ids.ID) (VMVersionsResult, bool) {
+	if !n.config.VMVersionTelemetryEnabled {
+		return VMVersionsResult{}, false
+	}
+	vdrs, ok := n.config.Validators.GetValidators(subnetID)
+	if !ok || vdrs.Weight() == 0 {
+		return VMVersionsResult{}, false
+	}
+
+	result := VMVersionsResult{
+		TotalWeight: vdrs.Weight(),
+		Weights:     make(map[ids.ID]map[string]uint64),
+	}
+	addWeight := func(nodeID ids.NodeID, vmVersions map[ids.ID]string) {
+		weight, ok := vdrs.GetWeight(nodeID)
+		if !ok {
+			return
+		}
+		for vmID, version := range vmVersions {
+			versionWeights, ok := result.Weights[vmID]
+			if !ok {
+				versionWeights = make(map[string]uint64)
+				result.Weights[vmID] = versionWeights
+			}
+			versionWeights[version] += weight
+		}
+	}
+
+	addWeight(n.config.MyNodeID, n.myVMVersions())
+
+	n.peersLock.RLock()
+	defer n.peersLock.RUnlock()
+
+	for i := 0; i < n.connectedPeers.Len(); i++ {
+		peer, _ := n.connectedPeers.GetByIndex(i)
+		addWeight(peer.ID(), peer.VMVersions())
+	}
+	return result, true
+}
+
 func (n *network) runTimers() {
 	gossipPeerlists := time.NewTicker(n.config.PeerListGossipFreq)
 	updateUptimes := time.NewTicker(n.config.UptimeMetricFreq)
@@ -1116,6 +1317,15 @@ func (n *network) runTimers() {
 		updateUptimes.Stop()
 	}()
 
+	// repairConnectivity is nil if the connected stake isn't checked, so that
+	// the case is never selected.
+	var repairConnectivity <-chan time.Time
+	if n.config.ConnectivityRepairFreq > 0 {
+		ticker := time.NewTicker(n.config.ConnectivityRepairFreq)
+		defer ticker.Stop()
+		repairConnectivity = ticker.C
+	}
+
 	for {
 		select {
 		case <-n.onCloseCtx.Done():
@@ -1151,6 +1361,9 @@ func (n *network) runTimers() {
 			result, _ := n.NodeUptime()
 			n.metrics.nodeUptimeWeightedAverage.Set(result.WeightedAveragePercentage)
 			n.metrics.nodeUptimeRewardingStake.Set(result.RewardingStakePercentage)
+
+		case <-repairConnectivity:
+			n.repairConnectivity()
 		}
 	}
 }
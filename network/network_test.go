@@ -6,6 +6,8 @@ package network
 import (
 	"context"
 	"crypto"
+	"crypto/x509"
+	"errors"
 	"net"
 	"sync"
 	"testing"
@@ -13,11 +15,17 @@ import (
 
 	"github.com/prometheus/client_golang/prometheus"
 
+	dto "github.com/prometheus/client_model/go"
+
 	"github.com/stretchr/testify/require"
 
+	"golang.org/x/time/rate"
+
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/message"
 	"github.com/ava-labs/avalanchego/network/dialer"
+	"github.com/ava-labs/avalanchego/network/peer"
+	"github.com/ava-labs/avalanchego/network/policy"
 	"github.com/ava-labs/avalanchego/network/throttling"
 	"github.com/ava-labs/avalanchego/snow/networking/router"
 	"github.com/ava-labs/avalanchego/snow/networking/tracker"
@@ -362,3 +370,221 @@ func TestTrackVerifiesSignatures(t *testing.T) {
 	}
 	wg.Wait()
 }
+
+func TestRepairConnectivity(t *testing.T) {
+	require := require.New(t)
+
+	_, networks, wg := newFullyConnectedTestNetwork(t, []router.InboundHandler{nil})
+
+	network := networks[0].(*network)
+	network.config.MinConnectedStake = .5
+	network.config.TargetConnectedStake = .5
+	network.config.ConnectivityRepairFreq = time.Minute
+	network.repairDialLimiter = rate.NewLimiter(rate.Inf, 1)
+
+	var (
+		heavyNodeID = ids.GenerateTestNodeID()
+		lightNodeID = ids.GenerateTestNodeID()
+		ip          = &peer.UnsignedIP{
+			IP: ips.IPPort{
+				IP:   net.IPv4(123, 132, 123, 123),
+				Port: 10000,
+			},
+		}
+	)
+	require.NoError(network.config.Validators.AddWeight(constants.PrimaryNetworkID, heavyNodeID, 2))
+	require.NoError(network.config.Validators.AddWeight(constants.PrimaryNetworkID, lightNodeID, 1))
+
+	network.peersLock.Lock()
+	heavyTracked := newTrackedIP(ip)
+	heavyTracked.delay = time.Hour
+	network.trackedIPs[heavyNodeID] = heavyTracked
+	lightTracked := newTrackedIP(ip)
+	lightTracked.delay = time.Hour
+	network.trackedIPs[lightNodeID] = lightTracked
+	network.peersLock.Unlock()
+
+	// Only this node's own stake is connected.
+	details, err := network.HealthCheck(context.Background())
+	require.Error(err)
+	connectedStake := details.(map[string]interface{})[ConnectedStakeKey].(map[string]float64)
+	require.Equal(.25, connectedStake[constants.PrimaryNetworkID.String()])
+
+	network.repairConnectivity()
+
+	// Redialing the heaviest validator is expected to reach the target.
+	network.peersLock.RLock()
+	require.NotEqual(heavyTracked, network.trackedIPs[heavyNodeID])
+	require.Equal(lightTracked, network.trackedIPs[lightNodeID])
+	network.peersLock.RUnlock()
+
+	for _, net := range networks {
+		net.StartClose()
+	}
+	wg.Wait()
+}
+
+func TestRepairConnectivityReportsConnectedStake(t *testing.T) {
+	require := require.New(t)
+
+	_, networks, wg := newFullyConnectedTestNetwork(t, []router.InboundHandler{nil})
+
+	network := networks[0].(*network)
+	network.config.MinConnectedStake = 0
+	// The connected stake is reported even if it isn't repaired.
+	network.config.TargetConnectedStake = 0
+
+	require.NoError(network.config.Validators.AddWeight(constants.PrimaryNetworkID, ids.GenerateTestNodeID(), 3))
+
+	network.repairConnectivity()
+
+	metric := &dto.Metric{}
+	require.NoError(network.metrics.connectedStake.WithLabelValues(constants.PrimaryNetworkID.String()).Write(metric))
+	require.Equal(.25, metric.GetGauge().GetValue())
+
+	for _, net := range networks {
+		net.StartClose()
+	}
+	wg.Wait()
+}
+
+func TestVMVersions(t *testing.T) {
+	require := require.New(t)
+
+	vmID := ids.GenerateTestID()
+	originalConfig := defaultConfig
+	defer func() {
+		defaultConfig = originalConfig
+	}()
+	defaultConfig.VMVersionTelemetryEnabled = true
+	defaultConfig.VMVersions = func() map[ids.ID]string {
+		return map[ids.ID]string{
+			vmID: "v1.0.0",
+		}
+	}
+
+	nodeIDs, networks, wg := newFullyConnectedTestNetwork(t, []router.InboundHandler{nil, nil})
+
+	network := networks[0].(*network)
+	require.NoError(network.config.Validators.AddWeight(constants.PrimaryNetworkID, nodeIDs[1], 1))
+	require.NoError(network.config.Validators.AddWeight(constants.PrimaryNetworkID, ids.GenerateTestNodeID(), 1))
+
+	// The stake of this node and of its peer reports the version, and the
+	// stake of the validator it isn't connected to doesn't.
+	result, ok := network.VMVersions(constants.PrimaryNetworkID)
+	require.True(ok)
+	require.Equal(uint64(4), result.TotalWeight)
+	require.Equal(map[ids.ID]map[string]uint64{
+		vmID: {
+			"v1.0.0": 3,
+		},
+	}, result.Weights)
+
+	_, ok = network.VMVersions(ids.GenerateTestID())
+	require.False(ok)
+
+	for _, net := range networks {
+		net.StartClose()
+	}
+	wg.Wait()
+}
+
+// testPolicy records the peers it is consulted about, and allows the peers
+// [allowF] returns true for.
+type testPolicy struct {
+	lock   sync.Mutex
+	peers  []policy.Peer
+	allowF func(policy.Peer) bool
+}
+
+func (p *testPolicy) Allow(_ context.Context, peer policy.Peer) (bool, error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.peers = append(p.peers, peer)
+	return p.allowF(peer), nil
+}
+
+// countingUpgrader counts the connections it is asked to upgrade, and fails to
+// upgrade them.
+type countingUpgrader struct {
+	upgrades int
+}
+
+func (u *countingUpgrader) Upgrade(net.Conn) (ids.NodeID, net.Conn, *x509.Certificate, error) {
+	u.upgrades++
+	return ids.EmptyNodeID, nil, nil, errors.New("not upgraded")
+}
+
+func TestPeerPolicy(t *testing.T) {
+	require := require.New(t)
+
+	peerPolicy := &testPolicy{
+		allowF: func(policy.Peer) bool {
+			return true
+		},
+	}
+	originalConfig := defaultConfig
+	defer func() {
+		defaultConfig = originalConfig
+	}()
+	defaultConfig.PeerPolicy = peerPolicy
+	defaultConfig.PeerPolicyTimeout = time.Second
+
+	nodeIDs, networks, wg := newFullyConnectedTestNetwork(t, []router.InboundHandler{nil, nil})
+
+	// The policy was consulted about each connection before its TLS
+	// handshake, when only dialed nodes are known, and about each node once
+	// its handshake was complete.
+	peerPolicy.lock.Lock()
+	var inbound, outbound int
+	handshaked := make(map[ids.NodeID]bool)
+	for _, peer := range peerPolicy.peers {
+		if !peer.HandshakeComplete {
+			if peer.Inbound {
+				require.Equal(ids.EmptyNodeID, peer.NodeID)
+				inbound++
+			} else {
+				require.Contains(nodeIDs, peer.NodeID)
+				outbound++
+			}
+			continue
+		}
+		handshaked[peer.NodeID] = true
+		require.Equal(version.CurrentApp.String(), peer.Version.String())
+		require.Empty(peer.TrackedSubnets)
+	}
+	require.Positive(inbound)
+	require.Positive(outbound)
+	for _, nodeID := range nodeIDs {
+		require.True(handshaked[nodeID])
+	}
+	peerPolicy.lock.Unlock()
+
+	// Connections that the policy denies are dropped.
+	network := networks[0].(*network)
+	peerPolicy.lock.Lock()
+	peerPolicy.allowF = func(policy.Peer) bool {
+		return false
+	}
+	peerPolicy.lock.Unlock()
+	require.False(network.allowedByPolicy(policy.Peer{
+		NodeID: nodeIDs[1],
+	}))
+	require.False(network.AllowHandshake(ids.GenerateTestNodeID(), ips.IPPort{}, version.CurrentApp, nil))
+
+	// Denied connections are dropped before their TLS handshake.
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(err)
+	defer listener.Close()
+	conn, err := net.Dial("tcp", listener.Addr().String())
+	require.NoError(err)
+	upgrader := &countingUpgrader{}
+	require.NoError(network.upgrade(conn, upgrader, nodeIDs[1]))
+	require.Zero(upgrader.upgrades)
+
+	for _, net := range networks {
+		net.StartClose()
+	}
+	wg.Wait()
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/ava-labs/avalanchego/snow/networking/router"
 	"github.com/ava-labs/avalanchego/snow/networking/tracker"
 	"github.com/ava-labs/avalanchego/snow/validators"
+	"github.com/ava-labs/avalanchego/utils/clockskew"
 	"github.com/ava-labs/avalanchego/utils/logging"
 	"github.com/ava-labs/avalanchego/utils/timer/mockable"
 	"github.com/ava-labs/avalanchego/version"
@@ -34,10 +35,14 @@ type Config struct {
 	MySubnets            ids.Set
 	Beacons              validators.Set
 	NetworkID            uint32
+	NetworkName          string
 	PingFrequency        time.Duration
 	PongTimeout          time.Duration
 	MaxClockDifference   time.Duration
 
+	// ClockSkew, if non-nil, is given the times reported by this peer.
+	ClockSkew clockskew.Estimator
+
 	// Unix time of the last message sent and received respectively
 	// Must only be accessed atomically
 	LastSent, LastReceived int64
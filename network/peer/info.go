@@ -19,4 +19,15 @@ type Info struct {
 	LastReceived   time.Time   `json:"lastReceived"`
 	ObservedUptime json.Uint32 `json:"observedUptime"`
 	TrackedSubnets []ids.ID    `json:"trackedSubnets"`
+	// VM ID -> version, as reported by the peer
+	VMVersions map[ids.ID]string `json:"vmVersions,omitempty"`
+	// Maintenance window the peer announced, if any
+	Maintenance *Maintenance `json:"maintenance,omitempty"`
+}
+
+// Maintenance is a period during which a peer expects to be taken down for
+// planned maintenance.
+type Maintenance struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
 }
@@ -6,6 +6,7 @@ package peer
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -31,7 +32,7 @@ func TestMessageQueue(t *testing.T) {
 
 	// Assert that the messages are popped in the same order they were pushed
 	for i := 0; i < numToSend; i++ {
-		m, err := mc.Pong(uint8(i))
+		m, err := mc.Pong(uint8(i), nil, time.Now(), time.Time{}, time.Time{})
 		require.NoError(err)
 		msgs = append(msgs, m)
 	}
@@ -7,6 +7,7 @@ import (
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/message"
 	"github.com/ava-labs/avalanchego/utils/ips"
+	"github.com/ava-labs/avalanchego/version"
 )
 
 // Network defines the interface that is used by a peer to help establish a well
@@ -19,6 +20,17 @@ type Network interface {
 	// connection is no longer desired and should be terminated.
 	AllowConnection(ids.NodeID) bool
 
+	// AllowHandshake is called by the peer once the Version message of the
+	// peer is verified, before the handshake is finished. [ip] is the IP the
+	// peer signed, and [subnets] are all the subnets the peer tracks. If false
+	// is returned, the connection is closed.
+	AllowHandshake(
+		nodeID ids.NodeID,
+		ip ips.IPPort,
+		version *version.Application,
+		subnets []ids.ID,
+	) bool
+
 	// Track allows the peer to notify the network of a potential new peer to
 	// connect to.
 	//
@@ -81,6 +81,17 @@ type Peer interface {
 	// returns true.
 	ObservedUptime() uint32
 
+	// VMVersions returns the versions of the VMs the peer last reported it
+	// runs, keyed by VM ID. Peers only report them if they opted into VM
+	// version telemetry. It should only be called after [Ready] returns true.
+	VMVersions() map[ids.ID]string
+
+	// Maintenance returns the maintenance window the peer last announced, if
+	// any. A peer announces a window shortly before it is taken down for
+	// planned maintenance. It should only be called after [Ready] returns
+	// true.
+	Maintenance() (Maintenance, bool)
+
 	// Send attempts to send [msg] to the peer. The peer takes ownership of
 	// [msg] for reference counting. This returns false if the message is
 	// guaranteed not to be delivered to the peer.
@@ -128,6 +139,14 @@ type peer struct {
 	// [observedUptimeLock] must be held while accessing [observedUptime]
 	observedUptime uint32
 
+	vmVersionsLock sync.RWMutex
+	// [vmVersionsLock] must be held while accessing [vmVersions]
+	vmVersions map[ids.ID]string
+
+	maintenanceLock sync.RWMutex
+	// [maintenanceLock] must be held while accessing [maintenance]
+	maintenance *Maintenance
+
 	// True if this peer has sent us a valid Version message and
 	// is running a compatible version.
 	// Only modified on the connection's reader routine.
@@ -157,6 +176,11 @@ type peer struct {
 	// Unix time of the last message sent and received respectively
 	// Must only be accessed atomically
 	lastSent, lastReceived int64
+
+	// Unix time, in nanoseconds, of the last ping sent. Used to estimate how
+	// long the pong took to arrive.
+	// Must only be accessed atomically
+	lastPingSent int64
 }
 
 // Start a new peer instance.
@@ -242,6 +266,8 @@ func (p *peer) Info() Info {
 		LastReceived:   time.Unix(atomic.LoadInt64(&p.lastReceived), 0),
 		ObservedUptime: json.Uint32(p.ObservedUptime()),
 		TrackedSubnets: p.trackedSubnets.List(),
+		VMVersions:     p.VMVersions(),
+		Maintenance:    p.maintenanceInfo(),
 	}
 }
 
@@ -264,6 +290,32 @@ func (p *peer) ObservedUptime() uint32 {
 	return uptime
 }
 
+func (p *peer) VMVersions() map[ids.ID]string {
+	p.vmVersionsLock.RLock()
+	defer p.vmVersionsLock.RUnlock()
+
+	vmVersions := make(map[ids.ID]string, len(p.vmVersions))
+	for vmID, version := range p.vmVersions {
+		vmVersions[vmID] = version
+	}
+	return vmVersions
+}
+
+func (p *peer) Maintenance() (Maintenance, bool) {
+	maintenance := p.maintenanceInfo()
+	if maintenance == nil {
+		return Maintenance{}, false
+	}
+	return *maintenance, true
+}
+
+func (p *peer) maintenanceInfo() *Maintenance {
+	p.maintenanceLock.RLock()
+	defer p.maintenanceLock.RUnlock()
+
+	return p.maintenance
+}
+
 func (p *peer) Send(ctx context.Context, msg message.OutboundMessage) bool {
 	return p.messageQueue.Push(ctx, msg)
 }
@@ -524,9 +576,12 @@ func (p *peer) writeMessage(writer io.Writer, msg message.OutboundMessage) {
 		return
 	}
 
-	now := p.Clock.Time().Unix()
-	atomic.StoreInt64(&p.Config.LastSent, now)
-	atomic.StoreInt64(&p.lastSent, now)
+	now := p.Clock.Time()
+	atomic.StoreInt64(&p.Config.LastSent, now.Unix())
+	atomic.StoreInt64(&p.lastSent, now.Unix())
+	if msg.Op() == message.PingOp {
+		atomic.StoreInt64(&p.lastPingSent, now.UnixNano())
+	}
 	p.Metrics.Sent(msg)
 }
 
@@ -637,6 +692,72 @@ func (p *peer) handlePong(msg *p2ppb.Pong) {
 	p.observedUptimeLock.Lock()
 	p.observedUptime = msg.UptimePct // [0, 100] percentage
 	p.observedUptimeLock.Unlock()
+
+	p.setVMVersions(msg.VmVersions)
+	p.setMaintenance(msg.MaintenanceStartMs, msg.MaintenanceEndMs)
+
+	if p.ClockSkew != nil && msg.MyTimeMs != 0 {
+		// The peer reported its time about halfway through the round trip of
+		// the ping, so half of the round trip has passed since.
+		peerTime := time.UnixMilli(int64(msg.MyTimeMs))
+		if pingSent := atomic.LoadInt64(&p.lastPingSent); pingSent != 0 {
+			if rtt := p.Clock.Time().Sub(time.Unix(0, pingSent)); rtt > 0 {
+				peerTime = peerTime.Add(rtt / 2)
+			}
+		}
+		p.ClockSkew.Observe(p.id, peerTime)
+	}
+}
+
+// setVMVersions records the versions of the VMs the peer reported. The report
+// is only telemetry, so a malformed report is dropped rather than treated as
+// misbehavior.
+func (p *peer) setVMVersions(reported []*p2ppb.VmVersion) {
+	if len(reported) == 0 {
+		return
+	}
+
+	vmVersions := make(map[ids.ID]string, len(reported))
+	for _, vmVersion := range reported {
+		vmID, err := ids.ToID(vmVersion.VmId)
+		if err != nil {
+			p.Log.Debug("dropping malformed VM versions",
+				zap.Stringer("nodeID", p.id),
+				zap.Error(err),
+			)
+			return
+		}
+		vmVersions[vmID] = vmVersion.Version
+	}
+
+	p.vmVersionsLock.Lock()
+	p.vmVersions = vmVersions
+	p.vmVersionsLock.Unlock()
+}
+
+// setMaintenance records the maintenance window the peer announced. A peer
+// that doesn't announce a window isn't expecting to be taken down.
+func (p *peer) setMaintenance(startMs, endMs uint64) {
+	var maintenance *Maintenance
+	if startMs != 0 && endMs > startMs {
+		maintenance = &Maintenance{
+			Start: time.UnixMilli(int64(startMs)),
+			End:   time.UnixMilli(int64(endMs)),
+		}
+	}
+
+	p.maintenanceLock.Lock()
+	previous := p.maintenance
+	p.maintenance = maintenance
+	p.maintenanceLock.Unlock()
+
+	if maintenance != nil && (previous == nil || !previous.Start.Equal(maintenance.Start) || !previous.End.Equal(maintenance.End)) {
+		p.Log.Debug("peer announced maintenance",
+			zap.Stringer("nodeID", p.id),
+			zap.Time("start", maintenance.Start),
+			zap.Time("end", maintenance.End),
+		)
+	}
 }
 
 func (p *peer) handleVersion(msg *p2ppb.Version) {
@@ -658,6 +779,23 @@ func (p *peer) handleVersion(msg *p2ppb.Version) {
 		return
 	}
 
+	if msg.NetworkName != p.NetworkName {
+		p.Log.Debug("network name mismatch",
+			zap.Stringer("nodeID", p.id),
+			zap.String("peerNetworkName", msg.NetworkName),
+			zap.String("ourNetworkName", p.NetworkName),
+		)
+		p.StartClose()
+		return
+	}
+
+	// Peers are observed even if their time is out of sync, as that is when the
+	// skew of the local clock matters most. The time is truncated to the
+	// second, so the peer's clock is on average half a second later.
+	if p.ClockSkew != nil {
+		p.ClockSkew.Observe(p.id, time.Unix(int64(msg.MyTime), int64(time.Second/2)))
+	}
+
 	myTime := p.Clock.Unix()
 	if math.Abs(float64(msg.MyTime)-float64(myTime)) > p.MaxClockDifference.Seconds() {
 		if p.Beacons.Contains(p.id) {
@@ -725,6 +863,7 @@ func (p *peer) handleVersion(msg *p2ppb.Version) {
 	}
 
 	// handle subnet IDs
+	subnetIDs := make([]ids.ID, 0, len(msg.TrackedSubnets))
 	for _, subnetIDBytes := range msg.TrackedSubnets {
 		subnetID, err := ids.ToID(subnetIDBytes)
 		if err != nil {
@@ -735,12 +874,15 @@ func (p *peer) handleVersion(msg *p2ppb.Version) {
 			p.StartClose()
 			return
 		}
+		subnetIDs = append(subnetIDs, subnetID)
 		// add only if we also track this subnet
 		if p.MySubnets.Contains(subnetID) {
 			p.trackedSubnets.Add(subnetID)
 		}
 	}
 
+	p.setVMVersions(msg.VmVersions)
+
 	// "net.IP" type in Golang is 16-byte
 	if ipLen := len(msg.IpAddr); ipLen != net.IPv6len {
 		p.Log.Debug("message with invalid field",
@@ -772,6 +914,15 @@ func (p *peer) handleVersion(msg *p2ppb.Version) {
 		return
 	}
 
+	if !p.Network.AllowHandshake(p.id, p.ip.IP.IP, peerVersion, subnetIDs) {
+		p.Log.Debug("disconnecting from peer",
+			zap.String("reason", "denied by the peer policy"),
+			zap.Stringer("nodeID", p.id),
+		)
+		p.StartClose()
+		return
+	}
+
 	p.gotVersion.SetValue(true)
 
 	peerlistMsg, err := p.Network.Peers()
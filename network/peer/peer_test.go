@@ -22,12 +22,15 @@ import (
 	"github.com/ava-labs/avalanchego/snow/networking/tracker"
 	"github.com/ava-labs/avalanchego/snow/validators"
 	"github.com/ava-labs/avalanchego/staking"
+	"github.com/ava-labs/avalanchego/utils/clockskew"
 	"github.com/ava-labs/avalanchego/utils/constants"
 	"github.com/ava-labs/avalanchego/utils/ips"
 	"github.com/ava-labs/avalanchego/utils/logging"
 	"github.com/ava-labs/avalanchego/utils/math/meter"
 	"github.com/ava-labs/avalanchego/utils/resource"
 	"github.com/ava-labs/avalanchego/version"
+
+	p2ppb "github.com/ava-labs/avalanchego/proto/pb/p2p"
 )
 
 type testPeer struct {
@@ -266,6 +269,159 @@ func TestReady(t *testing.T) {
 	require.NoError(err)
 }
 
+func TestNetworkNameMismatch(t *testing.T) {
+	require := require.New(t)
+
+	rawPeer0, rawPeer1 := makeRawTestPeers(t)
+	rawPeer0.config.NetworkName = "fork"
+	rawPeer0.config.Network.(*testNetwork).networkName = "fork"
+
+	peer0 := Start(
+		rawPeer0.config,
+		rawPeer0.conn,
+		rawPeer1.cert,
+		rawPeer1.nodeID,
+		NewThrottledMessageQueue(
+			rawPeer0.config.Metrics,
+			rawPeer1.nodeID,
+			logging.NoLog{},
+			throttling.NewNoOutboundThrottler(),
+		),
+	)
+	peer1 := Start(
+		rawPeer1.config,
+		rawPeer1.conn,
+		rawPeer0.cert,
+		rawPeer0.nodeID,
+		NewThrottledMessageQueue(
+			rawPeer1.config.Metrics,
+			rawPeer0.nodeID,
+			logging.NoLog{},
+			throttling.NewNoOutboundThrottler(),
+		),
+	)
+
+	// Both peers close the connection, as their network names don't match.
+	err := peer0.AwaitClosed(context.Background())
+	require.NoError(err)
+	require.False(peer0.Ready())
+	err = peer1.AwaitClosed(context.Background())
+	require.NoError(err)
+	require.False(peer1.Ready())
+}
+
+func TestVMVersions(t *testing.T) {
+	require := require.New(t)
+
+	rawPeer0, rawPeer1 := makeRawTestPeers(t)
+	vmVersions := map[ids.ID]string{
+		ids.GenerateTestID(): "v1.0.0",
+	}
+	rawPeer0.config.Network.(*testNetwork).vmVersions = vmVersions
+
+	peer0 := Start(
+		rawPeer0.config,
+		rawPeer0.conn,
+		rawPeer1.cert,
+		rawPeer1.nodeID,
+		NewThrottledMessageQueue(
+			rawPeer0.config.Metrics,
+			rawPeer1.nodeID,
+			logging.NoLog{},
+			throttling.NewNoOutboundThrottler(),
+		),
+	)
+	peer1 := Start(
+		rawPeer1.config,
+		rawPeer1.conn,
+		rawPeer0.cert,
+		rawPeer0.nodeID,
+		NewThrottledMessageQueue(
+			rawPeer1.config.Metrics,
+			rawPeer0.nodeID,
+			logging.NoLog{},
+			throttling.NewNoOutboundThrottler(),
+		),
+	)
+
+	err := peer0.AwaitReady(context.Background())
+	require.NoError(err)
+	err = peer1.AwaitReady(context.Background())
+	require.NoError(err)
+
+	// The versions are reported in the handshake.
+	require.Equal(vmVersions, peer1.VMVersions())
+	require.Equal(vmVersions, peer1.Info().VMVersions)
+	require.Empty(peer0.VMVersions())
+
+	peer0.StartClose()
+	err = peer0.AwaitClosed(context.Background())
+	require.NoError(err)
+	err = peer1.AwaitClosed(context.Background())
+	require.NoError(err)
+}
+
+func TestMaintenance(t *testing.T) {
+	require := require.New(t)
+
+	rawPeer0, rawPeer1 := makeRawTestPeers(t)
+	start := time.UnixMilli(time.Now().Add(time.Minute).UnixMilli())
+	end := start.Add(time.Hour)
+	network0 := rawPeer0.config.Network.(*testNetwork)
+	network0.maintenanceStart = start
+	network0.maintenanceEnd = end
+	// The window is announced in the pong peer0 replies to peer1's pings with.
+	rawPeer1.config.PingFrequency = 10 * time.Millisecond
+
+	peer0 := Start(
+		rawPeer0.config,
+		rawPeer0.conn,
+		rawPeer1.cert,
+		rawPeer1.nodeID,
+		NewThrottledMessageQueue(
+			rawPeer0.config.Metrics,
+			rawPeer1.nodeID,
+			logging.NoLog{},
+			throttling.NewNoOutboundThrottler(),
+		),
+	)
+	peer1 := Start(
+		rawPeer1.config,
+		rawPeer1.conn,
+		rawPeer0.cert,
+		rawPeer0.nodeID,
+		NewThrottledMessageQueue(
+			rawPeer1.config.Metrics,
+			rawPeer0.nodeID,
+			logging.NoLog{},
+			throttling.NewNoOutboundThrottler(),
+		),
+	)
+
+	err := peer0.AwaitReady(context.Background())
+	require.NoError(err)
+	err = peer1.AwaitReady(context.Background())
+	require.NoError(err)
+
+	require.Eventually(func() bool {
+		_, ok := peer1.Maintenance()
+		return ok
+	}, 5*time.Second, 10*time.Millisecond)
+
+	maintenance, _ := peer1.Maintenance()
+	require.True(start.Equal(maintenance.Start))
+	require.True(end.Equal(maintenance.End))
+	require.NotNil(peer1.Info().Maintenance)
+	_, ok := peer0.Maintenance()
+	require.False(ok)
+
+	peer0.StartClose()
+	err = peer0.AwaitClosed(context.Background())
+	require.NoError(err)
+	err = peer1.AwaitClosed(context.Background())
+	require.NoError(err)
+}
+
 func TestSend(t *testing.T) {
 	require := require.New(t)
 
@@ -287,3 +443,97 @@ func TestSend(t *testing.T) {
 	err = peer1.AwaitClosed(context.Background())
 	require.NoError(err)
 }
+
+func TestClockSkewObserved(t *testing.T) {
+	require := require.New(t)
+
+	rawPeer0, rawPeer1 := makeRawTestPeers(t)
+	rawPeer0.config.Network.(*testNetwork).clock.Set(time.Now().Add(-time.Hour))
+	clockSkew, err := clockskew.NewEstimator(
+		logging.NoLog{},
+		clockskew.Config{
+			WarnThreshold:     time.Second,
+			CriticalThreshold: time.Second,
+			MinPeers:          1,
+		},
+		"",
+		prometheus.NewRegistry(),
+	)
+	require.NoError(err)
+	rawPeer1.config.ClockSkew = clockSkew
+
+	peer0 := Start(
+		rawPeer0.config,
+		rawPeer0.conn,
+		rawPeer1.cert,
+		rawPeer1.nodeID,
+		NewThrottledMessageQueue(
+			rawPeer0.config.Metrics,
+			rawPeer1.nodeID,
+			logging.NoLog{},
+			throttling.NewNoOutboundThrottler(),
+		),
+	)
+	peer1 := Start(
+		rawPeer1.config,
+		rawPeer1.conn,
+		rawPeer0.cert,
+		rawPeer0.nodeID,
+		NewThrottledMessageQueue(
+			rawPeer1.config.Metrics,
+			rawPeer0.nodeID,
+			logging.NoLog{},
+			throttling.NewNoOutboundThrottler(),
+		),
+	)
+
+	// The peer is observed even though its time is out of sync, so the local
+	// clock is estimated to be an hour ahead.
+	err = peer1.AwaitClosed(context.Background())
+	require.NoError(err)
+	err = peer0.AwaitClosed(context.Background())
+	require.NoError(err)
+
+	skew, ok := clockSkew.Skew()
+	require.True(ok)
+	require.InDelta(time.Hour.Seconds(), skew.Seconds(), 2)
+}
+
+// testClockSkew records the last time observed.
+type testClockSkew struct {
+	clockskew.Estimator
+
+	observed time.Time
+}
+
+func (c *testClockSkew) Observe(_ ids.NodeID, peerTime time.Time) {
+	c.observed = peerTime
+}
+
+func TestPongClockSkewAccountsForLatency(t *testing.T) {
+	require := require.New(t)
+
+	clockSkew := &testClockSkew{}
+	p := &peer{
+		Config: &Config{
+			ClockSkew: clockSkew,
+		},
+	}
+	now := time.Unix(1000, 0)
+	p.Clock.Set(now)
+	pongTime := now.Add(-time.Minute)
+
+	// Without a ping, the time reported by the peer is used as is.
+	p.handlePong(&p2ppb.Pong{
+		MyTimeMs: uint64(pongTime.UnixMilli()),
+	})
+	require.Equal(pongTime, clockSkew.observed)
+
+	// The peer's time has advanced by half of the round trip since it was
+	// reported.
+	p.lastPingSent = now.Add(-200 * time.Millisecond).UnixNano()
+	p.handlePong(&p2ppb.Pong{
+		MyTimeMs: uint64(pongTime.UnixMilli()),
+	})
+	require.Equal(pongTime.Add(100*time.Millisecond), clockSkew.observed)
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/message"
 	"github.com/ava-labs/avalanchego/utils/ips"
+	"github.com/ava-labs/avalanchego/utils/timer/mockable"
 	"github.com/ava-labs/avalanchego/version"
 )
 
@@ -19,13 +20,20 @@ var _ Network = (*testNetwork)(nil)
 type testNetwork struct {
 	mc message.Creator
 
-	networkID uint32
-	ip        ips.IPPort
-	version   *version.Application
-	signer    crypto.Signer
-	subnets   ids.Set
+	networkID   uint32
+	networkName string
+	ip          ips.IPPort
+	version     *version.Application
+	signer      crypto.Signer
+	subnets     ids.Set
+	vmVersions  map[ids.ID]string
 
 	uptime uint8
+	clock  mockable.Clock
+
+	// Maintenance window announced in pongs, if non-zero
+	maintenanceStart time.Time
+	maintenanceEnd   time.Time
 }
 
 // NewTestNetwork creates and returns a new TestNetwork
@@ -55,6 +63,10 @@ func (*testNetwork) AllowConnection(ids.NodeID) bool {
 	return true
 }
 
+func (*testNetwork) AllowHandshake(ids.NodeID, ips.IPPort, *version.Application, []ids.ID) bool {
+	return true
+}
+
 func (*testNetwork) Track(ips.ClaimedIPPort) bool {
 	return true
 }
@@ -62,7 +74,7 @@ func (*testNetwork) Track(ips.ClaimedIPPort) bool {
 func (*testNetwork) Disconnected(ids.NodeID) {}
 
 func (n *testNetwork) Version() (message.OutboundMessage, error) {
-	now := uint64(time.Now().Unix())
+	now := n.clock.Unix()
 	unsignedIP := UnsignedIP{
 		IP:        n.ip,
 		Timestamp: now,
@@ -73,12 +85,14 @@ func (n *testNetwork) Version() (message.OutboundMessage, error) {
 	}
 	return n.mc.Version(
 		n.networkID,
+		n.networkName,
 		now,
 		n.ip,
 		n.version.String(),
 		now,
 		signedIP.Signature,
 		n.subnets.List(),
+		n.vmVersions,
 	)
 }
 
@@ -87,5 +101,5 @@ func (n *testNetwork) Peers() (message.OutboundMessage, error) {
 }
 
 func (n *testNetwork) Pong(ids.NodeID) (message.OutboundMessage, error) {
-	return n.mc.Pong(n.uptime)
+	return n.mc.Pong(n.uptime, n.vmVersions, n.clock.Time(), n.maintenanceStart, n.maintenanceEnd)
 }
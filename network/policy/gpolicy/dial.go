@@ -0,0 +1,36 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package gpolicy
+
+import (
+	"crypto/tls"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+
+	"github.com/ava-labs/avalanchego/vms/rpcchainvm/grpcutils"
+)
+
+// Prefix of the addresses of peer policy servers listening on a unix domain
+// socket
+const unixScheme = "unix://"
+
+// Dial connects to the peer policy server at [addr]. The server decides which
+// peers the node connects to, so it is only reached over a unix domain socket,
+// whose access is restricted by the permissions of the socket, or over TLS,
+// with the server authenticated by the root certificates of the system.
+func Dial(addr string) (*grpc.ClientConn, error) {
+	if strings.HasPrefix(addr, unixScheme) {
+		return grpcutils.Dial(addr)
+	}
+
+	opts := append(
+		grpcutils.DefaultDialOptions[:len(grpcutils.DefaultDialOptions):len(grpcutils.DefaultDialOptions)],
+		grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{
+			MinVersion: tls.VersionTLS12,
+		})),
+	)
+	return grpcutils.Dial(addr, opts...)
+}
@@ -0,0 +1,50 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package gpolicy
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/network/policy"
+
+	peerpolicypb "github.com/ava-labs/avalanchego/proto/pb/peerpolicy"
+)
+
+var _ policy.Policy = (*Client)(nil)
+
+// Client is a peer policy that talks over RPC.
+type Client struct {
+	client peerpolicypb.PeerPolicyClient
+}
+
+// NewClient returns a peer policy connected to a remote peer policy
+func NewClient(client peerpolicypb.PeerPolicyClient) *Client {
+	return &Client{client: client}
+}
+
+func (c *Client) Allow(ctx context.Context, peer policy.Peer) (bool, error) {
+	req := &peerpolicypb.AllowRequest{
+		NodeId:            peer.NodeID[:],
+		Inbound:           peer.Inbound,
+		RemoteIp:          peer.RemoteIP.IP,
+		RemotePort:        uint32(peer.RemoteIP.Port),
+		HandshakeComplete: peer.HandshakeComplete,
+	}
+	if peer.HandshakeComplete {
+		req.Ip = peer.IP.IP
+		req.Port = uint32(peer.IP.Port)
+		req.Version = peer.Version.String()
+		req.TrackedSubnets = make([][]byte, len(peer.TrackedSubnets))
+		for i, subnetID := range peer.TrackedSubnets {
+			subnetID := subnetID
+			req.TrackedSubnets[i] = subnetID[:]
+		}
+	}
+
+	resp, err := c.client.Allow(ctx, req)
+	if err != nil {
+		return false, err
+	}
+	return resp.Allowed, nil
+}
@@ -0,0 +1,67 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package gpolicy
+
+import (
+	"context"
+	"net"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/network/policy"
+	"github.com/ava-labs/avalanchego/utils/ips"
+	"github.com/ava-labs/avalanchego/version"
+
+	peerpolicypb "github.com/ava-labs/avalanchego/proto/pb/peerpolicy"
+)
+
+var _ peerpolicypb.PeerPolicyServer = (*Server)(nil)
+
+// Server is a peer policy that is managed over RPC.
+type Server struct {
+	peerpolicypb.UnsafePeerPolicyServer
+	policy policy.Policy
+}
+
+// NewServer returns a peer policy server that serves [policy]
+func NewServer(policy policy.Policy) *Server {
+	return &Server{policy: policy}
+}
+
+func (s *Server) Allow(ctx context.Context, req *peerpolicypb.AllowRequest) (*peerpolicypb.AllowResponse, error) {
+	nodeID, err := ids.ToNodeID(req.NodeId)
+	if err != nil {
+		return nil, err
+	}
+	peer := policy.Peer{
+		NodeID:  nodeID,
+		Inbound: req.Inbound,
+		RemoteIP: ips.IPPort{
+			IP:   net.IP(req.RemoteIp),
+			Port: uint16(req.RemotePort),
+		},
+		HandshakeComplete: req.HandshakeComplete,
+	}
+	if req.HandshakeComplete {
+		peer.IP = ips.IPPort{
+			IP:   net.IP(req.Ip),
+			Port: uint16(req.Port),
+		}
+		peer.Version, err = version.ParseApplication(req.Version)
+		if err != nil {
+			return nil, err
+		}
+		peer.TrackedSubnets = make([]ids.ID, len(req.TrackedSubnets))
+		for i, subnetIDBytes := range req.TrackedSubnets {
+			peer.TrackedSubnets[i], err = ids.ToID(subnetIDBytes)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	allowed, err := s.policy.Allow(ctx, peer)
+	return &peerpolicypb.AllowResponse{
+		Allowed: allowed,
+	}, err
+}
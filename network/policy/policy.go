@@ -0,0 +1,43 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package policy allows operators to decide which peers the node connects to,
+// without modifying the network.
+package policy
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/ips"
+	"github.com/ava-labs/avalanchego/version"
+)
+
+// Peer describes a peer that a connection decision is made about.
+type Peer struct {
+	// NodeID is the ID of the peer. Before the handshake is complete, it is
+	// the node the connection was dialed to, or empty if the connection is
+	// inbound.
+	NodeID ids.NodeID
+	// Inbound is true if the peer initiated the connection.
+	Inbound bool
+	// RemoteIP is the IP the connection is made with.
+	RemoteIP ips.IPPort
+
+	// HandshakeComplete is true if the fields below are set.
+	HandshakeComplete bool
+	// IP is the IP the peer signed in its handshake.
+	IP             ips.IPPort
+	Version        *version.Application
+	TrackedSubnets []ids.ID
+}
+
+// Policy decides which peers the node connects to.
+type Policy interface {
+	// Allow returns true if the node should connect to [peer].
+	//
+	// Allow is called once the connection is made, before its TLS handshake,
+	// and again once the handshake of the peer is complete. If an error is
+	// returned, the connection is dropped.
+	Allow(ctx context.Context, peer Peer) (bool, error)
+}
@@ -7,24 +7,37 @@ import (
 	"crypto/tls"
 	"time"
 
+	"github.com/ava-labs/avalanchego/api/accesslog"
+	"github.com/ava-labs/avalanchego/api/admin"
+	"github.com/ava-labs/avalanchego/api/pagination"
+	"github.com/ava-labs/avalanchego/api/server"
+	"github.com/ava-labs/avalanchego/api/shadow"
 	"github.com/ava-labs/avalanchego/chains"
+	"github.com/ava-labs/avalanchego/chains/quota"
+	"github.com/ava-labs/avalanchego/chains/replicas"
 	"github.com/ava-labs/avalanchego/genesis"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/nat"
 	"github.com/ava-labs/avalanchego/network"
 	"github.com/ava-labs/avalanchego/snow/consensus/avalanche"
+	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
 	"github.com/ava-labs/avalanchego/snow/networking/benchlist"
 	"github.com/ava-labs/avalanchego/snow/networking/router"
 	"github.com/ava-labs/avalanchego/snow/networking/sender"
 	"github.com/ava-labs/avalanchego/snow/networking/tracker"
 	"github.com/ava-labs/avalanchego/trace"
+	"github.com/ava-labs/avalanchego/utils/clockskew"
 	"github.com/ava-labs/avalanchego/utils/crypto/bls"
 	"github.com/ava-labs/avalanchego/utils/dynamicip"
+	"github.com/ava-labs/avalanchego/utils/gc"
 	"github.com/ava-labs/avalanchego/utils/ips"
 	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/maintenance"
 	"github.com/ava-labs/avalanchego/utils/profiler"
+	"github.com/ava-labs/avalanchego/utils/resource"
 	"github.com/ava-labs/avalanchego/utils/timer"
 	"github.com/ava-labs/avalanchego/vms"
+	"github.com/ava-labs/avalanchego/vms/rpcchainvm"
 )
 
 type IPCConfig struct {
@@ -51,11 +64,49 @@ type HTTPConfig struct {
 	HTTPSEnabled bool   `json:"httpsEnabled"`
 	HTTPSKey     []byte `json:"-"`
 	HTTPSCert    []byte `json:"-"`
+	// Client certificate authentication and certificate reloading
+	HTTPSConfig server.TLSConfig `json:"httpsConfig"`
 
 	APIAllowedOrigins []string `json:"apiAllowedOrigins"`
 
 	ShutdownTimeout time.Duration `json:"shutdownTimeout"`
 	ShutdownWait    time.Duration `json:"shutdownWait"`
+
+	// Duplicates read-only API requests to a secondary upstream
+	ShadowConfig shadow.Config `json:"shadowConfig"`
+
+	// Logs a sample of the API requests
+	AccessLogConfig accesslog.Config `json:"accessLogConfig"`
+
+	// Additional listeners that only serve the APIs of some chains
+	HTTPScopedListeners []server.ScopeConfig `json:"httpScopedListeners"`
+
+	// Listener that exclusively serves the privileged APIs
+	HTTPAdminListener server.AdminListenerConfig `json:"httpAdminListener"`
+
+	// Splits the JSON-RPC batch requests to some APIs
+	HTTPBatchConfig server.BatchConfig `json:"httpBatchConfig"`
+
+	// Limits the rate of the requests of each client to some APIs
+	HTTPRateLimits []server.RateLimit `json:"httpRateLimits"`
+
+	// Limits the size of the bodies of the requests to the APIs without a
+	// limit in [HTTPRequestBodySizeLimits]. If 0, they aren't limited.
+	HTTPMaxRequestBodySize int64 `json:"httpMaxRequestBodySize"`
+	// Limits the size of the bodies of the requests to some APIs
+	HTTPRequestBodySizeLimits []server.BodySizeLimit `json:"httpRequestBodySizeLimits"`
+
+	// Caches the responses to the API methods that chains mark as cacheable
+	HTTPResponseCacheConfig server.ResponseCacheConfig `json:"httpResponseCacheConfig"`
+
+	// Times out the requests to the APIs of chains without a timeout in
+	// [HTTPChainRequestTimeouts]. If 0, they aren't timed out.
+	HTTPChainRequestTimeout time.Duration `json:"httpChainRequestTimeout"`
+	// Times out the requests to the APIs of chains under some paths
+	HTTPChainRequestTimeouts []server.RouteTimeout `json:"httpChainRequestTimeouts"`
+
+	// HTTP/2 support of the API listeners
+	HTTP2Config server.HTTP2Config `json:"http2Config"`
 }
 
 type APIConfig struct {
@@ -64,11 +115,35 @@ type APIConfig struct {
 	IPCConfig        `json:"ipcConfig"`
 
 	// Enable/Disable APIs
-	AdminAPIEnabled    bool `json:"adminAPIEnabled"`
-	InfoAPIEnabled     bool `json:"infoAPIEnabled"`
-	KeystoreAPIEnabled bool `json:"keystoreAPIEnabled"`
-	MetricsAPIEnabled  bool `json:"metricsAPIEnabled"`
-	HealthAPIEnabled   bool `json:"healthAPIEnabled"`
+	AdminAPIEnabled            bool `json:"adminAPIEnabled"`
+	DebugAPIEnabled            bool `json:"debugAPIEnabled"`
+	EventsAPIEnabled           bool `json:"eventsAPIEnabled"`
+	OpenAPIEnabled             bool `json:"openAPIEnabled"`
+	InfoAPIEnabled             bool `json:"infoAPIEnabled"`
+	KeystoreAPIEnabled         bool `json:"keystoreAPIEnabled"`
+	KeystoreSessionsAPIEnabled bool `json:"keystoreSessionsAPIEnabled"`
+	MetricsAPIEnabled          bool `json:"metricsAPIEnabled"`
+	HealthAPIEnabled           bool `json:"healthAPIEnabled"`
+
+	// Limits of the clients of the event hub
+	EventsAPIConfig server.EventsConfig `json:"eventsAPIConfig"`
+
+	// If true, the Info API serves attestation evidence requested from the
+	// configfs-tsm interface at [InfoAPIAttestationTSMDir]
+	InfoAPIAttestationEnabled bool   `json:"infoAPIAttestationEnabled"`
+	InfoAPIAttestationTSMDir  string `json:"infoAPIAttestationTSMDir"`
+
+	// If true, the responses to [ResponseSigningMethods] are signed with the
+	// BLS key of the node
+	ResponseSigningEnabled bool     `json:"responseSigningEnabled"`
+	ResponseSigningMethods []string `json:"responseSigningMethods"`
+
+	// Maximum number of series each metrics namespace may report. If 0, the
+	// number of series isn't limited.
+	MetricsMaxSeriesPerNamespace int `json:"metricsMaxSeriesPerNamespace"`
+
+	// Overrides the maximum page size of the node's paginated API endpoints
+	MaxPageSizes pagination.Limits `json:"maxPageSizes"`
 }
 
 type IPConfig struct {
@@ -131,6 +206,10 @@ type DatabaseConfig struct {
 
 	// Path to config file
 	Config []byte `json:"-"`
+
+	// If true, the migrations that would be applied to the database are
+	// reported and the node exits without applying them
+	MigrateDryRun bool `json:"migrateDryRun"`
 }
 
 // Config contains all of the configurations of an Avalanche node.
@@ -166,6 +245,9 @@ type Config struct {
 	// Profiling configurations
 	ProfilerConfig profiler.Config `json:"profilerConfig"`
 
+	// Maintenance windows of the node
+	MaintenanceConfig maintenance.Config `json:"maintenanceConfig"`
+
 	// Logging configuration
 	LoggingConfig logging.Config `json:"loggingConfig"`
 
@@ -175,11 +257,78 @@ type Config struct {
 	// File Descriptor Limit
 	FdLimit uint64 `json:"fdLimit"`
 
+	// If true, the environment of the node is verified before it starts
+	PreflightChecksEnabled bool `json:"preflightChecksEnabled"`
+
+	// Garbage collection tuning of the node process
+	GCConfig      gc.Config `json:"gcConfig"`
+	GCBallastSize uint64    `json:"gcBallastSize"`
+
+	// Garbage collection tuning of the plugin processes, keyed by chain
+	ChainGCConfigs gc.ChainConfigs `json:"chainGCConfigs"`
+
+	// If true, plugins connect to the servers of the node over a single
+	// connection
+	PluginMultiplexEnabled bool `json:"pluginMultiplexEnabled"`
+
+	// If true, the node and its plugins communicate over unix domain sockets
+	PluginUnixSocketsEnabled bool `json:"pluginUnixSocketsEnabled"`
+
+	// Number of pages of elements the iterators of the plugins' databases
+	// fetch ahead of their consumer. If 0, each page is fetched with a
+	// separate request.
+	PluginDBIteratorPrefetch int `json:"pluginDBIteratorPrefetch"`
+
+	// Max size, in bytes, of the elements the plugins send in a single request
+	// when writing a batch to their databases. If 0, values are never split.
+	PluginDBMaxBatchBytes int `json:"pluginDBMaxBatchBytes"`
+
+	// If true, the plugins send and request a checksum with each value
+	// written to and read from their databases
+	PluginDBChecksumsEnabled bool `json:"pluginDBChecksumsEnabled"`
+
+	// Max duration the pending requests of the plugins to the servers of the
+	// node are given to finish when the plugins shut down
+	PluginShutdownDrainTimeout time.Duration `json:"pluginShutdownDrainTimeout"`
+
+	// Retries the calls to the plugins that fail because the plugins are
+	// unreachable, if the calls don't change the state of the plugins
+	PluginRPCRetry rpcchainvm.RetryConfig `json:"pluginRPCRetry"`
+
+	// If set, the plugin process of a chain that has limits in
+	// ChainResourceLimits is placed in a cgroup named after the chain under
+	// this cgroup
+	PluginCgroupRoot    string               `json:"pluginCgroupRoot"`
+	ChainResourceLimits resource.ChainLimits `json:"chainResourceLimits"`
+
+	// Name -> factory of a gRPC service the node can serve to plugins, in
+	// addition to the services every plugin is served. The services are
+	// provided by the program running the node, with
+	// config.WithPluginService.
+	PluginServices rpcchainvm.PluginServices `json:"-"`
+	// Chain ID or alias -> names of the services in PluginServices served to
+	// the chain's plugin
+	ChainPluginServices rpcchainvm.ChainPluginServices `json:"chainPluginServices"`
+
+	// If true, the node and its plugins authenticate each other with
+	// ephemeral TLS certificates
+	PluginMTLSEnabled bool `json:"pluginMTLSEnabled"`
+
+	// VM name or ID -> address of the VM, which runs outside of the node
+	ExternalVMs map[string]string `json:"externalVMs"`
+	// Host the servers that external VMs connect to listen on
+	ExternalVMListenHost string `json:"externalVMListenHost"`
+	// Authenticates the node and the external VMs to each other
+	ExternalVMTLSConfig *tls.Config `json:"-"`
+
 	// Consensus configuration
 	ConsensusParams avalanche.Parameters `json:"consensusParams"`
 
 	// Metrics
 	MeterVMEnabled bool `json:"meterVMEnabled"`
+	// Number of validators of each chain, by decreasing stake, whose query
+	// latencies are recorded individually
+	PeerQueryLatencyMetricsSize int `json:"peerQueryLatencyMetricsSize"`
 
 	// Router that is used to handle incoming consensus messages
 	ConsensusRouter          router.Router       `json:"-"`
@@ -197,9 +346,64 @@ type Config struct {
 	// ChainConfigs
 	ChainConfigs map[string]chains.ChainConfig `json:"-"`
 	ChainAliases map[ids.ID][]string           `json:"chainAliases"`
+	// Chain ID -> aliases whose API requests are redirected to the chain
+	ChainRetiredAliases map[ids.ID][]string `json:"chainRetiredAliases"`
+
+	// Chain alias -> ID or alias of the VM that audits the chain
+	ChainAuditVMs map[string]string `json:"chainAuditVMs"`
+
+	// Max size of the AppResponses delivered to a chain's VM. If 0, the size
+	// isn't limited.
+	AppResponseMaxSize int `json:"appResponseMaxSize"`
+	// Chain alias -> max size of the AppResponses delivered to the chain's VM
+	ChainAppResponseMaxSizes map[string]int `json:"chainAppResponseMaxSizes"`
+
+	// Number of notifications a chain's VM can queue for the consensus engine
+	ToEngineChannelSize int `json:"toEngineChannelSize"`
+	// Chain alias -> number of notifications the chain's VM can queue
+	ChainToEngineChannelSizes map[string]int `json:"chainToEngineChannelSizes"`
+
+	// Bytes per second the blob services of the chains upload and download.
+	// If 0, the bandwidth isn't capped.
+	BlobUploadBandwidth   uint64 `json:"blobUploadBandwidth"`
+	BlobDownloadBandwidth uint64 `json:"blobDownloadBandwidth"`
+
+	// Paces the blocks the chains' VMs are asked to build
+	BuildPacing block.BuildPacing `json:"buildPacing"`
+	// Chain alias -> pacing of the blocks the chain's VM is asked to build
+	ChainBuildPacing map[string]block.BuildPacing `json:"chainBuildPacing"`
+
+	// Chain alias -> read replicas of the chain's VM
+	ChainReadReplicas map[string]replicas.Config `json:"chainReadReplicas"`
+
+	// Profiles the node runs with
+	Profiles []string `json:"profiles"`
+
+	// If non-zero, a chain is reported as stale if it hasn't accepted a block
+	// for this long while a majority of its stake has accepted later blocks.
+	StaleChainTimeout time.Duration `json:"staleChainTimeout"`
+	// If true, stale chains are bootstrapped again
+	StaleChainResyncEnabled bool `json:"staleChainResyncEnabled"`
+
+	// If non-empty, each chain records the decisions of its consensus engine
+	// in a log in this directory
+	DecisionLogDir string `json:"decisionLogDir"`
+	// Size above which the oldest entries of a decision log are deleted
+	DecisionLogMaxSize uint64 `json:"decisionLogMaxSize"`
+
+	// If non-zero, polls that haven't finished after this long are sent again
+	// to up to [ConsensusHedgeBudget] backup validators
+	ConsensusHedgeDelay  time.Duration `json:"consensusHedgeDelay"`
+	ConsensusHedgeBudget int           `json:"consensusHedgeBudget"`
+
+	// Parent directory of the chains' data directories
+	ChainDataDir      string       `json:"chainDataDir"`
+	ChainDataDirQuota quota.Config `json:"chainDataDirQuota"`
 
 	// VM management
 	VMManager vms.Manager `json:"-"`
+	// VM ID -> aliases of the VMs aliased by the node's configuration
+	VMAliases map[ids.ID][]string `json:"vmAliases"`
 
 	// Halflife to use for the processing requests tracker.
 	// Larger halflife --> usage metrics change more slowly.
@@ -225,6 +429,10 @@ type Config struct {
 	RequiredAvailableDiskSpace         uint64 `json:"requiredAvailableDiskSpace"`
 	WarningThresholdAvailableDiskSpace uint64 `json:"warningThresholdAvailableDiskSpace"`
 
+	// Estimates the skew of the local clock, which is reported as a health
+	// check and annotates proposer window misses
+	ClockSkewConfig clockskew.Config `json:"clockSkewConfig"`
+
 	TraceConfig trace.Config `json:"traceConfig"`
 
 	// See comment on [MinPercentConnectedStakeHealthy] in platformvm.Config
@@ -233,6 +441,16 @@ type Config struct {
 	// See comment on [UseCurrentHeight] in platformvm.Config
 	UseCurrentHeight bool `json:"useCurrentHeight"`
 
+	// See comment on [ValidatorSnapshotEpochLength] in platformvm.Config
+	ValidatorSnapshotEpochLength uint64 `json:"validatorSnapshotEpochLength"`
+
+	// See comment on [StateDiffsEnabled] in platformvm.Config
+	PlatformStateDiffsEnabled bool `json:"platformStateDiffsEnabled"`
+
 	// ProvidedFlags contains all the flags set by the user
 	ProvidedFlags map[string]interface{} `json:"-"`
+
+	// ConfigOverrides contains the provided flags whose values differ from
+	// their defaults, along with where each of them was provided
+	ConfigOverrides []admin.ConfigOverride `json:"-"`
 }
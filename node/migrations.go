@@ -0,0 +1,51 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package node
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"go.uber.org/zap"
+
+	"github.com/ava-labs/avalanchego/database/migration"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+// migrations are the changes to the storage layout of the node's database, in
+// the order they're applied when the node starts. Once released, a migration
+// must never be modified or removed, and its version must never be reused.
+var migrations []*migration.Migration
+
+// ReportPendingMigrations logs the migrations that would be applied to the
+// database described by [config] without applying them.
+func ReportPendingMigrations(config DatabaseConfig, log logging.Logger) error {
+	registerer := prometheus.NewRegistry()
+	dbManager, err := newDBManager(config, log, registerer)
+	if err != nil {
+		return err
+	}
+	defer dbManager.Close()
+
+	currentDB := dbManager.Current()
+	migrator, err := migration.New(log, "migration", registerer, currentDB.Database, migrations)
+	if err != nil {
+		return err
+	}
+	pending, err := migrator.Pending()
+	if err != nil {
+		return err
+	}
+
+	log.Info("found pending migrations",
+		zap.Stringer("dbVersion", currentDB.Version),
+		zap.Int("numMigrations", len(pending)),
+	)
+	for _, m := range pending {
+		log.Info("pending migration",
+			zap.Uint64("version", m.Version),
+			zap.String("name", m.Name),
+		)
+	}
+	return nil
+}
@@ -11,6 +11,7 @@ import (
 	"io"
 	"net"
 	"os"
+	"path"
 	"path/filepath"
 	"sync"
 	"time"
@@ -25,19 +26,24 @@ import (
 
 	coreth "github.com/ava-labs/coreth/plugin/evm"
 
+	"github.com/ava-labs/avalanchego/api/accesslog"
 	"github.com/ava-labs/avalanchego/api/admin"
 	"github.com/ava-labs/avalanchego/api/auth"
+	"github.com/ava-labs/avalanchego/api/debug"
 	"github.com/ava-labs/avalanchego/api/health"
 	"github.com/ava-labs/avalanchego/api/info"
 	"github.com/ava-labs/avalanchego/api/keystore"
 	"github.com/ava-labs/avalanchego/api/metrics"
 	"github.com/ava-labs/avalanchego/api/server"
+	"github.com/ava-labs/avalanchego/api/shadow"
+	"github.com/ava-labs/avalanchego/api/signing"
 	"github.com/ava-labs/avalanchego/chains"
 	"github.com/ava-labs/avalanchego/chains/atomic"
 	"github.com/ava-labs/avalanchego/database"
 	"github.com/ava-labs/avalanchego/database/leveldb"
 	"github.com/ava-labs/avalanchego/database/manager"
 	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/database/migration"
 	"github.com/ava-labs/avalanchego/database/prefixdb"
 	"github.com/ava-labs/avalanchego/genesis"
 	"github.com/ava-labs/avalanchego/ids"
@@ -47,6 +53,7 @@ import (
 	"github.com/ava-labs/avalanchego/network"
 	"github.com/ava-labs/avalanchego/network/dialer"
 	"github.com/ava-labs/avalanchego/network/peer"
+	"github.com/ava-labs/avalanchego/network/policy/gpolicy"
 	"github.com/ava-labs/avalanchego/network/throttling"
 	"github.com/ava-labs/avalanchego/snow"
 	"github.com/ava-labs/avalanchego/snow/engine/common"
@@ -58,11 +65,15 @@ import (
 	"github.com/ava-labs/avalanchego/snow/validators"
 	"github.com/ava-labs/avalanchego/trace"
 	"github.com/ava-labs/avalanchego/utils"
+	"github.com/ava-labs/avalanchego/utils/attestation"
+	"github.com/ava-labs/avalanchego/utils/clockskew"
 	"github.com/ava-labs/avalanchego/utils/constants"
 	"github.com/ava-labs/avalanchego/utils/filesystem"
+	"github.com/ava-labs/avalanchego/utils/gc"
 	"github.com/ava-labs/avalanchego/utils/hashing"
 	"github.com/ava-labs/avalanchego/utils/ips"
 	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/maintenance"
 	"github.com/ava-labs/avalanchego/utils/math"
 	"github.com/ava-labs/avalanchego/utils/math/meter"
 	"github.com/ava-labs/avalanchego/utils/perms"
@@ -78,9 +89,11 @@ import (
 	"github.com/ava-labs/avalanchego/vms/platformvm/signer"
 	"github.com/ava-labs/avalanchego/vms/propertyfx"
 	"github.com/ava-labs/avalanchego/vms/registry"
+	"github.com/ava-labs/avalanchego/vms/rpcchainvm"
 	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
 
 	ipcsapi "github.com/ava-labs/avalanchego/api/ipcs"
+	peerpolicypb "github.com/ava-labs/avalanchego/proto/pb/peerpolicy"
 )
 
 var (
@@ -110,6 +123,9 @@ type Node struct {
 	// Indexes blocks, transactions and blocks
 	indexer indexer.Indexer
 
+	// Publishes the events of the chains. Nil if the event hub is disabled.
+	events *server.EventHub
+
 	// Handles calls to Keystore API
 	keystore keystore.Keystore
 
@@ -125,6 +141,9 @@ type Node struct {
 	// Manages creation of blockchains and routing messages to them
 	chainManager chains.Manager
 
+	// Records the staleness incidents of the chains
+	incidents common.IncidentLog
+
 	// Manages validator benching
 	benchlistManager benchlist.Manager
 
@@ -139,6 +158,8 @@ type Node struct {
 	// Net runs the networking stack
 	networkNamespace string
 	Net              network.Network
+	// peerPolicyConn is the connection to the peer policy server, if any
+	peerPolicyConn io.Closer
 
 	// tlsKeyLogWriterCloser is a debug file handle that writes all the TLS
 	// session keys. This value should only be non-nil during debugging.
@@ -153,6 +174,15 @@ type Node struct {
 	// Handles HTTP API calls
 	APIServer server.Server
 
+	// Signs the responses to the designated API methods. Nil if response
+	// signing is disabled.
+	responseSigner signing.Signer
+
+	// Begins and ends the maintenance windows of the node
+	maintenance maintenance.Scheduler
+	// Rejects API requests during maintenance windows
+	apiDrainer maintenance.Drainer
+
 	// This node's configuration
 	Config *Config
 
@@ -174,12 +204,21 @@ type Node struct {
 	// Metrics Registerer
 	MetricsRegisterer *prometheus.Registry
 	MetricsGatherer   metrics.MultiGatherer
+	// Limits the number of series reported by each metrics namespace
+	metricsGuard metrics.CardinalityGuard
 
 	// VM endpoint registry
 	VMRegistry registry.VMRegistry
 
 	resourceManager resource.Manager
 
+	// Estimates the skew of the local clock
+	clockSkew clockskew.Estimator
+
+	// Referenced for the lifetime of the node so that the garbage collector
+	// keeps counting it towards the live heap
+	gcBallast []byte
+
 	// Tracks the CPU/disk usage caused by processing
 	// messages of each peer.
 	resourceTracker tracker.ResourceTracker
@@ -199,6 +238,26 @@ type Node struct {
  ******************************************************************************
  */
 
+// vmVersions returns the versions of the registered VMs, keyed by VM ID.
+func (n *Node) vmVersions() map[ids.ID]string {
+	versions, err := n.Config.VMManager.Versions()
+	if err != nil {
+		n.Log.Warn("failed to get VM versions",
+			zap.Error(err),
+		)
+		return nil
+	}
+	vmVersions := make(map[ids.ID]string, len(versions))
+	for alias, version := range versions {
+		vmID, err := n.Config.VMManager.Lookup(alias)
+		if err != nil {
+			continue
+		}
+		vmVersions[vmID] = version
+	}
+	return vmVersions
+}
+
 // Initialize the networking layer.
 // Assumes [n.CPUTracker] and [n.CPUTargeter] have been initialized.
 func (n *Node) initNetworking(primaryNetVdrs validators.Set) error {
@@ -305,6 +364,21 @@ func (n *Node) initNetworking(primaryNetVdrs validators.Set) error {
 	n.Config.NetworkConfig.ResourceTracker = n.resourceTracker
 	n.Config.NetworkConfig.CPUTargeter = n.cpuTargeter
 	n.Config.NetworkConfig.DiskTargeter = n.diskTargeter
+	n.Config.NetworkConfig.VMVersions = n.vmVersions
+	n.Config.NetworkConfig.ClockSkew = n.clockSkew
+	n.Config.NetworkConfig.Maintenance = n.maintenance
+
+	if addr := n.Config.NetworkConfig.PeerPolicyAddr; addr != "" {
+		n.Log.Info("consulting external peer policy",
+			zap.String("addr", addr),
+		)
+		conn, err := gpolicy.Dial(addr)
+		if err != nil {
+			return fmt.Errorf("couldn't dial peer policy server: %w", err)
+		}
+		n.peerPolicyConn = conn
+		n.Config.NetworkConfig.PeerPolicy = gpolicy.NewClient(peerpolicypb.NewPeerPolicyClient(conn))
+	}
 
 	n.Net, err = network.NewNetwork(
 		&n.Config.NetworkConfig,
@@ -408,6 +482,46 @@ func (n *Node) Dispatch() error {
 		n.Shutdown(1)
 	})
 
+	// Start the scoped API listeners
+	for _, scope := range n.Config.HTTPScopedListeners {
+		scope := scope
+		go n.Log.RecoverAndPanic(func() {
+			var err error
+			if n.Config.HTTPSEnabled {
+				err = n.APIServer.DispatchScopedTLS(scope, n.Config.HTTPSCert, n.Config.HTTPSKey)
+			} else {
+				err = n.APIServer.DispatchScoped(scope)
+			}
+			if !n.shuttingDown.GetValue() {
+				n.Log.Fatal("scoped API server dispatch failed",
+					zap.Uint16("port", scope.Port),
+					zap.Error(err),
+				)
+			}
+			n.Shutdown(1)
+		})
+	}
+
+	// Start the admin API listener
+	if n.Config.HTTPAdminListener.Enabled() {
+		go n.Log.RecoverAndPanic(func() {
+			var err error
+			// Unix sockets are only reachable from the node's host, so they
+			// don't use TLS.
+			if n.Config.HTTPSEnabled && n.Config.HTTPAdminListener.Socket == "" {
+				err = n.APIServer.DispatchAdminTLS(n.Config.HTTPSCert, n.Config.HTTPSKey)
+			} else {
+				err = n.APIServer.DispatchAdmin()
+			}
+			if !n.shuttingDown.GetValue() {
+				n.Log.Fatal("admin API server dispatch failed",
+					zap.Error(err),
+				)
+			}
+			n.Shutdown(1)
+		})
+	}
+
 	// Add state sync nodes to the peer network
 	for i, peerIP := range n.Config.StateSyncIPs {
 		n.Net.ManuallyTrack(n.Config.StateSyncIDs[i], peerIP)
@@ -435,6 +549,14 @@ func (n *Node) Dispatch() error {
 		}
 	}
 
+	if n.peerPolicyConn != nil {
+		if err := n.peerPolicyConn.Close(); err != nil {
+			n.Log.Debug("error closing peer policy connection",
+				zap.Error(err),
+			)
+		}
+	}
+
 	// Wait until the node is done shutting down before returning
 	n.DoneShuttingDown.Wait()
 	return err
@@ -446,25 +568,26 @@ func (n *Node) Dispatch() error {
  ******************************************************************************
  */
 
-func (n *Node) initDatabase() error {
-	// start the db manager
-	var (
-		dbManager manager.Manager
-		err       error
-	)
-	switch n.Config.DatabaseConfig.Name {
+// newDBManager opens the databases described by [config].
+func newDBManager(config DatabaseConfig, log logging.Logger, registerer prometheus.Registerer) (manager.Manager, error) {
+	switch config.Name {
 	case leveldb.Name:
-		dbManager, err = manager.NewLevelDB(n.Config.DatabaseConfig.Path, n.Config.DatabaseConfig.Config, n.Log, version.CurrentDatabase, "db_internal", n.MetricsRegisterer)
+		return manager.NewLevelDB(config.Path, config.Config, log, version.CurrentDatabase, "db_internal", registerer)
 	case memdb.Name:
-		dbManager = manager.NewMemDB(version.CurrentDatabase)
+		return manager.NewMemDB(version.CurrentDatabase), nil
 	default:
-		err = fmt.Errorf(
+		return nil, fmt.Errorf(
 			"db-type was %q but should have been one of {%s, %s}",
-			n.Config.DatabaseConfig.Name,
+			config.Name,
 			leveldb.Name,
 			memdb.Name,
 		)
 	}
+}
+
+func (n *Node) initDatabase() error {
+	// start the db manager
+	dbManager, err := newDBManager(n.Config.DatabaseConfig, n.Log, n.MetricsRegisterer)
 	if err != nil {
 		return err
 	}
@@ -475,6 +598,7 @@ func (n *Node) initDatabase() error {
 	}
 
 	n.DBManager = meterDBManager
+	n.maintenance.Register("database", &databaseFlusher{dbManager: dbManager})
 
 	currentDB := dbManager.Current()
 	n.Log.Info("initializing database",
@@ -505,7 +629,12 @@ func (n *Node) initDatabase() error {
 	if genesisHash != expectedGenesisHash {
 		return fmt.Errorf("db contains invalid genesis hash. DB Genesis: %s Generated Genesis: %s", genesisHash, expectedGenesisHash)
 	}
-	return nil
+
+	migrator, err := migration.New(n.Log, "migration", n.MetricsRegisterer, n.DB, migrations)
+	if err != nil {
+		return err
+	}
+	return migrator.Run()
 }
 
 // Set the node IDs of the peers this node should first connect to
@@ -559,6 +688,7 @@ func (n *Node) initIndexer() error {
 		ShutdownF: func() {
 			n.Shutdown(0) // TODO put exit code here
 		},
+		MaxPageSizes: n.Config.MaxPageSizes,
 	})
 	if err != nil {
 		return fmt.Errorf("couldn't create index for txs: %w", err)
@@ -587,10 +717,99 @@ func (n *Node) initChains(genesisBytes []byte) {
 	n.chainManager.StartChainCreator(platformChain)
 }
 
+// initGC applies the garbage collection config of the node process
+func (n *Node) initGC() error {
+	if err := gc.Apply(n.Config.GCConfig); err != nil {
+		return err
+	}
+	if n.Config.GCBallastSize > 0 {
+		n.Log.Info("allocating memory ballast",
+			zap.Uint64("size", n.Config.GCBallastSize),
+		)
+		n.gcBallast = gc.NewBallast(n.Config.GCBallastSize)
+	}
+	return nil
+}
+
 // initAPIServer initializes the server that handles HTTP calls
 func (n *Node) initAPIServer() error {
 	n.Log.Info("initializing API server")
 	n.APIServer = server.New()
+	if err := n.APIServer.ConfigureHTTP2(n.Config.HTTP2Config); err != nil {
+		return fmt.Errorf("couldn't configure HTTP/2: %w", err)
+	}
+	if err := n.APIServer.ConfigureTLS(n.Config.HTTPSConfig); err != nil {
+		return fmt.Errorf("couldn't configure HTTPs: %w", err)
+	}
+	if err := n.APIServer.ConfigureAdminListener(n.Config.HTTPAdminListener); err != nil {
+		return fmt.Errorf("couldn't configure the admin listener: %w", err)
+	}
+	if err := n.APIServer.ConfigureBatches(n.Config.HTTPBatchConfig); err != nil {
+		return fmt.Errorf("couldn't configure batch requests: %w", err)
+	}
+	if err := n.APIServer.ConfigureResponseCache(n.Config.HTTPResponseCacheConfig); err != nil {
+		return fmt.Errorf("couldn't configure the response cache: %w", err)
+	}
+	if err := n.APIServer.ConfigureTimeouts(n.Config.HTTPChainRequestTimeout, n.Config.HTTPChainRequestTimeouts); err != nil {
+		return fmt.Errorf("couldn't configure request timeouts: %w", err)
+	}
+	if err := n.APIServer.ConfigureMaxPageSizes(n.Config.MaxPageSizes); err != nil {
+		return fmt.Errorf("couldn't configure max page sizes: %w", err)
+	}
+
+	var wrappers []server.Wrapper
+	if n.Config.ShadowConfig.Enabled() {
+		shadower, err := shadow.New(n.Log, "api_shadow", n.MetricsRegisterer, n.Config.ShadowConfig)
+		if err != nil {
+			return fmt.Errorf("couldn't create request shadower: %w", err)
+		}
+		n.Log.Info("API request shadowing is enabled",
+			zap.String("upstream", n.Config.ShadowConfig.Upstream),
+			zap.Float64("percentage", n.Config.ShadowConfig.Percentage),
+		)
+		// The shadower is added before the auth wrapper so that only
+		// authorized requests are shadowed.
+		wrappers = append(wrappers, shadower)
+	}
+	if n.Config.ResponseSigningEnabled {
+		n.responseSigner = signing.New(
+			n.Log,
+			n.Config.NetworkID,
+			n.ID,
+			n.Config.StakingSigningKey,
+			n.Config.ResponseSigningMethods,
+		)
+		n.Log.Info("API response signing is enabled",
+			zap.Strings("methods", n.Config.ResponseSigningMethods),
+		)
+		// The signer is added before the auth wrapper so that only the
+		// responses to authorized requests are signed.
+		wrappers = append(wrappers, n.responseSigner)
+	}
+	// The drainer is added last, other than the auth wrapper, so that the
+	// requests it rejects aren't shadowed or signed.
+	n.apiDrainer = maintenance.NewDrainer()
+	n.maintenance.Register("api", n.apiDrainer)
+	wrappers = append(wrappers, n.apiDrainer)
+
+	// The access logger is added after every other wrapper, including the auth
+	// wrapper, so that the requests they reject are logged.
+	var accessLogWrappers []server.Wrapper
+	if n.Config.AccessLogConfig.Enabled {
+		accessLog, err := n.LogFactory.Make("access")
+		if err != nil {
+			return fmt.Errorf("couldn't create access log: %w", err)
+		}
+		accessLogger, err := accesslog.New(accessLog, n.Config.AccessLogConfig)
+		if err != nil {
+			return fmt.Errorf("couldn't create access logger: %w", err)
+		}
+		n.Log.Info("API access logging is enabled",
+			zap.Float64("percentage", n.Config.AccessLogConfig.Percentage),
+			zap.Strings("excludedPaths", n.Config.AccessLogConfig.ExcludedPaths),
+		)
+		accessLogWrappers = append(accessLogWrappers, accessLogger)
+	}
 
 	if !n.Config.APIRequireAuthToken {
 		n.APIServer.Initialize(
@@ -603,11 +822,12 @@ func (n *Node) initAPIServer() error {
 			n.ID,
 			n.Config.TraceConfig.Enabled,
 			n.tracer,
+			append(wrappers, accessLogWrappers...)...,
 		)
-		return nil
+		return n.wrapAPIServer()
 	}
 
-	a, err := auth.New(n.Log, "auth", n.Config.APIAuthPassword)
+	a, err := auth.New(n.Log, "auth", n.Config.APIAuthPassword, n.APIServer.ResolvePath)
 	if err != nil {
 		return err
 	}
@@ -622,8 +842,11 @@ func (n *Node) initAPIServer() error {
 		n.ID,
 		n.Config.TraceConfig.Enabled,
 		n.tracer,
-		a,
+		append(append(wrappers, a), accessLogWrappers...)...,
 	)
+	if err := n.wrapAPIServer(); err != nil {
+		return err
+	}
 
 	// only create auth service if token authorization is required
 	n.Log.Info("API authorization is enabled. Auth tokens must be passed in the header of API requests, except requests to the auth service.")
@@ -638,6 +861,40 @@ func (n *Node) initAPIServer() error {
 	return n.APIServer.AddRoute(handler, &sync.RWMutex{}, "auth", "")
 }
 
+// wrapAPIServer limits and measures the API requests. The requests are
+// measured before they are limited, so that the rejected requests are measured
+// too.
+func (n *Node) wrapAPIServer() error {
+	if err := n.limitAPIRequests(); err != nil {
+		return err
+	}
+	return n.APIServer.MeasureRequests("api", n.MetricsRegisterer)
+}
+
+// limitAPIRequests limits the size of the API requests and the rate of the API
+// requests of each client. The size is limited before any other handling of
+// the requests, so that the requests over the limit are rejected as cheaply as
+// possible. The rate is limited once the requests passed the cors handler, so
+// that browsers can read the rejections.
+func (n *Node) limitAPIRequests() error {
+	err := n.APIServer.LimitBodySize(n.Config.HTTPMaxRequestBodySize, n.Config.HTTPRequestBodySizeLimits)
+	if err != nil {
+		return err
+	}
+	return n.limitAPIRate()
+}
+
+// limitAPIRate limits the rate of the API requests of each client.
+func (n *Node) limitAPIRate() error {
+	if len(n.Config.HTTPRateLimits) == 0 {
+		return nil
+	}
+	n.Log.Info("API rate limiting is enabled",
+		zap.Reflect("limits", n.Config.HTTPRateLimits),
+	)
+	return n.APIServer.LimitRate("api", n.MetricsRegisterer, n.Config.HTTPRateLimits)
+}
+
 // Add the default VM aliases
 func (n *Node) addDefaultVMAliases() error {
 	n.Log.Info("adding the default VM aliases")
@@ -681,6 +938,7 @@ func (n *Node) initChainManager(avaxAssetID ids.ID) error {
 	timeoutManager, err := timeout.NewManager(
 		&n.Config.AdaptiveTimeoutConfig,
 		n.benchlistManager,
+		n.Config.PeerQueryLatencyMetricsSize,
 		"requests",
 		n.MetricsRegisterer,
 	)
@@ -706,6 +964,7 @@ func (n *Node) initChainManager(avaxAssetID ids.ID) error {
 		return fmt.Errorf("couldn't initialize chain router: %w", err)
 	}
 
+	n.incidents = common.NewIncidentLog()
 	n.chainManager = chains.New(&chains.ManagerConfig{
 		StakingEnabled:                          n.Config.EnableStaking,
 		StakingCert:                             n.Config.StakingTLSCert,
@@ -738,6 +997,27 @@ func (n *Node) initChainManager(avaxAssetID ids.ID) error {
 		Metrics:                                 n.MetricsGatherer,
 		SubnetConfigs:                           n.Config.SubnetConfigs,
 		ChainConfigs:                            n.Config.ChainConfigs,
+		ChainAuditVMs:                           n.Config.ChainAuditVMs,
+		AppResponseMaxSize:                      n.Config.AppResponseMaxSize,
+		ChainAppResponseMaxSizes:                n.Config.ChainAppResponseMaxSizes,
+		ToEngineChannelSize:                     n.Config.ToEngineChannelSize,
+		ChainToEngineChannelSizes:               n.Config.ChainToEngineChannelSizes,
+		BlobUploadBandwidth:                     n.Config.BlobUploadBandwidth,
+		BlobDownloadBandwidth:                   n.Config.BlobDownloadBandwidth,
+		BuildPacing:                             n.Config.BuildPacing,
+		ChainBuildPacing:                        n.Config.ChainBuildPacing,
+		ChainReadReplicas:                       n.Config.ChainReadReplicas,
+		ClockSkew:                               n.clockSkew,
+		ChainDataDir:                            n.Config.ChainDataDir,
+		ChainDataDirQuota:                       n.Config.ChainDataDirQuota,
+		ChainHealthDependencies:                 []string{"network", "database"},
+		StaleChainTimeout:                       n.Config.StaleChainTimeout,
+		StaleChainResyncEnabled:                 n.Config.StaleChainResyncEnabled,
+		Incidents:                               n.incidents,
+		DecisionLogDir:                          n.Config.DecisionLogDir,
+		DecisionLogMaxSize:                      n.Config.DecisionLogMaxSize,
+		HedgeDelay:                              n.Config.ConsensusHedgeDelay,
+		HedgeBudget:                             n.Config.ConsensusHedgeBudget,
 		ConsensusGossipFrequency:                n.Config.ConsensusGossipFrequency,
 		GossipConfig:                            n.Config.GossipConfig,
 		BootstrapMaxTimeGetAncestors:            n.Config.BootstrapMaxTimeGetAncestors,
@@ -753,6 +1033,10 @@ func (n *Node) initChainManager(avaxAssetID ids.ID) error {
 
 	// Notify the API server when new chains are created
 	n.chainManager.AddRegistrant(n.APIServer)
+	if n.responseSigner != nil {
+		// Notify the response signer of the validator state of the chains
+		n.chainManager.AddRegistrant(n.responseSigner)
+	}
 	return nil
 }
 
@@ -808,11 +1092,15 @@ func (n *Node) initVMs() error {
 				BanffTime:                       version.GetBanffTime(n.Config.NetworkID),
 				MinPercentConnectedStakeHealthy: n.Config.MinPercentConnectedStakeHealthy,
 				UseCurrentHeight:                n.Config.UseCurrentHeight,
+				ValidatorSnapshotEpochLength:    n.Config.ValidatorSnapshotEpochLength,
+				StateDiffsEnabled:               n.Config.PlatformStateDiffsEnabled,
+				MaxPageSizes:                    n.Config.MaxPageSizes,
 			},
 		}),
 		vmRegisterer.Register(context.TODO(), constants.AVMID, &avm.Factory{
 			TxFee:            n.Config.TxFee,
 			CreateAssetTxFee: n.Config.CreateAssetTxFee,
+			MaxPageSizes:     n.Config.MaxPageSizes,
 		}),
 		vmRegisterer.Register(context.TODO(), constants.EVMID, &coreth.Factory{}),
 		n.Config.VMManager.RegisterFactory(context.TODO(), secp256k1fx.ID, &secp256k1fx.Factory{}),
@@ -823,15 +1111,39 @@ func (n *Node) initVMs() error {
 		return errs.Err
 	}
 
+	externalVMs := make(map[string]rpcchainvm.ExternalConfig, len(n.Config.ExternalVMs))
+	for name, addr := range n.Config.ExternalVMs {
+		externalVMs[name] = rpcchainvm.ExternalConfig{
+			Addr:       addr,
+			ListenHost: n.Config.ExternalVMListenHost,
+			TLSConfig:  n.Config.ExternalVMTLSConfig,
+		}
+	}
+
 	// initialize the vm registry
 	n.VMRegistry = registry.NewVMRegistry(registry.VMRegistryConfig{
 		VMGetter: registry.NewVMGetter(registry.VMGetterConfig{
-			FileReader:      filesystem.NewReader(),
-			Manager:         n.Config.VMManager,
-			PluginDirectory: n.Config.PluginDir,
-			CPUTracker:      n.resourceManager,
+			FileReader:           filesystem.NewReader(),
+			Manager:              n.Config.VMManager,
+			PluginDirectory:      n.Config.PluginDir,
+			CPUTracker:           n.resourceManager,
+			GCConfigs:            n.Config.ChainGCConfigs,
+			Multiplex:            n.Config.PluginMultiplexEnabled,
+			MTLS:                 n.Config.PluginMTLSEnabled,
+			UnixSockets:          n.Config.PluginUnixSocketsEnabled,
+			DBIteratorPrefetch:   n.Config.PluginDBIteratorPrefetch,
+			DBMaxBatchBytes:      n.Config.PluginDBMaxBatchBytes,
+			DBChecksums:          n.Config.PluginDBChecksumsEnabled,
+			ShutdownDrainTimeout: n.Config.PluginShutdownDrainTimeout,
+			RPCRetry:             n.Config.PluginRPCRetry,
+			CgroupRoot:           n.Config.PluginCgroupRoot,
+			ResourceLimits:       n.Config.ChainResourceLimits,
+			PluginServices:       n.Config.PluginServices,
+			ChainPluginServices:  n.Config.ChainPluginServices,
+			ExternalVMs:          externalVMs,
 		}),
-		VMRegisterer: vmRegisterer,
+		VMRegisterer:    vmRegisterer,
+		PluginDirectory: n.Config.PluginDir,
 	})
 
 	// register any vms that need to be installed as plugins from disk
@@ -862,6 +1174,22 @@ func (n *Node) initKeystoreAPI() error {
 	if err != nil {
 		return err
 	}
+	if n.Config.KeystoreSessionsAPIEnabled {
+		n.Log.Info("initializing keystore session API")
+		sessionHandler, err := n.keystore.CreateSessionHandler()
+		if err != nil {
+			return err
+		}
+		handler := &common.HTTPHandler{
+			LockOptions: common.NoLock,
+			Handler:     sessionHandler,
+		}
+		if err := n.APIServer.AddRoute(handler, &sync.RWMutex{}, "keystore", "/session"); err != nil {
+			return err
+		}
+	} else {
+		n.Log.Info("skipping keystore session API initialization because it has been disabled")
+	}
 	if !n.Config.KeystoreAPIEnabled {
 		n.Log.Info("skipping keystore API initialization because it has been disabled")
 		return nil
@@ -878,7 +1206,8 @@ func (n *Node) initKeystoreAPI() error {
 // Assumes n.APIServer is already set
 func (n *Node) initMetricsAPI() error {
 	n.MetricsRegisterer = prometheus.NewRegistry()
-	n.MetricsGatherer = metrics.NewMultiGatherer()
+	n.metricsGuard = metrics.NewCardinalityGuard(n.Log, n.Config.MetricsMaxSeriesPerNamespace)
+	n.MetricsGatherer = n.metricsGuard
 
 	if !n.Config.MetricsAPIEnabled {
 		n.Log.Info("skipping metrics API initialization because it has been disabled")
@@ -901,6 +1230,11 @@ func (n *Node) initMetricsAPI() error {
 		return err
 	}
 
+	// Duration of the most recent garbage collection pause.
+	if err := n.MetricsRegisterer.Register(gc.NewCollector()); err != nil {
+		return err
+	}
+
 	n.Log.Info("initializing metrics API")
 
 	return n.APIServer.AddRoute(
@@ -918,7 +1252,7 @@ func (n *Node) initMetricsAPI() error {
 }
 
 // initAdminAPI initializes the Admin API service
-// Assumes n.log, n.chainManager, and n.ValidatorAPI already initialized
+// Assumes n.log, n.chainManager, n.health, n.Net, and n.DB already initialized
 func (n *Node) initAdminAPI() error {
 	if !n.Config.AdminAPIEnabled {
 		n.Log.Info("skipping admin API initialization because it has been disabled")
@@ -935,6 +1269,19 @@ func (n *Node) initAdminAPI() error {
 			NodeConfig:   n.Config,
 			VMManager:    n.Config.VMManager,
 			VMRegistry:   n.VMRegistry,
+			PluginDir:    n.Config.PluginDir,
+
+			ProvidedFlags:   n.Config.ProvidedFlags,
+			ConfigOverrides: n.Config.ConfigOverrides,
+			Health:          n.health,
+			Network:         n.Net,
+			MetricsGatherer: n.MetricsGatherer,
+			DB:              n.DB,
+			DBDir:           n.Config.DatabaseConfig.Path,
+			LogDir:          n.Config.LoggingConfig.Directory,
+			Incidents:       n.incidents,
+			Maintenance:     n.maintenance,
+			CORS:            n.APIServer,
 		},
 	)
 	if err != nil {
@@ -943,6 +1290,71 @@ func (n *Node) initAdminAPI() error {
 	return n.APIServer.AddRoute(service, &sync.RWMutex{}, "admin", "")
 }
 
+// initDebugAPI initializes the Debug API service
+// Assumes n.APIServer and n.chainManager are already initialized
+func (n *Node) initDebugAPI() error {
+	if !n.Config.DebugAPIEnabled {
+		n.Log.Info("skipping debug API initialization because it has been disabled")
+		return nil
+	}
+	n.Log.Info("initializing debug API")
+	service := debug.New(n.Log, n.chainManager)
+	handler, err := service.Handler()
+	if err != nil {
+		return err
+	}
+	// Chain manager will notify the service when a chain is created
+	n.chainManager.AddRegistrant(service)
+	return n.APIServer.AddRoute(handler, &sync.RWMutex{}, "debug", "")
+}
+
+// initEventsAPI initializes the event hub
+// Assumes n.APIServer, n.chainManager and the acceptor groups are already
+// initialized
+func (n *Node) initEventsAPI() error {
+	if !n.Config.EventsAPIEnabled {
+		n.Log.Info("skipping events API initialization because it has been disabled")
+		return nil
+	}
+	n.Log.Info("initializing events API")
+	events, err := server.NewEventHub(
+		n.Log,
+		n.Config.EventsAPIConfig,
+		n.chainManager,
+		n.APIServer,
+		n.DecisionAcceptorGroup,
+		n.ConsensusAcceptorGroup,
+	)
+	if err != nil {
+		return err
+	}
+	n.events = events
+	// Chain manager will notify the hub when a chain is created
+	n.chainManager.AddRegistrant(events)
+	handler := &common.HTTPHandler{
+		LockOptions: common.NoLock,
+		Handler:     events,
+		OpenAPI:     []byte(`{"get":{"summary":"Subscribe to the containers accepted by the chains over a WebSocket","responses":{"101":{"description":"Switching to the WebSocket protocol"}}}}`),
+	}
+	return n.APIServer.AddRoute(handler, &sync.RWMutex{}, "events", "")
+}
+
+// initOpenAPI serves the OpenAPI document of the node's APIs
+// Assumes n.APIServer is already set
+func (n *Node) initOpenAPI() error {
+	if !n.Config.OpenAPIEnabled {
+		n.Log.Info("skipping OpenAPI document initialization because it has been disabled")
+		return nil
+	}
+	n.Log.Info("initializing OpenAPI document")
+	handler := &common.HTTPHandler{
+		LockOptions: common.NoLock,
+		Handler:     n.APIServer.OpenAPIHandler(version.CurrentApp.String()),
+		OpenAPI:     []byte(`{"get":{"summary":"OpenAPI document of the node's APIs","responses":{"200":{"description":"The OpenAPI document","content":{"application/json":{}}}}}}`),
+	}
+	return n.APIServer.AddRoute(handler, &sync.RWMutex{}, server.OpenAPIEndpoint, "")
+}
+
 // initProfiler initializes the continuous profiling
 func (n *Node) initProfiler() {
 	if !n.Config.ProfilerConfig.Enabled {
@@ -956,6 +1368,7 @@ func (n *Node) initProfiler() {
 		n.Config.ProfilerConfig.Freq,
 		n.Config.ProfilerConfig.MaxNumFiles,
 	)
+	n.maintenance.Register("profiler", &profilerPauser{profiler: n.profiler})
 	go n.Log.RecoverAndPanic(func() {
 		err := n.profiler.Dispatch()
 		if err != nil {
@@ -975,6 +1388,15 @@ func (n *Node) initInfoAPI() error {
 
 	n.Log.Info("initializing info API")
 
+	var attester attestation.Attester
+	if n.Config.InfoAPIAttestationEnabled {
+		var err error
+		attester, err = attestation.NewTSMAttester(n.Config.InfoAPIAttestationTSMDir)
+		if err != nil {
+			return err
+		}
+	}
+
 	primaryValidators, _ := n.vdrs.GetValidators(constants.PrimaryNetworkID)
 	service, err := info.NewService(
 		info.Parameters{
@@ -992,6 +1414,8 @@ func (n *Node) initInfoAPI() error {
 			AddSubnetValidatorFee:         n.Config.AddSubnetValidatorFee,
 			AddSubnetDelegatorFee:         n.Config.AddSubnetDelegatorFee,
 			VMManager:                     n.Config.VMManager,
+			MaxPageSizes:                  n.Config.MaxPageSizes,
+			Attester:                      attester,
 		},
 		n.Log,
 		n.chainManager,
@@ -1022,22 +1446,37 @@ func (n *Node) initHealthAPI() error {
 	}
 
 	n.Log.Info("initializing Health API")
-	err = healthChecker.RegisterHealthCheck("network", n.Net)
+	err = healthChecker.RegisterHealthCheck("network", n.Net, "diskspace")
 	if err != nil {
 		return fmt.Errorf("couldn't register network health check: %w", err)
 	}
 
-	err = healthChecker.RegisterHealthCheck("router", n.Config.ConsensusRouter)
+	err = healthChecker.RegisterHealthCheck("router", n.Config.ConsensusRouter, "network")
 	if err != nil {
 		return fmt.Errorf("couldn't register router health check: %w", err)
 	}
 
 	// TODO: add database health to liveness check
-	err = healthChecker.RegisterHealthCheck("database", n.DB)
+	err = healthChecker.RegisterHealthCheck("database", n.DB, "diskspace")
 	if err != nil {
 		return fmt.Errorf("couldn't register database health check: %w", err)
 	}
 
+	err = healthChecker.RegisterHealthCheck("metrics", n.metricsGuard)
+	if err != nil {
+		return fmt.Errorf("couldn't register metrics health check: %w", err)
+	}
+
+	err = healthChecker.RegisterHealthCheck("maintenance", n.maintenance)
+	if err != nil {
+		return fmt.Errorf("couldn't register maintenance health check: %w", err)
+	}
+
+	err = healthChecker.RegisterHealthCheck("clockskew", n.clockSkew)
+	if err != nil {
+		return fmt.Errorf("couldn't register clock skew health check: %w", err)
+	}
+
 	diskSpaceCheck := health.CheckerFunc(func(context.Context) (interface{}, error) {
 		// confirm that the node has enough disk space to continue operating
 		// if there is too little disk space remaining, first report unhealthy and then shutdown the node
@@ -1065,6 +1504,13 @@ func (n *Node) initHealthAPI() error {
 		return fmt.Errorf("couldn't register resource health check: %w", err)
 	}
 
+	// The node isn't ready to serve requests while the API endpoints of any
+	// chain reject them, such as while the chain is bootstrapping.
+	err = n.health.RegisterNonMonotonicReadinessCheck("apis", n.APIServer)
+	if err != nil {
+		return fmt.Errorf("couldn't register APIs readiness check: %w", err)
+	}
+
 	handler, err := health.NewGetAndPostHandler(n.Log, healthChecker)
 	if err != nil {
 		return err
@@ -1074,6 +1520,7 @@ func (n *Node) initHealthAPI() error {
 		&common.HTTPHandler{
 			LockOptions: common.NoLock,
 			Handler:     handler,
+			OpenAPI:     health.GetAndPostOpenAPI(),
 		},
 		&sync.RWMutex{},
 		"health",
@@ -1087,6 +1534,7 @@ func (n *Node) initHealthAPI() error {
 		&common.HTTPHandler{
 			LockOptions: common.NoLock,
 			Handler:     health.NewGetHandler(healthChecker.Readiness),
+			OpenAPI:     health.GetOpenAPI(),
 		},
 		&sync.RWMutex{},
 		"health",
@@ -1100,6 +1548,7 @@ func (n *Node) initHealthAPI() error {
 		&common.HTTPHandler{
 			LockOptions: common.NoLock,
 			Handler:     health.NewGetHandler(healthChecker.Health),
+			OpenAPI:     health.GetOpenAPI(),
 		},
 		&sync.RWMutex{},
 		"health",
@@ -1113,6 +1562,7 @@ func (n *Node) initHealthAPI() error {
 		&common.HTTPHandler{
 			LockOptions: common.NoLock,
 			Handler:     health.NewGetHandler(healthChecker.Liveness),
+			OpenAPI:     health.GetOpenAPI(),
 		},
 		&sync.RWMutex{},
 		"health",
@@ -1152,13 +1602,29 @@ func (n *Node) initChainAliases(genesisBytes []byte) error {
 	}
 
 	for chainID, aliases := range n.Config.ChainAliases {
-		for _, alias := range aliases {
+		endpoint := path.Join(constants.ChainAliasPrefix, chainID.String())
+		urlAliases := make([]string, len(aliases))
+		for i, alias := range aliases {
 			if err := n.chainManager.Alias(chainID, alias); err != nil {
 				return err
 			}
+			urlAliases[i] = path.Join(constants.ChainAliasPrefix, alias)
+		}
+		if err := n.APIServer.AddAliases(endpoint, urlAliases...); err != nil {
+			return err
 		}
 	}
 
+	for chainID, aliases := range n.Config.ChainRetiredAliases {
+		endpoint := path.Join(constants.ChainAliasPrefix, chainID.String())
+		urlAliases := make([]string, len(aliases))
+		for i, alias := range aliases {
+			urlAliases[i] = path.Join(constants.ChainAliasPrefix, alias)
+		}
+		if err := n.APIServer.AddRetiredAliases(endpoint, urlAliases...); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -1203,6 +1669,63 @@ func (n *Node) initResourceManager(reg prometheus.Registerer) error {
 	return err
 }
 
+// initMaintenance initializes the scheduler of the maintenance windows. The
+// parts of the node that prepare for maintenance register with the scheduler
+// as they are initialized.
+func (n *Node) initMaintenance() error {
+	var err error
+	n.maintenance, err = maintenance.NewScheduler(n.Log, n.Config.MaintenanceConfig)
+	return err
+}
+
+// databaseFlusher flushes the node's databases when a maintenance window
+// begins, so that little has to be recovered when the node restarts.
+type databaseFlusher struct {
+	dbManager manager.Manager
+}
+
+func (f *databaseFlusher) BeginMaintenance(context.Context, maintenance.Window) error {
+	errs := wrappers.Errs{}
+	for _, db := range f.dbManager.GetDatabases() {
+		if flusher, ok := db.Database.(database.Flusher); ok {
+			errs.Add(flusher.Flush())
+		}
+	}
+	return errs.Err
+}
+
+func (*databaseFlusher) EndMaintenance(context.Context) error {
+	return nil
+}
+
+// profilerPauser pauses continuous profiling during maintenance windows.
+type profilerPauser struct {
+	profiler profiler.ContinuousProfiler
+}
+
+func (p *profilerPauser) BeginMaintenance(context.Context, maintenance.Window) error {
+	p.profiler.Pause()
+	return nil
+}
+
+func (p *profilerPauser) EndMaintenance(context.Context) error {
+	p.profiler.Resume()
+	return nil
+}
+
+// Initialize [n.clockSkew].
+// Assumes [n.MetricsRegisterer] is already initialized.
+func (n *Node) initClockSkew() error {
+	var err error
+	n.clockSkew, err = clockskew.NewEstimator(
+		n.Log,
+		n.Config.ClockSkewConfig,
+		"clock_skew",
+		n.MetricsRegisterer,
+	)
+	return err
+}
+
 // Initialize [n.cpuTargeter].
 // Assumes [n.resourceTracker] is already initialized.
 func (n *Node) initCPUTargeter(
@@ -1251,6 +1774,10 @@ func (n *Node) Initialize(
 		zap.Reflect("config", n.Config),
 	)
 
+	if err := n.initGC(); err != nil { // Tune the garbage collector
+		return fmt.Errorf("couldn't tune garbage collection: %w", err)
+	}
+
 	if err = n.initBeacons(); err != nil { // Configure the beacons
 		return fmt.Errorf("problem initializing node beacons: %w", err)
 	}
@@ -1265,6 +1792,9 @@ func (n *Node) Initialize(
 		n.Config.ConsensusRouter = router.Trace(n.Config.ConsensusRouter, n.tracer)
 	}
 
+	if err := n.initMaintenance(); err != nil {
+		return fmt.Errorf("problem initializing maintenance scheduler: %w", err)
+	}
 	if err := n.initAPIServer(); err != nil { // Start the API Server
 		return fmt.Errorf("couldn't initialize API server: %w", err)
 	}
@@ -1305,6 +1835,9 @@ func (n *Node) Initialize(
 	if err := n.initResourceManager(n.MetricsRegisterer); err != nil {
 		return fmt.Errorf("problem initializing resource manager: %w", err)
 	}
+	if err := n.initClockSkew(); err != nil {
+		return fmt.Errorf("problem initializing clock skew estimator: %w", err)
+	}
 	n.initCPUTargeter(&config.CPUTargeterConfig, primaryNetVdrs)
 	n.initDiskTargeter(&config.DiskTargeterConfig, primaryNetVdrs)
 	if err = n.initNetworking(primaryNetVdrs); err != nil { // Set up networking layer.
@@ -1331,6 +1864,15 @@ func (n *Node) Initialize(
 	if err := n.initAdminAPI(); err != nil { // Start the Admin API
 		return fmt.Errorf("couldn't initialize admin API: %w", err)
 	}
+	if err := n.initDebugAPI(); err != nil { // Start the Debug API
+		return fmt.Errorf("couldn't initialize debug API: %w", err)
+	}
+	if err := n.initEventsAPI(); err != nil { // Start the event hub
+		return fmt.Errorf("couldn't initialize events API: %w", err)
+	}
+	if err := n.initOpenAPI(); err != nil { // Serve the OpenAPI document
+		return fmt.Errorf("couldn't initialize OpenAPI document: %w", err)
+	}
 	if err := n.initInfoAPI(); err != nil { // Start the Info API
 		return fmt.Errorf("couldn't initialize info API: %w", err)
 	}
@@ -1352,6 +1894,7 @@ func (n *Node) Initialize(
 
 	n.health.Start(context.TODO(), n.Config.HealthCheckFreq)
 	n.initProfiler()
+	go n.Log.RecoverAndPanic(n.maintenance.Dispatch)
 
 	// Start the Platform chain
 	n.initChains(n.Config.GenesisBytes)
@@ -1394,6 +1937,12 @@ func (n *Node) shutdown() {
 	if n.resourceManager != nil {
 		n.resourceManager.Shutdown()
 	}
+	if n.clockSkew != nil {
+		n.clockSkew.Shutdown()
+	}
+	if n.maintenance != nil {
+		n.maintenance.Shutdown()
+	}
 	if n.IPCs != nil {
 		if err := n.IPCs.Shutdown(); err != nil {
 			n.Log.Debug("error during IPC shutdown",
@@ -1415,6 +1964,9 @@ func (n *Node) shutdown() {
 			zap.Error(err),
 		)
 	}
+	if n.events != nil {
+		n.events.Close()
+	}
 	if err := n.indexer.Close(); err != nil {
 		n.Log.Debug("error closing tx indexer",
 			zap.Error(err),
@@ -9,6 +9,7 @@ package aliasreader
 import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
 	reflect "reflect"
 	sync "sync"
 )
@@ -161,18 +162,91 @@ func (x *AliasList) GetAliases() []string {
 	return nil
 }
 
+type AliasUpdate struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// id is the ID whose aliases changed
+	Id []byte `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// aliases are the aliases that were given to, or removed from, id
+	Aliases []string `protobuf:"bytes,2,rep,name=aliases,proto3" json:"aliases,omitempty"`
+	// removed is true if aliases were removed
+	Removed bool `protobuf:"varint,3,opt,name=removed,proto3" json:"removed,omitempty"`
+}
+
+func (x *AliasUpdate) Reset() {
+	*x = AliasUpdate{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_aliasreader_aliasreader_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AliasUpdate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AliasUpdate) ProtoMessage() {}
+
+func (x *AliasUpdate) ProtoReflect() protoreflect.Message {
+	mi := &file_aliasreader_aliasreader_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AliasUpdate.ProtoReflect.Descriptor instead.
+func (*AliasUpdate) Descriptor() ([]byte, []int) {
+	return file_aliasreader_aliasreader_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *AliasUpdate) GetId() []byte {
+	if x != nil {
+		return x.Id
+	}
+	return nil
+}
+
+func (x *AliasUpdate) GetAliases() []string {
+	if x != nil {
+		return x.Aliases
+	}
+	return nil
+}
+
+func (x *AliasUpdate) GetRemoved() bool {
+	if x != nil {
+		return x.Removed
+	}
+	return false
+}
+
 var File_aliasreader_aliasreader_proto protoreflect.FileDescriptor
 
 var file_aliasreader_aliasreader_proto_rawDesc = []byte{
 	0x0a, 0x1d, 0x61, 0x6c, 0x69, 0x61, 0x73, 0x72, 0x65, 0x61, 0x64, 0x65, 0x72, 0x2f, 0x61, 0x6c,
 	0x69, 0x61, 0x73, 0x72, 0x65, 0x61, 0x64, 0x65, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12,
-	0x0b, 0x61, 0x6c, 0x69, 0x61, 0x73, 0x72, 0x65, 0x61, 0x64, 0x65, 0x72, 0x22, 0x14, 0x0a, 0x02,
-	0x49, 0x44, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x02,
-	0x69, 0x64, 0x22, 0x1d, 0x0a, 0x05, 0x41, 0x6c, 0x69, 0x61, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x61,
-	0x6c, 0x69, 0x61, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x61, 0x6c, 0x69, 0x61,
-	0x73, 0x22, 0x25, 0x0a, 0x09, 0x41, 0x6c, 0x69, 0x61, 0x73, 0x4c, 0x69, 0x73, 0x74, 0x12, 0x18,
-	0x0a, 0x07, 0x61, 0x6c, 0x69, 0x61, 0x73, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52,
-	0x07, 0x61, 0x6c, 0x69, 0x61, 0x73, 0x65, 0x73, 0x32, 0xa5, 0x01, 0x0a, 0x0b, 0x41, 0x6c, 0x69,
+	0x0b, 0x61, 0x6c, 0x69, 0x61, 0x73, 0x72, 0x65, 0x61, 0x64, 0x65, 0x72, 0x1a, 0x1b, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x65, 0x6d,
+	0x70, 0x74, 0x79, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x14, 0x0a, 0x02, 0x49, 0x44, 0x12,
+	0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x02, 0x69, 0x64, 0x22,
+	0x1d, 0x0a, 0x05, 0x41, 0x6c, 0x69, 0x61, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x61, 0x6c, 0x69, 0x61,
+	0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x61, 0x6c, 0x69, 0x61, 0x73, 0x22, 0x25,
+	0x0a, 0x09, 0x41, 0x6c, 0x69, 0x61, 0x73, 0x4c, 0x69, 0x73, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x61,
+	0x6c, 0x69, 0x61, 0x73, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x61, 0x6c,
+	0x69, 0x61, 0x73, 0x65, 0x73, 0x22, 0x51, 0x0a, 0x0b, 0x41, 0x6c, 0x69, 0x61, 0x73, 0x55, 0x70,
+	0x64, 0x61, 0x74, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c,
+	0x52, 0x02, 0x69, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x61, 0x6c, 0x69, 0x61, 0x73, 0x65, 0x73, 0x18,
+	0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x61, 0x6c, 0x69, 0x61, 0x73, 0x65, 0x73, 0x12, 0x18,
+	0x0a, 0x07, 0x72, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x07, 0x72, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x64, 0x32, 0xe2, 0x01, 0x0a, 0x0b, 0x41, 0x6c, 0x69,
 	0x61, 0x73, 0x52, 0x65, 0x61, 0x64, 0x65, 0x72, 0x12, 0x2d, 0x0a, 0x06, 0x4c, 0x6f, 0x6f, 0x6b,
 	0x75, 0x70, 0x12, 0x12, 0x2e, 0x61, 0x6c, 0x69, 0x61, 0x73, 0x72, 0x65, 0x61, 0x64, 0x65, 0x72,
 	0x2e, 0x41, 0x6c, 0x69, 0x61, 0x73, 0x1a, 0x0f, 0x2e, 0x61, 0x6c, 0x69, 0x61, 0x73, 0x72, 0x65,
@@ -183,10 +257,14 @@ var file_aliasreader_aliasreader_proto_rawDesc = []byte{
 	0x41, 0x6c, 0x69, 0x61, 0x73, 0x65, 0x73, 0x12, 0x0f, 0x2e, 0x61, 0x6c, 0x69, 0x61, 0x73, 0x72,
 	0x65, 0x61, 0x64, 0x65, 0x72, 0x2e, 0x49, 0x44, 0x1a, 0x16, 0x2e, 0x61, 0x6c, 0x69, 0x61, 0x73,
 	0x72, 0x65, 0x61, 0x64, 0x65, 0x72, 0x2e, 0x41, 0x6c, 0x69, 0x61, 0x73, 0x4c, 0x69, 0x73, 0x74,
-	0x42, 0x36, 0x5a, 0x34, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x61,
-	0x76, 0x61, 0x2d, 0x6c, 0x61, 0x62, 0x73, 0x2f, 0x61, 0x76, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x68,
-	0x65, 0x67, 0x6f, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x70, 0x62, 0x2f, 0x61, 0x6c, 0x69,
-	0x61, 0x73, 0x72, 0x65, 0x61, 0x64, 0x65, 0x72, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x12, 0x3b, 0x0a, 0x05, 0x57, 0x61, 0x74, 0x63, 0x68, 0x12, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
+	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74,
+	0x79, 0x1a, 0x18, 0x2e, 0x61, 0x6c, 0x69, 0x61, 0x73, 0x72, 0x65, 0x61, 0x64, 0x65, 0x72, 0x2e,
+	0x41, 0x6c, 0x69, 0x61, 0x73, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x30, 0x01, 0x42, 0x36, 0x5a,
+	0x34, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x61, 0x76, 0x61, 0x2d,
+	0x6c, 0x61, 0x62, 0x73, 0x2f, 0x61, 0x76, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x68, 0x65, 0x67, 0x6f,
+	0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x70, 0x62, 0x2f, 0x61, 0x6c, 0x69, 0x61, 0x73, 0x72,
+	0x65, 0x61, 0x64, 0x65, 0x72, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -201,21 +279,25 @@ func file_aliasreader_aliasreader_proto_rawDescGZIP() []byte {
 	return file_aliasreader_aliasreader_proto_rawDescData
 }
 
-var file_aliasreader_aliasreader_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_aliasreader_aliasreader_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
 var file_aliasreader_aliasreader_proto_goTypes = []interface{}{
-	(*ID)(nil),        // 0: aliasreader.ID
-	(*Alias)(nil),     // 1: aliasreader.Alias
-	(*AliasList)(nil), // 2: aliasreader.AliasList
+	(*ID)(nil),            // 0: aliasreader.ID
+	(*Alias)(nil),         // 1: aliasreader.Alias
+	(*AliasList)(nil),     // 2: aliasreader.AliasList
+	(*AliasUpdate)(nil),   // 3: aliasreader.AliasUpdate
+	(*emptypb.Empty)(nil), // 4: google.protobuf.Empty
 }
 var file_aliasreader_aliasreader_proto_depIdxs = []int32{
 	1, // 0: aliasreader.AliasReader.Lookup:input_type -> aliasreader.Alias
 	0, // 1: aliasreader.AliasReader.PrimaryAlias:input_type -> aliasreader.ID
 	0, // 2: aliasreader.AliasReader.Aliases:input_type -> aliasreader.ID
-	0, // 3: aliasreader.AliasReader.Lookup:output_type -> aliasreader.ID
-	1, // 4: aliasreader.AliasReader.PrimaryAlias:output_type -> aliasreader.Alias
-	2, // 5: aliasreader.AliasReader.Aliases:output_type -> aliasreader.AliasList
-	3, // [3:6] is the sub-list for method output_type
-	0, // [0:3] is the sub-list for method input_type
+	4, // 3: aliasreader.AliasReader.Watch:input_type -> google.protobuf.Empty
+	0, // 4: aliasreader.AliasReader.Lookup:output_type -> aliasreader.ID
+	1, // 5: aliasreader.AliasReader.PrimaryAlias:output_type -> aliasreader.Alias
+	2, // 6: aliasreader.AliasReader.Aliases:output_type -> aliasreader.AliasList
+	3, // 7: aliasreader.AliasReader.Watch:output_type -> aliasreader.AliasUpdate
+	4, // [4:8] is the sub-list for method output_type
+	0, // [0:4] is the sub-list for method input_type
 	0, // [0:0] is the sub-list for extension type_name
 	0, // [0:0] is the sub-list for extension extendee
 	0, // [0:0] is the sub-list for field type_name
@@ -263,6 +345,18 @@ func file_aliasreader_aliasreader_proto_init() {
 				return nil
 			}
 		}
+		file_aliasreader_aliasreader_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AliasUpdate); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
@@ -270,7 +364,7 @@ func file_aliasreader_aliasreader_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_aliasreader_aliasreader_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   3,
+			NumMessages:   4,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
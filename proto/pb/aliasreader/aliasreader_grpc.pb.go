@@ -11,6 +11,7 @@ import (
 	grpc "google.golang.org/grpc"
 	codes "google.golang.org/grpc/codes"
 	status "google.golang.org/grpc/status"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
 )
 
 // This is a compile-time assertion to ensure that this generated file
@@ -25,6 +26,9 @@ type AliasReaderClient interface {
 	Lookup(ctx context.Context, in *Alias, opts ...grpc.CallOption) (*ID, error)
 	PrimaryAlias(ctx context.Context, in *ID, opts ...grpc.CallOption) (*Alias, error)
 	Aliases(ctx context.Context, in *ID, opts ...grpc.CallOption) (*AliasList, error)
+	// Watch streams every change of the aliases made after the call, until the
+	// client cancels it.
+	Watch(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (AliasReader_WatchClient, error)
 }
 
 type aliasReaderClient struct {
@@ -62,6 +66,38 @@ func (c *aliasReaderClient) Aliases(ctx context.Context, in *ID, opts ...grpc.Ca
 	return out, nil
 }
 
+func (c *aliasReaderClient) Watch(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (AliasReader_WatchClient, error) {
+	stream, err := c.cc.NewStream(ctx, &AliasReader_ServiceDesc.Streams[0], "/aliasreader.AliasReader/Watch", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &aliasReaderWatchClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type AliasReader_WatchClient interface {
+	Recv() (*AliasUpdate, error)
+	grpc.ClientStream
+}
+
+type aliasReaderWatchClient struct {
+	grpc.ClientStream
+}
+
+func (x *aliasReaderWatchClient) Recv() (*AliasUpdate, error) {
+	m := new(AliasUpdate)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // AliasReaderServer is the server API for AliasReader service.
 // All implementations must embed UnimplementedAliasReaderServer
 // for forward compatibility
@@ -69,6 +105,9 @@ type AliasReaderServer interface {
 	Lookup(context.Context, *Alias) (*ID, error)
 	PrimaryAlias(context.Context, *ID) (*Alias, error)
 	Aliases(context.Context, *ID) (*AliasList, error)
+	// Watch streams every change of the aliases made after the call, until the
+	// client cancels it.
+	Watch(*emptypb.Empty, AliasReader_WatchServer) error
 	mustEmbedUnimplementedAliasReaderServer()
 }
 
@@ -85,6 +124,9 @@ func (UnimplementedAliasReaderServer) PrimaryAlias(context.Context, *ID) (*Alias
 func (UnimplementedAliasReaderServer) Aliases(context.Context, *ID) (*AliasList, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Aliases not implemented")
 }
+func (UnimplementedAliasReaderServer) Watch(*emptypb.Empty, AliasReader_WatchServer) error {
+	return status.Errorf(codes.Unimplemented, "method Watch not implemented")
+}
 func (UnimplementedAliasReaderServer) mustEmbedUnimplementedAliasReaderServer() {}
 
 // UnsafeAliasReaderServer may be embedded to opt out of forward compatibility for this service.
@@ -152,6 +194,27 @@ func _AliasReader_Aliases_Handler(srv interface{}, ctx context.Context, dec func
 	return interceptor(ctx, in, info, handler)
 }
 
+func _AliasReader_Watch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(emptypb.Empty)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(AliasReaderServer).Watch(m, &aliasReaderWatchServer{stream})
+}
+
+type AliasReader_WatchServer interface {
+	Send(*AliasUpdate) error
+	grpc.ServerStream
+}
+
+type aliasReaderWatchServer struct {
+	grpc.ServerStream
+}
+
+func (x *aliasReaderWatchServer) Send(m *AliasUpdate) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 // AliasReader_ServiceDesc is the grpc.ServiceDesc for AliasReader service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -172,6 +235,12 @@ var AliasReader_ServiceDesc = grpc.ServiceDesc{
 			Handler:    _AliasReader_Aliases_Handler,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Watch",
+			Handler:       _AliasReader_Watch_Handler,
+			ServerStreams: true,
+		},
+	},
 	Metadata: "aliasreader/aliasreader.proto",
 }
@@ -153,6 +153,81 @@ func (x *SendAppResponseMsg) GetResponse() []byte {
 	return nil
 }
 
+type SendAppErrorMsg struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The node to send the error to
+	NodeId []byte `protobuf:"bytes,1,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
+	// ID of the request that failed
+	RequestId uint32 `protobuf:"varint,2,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	// Application-defined error code
+	ErrorCode int32 `protobuf:"zigzag32,3,opt,name=error_code,json=errorCode,proto3" json:"error_code,omitempty"`
+	// Application-defined error message
+	ErrorMessage string `protobuf:"bytes,4,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"`
+}
+
+func (x *SendAppErrorMsg) Reset() {
+	*x = SendAppErrorMsg{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_appsender_appsender_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SendAppErrorMsg) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SendAppErrorMsg) ProtoMessage() {}
+
+func (x *SendAppErrorMsg) ProtoReflect() protoreflect.Message {
+	mi := &file_appsender_appsender_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SendAppErrorMsg.ProtoReflect.Descriptor instead.
+func (*SendAppErrorMsg) Descriptor() ([]byte, []int) {
+	return file_appsender_appsender_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *SendAppErrorMsg) GetNodeId() []byte {
+	if x != nil {
+		return x.NodeId
+	}
+	return nil
+}
+
+func (x *SendAppErrorMsg) GetRequestId() uint32 {
+	if x != nil {
+		return x.RequestId
+	}
+	return 0
+}
+
+func (x *SendAppErrorMsg) GetErrorCode() int32 {
+	if x != nil {
+		return x.ErrorCode
+	}
+	return 0
+}
+
+func (x *SendAppErrorMsg) GetErrorMessage() string {
+	if x != nil {
+		return x.ErrorMessage
+	}
+	return ""
+}
+
 type SendAppGossipMsg struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -165,7 +240,7 @@ type SendAppGossipMsg struct {
 func (x *SendAppGossipMsg) Reset() {
 	*x = SendAppGossipMsg{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_appsender_appsender_proto_msgTypes[2]
+		mi := &file_appsender_appsender_proto_msgTypes[3]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -178,7 +253,7 @@ func (x *SendAppGossipMsg) String() string {
 func (*SendAppGossipMsg) ProtoMessage() {}
 
 func (x *SendAppGossipMsg) ProtoReflect() protoreflect.Message {
-	mi := &file_appsender_appsender_proto_msgTypes[2]
+	mi := &file_appsender_appsender_proto_msgTypes[3]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -191,7 +266,7 @@ func (x *SendAppGossipMsg) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SendAppGossipMsg.ProtoReflect.Descriptor instead.
 func (*SendAppGossipMsg) Descriptor() ([]byte, []int) {
-	return file_appsender_appsender_proto_rawDescGZIP(), []int{2}
+	return file_appsender_appsender_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *SendAppGossipMsg) GetMsg() []byte {
@@ -215,7 +290,7 @@ type SendAppGossipSpecificMsg struct {
 func (x *SendAppGossipSpecificMsg) Reset() {
 	*x = SendAppGossipSpecificMsg{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_appsender_appsender_proto_msgTypes[3]
+		mi := &file_appsender_appsender_proto_msgTypes[4]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -228,7 +303,7 @@ func (x *SendAppGossipSpecificMsg) String() string {
 func (*SendAppGossipSpecificMsg) ProtoMessage() {}
 
 func (x *SendAppGossipSpecificMsg) ProtoReflect() protoreflect.Message {
-	mi := &file_appsender_appsender_proto_msgTypes[3]
+	mi := &file_appsender_appsender_proto_msgTypes[4]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -241,7 +316,7 @@ func (x *SendAppGossipSpecificMsg) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SendAppGossipSpecificMsg.ProtoReflect.Descriptor instead.
 func (*SendAppGossipSpecificMsg) Descriptor() ([]byte, []int) {
-	return file_appsender_appsender_proto_rawDescGZIP(), []int{3}
+	return file_appsender_appsender_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *SendAppGossipSpecificMsg) GetNodeIds() [][]byte {
@@ -274,7 +349,7 @@ type SendCrossChainAppRequestMsg struct {
 func (x *SendCrossChainAppRequestMsg) Reset() {
 	*x = SendCrossChainAppRequestMsg{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_appsender_appsender_proto_msgTypes[4]
+		mi := &file_appsender_appsender_proto_msgTypes[5]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -287,7 +362,7 @@ func (x *SendCrossChainAppRequestMsg) String() string {
 func (*SendCrossChainAppRequestMsg) ProtoMessage() {}
 
 func (x *SendCrossChainAppRequestMsg) ProtoReflect() protoreflect.Message {
-	mi := &file_appsender_appsender_proto_msgTypes[4]
+	mi := &file_appsender_appsender_proto_msgTypes[5]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -300,7 +375,7 @@ func (x *SendCrossChainAppRequestMsg) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SendCrossChainAppRequestMsg.ProtoReflect.Descriptor instead.
 func (*SendCrossChainAppRequestMsg) Descriptor() ([]byte, []int) {
-	return file_appsender_appsender_proto_rawDescGZIP(), []int{4}
+	return file_appsender_appsender_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *SendCrossChainAppRequestMsg) GetChainId() []byte {
@@ -340,7 +415,7 @@ type SendCrossChainAppResponseMsg struct {
 func (x *SendCrossChainAppResponseMsg) Reset() {
 	*x = SendCrossChainAppResponseMsg{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_appsender_appsender_proto_msgTypes[5]
+		mi := &file_appsender_appsender_proto_msgTypes[6]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -353,7 +428,7 @@ func (x *SendCrossChainAppResponseMsg) String() string {
 func (*SendCrossChainAppResponseMsg) ProtoMessage() {}
 
 func (x *SendCrossChainAppResponseMsg) ProtoReflect() protoreflect.Message {
-	mi := &file_appsender_appsender_proto_msgTypes[5]
+	mi := &file_appsender_appsender_proto_msgTypes[6]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -366,7 +441,7 @@ func (x *SendCrossChainAppResponseMsg) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SendCrossChainAppResponseMsg.ProtoReflect.Descriptor instead.
 func (*SendCrossChainAppResponseMsg) Descriptor() ([]byte, []int) {
-	return file_appsender_appsender_proto_rawDescGZIP(), []int{5}
+	return file_appsender_appsender_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *SendCrossChainAppResponseMsg) GetChainId() []byte {
@@ -410,7 +485,16 @@ var file_appsender_appsender_proto_rawDesc = []byte{
 	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52,
 	0x09, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x49, 0x64, 0x12, 0x1a, 0x0a, 0x08, 0x72, 0x65,
 	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x08, 0x72, 0x65,
-	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x24, 0x0a, 0x10, 0x53, 0x65, 0x6e, 0x64, 0x41, 0x70,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x8d, 0x01, 0x0a, 0x0f, 0x53, 0x65, 0x6e, 0x64, 0x41,
+	0x70, 0x70, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x4d, 0x73, 0x67, 0x12, 0x17, 0x0a, 0x07, 0x6e, 0x6f,
+	0x64, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x6e, 0x6f, 0x64,
+	0x65, 0x49, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x5f, 0x69,
+	0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x09, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x49, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x5f, 0x63, 0x6f, 0x64, 0x65,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x11, 0x52, 0x09, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x43, 0x6f, 0x64,
+	0x65, 0x12, 0x23, 0x0a, 0x0d, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x5f, 0x6d, 0x65, 0x73, 0x73, 0x61,
+	0x67, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x4d,
+	0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0x24, 0x0a, 0x10, 0x53, 0x65, 0x6e, 0x64, 0x41, 0x70,
 	0x70, 0x47, 0x6f, 0x73, 0x73, 0x69, 0x70, 0x4d, 0x73, 0x67, 0x12, 0x10, 0x0a, 0x03, 0x6d, 0x73,
 	0x67, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x03, 0x6d, 0x73, 0x67, 0x22, 0x47, 0x0a, 0x18,
 	0x53, 0x65, 0x6e, 0x64, 0x41, 0x70, 0x70, 0x47, 0x6f, 0x73, 0x73, 0x69, 0x70, 0x53, 0x70, 0x65,
@@ -431,8 +515,8 @@ var file_appsender_appsender_proto_rawDesc = []byte{
 	0x6e, 0x49, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x5f, 0x69,
 	0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x09, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
 	0x49, 0x64, 0x12, 0x1a, 0x0a, 0x08, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x18, 0x03,
-	0x20, 0x01, 0x28, 0x0c, 0x52, 0x08, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x32, 0xf3,
-	0x03, 0x0a, 0x09, 0x41, 0x70, 0x70, 0x53, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x12, 0x46, 0x0a, 0x0e,
+	0x20, 0x01, 0x28, 0x0c, 0x52, 0x08, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x32, 0xb7,
+	0x04, 0x0a, 0x09, 0x41, 0x70, 0x70, 0x53, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x12, 0x46, 0x0a, 0x0e,
 	0x53, 0x65, 0x6e, 0x64, 0x41, 0x70, 0x70, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1c,
 	0x2e, 0x61, 0x70, 0x70, 0x73, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x2e, 0x53, 0x65, 0x6e, 0x64, 0x41,
 	0x70, 0x70, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x4d, 0x73, 0x67, 0x1a, 0x16, 0x2e, 0x67,
@@ -441,33 +525,37 @@ var file_appsender_appsender_proto_rawDesc = []byte{
 	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1d, 0x2e, 0x61, 0x70, 0x70, 0x73, 0x65, 0x6e,
 	0x64, 0x65, 0x72, 0x2e, 0x53, 0x65, 0x6e, 0x64, 0x41, 0x70, 0x70, 0x52, 0x65, 0x73, 0x70, 0x6f,
 	0x6e, 0x73, 0x65, 0x4d, 0x73, 0x67, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e,
-	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x44,
-	0x0a, 0x0d, 0x53, 0x65, 0x6e, 0x64, 0x41, 0x70, 0x70, 0x47, 0x6f, 0x73, 0x73, 0x69, 0x70, 0x12,
-	0x1b, 0x2e, 0x61, 0x70, 0x70, 0x73, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x2e, 0x53, 0x65, 0x6e, 0x64,
-	0x41, 0x70, 0x70, 0x47, 0x6f, 0x73, 0x73, 0x69, 0x70, 0x4d, 0x73, 0x67, 0x1a, 0x16, 0x2e, 0x67,
-	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45,
-	0x6d, 0x70, 0x74, 0x79, 0x12, 0x54, 0x0a, 0x15, 0x53, 0x65, 0x6e, 0x64, 0x41, 0x70, 0x70, 0x47,
-	0x6f, 0x73, 0x73, 0x69, 0x70, 0x53, 0x70, 0x65, 0x63, 0x69, 0x66, 0x69, 0x63, 0x12, 0x23, 0x2e,
-	0x61, 0x70, 0x70, 0x73, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x2e, 0x53, 0x65, 0x6e, 0x64, 0x41, 0x70,
-	0x70, 0x47, 0x6f, 0x73, 0x73, 0x69, 0x70, 0x53, 0x70, 0x65, 0x63, 0x69, 0x66, 0x69, 0x63, 0x4d,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x42,
+	0x0a, 0x0c, 0x53, 0x65, 0x6e, 0x64, 0x41, 0x70, 0x70, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x1a,
+	0x2e, 0x61, 0x70, 0x70, 0x73, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x2e, 0x53, 0x65, 0x6e, 0x64, 0x41,
+	0x70, 0x70, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x4d, 0x73, 0x67, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f,
+	0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70,
+	0x74, 0x79, 0x12, 0x44, 0x0a, 0x0d, 0x53, 0x65, 0x6e, 0x64, 0x41, 0x70, 0x70, 0x47, 0x6f, 0x73,
+	0x73, 0x69, 0x70, 0x12, 0x1b, 0x2e, 0x61, 0x70, 0x70, 0x73, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x2e,
+	0x53, 0x65, 0x6e, 0x64, 0x41, 0x70, 0x70, 0x47, 0x6f, 0x73, 0x73, 0x69, 0x70, 0x4d, 0x73, 0x67,
+	0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
+	0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x54, 0x0a, 0x15, 0x53, 0x65, 0x6e, 0x64,
+	0x41, 0x70, 0x70, 0x47, 0x6f, 0x73, 0x73, 0x69, 0x70, 0x53, 0x70, 0x65, 0x63, 0x69, 0x66, 0x69,
+	0x63, 0x12, 0x23, 0x2e, 0x61, 0x70, 0x70, 0x73, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x2e, 0x53, 0x65,
+	0x6e, 0x64, 0x41, 0x70, 0x70, 0x47, 0x6f, 0x73, 0x73, 0x69, 0x70, 0x53, 0x70, 0x65, 0x63, 0x69,
+	0x66, 0x69, 0x63, 0x4d, 0x73, 0x67, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x5a,
+	0x0a, 0x18, 0x53, 0x65, 0x6e, 0x64, 0x43, 0x72, 0x6f, 0x73, 0x73, 0x43, 0x68, 0x61, 0x69, 0x6e,
+	0x41, 0x70, 0x70, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x26, 0x2e, 0x61, 0x70, 0x70,
+	0x73, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x2e, 0x53, 0x65, 0x6e, 0x64, 0x43, 0x72, 0x6f, 0x73, 0x73,
+	0x43, 0x68, 0x61, 0x69, 0x6e, 0x41, 0x70, 0x70, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x4d,
 	0x73, 0x67, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
-	0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x5a, 0x0a, 0x18, 0x53, 0x65,
+	0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x5c, 0x0a, 0x19, 0x53, 0x65,
 	0x6e, 0x64, 0x43, 0x72, 0x6f, 0x73, 0x73, 0x43, 0x68, 0x61, 0x69, 0x6e, 0x41, 0x70, 0x70, 0x52,
-	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x26, 0x2e, 0x61, 0x70, 0x70, 0x73, 0x65, 0x6e, 0x64,
-	0x65, 0x72, 0x2e, 0x53, 0x65, 0x6e, 0x64, 0x43, 0x72, 0x6f, 0x73, 0x73, 0x43, 0x68, 0x61, 0x69,
-	0x6e, 0x41, 0x70, 0x70, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x4d, 0x73, 0x67, 0x1a, 0x16,
-	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
-	0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x5c, 0x0a, 0x19, 0x53, 0x65, 0x6e, 0x64, 0x43, 0x72,
-	0x6f, 0x73, 0x73, 0x43, 0x68, 0x61, 0x69, 0x6e, 0x41, 0x70, 0x70, 0x52, 0x65, 0x73, 0x70, 0x6f,
-	0x6e, 0x73, 0x65, 0x12, 0x27, 0x2e, 0x61, 0x70, 0x70, 0x73, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x2e,
-	0x53, 0x65, 0x6e, 0x64, 0x43, 0x72, 0x6f, 0x73, 0x73, 0x43, 0x68, 0x61, 0x69, 0x6e, 0x41, 0x70,
-	0x70, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x4d, 0x73, 0x67, 0x1a, 0x16, 0x2e, 0x67,
-	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45,
-	0x6d, 0x70, 0x74, 0x79, 0x42, 0x34, 0x5a, 0x32, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63,
-	0x6f, 0x6d, 0x2f, 0x61, 0x76, 0x61, 0x2d, 0x6c, 0x61, 0x62, 0x73, 0x2f, 0x61, 0x76, 0x61, 0x6c,
-	0x61, 0x6e, 0x63, 0x68, 0x65, 0x67, 0x6f, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x70, 0x62,
-	0x2f, 0x61, 0x70, 0x70, 0x73, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74,
-	0x6f, 0x33,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x27, 0x2e, 0x61, 0x70, 0x70, 0x73, 0x65, 0x6e,
+	0x64, 0x65, 0x72, 0x2e, 0x53, 0x65, 0x6e, 0x64, 0x43, 0x72, 0x6f, 0x73, 0x73, 0x43, 0x68, 0x61,
+	0x69, 0x6e, 0x41, 0x70, 0x70, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x4d, 0x73, 0x67,
+	0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
+	0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x42, 0x34, 0x5a, 0x32, 0x67, 0x69, 0x74, 0x68,
+	0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x61, 0x76, 0x61, 0x2d, 0x6c, 0x61, 0x62, 0x73, 0x2f,
+	0x61, 0x76, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x68, 0x65, 0x67, 0x6f, 0x2f, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x2f, 0x70, 0x62, 0x2f, 0x61, 0x70, 0x70, 0x73, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x62, 0x06,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -482,31 +570,34 @@ func file_appsender_appsender_proto_rawDescGZIP() []byte {
 	return file_appsender_appsender_proto_rawDescData
 }
 
-var file_appsender_appsender_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_appsender_appsender_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
 var file_appsender_appsender_proto_goTypes = []interface{}{
 	(*SendAppRequestMsg)(nil),            // 0: appsender.SendAppRequestMsg
 	(*SendAppResponseMsg)(nil),           // 1: appsender.SendAppResponseMsg
-	(*SendAppGossipMsg)(nil),             // 2: appsender.SendAppGossipMsg
-	(*SendAppGossipSpecificMsg)(nil),     // 3: appsender.SendAppGossipSpecificMsg
-	(*SendCrossChainAppRequestMsg)(nil),  // 4: appsender.SendCrossChainAppRequestMsg
-	(*SendCrossChainAppResponseMsg)(nil), // 5: appsender.SendCrossChainAppResponseMsg
-	(*emptypb.Empty)(nil),                // 6: google.protobuf.Empty
+	(*SendAppErrorMsg)(nil),              // 2: appsender.SendAppErrorMsg
+	(*SendAppGossipMsg)(nil),             // 3: appsender.SendAppGossipMsg
+	(*SendAppGossipSpecificMsg)(nil),     // 4: appsender.SendAppGossipSpecificMsg
+	(*SendCrossChainAppRequestMsg)(nil),  // 5: appsender.SendCrossChainAppRequestMsg
+	(*SendCrossChainAppResponseMsg)(nil), // 6: appsender.SendCrossChainAppResponseMsg
+	(*emptypb.Empty)(nil),                // 7: google.protobuf.Empty
 }
 var file_appsender_appsender_proto_depIdxs = []int32{
 	0, // 0: appsender.AppSender.SendAppRequest:input_type -> appsender.SendAppRequestMsg
 	1, // 1: appsender.AppSender.SendAppResponse:input_type -> appsender.SendAppResponseMsg
-	2, // 2: appsender.AppSender.SendAppGossip:input_type -> appsender.SendAppGossipMsg
-	3, // 3: appsender.AppSender.SendAppGossipSpecific:input_type -> appsender.SendAppGossipSpecificMsg
-	4, // 4: appsender.AppSender.SendCrossChainAppRequest:input_type -> appsender.SendCrossChainAppRequestMsg
-	5, // 5: appsender.AppSender.SendCrossChainAppResponse:input_type -> appsender.SendCrossChainAppResponseMsg
-	6, // 6: appsender.AppSender.SendAppRequest:output_type -> google.protobuf.Empty
-	6, // 7: appsender.AppSender.SendAppResponse:output_type -> google.protobuf.Empty
-	6, // 8: appsender.AppSender.SendAppGossip:output_type -> google.protobuf.Empty
-	6, // 9: appsender.AppSender.SendAppGossipSpecific:output_type -> google.protobuf.Empty
-	6, // 10: appsender.AppSender.SendCrossChainAppRequest:output_type -> google.protobuf.Empty
-	6, // 11: appsender.AppSender.SendCrossChainAppResponse:output_type -> google.protobuf.Empty
-	6, // [6:12] is the sub-list for method output_type
-	0, // [0:6] is the sub-list for method input_type
+	2, // 2: appsender.AppSender.SendAppError:input_type -> appsender.SendAppErrorMsg
+	3, // 3: appsender.AppSender.SendAppGossip:input_type -> appsender.SendAppGossipMsg
+	4, // 4: appsender.AppSender.SendAppGossipSpecific:input_type -> appsender.SendAppGossipSpecificMsg
+	5, // 5: appsender.AppSender.SendCrossChainAppRequest:input_type -> appsender.SendCrossChainAppRequestMsg
+	6, // 6: appsender.AppSender.SendCrossChainAppResponse:input_type -> appsender.SendCrossChainAppResponseMsg
+	7, // 7: appsender.AppSender.SendAppRequest:output_type -> google.protobuf.Empty
+	7, // 8: appsender.AppSender.SendAppResponse:output_type -> google.protobuf.Empty
+	7, // 9: appsender.AppSender.SendAppError:output_type -> google.protobuf.Empty
+	7, // 10: appsender.AppSender.SendAppGossip:output_type -> google.protobuf.Empty
+	7, // 11: appsender.AppSender.SendAppGossipSpecific:output_type -> google.protobuf.Empty
+	7, // 12: appsender.AppSender.SendCrossChainAppRequest:output_type -> google.protobuf.Empty
+	7, // 13: appsender.AppSender.SendCrossChainAppResponse:output_type -> google.protobuf.Empty
+	7, // [7:14] is the sub-list for method output_type
+	0, // [0:7] is the sub-list for method input_type
 	0, // [0:0] is the sub-list for extension type_name
 	0, // [0:0] is the sub-list for extension extendee
 	0, // [0:0] is the sub-list for field type_name
@@ -543,7 +634,7 @@ func file_appsender_appsender_proto_init() {
 			}
 		}
 		file_appsender_appsender_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*SendAppGossipMsg); i {
+			switch v := v.(*SendAppErrorMsg); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -555,7 +646,7 @@ func file_appsender_appsender_proto_init() {
 			}
 		}
 		file_appsender_appsender_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*SendAppGossipSpecificMsg); i {
+			switch v := v.(*SendAppGossipMsg); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -567,7 +658,7 @@ func file_appsender_appsender_proto_init() {
 			}
 		}
 		file_appsender_appsender_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*SendCrossChainAppRequestMsg); i {
+			switch v := v.(*SendAppGossipSpecificMsg); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -579,6 +670,18 @@ func file_appsender_appsender_proto_init() {
 			}
 		}
 		file_appsender_appsender_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SendCrossChainAppRequestMsg); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_appsender_appsender_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*SendCrossChainAppResponseMsg); i {
 			case 0:
 				return &v.state
@@ -597,7 +700,7 @@ func file_appsender_appsender_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_appsender_appsender_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   6,
+			NumMessages:   7,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -25,6 +25,7 @@ const _ = grpc.SupportPackageIsVersion7
 type AppSenderClient interface {
 	SendAppRequest(ctx context.Context, in *SendAppRequestMsg, opts ...grpc.CallOption) (*emptypb.Empty, error)
 	SendAppResponse(ctx context.Context, in *SendAppResponseMsg, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	SendAppError(ctx context.Context, in *SendAppErrorMsg, opts ...grpc.CallOption) (*emptypb.Empty, error)
 	SendAppGossip(ctx context.Context, in *SendAppGossipMsg, opts ...grpc.CallOption) (*emptypb.Empty, error)
 	SendAppGossipSpecific(ctx context.Context, in *SendAppGossipSpecificMsg, opts ...grpc.CallOption) (*emptypb.Empty, error)
 	SendCrossChainAppRequest(ctx context.Context, in *SendCrossChainAppRequestMsg, opts ...grpc.CallOption) (*emptypb.Empty, error)
@@ -57,6 +58,15 @@ func (c *appSenderClient) SendAppResponse(ctx context.Context, in *SendAppRespon
 	return out, nil
 }
 
+func (c *appSenderClient) SendAppError(ctx context.Context, in *SendAppErrorMsg, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/appsender.AppSender/SendAppError", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *appSenderClient) SendAppGossip(ctx context.Context, in *SendAppGossipMsg, opts ...grpc.CallOption) (*emptypb.Empty, error) {
 	out := new(emptypb.Empty)
 	err := c.cc.Invoke(ctx, "/appsender.AppSender/SendAppGossip", in, out, opts...)
@@ -99,6 +109,7 @@ func (c *appSenderClient) SendCrossChainAppResponse(ctx context.Context, in *Sen
 type AppSenderServer interface {
 	SendAppRequest(context.Context, *SendAppRequestMsg) (*emptypb.Empty, error)
 	SendAppResponse(context.Context, *SendAppResponseMsg) (*emptypb.Empty, error)
+	SendAppError(context.Context, *SendAppErrorMsg) (*emptypb.Empty, error)
 	SendAppGossip(context.Context, *SendAppGossipMsg) (*emptypb.Empty, error)
 	SendAppGossipSpecific(context.Context, *SendAppGossipSpecificMsg) (*emptypb.Empty, error)
 	SendCrossChainAppRequest(context.Context, *SendCrossChainAppRequestMsg) (*emptypb.Empty, error)
@@ -116,6 +127,9 @@ func (UnimplementedAppSenderServer) SendAppRequest(context.Context, *SendAppRequ
 func (UnimplementedAppSenderServer) SendAppResponse(context.Context, *SendAppResponseMsg) (*emptypb.Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method SendAppResponse not implemented")
 }
+func (UnimplementedAppSenderServer) SendAppError(context.Context, *SendAppErrorMsg) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SendAppError not implemented")
+}
 func (UnimplementedAppSenderServer) SendAppGossip(context.Context, *SendAppGossipMsg) (*emptypb.Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method SendAppGossip not implemented")
 }
@@ -177,6 +191,24 @@ func _AppSender_SendAppResponse_Handler(srv interface{}, ctx context.Context, de
 	return interceptor(ctx, in, info, handler)
 }
 
+func _AppSender_SendAppError_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SendAppErrorMsg)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AppSenderServer).SendAppError(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/appsender.AppSender/SendAppError",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AppSenderServer).SendAppError(ctx, req.(*SendAppErrorMsg))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _AppSender_SendAppGossip_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(SendAppGossipMsg)
 	if err := dec(in); err != nil {
@@ -264,6 +296,10 @@ var AppSender_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "SendAppResponse",
 			Handler:    _AppSender_SendAppResponse_Handler,
 		},
+		{
+			MethodName: "SendAppError",
+			Handler:    _AppSender_SendAppError_Handler,
+		},
 		{
 			MethodName: "SendAppGossip",
 			Handler:    _AppSender_SendAppGossip_Handler,
@@ -0,0 +1,271 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.2.0
+// - protoc             (unknown)
+// source: blob/blob.proto
+
+package blob
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// BlobClient is the client API for Blob service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type BlobClient interface {
+	// Put stores the blob made of the chunks sent through the stream once the
+	// client closes it.
+	Put(ctx context.Context, opts ...grpc.CallOption) (Blob_PutClient, error)
+	// Get fails with NOT_FOUND if the blob isn't stored.
+	Get(ctx context.Context, in *GetRequest, opts ...grpc.CallOption) (Blob_GetClient, error)
+	Fetch(ctx context.Context, in *FetchRequest, opts ...grpc.CallOption) (Blob_FetchClient, error)
+}
+
+type blobClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewBlobClient(cc grpc.ClientConnInterface) BlobClient {
+	return &blobClient{cc}
+}
+
+func (c *blobClient) Put(ctx context.Context, opts ...grpc.CallOption) (Blob_PutClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Blob_ServiceDesc.Streams[0], "/blob.Blob/Put", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &blobPutClient{stream}
+	return x, nil
+}
+
+type Blob_PutClient interface {
+	Send(*BlobChunk) error
+	CloseAndRecv() (*PutResponse, error)
+	grpc.ClientStream
+}
+
+type blobPutClient struct {
+	grpc.ClientStream
+}
+
+func (x *blobPutClient) Send(m *BlobChunk) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *blobPutClient) CloseAndRecv() (*PutResponse, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(PutResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *blobClient) Get(ctx context.Context, in *GetRequest, opts ...grpc.CallOption) (Blob_GetClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Blob_ServiceDesc.Streams[1], "/blob.Blob/Get", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &blobGetClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Blob_GetClient interface {
+	Recv() (*BlobChunk, error)
+	grpc.ClientStream
+}
+
+type blobGetClient struct {
+	grpc.ClientStream
+}
+
+func (x *blobGetClient) Recv() (*BlobChunk, error) {
+	m := new(BlobChunk)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *blobClient) Fetch(ctx context.Context, in *FetchRequest, opts ...grpc.CallOption) (Blob_FetchClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Blob_ServiceDesc.Streams[2], "/blob.Blob/Fetch", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &blobFetchClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Blob_FetchClient interface {
+	Recv() (*BlobChunk, error)
+	grpc.ClientStream
+}
+
+type blobFetchClient struct {
+	grpc.ClientStream
+}
+
+func (x *blobFetchClient) Recv() (*BlobChunk, error) {
+	m := new(BlobChunk)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// BlobServer is the server API for Blob service.
+// All implementations must embed UnimplementedBlobServer
+// for forward compatibility
+type BlobServer interface {
+	// Put stores the blob made of the chunks sent through the stream once the
+	// client closes it.
+	Put(Blob_PutServer) error
+	// Get fails with NOT_FOUND if the blob isn't stored.
+	Get(*GetRequest, Blob_GetServer) error
+	Fetch(*FetchRequest, Blob_FetchServer) error
+	mustEmbedUnimplementedBlobServer()
+}
+
+// UnimplementedBlobServer must be embedded to have forward compatible implementations.
+type UnimplementedBlobServer struct {
+}
+
+func (UnimplementedBlobServer) Put(Blob_PutServer) error {
+	return status.Errorf(codes.Unimplemented, "method Put not implemented")
+}
+func (UnimplementedBlobServer) Get(*GetRequest, Blob_GetServer) error {
+	return status.Errorf(codes.Unimplemented, "method Get not implemented")
+}
+func (UnimplementedBlobServer) Fetch(*FetchRequest, Blob_FetchServer) error {
+	return status.Errorf(codes.Unimplemented, "method Fetch not implemented")
+}
+func (UnimplementedBlobServer) mustEmbedUnimplementedBlobServer() {}
+
+// UnsafeBlobServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to BlobServer will
+// result in compilation errors.
+type UnsafeBlobServer interface {
+	mustEmbedUnimplementedBlobServer()
+}
+
+func RegisterBlobServer(s grpc.ServiceRegistrar, srv BlobServer) {
+	s.RegisterService(&Blob_ServiceDesc, srv)
+}
+
+func _Blob_Put_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(BlobServer).Put(&blobPutServer{stream})
+}
+
+type Blob_PutServer interface {
+	SendAndClose(*PutResponse) error
+	Recv() (*BlobChunk, error)
+	grpc.ServerStream
+}
+
+type blobPutServer struct {
+	grpc.ServerStream
+}
+
+func (x *blobPutServer) SendAndClose(m *PutResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *blobPutServer) Recv() (*BlobChunk, error) {
+	m := new(BlobChunk)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _Blob_Get_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(GetRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BlobServer).Get(m, &blobGetServer{stream})
+}
+
+type Blob_GetServer interface {
+	Send(*BlobChunk) error
+	grpc.ServerStream
+}
+
+type blobGetServer struct {
+	grpc.ServerStream
+}
+
+func (x *blobGetServer) Send(m *BlobChunk) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Blob_Fetch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(FetchRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BlobServer).Fetch(m, &blobFetchServer{stream})
+}
+
+type Blob_FetchServer interface {
+	Send(*BlobChunk) error
+	grpc.ServerStream
+}
+
+type blobFetchServer struct {
+	grpc.ServerStream
+}
+
+func (x *blobFetchServer) Send(m *BlobChunk) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// Blob_ServiceDesc is the grpc.ServiceDesc for Blob service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Blob_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "blob.Blob",
+	HandlerType: (*BlobServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Put",
+			Handler:       _Blob_Put_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "Get",
+			Handler:       _Blob_Get_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "Fetch",
+			Handler:       _Blob_Fetch_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "blob/blob.proto",
+}
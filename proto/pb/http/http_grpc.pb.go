@@ -31,6 +31,17 @@ type HTTPClient interface {
 	// gRPC servers the CPU cost as well as file descriptor overhead is less
 	// (no additional goroutines).
 	HandleSimple(ctx context.Context, in *HandleSimpleHTTPRequest, opts ...grpc.CallOption) (*HandleSimpleHTTPResponse, error)
+	// HandleUpgrade serves a request that asks to upgrade its connection to
+	// another protocol, such as websockets. Unlike Handle, the connection is
+	// tunneled through the stream, so neither side has to dial the other. If the
+	// handler hijacks the connection, the rest of the stream carries the bytes
+	// of the connection in both directions.
+	HandleUpgrade(ctx context.Context, opts ...grpc.CallOption) (HTTP_HandleUpgradeClient, error)
+	// HandleStream handles http requests like HandleSimple, but streams the
+	// response instead of buffering it. Every time the handler flushes its
+	// response, the bytes written so far are sent, so long-polling and
+	// server-sent events endpoints can be served.
+	HandleStream(ctx context.Context, in *HandleSimpleHTTPRequest, opts ...grpc.CallOption) (HTTP_HandleStreamClient, error)
 }
 
 type hTTPClient struct {
@@ -59,6 +70,69 @@ func (c *hTTPClient) HandleSimple(ctx context.Context, in *HandleSimpleHTTPReque
 	return out, nil
 }
 
+func (c *hTTPClient) HandleUpgrade(ctx context.Context, opts ...grpc.CallOption) (HTTP_HandleUpgradeClient, error) {
+	stream, err := c.cc.NewStream(ctx, &HTTP_ServiceDesc.Streams[0], "/http.HTTP/HandleUpgrade", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &hTTPHandleUpgradeClient{stream}
+	return x, nil
+}
+
+type HTTP_HandleUpgradeClient interface {
+	Send(*UpgradeRequest) error
+	Recv() (*UpgradeResponse, error)
+	grpc.ClientStream
+}
+
+type hTTPHandleUpgradeClient struct {
+	grpc.ClientStream
+}
+
+func (x *hTTPHandleUpgradeClient) Send(m *UpgradeRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *hTTPHandleUpgradeClient) Recv() (*UpgradeResponse, error) {
+	m := new(UpgradeResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *hTTPClient) HandleStream(ctx context.Context, in *HandleSimpleHTTPRequest, opts ...grpc.CallOption) (HTTP_HandleStreamClient, error) {
+	stream, err := c.cc.NewStream(ctx, &HTTP_ServiceDesc.Streams[1], "/http.HTTP/HandleStream", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &hTTPHandleStreamClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type HTTP_HandleStreamClient interface {
+	Recv() (*HandleStreamResponse, error)
+	grpc.ClientStream
+}
+
+type hTTPHandleStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *hTTPHandleStreamClient) Recv() (*HandleStreamResponse, error) {
+	m := new(HandleStreamResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // HTTPServer is the server API for HTTP service.
 // All implementations must embed UnimplementedHTTPServer
 // for forward compatibility
@@ -71,6 +145,17 @@ type HTTPServer interface {
 	// gRPC servers the CPU cost as well as file descriptor overhead is less
 	// (no additional goroutines).
 	HandleSimple(context.Context, *HandleSimpleHTTPRequest) (*HandleSimpleHTTPResponse, error)
+	// HandleUpgrade serves a request that asks to upgrade its connection to
+	// another protocol, such as websockets. Unlike Handle, the connection is
+	// tunneled through the stream, so neither side has to dial the other. If the
+	// handler hijacks the connection, the rest of the stream carries the bytes
+	// of the connection in both directions.
+	HandleUpgrade(HTTP_HandleUpgradeServer) error
+	// HandleStream handles http requests like HandleSimple, but streams the
+	// response instead of buffering it. Every time the handler flushes its
+	// response, the bytes written so far are sent, so long-polling and
+	// server-sent events endpoints can be served.
+	HandleStream(*HandleSimpleHTTPRequest, HTTP_HandleStreamServer) error
 	mustEmbedUnimplementedHTTPServer()
 }
 
@@ -84,6 +169,12 @@ func (UnimplementedHTTPServer) Handle(context.Context, *HTTPRequest) (*emptypb.E
 func (UnimplementedHTTPServer) HandleSimple(context.Context, *HandleSimpleHTTPRequest) (*HandleSimpleHTTPResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method HandleSimple not implemented")
 }
+func (UnimplementedHTTPServer) HandleUpgrade(HTTP_HandleUpgradeServer) error {
+	return status.Errorf(codes.Unimplemented, "method HandleUpgrade not implemented")
+}
+func (UnimplementedHTTPServer) HandleStream(*HandleSimpleHTTPRequest, HTTP_HandleStreamServer) error {
+	return status.Errorf(codes.Unimplemented, "method HandleStream not implemented")
+}
 func (UnimplementedHTTPServer) mustEmbedUnimplementedHTTPServer() {}
 
 // UnsafeHTTPServer may be embedded to opt out of forward compatibility for this service.
@@ -133,6 +224,53 @@ func _HTTP_HandleSimple_Handler(srv interface{}, ctx context.Context, dec func(i
 	return interceptor(ctx, in, info, handler)
 }
 
+func _HTTP_HandleUpgrade_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(HTTPServer).HandleUpgrade(&hTTPHandleUpgradeServer{stream})
+}
+
+type HTTP_HandleUpgradeServer interface {
+	Send(*UpgradeResponse) error
+	Recv() (*UpgradeRequest, error)
+	grpc.ServerStream
+}
+
+type hTTPHandleUpgradeServer struct {
+	grpc.ServerStream
+}
+
+func (x *hTTPHandleUpgradeServer) Send(m *UpgradeResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *hTTPHandleUpgradeServer) Recv() (*UpgradeRequest, error) {
+	m := new(UpgradeRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _HTTP_HandleStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(HandleSimpleHTTPRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(HTTPServer).HandleStream(m, &hTTPHandleStreamServer{stream})
+}
+
+type HTTP_HandleStreamServer interface {
+	Send(*HandleStreamResponse) error
+	grpc.ServerStream
+}
+
+type hTTPHandleStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *hTTPHandleStreamServer) Send(m *HandleStreamResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 // HTTP_ServiceDesc is the grpc.ServiceDesc for HTTP service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -149,6 +287,18 @@ var HTTP_ServiceDesc = grpc.ServiceDesc{
 			Handler:    _HTTP_HandleSimple_Handler,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "HandleUpgrade",
+			Handler:       _HTTP_HandleUpgrade_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "HandleStream",
+			Handler:       _HTTP_HandleStream_Handler,
+			ServerStreams: true,
+		},
+	},
 	Metadata: "http/http.proto",
 }
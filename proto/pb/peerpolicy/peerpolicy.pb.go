@@ -0,0 +1,301 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.28.0
+// 	protoc        (unknown)
+// source: peerpolicy/peerpolicy.proto
+
+package peerpolicy
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type AllowRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// node_id is the ID of the peer. Before the handshake is complete, it is the
+	// node the connection was dialed to, or the empty ID if the connection is
+	// inbound.
+	NodeId []byte `protobuf:"bytes,1,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
+	// inbound is true if the peer initiated the connection
+	Inbound bool `protobuf:"varint,2,opt,name=inbound,proto3" json:"inbound,omitempty"`
+	// remote_ip is the IP the connection is made with
+	RemoteIp   []byte `protobuf:"bytes,3,opt,name=remote_ip,json=remoteIp,proto3" json:"remote_ip,omitempty"`
+	RemotePort uint32 `protobuf:"varint,4,opt,name=remote_port,json=remotePort,proto3" json:"remote_port,omitempty"`
+	// handshake_complete is true if the fields below are set
+	HandshakeComplete bool `protobuf:"varint,5,opt,name=handshake_complete,json=handshakeComplete,proto3" json:"handshake_complete,omitempty"`
+	// ip is the IP the peer signed in its handshake
+	Ip             []byte   `protobuf:"bytes,6,opt,name=ip,proto3" json:"ip,omitempty"`
+	Port           uint32   `protobuf:"varint,7,opt,name=port,proto3" json:"port,omitempty"`
+	Version        string   `protobuf:"bytes,8,opt,name=version,proto3" json:"version,omitempty"`
+	TrackedSubnets [][]byte `protobuf:"bytes,9,rep,name=tracked_subnets,json=trackedSubnets,proto3" json:"tracked_subnets,omitempty"`
+}
+
+func (x *AllowRequest) Reset() {
+	*x = AllowRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_peerpolicy_peerpolicy_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AllowRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AllowRequest) ProtoMessage() {}
+
+func (x *AllowRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_peerpolicy_peerpolicy_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AllowRequest.ProtoReflect.Descriptor instead.
+func (*AllowRequest) Descriptor() ([]byte, []int) {
+	return file_peerpolicy_peerpolicy_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *AllowRequest) GetNodeId() []byte {
+	if x != nil {
+		return x.NodeId
+	}
+	return nil
+}
+
+func (x *AllowRequest) GetInbound() bool {
+	if x != nil {
+		return x.Inbound
+	}
+	return false
+}
+
+func (x *AllowRequest) GetRemoteIp() []byte {
+	if x != nil {
+		return x.RemoteIp
+	}
+	return nil
+}
+
+func (x *AllowRequest) GetRemotePort() uint32 {
+	if x != nil {
+		return x.RemotePort
+	}
+	return 0
+}
+
+func (x *AllowRequest) GetHandshakeComplete() bool {
+	if x != nil {
+		return x.HandshakeComplete
+	}
+	return false
+}
+
+func (x *AllowRequest) GetIp() []byte {
+	if x != nil {
+		return x.Ip
+	}
+	return nil
+}
+
+func (x *AllowRequest) GetPort() uint32 {
+	if x != nil {
+		return x.Port
+	}
+	return 0
+}
+
+func (x *AllowRequest) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *AllowRequest) GetTrackedSubnets() [][]byte {
+	if x != nil {
+		return x.TrackedSubnets
+	}
+	return nil
+}
+
+type AllowResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Allowed bool `protobuf:"varint,1,opt,name=allowed,proto3" json:"allowed,omitempty"`
+}
+
+func (x *AllowResponse) Reset() {
+	*x = AllowResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_peerpolicy_peerpolicy_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AllowResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AllowResponse) ProtoMessage() {}
+
+func (x *AllowResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_peerpolicy_peerpolicy_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AllowResponse.ProtoReflect.Descriptor instead.
+func (*AllowResponse) Descriptor() ([]byte, []int) {
+	return file_peerpolicy_peerpolicy_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *AllowResponse) GetAllowed() bool {
+	if x != nil {
+		return x.Allowed
+	}
+	return false
+}
+
+var File_peerpolicy_peerpolicy_proto protoreflect.FileDescriptor
+
+var file_peerpolicy_peerpolicy_proto_rawDesc = []byte{
+	0x0a, 0x1b, 0x70, 0x65, 0x65, 0x72, 0x70, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x2f, 0x70, 0x65, 0x65,
+	0x72, 0x70, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0a, 0x70,
+	0x65, 0x65, 0x72, 0x70, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x22, 0x95, 0x02, 0x0a, 0x0c, 0x41, 0x6c,
+	0x6c, 0x6f, 0x77, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x17, 0x0a, 0x07, 0x6e, 0x6f,
+	0x64, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x6e, 0x6f, 0x64,
+	0x65, 0x49, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x69, 0x6e, 0x62, 0x6f, 0x75, 0x6e, 0x64, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x69, 0x6e, 0x62, 0x6f, 0x75, 0x6e, 0x64, 0x12, 0x1b, 0x0a,
+	0x09, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x5f, 0x69, 0x70, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c,
+	0x52, 0x08, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x49, 0x70, 0x12, 0x1f, 0x0a, 0x0b, 0x72, 0x65,
+	0x6d, 0x6f, 0x74, 0x65, 0x5f, 0x70, 0x6f, 0x72, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52,
+	0x0a, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x50, 0x6f, 0x72, 0x74, 0x12, 0x2d, 0x0a, 0x12, 0x68,
+	0x61, 0x6e, 0x64, 0x73, 0x68, 0x61, 0x6b, 0x65, 0x5f, 0x63, 0x6f, 0x6d, 0x70, 0x6c, 0x65, 0x74,
+	0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x11, 0x68, 0x61, 0x6e, 0x64, 0x73, 0x68, 0x61,
+	0x6b, 0x65, 0x43, 0x6f, 0x6d, 0x70, 0x6c, 0x65, 0x74, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x70,
+	0x18, 0x06, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x02, 0x69, 0x70, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x6f,
+	0x72, 0x74, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x04, 0x70, 0x6f, 0x72, 0x74, 0x12, 0x18,
+	0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x27, 0x0a, 0x0f, 0x74, 0x72, 0x61, 0x63,
+	0x6b, 0x65, 0x64, 0x5f, 0x73, 0x75, 0x62, 0x6e, 0x65, 0x74, 0x73, 0x18, 0x09, 0x20, 0x03, 0x28,
+	0x0c, 0x52, 0x0e, 0x74, 0x72, 0x61, 0x63, 0x6b, 0x65, 0x64, 0x53, 0x75, 0x62, 0x6e, 0x65, 0x74,
+	0x73, 0x22, 0x29, 0x0a, 0x0d, 0x41, 0x6c, 0x6c, 0x6f, 0x77, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x65, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x07, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x65, 0x64, 0x32, 0x4a, 0x0a, 0x0a,
+	0x50, 0x65, 0x65, 0x72, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x12, 0x3c, 0x0a, 0x05, 0x41, 0x6c,
+	0x6c, 0x6f, 0x77, 0x12, 0x18, 0x2e, 0x70, 0x65, 0x65, 0x72, 0x70, 0x6f, 0x6c, 0x69, 0x63, 0x79,
+	0x2e, 0x41, 0x6c, 0x6c, 0x6f, 0x77, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e,
+	0x70, 0x65, 0x65, 0x72, 0x70, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x2e, 0x41, 0x6c, 0x6c, 0x6f, 0x77,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x35, 0x5a, 0x33, 0x67, 0x69, 0x74, 0x68,
+	0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x61, 0x76, 0x61, 0x2d, 0x6c, 0x61, 0x62, 0x73, 0x2f,
+	0x61, 0x76, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x68, 0x65, 0x67, 0x6f, 0x2f, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x2f, 0x70, 0x62, 0x2f, 0x70, 0x65, 0x65, 0x72, 0x70, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x62,
+	0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_peerpolicy_peerpolicy_proto_rawDescOnce sync.Once
+	file_peerpolicy_peerpolicy_proto_rawDescData = file_peerpolicy_peerpolicy_proto_rawDesc
+)
+
+func file_peerpolicy_peerpolicy_proto_rawDescGZIP() []byte {
+	file_peerpolicy_peerpolicy_proto_rawDescOnce.Do(func() {
+		file_peerpolicy_peerpolicy_proto_rawDescData = protoimpl.X.CompressGZIP(file_peerpolicy_peerpolicy_proto_rawDescData)
+	})
+	return file_peerpolicy_peerpolicy_proto_rawDescData
+}
+
+var file_peerpolicy_peerpolicy_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_peerpolicy_peerpolicy_proto_goTypes = []interface{}{
+	(*AllowRequest)(nil),  // 0: peerpolicy.AllowRequest
+	(*AllowResponse)(nil), // 1: peerpolicy.AllowResponse
+}
+var file_peerpolicy_peerpolicy_proto_depIdxs = []int32{
+	0, // 0: peerpolicy.PeerPolicy.Allow:input_type -> peerpolicy.AllowRequest
+	1, // 1: peerpolicy.PeerPolicy.Allow:output_type -> peerpolicy.AllowResponse
+	1, // [1:2] is the sub-list for method output_type
+	0, // [0:1] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_peerpolicy_peerpolicy_proto_init() }
+func file_peerpolicy_peerpolicy_proto_init() {
+	if File_peerpolicy_peerpolicy_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_peerpolicy_peerpolicy_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AllowRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_peerpolicy_peerpolicy_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AllowResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_peerpolicy_peerpolicy_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_peerpolicy_peerpolicy_proto_goTypes,
+		DependencyIndexes: file_peerpolicy_peerpolicy_proto_depIdxs,
+		MessageInfos:      file_peerpolicy_peerpolicy_proto_msgTypes,
+	}.Build()
+	File_peerpolicy_peerpolicy_proto = out.File
+	file_peerpolicy_peerpolicy_proto_rawDesc = nil
+	file_peerpolicy_peerpolicy_proto_goTypes = nil
+	file_peerpolicy_peerpolicy_proto_depIdxs = nil
+}
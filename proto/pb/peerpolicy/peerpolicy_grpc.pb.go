@@ -0,0 +1,105 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.2.0
+// - protoc             (unknown)
+// source: peerpolicy/peerpolicy.proto
+
+package peerpolicy
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// PeerPolicyClient is the client API for PeerPolicy service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type PeerPolicyClient interface {
+	Allow(ctx context.Context, in *AllowRequest, opts ...grpc.CallOption) (*AllowResponse, error)
+}
+
+type peerPolicyClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewPeerPolicyClient(cc grpc.ClientConnInterface) PeerPolicyClient {
+	return &peerPolicyClient{cc}
+}
+
+func (c *peerPolicyClient) Allow(ctx context.Context, in *AllowRequest, opts ...grpc.CallOption) (*AllowResponse, error) {
+	out := new(AllowResponse)
+	err := c.cc.Invoke(ctx, "/peerpolicy.PeerPolicy/Allow", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// PeerPolicyServer is the server API for PeerPolicy service.
+// All implementations must embed UnimplementedPeerPolicyServer
+// for forward compatibility
+type PeerPolicyServer interface {
+	Allow(context.Context, *AllowRequest) (*AllowResponse, error)
+	mustEmbedUnimplementedPeerPolicyServer()
+}
+
+// UnimplementedPeerPolicyServer must be embedded to have forward compatible implementations.
+type UnimplementedPeerPolicyServer struct {
+}
+
+func (UnimplementedPeerPolicyServer) Allow(context.Context, *AllowRequest) (*AllowResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Allow not implemented")
+}
+func (UnimplementedPeerPolicyServer) mustEmbedUnimplementedPeerPolicyServer() {}
+
+// UnsafePeerPolicyServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to PeerPolicyServer will
+// result in compilation errors.
+type UnsafePeerPolicyServer interface {
+	mustEmbedUnimplementedPeerPolicyServer()
+}
+
+func RegisterPeerPolicyServer(s grpc.ServiceRegistrar, srv PeerPolicyServer) {
+	s.RegisterService(&PeerPolicy_ServiceDesc, srv)
+}
+
+func _PeerPolicy_Allow_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AllowRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PeerPolicyServer).Allow(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/peerpolicy.PeerPolicy/Allow",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PeerPolicyServer).Allow(ctx, req.(*AllowRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// PeerPolicy_ServiceDesc is the grpc.ServiceDesc for PeerPolicy service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var PeerPolicy_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "peerpolicy.PeerPolicy",
+	HandlerType: (*PeerPolicyServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Allow",
+			Handler:    _PeerPolicy_Allow_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "peerpolicy/peerpolicy.proto",
+}
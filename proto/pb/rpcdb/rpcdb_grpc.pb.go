@@ -35,6 +35,13 @@ type DatabaseClient interface {
 	IteratorNext(ctx context.Context, in *IteratorNextRequest, opts ...grpc.CallOption) (*IteratorNextResponse, error)
 	IteratorError(ctx context.Context, in *IteratorErrorRequest, opts ...grpc.CallOption) (*IteratorErrorResponse, error)
 	IteratorRelease(ctx context.Context, in *IteratorReleaseRequest, opts ...grpc.CallOption) (*IteratorReleaseResponse, error)
+	// IteratorStream iterates over the database and streams the elements in
+	// pages, so that the client doesn't wait on a round trip for each page.
+	IteratorStream(ctx context.Context, in *IteratorStreamRequest, opts ...grpc.CallOption) (Database_IteratorStreamClient, error)
+	// NewSnapshot pins a view of the database's current contents that reads can
+	// be made from until it's released.
+	NewSnapshot(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*NewSnapshotResponse, error)
+	ReleaseSnapshot(ctx context.Context, in *ReleaseSnapshotRequest, opts ...grpc.CallOption) (*ReleaseSnapshotResponse, error)
 }
 
 type databaseClient struct {
@@ -153,6 +160,56 @@ func (c *databaseClient) IteratorRelease(ctx context.Context, in *IteratorReleas
 	return out, nil
 }
 
+func (c *databaseClient) IteratorStream(ctx context.Context, in *IteratorStreamRequest, opts ...grpc.CallOption) (Database_IteratorStreamClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Database_ServiceDesc.Streams[0], "/rpcdb.Database/IteratorStream", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &databaseIteratorStreamClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Database_IteratorStreamClient interface {
+	Recv() (*IteratorStreamResponse, error)
+	grpc.ClientStream
+}
+
+type databaseIteratorStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *databaseIteratorStreamClient) Recv() (*IteratorStreamResponse, error) {
+	m := new(IteratorStreamResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *databaseClient) NewSnapshot(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*NewSnapshotResponse, error) {
+	out := new(NewSnapshotResponse)
+	err := c.cc.Invoke(ctx, "/rpcdb.Database/NewSnapshot", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *databaseClient) ReleaseSnapshot(ctx context.Context, in *ReleaseSnapshotRequest, opts ...grpc.CallOption) (*ReleaseSnapshotResponse, error) {
+	out := new(ReleaseSnapshotResponse)
+	err := c.cc.Invoke(ctx, "/rpcdb.Database/ReleaseSnapshot", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // DatabaseServer is the server API for Database service.
 // All implementations must embed UnimplementedDatabaseServer
 // for forward compatibility
@@ -169,6 +226,13 @@ type DatabaseServer interface {
 	IteratorNext(context.Context, *IteratorNextRequest) (*IteratorNextResponse, error)
 	IteratorError(context.Context, *IteratorErrorRequest) (*IteratorErrorResponse, error)
 	IteratorRelease(context.Context, *IteratorReleaseRequest) (*IteratorReleaseResponse, error)
+	// IteratorStream iterates over the database and streams the elements in
+	// pages, so that the client doesn't wait on a round trip for each page.
+	IteratorStream(*IteratorStreamRequest, Database_IteratorStreamServer) error
+	// NewSnapshot pins a view of the database's current contents that reads can
+	// be made from until it's released.
+	NewSnapshot(context.Context, *emptypb.Empty) (*NewSnapshotResponse, error)
+	ReleaseSnapshot(context.Context, *ReleaseSnapshotRequest) (*ReleaseSnapshotResponse, error)
 	mustEmbedUnimplementedDatabaseServer()
 }
 
@@ -212,6 +276,15 @@ func (UnimplementedDatabaseServer) IteratorError(context.Context, *IteratorError
 func (UnimplementedDatabaseServer) IteratorRelease(context.Context, *IteratorReleaseRequest) (*IteratorReleaseResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method IteratorRelease not implemented")
 }
+func (UnimplementedDatabaseServer) IteratorStream(*IteratorStreamRequest, Database_IteratorStreamServer) error {
+	return status.Errorf(codes.Unimplemented, "method IteratorStream not implemented")
+}
+func (UnimplementedDatabaseServer) NewSnapshot(context.Context, *emptypb.Empty) (*NewSnapshotResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method NewSnapshot not implemented")
+}
+func (UnimplementedDatabaseServer) ReleaseSnapshot(context.Context, *ReleaseSnapshotRequest) (*ReleaseSnapshotResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReleaseSnapshot not implemented")
+}
 func (UnimplementedDatabaseServer) mustEmbedUnimplementedDatabaseServer() {}
 
 // UnsafeDatabaseServer may be embedded to opt out of forward compatibility for this service.
@@ -441,6 +514,63 @@ func _Database_IteratorRelease_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Database_IteratorStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(IteratorStreamRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(DatabaseServer).IteratorStream(m, &databaseIteratorStreamServer{stream})
+}
+
+type Database_IteratorStreamServer interface {
+	Send(*IteratorStreamResponse) error
+	grpc.ServerStream
+}
+
+type databaseIteratorStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *databaseIteratorStreamServer) Send(m *IteratorStreamResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Database_NewSnapshot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DatabaseServer).NewSnapshot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpcdb.Database/NewSnapshot",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DatabaseServer).NewSnapshot(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Database_ReleaseSnapshot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReleaseSnapshotRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DatabaseServer).ReleaseSnapshot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpcdb.Database/ReleaseSnapshot",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DatabaseServer).ReleaseSnapshot(ctx, req.(*ReleaseSnapshotRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Database_ServiceDesc is the grpc.ServiceDesc for Database service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -496,7 +626,21 @@ var Database_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "IteratorRelease",
 			Handler:    _Database_IteratorRelease_Handler,
 		},
+		{
+			MethodName: "NewSnapshot",
+			Handler:    _Database_NewSnapshot_Handler,
+		},
+		{
+			MethodName: "ReleaseSnapshot",
+			Handler:    _Database_ReleaseSnapshot_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "IteratorStream",
+			Handler:       _Database_IteratorStream_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "rpcdb/rpcdb.proto",
 }
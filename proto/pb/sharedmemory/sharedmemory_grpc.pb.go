@@ -25,6 +25,11 @@ type SharedMemoryClient interface {
 	Get(ctx context.Context, in *GetRequest, opts ...grpc.CallOption) (*GetResponse, error)
 	Indexed(ctx context.Context, in *IndexedRequest, opts ...grpc.CallOption) (*IndexedResponse, error)
 	Apply(ctx context.Context, in *ApplyRequest, opts ...grpc.CallOption) (*ApplyResponse, error)
+	// ApplyStream applies the requests and batches sent through the stream once
+	// the client closes it, so that large atomic operations aren't limited by
+	// the size of a single message. Nothing is applied if the stream fails.
+	// The id and continues fields of the requests are ignored.
+	ApplyStream(ctx context.Context, opts ...grpc.CallOption) (SharedMemory_ApplyStreamClient, error)
 }
 
 type sharedMemoryClient struct {
@@ -62,6 +67,40 @@ func (c *sharedMemoryClient) Apply(ctx context.Context, in *ApplyRequest, opts .
 	return out, nil
 }
 
+func (c *sharedMemoryClient) ApplyStream(ctx context.Context, opts ...grpc.CallOption) (SharedMemory_ApplyStreamClient, error) {
+	stream, err := c.cc.NewStream(ctx, &SharedMemory_ServiceDesc.Streams[0], "/sharedmemory.SharedMemory/ApplyStream", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &sharedMemoryApplyStreamClient{stream}
+	return x, nil
+}
+
+type SharedMemory_ApplyStreamClient interface {
+	Send(*ApplyRequest) error
+	CloseAndRecv() (*ApplyResponse, error)
+	grpc.ClientStream
+}
+
+type sharedMemoryApplyStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *sharedMemoryApplyStreamClient) Send(m *ApplyRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *sharedMemoryApplyStreamClient) CloseAndRecv() (*ApplyResponse, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(ApplyResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // SharedMemoryServer is the server API for SharedMemory service.
 // All implementations must embed UnimplementedSharedMemoryServer
 // for forward compatibility
@@ -69,6 +108,11 @@ type SharedMemoryServer interface {
 	Get(context.Context, *GetRequest) (*GetResponse, error)
 	Indexed(context.Context, *IndexedRequest) (*IndexedResponse, error)
 	Apply(context.Context, *ApplyRequest) (*ApplyResponse, error)
+	// ApplyStream applies the requests and batches sent through the stream once
+	// the client closes it, so that large atomic operations aren't limited by
+	// the size of a single message. Nothing is applied if the stream fails.
+	// The id and continues fields of the requests are ignored.
+	ApplyStream(SharedMemory_ApplyStreamServer) error
 	mustEmbedUnimplementedSharedMemoryServer()
 }
 
@@ -85,6 +129,9 @@ func (UnimplementedSharedMemoryServer) Indexed(context.Context, *IndexedRequest)
 func (UnimplementedSharedMemoryServer) Apply(context.Context, *ApplyRequest) (*ApplyResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Apply not implemented")
 }
+func (UnimplementedSharedMemoryServer) ApplyStream(SharedMemory_ApplyStreamServer) error {
+	return status.Errorf(codes.Unimplemented, "method ApplyStream not implemented")
+}
 func (UnimplementedSharedMemoryServer) mustEmbedUnimplementedSharedMemoryServer() {}
 
 // UnsafeSharedMemoryServer may be embedded to opt out of forward compatibility for this service.
@@ -152,6 +199,32 @@ func _SharedMemory_Apply_Handler(srv interface{}, ctx context.Context, dec func(
 	return interceptor(ctx, in, info, handler)
 }
 
+func _SharedMemory_ApplyStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(SharedMemoryServer).ApplyStream(&sharedMemoryApplyStreamServer{stream})
+}
+
+type SharedMemory_ApplyStreamServer interface {
+	SendAndClose(*ApplyResponse) error
+	Recv() (*ApplyRequest, error)
+	grpc.ServerStream
+}
+
+type sharedMemoryApplyStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *sharedMemoryApplyStreamServer) SendAndClose(m *ApplyResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *sharedMemoryApplyStreamServer) Recv() (*ApplyRequest, error) {
+	m := new(ApplyRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // SharedMemory_ServiceDesc is the grpc.ServiceDesc for SharedMemory service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -172,6 +245,12 @@ var SharedMemory_ServiceDesc = grpc.ServiceDesc{
 			Handler:    _SharedMemory_Apply_Handler,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ApplyStream",
+			Handler:       _SharedMemory_ApplyStream_Handler,
+			ClientStreams: true,
+		},
+	},
 	Metadata: "sharedmemory/sharedmemory.proto",
 }
@@ -0,0 +1,105 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.2.0
+// - protoc             (unknown)
+// source: signatureaggregator/signatureaggregator.proto
+
+package signatureaggregator
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// SignatureAggregatorClient is the client API for SignatureAggregator service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type SignatureAggregatorClient interface {
+	AggregateSignatures(ctx context.Context, in *AggregateSignaturesRequest, opts ...grpc.CallOption) (*AggregateSignaturesResponse, error)
+}
+
+type signatureAggregatorClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewSignatureAggregatorClient(cc grpc.ClientConnInterface) SignatureAggregatorClient {
+	return &signatureAggregatorClient{cc}
+}
+
+func (c *signatureAggregatorClient) AggregateSignatures(ctx context.Context, in *AggregateSignaturesRequest, opts ...grpc.CallOption) (*AggregateSignaturesResponse, error) {
+	out := new(AggregateSignaturesResponse)
+	err := c.cc.Invoke(ctx, "/signatureaggregator.SignatureAggregator/AggregateSignatures", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// SignatureAggregatorServer is the server API for SignatureAggregator service.
+// All implementations must embed UnimplementedSignatureAggregatorServer
+// for forward compatibility
+type SignatureAggregatorServer interface {
+	AggregateSignatures(context.Context, *AggregateSignaturesRequest) (*AggregateSignaturesResponse, error)
+	mustEmbedUnimplementedSignatureAggregatorServer()
+}
+
+// UnimplementedSignatureAggregatorServer must be embedded to have forward compatible implementations.
+type UnimplementedSignatureAggregatorServer struct {
+}
+
+func (UnimplementedSignatureAggregatorServer) AggregateSignatures(context.Context, *AggregateSignaturesRequest) (*AggregateSignaturesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AggregateSignatures not implemented")
+}
+func (UnimplementedSignatureAggregatorServer) mustEmbedUnimplementedSignatureAggregatorServer() {}
+
+// UnsafeSignatureAggregatorServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to SignatureAggregatorServer will
+// result in compilation errors.
+type UnsafeSignatureAggregatorServer interface {
+	mustEmbedUnimplementedSignatureAggregatorServer()
+}
+
+func RegisterSignatureAggregatorServer(s grpc.ServiceRegistrar, srv SignatureAggregatorServer) {
+	s.RegisterService(&SignatureAggregator_ServiceDesc, srv)
+}
+
+func _SignatureAggregator_AggregateSignatures_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AggregateSignaturesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SignatureAggregatorServer).AggregateSignatures(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/signatureaggregator.SignatureAggregator/AggregateSignatures",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SignatureAggregatorServer).AggregateSignatures(ctx, req.(*AggregateSignaturesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// SignatureAggregator_ServiceDesc is the grpc.ServiceDesc for SignatureAggregator service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var SignatureAggregator_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "signatureaggregator.SignatureAggregator",
+	HandlerType: (*SignatureAggregatorServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "AggregateSignatures",
+			Handler:    _SignatureAggregator_AggregateSignatures_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "signatureaggregator/signatureaggregator.proto",
+}
@@ -31,6 +31,12 @@ type ValidatorStateClient interface {
 	// GetValidatorSet returns the weights of the nodeIDs for the provided
 	// subnet at the requested P-chain height.
 	GetValidatorSet(ctx context.Context, in *GetValidatorSetRequest, opts ...grpc.CallOption) (*GetValidatorSetResponse, error)
+	// GetEpochLength returns the number of P-chain blocks in an epoch of
+	// validator set snapshots.
+	GetEpochLength(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*GetEpochLengthResponse, error)
+	// GetValidatorSetAtEpoch returns the weights of the nodeIDs for the provided
+	// subnet at the P-chain height the requested epoch begins at.
+	GetValidatorSetAtEpoch(ctx context.Context, in *GetValidatorSetAtEpochRequest, opts ...grpc.CallOption) (*GetValidatorSetResponse, error)
 }
 
 type validatorStateClient struct {
@@ -68,6 +74,24 @@ func (c *validatorStateClient) GetValidatorSet(ctx context.Context, in *GetValid
 	return out, nil
 }
 
+func (c *validatorStateClient) GetEpochLength(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*GetEpochLengthResponse, error) {
+	out := new(GetEpochLengthResponse)
+	err := c.cc.Invoke(ctx, "/validatorstate.ValidatorState/GetEpochLength", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *validatorStateClient) GetValidatorSetAtEpoch(ctx context.Context, in *GetValidatorSetAtEpochRequest, opts ...grpc.CallOption) (*GetValidatorSetResponse, error) {
+	out := new(GetValidatorSetResponse)
+	err := c.cc.Invoke(ctx, "/validatorstate.ValidatorState/GetValidatorSetAtEpoch", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ValidatorStateServer is the server API for ValidatorState service.
 // All implementations must embed UnimplementedValidatorStateServer
 // for forward compatibility
@@ -80,6 +104,12 @@ type ValidatorStateServer interface {
 	// GetValidatorSet returns the weights of the nodeIDs for the provided
 	// subnet at the requested P-chain height.
 	GetValidatorSet(context.Context, *GetValidatorSetRequest) (*GetValidatorSetResponse, error)
+	// GetEpochLength returns the number of P-chain blocks in an epoch of
+	// validator set snapshots.
+	GetEpochLength(context.Context, *emptypb.Empty) (*GetEpochLengthResponse, error)
+	// GetValidatorSetAtEpoch returns the weights of the nodeIDs for the provided
+	// subnet at the P-chain height the requested epoch begins at.
+	GetValidatorSetAtEpoch(context.Context, *GetValidatorSetAtEpochRequest) (*GetValidatorSetResponse, error)
 	mustEmbedUnimplementedValidatorStateServer()
 }
 
@@ -96,6 +126,12 @@ func (UnimplementedValidatorStateServer) GetCurrentHeight(context.Context, *empt
 func (UnimplementedValidatorStateServer) GetValidatorSet(context.Context, *GetValidatorSetRequest) (*GetValidatorSetResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetValidatorSet not implemented")
 }
+func (UnimplementedValidatorStateServer) GetEpochLength(context.Context, *emptypb.Empty) (*GetEpochLengthResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetEpochLength not implemented")
+}
+func (UnimplementedValidatorStateServer) GetValidatorSetAtEpoch(context.Context, *GetValidatorSetAtEpochRequest) (*GetValidatorSetResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetValidatorSetAtEpoch not implemented")
+}
 func (UnimplementedValidatorStateServer) mustEmbedUnimplementedValidatorStateServer() {}
 
 // UnsafeValidatorStateServer may be embedded to opt out of forward compatibility for this service.
@@ -163,6 +199,42 @@ func _ValidatorState_GetValidatorSet_Handler(srv interface{}, ctx context.Contex
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ValidatorState_GetEpochLength_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ValidatorStateServer).GetEpochLength(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/validatorstate.ValidatorState/GetEpochLength",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ValidatorStateServer).GetEpochLength(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ValidatorState_GetValidatorSetAtEpoch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetValidatorSetAtEpochRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ValidatorStateServer).GetValidatorSetAtEpoch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/validatorstate.ValidatorState/GetValidatorSetAtEpoch",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ValidatorStateServer).GetValidatorSetAtEpoch(ctx, req.(*GetValidatorSetAtEpochRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // ValidatorState_ServiceDesc is the grpc.ServiceDesc for ValidatorState service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -182,6 +254,14 @@ var ValidatorState_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetValidatorSet",
 			Handler:    _ValidatorState_GetValidatorSet_Handler,
 		},
+		{
+			MethodName: "GetEpochLength",
+			Handler:    _ValidatorState_GetEpochLength_Handler,
+		},
+		{
+			MethodName: "GetValidatorSetAtEpoch",
+			Handler:    _ValidatorState_GetValidatorSetAtEpoch_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "validatorstate/validator_state.proto",
@@ -25,8 +25,17 @@ const _ = grpc.SupportPackageIsVersion7
 type VMClient interface {
 	// ChainVM
 	//
+	// Handshake exchanges the protocol versions of the node and the VM, and
+	// returns the capabilities of the VM. It is called before Initialize.
+	Handshake(ctx context.Context, in *HandshakeRequest, opts ...grpc.CallOption) (*HandshakeResponse, error)
 	// Initialize this VM.
 	Initialize(ctx context.Context, in *InitializeRequest, opts ...grpc.CallOption) (*InitializeResponse, error)
+	// SupportedFxs returns the IDs of the feature extensions the VM can be
+	// initialized with.
+	SupportedFxs(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*SupportedFxsResponse, error)
+	// ConfigSchema returns the JSON schema of the chain configs of the VM. It
+	// may be called before Initialize.
+	ConfigSchema(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*ConfigSchemaResponse, error)
 	// SetState communicates to VM its next state it starts
 	SetState(ctx context.Context, in *SetStateRequest, opts ...grpc.CallOption) (*SetStateResponse, error)
 	// Shutdown is called when the node is shutting down.
@@ -44,6 +53,10 @@ type VMClient interface {
 	Disconnected(ctx context.Context, in *DisconnectedRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
 	// Attempt to create a new block from data contained in the VM.
 	BuildBlock(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*BuildBlockResponse, error)
+	// Attempt to create a new block given the P-Chain height provided by the
+	// proposervm. Only called if the plugin reports the block context
+	// capability.
+	BuildBlockWithContext(ctx context.Context, in *BuildBlockWithContextRequest, opts ...grpc.CallOption) (*BuildBlockResponse, error)
 	// Attempt to create a block from a stream of bytes.
 	ParseBlock(ctx context.Context, in *ParseBlockRequest, opts ...grpc.CallOption) (*ParseBlockResponse, error)
 	// Attempt to load a block.
@@ -52,6 +65,9 @@ type VMClient interface {
 	SetPreference(ctx context.Context, in *SetPreferenceRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
 	// Attempt to verify the health of the VM.
 	Health(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*HealthResponse, error)
+	// ConnectionHealth returns the health of the servers of the node, probed
+	// over the connections of the VM to them.
+	ConnectionHealth(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*ConnectionHealthResponse, error)
 	// Version returns the version of the VM.
 	Version(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*VersionResponse, error)
 	// Notify this engine of a request for data from [nodeID].
@@ -64,6 +80,11 @@ type VMClient interface {
 	AppResponse(ctx context.Context, in *AppResponseMsg, opts ...grpc.CallOption) (*emptypb.Empty, error)
 	// Notify this engine of a gossip message from [nodeID].
 	AppGossip(ctx context.Context, in *AppGossipMsg, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// Notify this engine of batches of gossip messages over a single stream.
+	// The first response acknowledges the stream, and each batch is then
+	// acknowledged once its messages have been delivered, one at a time, as by
+	// AppGossip.
+	AppGossipStream(ctx context.Context, opts ...grpc.CallOption) (VM_AppGossipStreamClient, error)
 	// Attempts to gather metrics from a VM.
 	Gather(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*GatherResponse, error)
 	CrossChainAppRequest(ctx context.Context, in *CrossChainAppRequestMsg, opts ...grpc.CallOption) (*emptypb.Empty, error)
@@ -72,6 +93,7 @@ type VMClient interface {
 	// BatchedChainVM
 	GetAncestors(ctx context.Context, in *GetAncestorsRequest, opts ...grpc.CallOption) (*GetAncestorsResponse, error)
 	BatchedParseBlock(ctx context.Context, in *BatchedParseBlockRequest, opts ...grpc.CallOption) (*BatchedParseBlockResponse, error)
+	BatchedGetBlock(ctx context.Context, in *BatchedGetBlockRequest, opts ...grpc.CallOption) (*BatchedGetBlockResponse, error)
 	// HeightIndexedChainVM
 	VerifyHeightIndex(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*VerifyHeightIndexResponse, error)
 	GetBlockIDAtHeight(ctx context.Context, in *GetBlockIDAtHeightRequest, opts ...grpc.CallOption) (*GetBlockIDAtHeightResponse, error)
@@ -88,10 +110,36 @@ type VMClient interface {
 	// GetStateSummary retrieves the state summary that was generated at height
 	// [summaryHeight].
 	GetStateSummary(ctx context.Context, in *GetStateSummaryRequest, opts ...grpc.CallOption) (*GetStateSummaryResponse, error)
+	// Decoder
+	//
+	// DecodeBlock returns a JSON representation of a block without processing it.
+	DecodeBlock(ctx context.Context, in *DecodeBlockRequest, opts ...grpc.CallOption) (*DecodeBlockResponse, error)
+	// StateDiffer
+	//
+	// GetStateDiff streams the keys whose values differ between the states at
+	// the accepted heights [start_height] and [end_height], in increasing key
+	// order. The last response holds the digest of the diff.
+	GetStateDiff(ctx context.Context, in *GetStateDiffRequest, opts ...grpc.CallOption) (VM_GetStateDiffClient, error)
+	// MessageVerifier
+	//
+	// VerifyMessage returns an error if the node shouldn't sign [message] with
+	// its BLS key.
+	VerifyMessage(ctx context.Context, in *VerifyMessageRequest, opts ...grpc.CallOption) (*VerifyMessageResponse, error)
 	// Block
 	BlockVerify(ctx context.Context, in *BlockVerifyRequest, opts ...grpc.CallOption) (*BlockVerifyResponse, error)
+	// Verify a block given the P-Chain height provided by the proposervm. Only
+	// called for the blocks whose responses set verify_with_context.
+	BlockVerifyWithContext(ctx context.Context, in *BlockVerifyWithContextRequest, opts ...grpc.CallOption) (*BlockVerifyResponse, error)
 	BlockAccept(ctx context.Context, in *BlockAcceptRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
 	BlockReject(ctx context.Context, in *BlockRejectRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// BatchedBlockAccept accepts the blocks [ids], in order.
+	BatchedBlockAccept(ctx context.Context, in *BatchedBlockAcceptRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// BatchedBlockReject rejects the blocks [ids], in order.
+	BatchedBlockReject(ctx context.Context, in *BatchedBlockRejectRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// BlockOptions returns the options of the oracle block [id], in the order
+	// the VM prefers them. Only called for the blocks whose responses set
+	// oracle.
+	BlockOptions(ctx context.Context, in *BlockOptionsRequest, opts ...grpc.CallOption) (*BlockOptionsResponse, error)
 	// StateSummary
 	StateSummaryAccept(ctx context.Context, in *StateSummaryAcceptRequest, opts ...grpc.CallOption) (*StateSummaryAcceptResponse, error)
 }
@@ -104,6 +152,15 @@ func NewVMClient(cc grpc.ClientConnInterface) VMClient {
 	return &vMClient{cc}
 }
 
+func (c *vMClient) Handshake(ctx context.Context, in *HandshakeRequest, opts ...grpc.CallOption) (*HandshakeResponse, error) {
+	out := new(HandshakeResponse)
+	err := c.cc.Invoke(ctx, "/vm.VM/Handshake", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *vMClient) Initialize(ctx context.Context, in *InitializeRequest, opts ...grpc.CallOption) (*InitializeResponse, error) {
 	out := new(InitializeResponse)
 	err := c.cc.Invoke(ctx, "/vm.VM/Initialize", in, out, opts...)
@@ -113,6 +170,24 @@ func (c *vMClient) Initialize(ctx context.Context, in *InitializeRequest, opts .
 	return out, nil
 }
 
+func (c *vMClient) SupportedFxs(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*SupportedFxsResponse, error) {
+	out := new(SupportedFxsResponse)
+	err := c.cc.Invoke(ctx, "/vm.VM/SupportedFxs", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *vMClient) ConfigSchema(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*ConfigSchemaResponse, error) {
+	out := new(ConfigSchemaResponse)
+	err := c.cc.Invoke(ctx, "/vm.VM/ConfigSchema", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *vMClient) SetState(ctx context.Context, in *SetStateRequest, opts ...grpc.CallOption) (*SetStateResponse, error) {
 	out := new(SetStateResponse)
 	err := c.cc.Invoke(ctx, "/vm.VM/SetState", in, out, opts...)
@@ -176,6 +251,15 @@ func (c *vMClient) BuildBlock(ctx context.Context, in *emptypb.Empty, opts ...gr
 	return out, nil
 }
 
+func (c *vMClient) BuildBlockWithContext(ctx context.Context, in *BuildBlockWithContextRequest, opts ...grpc.CallOption) (*BuildBlockResponse, error) {
+	out := new(BuildBlockResponse)
+	err := c.cc.Invoke(ctx, "/vm.VM/BuildBlockWithContext", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *vMClient) ParseBlock(ctx context.Context, in *ParseBlockRequest, opts ...grpc.CallOption) (*ParseBlockResponse, error) {
 	out := new(ParseBlockResponse)
 	err := c.cc.Invoke(ctx, "/vm.VM/ParseBlock", in, out, opts...)
@@ -212,6 +296,15 @@ func (c *vMClient) Health(ctx context.Context, in *emptypb.Empty, opts ...grpc.C
 	return out, nil
 }
 
+func (c *vMClient) ConnectionHealth(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*ConnectionHealthResponse, error) {
+	out := new(ConnectionHealthResponse)
+	err := c.cc.Invoke(ctx, "/vm.VM/ConnectionHealth", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *vMClient) Version(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*VersionResponse, error) {
 	out := new(VersionResponse)
 	err := c.cc.Invoke(ctx, "/vm.VM/Version", in, out, opts...)
@@ -257,6 +350,37 @@ func (c *vMClient) AppGossip(ctx context.Context, in *AppGossipMsg, opts ...grpc
 	return out, nil
 }
 
+func (c *vMClient) AppGossipStream(ctx context.Context, opts ...grpc.CallOption) (VM_AppGossipStreamClient, error) {
+	stream, err := c.cc.NewStream(ctx, &VM_ServiceDesc.Streams[0], "/vm.VM/AppGossipStream", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &vMAppGossipStreamClient{stream}
+	return x, nil
+}
+
+type VM_AppGossipStreamClient interface {
+	Send(*AppGossipBatch) error
+	Recv() (*AppGossipBatchAck, error)
+	grpc.ClientStream
+}
+
+type vMAppGossipStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *vMAppGossipStreamClient) Send(m *AppGossipBatch) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *vMAppGossipStreamClient) Recv() (*AppGossipBatchAck, error) {
+	m := new(AppGossipBatchAck)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func (c *vMClient) Gather(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*GatherResponse, error) {
 	out := new(GatherResponse)
 	err := c.cc.Invoke(ctx, "/vm.VM/Gather", in, out, opts...)
@@ -311,6 +435,15 @@ func (c *vMClient) BatchedParseBlock(ctx context.Context, in *BatchedParseBlockR
 	return out, nil
 }
 
+func (c *vMClient) BatchedGetBlock(ctx context.Context, in *BatchedGetBlockRequest, opts ...grpc.CallOption) (*BatchedGetBlockResponse, error) {
+	out := new(BatchedGetBlockResponse)
+	err := c.cc.Invoke(ctx, "/vm.VM/BatchedGetBlock", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *vMClient) VerifyHeightIndex(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*VerifyHeightIndexResponse, error) {
 	out := new(VerifyHeightIndexResponse)
 	err := c.cc.Invoke(ctx, "/vm.VM/VerifyHeightIndex", in, out, opts...)
@@ -374,6 +507,56 @@ func (c *vMClient) GetStateSummary(ctx context.Context, in *GetStateSummaryReque
 	return out, nil
 }
 
+func (c *vMClient) DecodeBlock(ctx context.Context, in *DecodeBlockRequest, opts ...grpc.CallOption) (*DecodeBlockResponse, error) {
+	out := new(DecodeBlockResponse)
+	err := c.cc.Invoke(ctx, "/vm.VM/DecodeBlock", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *vMClient) GetStateDiff(ctx context.Context, in *GetStateDiffRequest, opts ...grpc.CallOption) (VM_GetStateDiffClient, error) {
+	stream, err := c.cc.NewStream(ctx, &VM_ServiceDesc.Streams[1], "/vm.VM/GetStateDiff", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &vMGetStateDiffClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type VM_GetStateDiffClient interface {
+	Recv() (*GetStateDiffResponse, error)
+	grpc.ClientStream
+}
+
+type vMGetStateDiffClient struct {
+	grpc.ClientStream
+}
+
+func (x *vMGetStateDiffClient) Recv() (*GetStateDiffResponse, error) {
+	m := new(GetStateDiffResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *vMClient) VerifyMessage(ctx context.Context, in *VerifyMessageRequest, opts ...grpc.CallOption) (*VerifyMessageResponse, error) {
+	out := new(VerifyMessageResponse)
+	err := c.cc.Invoke(ctx, "/vm.VM/VerifyMessage", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *vMClient) BlockVerify(ctx context.Context, in *BlockVerifyRequest, opts ...grpc.CallOption) (*BlockVerifyResponse, error) {
 	out := new(BlockVerifyResponse)
 	err := c.cc.Invoke(ctx, "/vm.VM/BlockVerify", in, out, opts...)
@@ -383,6 +566,15 @@ func (c *vMClient) BlockVerify(ctx context.Context, in *BlockVerifyRequest, opts
 	return out, nil
 }
 
+func (c *vMClient) BlockVerifyWithContext(ctx context.Context, in *BlockVerifyWithContextRequest, opts ...grpc.CallOption) (*BlockVerifyResponse, error) {
+	out := new(BlockVerifyResponse)
+	err := c.cc.Invoke(ctx, "/vm.VM/BlockVerifyWithContext", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *vMClient) BlockAccept(ctx context.Context, in *BlockAcceptRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
 	out := new(emptypb.Empty)
 	err := c.cc.Invoke(ctx, "/vm.VM/BlockAccept", in, out, opts...)
@@ -401,6 +593,33 @@ func (c *vMClient) BlockReject(ctx context.Context, in *BlockRejectRequest, opts
 	return out, nil
 }
 
+func (c *vMClient) BatchedBlockAccept(ctx context.Context, in *BatchedBlockAcceptRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/vm.VM/BatchedBlockAccept", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *vMClient) BatchedBlockReject(ctx context.Context, in *BatchedBlockRejectRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/vm.VM/BatchedBlockReject", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *vMClient) BlockOptions(ctx context.Context, in *BlockOptionsRequest, opts ...grpc.CallOption) (*BlockOptionsResponse, error) {
+	out := new(BlockOptionsResponse)
+	err := c.cc.Invoke(ctx, "/vm.VM/BlockOptions", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *vMClient) StateSummaryAccept(ctx context.Context, in *StateSummaryAcceptRequest, opts ...grpc.CallOption) (*StateSummaryAcceptResponse, error) {
 	out := new(StateSummaryAcceptResponse)
 	err := c.cc.Invoke(ctx, "/vm.VM/StateSummaryAccept", in, out, opts...)
@@ -416,8 +635,17 @@ func (c *vMClient) StateSummaryAccept(ctx context.Context, in *StateSummaryAccep
 type VMServer interface {
 	// ChainVM
 	//
+	// Handshake exchanges the protocol versions of the node and the VM, and
+	// returns the capabilities of the VM. It is called before Initialize.
+	Handshake(context.Context, *HandshakeRequest) (*HandshakeResponse, error)
 	// Initialize this VM.
 	Initialize(context.Context, *InitializeRequest) (*InitializeResponse, error)
+	// SupportedFxs returns the IDs of the feature extensions the VM can be
+	// initialized with.
+	SupportedFxs(context.Context, *emptypb.Empty) (*SupportedFxsResponse, error)
+	// ConfigSchema returns the JSON schema of the chain configs of the VM. It
+	// may be called before Initialize.
+	ConfigSchema(context.Context, *emptypb.Empty) (*ConfigSchemaResponse, error)
 	// SetState communicates to VM its next state it starts
 	SetState(context.Context, *SetStateRequest) (*SetStateResponse, error)
 	// Shutdown is called when the node is shutting down.
@@ -435,6 +663,10 @@ type VMServer interface {
 	Disconnected(context.Context, *DisconnectedRequest) (*emptypb.Empty, error)
 	// Attempt to create a new block from data contained in the VM.
 	BuildBlock(context.Context, *emptypb.Empty) (*BuildBlockResponse, error)
+	// Attempt to create a new block given the P-Chain height provided by the
+	// proposervm. Only called if the plugin reports the block context
+	// capability.
+	BuildBlockWithContext(context.Context, *BuildBlockWithContextRequest) (*BuildBlockResponse, error)
 	// Attempt to create a block from a stream of bytes.
 	ParseBlock(context.Context, *ParseBlockRequest) (*ParseBlockResponse, error)
 	// Attempt to load a block.
@@ -443,6 +675,9 @@ type VMServer interface {
 	SetPreference(context.Context, *SetPreferenceRequest) (*emptypb.Empty, error)
 	// Attempt to verify the health of the VM.
 	Health(context.Context, *emptypb.Empty) (*HealthResponse, error)
+	// ConnectionHealth returns the health of the servers of the node, probed
+	// over the connections of the VM to them.
+	ConnectionHealth(context.Context, *emptypb.Empty) (*ConnectionHealthResponse, error)
 	// Version returns the version of the VM.
 	Version(context.Context, *emptypb.Empty) (*VersionResponse, error)
 	// Notify this engine of a request for data from [nodeID].
@@ -455,6 +690,11 @@ type VMServer interface {
 	AppResponse(context.Context, *AppResponseMsg) (*emptypb.Empty, error)
 	// Notify this engine of a gossip message from [nodeID].
 	AppGossip(context.Context, *AppGossipMsg) (*emptypb.Empty, error)
+	// Notify this engine of batches of gossip messages over a single stream.
+	// The first response acknowledges the stream, and each batch is then
+	// acknowledged once its messages have been delivered, one at a time, as by
+	// AppGossip.
+	AppGossipStream(VM_AppGossipStreamServer) error
 	// Attempts to gather metrics from a VM.
 	Gather(context.Context, *emptypb.Empty) (*GatherResponse, error)
 	CrossChainAppRequest(context.Context, *CrossChainAppRequestMsg) (*emptypb.Empty, error)
@@ -463,6 +703,7 @@ type VMServer interface {
 	// BatchedChainVM
 	GetAncestors(context.Context, *GetAncestorsRequest) (*GetAncestorsResponse, error)
 	BatchedParseBlock(context.Context, *BatchedParseBlockRequest) (*BatchedParseBlockResponse, error)
+	BatchedGetBlock(context.Context, *BatchedGetBlockRequest) (*BatchedGetBlockResponse, error)
 	// HeightIndexedChainVM
 	VerifyHeightIndex(context.Context, *emptypb.Empty) (*VerifyHeightIndexResponse, error)
 	GetBlockIDAtHeight(context.Context, *GetBlockIDAtHeightRequest) (*GetBlockIDAtHeightResponse, error)
@@ -479,10 +720,36 @@ type VMServer interface {
 	// GetStateSummary retrieves the state summary that was generated at height
 	// [summaryHeight].
 	GetStateSummary(context.Context, *GetStateSummaryRequest) (*GetStateSummaryResponse, error)
+	// Decoder
+	//
+	// DecodeBlock returns a JSON representation of a block without processing it.
+	DecodeBlock(context.Context, *DecodeBlockRequest) (*DecodeBlockResponse, error)
+	// StateDiffer
+	//
+	// GetStateDiff streams the keys whose values differ between the states at
+	// the accepted heights [start_height] and [end_height], in increasing key
+	// order. The last response holds the digest of the diff.
+	GetStateDiff(*GetStateDiffRequest, VM_GetStateDiffServer) error
+	// MessageVerifier
+	//
+	// VerifyMessage returns an error if the node shouldn't sign [message] with
+	// its BLS key.
+	VerifyMessage(context.Context, *VerifyMessageRequest) (*VerifyMessageResponse, error)
 	// Block
 	BlockVerify(context.Context, *BlockVerifyRequest) (*BlockVerifyResponse, error)
+	// Verify a block given the P-Chain height provided by the proposervm. Only
+	// called for the blocks whose responses set verify_with_context.
+	BlockVerifyWithContext(context.Context, *BlockVerifyWithContextRequest) (*BlockVerifyResponse, error)
 	BlockAccept(context.Context, *BlockAcceptRequest) (*emptypb.Empty, error)
 	BlockReject(context.Context, *BlockRejectRequest) (*emptypb.Empty, error)
+	// BatchedBlockAccept accepts the blocks [ids], in order.
+	BatchedBlockAccept(context.Context, *BatchedBlockAcceptRequest) (*emptypb.Empty, error)
+	// BatchedBlockReject rejects the blocks [ids], in order.
+	BatchedBlockReject(context.Context, *BatchedBlockRejectRequest) (*emptypb.Empty, error)
+	// BlockOptions returns the options of the oracle block [id], in the order
+	// the VM prefers them. Only called for the blocks whose responses set
+	// oracle.
+	BlockOptions(context.Context, *BlockOptionsRequest) (*BlockOptionsResponse, error)
 	// StateSummary
 	StateSummaryAccept(context.Context, *StateSummaryAcceptRequest) (*StateSummaryAcceptResponse, error)
 	mustEmbedUnimplementedVMServer()
@@ -492,9 +759,18 @@ type VMServer interface {
 type UnimplementedVMServer struct {
 }
 
+func (UnimplementedVMServer) Handshake(context.Context, *HandshakeRequest) (*HandshakeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Handshake not implemented")
+}
 func (UnimplementedVMServer) Initialize(context.Context, *InitializeRequest) (*InitializeResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Initialize not implemented")
 }
+func (UnimplementedVMServer) SupportedFxs(context.Context, *emptypb.Empty) (*SupportedFxsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SupportedFxs not implemented")
+}
+func (UnimplementedVMServer) ConfigSchema(context.Context, *emptypb.Empty) (*ConfigSchemaResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ConfigSchema not implemented")
+}
 func (UnimplementedVMServer) SetState(context.Context, *SetStateRequest) (*SetStateResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method SetState not implemented")
 }
@@ -516,6 +792,9 @@ func (UnimplementedVMServer) Disconnected(context.Context, *DisconnectedRequest)
 func (UnimplementedVMServer) BuildBlock(context.Context, *emptypb.Empty) (*BuildBlockResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method BuildBlock not implemented")
 }
+func (UnimplementedVMServer) BuildBlockWithContext(context.Context, *BuildBlockWithContextRequest) (*BuildBlockResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BuildBlockWithContext not implemented")
+}
 func (UnimplementedVMServer) ParseBlock(context.Context, *ParseBlockRequest) (*ParseBlockResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ParseBlock not implemented")
 }
@@ -528,6 +807,9 @@ func (UnimplementedVMServer) SetPreference(context.Context, *SetPreferenceReques
 func (UnimplementedVMServer) Health(context.Context, *emptypb.Empty) (*HealthResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Health not implemented")
 }
+func (UnimplementedVMServer) ConnectionHealth(context.Context, *emptypb.Empty) (*ConnectionHealthResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ConnectionHealth not implemented")
+}
 func (UnimplementedVMServer) Version(context.Context, *emptypb.Empty) (*VersionResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Version not implemented")
 }
@@ -543,6 +825,9 @@ func (UnimplementedVMServer) AppResponse(context.Context, *AppResponseMsg) (*emp
 func (UnimplementedVMServer) AppGossip(context.Context, *AppGossipMsg) (*emptypb.Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method AppGossip not implemented")
 }
+func (UnimplementedVMServer) AppGossipStream(VM_AppGossipStreamServer) error {
+	return status.Errorf(codes.Unimplemented, "method AppGossipStream not implemented")
+}
 func (UnimplementedVMServer) Gather(context.Context, *emptypb.Empty) (*GatherResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Gather not implemented")
 }
@@ -561,6 +846,9 @@ func (UnimplementedVMServer) GetAncestors(context.Context, *GetAncestorsRequest)
 func (UnimplementedVMServer) BatchedParseBlock(context.Context, *BatchedParseBlockRequest) (*BatchedParseBlockResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method BatchedParseBlock not implemented")
 }
+func (UnimplementedVMServer) BatchedGetBlock(context.Context, *BatchedGetBlockRequest) (*BatchedGetBlockResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchedGetBlock not implemented")
+}
 func (UnimplementedVMServer) VerifyHeightIndex(context.Context, *emptypb.Empty) (*VerifyHeightIndexResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method VerifyHeightIndex not implemented")
 }
@@ -582,15 +870,36 @@ func (UnimplementedVMServer) ParseStateSummary(context.Context, *ParseStateSumma
 func (UnimplementedVMServer) GetStateSummary(context.Context, *GetStateSummaryRequest) (*GetStateSummaryResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetStateSummary not implemented")
 }
+func (UnimplementedVMServer) DecodeBlock(context.Context, *DecodeBlockRequest) (*DecodeBlockResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DecodeBlock not implemented")
+}
+func (UnimplementedVMServer) GetStateDiff(*GetStateDiffRequest, VM_GetStateDiffServer) error {
+	return status.Errorf(codes.Unimplemented, "method GetStateDiff not implemented")
+}
+func (UnimplementedVMServer) VerifyMessage(context.Context, *VerifyMessageRequest) (*VerifyMessageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method VerifyMessage not implemented")
+}
 func (UnimplementedVMServer) BlockVerify(context.Context, *BlockVerifyRequest) (*BlockVerifyResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method BlockVerify not implemented")
 }
+func (UnimplementedVMServer) BlockVerifyWithContext(context.Context, *BlockVerifyWithContextRequest) (*BlockVerifyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BlockVerifyWithContext not implemented")
+}
 func (UnimplementedVMServer) BlockAccept(context.Context, *BlockAcceptRequest) (*emptypb.Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method BlockAccept not implemented")
 }
 func (UnimplementedVMServer) BlockReject(context.Context, *BlockRejectRequest) (*emptypb.Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method BlockReject not implemented")
 }
+func (UnimplementedVMServer) BatchedBlockAccept(context.Context, *BatchedBlockAcceptRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchedBlockAccept not implemented")
+}
+func (UnimplementedVMServer) BatchedBlockReject(context.Context, *BatchedBlockRejectRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchedBlockReject not implemented")
+}
+func (UnimplementedVMServer) BlockOptions(context.Context, *BlockOptionsRequest) (*BlockOptionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BlockOptions not implemented")
+}
 func (UnimplementedVMServer) StateSummaryAccept(context.Context, *StateSummaryAcceptRequest) (*StateSummaryAcceptResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method StateSummaryAccept not implemented")
 }
@@ -607,6 +916,24 @@ func RegisterVMServer(s grpc.ServiceRegistrar, srv VMServer) {
 	s.RegisterService(&VM_ServiceDesc, srv)
 }
 
+func _VM_Handshake_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HandshakeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VMServer).Handshake(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/vm.VM/Handshake",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VMServer).Handshake(ctx, req.(*HandshakeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _VM_Initialize_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(InitializeRequest)
 	if err := dec(in); err != nil {
@@ -625,6 +952,42 @@ func _VM_Initialize_Handler(srv interface{}, ctx context.Context, dec func(inter
 	return interceptor(ctx, in, info, handler)
 }
 
+func _VM_SupportedFxs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VMServer).SupportedFxs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/vm.VM/SupportedFxs",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VMServer).SupportedFxs(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VM_ConfigSchema_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VMServer).ConfigSchema(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/vm.VM/ConfigSchema",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VMServer).ConfigSchema(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _VM_SetState_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(SetStateRequest)
 	if err := dec(in); err != nil {
@@ -751,6 +1114,24 @@ func _VM_BuildBlock_Handler(srv interface{}, ctx context.Context, dec func(inter
 	return interceptor(ctx, in, info, handler)
 }
 
+func _VM_BuildBlockWithContext_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BuildBlockWithContextRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VMServer).BuildBlockWithContext(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/vm.VM/BuildBlockWithContext",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VMServer).BuildBlockWithContext(ctx, req.(*BuildBlockWithContextRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _VM_ParseBlock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ParseBlockRequest)
 	if err := dec(in); err != nil {
@@ -823,6 +1204,24 @@ func _VM_Health_Handler(srv interface{}, ctx context.Context, dec func(interface
 	return interceptor(ctx, in, info, handler)
 }
 
+func _VM_ConnectionHealth_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VMServer).ConnectionHealth(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/vm.VM/ConnectionHealth",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VMServer).ConnectionHealth(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _VM_Version_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(emptypb.Empty)
 	if err := dec(in); err != nil {
@@ -913,6 +1312,32 @@ func _VM_AppGossip_Handler(srv interface{}, ctx context.Context, dec func(interf
 	return interceptor(ctx, in, info, handler)
 }
 
+func _VM_AppGossipStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(VMServer).AppGossipStream(&vMAppGossipStreamServer{stream})
+}
+
+type VM_AppGossipStreamServer interface {
+	Send(*AppGossipBatchAck) error
+	Recv() (*AppGossipBatch, error)
+	grpc.ServerStream
+}
+
+type vMAppGossipStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *vMAppGossipStreamServer) Send(m *AppGossipBatchAck) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *vMAppGossipStreamServer) Recv() (*AppGossipBatch, error) {
+	m := new(AppGossipBatch)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func _VM_Gather_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(emptypb.Empty)
 	if err := dec(in); err != nil {
@@ -1021,6 +1446,24 @@ func _VM_BatchedParseBlock_Handler(srv interface{}, ctx context.Context, dec fun
 	return interceptor(ctx, in, info, handler)
 }
 
+func _VM_BatchedGetBlock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchedGetBlockRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VMServer).BatchedGetBlock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/vm.VM/BatchedGetBlock",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VMServer).BatchedGetBlock(ctx, req.(*BatchedGetBlockRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _VM_VerifyHeightIndex_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(emptypb.Empty)
 	if err := dec(in); err != nil {
@@ -1147,6 +1590,63 @@ func _VM_GetStateSummary_Handler(srv interface{}, ctx context.Context, dec func(
 	return interceptor(ctx, in, info, handler)
 }
 
+func _VM_DecodeBlock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DecodeBlockRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VMServer).DecodeBlock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/vm.VM/DecodeBlock",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VMServer).DecodeBlock(ctx, req.(*DecodeBlockRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VM_GetStateDiff_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(GetStateDiffRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(VMServer).GetStateDiff(m, &vMGetStateDiffServer{stream})
+}
+
+type VM_GetStateDiffServer interface {
+	Send(*GetStateDiffResponse) error
+	grpc.ServerStream
+}
+
+type vMGetStateDiffServer struct {
+	grpc.ServerStream
+}
+
+func (x *vMGetStateDiffServer) Send(m *GetStateDiffResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _VM_VerifyMessage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VerifyMessageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VMServer).VerifyMessage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/vm.VM/VerifyMessage",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VMServer).VerifyMessage(ctx, req.(*VerifyMessageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _VM_BlockVerify_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(BlockVerifyRequest)
 	if err := dec(in); err != nil {
@@ -1165,6 +1665,24 @@ func _VM_BlockVerify_Handler(srv interface{}, ctx context.Context, dec func(inte
 	return interceptor(ctx, in, info, handler)
 }
 
+func _VM_BlockVerifyWithContext_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BlockVerifyWithContextRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VMServer).BlockVerifyWithContext(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/vm.VM/BlockVerifyWithContext",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VMServer).BlockVerifyWithContext(ctx, req.(*BlockVerifyWithContextRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _VM_BlockAccept_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(BlockAcceptRequest)
 	if err := dec(in); err != nil {
@@ -1201,6 +1719,60 @@ func _VM_BlockReject_Handler(srv interface{}, ctx context.Context, dec func(inte
 	return interceptor(ctx, in, info, handler)
 }
 
+func _VM_BatchedBlockAccept_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchedBlockAcceptRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VMServer).BatchedBlockAccept(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/vm.VM/BatchedBlockAccept",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VMServer).BatchedBlockAccept(ctx, req.(*BatchedBlockAcceptRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VM_BatchedBlockReject_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchedBlockRejectRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VMServer).BatchedBlockReject(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/vm.VM/BatchedBlockReject",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VMServer).BatchedBlockReject(ctx, req.(*BatchedBlockRejectRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VM_BlockOptions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BlockOptionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VMServer).BlockOptions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/vm.VM/BlockOptions",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VMServer).BlockOptions(ctx, req.(*BlockOptionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _VM_StateSummaryAccept_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(StateSummaryAcceptRequest)
 	if err := dec(in); err != nil {
@@ -1226,10 +1798,22 @@ var VM_ServiceDesc = grpc.ServiceDesc{
 	ServiceName: "vm.VM",
 	HandlerType: (*VMServer)(nil),
 	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Handshake",
+			Handler:    _VM_Handshake_Handler,
+		},
 		{
 			MethodName: "Initialize",
 			Handler:    _VM_Initialize_Handler,
 		},
+		{
+			MethodName: "SupportedFxs",
+			Handler:    _VM_SupportedFxs_Handler,
+		},
+		{
+			MethodName: "ConfigSchema",
+			Handler:    _VM_ConfigSchema_Handler,
+		},
 		{
 			MethodName: "SetState",
 			Handler:    _VM_SetState_Handler,
@@ -1258,6 +1842,10 @@ var VM_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "BuildBlock",
 			Handler:    _VM_BuildBlock_Handler,
 		},
+		{
+			MethodName: "BuildBlockWithContext",
+			Handler:    _VM_BuildBlockWithContext_Handler,
+		},
 		{
 			MethodName: "ParseBlock",
 			Handler:    _VM_ParseBlock_Handler,
@@ -1274,6 +1862,10 @@ var VM_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Health",
 			Handler:    _VM_Health_Handler,
 		},
+		{
+			MethodName: "ConnectionHealth",
+			Handler:    _VM_ConnectionHealth_Handler,
+		},
 		{
 			MethodName: "Version",
 			Handler:    _VM_Version_Handler,
@@ -1318,6 +1910,10 @@ var VM_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "BatchedParseBlock",
 			Handler:    _VM_BatchedParseBlock_Handler,
 		},
+		{
+			MethodName: "BatchedGetBlock",
+			Handler:    _VM_BatchedGetBlock_Handler,
+		},
 		{
 			MethodName: "VerifyHeightIndex",
 			Handler:    _VM_VerifyHeightIndex_Handler,
@@ -1346,10 +1942,22 @@ var VM_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetStateSummary",
 			Handler:    _VM_GetStateSummary_Handler,
 		},
+		{
+			MethodName: "DecodeBlock",
+			Handler:    _VM_DecodeBlock_Handler,
+		},
+		{
+			MethodName: "VerifyMessage",
+			Handler:    _VM_VerifyMessage_Handler,
+		},
 		{
 			MethodName: "BlockVerify",
 			Handler:    _VM_BlockVerify_Handler,
 		},
+		{
+			MethodName: "BlockVerifyWithContext",
+			Handler:    _VM_BlockVerifyWithContext_Handler,
+		},
 		{
 			MethodName: "BlockAccept",
 			Handler:    _VM_BlockAccept_Handler,
@@ -1358,11 +1966,35 @@ var VM_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "BlockReject",
 			Handler:    _VM_BlockReject_Handler,
 		},
+		{
+			MethodName: "BatchedBlockAccept",
+			Handler:    _VM_BatchedBlockAccept_Handler,
+		},
+		{
+			MethodName: "BatchedBlockReject",
+			Handler:    _VM_BatchedBlockReject_Handler,
+		},
+		{
+			MethodName: "BlockOptions",
+			Handler:    _VM_BlockOptions_Handler,
+		},
 		{
 			MethodName: "StateSummaryAccept",
 			Handler:    _VM_StateSummaryAccept_Handler,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "AppGossipStream",
+			Handler:       _VM_AppGossipStream_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "GetStateDiff",
+			Handler:       _VM_GetStateDiff_Handler,
+			ServerStreams: true,
+		},
+	},
 	Metadata: "vm/vm.proto",
 }
@@ -0,0 +1,30 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package snow
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+// BlobService is the node service that stores large, content-addressed blobs,
+// such as state sync snapshots or upgrade bundles, for a chain's VM and
+// transfers them between the nodes of the chain.
+//
+// Blobs are transferred in chunks that are persisted as they're received, so
+// a transfer that failed is resumed by fetching the blob again. The bandwidth
+// the node uses to transfer blobs is capped by the node's configuration.
+type BlobService interface {
+	// Put stores [blob], so that the other nodes can fetch it, and returns
+	// its ID.
+	Put(ctx context.Context, blob []byte) (ids.ID, error)
+	// Get returns the blob [blobID], or database.ErrNotFound if it isn't
+	// stored.
+	Get(ctx context.Context, blobID ids.ID) ([]byte, error)
+	// Fetch downloads the blob [blobID] from [peers], stores it and returns
+	// it. The chunks of the blob that are already stored aren't downloaded
+	// again.
+	Fetch(ctx context.Context, blobID ids.ID, peers []ids.NodeID) ([]byte, error)
+}
@@ -50,6 +50,18 @@ func (p *earlyTermNoTraversalPoll) Drop(vdr ids.NodeID) {
 	p.polled.Remove(vdr)
 }
 
+// Substitute moves one of the outstanding votes of [vdr] to [backup]
+func (p *earlyTermNoTraversalPoll) Substitute(vdr, backup ids.NodeID) bool {
+	count := p.polled.Count(vdr)
+	if count == 0 {
+		return false
+	}
+	p.polled.Remove(vdr)
+	p.polled.AddCount(vdr, count-1)
+	p.polled.Add(backup)
+	return true
+}
+
 // Finished returns true when all validators have voted
 func (p *earlyTermNoTraversalPoll) Finished() bool {
 	remaining := p.polled.Len()
@@ -17,7 +17,13 @@ type Set interface {
 	Add(requestID uint32, vdrs ids.NodeIDBag) bool
 	Vote(requestID uint32, vdr ids.NodeID, vote ids.ID) []ids.Bag
 	Drop(requestID uint32, vdr ids.NodeID) []ids.Bag
+	// Substitute moves one of the outstanding votes of [vdr] in the poll
+	// [requestID] to [backup]. Returns false if [vdr] has no outstanding vote.
+	Substitute(requestID uint32, vdr, backup ids.NodeID) bool
+	Finished(requestID uint32) bool
 	Len() int
+	// Clear drops every outstanding poll
+	Clear()
 }
 
 // Poll is an outstanding poll
@@ -26,6 +32,7 @@ type Poll interface {
 
 	Vote(vdr ids.NodeID, vote ids.ID)
 	Drop(vdr ids.NodeID)
+	Substitute(vdr, backup ids.NodeID) bool
 	Finished() bool
 	Result() ids.Bag
 }
@@ -197,11 +197,41 @@ func (s *set) Drop(requestID uint32, vdr ids.NodeID) []ids.Bag {
 	return s.processFinishedPolls()
 }
 
+// Substitute moves one of the outstanding votes of [vdr] in the poll
+// [requestID] to [backup], so that [backup] answers for it.
+func (s *set) Substitute(requestID uint32, vdr, backup ids.NodeID) bool {
+	holder, exists := s.polls.Get(requestID)
+	if !exists {
+		return false
+	}
+
+	s.log.Verbo("substituting validator",
+		zap.Stringer("validator", vdr),
+		zap.Stringer("backup", backup),
+		zap.Uint32("requestID", requestID),
+	)
+	return holder.GetPoll().Substitute(vdr, backup)
+}
+
+// Finished returns true if the poll [requestID] doesn't need more votes. A poll
+// that finished early may be kept until the polls issued before it finish.
+func (s *set) Finished(requestID uint32) bool {
+	holder, exists := s.polls.Get(requestID)
+	return !exists || holder.GetPoll().Finished()
+}
+
 // Len returns the number of outstanding polls
 func (s *set) Len() int {
 	return s.polls.Len()
 }
 
+// Clear drops every outstanding poll, so that the votes sent in response to
+// them are dropped.
+func (s *set) Clear() {
+	s.numPolls.Sub(float64(s.polls.Len()))
+	s.polls = linkedhashmap.New[uint32, pollHolder]()
+}
+
 func (s *set) String() string {
 	sb := strings.Builder{}
 	sb.WriteString(fmt.Sprintf("current polls: (Size = %d)", s.polls.Len()))
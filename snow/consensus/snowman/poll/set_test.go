@@ -215,6 +215,94 @@ func TestCreateAndFinishPollOutOfOrder_UnfinishedPollsGaps(t *testing.T) {
 	require.Equal(t, vtx3, results[2].List()[0])
 }
 
+func TestSetFinished(t *testing.T) {
+	require := require.New(t)
+
+	factory := NewNoEarlyTermFactory()
+	log := logging.NoLog{}
+	namespace := ""
+	registerer := prometheus.NewRegistry()
+	s := NewSet(factory, log, namespace, registerer)
+
+	vdr1 := ids.NodeID{1}
+	vdr2 := ids.NodeID{2}
+	vdrBag := ids.NodeIDBag{}
+	vdrBag.Add(vdr1, vdr2)
+	require.True(s.Add(1, vdrBag))
+	require.True(s.Add(2, vdrBag))
+
+	vtxID := ids.ID{1}
+	require.Empty(s.Vote(2, vdr1, vtxID))
+	require.Empty(s.Vote(2, vdr2, vtxID))
+
+	// Poll 2 is kept until poll 1 finishes, but it doesn't need more votes.
+	require.Equal(2, s.Len())
+	require.False(s.Finished(1))
+	require.True(s.Finished(2))
+	require.True(s.Finished(3))
+}
+
+func TestSetSubstitute(t *testing.T) {
+	require := require.New(t)
+
+	factory := NewNoEarlyTermFactory()
+	log := logging.NoLog{}
+	namespace := ""
+	registerer := prometheus.NewRegistry()
+	s := NewSet(factory, log, namespace, registerer)
+
+	vdr1 := ids.NodeID{1}
+	vdr2 := ids.NodeID{2}
+	backup := ids.NodeID{3}
+	vdrBag := ids.NodeIDBag{}
+	vdrBag.Add(vdr1, vdr2, vdr2)
+	require.True(s.Add(1, vdrBag))
+
+	// The backup answers for one of the votes of vdr2, under its own ID.
+	require.True(s.Substitute(1, vdr2, backup))
+	require.False(s.Substitute(1, ids.NodeID{4}, vdr1))
+	require.False(s.Substitute(2, vdr2, backup))
+
+	vtxID := ids.ID{1}
+	require.Empty(s.Vote(1, vdr1, vtxID))
+	require.Empty(s.Vote(1, backup, vtxID))
+	results := s.Vote(1, vdr2, vtxID)
+	require.Len(results, 1)
+	require.Equal(3, results[0].Count(vtxID))
+
+	// Substitutes can't answer more than the votes they were given.
+	vdrBag = ids.NodeIDBag{}
+	vdrBag.Add(vdr1, vdr2, vdr2)
+	require.True(s.Add(2, vdrBag))
+	require.True(s.Substitute(2, vdr1, backup))
+	require.False(s.Substitute(2, vdr1, backup))
+	require.Empty(s.Vote(2, vdr1, vtxID))
+	require.Empty(s.Vote(2, vdr2, vtxID))
+	results = s.Vote(2, backup, vtxID)
+	require.Len(results, 1)
+	require.Equal(3, results[0].Count(vtxID))
+}
+
+func TestSetClear(t *testing.T) {
+	require := require.New(t)
+
+	factory := NewNoEarlyTermFactory()
+	log := logging.NoLog{}
+	namespace := ""
+	registerer := prometheus.NewRegistry()
+	s := NewSet(factory, log, namespace, registerer)
+
+	vdr1 := ids.NodeID{1}
+	vdrBag := ids.NodeIDBag{}
+	vdrBag.Add(vdr1)
+	require.True(s.Add(1, vdrBag))
+
+	s.Clear()
+	require.Zero(s.Len())
+	// The votes of the dropped poll are ignored.
+	require.Empty(s.Vote(1, vdr1, ids.ID{1}))
+}
+
 func TestCreateAndFinishSuccessfulPoll(t *testing.T) {
 	factory := NewNoEarlyTermFactory()
 	log := logging.NoLog{}
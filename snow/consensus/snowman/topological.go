@@ -102,29 +102,39 @@ func (ts *Topological) Initialize(ctx *snow.ConsensusContext, params snowball.Pa
 		return err
 	}
 
-	latencyMetrics, err := metrics.NewLatency("blks", "block(s)", ctx.Log, "", ctx.Registerer)
-	if err != nil {
-		return err
-	}
-	ts.Latency = latencyMetrics
+	if ts.Latency == nil {
+		latencyMetrics, err := metrics.NewLatency("blks", "block(s)", ctx.Log, "", ctx.Registerer)
+		if err != nil {
+			return err
+		}
+		ts.Latency = latencyMetrics
 
-	pollsMetrics, err := metrics.NewPolls("", ctx.Registerer)
-	if err != nil {
-		return err
-	}
-	ts.Polls = pollsMetrics
+		pollsMetrics, err := metrics.NewPolls("", ctx.Registerer)
+		if err != nil {
+			return err
+		}
+		ts.Polls = pollsMetrics
 
-	heightMetrics, err := metrics.NewHeight("", ctx.Registerer)
-	if err != nil {
-		return err
-	}
-	ts.Height = heightMetrics
+		heightMetrics, err := metrics.NewHeight("", ctx.Registerer)
+		if err != nil {
+			return err
+		}
+		ts.Height = heightMetrics
 
-	timestampMetrics, err := metrics.NewTimestamp("", ctx.Registerer)
-	if err != nil {
-		return err
+		timestampMetrics, err := metrics.NewTimestamp("", ctx.Registerer)
+		if err != nil {
+			return err
+		}
+		ts.Timestamp = timestampMetrics
+	} else {
+		// Consensus is being restarted, so the metrics are kept and the blocks
+		// that were processing are dropped.
+		for blkID, blk := range ts.blocks {
+			if blk.blk != nil && blkID != ts.head {
+				ts.Latency.Rejected(blkID, ts.pollNumber, len(blk.blk.Bytes()))
+			}
+		}
 	}
-	ts.Timestamp = timestampMetrics
 
 	ts.leaves = ids.Set{}
 	ts.kahnNodes = make(map[ids.ID]kahnNode)
@@ -52,11 +52,21 @@ type Context struct {
 	SNLookup     SubnetLookup
 	Metrics      metrics.OptionalGatherer
 
+	// ChainDataDir is a directory this chain may use to persist data outside
+	// of the provided databases.
+	ChainDataDir string
+
 	// snowman++ attributes
 	ValidatorState    validators.State  // interface for P-Chain validators
 	StakingLeafSigner crypto.Signer     // block signer
 	StakingCertLeaf   *x509.Certificate // block certificate
 	StakingBLSKey     *bls.SecretKey    // bls signer
+
+	// SignatureAggregator collects the signatures of the subnet's validators
+	// over messages of this chain.
+	SignatureAggregator SignatureAggregator
+	// Blobs stores the blobs of this chain and transfers them between nodes.
+	Blobs BlobService
 }
 
 // Expose gatherer interface for unit testing.
@@ -264,6 +264,10 @@ func (b *bootstrapper) GetAncestorsFailed(ctx context.Context, nodeID ids.NodeID
 	return b.fetch(ctx, vtxID)
 }
 
+func (b *bootstrapper) AppRequestFailedWithError(ctx context.Context, nodeID ids.NodeID, requestID uint32, appErr *common.AppError) error {
+	return common.NotifyAppRequestFailed(ctx, b.AppHandler, nodeID, requestID, appErr)
+}
+
 func (b *bootstrapper) Connected(
 	ctx context.Context,
 	nodeID ids.NodeID,
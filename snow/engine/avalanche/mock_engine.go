@@ -141,6 +141,20 @@ func (mr *MockEngineMockRecorder) AppRequestFailed(arg0, arg1, arg2 interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AppRequestFailed", reflect.TypeOf((*MockEngine)(nil).AppRequestFailed), arg0, arg1, arg2)
 }
 
+// AppRequestFailedWithError mocks base method.
+func (m *MockEngine) AppRequestFailedWithError(arg0 context.Context, arg1 ids.NodeID, arg2 uint32, arg3 *common.AppError) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AppRequestFailedWithError", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AppRequestFailedWithError indicates an expected call of AppRequestFailedWithError.
+func (mr *MockEngineMockRecorder) AppRequestFailedWithError(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AppRequestFailedWithError", reflect.TypeOf((*MockEngine)(nil).AppRequestFailedWithError), arg0, arg1, arg2, arg3)
+}
+
 // AppResponse mocks base method.
 func (m *MockEngine) AppResponse(arg0 context.Context, arg1 ids.NodeID, arg2 uint32, arg3 []byte) error {
 	m.ctrl.T.Helper()
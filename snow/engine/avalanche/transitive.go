@@ -258,6 +258,11 @@ func (t *Transitive) AppRequestFailed(ctx context.Context, nodeID ids.NodeID, re
 	return t.VM.AppRequestFailed(ctx, nodeID, requestID)
 }
 
+func (t *Transitive) AppRequestFailedWithError(ctx context.Context, nodeID ids.NodeID, requestID uint32, appErr *common.AppError) error {
+	// Notify the VM that a request it made failed, and why
+	return common.NotifyAppRequestFailed(ctx, t.VM, nodeID, requestID, appErr)
+}
+
 func (t *Transitive) AppResponse(ctx context.Context, nodeID ids.NodeID, requestID uint32, response []byte) error {
 	// Notify the VM of a response to its request
 	return t.VM.AppResponse(ctx, nodeID, requestID, response)
@@ -0,0 +1,22 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vertex
+
+import (
+	"context"
+	"errors"
+)
+
+var ErrDecoderNotImplemented = errors.New("vm does not implement Decoder interface")
+
+// Decoder describes the contents of transactions without processing them. It
+// is intended for debugging and audit tooling.
+type Decoder interface {
+	// DecodeTx returns a JSON representation of the transaction encoded in
+	// [txBytes].
+	//
+	// The transaction must not be verified, persisted, or otherwise processed
+	// by the VM.
+	DecodeTx(ctx context.Context, txBytes []byte) ([]byte, error)
+}
@@ -78,6 +78,19 @@ func (c *Client) SendAppResponse(ctx context.Context, nodeID ids.NodeID, request
 	return err
 }
 
+func (c *Client) SendAppError(ctx context.Context, nodeID ids.NodeID, requestID uint32, errorCode int32, errorMessage string) error {
+	_, err := c.client.SendAppError(
+		ctx,
+		&appsenderpb.SendAppErrorMsg{
+			NodeId:       nodeID[:],
+			RequestId:    requestID,
+			ErrorCode:    errorCode,
+			ErrorMessage: errorMessage,
+		},
+	)
+	return err
+}
+
 func (c *Client) SendAppGossip(ctx context.Context, msg []byte) error {
 	_, err := c.client.SendAppGossip(
 		ctx,
@@ -66,6 +66,15 @@ func (s *Server) SendAppResponse(ctx context.Context, req *appsenderpb.SendAppRe
 	return &emptypb.Empty{}, err
 }
 
+func (s *Server) SendAppError(ctx context.Context, req *appsenderpb.SendAppErrorMsg) (*emptypb.Empty, error) {
+	nodeID, err := ids.ToNodeID(req.NodeId)
+	if err != nil {
+		return nil, err
+	}
+	err = s.appSender.SendAppError(ctx, nodeID, req.RequestId, req.ErrorCode, req.ErrorMessage)
+	return &emptypb.Empty{}, err
+}
+
 func (s *Server) SendAppGossip(ctx context.Context, req *appsenderpb.SendAppGossipMsg) (*emptypb.Empty, error) {
 	err := s.appSender.SendAppGossip(ctx, req.Msg)
 	return &emptypb.Empty{}, err
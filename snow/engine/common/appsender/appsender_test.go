@@ -0,0 +1,69 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package appsender
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/vms/rpcchainvm/grpcutils"
+
+	appsenderpb "github.com/ava-labs/avalanchego/proto/pb/appsender"
+)
+
+const bufSize = 1024 * 1024
+
+func TestSendAppGossipSpecific(t *testing.T) {
+	require := require.New(t)
+
+	expectedNodeIDs := ids.NewNodeIDSet(2)
+	expectedNodeIDs.Add(ids.GenerateTestNodeID(), ids.GenerateTestNodeID())
+	expectedMsg := []byte("gossip")
+
+	called := false
+	sender := &common.SenderTest{
+		T: t,
+		SendAppGossipSpecificF: func(_ context.Context, nodeIDs ids.NodeIDSet, msg []byte) error {
+			called = true
+			require.Equal(expectedNodeIDs, nodeIDs)
+			require.Equal(expectedMsg, msg)
+			return nil
+		},
+	}
+
+	listener := bufconn.Listen(bufSize)
+	serverCloser := grpcutils.ServerCloser{}
+	defer serverCloser.Stop()
+
+	serverFunc := func(opts []grpc.ServerOption) *grpc.Server {
+		server := grpc.NewServer(opts...)
+		appsenderpb.RegisterAppSenderServer(server, NewServer(sender))
+		serverCloser.Add(server)
+		return server
+	}
+	go grpcutils.Serve(listener, serverFunc)
+
+	dialer := grpc.WithContextDialer(
+		func(context.Context, string) (net.Conn, error) {
+			return listener.Dial()
+		},
+	)
+	dopts := grpcutils.DefaultDialOptions
+	dopts = append(dopts, dialer)
+	conn, err := grpcutils.Dial("", dopts...)
+	require.NoError(err)
+	defer conn.Close()
+
+	client := NewClient(appsenderpb.NewAppSenderClient(conn))
+	require.NoError(client.SendAppGossipSpecific(context.Background(), expectedNodeIDs, expectedMsg))
+	require.True(called)
+}
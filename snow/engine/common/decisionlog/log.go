@@ -0,0 +1,220 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package decisionlog
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/perms"
+)
+
+const (
+	// A log is split into this many segments, which are deleted oldest first
+	// once the log exceeds its max size.
+	numSegments = 8
+
+	segmentExtension = ".log"
+)
+
+const (
+	// PollIssued is recorded when a query is sent to a sample of validators.
+	PollIssued Kind = "pollIssued"
+	// VoteReceived is recorded when a validator answers a query.
+	VoteReceived Kind = "voteReceived"
+	// QueryFailed is recorded when a validator didn't answer a query.
+	QueryFailed Kind = "queryFailed"
+	// Accepted is recorded when a block is accepted.
+	Accepted Kind = "accepted"
+	// Rejected is recorded when a block is rejected.
+	Rejected Kind = "rejected"
+)
+
+var (
+	_ Log = (*log)(nil)
+	_ Log = noLog{}
+)
+
+// Kind of a decision.
+type Kind string
+
+// Entry is a decision of the consensus engine of a chain.
+type Entry struct {
+	Kind      Kind      `json:"kind"`
+	Timestamp time.Time `json:"timestamp"`
+	// Request of the poll the entry is about
+	RequestID uint32 `json:"requestID,omitempty"`
+	// Validator that answered a query
+	NodeID ids.NodeID `json:"nodeID"`
+	// Validators a query was sent to
+	NodeIDs []ids.NodeID `json:"nodeIDs,omitempty"`
+	// Block that is queried, voted for, or decided
+	BlkID  ids.ID `json:"blkID"`
+	Height uint64 `json:"height,omitempty"`
+}
+
+// Log is an append-only log of decisions.
+type Log interface {
+	// Record [entry], timestamped with the current time.
+	Record(entry Entry)
+
+	// Close the log. The log must not be used after it is closed.
+	Close() error
+}
+
+type log struct {
+	dir            string
+	maxSegmentSize int64
+
+	lock sync.Mutex
+	// Sequence numbers of the segments, from the oldest to the current one
+	segments    []uint64
+	segment     *os.File
+	segmentSize int64
+	// First error the log ran into. Once set, no entry is recorded.
+	err error
+}
+
+// New returns a log that writes to [dir], and deletes the oldest entries once
+// it takes more than about [maxSize] bytes. Entries recorded by previous
+// processes are kept, and appended to.
+func New(dir string, maxSize uint64) (Log, error) {
+	if err := os.MkdirAll(dir, perms.ReadWriteExecute); err != nil {
+		return nil, err
+	}
+	segments, err := listSegments(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	maxSegmentSize := int64(maxSize / numSegments)
+	if maxSegmentSize < 1 {
+		maxSegmentSize = 1
+	}
+	l := &log{
+		dir:            dir,
+		maxSegmentSize: maxSegmentSize,
+		segments:       segments,
+	}
+	// Each process starts a new segment, so that an entry partially written by
+	// a crashed process is never followed by more entries.
+	return l, l.rotate()
+}
+
+func (l *log) Record(entry Entry) {
+	entry.Timestamp = time.Now()
+	entryBytes, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	entryBytes = append(entryBytes, '\n')
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if l.err != nil {
+		return
+	}
+	if l.segmentSize >= l.maxSegmentSize {
+		if l.err = l.rotate(); l.err != nil {
+			return
+		}
+	}
+	n, err := l.segment.Write(entryBytes)
+	l.segmentSize += int64(n)
+	l.err = err
+}
+
+func (l *log) Close() error {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if l.segment == nil {
+		return nil
+	}
+	err := l.segment.Close()
+	l.segment = nil
+	l.err = os.ErrClosed
+	return err
+}
+
+// rotate closes the current segment, if any, deletes the oldest segments
+// beyond the retention, and opens a new segment.
+func (l *log) rotate() error {
+	if l.segment != nil {
+		if err := l.segment.Close(); err != nil {
+			return err
+		}
+	}
+
+	var seq uint64
+	if len(l.segments) > 0 {
+		seq = l.segments[len(l.segments)-1] + 1
+	}
+	l.segments = append(l.segments, seq)
+	for len(l.segments) > numSegments {
+		if err := os.Remove(segmentPath(l.dir, l.segments[0])); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		l.segments = l.segments[1:]
+	}
+
+	segment, err := perms.Create(segmentPath(l.dir, seq), perms.ReadWrite)
+	if err != nil {
+		return err
+	}
+	l.segment = segment
+	l.segmentSize = 0
+	return nil
+}
+
+// listSegments returns the sequence numbers of the segments in [dir], from
+// the oldest to the most recent.
+func listSegments(dir string) ([]uint64, error) {
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var segments []uint64
+	for _, file := range files {
+		name := file.Name()
+		if file.IsDir() || !strings.HasSuffix(name, segmentExtension) {
+			continue
+		}
+		seq, err := strconv.ParseUint(strings.TrimSuffix(name, segmentExtension), 10, 64)
+		if err != nil {
+			continue
+		}
+		segments = append(segments, seq)
+	}
+	sort.Slice(segments, func(i, j int) bool {
+		return segments[i] < segments[j]
+	})
+	return segments, nil
+}
+
+func segmentPath(dir string, seq uint64) string {
+	return filepath.Join(dir, fmt.Sprintf("%020d%s", seq, segmentExtension))
+}
+
+type noLog struct{}
+
+// NewNoLog returns a log that doesn't record anything.
+func NewNoLog() Log {
+	return noLog{}
+}
+
+func (noLog) Record(Entry) {}
+
+func (noLog) Close() error {
+	return nil
+}
@@ -0,0 +1,112 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package decisionlog
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+func readAll(t *testing.T, dir string) []Entry {
+	var entries []Entry
+	require.NoError(t, Read(dir, func(entry Entry) error {
+		entries = append(entries, entry)
+		return nil
+	}))
+	return entries
+}
+
+func TestLog(t *testing.T) {
+	require := require.New(t)
+
+	dir := t.TempDir()
+	l, err := New(dir, 1<<20)
+	require.NoError(err)
+
+	blkID := ids.GenerateTestID()
+	nodeID := ids.GenerateTestNodeID()
+	l.Record(Entry{
+		Kind:      PollIssued,
+		RequestID: 1,
+		NodeIDs:   []ids.NodeID{nodeID},
+		BlkID:     blkID,
+	})
+	l.Record(Entry{
+		Kind:      VoteReceived,
+		RequestID: 1,
+		NodeID:    nodeID,
+		BlkID:     blkID,
+	})
+	require.NoError(l.Close())
+
+	// A later process appends to the entries of the previous one.
+	l, err = New(dir, 1<<20)
+	require.NoError(err)
+	l.Record(Entry{
+		Kind:   Accepted,
+		BlkID:  blkID,
+		Height: 1,
+	})
+	require.NoError(l.Close())
+
+	entries := readAll(t, dir)
+	require.Len(entries, 3)
+	require.Equal(PollIssued, entries[0].Kind)
+	require.Equal([]ids.NodeID{nodeID}, entries[0].NodeIDs)
+	require.Equal(VoteReceived, entries[1].Kind)
+	require.Equal(nodeID, entries[1].NodeID)
+	require.Equal(Accepted, entries[2].Kind)
+	require.Equal(blkID, entries[2].BlkID)
+	require.EqualValues(1, entries[2].Height)
+	require.False(entries[2].Timestamp.Before(entries[0].Timestamp))
+}
+
+func TestLogRetention(t *testing.T) {
+	require := require.New(t)
+
+	dir := t.TempDir()
+	// Each segment holds a single entry.
+	l, err := New(dir, numSegments)
+	require.NoError(err)
+	for height := uint64(0); height < 2*numSegments; height++ {
+		l.Record(Entry{
+			Kind:   Accepted,
+			Height: height,
+		})
+	}
+	require.NoError(l.Close())
+
+	entries := readAll(t, dir)
+	require.Len(entries, numSegments)
+	for i, entry := range entries {
+		require.EqualValues(numSegments+i, entry.Height)
+	}
+}
+
+func TestReadPartialEntry(t *testing.T) {
+	require := require.New(t)
+
+	dir := t.TempDir()
+	l, err := New(dir, 1<<20)
+	require.NoError(err)
+	l.Record(Entry{Kind: Rejected})
+	require.NoError(l.Close())
+
+	// Simulate a process that crashed while writing an entry.
+	segments, err := listSegments(dir)
+	require.NoError(err)
+	f, err := os.OpenFile(segmentPath(dir, segments[0]), os.O_APPEND|os.O_WRONLY, 0)
+	require.NoError(err)
+	_, err = f.WriteString(`{"kind":"acc`)
+	require.NoError(err)
+	require.NoError(f.Close())
+
+	entries := readAll(t, dir)
+	require.Len(entries, 1)
+	require.Equal(Rejected, entries[0].Kind)
+}
@@ -0,0 +1,57 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package decisionlog
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// Read calls [f] with each entry of the log in [dir], from the oldest to the
+// most recent, until [f] returns an error. A segment whose last entry was
+// partially written, such as by a process that crashed, is read up to that
+// entry.
+func Read(dir string, f func(Entry) error) error {
+	segments, err := listSegments(dir)
+	if err != nil {
+		return err
+	}
+	for _, seq := range segments {
+		if err := readSegment(segmentPath(dir, seq), f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func readSegment(path string, f func(Entry) error) error {
+	segment, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			// The segment was deleted since the segments were listed.
+			return nil
+		}
+		return err
+	}
+	defer segment.Close()
+
+	reader := bufio.NewReader(segment)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err != nil {
+			// The last line of the segment is either empty or partially
+			// written.
+			return nil
+		}
+		var entry Entry
+		if err := json.Unmarshal(line, &entry); err != nil {
+			return fmt.Errorf("couldn't parse entry of %s: %w", path, err)
+		}
+		if err := f(entry); err != nil {
+			return err
+		}
+	}
+}
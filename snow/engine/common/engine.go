@@ -42,6 +42,7 @@ type Handler interface {
 	QueryHandler
 	ChitsHandler
 	AppHandler
+	AppErrorHandler
 	InternalHandler
 }
 
@@ -528,6 +529,22 @@ type AppHandler interface {
 	CrossChainAppHandler
 }
 
+// AppErrorHandler defines the interface an AppHandler can optionally implement
+// to be told why an AppRequest it sent failed.
+type AppErrorHandler interface {
+	// AppRequestFailedWithError notifies this engine that an AppRequest
+	// message it sent to [nodeID] with request ID [requestID] failed with
+	// [appErr].
+	//
+	// Errors reported by this node, such as ErrTimeout, have codes that
+	// aren't positive. Errors sent by [nodeID] have the code and message it
+	// specified.
+	//
+	// If implemented, this is called instead of AppRequestFailed, with the
+	// same guarantees.
+	AppRequestFailedWithError(ctx context.Context, nodeID ids.NodeID, requestID uint32, appErr *AppError) error
+}
+
 // InternalHandler defines how this consensus engine reacts to messages from
 // other components of this validator. Functions only return fatal errors if
 // they occur.
@@ -0,0 +1,79 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package common
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+// Error codes that aren't positive are reserved for the errors reported by the
+// node. VMs define their own errors with positive codes.
+var (
+	_ error = (*AppError)(nil)
+
+	// ErrUndefined is reported when a peer fails an AppRequest without
+	// specifying why.
+	ErrUndefined = &AppError{
+		Code:    0,
+		Message: "undefined",
+	}
+	// ErrTimeout is reported when an AppRequest isn't answered before its
+	// deadline, or couldn't be sent.
+	ErrTimeout = &AppError{
+		Code:    -1,
+		Message: "timed out",
+	}
+	// ErrResponseTooLarge is reported when an AppResponse is dropped because
+	// it's larger than the node allows.
+	ErrResponseTooLarge = &AppError{
+		Code:    -2,
+		Message: "response too large",
+	}
+	// ErrUnknownService is sent in response to an AppRequest for a node
+	// service that the peer doesn't run.
+	ErrUnknownService = &AppError{
+		Code:    -3,
+		Message: "unknown service",
+	}
+)
+
+// AppError is the reason an AppRequest failed. It's either reported by the
+// node or sent by the peer the request was sent to.
+type AppError struct {
+	// Code is the error code. Errors with the same code are considered equal.
+	Code int32
+	// Message is a human-readable description of the error.
+	Message string
+}
+
+func (a *AppError) Error() string {
+	return fmt.Sprintf("%d: %s", a.Code, a.Message)
+}
+
+// Is returns true if [target] is an AppError with the same code, so that
+// errors.Is can be used to compare an error received from a peer against the
+// errors defined by the VM.
+func (a *AppError) Is(target error) bool {
+	appErr, ok := target.(*AppError)
+	return ok && a.Code == appErr.Code
+}
+
+// NotifyAppRequestFailed notifies [handler] that the AppRequest it sent to
+// [nodeID] with request ID [requestID] failed with [appErr]. Handlers that
+// don't implement AppErrorHandler aren't told why the request failed.
+func NotifyAppRequestFailed(
+	ctx context.Context,
+	handler AppHandler,
+	nodeID ids.NodeID,
+	requestID uint32,
+	appErr *AppError,
+) error {
+	if errHandler, ok := handler.(AppErrorHandler); ok {
+		return errHandler.AppRequestFailedWithError(ctx, nodeID, requestID, appErr)
+	}
+	return handler.AppRequestFailed(ctx, nodeID, requestID)
+}
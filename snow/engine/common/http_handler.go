@@ -20,4 +20,13 @@ const (
 type HTTPHandler struct {
 	LockOptions LockOption
 	Handler     http.Handler
+	// CachedMethods are the JSON-RPC methods of [Handler] whose responses may
+	// be cached by the node, because their results never change once they are
+	// returned, such as the results of fetching accepted blocks by ID. The
+	// responses marked as "Cache-Control: no-store" aren't cached, see
+	// json.FinalReply.
+	CachedMethods []string
+	// OpenAPI is an optional OpenAPI path item object, encoded as JSON, that
+	// describes [Handler]. It is served in the node's OpenAPI document.
+	OpenAPI []byte
 }
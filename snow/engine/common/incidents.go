@@ -0,0 +1,73 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package common
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+// maxIncidents is the maximum number of incidents an IncidentLog remembers.
+const maxIncidents = 256
+
+const (
+	// IncidentStale is recorded when a chain stops accepting blocks that its
+	// peers have accepted.
+	IncidentStale = "stale"
+	// IncidentResync is recorded when a stale chain is resynced.
+	IncidentResync = "resync"
+	// IncidentRecovered is recorded when a stale chain accepts blocks again.
+	IncidentRecovered = "recovered"
+)
+
+var _ IncidentLog = (*incidentLog)(nil)
+
+// Incident is a notable event in the life of a chain.
+type Incident struct {
+	ChainID   ids.ID    `json:"chainID"`
+	Kind      string    `json:"kind"`
+	Message   string    `json:"message"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// IncidentLog remembers the most recent incidents of the chains of this node.
+type IncidentLog interface {
+	// Record [incident] in the log, forgetting the oldest incident if the log
+	// is full.
+	Record(incident Incident)
+
+	// Incidents returns the remembered incidents, from the oldest to the most
+	// recent.
+	Incidents() []Incident
+}
+
+type incidentLog struct {
+	lock      sync.RWMutex
+	incidents []Incident
+}
+
+func NewIncidentLog() IncidentLog {
+	return &incidentLog{}
+}
+
+func (l *incidentLog) Record(incident Incident) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if len(l.incidents) >= maxIncidents {
+		l.incidents = l.incidents[1:]
+	}
+	l.incidents = append(l.incidents, incident)
+}
+
+func (l *incidentLog) Incidents() []Incident {
+	l.lock.RLock()
+	defer l.lock.RUnlock()
+
+	incidents := make([]Incident, len(l.incidents))
+	copy(incidents, l.incidents)
+	return incidents
+}
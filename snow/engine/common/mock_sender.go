@@ -101,6 +101,20 @@ func (mr *MockSenderMockRecorder) SendAncestors(arg0, arg1, arg2, arg3 interface
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendAncestors", reflect.TypeOf((*MockSender)(nil).SendAncestors), arg0, arg1, arg2, arg3)
 }
 
+// SendAppError mocks base method.
+func (m *MockSender) SendAppError(arg0 context.Context, arg1 ids.NodeID, arg2 uint32, arg3 int32, arg4 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendAppError", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendAppError indicates an expected call of SendAppError.
+func (mr *MockSenderMockRecorder) SendAppError(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendAppError", reflect.TypeOf((*MockSender)(nil).SendAppError), arg0, arg1, arg2, arg3, arg4)
+}
+
 // SendAppGossip mocks base method.
 func (m *MockSender) SendAppGossip(arg0 context.Context, arg1 []byte) error {
 	m.ctrl.T.Helper()
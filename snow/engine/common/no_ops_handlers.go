@@ -318,6 +318,17 @@ func (nop *noOpAppHandler) AppRequestFailed(_ context.Context, nodeID ids.NodeID
 	return nil
 }
 
+func (nop *noOpAppHandler) AppRequestFailedWithError(_ context.Context, nodeID ids.NodeID, requestID uint32, appErr *AppError) error {
+	nop.log.Debug("dropping request",
+		zap.String("reason", "unhandled by this gear"),
+		zap.Stringer("messageOp", message.AppRequestFailedOp),
+		zap.Stringer("nodeID", nodeID),
+		zap.Uint32("requestID", requestID),
+		zap.Error(appErr),
+	)
+	return nil
+}
+
 func (nop *noOpAppHandler) AppResponse(_ context.Context, nodeID ids.NodeID, requestID uint32, _ []byte) error {
 	nop.log.Debug("dropping request",
 		zap.String("reason", "unhandled by this gear"),
@@ -141,6 +141,13 @@ type NetworkAppSender interface {
 	// to this AppSender received from [nodeID] with ID [requestID].
 	// A non-nil error should be considered fatal.
 	SendAppResponse(ctx context.Context, nodeID ids.NodeID, requestID uint32, appResponseBytes []byte) error
+	// Send an application-level error to a request, instead of a response.
+	// This error must be in response to an AppRequest that the VM corresponding
+	// to this AppSender received from [nodeID] with ID [requestID].
+	// [errorCode] should be positive, as the other codes are reserved for the
+	// errors reported by the node.
+	// A non-nil error should be considered fatal.
+	SendAppError(ctx context.Context, nodeID ids.NodeID, requestID uint32, errorCode int32, errorMessage string) error
 	// Gossip an application-level message.
 	// A non-nil error should be considered fatal.
 	SendAppGossip(ctx context.Context, appGossipBytes []byte) error
@@ -41,6 +41,7 @@ var (
 	errQueryFailed                   = errors.New("unexpectedly called QueryFailed")
 	errChits                         = errors.New("unexpectedly called Chits")
 	errStart                         = errors.New("unexpectedly called Start")
+	errAppRequestFailedWithError     = errors.New("unexpectedly called AppRequestFailedWithError")
 
 	_ Engine = (*EngineTest)(nil)
 )
@@ -102,6 +103,7 @@ type EngineTest struct {
 	CantAppResponse,
 	CantAppGossip,
 	CantAppRequestFailed,
+	CantAppRequestFailedWithError,
 
 	CantGetVM bool
 
@@ -119,6 +121,7 @@ type EngineTest struct {
 	GetAcceptedFrontierF, GetFailedF, GetAncestorsFailedF,
 	QueryFailedF, GetAcceptedFrontierFailedF, GetAcceptedFailedF func(ctx context.Context, nodeID ids.NodeID, requestID uint32) error
 	AppRequestFailedF           func(ctx context.Context, nodeID ids.NodeID, requestID uint32) error
+	AppRequestFailedWithErrorF  func(ctx context.Context, nodeID ids.NodeID, requestID uint32, appErr *AppError) error
 	StateSummaryFrontierF       func(ctx context.Context, nodeID ids.NodeID, requestID uint32, summary []byte) error
 	GetAcceptedStateSummaryF    func(ctx context.Context, nodeID ids.NodeID, requestID uint32, keys []uint64) error
 	AcceptedStateSummaryF       func(ctx context.Context, nodeID ids.NodeID, requestID uint32, summaryIDs []ids.ID) error
@@ -170,6 +173,7 @@ func (e *EngineTest) Default(cant bool) {
 	e.CantHealth = cant
 	e.CantAppRequest = cant
 	e.CantAppRequestFailed = cant
+	e.CantAppRequestFailedWithError = cant
 	e.CantAppResponse = cant
 	e.CantAppGossip = cant
 	e.CantGetVM = cant
@@ -617,6 +621,19 @@ func (e *EngineTest) AppRequestFailed(ctx context.Context, nodeID ids.NodeID, re
 	return errAppRequestFailed
 }
 
+func (e *EngineTest) AppRequestFailedWithError(ctx context.Context, nodeID ids.NodeID, requestID uint32, appErr *AppError) error {
+	if e.AppRequestFailedWithErrorF != nil {
+		return e.AppRequestFailedWithErrorF(ctx, nodeID, requestID, appErr)
+	}
+	if !e.CantAppRequestFailedWithError {
+		return nil
+	}
+	if e.T != nil {
+		e.T.Fatal(errAppRequestFailedWithError)
+	}
+	return errAppRequestFailedWithError
+}
+
 func (e *EngineTest) AppGossip(ctx context.Context, nodeID ids.NodeID, msg []byte) error {
 	if e.AppGossipF != nil {
 		return e.AppGossipF(ctx, nodeID, msg)
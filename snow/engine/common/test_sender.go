@@ -18,6 +18,7 @@ var (
 	errAccept                = errors.New("unexpectedly called Accept")
 	errSendAppRequest        = errors.New("unexpectedly called SendAppRequest")
 	errSendAppResponse       = errors.New("unexpectedly called SendAppResponse")
+	errSendAppError          = errors.New("unexpectedly called SendAppError")
 	errSendAppGossip         = errors.New("unexpectedly called SendAppGossip")
 	errSendAppGossipSpecific = errors.New("unexpectedly called SendAppGossipSpecific")
 )
@@ -34,7 +35,7 @@ type SenderTest struct {
 	CantSendGet, CantSendGetAncestors, CantSendPut, CantSendAncestors,
 	CantSendPullQuery, CantSendPushQuery, CantSendChits,
 	CantSendGossip,
-	CantSendAppRequest, CantSendAppResponse, CantSendAppGossip, CantSendAppGossipSpecific,
+	CantSendAppRequest, CantSendAppResponse, CantSendAppError, CantSendAppGossip, CantSendAppGossipSpecific,
 	CantSendCrossChainAppRequest, CantSendCrossChainAppResponse bool
 
 	AcceptF                      func(*snow.ConsensusContext, ids.ID, []byte) error
@@ -56,6 +57,7 @@ type SenderTest struct {
 	SendGossipF                  func(context.Context, []byte)
 	SendAppRequestF              func(context.Context, ids.NodeIDSet, uint32, []byte) error
 	SendAppResponseF             func(context.Context, ids.NodeID, uint32, []byte) error
+	SendAppErrorF                func(context.Context, ids.NodeID, uint32, int32, string) error
 	SendAppGossipF               func(context.Context, []byte) error
 	SendAppGossipSpecificF       func(context.Context, ids.NodeIDSet, []byte) error
 	SendCrossChainAppRequestF    func(context.Context, ids.ID, uint32, []byte)
@@ -83,6 +85,7 @@ func (s *SenderTest) Default(cant bool) {
 	s.CantSendGossip = cant
 	s.CantSendAppRequest = cant
 	s.CantSendAppResponse = cant
+	s.CantSendAppError = cant
 	s.CantSendAppGossip = cant
 	s.CantSendAppGossipSpecific = cant
 	s.CantSendCrossChainAppRequest = cant
@@ -325,6 +328,19 @@ func (s *SenderTest) SendAppResponse(ctx context.Context, nodeID ids.NodeID, req
 	return errSendAppResponse
 }
 
+// SendAppError calls SendAppErrorF if it was initialized. If it wasn't
+// initialized and this function shouldn't be called and testing was
+// initialized, then testing will fail.
+func (s *SenderTest) SendAppError(ctx context.Context, nodeID ids.NodeID, requestID uint32, errorCode int32, errorMessage string) error {
+	switch {
+	case s.SendAppErrorF != nil:
+		return s.SendAppErrorF(ctx, nodeID, requestID, errorCode, errorMessage)
+	case s.CantSendAppError && s.T != nil:
+		s.T.Fatal(errSendAppError)
+	}
+	return errSendAppError
+}
+
 // SendAppGossip calls SendAppGossipF if it was initialized. If it wasn't
 // initialized and this function shouldn't be called and testing was
 // initialized, then testing will fail.
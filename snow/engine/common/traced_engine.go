@@ -296,6 +296,17 @@ func (e *tracedEngine) AppRequestFailed(ctx context.Context, nodeID ids.NodeID,
 	return e.engine.AppRequestFailed(ctx, nodeID, requestID)
 }
 
+func (e *tracedEngine) AppRequestFailedWithError(ctx context.Context, nodeID ids.NodeID, requestID uint32, appErr *AppError) error {
+	ctx, span := e.tracer.Start(ctx, "tracedEngine.AppRequestFailedWithError", oteltrace.WithAttributes(
+		attribute.Stringer("nodeID", nodeID),
+		attribute.Int64("requestID", int64(requestID)),
+		attribute.Int64("errorCode", int64(appErr.Code)),
+	))
+	defer span.End()
+
+	return e.engine.AppRequestFailedWithError(ctx, nodeID, requestID, appErr)
+}
+
 func (e *tracedEngine) AppGossip(ctx context.Context, nodeID ids.NodeID, msg []byte) error {
 	ctx, span := e.tracer.Start(ctx, "tracedEngine.AppGossip", oteltrace.WithAttributes(
 		attribute.Stringer("nodeID", nodeID),
@@ -0,0 +1,185 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package tracker
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+
+	safemath "github.com/ava-labs/avalanchego/utils/math"
+)
+
+// minLatency bounds the latency used to score peers to avoid dividing by 0.
+const minLatency = time.Millisecond
+
+var _ PeerSLA = (*peerSLA)(nil)
+
+// PeerSLA tracks how reliably and how quickly peers respond to requests.
+//
+// Requests that could be sent to any one of multiple peers can use the
+// tracked history to prefer the peers that have performed the best.
+type PeerSLA interface {
+	// RequestSent marks that request [requestID] was sent to [nodeID].
+	RequestSent(nodeID ids.NodeID, requestID uint32)
+	// ResponseReceived marks that [nodeID] responded to request [requestID].
+	ResponseReceived(nodeID ids.NodeID, requestID uint32)
+	// RequestFailed marks that [nodeID] failed to usefully respond to request
+	// [requestID].
+	RequestFailed(nodeID ids.NodeID, requestID uint32)
+
+	// Score returns the score of [nodeID]. Higher scores are better. Peers
+	// that haven't been sent any requests yet have the highest possible score,
+	// so that they are tried at least once.
+	Score(nodeID ids.NodeID) float64
+	// Preferred returns the peer of [nodeIDs] with the highest score. Returns
+	// false if [nodeIDs] is empty.
+	Preferred(nodeIDs []ids.NodeID) (ids.NodeID, bool)
+}
+
+type peerSLA struct {
+	halflife time.Duration
+	// now is used to read the current time, it's replaced in tests
+	now func() time.Time
+
+	lock  sync.Mutex
+	peers map[ids.NodeID]*peerHistory
+}
+
+type peerHistory struct {
+	// requestID -> time the request was sent
+	outstanding map[uint32]time.Time
+	// successRate is the moving average of the fraction of requests that were
+	// answered
+	successRate safemath.Averager
+	// latency is the moving average of the number of seconds it took to
+	// receive a response
+	latency safemath.Averager
+}
+
+// NewPeerSLA returns a new tracker that weighs the history of each peer with
+// an exponential moving average with the provided [halflife].
+func NewPeerSLA(halflife time.Duration) PeerSLA {
+	return &peerSLA{
+		halflife: halflife,
+		now:      time.Now,
+		peers:    make(map[ids.NodeID]*peerHistory),
+	}
+}
+
+func (p *peerSLA) RequestSent(nodeID ids.NodeID, requestID uint32) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	history, ok := p.peers[nodeID]
+	if !ok {
+		history = &peerHistory{
+			outstanding: make(map[uint32]time.Time),
+		}
+		p.peers[nodeID] = history
+	}
+	history.outstanding[requestID] = p.now()
+}
+
+func (p *peerSLA) ResponseReceived(nodeID ids.NodeID, requestID uint32) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	history, sentTime, ok := p.remove(nodeID, requestID)
+	if !ok {
+		return
+	}
+
+	now := p.now()
+	p.observeSuccess(history, 1, now)
+
+	latency := now.Sub(sentTime).Seconds()
+	if history.latency == nil {
+		history.latency = safemath.NewAverager(latency, p.halflife, now)
+	} else {
+		history.latency.Observe(latency, now)
+	}
+}
+
+func (p *peerSLA) RequestFailed(nodeID ids.NodeID, requestID uint32) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	history, _, ok := p.remove(nodeID, requestID)
+	if !ok {
+		return
+	}
+
+	p.observeSuccess(history, 0, p.now())
+}
+
+func (p *peerSLA) Score(nodeID ids.NodeID) float64 {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	return p.score(nodeID)
+}
+
+func (p *peerSLA) Preferred(nodeIDs []ids.NodeID) (ids.NodeID, bool) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if len(nodeIDs) == 0 {
+		return ids.EmptyNodeID, false
+	}
+
+	preferred := nodeIDs[0]
+	preferredScore := p.score(preferred)
+	for _, nodeID := range nodeIDs[1:] {
+		if score := p.score(nodeID); score > preferredScore {
+			preferred = nodeID
+			preferredScore = score
+		}
+	}
+	return preferred, true
+}
+
+// Assumes [p.lock] is held.
+func (p *peerSLA) observeSuccess(history *peerHistory, success float64, now time.Time) {
+	if history.successRate == nil {
+		history.successRate = safemath.NewAverager(success, p.halflife, now)
+	} else {
+		history.successRate.Observe(success, now)
+	}
+}
+
+// remove removes the outstanding request and returns the time it was sent.
+//
+// Assumes [p.lock] is held.
+func (p *peerSLA) remove(nodeID ids.NodeID, requestID uint32) (*peerHistory, time.Time, bool) {
+	history, ok := p.peers[nodeID]
+	if !ok {
+		return nil, time.Time{}, false
+	}
+	sentTime, ok := history.outstanding[requestID]
+	if !ok {
+		return nil, time.Time{}, false
+	}
+	delete(history.outstanding, requestID)
+	return history, sentTime, true
+}
+
+// score returns the expected number of successful responses per second that
+// [nodeID] will provide.
+//
+// Assumes [p.lock] is held.
+func (p *peerSLA) score(nodeID ids.NodeID) float64 {
+	history, ok := p.peers[nodeID]
+	if !ok || history.successRate == nil {
+		return math.Inf(1)
+	}
+
+	latency := minLatency.Seconds()
+	if history.latency != nil {
+		latency = math.Max(history.latency.Read(), latency)
+	}
+	return history.successRate.Read() / latency
+}
@@ -0,0 +1,76 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package tracker
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+func TestPeerSLAPreferred(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Unix(0, 0)
+	sla := NewPeerSLA(time.Minute).(*peerSLA)
+	sla.now = func() time.Time {
+		return now
+	}
+
+	fast := ids.GenerateTestNodeID()
+	slow := ids.GenerateTestNodeID()
+	unreliable := ids.GenerateTestNodeID()
+	unknown := ids.GenerateTestNodeID()
+
+	_, ok := sla.Preferred(nil)
+	require.False(ok)
+
+	// Peers that were never sent a request are explored first.
+	require.Equal(math.Inf(1), sla.Score(unknown))
+
+	sla.RequestSent(fast, 1)
+	sla.RequestSent(slow, 2)
+	sla.RequestSent(unreliable, 3)
+
+	now = now.Add(100 * time.Millisecond)
+	sla.ResponseReceived(fast, 1)
+	sla.RequestFailed(unreliable, 3)
+
+	now = now.Add(time.Second)
+	sla.ResponseReceived(slow, 2)
+
+	require.Greater(sla.Score(fast), sla.Score(slow))
+	require.Greater(sla.Score(slow), sla.Score(unreliable))
+
+	preferred, ok := sla.Preferred([]ids.NodeID{unreliable, slow, fast})
+	require.True(ok)
+	require.Equal(fast, preferred)
+
+	preferred, ok = sla.Preferred([]ids.NodeID{fast, unknown})
+	require.True(ok)
+	require.Equal(unknown, preferred)
+}
+
+func TestPeerSLAIgnoresUnknownRequests(t *testing.T) {
+	require := require.New(t)
+
+	sla := NewPeerSLA(time.Minute)
+	nodeID := ids.GenerateTestNodeID()
+
+	sla.ResponseReceived(nodeID, 1)
+	sla.RequestFailed(nodeID, 1)
+	require.Equal(math.Inf(1), sla.Score(nodeID))
+
+	sla.RequestSent(nodeID, 1)
+	sla.RequestFailed(nodeID, 1)
+	require.Zero(sla.Score(nodeID))
+
+	// The request was already marked as failed.
+	sla.ResponseReceived(nodeID, 1)
+	require.Zero(sla.Score(nodeID))
+}
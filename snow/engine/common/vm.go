@@ -10,6 +10,7 @@ import (
 	"github.com/ava-labs/avalanchego/database/manager"
 	"github.com/ava-labs/avalanchego/snow"
 	"github.com/ava-labs/avalanchego/snow/validators"
+	"github.com/ava-labs/avalanchego/trace"
 )
 
 // VM describes the interface that all consensus VMs must implement
@@ -98,3 +99,65 @@ type VM interface {
 	// information about their accounts.
 	CreateHandlers(context.Context) (map[string]*HTTPHandler, error)
 }
+
+// ConfigSchemaVM is a VM that describes the chain configs it accepts. The chain
+// config of a chain running such a VM is validated before the chain is
+// initialized.
+type ConfigSchemaVM interface {
+	// ConfigSchema returns the JSON schema of the chain configs of the VM, or
+	// nil if the chain configs shouldn't be validated.
+	//
+	// ConfigSchema may be called before the VM is initialized.
+	ConfigSchema(context.Context) ([]byte, error)
+}
+
+// GRPCConfigurableVM is a VM that talks to the node over gRPC connections that
+// can be tuned per chain.
+type GRPCConfigurableVM interface {
+	// SetGRPCConfig tunes the gRPC connections of the VM with the gRPC config
+	// of its chain.
+	//
+	// SetGRPCConfig is called before the VM is initialized, if the chain has a
+	// gRPC config.
+	SetGRPCConfig(configBytes []byte) error
+}
+
+// CacheConfigurableVM is a VM that caches the blocks of its chain with caches
+// that can be sized per chain.
+type CacheConfigurableVM interface {
+	// SetCacheConfig sizes the caches of the VM with the cache config of its
+	// chain.
+	//
+	// SetCacheConfig is called before the VM is initialized, if the chain has
+	// a cache config.
+	SetCacheConfig(configBytes []byte) error
+}
+
+// TraceableVM is a VM that talks to the node over connections whose calls can
+// be traced.
+type TraceableVM interface {
+	// SetTracer traces the calls between the node and the VM with [tracer].
+	//
+	// SetTracer is called before the VM is initialized, if tracing is enabled.
+	SetTracer(tracer trace.Tracer)
+}
+
+// FailableVM is a VM that can fail in a way it can't recover from, such as a
+// plugin that was restarted with a state its chain can't resume from.
+type FailableVM interface {
+	// SetOnFailed registers [onFailed] to be called with the reason the VM
+	// failed. The VM isn't called again once it failed.
+	//
+	// SetOnFailed is called once the VM's chain is created.
+	SetOnFailed(onFailed func(err error))
+}
+
+// UpgradableVM is a VM running in a plugin whose binary can be replaced while
+// its chain is running.
+type UpgradableVM interface {
+	// UpgradePlugin replaces the plugin of the VM with the plugin at [path],
+	// and brings the new plugin to the state of the previous one.
+	//
+	// UpgradePlugin may be called at any time after the VM is initialized.
+	UpgradePlugin(ctx context.Context, path string) error
+}
@@ -29,6 +29,12 @@ type BatchedChainVM interface {
 	) ([][]byte, error)
 
 	BatchedParseBlock(ctx context.Context, blks [][]byte) ([]snowman.Block, error)
+
+	// BatchedGetBlock returns the blocks of [blkIDs]. If blkIDs[i] couldn't
+	// be fetched, the i-th returned block is nil and the i-th returned error
+	// is why, which is database.ErrNotFound if the block doesn't exist. The
+	// returned error is only non-nil if none of the blocks could be fetched.
+	BatchedGetBlock(ctx context.Context, blkIDs []ids.ID) ([]snowman.Block, []error, error)
 }
 
 func GetAncestors(
@@ -120,3 +126,35 @@ func BatchedParseBlock(
 	}
 	return blocks, nil
 }
+
+func BatchedGetBlock(
+	ctx context.Context,
+	vm Getter,
+	blkIDs []ids.ID,
+) ([]snowman.Block, []error, error) {
+	// Try and batch GetBlock requests
+	if vm, ok := vm.(BatchedChainVM); ok {
+		blocks, errs, err := vm.BatchedGetBlock(ctx, blkIDs)
+		if err == nil {
+			return blocks, errs, nil
+		}
+		if err != ErrRemoteVMNotImplemented {
+			return nil, nil, err
+		}
+	}
+
+	// We couldn't batch the GetBlock requests, try to get them one at a time.
+	var (
+		blocks = make([]snowman.Block, len(blkIDs))
+		errs   = make([]error, len(blkIDs))
+	)
+	for i, blkID := range blkIDs {
+		block, err := vm.GetBlock(ctx, blkID)
+		if err != nil {
+			errs[i] = err
+			continue
+		}
+		blocks[i] = block
+	}
+	return blocks, errs, nil
+}
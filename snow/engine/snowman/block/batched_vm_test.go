@@ -42,3 +42,48 @@ func TestGetAncestorsPropagatesErrors(t *testing.T) {
 	require.Nil(t, containers)
 	require.ErrorIs(t, err, someError)
 }
+
+func TestBatchedGetBlockFallsBackToGetBlock(t *testing.T) {
+	require := require.New(t)
+
+	vm := &TestVM{}
+	blk := &snowman.TestBlock{}
+	blkID := ids.GenerateTestID()
+	missingID := ids.GenerateTestID()
+	vm.GetBlockF = func(_ context.Context, id ids.ID) (snowman.Block, error) {
+		if id == blkID {
+			return blk, nil
+		}
+		return nil, database.ErrNotFound
+	}
+
+	blks, errs, err := BatchedGetBlock(context.Background(), vm, []ids.ID{blkID, missingID})
+	require.NoError(err)
+	require.Equal([]snowman.Block{blk, nil}, blks)
+	require.NoError(errs[0])
+	require.ErrorIs(errs[1], database.ErrNotFound)
+}
+
+func TestBatchedGetBlockFallsBackIfNotImplemented(t *testing.T) {
+	require := require.New(t)
+
+	vm := &struct {
+		*TestVM
+		*TestBatchedVM
+	}{
+		TestVM:        &TestVM{},
+		TestBatchedVM: &TestBatchedVM{},
+	}
+	vm.TestBatchedVM.BatchedGetBlockF = func(context.Context, []ids.ID) ([]snowman.Block, []error, error) {
+		return nil, nil, ErrRemoteVMNotImplemented
+	}
+	blk := &snowman.TestBlock{}
+	vm.TestVM.GetBlockF = func(context.Context, ids.ID) (snowman.Block, error) {
+		return blk, nil
+	}
+
+	blks, errs, err := BatchedGetBlock(context.Background(), vm, []ids.ID{ids.GenerateTestID()})
+	require.NoError(err)
+	require.Equal([]snowman.Block{blk}, blks)
+	require.Equal([]error{nil}, errs)
+}
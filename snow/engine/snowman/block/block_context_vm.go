@@ -0,0 +1,57 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package block
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
+)
+
+// Context is the block context the proposervm optionally provides to the VM it
+// wraps.
+type Context struct {
+	// PChainHeight is the P-Chain height the block is built or verified
+	// against. The proposervm verifies the proposer of a block against the
+	// P-Chain height recorded in its parent, so this is the P-Chain height of
+	// the parent block rather than the P-Chain height of the block itself.
+	//
+	// Pre-fork blocks and post-fork options aren't verified against the
+	// P-Chain, so they are never provided a context.
+	PChainHeight uint64
+}
+
+// BuildBlockWithContextChainVM defines the interface a ChainVM can optionally
+// implement to account for the P-Chain height when building blocks.
+type BuildBlockWithContextChainVM interface {
+	// BuildBlockWithContext attempts to build a new block given that the
+	// P-Chain height is [blockCtx.PChainHeight].
+	//
+	// This is called instead of BuildBlock once the proposervm is activated.
+	BuildBlockWithContext(ctx context.Context, blockCtx *Context) (snowman.Block, error)
+}
+
+// WithVerifyContext defines the interface a Block can optionally implement to
+// account for the P-Chain height when verifying itself.
+//
+// As with Verify, blocks are verified in topological order, and a block that
+// was accepted or rejected is never verified.
+type WithVerifyContext interface {
+	// ShouldVerifyWithContext returns true if VerifyWithContext should be
+	// called rather than Verify.
+	//
+	// This is only called once the proposervm is activated.
+	ShouldVerifyWithContext(context.Context) (bool, error)
+
+	// VerifyWithContext verifies the block given that the P-Chain height is
+	// [blockCtx.PChainHeight].
+	//
+	// It may be called again with a different context. If nil is returned,
+	// either Accept or Reject is eventually called, unless the VM is shut
+	// down.
+	//
+	// The context isn't provided again on Accept, so it may only be used to
+	// determine the validity of the block, not to alter its state transition.
+	VerifyWithContext(ctx context.Context, blockCtx *Context) error
+}
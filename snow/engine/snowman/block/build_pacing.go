@@ -0,0 +1,44 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package block
+
+import (
+	"errors"
+	"time"
+)
+
+var errNegativeMinInterval = errors.New("min build interval must be >= 0")
+
+// BuildPacing paces the blocks the consensus engine asks the VM to build, so
+// that the VM isn't asked to build a block for every tx under trickle load.
+type BuildPacing struct {
+	// Min time between two requests of the engine to build a block. The
+	// PendingTxs notifications the VM sends in the meantime are coalesced
+	// into a single request. If 0, blocks are built as soon as the VM has
+	// pending txs.
+	MinInterval time.Duration `json:"minInterval"`
+
+	// Hint of the number of txs the VM should have pending before notifying
+	// the engine, unless [MinInterval] has elapsed since the last block was
+	// built. The engine doesn't enforce this hint. If 0, the VM may notify
+	// the engine as soon as it has a pending tx.
+	MinPendingTxs uint32 `json:"minPendingTxs"`
+}
+
+func (p BuildPacing) Verify() error {
+	if p.MinInterval < 0 {
+		return errNegativeMinInterval
+	}
+	return nil
+}
+
+// BuildPacingVM is a VM that batches its pending txs according to the pacing
+// of the blocks the engine asks it to build.
+type BuildPacingVM interface {
+	// SetBuildPacing gives the VM the pacing of its chain's blocks.
+	//
+	// SetBuildPacing is called before the VM is initialized, if the chain's
+	// blocks are paced.
+	SetBuildPacing(pacing BuildPacing)
+}
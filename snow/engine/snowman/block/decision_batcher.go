@@ -0,0 +1,28 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package block
+
+import (
+	"context"
+)
+
+// DecisionBatcher is implemented by VMs that can apply the decisions made on
+// their blocks in batches, rather than one block at a time. This allows more
+// efficient operations when the VM is called over the network.
+type DecisionBatcher interface {
+	// BatchDecisions calls [f]. The blocks accepted and rejected by [f] may
+	// only be decided by the VM, in the order [f] decided them, once [f]
+	// returns. [f] must not depend on the VM having applied the decisions it
+	// made.
+	BatchDecisions(ctx context.Context, f func(context.Context) error) error
+}
+
+// BatchDecisions calls [f], batching the decisions it makes on the blocks of
+// [vm] if [vm] supports it.
+func BatchDecisions(ctx context.Context, vm ChainVM, f func(context.Context) error) error {
+	if vm, ok := vm.(DecisionBatcher); ok {
+		return vm.BatchDecisions(ctx, f)
+	}
+	return f(ctx)
+}
@@ -0,0 +1,22 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package block
+
+import (
+	"context"
+	"errors"
+)
+
+var ErrDecoderNotImplemented = errors.New("vm does not implement Decoder interface")
+
+// Decoder describes the contents of blocks without processing them. It is
+// intended for debugging and audit tooling.
+type Decoder interface {
+	// DecodeBlock returns a JSON representation of the block encoded in
+	// [blkBytes].
+	//
+	// The block must not be verified, cached, or otherwise processed by the
+	// VM.
+	DecodeBlock(ctx context.Context, blkBytes []byte) ([]byte, error)
+}
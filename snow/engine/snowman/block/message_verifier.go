@@ -0,0 +1,22 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package block
+
+import (
+	"context"
+	"errors"
+)
+
+var ErrMessageVerifierNotImplemented = errors.New("vm does not implement MessageVerifier interface")
+
+// MessageVerifier approves the messages that this node signs with its BLS key
+// when the signature aggregation service of another node requests it.
+type MessageVerifier interface {
+	// VerifyMessage returns nil if this node should sign [msg]. Messages of
+	// VMs that don't implement MessageVerifier are never signed.
+	//
+	// VerifyMessage may be called concurrently, and without the context lock
+	// held.
+	VerifyMessage(ctx context.Context, msg []byte) error
+}
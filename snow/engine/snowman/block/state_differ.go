@@ -0,0 +1,91 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package block
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"hash"
+)
+
+var (
+	ErrStateDifferNotImplemented = errors.New("vm does not implement StateDiffer interface")
+	ErrUnorderedStateDiff        = errors.New("state diff keys aren't in increasing order")
+)
+
+// StateDiffer exports the changes made to the state of a VM between two
+// accepted heights. It is intended for audit tooling and for maintaining
+// replicas of the state outside of the node.
+type StateDiffer interface {
+	// GetStateDiff calls [f] with each key whose value differs between the
+	// state after the block accepted at [startHeight] and the state after the
+	// block accepted at [endHeight], along with its value at [endHeight]. A
+	// nil value means that the key was deleted. The keys are given in
+	// increasing order, so the diff between two heights is deterministic.
+	// [key] and [value] may be modified by the VM once [f] returns.
+	//
+	// The node holds the chain's read lock while it exports a diff, so no
+	// block is accepted during the export.
+	GetStateDiff(
+		ctx context.Context,
+		startHeight uint64,
+		endHeight uint64,
+		f func(key []byte, value []byte) error,
+	) error
+}
+
+// StateDiffDigest is a digest of the entries of a state diff, in the order
+// they were exported. Two exports of the same diff have the same digest.
+//
+// The digest only commits to the exported entries, not to the state of the
+// VM, so it detects diffs that were reordered or truncated in transit but
+// doesn't prove that a diff matches the chain.
+type StateDiffDigest struct {
+	hash       hash.Hash
+	numEntries uint64
+	lastKey    []byte
+}
+
+func NewStateDiffDigest() *StateDiffDigest {
+	return &StateDiffDigest{
+		hash: sha256.New(),
+	}
+}
+
+// Add [key] and its [value], which is nil if [key] was deleted, to the digest.
+// Returns an error if [key] isn't greater than the previously added key.
+func (d *StateDiffDigest) Add(key []byte, value []byte) error {
+	if d.numEntries > 0 && bytes.Compare(key, d.lastKey) <= 0 {
+		return ErrUnorderedStateDiff
+	}
+	d.numEntries++
+	d.lastKey = append(d.lastKey[:0], key...)
+
+	var header [9]byte
+	binary.BigEndian.PutUint32(header[:4], uint32(len(key)))
+	_, _ = d.hash.Write(header[:4])
+	_, _ = d.hash.Write(key)
+	if value == nil {
+		_, _ = d.hash.Write(header[4:5]) // deleted
+		return nil
+	}
+	header[4] = 1
+	binary.BigEndian.PutUint32(header[5:], uint32(len(value)))
+	_, _ = d.hash.Write(header[4:])
+	_, _ = d.hash.Write(value)
+	return nil
+}
+
+// NumEntries returns the number of entries added to the digest.
+func (d *StateDiffDigest) NumEntries() uint64 {
+	return d.numEntries
+}
+
+// Sum returns the digest of the entries added so far.
+func (d *StateDiffDigest) Sum() []byte {
+	return d.hash.Sum(nil)
+}
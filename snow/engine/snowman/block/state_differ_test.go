@@ -0,0 +1,37 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package block
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStateDiffDigest(t *testing.T) {
+	require := require.New(t)
+
+	d1 := NewStateDiffDigest()
+	require.NoError(d1.Add([]byte{1}, []byte{}))
+	require.NoError(d1.Add([]byte{2}, nil))
+	require.NoError(d1.Add([]byte{2, 0}, []byte{3}))
+	require.EqualValues(3, d1.NumEntries())
+
+	d2 := NewStateDiffDigest()
+	require.NoError(d2.Add([]byte{1}, []byte{}))
+	require.NoError(d2.Add([]byte{2}, nil))
+	require.NoError(d2.Add([]byte{2, 0}, []byte{3}))
+	require.Equal(d1.Sum(), d2.Sum())
+
+	// A deleted key differs from an empty value.
+	d3 := NewStateDiffDigest()
+	require.NoError(d3.Add([]byte{1}, nil))
+	require.NoError(d3.Add([]byte{2}, nil))
+	require.NoError(d3.Add([]byte{2, 0}, []byte{3}))
+	require.NotEqual(d1.Sum(), d3.Sum())
+
+	// Keys must be strictly increasing.
+	require.ErrorIs(d3.Add([]byte{2, 0}, nil), ErrUnorderedStateDiff)
+	require.ErrorIs(d3.Add([]byte{1}, nil), ErrUnorderedStateDiff)
+}
@@ -16,6 +16,7 @@ import (
 var (
 	errGetAncestor       = errors.New("unexpectedly called GetAncestor")
 	errBatchedParseBlock = errors.New("unexpectedly called BatchedParseBlock")
+	errBatchedGetBlock   = errors.New("unexpectedly called BatchedGetBlock")
 
 	_ BatchedChainVM = (*TestBatchedVM)(nil)
 )
@@ -26,6 +27,7 @@ type TestBatchedVM struct {
 
 	CantGetAncestors    bool
 	CantBatchParseBlock bool
+	CantBatchGetBlock   bool
 
 	GetAncestorsF func(
 		ctx context.Context,
@@ -39,11 +41,17 @@ type TestBatchedVM struct {
 		ctx context.Context,
 		blks [][]byte,
 	) ([]snowman.Block, error)
+
+	BatchedGetBlockF func(
+		ctx context.Context,
+		blkIDs []ids.ID,
+	) ([]snowman.Block, []error, error)
 }
 
 func (vm *TestBatchedVM) Default(cant bool) {
 	vm.CantGetAncestors = cant
 	vm.CantBatchParseBlock = cant
+	vm.CantBatchGetBlock = cant
 }
 
 func (vm *TestBatchedVM) GetAncestors(
@@ -80,3 +88,16 @@ func (vm *TestBatchedVM) BatchedParseBlock(
 	}
 	return nil, errBatchedParseBlock
 }
+
+func (vm *TestBatchedVM) BatchedGetBlock(
+	ctx context.Context,
+	blkIDs []ids.ID,
+) ([]snowman.Block, []error, error) {
+	if vm.BatchedGetBlockF != nil {
+		return vm.BatchedGetBlockF(ctx, blkIDs)
+	}
+	if vm.CantBatchGetBlock && vm.T != nil {
+		vm.T.Fatal(errBatchedGetBlock)
+	}
+	return nil, nil, errBatchedGetBlock
+}
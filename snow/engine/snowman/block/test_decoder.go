@@ -0,0 +1,35 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package block
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+var (
+	errDecodeBlock = errors.New("unexpectedly called DecodeBlock")
+
+	_ Decoder = (*TestDecoder)(nil)
+)
+
+// TestDecoder is a Decoder that is useful for testing.
+type TestDecoder struct {
+	T *testing.T
+
+	CantDecodeBlock bool
+
+	DecodeBlockF func(ctx context.Context, blkBytes []byte) ([]byte, error)
+}
+
+func (vm *TestDecoder) DecodeBlock(ctx context.Context, blkBytes []byte) ([]byte, error) {
+	if vm.DecodeBlockF != nil {
+		return vm.DecodeBlockF(ctx, blkBytes)
+	}
+	if vm.CantDecodeBlock && vm.T != nil {
+		vm.T.Fatal(errDecodeBlock)
+	}
+	return nil, errDecodeBlock
+}
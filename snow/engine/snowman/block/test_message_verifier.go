@@ -0,0 +1,35 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package block
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+var (
+	errVerifyMessage = errors.New("unexpectedly called VerifyMessage")
+
+	_ MessageVerifier = (*TestMessageVerifier)(nil)
+)
+
+// TestMessageVerifier is a MessageVerifier that is useful for testing.
+type TestMessageVerifier struct {
+	T *testing.T
+
+	CantVerifyMessage bool
+
+	VerifyMessageF func(ctx context.Context, msg []byte) error
+}
+
+func (vm *TestMessageVerifier) VerifyMessage(ctx context.Context, msg []byte) error {
+	if vm.VerifyMessageF != nil {
+		return vm.VerifyMessageF(ctx, msg)
+	}
+	if vm.CantVerifyMessage && vm.T != nil {
+		vm.T.Fatal(errVerifyMessage)
+	}
+	return errVerifyMessage
+}
@@ -0,0 +1,35 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package block
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+var (
+	errGetStateDiff = errors.New("unexpectedly called GetStateDiff")
+
+	_ StateDiffer = (*TestStateDiffer)(nil)
+)
+
+// TestStateDiffer is a StateDiffer that is useful for testing.
+type TestStateDiffer struct {
+	T *testing.T
+
+	CantGetStateDiff bool
+
+	GetStateDiffF func(ctx context.Context, startHeight, endHeight uint64, f func(key []byte, value []byte) error) error
+}
+
+func (vm *TestStateDiffer) GetStateDiff(ctx context.Context, startHeight, endHeight uint64, f func(key []byte, value []byte) error) error {
+	if vm.GetStateDiffF != nil {
+		return vm.GetStateDiffF(ctx, startHeight, endHeight, f)
+	}
+	if vm.CantGetStateDiff && vm.T != nil {
+		vm.T.Fatal(errGetStateDiff)
+	}
+	return errGetStateDiff
+}
@@ -18,13 +18,20 @@ import (
 	"github.com/ava-labs/avalanchego/snow/choices"
 	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
 	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/snow/engine/common/tracker"
 	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
 	"github.com/ava-labs/avalanchego/utils/timer"
 	"github.com/ava-labs/avalanchego/version"
 )
 
-// Parameters for delaying bootstrapping to avoid potential CPU burns
-const bootstrappingDelay = 10 * time.Second
+const (
+	// Parameters for delaying bootstrapping to avoid potential CPU burns
+	bootstrappingDelay = 10 * time.Second
+
+	// Halflife of the history used to prefer peers that respond quickly and
+	// reliably to GetAncestors requests
+	peerSLAHalflife = time.Minute
+)
 
 var (
 	_ common.BootstrapableEngine = (*bootstrapper)(nil)
@@ -74,6 +81,10 @@ type bootstrapper struct {
 	// again.
 	fetchFrom ids.NodeIDSet
 
+	// peerSLA tracks how well the peers have responded to our GetAncestors
+	// requests. The best peer in [fetchFrom] is sent the next request.
+	peerSLA tracker.PeerSLA
+
 	// bootstrappedOnce ensures that the [Bootstrapped] callback is only invoked
 	// once, even if bootstrapping is retried.
 	bootstrappedOnce sync.Once
@@ -99,6 +110,7 @@ func New(ctx context.Context, config Config, onFinished func(ctx context.Context
 			OnFinished: onFinished,
 		},
 		executedStateTransitions: math.MaxInt32,
+		peerSLA:                  tracker.NewPeerSLA(peerSLAHalflife),
 	}
 
 	b.parser = &parser{
@@ -166,6 +178,7 @@ func (b *bootstrapper) Ancestors(ctx context.Context, nodeID ids.NodeID, request
 			zap.Uint32("requestID", requestID),
 		)
 
+		b.peerSLA.RequestFailed(nodeID, requestID)
 		b.markUnavailable(nodeID)
 
 		// Send another request for this
@@ -191,6 +204,7 @@ func (b *bootstrapper) Ancestors(ctx context.Context, nodeID ids.NodeID, request
 			zap.Uint32("requestID", requestID),
 			zap.Error(err),
 		)
+		b.peerSLA.RequestFailed(nodeID, requestID)
 		return b.fetch(ctx, wantedBlkID)
 	}
 
@@ -199,6 +213,7 @@ func (b *bootstrapper) Ancestors(ctx context.Context, nodeID ids.NodeID, request
 			zap.Stringer("nodeID", nodeID),
 			zap.Uint32("requestID", requestID),
 		)
+		b.peerSLA.RequestFailed(nodeID, requestID)
 		return b.fetch(ctx, wantedBlkID)
 	}
 
@@ -208,8 +223,10 @@ func (b *bootstrapper) Ancestors(ctx context.Context, nodeID ids.NodeID, request
 			zap.Stringer("expectedBlkID", wantedBlkID),
 			zap.Stringer("blkID", actualID),
 		)
+		b.peerSLA.RequestFailed(nodeID, requestID)
 		return b.fetch(ctx, wantedBlkID)
 	}
+	b.peerSLA.ResponseReceived(nodeID, requestID)
 
 	blockSet := make(map[ids.ID]snowman.Block, len(blocks))
 	for _, block := range blocks[1:] {
@@ -229,12 +246,17 @@ func (b *bootstrapper) GetAncestorsFailed(ctx context.Context, nodeID ids.NodeID
 	}
 
 	// This node timed out their request, so we can add them back to [fetchFrom]
+	b.peerSLA.RequestFailed(nodeID, requestID)
 	b.fetchFrom.Add(nodeID)
 
 	// Send another request for this
 	return b.fetch(ctx, blkID)
 }
 
+func (b *bootstrapper) AppRequestFailedWithError(ctx context.Context, nodeID ids.NodeID, requestID uint32, appErr *common.AppError) error {
+	return common.NotifyAppRequestFailed(ctx, b.AppHandler, nodeID, requestID, appErr)
+}
+
 func (b *bootstrapper) Connected(ctx context.Context, nodeID ids.NodeID, nodeVersion *version.Application) error {
 	if err := b.VM.Connected(ctx, nodeID, nodeVersion); err != nil {
 		return err
@@ -362,7 +384,7 @@ func (b *bootstrapper) fetch(ctx context.Context, blkID ids.ID) error {
 		return b.checkFinish(ctx)
 	}
 
-	validatorID, ok := b.fetchFrom.Peek()
+	validatorID, ok := b.peerSLA.Preferred(b.fetchFrom.List())
 	if !ok {
 		return fmt.Errorf("dropping request for %s as there are no validators", blkID)
 	}
@@ -371,6 +393,7 @@ func (b *bootstrapper) fetch(ctx context.Context, blkID ids.ID) error {
 	b.markUnavailable(validatorID)
 
 	b.Config.SharedCfg.RequestID++
+	b.peerSLA.RequestSent(validatorID, b.Config.SharedCfg.RequestID)
 
 	b.OutstandingRequests.Add(validatorID, b.Config.SharedCfg.RequestID, blkID)
 	b.Config.Sender.SendGetAncestors(ctx, validatorID, b.Config.SharedCfg.RequestID, blkID) // request block and ancestors
@@ -546,14 +569,20 @@ func (b *bootstrapper) checkFinish(ctx context.Context) error {
 		)
 	}
 
-	executedBlocks, err := b.Blocked.ExecuteAll(
-		ctx,
-		b.Config.Ctx,
-		b,
-		b.Config.SharedCfg.Restarted,
-		b.Ctx.ConsensusAcceptor,
-		b.Ctx.DecisionAcceptor,
-	)
+	// The blocks accepted while executing are sent to the VM in batches.
+	var executedBlocks int
+	err := block.BatchDecisions(ctx, b.VM, func(ctx context.Context) error {
+		var err error
+		executedBlocks, err = b.Blocked.ExecuteAll(
+			ctx,
+			b.Config.Ctx,
+			b,
+			b.Config.SharedCfg.Restarted,
+			b.Ctx.ConsensusAcceptor,
+			b.Ctx.DecisionAcceptor,
+		)
+		return err
+	})
 	if err != nil || b.Halted() {
 		return err
 	}
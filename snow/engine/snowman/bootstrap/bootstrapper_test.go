@@ -297,6 +297,96 @@ func TestBootstrapperSingleFrontier(t *testing.T) {
 // Requests again and gets response from unexpected peer.
 // Requests again and gets an unexpected block.
 // Requests again and gets the expected block.
+// decisionBatchingVM counts the calls to BatchDecisions.
+type decisionBatchingVM struct {
+	*block.TestVM
+
+	batches int
+}
+
+func (vm *decisionBatchingVM) BatchDecisions(ctx context.Context, f func(context.Context) error) error {
+	vm.batches++
+	return f(ctx)
+}
+
+func TestBootstrapperBatchesDecisions(t *testing.T) {
+	require := require.New(t)
+
+	config, _, _, vm := newConfig(t)
+	batchingVM := &decisionBatchingVM{TestVM: vm}
+	config.VM = batchingVM
+
+	blk0 := &snowman.TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.Empty.Prefix(0),
+			StatusV: choices.Accepted,
+		},
+		HeightV: 0,
+		BytesV:  []byte{0},
+	}
+	blk1 := &snowman.TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.Empty.Prefix(1),
+			StatusV: choices.Processing,
+		},
+		ParentV: blk0.IDV,
+		HeightV: 1,
+		BytesV:  []byte{1},
+	}
+	blk2 := &snowman.TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.Empty.Prefix(2),
+			StatusV: choices.Processing,
+		},
+		ParentV: blk1.IDV,
+		HeightV: 2,
+		BytesV:  []byte{2},
+	}
+	blks := []*snowman.TestBlock{blk0, blk1, blk2}
+
+	vm.CantLastAccepted = false
+	vm.LastAcceptedF = func(context.Context) (ids.ID, error) {
+		return blk0.ID(), nil
+	}
+	vm.GetBlockF = func(_ context.Context, blkID ids.ID) (snowman.Block, error) {
+		for _, blk := range blks {
+			if blk.ID() == blkID {
+				return blk, nil
+			}
+		}
+		return nil, database.ErrNotFound
+	}
+	vm.ParseBlockF = func(_ context.Context, blkBytes []byte) (snowman.Block, error) {
+		for _, blk := range blks {
+			if bytes.Equal(blk.Bytes(), blkBytes) {
+				return blk, nil
+			}
+		}
+		return nil, errUnknownBlock
+	}
+
+	bs, err := New(
+		context.Background(),
+		config,
+		func(context.Context, uint32) error {
+			config.Ctx.SetState(snow.NormalOp)
+			return nil
+		},
+	)
+	require.NoError(err)
+
+	vm.CantSetState = false
+	require.NoError(bs.Start(context.Background(), 0))
+
+	// Both blocks are accepted while executing, so they're accepted in a
+	// single batch.
+	require.NoError(bs.ForceAccepted(context.Background(), []ids.ID{blk2.ID()}))
+	require.Equal(snow.State(snow.NormalOp), config.Ctx.GetState())
+	require.Equal(choices.Accepted, blk1.Status())
+	require.Equal(choices.Accepted, blk2.Status())
+	require.Equal(1, batchingVM.batches)
+}
+
 func TestBootstrapperUnknownByzantineResponse(t *testing.T) {
 	config, peerID, sender, vm := newConfig(t)
 
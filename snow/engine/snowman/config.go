@@ -4,10 +4,14 @@
 package snowman
 
 import (
+	"context"
+	"time"
+
 	"github.com/ava-labs/avalanchego/snow"
 	"github.com/ava-labs/avalanchego/snow/consensus/snowball"
 	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
 	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/snow/engine/common/decisionlog"
 	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
 	"github.com/ava-labs/avalanchego/snow/validators"
 )
@@ -22,4 +26,31 @@ type Config struct {
 	Validators validators.Set
 	Params     snowball.Parameters
 	Consensus  snowman.Consensus
+	// Delivers the timeouts used to hedge queries. Hedging is disabled if nil.
+	Timer common.Timer
+
+	// If non-zero, the chain is considered stale if it hasn't accepted a block
+	// for this long while a majority of its stake has accepted blocks that it
+	// hasn't accepted.
+	StaleTimeout time.Duration
+	// If non-nil, called when the chain becomes stale to bootstrap the chain
+	// again. The engine is restarted once the chain is bootstrapped.
+	Resync func(ctx context.Context, startReqID uint32) error
+	// If non-nil, staleness incidents are recorded in this log.
+	Incidents common.IncidentLog
+	// If non-nil, the polls, votes and decisions of the engine are recorded
+	// in this log. The log is closed when the engine shuts down.
+	Decisions decisionlog.Log
+
+	// If non-zero, a poll that hasn't finished after this long is hedged: the
+	// query is sent again to backup validators, sampled by stake. A backup
+	// that answers before the validator it backs up takes one of its votes.
+	HedgeDelay time.Duration
+	// Maximum number of backup validators queried for each poll. If 0,
+	// queries aren't hedged.
+	HedgeBudget int
+
+	// Paces the blocks the VM is asked to build. Blocks are built as soon as
+	// the VM has pending txs if the min interval is 0 or [Timer] is nil.
+	BuildPacing block.BuildPacing
 }
@@ -0,0 +1,205 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package snowman
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+// hedgedPoll tracks the queries of a poll that may be hedged. A backup
+// validator is sampled by stake, like the validators of the poll. If the backup
+// answers before the validator it backs up, one of the votes of that validator
+// is moved to the backup, and the backup's answer is counted under its own ID.
+type hedgedPoll struct {
+	blkID  ids.ID
+	issued time.Time
+	hedged bool
+	// Validators that were queried, including the backups
+	polled ids.NodeIDSet
+	// Sampled validators that haven't answered yet, nor been answered for by
+	// a backup
+	pending ids.NodeIDSet
+	// Outstanding backup -> the sampled validator it backs up
+	backups map[ids.NodeID]ids.NodeID
+	// Outstanding backups that were given a vote of the validator they back
+	// up, because that validator failed to answer
+	substituted ids.NodeIDSet
+}
+
+func (p *hedgedPoll) done() bool {
+	return p.pending.Len() == 0 && len(p.backups) == 0
+}
+
+func (t *Transitive) hedgingEnabled() bool {
+	return t.HedgeDelay > 0 && t.HedgeBudget > 0 && t.Timer != nil
+}
+
+// trackHedge starts tracking the poll [t.RequestID] about [blkID] that was sent
+// to [vdrs], so that it can be hedged once [t.HedgeDelay] elapses.
+func (t *Transitive) trackHedge(blkID ids.ID, vdrs []ids.NodeID) {
+	if !t.hedgingEnabled() {
+		return
+	}
+
+	p := &hedgedPoll{
+		blkID:   blkID,
+		issued:  t.clock.Time(),
+		polled:  ids.NewNodeIDSet(len(vdrs)),
+		pending: ids.NewNodeIDSet(len(vdrs)),
+		backups: make(map[ids.NodeID]ids.NodeID),
+	}
+	p.polled.Add(vdrs...)
+	p.pending.Add(vdrs...)
+	t.hedges[t.RequestID] = p
+	t.Timer.RegisterTimeout(t.HedgeDelay)
+}
+
+// hedgedResponse returns false if the answer of [nodeID] to the poll
+// [requestID] should be dropped, because it's the answer of a backup whose
+// validator already answered.
+func (t *Transitive) hedgedResponse(nodeID ids.NodeID, requestID uint32) bool {
+	p, ok := t.hedges[requestID]
+	if !ok {
+		return true
+	}
+	defer t.forgetHedgeIfDone(requestID, p)
+
+	original, isBackup := p.backups[nodeID]
+	if !isBackup {
+		p.pending.Remove(nodeID)
+		return true
+	}
+	delete(p.backups, nodeID)
+	if p.substituted.Contains(nodeID) {
+		p.substituted.Remove(nodeID)
+		t.metrics.numHedgedResponses.Inc()
+		return true
+	}
+	if !p.pending.Contains(original) || !t.polls.Substitute(requestID, original, nodeID) {
+		return false
+	}
+	// The remaining votes of [original], if any, are counted as usual.
+	p.pending.Remove(original)
+	t.metrics.numHedgedResponses.Inc()
+	return true
+}
+
+// hedgedFailure returns false if the failure of the query sent to [nodeID] for
+// the poll [requestID] should be dropped, because it's the failure of a backup
+// that wasn't given a vote.
+func (t *Transitive) hedgedFailure(nodeID ids.NodeID, requestID uint32) bool {
+	p, ok := t.hedges[requestID]
+	if !ok {
+		return true
+	}
+	defer t.forgetHedgeIfDone(requestID, p)
+
+	if _, isBackup := p.backups[nodeID]; isBackup {
+		delete(p.backups, nodeID)
+		if !p.substituted.Contains(nodeID) {
+			return false
+		}
+		p.substituted.Remove(nodeID)
+		return true
+	}
+
+	if !p.pending.Contains(nodeID) {
+		return true
+	}
+	p.pending.Remove(nodeID)
+	// The backups of [nodeID] may still answer for one of its votes each.
+	for backup, original := range p.backups {
+		if original == nodeID && t.polls.Substitute(requestID, nodeID, backup) {
+			p.substituted.Add(backup)
+		}
+	}
+	return true
+}
+
+func (t *Transitive) forgetHedgeIfDone(requestID uint32, p *hedgedPoll) {
+	if p.done() {
+		delete(t.hedges, requestID)
+	}
+}
+
+// sendHedges sends the backup queries of the polls that have been waiting for
+// [t.HedgeDelay]. Each poll is hedged at most once, with up to
+// [t.HedgeBudget] backups.
+func (t *Transitive) sendHedges(ctx context.Context) {
+	now := t.clock.Time()
+	for requestID, p := range t.hedges {
+		if t.polls.Finished(requestID) {
+			// Answers to this poll no longer matter, so they don't need to be
+			// translated.
+			delete(t.hedges, requestID)
+			continue
+		}
+		if p.hedged || now.Sub(p.issued) < t.HedgeDelay {
+			continue
+		}
+		p.hedged = true
+
+		numBackups := t.HedgeBudget
+		if pending := p.pending.Len(); pending < numBackups {
+			numBackups = pending
+		}
+		backups := t.sampleBackups(p, numBackups)
+		if backups.Len() == 0 {
+			t.Ctx.Log.Debug("dropped hedged query",
+				zap.String("reason", "no unpolled validators"),
+				zap.Uint32("requestID", requestID),
+				zap.Stringer("blkID", p.blkID),
+			)
+			continue
+		}
+
+		t.Ctx.Log.Verbo("hedging query",
+			zap.Uint32("requestID", requestID),
+			zap.Stringer("blkID", p.blkID),
+			zap.Stringer("backups", backups),
+		)
+		t.metrics.numHedgedQueries.Add(float64(backups.Len()))
+		t.Sender.SendPullQuery(ctx, backups, requestID, p.blkID)
+	}
+}
+
+// sampleBackups returns up to [numBackups] validators that weren't queried by
+// [p], sampled by stake, and assigns each of them a pending validator of [p] to
+// back up.
+func (t *Transitive) sampleBackups(p *hedgedPoll, numBackups int) ids.NodeIDSet {
+	backups := ids.NewNodeIDSet(numBackups)
+	if numBackups == 0 {
+		return backups
+	}
+	// Sampling [numBackups] more validators than the poll sampled ensures that
+	// some of them weren't polled when the validators have similar weights.
+	sampleSize := uint64(t.Params.K + numBackups)
+	if weight := t.Validators.Weight(); weight < sampleSize {
+		sampleSize = weight
+	}
+	vdrs, err := t.Validators.Sample(int(sampleSize))
+	if err != nil {
+		return backups
+	}
+
+	originals := p.pending.List()
+	for _, vdr := range vdrs {
+		if backups.Len() == numBackups {
+			break
+		}
+		backup := vdr.ID()
+		if p.polled.Contains(backup) {
+			continue
+		}
+		p.polled.Add(backup)
+		p.backups[backup] = originals[backups.Len()]
+		backups.Add(backup)
+	}
+	return backups
+}
@@ -0,0 +1,234 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package snowman
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowball"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+)
+
+// setupHedging returns an engine with 4 validators that polls 3 of them, and
+// needs all 3 answers to finish a poll, along with a processing block.
+func setupHedging(t *testing.T) (*common.SenderTest, *common.TimerTest, *Transitive, *snowman.TestBlock) {
+	require := require.New(t)
+
+	timer := &common.TimerTest{T: t}
+	commonCfg := common.DefaultConfigTest()
+	engCfg := DefaultConfigs()
+	engCfg.Params = snowball.Parameters{
+		K:                       3,
+		Alpha:                   3,
+		BetaVirtuous:            1,
+		BetaRogue:               2,
+		ConcurrentRepolls:       1,
+		OptimalProcessing:       1,
+		MaxOutstandingItems:     1,
+		MaxItemProcessingTime:   1,
+		MixedQueryNumPushNonVdr: 3,
+	}
+	engCfg.Timer = timer
+	engCfg.HedgeDelay = time.Second
+	engCfg.HedgeBudget = 1
+	_, vals, sender, vm, te, gBlk := setup(t, commonCfg, engCfg)
+	for i := 0; i < 3; i++ {
+		require.NoError(vals.AddWeight(ids.GenerateTestNodeID(), 1))
+	}
+
+	blk := &snowman.TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.GenerateTestID(),
+			StatusV: choices.Processing,
+		},
+		ParentV: gBlk.ID(),
+		HeightV: 1,
+		BytesV:  []byte{1},
+	}
+	vm.GetBlockF = func(_ context.Context, blkID ids.ID) (snowman.Block, error) {
+		switch blkID {
+		case gBlk.ID():
+			return gBlk, nil
+		case blk.ID():
+			return blk, nil
+		default:
+			return nil, errUnknownBlock
+		}
+	}
+	return sender, timer, te, blk
+}
+
+func TestHedgedQuery(t *testing.T) {
+	require := require.New(t)
+
+	sender, timer, te, blk := setupHedging(t)
+
+	registered := time.Duration(0)
+	timer.RegisterTimeoutF = func(d time.Duration) {
+		registered = d
+	}
+	var (
+		requestID uint32
+		polled    ids.NodeIDSet
+	)
+	sender.SendPushQueryF = func(_ context.Context, vdrs ids.NodeIDSet, reqID uint32, _ []byte) {
+		requestID = reqID
+		polled = vdrs
+	}
+	now := time.Now()
+	te.clock.Set(now)
+	require.NoError(te.issue(context.Background(), blk))
+	require.Equal(time.Second, registered)
+	require.Equal(3, polled.Len())
+
+	// Two of the polled validators answer quickly, and the third is slow.
+	vdrs := polled.List()
+	slow := vdrs[2]
+	require.NoError(te.Chits(context.Background(), vdrs[0], requestID, []ids.ID{blk.ID()}))
+	require.NoError(te.Chits(context.Background(), vdrs[1], requestID, []ids.ID{blk.ID()}))
+
+	// The query isn't hedged before the delay.
+	sender.SendPullQueryF = func(context.Context, ids.NodeIDSet, uint32, ids.ID) {
+		require.FailNow("hedged too early")
+	}
+	require.NoError(te.Timeout(context.Background()))
+
+	var backups ids.NodeIDSet
+	sender.SendPullQueryF = func(_ context.Context, vdrs ids.NodeIDSet, reqID uint32, blkID ids.ID) {
+		require.Equal(requestID, reqID)
+		require.Equal(blk.ID(), blkID)
+		backups = vdrs
+	}
+	te.clock.Set(now.Add(time.Second))
+	require.NoError(te.Timeout(context.Background()))
+	require.Equal(1, backups.Len())
+	backup := backups.List()[0]
+	require.False(polled.Contains(backup))
+
+	// The query is only hedged once.
+	backups = nil
+	require.NoError(te.Timeout(context.Background()))
+	require.Nil(backups)
+
+	// The backup answers for the vote of the slow validator.
+	require.NoError(te.Chits(context.Background(), backup, requestID, []ids.ID{blk.ID()}))
+	require.Equal(choices.Accepted, blk.Status())
+	require.Empty(te.hedges)
+
+	// The late answer of the slow validator is ignored.
+	require.NoError(te.Chits(context.Background(), slow, requestID, []ids.ID{blk.ID()}))
+}
+
+func TestHedgedQueryFailures(t *testing.T) {
+	require := require.New(t)
+
+	sender, timer, te, blk := setupHedging(t)
+	timer.RegisterTimeoutF = func(time.Duration) {}
+
+	var (
+		requestID uint32
+		polled    ids.NodeIDSet
+		backups   ids.NodeIDSet
+	)
+	sender.SendPushQueryF = func(_ context.Context, vdrs ids.NodeIDSet, reqID uint32, _ []byte) {
+		requestID = reqID
+		polled = vdrs
+	}
+	sender.SendPullQueryF = func(_ context.Context, vdrs ids.NodeIDSet, _ uint32, _ ids.ID) {
+		backups = vdrs
+	}
+	now := time.Now()
+	te.clock.Set(now)
+	require.NoError(te.issue(context.Background(), blk))
+
+	vdrs := polled.List()
+	require.NoError(te.Chits(context.Background(), vdrs[0], requestID, []ids.ID{blk.ID()}))
+	require.NoError(te.Chits(context.Background(), vdrs[1], requestID, []ids.ID{blk.ID()}))
+
+	te.clock.Set(now.Add(time.Second))
+	require.NoError(te.Timeout(context.Background()))
+	require.Equal(1, backups.Len())
+	backup := backups.List()[0]
+
+	// The vote of the slow validator is given to its backup when it fails.
+	require.NoError(te.QueryFailed(context.Background(), vdrs[2], requestID))
+	require.False(te.polls.Finished(requestID))
+
+	// Once the backup fails too, its vote is dropped from the poll.
+	require.NoError(te.QueryFailed(context.Background(), backup, requestID))
+	require.True(te.polls.Finished(requestID))
+	require.NotContains(te.hedges, requestID)
+}
+
+func TestHedgedQueryValidatorAnswersFirst(t *testing.T) {
+	require := require.New(t)
+
+	sender, timer, te, blk := setupHedging(t)
+	timer.RegisterTimeoutF = func(time.Duration) {}
+
+	var (
+		requestID uint32
+		polled    ids.NodeIDSet
+		backups   ids.NodeIDSet
+	)
+	sender.SendPushQueryF = func(_ context.Context, vdrs ids.NodeIDSet, reqID uint32, _ []byte) {
+		requestID = reqID
+		polled = vdrs
+	}
+	sender.SendPullQueryF = func(_ context.Context, vdrs ids.NodeIDSet, _ uint32, _ ids.ID) {
+		backups = vdrs
+	}
+	now := time.Now()
+	te.clock.Set(now)
+	require.NoError(te.issue(context.Background(), blk))
+
+	vdrs := polled.List()
+	require.NoError(te.Chits(context.Background(), vdrs[0], requestID, []ids.ID{blk.ID()}))
+
+	te.clock.Set(now.Add(time.Second))
+	require.NoError(te.Timeout(context.Background()))
+	require.Equal(1, backups.Len())
+	backup := backups.List()[0]
+	original := te.hedges[requestID].backups[backup]
+
+	// The validator answers before its backup, so the backup's answer is
+	// dropped.
+	require.NoError(te.Chits(context.Background(), original, requestID, []ids.ID{blk.ID()}))
+	require.NoError(te.Chits(context.Background(), backup, requestID, []ids.ID{blk.ID()}))
+	require.False(te.polls.Finished(requestID))
+	require.Equal(choices.Processing, blk.Status())
+}
+
+func TestHedgedQuerySamplesUnpolledValidators(t *testing.T) {
+	require := require.New(t)
+
+	_, _, te, _ := setupHedging(t)
+	// Sample every validator as a candidate.
+	te.Params.K = te.Validators.Len() - 1
+
+	vdrs := te.Validators.List()
+	p := &hedgedPoll{
+		polled:  ids.NewNodeIDSet(len(vdrs)),
+		pending: ids.NewNodeIDSet(1),
+		backups: make(map[ids.NodeID]ids.NodeID),
+	}
+	for _, vdr := range vdrs[1:] {
+		p.polled.Add(vdr.ID())
+	}
+	slow := vdrs[1].ID()
+	p.pending.Add(slow)
+
+	backups := te.sampleBackups(p, 1)
+	unpolled := vdrs[0].ID()
+	require.Equal(ids.NodeIDSet{unpolled: struct{}{}}, backups)
+	require.Equal(slow, p.backups[unpolled])
+	require.True(p.polled.Contains(unpolled))
+}
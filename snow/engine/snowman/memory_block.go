@@ -7,6 +7,7 @@ import (
 	"context"
 
 	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
+	"github.com/ava-labs/avalanchego/snow/engine/common/decisionlog"
 )
 
 var _ snowman.Block = (*memoryBlock)(nil)
@@ -15,14 +16,20 @@ var _ snowman.Block = (*memoryBlock)(nil)
 type memoryBlock struct {
 	snowman.Block
 
-	tree    AncestorTree
-	metrics *metrics
+	tree      AncestorTree
+	metrics   *metrics
+	decisions decisionlog.Log
 }
 
 // Accept accepts the underlying block & removes sibling subtrees
 func (mb *memoryBlock) Accept(ctx context.Context) error {
 	mb.tree.RemoveSubtree(mb.Parent())
 	mb.metrics.numNonVerifieds.Set(float64(mb.tree.Len()))
+	mb.decisions.Record(decisionlog.Entry{
+		Kind:   decisionlog.Accepted,
+		BlkID:  mb.ID(),
+		Height: mb.Height(),
+	})
 	return mb.Block.Accept(ctx)
 }
 
@@ -30,5 +37,10 @@ func (mb *memoryBlock) Accept(ctx context.Context) error {
 func (mb *memoryBlock) Reject(ctx context.Context) error {
 	mb.tree.RemoveSubtree(mb.ID())
 	mb.metrics.numNonVerifieds.Set(float64(mb.tree.Len()))
+	mb.decisions.Record(decisionlog.Entry{
+		Kind:   decisionlog.Rejected,
+		BlkID:  mb.ID(),
+		Height: mb.Height(),
+	})
 	return mb.Block.Reject(ctx)
 }
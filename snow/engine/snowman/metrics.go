@@ -13,6 +13,8 @@ import (
 type metrics struct {
 	bootstrapFinished, numRequests, numBlocked, numBlockers, numNonVerifieds prometheus.Gauge
 	numBuilt, numBuildsFailed, numUselessPutBytes, numUselessPushQueryBytes  prometheus.Counter
+	numHedgedQueries, numHedgedResponses                                     prometheus.Counter
+	numBuildsPaced                                                           prometheus.Counter
 	getAncestorsBlks                                                         metric.Averager
 }
 
@@ -71,6 +73,21 @@ func (m *metrics) Initialize(namespace string, reg prometheus.Registerer) error
 		Name:      "non_verified_blks",
 		Help:      "Number of non-verified blocks in the memory",
 	})
+	m.numHedgedQueries = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "hedged_queries",
+		Help:      "Number of queries sent to backup validators because a poll was slow",
+	})
+	m.numHedgedResponses = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "hedged_responses",
+		Help:      "Number of answers of backup validators that were counted for a sampled validator",
+	})
+	m.numBuildsPaced = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "blk_builds_paced",
+		Help:      "Number of times building a block was delayed to respect the min build interval",
+	})
 
 	errs.Add(
 		reg.Register(m.bootstrapFinished),
@@ -82,6 +99,9 @@ func (m *metrics) Initialize(namespace string, reg prometheus.Registerer) error
 		reg.Register(m.numBuildsFailed),
 		reg.Register(m.numUselessPutBytes),
 		reg.Register(m.numUselessPushQueryBytes),
+		reg.Register(m.numHedgedQueries),
+		reg.Register(m.numHedgedResponses),
+		reg.Register(m.numBuildsPaced),
 	)
 	return errs.Err
 }
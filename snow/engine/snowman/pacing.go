@@ -0,0 +1,39 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package snowman
+
+import "time"
+
+// buildPacing tracks the requests of the engine to build blocks, so that they
+// are spaced by at least the min build interval.
+type buildPacing struct {
+	// Time of the last request to build a block
+	lastBuild time.Time
+	// True if a timeout is registered to build the pending blocks once the
+	// min build interval elapses
+	scheduled bool
+}
+
+func (t *Transitive) pacingEnabled() bool {
+	return t.BuildPacing.MinInterval > 0 && t.Timer != nil
+}
+
+// paceBuild returns true if the next block can't be built yet because the min
+// build interval hasn't elapsed since the last block was built. The block is
+// then built once the interval elapses.
+func (t *Transitive) paceBuild() bool {
+	if !t.pacingEnabled() {
+		return false
+	}
+	wait := t.BuildPacing.MinInterval - t.clock.Time().Sub(t.pacing.lastBuild)
+	if wait <= 0 {
+		return false
+	}
+	if !t.pacing.scheduled {
+		t.pacing.scheduled = true
+		t.metrics.numBuildsPaced.Inc()
+		t.Timer.RegisterTimeout(wait)
+	}
+	return true
+}
@@ -0,0 +1,124 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package snowman
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
+)
+
+func TestBuildPacing(t *testing.T) {
+	require := require.New(t)
+
+	timer := &common.TimerTest{T: t}
+	commonCfg := common.DefaultConfigTest()
+	engCfg := DefaultConfigs()
+	engCfg.Timer = timer
+	engCfg.BuildPacing = block.BuildPacing{
+		MinInterval: time.Second,
+	}
+	_, _, sender, vm, te, gBlk := setup(t, commonCfg, engCfg)
+	sender.Default(false)
+
+	vm.GetBlockF = func(_ context.Context, blkID ids.ID) (snowman.Block, error) {
+		if blkID == gBlk.ID() {
+			return gBlk, nil
+		}
+		return nil, errUnknownBlock
+	}
+	built := 0
+	vm.BuildBlockF = func(context.Context) (snowman.Block, error) {
+		built++
+		return &snowman.TestBlock{
+			TestDecidable: choices.TestDecidable{
+				IDV:     ids.GenerateTestID(),
+				StatusV: choices.Processing,
+			},
+			ParentV: gBlk.ID(),
+			HeightV: 1,
+			BytesV:  []byte{byte(built)},
+		}, nil
+	}
+	var registered []time.Duration
+	timer.RegisterTimeoutF = func(d time.Duration) {
+		registered = append(registered, d)
+	}
+
+	now := time.Now()
+	te.clock.Set(now)
+
+	// The first block is built right away.
+	require.NoError(te.Notify(context.Background(), common.PendingTxs))
+	require.Equal(1, built)
+	require.Empty(registered)
+
+	// The next block waits for the min interval to elapse, and the
+	// notifications sent in the meantime are coalesced.
+	te.clock.Set(now.Add(100 * time.Millisecond))
+	require.NoError(te.Notify(context.Background(), common.PendingTxs))
+	require.NoError(te.Notify(context.Background(), common.PendingTxs))
+	require.Equal(1, built)
+	require.Equal([]time.Duration{900 * time.Millisecond}, registered)
+
+	// A timeout that fires early, such as a hedging timeout, doesn't build
+	// the block.
+	te.clock.Set(now.Add(500 * time.Millisecond))
+	require.NoError(te.Timeout(context.Background()))
+	require.Equal(1, built)
+	require.Equal([]time.Duration{900 * time.Millisecond, 500 * time.Millisecond}, registered)
+
+	te.clock.Set(now.Add(time.Second))
+	require.NoError(te.Timeout(context.Background()))
+	require.Equal(2, built)
+
+	// Nothing is left to build.
+	require.NoError(te.Timeout(context.Background()))
+	require.Equal(2, built)
+	require.Len(registered, 2)
+}
+
+func TestBuildPacingDisabledWithoutTimer(t *testing.T) {
+	require := require.New(t)
+
+	commonCfg := common.DefaultConfigTest()
+	engCfg := DefaultConfigs()
+	engCfg.BuildPacing = block.BuildPacing{
+		MinInterval: time.Second,
+	}
+	_, _, sender, vm, te, gBlk := setup(t, commonCfg, engCfg)
+	sender.Default(false)
+
+	vm.GetBlockF = func(_ context.Context, blkID ids.ID) (snowman.Block, error) {
+		if blkID == gBlk.ID() {
+			return gBlk, nil
+		}
+		return nil, errUnknownBlock
+	}
+	built := 0
+	vm.BuildBlockF = func(context.Context) (snowman.Block, error) {
+		built++
+		return &snowman.TestBlock{
+			TestDecidable: choices.TestDecidable{
+				IDV:     ids.GenerateTestID(),
+				StatusV: choices.Processing,
+			},
+			ParentV: gBlk.ID(),
+			HeightV: 1,
+			BytesV:  []byte{byte(built)},
+		}, nil
+	}
+
+	require.NoError(te.Notify(context.Background(), common.PendingTxs))
+	require.NoError(te.Notify(context.Background(), common.PendingTxs))
+	require.Equal(2, built)
+}
@@ -0,0 +1,238 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package snowman
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+)
+
+// ErrStaleChain is reported by the health check of a chain that stopped
+// accepting blocks that its peers have accepted.
+var ErrStaleChain = errors.New("chain is stale")
+
+// staleness tracks whether the chain has fallen behind its peers.
+type staleness struct {
+	// Request ID of the latest requests for the accepted frontiers of the
+	// validators
+	requestID uint32
+	// Last accepted block reported by each validator
+	peerAccepted map[ids.NodeID]ids.ID
+
+	// Last accepted block the last time the staleness was checked, and the
+	// time it was first seen.
+	lastAcceptedID     ids.ID
+	lastAcceptedHeight uint64
+	lastAcceptedTime   time.Time
+
+	// Portion, in [0, 1], of the stake that has accepted blocks that this
+	// chain hasn't accepted, the last time the staleness was checked.
+	stakeAhead float64
+	// Time since which more than half of the stake has been ahead while this
+	// chain didn't accept any block. Zero if less than half of the stake is
+	// ahead.
+	behindSince time.Time
+
+	stale bool
+}
+
+type staleResult struct {
+	LastAcceptedHeight uint64    `json:"lastAcceptedHeight"`
+	LastAcceptedTime   time.Time `json:"lastAcceptedTime"`
+	StakeAhead         float64   `json:"stakeAhead"`
+}
+
+// resetStaleness forgets the blocks accepted by peers and restarts the
+// timeout of the current last accepted block.
+func (t *Transitive) resetStaleness(lastAcceptedID ids.ID, lastAcceptedHeight uint64) {
+	t.staleness.peerAccepted = make(map[ids.NodeID]ids.ID)
+	t.staleness.lastAcceptedID = lastAcceptedID
+	t.staleness.lastAcceptedHeight = lastAcceptedHeight
+	t.staleness.lastAcceptedTime = t.clock.Time()
+	t.staleness.stakeAhead = 0
+	t.staleness.behindSince = time.Time{}
+}
+
+// AcceptedFrontier records the last accepted block of [nodeID], if it's a
+// response to the latest request for the accepted frontiers.
+func (t *Transitive) AcceptedFrontier(_ context.Context, nodeID ids.NodeID, requestID uint32, containerIDs []ids.ID) error {
+	if t.StaleTimeout == 0 || requestID != t.staleness.requestID {
+		return nil
+	}
+	if _, isValidator := t.Validators.GetWeight(nodeID); !isValidator {
+		return nil
+	}
+	if len(containerIDs) == 0 {
+		delete(t.staleness.peerAccepted, nodeID)
+		return nil
+	}
+	t.staleness.peerAccepted[nodeID] = containerIDs[0]
+	return nil
+}
+
+func (*Transitive) GetAcceptedFrontierFailed(context.Context, ids.NodeID, uint32) error {
+	return nil
+}
+
+// requestAcceptedFrontiers requests the last accepted block of a sample of the
+// validators.
+func (t *Transitive) requestAcceptedFrontiers(ctx context.Context) {
+	vdrs, err := t.Validators.Sample(t.Params.K)
+	if err != nil {
+		t.Ctx.Log.Debug("dropped accepted frontier requests",
+			zap.String("reason", "insufficient number of validators"),
+		)
+		return
+	}
+
+	vdrIDs := ids.NodeIDSet{}
+	for _, vdr := range vdrs {
+		if vdr.ID() != t.Ctx.NodeID {
+			vdrIDs.Add(vdr.ID())
+		}
+	}
+	if vdrIDs.Len() == 0 {
+		return
+	}
+
+	t.RequestID++
+	t.staleness.requestID = t.RequestID
+	t.Sender.SendGetAcceptedFrontier(ctx, vdrIDs, t.RequestID)
+}
+
+// checkStaleness updates whether the chain is stale. The chain is stale once
+// more than half of the stake has accepted blocks that the chain hasn't
+// accepted, and the chain hasn't accepted any block, for [StaleTimeout].
+// Incidents are recorded when the chain becomes stale or recovers, and the
+// chain is resynced when it becomes stale if [Resync] is provided.
+func (t *Transitive) checkStaleness(ctx context.Context) error {
+	if t.StaleTimeout == 0 {
+		return nil
+	}
+	// The responses are used the next time the staleness is checked.
+	defer t.requestAcceptedFrontiers(ctx)
+
+	now := t.clock.Time()
+	lastAcceptedID, err := t.VM.LastAccepted(ctx)
+	if err != nil {
+		return err
+	}
+	if lastAcceptedID != t.staleness.lastAcceptedID {
+		lastAccepted, err := t.GetBlock(ctx, lastAcceptedID)
+		if err != nil {
+			return err
+		}
+		t.staleness.lastAcceptedID = lastAcceptedID
+		t.staleness.lastAcceptedHeight = lastAccepted.Height()
+		t.staleness.lastAcceptedTime = now
+	}
+
+	aheadWeight := uint64(0)
+	for nodeID, acceptedID := range t.staleness.peerAccepted {
+		if t.isAccepted(ctx, acceptedID) {
+			continue
+		}
+		// The sum can't overflow, as it is bounded by the total weight.
+		weight, _ := t.Validators.GetWeight(nodeID)
+		aheadWeight += weight
+	}
+	totalWeight := t.Validators.Weight()
+	if totalWeight == 0 {
+		t.staleness.stakeAhead = 0
+	} else {
+		t.staleness.stakeAhead = float64(aheadWeight) / float64(totalWeight)
+	}
+
+	switch {
+	case t.staleness.stakeAhead <= .5:
+		t.staleness.behindSince = time.Time{}
+	case t.staleness.behindSince.Before(t.staleness.lastAcceptedTime):
+		// The timeout restarts whenever a block is accepted.
+		t.staleness.behindSince = now
+	}
+
+	timeBehind := time.Duration(0)
+	if !t.staleness.behindSince.IsZero() {
+		timeBehind = now.Sub(t.staleness.behindSince)
+	}
+	stale := !t.staleness.behindSince.IsZero() && timeBehind >= t.StaleTimeout
+	wasStale := t.staleness.stale
+	t.staleness.stale = stale
+	switch {
+	case stale && !wasStale:
+		t.Ctx.Log.Warn("chain is stale",
+			zap.Uint64("lastAcceptedHeight", t.staleness.lastAcceptedHeight),
+			zap.Duration("timeBehind", timeBehind),
+			zap.Float64("stakeAhead", t.staleness.stakeAhead),
+		)
+		t.recordIncident(common.IncidentStale, fmt.Sprintf(
+			"no block accepted past height %d for %s while %.2f%% of the stake accepted later blocks",
+			t.staleness.lastAcceptedHeight,
+			timeBehind,
+			100*t.staleness.stakeAhead,
+		))
+		if t.Resync == nil {
+			return nil
+		}
+
+		t.Ctx.Log.Info("resyncing stale chain")
+		t.recordIncident(common.IncidentResync, fmt.Sprintf(
+			"bootstrapping again from height %d",
+			t.staleness.lastAcceptedHeight,
+		))
+		return t.Resync(ctx, t.RequestID)
+	case !stale && wasStale:
+		t.Ctx.Log.Info("chain is no longer stale",
+			zap.Uint64("lastAcceptedHeight", t.staleness.lastAcceptedHeight),
+		)
+		t.recordIncident(common.IncidentRecovered, fmt.Sprintf(
+			"accepting blocks again from height %d",
+			t.staleness.lastAcceptedHeight,
+		))
+	}
+	return nil
+}
+
+// isAccepted returns true if this chain accepted [blkID].
+func (t *Transitive) isAccepted(ctx context.Context, blkID ids.ID) bool {
+	blk, err := t.GetBlock(ctx, blkID)
+	return err == nil && blk.Status() == choices.Accepted
+}
+
+// staleHealthCheck reports the staleness as of the last time it was checked.
+func (t *Transitive) staleHealthCheck() (interface{}, error) {
+	if t.StaleTimeout == 0 {
+		return nil, nil
+	}
+
+	result := staleResult{
+		LastAcceptedHeight: t.staleness.lastAcceptedHeight,
+		LastAcceptedTime:   t.staleness.lastAcceptedTime,
+		StakeAhead:         t.staleness.stakeAhead,
+	}
+	if t.staleness.stale {
+		return result, ErrStaleChain
+	}
+	return result, nil
+}
+
+func (t *Transitive) recordIncident(kind, message string) {
+	if t.Incidents == nil {
+		return
+	}
+	t.Incidents.Record(common.Incident{
+		ChainID:   t.Ctx.ChainID,
+		Kind:      kind,
+		Message:   message,
+		Timestamp: t.clock.Time(),
+	})
+}
@@ -0,0 +1,185 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package snowman
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
+)
+
+// setupStaleness returns an engine whose only validator is [vdr], the last
+// accepted block of its VM, and a function that reports [acceptedID] as the
+// last accepted block of [nodeID] in response to the latest accepted frontier
+// request.
+func setupStaleness(t *testing.T, engCfg Config) (ids.NodeID, *block.TestVM, *Transitive, *snowman.Block, func(nodeID ids.NodeID, acceptedID ids.ID)) {
+	commonCfg := common.DefaultConfigTest()
+	engCfg.StaleTimeout = time.Minute
+	vdr, _, sender, vm, te, gBlk := setup(t, commonCfg, engCfg)
+	sender.Default(false)
+	vm.CantHealthCheck = false
+	vm.HealthCheckF = func(context.Context) (interface{}, error) {
+		return nil, nil
+	}
+
+	var requestID uint32
+	sender.SendGetAcceptedFrontierF = func(_ context.Context, nodeIDs ids.NodeIDSet, reqID uint32) {
+		require.True(t, nodeIDs.Contains(vdr))
+		requestID = reqID
+	}
+
+	lastAccepted := gBlk
+	vm.LastAcceptedF = func(context.Context) (ids.ID, error) {
+		return lastAccepted.ID(), nil
+	}
+	vm.GetBlockF = func(_ context.Context, blkID ids.ID) (snowman.Block, error) {
+		if blkID == lastAccepted.ID() {
+			return lastAccepted, nil
+		}
+		return nil, errUnknownBlock
+	}
+
+	report := func(nodeID ids.NodeID, acceptedID ids.ID) {
+		require.NoError(t, te.AcceptedFrontier(context.Background(), nodeID, requestID, []ids.ID{acceptedID}))
+	}
+	return vdr, vm, te, &lastAccepted, report
+}
+
+func TestStaleChainResync(t *testing.T) {
+	require := require.New(t)
+
+	incidents := common.NewIncidentLog()
+	resynced := false
+	engCfg := DefaultConfigs()
+	engCfg.Incidents = incidents
+	engCfg.Resync = func(context.Context, uint32) error {
+		resynced = true
+		return nil
+	}
+	vdr, _, te, lastAccepted, report := setupStaleness(t, engCfg)
+
+	now := time.Now()
+	te.clock.Set(now)
+
+	// The validator accepted a block that this chain doesn't know.
+	peerAccepted := &snowman.TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.GenerateTestID(),
+			StatusV: choices.Accepted,
+		},
+		ParentV: ids.GenerateTestID(),
+		HeightV: 10,
+	}
+	require.NoError(te.Gossip(context.Background()))
+	report(vdr, peerAccepted.ID())
+
+	// The chain isn't stale until it has been behind for the timeout.
+	require.NoError(te.Gossip(context.Background()))
+	_, err := te.HealthCheck(context.Background())
+	require.NoError(err)
+	require.False(resynced)
+
+	te.clock.Set(now.Add(time.Minute))
+	require.NoError(te.Gossip(context.Background()))
+	_, err = te.HealthCheck(context.Background())
+	require.ErrorIs(err, ErrStaleChain)
+	require.True(resynced)
+
+	recorded := incidents.Incidents()
+	require.Len(recorded, 2)
+	require.Equal(common.IncidentStale, recorded[0].Kind)
+	require.Equal(common.IncidentResync, recorded[1].Kind)
+
+	// Once the chain is bootstrapped again, the engine is restarted at the
+	// block the validator accepted.
+	*lastAccepted = peerAccepted
+	require.NoError(te.Start(context.Background(), te.RequestID))
+	require.NoError(te.Gossip(context.Background()))
+	report(vdr, peerAccepted.ID())
+	require.NoError(te.Gossip(context.Background()))
+	_, err = te.HealthCheck(context.Background())
+	require.NoError(err)
+
+	recorded = incidents.Incidents()
+	require.Len(recorded, 3)
+	require.Equal(common.IncidentRecovered, recorded[2].Kind)
+}
+
+func TestStaleChainIdle(t *testing.T) {
+	require := require.New(t)
+
+	vdr, _, te, lastAccepted, report := setupStaleness(t, DefaultConfigs())
+
+	now := time.Now()
+	te.clock.Set(now)
+
+	// The validator accepted the same block as this chain, so the chain isn't
+	// behind, however long it has been since a block was accepted.
+	require.NoError(te.Gossip(context.Background()))
+	report(vdr, (*lastAccepted).ID())
+
+	te.clock.Set(now.Add(time.Hour))
+	require.NoError(te.Gossip(context.Background()))
+	_, err := te.HealthCheck(context.Background())
+	require.NoError(err)
+}
+
+func TestStaleChainIgnoresProcessingBlocks(t *testing.T) {
+	require := require.New(t)
+
+	vdr, vm, te, lastAccepted, report := setupStaleness(t, DefaultConfigs())
+
+	now := time.Now()
+	te.clock.Set(now)
+
+	// The validator sends a block far past the last accepted block, but it
+	// hasn't accepted any block past the last accepted block.
+	blk := &snowman.TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.GenerateTestID(),
+			StatusV: choices.Processing,
+		},
+		ParentV: ids.GenerateTestID(),
+		HeightV: 10,
+		BytesV:  []byte{10},
+	}
+	vm.ParseBlockF = func(context.Context, []byte) (snowman.Block, error) {
+		return blk, nil
+	}
+	require.NoError(te.PushQuery(context.Background(), vdr, 0, blk.Bytes()))
+	require.NoError(te.Gossip(context.Background()))
+	report(vdr, (*lastAccepted).ID())
+
+	te.clock.Set(now.Add(time.Minute))
+	require.NoError(te.Gossip(context.Background()))
+	_, err := te.HealthCheck(context.Background())
+	require.NoError(err)
+}
+
+func TestStaleChainIgnoresNonValidators(t *testing.T) {
+	require := require.New(t)
+
+	_, _, te, _, report := setupStaleness(t, DefaultConfigs())
+
+	now := time.Now()
+	te.clock.Set(now)
+
+	require.NoError(te.Gossip(context.Background()))
+	report(ids.GenerateTestNodeID(), ids.GenerateTestID())
+
+	te.clock.Set(now.Add(time.Minute))
+	require.NoError(te.Gossip(context.Background()))
+	te.clock.Set(now.Add(2 * time.Minute))
+	require.NoError(te.Gossip(context.Background()))
+	_, err := te.HealthCheck(context.Background())
+	require.NoError(err)
+}
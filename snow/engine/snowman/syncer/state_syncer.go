@@ -521,6 +521,10 @@ func (ss *stateSyncer) AppRequestFailed(ctx context.Context, nodeID ids.NodeID,
 	return ss.VM.AppRequestFailed(ctx, nodeID, requestID)
 }
 
+func (ss *stateSyncer) AppRequestFailedWithError(ctx context.Context, nodeID ids.NodeID, requestID uint32, appErr *common.AppError) error {
+	return common.NotifyAppRequestFailed(ctx, ss.VM, nodeID, requestID, appErr)
+}
+
 func (ss *stateSyncer) Notify(ctx context.Context, msg common.Message) error {
 	if msg != common.StateSyncDone {
 		ss.Ctx.Log.Warn("received an unexpected message from the VM",
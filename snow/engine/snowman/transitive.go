@@ -18,7 +18,10 @@ import (
 	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
 	"github.com/ava-labs/avalanchego/snow/consensus/snowman/poll"
 	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/snow/engine/common/decisionlog"
+	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
 	"github.com/ava-labs/avalanchego/snow/events"
+	"github.com/ava-labs/avalanchego/utils/timer/mockable"
 	"github.com/ava-labs/avalanchego/utils/wrappers"
 	"github.com/ava-labs/avalanchego/version"
 )
@@ -40,7 +43,6 @@ type Transitive struct {
 	// list of NoOpsHandler for messages dropped by engine
 	common.StateSummaryFrontierHandler
 	common.AcceptedStateSummaryHandler
-	common.AcceptedFrontierHandler
 	common.AcceptedHandler
 	common.AncestorsHandler
 
@@ -48,6 +50,8 @@ type Transitive struct {
 
 	// track outstanding preference requests
 	polls poll.Set
+	// Request ID --> the queries of a poll that may be hedged
+	hedges map[uint32]*hedgedPoll
 
 	// blocks that have we have sent get requests for but haven't yet received
 	blkReqs common.Requests
@@ -72,6 +76,14 @@ type Transitive struct {
 	// number of times build block needs to be called once the number of
 	// processing blocks has gone below the optimal number.
 	pendingBuildBlocks int
+	// Paces the requests to build blocks
+	pacing buildPacing
+
+	// Tracks whether the chain has fallen behind its peers
+	staleness staleness
+
+	// Useful for faking time in tests
+	clock mockable.Clock
 
 	// errs tracks if an error has occurred in a callback
 	errs wrappers.Errs
@@ -88,22 +100,28 @@ func newTransitive(config Config) (*Transitive, error) {
 	if err != nil {
 		return nil, err
 	}
+	if config.Decisions == nil {
+		config.Decisions = decisionlog.NewNoLog()
+	}
 	factory := poll.NewEarlyTermNoTraversalFactory(config.Params.Alpha)
 	t := &Transitive{
 		Config:                      config,
 		StateSummaryFrontierHandler: common.NewNoOpStateSummaryFrontierHandler(config.Ctx.Log),
 		AcceptedStateSummaryHandler: common.NewNoOpAcceptedStateSummaryHandler(config.Ctx.Log),
-		AcceptedFrontierHandler:     common.NewNoOpAcceptedFrontierHandler(config.Ctx.Log),
 		AcceptedHandler:             common.NewNoOpAcceptedHandler(config.Ctx.Log),
 		AncestorsHandler:            common.NewNoOpAncestorsHandler(config.Ctx.Log),
 		pending:                     make(map[ids.ID]snowman.Block),
 		nonVerifieds:                NewAncestorTree(),
 		nonVerifiedCache:            nonVerifiedCache,
+		staleness: staleness{
+			peerAccepted: make(map[ids.NodeID]ids.ID),
+		},
 		polls: poll.NewSet(factory,
 			config.Ctx.Log,
 			"",
 			config.Ctx.Registerer,
 		),
+		hedges: make(map[uint32]*hedgedPoll),
 	}
 
 	return t, t.metrics.Initialize("", config.Ctx.Registerer)
@@ -242,12 +260,27 @@ func (t *Transitive) Chits(ctx context.Context, nodeID ids.NodeID, requestID uin
 		return t.QueryFailed(ctx, nodeID, requestID)
 	}
 	blkID := votes[0]
+	t.Decisions.Record(decisionlog.Entry{
+		Kind:      decisionlog.VoteReceived,
+		RequestID: requestID,
+		NodeID:    nodeID,
+		BlkID:     blkID,
+	})
 
 	t.Ctx.Log.Verbo("called Chits for the block",
 		zap.Stringer("blkID", blkID),
 		zap.Stringer("nodeID", nodeID),
 		zap.Uint32("requestID", requestID))
 
+	if !t.hedgedResponse(nodeID, requestID) {
+		t.Ctx.Log.Verbo("dropping hedged chits",
+			zap.String("reason", "validator already answered"),
+			zap.Stringer("nodeID", nodeID),
+			zap.Uint32("requestID", requestID),
+		)
+		return nil
+	}
+
 	// Will record chits once [blkID] has been issued into consensus
 	v := &voter{
 		t:         t,
@@ -271,6 +304,14 @@ func (t *Transitive) Chits(ctx context.Context, nodeID ids.NodeID, requestID uin
 }
 
 func (t *Transitive) QueryFailed(ctx context.Context, nodeID ids.NodeID, requestID uint32) error {
+	t.Decisions.Record(decisionlog.Entry{
+		Kind:      decisionlog.QueryFailed,
+		RequestID: requestID,
+		NodeID:    nodeID,
+	})
+	if !t.hedgedFailure(nodeID, requestID) {
+		return nil
+	}
 	t.blocked.Register(
 		ctx,
 		&voter{
@@ -305,6 +346,11 @@ func (t *Transitive) AppRequestFailed(ctx context.Context, nodeID ids.NodeID, re
 	return t.VM.AppRequestFailed(ctx, nodeID, requestID)
 }
 
+func (t *Transitive) AppRequestFailedWithError(ctx context.Context, nodeID ids.NodeID, requestID uint32, appErr *common.AppError) error {
+	// Notify the VM that a request it made failed, and why
+	return common.NotifyAppRequestFailed(ctx, t.VM, nodeID, requestID, appErr)
+}
+
 func (t *Transitive) AppResponse(ctx context.Context, nodeID ids.NodeID, requestID uint32, response []byte) error {
 	// Notify the VM of a response to its request
 	return t.VM.AppResponse(ctx, nodeID, requestID, response)
@@ -320,14 +366,28 @@ func (t *Transitive) Connected(ctx context.Context, nodeID ids.NodeID, nodeVersi
 }
 
 func (t *Transitive) Disconnected(ctx context.Context, nodeID ids.NodeID) error {
+	delete(t.staleness.peerAccepted, nodeID)
 	return t.VM.Disconnected(ctx, nodeID)
 }
 
-func (*Transitive) Timeout(context.Context) error {
-	return nil
+func (t *Transitive) Timeout(ctx context.Context) error {
+	t.sendHedges(ctx)
+	if !t.pacing.scheduled {
+		return nil
+	}
+	t.pacing.scheduled = false
+	return t.buildBlocks(ctx)
 }
 
 func (t *Transitive) Gossip(ctx context.Context) error {
+	if err := t.checkStaleness(ctx); err != nil {
+		return err
+	}
+	if t.Ctx.GetState() != snow.NormalOp {
+		// The chain is being resynced.
+		return nil
+	}
+
 	blkID, err := t.VM.LastAccepted(ctx)
 	if err != nil {
 		return err
@@ -353,7 +413,12 @@ func (*Transitive) Halt(context.Context) {}
 
 func (t *Transitive) Shutdown(ctx context.Context) error {
 	t.Ctx.Log.Info("shutting down consensus engine")
-	return t.VM.Shutdown(ctx)
+	errs := wrappers.Errs{}
+	errs.Add(
+		t.VM.Shutdown(ctx),
+		t.Decisions.Close(),
+	)
+	return errs.Err
 }
 
 func (t *Transitive) Notify(ctx context.Context, msg common.Message) error {
@@ -364,6 +429,12 @@ func (t *Transitive) Notify(ctx context.Context, msg common.Message) error {
 		return nil
 	}
 
+	// The block that is waiting for the min build interval to elapse will
+	// include the txs the VM is notifying about.
+	if t.pacing.scheduled {
+		return nil
+	}
+
 	// the pending txs message means we should attempt to build a block.
 	t.pendingBuildBlocks++
 	return t.buildBlocks(ctx)
@@ -388,6 +459,19 @@ func (t *Transitive) Start(ctx context.Context, startReqID uint32) error {
 		return err
 	}
 
+	// Drop the blocks of any previous run of the engine, as the chain may have
+	// been resynced since.
+	t.pending = make(map[ids.ID]snowman.Block)
+	t.nonVerifieds = NewAncestorTree()
+	t.nonVerifiedCache.Flush()
+	t.blkReqs = common.Requests{}
+	t.blocked = events.Blocker{}
+	t.pendingBuildBlocks = 0
+	t.pacing.scheduled = false
+	t.polls.Clear()
+	t.hedges = make(map[uint32]*hedgedPoll)
+	t.resetStaleness(lastAcceptedID, lastAccepted.Height())
+
 	// initialize consensus to the last accepted blockID
 	if err := t.Consensus.Initialize(t.Ctx, t.Params, lastAcceptedID, lastAccepted.Height(), lastAccepted.Timestamp()); err != nil {
 		return err
@@ -434,10 +518,19 @@ func (t *Transitive) Start(ctx context.Context, startReqID uint32) error {
 func (t *Transitive) HealthCheck(ctx context.Context) (interface{}, error) {
 	consensusIntf, consensusErr := t.Consensus.HealthCheck(ctx)
 	vmIntf, vmErr := t.VM.HealthCheck(ctx)
+	staleIntf, staleErr := t.staleHealthCheck()
 	intf := map[string]interface{}{
 		"consensus": consensusIntf,
 		"vm":        vmIntf,
 	}
+	if staleIntf != nil {
+		intf["stale"] = staleIntf
+	}
+	if staleErr != nil {
+		// The chain being stale is reported on its own, as it is usually the
+		// cause of the other failures.
+		return intf, staleErr
+	}
 	if consensusErr == nil {
 		return intf, vmErr
 	}
@@ -462,6 +555,48 @@ func (t *Transitive) GetBlock(ctx context.Context, blkID ids.ID) (snowman.Block,
 	return t.VM.GetBlock(ctx, blkID)
 }
 
+// batchedGetBlock returns the blocks of [blkIDs] as GetBlock would. The blocks
+// that aren't pending are fetched from the VM at once. If blkIDs[i] couldn't
+// be fetched, the i-th returned block is nil and the i-th returned error is
+// why.
+func (t *Transitive) batchedGetBlock(ctx context.Context, blkIDs []ids.ID) ([]snowman.Block, []error) {
+	var (
+		blks = make([]snowman.Block, len(blkIDs))
+		errs = make([]error, len(blkIDs))
+		// indices of [blkIDs] that must be fetched from the VM
+		vmIndices []int
+		vmIDs     []ids.ID
+	)
+	for i, blkID := range blkIDs {
+		if blk, ok := t.pending[blkID]; ok {
+			blks[i] = blk
+			continue
+		}
+		if blk, ok := t.nonVerifiedCache.Get(blkID); ok {
+			blks[i] = blk.(snowman.Block)
+			continue
+		}
+		vmIndices = append(vmIndices, i)
+		vmIDs = append(vmIDs, blkID)
+	}
+	if len(vmIDs) == 0 {
+		return blks, errs
+	}
+
+	vmBlks, vmErrs, err := block.BatchedGetBlock(ctx, t.VM, vmIDs)
+	for j, i := range vmIndices {
+		switch {
+		case err != nil:
+			errs[i] = err
+		case vmErrs[j] != nil:
+			errs[i] = vmErrs[j]
+		default:
+			blks[i] = vmBlks[j]
+		}
+	}
+	return blks, errs
+}
+
 // Build blocks if they have been requested and the number of processing blocks
 // is less than optimal.
 func (t *Transitive) buildBlocks(ctx context.Context) error {
@@ -469,8 +604,12 @@ func (t *Transitive) buildBlocks(ctx context.Context) error {
 		return err
 	}
 	for t.pendingBuildBlocks > 0 && t.Consensus.NumProcessing() < t.Params.OptimalProcessing {
+		if t.paceBuild() {
+			return nil
+		}
 		t.pendingBuildBlocks--
 
+		t.pacing.lastBuild = t.clock.Time()
 		blk, err := t.VM.BuildBlock(ctx)
 		if err != nil {
 			t.Ctx.Log.Debug("failed building block",
@@ -702,6 +841,8 @@ func (t *Transitive) pullQuery(ctx context.Context, blkID ids.ID) {
 	t.RequestID++
 	if t.polls.Add(t.RequestID, vdrBag) {
 		vdrList := vdrBag.List()
+		t.recordPoll(blkID, vdrList)
+		t.trackHedge(blkID, vdrList)
 		vdrSet := ids.NewNodeIDSet(len(vdrList))
 		vdrSet.Add(vdrList...)
 		t.Sender.SendPullQuery(ctx, vdrSet, t.RequestID, blkID)
@@ -732,6 +873,10 @@ func (t *Transitive) sendMixedQuery(ctx context.Context, blk snowman.Block) {
 
 	t.RequestID++
 	if t.polls.Add(t.RequestID, vdrBag) {
+		vdrList := vdrBag.List() // Note that this doesn't contain duplicates; length may be < k
+		t.recordPoll(blkID, vdrList)
+		t.trackHedge(blkID, vdrList)
+
 		// Send a push query to some of the validators, and a pull query to the rest.
 		numPushTo := t.Params.MixedQueryNumPushVdr
 		if !t.Validators.Contains(t.Ctx.NodeID) {
@@ -740,7 +885,7 @@ func (t *Transitive) sendMixedQuery(ctx context.Context, blk snowman.Block) {
 		common.SendMixedQuery(
 			ctx,
 			t.Sender,
-			vdrBag.List(), // Note that this doesn't contain duplicates; length may be < k
+			vdrList,
 			numPushTo,
 			t.RequestID,
 			blkID,
@@ -749,6 +894,17 @@ func (t *Transitive) sendMixedQuery(ctx context.Context, blk snowman.Block) {
 	}
 }
 
+// recordPoll records that the poll [t.RequestID] about [blkID] was sent to
+// [vdrs].
+func (t *Transitive) recordPoll(blkID ids.ID, vdrs []ids.NodeID) {
+	t.Decisions.Record(decisionlog.Entry{
+		Kind:      decisionlog.PollIssued,
+		RequestID: t.RequestID,
+		NodeIDs:   vdrs,
+		BlkID:     blkID,
+	})
+}
+
 // issue [blk] to consensus
 func (t *Transitive) deliver(ctx context.Context, blk snowman.Block) error {
 	blkID := blk.ID()
@@ -900,8 +1056,9 @@ func (t *Transitive) addUnverifiedBlockToConsensus(ctx context.Context, blk snow
 		zap.Stringer("blkID", blkID),
 	)
 	return true, t.Consensus.Add(ctx, &memoryBlock{
-		Block:   blk,
-		metrics: &t.metrics,
-		tree:    t.nonVerifieds,
+		Block:     blk,
+		metrics:   &t.metrics,
+		tree:      t.nonVerifieds,
+		decisions: t.Decisions,
 	})
 }
@@ -3383,3 +3383,55 @@ func TestEngineBuildBlockWithCachedNonVerifiedParent(t *testing.T) {
 	require.NoError(err)
 	require.True(*sentQuery)
 }
+
+func TestEngineBatchedGetBlock(t *testing.T) {
+	require := require.New(t)
+
+	_, _, _, vm, te, gBlk := setupDefaultConfig(t)
+
+	pendingBlk := &snowman.TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.GenerateTestID(),
+			StatusV: choices.Processing,
+		},
+		ParentV: gBlk.ID(),
+		HeightV: 1,
+		BytesV:  []byte{1},
+	}
+	te.pending[pendingBlk.ID()] = pendingBlk
+
+	batchedVM := &block.TestBatchedVM{T: t}
+	te.VM = &struct {
+		*block.TestVM
+		*block.TestBatchedVM
+	}{
+		TestVM:        vm,
+		TestBatchedVM: batchedVM,
+	}
+
+	// The blocks that aren't pending are fetched from the VM at once.
+	var requested [][]ids.ID
+	batchedVM.BatchedGetBlockF = func(_ context.Context, blkIDs []ids.ID) ([]snowman.Block, []error, error) {
+		requested = append(requested, blkIDs)
+		blks := make([]snowman.Block, len(blkIDs))
+		errs := make([]error, len(blkIDs))
+		for i, blkID := range blkIDs {
+			if blkID == gBlk.ID() {
+				blks[i] = gBlk
+				continue
+			}
+			errs[i] = errUnknownBlock
+		}
+		return blks, errs, nil
+	}
+
+	unknownID := ids.GenerateTestID()
+	blks, errs := te.batchedGetBlock(context.Background(), []ids.ID{
+		gBlk.ID(),
+		pendingBlk.ID(),
+		unknownID,
+	})
+	require.Equal([][]ids.ID{{gBlk.ID(), unknownID}}, requested)
+	require.Equal([]snowman.Block{gBlk, pendingBlk, nil}, blks)
+	require.Equal([]error{nil, nil, errUnknownBlock}, errs)
+}
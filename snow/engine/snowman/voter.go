@@ -9,6 +9,7 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
 )
 
 // Voter records chits received from [vdr] once its dependencies are met.
@@ -57,15 +58,22 @@ func (v *voter) Update(ctx context.Context) {
 		results[i] = v.bubbleVotes(ctx, result)
 	}
 
-	for _, result := range results {
-		result := result
+	// The blocks decided by the polls are sent to the VM together.
+	err := block.BatchDecisions(ctx, v.t.VM, func(ctx context.Context) error {
+		for _, result := range results {
+			result := result
 
-		v.t.Ctx.Log.Debug("finishing poll",
-			zap.Stringer("result", &result),
-		)
-		if err := v.t.Consensus.RecordPoll(ctx, result); err != nil {
-			v.t.errs.Add(err)
+			v.t.Ctx.Log.Debug("finishing poll",
+				zap.Stringer("result", &result),
+			)
+			if err := v.t.Consensus.RecordPoll(ctx, result); err != nil {
+				return err
+			}
 		}
+		return nil
+	})
+	if err != nil {
+		v.t.errs.Add(err)
 	}
 
 	if v.t.errs.Errored() {
@@ -96,18 +104,27 @@ func (v *voter) Update(ctx context.Context) {
 func (v *voter) bubbleVotes(ctx context.Context, votes ids.Bag) ids.Bag {
 	bubbledVotes := ids.Bag{}
 
+	// use rootIDs in case of the votes are for non-verified block IDs
+	voteIDs := votes.List()
+	rootIDs := make([]ids.ID, len(voteIDs))
+	for i, vote := range voteIDs {
+		rootIDs[i] = v.t.nonVerifieds.GetRoot(vote)
+	}
+	// The roots of the votes are fetched at once, so that a VM that fetches
+	// blocks remotely handles a single request per poll.
+	rootBlks, rootErrs := v.t.batchedGetBlock(ctx, rootIDs)
+
 votesLoop:
-	for _, vote := range votes.List() {
+	for i, vote := range voteIDs {
 		count := votes.Count(vote)
-		// use rootID in case of this is a non-verified block ID
-		rootID := v.t.nonVerifieds.GetRoot(vote)
+		rootID := rootIDs[i]
 		v.t.Ctx.Log.Verbo("bubbling vote(s) through unverified blocks",
 			zap.Int("numVotes", count),
 			zap.Stringer("voteID", vote),
 			zap.Stringer("parentID", rootID),
 		)
 
-		blk, err := v.t.GetBlock(ctx, rootID)
+		blk, err := rootBlks[i], rootErrs[i]
 		// If we cannot retrieve the block, drop [vote]
 		if err != nil {
 			v.t.Ctx.Log.Debug("dropping vote(s)",
@@ -0,0 +1,316 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package appmux
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+// serviceRequestBit is set in the IDs of the AppRequests sent by services, and
+// cleared in the IDs of the AppRequests sent by the VM.
+const serviceRequestBit uint32 = 1 << 31
+
+var (
+	_ common.AppSender = (*vmSender)(nil)
+	_ common.AppSender = (*serviceSender)(nil)
+
+	errDuplicateService  = errors.New("duplicate service")
+	errServiceGossip     = errors.New("services can't gossip")
+	errServiceCrossChain = errors.New("services can't send cross-chain messages")
+)
+
+// ServiceID identifies a node service. A request sent by a service is handled
+// by the service with the same ID on the receiving node.
+type ServiceID byte
+
+// Handler handles the App messages of a chain that aren't for a service. It's
+// typically the engine of the chain, which passes them to the VM.
+type Handler interface {
+	AppRequest(ctx context.Context, nodeID ids.NodeID, requestID uint32, deadline time.Time, request []byte) error
+	AppResponse(ctx context.Context, nodeID ids.NodeID, requestID uint32, response []byte) error
+	AppRequestFailedWithError(ctx context.Context, nodeID ids.NodeID, requestID uint32, appErr *common.AppError) error
+}
+
+// Service is a node service that exchanges AppRequests with the services of
+// the same ID on the other nodes of a chain. As with the engine, returned
+// errors are fatal to the chain.
+type Service interface {
+	// AppRequest handles a request sent by the service on [nodeID].
+	AppRequest(ctx context.Context, nodeID ids.NodeID, requestID uint32, deadline time.Time, request []byte) error
+	// AppResponse handles the response of [nodeID] to a request this service
+	// sent.
+	AppResponse(ctx context.Context, nodeID ids.NodeID, requestID uint32, response []byte) error
+	// AppRequestFailed is called when a request this service sent to [nodeID]
+	// failed.
+	AppRequestFailed(ctx context.Context, nodeID ids.NodeID, requestID uint32, appErr *common.AppError) error
+}
+
+type request struct {
+	// service that sent the request, or nil if the VM sent it
+	service Service
+	// requestID the request was sent with by the VM or [service]
+	requestID uint32
+	// nodeIDs that haven't responded yet
+	outstanding ids.NodeIDSet
+}
+
+// Mux shares the App messages of a chain between the chain's VM and the node
+// services of the chain.
+//
+// The requests sent by the VM and the services are sent with request IDs
+// chosen by the Mux, so that they don't collide, and the responses are passed
+// to their sender with the request IDs it chose. The request IDs of services
+// have the top bit set, and their requests are prefixed with the ID of the
+// service, so the receiving node knows which service to pass them to.
+type Mux struct {
+	log    logging.Logger
+	sender common.AppSender
+
+	lock     sync.Mutex
+	services map[ServiceID]Service
+	// requestID the next request will be sent with, before the top bit is set
+	// or cleared
+	nextRequestID uint32
+	// request ID the request was sent with -> request
+	requests map[uint32]*request
+}
+
+func New(log logging.Logger, sender common.AppSender) *Mux {
+	return &Mux{
+		log:      log,
+		sender:   sender,
+		services: make(map[ServiceID]Service),
+		requests: make(map[uint32]*request),
+	}
+}
+
+// VMSender returns the sender of the VM.
+func (m *Mux) VMSender() common.AppSender {
+	return &vmSender{
+		AppSender: m.sender,
+		mux:       m,
+	}
+}
+
+// Register [service] as the service with ID [serviceID]. Returns the sender of
+// the service, which only sends AppRequests, AppResponses and AppErrors.
+func (m *Mux) Register(serviceID ServiceID, service Service) (common.AppSender, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if _, ok := m.services[serviceID]; ok {
+		return nil, fmt.Errorf("%w: %d", errDuplicateService, serviceID)
+	}
+	m.services[serviceID] = service
+	return &serviceSender{
+		mux:       m,
+		serviceID: serviceID,
+		service:   service,
+	}, nil
+}
+
+// AppRequest passes a request from [nodeID] to the service that sent it, or to
+// [handler] if the VM sent it. Requests for services that aren't registered are
+// answered with common.ErrUnknownService.
+func (m *Mux) AppRequest(
+	ctx context.Context,
+	handler Handler,
+	nodeID ids.NodeID,
+	requestID uint32,
+	deadline time.Time,
+	requestBytes []byte,
+) error {
+	if requestID&serviceRequestBit == 0 {
+		return handler.AppRequest(ctx, nodeID, requestID, deadline, requestBytes)
+	}
+
+	var (
+		service Service
+		ok      bool
+	)
+	if len(requestBytes) > 0 {
+		m.lock.Lock()
+		service, ok = m.services[ServiceID(requestBytes[0])]
+		m.lock.Unlock()
+	}
+	if !ok {
+		m.log.Debug("dropping request for unknown service",
+			zap.Stringer("nodeID", nodeID),
+			zap.Uint32("requestID", requestID),
+		)
+		return m.sender.SendAppError(ctx, nodeID, requestID, common.ErrUnknownService.Code, common.ErrUnknownService.Message)
+	}
+	return service.AppRequest(ctx, nodeID, requestID, deadline, requestBytes[1:])
+}
+
+// AppResponse passes the response of [nodeID] to the VM or service that sent
+// the request.
+func (m *Mux) AppResponse(
+	ctx context.Context,
+	handler Handler,
+	nodeID ids.NodeID,
+	requestID uint32,
+	responseBytes []byte,
+) error {
+	request, ok := m.complete(nodeID, requestID)
+	switch {
+	case !ok:
+		m.log.Debug("dropping unexpected response",
+			zap.Stringer("nodeID", nodeID),
+			zap.Uint32("requestID", requestID),
+		)
+		return nil
+	case request.service == nil:
+		return handler.AppResponse(ctx, nodeID, request.requestID, responseBytes)
+	default:
+		return request.service.AppResponse(ctx, nodeID, request.requestID, responseBytes)
+	}
+}
+
+// AppRequestFailed tells the VM or service that sent a request to [nodeID] that
+// it failed.
+func (m *Mux) AppRequestFailed(
+	ctx context.Context,
+	handler Handler,
+	nodeID ids.NodeID,
+	requestID uint32,
+	appErr *common.AppError,
+) error {
+	request, ok := m.complete(nodeID, requestID)
+	switch {
+	case !ok:
+		m.log.Debug("dropping unexpected request failure",
+			zap.Stringer("nodeID", nodeID),
+			zap.Uint32("requestID", requestID),
+		)
+		return nil
+	case request.service == nil:
+		return handler.AppRequestFailedWithError(ctx, nodeID, request.requestID, appErr)
+	default:
+		return request.service.AppRequestFailed(ctx, nodeID, request.requestID, appErr)
+	}
+}
+
+// sendAppRequest sends the request [requestID] of [service], or of the VM if
+// [service] is nil, with a request ID chosen by the mux.
+func (m *Mux) sendAppRequest(
+	ctx context.Context,
+	service Service,
+	nodeIDs ids.NodeIDSet,
+	requestID uint32,
+	requestBytes []byte,
+) error {
+	// The sender may modify [nodeIDs], so the outstanding nodeIDs are copied.
+	outstanding := ids.NewNodeIDSet(nodeIDs.Len())
+	outstanding.Union(nodeIDs)
+
+	m.lock.Lock()
+	networkRequestID := m.newRequestID(service != nil)
+	if outstanding.Len() > 0 {
+		m.requests[networkRequestID] = &request{
+			service:     service,
+			requestID:   requestID,
+			outstanding: outstanding,
+		}
+	}
+	m.lock.Unlock()
+
+	if err := m.sender.SendAppRequest(ctx, nodeIDs, networkRequestID, requestBytes); err != nil {
+		m.lock.Lock()
+		delete(m.requests, networkRequestID)
+		m.lock.Unlock()
+		return err
+	}
+	return nil
+}
+
+// newRequestID returns a request ID that isn't used by an outstanding request.
+//
+// Assumes [m.lock] is held.
+func (m *Mux) newRequestID(isService bool) uint32 {
+	for {
+		requestID := m.nextRequestID &^ serviceRequestBit
+		if isService {
+			requestID |= serviceRequestBit
+		}
+		m.nextRequestID++
+		if _, ok := m.requests[requestID]; !ok {
+			return requestID
+		}
+	}
+}
+
+// complete marks the request [requestID] to [nodeID] as answered. Returns false
+// if the request isn't outstanding.
+func (m *Mux) complete(nodeID ids.NodeID, requestID uint32) (*request, bool) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	request, ok := m.requests[requestID]
+	if !ok || !request.outstanding.Contains(nodeID) {
+		return nil, false
+	}
+	request.outstanding.Remove(nodeID)
+	if request.outstanding.Len() == 0 {
+		delete(m.requests, requestID)
+	}
+	return request, true
+}
+
+type vmSender struct {
+	common.AppSender
+
+	mux *Mux
+}
+
+func (s *vmSender) SendAppRequest(ctx context.Context, nodeIDs ids.NodeIDSet, requestID uint32, requestBytes []byte) error {
+	return s.mux.sendAppRequest(ctx, nil, nodeIDs, requestID, requestBytes)
+}
+
+type serviceSender struct {
+	mux       *Mux
+	serviceID ServiceID
+	service   Service
+}
+
+func (s *serviceSender) SendAppRequest(ctx context.Context, nodeIDs ids.NodeIDSet, requestID uint32, requestBytes []byte) error {
+	prefixedBytes := make([]byte, 1+len(requestBytes))
+	prefixedBytes[0] = byte(s.serviceID)
+	copy(prefixedBytes[1:], requestBytes)
+	return s.mux.sendAppRequest(ctx, s.service, nodeIDs, requestID, prefixedBytes)
+}
+
+func (s *serviceSender) SendAppResponse(ctx context.Context, nodeID ids.NodeID, requestID uint32, responseBytes []byte) error {
+	return s.mux.sender.SendAppResponse(ctx, nodeID, requestID, responseBytes)
+}
+
+func (s *serviceSender) SendAppError(ctx context.Context, nodeID ids.NodeID, requestID uint32, errorCode int32, errorMessage string) error {
+	return s.mux.sender.SendAppError(ctx, nodeID, requestID, errorCode, errorMessage)
+}
+
+func (*serviceSender) SendAppGossip(context.Context, []byte) error {
+	return errServiceGossip
+}
+
+func (*serviceSender) SendAppGossipSpecific(context.Context, ids.NodeIDSet, []byte) error {
+	return errServiceGossip
+}
+
+func (*serviceSender) SendCrossChainAppRequest(context.Context, ids.ID, uint32, []byte) error {
+	return errServiceCrossChain
+}
+
+func (*serviceSender) SendCrossChainAppResponse(context.Context, ids.ID, uint32, []byte) error {
+	return errServiceCrossChain
+}
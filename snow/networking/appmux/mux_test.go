@@ -0,0 +1,212 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package appmux
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+var errSendFailed = errors.New("send failed")
+
+type appMessage struct {
+	nodeID    ids.NodeID
+	requestID uint32
+	bytes     []byte
+	appErr    *common.AppError
+}
+
+// testHandler records the messages it's given.
+type testHandler struct {
+	requests  []appMessage
+	responses []appMessage
+	failures  []appMessage
+}
+
+func (h *testHandler) AppRequest(_ context.Context, nodeID ids.NodeID, requestID uint32, _ time.Time, request []byte) error {
+	h.requests = append(h.requests, appMessage{nodeID: nodeID, requestID: requestID, bytes: request})
+	return nil
+}
+
+func (h *testHandler) AppResponse(_ context.Context, nodeID ids.NodeID, requestID uint32, response []byte) error {
+	h.responses = append(h.responses, appMessage{nodeID: nodeID, requestID: requestID, bytes: response})
+	return nil
+}
+
+func (h *testHandler) AppRequestFailedWithError(_ context.Context, nodeID ids.NodeID, requestID uint32, appErr *common.AppError) error {
+	h.failures = append(h.failures, appMessage{nodeID: nodeID, requestID: requestID, appErr: appErr})
+	return nil
+}
+
+func (h *testHandler) AppRequestFailed(ctx context.Context, nodeID ids.NodeID, requestID uint32, appErr *common.AppError) error {
+	return h.AppRequestFailedWithError(ctx, nodeID, requestID, appErr)
+}
+
+// newTestMux returns a mux whose sent requests and errors are recorded.
+func newTestMux(t *testing.T) (*Mux, *[]appMessage, *[]appMessage) {
+	var (
+		requests []appMessage
+		errs     []appMessage
+		sender   = &common.SenderTest{T: t}
+	)
+	sender.SendAppRequestF = func(_ context.Context, nodeIDs ids.NodeIDSet, requestID uint32, request []byte) error {
+		for nodeID := range nodeIDs {
+			requests = append(requests, appMessage{nodeID: nodeID, requestID: requestID, bytes: request})
+		}
+		// The sender may modify the set it's given.
+		nodeIDs.Clear()
+		return nil
+	}
+	sender.SendAppErrorF = func(_ context.Context, nodeID ids.NodeID, requestID uint32, errorCode int32, errorMessage string) error {
+		errs = append(errs, appMessage{
+			nodeID:    nodeID,
+			requestID: requestID,
+			appErr: &common.AppError{
+				Code:    errorCode,
+				Message: errorMessage,
+			},
+		})
+		return nil
+	}
+	return New(logging.NoLog{}, sender), &requests, &errs
+}
+
+func TestMuxVMRequests(t *testing.T) {
+	require := require.New(t)
+
+	mux, sent, _ := newTestMux(t)
+	vm := &testHandler{}
+	nodeID0 := ids.GenerateTestNodeID()
+	nodeID1 := ids.GenerateTestNodeID()
+
+	nodeIDs := ids.NewNodeIDSet(2)
+	nodeIDs.Add(nodeID0, nodeID1)
+	require.NoError(mux.VMSender().SendAppRequest(context.Background(), nodeIDs, serviceRequestBit|5, []byte{1}))
+	require.Len(*sent, 2)
+	networkRequestID := (*sent)[0].requestID
+	require.Zero(networkRequestID & serviceRequestBit)
+	require.Equal([]byte{1}, (*sent)[0].bytes)
+
+	// The VM is given the request ID it chose.
+	require.NoError(mux.AppResponse(context.Background(), vm, nodeID0, networkRequestID, []byte{2}))
+	require.Equal([]appMessage{{nodeID: nodeID0, requestID: serviceRequestBit | 5, bytes: []byte{2}}}, vm.responses)
+
+	// Only one response per node is expected.
+	require.NoError(mux.AppResponse(context.Background(), vm, nodeID0, networkRequestID, []byte{3}))
+	require.Len(vm.responses, 1)
+
+	require.NoError(mux.AppRequestFailed(context.Background(), vm, nodeID1, networkRequestID, common.ErrTimeout))
+	require.Equal([]appMessage{{nodeID: nodeID1, requestID: serviceRequestBit | 5, appErr: common.ErrTimeout}}, vm.failures)
+	require.Empty(mux.requests)
+
+	// Requests from peers without the top bit set are for the VM.
+	require.NoError(mux.AppRequest(context.Background(), vm, nodeID0, 7, time.Time{}, []byte{4}))
+	require.Equal([]appMessage{{nodeID: nodeID0, requestID: 7, bytes: []byte{4}}}, vm.requests)
+}
+
+func TestMuxServiceRequests(t *testing.T) {
+	require := require.New(t)
+
+	mux, sent, errs := newTestMux(t)
+	vm := &testHandler{}
+	service := &testHandler{}
+	serviceSender, err := mux.Register(1, service)
+	require.NoError(err)
+
+	_, err = mux.Register(1, &testHandler{})
+	require.ErrorIs(err, errDuplicateService)
+
+	nodeID := ids.GenerateTestNodeID()
+	nodeIDs := ids.NewNodeIDSet(1)
+	nodeIDs.Add(nodeID)
+	require.NoError(serviceSender.SendAppRequest(context.Background(), nodeIDs, 5, []byte{1}))
+	require.Len(*sent, 1)
+	networkRequestID := (*sent)[0].requestID
+	require.NotZero(networkRequestID & serviceRequestBit)
+	require.Equal([]byte{1, 1}, (*sent)[0].bytes)
+
+	// The response is given to the service rather than the VM.
+	require.NoError(mux.AppResponse(context.Background(), vm, nodeID, networkRequestID, []byte{2}))
+	require.Empty(vm.responses)
+	require.Equal([]appMessage{{nodeID: nodeID, requestID: 5, bytes: []byte{2}}}, service.responses)
+
+	// Requests from the service on other nodes are given to the service,
+	// without the ID of the service.
+	require.NoError(mux.AppRequest(context.Background(), vm, nodeID, serviceRequestBit|7, time.Time{}, []byte{1, 3}))
+	require.Empty(vm.requests)
+	require.Equal([]appMessage{{nodeID: nodeID, requestID: serviceRequestBit | 7, bytes: []byte{3}}}, service.requests)
+
+	// Requests for unknown services are answered with an error.
+	require.NoError(mux.AppRequest(context.Background(), vm, nodeID, serviceRequestBit|8, time.Time{}, []byte{2, 3}))
+	require.NoError(mux.AppRequest(context.Background(), vm, nodeID, serviceRequestBit|9, time.Time{}, nil))
+	require.Len(service.requests, 1)
+	require.Equal([]appMessage{
+		{nodeID: nodeID, requestID: serviceRequestBit | 8, appErr: common.ErrUnknownService},
+		{nodeID: nodeID, requestID: serviceRequestBit | 9, appErr: common.ErrUnknownService},
+	}, *errs)
+
+	require.ErrorIs(serviceSender.SendAppGossip(context.Background(), []byte{1}), errServiceGossip)
+	require.ErrorIs(serviceSender.SendCrossChainAppRequest(context.Background(), ids.GenerateTestID(), 1, []byte{1}), errServiceCrossChain)
+}
+
+func TestMuxRequestIDsDontCollide(t *testing.T) {
+	require := require.New(t)
+
+	mux, sent, _ := newTestMux(t)
+	serviceSender, err := mux.Register(0, &testHandler{})
+	require.NoError(err)
+
+	nodeID := ids.GenerateTestNodeID()
+	nodeIDs := ids.NewNodeIDSet(1)
+	nodeIDs.Add(nodeID)
+	require.NoError(mux.VMSender().SendAppRequest(context.Background(), nodeIDs, 1, nil))
+	nodeIDs.Add(nodeID)
+	require.NoError(serviceSender.SendAppRequest(context.Background(), nodeIDs, 1, nil))
+	nodeIDs.Add(nodeID)
+	require.NoError(mux.VMSender().SendAppRequest(context.Background(), nodeIDs, 1, nil))
+
+	require.Len(*sent, 3)
+	requestIDs := map[uint32]struct{}{}
+	for _, request := range *sent {
+		requestIDs[request.requestID] = struct{}{}
+	}
+	require.Len(requestIDs, 3)
+}
+
+func TestMuxSendFailed(t *testing.T) {
+	require := require.New(t)
+
+	sender := &common.SenderTest{T: t}
+	sender.SendAppRequestF = func(context.Context, ids.NodeIDSet, uint32, []byte) error {
+		return errSendFailed
+	}
+	mux := New(logging.NoLog{}, sender)
+
+	nodeIDs := ids.NewNodeIDSet(1)
+	nodeIDs.Add(ids.GenerateTestNodeID())
+	err := mux.VMSender().SendAppRequest(context.Background(), nodeIDs, 1, nil)
+	require.ErrorIs(err, errSendFailed)
+	require.Empty(mux.requests)
+}
+
+func TestMuxUnexpectedResponse(t *testing.T) {
+	require := require.New(t)
+
+	mux, _, _ := newTestMux(t)
+	vm := &testHandler{}
+	nodeID := ids.GenerateTestNodeID()
+
+	require.NoError(mux.AppResponse(context.Background(), vm, nodeID, 1, nil))
+	require.NoError(mux.AppRequestFailed(context.Background(), vm, nodeID, 1, common.ErrTimeout))
+	require.Empty(vm.responses)
+	require.Empty(vm.failures)
+}
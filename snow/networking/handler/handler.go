@@ -21,6 +21,7 @@ import (
 	"github.com/ava-labs/avalanchego/message"
 	"github.com/ava-labs/avalanchego/snow"
 	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/snow/networking/appmux"
 	"github.com/ava-labs/avalanchego/snow/networking/tracker"
 	"github.com/ava-labs/avalanchego/snow/networking/worker"
 	"github.com/ava-labs/avalanchego/snow/validators"
@@ -52,6 +53,11 @@ type Handler interface {
 	Bootstrapper() common.BootstrapableEngine
 	SetConsensus(engine common.Engine)
 	Consensus() common.Engine
+	// ConsensusTimer delivers timeouts to the consensus engine only. Unlike
+	// the timeouts registered with the handler, they aren't preempted once the
+	// subnet is bootstrapped, and they are dropped if the chain isn't running
+	// consensus when they fire.
+	ConsensusTimer() common.Timer
 
 	SetOnStopped(onStopped func())
 	Start(ctx context.Context, recoverPanic bool)
@@ -77,6 +83,12 @@ type handler struct {
 	msgFromVMChan   <-chan common.Message
 	preemptTimeouts chan struct{}
 	gossipFrequency time.Duration
+	// AppResponses larger than this many bytes are dropped. If 0, the size of
+	// AppResponses isn't limited.
+	maxAppResponseSize int
+	// Shares the App messages between the engine and the node services of the
+	// chain. If nil, every App message is passed to the engine.
+	appMux *appmux.Mux
 
 	stateSyncer  common.StateSyncer
 	bootstrapper common.BootstrapableEngine
@@ -97,6 +109,8 @@ type handler struct {
 	// Worker pool for handling asynchronous consensus messages
 	asyncMessagePool worker.Pool
 	timeouts         chan struct{}
+	// Timeouts for the consensus engine only
+	consensusTimeouts chan struct{}
 
 	closeOnce            sync.Once
 	closingChan          chan struct{}
@@ -114,18 +128,23 @@ func New(
 	preemptTimeouts chan struct{},
 	gossipFrequency time.Duration,
 	resourceTracker tracker.ResourceTracker,
+	maxAppResponseSize int,
+	appMux *appmux.Mux,
 ) (Handler, error) {
 	h := &handler{
-		ctx:              ctx,
-		validators:       validators,
-		msgFromVMChan:    msgFromVMChan,
-		preemptTimeouts:  preemptTimeouts,
-		gossipFrequency:  gossipFrequency,
-		asyncMessagePool: worker.NewPool(threadPoolSize),
-		timeouts:         make(chan struct{}, 1),
-		closingChan:      make(chan struct{}),
-		closed:           make(chan struct{}),
-		resourceTracker:  resourceTracker,
+		ctx:                ctx,
+		validators:         validators,
+		msgFromVMChan:      msgFromVMChan,
+		preemptTimeouts:    preemptTimeouts,
+		gossipFrequency:    gossipFrequency,
+		maxAppResponseSize: maxAppResponseSize,
+		appMux:             appMux,
+		asyncMessagePool:   worker.NewPool(threadPoolSize),
+		timeouts:           make(chan struct{}, 1),
+		consensusTimeouts:  make(chan struct{}, 1),
+		closingChan:        make(chan struct{}),
+		closed:             make(chan struct{}),
+		resourceTracker:    resourceTracker,
 	}
 
 	var err error
@@ -264,7 +283,9 @@ func (h *handler) HealthCheck(ctx context.Context) (interface{}, error) {
 // Push the message onto the handler's queue
 func (h *handler) Push(ctx context.Context, msg message.InboundMessage) {
 	switch msg.Op() {
-	case message.AppRequestOp, message.AppRequestFailedOp, message.AppResponseOp, message.AppGossipOp,
+	case message.AppResponseOp:
+		h.asyncMessageQueue.Push(ctx, h.checkAppResponseSize(msg))
+	case message.AppRequestOp, message.AppRequestFailedOp, message.AppGossipOp, message.AppErrorOp,
 		message.CrossChainAppRequestOp, message.CrossChainAppRequestFailedOp, message.CrossChainAppResponseOp:
 		h.asyncMessageQueue.Push(ctx, msg)
 	default:
@@ -272,6 +293,40 @@ func (h *handler) Push(ctx context.Context, msg message.InboundMessage) {
 	}
 }
 
+// checkAppResponseSize returns [msg] if it isn't larger than the max
+// AppResponse size. Otherwise, [msg] is dropped before it reaches the VM and
+// the request is failed instead, so that the VM doesn't wait for a response
+// that will never be delivered.
+func (h *handler) checkAppResponseSize(msg message.InboundMessage) message.InboundMessage {
+	appResponse, ok := msg.Message().(*p2ppb.AppResponse)
+	if !ok {
+		return msg
+	}
+
+	size := len(appResponse.AppBytes)
+	h.metrics.appResponseSize.Observe(float64(size))
+	if h.maxAppResponseSize == 0 || size <= h.maxAppResponseSize {
+		return msg
+	}
+
+	nodeID := msg.NodeID()
+	h.ctx.Log.Debug("dropping oversized AppResponse",
+		zap.Stringer("nodeID", nodeID),
+		zap.Uint32("requestID", appResponse.RequestId),
+		zap.Int("size", size),
+		zap.Int("maxSize", h.maxAppResponseSize),
+	)
+	h.metrics.oversizedAppResponses.Inc()
+	msg.OnFinishedHandling()
+	return message.InternalAppRequestFailed(
+		nodeID,
+		h.ctx.ChainID,
+		appResponse.RequestId,
+		common.ErrResponseTooLarge.Code,
+		common.ErrResponseTooLarge.Message,
+	)
+}
+
 func (h *handler) Len() int {
 	return h.syncMessageQueue.Len() + h.asyncMessageQueue.Len()
 }
@@ -296,6 +351,32 @@ func (h *handler) RegisterTimeout(d time.Duration) {
 	}()
 }
 
+func (h *handler) ConsensusTimer() common.Timer {
+	return consensusTimer{h: h}
+}
+
+type consensusTimer struct {
+	h *handler
+}
+
+func (t consensusTimer) RegisterTimeout(d time.Duration) {
+	go func() {
+		timer := time.NewTimer(d)
+		defer timer.Stop()
+
+		select {
+		case <-timer.C:
+		case <-t.h.closingChan:
+			return
+		}
+
+		select {
+		case t.h.consensusTimeouts <- struct{}{}:
+		default:
+		}
+	}()
+}
+
 func (h *handler) Stop(ctx context.Context) {
 	h.closeOnce.Do(func() {
 		// Must hold the locks here to ensure there's no race condition in where
@@ -394,6 +475,16 @@ func (h *handler) dispatchChans(ctx context.Context) {
 
 		case <-h.timeouts:
 			msg = message.InternalTimeout(h.ctx.NodeID)
+
+		case <-h.consensusTimeouts:
+			if err := h.handleConsensusTimeout(); err != nil {
+				h.StopWithError(ctx, fmt.Errorf(
+					"%w while processing consensus timeout",
+					err,
+				))
+				return
+			}
+			continue
 		}
 
 		if err := h.handleChanMsg(msg); err != nil {
@@ -694,6 +785,9 @@ func (h *handler) executeAsyncMsg(ctx context.Context, msg message.InboundMessag
 
 	switch m := msg.Message().(type) {
 	case *p2ppb.AppRequest:
+		if h.appMux != nil {
+			return h.appMux.AppRequest(ctx, engine, nodeID, m.RequestId, msg.Expiration(), m.AppBytes)
+		}
 		return engine.AppRequest(
 			ctx,
 			nodeID,
@@ -703,10 +797,22 @@ func (h *handler) executeAsyncMsg(ctx context.Context, msg message.InboundMessag
 		)
 
 	case *p2ppb.AppResponse:
+		if h.appMux != nil {
+			return h.appMux.AppResponse(ctx, engine, nodeID, m.RequestId, m.AppBytes)
+		}
 		return engine.AppResponse(ctx, nodeID, m.RequestId, m.AppBytes)
 
+	case *p2ppb.AppError:
+		return h.appRequestFailed(ctx, engine, nodeID, m.RequestId, &common.AppError{
+			Code:    m.ErrorCode,
+			Message: m.ErrorMessage,
+		})
+
 	case *message.AppRequestFailed:
-		return engine.AppRequestFailed(ctx, nodeID, m.RequestID)
+		return h.appRequestFailed(ctx, engine, nodeID, m.RequestID, &common.AppError{
+			Code:    m.ErrorCode,
+			Message: m.ErrorMessage,
+		})
 
 	case *p2ppb.AppGossip:
 		return engine.AppGossip(ctx, nodeID, m.AppBytes)
@@ -743,6 +849,19 @@ func (h *handler) executeAsyncMsg(ctx context.Context, msg message.InboundMessag
 	}
 }
 
+func (h *handler) appRequestFailed(
+	ctx context.Context,
+	engine common.Engine,
+	nodeID ids.NodeID,
+	requestID uint32,
+	appErr *common.AppError,
+) error {
+	if h.appMux != nil {
+		return h.appMux.AppRequestFailed(ctx, engine, nodeID, requestID, appErr)
+	}
+	return engine.AppRequestFailedWithError(ctx, nodeID, requestID, appErr)
+}
+
 // Any returned error is treated as fatal
 func (h *handler) handleChanMsg(msg message.InboundMessage) error {
 	var (
@@ -791,6 +910,19 @@ func (h *handler) handleChanMsg(msg message.InboundMessage) error {
 	}
 }
 
+// Any returned error is treated as fatal
+func (h *handler) handleConsensusTimeout() error {
+	h.ctx.Lock.Lock()
+	defer h.ctx.Lock.Unlock()
+
+	// The chain may have left consensus, to bootstrap again, since the timeout
+	// was registered.
+	if h.ctx.GetState() != snow.NormalOp {
+		return nil
+	}
+	return h.engine.Timeout(context.TODO())
+}
+
 func (h *handler) getEngine() (common.Engine, error) {
 	state := h.ctx.GetState()
 	switch state {
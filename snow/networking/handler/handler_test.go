@@ -47,6 +47,8 @@ func TestHandlerDropsTimedOutMessages(t *testing.T) {
 		nil,
 		time.Second,
 		resourceTracker,
+		0,
+		nil,
 	)
 	require.NoError(t, err)
 	handler := handlerIntf.(*handler)
@@ -127,6 +129,8 @@ func TestHandlerClosesOnError(t *testing.T) {
 		nil,
 		time.Second,
 		resourceTracker,
+		0,
+		nil,
 	)
 	require.NoError(t, err)
 	handler := handlerIntf.(*handler)
@@ -205,6 +209,8 @@ func TestHandlerDropsGossipDuringBootstrapping(t *testing.T) {
 		nil,
 		1,
 		resourceTracker,
+		0,
+		nil,
 	)
 	require.NoError(t, err)
 	handler := handlerIntf.(*handler)
@@ -273,6 +279,8 @@ func TestHandlerDispatchInternal(t *testing.T) {
 		nil,
 		time.Second,
 		resourceTracker,
+		0,
+		nil,
 	)
 	require.NoError(t, err)
 
@@ -312,3 +320,142 @@ func TestHandlerDispatchInternal(t *testing.T) {
 	case <-calledNotify:
 	}
 }
+
+// Test that oversized AppResponses are reported to the engine as failed
+// requests
+func TestHandlerDropsOversizedAppResponses(t *testing.T) {
+	require := require.New(t)
+
+	ctx := snow.DefaultConsensusContextTest()
+	vdrs := validators.NewSet()
+	require.NoError(vdrs.AddWeight(ids.GenerateTestNodeID(), 1))
+
+	resourceTracker, err := tracker.NewResourceTracker(
+		prometheus.NewRegistry(),
+		resource.NoUsage,
+		meter.ContinuousFactory{},
+		time.Second,
+	)
+	require.NoError(err)
+	handlerIntf, err := New(
+		ctx,
+		vdrs,
+		nil,
+		nil,
+		time.Second,
+		resourceTracker,
+		2,
+		nil,
+	)
+	require.NoError(err)
+	handler := handlerIntf.(*handler)
+
+	bootstrapper := &common.BootstrapperTest{
+		BootstrapableTest: common.BootstrapableTest{
+			T: t,
+		},
+		EngineTest: common.EngineTest{
+			T: t,
+		},
+	}
+	bootstrapper.Default(false)
+	handler.SetBootstrapper(bootstrapper)
+
+	var (
+		responses = make(chan uint32, 1)
+		failures  = make(chan *common.AppError, 1)
+	)
+	engine := &common.EngineTest{T: t}
+	engine.Default(false)
+	engine.ContextF = func() *snow.ConsensusContext {
+		return ctx
+	}
+	engine.AppResponseF = func(_ context.Context, _ ids.NodeID, requestID uint32, _ []byte) error {
+		responses <- requestID
+		return nil
+	}
+	engine.AppRequestFailedWithErrorF = func(_ context.Context, _ ids.NodeID, _ uint32, appErr *common.AppError) error {
+		failures <- appErr
+		return nil
+	}
+	handler.SetConsensus(engine)
+	ctx.SetState(snow.NormalOp) // assumed bootstrapping is done
+
+	bootstrapper.StartF = func(context.Context, uint32) error {
+		return nil
+	}
+	handler.Start(context.Background(), false)
+
+	nodeID := ids.GenerateTestNodeID()
+	handler.Push(context.Background(), message.InboundAppResponse(ctx.ChainID, 1, []byte{1, 2}, nodeID))
+	require.Equal(uint32(1), <-responses)
+
+	handler.Push(context.Background(), message.InboundAppResponse(ctx.ChainID, 2, []byte{1, 2, 3}, nodeID))
+	require.ErrorIs(<-failures, common.ErrResponseTooLarge)
+}
+
+func TestHandlerForwardsAppErrors(t *testing.T) {
+	require := require.New(t)
+
+	ctx := snow.DefaultConsensusContextTest()
+	vdrs := validators.NewSet()
+	require.NoError(vdrs.AddWeight(ids.GenerateTestNodeID(), 1))
+
+	resourceTracker, err := tracker.NewResourceTracker(
+		prometheus.NewRegistry(),
+		resource.NoUsage,
+		meter.ContinuousFactory{},
+		time.Second,
+	)
+	require.NoError(err)
+	handlerIntf, err := New(
+		ctx,
+		vdrs,
+		nil,
+		nil,
+		time.Second,
+		resourceTracker,
+		0,
+		nil,
+	)
+	require.NoError(err)
+	handler := handlerIntf.(*handler)
+
+	bootstrapper := &common.BootstrapperTest{
+		BootstrapableTest: common.BootstrapableTest{
+			T: t,
+		},
+		EngineTest: common.EngineTest{
+			T: t,
+		},
+	}
+	bootstrapper.Default(false)
+	handler.SetBootstrapper(bootstrapper)
+
+	failures := make(chan *common.AppError, 1)
+	engine := &common.EngineTest{T: t}
+	engine.Default(false)
+	engine.ContextF = func() *snow.ConsensusContext {
+		return ctx
+	}
+	engine.AppRequestFailedWithErrorF = func(_ context.Context, _ ids.NodeID, _ uint32, appErr *common.AppError) error {
+		failures <- appErr
+		return nil
+	}
+	handler.SetConsensus(engine)
+	ctx.SetState(snow.NormalOp) // assumed bootstrapping is done
+
+	bootstrapper.StartF = func(context.Context, uint32) error {
+		return nil
+	}
+	handler.Start(context.Background(), false)
+
+	nodeID := ids.GenerateTestNodeID()
+	handler.Push(context.Background(), message.InboundAppError(ctx.ChainID, 1, 7, "rate limited", nodeID))
+	appErr := <-failures
+	require.Equal(int32(7), appErr.Code)
+	require.Equal("rate limited", appErr.Message)
+
+	handler.Push(context.Background(), message.InternalAppRequestFailed(nodeID, ctx.ChainID, 2, common.ErrTimeout.Code, common.ErrTimeout.Message))
+	require.ErrorIs(<-failures, common.ErrTimeout)
+}
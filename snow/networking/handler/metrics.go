@@ -14,9 +14,11 @@ import (
 )
 
 type metrics struct {
-	expired      prometheus.Counter
-	asyncExpired prometheus.Counter
-	messages     map[message.Op]metric.Averager
+	expired               prometheus.Counter
+	asyncExpired          prometheus.Counter
+	oversizedAppResponses prometheus.Counter
+	appResponseSize       metric.Averager
+	messages              map[message.Op]metric.Averager
 }
 
 func newMetrics(namespace string, reg prometheus.Registerer) (*metrics, error) {
@@ -32,9 +34,22 @@ func newMetrics(namespace string, reg prometheus.Registerer) (*metrics, error) {
 		Name:      "async_expired",
 		Help:      "Incoming async messages dropped because the message deadline expired",
 	})
+	oversizedAppResponses := prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "oversized_app_responses",
+		Help:      "Incoming AppResponses dropped because they were larger than the max AppResponse size",
+	})
 	errs.Add(
 		reg.Register(expired),
 		reg.Register(asyncExpired),
+		reg.Register(oversizedAppResponses),
+	)
+	appResponseSize := metric.NewAveragerWithErrs(
+		namespace,
+		"app_response_size",
+		"size (in bytes) of incoming AppResponses",
+		reg,
+		&errs,
 	)
 
 	messages := make(map[message.Op]metric.Averager, len(message.ConsensusOps))
@@ -50,8 +65,10 @@ func newMetrics(namespace string, reg prometheus.Registerer) (*metrics, error) {
 	}
 
 	return &metrics{
-		expired:      expired,
-		asyncExpired: asyncExpired,
-		messages:     messages,
+		expired:               expired,
+		asyncExpired:          asyncExpired,
+		oversizedAppResponses: oversizedAppResponses,
+		appResponseSize:       appResponseSize,
+		messages:              messages,
 	}, errs.Err
 }
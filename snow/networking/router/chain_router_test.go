@@ -44,6 +44,7 @@ func TestShutdown(t *testing.T) {
 			TimeoutHalflife:    5 * time.Minute,
 		},
 		benchlist,
+		0,
 		"",
 		prometheus.NewRegistry(),
 	)
@@ -82,6 +83,8 @@ func TestShutdown(t *testing.T) {
 		nil,
 		time.Second,
 		resourceTracker,
+		0,
+		nil,
 	)
 	require.NoError(t, err)
 
@@ -165,6 +168,7 @@ func TestShutdownTimesOut(t *testing.T) {
 			TimeoutHalflife:    5 * time.Minute,
 		},
 		benchlist,
+		0,
 		"",
 		metrics,
 	)
@@ -202,6 +206,8 @@ func TestShutdownTimesOut(t *testing.T) {
 		nil,
 		time.Second,
 		resourceTracker,
+		0,
+		nil,
 	)
 	require.NoError(t, err)
 
@@ -285,6 +291,7 @@ func TestRouterTimeout(t *testing.T) {
 			TimeoutHalflife:    5 * time.Minute,
 		},
 		benchlist.NewNoBenchlist(),
+		0,
 		"",
 		prometheus.NewRegistry(),
 	)
@@ -340,6 +347,8 @@ func TestRouterTimeout(t *testing.T) {
 		nil,
 		time.Second,
 		resourceTracker,
+		0,
+		nil,
 	)
 	r.NoError(err)
 
@@ -396,7 +405,7 @@ func TestRouterTimeout(t *testing.T) {
 		calledQueryFailed = true
 		return nil
 	}
-	bootstrapper.AppRequestFailedF = func(context.Context, ids.NodeID, uint32) error {
+	bootstrapper.AppRequestFailedWithErrorF = func(context.Context, ids.NodeID, uint32, *common.AppError) error {
 		defer wg.Done()
 		calledAppRequestFailed = true
 		return nil
@@ -557,6 +566,8 @@ func TestRouterTimeout(t *testing.T) {
 				nodeID,
 				ctx.ChainID,
 				requestID,
+				common.ErrTimeout.Code,
+				common.ErrTimeout.Message,
 			),
 		)
 	}
@@ -607,6 +618,7 @@ func TestRouterClearTimeouts(t *testing.T) {
 			TimeoutHalflife:    5 * time.Minute,
 		},
 		benchlist.NewNoBenchlist(),
+		0,
 		"",
 		prometheus.NewRegistry(),
 	)
@@ -649,6 +661,8 @@ func TestRouterClearTimeouts(t *testing.T) {
 		nil,
 		time.Second,
 		resourceTracker,
+		0,
+		nil,
 	)
 	require.NoError(t, err)
 
@@ -815,6 +829,8 @@ func TestRouterClearTimeouts(t *testing.T) {
 				nodeID,
 				ctx.ChainID,
 				requestID,
+				common.ErrTimeout.Code,
+				common.ErrTimeout.Message,
 			),
 		)
 		msg := message.InboundAppResponse(
@@ -867,6 +883,7 @@ func TestValidatorOnlyMessageDrops(t *testing.T) {
 			TimeoutHalflife:    5 * time.Minute,
 		},
 		benchlist.NewNoBenchlist(),
+		0,
 		"",
 		prometheus.NewRegistry(),
 	)
@@ -913,6 +930,8 @@ func TestValidatorOnlyMessageDrops(t *testing.T) {
 		nil,
 		time.Second,
 		resourceTracker,
+		0,
+		nil,
 	)
 	require.NoError(t, err)
 
@@ -1012,6 +1031,7 @@ func TestRouterCrossChainMessages(t *testing.T) {
 			TimeoutHalflife:    5 * time.Minute,
 		},
 		benchlist.NewNoBenchlist(),
+		0,
 		"timeoutManager",
 		prometheus.NewRegistry(),
 	)
@@ -1061,6 +1081,8 @@ func TestRouterCrossChainMessages(t *testing.T) {
 		nil,
 		time.Second,
 		resourceTracker,
+		0,
+		nil,
 	)
 	require.NoError(t, err)
 
@@ -1077,6 +1099,8 @@ func TestRouterCrossChainMessages(t *testing.T) {
 		nil,
 		time.Second,
 		resourceTracker,
+		0,
+		nil,
 	)
 	require.NoError(t, err)
 
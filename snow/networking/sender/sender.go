@@ -1189,6 +1189,8 @@ func (s *sender) SendAppRequest(ctx context.Context, nodeIDs ids.NodeIDSet, requ
 			nodeID,
 			s.ctx.ChainID,
 			requestID,
+			common.ErrTimeout.Code,
+			common.ErrTimeout.Message,
 		)
 		s.router.RegisterRequest(
 			ctx,
@@ -1234,6 +1236,8 @@ func (s *sender) SendAppRequest(ctx context.Context, nodeIDs ids.NodeIDSet, requ
 				nodeID,
 				s.ctx.ChainID,
 				requestID,
+				common.ErrTimeout.Code,
+				common.ErrTimeout.Message,
 			)
 			go s.router.HandleInbound(ctx, inMsg)
 		}
@@ -1289,6 +1293,8 @@ func (s *sender) SendAppRequest(ctx context.Context, nodeIDs ids.NodeIDSet, requ
 				nodeID,
 				s.ctx.ChainID,
 				requestID,
+				common.ErrTimeout.Code,
+				common.ErrTimeout.Message,
 			)
 			go s.router.HandleInbound(ctx, inMsg)
 		}
@@ -1354,6 +1360,61 @@ func (s *sender) SendAppResponse(ctx context.Context, nodeID ids.NodeID, request
 	return nil
 }
 
+// SendAppError sends an error to an application-level request from the given
+// node, in place of a response
+func (s *sender) SendAppError(ctx context.Context, nodeID ids.NodeID, requestID uint32, errorCode int32, errorMessage string) error {
+	if nodeID == s.ctx.NodeID {
+		inMsg := message.InboundAppError(
+			s.ctx.ChainID,
+			requestID,
+			errorCode,
+			errorMessage,
+			nodeID,
+		)
+		go s.router.HandleInbound(ctx, inMsg)
+		return nil
+	}
+
+	// Create the outbound message.
+	outMsg, err := s.msgCreator.AppError(
+		s.ctx.ChainID,
+		requestID,
+		errorCode,
+		errorMessage,
+	)
+	if err != nil {
+		s.ctx.Log.Error("failed to build message",
+			zap.Stringer("messageOp", message.AppErrorOp),
+			zap.Stringer("chainID", s.ctx.ChainID),
+			zap.Uint32("requestID", requestID),
+			zap.Int32("errorCode", errorCode),
+			zap.String("errorMessage", errorMessage),
+			zap.Error(err),
+		)
+		return nil
+	}
+
+	// Send the message over the network.
+	nodeIDs := ids.NewNodeIDSet(1)
+	nodeIDs.Add(nodeID)
+	sentTo := s.sender.Send(
+		outMsg,
+		nodeIDs,
+		s.ctx.SubnetID,
+		s.ctx.IsValidatorOnly(),
+	)
+	if sentTo.Len() == 0 {
+		s.ctx.Log.Debug("failed to send message",
+			zap.Stringer("messageOp", message.AppErrorOp),
+			zap.Stringer("nodeID", nodeID),
+			zap.Stringer("chainID", s.ctx.ChainID),
+			zap.Uint32("requestID", requestID),
+			zap.Int32("errorCode", errorCode),
+		)
+	}
+	return nil
+}
+
 func (s *sender) SendAppGossipSpecific(_ context.Context, nodeIDs ids.NodeIDSet, appGossipBytes []byte) error {
 	// Create the outbound message.
 	outMsg, err := s.msgCreator.AppGossip(s.ctx.ChainID, appGossipBytes)
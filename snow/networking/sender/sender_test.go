@@ -53,6 +53,7 @@ func TestTimeout(t *testing.T) {
 			TimeoutCoefficient: 1.25,
 		},
 		benchlist,
+		0,
 		"",
 		prometheus.NewRegistry(),
 	)
@@ -113,6 +114,8 @@ func TestTimeout(t *testing.T) {
 		nil,
 		time.Hour,
 		resourceTracker,
+		0,
+		nil,
 	)
 	require.NoError(err)
 
@@ -168,7 +171,9 @@ func TestTimeout(t *testing.T) {
 	bootstrapper.GetAncestorsFailedF = failed
 	bootstrapper.GetFailedF = failed
 	bootstrapper.QueryFailedF = failed
-	bootstrapper.AppRequestFailedF = failed
+	bootstrapper.AppRequestFailedWithErrorF = func(ctx context.Context, nodeID ids.NodeID, requestID uint32, _ *common.AppError) error {
+		return failed(ctx, nodeID, requestID)
+	}
 	bootstrapper.CrossChainAppRequestFailedF = func(_ context.Context, chainID ids.ID, _ uint32) error {
 		failedLock.Lock()
 		defer failedLock.Unlock()
@@ -299,6 +304,7 @@ func TestReliableMessages(t *testing.T) {
 			TimeoutCoefficient: 1.25,
 		},
 		benchlist,
+		0,
 		"",
 		prometheus.NewRegistry(),
 	)
@@ -361,6 +367,8 @@ func TestReliableMessages(t *testing.T) {
 		nil,
 		1,
 		resourceTracker,
+		0,
+		nil,
 	)
 	require.NoError(t, err)
 
@@ -429,6 +437,7 @@ func TestReliableMessagesToMyself(t *testing.T) {
 			TimeoutCoefficient: 1.25,
 		},
 		benchlist,
+		0,
 		"",
 		prometheus.NewRegistry(),
 	)
@@ -484,6 +493,8 @@ func TestReliableMessagesToMyself(t *testing.T) {
 		nil,
 		time.Second,
 		resourceTracker,
+		0,
+		nil,
 	)
 	require.NoError(t, err)
 
@@ -237,6 +237,18 @@ func (s *tracedSender) SendAppResponse(ctx context.Context, nodeID ids.NodeID, r
 	return s.sender.SendAppResponse(ctx, nodeID, requestID, appResponseBytes)
 }
 
+func (s *tracedSender) SendAppError(ctx context.Context, nodeID ids.NodeID, requestID uint32, errorCode int32, errorMessage string) error {
+	ctx, span := s.tracer.Start(ctx, "tracedSender.SendAppError", oteltrace.WithAttributes(
+		attribute.Stringer("recipients", nodeID),
+		attribute.Int64("requestID", int64(requestID)),
+		attribute.Int64("errorCode", int64(errorCode)),
+		attribute.String("errorMessage", errorMessage),
+	))
+	defer span.End()
+
+	return s.sender.SendAppError(ctx, nodeID, requestID, errorCode, errorMessage)
+}
+
 func (s *tracedSender) SendAppGossipSpecific(ctx context.Context, nodeIDs ids.NodeIDSet, appGossipBytes []byte) error {
 	_, span := s.tracer.Start(ctx, "tracedSender.SendAppGossipSpecific", oteltrace.WithAttributes(
 		attribute.Stringer("recipients", nodeIDs),
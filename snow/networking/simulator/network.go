@@ -0,0 +1,294 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package simulator connects in-process engines over a simulated network, so
+// that consensus can be tested under adversarial network conditions.
+//
+// The simulation is deterministic: messages are delivered by a single
+// goroutine, in the order of their simulated delivery time, and the messages
+// that are lost are chosen by a seeded source of randomness.
+package simulator
+
+import (
+	"container/heap"
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+)
+
+var (
+	errUnknownNode   = errors.New("unknown node")
+	errDuplicateNode = errors.New("duplicated node")
+)
+
+// Config of the simulated network.
+type Config struct {
+	// Time it takes for a message to reach its destination
+	Latency time.Duration
+	// Up to this much time is randomly added to the latency of each message
+	Jitter time.Duration
+	// Probability, in [0, 1], that a message is lost
+	LossRate float64
+	// Time after which a request that wasn't answered fails
+	Timeout time.Duration
+	// Seed of the randomness used to compute jitters and lose messages
+	Seed int64
+}
+
+// Network delivers the messages sent by the engines registered on it.
+// Network isn't safe for concurrent use.
+type Network struct {
+	config Config
+	rng    *rand.Rand
+
+	now    time.Time
+	events eventHeap
+	// Number of events scheduled so far, used to deliver the events scheduled
+	// at the same time in the order they were scheduled.
+	numScheduled uint64
+
+	nodeIDs []ids.NodeID
+	engines map[ids.NodeID]common.Engine
+	// Node ID -> extra latency of the messages sent to and from the node
+	latencies map[ids.NodeID]time.Duration
+	// Node ID -> partition of the node. Nodes can only reach the nodes of
+	// their partition.
+	partitions map[ids.NodeID]int
+
+	// Requests that haven't been answered yet, nor failed
+	requests map[request]struct{}
+
+	// First error returned by an engine
+	err error
+}
+
+// request identifies a request sent by [requester] to [responder].
+type request struct {
+	requester, responder ids.NodeID
+	requestID            uint32
+}
+
+// New returns an empty network, whose simulated time starts at [start].
+func New(config Config, start time.Time) *Network {
+	return &Network{
+		config: config,
+		// #nosec G404
+		rng:        rand.New(rand.NewSource(config.Seed)),
+		now:        start,
+		engines:    make(map[ids.NodeID]common.Engine),
+		latencies:  make(map[ids.NodeID]time.Duration),
+		partitions: make(map[ids.NodeID]int),
+		requests:   make(map[request]struct{}),
+	}
+}
+
+// Sender returns the sender that [nodeID] uses to send messages over the
+// network. The engine of [nodeID] must be registered before any message is
+// delivered.
+func (n *Network) Sender(nodeID ids.NodeID) common.Sender {
+	return &sender{
+		network: n,
+		nodeID:  nodeID,
+	}
+}
+
+// Register [engine] as the engine of [nodeID].
+func (n *Network) Register(nodeID ids.NodeID, engine common.Engine) error {
+	if _, ok := n.engines[nodeID]; ok {
+		return fmt.Errorf("%w: %s", errDuplicateNode, nodeID)
+	}
+	n.nodeIDs = append(n.nodeIDs, nodeID)
+	n.engines[nodeID] = engine
+	return nil
+}
+
+// Now returns the simulated time.
+func (n *Network) Now() time.Time {
+	return n.now
+}
+
+// SetLatency adds [latency] to the latency of the messages sent to and from
+// [nodeID].
+func (n *Network) SetLatency(nodeID ids.NodeID, latency time.Duration) {
+	n.latencies[nodeID] = latency
+}
+
+// Partition the network, so that nodes can only reach the nodes in the same
+// group. Nodes that aren't in any group are put in a group of their own.
+func (n *Network) Partition(groups ...[]ids.NodeID) {
+	n.partitions = make(map[ids.NodeID]int)
+	for i, group := range groups {
+		for _, nodeID := range group {
+			n.partitions[nodeID] = i + 1
+		}
+	}
+	for i, nodeID := range n.nodeIDs {
+		if _, ok := n.partitions[nodeID]; !ok {
+			n.partitions[nodeID] = len(groups) + i + 1
+		}
+	}
+}
+
+// Heal the partitions of the network.
+func (n *Network) Heal() {
+	n.partitions = make(map[ids.NodeID]int)
+}
+
+// Schedule [f] to be called on the engine of [nodeID] after [delay].
+func (n *Network) Schedule(nodeID ids.NodeID, delay time.Duration, f func(context.Context, common.Engine) error) {
+	n.numScheduled++
+	heap.Push(&n.events, &event{
+		time:   n.now.Add(delay),
+		seq:    n.numScheduled,
+		nodeID: nodeID,
+		f:      f,
+	})
+}
+
+// Step delivers the next message. It returns false if there is no message
+// left to deliver.
+func (n *Network) Step(ctx context.Context) (bool, error) {
+	if n.err != nil {
+		return false, n.err
+	}
+	if n.events.Len() == 0 {
+		return false, nil
+	}
+
+	e := heap.Pop(&n.events).(*event)
+	n.now = e.time
+	engine, ok := n.engines[e.nodeID]
+	if !ok {
+		n.err = fmt.Errorf("%w: %s", errUnknownNode, e.nodeID)
+		return false, n.err
+	}
+	if err := e.f(ctx, engine); err != nil {
+		n.err = fmt.Errorf("engine of %s failed: %w", e.nodeID, err)
+		return false, n.err
+	}
+	return true, nil
+}
+
+// RunUntil delivers messages until [done] returns true, no message is left to
+// deliver, or [duration] of simulated time passed. It returns whether [done]
+// returned true.
+func (n *Network) RunUntil(ctx context.Context, done func() bool, duration time.Duration) (bool, error) {
+	end := n.now.Add(duration)
+	for !done() {
+		if n.events.Len() == 0 || n.events[0].time.After(end) {
+			return false, nil
+		}
+		if _, err := n.Step(ctx); err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// send [f] from [from] to [to]. The message is dropped if it is lost or if
+// the nodes are partitioned.
+func (n *Network) send(from, to ids.NodeID, f func(context.Context, common.Engine) error) {
+	if !n.reachable(from, to) {
+		return
+	}
+	if n.config.LossRate > 0 && n.rng.Float64() < n.config.LossRate {
+		return
+	}
+	latency := n.config.Latency + n.latencies[from] + n.latencies[to]
+	if n.config.Jitter > 0 {
+		latency += time.Duration(n.rng.Int63n(int64(n.config.Jitter)))
+	}
+	n.Schedule(to, latency, f)
+}
+
+// sendRequest sends a request from [requester] to [responder]. If the request
+// isn't answered before the timeout, [onFailure] is called on the engine of
+// [requester].
+func (n *Network) sendRequest(
+	requester, responder ids.NodeID,
+	requestID uint32,
+	f func(context.Context, common.Engine) error,
+	onFailure func(context.Context, common.Engine) error,
+) {
+	req := request{
+		requester: requester,
+		responder: responder,
+		requestID: requestID,
+	}
+	n.requests[req] = struct{}{}
+	n.send(requester, responder, f)
+	n.Schedule(requester, n.config.Timeout, func(ctx context.Context, engine common.Engine) error {
+		if _, ok := n.requests[req]; !ok {
+			return nil
+		}
+		delete(n.requests, req)
+		return onFailure(ctx, engine)
+	})
+}
+
+// sendResponse sends a response from [responder] to [requester]. The response
+// is dropped if the request already failed.
+func (n *Network) sendResponse(
+	responder, requester ids.NodeID,
+	requestID uint32,
+	f func(context.Context, common.Engine) error,
+) {
+	req := request{
+		requester: requester,
+		responder: responder,
+		requestID: requestID,
+	}
+	n.send(responder, requester, func(ctx context.Context, engine common.Engine) error {
+		if _, ok := n.requests[req]; !ok {
+			return nil
+		}
+		delete(n.requests, req)
+		return f(ctx, engine)
+	})
+}
+
+func (n *Network) reachable(from, to ids.NodeID) bool {
+	return n.partitions[from] == n.partitions[to]
+}
+
+type event struct {
+	time   time.Time
+	seq    uint64
+	nodeID ids.NodeID
+	f      func(context.Context, common.Engine) error
+}
+
+type eventHeap []*event
+
+func (h eventHeap) Len() int {
+	return len(h)
+}
+
+func (h eventHeap) Less(i, j int) bool {
+	if !h[i].time.Equal(h[j].time) {
+		return h[i].time.Before(h[j].time)
+	}
+	return h[i].seq < h[j].seq
+}
+
+func (h eventHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+}
+
+func (h *eventHeap) Push(x interface{}) {
+	*h = append(*h, x.(*event))
+}
+
+func (h *eventHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	e := old[n-1]
+	old[n-1] = nil
+	*h = old[:n-1]
+	return e
+}
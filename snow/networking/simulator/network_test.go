@@ -0,0 +1,223 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package simulator
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowball"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
+	"github.com/ava-labs/avalanchego/snow/engine/snowman/getter"
+	"github.com/ava-labs/avalanchego/snow/validators"
+
+	snowmanconsensus "github.com/ava-labs/avalanchego/snow/consensus/snowman"
+	snowmanengine "github.com/ava-labs/avalanchego/snow/engine/snowman"
+)
+
+var (
+	errUnknownBlock = errors.New("unknown block")
+
+	genesisID    = ids.GenerateTestID()
+	genesisBytes = []byte{0}
+	blkID        = ids.GenerateTestID()
+	blkBytes     = []byte{1}
+)
+
+// testNode is a node running a snowman engine on a chain with a single block
+// after genesis.
+type testNode struct {
+	nodeID ids.NodeID
+	blocks map[ids.ID]*snowmanconsensus.TestBlock
+}
+
+func newTestNode(t *testing.T, network *Network, vdrs validators.Set, nodeID ids.NodeID, k int) *testNode {
+	require := require.New(t)
+
+	n := &testNode{
+		nodeID: nodeID,
+		blocks: map[ids.ID]*snowmanconsensus.TestBlock{
+			genesisID: {
+				TestDecidable: choices.TestDecidable{
+					IDV:     genesisID,
+					StatusV: choices.Accepted,
+				},
+				BytesV: genesisBytes,
+			},
+		},
+	}
+	require.NoError(vdrs.AddWeight(n.nodeID, 1))
+
+	vm := &block.TestVM{}
+	vm.LastAcceptedF = func(context.Context) (ids.ID, error) {
+		return genesisID, nil
+	}
+	vm.GetBlockF = func(_ context.Context, blkID ids.ID) (snowmanconsensus.Block, error) {
+		blk, ok := n.blocks[blkID]
+		if !ok {
+			return nil, errUnknownBlock
+		}
+		return blk, nil
+	}
+	vm.ParseBlockF = func(_ context.Context, b []byte) (snowmanconsensus.Block, error) {
+		switch {
+		case bytes.Equal(b, genesisBytes):
+			return n.blocks[genesisID], nil
+		case bytes.Equal(b, blkBytes):
+			return n.block(), nil
+		default:
+			return nil, errUnknownBlock
+		}
+	}
+	vm.BuildBlockF = func(context.Context) (snowmanconsensus.Block, error) {
+		return n.block(), nil
+	}
+
+	ctx := snow.DefaultConsensusContextTest()
+	ctx.NodeID = n.nodeID
+	sender := network.Sender(n.nodeID)
+
+	commonCfg := common.DefaultConfigTest()
+	commonCfg.Ctx = ctx
+	commonCfg.Sender = sender
+	getHandler, err := getter.New(vm, commonCfg)
+	require.NoError(err)
+
+	engine, err := snowmanengine.New(snowmanengine.Config{
+		AllGetsServer: getHandler,
+		Ctx:           ctx,
+		VM:            vm,
+		Sender:        sender,
+		Validators:    vdrs,
+		Params: snowball.Parameters{
+			K:                     k,
+			Alpha:                 k/2 + 1,
+			BetaVirtuous:          2,
+			BetaRogue:             3,
+			ConcurrentRepolls:     1,
+			OptimalProcessing:     100,
+			MaxOutstandingItems:   1,
+			MaxItemProcessingTime: 1,
+		},
+		Consensus: &snowmanconsensus.Topological{},
+	})
+	require.NoError(err)
+	require.NoError(engine.Start(context.Background(), 0))
+	require.NoError(network.Register(n.nodeID, engine))
+	return n
+}
+
+// block returns the instance of the block after genesis of [n].
+func (n *testNode) block() *snowmanconsensus.TestBlock {
+	if blk, ok := n.blocks[blkID]; ok {
+		return blk
+	}
+	blk := &snowmanconsensus.TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     blkID,
+			StatusV: choices.Processing,
+		},
+		ParentV: genesisID,
+		HeightV: 1,
+		BytesV:  blkBytes,
+	}
+	n.blocks[blkID] = blk
+	return blk
+}
+
+func newTestNetwork(t *testing.T, config Config, numNodes int) (*Network, []*testNode) {
+	network := New(config, time.Unix(0, 0))
+	vdrs := validators.NewSet()
+	nodes := make([]*testNode, numNodes)
+	for i := range nodes {
+		// The node IDs are fixed so that the order the messages are sent in
+		// is the same in every run.
+		nodes[i] = newTestNode(t, network, vdrs, ids.NodeID{byte(i + 1)}, numNodes)
+	}
+	return network, nodes
+}
+
+// buildBlock schedules [node] to build and issue the block after genesis.
+func buildBlock(network *Network, node *testNode) {
+	network.Schedule(node.nodeID, 0, func(ctx context.Context, engine common.Engine) error {
+		return engine.Notify(ctx, common.PendingTxs)
+	})
+}
+
+// allAccepted returns a function that reports whether every node in [nodes]
+// accepted the block after genesis.
+func allAccepted(nodes []*testNode) func() bool {
+	return func() bool {
+		for _, node := range nodes {
+			blk, ok := node.blocks[blkID]
+			if !ok || blk.Status() != choices.Accepted {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+func TestNetworkConsensus(t *testing.T) {
+	require := require.New(t)
+
+	network, nodes := newTestNetwork(t, Config{
+		Latency:  50 * time.Millisecond,
+		Jitter:   100 * time.Millisecond,
+		LossRate: .1,
+		Timeout:  time.Second,
+		Seed:     1,
+	}, 5)
+	network.SetLatency(nodes[0].nodeID, 200*time.Millisecond)
+
+	buildBlock(network, nodes[1])
+	accepted, err := network.RunUntil(context.Background(), allAccepted(nodes), time.Minute)
+	require.NoError(err)
+	require.True(accepted)
+}
+
+func TestNetworkPartition(t *testing.T) {
+	require := require.New(t)
+
+	network, nodes := newTestNetwork(t, Config{
+		Latency: 50 * time.Millisecond,
+		Timeout: time.Second,
+	}, 4)
+
+	// Neither side of the partition holds a majority of the validators, so
+	// the block can't be accepted until the partition is healed.
+	network.Partition(
+		[]ids.NodeID{nodes[0].nodeID, nodes[1].nodeID},
+		[]ids.NodeID{nodes[2].nodeID, nodes[3].nodeID},
+	)
+	buildBlock(network, nodes[0])
+	accepted, err := network.RunUntil(context.Background(), allAccepted(nodes), time.Minute)
+	require.NoError(err)
+	require.False(accepted)
+	require.Equal(choices.Processing, nodes[0].blocks[blkID].Status())
+
+	network.Heal()
+	accepted, err = network.RunUntil(context.Background(), allAccepted(nodes), time.Minute)
+	require.NoError(err)
+	require.True(accepted)
+}
+
+func TestNetworkDuplicateNode(t *testing.T) {
+	require := require.New(t)
+
+	network := New(Config{}, time.Unix(0, 0))
+	nodeID := ids.GenerateTestNodeID()
+	require.NoError(network.Register(nodeID, &common.EngineTest{}))
+	err := network.Register(nodeID, &common.EngineTest{})
+	require.ErrorIs(err, errDuplicateNode)
+}
@@ -0,0 +1,253 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package simulator
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/utils/constants"
+)
+
+var _ common.Sender = (*sender)(nil)
+
+// sender sends the messages of [nodeID] over [network].
+type sender struct {
+	network *Network
+	nodeID  ids.NodeID
+}
+
+func (*sender) Accept(*snow.ConsensusContext, ids.ID, []byte) error {
+	return nil
+}
+
+func (s *sender) SendGetStateSummaryFrontier(_ context.Context, nodeIDs ids.NodeIDSet, requestID uint32) {
+	for _, nodeID := range nodeIDs.SortedList() {
+		nodeID := nodeID
+		s.network.sendRequest(s.nodeID, nodeID, requestID,
+			func(ctx context.Context, engine common.Engine) error {
+				return engine.GetStateSummaryFrontier(ctx, s.nodeID, requestID)
+			},
+			func(ctx context.Context, engine common.Engine) error {
+				return engine.GetStateSummaryFrontierFailed(ctx, nodeID, requestID)
+			},
+		)
+	}
+}
+
+func (s *sender) SendStateSummaryFrontier(_ context.Context, nodeID ids.NodeID, requestID uint32, summary []byte) {
+	s.network.sendResponse(s.nodeID, nodeID, requestID, func(ctx context.Context, engine common.Engine) error {
+		return engine.StateSummaryFrontier(ctx, s.nodeID, requestID, summary)
+	})
+}
+
+func (s *sender) SendGetAcceptedStateSummary(_ context.Context, nodeIDs ids.NodeIDSet, requestID uint32, heights []uint64) {
+	for _, nodeID := range nodeIDs.SortedList() {
+		nodeID := nodeID
+		s.network.sendRequest(s.nodeID, nodeID, requestID,
+			func(ctx context.Context, engine common.Engine) error {
+				return engine.GetAcceptedStateSummary(ctx, s.nodeID, requestID, heights)
+			},
+			func(ctx context.Context, engine common.Engine) error {
+				return engine.GetAcceptedStateSummaryFailed(ctx, nodeID, requestID)
+			},
+		)
+	}
+}
+
+func (s *sender) SendAcceptedStateSummary(_ context.Context, nodeID ids.NodeID, requestID uint32, summaryIDs []ids.ID) {
+	s.network.sendResponse(s.nodeID, nodeID, requestID, func(ctx context.Context, engine common.Engine) error {
+		return engine.AcceptedStateSummary(ctx, s.nodeID, requestID, summaryIDs)
+	})
+}
+
+func (s *sender) SendGetAcceptedFrontier(_ context.Context, nodeIDs ids.NodeIDSet, requestID uint32) {
+	for _, nodeID := range nodeIDs.SortedList() {
+		nodeID := nodeID
+		s.network.sendRequest(s.nodeID, nodeID, requestID,
+			func(ctx context.Context, engine common.Engine) error {
+				return engine.GetAcceptedFrontier(ctx, s.nodeID, requestID)
+			},
+			func(ctx context.Context, engine common.Engine) error {
+				return engine.GetAcceptedFrontierFailed(ctx, nodeID, requestID)
+			},
+		)
+	}
+}
+
+func (s *sender) SendAcceptedFrontier(_ context.Context, nodeID ids.NodeID, requestID uint32, containerIDs []ids.ID) {
+	s.network.sendResponse(s.nodeID, nodeID, requestID, func(ctx context.Context, engine common.Engine) error {
+		return engine.AcceptedFrontier(ctx, s.nodeID, requestID, containerIDs)
+	})
+}
+
+func (s *sender) SendGetAccepted(_ context.Context, nodeIDs ids.NodeIDSet, requestID uint32, containerIDs []ids.ID) {
+	for _, nodeID := range nodeIDs.SortedList() {
+		nodeID := nodeID
+		s.network.sendRequest(s.nodeID, nodeID, requestID,
+			func(ctx context.Context, engine common.Engine) error {
+				return engine.GetAccepted(ctx, s.nodeID, requestID, containerIDs)
+			},
+			func(ctx context.Context, engine common.Engine) error {
+				return engine.GetAcceptedFailed(ctx, nodeID, requestID)
+			},
+		)
+	}
+}
+
+func (s *sender) SendAccepted(_ context.Context, nodeID ids.NodeID, requestID uint32, containerIDs []ids.ID) {
+	s.network.sendResponse(s.nodeID, nodeID, requestID, func(ctx context.Context, engine common.Engine) error {
+		return engine.Accepted(ctx, s.nodeID, requestID, containerIDs)
+	})
+}
+
+func (s *sender) SendGet(_ context.Context, nodeID ids.NodeID, requestID uint32, containerID ids.ID) {
+	s.network.sendRequest(s.nodeID, nodeID, requestID,
+		func(ctx context.Context, engine common.Engine) error {
+			return engine.Get(ctx, s.nodeID, requestID, containerID)
+		},
+		func(ctx context.Context, engine common.Engine) error {
+			return engine.GetFailed(ctx, nodeID, requestID)
+		},
+	)
+}
+
+func (s *sender) SendGetAncestors(_ context.Context, nodeID ids.NodeID, requestID uint32, containerID ids.ID) {
+	s.network.sendRequest(s.nodeID, nodeID, requestID,
+		func(ctx context.Context, engine common.Engine) error {
+			return engine.GetAncestors(ctx, s.nodeID, requestID, containerID)
+		},
+		func(ctx context.Context, engine common.Engine) error {
+			return engine.GetAncestorsFailed(ctx, nodeID, requestID)
+		},
+	)
+}
+
+func (s *sender) SendPut(_ context.Context, nodeID ids.NodeID, requestID uint32, container []byte) {
+	s.network.sendResponse(s.nodeID, nodeID, requestID, func(ctx context.Context, engine common.Engine) error {
+		return engine.Put(ctx, s.nodeID, requestID, container)
+	})
+}
+
+func (s *sender) SendAncestors(_ context.Context, nodeID ids.NodeID, requestID uint32, containers [][]byte) {
+	s.network.sendResponse(s.nodeID, nodeID, requestID, func(ctx context.Context, engine common.Engine) error {
+		return engine.Ancestors(ctx, s.nodeID, requestID, containers)
+	})
+}
+
+func (s *sender) SendPushQuery(_ context.Context, nodeIDs ids.NodeIDSet, requestID uint32, container []byte) {
+	for _, nodeID := range nodeIDs.SortedList() {
+		nodeID := nodeID
+		s.network.sendRequest(s.nodeID, nodeID, requestID,
+			func(ctx context.Context, engine common.Engine) error {
+				return engine.PushQuery(ctx, s.nodeID, requestID, container)
+			},
+			func(ctx context.Context, engine common.Engine) error {
+				return engine.QueryFailed(ctx, nodeID, requestID)
+			},
+		)
+	}
+}
+
+func (s *sender) SendPullQuery(_ context.Context, nodeIDs ids.NodeIDSet, requestID uint32, containerID ids.ID) {
+	for _, nodeID := range nodeIDs.SortedList() {
+		nodeID := nodeID
+		s.network.sendRequest(s.nodeID, nodeID, requestID,
+			func(ctx context.Context, engine common.Engine) error {
+				return engine.PullQuery(ctx, s.nodeID, requestID, containerID)
+			},
+			func(ctx context.Context, engine common.Engine) error {
+				return engine.QueryFailed(ctx, nodeID, requestID)
+			},
+		)
+	}
+}
+
+func (s *sender) SendChits(_ context.Context, nodeID ids.NodeID, requestID uint32, votes []ids.ID) {
+	s.network.sendResponse(s.nodeID, nodeID, requestID, func(ctx context.Context, engine common.Engine) error {
+		return engine.Chits(ctx, s.nodeID, requestID, votes)
+	})
+}
+
+// SendGossip sends [container] to every other node.
+func (s *sender) SendGossip(_ context.Context, container []byte) {
+	for _, nodeID := range s.network.nodeIDs {
+		if nodeID == s.nodeID {
+			continue
+		}
+		s.network.send(s.nodeID, nodeID, func(ctx context.Context, engine common.Engine) error {
+			return engine.Put(ctx, s.nodeID, constants.GossipMsgRequestID, container)
+		})
+	}
+}
+
+func (s *sender) SendAppRequest(_ context.Context, nodeIDs ids.NodeIDSet, requestID uint32, appRequestBytes []byte) error {
+	for _, nodeID := range nodeIDs.SortedList() {
+		nodeID := nodeID
+		deadline := s.network.now.Add(s.network.config.Timeout)
+		s.network.sendRequest(s.nodeID, nodeID, requestID,
+			func(ctx context.Context, engine common.Engine) error {
+				return engine.AppRequest(ctx, s.nodeID, requestID, deadline, appRequestBytes)
+			},
+			func(ctx context.Context, engine common.Engine) error {
+				return engine.AppRequestFailedWithError(ctx, nodeID, requestID, common.ErrTimeout)
+			},
+		)
+	}
+	return nil
+}
+
+func (s *sender) SendAppResponse(_ context.Context, nodeID ids.NodeID, requestID uint32, appResponseBytes []byte) error {
+	s.network.sendResponse(s.nodeID, nodeID, requestID, func(ctx context.Context, engine common.Engine) error {
+		return engine.AppResponse(ctx, s.nodeID, requestID, appResponseBytes)
+	})
+	return nil
+}
+
+func (s *sender) SendAppError(_ context.Context, nodeID ids.NodeID, requestID uint32, errorCode int32, errorMessage string) error {
+	s.network.sendResponse(s.nodeID, nodeID, requestID, func(ctx context.Context, engine common.Engine) error {
+		return engine.AppRequestFailedWithError(ctx, s.nodeID, requestID, &common.AppError{
+			Code:    errorCode,
+			Message: errorMessage,
+		})
+	})
+	return nil
+}
+
+// SendAppGossip sends [appGossipBytes] to every other node.
+func (s *sender) SendAppGossip(ctx context.Context, appGossipBytes []byte) error {
+	nodeIDs := ids.NewNodeIDSet(len(s.network.nodeIDs))
+	for _, nodeID := range s.network.nodeIDs {
+		if nodeID != s.nodeID {
+			nodeIDs.Add(nodeID)
+		}
+	}
+	return s.SendAppGossipSpecific(ctx, nodeIDs, appGossipBytes)
+}
+
+func (s *sender) SendAppGossipSpecific(_ context.Context, nodeIDs ids.NodeIDSet, appGossipBytes []byte) error {
+	for _, nodeID := range nodeIDs.SortedList() {
+		s.network.send(s.nodeID, nodeID, func(ctx context.Context, engine common.Engine) error {
+			return engine.AppGossip(ctx, s.nodeID, appGossipBytes)
+		})
+	}
+	return nil
+}
+
+// SendCrossChainAppRequest fails the request, as the network only simulates a
+// single chain.
+func (s *sender) SendCrossChainAppRequest(_ context.Context, chainID ids.ID, requestID uint32, _ []byte) error {
+	s.network.Schedule(s.nodeID, s.network.config.Timeout, func(ctx context.Context, engine common.Engine) error {
+		return engine.CrossChainAppRequestFailed(ctx, chainID, requestID)
+	})
+	return nil
+}
+
+// SendCrossChainAppResponse drops the response, as the network only simulates
+// a single chain.
+func (*sender) SendCrossChainAppResponse(context.Context, ids.ID, uint32, []byte) error {
+	return nil
+}
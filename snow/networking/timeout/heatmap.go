@@ -0,0 +1,131 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package timeout
+
+import (
+	"bytes"
+	"sort"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/validators"
+	"github.com/ava-labs/avalanchego/utils"
+)
+
+const (
+	peerLabel = "peer"
+	// Label of the latencies of the peers that aren't among the heaviest
+	// validators.
+	otherPeersLabel = "other"
+)
+
+var (
+	// Query latency buckets, in seconds, from 10ms to ~10s.
+	queryLatencyBuckets = prometheus.ExponentialBuckets(.01, 2, 11)
+
+	_ validators.SetCallbackListener = (*peerLatencies)(nil)
+)
+
+// peerLatencies records the latency of the consensus queries sent to each of
+// the [size] heaviest validators of a chain. The latencies of the queries sent
+// to any other peer are recorded under a shared label, which bounds the number
+// of time series regardless of the size of the validator set.
+type peerLatencies struct {
+	vdrs      validators.Set
+	size      int
+	latencies *prometheus.HistogramVec
+
+	// Set by the validator set callbacks, which are called with the lock of
+	// the validator set held, so the heaviest validators are only recomputed
+	// the next time a latency is observed.
+	stale utils.AtomicBool
+
+	lock sync.Mutex
+	// Node IDs of the [size] heaviest validators
+	heaviest ids.NodeIDSet
+}
+
+func newPeerLatencies(
+	vdrs validators.Set,
+	size int,
+	registerer prometheus.Registerer,
+) (*peerLatencies, error) {
+	p := &peerLatencies{
+		vdrs: vdrs,
+		size: size,
+		latencies: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Namespace: "lat",
+				Name:      "peer_query",
+				Help:      "time (in seconds) spent waiting for each peer to answer a consensus query",
+				Buckets:   queryLatencyBuckets,
+			},
+			[]string{peerLabel},
+		),
+		heaviest: ids.NewNodeIDSet(size),
+	}
+	p.stale.SetValue(true)
+	vdrs.RegisterCallbackListener(p)
+	return p, registerer.Register(p.latencies)
+}
+
+func (p *peerLatencies) OnValidatorAdded(ids.NodeID, uint64) {
+	p.stale.SetValue(true)
+}
+
+func (p *peerLatencies) OnValidatorRemoved(ids.NodeID, uint64) {
+	p.stale.SetValue(true)
+}
+
+func (p *peerLatencies) OnValidatorWeightChanged(ids.NodeID, uint64, uint64) {
+	p.stale.SetValue(true)
+}
+
+// observe records that [nodeID] answered a consensus query in [seconds].
+func (p *peerLatencies) observe(nodeID ids.NodeID, seconds float64) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if p.stale.GetValue() {
+		p.stale.SetValue(false)
+		p.updateHeaviest()
+	}
+
+	label := otherPeersLabel
+	if p.heaviest.Contains(nodeID) {
+		label = nodeID.String()
+	}
+	p.latencies.WithLabelValues(label).Observe(seconds)
+}
+
+// updateHeaviest recomputes the heaviest validators, and removes the
+// latencies of the validators that are no longer among them.
+//
+// Assumes [p.lock] is held.
+func (p *peerLatencies) updateHeaviest() {
+	vdrList := p.vdrs.List()
+	sort.Slice(vdrList, func(i, j int) bool {
+		iWeight, jWeight := vdrList[i].Weight(), vdrList[j].Weight()
+		if iWeight != jWeight {
+			return iWeight > jWeight
+		}
+		return bytes.Compare(vdrList[i].ID().Bytes(), vdrList[j].ID().Bytes()) == -1
+	})
+	if len(vdrList) > p.size {
+		vdrList = vdrList[:p.size]
+	}
+
+	heaviest := ids.NewNodeIDSet(len(vdrList))
+	for _, vdr := range vdrList {
+		heaviest.Add(vdr.ID())
+	}
+	for nodeID := range p.heaviest {
+		if !heaviest.Contains(nodeID) {
+			p.latencies.DeleteLabelValues(nodeID.String())
+		}
+	}
+	p.heaviest = heaviest
+}
@@ -0,0 +1,72 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package timeout
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/validators"
+)
+
+// observedPeers returns the number of observations recorded for each label.
+func observedPeers(t *testing.T, registry *prometheus.Registry) map[string]uint64 {
+	metricFamilies, err := registry.Gather()
+	require.NoError(t, err)
+
+	observed := make(map[string]uint64)
+	for _, metricFamily := range metricFamilies {
+		for _, metric := range metricFamily.GetMetric() {
+			for _, label := range metric.GetLabel() {
+				if label.GetName() == peerLabel {
+					observed[label.GetValue()] = metric.GetHistogram().GetSampleCount()
+				}
+			}
+		}
+	}
+	return observed
+}
+
+func TestPeerLatencies(t *testing.T) {
+	require := require.New(t)
+
+	vdr0 := ids.GenerateTestNodeID()
+	vdr1 := ids.GenerateTestNodeID()
+	vdr2 := ids.GenerateTestNodeID()
+	nonVdr := ids.GenerateTestNodeID()
+
+	vdrs := validators.NewSet()
+	require.NoError(vdrs.AddWeight(vdr0, 3))
+	require.NoError(vdrs.AddWeight(vdr1, 2))
+	require.NoError(vdrs.AddWeight(vdr2, 1))
+
+	registry := prometheus.NewRegistry()
+	latencies, err := newPeerLatencies(vdrs, 2, registry)
+	require.NoError(err)
+
+	for _, nodeID := range []ids.NodeID{vdr0, vdr1, vdr2, nonVdr} {
+		latencies.observe(nodeID, .1)
+	}
+	require.Equal(map[string]uint64{
+		vdr0.String():   1,
+		vdr1.String():   1,
+		otherPeersLabel: 2,
+	}, observedPeers(t, registry))
+
+	// Once [vdr2] is heavier than [vdr1], the latencies of [vdr1] are no
+	// longer recorded individually.
+	require.NoError(vdrs.AddWeight(vdr2, 2))
+	for _, nodeID := range []ids.NodeID{vdr1, vdr2} {
+		latencies.observe(nodeID, .1)
+	}
+	require.Equal(map[string]uint64{
+		vdr0.String():   1,
+		vdr2.String():   1,
+		otherPeersLabel: 3,
+	}, observedPeers(t, registry))
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/ava-labs/avalanchego/message"
 	"github.com/ava-labs/avalanchego/snow"
 	"github.com/ava-labs/avalanchego/snow/networking/benchlist"
+	"github.com/ava-labs/avalanchego/snow/validators"
 	"github.com/ava-labs/avalanchego/utils/timer"
 )
 
@@ -28,10 +29,10 @@ type Manager interface {
 	// IsBenched returns true if messages to [nodeID] regarding [chainID]
 	// should not be sent over the network and should immediately fail.
 	IsBenched(nodeID ids.NodeID, chainID ids.ID) bool
-	// Register the existence of the given chain.
-	// Must be called before any method calls that use the
+	// Register the existence of the given chain, which is validated by
+	// [vdrs]. Must be called before any method calls that use the
 	// ID of the chain.
-	RegisterChain(ctx *snow.ConsensusContext) error
+	RegisterChain(ctx *snow.ConsensusContext, vdrs validators.Set) error
 	// RegisterRequest notes that we expect a response of type [op] from
 	// [nodeID] for chain [chainID]. If we don't receive a response in
 	// time, [timeoutHandler] is executed.
@@ -64,9 +65,14 @@ type Manager interface {
 	RemoveRequest(requestID ids.RequestID)
 }
 
+// NewManager returns a timeout manager. The latencies of the consensus queries
+// sent to each of the [peerLatenciesSize] heaviest validators of a chain are
+// recorded in the metrics of the chain. If [peerLatenciesSize] is 0, per peer
+// query latencies aren't recorded.
 func NewManager(
 	timeoutConfig *timer.AdaptiveTimeoutConfig,
 	benchlistMgr benchlist.Manager,
+	peerLatenciesSize int,
 	metricsNamespace string,
 	metricsRegister prometheus.Registerer,
 ) (Manager, error) {
@@ -81,6 +87,9 @@ func NewManager(
 	return &manager{
 		benchlistMgr: benchlistMgr,
 		tm:           tm,
+		metrics: metrics{
+			peerLatenciesSize: peerLatenciesSize,
+		},
 	}, nil
 }
 
@@ -104,8 +113,8 @@ func (m *manager) IsBenched(nodeID ids.NodeID, chainID ids.ID) bool {
 	return m.benchlistMgr.IsBenched(nodeID, chainID)
 }
 
-func (m *manager) RegisterChain(ctx *snow.ConsensusContext) error {
-	if err := m.metrics.RegisterChain(ctx); err != nil {
+func (m *manager) RegisterChain(ctx *snow.ConsensusContext, vdrs validators.Set) error {
+	if err := m.metrics.RegisterChain(ctx, vdrs); err != nil {
 		return fmt.Errorf("couldn't register timeout metrics for chain %s: %w", ctx.ChainID, err)
 	}
 	if err := m.benchlistMgr.RegisterChain(ctx); err != nil {
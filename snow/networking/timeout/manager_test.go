@@ -27,6 +27,7 @@ func TestManagerFire(t *testing.T) {
 			TimeoutHalflife:    5 * time.Minute,
 		},
 		benchlist,
+		0,
 		"",
 		prometheus.NewRegistry(),
 	)
@@ -60,6 +61,7 @@ func TestManagerCancel(t *testing.T) {
 			TimeoutHalflife:    5 * time.Minute,
 		},
 		benchlist,
+		0,
 		"",
 		prometheus.NewRegistry(),
 	)
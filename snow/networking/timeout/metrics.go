@@ -15,6 +15,7 @@ import (
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/message"
 	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/validators"
 	"github.com/ava-labs/avalanchego/utils/metric"
 	"github.com/ava-labs/avalanchego/utils/wrappers"
 )
@@ -25,11 +26,15 @@ const (
 )
 
 type metrics struct {
+	// Number of validators, by decreasing stake, whose query latencies are
+	// recorded individually. If 0, per peer query latencies aren't recorded.
+	peerLatenciesSize int
+
 	lock           sync.Mutex
 	chainToMetrics map[ids.ID]*chainMetrics
 }
 
-func (m *metrics) RegisterChain(ctx *snow.ConsensusContext) error {
+func (m *metrics) RegisterChain(ctx *snow.ConsensusContext, vdrs validators.Set) error {
 	m.lock.Lock()
 	defer m.lock.Unlock()
 
@@ -39,7 +44,7 @@ func (m *metrics) RegisterChain(ctx *snow.ConsensusContext) error {
 	if _, exists := m.chainToMetrics[ctx.ChainID]; exists {
 		return fmt.Errorf("chain %s has already been registered", ctx.ChainID)
 	}
-	cm, err := newChainMetrics(ctx, false)
+	cm, err := newChainMetrics(ctx, false, vdrs, m.peerLatenciesSize)
 	if err != nil {
 		return fmt.Errorf("couldn't create metrics for chain %s: %w", ctx.ChainID, err)
 	}
@@ -68,9 +73,18 @@ type chainMetrics struct {
 
 	summaryEnabled   bool
 	messageSummaries map[message.Op]*prometheus.SummaryVec
+
+	// Latencies of the consensus queries sent to each of the heaviest
+	// validators. Nil if per peer query latencies aren't recorded.
+	peerLatencies *peerLatencies
 }
 
-func newChainMetrics(ctx *snow.ConsensusContext, summaryEnabled bool) (*chainMetrics, error) {
+func newChainMetrics(
+	ctx *snow.ConsensusContext,
+	summaryEnabled bool,
+	vdrs validators.Set,
+	peerLatenciesSize int,
+) (*chainMetrics, error) {
 	cm := &chainMetrics{
 		ctx: ctx,
 
@@ -81,6 +95,13 @@ func newChainMetrics(ctx *snow.ConsensusContext, summaryEnabled bool) (*chainMet
 	}
 
 	errs := wrappers.Errs{}
+	if peerLatenciesSize > 0 && vdrs != nil {
+		peerLatencies, err := newPeerLatencies(vdrs, peerLatenciesSize, ctx.Registerer)
+		if err != nil {
+			errs.Add(fmt.Errorf("failed to register peer query latencies: %w", err))
+		}
+		cm.peerLatencies = peerLatencies
+	}
 	for _, op := range message.ConsensusResponseOps {
 		cm.messageLatencies[op] = metric.NewAveragerWithErrs(
 			"lat",
@@ -117,6 +138,9 @@ func (cm *chainMetrics) observe(nodeID ids.NodeID, op message.Op, latency time.D
 	if msg, exists := cm.messageLatencies[op]; exists {
 		msg.Observe(lat)
 	}
+	if op == message.ChitsOp && cm.peerLatencies != nil {
+		cm.peerLatencies.observe(nodeID, latency.Seconds())
+	}
 
 	if !cm.summaryEnabled {
 		return
@@ -0,0 +1,51 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package snow
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/crypto/bls"
+)
+
+// SigningValidator is a validator whose BLS signature is requested by a
+// SignatureAggregator.
+type SigningValidator struct {
+	NodeID    ids.NodeID
+	PublicKey *bls.PublicKey
+	Weight    uint64
+}
+
+// AggregateSignature is a BLS signature aggregated from the signatures of a
+// quorum of validators.
+type AggregateSignature struct {
+	Signature *bls.Signature
+	// Signers is the big-endian bitset of the validators whose signatures are
+	// included in [Signature]. Bit i refers to the i-th requested validator.
+	Signers []byte
+	// SignedWeight is the cumulative weight of the signers.
+	SignedWeight uint64
+	// TotalWeight is the cumulative weight of the requested validators.
+	TotalWeight uint64
+}
+
+// SignatureAggregator is the node service that collects the BLS signatures of
+// the validators of a chain's subnet over messages of the chain's VM.
+//
+// The other nodes only sign a message if their instance of the VM approves it,
+// see block.MessageVerifier.
+type SignatureAggregator interface {
+	// AggregateSignatures requests signatures over [msg] from [vdrs] and
+	// returns the aggregate once at least [quorumNum]/[quorumDen] of the total
+	// weight of [vdrs] has signed, once every validator has responded, or once
+	// [ctx] is done. Fails if less than the quorum signed.
+	AggregateSignatures(
+		ctx context.Context,
+		msg []byte,
+		vdrs []*SigningValidator,
+		quorumNum uint64,
+		quorumDen uint64,
+	) (*AggregateSignature, error)
+}
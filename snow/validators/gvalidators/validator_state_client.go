@@ -8,20 +8,41 @@ import (
 
 	"google.golang.org/protobuf/types/known/emptypb"
 
+	"github.com/ava-labs/avalanchego/cache"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/snow/validators"
 
 	pb "github.com/ava-labs/avalanchego/proto/pb/validatorstate"
 )
 
+// validatorSetsCacheSize is the number of validator sets cached by a Client.
+const validatorSetsCacheSize = 64
+
 var _ validators.State = (*Client)(nil)
 
+// validatorSetKey identifies a cached validator set. Sets requested by epoch
+// and by height are cached separately.
+type validatorSetKey struct {
+	atEpoch  bool
+	height   uint64
+	subnetID ids.ID
+}
+
 type Client struct {
 	client pb.ValidatorStateClient
+
+	// The validator set of a subnet at a given height never changes once the
+	// P-chain accepted that height, so validator sets are cached until
+	// they're evicted. The heights of the P-chain change, so they're always
+	// requested from the server.
+	validatorSets cache.LRU
 }
 
 func NewClient(client pb.ValidatorStateClient) *Client {
-	return &Client{client: client}
+	return &Client{
+		client:        client,
+		validatorSets: cache.LRU{Size: validatorSetsCacheSize},
+	}
 }
 
 func (c *Client) GetMinimumHeight(ctx context.Context) (uint64, error) {
@@ -41,6 +62,14 @@ func (c *Client) GetCurrentHeight(ctx context.Context) (uint64, error) {
 }
 
 func (c *Client) GetValidatorSet(ctx context.Context, height uint64, subnetID ids.ID) (map[ids.NodeID]uint64, error) {
+	key := validatorSetKey{
+		height:   height,
+		subnetID: subnetID,
+	}
+	if vdrs, ok := c.validatorSets.Get(key); ok {
+		return copyValidatorSet(vdrs.(map[ids.NodeID]uint64)), nil
+	}
+
 	resp, err := c.client.GetValidatorSet(ctx, &pb.GetValidatorSetRequest{
 		Height:   height,
 		SubnetId: subnetID[:],
@@ -48,7 +77,57 @@ func (c *Client) GetValidatorSet(ctx context.Context, height uint64, subnetID id
 	if err != nil {
 		return nil, err
 	}
+	return c.cacheValidatorSet(key, resp)
+}
+
+func (c *Client) GetEpochLength(ctx context.Context) (uint64, error) {
+	resp, err := c.client.GetEpochLength(ctx, &emptypb.Empty{})
+	if err != nil {
+		return 0, err
+	}
+	return resp.Length, nil
+}
+
+func (c *Client) GetValidatorSetAtEpoch(ctx context.Context, epoch uint64, subnetID ids.ID) (map[ids.NodeID]uint64, error) {
+	key := validatorSetKey{
+		atEpoch:  true,
+		height:   epoch,
+		subnetID: subnetID,
+	}
+	if vdrs, ok := c.validatorSets.Get(key); ok {
+		return copyValidatorSet(vdrs.(map[ids.NodeID]uint64)), nil
+	}
+
+	resp, err := c.client.GetValidatorSetAtEpoch(ctx, &pb.GetValidatorSetAtEpochRequest{
+		Epoch:    epoch,
+		SubnetId: subnetID[:],
+	})
+	if err != nil {
+		return nil, err
+	}
+	return c.cacheValidatorSet(key, resp)
+}
+
+func (c *Client) cacheValidatorSet(key validatorSetKey, resp *pb.GetValidatorSetResponse) (map[ids.NodeID]uint64, error) {
+	vdrs, err := parseValidatorSet(resp)
+	if err != nil {
+		return nil, err
+	}
+	c.validatorSets.Put(key, vdrs)
+	return copyValidatorSet(vdrs), nil
+}
+
+// copyValidatorSet returns a copy of [vdrs], so that callers can't modify the
+// cached validator sets.
+func copyValidatorSet(vdrs map[ids.NodeID]uint64) map[ids.NodeID]uint64 {
+	vdrsCopy := make(map[ids.NodeID]uint64, len(vdrs))
+	for nodeID, weight := range vdrs {
+		vdrsCopy[nodeID] = weight
+	}
+	return vdrsCopy
+}
 
+func parseValidatorSet(resp *pb.GetValidatorSetResponse) (map[ids.NodeID]uint64, error) {
 	vdrs := make(map[ids.NodeID]uint64, len(resp.Validators))
 	for _, validator := range resp.Validators {
 		nodeID, err := ids.ToNodeID(validator.NodeId)
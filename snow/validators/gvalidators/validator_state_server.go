@@ -45,7 +45,28 @@ func (s *Server) GetValidatorSet(ctx context.Context, req *pb.GetValidatorSetReq
 	if err != nil {
 		return nil, err
 	}
+	return validatorSetResponse(vdrs), nil
+}
+
+func (s *Server) GetEpochLength(ctx context.Context, _ *emptypb.Empty) (*pb.GetEpochLengthResponse, error) {
+	length, err := s.state.GetEpochLength(ctx)
+	return &pb.GetEpochLengthResponse{Length: length}, err
+}
+
+func (s *Server) GetValidatorSetAtEpoch(ctx context.Context, req *pb.GetValidatorSetAtEpochRequest) (*pb.GetValidatorSetResponse, error) {
+	subnetID, err := ids.ToID(req.SubnetId)
+	if err != nil {
+		return nil, err
+	}
+
+	vdrs, err := s.state.GetValidatorSetAtEpoch(ctx, req.Epoch, subnetID)
+	if err != nil {
+		return nil, err
+	}
+	return validatorSetResponse(vdrs), nil
+}
 
+func validatorSetResponse(vdrs map[ids.NodeID]uint64) *pb.GetValidatorSetResponse {
 	resp := &pb.GetValidatorSetResponse{
 		Validators: make([]*pb.Validator, len(vdrs)),
 	}
@@ -59,5 +80,5 @@ func (s *Server) GetValidatorSet(ctx context.Context, req *pb.GetValidatorSetReq
 		}
 		i++
 	}
-	return resp, nil
+	return resp
 }
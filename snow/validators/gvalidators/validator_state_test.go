@@ -143,9 +143,117 @@ func TestGetValidatorSet(t *testing.T) {
 	require.NoError(err)
 	require.Equal(expectedVdrs, vdrs)
 
+	// Cached path
+	vdrs, err = state.client.GetValidatorSet(context.Background(), height, subnetID)
+	require.NoError(err)
+	require.Equal(expectedVdrs, vdrs)
+
+	// Error path
+	state.server.EXPECT().GetValidatorSet(gomock.Any(), height+1, subnetID).Return(expectedVdrs, errCustom)
+
+	_, err = state.client.GetValidatorSet(context.Background(), height+1, subnetID)
+	require.Error(err)
+}
+
+func TestGetEpochLength(t *testing.T) {
+	require := require.New(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	state := setupState(t, ctrl)
+	defer state.closeFn()
+
+	// Happy path
+	expectedLength := uint64(1024)
+	state.server.EXPECT().GetEpochLength(gomock.Any()).Return(expectedLength, nil)
+
+	length, err := state.client.GetEpochLength(context.Background())
+	require.NoError(err)
+	require.Equal(expectedLength, length)
+
+	// Error path
+	state.server.EXPECT().GetEpochLength(gomock.Any()).Return(expectedLength, errCustom)
+
+	_, err = state.client.GetEpochLength(context.Background())
+	require.Error(err)
+}
+
+func TestGetValidatorSetAtEpoch(t *testing.T) {
+	require := require.New(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	state := setupState(t, ctrl)
+	defer state.closeFn()
+
+	// Happy path
+	expectedVdrs := map[ids.NodeID]uint64{
+		ids.GenerateTestNodeID(): 1,
+		ids.GenerateTestNodeID(): 2,
+	}
+	epoch := uint64(7)
+	subnetID := ids.GenerateTestID()
+	state.server.EXPECT().GetValidatorSetAtEpoch(gomock.Any(), epoch, subnetID).Return(expectedVdrs, nil)
+
+	vdrs, err := state.client.GetValidatorSetAtEpoch(context.Background(), epoch, subnetID)
+	require.NoError(err)
+	require.Equal(expectedVdrs, vdrs)
+
+	// Cached path, which must not be confused with the set at height [epoch]
+	vdrs, err = state.client.GetValidatorSetAtEpoch(context.Background(), epoch, subnetID)
+	require.NoError(err)
+	require.Equal(expectedVdrs, vdrs)
+
+	state.server.EXPECT().GetValidatorSet(gomock.Any(), epoch, subnetID).Return(nil, errCustom)
+	_, err = state.client.GetValidatorSet(context.Background(), epoch, subnetID)
+	require.Error(err)
+
 	// Error path
-	state.server.EXPECT().GetValidatorSet(gomock.Any(), height, subnetID).Return(expectedVdrs, errCustom)
+	state.server.EXPECT().GetValidatorSetAtEpoch(gomock.Any(), epoch+1, subnetID).Return(expectedVdrs, errCustom)
 
-	_, err = state.client.GetValidatorSet(context.Background(), height, subnetID)
+	_, err = state.client.GetValidatorSetAtEpoch(context.Background(), epoch+1, subnetID)
 	require.Error(err)
 }
+
+func TestGetCurrentHeightNotCached(t *testing.T) {
+	require := require.New(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	state := setupState(t, ctrl)
+	defer state.closeFn()
+
+	// Every call reaches the server, as the P-chain keeps accepting blocks.
+	state.server.EXPECT().GetCurrentHeight(gomock.Any()).Return(uint64(10), nil)
+	state.server.EXPECT().GetCurrentHeight(gomock.Any()).Return(uint64(11), nil)
+	for _, expectedHeight := range []uint64{10, 11} {
+		height, err := state.client.GetCurrentHeight(context.Background())
+		require.NoError(err)
+		require.Equal(expectedHeight, height)
+	}
+}
+
+func TestGetValidatorSetReturnsCopies(t *testing.T) {
+	require := require.New(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	state := setupState(t, ctrl)
+	defer state.closeFn()
+
+	nodeID := ids.GenerateTestNodeID()
+	height := uint64(1337)
+	subnetID := ids.GenerateTestID()
+	state.server.EXPECT().GetValidatorSet(gomock.Any(), height, subnetID).Return(map[ids.NodeID]uint64{
+		nodeID: 1,
+	}, nil)
+
+	vdrs, err := state.client.GetValidatorSet(context.Background(), height, subnetID)
+	require.NoError(err)
+	vdrs[nodeID] = 2
+	delete(vdrs, nodeID)
+
+	vdrs, err = state.client.GetValidatorSet(context.Background(), height, subnetID)
+	require.NoError(err)
+	require.Equal(map[ids.NodeID]uint64{nodeID: 1}, vdrs)
+}
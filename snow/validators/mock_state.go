@@ -53,6 +53,21 @@ func (mr *MockStateMockRecorder) GetCurrentHeight(arg0 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCurrentHeight", reflect.TypeOf((*MockState)(nil).GetCurrentHeight), arg0)
 }
 
+// GetEpochLength mocks base method.
+func (m *MockState) GetEpochLength(arg0 context.Context) (uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEpochLength", arg0)
+	ret0, _ := ret[0].(uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEpochLength indicates an expected call of GetEpochLength.
+func (mr *MockStateMockRecorder) GetEpochLength(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEpochLength", reflect.TypeOf((*MockState)(nil).GetEpochLength), arg0)
+}
+
 // GetMinimumHeight mocks base method.
 func (m *MockState) GetMinimumHeight(arg0 context.Context) (uint64, error) {
 	m.ctrl.T.Helper()
@@ -82,3 +97,18 @@ func (mr *MockStateMockRecorder) GetValidatorSet(arg0, arg1, arg2 interface{}) *
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetValidatorSet", reflect.TypeOf((*MockState)(nil).GetValidatorSet), arg0, arg1, arg2)
 }
+
+// GetValidatorSetAtEpoch mocks base method.
+func (m *MockState) GetValidatorSetAtEpoch(arg0 context.Context, arg1 uint64, arg2 ids.ID) (map[ids.NodeID]uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetValidatorSetAtEpoch", arg0, arg1, arg2)
+	ret0, _ := ret[0].(map[ids.NodeID]uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetValidatorSetAtEpoch indicates an expected call of GetValidatorSetAtEpoch.
+func (mr *MockStateMockRecorder) GetValidatorSetAtEpoch(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetValidatorSetAtEpoch", reflect.TypeOf((*MockState)(nil).GetValidatorSetAtEpoch), arg0, arg1, arg2)
+}
@@ -25,6 +25,16 @@ type State interface {
 	// subnet at the requested P-chain height.
 	// The returned map should not be modified.
 	GetValidatorSet(ctx context.Context, height uint64, subnetID ids.ID) (map[ids.NodeID]uint64, error)
+
+	// GetEpochLength returns the number of P-chain blocks in an epoch. The
+	// validator sets of the tracked subnets are snapshotted at the first
+	// height of each epoch, so they can be retrieved without reconstructing
+	// them from the current validator set.
+	GetEpochLength(context.Context) (uint64, error)
+	// GetValidatorSetAtEpoch returns the weights of the nodeIDs for the
+	// provided subnet at the P-chain height [epoch] begins at.
+	// The returned map should not be modified.
+	GetValidatorSetAtEpoch(ctx context.Context, epoch uint64, subnetID ids.ID) (map[ids.NodeID]uint64, error)
 }
 
 type lockedState struct {
@@ -60,6 +70,20 @@ func (s *lockedState) GetValidatorSet(ctx context.Context, height uint64, subnet
 	return s.s.GetValidatorSet(ctx, height, subnetID)
 }
 
+func (s *lockedState) GetEpochLength(ctx context.Context) (uint64, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.s.GetEpochLength(ctx)
+}
+
+func (s *lockedState) GetValidatorSetAtEpoch(ctx context.Context, epoch uint64, subnetID ids.ID) (map[ids.NodeID]uint64, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.s.GetValidatorSetAtEpoch(ctx, epoch, subnetID)
+}
+
 type noValidators struct {
 	State
 }
@@ -73,3 +97,7 @@ func NewNoValidatorsState(state State) State {
 func (*noValidators) GetValidatorSet(context.Context, uint64, ids.ID) (map[ids.NodeID]uint64, error) {
 	return nil, nil
 }
+
+func (*noValidators) GetValidatorSetAtEpoch(context.Context, uint64, ids.ID) (map[ids.NodeID]uint64, error) {
+	return nil, nil
+}
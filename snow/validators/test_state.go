@@ -15,6 +15,8 @@ var (
 	errMinimumHeight   = errors.New("unexpectedly called GetMinimumHeight")
 	errCurrentHeight   = errors.New("unexpectedly called GetCurrentHeight")
 	errGetValidatorSet = errors.New("unexpectedly called GetValidatorSet")
+	errGetEpochLength  = errors.New("unexpectedly called GetEpochLength")
+	errGetEpochSet     = errors.New("unexpectedly called GetValidatorSetAtEpoch")
 )
 
 var _ State = (*TestState)(nil)
@@ -24,11 +26,15 @@ type TestState struct {
 
 	CantGetMinimumHeight,
 	CantGetCurrentHeight,
-	CantGetValidatorSet bool
+	CantGetValidatorSet,
+	CantGetEpochLength,
+	CantGetValidatorSetAtEpoch bool
 
-	GetMinimumHeightF func(context.Context) (uint64, error)
-	GetCurrentHeightF func(context.Context) (uint64, error)
-	GetValidatorSetF  func(ctx context.Context, height uint64, subnetID ids.ID) (map[ids.NodeID]uint64, error)
+	GetMinimumHeightF       func(context.Context) (uint64, error)
+	GetCurrentHeightF       func(context.Context) (uint64, error)
+	GetValidatorSetF        func(ctx context.Context, height uint64, subnetID ids.ID) (map[ids.NodeID]uint64, error)
+	GetEpochLengthF         func(context.Context) (uint64, error)
+	GetValidatorSetAtEpochF func(ctx context.Context, epoch uint64, subnetID ids.ID) (map[ids.NodeID]uint64, error)
 }
 
 func (vm *TestState) GetMinimumHeight(ctx context.Context) (uint64, error) {
@@ -60,3 +66,23 @@ func (vm *TestState) GetValidatorSet(ctx context.Context, height uint64, subnetI
 	}
 	return nil, errGetValidatorSet
 }
+
+func (vm *TestState) GetEpochLength(ctx context.Context) (uint64, error) {
+	if vm.GetEpochLengthF != nil {
+		return vm.GetEpochLengthF(ctx)
+	}
+	if vm.CantGetEpochLength && vm.T != nil {
+		vm.T.Fatal(errGetEpochLength)
+	}
+	return 0, errGetEpochLength
+}
+
+func (vm *TestState) GetValidatorSetAtEpoch(ctx context.Context, epoch uint64, subnetID ids.ID) (map[ids.NodeID]uint64, error) {
+	if vm.GetValidatorSetAtEpochF != nil {
+		return vm.GetValidatorSetAtEpochF(ctx, epoch, subnetID)
+	}
+	if vm.CantGetValidatorSetAtEpoch && vm.T != nil {
+		vm.T.Fatal(errGetEpochSet)
+	}
+	return nil, errGetEpochSet
+}
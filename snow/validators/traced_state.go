@@ -22,6 +22,8 @@ type tracedState struct {
 	getMinimumHeightTag string
 	getCurrentHeightTag string
 	getValidatorSetTag  string
+	getEpochLengthTag   string
+	getEpochSetTag      string
 	tracer              trace.Tracer
 }
 
@@ -31,6 +33,8 @@ func Trace(s State, name string, tracer trace.Tracer) State {
 		getMinimumHeightTag: fmt.Sprintf("%s.GetMinimumHeight", name),
 		getCurrentHeightTag: fmt.Sprintf("%s.GetCurrentHeight", name),
 		getValidatorSetTag:  fmt.Sprintf("%s.GetValidatorSet", name),
+		getEpochLengthTag:   fmt.Sprintf("%s.GetEpochLength", name),
+		getEpochSetTag:      fmt.Sprintf("%s.GetValidatorSetAtEpoch", name),
 		tracer:              tracer,
 	}
 }
@@ -58,3 +62,20 @@ func (s *tracedState) GetValidatorSet(ctx context.Context, height uint64, subnet
 
 	return s.s.GetValidatorSet(ctx, height, subnetID)
 }
+
+func (s *tracedState) GetEpochLength(ctx context.Context) (uint64, error) {
+	ctx, span := s.tracer.Start(ctx, s.getEpochLengthTag)
+	defer span.End()
+
+	return s.s.GetEpochLength(ctx)
+}
+
+func (s *tracedState) GetValidatorSetAtEpoch(ctx context.Context, epoch uint64, subnetID ids.ID) (map[ids.NodeID]uint64, error) {
+	ctx, span := s.tracer.Start(ctx, s.getEpochSetTag, oteltrace.WithAttributes(
+		attribute.Int64("epoch", int64(epoch)),
+		attribute.Stringer("subnetID", subnetID),
+	))
+	defer span.End()
+
+	return s.s.GetValidatorSetAtEpoch(ctx, epoch, subnetID)
+}
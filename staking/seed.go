@@ -0,0 +1,207 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package staking
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/binary"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"time"
+
+	"github.com/ava-labs/avalanchego/utils/hashing"
+)
+
+const (
+	seededKeyBits            = 4096
+	seededKeyPublicExponent  = 65537
+	primalityTestIterations  = 20
+	seededTLSDomainSeparator = "avalanche staking tls"
+)
+
+var (
+	errEmptySeed = errors.New("seed must not be empty")
+
+	// Seeded certificates must not depend on the current time, otherwise the
+	// node ID would change on every restart.
+	seededCertNotBefore = time.Date(2000, time.January, 0, 0, 0, 0, 0, time.UTC)
+	seededCertNotAfter  = time.Date(2100, time.January, 0, 0, 0, 0, 0, time.UTC)
+)
+
+// NewTLSCertFromSeed deterministically derives a staking key/certificate pair,
+// and therefore a node ID, from [seed].
+//
+// Anyone who knows [seed] can impersonate the node, so this must only be used
+// for ephemeral test networks.
+func NewTLSCertFromSeed(seed []byte) (*tls.Certificate, error) {
+	certBytes, keyBytes, err := NewCertAndKeyBytesFromSeed(seed)
+	if err != nil {
+		return nil, err
+	}
+	return LoadTLSCertFromBytes(keyBytes, certBytes)
+}
+
+// NewCertAndKeyBytesFromSeed deterministically derives a staking private key /
+// staking certificate pair from [seed].
+// Returns the PEM byte representations of both.
+func NewCertAndKeyBytesFromSeed(seed []byte) ([]byte, []byte, error) {
+	if len(seed) == 0 {
+		return nil, nil, errEmptySeed
+	}
+
+	key, err := newSeededRSAKey(newSeedReader(seed, []byte(seededTLSDomainSeparator)), seededKeyBits)
+	if err != nil {
+		return nil, nil, fmt.Errorf("couldn't generate rsa key: %w", err)
+	}
+
+	// PKCS #1 v1.5 signatures are deterministic, so the certificate only
+	// depends on the key and the template.
+	certTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(0),
+		NotBefore:             seededCertNotBefore,
+		NotAfter:              seededCertNotAfter,
+		KeyUsage:              x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature | x509.KeyUsageDataEncipherment,
+		BasicConstraintsValid: true,
+	}
+	certBytes, err := x509.CreateCertificate(rand.Reader, certTemplate, certTemplate, &key.PublicKey, key)
+	if err != nil {
+		return nil, nil, fmt.Errorf("couldn't create certificate: %w", err)
+	}
+	var certBuff bytes.Buffer
+	if err := pem.Encode(&certBuff, &pem.Block{Type: "CERTIFICATE", Bytes: certBytes}); err != nil {
+		return nil, nil, fmt.Errorf("couldn't write cert file: %w", err)
+	}
+
+	privBytes, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, nil, fmt.Errorf("couldn't marshal private key: %w", err)
+	}
+
+	var keyBuff bytes.Buffer
+	if err := pem.Encode(&keyBuff, &pem.Block{Type: "PRIVATE KEY", Bytes: privBytes}); err != nil {
+		return nil, nil, fmt.Errorf("couldn't write private key: %w", err)
+	}
+	return certBuff.Bytes(), keyBuff.Bytes(), nil
+}
+
+// newSeedReader returns an infinite stream of bytes that is deterministically
+// derived from [seed]. Streams derived with different [domain]s are
+// independent.
+func newSeedReader(seed, domain []byte) io.Reader {
+	return &seedReader{
+		seed:   seed,
+		domain: domain,
+	}
+}
+
+type seedReader struct {
+	seed    []byte
+	domain  []byte
+	counter uint64
+	buf     []byte
+}
+
+func (r *seedReader) Read(p []byte) (int, error) {
+	n := 0
+	for n < len(p) {
+		if len(r.buf) == 0 {
+			var counter [8]byte
+			binary.BigEndian.PutUint64(counter[:], r.counter)
+			r.counter++
+
+			preimage := make([]byte, 0, len(r.domain)+len(r.seed)+len(counter))
+			preimage = append(preimage, r.domain...)
+			preimage = append(preimage, r.seed...)
+			preimage = append(preimage, counter[:]...)
+			r.buf = hashing.ComputeHash256(preimage)
+		}
+		copied := copy(p[n:], r.buf)
+		r.buf = r.buf[copied:]
+		n += copied
+	}
+	return n, nil
+}
+
+// newSeededRSAKey generates an RSA key using only the bytes read from [r].
+//
+// [rsa.GenerateKey] intentionally doesn't produce the same key for the same
+// randomness, so the primes are searched for directly.
+func newSeededRSAKey(r io.Reader, bits int) (*rsa.PrivateKey, error) {
+	e := big.NewInt(seededKeyPublicExponent)
+	one := big.NewInt(1)
+	for {
+		p, err := newSeededPrime(r, bits/2)
+		if err != nil {
+			return nil, err
+		}
+		q, err := newSeededPrime(r, bits-bits/2)
+		if err != nil {
+			return nil, err
+		}
+		if p.Cmp(q) == 0 {
+			continue
+		}
+
+		n := new(big.Int).Mul(p, q)
+		if n.BitLen() != bits {
+			continue
+		}
+
+		pMinus1 := new(big.Int).Sub(p, one)
+		qMinus1 := new(big.Int).Sub(q, one)
+		totient := new(big.Int).Mul(pMinus1, qMinus1)
+		d := new(big.Int).ModInverse(e, totient)
+		if d == nil {
+			continue
+		}
+
+		key := &rsa.PrivateKey{
+			PublicKey: rsa.PublicKey{
+				N: n,
+				E: seededKeyPublicExponent,
+			},
+			D:      d,
+			Primes: []*big.Int{p, q},
+		}
+		key.Precompute()
+		return key, key.Validate()
+	}
+}
+
+// newSeededPrime returns the first prime of length [bits] that is at least the
+// next candidate read from [r].
+func newSeededPrime(r io.Reader, bits int) (*big.Int, error) {
+	two := big.NewInt(2)
+	candidateBytes := make([]byte, (bits+7)/8)
+	for {
+		if _, err := io.ReadFull(r, candidateBytes); err != nil {
+			return nil, err
+		}
+
+		candidate := new(big.Int).SetBytes(candidateBytes)
+		// Drop any excess bits and set the top two bits so that the product
+		// of two such primes has exactly twice the number of bits.
+		candidate.SetBit(candidate, bits-1, 1)
+		candidate.SetBit(candidate, bits-2, 1)
+		for i := len(candidateBytes) * 8; i >= bits; i-- {
+			candidate.SetBit(candidate, i, 0)
+		}
+		// Only odd numbers can be prime.
+		candidate.SetBit(candidate, 0, 1)
+
+		for candidate.BitLen() == bits {
+			if candidate.ProbablyPrime(primalityTestIterations) {
+				return candidate, nil
+			}
+			candidate.Add(candidate, two)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package staking
+
+import (
+	"crypto"
+	"crypto/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/utils/hashing"
+)
+
+func TestNewCertAndKeyBytesFromSeed(t *testing.T) {
+	require := require.New(t)
+
+	certBytes0, keyBytes0, err := NewCertAndKeyBytesFromSeed([]byte("seed"))
+	require.NoError(err)
+
+	certBytes1, keyBytes1, err := NewCertAndKeyBytesFromSeed([]byte("seed"))
+	require.NoError(err)
+	require.Equal(certBytes0, certBytes1)
+	require.Equal(keyBytes0, keyBytes1)
+
+	certBytes2, _, err := NewCertAndKeyBytesFromSeed([]byte("other seed"))
+	require.NoError(err)
+	require.NotEqual(certBytes0, certBytes2)
+
+	cert, err := LoadTLSCertFromBytes(keyBytes0, certBytes0)
+	require.NoError(err)
+
+	msg := []byte("msg")
+	sig, err := cert.PrivateKey.(crypto.Signer).Sign(rand.Reader, hashing.ComputeHash256(msg), crypto.SHA256)
+	require.NoError(err)
+	require.NoError(cert.Leaf.CheckSignature(cert.Leaf.SignatureAlgorithm, msg, sig))
+}
+
+func TestNewCertAndKeyBytesFromEmptySeed(t *testing.T) {
+	_, _, err := NewCertAndKeyBytesFromSeed(nil)
+	require.ErrorIs(t, err, errEmptySeed)
+}
+
+func TestSeedReader(t *testing.T) {
+	require := require.New(t)
+
+	read := func(domain string, sizes ...int) []byte {
+		r := newSeedReader([]byte("seed"), []byte(domain))
+		var out []byte
+		for _, size := range sizes {
+			b := make([]byte, size)
+			n, err := r.Read(b)
+			require.NoError(err)
+			require.Equal(size, n)
+			out = append(out, b...)
+		}
+		return out
+	}
+
+	// The stream doesn't depend on how it is read.
+	require.Equal(read("a", 100), read("a", 1, 31, 33, 35))
+	require.NotEqual(read("a", 100), read("b", 100))
+}
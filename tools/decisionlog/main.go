@@ -0,0 +1,138 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// decisionlog prints the decision log of a chain, as written by a node run
+// with --decision-log-enabled.
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/engine/common/decisionlog"
+)
+
+var errMissingDir = errors.New("missing -dir")
+
+type filter struct {
+	kinds  map[decisionlog.Kind]bool
+	blkID  ids.ID
+	nodeID ids.NodeID
+	since  time.Time
+	until  time.Time
+}
+
+func (f *filter) matches(entry decisionlog.Entry) bool {
+	if len(f.kinds) > 0 && !f.kinds[entry.Kind] {
+		return false
+	}
+	if f.blkID != ids.Empty && entry.BlkID != f.blkID {
+		return false
+	}
+	if f.nodeID != ids.EmptyNodeID && entry.NodeID != f.nodeID && !containsNodeID(entry.NodeIDs, f.nodeID) {
+		return false
+	}
+	if !f.since.IsZero() && entry.Timestamp.Before(f.since) {
+		return false
+	}
+	if !f.until.IsZero() && entry.Timestamp.After(f.until) {
+		return false
+	}
+	return true
+}
+
+func containsNodeID(nodeIDs []ids.NodeID, nodeID ids.NodeID) bool {
+	for _, id := range nodeIDs {
+		if id == nodeID {
+			return true
+		}
+	}
+	return false
+}
+
+func main() {
+	if err := run(); err != nil {
+		fmt.Fprintf(os.Stderr, "decisionlog: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	dir := flag.String("dir", "", "Decision log of a chain, which is the directory [decision-log-dir]/[chainID]")
+	kinds := flag.String("kinds", "", "Comma separated kinds of the entries to print. Any of pollIssued, voteReceived, queryFailed, accepted, rejected. If empty, every entry is printed")
+	blkID := flag.String("blk", "", "If set, only the entries about this block are printed")
+	nodeID := flag.String("node", "", "If set, only the entries about this node are printed")
+	since := flag.String("since", "", "If set, only the entries recorded at or after this RFC3339 time are printed")
+	until := flag.String("until", "", "If set, only the entries recorded at or before this RFC3339 time are printed")
+	jsonOutput := flag.Bool("json", false, "If true, entries are printed as JSON, one per line")
+	flag.Parse()
+
+	if *dir == "" {
+		return errMissingDir
+	}
+
+	f := filter{
+		kinds: make(map[decisionlog.Kind]bool),
+	}
+	for _, kind := range strings.Split(*kinds, ",") {
+		if kind = strings.TrimSpace(kind); kind != "" {
+			f.kinds[decisionlog.Kind(kind)] = true
+		}
+	}
+	var err error
+	if *blkID != "" {
+		if f.blkID, err = ids.FromString(*blkID); err != nil {
+			return fmt.Errorf("invalid -blk: %w", err)
+		}
+	}
+	if *nodeID != "" {
+		if f.nodeID, err = ids.NodeIDFromString(*nodeID); err != nil {
+			return fmt.Errorf("invalid -node: %w", err)
+		}
+	}
+	if *since != "" {
+		if f.since, err = time.Parse(time.RFC3339, *since); err != nil {
+			return fmt.Errorf("invalid -since: %w", err)
+		}
+	}
+	if *until != "" {
+		if f.until, err = time.Parse(time.RFC3339, *until); err != nil {
+			return fmt.Errorf("invalid -until: %w", err)
+		}
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	return decisionlog.Read(*dir, func(entry decisionlog.Entry) error {
+		if !f.matches(entry) {
+			return nil
+		}
+		if *jsonOutput {
+			return encoder.Encode(entry)
+		}
+		_, err := fmt.Println(format(entry))
+		return err
+	})
+}
+
+// format [entry] as a single human readable line.
+func format(entry decisionlog.Entry) string {
+	sb := strings.Builder{}
+	fmt.Fprintf(&sb, "%s %-12s", entry.Timestamp.Format(time.RFC3339Nano), entry.Kind)
+	switch entry.Kind {
+	case decisionlog.PollIssued:
+		fmt.Fprintf(&sb, " requestID=%d blkID=%s validators=%v", entry.RequestID, entry.BlkID, entry.NodeIDs)
+	case decisionlog.VoteReceived:
+		fmt.Fprintf(&sb, " requestID=%d nodeID=%s blkID=%s", entry.RequestID, entry.NodeID, entry.BlkID)
+	case decisionlog.QueryFailed:
+		fmt.Fprintf(&sb, " requestID=%d nodeID=%s", entry.RequestID, entry.NodeID)
+	default:
+		fmt.Fprintf(&sb, " blkID=%s height=%d", entry.BlkID, entry.Height)
+	}
+	return sb.String()
+}
@@ -0,0 +1,60 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package attestation produces and verifies hardware attestation evidence that
+// a node runs in a confidential VM, such as an AMD SEV-SNP or an Intel TDX
+// guest. The evidence is bound to the ID of the node and to a nonce chosen by
+// the verifier. SEV-SNP evidence is verified by this package, while TDX
+// evidence is only checked to be bound to the node and the nonce here, and its
+// signature is verified off-node.
+package attestation
+
+import (
+	"crypto/sha512"
+
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+const (
+	// TypeSEVSNP is the type of the attestation reports of AMD SEV-SNP guests
+	TypeSEVSNP = "sev-snp"
+	// TypeTDX is the type of the quotes of Intel TDX guests
+	TypeTDX = "tdx"
+
+	// ReportDataLen is the number of bytes of user data bound to the evidence
+	ReportDataLen = 64
+
+	// MaxNonceLen is the max number of bytes of the nonce of the verifier
+	MaxNonceLen = 64
+)
+
+// Prefixes the hashed report data, so that the evidence can't be confused with
+// evidence bound to other data.
+var reportDataPrefix = []byte("avalanche node attestation")
+
+// Evidence is the hardware attestation evidence of a node.
+type Evidence struct {
+	// Type of the evidence, either TypeSEVSNP or TypeTDX
+	Type string
+	// Report is the attestation report or quote, as produced by the hardware
+	Report []byte
+}
+
+// Attester produces attestation evidence.
+type Attester interface {
+	// Attest returns evidence whose report data is [reportData].
+	Attest(reportData [ReportDataLen]byte) (*Evidence, error)
+}
+
+// ReportData returns the report data that binds evidence to [nodeID] and to
+// the [nonce] chosen by the verifier.
+func ReportData(nodeID ids.NodeID, nonce []byte) [ReportDataLen]byte {
+	hasher := sha512.New()
+	_, _ = hasher.Write(reportDataPrefix)
+	_, _ = hasher.Write(nodeID[:])
+	_, _ = hasher.Write(nonce)
+
+	var reportData [ReportDataLen]byte
+	copy(reportData[:], hasher.Sum(nil))
+	return reportData
+}
@@ -0,0 +1,128 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package attestation
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/sha512"
+	"crypto/x509"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+const (
+	// SEVSNPMeasurementLen is the number of bytes of the launch measurement of
+	// an SEV-SNP guest
+	SEVSNPMeasurementLen = 48
+
+	// Length of an SEV-SNP attestation report
+	sevSNPReportLen = 0x4A0
+	// Offset of the signature algorithm in an SEV-SNP attestation report
+	sevSNPSignatureAlgoOffset = 0x34
+	// Offset of the launch measurement in an SEV-SNP attestation report
+	sevSNPMeasurementOffset = 0x90
+	// Offset of the signature in an SEV-SNP attestation report. The signature
+	// covers the report up to this offset.
+	sevSNPSignatureOffset = 0x2A0
+	// Length of each of the little-endian R and S components of the signature
+	sevSNPSignatureComponentLen = 72
+	// Signature algorithm of reports signed with ECDSA P-384 with SHA-384
+	sevSNPSignatureAlgoECDSAP384SHA384 = 1
+)
+
+var (
+	errUnsupportedType          = errors.New("unsupported evidence type")
+	errUnsupportedSignatureAlgo = errors.New("unsupported signature algorithm")
+	errUntrustedVCEK            = errors.New("untrusted VCEK")
+	errInvalidVCEKKey           = errors.New("VCEK isn't an ECDSA P-384 key")
+	errInvalidSignature         = errors.New("invalid signature")
+	errUntrustedMeasurement     = errors.New("untrusted measurement")
+
+	_ SignatureVerifier = (*SEVSNPVerifier)(nil)
+)
+
+// SEVSNPVerifier verifies that AMD SEV-SNP attestation reports were signed by
+// the versioned chip endorsement key (VCEK) of a genuine AMD processor.
+//
+// The VCEK depends on the chip and on its TCB version, so it's fetched by the
+// verifier from the AMD Key Distribution Service, along with the certificates
+// of the AMD root key (ARK) and of the AMD SEV key (ASK) that endorse it.
+//
+// Intel TDX quotes aren't verified by this package, as they are endorsed by
+// collateral only Intel's quote verification library checks. They have to be
+// verified off-node, after Verify has checked that they are bound to the node
+// ID and to the nonce.
+type SEVSNPVerifier struct {
+	// Roots are the trusted ARK certificates
+	Roots *x509.CertPool
+	// Intermediates are the ASK certificates
+	Intermediates *x509.CertPool
+	// VCEK is the certificate of the key that signed the reports
+	VCEK *x509.Certificate
+	// Measurements are the trusted launch measurements of the guest. If empty,
+	// the measurement isn't checked.
+	Measurements [][SEVSNPMeasurementLen]byte
+}
+
+func (v *SEVSNPVerifier) VerifySignature(evidence *Evidence) error {
+	if evidence.Type != TypeSEVSNP {
+		return fmt.Errorf("%w: %q", errUnsupportedType, evidence.Type)
+	}
+	report := evidence.Report
+	if len(report) < sevSNPReportLen {
+		return fmt.Errorf("%w: %s report is %d bytes",
+			errReportTooShort,
+			evidence.Type,
+			len(report),
+		)
+	}
+	signatureAlgo := binary.LittleEndian.Uint32(report[sevSNPSignatureAlgoOffset:])
+	if signatureAlgo != sevSNPSignatureAlgoECDSAP384SHA384 {
+		return fmt.Errorf("%w: %d", errUnsupportedSignatureAlgo, signatureAlgo)
+	}
+
+	if _, err := v.VCEK.Verify(x509.VerifyOptions{
+		Roots:         v.Roots,
+		Intermediates: v.Intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	}); err != nil {
+		return fmt.Errorf("%w: %s", errUntrustedVCEK, err)
+	}
+	key, ok := v.VCEK.PublicKey.(*ecdsa.PublicKey)
+	if !ok || key.Curve != elliptic.P384() {
+		return errInvalidVCEKKey
+	}
+
+	signature := report[sevSNPSignatureOffset:]
+	r := littleEndianInt(signature[:sevSNPSignatureComponentLen])
+	s := littleEndianInt(signature[sevSNPSignatureComponentLen : 2*sevSNPSignatureComponentLen])
+	digest := sha512.Sum384(report[:sevSNPSignatureOffset])
+	if !ecdsa.Verify(key, digest[:], r, s) {
+		return errInvalidSignature
+	}
+
+	if len(v.Measurements) == 0 {
+		return nil
+	}
+	measurement := report[sevSNPMeasurementOffset : sevSNPMeasurementOffset+SEVSNPMeasurementLen]
+	for _, trusted := range v.Measurements {
+		if bytes.Equal(measurement, trusted[:]) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %x", errUntrustedMeasurement, measurement)
+}
+
+// littleEndianInt returns the unsigned integer encoded in little-endian by [b].
+func littleEndianInt(b []byte) *big.Int {
+	bigEndian := make([]byte, len(b))
+	for i, v := range b {
+		bigEndian[len(b)-1-i] = v
+	}
+	return new(big.Int).SetBytes(bigEndian)
+}
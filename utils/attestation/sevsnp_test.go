@@ -0,0 +1,169 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package attestation
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha512"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/binary"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+// newTestCert returns a certificate of [key] named [name], signed by [parent]
+// with [parentKey]. The certificate is self-signed if [parent] is nil.
+func newTestCert(t *testing.T, name string, key *ecdsa.PrivateKey, parent *x509.Certificate, parentKey crypto.Signer) *x509.Certificate {
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  name != "VCEK",
+	}
+	if parent == nil {
+		parent = template
+		parentKey = key
+	}
+	certBytes, err := x509.CreateCertificate(rand.Reader, template, parent, key.Public(), parentKey)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(certBytes)
+	require.NoError(t, err)
+	return cert
+}
+
+func newTestKey(t *testing.T) *ecdsa.PrivateKey {
+	key, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+	return key
+}
+
+// newTestSEVSNPReport returns an SEV-SNP report of [reportData] and
+// [measurement], signed with [key].
+func newTestSEVSNPReport(t *testing.T, reportData [ReportDataLen]byte, measurement [SEVSNPMeasurementLen]byte, key *ecdsa.PrivateKey) []byte {
+	report := make([]byte, sevSNPReportLen)
+	binary.LittleEndian.PutUint32(report, 2)
+	binary.LittleEndian.PutUint32(report[sevSNPSignatureAlgoOffset:], sevSNPSignatureAlgoECDSAP384SHA384)
+	copy(report[sevSNPReportDataOffset:], reportData[:])
+	copy(report[sevSNPMeasurementOffset:], measurement[:])
+
+	digest := sha512.Sum384(report[:sevSNPSignatureOffset])
+	r, s, err := ecdsa.Sign(rand.Reader, key, digest[:])
+	require.NoError(t, err)
+	putLittleEndianInt(report[sevSNPSignatureOffset:], r)
+	putLittleEndianInt(report[sevSNPSignatureOffset+sevSNPSignatureComponentLen:], s)
+	return report
+}
+
+func putLittleEndianInt(b []byte, v *big.Int) {
+	bigEndian := v.Bytes()
+	for i, byteValue := range bigEndian {
+		b[len(bigEndian)-1-i] = byteValue
+	}
+}
+
+func TestSEVSNPVerifier(t *testing.T) {
+	arkKey, askKey, vcekKey := newTestKey(t), newTestKey(t), newTestKey(t)
+	ark := newTestCert(t, "ARK", arkKey, nil, nil)
+	ask := newTestCert(t, "ASK", askKey, ark, arkKey)
+	vcek := newTestCert(t, "VCEK", vcekKey, ask, askKey)
+	untrustedVCEK := newTestCert(t, "VCEK", vcekKey, nil, nil)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ark)
+	intermediates := x509.NewCertPool()
+	intermediates.AddCert(ask)
+
+	nodeID := ids.GenerateTestNodeID()
+	nonce := []byte("nonce")
+	reportData := ReportData(nodeID, nonce)
+	measurement := [SEVSNPMeasurementLen]byte{1, 2, 3}
+	report := newTestSEVSNPReport(t, reportData, measurement, vcekKey)
+
+	tamperedReport := append([]byte{}, report...)
+	tamperedReport[sevSNPMeasurementOffset]++
+
+	otherAlgoReport := append([]byte{}, report...)
+	binary.LittleEndian.PutUint32(otherAlgoReport[sevSNPSignatureAlgoOffset:], 2)
+
+	tests := []struct {
+		name         string
+		evidence     *Evidence
+		vcek         *x509.Certificate
+		measurements [][SEVSNPMeasurementLen]byte
+		expectedErr  error
+	}{
+		{
+			name:     "valid",
+			evidence: &Evidence{Type: TypeSEVSNP, Report: report},
+			vcek:     vcek,
+		},
+		{
+			name:         "trusted measurement",
+			evidence:     &Evidence{Type: TypeSEVSNP, Report: report},
+			vcek:         vcek,
+			measurements: [][SEVSNPMeasurementLen]byte{{4}, measurement},
+		},
+		{
+			name:         "untrusted measurement",
+			evidence:     &Evidence{Type: TypeSEVSNP, Report: report},
+			vcek:         vcek,
+			measurements: [][SEVSNPMeasurementLen]byte{{4}},
+			expectedErr:  errUntrustedMeasurement,
+		},
+		{
+			name:        "tampered report",
+			evidence:    &Evidence{Type: TypeSEVSNP, Report: tamperedReport},
+			vcek:        vcek,
+			expectedErr: errInvalidSignature,
+		},
+		{
+			name:        "untrusted VCEK",
+			evidence:    &Evidence{Type: TypeSEVSNP, Report: report},
+			vcek:        untrustedVCEK,
+			expectedErr: errUntrustedVCEK,
+		},
+		{
+			name:        "unsupported signature algorithm",
+			evidence:    &Evidence{Type: TypeSEVSNP, Report: otherAlgoReport},
+			vcek:        vcek,
+			expectedErr: errUnsupportedSignatureAlgo,
+		},
+		{
+			name:        "report too short",
+			evidence:    &Evidence{Type: TypeSEVSNP, Report: report[:sevSNPSignatureOffset]},
+			vcek:        vcek,
+			expectedErr: errReportTooShort,
+		},
+		{
+			name:        "tdx",
+			evidence:    newTestEvidence(TypeTDX, tdxReportDataOffset, reportData),
+			vcek:        vcek,
+			expectedErr: errUnsupportedType,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			verifier := &SEVSNPVerifier{
+				Roots:         roots,
+				Intermediates: intermediates,
+				VCEK:          test.vcek,
+				Measurements:  test.measurements,
+			}
+			err := Verify(test.evidence, nodeID, nonce, verifier)
+			require.ErrorIs(t, err, test.expectedErr)
+		})
+	}
+}
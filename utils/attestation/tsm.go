@@ -0,0 +1,86 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package attestation
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// DefaultTSMDir is where the Linux configfs-tsm interface exposes attestation
+// reports.
+const DefaultTSMDir = "/sys/kernel/config/tsm/report"
+
+var (
+	errUnknownProvider = errors.New("unknown attestation provider")
+
+	_ Attester = (*tsmAttester)(nil)
+)
+
+// Maps the providers of the configfs-tsm interface to the type of the evidence
+// they produce.
+var tsmProviders = map[string]string{
+	"sev_guest": TypeSEVSNP,
+	"tdx_guest": TypeTDX,
+}
+
+type tsmAttester struct {
+	dir string
+
+	// Reports are requested one at a time.
+	lock sync.Mutex
+}
+
+// NewTSMAttester returns an Attester that requests evidence from the Linux
+// configfs-tsm interface mounted at [dir]. Returns an error if the interface
+// isn't available, such as when the node doesn't run in a confidential VM.
+func NewTSMAttester(dir string) (Attester, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, fmt.Errorf("attestation reports unavailable: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("attestation reports unavailable: %q isn't a directory", dir)
+	}
+	return &tsmAttester{
+		dir: dir,
+	}, nil
+}
+
+func (a *tsmAttester) Attest(reportData [ReportDataLen]byte) (*Evidence, error) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	// Each report is requested through a new entry of the interface.
+	entry, err := os.MkdirTemp(a.dir, "avalanchego-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(entry)
+
+	if err := os.WriteFile(filepath.Join(entry, "inblob"), reportData[:], 0); err != nil {
+		return nil, err
+	}
+	providerBytes, err := os.ReadFile(filepath.Join(entry, "provider"))
+	if err != nil {
+		return nil, err
+	}
+	provider := strings.TrimSpace(string(providerBytes))
+	evidenceType, ok := tsmProviders[provider]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", errUnknownProvider, provider)
+	}
+	report, err := os.ReadFile(filepath.Join(entry, "outblob"))
+	if err != nil {
+		return nil, err
+	}
+	return &Evidence{
+		Type:   evidenceType,
+		Report: report,
+	}, nil
+}
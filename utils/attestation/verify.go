@@ -0,0 +1,82 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package attestation
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+const (
+	// Offset of the report data in an SEV-SNP attestation report
+	sevSNPReportDataOffset = 0x50
+	// Offset of the report data in a TDX quote: the report data follows the
+	// 48 byte header of the quote and 520 bytes of the TD quote body.
+	tdxReportDataOffset = 48 + 520
+)
+
+var (
+	errUnknownType        = errors.New("unknown evidence type")
+	errReportTooShort     = errors.New("report too short")
+	errMissingVerifier    = errors.New("missing signature verifier")
+	errNonceTooLong       = errors.New("nonce too long")
+	ErrReportDataMismatch = errors.New("report data doesn't match the node ID and nonce")
+)
+
+// SignatureVerifier verifies that evidence was signed by genuine hardware, and
+// that the measurements it reports are trusted. Implementations rely on the
+// certificate chains and policies of the hardware vendors. SEVSNPVerifier
+// verifies SEV-SNP reports; TDX quotes are verified off-node.
+type SignatureVerifier interface {
+	VerifySignature(evidence *Evidence) error
+}
+
+// GetReportData returns the report data of [evidence]. The signature of the
+// evidence isn't verified.
+func GetReportData(evidence *Evidence) ([ReportDataLen]byte, error) {
+	var offset int
+	switch evidence.Type {
+	case TypeSEVSNP:
+		offset = sevSNPReportDataOffset
+	case TypeTDX:
+		offset = tdxReportDataOffset
+	default:
+		return [ReportDataLen]byte{}, fmt.Errorf("%w: %q", errUnknownType, evidence.Type)
+	}
+	if len(evidence.Report) < offset+ReportDataLen {
+		return [ReportDataLen]byte{}, fmt.Errorf("%w: %s report is %d bytes",
+			errReportTooShort,
+			evidence.Type,
+			len(evidence.Report),
+		)
+	}
+
+	var reportData [ReportDataLen]byte
+	copy(reportData[:], evidence.Report[offset:])
+	return reportData, nil
+}
+
+// Verify returns nil if [evidence] is bound to [nodeID] and [nonce], and its
+// signature is verified by [verifier].
+func Verify(evidence *Evidence, nodeID ids.NodeID, nonce []byte, verifier SignatureVerifier) error {
+	if verifier == nil {
+		return errMissingVerifier
+	}
+	if len(nonce) > MaxNonceLen {
+		return fmt.Errorf("%w: %d > %d", errNonceTooLong, len(nonce), MaxNonceLen)
+	}
+
+	reportData, err := GetReportData(evidence)
+	if err != nil {
+		return err
+	}
+	expectedReportData := ReportData(nodeID, nonce)
+	if !bytes.Equal(reportData[:], expectedReportData[:]) {
+		return ErrReportDataMismatch
+	}
+	return verifier.VerifySignature(evidence)
+}
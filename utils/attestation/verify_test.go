@@ -0,0 +1,140 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package attestation
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+var errBadSignature = errors.New("bad signature")
+
+type testVerifier struct {
+	err error
+}
+
+func (v testVerifier) VerifySignature(*Evidence) error {
+	return v.err
+}
+
+func newTestEvidence(evidenceType string, offset int, reportData [ReportDataLen]byte) *Evidence {
+	report := make([]byte, offset+ReportDataLen+16)
+	copy(report[offset:], reportData[:])
+	return &Evidence{
+		Type:   evidenceType,
+		Report: report,
+	}
+}
+
+func TestReportData(t *testing.T) {
+	require := require.New(t)
+
+	nodeID := ids.GenerateTestNodeID()
+	nonce := []byte{1, 2, 3}
+	require.Equal(ReportData(nodeID, nonce), ReportData(nodeID, nonce))
+	require.NotEqual(ReportData(nodeID, nonce), ReportData(nodeID, []byte{1, 2, 4}))
+	require.NotEqual(ReportData(nodeID, nonce), ReportData(ids.GenerateTestNodeID(), nonce))
+}
+
+func TestVerify(t *testing.T) {
+	nodeID := ids.GenerateTestNodeID()
+	nonce := []byte("nonce")
+	reportData := ReportData(nodeID, nonce)
+
+	tests := []struct {
+		name        string
+		evidence    *Evidence
+		nonce       []byte
+		verifier    SignatureVerifier
+		expectedErr error
+	}{
+		{
+			name:     "sev-snp",
+			evidence: newTestEvidence(TypeSEVSNP, sevSNPReportDataOffset, reportData),
+			nonce:    nonce,
+			verifier: testVerifier{},
+		},
+		{
+			name:     "tdx",
+			evidence: newTestEvidence(TypeTDX, tdxReportDataOffset, reportData),
+			nonce:    nonce,
+			verifier: testVerifier{},
+		},
+		{
+			name:        "wrong nonce",
+			evidence:    newTestEvidence(TypeSEVSNP, sevSNPReportDataOffset, reportData),
+			nonce:       []byte("other nonce"),
+			verifier:    testVerifier{},
+			expectedErr: ErrReportDataMismatch,
+		},
+		{
+			name:        "report data at the wrong offset",
+			evidence:    newTestEvidence(TypeSEVSNP, tdxReportDataOffset, reportData),
+			nonce:       nonce,
+			verifier:    testVerifier{},
+			expectedErr: ErrReportDataMismatch,
+		},
+		{
+			name:        "bad signature",
+			evidence:    newTestEvidence(TypeTDX, tdxReportDataOffset, reportData),
+			nonce:       nonce,
+			verifier:    testVerifier{err: errBadSignature},
+			expectedErr: errBadSignature,
+		},
+		{
+			name:        "missing verifier",
+			evidence:    newTestEvidence(TypeTDX, tdxReportDataOffset, reportData),
+			nonce:       nonce,
+			expectedErr: errMissingVerifier,
+		},
+		{
+			name:        "nonce too long",
+			evidence:    newTestEvidence(TypeTDX, tdxReportDataOffset, reportData),
+			nonce:       make([]byte, MaxNonceLen+1),
+			verifier:    testVerifier{},
+			expectedErr: errNonceTooLong,
+		},
+		{
+			name: "unknown type",
+			evidence: &Evidence{
+				Type:   "unknown",
+				Report: make([]byte, 1024),
+			},
+			nonce:       nonce,
+			verifier:    testVerifier{},
+			expectedErr: errUnknownType,
+		},
+		{
+			name: "report too short",
+			evidence: &Evidence{
+				Type:   TypeTDX,
+				Report: make([]byte, tdxReportDataOffset),
+			},
+			nonce:       nonce,
+			verifier:    testVerifier{},
+			expectedErr: errReportTooShort,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := Verify(test.evidence, nodeID, test.nonce, test.verifier)
+			require.ErrorIs(t, err, test.expectedErr)
+		})
+	}
+}
+
+func TestNewTSMAttesterUnavailable(t *testing.T) {
+	require := require.New(t)
+
+	dir := t.TempDir()
+	_, err := NewTSMAttester(dir + "/missing")
+	require.Error(err)
+
+	_, err = NewTSMAttester(dir)
+	require.NoError(err)
+}
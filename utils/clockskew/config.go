@@ -0,0 +1,48 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package clockskew
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	errNonPositiveThreshold = errors.New("clock skew thresholds must be positive")
+	errCriticalBelowWarn    = errors.New("critical clock skew threshold must be at least the warning threshold")
+	errNonPositiveNTPConfig = errors.New("NTP query frequency and timeout must be positive")
+)
+
+type Config struct {
+	// WarnThreshold is the estimated skew at which a warning is reported.
+	WarnThreshold time.Duration `json:"warnThreshold"`
+	// CriticalThreshold is the estimated skew at which the node reports
+	// unhealthy.
+	CriticalThreshold time.Duration `json:"criticalThreshold"`
+	// MinPeers is the number of peers whose timestamps are required to
+	// estimate the skew from peers.
+	MinPeers int `json:"minPeers"`
+
+	// NTPServer is the address of the NTP server the clock is compared
+	// against. If empty, NTP isn't queried.
+	NTPServer string `json:"ntpServer"`
+	// NTPFrequency is how often [NTPServer] is queried.
+	NTPFrequency time.Duration `json:"ntpFrequency"`
+	// NTPTimeout is the maximum amount of time a query of [NTPServer] may take.
+	NTPTimeout time.Duration `json:"ntpTimeout"`
+}
+
+func (c *Config) Verify() error {
+	switch {
+	case c.WarnThreshold <= 0 || c.CriticalThreshold <= 0:
+		return errNonPositiveThreshold
+	case c.CriticalThreshold < c.WarnThreshold:
+		return fmt.Errorf("%w: %s < %s", errCriticalBelowWarn, c.CriticalThreshold, c.WarnThreshold)
+	case c.NTPServer != "" && (c.NTPFrequency <= 0 || c.NTPTimeout <= 0):
+		return errNonPositiveNTPConfig
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,274 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package clockskew estimates how far the local clock is from the clocks of
+// the rest of the network.
+package clockskew
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"go.uber.org/zap"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/timer/mockable"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+)
+
+const (
+	// Times reported by peers are forgotten after this long, so that peers
+	// that disconnected no longer count.
+	maxObservationAge = 5 * time.Minute
+
+	sourcePeers = "peers"
+	sourceNTP   = "ntp"
+)
+
+var (
+	errCriticalSkew = errors.New("clock skew is critical")
+
+	_ Estimator = (*estimator)(nil)
+)
+
+// Estimator estimates the skew of the local clock: how far ahead of the
+// network the local clock is. A negative skew means the local clock is
+// behind.
+type Estimator interface {
+	// Observe records that [nodeID] reported its time as [peerTime]. Only the
+	// latest time reported by each peer is used.
+	Observe(nodeID ids.NodeID, peerTime time.Time)
+
+	// Skew returns the estimated skew, and false if there isn't enough
+	// information to estimate it.
+	Skew() (time.Duration, bool)
+
+	// HealthCheck returns an error if the estimated skew reaches the critical
+	// threshold.
+	HealthCheck(context.Context) (interface{}, error)
+
+	// Shutdown stops querying the NTP server.
+	Shutdown()
+}
+
+type observation struct {
+	// Skew of the local clock relative to the peer
+	skew time.Duration
+	// Local time of the observation
+	time time.Time
+}
+
+type estimator struct {
+	log     logging.Logger
+	config  Config
+	clock   mockable.Clock
+	onClose chan struct{}
+	closed  sync.Once
+
+	peerSkewMetric prometheus.Gauge
+	ntpSkewMetric  prometheus.Gauge
+
+	lock sync.Mutex
+	// Node ID -> latest observation of the node's time
+	observations map[ids.NodeID]observation
+	// Skew relative to the NTP server, valid if [ntpSkewSet]
+	ntpSkew    time.Duration
+	ntpSkewSet bool
+	ntpErr     error
+}
+
+// NewEstimator returns an Estimator that estimates the skew from the times
+// reported by peers and, if configured, from an NTP server. The NTP server is
+// queried in the background until Shutdown is called.
+func NewEstimator(
+	log logging.Logger,
+	config Config,
+	namespace string,
+	registerer prometheus.Registerer,
+) (Estimator, error) {
+	if err := config.Verify(); err != nil {
+		return nil, err
+	}
+
+	e := &estimator{
+		log:     log,
+		config:  config,
+		onClose: make(chan struct{}),
+		peerSkewMetric: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "peers_seconds",
+			Help:      "Median skew, in seconds, of the local clock relative to the times reported by peers",
+		}),
+		ntpSkewMetric: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "ntp_seconds",
+			Help:      "Skew, in seconds, of the local clock relative to the NTP server",
+		}),
+		observations: make(map[ids.NodeID]observation),
+	}
+
+	errs := wrappers.Errs{}
+	errs.Add(
+		registerer.Register(e.peerSkewMetric),
+		registerer.Register(e.ntpSkewMetric),
+	)
+	if errs.Errored() {
+		return nil, errs.Err
+	}
+
+	if config.NTPServer != "" {
+		go e.queryNTP()
+	}
+	return e, nil
+}
+
+func (e *estimator) Observe(nodeID ids.NodeID, peerTime time.Time) {
+	now := e.clock.Time()
+
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	e.observations[nodeID] = observation{
+		skew: now.Sub(peerTime),
+		time: now,
+	}
+}
+
+func (e *estimator) Skew() (time.Duration, bool) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	skew, _, ok := e.skew()
+	return skew, ok
+}
+
+func (e *estimator) HealthCheck(context.Context) (interface{}, error) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	peerSkew, ok := e.peerSkew()
+	details := map[string]interface{}{
+		"peers": len(e.observations),
+	}
+	if ok {
+		details["peerSkew"] = peerSkew.String()
+		e.peerSkewMetric.Set(peerSkew.Seconds())
+	}
+	if e.ntpSkewSet {
+		details["ntpSkew"] = e.ntpSkew.String()
+	}
+	if e.ntpErr != nil {
+		details["ntpError"] = e.ntpErr.Error()
+	}
+
+	skew, source, ok := e.skew()
+	if !ok {
+		return details, nil
+	}
+	details["skew"] = skew.String()
+	details["source"] = source
+
+	absSkew := skew
+	if absSkew < 0 {
+		absSkew = -absSkew
+	}
+	switch {
+	case absSkew >= e.config.CriticalThreshold:
+		return details, fmt.Errorf("%w: %s from %s exceeds %s",
+			errCriticalSkew,
+			skew,
+			source,
+			e.config.CriticalThreshold,
+		)
+	case absSkew >= e.config.WarnThreshold:
+		details["warning"] = fmt.Sprintf("clock skew exceeds %s", e.config.WarnThreshold)
+		e.log.Warn("local clock is skewed",
+			zap.Duration("skew", skew),
+			zap.String("source", source),
+		)
+	}
+	return details, nil
+}
+
+func (e *estimator) Shutdown() {
+	e.closed.Do(func() {
+		close(e.onClose)
+	})
+}
+
+// skew returns the skew relative to the NTP server if it's known, as it's
+// more precise than the times reported by peers.
+//
+// Assumes [e.lock] is held.
+func (e *estimator) skew() (time.Duration, string, bool) {
+	if e.ntpSkewSet {
+		return e.ntpSkew, sourceNTP, true
+	}
+	skew, ok := e.peerSkew()
+	return skew, sourcePeers, ok
+}
+
+// peerSkew returns the median skew relative to peers, after forgetting stale
+// observations.
+//
+// Assumes [e.lock] is held.
+func (e *estimator) peerSkew() (time.Duration, bool) {
+	minTime := e.clock.Time().Add(-maxObservationAge)
+	skews := make([]time.Duration, 0, len(e.observations))
+	for nodeID, observation := range e.observations {
+		if observation.time.Before(minTime) {
+			delete(e.observations, nodeID)
+			continue
+		}
+		skews = append(skews, observation.skew)
+	}
+	if len(skews) == 0 || len(skews) < e.config.MinPeers {
+		return 0, false
+	}
+
+	sort.Slice(skews, func(i, j int) bool {
+		return skews[i] < skews[j]
+	})
+	middle := len(skews) / 2
+	if len(skews)%2 == 1 {
+		return skews[middle], true
+	}
+	return (skews[middle-1] + skews[middle]) / 2, true
+}
+
+func (e *estimator) queryNTP() {
+	ticker := time.NewTicker(e.config.NTPFrequency)
+	defer ticker.Stop()
+
+	for {
+		skew, err := queryNTP(e.config.NTPServer, e.config.NTPTimeout, &e.clock)
+		e.lock.Lock()
+		// A failed query clears the skew, so that a stale skew isn't preferred
+		// to the times reported by peers.
+		e.ntpErr = err
+		e.ntpSkew = skew
+		e.ntpSkewSet = err == nil
+		e.ntpSkewMetric.Set(skew.Seconds())
+		e.lock.Unlock()
+
+		if err != nil {
+			e.log.Debug("failed to query NTP server",
+				zap.String("server", e.config.NTPServer),
+				zap.Error(err),
+			)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-e.onClose:
+			return
+		}
+	}
+}
@@ -0,0 +1,189 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package clockskew
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+var testConfig = Config{
+	WarnThreshold:     2 * time.Second,
+	CriticalThreshold: 5 * time.Second,
+	MinPeers:          3,
+}
+
+func newTestEstimator(t *testing.T, config Config) *estimator {
+	e, err := NewEstimator(logging.NoLog{}, config, "", prometheus.NewRegistry())
+	require.NoError(t, err)
+	t.Cleanup(e.Shutdown)
+	return e.(*estimator)
+}
+
+func TestEstimatorPeerSkew(t *testing.T) {
+	require := require.New(t)
+
+	e := newTestEstimator(t, testConfig)
+	now := time.Unix(1_000_000, 0)
+	e.clock.Set(now)
+
+	// Not enough peers to estimate the skew
+	e.Observe(ids.GenerateTestNodeID(), now.Add(-time.Second))
+	e.Observe(ids.GenerateTestNodeID(), now.Add(-3*time.Second))
+	_, ok := e.Skew()
+	require.False(ok)
+
+	// The local clock is ahead of the median peer
+	e.Observe(ids.GenerateTestNodeID(), now.Add(-5*time.Second))
+	skew, ok := e.Skew()
+	require.True(ok)
+	require.Equal(3*time.Second, skew)
+
+	details, err := e.HealthCheck(context.Background())
+	require.NoError(err)
+	require.Contains(details, "warning")
+
+	// Only the latest time of each peer is used
+	nodeID := ids.GenerateTestNodeID()
+	e.Observe(nodeID, now.Add(-10*time.Second))
+	e.Observe(nodeID, now.Add(-6*time.Second))
+	skew, ok = e.Skew()
+	require.True(ok)
+	require.Equal(4*time.Second, skew)
+
+	// The median is robust to outliers
+	e.Observe(ids.GenerateTestNodeID(), now.Add(time.Hour))
+	skew, ok = e.Skew()
+	require.True(ok)
+	require.Equal(3*time.Second, skew)
+
+	e.Observe(ids.GenerateTestNodeID(), now.Add(-20*time.Second))
+	e.Observe(ids.GenerateTestNodeID(), now.Add(-30*time.Second))
+	_, err = e.HealthCheck(context.Background())
+	require.ErrorIs(err, errCriticalSkew)
+
+	// Stale observations are forgotten
+	e.clock.Set(now.Add(maxObservationAge + time.Second))
+	_, ok = e.Skew()
+	require.False(ok)
+	details, err = e.HealthCheck(context.Background())
+	require.NoError(err)
+	require.Equal(0, details.(map[string]interface{})["peers"])
+}
+
+func TestConfigVerify(t *testing.T) {
+	require := require.New(t)
+
+	config := testConfig
+	require.NoError(config.Verify())
+
+	config.CriticalThreshold = time.Second
+	require.ErrorIs(config.Verify(), errCriticalBelowWarn)
+
+	config = testConfig
+	config.WarnThreshold = 0
+	require.ErrorIs(config.Verify(), errNonPositiveThreshold)
+
+	config = testConfig
+	config.NTPServer = "localhost"
+	require.ErrorIs(config.Verify(), errNonPositiveNTPConfig)
+}
+
+// serveNTP answers NTP queries as a server whose clock is [offset] ahead of
+// the local clock, until the returned connection is closed.
+func serveNTP(t *testing.T, offset time.Duration) net.PacketConn {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = conn.Close()
+	})
+
+	go func() {
+		request := make([]byte, ntpPacketLen)
+		for {
+			_, addr, err := conn.ReadFrom(request)
+			if err != nil {
+				return
+			}
+			response := make([]byte, ntpPacketLen)
+			response[0] = 4<<3 | ntpServerMode
+			response[1] = 1
+			copy(response[24:32], request[40:48])
+			now := time.Now().Add(offset)
+			putNTPTime(response[32:], now)
+			putNTPTime(response[40:], now)
+			_, _ = conn.WriteTo(response, addr)
+		}
+	}()
+	return conn
+}
+
+func TestEstimatorNTPSkew(t *testing.T) {
+	require := require.New(t)
+
+	config := testConfig
+	config.NTPServer = serveNTP(t, -time.Minute).LocalAddr().String()
+	config.NTPFrequency = time.Hour
+	config.NTPTimeout = time.Second
+	e := newTestEstimator(t, config)
+
+	require.Eventually(func() bool {
+		_, ok := e.Skew()
+		return ok
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// The local clock is a minute ahead of the NTP server
+	skew, _ := e.Skew()
+	require.InDelta(time.Minute.Seconds(), skew.Seconds(), 1)
+
+	details, err := e.HealthCheck(context.Background())
+	require.ErrorIs(err, errCriticalSkew)
+	require.Equal(sourceNTP, details.(map[string]interface{})["source"])
+}
+
+func TestEstimatorNTPErrorClearsSkew(t *testing.T) {
+	require := require.New(t)
+
+	conn := serveNTP(t, -time.Minute)
+	config := testConfig
+	config.NTPServer = conn.LocalAddr().String()
+	config.NTPFrequency = 10 * time.Millisecond
+	config.NTPTimeout = 100 * time.Millisecond
+	e := newTestEstimator(t, config)
+
+	require.Eventually(func() bool {
+		_, ok := e.Skew()
+		return ok
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// Once the NTP server stops answering, its skew is no longer used.
+	require.NoError(conn.Close())
+	require.Eventually(func() bool {
+		_, ok := e.Skew()
+		return !ok
+	}, 5*time.Second, 10*time.Millisecond)
+
+	details, err := e.HealthCheck(context.Background())
+	require.NoError(err)
+	require.NotContains(details, "ntpSkew")
+	require.Contains(details, "ntpError")
+}
+
+func TestNTPTimeEncoding(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Unix(1_700_000_000, 123_456_789)
+	b := make([]byte, 8)
+	putNTPTime(b, now)
+	require.WithinDuration(now, getNTPTime(b), time.Microsecond)
+}
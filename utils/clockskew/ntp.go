@@ -0,0 +1,102 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package clockskew
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/ava-labs/avalanchego/utils/timer/mockable"
+)
+
+const (
+	ntpPort      = "123"
+	ntpPacketLen = 48
+
+	// Seconds between the NTP epoch, 1900, and the Unix epoch, 1970
+	ntpEpochOffset = 2_208_988_800
+
+	// Leap indicator 0, version 4, client mode
+	ntpClientHeader = 0<<6 | 4<<3 | 3
+	ntpServerMode   = 4
+)
+
+var (
+	errShortNTPResponse    = errors.New("short NTP response")
+	errUnexpectedNTPMode   = errors.New("unexpected NTP mode")
+	errNTPKissOfDeath      = errors.New("NTP server refused the query")
+	errUnexpectedNTPOrigin = errors.New("NTP response doesn't match the query")
+)
+
+// QueryNTP returns the skew of the local clock relative to the NTP server at
+// [server].
+func QueryNTP(server string, timeout time.Duration) (time.Duration, error) {
+	return queryNTP(server, timeout, &mockable.Clock{})
+}
+
+// queryNTP returns the skew of [clock] relative to the NTP server at [server],
+// using the simple network time protocol (RFC 4330).
+func queryNTP(server string, timeout time.Duration, clock *mockable.Clock) (time.Duration, error) {
+	if _, _, err := net.SplitHostPort(server); err != nil {
+		server = net.JoinHostPort(server, ntpPort)
+	}
+
+	conn, err := net.DialTimeout("udp", server, timeout)
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+
+	if err := conn.SetDeadline(time.Now().Add(timeout)); err != nil {
+		return 0, err
+	}
+
+	request := make([]byte, ntpPacketLen)
+	request[0] = ntpClientHeader
+	sentTime := clock.Time()
+	putNTPTime(request[40:], sentTime)
+	if _, err := conn.Write(request); err != nil {
+		return 0, err
+	}
+
+	response := make([]byte, ntpPacketLen)
+	n, err := conn.Read(response)
+	if err != nil {
+		return 0, err
+	}
+	receivedTime := clock.Time()
+
+	switch {
+	case n < ntpPacketLen:
+		return 0, fmt.Errorf("%w: %d bytes", errShortNTPResponse, n)
+	case response[0]&0x07 != ntpServerMode:
+		return 0, fmt.Errorf("%w: %d", errUnexpectedNTPMode, response[0]&0x07)
+	case response[1] == 0:
+		return 0, errNTPKissOfDeath
+	case !bytes.Equal(response[24:32], request[40:48]):
+		return 0, errUnexpectedNTPOrigin
+	}
+
+	serverReceiveTime := getNTPTime(response[32:])
+	serverTransmitTime := getNTPTime(response[40:])
+	return (sentTime.Sub(serverReceiveTime) + receivedTime.Sub(serverTransmitTime)) / 2, nil
+}
+
+func putNTPTime(b []byte, t time.Time) {
+	seconds := uint64(t.Unix() + ntpEpochOffset)
+	fraction := (uint64(t.Nanosecond()) << 32) / uint64(time.Second)
+	binary.BigEndian.PutUint32(b, uint32(seconds))
+	binary.BigEndian.PutUint32(b[4:], uint32(fraction))
+}
+
+func getNTPTime(b []byte) time.Time {
+	seconds := int64(binary.BigEndian.Uint32(b))
+	fraction := uint64(binary.BigEndian.Uint32(b[4:]))
+	nanoseconds := (fraction * uint64(time.Second)) >> 32
+	return time.Unix(seconds-ntpEpochOffset, int64(nanoseconds))
+}
@@ -9,12 +9,17 @@ import (
 	"runtime"
 
 	blst "github.com/supranational/blst/bindings/go"
+
+	"github.com/ava-labs/avalanchego/utils/hashing"
 )
 
 const SecretKeyLen = blst.BLST_SCALAR_BYTES
 
 var (
 	errFailedSecretKeyDeserialize = errors.New("couldn't deserialize secret key")
+	errEmptySeed                  = errors.New("seed must not be empty")
+
+	seedDomainSeparator = []byte("avalanche staking signer")
 
 	// The ciphersuite is more commonly known as G2ProofOfPossession.
 	// There are two digests to ensure that that message space for normal
@@ -38,6 +43,20 @@ func NewSecretKey() (*SecretKey, error) {
 	return sk, nil
 }
 
+// NewSecretKeyFromSeed deterministically derives a secret key from [seed].
+//
+// Anyone who knows [seed] can recover the secret key, so this must only be
+// used for ephemeral test networks.
+func NewSecretKeyFromSeed(seed []byte) (*SecretKey, error) {
+	if len(seed) == 0 {
+		return nil, errEmptySeed
+	}
+	ikm := make([]byte, 0, len(seedDomainSeparator)+len(seed))
+	ikm = append(ikm, seedDomainSeparator...)
+	ikm = append(ikm, seed...)
+	return blst.KeyGen(hashing.ComputeHash256(ikm)), nil
+}
+
 // SecretKeyToBytes returns the big-endian format of the secret key.
 func SecretKeyToBytes(sk *SecretKey) []byte {
 	return sk.Serialize()
@@ -47,3 +47,20 @@ func TestSecretKeyBytes(t *testing.T) {
 	require.Equal(skBytes, sk2Bytes)
 	require.Equal(sig, sig2)
 }
+
+func TestNewSecretKeyFromSeed(t *testing.T) {
+	require := require.New(t)
+
+	sk0, err := NewSecretKeyFromSeed([]byte("seed"))
+	require.NoError(err)
+	sk1, err := NewSecretKeyFromSeed([]byte("seed"))
+	require.NoError(err)
+	sk2, err := NewSecretKeyFromSeed([]byte("other seed"))
+	require.NoError(err)
+
+	require.Equal(SecretKeyToBytes(sk0), SecretKeyToBytes(sk1))
+	require.NotEqual(SecretKeyToBytes(sk0), SecretKeyToBytes(sk2))
+
+	_, err = NewSecretKeyFromSeed(nil)
+	require.ErrorIs(err, errEmptySeed)
+}
@@ -0,0 +1,32 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package gc
+
+import (
+	"runtime/debug"
+)
+
+// Apply applies [config] to the current process.
+func Apply(config Config) error {
+	switch {
+	case config.GCPercent < 0:
+		debug.SetGCPercent(-1)
+	case config.GCPercent > 0:
+		debug.SetGCPercent(config.GCPercent)
+	}
+	if config.MemoryLimit > 0 {
+		return setMemoryLimit(config.MemoryLimit)
+	}
+	return nil
+}
+
+// NewBallast returns an allocation of [size] bytes that the garbage collector
+// counts towards the live heap.
+//
+// As long as the ballast is referenced, the heap size that triggers a
+// collection is raised by roughly [size] * GOGC / 100 without the ballast's
+// pages being touched, and therefore without consuming physical memory.
+func NewBallast(size uint64) []byte {
+	return make([]byte, size)
+}
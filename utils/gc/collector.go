@@ -0,0 +1,45 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package gc
+
+import (
+	"runtime/debug"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var _ prometheus.Collector = (*collector)(nil)
+
+type collector struct {
+	lastPause *prometheus.Desc
+}
+
+// NewCollector returns a collector that reports the duration of the most
+// recent garbage collection pause of the current process.
+func NewCollector() prometheus.Collector {
+	return &collector{
+		lastPause: prometheus.NewDesc(
+			"gc_last_pause_seconds",
+			"duration of the most recent garbage collection pause",
+			nil,
+			nil,
+		),
+	}
+}
+
+func (c *collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.lastPause
+}
+
+func (c *collector) Collect(ch chan<- prometheus.Metric) {
+	var stats debug.GCStats
+	debug.ReadGCStats(&stats)
+
+	// Pauses are ordered from most recent to least recent.
+	var lastPause float64
+	if len(stats.Pause) > 0 {
+		lastPause = stats.Pause[0].Seconds()
+	}
+	ch <- prometheus.MustNewConstMetric(c.lastPause, prometheus.GaugeValue, lastPause)
+}
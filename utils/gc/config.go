@@ -0,0 +1,70 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package gc
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+var errGCOffWithoutMemoryLimit = errors.New("garbage collection can only be turned off if a memory limit is set")
+
+// Config tunes the garbage collector of a Go process.
+type Config struct {
+	// GCPercent is the garbage collection target percentage, as in GOGC. If 0,
+	// the runtime's default is used. If negative, the garbage collector only
+	// runs when the memory limit is reached, so [MemoryLimit] must be set.
+	GCPercent int `json:"gcPercent"`
+
+	// MemoryLimit is the soft memory limit of the process in bytes, as in
+	// GOMEMLIMIT. If 0, the memory limit isn't set.
+	MemoryLimit uint64 `json:"memoryLimit"`
+}
+
+// Verify returns an error if the garbage collector would never run, which
+// lets the heap grow until the process runs out of memory.
+func (c Config) Verify() error {
+	if c.GCPercent < 0 && c.MemoryLimit == 0 {
+		return errGCOffWithoutMemoryLimit
+	}
+	return nil
+}
+
+// Env returns the environment variables that apply this config to a Go
+// process when it is launched.
+func (c Config) Env() []string {
+	var env []string
+	switch {
+	case c.GCPercent < 0:
+		env = append(env, "GOGC=off")
+	case c.GCPercent > 0:
+		env = append(env, "GOGC="+strconv.Itoa(c.GCPercent))
+	}
+	if c.MemoryLimit > 0 {
+		env = append(env, "GOMEMLIMIT="+strconv.FormatUint(c.MemoryLimit, 10))
+	}
+	return env
+}
+
+// ChainConfigs maps the ID or an alias of a chain to the config applied to the
+// process running the chain's VM.
+type ChainConfigs map[string]Config
+
+// Get returns the config of the chain with ID [chainID] and aliases
+// [aliases]. The config registered for the chain's ID takes precedence over
+// the configs registered for its aliases. Returns false if no config was
+// registered for the chain.
+func (c ChainConfigs) Get(chainID ids.ID, aliases []string) (Config, bool) {
+	if config, ok := c[chainID.String()]; ok {
+		return config, true
+	}
+	for _, alias := range aliases {
+		if config, ok := c[alias]; ok {
+			return config, true
+		}
+	}
+	return Config{}, false
+}
@@ -0,0 +1,72 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package gc
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+func TestConfigEnv(t *testing.T) {
+	require := require.New(t)
+
+	require.Empty(Config{}.Env())
+	require.Equal([]string{"GOGC=50"}, Config{GCPercent: 50}.Env())
+	require.Equal(
+		[]string{"GOGC=off", "GOMEMLIMIT=1073741824"},
+		Config{GCPercent: -1, MemoryLimit: 1024 * 1024 * 1024}.Env(),
+	)
+}
+
+func TestConfigVerify(t *testing.T) {
+	require := require.New(t)
+
+	require.NoError(Config{}.Verify())
+	require.NoError(Config{GCPercent: 50}.Verify())
+	require.NoError(Config{GCPercent: -1, MemoryLimit: 1024}.Verify())
+	require.ErrorIs(Config{GCPercent: -1}.Verify(), errGCOffWithoutMemoryLimit)
+}
+
+func TestChainConfigsGet(t *testing.T) {
+	require := require.New(t)
+
+	chainID := ids.GenerateTestID()
+	configs := ChainConfigs{
+		"X":              {GCPercent: 1},
+		chainID.String(): {GCPercent: 2},
+	}
+
+	config, ok := configs.Get(chainID, []string{"X"})
+	require.True(ok)
+	require.Equal(2, config.GCPercent)
+
+	config, ok = configs.Get(ids.GenerateTestID(), []string{"avm", "X"})
+	require.True(ok)
+	require.Equal(1, config.GCPercent)
+
+	_, ok = configs.Get(ids.GenerateTestID(), nil)
+	require.False(ok)
+}
+
+func TestCollector(t *testing.T) {
+	require := require.New(t)
+
+	registry := prometheus.NewRegistry()
+	require.NoError(registry.Register(NewCollector()))
+
+	runtime.GC()
+
+	families, err := registry.Gather()
+	require.NoError(err)
+	require.Len(families, 1)
+	require.Equal("gc_last_pause_seconds", families[0].GetName())
+	require.Len(families[0].Metric, 1)
+	require.Positive(families[0].Metric[0].GetGauge().GetValue())
+}
@@ -0,0 +1,20 @@
+//go:build go1.19
+// +build go1.19
+
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package gc
+
+import (
+	"math"
+	"runtime/debug"
+)
+
+func setMemoryLimit(limit uint64) error {
+	if limit > math.MaxInt64 {
+		limit = math.MaxInt64
+	}
+	debug.SetMemoryLimit(int64(limit))
+	return nil
+}
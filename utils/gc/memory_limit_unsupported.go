@@ -0,0 +1,17 @@
+//go:build !go1.19
+// +build !go1.19
+
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package gc
+
+import (
+	"errors"
+)
+
+var errMemoryLimitUnsupported = errors.New("setting a memory limit requires go1.19 or later")
+
+func setMemoryLimit(uint64) error {
+	return errMemoryLimitUnsupported
+}
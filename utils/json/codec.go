@@ -20,6 +20,14 @@ const (
 	Null = "null"
 )
 
+// FinalReply is implemented by the replies of methods whose results may still
+// change, such as the result of fetching a processing block. The responses
+// with replies that aren't final are marked as uncacheable.
+type FinalReply interface {
+	// IsFinal returns true if the reply never changes once it's returned.
+	IsFinal() bool
+}
+
 var (
 	errUppercaseMethod = errors.New("method must start with a non-uppercase letter")
 	errInvalidArg      = errors.New("couldn't unmarshal an argument. Ensure arguments are valid and properly formatted. See documentation for example calls")
@@ -34,10 +42,18 @@ func NewCodec() rpc.Codec {
 type lowercase struct{ *json2.Codec }
 
 func (lc lowercase) NewRequest(r *http.Request) rpc.CodecRequest {
-	return &request{lc.Codec.NewRequest(r).(*json2.CodecRequest)}
+	return &request{
+		CodecRequest: lc.Codec.NewRequest(r).(*json2.CodecRequest),
+		fields:       parseFieldMask(r.URL.Query().Get(FieldsParam)),
+	}
 }
 
-type request struct{ *json2.CodecRequest }
+type request struct {
+	*json2.CodecRequest
+
+	// If non-nil, only these fields of the result are returned
+	fields fieldMask
+}
 
 func (r *request) Method() (string, error) {
 	method, err := r.CodecRequest.Method()
@@ -63,3 +79,20 @@ func (r *request) ReadRequest(args interface{}) error {
 	}
 	return nil
 }
+
+func (r *request) WriteResponse(w http.ResponseWriter, reply interface{}) {
+	if reply, ok := reply.(FinalReply); ok && !reply.IsFinal() {
+		w.Header().Set("Cache-Control", "no-store")
+	}
+	if r.fields == nil {
+		r.CodecRequest.WriteResponse(w, reply)
+		return
+	}
+
+	masked, err := r.fields.apply(reply)
+	if err != nil {
+		r.CodecRequest.WriteError(w, http.StatusInternalServerError, err)
+		return
+	}
+	r.CodecRequest.WriteResponse(w, masked)
+}
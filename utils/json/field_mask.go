@@ -0,0 +1,250 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package json
+
+import (
+	"encoding"
+	stdjson "encoding/json"
+	"reflect"
+	"strings"
+)
+
+// FieldsParam is the query parameter that restricts the result of a call to
+// the given fields. Fields are separated by commas, and nested fields are
+// separated from their parent by dots. For example, ?fields=validators.nodeID
+// only returns the node ID of each validator. A field mask applies to each
+// element of an array.
+const FieldsParam = "fields"
+
+var (
+	marshalerType     = reflect.TypeOf((*stdjson.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// fieldMask is a set of fields, each with the mask of its nested fields. A
+// field with an empty mask is returned whole.
+type fieldMask map[string]fieldMask
+
+// parseFieldMask parses the value of the fields query parameter. It returns
+// nil if no field is given.
+func parseFieldMask(fields string) fieldMask {
+	var mask fieldMask
+	for _, path := range strings.Split(fields, ",") {
+		current := mask
+		for _, name := range strings.Split(strings.TrimSpace(path), ".") {
+			if name == "" {
+				continue
+			}
+			if mask == nil {
+				mask = fieldMask{}
+				current = mask
+			}
+			next, ok := current[name]
+			if !ok {
+				next = fieldMask{}
+				current[name] = next
+			}
+			current = next
+		}
+	}
+	return mask
+}
+
+// apply the mask to [value], which is then only serialized in part. Values
+// that serialize themselves are serialized whole before being masked.
+func (m fieldMask) apply(value interface{}) (interface{}, error) {
+	return m.applyValue(reflect.ValueOf(value))
+}
+
+func (m fieldMask) applyValue(v reflect.Value) (interface{}, error) {
+	if !v.IsValid() {
+		return nil, nil
+	}
+	if len(m) == 0 {
+		return v.Interface(), nil
+	}
+	if marshals(v) {
+		return m.applyMarshaled(v)
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil, nil
+		}
+		return m.applyValue(v.Elem())
+	case reflect.Struct:
+		result := make(map[string]interface{}, len(m))
+		if err := m.applyStruct(v, result); err != nil {
+			return nil, err
+		}
+		return result, nil
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return m.applyMarshaled(v)
+		}
+		result := make(map[string]interface{}, len(m))
+		for name, mask := range m {
+			fieldValue := v.MapIndex(reflect.ValueOf(name).Convert(v.Type().Key()))
+			if !fieldValue.IsValid() {
+				continue
+			}
+			masked, err := mask.applyValue(fieldValue)
+			if err != nil {
+				return nil, err
+			}
+			result[name] = masked
+		}
+		return result, nil
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return nil, nil
+		}
+		result := make([]interface{}, v.Len())
+		for i := range result {
+			masked, err := m.applyValue(v.Index(i))
+			if err != nil {
+				return nil, err
+			}
+			result[i] = masked
+		}
+		return result, nil
+	default:
+		// Scalars don't have fields to select.
+		return v.Interface(), nil
+	}
+}
+
+// applyStruct adds the masked fields of the struct [v] to [result]. The fields
+// of embedded structs are added as if they were fields of [v], as they are
+// when serialized.
+func (m fieldMask) applyStruct(v reflect.Value, result map[string]interface{}) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, omitEmpty, skip := jsonFieldName(field)
+		if skip {
+			continue
+		}
+
+		fieldValue := v.Field(i)
+		if field.Anonymous && name == "" {
+			if fieldValue.Kind() == reflect.Ptr {
+				if fieldValue.IsNil() {
+					// Nil embedded structs aren't serialized.
+					continue
+				}
+				fieldValue = fieldValue.Elem()
+			}
+			if fieldValue.Kind() == reflect.Struct && !marshals(fieldValue) {
+				if err := m.applyStruct(fieldValue, result); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		mask, ok := m[name]
+		if !ok || (omitEmpty && isEmptyValue(fieldValue)) {
+			continue
+		}
+		masked, err := mask.applyValue(fieldValue)
+		if err != nil {
+			return err
+		}
+		result[name] = masked
+	}
+	return nil
+}
+
+// applyMarshaled serializes [v] before masking it.
+func (m fieldMask) applyMarshaled(v reflect.Value) (interface{}, error) {
+	b, err := stdjson.Marshal(v.Interface())
+	if err != nil {
+		return nil, err
+	}
+	var decoded interface{}
+	if err := stdjson.Unmarshal(b, &decoded); err != nil {
+		return nil, err
+	}
+	return m.applyDecoded(decoded), nil
+}
+
+func (m fieldMask) applyDecoded(value interface{}) interface{} {
+	if len(m) == 0 {
+		return value
+	}
+	switch value := value.(type) {
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(m))
+		for name, mask := range m {
+			if fieldValue, ok := value[name]; ok {
+				result[name] = mask.applyDecoded(fieldValue)
+			}
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(value))
+		for i, element := range value {
+			result[i] = m.applyDecoded(element)
+		}
+		return result
+	default:
+		return value
+	}
+}
+
+// marshals returns whether [v] serializes itself.
+func marshals(v reflect.Value) bool {
+	for _, t := range []reflect.Type{marshalerType, textMarshalerType} {
+		if v.Type().Implements(t) || (v.CanAddr() && v.Addr().Type().Implements(t)) {
+			return true
+		}
+	}
+	return false
+}
+
+// jsonFieldName returns the name [field] is serialized as, or an empty string
+// if it isn't renamed, whether it's omitted when empty, and whether it isn't
+// serialized.
+func jsonFieldName(field reflect.StructField) (string, bool, bool) {
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", false, true
+	}
+	options := strings.Split(tag, ",")
+	for _, option := range options[1:] {
+		if option == "omitempty" {
+			return options[0], true, false
+		}
+	}
+	return options[0], false, false
+}
+
+// isEmptyValue returns whether [v] is omitted when serialized by a field
+// tagged with omitempty.
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Interface, reflect.Ptr:
+		return v.IsNil()
+	default:
+		return false
+	}
+}
@@ -0,0 +1,124 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package json
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	stdjson "encoding/json"
+
+	"github.com/gorilla/rpc/v2"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+type testStaker struct {
+	NodeID ids.NodeID `json:"nodeID"`
+	Weight Uint64     `json:"weight"`
+}
+
+type testValidator struct {
+	testStaker
+	Delegators []testStaker `json:"delegators"`
+	Uptime     *Float32     `json:"uptime,omitempty"`
+	internal   int
+}
+
+type testReply struct {
+	Validators []testValidator    `json:"validators"`
+	Heights    map[string]int     `json:"heights"`
+	Ignored    string             `json:"-"`
+	Raw        stdjson.RawMessage `json:"raw"`
+}
+
+func TestParseFieldMask(t *testing.T) {
+	require := require.New(t)
+
+	require.Nil(parseFieldMask(""))
+	require.Nil(parseFieldMask(" , ."))
+	require.Equal(fieldMask{
+		"validators": {
+			"nodeID":     {},
+			"delegators": {"weight": {}},
+		},
+		"heights": {},
+	}, parseFieldMask("validators.nodeID, validators.delegators.weight,heights"))
+}
+
+func TestFieldMaskApply(t *testing.T) {
+	require := require.New(t)
+
+	nodeID := ids.GenerateTestNodeID()
+	uptime := Float32(.5)
+	reply := &testReply{
+		Validators: []testValidator{{
+			testStaker: testStaker{
+				NodeID: nodeID,
+				Weight: 2,
+			},
+			Delegators: []testStaker{{Weight: 1}},
+			Uptime:     &uptime,
+			internal:   1,
+		}, {
+			Delegators: []testStaker{},
+		}},
+		Heights: map[string]int{"a": 1, "b": 2},
+		Ignored: "ignored",
+		Raw:     stdjson.RawMessage(`{"a":1,"b":2}`),
+	}
+
+	tests := []struct {
+		fields   string
+		expected string
+	}{
+		{
+			fields:   "validators.nodeID,validators.delegators.weight",
+			expected: `{"validators":[{"nodeID":"` + nodeID.String() + `","delegators":[{"weight":"1"}]},{"nodeID":"` + ids.EmptyNodeID.String() + `","delegators":[]}]}`,
+		},
+		{
+			fields:   "validators.uptime,heights.b,unknown",
+			expected: `{"validators":[{"uptime":"0.5000"},{}],"heights":{"b":2}}`,
+		},
+		{
+			fields:   "raw.a,Ignored,validators.internal",
+			expected: `{"raw":{"a":1},"validators":[{},{}]}`,
+		},
+	}
+	for _, test := range tests {
+		masked, err := parseFieldMask(test.fields).apply(reply)
+		require.NoError(err)
+		maskedBytes, err := stdjson.Marshal(masked)
+		require.NoError(err)
+		require.JSONEq(test.expected, string(maskedBytes), test.fields)
+	}
+}
+
+type testService struct{}
+
+func (*testService) GetHeights(_ *http.Request, _ *struct{}, reply *map[string]int) error {
+	*reply = map[string]int{"a": 1, "b": 2}
+	return nil
+}
+
+func TestCodecFieldMask(t *testing.T) {
+	require := require.New(t)
+
+	server := rpc.NewServer()
+	server.RegisterCodec(NewCodec(), "application/json")
+	require.NoError(server.RegisterService(&testService{}, "test"))
+
+	body := []byte(`{"jsonrpc":"2.0","method":"test.getHeights","params":{},"id":1}`)
+	req := httptest.NewRequest(http.MethodPost, "/?"+FieldsParam+"=a", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+
+	require.Equal(http.StatusOK, w.Code)
+	require.JSONEq(`{"jsonrpc":"2.0","result":{"a":1},"id":1}`, w.Body.String())
+}
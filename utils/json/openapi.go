@@ -0,0 +1,127 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package json
+
+import (
+	"net/http"
+	"reflect"
+	"sort"
+	"unicode"
+	"unicode/utf8"
+
+	stdjson "encoding/json"
+)
+
+var (
+	typeOfRequest = reflect.TypeOf((*http.Request)(nil))
+	typeOfError   = reflect.TypeOf((*error)(nil)).Elem()
+)
+
+// OpenAPIPathItem returns the OpenAPI path item object, encoded as JSON, of a
+// JSON-RPC endpoint that serves the methods of [receiver] as [service]. The
+// methods are named as the codec returned by NewCodec expects them.
+func OpenAPIPathItem(summary, service string, receiver interface{}) []byte {
+	return PaginatedOpenAPIPathItem(summary, service, receiver, nil)
+}
+
+// PaginatedOpenAPIPathItem returns the path item returned by OpenAPIPathItem.
+// The maximum number of items returned per page by each of the paginated
+// methods in [maxPageSizes] is advertised as the "x-max-page-sizes" extension
+// of the operation.
+func PaginatedOpenAPIPathItem(summary, service string, receiver interface{}, maxPageSizes map[string]uint64) []byte {
+	methods := rpcMethods(service, receiver)
+	operation := map[string]interface{}{
+		"summary": summary,
+		"requestBody": map[string]interface{}{
+			"required": true,
+			"content": map[string]interface{}{
+				"application/json": map[string]interface{}{
+					"schema": map[string]interface{}{
+						"type":     "object",
+						"required": []string{"jsonrpc", "method"},
+						"properties": map[string]interface{}{
+							"jsonrpc": map[string]interface{}{
+								"type": "string",
+								"enum": []string{"2.0"},
+							},
+							"id": map[string]interface{}{},
+							"method": map[string]interface{}{
+								"type": "string",
+								"enum": methods,
+							},
+							"params": map[string]interface{}{
+								"type": "object",
+							},
+						},
+					},
+				},
+			},
+		},
+		"responses": map[string]interface{}{
+			"200": map[string]interface{}{
+				"description": "The JSON-RPC response",
+				"content": map[string]interface{}{
+					"application/json": map[string]interface{}{},
+				},
+			},
+		},
+	}
+	if len(maxPageSizes) > 0 {
+		operation["x-max-page-sizes"] = maxPageSizes
+	}
+	pathItem := map[string]interface{}{
+		"summary": summary,
+		"post":    operation,
+	}
+	// The path item only contains values that can be marshalled.
+	b, _ := stdjson.Marshal(pathItem)
+	return b
+}
+
+// rpcMethods returns the sorted names of the JSON-RPC methods of [receiver]
+// when it's registered as [service] to an rpc server that uses the codec
+// returned by NewCodec.
+func rpcMethods(service string, receiver interface{}) []string {
+	receiverType := reflect.TypeOf(receiver)
+	methods := []string{}
+	for i := 0; i < receiverType.NumMethod(); i++ {
+		method := receiverType.Method(i)
+		if !isRPCMethod(method) {
+			continue
+		}
+		firstRune, runeLen := utf8.DecodeRuneInString(method.Name)
+		methods = append(methods, service+"."+string(unicode.ToLower(firstRune))+method.Name[runeLen:])
+	}
+	sort.Strings(methods)
+	return methods
+}
+
+// isRPCMethod returns true if [method] is registered by an rpc server, which
+// requires it to be of the form:
+//
+//	func (*T) Method(*http.Request, *Args, *Reply) error
+func isRPCMethod(method reflect.Method) bool {
+	methodType := method.Type
+	if method.PkgPath != "" || methodType.NumIn() != 4 || methodType.NumOut() != 1 {
+		return false
+	}
+	if methodType.In(1) != typeOfRequest || methodType.Out(0) != typeOfError {
+		return false
+	}
+	for _, argType := range []reflect.Type{methodType.In(2), methodType.In(3)} {
+		if argType.Kind() != reflect.Ptr {
+			return false
+		}
+		elemType := argType.Elem()
+		if elemType.PkgPath() != "" && !isExported(elemType.Name()) {
+			return false
+		}
+	}
+	return true
+}
+
+func isExported(name string) bool {
+	firstRune, _ := utf8.DecodeRuneInString(name)
+	return unicode.IsUpper(firstRune)
+}
@@ -0,0 +1,89 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package json
+
+import (
+	"net/http"
+	"testing"
+
+	stdjson "encoding/json"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testRPCArgs struct{}
+
+type TestRPCReply struct{}
+
+type testRPCService struct{}
+
+func (*testRPCService) GetValue(*http.Request, *struct{}, *TestRPCReply) error {
+	return nil
+}
+
+func (*testRPCService) URLs(*http.Request, *struct{}, *TestRPCReply) error {
+	return nil
+}
+
+// Unexported argument types aren't registered
+func (*testRPCService) Unexported(*http.Request, *testRPCArgs, *TestRPCReply) error {
+	return nil
+}
+
+// Methods of other forms aren't registered
+func (*testRPCService) Close() error {
+	return nil
+}
+
+func TestOpenAPIPathItem(t *testing.T) {
+	require := require.New(t)
+
+	require.Equal(
+		[]string{"test.getValue", "test.uRLs"},
+		rpcMethods("test", &testRPCService{}),
+	)
+
+	pathItem := struct {
+		Summary string `json:"summary"`
+		Post    struct {
+			RequestBody struct {
+				Content map[string]struct {
+					Schema struct {
+						Properties struct {
+							Method struct {
+								Enum []string `json:"enum"`
+							} `json:"method"`
+						} `json:"properties"`
+					} `json:"schema"`
+				} `json:"content"`
+			} `json:"requestBody"`
+		} `json:"post"`
+	}{}
+	require.NoError(stdjson.Unmarshal(OpenAPIPathItem("Test API", "test", &testRPCService{}), &pathItem))
+	require.Equal("Test API", pathItem.Summary)
+	require.Equal(
+		[]string{"test.getValue", "test.uRLs"},
+		pathItem.Post.RequestBody.Content["application/json"].Schema.Properties.Method.Enum,
+	)
+}
+
+func TestPaginatedOpenAPIPathItem(t *testing.T) {
+	require := require.New(t)
+
+	pathItem := struct {
+		Post struct {
+			MaxPageSizes map[string]uint64 `json:"x-max-page-sizes"`
+		} `json:"post"`
+	}{}
+	maxPageSizes := map[string]uint64{"test.getValue": 10}
+	require.NoError(stdjson.Unmarshal(PaginatedOpenAPIPathItem("Test API", "test", &testRPCService{}, maxPageSizes), &pathItem))
+	require.Equal(maxPageSizes, pathItem.Post.MaxPageSizes)
+
+	// Endpoints without paginated methods don't advertise page sizes.
+	fields := struct {
+		Post map[string]stdjson.RawMessage `json:"post"`
+	}{}
+	require.NoError(stdjson.Unmarshal(OpenAPIPathItem("Test API", "test", &testRPCService{}), &fields))
+	require.NotContains(fields.Post, "x-max-page-sizes")
+}
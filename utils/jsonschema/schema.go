@@ -0,0 +1,369 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package jsonschema validates JSON documents against a subset of JSON Schema.
+//
+// The supported keywords are type, enum, properties, required,
+// additionalProperties, items, minItems, maxItems, minimum, maximum,
+// exclusiveMinimum, exclusiveMaximum, minLength, maxLength and pattern.
+// Annotations, such as title and description, are allowed but have no effect.
+// Schemas that use any other keyword are rejected, so that a document is never
+// accepted because a constraint of its schema was ignored.
+package jsonschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+var (
+	ErrInvalidDocument = errors.New("document doesn't match schema")
+
+	errInvalidType                 = errors.New("invalid type")
+	errInvalidAdditionalProperties = errors.New("additionalProperties must be a boolean or a schema")
+	errUnsupportedKeyword          = errors.New("unsupported keyword")
+
+	// keywords that are either supported or annotations
+	knownKeywords = map[string]struct{}{
+		"type":                 {},
+		"enum":                 {},
+		"properties":           {},
+		"required":             {},
+		"additionalProperties": {},
+		"items":                {},
+		"minItems":             {},
+		"maxItems":             {},
+		"minimum":              {},
+		"maximum":              {},
+		"exclusiveMinimum":     {},
+		"exclusiveMaximum":     {},
+		"minLength":            {},
+		"maxLength":            {},
+		"pattern":              {},
+
+		"$schema":     {},
+		"$id":         {},
+		"$comment":    {},
+		"title":       {},
+		"description": {},
+		"default":     {},
+		"examples":    {},
+		"deprecated":  {},
+		"readOnly":    {},
+		"writeOnly":   {},
+	}
+)
+
+// Schema is a parsed JSON schema.
+type Schema struct {
+	Type                 types              `json:"type"`
+	Enum                 []json.RawMessage  `json:"enum"`
+	Properties           map[string]*Schema `json:"properties"`
+	Required             []string           `json:"required"`
+	AdditionalProperties *additional        `json:"additionalProperties"`
+	Items                *Schema            `json:"items"`
+	MinItems             *int               `json:"minItems"`
+	MaxItems             *int               `json:"maxItems"`
+	Minimum              *float64           `json:"minimum"`
+	Maximum              *float64           `json:"maximum"`
+	ExclusiveMinimum     *float64           `json:"exclusiveMinimum"`
+	ExclusiveMaximum     *float64           `json:"exclusiveMaximum"`
+	MinLength            *int               `json:"minLength"`
+	MaxLength            *int               `json:"maxLength"`
+	Pattern              string             `json:"pattern"`
+
+	pattern *regexp.Regexp
+	enum    []interface{}
+}
+
+// schemaFields has the fields of Schema, without its unmarshaller.
+type schemaFields Schema
+
+func (s *Schema) UnmarshalJSON(b []byte) error {
+	var keywords map[string]json.RawMessage
+	if err := json.Unmarshal(b, &keywords); err != nil {
+		return err
+	}
+	unknown := []string{}
+	for keyword := range keywords {
+		if _, ok := knownKeywords[keyword]; !ok {
+			unknown = append(unknown, keyword)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return fmt.Errorf("%w: %s", errUnsupportedKeyword, strings.Join(unknown, ", "))
+	}
+	return json.Unmarshal(b, (*schemaFields)(s))
+}
+
+// Parse the JSON schema [schemaBytes].
+func Parse(schemaBytes []byte) (*Schema, error) {
+	s := &Schema{}
+	if err := json.Unmarshal(schemaBytes, s); err != nil {
+		return nil, fmt.Errorf("couldn't parse schema: %w", err)
+	}
+	if err := s.compile(); err != nil {
+		return nil, fmt.Errorf("couldn't parse schema: %w", err)
+	}
+	return s, nil
+}
+
+// Validate [document] against the schema. Every mismatch is reported in the
+// returned error, along with the JSON pointer of the mismatching value.
+func (s *Schema) Validate(document []byte) error {
+	value, err := decode(document)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidDocument, err)
+	}
+
+	var mismatches []string
+	s.validate("", value, &mismatches)
+	if len(mismatches) > 0 {
+		return fmt.Errorf("%w: %s", ErrInvalidDocument, strings.Join(mismatches, "; "))
+	}
+	return nil
+}
+
+func (s *Schema) compile() error {
+	for _, t := range s.Type {
+		switch t {
+		case "null", "boolean", "object", "array", "number", "integer", "string":
+		default:
+			return fmt.Errorf("%w: %q", errInvalidType, t)
+		}
+	}
+	for _, rawValue := range s.Enum {
+		value, err := decode(rawValue)
+		if err != nil {
+			return err
+		}
+		s.enum = append(s.enum, value)
+	}
+	if s.Pattern != "" {
+		pattern, err := regexp.Compile(s.Pattern)
+		if err != nil {
+			return err
+		}
+		s.pattern = pattern
+	}
+	for _, property := range s.Properties {
+		if err := property.compile(); err != nil {
+			return err
+		}
+	}
+	if s.AdditionalProperties != nil && s.AdditionalProperties.schema != nil {
+		if err := s.AdditionalProperties.schema.compile(); err != nil {
+			return err
+		}
+	}
+	if s.Items != nil {
+		return s.Items.compile()
+	}
+	return nil
+}
+
+func (s *Schema) validate(path string, value interface{}, mismatches *[]string) {
+	mismatch := func(format string, args ...interface{}) {
+		location := path
+		if location == "" {
+			location = "/"
+		}
+		*mismatches = append(*mismatches, location+": "+fmt.Sprintf(format, args...))
+	}
+
+	if len(s.Type) > 0 && !s.Type.matches(value) {
+		mismatch("expected %s, got %s", strings.Join(s.Type, " or "), typeOf(value))
+		return
+	}
+	if len(s.enum) > 0 && !s.inEnum(value) {
+		mismatch("value isn't one of the allowed values")
+	}
+
+	switch value := value.(type) {
+	case map[string]interface{}:
+		for _, name := range s.Required {
+			if _, ok := value[name]; !ok {
+				mismatch("missing required property %q", name)
+			}
+		}
+		names := make([]string, 0, len(value))
+		for name := range value {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			propertyPath := path + "/" + escape(name)
+			if property, ok := s.Properties[name]; ok {
+				property.validate(propertyPath, value[name], mismatches)
+				continue
+			}
+			switch {
+			case s.AdditionalProperties == nil:
+			case s.AdditionalProperties.schema != nil:
+				s.AdditionalProperties.schema.validate(propertyPath, value[name], mismatches)
+			case !s.AdditionalProperties.allowed:
+				mismatch("unknown property %q", name)
+			}
+		}
+	case []interface{}:
+		if s.MinItems != nil && len(value) < *s.MinItems {
+			mismatch("expected at least %d items, got %d", *s.MinItems, len(value))
+		}
+		if s.MaxItems != nil && len(value) > *s.MaxItems {
+			mismatch("expected at most %d items, got %d", *s.MaxItems, len(value))
+		}
+		if s.Items != nil {
+			for i, item := range value {
+				s.Items.validate(fmt.Sprintf("%s/%d", path, i), item, mismatches)
+			}
+		}
+	case json.Number:
+		number, err := value.Float64()
+		if err != nil {
+			mismatch("invalid number %s", value)
+			return
+		}
+		if s.Minimum != nil && number < *s.Minimum {
+			mismatch("%s is less than the minimum %v", value, *s.Minimum)
+		}
+		if s.Maximum != nil && number > *s.Maximum {
+			mismatch("%s is greater than the maximum %v", value, *s.Maximum)
+		}
+		if s.ExclusiveMinimum != nil && number <= *s.ExclusiveMinimum {
+			mismatch("%s must be greater than %v", value, *s.ExclusiveMinimum)
+		}
+		if s.ExclusiveMaximum != nil && number >= *s.ExclusiveMaximum {
+			mismatch("%s must be less than %v", value, *s.ExclusiveMaximum)
+		}
+	case string:
+		length := utf8.RuneCountInString(value)
+		if s.MinLength != nil && length < *s.MinLength {
+			mismatch("expected at least %d characters, got %d", *s.MinLength, length)
+		}
+		if s.MaxLength != nil && length > *s.MaxLength {
+			mismatch("expected at most %d characters, got %d", *s.MaxLength, length)
+		}
+		if s.pattern != nil && !s.pattern.MatchString(value) {
+			mismatch("%q doesn't match pattern %q", value, s.Pattern)
+		}
+	}
+}
+
+func (s *Schema) inEnum(value interface{}) bool {
+	for _, allowed := range s.enum {
+		if equal(allowed, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// types is the value of the type keyword, which is either a single type or a
+// list of types.
+type types []string
+
+func (t *types) UnmarshalJSON(b []byte) error {
+	var single string
+	if err := json.Unmarshal(b, &single); err == nil {
+		*t = types{single}
+		return nil
+	}
+	var list []string
+	if err := json.Unmarshal(b, &list); err != nil {
+		return fmt.Errorf("%w: %s", errInvalidType, b)
+	}
+	*t = list
+	return nil
+}
+
+func (t types) matches(value interface{}) bool {
+	valueType := typeOf(value)
+	for _, allowed := range t {
+		if allowed == valueType || (allowed == "number" && valueType == "integer") {
+			return true
+		}
+	}
+	return false
+}
+
+// additional is the value of the additionalProperties keyword, which is either
+// a boolean or a schema.
+type additional struct {
+	allowed bool
+	schema  *Schema
+}
+
+func (a *additional) UnmarshalJSON(b []byte) error {
+	if err := json.Unmarshal(b, &a.allowed); err == nil {
+		return nil
+	}
+	a.allowed = true
+	a.schema = &Schema{}
+	if err := json.Unmarshal(b, a.schema); err != nil {
+		return fmt.Errorf("%w: %s", errInvalidAdditionalProperties, err)
+	}
+	return nil
+}
+
+// decode [b], keeping numbers as json.Number so that integers can be told
+// apart from other numbers.
+func decode(b []byte) (interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	if decoder.More() {
+		return nil, errors.New("unexpected data after the JSON value")
+	}
+	return value, nil
+}
+
+func typeOf(value interface{}) string {
+	switch value := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case json.Number:
+		if number, err := value.Float64(); err == nil && number == math.Trunc(number) {
+			return "integer"
+		}
+		return "number"
+	default:
+		return "string"
+	}
+}
+
+// equal reports whether the JSON values [a] and [b] are equal. Numbers are
+// compared by value, unless they are nested in objects or arrays.
+func equal(a, b interface{}) bool {
+	aNumber, aOK := a.(json.Number)
+	bNumber, bOK := b.(json.Number)
+	if aOK && bOK {
+		aValue, aErr := aNumber.Float64()
+		bValue, bErr := bNumber.Float64()
+		return aErr == nil && bErr == nil && aValue == bValue
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// escape [name] to be used in a JSON pointer, as defined by RFC 6901.
+func escape(name string) string {
+	name = strings.ReplaceAll(name, "~", "~0")
+	return strings.ReplaceAll(name, "/", "~1")
+}
@@ -0,0 +1,117 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package jsonschema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testSchema = `{
+	"$schema": "http://json-schema.org/draft-07/schema#",
+	"type": "object",
+	"properties": {
+		"name": {"type": "string", "minLength": 1, "maxLength": 8, "pattern": "^[a-z]+$"},
+		"size": {"type": "integer", "minimum": 1, "maximum": 10},
+		"ratio": {"type": "number", "exclusiveMinimum": 0, "exclusiveMaximum": 1},
+		"mode": {"enum": ["fast", "safe", 3]},
+		"peers": {"type": "array", "items": {"type": "string"}, "maxItems": 2},
+		"limits": {"type": "object", "additionalProperties": {"type": "integer"}},
+		"optional": {"type": ["string", "null"]}
+	},
+	"required": ["name"],
+	"additionalProperties": false
+}`
+
+func TestSchema(t *testing.T) {
+	schema, err := Parse([]byte(testSchema))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		document    string
+		mismatches  []string
+		expectedErr error
+	}{
+		{
+			name:     "valid",
+			document: `{"name":"abc","size":10,"ratio":0.5,"mode":3,"peers":["a"],"limits":{"a":1},"optional":null}`,
+		},
+		{
+			name:        "not json",
+			document:    `{"name":`,
+			expectedErr: ErrInvalidDocument,
+		},
+		{
+			name:        "wrong type",
+			document:    `[]`,
+			mismatches:  []string{"/: expected object, got array"},
+			expectedErr: ErrInvalidDocument,
+		},
+		{
+			name:     "every mismatch is reported",
+			document: `{"size":1.5,"ratio":1,"mode":"slow","peers":["a",1,"c"],"limits":{"a/b":"1"},"other":true}`,
+			mismatches: []string{
+				`/: missing required property "name"`,
+				"/limits/a~1b: expected integer, got string",
+				"/mode: value isn't one of the allowed values",
+				`/: unknown property "other"`,
+				"/peers: expected at most 2 items, got 3",
+				"/peers/1: expected string, got integer",
+				"/ratio: 1 must be less than 1",
+				"/size: expected integer, got number",
+			},
+			expectedErr: ErrInvalidDocument,
+		},
+		{
+			name:     "string constraints",
+			document: `{"name":"ABCDEFGHI"}`,
+			mismatches: []string{
+				"/name: expected at most 8 characters, got 9",
+				`/name: "ABCDEFGHI" doesn't match pattern "^[a-z]+$"`,
+			},
+			expectedErr: ErrInvalidDocument,
+		},
+		{
+			name:        "number constraints",
+			document:    `{"name":"a","size":0}`,
+			mismatches:  []string{"/size: 0 is less than the minimum 1"},
+			expectedErr: ErrInvalidDocument,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require := require.New(t)
+
+			err := schema.Validate([]byte(test.document))
+			require.ErrorIs(err, test.expectedErr)
+			for _, mismatch := range test.mismatches {
+				require.Contains(err.Error(), mismatch)
+			}
+		})
+	}
+}
+
+func TestParseUnsupportedKeyword(t *testing.T) {
+	_, err := Parse([]byte(`{"type": "object", "properties": {"a": {"type": "integer", "multipleOf": 2}}}`))
+	require.ErrorIs(t, err, errUnsupportedKeyword)
+}
+
+func TestParseInvalidSchema(t *testing.T) {
+	tests := []string{
+		`{"type": "decimal"}`,
+		`{"pattern": "("}`,
+		`{"properties": {"a": {"type": 1}}}`,
+		`{"additionalProperties": 1}`,
+		`{"oneOf": [{"type": "string"}, {"type": "null"}]}`,
+		`{"properties": {"a": {"$ref": "#/definitions/a"}}}`,
+		`{"items": {"type": "string", "format": "uri"}}`,
+		`{"additionalProperties": {"const": 1}}`,
+	}
+	for _, schema := range tests {
+		_, err := Parse([]byte(schema))
+		require.Error(t, err, schema)
+	}
+}
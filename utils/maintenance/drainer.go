@@ -0,0 +1,93 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package maintenance
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/ava-labs/avalanchego/api/server"
+	"github.com/ava-labs/avalanchego/utils/timer/mockable"
+)
+
+var (
+	// Routes that are still served during maintenance, so that operators can
+	// monitor the node and cancel the maintenance.
+	drainExemptPathPrefixes = []string{
+		"/ext/admin",
+		"/ext/health",
+		"/ext/info",
+		"/ext/metrics",
+	}
+
+	_ Drainer = (*drainer)(nil)
+)
+
+// Drainer rejects the API requests received during maintenance, other than
+// the requests to the routes operators need, so that clients retry them on
+// another node instead of failing when the node is taken down.
+type Drainer interface {
+	server.Wrapper
+	Participant
+}
+
+type drainer struct {
+	clock mockable.Clock
+
+	lock sync.RWMutex
+	// Window in progress. Nil if the node isn't in maintenance.
+	window *Window
+}
+
+func NewDrainer() Drainer {
+	return &drainer{}
+}
+
+func (d *drainer) BeginMaintenance(_ context.Context, window Window) error {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	d.window = &window
+	return nil
+}
+
+func (d *drainer) EndMaintenance(context.Context) error {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	d.window = nil
+	return nil
+}
+
+func (d *drainer) WrapHandler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		d.lock.RLock()
+		window := d.window
+		d.lock.RUnlock()
+
+		if window == nil || isDrainExempt(r.URL.Path) {
+			h.ServeHTTP(w, r)
+			return
+		}
+
+		retryAfter := math.Ceil(window.End().Sub(d.clock.Time()).Seconds())
+		if retryAfter > 0 {
+			w.Header().Set("Retry-After", strconv.FormatFloat(retryAfter, 'f', 0, 64))
+		}
+		http.Error(w, errInMaintenance.Error(), http.StatusServiceUnavailable)
+	})
+}
+
+func isDrainExempt(path string) bool {
+	for _, prefix := range drainExemptPathPrefixes {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,46 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package maintenance
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDrainer(t *testing.T) {
+	require := require.New(t)
+
+	d := NewDrainer()
+	handler := d.WrapHandler(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	serve := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, path, nil))
+		return w
+	}
+
+	require.Equal(http.StatusOK, serve("/ext/bc/C/rpc").Code)
+
+	start := time.Unix(1_000_000, 0)
+	d.(*drainer).clock.Set(start)
+	require.NoError(d.BeginMaintenance(context.Background(), Window{
+		Start:    start,
+		Duration: 90 * time.Second,
+	}))
+
+	w := serve("/ext/bc/C/rpc")
+	require.Equal(http.StatusServiceUnavailable, w.Code)
+	require.Equal("90", w.Header().Get("Retry-After"))
+	require.Equal(http.StatusOK, serve("/ext/health").Code)
+	require.Equal(http.StatusOK, serve("/ext/admin").Code)
+
+	require.NoError(d.EndMaintenance(context.Background()))
+	require.Equal(http.StatusOK, serve("/ext/bc/C/rpc").Code)
+}
@@ -0,0 +1,310 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package maintenance schedules the windows during which the node is expected
+// to be taken down for planned maintenance, and prepares the node for them so
+// that planned restarts cause as few errors as possible.
+package maintenance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/ava-labs/avalanchego/api/health"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/timer/mockable"
+)
+
+var (
+	errInMaintenance = errors.New("node is in a maintenance window")
+	errWindowOver    = errors.New("maintenance window is already over")
+	errUnknownWindow = errors.New("unknown maintenance window")
+
+	_ Scheduler = (*scheduler)(nil)
+)
+
+// Participant prepares a part of the node, such as its API server or its
+// databases, for maintenance.
+type Participant interface {
+	// BeginMaintenance is called when [window] begins.
+	BeginMaintenance(ctx context.Context, window Window) error
+	// EndMaintenance is called when the window that began ends or is
+	// cancelled.
+	EndMaintenance(ctx context.Context) error
+}
+
+// Announcer reports the maintenance window to announce to peers.
+type Announcer interface {
+	// Announced returns the window that is in progress or that begins within
+	// the announcement lead time, if any.
+	Announced() (Window, bool)
+}
+
+// Scheduler begins and ends the maintenance windows of the node.
+//
+// While a window is in progress, the node is reported as unhealthy so that
+// load balancers stop sending it requests.
+type Scheduler interface {
+	health.Checker
+	Announcer
+
+	// Register adds a participant that is notified when windows begin and
+	// end. Must be called before Dispatch.
+	Register(name string, participant Participant)
+
+	// Schedule adds [window]. The window must not overlap a scheduled window.
+	Schedule(window Window) error
+	// Cancel removes the window that starts at [start]. If the window is in
+	// progress, it ends.
+	Cancel(start time.Time) error
+	// Windows returns the windows that aren't over, by start time.
+	Windows() []Window
+
+	// Dispatch begins and ends the windows until Shutdown is called.
+	Dispatch()
+	Shutdown()
+}
+
+type namedParticipant struct {
+	name        string
+	participant Participant
+}
+
+type scheduler struct {
+	log            logging.Logger
+	announceBefore time.Duration
+	clock          mockable.Clock
+
+	lock sync.Mutex
+	// Windows that aren't over, by start time
+	windows []Window
+	// Window whose participants were notified that it began. Nil if no
+	// window is in progress.
+	active       *Window
+	participants []namedParticipant
+
+	// Signaled when the windows change
+	wakeup    chan struct{}
+	closer    chan struct{}
+	closeOnce sync.Once
+}
+
+func NewScheduler(log logging.Logger, config Config) (Scheduler, error) {
+	if err := config.Verify(); err != nil {
+		return nil, err
+	}
+	windows := make([]Window, len(config.Windows))
+	copy(windows, config.Windows)
+	sortWindows(windows)
+	return &scheduler{
+		log:            log,
+		announceBefore: config.AnnounceBefore,
+		windows:        windows,
+		wakeup:         make(chan struct{}, 1),
+		closer:         make(chan struct{}),
+	}, nil
+}
+
+func (s *scheduler) Register(name string, participant Participant) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.participants = append(s.participants, namedParticipant{
+		name:        name,
+		participant: participant,
+	})
+}
+
+func (s *scheduler) Schedule(window Window) error {
+	if err := window.Verify(); err != nil {
+		return err
+	}
+	if !s.clock.Time().Before(window.End()) {
+		return errWindowOver
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for _, scheduled := range s.windows {
+		if scheduled.overlaps(window) {
+			return fmt.Errorf("%w: %s", errOverlappingWindows, scheduled.Start)
+		}
+	}
+	s.windows = append(s.windows, window)
+	sortWindows(s.windows)
+	s.notify()
+	return nil
+}
+
+func (s *scheduler) Cancel(start time.Time) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for i, window := range s.windows {
+		if window.Start.Equal(start) {
+			s.windows = append(s.windows[:i], s.windows[i+1:]...)
+			s.notify()
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s", errUnknownWindow, start)
+}
+
+func (s *scheduler) Windows() []Window {
+	now := s.clock.Time()
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	windows := make([]Window, 0, len(s.windows))
+	for _, window := range s.windows {
+		if now.Before(window.End()) {
+			windows = append(windows, window)
+		}
+	}
+	return windows
+}
+
+func (s *scheduler) Announced() (Window, bool) {
+	now := s.clock.Time()
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for _, window := range s.windows {
+		if now.Before(window.End()) && !now.Add(s.announceBefore).Before(window.Start) {
+			return window, true
+		}
+	}
+	return Window{}, false
+}
+
+func (s *scheduler) HealthCheck(context.Context) (interface{}, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	details := map[string]interface{}{
+		"inMaintenance": s.active != nil,
+	}
+	if s.active == nil {
+		return details, nil
+	}
+	details["window"] = *s.active
+	return details, errInMaintenance
+}
+
+func (s *scheduler) Dispatch() {
+	timer := time.NewTimer(s.update())
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-timer.C:
+		case <-s.wakeup:
+			if !timer.Stop() {
+				<-timer.C
+			}
+		case <-s.closer:
+			return
+		}
+		timer.Reset(s.update())
+	}
+}
+
+func (s *scheduler) Shutdown() {
+	s.closeOnce.Do(func() {
+		close(s.closer)
+	})
+}
+
+// notify wakes up Dispatch to account for a change of the windows.
+//
+// Assumes [s.lock] is held.
+func (s *scheduler) notify() {
+	select {
+	case s.wakeup <- struct{}{}:
+	default:
+	}
+}
+
+// update begins or ends the window in progress, and returns the duration
+// until the next window begins or ends.
+func (s *scheduler) update() time.Duration {
+	now := s.clock.Time()
+
+	s.lock.Lock()
+	// Drop the windows that are over.
+	i := 0
+	for i < len(s.windows) && !now.Before(s.windows[i].End()) {
+		i++
+	}
+	s.windows = s.windows[i:]
+
+	var (
+		current *Window
+		wait    = time.Duration(-1)
+	)
+	if len(s.windows) > 0 {
+		next := s.windows[0]
+		if next.contains(now) {
+			current = &next
+			wait = next.End().Sub(now)
+		} else {
+			wait = next.Start.Sub(now)
+		}
+	}
+
+	ending := s.active != nil && (current == nil || !current.Start.Equal(s.active.Start))
+	beginning := current != nil && (s.active == nil || ending)
+	s.active = current
+	participants := s.participants
+	s.lock.Unlock()
+
+	if ending {
+		s.end(participants)
+	}
+	if beginning {
+		s.begin(participants, *current)
+	}
+	if wait < 0 {
+		// Nothing is scheduled, so only a change of the windows can require
+		// an update.
+		wait = time.Hour
+	}
+	return wait
+}
+
+func (s *scheduler) begin(participants []namedParticipant, window Window) {
+	s.log.Info("maintenance window began",
+		zap.Time("start", window.Start),
+		zap.Time("end", window.End()),
+		zap.String("reason", window.Reason),
+	)
+	for _, p := range participants {
+		if err := p.participant.BeginMaintenance(context.Background(), window); err != nil {
+			s.log.Warn("failed to prepare for maintenance",
+				zap.String("participant", p.name),
+				zap.Error(err),
+			)
+		}
+	}
+}
+
+func (s *scheduler) end(participants []namedParticipant) {
+	s.log.Info("maintenance window ended")
+	for i := len(participants) - 1; i >= 0; i-- {
+		p := participants[i]
+		if err := p.participant.EndMaintenance(context.Background()); err != nil {
+			s.log.Warn("failed to resume after maintenance",
+				zap.String("participant", p.name),
+				zap.Error(err),
+			)
+		}
+	}
+}
@@ -0,0 +1,165 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package maintenance
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+// testParticipant records the windows it was notified of.
+type testParticipant struct {
+	name  string
+	calls *[]string
+}
+
+func (p *testParticipant) BeginMaintenance(_ context.Context, window Window) error {
+	*p.calls = append(*p.calls, "begin "+p.name+" "+window.Reason)
+	return nil
+}
+
+func (p *testParticipant) EndMaintenance(context.Context) error {
+	*p.calls = append(*p.calls, "end "+p.name)
+	return nil
+}
+
+func TestConfigVerify(t *testing.T) {
+	start := time.Unix(1_000_000, 0)
+	tests := []struct {
+		name        string
+		config      Config
+		expectedErr error
+	}{
+		{
+			name: "valid",
+			config: Config{
+				Windows: []Window{
+					{Start: start.Add(time.Hour), Duration: time.Hour},
+					{Start: start, Duration: time.Hour},
+				},
+			},
+		},
+		{
+			name:        "negative announce",
+			config:      Config{AnnounceBefore: -1},
+			expectedErr: errNegativeAnnounce,
+		},
+		{
+			name: "missing start",
+			config: Config{
+				Windows: []Window{{Duration: time.Hour}},
+			},
+			expectedErr: errMissingStart,
+		},
+		{
+			name: "non-positive duration",
+			config: Config{
+				Windows: []Window{{Start: start}},
+			},
+			expectedErr: errNonPositiveDuration,
+		},
+		{
+			name: "overlapping",
+			config: Config{
+				Windows: []Window{
+					{Start: start.Add(time.Minute), Duration: time.Hour},
+					{Start: start, Duration: time.Hour},
+				},
+			},
+			expectedErr: errOverlappingWindows,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.ErrorIs(t, test.config.Verify(), test.expectedErr)
+		})
+	}
+}
+
+func TestSchedulerBeginsAndEndsWindows(t *testing.T) {
+	require := require.New(t)
+
+	start := time.Unix(1_000_000, 0)
+	first := Window{Start: start.Add(time.Hour), Duration: time.Hour, Reason: "first"}
+	second := Window{Start: first.End(), Duration: time.Hour, Reason: "second"}
+
+	s, err := NewScheduler(logging.NoLog{}, Config{
+		Windows:        []Window{second, first},
+		AnnounceBefore: 10 * time.Minute,
+	})
+	require.NoError(err)
+	sched := s.(*scheduler)
+	sched.clock.Set(start)
+
+	calls := []string{}
+	sched.Register("a", &testParticipant{name: "a", calls: &calls})
+	sched.Register("b", &testParticipant{name: "b", calls: &calls})
+
+	require.Equal(time.Hour, sched.update())
+	require.Empty(calls)
+	_, announced := sched.Announced()
+	require.False(announced)
+	_, err = sched.HealthCheck(context.Background())
+	require.NoError(err)
+
+	sched.clock.Set(first.Start.Add(-5 * time.Minute))
+	window, announced := sched.Announced()
+	require.True(announced)
+	require.Equal(first, window)
+
+	sched.clock.Set(first.Start)
+	require.Equal(time.Hour, sched.update())
+	require.Equal([]string{"begin a first", "begin b first"}, calls)
+	_, err = sched.HealthCheck(context.Background())
+	require.ErrorIs(err, errInMaintenance)
+
+	// Back to back windows end the first window before beginning the second,
+	// and participants end in the reverse order they began.
+	calls = calls[:0]
+	sched.clock.Set(second.Start)
+	require.Equal(time.Hour, sched.update())
+	require.Equal([]string{"end b", "end a", "begin a second", "begin b second"}, calls)
+	require.Equal([]Window{second}, sched.Windows())
+
+	calls = calls[:0]
+	sched.clock.Set(second.End())
+	require.Equal(time.Hour, sched.update())
+	require.Equal([]string{"end b", "end a"}, calls)
+	require.Empty(sched.Windows())
+	_, err = sched.HealthCheck(context.Background())
+	require.NoError(err)
+}
+
+func TestSchedulerScheduleAndCancel(t *testing.T) {
+	require := require.New(t)
+
+	start := time.Unix(1_000_000, 0)
+	s, err := NewScheduler(logging.NoLog{}, Config{})
+	require.NoError(err)
+	sched := s.(*scheduler)
+	sched.clock.Set(start)
+
+	calls := []string{}
+	sched.Register("a", &testParticipant{name: "a", calls: &calls})
+
+	window := Window{Start: start, Duration: time.Hour, Reason: "upgrade"}
+	require.NoError(sched.Schedule(window))
+	require.ErrorIs(sched.Schedule(Window{Start: start.Add(time.Minute), Duration: time.Hour}), errOverlappingWindows)
+	require.ErrorIs(sched.Schedule(Window{Start: start.Add(-time.Hour), Duration: time.Hour}), errWindowOver)
+
+	require.Equal(time.Hour, sched.update())
+	require.Equal([]string{"begin a upgrade"}, calls)
+
+	// Cancelling the window in progress ends it.
+	require.ErrorIs(sched.Cancel(start.Add(time.Second)), errUnknownWindow)
+	require.NoError(sched.Cancel(start))
+	require.Equal(time.Hour, sched.update())
+	require.Equal([]string{"begin a upgrade", "end a"}, calls)
+	require.Empty(sched.Windows())
+}
@@ -0,0 +1,86 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package maintenance
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+var (
+	errMissingStart        = errors.New("maintenance window must have a start time")
+	errNonPositiveDuration = errors.New("maintenance window duration must be positive")
+	errNegativeAnnounce    = errors.New("maintenance announcement lead time must be non-negative")
+	errOverlappingWindows  = errors.New("maintenance windows overlap")
+)
+
+// Window is a period during which the node is expected to be taken down for
+// planned maintenance, such as a restart to upgrade it.
+type Window struct {
+	Start    time.Time     `json:"start"`
+	Duration time.Duration `json:"duration"`
+	// Reason is logged when the window begins
+	Reason string `json:"reason,omitempty"`
+}
+
+// End returns the time the window ends at.
+func (w Window) End() time.Time {
+	return w.Start.Add(w.Duration)
+}
+
+func (w Window) Verify() error {
+	switch {
+	case w.Start.IsZero():
+		return errMissingStart
+	case w.Duration <= 0:
+		return errNonPositiveDuration
+	default:
+		return nil
+	}
+}
+
+// contains returns true if [t] is within the window.
+func (w Window) contains(t time.Time) bool {
+	return !t.Before(w.Start) && t.Before(w.End())
+}
+
+func (w Window) overlaps(other Window) bool {
+	return w.Start.Before(other.End()) && other.Start.Before(w.End())
+}
+
+type Config struct {
+	// Maintenance windows scheduled when the node starts. More windows can be
+	// scheduled through the admin API.
+	Windows []Window `json:"windows"`
+
+	// AnnounceBefore is how long before a window begins that the window is
+	// announced to peers.
+	AnnounceBefore time.Duration `json:"announceBefore"`
+}
+
+func (c Config) Verify() error {
+	if c.AnnounceBefore < 0 {
+		return errNegativeAnnounce
+	}
+	windows := make([]Window, len(c.Windows))
+	copy(windows, c.Windows)
+	sortWindows(windows)
+	for i, window := range windows {
+		if err := window.Verify(); err != nil {
+			return fmt.Errorf("invalid maintenance window %d: %w", i, err)
+		}
+		if i > 0 && windows[i-1].overlaps(window) {
+			return fmt.Errorf("%w: %s and %s", errOverlappingWindows, windows[i-1].Start, window.Start)
+		}
+	}
+	return nil
+}
+
+func sortWindows(windows []Window) {
+	sort.Slice(windows, func(i, j int) bool {
+		return windows[i].Start.Before(windows[j].Start)
+	})
+}
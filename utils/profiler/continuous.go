@@ -9,6 +9,7 @@ import (
 
 	"golang.org/x/sync/errgroup"
 
+	"github.com/ava-labs/avalanchego/utils"
 	"github.com/ava-labs/avalanchego/utils/filesystem"
 )
 
@@ -24,12 +25,18 @@ type Config struct {
 type ContinuousProfiler interface {
 	Dispatch() error
 	Shutdown()
+
+	// Pause stops capturing profiles, once the profile being captured is
+	// complete, until Resume is called.
+	Pause()
+	Resume()
 }
 
 type continuousProfiler struct {
 	profiler    *profiler
 	freq        time.Duration
 	maxNumFiles int
+	paused      utils.AtomicBool
 
 	// Dispatch returns when closer is closed
 	closer chan struct{}
@@ -49,6 +56,15 @@ func (p *continuousProfiler) Dispatch() error {
 	defer t.Stop()
 
 	for {
+		if p.paused.GetValue() {
+			select {
+			case <-p.closer:
+				return nil
+			case <-t.C:
+			}
+			continue
+		}
+
 		if err := p.start(); err != nil {
 			return err
 		}
@@ -98,6 +114,14 @@ func (p *continuousProfiler) Shutdown() {
 	close(p.closer)
 }
 
+func (p *continuousProfiler) Pause() {
+	p.paused.SetValue(true)
+}
+
+func (p *continuousProfiler) Resume() {
+	p.paused.SetValue(false)
+}
+
 // Renames the file at [name] to [name].1, the file at [name].1 to [name].2, etc.
 // Assumes that there is a file at [name].
 func rotate(name string, maxNumFiles int) error {
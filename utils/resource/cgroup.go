@@ -0,0 +1,193 @@
+//go:build linux
+// +build linux
+
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package resource
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ava-labs/avalanchego/utils/perms"
+)
+
+// cpuPeriod is the period, in microseconds, over which the CPU usage of a
+// group is limited.
+const cpuPeriod = 100_000
+
+var _ Group = (*cgroup)(nil)
+
+type cgroup struct {
+	path string
+
+	cpuThrottledPeriods *prometheus.Desc
+	cpuThrottledTime    *prometheus.Desc
+	memoryMaxEvents     *prometheus.Desc
+	oomKills            *prometheus.Desc
+}
+
+// NewGroup returns the cgroup named [name] under the cgroup [root], limited by
+// [limits]. The cgroup is created if it doesn't exist. [root] must be a cgroup
+// v2 directory delegated to the node, with the cpu and memory controllers
+// enabled in its cgroup.subtree_control.
+//
+// The metrics of the group are reported under [namespace].
+func NewGroup(root, name, namespace string, limits Limits) (Group, error) {
+	if err := limits.Verify(); err != nil {
+		return nil, err
+	}
+
+	path := filepath.Join(root, name)
+	if err := os.Mkdir(path, perms.ReadWriteExecute); err != nil && !errors.Is(err, fs.ErrExist) {
+		return nil, fmt.Errorf("couldn't create cgroup %q: %w", path, err)
+	}
+
+	// The limits left unset are reset, as the cgroup may be left over from a
+	// previous run with other limits.
+	cpuMax := "max"
+	if limits.CPU > 0 {
+		cpuMax = strconv.FormatUint(uint64(limits.CPU*cpuPeriod), 10)
+	}
+	if err := writeLimit(path, "cpu.max", fmt.Sprintf("%s %d", cpuMax, cpuPeriod), limits.CPU > 0); err != nil {
+		return nil, err
+	}
+	memoryMax := "max"
+	if limits.Memory > 0 {
+		memoryMax = strconv.FormatUint(limits.Memory, 10)
+	}
+	if err := writeLimit(path, "memory.max", memoryMax, limits.Memory > 0); err != nil {
+		return nil, err
+	}
+
+	return &cgroup{
+		path: path,
+		cpuThrottledPeriods: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "cpu_throttled_periods_total"),
+			"Number of periods in which the processes were throttled for reaching their CPU limit",
+			nil,
+			nil,
+		),
+		cpuThrottledTime: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "cpu_throttled_seconds_total"),
+			"Total time the processes were throttled for reaching their CPU limit in seconds",
+			nil,
+			nil,
+		),
+		memoryMaxEvents: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "memory_max_events_total"),
+			"Number of times the memory of the processes reached their memory limit",
+			nil,
+			nil,
+		),
+		oomKills: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "oom_kills_total"),
+			"Number of processes killed for exceeding their memory limit",
+			nil,
+			nil,
+		),
+	}, nil
+}
+
+// writeLimit writes [value] to the interface file [file] of the cgroup at
+// [path]. If the limit isn't [required], a missing interface file, such as
+// the file of a controller that isn't enabled, is ignored.
+func writeLimit(path, file, value string, required bool) error {
+	err := writeFile(filepath.Join(path, file), value)
+	if err == nil || (!required && errors.Is(err, fs.ErrNotExist)) {
+		return nil
+	}
+	return fmt.Errorf("couldn't write %q of cgroup %q: %w", file, path, err)
+}
+
+// writeFile writes [value] to the existing interface file at [path]. The
+// interface files of a cgroup can't be created.
+func writeFile(path, value string) error {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_TRUNC, 0)
+	if err != nil {
+		return err
+	}
+	_, err = file.WriteString(value)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+func (c *cgroup) Add(pid int) error {
+	if err := writeFile(filepath.Join(c.path, "cgroup.procs"), strconv.Itoa(pid)); err != nil {
+		return fmt.Errorf("couldn't move process %d to cgroup %q: %w", pid, c.path, err)
+	}
+	return nil
+}
+
+func (c *cgroup) Close() error {
+	err := os.Remove(c.path)
+	if errors.Is(err, syscall.EBUSY) {
+		return nil
+	}
+	return err
+}
+
+func (c *cgroup) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.cpuThrottledPeriods
+	ch <- c.cpuThrottledTime
+	ch <- c.memoryMaxEvents
+	ch <- c.oomKills
+}
+
+// Collect reports every statistic of the cgroup that could be read. The
+// statistics of the controllers that aren't enabled are omitted.
+func (c *cgroup) Collect(ch chan<- prometheus.Metric) {
+	if stats, err := readStats(filepath.Join(c.path, "cpu.stat")); err == nil {
+		if periods, ok := stats["nr_throttled"]; ok {
+			ch <- prometheus.MustNewConstMetric(c.cpuThrottledPeriods, prometheus.CounterValue, float64(periods))
+		}
+		if usec, ok := stats["throttled_usec"]; ok {
+			ch <- prometheus.MustNewConstMetric(c.cpuThrottledTime, prometheus.CounterValue, float64(usec)/1_000_000)
+		}
+	}
+	if events, err := readStats(filepath.Join(c.path, "memory.events")); err == nil {
+		if maxEvents, ok := events["max"]; ok {
+			ch <- prometheus.MustNewConstMetric(c.memoryMaxEvents, prometheus.CounterValue, float64(maxEvents))
+		}
+		if kills, ok := events["oom_kill"]; ok {
+			ch <- prometheus.MustNewConstMetric(c.oomKills, prometheus.CounterValue, float64(kills))
+		}
+	}
+}
+
+// readStats parses a flat keyed cgroup file, made of lines of a key and a
+// value.
+func readStats(path string) (map[string]uint64, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	stats := make(map[string]uint64)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 {
+			continue
+		}
+		value, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			continue
+		}
+		stats[fields[0]] = value
+	}
+	return stats, scanner.Err()
+}
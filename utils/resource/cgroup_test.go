@@ -0,0 +1,99 @@
+//go:build linux
+// +build linux
+
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package resource
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/stretchr/testify/require"
+)
+
+// newFakeCgroup creates the directory of the cgroup named [name] under [root],
+// holding the interface files of the cpu and memory controllers, as a cgroup
+// v2 filesystem would.
+func newFakeCgroup(t *testing.T, root, name string) string {
+	t.Helper()
+
+	path := filepath.Join(root, name)
+	require.NoError(t, os.Mkdir(path, 0o750))
+	for _, file := range []string{"cgroup.procs", "cpu.max", "memory.max", "cpu.stat", "memory.events"} {
+		require.NoError(t, os.WriteFile(filepath.Join(path, file), nil, 0o640))
+	}
+	return path
+}
+
+func TestGroupLimits(t *testing.T) {
+	require := require.New(t)
+
+	root := t.TempDir()
+	path := newFakeCgroup(t, root, "chain")
+	require.NoError(os.WriteFile(filepath.Join(path, "memory.max"), []byte("1024"), 0o640))
+
+	group, err := NewGroup(root, "chain", "plugin", Limits{CPU: 1.5})
+	require.NoError(err)
+
+	cpuMax, err := os.ReadFile(filepath.Join(path, "cpu.max"))
+	require.NoError(err)
+	require.Equal("150000 100000", string(cpuMax))
+
+	// The limits left over from a previous run are reset.
+	memoryMax, err := os.ReadFile(filepath.Join(path, "memory.max"))
+	require.NoError(err)
+	require.Equal("max", string(memoryMax))
+
+	require.NoError(group.Add(1234))
+	procs, err := os.ReadFile(filepath.Join(path, "cgroup.procs"))
+	require.NoError(err)
+	require.Equal("1234", string(procs))
+}
+
+func TestGroupMissingController(t *testing.T) {
+	require := require.New(t)
+
+	root := t.TempDir()
+	path := newFakeCgroup(t, root, "chain")
+	require.NoError(os.Remove(filepath.Join(path, "memory.max")))
+
+	// The memory controller is only required if the memory is limited.
+	_, err := NewGroup(root, "chain", "plugin", Limits{CPU: 1})
+	require.NoError(err)
+
+	_, err = NewGroup(root, "chain", "plugin", Limits{Memory: 1024})
+	require.ErrorIs(err, os.ErrNotExist)
+}
+
+func TestGroupCollect(t *testing.T) {
+	require := require.New(t)
+
+	root := t.TempDir()
+	path := newFakeCgroup(t, root, "chain")
+	require.NoError(os.WriteFile(filepath.Join(path, "cpu.stat"), []byte("usage_usec 10\nnr_periods 20\nnr_throttled 5\nthrottled_usec 2500000\n"), 0o640))
+	require.NoError(os.WriteFile(filepath.Join(path, "memory.events"), []byte("low 0\nhigh 0\nmax 3\noom 1\noom_kill 1\n"), 0o640))
+
+	group, err := NewGroup(root, "chain", "plugin", Limits{})
+	require.NoError(err)
+
+	registry := prometheus.NewRegistry()
+	require.NoError(registry.Register(group))
+	families, err := registry.Gather()
+	require.NoError(err)
+	values := make(map[string]float64, len(families))
+	for _, family := range families {
+		require.Len(family.Metric, 1)
+		values[family.GetName()] = family.Metric[0].Counter.GetValue()
+	}
+	require.Equal(map[string]float64{
+		"plugin_cpu_throttled_periods_total": 5,
+		"plugin_cpu_throttled_seconds_total": 2.5,
+		"plugin_memory_max_events_total":     3,
+		"plugin_oom_kills_total":             1,
+	}, values)
+}
@@ -0,0 +1,18 @@
+//go:build !linux
+// +build !linux
+
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package resource
+
+import (
+	"errors"
+)
+
+var errCgroupsUnsupported = errors.New("cgroups are only supported on linux")
+
+// NewGroup returns an error, as cgroups are only supported on linux.
+func NewGroup(string, string, string, Limits) (Group, error) {
+	return nil, errCgroupsUnsupported
+}
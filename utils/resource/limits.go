@@ -0,0 +1,67 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package resource
+
+import (
+	"errors"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+var errNegativeCPULimit = errors.New("cpu limit must be >= 0")
+
+// Limits are the ceilings of the resources a group of processes can consume.
+type Limits struct {
+	// CPU is the max number of CPU cores of usage of the group. The processes
+	// are throttled once they reach it. If 0, the CPU usage isn't limited.
+	CPU float64 `json:"cpu"`
+
+	// Memory is the max number of bytes of memory of the group. The processes
+	// are killed if they can't be kept under it. If 0, the memory usage isn't
+	// limited.
+	Memory uint64 `json:"memory"`
+}
+
+func (l Limits) Verify() error {
+	if l.CPU < 0 {
+		return errNegativeCPULimit
+	}
+	return nil
+}
+
+// ChainLimits maps the ID or an alias of a chain to the limits of the process
+// running the chain's VM.
+type ChainLimits map[string]Limits
+
+// Get returns the limits of the chain with ID [chainID] and aliases
+// [aliases]. The limits registered for the chain's ID take precedence over the
+// limits registered for its aliases. Returns false if no limits were
+// registered for the chain.
+func (c ChainLimits) Get(chainID ids.ID, aliases []string) (Limits, bool) {
+	if limits, ok := c[chainID.String()]; ok {
+		return limits, true
+	}
+	for _, alias := range aliases {
+		if limits, ok := c[alias]; ok {
+			return limits, true
+		}
+	}
+	return Limits{}, false
+}
+
+// Group is a group of processes whose resources are limited together. It
+// reports how often the processes were throttled as metrics, which are read
+// when the metrics are gathered.
+type Group interface {
+	prometheus.Collector
+
+	// Add moves the process [pid] into the group.
+	Add(pid int) error
+
+	// Close removes the group, unless processes are still in it, such as the
+	// processes added to the group by another user.
+	Close() error
+}
@@ -0,0 +1,41 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package resource
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+func TestChainLimitsGet(t *testing.T) {
+	require := require.New(t)
+
+	chainID := ids.GenerateTestID()
+	limits := ChainLimits{
+		"X":              {CPU: 1},
+		chainID.String(): {CPU: 2},
+	}
+
+	chainLimits, ok := limits.Get(chainID, []string{"X"})
+	require.True(ok)
+	require.Equal(2.0, chainLimits.CPU)
+
+	chainLimits, ok = limits.Get(ids.GenerateTestID(), []string{"avm", "X"})
+	require.True(ok)
+	require.Equal(1.0, chainLimits.CPU)
+
+	_, ok = limits.Get(ids.GenerateTestID(), nil)
+	require.False(ok)
+}
+
+func TestLimitsVerify(t *testing.T) {
+	require := require.New(t)
+
+	require.NoError(Limits{}.Verify())
+	require.NoError(Limits{CPU: 1.5, Memory: 1024}.Verify())
+	require.ErrorIs(Limits{CPU: -1}.Verify(), errNegativeCPULimit)
+}
@@ -0,0 +1,117 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package resource
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/shirou/gopsutil/process"
+)
+
+var _ ProcessCollector = (*processCollector)(nil)
+
+// ProcessCollector reports the resource usage of a single process, such as the
+// process of a VM plugin, as metrics. The usage is read when the metrics are
+// gathered.
+type ProcessCollector interface {
+	prometheus.Collector
+
+	// SetProcess changes the process whose usage is reported to [pid]. If
+	// [pid] is 0, no usage is reported.
+	SetProcess(pid int)
+}
+
+type processCollector struct {
+	lock sync.RWMutex
+	pid  int
+
+	cpu          *prometheus.Desc
+	rss          *prometheus.Desc
+	openFDs      *prometheus.Desc
+	readBytes    *prometheus.Desc
+	writtenBytes *prometheus.Desc
+}
+
+func NewProcessCollector(namespace string) ProcessCollector {
+	return &processCollector{
+		cpu: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "cpu_seconds_total"),
+			"Total user and system CPU time spent by the process in seconds",
+			nil,
+			nil,
+		),
+		rss: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "resident_memory_bytes"),
+			"Resident memory size of the process in bytes",
+			nil,
+			nil,
+		),
+		openFDs: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "open_fds"),
+			"Number of file descriptors opened by the process",
+			nil,
+			nil,
+		),
+		readBytes: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "disk_read_bytes_total"),
+			"Total number of bytes read from disk by the process",
+			nil,
+			nil,
+		),
+		writtenBytes: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "disk_written_bytes_total"),
+			"Total number of bytes written to disk by the process",
+			nil,
+			nil,
+		),
+	}
+}
+
+func (c *processCollector) SetProcess(pid int) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.pid = pid
+}
+
+func (c *processCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.cpu
+	ch <- c.rss
+	ch <- c.openFDs
+	ch <- c.readBytes
+	ch <- c.writtenBytes
+}
+
+// Collect reports every usage of the process that could be read. The usages
+// that can't be read, such as the open file descriptors on platforms that
+// don't expose them, are omitted.
+func (c *processCollector) Collect(ch chan<- prometheus.Metric) {
+	c.lock.RLock()
+	pid := c.pid
+	c.lock.RUnlock()
+
+	if pid == 0 {
+		return
+	}
+	p, err := process.NewProcess(int32(pid))
+	if err != nil {
+		return
+	}
+
+	if times, err := p.Times(); err == nil {
+		ch <- prometheus.MustNewConstMetric(c.cpu, prometheus.CounterValue, times.User+times.System)
+	}
+	if mem, err := p.MemoryInfo(); err == nil {
+		ch <- prometheus.MustNewConstMetric(c.rss, prometheus.GaugeValue, float64(mem.RSS))
+	}
+	if fds, err := p.NumFDs(); err == nil {
+		ch <- prometheus.MustNewConstMetric(c.openFDs, prometheus.GaugeValue, float64(fds))
+	}
+	if io, err := p.IOCounters(); err == nil {
+		ch <- prometheus.MustNewConstMetric(c.readBytes, prometheus.CounterValue, float64(io.ReadBytes))
+		ch <- prometheus.MustNewConstMetric(c.writtenBytes, prometheus.CounterValue, float64(io.WriteBytes))
+	}
+}
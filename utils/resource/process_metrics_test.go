@@ -0,0 +1,48 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package resource
+
+import (
+	"os"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessCollector(t *testing.T) {
+	require := require.New(t)
+
+	c := NewProcessCollector("plugin")
+	registry := prometheus.NewRegistry()
+	require.NoError(registry.Register(c))
+
+	// Nothing is reported until a process is set.
+	families, err := registry.Gather()
+	require.NoError(err)
+	require.Empty(families)
+
+	c.SetProcess(os.Getpid())
+	families, err = registry.Gather()
+	require.NoError(err)
+	values := make(map[string]float64, len(families))
+	for _, family := range families {
+		require.Len(family.Metric, 1)
+		metric := family.Metric[0]
+		switch {
+		case metric.Counter != nil:
+			values[family.GetName()] = metric.Counter.GetValue()
+		case metric.Gauge != nil:
+			values[family.GetName()] = metric.Gauge.GetValue()
+		}
+	}
+	require.Contains(values, "plugin_cpu_seconds_total")
+	require.Greater(values["plugin_resident_memory_bytes"], 0.0)
+
+	c.SetProcess(0)
+	families, err = registry.Gather()
+	require.NoError(err)
+	require.Empty(families)
+}
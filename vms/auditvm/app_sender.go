@@ -0,0 +1,45 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package auditvm
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+)
+
+var _ common.AppSender = noOpAppSender{}
+
+// noOpAppSender drops all the messages sent by the auditor, so that it never
+// communicates with the network.
+type noOpAppSender struct{}
+
+func (noOpAppSender) SendAppRequest(context.Context, ids.NodeIDSet, uint32, []byte) error {
+	return nil
+}
+
+func (noOpAppSender) SendAppResponse(context.Context, ids.NodeID, uint32, []byte) error {
+	return nil
+}
+
+func (noOpAppSender) SendAppError(context.Context, ids.NodeID, uint32, int32, string) error {
+	return nil
+}
+
+func (noOpAppSender) SendAppGossip(context.Context, []byte) error {
+	return nil
+}
+
+func (noOpAppSender) SendAppGossipSpecific(context.Context, ids.NodeIDSet, []byte) error {
+	return nil
+}
+
+func (noOpAppSender) SendCrossChainAppRequest(context.Context, ids.ID, uint32, []byte) error {
+	return nil
+}
+
+func (noOpAppSender) SendCrossChainAppResponse(context.Context, ids.ID, uint32, []byte) error {
+	return nil
+}
@@ -0,0 +1,158 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package auditvm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
+)
+
+var (
+	errDiverged         = errors.New("auditor diverged from the vm")
+	errOutOfSync        = errors.New("auditor couldn't catch up to the vm")
+	errNotHeightIndexed = errors.New("vm isn't height indexed")
+)
+
+// failure describes why the audit was halted.
+type failure struct {
+	BlockID ids.ID `json:"blockID"`
+	Height  uint64 `json:"height"`
+	Reason  string `json:"reason"`
+
+	err error
+}
+
+// prepareAudit parses and verifies [blk] with the auditor before [blk] is
+// accepted by the VM. Returns nil if the audit has been halted.
+//
+// The block must be verified by the auditor before the VM accepts it, so that
+// both instances observe the same shared memory.
+func (vm *blockVM) prepareAudit(ctx context.Context, blk snowman.Block) snowman.Block {
+	if vm.halted() {
+		return nil
+	}
+
+	if err := vm.catchUp(ctx, blk); err != nil {
+		vm.halt(errOutOfSync, blk, err)
+		return nil
+	}
+
+	auditBlk, err := vm.auditor.ParseBlock(ctx, blk.Bytes())
+	if err != nil {
+		vm.halt(errDiverged, blk, fmt.Errorf("couldn't parse block: %w", err))
+		return nil
+	}
+	if auditBlkID := auditBlk.ID(); auditBlkID != blk.ID() {
+		vm.halt(errDiverged, blk, fmt.Errorf("auditor parsed block as %s", auditBlkID))
+		return nil
+	}
+	if err := auditBlk.Verify(ctx); err != nil {
+		vm.halt(errDiverged, blk, fmt.Errorf("block failed verification: %w", err))
+		return nil
+	}
+	return auditBlk
+}
+
+// finishAudit accepts [auditBlk] with the auditor after [blk] was accepted by
+// the VM.
+func (vm *blockVM) finishAudit(ctx context.Context, blk, auditBlk snowman.Block) {
+	if auditBlk == nil {
+		return
+	}
+	if err := auditBlk.Accept(ctx); err != nil {
+		vm.halt(errDiverged, blk, fmt.Errorf("block failed to be accepted: %w", err))
+		return
+	}
+	vm.metrics.audited.Inc()
+}
+
+// catchUp accepts the blocks that were accepted by the VM before the audit
+// started, so that the auditor's last accepted block is the parent of [blk].
+func (vm *blockVM) catchUp(ctx context.Context, blk snowman.Block) error {
+	lastAcceptedID, err := vm.auditor.LastAccepted(ctx)
+	if err != nil {
+		return err
+	}
+	parentID := blk.Parent()
+	if lastAcceptedID == parentID {
+		return nil
+	}
+	if vm.hVM == nil {
+		return errNotHeightIndexed
+	}
+
+	lastAccepted, err := vm.auditor.GetBlock(ctx, lastAcceptedID)
+	if err != nil {
+		return err
+	}
+	for height := lastAccepted.Height() + 1; height < blk.Height(); height++ {
+		blkID, err := vm.hVM.GetBlockIDAtHeight(ctx, height)
+		if err != nil {
+			return err
+		}
+		vmBlk, err := vm.ChainVM.GetBlock(ctx, blkID)
+		if err != nil {
+			return err
+		}
+		auditBlk, err := vm.auditor.ParseBlock(ctx, vmBlk.Bytes())
+		if err != nil {
+			return err
+		}
+		if auditBlkID := auditBlk.ID(); auditBlkID != blkID {
+			return fmt.Errorf("auditor parsed block %s at height %d as %s", blkID, height, auditBlkID)
+		}
+		if err := auditBlk.Verify(ctx); err != nil {
+			return err
+		}
+		if err := auditBlk.Accept(ctx); err != nil {
+			return err
+		}
+		vm.metrics.audited.Inc()
+	}
+
+	lastAcceptedID, err = vm.auditor.LastAccepted(ctx)
+	if err != nil {
+		return err
+	}
+	if lastAcceptedID != parentID {
+		return fmt.Errorf("auditor's last accepted block is %s rather than %s", lastAcceptedID, parentID)
+	}
+	return nil
+}
+
+func (vm *blockVM) halted() bool {
+	vm.lock.Lock()
+	defer vm.lock.Unlock()
+
+	return vm.failure != nil
+}
+
+// halt stops the audit because of [err] while auditing [blk].
+func (vm *blockVM) halt(reason error, blk snowman.Block, err error) {
+	vm.lock.Lock()
+	defer vm.lock.Unlock()
+
+	blkID := blk.ID()
+	height := blk.Height()
+	vm.failure = &failure{
+		BlockID: blkID,
+		Height:  height,
+		Reason:  err.Error(),
+		err:     fmt.Errorf("%w at block %s: %s", reason, blkID, err),
+	}
+	vm.metrics.halted.Set(1)
+
+	vm.chainCtx.Log.Error("halting block audit",
+		zap.Error(reason),
+		zap.Stringer("blkID", blkID),
+		zap.Uint64("height", height),
+		zap.NamedError("cause", err),
+	)
+}
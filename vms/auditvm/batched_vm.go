@@ -0,0 +1,61 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package auditvm
+
+import (
+	"context"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
+	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
+)
+
+func (vm *blockVM) GetAncestors(
+	ctx context.Context,
+	blkID ids.ID,
+	maxBlocksNum int,
+	maxBlocksSize int,
+	maxBlocksRetrivalTime time.Duration,
+) ([][]byte, error) {
+	if vm.bVM == nil {
+		return nil, block.ErrRemoteVMNotImplemented
+	}
+	return vm.bVM.GetAncestors(
+		ctx,
+		blkID,
+		maxBlocksNum,
+		maxBlocksSize,
+		maxBlocksRetrivalTime,
+	)
+}
+
+func (vm *blockVM) BatchedParseBlock(ctx context.Context, blks [][]byte) ([]snowman.Block, error) {
+	if vm.bVM == nil {
+		return nil, block.ErrRemoteVMNotImplemented
+	}
+
+	blocks, err := vm.bVM.BatchedParseBlock(ctx, blks)
+	wrappedBlocks := make([]snowman.Block, len(blocks))
+	for i, block := range blocks {
+		wrappedBlocks[i] = vm.wrapBlock(block)
+	}
+	return wrappedBlocks, err
+}
+
+func (vm *blockVM) BatchedGetBlock(ctx context.Context, blkIDs []ids.ID) ([]snowman.Block, []error, error) {
+	if vm.bVM == nil {
+		return nil, nil, block.ErrRemoteVMNotImplemented
+	}
+
+	blocks, errs, err := vm.bVM.BatchedGetBlock(ctx, blkIDs)
+	wrappedBlocks := make([]snowman.Block, len(blocks))
+	for i, block := range blocks {
+		if block == nil {
+			continue
+		}
+		wrappedBlocks[i] = vm.wrapBlock(block)
+	}
+	return wrappedBlocks, errs, err
+}
@@ -0,0 +1,68 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package auditvm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
+	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
+)
+
+var (
+	errExpectedBlockWithVerifyContext = errors.New("expected block.WithVerifyContext")
+
+	_ snowman.Block           = (*auditBlock)(nil)
+	_ snowman.OracleBlock     = (*auditBlock)(nil)
+	_ block.WithVerifyContext = (*auditBlock)(nil)
+)
+
+type auditBlock struct {
+	snowman.Block
+
+	vm *blockVM
+}
+
+func (b *auditBlock) ShouldVerifyWithContext(ctx context.Context) (bool, error) {
+	blkWithCtx, ok := b.Block.(block.WithVerifyContext)
+	if !ok {
+		return false, nil
+	}
+	return blkWithCtx.ShouldVerifyWithContext(ctx)
+}
+
+func (b *auditBlock) VerifyWithContext(ctx context.Context, blockCtx *block.Context) error {
+	blkWithCtx, ok := b.Block.(block.WithVerifyContext)
+	if !ok {
+		return fmt.Errorf("%w but got %T", errExpectedBlockWithVerifyContext, b.Block)
+	}
+	return blkWithCtx.VerifyWithContext(ctx, blockCtx)
+}
+
+func (b *auditBlock) Accept(ctx context.Context) error {
+	auditBlk := b.vm.prepareAudit(ctx, b.Block)
+	if err := b.Block.Accept(ctx); err != nil {
+		return err
+	}
+	b.vm.finishAudit(ctx, b.Block, auditBlk)
+	return nil
+}
+
+func (b *auditBlock) Options(ctx context.Context) ([2]snowman.Block, error) {
+	oracleBlock, ok := b.Block.(snowman.OracleBlock)
+	if !ok {
+		return [2]snowman.Block{}, snowman.ErrNotOracle
+	}
+
+	blks, err := oracleBlock.Options(ctx)
+	if err != nil {
+		return [2]snowman.Block{}, err
+	}
+	return [2]snowman.Block{
+		b.vm.wrapBlock(blks[0]),
+		b.vm.wrapBlock(blks[1]),
+	}, nil
+}
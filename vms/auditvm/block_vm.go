@@ -0,0 +1,240 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package auditvm
+
+import (
+	"context"
+	"path/filepath"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ava-labs/avalanchego/api/metrics"
+	"github.com/ava-labs/avalanchego/database/manager"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+)
+
+// auditorChannelSize is the number of messages the auditor can send to its
+// engine before the messages are dropped. The auditor doesn't have an engine,
+// so the messages are never read.
+const auditorChannelSize = 1
+
+var (
+	_ block.ChainVM              = (*blockVM)(nil)
+	_ block.BatchedChainVM       = (*blockVM)(nil)
+	_ block.HeightIndexedChainVM = (*blockVM)(nil)
+	_ block.Decoder              = (*blockVM)(nil)
+	_ block.StateDiffer          = (*blockVM)(nil)
+	_ block.MessageVerifier      = (*blockVM)(nil)
+
+	_ block.BuildBlockWithContextChainVM = (*blockVM)(nil)
+
+	_ common.AppErrorHandler = (*blockVM)(nil)
+)
+
+// blockVM re-verifies every block accepted by a VM against a second instance
+// of the VM, the auditor, which may be running a different build.
+//
+// The auditor never builds blocks and never participates in consensus. It only
+// follows the accepted chain. Once the auditor diverges from the VM, the audit
+// is halted and the VM's health check fails.
+//
+// State sync isn't supported while auditing, as the auditor must execute every
+// accepted block.
+type blockVM struct {
+	block.ChainVM
+	bVM  block.BatchedChainVM
+	hVM  block.HeightIndexedChainVM
+	dVM  block.Decoder
+	sdVM block.StateDiffer
+	mvVM block.MessageVerifier
+	bbVM block.BuildBlockWithContextChainVM
+
+	auditor   block.ChainVM
+	auditorDB manager.Manager
+	// toAuditor is the channel the auditor uses to notify its non-existent
+	// engine
+	toAuditor chan common.Message
+
+	chainCtx *snow.Context
+	metrics  *auditMetrics
+
+	lock sync.Mutex
+	// failure is the reason the audit was halted, or nil if the audit is
+	// ongoing
+	failure *failure
+}
+
+// NewBlockVM returns a VM that audits [vm] using [auditor]. The auditor is
+// initialized alongside [vm] and stores its state in [auditorDB].
+func NewBlockVM(vm, auditor block.ChainVM, auditorDB manager.Manager) block.ChainVM {
+	bVM, _ := vm.(block.BatchedChainVM)
+	hVM, _ := vm.(block.HeightIndexedChainVM)
+	dVM, _ := vm.(block.Decoder)
+	sdVM, _ := vm.(block.StateDiffer)
+	mvVM, _ := vm.(block.MessageVerifier)
+	bbVM, _ := vm.(block.BuildBlockWithContextChainVM)
+	return &blockVM{
+		ChainVM:   vm,
+		bVM:       bVM,
+		hVM:       hVM,
+		dVM:       dVM,
+		sdVM:      sdVM,
+		mvVM:      mvVM,
+		bbVM:      bbVM,
+		auditor:   auditor,
+		auditorDB: auditorDB,
+		toAuditor: make(chan common.Message, auditorChannelSize),
+	}
+}
+
+func (vm *blockVM) Initialize(
+	ctx context.Context,
+	chainCtx *snow.Context,
+	db manager.Manager,
+	genesisBytes,
+	upgradeBytes,
+	configBytes []byte,
+	toEngine chan<- common.Message,
+	fxs []*common.Fx,
+	appSender common.AppSender,
+) error {
+	registerer := prometheus.NewRegistry()
+	m, err := newMetrics(registerer)
+	if err != nil {
+		return err
+	}
+	vm.metrics = m
+
+	vmGatherer := metrics.NewOptionalGatherer()
+	auditorGatherer := metrics.NewOptionalGatherer()
+	multiGatherer := metrics.NewMultiGatherer()
+	errs := wrappers.Errs{}
+	errs.Add(
+		multiGatherer.Register("auditvm", registerer),
+		multiGatherer.Register("auditor", auditorGatherer),
+		multiGatherer.Register("", vmGatherer),
+		chainCtx.Metrics.Register(multiGatherer),
+	)
+	if errs.Errored() {
+		return errs.Err
+	}
+	chainCtx.Metrics = vmGatherer
+	vm.chainCtx = chainCtx
+
+	if err := vm.ChainVM.Initialize(ctx, chainCtx, db, genesisBytes, upgradeBytes, configBytes, toEngine, fxs, appSender); err != nil {
+		return err
+	}
+
+	auditorCtx := &snow.Context{
+		NetworkID:   chainCtx.NetworkID,
+		SubnetID:    chainCtx.SubnetID,
+		ChainID:     chainCtx.ChainID,
+		NodeID:      chainCtx.NodeID,
+		XChainID:    chainCtx.XChainID,
+		AVAXAssetID: chainCtx.AVAXAssetID,
+		Log:         chainCtx.Log,
+		Keystore:    chainCtx.Keystore,
+		// The auditor must not modify the shared memory, as the changes are
+		// already applied by the VM.
+		SharedMemory:      &readOnlySharedMemory{SharedMemory: chainCtx.SharedMemory},
+		BCLookup:          chainCtx.BCLookup,
+		SNLookup:          chainCtx.SNLookup,
+		Metrics:           auditorGatherer,
+		ChainDataDir:      filepath.Join(chainCtx.ChainDataDir, "auditor"),
+		ValidatorState:    chainCtx.ValidatorState,
+		StakingLeafSigner: chainCtx.StakingLeafSigner,
+		StakingCertLeaf:   chainCtx.StakingCertLeaf,
+		StakingBLSKey:     chainCtx.StakingBLSKey,
+	}
+	return vm.auditor.Initialize(
+		ctx,
+		auditorCtx,
+		vm.auditorDB,
+		genesisBytes,
+		upgradeBytes,
+		configBytes,
+		vm.toAuditor,
+		fxs,
+		noOpAppSender{},
+	)
+}
+
+func (vm *blockVM) SetState(ctx context.Context, state snow.State) error {
+	if err := vm.ChainVM.SetState(ctx, state); err != nil {
+		return err
+	}
+	return vm.auditor.SetState(ctx, state)
+}
+
+func (vm *blockVM) Shutdown(ctx context.Context) error {
+	errs := wrappers.Errs{}
+	errs.Add(
+		vm.ChainVM.Shutdown(ctx),
+		vm.auditor.Shutdown(ctx),
+	)
+	return errs.Err
+}
+
+// HealthCheck reports unhealthy if the audit was halted.
+func (vm *blockVM) HealthCheck(ctx context.Context) (interface{}, error) {
+	vmDetails, vmErr := vm.ChainVM.HealthCheck(ctx)
+
+	vm.lock.Lock()
+	defer vm.lock.Unlock()
+
+	details := map[string]interface{}{
+		"vm": vmDetails,
+	}
+	if vm.failure != nil {
+		details["audit"] = vm.failure
+	}
+	if vmErr != nil {
+		return details, vmErr
+	}
+	if vm.failure != nil {
+		return details, vm.failure.err
+	}
+	return details, nil
+}
+
+func (vm *blockVM) AppRequestFailedWithError(ctx context.Context, nodeID ids.NodeID, requestID uint32, appErr *common.AppError) error {
+	return common.NotifyAppRequestFailed(ctx, vm.ChainVM, nodeID, requestID, appErr)
+}
+
+func (vm *blockVM) BuildBlock(ctx context.Context) (snowman.Block, error) {
+	blk, err := vm.ChainVM.BuildBlock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return vm.wrapBlock(blk), nil
+}
+
+func (vm *blockVM) ParseBlock(ctx context.Context, b []byte) (snowman.Block, error) {
+	blk, err := vm.ChainVM.ParseBlock(ctx, b)
+	if err != nil {
+		return nil, err
+	}
+	return vm.wrapBlock(blk), nil
+}
+
+func (vm *blockVM) GetBlock(ctx context.Context, blkID ids.ID) (snowman.Block, error) {
+	blk, err := vm.ChainVM.GetBlock(ctx, blkID)
+	if err != nil {
+		return nil, err
+	}
+	return vm.wrapBlock(blk), nil
+}
+
+func (vm *blockVM) wrapBlock(blk snowman.Block) snowman.Block {
+	return &auditBlock{
+		Block: blk,
+		vm:    vm,
+	}
+}
@@ -0,0 +1,215 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package auditvm
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/database/manager"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
+	"github.com/ava-labs/avalanchego/version"
+)
+
+var errTest = errors.New("non-deterministic execution")
+
+type heightIndexedVM struct {
+	*block.TestVM
+	*block.TestHeightIndexedVM
+}
+
+// newTestChain returns [numBlocks] blocks, where the first block is the
+// genesis block and is accepted.
+func newTestChain(numBlocks int) []*snowman.TestBlock {
+	blks := make([]*snowman.TestBlock, numBlocks)
+	parentID := ids.Empty
+	for i := range blks {
+		blkID := ids.GenerateTestID()
+		blks[i] = &snowman.TestBlock{
+			TestDecidable: choices.TestDecidable{
+				IDV:     blkID,
+				StatusV: choices.Processing,
+			},
+			ParentV: parentID,
+			HeightV: uint64(i),
+			BytesV:  blkID[:],
+		}
+		parentID = blkID
+	}
+	blks[0].StatusV = choices.Accepted
+	return blks
+}
+
+// copyChain returns a copy of [blks] that can be independently decided.
+func copyChain(blks []*snowman.TestBlock) []*snowman.TestBlock {
+	copies := make([]*snowman.TestBlock, len(blks))
+	for i, blk := range blks {
+		blkCopy := *blk
+		copies[i] = &blkCopy
+	}
+	return copies
+}
+
+func newTestVM(t *testing.T, blks []*snowman.TestBlock) *heightIndexedVM {
+	vm := &heightIndexedVM{
+		TestVM: &block.TestVM{
+			TestVM: common.TestVM{T: t},
+		},
+		TestHeightIndexedVM: &block.TestHeightIndexedVM{T: t},
+	}
+	vm.InitializeF = func(context.Context, *snow.Context, manager.Manager, []byte, []byte, []byte, chan<- common.Message, []*common.Fx, common.AppSender) error {
+		return nil
+	}
+	vm.HealthCheckF = func(context.Context) (interface{}, error) {
+		return nil, nil
+	}
+	vm.ParseBlockF = func(_ context.Context, b []byte) (snowman.Block, error) {
+		for _, blk := range blks {
+			if string(blk.BytesV) == string(b) {
+				return blk, nil
+			}
+		}
+		return nil, errTest
+	}
+	vm.GetBlockF = func(_ context.Context, blkID ids.ID) (snowman.Block, error) {
+		for _, blk := range blks {
+			if blk.IDV == blkID {
+				return blk, nil
+			}
+		}
+		return nil, errTest
+	}
+	vm.LastAcceptedF = func(context.Context) (ids.ID, error) {
+		lastAccepted := blks[0].IDV
+		for _, blk := range blks {
+			if blk.StatusV == choices.Accepted {
+				lastAccepted = blk.IDV
+			}
+		}
+		return lastAccepted, nil
+	}
+	vm.GetBlockIDAtHeightF = func(_ context.Context, height uint64) (ids.ID, error) {
+		return blks[height].IDV, nil
+	}
+	return vm
+}
+
+func newTestBlockVM(t *testing.T, vm, auditor block.ChainVM) block.ChainVM {
+	auditVM := NewBlockVM(vm, auditor, manager.NewMemDB(version.Semantic1_0_0))
+	require.NoError(t, auditVM.Initialize(
+		context.Background(),
+		snow.DefaultContextTest(),
+		manager.NewMemDB(version.Semantic1_0_0),
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+	))
+	return auditVM
+}
+
+func acceptBytes(t *testing.T, vm block.ChainVM, b []byte) {
+	blk, err := vm.ParseBlock(context.Background(), b)
+	require.NoError(t, err)
+	require.NoError(t, blk.Verify(context.Background()))
+	require.NoError(t, blk.Accept(context.Background()))
+}
+
+func TestAudit(t *testing.T) {
+	require := require.New(t)
+
+	blks := newTestChain(3)
+	auditorBlks := copyChain(blks)
+	vm := newTestBlockVM(t, newTestVM(t, blks), newTestVM(t, auditorBlks))
+
+	for _, blk := range blks[1:] {
+		acceptBytes(t, vm, blk.BytesV)
+	}
+
+	for i := range blks {
+		require.Equal(choices.Accepted, blks[i].Status())
+		require.Equal(choices.Accepted, auditorBlks[i].Status())
+	}
+
+	_, err := vm.HealthCheck(context.Background())
+	require.NoError(err)
+}
+
+func TestAuditDivergence(t *testing.T) {
+	require := require.New(t)
+
+	blks := newTestChain(4)
+	auditorBlks := copyChain(blks)
+	auditorBlks[2].VerifyV = errTest
+	vm := newTestBlockVM(t, newTestVM(t, blks), newTestVM(t, auditorBlks))
+
+	for _, blk := range blks[1:] {
+		acceptBytes(t, vm, blk.BytesV)
+	}
+
+	// The VM must keep accepting blocks after the auditor diverged.
+	for _, blk := range blks {
+		require.Equal(choices.Accepted, blk.Status())
+	}
+	require.Equal(choices.Accepted, auditorBlks[1].Status())
+	require.Equal(choices.Processing, auditorBlks[2].Status())
+	require.Equal(choices.Processing, auditorBlks[3].Status())
+
+	details, err := vm.HealthCheck(context.Background())
+	require.ErrorIs(err, errDiverged)
+	detailsMap, ok := details.(map[string]interface{})
+	require.True(ok)
+	f, ok := detailsMap["audit"].(*failure)
+	require.True(ok)
+	require.Equal(blks[2].IDV, f.BlockID)
+	require.Equal(uint64(2), f.Height)
+}
+
+func TestAuditCatchUp(t *testing.T) {
+	require := require.New(t)
+
+	blks := newTestChain(4)
+	// The VM accepted blocks before the audit started.
+	blks[1].StatusV = choices.Accepted
+	blks[2].StatusV = choices.Accepted
+	auditorBlks := copyChain(blks)
+	auditorBlks[1].StatusV = choices.Processing
+	auditorBlks[2].StatusV = choices.Processing
+	vm := newTestBlockVM(t, newTestVM(t, blks), newTestVM(t, auditorBlks))
+
+	acceptBytes(t, vm, blks[3].BytesV)
+
+	for _, blk := range auditorBlks {
+		require.Equal(choices.Accepted, blk.Status())
+	}
+
+	_, err := vm.HealthCheck(context.Background())
+	require.NoError(err)
+}
+
+func TestAuditCatchUpRequiresHeightIndex(t *testing.T) {
+	require := require.New(t)
+
+	blks := newTestChain(3)
+	blks[1].StatusV = choices.Accepted
+	auditorBlks := copyChain(blks)
+	auditorBlks[1].StatusV = choices.Processing
+	vm := newTestBlockVM(t, newTestVM(t, blks).TestVM, newTestVM(t, auditorBlks))
+
+	acceptBytes(t, vm, blks[2].BytesV)
+
+	require.Equal(choices.Accepted, blks[2].Status())
+	_, err := vm.HealthCheck(context.Background())
+	require.ErrorIs(err, errOutOfSync)
+}
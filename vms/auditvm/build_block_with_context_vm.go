@@ -0,0 +1,23 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package auditvm
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
+	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
+)
+
+func (vm *blockVM) BuildBlockWithContext(ctx context.Context, blockCtx *block.Context) (snowman.Block, error) {
+	if vm.bbVM == nil {
+		return vm.BuildBlock(ctx)
+	}
+
+	blk, err := vm.bbVM.BuildBlockWithContext(ctx, blockCtx)
+	if err != nil {
+		return nil, err
+	}
+	return vm.wrapBlock(blk), nil
+}
@@ -0,0 +1,17 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package auditvm
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
+)
+
+func (vm *blockVM) DecodeBlock(ctx context.Context, blkBytes []byte) ([]byte, error) {
+	if vm.dVM == nil {
+		return nil, block.ErrDecoderNotImplemented
+	}
+	return vm.dVM.DecodeBlock(ctx, blkBytes)
+}
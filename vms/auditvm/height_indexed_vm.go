@@ -0,0 +1,25 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package auditvm
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
+)
+
+func (vm *blockVM) VerifyHeightIndex(ctx context.Context) error {
+	if vm.hVM == nil {
+		return block.ErrHeightIndexedVMNotImplemented
+	}
+	return vm.hVM.VerifyHeightIndex(ctx)
+}
+
+func (vm *blockVM) GetBlockIDAtHeight(ctx context.Context, height uint64) (ids.ID, error) {
+	if vm.hVM == nil {
+		return ids.Empty, block.ErrHeightIndexedVMNotImplemented
+	}
+	return vm.hVM.GetBlockIDAtHeight(ctx, height)
+}
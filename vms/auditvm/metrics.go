@@ -0,0 +1,37 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package auditvm
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+)
+
+type auditMetrics struct {
+	// audited counts the blocks that were accepted by the auditor
+	audited prometheus.Counter
+	// halted is 1 if the audit was halted and 0 otherwise
+	halted prometheus.Gauge
+}
+
+func newMetrics(registerer prometheus.Registerer) (*auditMetrics, error) {
+	m := &auditMetrics{
+		audited: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "blocks_audited",
+			Help: "number of accepted blocks that were re-executed by the auditor",
+		}),
+		halted: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "halted",
+			Help: "1 if the auditor diverged from the vm, or couldn't keep up with it, and 0 otherwise",
+		}),
+	}
+
+	errs := wrappers.Errs{}
+	errs.Add(
+		registerer.Register(m.audited),
+		registerer.Register(m.halted),
+	)
+	return m, errs.Err
+}
@@ -0,0 +1,28 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package auditvm
+
+import (
+	"github.com/ava-labs/avalanchego/chains/atomic"
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+var _ atomic.SharedMemory = (*readOnlySharedMemory)(nil)
+
+// readOnlySharedMemory reads from the shared memory without ever modifying it.
+type readOnlySharedMemory struct {
+	atomic.SharedMemory
+}
+
+// Apply drops [requests], but still writes [batches] as they contain the
+// auditor's own state.
+func (*readOnlySharedMemory) Apply(_ map[ids.ID]*atomic.Requests, batches ...database.Batch) error {
+	for _, batch := range batches {
+		if err := batch.Write(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
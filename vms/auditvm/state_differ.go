@@ -0,0 +1,22 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package auditvm
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
+)
+
+func (vm *blockVM) GetStateDiff(
+	ctx context.Context,
+	startHeight uint64,
+	endHeight uint64,
+	f func(key []byte, value []byte) error,
+) error {
+	if vm.sdVM == nil {
+		return block.ErrStateDifferNotImplemented
+	}
+	return vm.sdVM.GetStateDiff(ctx, startHeight, endHeight, f)
+}
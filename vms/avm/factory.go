@@ -4,6 +4,7 @@
 package avm
 
 import (
+	"github.com/ava-labs/avalanchego/api/pagination"
 	"github.com/ava-labs/avalanchego/snow"
 	"github.com/ava-labs/avalanchego/vms"
 )
@@ -13,6 +14,9 @@ var _ vms.Factory = (*Factory)(nil)
 type Factory struct {
 	TxFee            uint64
 	CreateAssetTxFee uint64
+	// MaxPageSizes are the node's maximum page sizes of the paginated API
+	// endpoints. They are overridden by the chain's config.
+	MaxPageSizes pagination.Limits
 }
 
 func (f *Factory) New(*snow.Context) (interface{}, error) {
@@ -8,16 +8,20 @@ import (
 	"fmt"
 	"math"
 	"net/http"
+	"time"
 
 	"go.uber.org/zap"
 
 	"github.com/ava-labs/avalanchego/api"
+	"github.com/ava-labs/avalanchego/api/pagination"
+	"github.com/ava-labs/avalanchego/database/snapshots"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/snow/choices"
 	"github.com/ava-labs/avalanchego/utils/crypto"
 	"github.com/ava-labs/avalanchego/utils/formatting"
 	"github.com/ava-labs/avalanchego/utils/json"
 	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/vms/avm/states"
 	"github.com/ava-labs/avalanchego/vms/avm/txs"
 	"github.com/ava-labs/avalanchego/vms/components/avax"
 	"github.com/ava-labs/avalanchego/vms/components/keystore"
@@ -32,10 +36,16 @@ const (
 	// Max number of addresses that can be passed in as argument to GetUTXOs
 	maxGetUTXOsAddrs = 1024
 
-	// Max number of items allowed in a page
-	maxPageSize uint64 = 1024
+	getAddressTxsEndpoint = "avm.getAddressTxs"
+	getUTXOsEndpoint      = "avm.getUTXOs"
 )
 
+// MaxPageSizeBounds bounds the pages of UTXOs and txs the AVM returns.
+var MaxPageSizeBounds = pagination.Limits{
+	getAddressTxsEndpoint: pagination.DefaultMaxPageSize,
+	getUTXOsEndpoint:      pagination.DefaultMaxPageSize,
+}
+
 var (
 	errUnknownAssetID         = errors.New("unknown asset ID")
 	errTxNotCreateAsset       = errors.New("transaction doesn't create an asset")
@@ -51,6 +61,7 @@ var (
 	errNoAddresses            = errors.New("no addresses provided")
 	errNoKeys                 = errors.New("from addresses have no keys or funds")
 	errMissingPrivateKey      = errors.New("argument 'privateKey' not given")
+	errSnapshotOfSourceChain  = errors.New("snapshots can't be read from for UTXOs imported from another chain")
 )
 
 // Service defines the base service for the asset vm
@@ -115,8 +126,9 @@ func (service *Service) GetAddressTxs(_ *http.Request, args *GetAddressTxsArgs,
 		zap.Uint64("cursor", cursor),
 		zap.Uint64("pageSize", pageSize),
 	)
-	if pageSize > maxPageSize {
-		return fmt.Errorf("pageSize > maximum allowed (%d)", maxPageSize)
+	maxPageSize := service.vm.maxPageSizes.MaxPageSize(getAddressTxsEndpoint, pagination.DefaultMaxPageSize)
+	if err := pagination.VerifyPageSize(pageSize, maxPageSize); err != nil {
+		return err
 	} else if pageSize == 0 {
 		pageSize = maxPageSize
 	}
@@ -191,11 +203,13 @@ func (service *Service) GetTx(_ *http.Request, args *api.GetTxArgs, reply *api.G
 		vm:   service.vm,
 		txID: args.TxID,
 	}
-	if status := tx.Status(); !status.Fetched() {
+	txStatus := tx.Status()
+	if !txStatus.Fetched() {
 		return errUnknownTx
 	}
 
 	reply.Encoding = args.Encoding
+	reply.Final = txStatus == choices.Accepted
 
 	if args.Encoding == formatting.JSON {
 		reply.Tx = tx
@@ -215,8 +229,53 @@ func (service *Service) GetTx(_ *http.Request, args *api.GetTxArgs, reply *api.G
 	return nil
 }
 
+// CreateSnapshotReply defines the CreateSnapshot replies returned from the API
+type CreateSnapshotReply struct {
+	SnapshotID ids.ID    `json:"snapshotID"`
+	Expiry     time.Time `json:"expiry"`
+}
+
+// CreateSnapshot pins the UTXOs the chain currently has, so that the pages of
+// a GetUTXOs query that passes the returned snapshot ID observe the same UTXOs.
+// The snapshot is released once it expires.
+func (service *Service) CreateSnapshot(_ *http.Request, _ *struct{}, reply *CreateSnapshotReply) error {
+	service.vm.ctx.Log.Debug("AVM: CreateSnapshot called")
+
+	snapshotID, expiry, err := service.vm.snapshots.Create()
+	if err != nil {
+		return fmt.Errorf("couldn't create snapshot: %w", err)
+	}
+	reply.SnapshotID = snapshotID
+	reply.Expiry = expiry
+	return nil
+}
+
+// SnapshotArgs are arguments for passing into ReleaseSnapshot requests
+type SnapshotArgs struct {
+	SnapshotID ids.ID `json:"snapshotID"`
+}
+
+// ReleaseSnapshot releases a snapshot created by CreateSnapshot before it
+// expires
+func (service *Service) ReleaseSnapshot(_ *http.Request, args *SnapshotArgs, _ *api.EmptyReply) error {
+	service.vm.ctx.Log.Debug("AVM: ReleaseSnapshot called",
+		zap.Stringer("snapshotID", args.SnapshotID),
+	)
+
+	service.vm.snapshots.Release(args.SnapshotID)
+	return nil
+}
+
+// GetUTXOsArgs are arguments for passing into GetUTXOs requests
+type GetUTXOsArgs struct {
+	api.GetUTXOsArgs
+	// SnapshotID, if set, is the snapshot created by CreateSnapshot that the
+	// UTXOs are read from
+	SnapshotID ids.ID `json:"snapshotID"`
+}
+
 // GetUTXOs gets all utxos for passed in addresses
-func (service *Service) GetUTXOs(_ *http.Request, args *api.GetUTXOsArgs, reply *api.GetUTXOsReply) error {
+func (service *Service) GetUTXOs(_ *http.Request, args *GetUTXOsArgs, reply *api.GetUTXOsReply) error {
 	service.vm.ctx.Log.Debug("AVM: GetUTXOs called",
 		logging.UserStrings("addresses", args.Addresses),
 	)
@@ -238,6 +297,9 @@ func (service *Service) GetUTXOs(_ *http.Request, args *api.GetUTXOsArgs, reply
 		}
 		sourceChain = chainID
 	}
+	if sourceChain != service.vm.ctx.ChainID && args.SnapshotID != ids.Empty {
+		return errSnapshotOfSourceChain
+	}
 
 	addrSet, err := avax.ParseServiceAddresses(service.vm, args.Addresses)
 	if err != nil {
@@ -262,13 +324,21 @@ func (service *Service) GetUTXOs(_ *http.Request, args *api.GetUTXOsArgs, reply
 		endAddr   ids.ShortID
 		endUTXOID ids.ID
 	)
-	limit := int(args.Limit)
-	if limit <= 0 || int(maxPageSize) < limit {
-		limit = int(maxPageSize)
-	}
+	limit := int(pagination.PageSize(
+		uint64(args.Limit),
+		service.vm.maxPageSizes.MaxPageSize(getUTXOsEndpoint, pagination.DefaultMaxPageSize),
+	))
 	if sourceChain == service.vm.ctx.ChainID {
+		var utxoReader avax.UTXOReader = service.vm.state
+		if args.SnapshotID != ids.Empty {
+			snapshot, err := service.vm.snapshots.Get(args.SnapshotID)
+			if err != nil {
+				return fmt.Errorf("couldn't get snapshot %s: %w", args.SnapshotID, err)
+			}
+			utxoReader = states.NewUTXOReader(snapshots.NewDatabase(snapshot), service.vm.parser)
+		}
 		utxos, endAddr, endUTXOID, err = avax.GetPaginatedUTXOs(
-			service.vm.state,
+			utxoReader,
 			addrSet,
 			startAddr,
 			startUTXO,
@@ -1492,7 +1562,7 @@ func (service *Service) Import(_ *http.Request, args *ImportArgs, reply *api.JSO
 		return err
 	}
 
-	atomicUTXOs, _, _, err := service.vm.GetAtomicUTXOs(chainID, kc.Addrs, ids.ShortEmpty, ids.Empty, int(maxPageSize))
+	atomicUTXOs, _, _, err := service.vm.GetAtomicUTXOs(chainID, kc.Addrs, ids.ShortEmpty, ids.Empty, int(pagination.DefaultMaxPageSize))
 	if err != nil {
 		return fmt.Errorf("problem retrieving user's atomic UTXOs: %w", err)
 	}
@@ -18,8 +18,10 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/ava-labs/avalanchego/api"
+	"github.com/ava-labs/avalanchego/api/pagination"
 	"github.com/ava-labs/avalanchego/chains/atomic"
 	"github.com/ava-labs/avalanchego/database/manager"
+	"github.com/ava-labs/avalanchego/database/snapshots"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/snow"
 	"github.com/ava-labs/avalanchego/snow/choices"
@@ -466,6 +468,12 @@ func TestServiceGetTxs(t *testing.T) {
 	require.NoError(t, err)
 	require.Len(t, getTxsReply.TxIDs, 10)
 	require.Equal(t, getTxsReply.TxIDs, testTxs[10:20])
+
+	// a page larger than the maximum page size is rejected
+	vm.maxPageSizes = pagination.Limits{getAddressTxsEndpoint: 5}
+	getTxsReply = &GetAddressTxsReply{}
+	err = s.GetAddressTxs(nil, getTxsArgs, getTxsReply)
+	require.ErrorIs(t, err, pagination.ErrPageSizeTooLarge)
 }
 
 func TestServiceGetAllBalances(t *testing.T) {
@@ -2051,7 +2059,7 @@ func TestServiceGetUTXOs(t *testing.T) {
 	for _, test := range tests {
 		t.Run(test.label, func(t *testing.T) {
 			reply := &api.GetUTXOsReply{}
-			err := s.GetUTXOs(nil, test.args, reply)
+			err := s.GetUTXOs(nil, &GetUTXOsArgs{GetUTXOsArgs: *test.args}, reply)
 			if err != nil {
 				if !test.shouldErr {
 					t.Fatal(err)
@@ -2068,6 +2076,62 @@ func TestServiceGetUTXOs(t *testing.T) {
 	}
 }
 
+func TestServiceGetUTXOsSnapshot(t *testing.T) {
+	require := require.New(t)
+
+	_, vm, s, _, _ := setup(t, true)
+	defer func() {
+		require.NoError(vm.Shutdown(context.Background()))
+		vm.ctx.Lock.Unlock()
+	}()
+
+	rawAddr := ids.GenerateTestShortID()
+	addr, err := vm.FormatLocalAddress(rawAddr)
+	require.NoError(err)
+
+	putUTXO := func() {
+		utxo := &avax.UTXO{
+			UTXOID: avax.UTXOID{
+				TxID: ids.GenerateTestID(),
+			},
+			Asset: avax.Asset{ID: vm.ctx.AVAXAssetID},
+			Out: &secp256k1fx.TransferOutput{
+				Amt: 1,
+				OutputOwners: secp256k1fx.OutputOwners{
+					Threshold: 1,
+					Addrs:     []ids.ShortID{rawAddr},
+				},
+			},
+		}
+		require.NoError(vm.state.PutUTXO(utxo))
+		require.NoError(vm.db.Commit())
+	}
+
+	putUTXO()
+	snapshot := &CreateSnapshotReply{}
+	require.NoError(s.CreateSnapshot(nil, &struct{}{}, snapshot))
+	putUTXO()
+
+	args := &GetUTXOsArgs{
+		GetUTXOsArgs: api.GetUTXOsArgs{
+			Addresses: []string{addr},
+		},
+	}
+	reply := &api.GetUTXOsReply{}
+	require.NoError(s.GetUTXOs(nil, args, reply))
+	require.Len(reply.UTXOs, 2)
+
+	// The snapshot doesn't observe the UTXO added after it was created.
+	args.SnapshotID = snapshot.SnapshotID
+	reply = &api.GetUTXOsReply{}
+	require.NoError(s.GetUTXOs(nil, args, reply))
+	require.Len(reply.UTXOs, 1)
+
+	require.NoError(s.ReleaseSnapshot(nil, &SnapshotArgs{SnapshotID: snapshot.SnapshotID}, &api.EmptyReply{}))
+	err = s.GetUTXOs(nil, args, &api.GetUTXOsReply{})
+	require.ErrorIs(err, snapshots.ErrUnknownSnapshot)
+}
+
 func TestGetAssetDescription(t *testing.T) {
 	_, vm, s, _, genesisTx := setup(t, true)
 	defer func() {
@@ -61,3 +61,10 @@ func New(db database.Database, parser txs.Parser, metrics prometheus.Registerer)
 		TxState:        txState,
 	}, err
 }
+
+// NewUTXOReader returns a reader of the UTXOs that [New] stores in [db]. The
+// reader doesn't meter its caches, so that it can be created for each read
+// from a snapshot of [db].
+func NewUTXOReader(db database.Database, parser txs.Parser) avax.UTXOReader {
+	return avax.NewUTXOState(prefixdb.New(utxoPrefix, db), parser.Codec())
+}
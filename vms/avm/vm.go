@@ -19,9 +19,11 @@ import (
 
 	"go.uber.org/zap"
 
+	"github.com/ava-labs/avalanchego/api/pagination"
 	"github.com/ava-labs/avalanchego/cache"
 	"github.com/ava-labs/avalanchego/database"
 	"github.com/ava-labs/avalanchego/database/manager"
+	"github.com/ava-labs/avalanchego/database/snapshots"
 	"github.com/ava-labs/avalanchego/database/versiondb"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/pubsub"
@@ -53,6 +55,11 @@ const (
 	batchSize          = 30
 	assetToFxCacheSize = 1024
 	txDeduplicatorSize = 8192
+
+	// Snapshots created through the API are released after [apiSnapshotTTL].
+	// At most [maxAPISnapshots] are held at once.
+	apiSnapshotTTL  = time.Minute
+	maxAPISnapshots = 64
 )
 
 var (
@@ -62,7 +69,8 @@ var (
 	errBootstrapping             = errors.New("chain is currently bootstrapping")
 	errInsufficientFunds         = errors.New("insufficient funds")
 
-	_ vertex.DAGVM = (*VM)(nil)
+	_ vertex.DAGVM   = (*VM)(nil)
+	_ vertex.Decoder = (*VM)(nil)
 )
 
 type VM struct {
@@ -103,6 +111,9 @@ type VM struct {
 	baseDB database.Database
 	db     *versiondb.Database
 
+	// Snapshots of [baseDB] that paginated API queries are read from
+	snapshots *snapshots.Registry
+
 	typeToFxIndex map[reflect.Type]int
 	fxs           []*extensions.ParsedFx
 
@@ -110,6 +121,9 @@ type VM struct {
 
 	addressTxsIndexer index.AddressTxsIndexer
 
+	// Maximum page sizes of the paginated API endpoints
+	maxPageSizes pagination.Limits
+
 	uniqueTxs cache.Deduplicator
 }
 
@@ -130,6 +144,10 @@ func (*VM) Disconnected(context.Context, ids.NodeID) error {
 type Config struct {
 	IndexTransactions    bool `json:"index-transactions"`
 	IndexAllowIncomplete bool `json:"index-allow-incomplete"`
+	// MaxPageSizes overrides the node's maximum page size of the paginated API
+	// endpoints, e.g. {"avm.getUTXOs": 512}. They can't exceed
+	// [MaxPageSizeBounds].
+	MaxPageSizes pagination.Limits `json:"max-page-sizes"`
 }
 
 func (vm *VM) Initialize(
@@ -148,6 +166,9 @@ func (vm *VM) Initialize(
 		if err := stdjson.Unmarshal(configBytes, &avmConfig); err != nil {
 			return err
 		}
+		if err := avmConfig.MaxPageSizes.Verify(MaxPageSizeBounds); err != nil {
+			return err
+		}
 		ctx.Log.Info("VM config initialized",
 			zap.Reflect("config", avmConfig),
 		)
@@ -167,9 +188,11 @@ func (vm *VM) Initialize(
 
 	db := dbManager.Current().Database
 	vm.ctx = ctx
+	vm.maxPageSizes = vm.Factory.MaxPageSizes.Override(avmConfig.MaxPageSizes).Effective(MaxPageSizeBounds)
 	vm.toEngine = toEngine
 	vm.baseDB = db
 	vm.db = versiondb.New(db)
+	vm.snapshots = snapshots.NewRegistry(db, apiSnapshotTTL, maxAPISnapshots)
 	vm.assetToFxCache = &cache.LRU{Size: assetToFxCacheSize}
 
 	vm.pubsub = pubsub.New(ctx.Log)
@@ -290,6 +313,7 @@ func (vm *VM) Shutdown(context.Context) error {
 	vm.timer.Stop()
 	vm.ctx.Lock.Lock()
 
+	vm.snapshots.Close()
 	return vm.baseDB.Close()
 }
 
@@ -319,8 +343,17 @@ func (vm *VM) CreateHandlers(context.Context) (map[string]*common.HTTPHandler, e
 	err := walletServer.RegisterService(&vm.walletService, "wallet")
 
 	return map[string]*common.HTTPHandler{
-		"":        {Handler: rpcServer},
-		"/wallet": {Handler: walletServer},
+		"": {
+			Handler: rpcServer,
+			// Accepted transactions never change, so they can be cached once
+			// they are fetched by ID.
+			CachedMethods: []string{"avm.getTx"},
+			OpenAPI:       json.PaginatedOpenAPIPathItem("X-Chain API", "avm", &Service{}, vm.maxPageSizes),
+		},
+		"/wallet": {
+			Handler: walletServer,
+			OpenAPI: json.OpenAPIPathItem("X-Chain wallet API", "wallet", &vm.walletService),
+		},
 		"/events": {LockOptions: common.NoLock, Handler: vm.pubsub},
 	}, err
 }
@@ -334,7 +367,11 @@ func (*VM) CreateStaticHandlers(context.Context) (map[string]*common.HTTPHandler
 	// name this service "avm"
 	staticService := CreateStaticService()
 	return map[string]*common.HTTPHandler{
-		"": {LockOptions: common.WriteLock, Handler: newServer},
+		"": {
+			LockOptions: common.WriteLock,
+			Handler:     newServer,
+			OpenAPI:     json.OpenAPIPathItem("Static API of the AVM", "avm", staticService),
+		},
 	}, newServer.RegisterService(staticService, "avm")
 }
 
@@ -350,6 +387,23 @@ func (vm *VM) ParseTx(_ context.Context, b []byte) (snowstorm.Tx, error) {
 	return vm.parseTx(b)
 }
 
+func (vm *VM) DecodeTx(_ context.Context, b []byte) ([]byte, error) {
+	tx, err := vm.parser.Parse(b)
+	if err != nil {
+		return nil, err
+	}
+	err = tx.Unsigned.Visit(&txInit{
+		tx:            tx,
+		ctx:           vm.ctx,
+		typeToFxIndex: vm.typeToFxIndex,
+		fxs:           vm.fxs,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return stdjson.Marshal(tx)
+}
+
 func (vm *VM) GetTx(_ context.Context, txID ids.ID) (snowstorm.Tx, error) {
 	tx := &UniqueTx{
 		vm:   vm,
@@ -18,6 +18,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/ava-labs/avalanchego/api/keystore"
+	"github.com/ava-labs/avalanchego/api/pagination"
 	"github.com/ava-labs/avalanchego/chains/atomic"
 	"github.com/ava-labs/avalanchego/database/manager"
 	"github.com/ava-labs/avalanchego/database/mockdb"
@@ -554,6 +555,37 @@ func TestFxInitializationFailure(t *testing.T) {
 	}
 }
 
+func TestMaxPageSizesExceedBounds(t *testing.T) {
+	require := require.New(t)
+
+	vm := &VM{}
+	ctx := NewContext(t)
+	ctx.Lock.Lock()
+	defer func() {
+		require.NoError(vm.Shutdown(context.Background()))
+		ctx.Lock.Unlock()
+	}()
+
+	configBytes, err := stdjson.Marshal(Config{
+		MaxPageSizes: pagination.Limits{
+			getUTXOsEndpoint: pagination.DefaultMaxPageSize + 1,
+		},
+	})
+	require.NoError(err)
+	err = vm.Initialize(
+		context.Background(),
+		ctx,                                     // context
+		manager.NewMemDB(version.Semantic1_0_0), // dbManager
+		BuildGenesisTest(t),                     // genesisState
+		nil,                                     // upgradeBytes
+		configBytes,                             // configBytes
+		make(chan common.Message, 1),            // engineMessenger
+		nil,                                     // fxs
+		nil,                                     // AppSender
+	)
+	require.Error(err)
+}
+
 func TestIssueTx(t *testing.T) {
 	genesisBytes, issuer, vm, _ := GenesisVM(t)
 	ctx := vm.ctx
@@ -0,0 +1,308 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package aggregator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"go.uber.org/zap"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/utils/crypto/bls"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/math"
+)
+
+var (
+	_ Aggregator = (*aggregator)(nil)
+
+	errNoValidators       = errors.New("no validators provided")
+	errInvalidQuorum      = errors.New("quorum numerator must be in the range (0, denominator]")
+	errDuplicateValidator = errors.New("duplicate validator")
+	errInsufficientWeight = errors.New("insufficient signature weight")
+)
+
+// Validator is a member of the signing set of a subnet.
+type Validator = snow.SigningValidator
+
+// Result is the outcome of a successful aggregation.
+type Result struct {
+	// Signature is the aggregate of all the valid signatures that were
+	// collected.
+	Signature *bls.Signature
+	// Signers identifies the validators whose signatures are included in
+	// [Signature].
+	Signers *Bits
+	// SignedWeight is the cumulative weight of [Signers].
+	SignedWeight uint64
+	// TotalWeight is the cumulative weight of all the requested validators.
+	TotalWeight uint64
+}
+
+// Aggregator collects BLS signatures over a message from a weighted quorum of
+// validators using AppRequests.
+//
+// The VM using an Aggregator must forward AppResponse and AppRequestFailed
+// messages to it. Messages for requests the Aggregator didn't issue are
+// reported as unhandled so that the VM can process them itself.
+type Aggregator interface {
+	// Aggregate requests signatures over [msg] from [vdrs] and returns once
+	// at least [quorumNum]/[quorumDen] of the total weight of [vdrs] has
+	// signed, once every validator has responded, or once [ctx] is done.
+	//
+	// The indices of the returned bitset refer to the order of [vdrs].
+	Aggregate(
+		ctx context.Context,
+		msg []byte,
+		vdrs []*Validator,
+		quorumNum uint64,
+		quorumDen uint64,
+	) (*Result, error)
+
+	// AppResponse returns true if [requestID] was issued by this aggregator.
+	AppResponse(ctx context.Context, nodeID ids.NodeID, requestID uint32, response []byte) bool
+
+	// AppRequestFailed returns true if [requestID] was issued by this
+	// aggregator.
+	AppRequestFailed(ctx context.Context, nodeID ids.NodeID, requestID uint32, appErr *common.AppError) bool
+}
+
+type Config struct {
+	Log    logging.Logger
+	Sender common.AppSender
+	// NextRequestID returns a request ID that isn't used by any other
+	// outstanding AppRequest of the VM.
+	NextRequestID func() uint32
+}
+
+type signatureResult struct {
+	nodeID    ids.NodeID
+	signature *bls.Signature
+}
+
+type pendingRequest struct {
+	// nodeIDs that haven't responded yet
+	outstanding ids.NodeIDSet
+	results     chan signatureResult
+}
+
+type aggregator struct {
+	config Config
+
+	lock    sync.Mutex
+	pending map[uint32]*pendingRequest
+}
+
+func New(config Config) Aggregator {
+	return &aggregator{
+		config:  config,
+		pending: make(map[uint32]*pendingRequest),
+	}
+}
+
+func (a *aggregator) Aggregate(
+	ctx context.Context,
+	msg []byte,
+	vdrs []*Validator,
+	quorumNum uint64,
+	quorumDen uint64,
+) (*Result, error) {
+	if len(vdrs) == 0 {
+		return nil, errNoValidators
+	}
+	if quorumNum == 0 || quorumNum > quorumDen {
+		return nil, errInvalidQuorum
+	}
+
+	var (
+		indices     = make(map[ids.NodeID]int, len(vdrs))
+		nodeIDs     = ids.NewNodeIDSet(len(vdrs))
+		totalWeight uint64
+		err         error
+	)
+	for i, vdr := range vdrs {
+		if _, ok := indices[vdr.NodeID]; ok {
+			return nil, fmt.Errorf("%w: %s", errDuplicateValidator, vdr.NodeID)
+		}
+		indices[vdr.NodeID] = i
+		nodeIDs.Add(vdr.NodeID)
+
+		totalWeight, err = math.Add64(totalWeight, vdr.Weight)
+		if err != nil {
+			return nil, err
+		}
+	}
+	// The quorum is reached once signedWeight * quorumDen >=
+	// totalWeight * quorumNum.
+	requiredWeight, err := math.Mul64(totalWeight, quorumNum)
+	if err != nil {
+		return nil, err
+	}
+
+	requestBytes, err := build(&SignatureRequest{Message: msg})
+	if err != nil {
+		return nil, err
+	}
+
+	requestID := a.config.NextRequestID()
+	request := &pendingRequest{
+		outstanding: ids.NewNodeIDSet(len(vdrs)),
+		results:     make(chan signatureResult, len(vdrs)),
+	}
+	request.outstanding.Union(nodeIDs)
+
+	a.lock.Lock()
+	a.pending[requestID] = request
+	a.lock.Unlock()
+
+	defer func() {
+		a.lock.Lock()
+		delete(a.pending, requestID)
+		a.lock.Unlock()
+	}()
+
+	// The sender may modify the provided set, so [nodeIDs] is not used after
+	// this call.
+	if err := a.config.Sender.SendAppRequest(ctx, nodeIDs, requestID, requestBytes); err != nil {
+		return nil, err
+	}
+
+	var (
+		signers      = &Bits{}
+		signatures   = make([]*bls.Signature, 0, len(vdrs))
+		signedWeight uint64
+	)
+	for received := 0; received < len(vdrs); received++ {
+		var result signatureResult
+		select {
+		case result = <-request.results:
+		case <-ctx.Done():
+			return a.result(signatures, signers, signedWeight, totalWeight, requiredWeight, quorumDen)
+		}
+		if result.signature == nil {
+			continue
+		}
+
+		index := indices[result.nodeID]
+		vdr := vdrs[index]
+		if !bls.Verify(vdr.PublicKey, result.signature, msg) {
+			a.config.Log.Debug("dropping invalid signature",
+				zap.Stringer("nodeID", result.nodeID),
+				zap.Uint32("requestID", requestID),
+			)
+			continue
+		}
+
+		signers.Add(index)
+		signatures = append(signatures, result.signature)
+		signedWeight, err = math.Add64(signedWeight, vdr.Weight)
+		if err != nil {
+			return nil, err
+		}
+
+		if hasQuorum(signedWeight, requiredWeight, quorumDen) {
+			break
+		}
+	}
+	return a.result(signatures, signers, signedWeight, totalWeight, requiredWeight, quorumDen)
+}
+
+func (a *aggregator) result(
+	signatures []*bls.Signature,
+	signers *Bits,
+	signedWeight uint64,
+	totalWeight uint64,
+	requiredWeight uint64,
+	quorumDen uint64,
+) (*Result, error) {
+	if !hasQuorum(signedWeight, requiredWeight, quorumDen) {
+		return nil, fmt.Errorf("%w: signed weight %d out of total weight %d",
+			errInsufficientWeight,
+			signedWeight,
+			totalWeight,
+		)
+	}
+
+	signature, err := bls.AggregateSignatures(signatures)
+	if err != nil {
+		return nil, err
+	}
+	return &Result{
+		Signature:    signature,
+		Signers:      signers,
+		SignedWeight: signedWeight,
+		TotalWeight:  totalWeight,
+	}, nil
+}
+
+func (a *aggregator) AppResponse(_ context.Context, nodeID ids.NodeID, requestID uint32, responseBytes []byte) bool {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	request, ok := a.pending[requestID]
+	if !ok {
+		return false
+	}
+	if !request.outstanding.Contains(nodeID) {
+		// Either this node wasn't asked for a signature or it already
+		// responded.
+		return true
+	}
+	request.outstanding.Remove(nodeID)
+
+	result := signatureResult{nodeID: nodeID}
+	response := SignatureResponse{}
+	if err := parse(responseBytes, &response); err != nil {
+		a.config.Log.Debug("failed to parse signature response",
+			zap.Stringer("nodeID", nodeID),
+			zap.Uint32("requestID", requestID),
+			zap.Error(err),
+		)
+	} else if signature, err := bls.SignatureFromBytes(response.Signature); err != nil {
+		a.config.Log.Debug("failed to parse signature",
+			zap.Stringer("nodeID", nodeID),
+			zap.Uint32("requestID", requestID),
+			zap.Error(err),
+		)
+	} else {
+		result.signature = signature
+	}
+
+	// [results] has capacity for a result from every requested node, so this
+	// never blocks.
+	request.results <- result
+	return true
+}
+
+func (a *aggregator) AppRequestFailed(_ context.Context, nodeID ids.NodeID, requestID uint32, appErr *common.AppError) bool {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	request, ok := a.pending[requestID]
+	if !ok {
+		return false
+	}
+	if request.outstanding.Contains(nodeID) {
+		a.config.Log.Debug("failed to get signature",
+			zap.Stringer("nodeID", nodeID),
+			zap.Uint32("requestID", requestID),
+			zap.Error(appErr),
+		)
+		request.outstanding.Remove(nodeID)
+		request.results <- signatureResult{nodeID: nodeID}
+	}
+	return true
+}
+
+func hasQuorum(signedWeight, requiredWeight, quorumDen uint64) bool {
+	weight, err := math.Mul64(signedWeight, quorumDen)
+	// If the multiplication overflowed, [weight] is certainly larger than
+	// [requiredWeight], which didn't overflow.
+	return err != nil || weight >= requiredWeight
+}
@@ -0,0 +1,195 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package aggregator
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/utils/crypto/bls"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+var errRefused = errors.New("refused")
+
+type testNetwork struct {
+	aggregator Aggregator
+	handlers   map[ids.NodeID]*Handler
+	offline    ids.NodeIDSet
+}
+
+func newTestNetwork(t *testing.T, weights []uint64) (*testNetwork, []*Validator) {
+	require := require.New(t)
+
+	network := &testNetwork{
+		handlers: make(map[ids.NodeID]*Handler),
+	}
+
+	requestID := uint32(0)
+	sender := &common.SenderTest{T: t}
+	sender.SendAppRequestF = func(ctx context.Context, nodeIDs ids.NodeIDSet, requestID uint32, request []byte) error {
+		for nodeID := range nodeIDs {
+			nodeID := nodeID
+			handler, ok := network.handlers[nodeID]
+			online := ok && !network.offline.Contains(nodeID)
+			go func() {
+				if !online {
+					network.aggregator.AppRequestFailed(ctx, nodeID, requestID, common.ErrTimeout)
+					return
+				}
+				_ = handler.AppRequest(ctx, nodeID, requestID, request)
+			}()
+		}
+		return nil
+	}
+	network.aggregator = New(Config{
+		Log:    logging.NoLog{},
+		Sender: sender,
+		NextRequestID: func() uint32 {
+			requestID++
+			return requestID
+		},
+	})
+
+	vdrs := make([]*Validator, len(weights))
+	for i, weight := range weights {
+		sk, err := bls.NewSecretKey()
+		require.NoError(err)
+
+		nodeID := ids.GenerateTestNodeID()
+		handlerSender := &common.SenderTest{T: t}
+		handlerSender.SendAppResponseF = func(ctx context.Context, _ ids.NodeID, requestID uint32, response []byte) error {
+			network.aggregator.AppResponse(ctx, nodeID, requestID, response)
+			return nil
+		}
+		handlerSender.SendAppErrorF = func(ctx context.Context, _ ids.NodeID, requestID uint32, errorCode int32, errorMessage string) error {
+			network.aggregator.AppRequestFailed(ctx, nodeID, requestID, &common.AppError{
+				Code:    errorCode,
+				Message: errorMessage,
+			})
+			return nil
+		}
+		network.handlers[nodeID] = &Handler{
+			Log:    logging.NoLog{},
+			Sender: handlerSender,
+			Key:    sk,
+		}
+		vdrs[i] = &Validator{
+			NodeID:    nodeID,
+			PublicKey: bls.PublicFromSecretKey(sk),
+			Weight:    weight,
+		}
+	}
+	return network, vdrs
+}
+
+func TestAggregateAllSigners(t *testing.T) {
+	require := require.New(t)
+
+	network, vdrs := newTestNetwork(t, []uint64{1, 2, 3})
+	msg := []byte("hello")
+
+	result, err := network.aggregator.Aggregate(context.Background(), msg, vdrs, 1, 1)
+	require.NoError(err)
+	require.Equal(uint64(6), result.SignedWeight)
+	require.Equal(uint64(6), result.TotalWeight)
+	require.Equal(3, result.Signers.Len())
+
+	pks := make([]*bls.PublicKey, 0, len(vdrs))
+	for i, vdr := range vdrs {
+		require.True(result.Signers.Contains(i))
+		pks = append(pks, vdr.PublicKey)
+	}
+	aggPK, err := bls.AggregatePublicKeys(pks)
+	require.NoError(err)
+	require.True(bls.Verify(aggPK, result.Signature, msg))
+}
+
+func TestAggregatePartialQuorum(t *testing.T) {
+	require := require.New(t)
+
+	network, vdrs := newTestNetwork(t, []uint64{1, 1, 2})
+	network.offline.Add(vdrs[0].NodeID)
+	msg := []byte("hello")
+
+	result, err := network.aggregator.Aggregate(context.Background(), msg, vdrs, 2, 3)
+	require.NoError(err)
+	require.False(result.Signers.Contains(0))
+	require.Equal(uint64(3), result.SignedWeight)
+
+	bits := BitsFromBytes(result.Signers.Bytes())
+	require.False(bits.Contains(0))
+	require.True(bits.Contains(1))
+	require.True(bits.Contains(2))
+}
+
+func TestAggregateInsufficientWeight(t *testing.T) {
+	require := require.New(t)
+
+	network, vdrs := newTestNetwork(t, []uint64{1, 1, 2})
+	network.offline.Add(vdrs[2].NodeID)
+	network.handlers[vdrs[1].NodeID].Verify = func(context.Context, []byte) error {
+		return errRefused
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := network.aggregator.Aggregate(ctx, []byte("hello"), vdrs, 2, 3)
+	require.ErrorIs(err, errInsufficientWeight)
+}
+
+func TestAggregateRefusalIsReported(t *testing.T) {
+	require := require.New(t)
+
+	network, vdrs := newTestNetwork(t, []uint64{1, 1})
+	handler := network.handlers[vdrs[1].NodeID]
+	handler.Verify = func(context.Context, []byte) error {
+		return errRefused
+	}
+	var refusal *common.AppError
+	forward := handler.Sender.(*common.SenderTest).SendAppErrorF
+	handler.Sender.(*common.SenderTest).SendAppErrorF = func(ctx context.Context, nodeID ids.NodeID, requestID uint32, errorCode int32, errorMessage string) error {
+		refusal = &common.AppError{
+			Code:    errorCode,
+			Message: errorMessage,
+		}
+		return forward(ctx, nodeID, requestID, errorCode, errorMessage)
+	}
+
+	// The refusal is reported as a failed request, so the aggregation fails
+	// without waiting for [ctx] to be done.
+	_, err := network.aggregator.Aggregate(context.Background(), []byte("hello"), vdrs, 1, 1)
+	require.ErrorIs(err, errInsufficientWeight)
+	require.ErrorIs(refusal, ErrSignatureRefused)
+}
+
+func TestAggregateInvalidArguments(t *testing.T) {
+	require := require.New(t)
+
+	network, vdrs := newTestNetwork(t, []uint64{1})
+
+	_, err := network.aggregator.Aggregate(context.Background(), nil, nil, 1, 1)
+	require.ErrorIs(err, errNoValidators)
+
+	_, err = network.aggregator.Aggregate(context.Background(), nil, vdrs, 2, 1)
+	require.ErrorIs(err, errInvalidQuorum)
+
+	_, err = network.aggregator.Aggregate(context.Background(), nil, []*Validator{vdrs[0], vdrs[0]}, 1, 1)
+	require.ErrorIs(err, errDuplicateValidator)
+}
+
+func TestAggregatorIgnoresUnknownRequests(t *testing.T) {
+	require := require.New(t)
+
+	network, _ := newTestNetwork(t, nil)
+
+	require.False(network.aggregator.AppResponse(context.Background(), ids.GenerateTestNodeID(), 1, nil))
+	require.False(network.aggregator.AppRequestFailed(context.Background(), ids.GenerateTestNodeID(), 1, common.ErrTimeout))
+}
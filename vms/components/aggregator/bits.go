@@ -0,0 +1,47 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package aggregator
+
+import (
+	"math/big"
+)
+
+// Bits is a bitset identifying which validators contributed to an aggregate
+// signature. Bit i refers to the i-th validator provided to Aggregate.
+type Bits struct {
+	bits big.Int
+}
+
+// BitsFromBytes parses the big-endian format of the bitset.
+func BitsFromBytes(b []byte) *Bits {
+	bits := &Bits{}
+	bits.bits.SetBytes(b)
+	return bits
+}
+
+// Add marks the validator at index [i] as a signer.
+func (b *Bits) Add(i int) {
+	b.bits.SetBit(&b.bits, i, 1)
+}
+
+// Contains returns true if the validator at index [i] is a signer.
+func (b *Bits) Contains(i int) bool {
+	return b.bits.Bit(i) == 1
+}
+
+// Len returns the number of signers in the bitset.
+func (b *Bits) Len() int {
+	count := 0
+	for i := 0; i < b.bits.BitLen(); i++ {
+		if b.bits.Bit(i) == 1 {
+			count++
+		}
+	}
+	return count
+}
+
+// Bytes returns the big-endian format of the bitset.
+func (b *Bits) Bytes() []byte {
+	return b.bits.Bytes()
+}
@@ -0,0 +1,35 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package aggregator
+
+import (
+	"github.com/ava-labs/avalanchego/codec"
+	"github.com/ava-labs/avalanchego/codec/linearcodec"
+	"github.com/ava-labs/avalanchego/utils/units"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+)
+
+const (
+	codecVersion   uint16 = 0
+	maxMessageSize        = 256 * units.KiB
+	maxSliceLen           = maxMessageSize
+)
+
+// Codec does serialization and deserialization
+var c codec.Manager
+
+func init() {
+	c = codec.NewManager(maxMessageSize)
+	lc := linearcodec.NewCustomMaxLength(maxSliceLen)
+
+	errs := wrappers.Errs{}
+	errs.Add(
+		lc.RegisterType(&SignatureRequest{}),
+		lc.RegisterType(&SignatureResponse{}),
+		c.RegisterCodec(codecVersion, lc),
+	)
+	if errs.Errored() {
+		panic(errs.Err)
+	}
+}
@@ -0,0 +1,17 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package aggregator collects BLS signatures over a message from a weighted
+// quorum of a subnet's validators and aggregates them.
+//
+// The node runs a Service for each chain, which VMs reach through
+// snow.Context.SignatureAggregator. The signature requests are AppRequests of
+// the chain, which the node shares between the VM and its services. A node
+// only signs a message if its instance of the VM approves it.
+//
+// The caller supplies the validators to collect signatures from, along with
+// their BLS public keys. The validator state of the node only reports the
+// weights of the validators, so a VM typically reads the weights at the
+// P-chain height it aggregates for from snow.Context.ValidatorState and the
+// public keys from the P-chain.
+package aggregator
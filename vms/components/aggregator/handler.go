@@ -0,0 +1,78 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package aggregator
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/utils/crypto/bls"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+var (
+	// ErrInvalidRequest is sent in response to a signature request that can't
+	// be parsed.
+	ErrInvalidRequest = &common.AppError{
+		Code:    1,
+		Message: "invalid signature request",
+	}
+	// ErrSignatureRefused is sent in response to a signature request for a
+	// message that fails verification.
+	ErrSignatureRefused = &common.AppError{
+		Code:    2,
+		Message: "refused to sign message",
+	}
+)
+
+// Handler responds to SignatureRequests issued by an Aggregator by signing
+// the requested message with this node's BLS key.
+type Handler struct {
+	Log    logging.Logger
+	Sender common.AppSender
+	Key    *bls.SecretKey
+
+	// Verify returns a non-nil error if this node should refuse to sign
+	// [msg]. If nil, every message is signed.
+	Verify func(ctx context.Context, msg []byte) error
+}
+
+// AppRequest handles a signature request from [nodeID]. Requests that can't be
+// parsed or that fail verification are answered with ErrInvalidRequest or
+// ErrSignatureRefused, so that the requester doesn't wait for a signature that
+// will never be sent.
+func (h *Handler) AppRequest(ctx context.Context, nodeID ids.NodeID, requestID uint32, requestBytes []byte) error {
+	request := SignatureRequest{}
+	if err := parse(requestBytes, &request); err != nil {
+		h.Log.Debug("failed to parse signature request",
+			zap.Stringer("nodeID", nodeID),
+			zap.Uint32("requestID", requestID),
+			zap.Error(err),
+		)
+		return h.Sender.SendAppError(ctx, nodeID, requestID, ErrInvalidRequest.Code, ErrInvalidRequest.Message)
+	}
+
+	if h.Verify != nil {
+		if err := h.Verify(ctx, request.Message); err != nil {
+			h.Log.Debug("refusing to sign message",
+				zap.Stringer("nodeID", nodeID),
+				zap.Uint32("requestID", requestID),
+				zap.Error(err),
+			)
+			return h.Sender.SendAppError(ctx, nodeID, requestID, ErrSignatureRefused.Code, ErrSignatureRefused.Message)
+		}
+	}
+
+	signature := bls.Sign(h.Key, request.Message)
+	responseBytes, err := build(&SignatureResponse{
+		Signature: bls.SignatureToBytes(signature),
+	})
+	if err != nil {
+		return err
+	}
+	return h.Sender.SendAppResponse(ctx, nodeID, requestID, responseBytes)
+}
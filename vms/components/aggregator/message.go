@@ -0,0 +1,36 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package aggregator
+
+import (
+	"errors"
+)
+
+var errUnexpectedCodecVersion = errors.New("unexpected codec version")
+
+// SignatureRequest asks a validator to sign [Message] with its BLS key.
+type SignatureRequest struct {
+	Message []byte `serialize:"true"`
+}
+
+// SignatureResponse carries the compressed BLS signature of the requested
+// message.
+type SignatureResponse struct {
+	Signature []byte `serialize:"true"`
+}
+
+func parse(bytes []byte, msg interface{}) error {
+	version, err := c.Unmarshal(bytes, msg)
+	if err != nil {
+		return err
+	}
+	if version != codecVersion {
+		return errUnexpectedCodecVersion
+	}
+	return nil
+}
+
+func build(msg interface{}) ([]byte, error) {
+	return c.Marshal(codecVersion, msg)
+}
@@ -0,0 +1,107 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package aggregator
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/snow/networking/appmux"
+	"github.com/ava-labs/avalanchego/utils/crypto/bls"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+// ServiceID is the ID of the signature aggregation service of the node.
+const ServiceID appmux.ServiceID = 0
+
+var (
+	_ snow.SignatureAggregator = (*Service)(nil)
+	_ appmux.Service           = (*Service)(nil)
+
+	errNoSigningKey = errors.New("node has no BLS key")
+)
+
+// Service is the signature aggregation service of the node for a chain. It
+// collects the signatures requested by the chain's VM from the services of the
+// other nodes, and signs the messages the chain's VM approves when the
+// services of the other nodes request it.
+type Service struct {
+	aggregator    Aggregator
+	handler       *Handler
+	nextRequestID uint32
+}
+
+// NewService registers the service of a chain with the chain's [mux]. The
+// messages approved by [verify] are signed with [key]. If [key] is nil, no
+// message is signed.
+func NewService(
+	log logging.Logger,
+	mux *appmux.Mux,
+	key *bls.SecretKey,
+	verify func(ctx context.Context, msg []byte) error,
+) (*Service, error) {
+	s := &Service{}
+	sender, err := mux.Register(ServiceID, s)
+	if err != nil {
+		return nil, err
+	}
+
+	if key == nil {
+		verify = func(context.Context, []byte) error {
+			return errNoSigningKey
+		}
+	}
+	s.aggregator = New(Config{
+		Log:    log,
+		Sender: sender,
+		NextRequestID: func() uint32 {
+			return atomic.AddUint32(&s.nextRequestID, 1)
+		},
+	})
+	s.handler = &Handler{
+		Log:    log,
+		Sender: sender,
+		Key:    key,
+		Verify: verify,
+	}
+	return s, nil
+}
+
+func (s *Service) AggregateSignatures(
+	ctx context.Context,
+	msg []byte,
+	vdrs []*snow.SigningValidator,
+	quorumNum uint64,
+	quorumDen uint64,
+) (*snow.AggregateSignature, error) {
+	result, err := s.aggregator.Aggregate(ctx, msg, vdrs, quorumNum, quorumDen)
+	if err != nil {
+		return nil, err
+	}
+	return &snow.AggregateSignature{
+		Signature:    result.Signature,
+		Signers:      result.Signers.Bytes(),
+		SignedWeight: result.SignedWeight,
+		TotalWeight:  result.TotalWeight,
+	}, nil
+}
+
+func (s *Service) AppRequest(ctx context.Context, nodeID ids.NodeID, requestID uint32, _ time.Time, request []byte) error {
+	return s.handler.AppRequest(ctx, nodeID, requestID, request)
+}
+
+func (s *Service) AppResponse(ctx context.Context, nodeID ids.NodeID, requestID uint32, response []byte) error {
+	s.aggregator.AppResponse(ctx, nodeID, requestID, response)
+	return nil
+}
+
+func (s *Service) AppRequestFailed(ctx context.Context, nodeID ids.NodeID, requestID uint32, appErr *common.AppError) error {
+	s.aggregator.AppRequestFailed(ctx, nodeID, requestID, appErr)
+	return nil
+}
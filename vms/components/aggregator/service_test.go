@@ -0,0 +1,143 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package aggregator
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/snow/networking/appmux"
+	"github.com/ava-labs/avalanchego/utils/crypto/bls"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+var errUnexpectedVMMessage = errors.New("unexpected VM message")
+
+// noVM fails on the App messages that are passed to the VM, as the nodes of
+// the test only run the signature aggregation service.
+type noVM struct{}
+
+func (noVM) AppRequest(context.Context, ids.NodeID, uint32, time.Time, []byte) error {
+	return errUnexpectedVMMessage
+}
+
+func (noVM) AppResponse(context.Context, ids.NodeID, uint32, []byte) error {
+	return errUnexpectedVMMessage
+}
+
+func (noVM) AppRequestFailedWithError(context.Context, ids.NodeID, uint32, *common.AppError) error {
+	return errUnexpectedVMMessage
+}
+
+type testNode struct {
+	nodeID  ids.NodeID
+	mux     *appmux.Mux
+	service *Service
+}
+
+// newTestNodes returns nodes connected to each other, which sign the messages
+// that [verify] approves with their keys in [keys].
+func newTestNodes(t *testing.T, keys []*bls.SecretKey, verify func(ctx context.Context, msg []byte) error) []*testNode {
+	require := require.New(t)
+
+	nodes := make(map[ids.NodeID]*testNode, len(keys))
+	testNodes := make([]*testNode, len(keys))
+	for i, key := range keys {
+		node := &testNode{
+			nodeID: ids.GenerateTestNodeID(),
+		}
+		sender := &common.SenderTest{T: t}
+		sender.SendAppRequestF = func(ctx context.Context, nodeIDs ids.NodeIDSet, requestID uint32, request []byte) error {
+			for nodeID := range nodeIDs {
+				peer := nodes[nodeID]
+				go func() {
+					require.NoError(peer.mux.AppRequest(ctx, noVM{}, node.nodeID, requestID, time.Time{}, request))
+				}()
+			}
+			return nil
+		}
+		sender.SendAppResponseF = func(ctx context.Context, nodeID ids.NodeID, requestID uint32, response []byte) error {
+			go func() {
+				require.NoError(nodes[nodeID].mux.AppResponse(ctx, noVM{}, node.nodeID, requestID, response))
+			}()
+			return nil
+		}
+		sender.SendAppErrorF = func(ctx context.Context, nodeID ids.NodeID, requestID uint32, errorCode int32, errorMessage string) error {
+			go func() {
+				require.NoError(nodes[nodeID].mux.AppRequestFailed(ctx, noVM{}, node.nodeID, requestID, &common.AppError{
+					Code:    errorCode,
+					Message: errorMessage,
+				}))
+			}()
+			return nil
+		}
+
+		node.mux = appmux.New(logging.NoLog{}, sender)
+		service, err := NewService(logging.NoLog{}, node.mux, key, verify)
+		require.NoError(err)
+		node.service = service
+
+		nodes[node.nodeID] = node
+		testNodes[i] = node
+	}
+	return testNodes
+}
+
+func TestServiceAggregateSignatures(t *testing.T) {
+	require := require.New(t)
+
+	approved := []byte("approved")
+	keys := make([]*bls.SecretKey, 4)
+	for i := range keys {
+		key, err := bls.NewSecretKey()
+		require.NoError(err)
+		keys[i] = key
+	}
+	// The last node has no BLS key, so it can't sign.
+	keys[3] = nil
+	nodes := newTestNodes(t, keys, func(_ context.Context, msg []byte) error {
+		if string(msg) != string(approved) {
+			return errRefused
+		}
+		return nil
+	})
+
+	vdrs := make([]*snow.SigningValidator, len(nodes))
+	for i, node := range nodes {
+		vdrs[i] = &snow.SigningValidator{
+			NodeID: node.nodeID,
+			Weight: 1,
+		}
+		if keys[i] != nil {
+			vdrs[i].PublicKey = bls.PublicFromSecretKey(keys[i])
+		}
+	}
+
+	signature, err := nodes[0].service.AggregateSignatures(context.Background(), approved, vdrs, 3, 4)
+	require.NoError(err)
+	require.Equal(uint64(3), signature.SignedWeight)
+	require.Equal(uint64(4), signature.TotalWeight)
+
+	signers := BitsFromBytes(signature.Signers)
+	require.Equal(3, signers.Len())
+	require.False(signers.Contains(3))
+	aggPK, err := bls.AggregatePublicKeys([]*bls.PublicKey{
+		vdrs[0].PublicKey,
+		vdrs[1].PublicKey,
+		vdrs[2].PublicKey,
+	})
+	require.NoError(err)
+	require.True(bls.Verify(aggPK, signature.Signature, approved))
+
+	// Every node refuses to sign the messages that their VM doesn't approve.
+	_, err = nodes[0].service.AggregateSignatures(context.Background(), []byte("unapproved"), vdrs, 1, 4)
+	require.ErrorIs(err, errInsufficientWeight)
+}
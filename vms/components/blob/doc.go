@@ -0,0 +1,12 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package blob transfers large, content-addressed blobs, such as state sync
+// snapshots or upgrade bundles, between the nodes of a chain.
+//
+// The node runs a Service for each chain, which VMs reach through
+// snow.Context.Blobs. A blob is stored as a manifest and a set of chunks, and
+// is fetched chunk by chunk with AppRequests of the chain, which the node
+// shares between the VM and its services. The bandwidth of the transfers is
+// capped across all the chains of the node.
+package blob
@@ -0,0 +1,359 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package blob
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"golang.org/x/sync/errgroup"
+
+	"golang.org/x/time/rate"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/utils/hashing"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+// maxAttemptsPerPeer bounds the number of times a manifest or a chunk is
+// requested, relative to the number of peers it's fetched from.
+const maxAttemptsPerPeer = 3
+
+var (
+	_ Fetcher = (*fetcher)(nil)
+
+	errNoPeers           = errors.New("no peers to fetch from")
+	errTooManyAttempts   = errors.New("too many failed attempts")
+	errInvalidMaxPending = errors.New("max outstanding requests must be positive")
+	errWrongManifest     = errors.New("manifest doesn't match the requested blob")
+	errWrongChunk        = errors.New("chunk doesn't match the manifest")
+)
+
+// Fetcher downloads blobs from the Handlers of other nodes using AppRequests.
+//
+// The VM using a Fetcher must forward AppResponse and AppRequestFailed
+// messages to it. Messages for requests the Fetcher didn't issue are reported
+// as unhandled so that the VM can process them itself.
+type Fetcher interface {
+	// Fetch downloads the blob [blobID] from [peers] into the Store and
+	// returns it.
+	//
+	// Chunks are persisted as soon as they're received, and chunks that are
+	// already in the Store aren't requested, so a fetch that failed or was
+	// cancelled is resumed by calling Fetch again.
+	Fetch(ctx context.Context, blobID ids.ID, peers []ids.NodeID) ([]byte, error)
+
+	// AppResponse returns true if [requestID] was issued by this fetcher.
+	AppResponse(ctx context.Context, nodeID ids.NodeID, requestID uint32, response []byte) bool
+
+	// AppRequestFailed returns true if [requestID] was issued by this fetcher.
+	AppRequestFailed(ctx context.Context, nodeID ids.NodeID, requestID uint32, appErr *common.AppError) bool
+}
+
+type Config struct {
+	Log    logging.Logger
+	Sender common.AppSender
+	// NextRequestID returns a request ID that isn't used by any other
+	// outstanding AppRequest of the VM. It's called concurrently.
+	NextRequestID func() uint32
+	Store         *Store
+
+	// Limiter caps the number of chunk bytes requested per second. If nil,
+	// downloads aren't capped.
+	Limiter *rate.Limiter
+	// MaxOutstanding is the maximum number of chunk requests a single fetch
+	// has outstanding at once.
+	MaxOutstanding int
+	// RetryDelay is how long to wait before retrying a request that failed.
+	RetryDelay time.Duration
+}
+
+type result struct {
+	response []byte
+	appErr   *common.AppError
+}
+
+type pendingRequest struct {
+	nodeID  ids.NodeID
+	results chan result
+}
+
+type fetcher struct {
+	config Config
+
+	lock    sync.Mutex
+	pending map[uint32]*pendingRequest
+}
+
+func NewFetcher(config Config) Fetcher {
+	return &fetcher{
+		config:  config,
+		pending: make(map[uint32]*pendingRequest),
+	}
+}
+
+func (f *fetcher) Fetch(ctx context.Context, blobID ids.ID, peers []ids.NodeID) ([]byte, error) {
+	if f.config.MaxOutstanding <= 0 {
+		return nil, errInvalidMaxPending
+	}
+	if len(peers) == 0 {
+		return nil, errNoPeers
+	}
+	p := newPeerSet(peers)
+
+	m, err := f.config.Store.GetManifest(blobID)
+	if err == database.ErrNotFound {
+		m, err = f.fetchManifest(ctx, blobID, p)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	missing, err := f.config.Store.Missing(m)
+	if err != nil {
+		return nil, err
+	}
+
+	indices := make(chan int, len(missing))
+	for _, index := range missing {
+		indices <- index
+	}
+	close(indices)
+
+	numWorkers := f.config.MaxOutstanding
+	if numWorkers > len(missing) {
+		numWorkers = len(missing)
+	}
+	eg, egCtx := errgroup.WithContext(ctx)
+	for i := 0; i < numWorkers; i++ {
+		eg.Go(func() error {
+			for index := range indices {
+				if err := f.fetchChunk(egCtx, m, index, p); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	}
+	if err := eg.Wait(); err != nil {
+		return nil, fmt.Errorf("failed to fetch blob %s: %w", blobID, err)
+	}
+	return f.config.Store.Get(blobID)
+}
+
+func (f *fetcher) fetchManifest(ctx context.Context, blobID ids.ID, p *peerSet) (*Manifest, error) {
+	requestBytes, err := buildRequest(&ManifestRequest{BlobID: blobID})
+	if err != nil {
+		return nil, err
+	}
+
+	var m *Manifest
+	err = f.fetch(ctx, p, requestBytes, func(responseBytes []byte) error {
+		parsed, err := ParseManifest(responseBytes)
+		if err != nil {
+			return err
+		}
+		if parsed.ID() != blobID {
+			return errWrongManifest
+		}
+		m = parsed
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch manifest of %s: %w", blobID, err)
+	}
+	return m, f.config.Store.PutManifest(m)
+}
+
+func (f *fetcher) fetchChunk(ctx context.Context, m *Manifest, index int, p *peerSet) error {
+	requestBytes, err := buildRequest(&ChunkRequest{
+		BlobID: m.ID(),
+		Index:  uint32(index),
+	})
+	if err != nil {
+		return err
+	}
+
+	if f.config.Limiter != nil {
+		if err := f.config.Limiter.WaitN(ctx, tokens(f.config.Limiter, m.chunkLen(index))); err != nil {
+			return err
+		}
+	}
+
+	var chunk []byte
+	err = f.fetch(ctx, p, requestBytes, func(responseBytes []byte) error {
+		response := ChunkResponse{}
+		if err := parse(responseBytes, &response); err != nil {
+			return err
+		}
+		if hashing.ComputeHash256Array(response.Chunk) != m.Chunks[index] {
+			return errWrongChunk
+		}
+		chunk = response.Chunk
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to fetch chunk %d: %w", index, err)
+	}
+	_, err = f.config.Store.PutChunk(chunk)
+	return err
+}
+
+// fetch sends [requestBytes] to [p] one peer at a time until a response is
+// accepted by [verify]. Peers that send invalid responses, or that don't have
+// the blob, aren't asked again.
+func (f *fetcher) fetch(ctx context.Context, p *peerSet, requestBytes []byte, verify func([]byte) error) error {
+	for attempt := 0; ; attempt++ {
+		nodeID, ok := p.next()
+		if !ok {
+			return errNoPeers
+		}
+		if attempt >= p.maxAttempts {
+			return errTooManyAttempts
+		}
+
+		response, appErr, err := f.request(ctx, nodeID, requestBytes)
+		if err != nil {
+			return err
+		}
+		if appErr == nil {
+			err := verify(response)
+			if err == nil {
+				return nil
+			}
+			f.config.Log.Debug("dropping peer that sent an invalid response",
+				zap.Stringer("nodeID", nodeID),
+				zap.Error(err),
+			)
+			p.remove(nodeID)
+			continue
+		}
+
+		f.config.Log.Debug("blob request failed",
+			zap.Stringer("nodeID", nodeID),
+			zap.Error(appErr),
+		)
+		if appErr.Is(ErrUnknownBlob) || appErr.Is(ErrInvalidRequest) {
+			p.remove(nodeID)
+			continue
+		}
+		if f.config.RetryDelay > 0 {
+			timer := time.NewTimer(f.config.RetryDelay)
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				timer.Stop()
+				return ctx.Err()
+			}
+		}
+	}
+}
+
+// request sends [requestBytes] to [nodeID] and waits for the response. If the
+// request fails, the returned AppError is non-nil.
+func (f *fetcher) request(ctx context.Context, nodeID ids.NodeID, requestBytes []byte) ([]byte, *common.AppError, error) {
+	requestID := f.config.NextRequestID()
+	request := &pendingRequest{
+		nodeID:  nodeID,
+		results: make(chan result, 1),
+	}
+
+	f.lock.Lock()
+	f.pending[requestID] = request
+	f.lock.Unlock()
+
+	defer func() {
+		f.lock.Lock()
+		delete(f.pending, requestID)
+		f.lock.Unlock()
+	}()
+
+	nodeIDs := ids.NewNodeIDSet(1)
+	nodeIDs.Add(nodeID)
+	if err := f.config.Sender.SendAppRequest(ctx, nodeIDs, requestID, requestBytes); err != nil {
+		return nil, nil, err
+	}
+
+	select {
+	case result := <-request.results:
+		return result.response, result.appErr, nil
+	case <-ctx.Done():
+		return nil, nil, ctx.Err()
+	}
+}
+
+func (f *fetcher) AppResponse(_ context.Context, nodeID ids.NodeID, requestID uint32, responseBytes []byte) bool {
+	return f.deliver(nodeID, requestID, result{response: responseBytes})
+}
+
+func (f *fetcher) AppRequestFailed(_ context.Context, nodeID ids.NodeID, requestID uint32, appErr *common.AppError) bool {
+	return f.deliver(nodeID, requestID, result{appErr: appErr})
+}
+
+func (f *fetcher) deliver(nodeID ids.NodeID, requestID uint32, r result) bool {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	request, ok := f.pending[requestID]
+	if !ok {
+		return false
+	}
+	if request.nodeID == nodeID {
+		// Only the first result of a request is delivered, so [results]
+		// never blocks.
+		delete(f.pending, requestID)
+		request.results <- r
+	}
+	return true
+}
+
+// peerSet is the set of peers a blob is fetched from. It's shared by all the
+// chunk requests of a fetch.
+type peerSet struct {
+	maxAttempts int
+
+	lock  sync.Mutex
+	peers []ids.NodeID
+	index int
+}
+
+func newPeerSet(peers []ids.NodeID) *peerSet {
+	return &peerSet{
+		maxAttempts: maxAttemptsPerPeer * len(peers),
+		peers:       append([]ids.NodeID(nil), peers...),
+	}
+}
+
+// next returns the peers in round-robin order.
+func (p *peerSet) next() (ids.NodeID, bool) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if len(p.peers) == 0 {
+		return ids.EmptyNodeID, false
+	}
+	p.index %= len(p.peers)
+	nodeID := p.peers[p.index]
+	p.index++
+	return nodeID, true
+}
+
+func (p *peerSet) remove(nodeID ids.NodeID) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	for i, peer := range p.peers {
+		if peer == nodeID {
+			p.peers = append(p.peers[:i], p.peers[i+1:]...)
+			return
+		}
+	}
+}
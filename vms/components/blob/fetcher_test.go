@@ -0,0 +1,235 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package blob
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"golang.org/x/time/rate"
+
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+const testChunkSize = 16
+
+type testNetwork struct {
+	fetcher  Fetcher
+	store    *Store
+	handlers map[ids.NodeID]*Handler
+	// responses sent by corrupt nodes are modified before being delivered
+	corrupt ids.NodeIDSet
+
+	lock     sync.Mutex
+	requests int
+}
+
+func newTestNetwork(t *testing.T, numPeers int) (*testNetwork, []ids.NodeID) {
+	network := &testNetwork{
+		store:    NewStore(memdb.New()),
+		handlers: make(map[ids.NodeID]*Handler),
+	}
+
+	requestID := uint32(0)
+	sender := &common.SenderTest{T: t}
+	sender.SendAppRequestF = func(ctx context.Context, nodeIDs ids.NodeIDSet, requestID uint32, request []byte) error {
+		network.lock.Lock()
+		network.requests++
+		network.lock.Unlock()
+
+		for nodeID := range nodeIDs {
+			nodeID := nodeID
+			handler, ok := network.handlers[nodeID]
+			go func() {
+				if !ok {
+					network.fetcher.AppRequestFailed(ctx, nodeID, requestID, common.ErrTimeout)
+					return
+				}
+				_ = handler.AppRequest(ctx, nodeID, requestID, request)
+			}()
+		}
+		return nil
+	}
+	network.fetcher = NewFetcher(Config{
+		Log:    logging.NoLog{},
+		Sender: sender,
+		NextRequestID: func() uint32 {
+			return atomic.AddUint32(&requestID, 1)
+		},
+		Store:          network.store,
+		MaxOutstanding: 4,
+	})
+
+	peers := make([]ids.NodeID, numPeers)
+	for i := range peers {
+		nodeID := ids.GenerateTestNodeID()
+		handlerSender := &common.SenderTest{T: t}
+		handlerSender.SendAppResponseF = func(ctx context.Context, _ ids.NodeID, requestID uint32, response []byte) error {
+			if network.corrupt.Contains(nodeID) {
+				response = append([]byte(nil), response...)
+				response[len(response)-1] ^= 1
+			}
+			network.fetcher.AppResponse(ctx, nodeID, requestID, response)
+			return nil
+		}
+		handlerSender.SendAppErrorF = func(ctx context.Context, _ ids.NodeID, requestID uint32, errorCode int32, errorMessage string) error {
+			network.fetcher.AppRequestFailed(ctx, nodeID, requestID, &common.AppError{
+				Code:    errorCode,
+				Message: errorMessage,
+			})
+			return nil
+		}
+		network.handlers[nodeID] = &Handler{
+			Log:    logging.NoLog{},
+			Sender: handlerSender,
+			Store:  NewStore(memdb.New()),
+		}
+		peers[i] = nodeID
+	}
+	return network, peers
+}
+
+func testBlob(size int) []byte {
+	blob := make([]byte, size)
+	for i := range blob {
+		blob[i] = byte(i)
+	}
+	return blob
+}
+
+func TestFetch(t *testing.T) {
+	require := require.New(t)
+
+	network, peers := newTestNetwork(t, 3)
+	blob := testBlob(10*testChunkSize + 3)
+
+	var blobID ids.ID
+	for _, nodeID := range peers {
+		m, err := network.handlers[nodeID].Store.Put(blob, testChunkSize)
+		require.NoError(err)
+		blobID = m.ID()
+	}
+
+	fetched, err := network.fetcher.Fetch(context.Background(), blobID, peers)
+	require.NoError(err)
+	require.Equal(blob, fetched)
+
+	stored, err := network.store.Get(blobID)
+	require.NoError(err)
+	require.Equal(blob, stored)
+}
+
+func TestFetchSkipsPeersWithoutBlob(t *testing.T) {
+	require := require.New(t)
+
+	network, peers := newTestNetwork(t, 3)
+	blob := testBlob(5 * testChunkSize)
+
+	m, err := network.handlers[peers[1]].Store.Put(blob, testChunkSize)
+	require.NoError(err)
+
+	fetched, err := network.fetcher.Fetch(context.Background(), m.ID(), peers)
+	require.NoError(err)
+	require.Equal(blob, fetched)
+}
+
+func TestFetchDropsCorruptPeers(t *testing.T) {
+	require := require.New(t)
+
+	network, peers := newTestNetwork(t, 2)
+	blob := testBlob(5 * testChunkSize)
+
+	var blobID ids.ID
+	for _, nodeID := range peers {
+		m, err := network.handlers[nodeID].Store.Put(blob, testChunkSize)
+		require.NoError(err)
+		blobID = m.ID()
+	}
+	network.corrupt.Add(peers[0])
+
+	fetched, err := network.fetcher.Fetch(context.Background(), blobID, peers)
+	require.NoError(err)
+	require.Equal(blob, fetched)
+}
+
+func TestFetchUnavailable(t *testing.T) {
+	require := require.New(t)
+
+	network, peers := newTestNetwork(t, 2)
+	blob := testBlob(5 * testChunkSize)
+
+	m, err := NewStore(memdb.New()).Put(blob, testChunkSize)
+	require.NoError(err)
+
+	_, err = network.fetcher.Fetch(context.Background(), m.ID(), peers)
+	require.ErrorIs(err, errNoPeers)
+
+	_, err = network.fetcher.Fetch(context.Background(), m.ID(), nil)
+	require.ErrorIs(err, errNoPeers)
+}
+
+func TestFetchResumes(t *testing.T) {
+	require := require.New(t)
+
+	network, peers := newTestNetwork(t, 1)
+	blob := testBlob(5 * testChunkSize)
+
+	m, err := network.handlers[peers[0]].Store.Put(blob, testChunkSize)
+	require.NoError(err)
+
+	// Simulate an interrupted fetch that received the manifest and the first
+	// two chunks.
+	require.NoError(network.store.PutManifest(m))
+	_, err = network.store.PutChunk(blob[:testChunkSize])
+	require.NoError(err)
+	_, err = network.store.PutChunk(blob[testChunkSize : 2*testChunkSize])
+	require.NoError(err)
+
+	missing, err := network.store.Missing(m)
+	require.NoError(err)
+	require.Equal([]int{2, 3, 4}, missing)
+
+	fetched, err := network.fetcher.Fetch(context.Background(), m.ID(), peers)
+	require.NoError(err)
+	require.Equal(blob, fetched)
+	require.Equal(3, network.requests)
+}
+
+func TestFetchBandwidthExceeded(t *testing.T) {
+	require := require.New(t)
+
+	network, peers := newTestNetwork(t, 1)
+	blob := testBlob(5 * testChunkSize)
+
+	handler := network.handlers[peers[0]]
+	m, err := handler.Store.Put(blob, testChunkSize)
+	require.NoError(err)
+
+	// The handler is only willing to upload two chunks.
+	handler.Limiter = rate.NewLimiter(rate.Every(time.Hour), 2*testChunkSize)
+
+	_, err = network.fetcher.Fetch(context.Background(), m.ID(), peers)
+	require.ErrorIs(err, errTooManyAttempts)
+
+	missing, err := network.store.Missing(m)
+	require.NoError(err)
+	require.Len(missing, 3)
+}
+
+func TestUnexpectedResponse(t *testing.T) {
+	require := require.New(t)
+
+	network, _ := newTestNetwork(t, 0)
+	nodeID := ids.GenerateTestNodeID()
+	require.False(network.fetcher.AppResponse(context.Background(), nodeID, 1, nil))
+	require.False(network.fetcher.AppRequestFailed(context.Background(), nodeID, 1, common.ErrTimeout))
+}
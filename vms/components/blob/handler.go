@@ -0,0 +1,139 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package blob
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"golang.org/x/time/rate"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+var (
+	// ErrInvalidRequest is sent in response to a blob request that can't be
+	// parsed.
+	ErrInvalidRequest = &common.AppError{
+		Code:    1,
+		Message: "invalid blob request",
+	}
+	// ErrUnknownBlob is sent in response to a request for a manifest or a
+	// chunk that this node doesn't have.
+	ErrUnknownBlob = &common.AppError{
+		Code:    2,
+		Message: "unknown blob",
+	}
+	// ErrBandwidthExceeded is sent in response to a chunk request that would
+	// exceed the upload limit of this node.
+	ErrBandwidthExceeded = &common.AppError{
+		Code:    3,
+		Message: "bandwidth limit exceeded",
+	}
+)
+
+// Handler serves the manifests and chunks of the blobs in [Store] to the
+// Fetchers of other nodes.
+type Handler struct {
+	Log    logging.Logger
+	Sender common.AppSender
+	Store  *Store
+
+	// Limiter caps the number of chunk bytes sent per second. Chunk requests
+	// that would exceed the limit are refused with ErrBandwidthExceeded, so
+	// that the requester can ask another peer. If nil, uploads aren't capped.
+	Limiter *rate.Limiter
+}
+
+// AppRequest handles a blob request from [nodeID]. Every request is answered,
+// either with the requested data or with one of the errors above, so that the
+// requester doesn't wait for a response that will never be sent.
+func (h *Handler) AppRequest(ctx context.Context, nodeID ids.NodeID, requestID uint32, requestBytes []byte) error {
+	request, err := parseRequest(requestBytes)
+	if err != nil {
+		h.Log.Debug("failed to parse blob request",
+			zap.Stringer("nodeID", nodeID),
+			zap.Uint32("requestID", requestID),
+			zap.Error(err),
+		)
+		return h.sendError(ctx, nodeID, requestID, ErrInvalidRequest)
+	}
+
+	var responseBytes []byte
+	switch request := request.(type) {
+	case *ManifestRequest:
+		responseBytes, err = h.manifest(request)
+	case *ChunkRequest:
+		responseBytes, err = h.chunk(request)
+	default:
+		err = ErrInvalidRequest
+	}
+	if appErr, ok := err.(*common.AppError); ok {
+		h.Log.Debug("failed to serve blob request",
+			zap.Stringer("nodeID", nodeID),
+			zap.Uint32("requestID", requestID),
+			zap.Error(appErr),
+		)
+		return h.sendError(ctx, nodeID, requestID, appErr)
+	}
+	if err != nil {
+		return err
+	}
+	return h.Sender.SendAppResponse(ctx, nodeID, requestID, responseBytes)
+}
+
+func (h *Handler) manifest(request *ManifestRequest) ([]byte, error) {
+	m, err := h.Store.GetManifest(request.BlobID)
+	if err == database.ErrNotFound {
+		return nil, ErrUnknownBlob
+	}
+	if err != nil {
+		return nil, err
+	}
+	return m.Bytes(), nil
+}
+
+func (h *Handler) chunk(request *ChunkRequest) ([]byte, error) {
+	m, err := h.Store.GetManifest(request.BlobID)
+	if err == database.ErrNotFound {
+		return nil, ErrUnknownBlob
+	}
+	if err != nil {
+		return nil, err
+	}
+	if int(request.Index) >= len(m.Chunks) {
+		return nil, ErrInvalidRequest
+	}
+
+	chunk, err := h.Store.GetChunk(m.Chunks[request.Index])
+	if err == database.ErrNotFound {
+		return nil, ErrUnknownBlob
+	}
+	if err != nil {
+		return nil, err
+	}
+	if h.Limiter != nil && !h.Limiter.AllowN(time.Now(), tokens(h.Limiter, len(chunk))) {
+		return nil, ErrBandwidthExceeded
+	}
+	return build(&ChunkResponse{Chunk: chunk})
+}
+
+func (h *Handler) sendError(ctx context.Context, nodeID ids.NodeID, requestID uint32, appErr *common.AppError) error {
+	return h.Sender.SendAppError(ctx, nodeID, requestID, appErr.Code, appErr.Message)
+}
+
+// tokens returns the number of tokens to take from [limiter] for [n] bytes.
+// Chunks larger than the burst of [limiter] take the whole burst, so that they
+// can still be transferred.
+func tokens(limiter *rate.Limiter, n int) int {
+	if burst := limiter.Burst(); n > burst {
+		return burst
+	}
+	return n
+}
@@ -0,0 +1,110 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package blob
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/hashing"
+	"github.com/ava-labs/avalanchego/utils/units"
+)
+
+const (
+	// DefaultChunkSize is the chunk size used by Store.Put if none is
+	// provided.
+	DefaultChunkSize = 512 * units.KiB
+	// MaxChunkSize is the largest chunk size a manifest may specify. Chunks
+	// must fit in a single AppResponse.
+	MaxChunkSize = 1 * units.MiB
+)
+
+var (
+	errInvalidChunkSize = errors.New("invalid chunk size")
+	errWrongChunkCount  = errors.New("wrong number of chunks")
+)
+
+// Manifest describes how a blob is split into chunks. A blob is identified by
+// the hash of its manifest, and every chunk is identified by its own hash, so
+// that all the data received from peers can be verified.
+type Manifest struct {
+	// Size is the length of the blob in bytes.
+	Size uint64 `serialize:"true"`
+	// ChunkSize is the length of every chunk except the last one, which may
+	// be shorter.
+	ChunkSize uint32 `serialize:"true"`
+	// Chunks are the IDs of the chunks of the blob, in order.
+	Chunks []ids.ID `serialize:"true"`
+
+	id    ids.ID
+	bytes []byte
+}
+
+// ParseManifest parses and verifies a manifest.
+func ParseManifest(bytes []byte) (*Manifest, error) {
+	m := &Manifest{}
+	if err := parse(bytes, m); err != nil {
+		return nil, err
+	}
+	if err := m.Verify(); err != nil {
+		return nil, err
+	}
+	m.bytes = bytes
+	m.id = hashing.ComputeHash256Array(bytes)
+	return m, nil
+}
+
+func newManifest(size uint64, chunkSize uint32, chunks []ids.ID) (*Manifest, error) {
+	m := &Manifest{
+		Size:      size,
+		ChunkSize: chunkSize,
+		Chunks:    chunks,
+	}
+	if err := m.Verify(); err != nil {
+		return nil, err
+	}
+	bytes, err := build(m)
+	if err != nil {
+		return nil, err
+	}
+	m.bytes = bytes
+	m.id = hashing.ComputeHash256Array(bytes)
+	return m, nil
+}
+
+// ID returns the ID of the blob described by this manifest.
+func (m *Manifest) ID() ids.ID {
+	return m.id
+}
+
+// Bytes returns the serialized manifest.
+func (m *Manifest) Bytes() []byte {
+	return m.bytes
+}
+
+// Verify returns nil if the chunks of the manifest cover exactly [Size]
+// bytes.
+func (m *Manifest) Verify() error {
+	if m.ChunkSize == 0 || m.ChunkSize > MaxChunkSize {
+		return fmt.Errorf("%w: %d", errInvalidChunkSize, m.ChunkSize)
+	}
+	expected := m.Size / uint64(m.ChunkSize)
+	if m.Size%uint64(m.ChunkSize) != 0 {
+		expected++
+	}
+	if uint64(len(m.Chunks)) != expected {
+		return fmt.Errorf("%w: expected %d but got %d", errWrongChunkCount, expected, len(m.Chunks))
+	}
+	return nil
+}
+
+// chunkLen returns the expected length of the chunk at [index].
+func (m *Manifest) chunkLen(index int) int {
+	start := uint64(index) * uint64(m.ChunkSize)
+	if remaining := m.Size - start; remaining < uint64(m.ChunkSize) {
+		return int(remaining)
+	}
+	return int(m.ChunkSize)
+}
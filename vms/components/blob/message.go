@@ -0,0 +1,68 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package blob
+
+import (
+	"errors"
+
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+var (
+	_ Request = (*ManifestRequest)(nil)
+	_ Request = (*ChunkRequest)(nil)
+
+	errUnexpectedCodecVersion = errors.New("unexpected codec version")
+)
+
+// Request is a request for a part of a blob.
+type Request interface {
+	isRequest()
+}
+
+// ManifestRequest asks a peer for the manifest of [BlobID]. The response is
+// the serialized Manifest.
+type ManifestRequest struct {
+	BlobID ids.ID `serialize:"true"`
+}
+
+func (*ManifestRequest) isRequest() {}
+
+// ChunkRequest asks a peer for the chunk at [Index] of the manifest of
+// [BlobID].
+type ChunkRequest struct {
+	BlobID ids.ID `serialize:"true"`
+	Index  uint32 `serialize:"true"`
+}
+
+func (*ChunkRequest) isRequest() {}
+
+// ChunkResponse carries the requested chunk.
+type ChunkResponse struct {
+	Chunk []byte `serialize:"true"`
+}
+
+func parseRequest(bytes []byte) (Request, error) {
+	var request Request
+	return request, parse(bytes, &request)
+}
+
+func buildRequest(request Request) ([]byte, error) {
+	return build(&request)
+}
+
+func parse(bytes []byte, msg interface{}) error {
+	version, err := c.Unmarshal(bytes, msg)
+	if err != nil {
+		return err
+	}
+	if version != codecVersion {
+		return errUnexpectedCodecVersion
+	}
+	return nil
+}
+
+func build(msg interface{}) ([]byte, error) {
+	return c.Marshal(codecVersion, msg)
+}
@@ -0,0 +1,110 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package blob
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/snow/networking/appmux"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+const (
+	// ServiceID is the ID of the blob service of the node.
+	ServiceID appmux.ServiceID = 1
+
+	serviceMaxOutstanding = 4
+	serviceRetryDelay     = time.Second
+)
+
+var (
+	_ snow.BlobService = (*Service)(nil)
+	_ appmux.Service   = (*Service)(nil)
+)
+
+// Service is the blob service of the node for a chain. It serves the blobs in
+// its Store to the services of the other nodes, and fetches blobs from them
+// on behalf of the chain's VM.
+type Service struct {
+	store         *Store
+	handler       *Handler
+	fetcher       Fetcher
+	nextRequestID uint32
+}
+
+// NewService registers the service of a chain with the chain's [mux]. The
+// bytes of the chunks the service uploads and downloads are capped by
+// [uploadLimiter] and [downloadLimiter], which may be shared with the services
+// of other chains. A nil limiter doesn't cap the bandwidth.
+func NewService(
+	log logging.Logger,
+	mux *appmux.Mux,
+	store *Store,
+	uploadLimiter *rate.Limiter,
+	downloadLimiter *rate.Limiter,
+) (*Service, error) {
+	s := &Service{
+		store: store,
+	}
+	sender, err := mux.Register(ServiceID, s)
+	if err != nil {
+		return nil, err
+	}
+
+	s.handler = &Handler{
+		Log:     log,
+		Sender:  sender,
+		Store:   store,
+		Limiter: uploadLimiter,
+	}
+	s.fetcher = NewFetcher(Config{
+		Log:    log,
+		Sender: sender,
+		NextRequestID: func() uint32 {
+			return atomic.AddUint32(&s.nextRequestID, 1)
+		},
+		Store:          store,
+		Limiter:        downloadLimiter,
+		MaxOutstanding: serviceMaxOutstanding,
+		RetryDelay:     serviceRetryDelay,
+	})
+	return s, nil
+}
+
+func (s *Service) Put(_ context.Context, blob []byte) (ids.ID, error) {
+	m, err := s.store.Put(blob, 0)
+	if err != nil {
+		return ids.Empty, err
+	}
+	return m.ID(), nil
+}
+
+func (s *Service) Get(_ context.Context, blobID ids.ID) ([]byte, error) {
+	return s.store.Get(blobID)
+}
+
+func (s *Service) Fetch(ctx context.Context, blobID ids.ID, peers []ids.NodeID) ([]byte, error) {
+	return s.fetcher.Fetch(ctx, blobID, peers)
+}
+
+func (s *Service) AppRequest(ctx context.Context, nodeID ids.NodeID, requestID uint32, _ time.Time, request []byte) error {
+	return s.handler.AppRequest(ctx, nodeID, requestID, request)
+}
+
+func (s *Service) AppResponse(ctx context.Context, nodeID ids.NodeID, requestID uint32, response []byte) error {
+	s.fetcher.AppResponse(ctx, nodeID, requestID, response)
+	return nil
+}
+
+func (s *Service) AppRequestFailed(ctx context.Context, nodeID ids.NodeID, requestID uint32, appErr *common.AppError) error {
+	s.fetcher.AppRequestFailed(ctx, nodeID, requestID, appErr)
+	return nil
+}
@@ -0,0 +1,121 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package blob
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/snow/networking/appmux"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+var errUnexpectedVMMessage = errors.New("unexpected VM message")
+
+// noVM fails on the App messages that are passed to the VM, as the nodes of
+// the test only run the blob service.
+type noVM struct{}
+
+func (noVM) AppRequest(context.Context, ids.NodeID, uint32, time.Time, []byte) error {
+	return errUnexpectedVMMessage
+}
+
+func (noVM) AppResponse(context.Context, ids.NodeID, uint32, []byte) error {
+	return errUnexpectedVMMessage
+}
+
+func (noVM) AppRequestFailedWithError(context.Context, ids.NodeID, uint32, *common.AppError) error {
+	return errUnexpectedVMMessage
+}
+
+type testNode struct {
+	nodeID  ids.NodeID
+	mux     *appmux.Mux
+	service *Service
+}
+
+// newTestNodes returns [numNodes] nodes connected to each other.
+func newTestNodes(t *testing.T, numNodes int) []*testNode {
+	require := require.New(t)
+
+	nodes := make(map[ids.NodeID]*testNode, numNodes)
+	testNodes := make([]*testNode, numNodes)
+	for i := range testNodes {
+		node := &testNode{
+			nodeID: ids.GenerateTestNodeID(),
+		}
+		sender := &common.SenderTest{T: t}
+		sender.SendAppRequestF = func(ctx context.Context, nodeIDs ids.NodeIDSet, requestID uint32, request []byte) error {
+			for nodeID := range nodeIDs {
+				peer := nodes[nodeID]
+				go func() {
+					require.NoError(peer.mux.AppRequest(ctx, noVM{}, node.nodeID, requestID, time.Time{}, request))
+				}()
+			}
+			return nil
+		}
+		sender.SendAppResponseF = func(ctx context.Context, nodeID ids.NodeID, requestID uint32, response []byte) error {
+			go func() {
+				require.NoError(nodes[nodeID].mux.AppResponse(ctx, noVM{}, node.nodeID, requestID, response))
+			}()
+			return nil
+		}
+		sender.SendAppErrorF = func(ctx context.Context, nodeID ids.NodeID, requestID uint32, errorCode int32, errorMessage string) error {
+			go func() {
+				require.NoError(nodes[nodeID].mux.AppRequestFailed(ctx, noVM{}, node.nodeID, requestID, &common.AppError{
+					Code:    errorCode,
+					Message: errorMessage,
+				}))
+			}()
+			return nil
+		}
+
+		node.mux = appmux.New(logging.NoLog{}, sender)
+		service, err := NewService(logging.NoLog{}, node.mux, NewStore(memdb.New()), nil, nil)
+		require.NoError(err)
+		node.service = service
+
+		nodes[node.nodeID] = node
+		testNodes[i] = node
+	}
+	return testNodes
+}
+
+func TestServiceFetch(t *testing.T) {
+	require := require.New(t)
+
+	nodes := newTestNodes(t, 3)
+
+	blob := make([]byte, 2*DefaultChunkSize+1)
+	for i := range blob {
+		blob[i] = byte(i)
+	}
+	blobID, err := nodes[0].service.Put(context.Background(), blob)
+	require.NoError(err)
+
+	_, err = nodes[1].service.Get(context.Background(), blobID)
+	require.ErrorIs(err, database.ErrNotFound)
+
+	// Node 2 doesn't have the blob, so it's only fetched from node 0.
+	fetched, err := nodes[1].service.Fetch(context.Background(), blobID, []ids.NodeID{nodes[2].nodeID, nodes[0].nodeID})
+	require.NoError(err)
+	require.Equal(blob, fetched)
+
+	stored, err := nodes[1].service.Get(context.Background(), blobID)
+	require.NoError(err)
+	require.Equal(blob, stored)
+
+	// Node 2 can fetch the blob from node 1 now.
+	fetched, err = nodes[2].service.Fetch(context.Background(), blobID, []ids.NodeID{nodes[1].nodeID})
+	require.NoError(err)
+	require.Equal(blob, fetched)
+}
@@ -0,0 +1,129 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package blob
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/prefixdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/hashing"
+)
+
+var (
+	manifestPrefix = []byte("manifest")
+	chunkPrefix    = []byte("chunk")
+
+	errIncompleteBlob = errors.New("blob is incomplete")
+)
+
+// Store persists blobs as a manifest and a set of content-addressed chunks.
+// Chunks are shared by every blob that contains them.
+type Store struct {
+	manifests database.Database
+	chunks    database.Database
+}
+
+func NewStore(db database.Database) *Store {
+	return &Store{
+		manifests: prefixdb.New(manifestPrefix, db),
+		chunks:    prefixdb.New(chunkPrefix, db),
+	}
+}
+
+// Put splits [blob] into chunks of [chunkSize] bytes, stores it and returns
+// its manifest. If [chunkSize] is 0, DefaultChunkSize is used.
+func (s *Store) Put(blob []byte, chunkSize uint32) (*Manifest, error) {
+	if chunkSize == 0 {
+		chunkSize = DefaultChunkSize
+	}
+	if chunkSize > MaxChunkSize {
+		return nil, fmt.Errorf("%w: %d", errInvalidChunkSize, chunkSize)
+	}
+
+	chunks := make([]ids.ID, 0, (len(blob)+int(chunkSize)-1)/int(chunkSize))
+	for start := 0; start < len(blob); start += int(chunkSize) {
+		end := start + int(chunkSize)
+		if end > len(blob) {
+			end = len(blob)
+		}
+		chunkID, err := s.PutChunk(blob[start:end])
+		if err != nil {
+			return nil, err
+		}
+		chunks = append(chunks, chunkID)
+	}
+
+	m, err := newManifest(uint64(len(blob)), chunkSize, chunks)
+	if err != nil {
+		return nil, err
+	}
+	return m, s.PutManifest(m)
+}
+
+// Get returns the blob [blobID]. If the manifest isn't known,
+// database.ErrNotFound is returned. If any of its chunks are missing, an
+// error is returned.
+func (s *Store) Get(blobID ids.ID) ([]byte, error) {
+	m, err := s.GetManifest(blobID)
+	if err != nil {
+		return nil, err
+	}
+
+	blob := make([]byte, 0, m.Size)
+	for i, chunkID := range m.Chunks {
+		chunk, err := s.GetChunk(chunkID)
+		if err == database.ErrNotFound {
+			return nil, fmt.Errorf("%w: missing chunk %d of %s", errIncompleteBlob, i, blobID)
+		}
+		if err != nil {
+			return nil, err
+		}
+		blob = append(blob, chunk...)
+	}
+	return blob, nil
+}
+
+// GetManifest returns the manifest of [blobID], or database.ErrNotFound.
+func (s *Store) GetManifest(blobID ids.ID) (*Manifest, error) {
+	bytes, err := s.manifests.Get(blobID[:])
+	if err != nil {
+		return nil, err
+	}
+	return ParseManifest(bytes)
+}
+
+// PutManifest stores [m]. Its chunks may be added afterwards.
+func (s *Store) PutManifest(m *Manifest) error {
+	id := m.ID()
+	return s.manifests.Put(id[:], m.Bytes())
+}
+
+// GetChunk returns the chunk [chunkID], or database.ErrNotFound.
+func (s *Store) GetChunk(chunkID ids.ID) ([]byte, error) {
+	return s.chunks.Get(chunkID[:])
+}
+
+// PutChunk stores [chunk] and returns its ID.
+func (s *Store) PutChunk(chunk []byte) (ids.ID, error) {
+	chunkID := hashing.ComputeHash256Array(chunk)
+	return chunkID, s.chunks.Put(chunkID[:], chunk)
+}
+
+// Missing returns the indices of the chunks of [m] that aren't stored.
+func (s *Store) Missing(m *Manifest) ([]int, error) {
+	var missing []int
+	for i, chunkID := range m.Chunks {
+		has, err := s.chunks.Has(chunkID[:])
+		if err != nil {
+			return nil, err
+		}
+		if !has {
+			missing = append(missing, i)
+		}
+	}
+	return missing, nil
+}
@@ -0,0 +1,114 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package blob
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+func TestStorePutGet(t *testing.T) {
+	require := require.New(t)
+
+	s := NewStore(memdb.New())
+	blob := testBlob(3*testChunkSize + 1)
+
+	m, err := s.Put(blob, testChunkSize)
+	require.NoError(err)
+	require.Equal(uint64(len(blob)), m.Size)
+	require.Len(m.Chunks, 4)
+
+	got, err := s.Get(m.ID())
+	require.NoError(err)
+	require.Equal(blob, got)
+
+	parsed, err := ParseManifest(m.Bytes())
+	require.NoError(err)
+	require.Equal(m.ID(), parsed.ID())
+	require.Equal(m.Chunks, parsed.Chunks)
+
+	_, err = s.Get(ids.GenerateTestID())
+	require.ErrorIs(err, database.ErrNotFound)
+}
+
+func TestStoreEmptyBlob(t *testing.T) {
+	require := require.New(t)
+
+	s := NewStore(memdb.New())
+	m, err := s.Put(nil, 0)
+	require.NoError(err)
+	require.Empty(m.Chunks)
+
+	got, err := s.Get(m.ID())
+	require.NoError(err)
+	require.Empty(got)
+}
+
+func TestStoreIncompleteBlob(t *testing.T) {
+	require := require.New(t)
+
+	blob := testBlob(2 * testChunkSize)
+	m, err := NewStore(memdb.New()).Put(blob, testChunkSize)
+	require.NoError(err)
+
+	s := NewStore(memdb.New())
+	require.NoError(s.PutManifest(m))
+
+	missing, err := s.Missing(m)
+	require.NoError(err)
+	require.Equal([]int{0, 1}, missing)
+
+	_, err = s.Get(m.ID())
+	require.ErrorIs(err, errIncompleteBlob)
+}
+
+func TestManifestVerify(t *testing.T) {
+	tests := []struct {
+		name     string
+		manifest *Manifest
+		err      error
+	}{
+		{
+			name: "valid",
+			manifest: &Manifest{
+				Size:      testChunkSize + 1,
+				ChunkSize: testChunkSize,
+				Chunks:    []ids.ID{{}, {}},
+			},
+		},
+		{
+			name: "zero chunk size",
+			manifest: &Manifest{
+				Size: 1,
+			},
+			err: errInvalidChunkSize,
+		},
+		{
+			name: "chunk size too large",
+			manifest: &Manifest{
+				ChunkSize: MaxChunkSize + 1,
+			},
+			err: errInvalidChunkSize,
+		},
+		{
+			name: "missing chunk",
+			manifest: &Manifest{
+				Size:      testChunkSize + 1,
+				ChunkSize: testChunkSize,
+				Chunks:    []ids.ID{{}},
+			},
+			err: errWrongChunkCount,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.ErrorIs(t, test.manifest.Verify(), test.err)
+		})
+	}
+}
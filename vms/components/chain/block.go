@@ -5,11 +5,20 @@ package chain
 
 import (
 	"context"
+	"errors"
+	"fmt"
 
 	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
+	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
 )
 
-var _ snowman.Block = (*BlockWrapper)(nil)
+var (
+	errExpectedBlockWithVerifyContext = errors.New("expected block.WithVerifyContext")
+
+	_ snowman.Block           = (*BlockWrapper)(nil)
+	_ block.WithVerifyContext = (*BlockWrapper)(nil)
+	_ snowman.OracleBlock     = (*BlockWrapper)(nil)
+)
 
 // BlockWrapper wraps a snowman Block while adding a smart caching layer to improve
 // VM performance.
@@ -32,10 +41,64 @@ func (bw *BlockWrapper) Verify(ctx context.Context) error {
 		return err
 	}
 
+	bw.verified()
+	return nil
+}
+
+// ShouldVerifyWithContext returns true if the underlying block accounts for
+// the P-Chain height when verifying itself.
+func (bw *BlockWrapper) ShouldVerifyWithContext(ctx context.Context) (bool, error) {
+	blkWithCtx, ok := bw.Block.(block.WithVerifyContext)
+	if !ok {
+		return false, nil
+	}
+	return blkWithCtx.ShouldVerifyWithContext(ctx)
+}
+
+// VerifyWithContext verifies the underlying block given the P-Chain height
+// provided by the proposervm, and caches it as Verify does.
+func (bw *BlockWrapper) VerifyWithContext(ctx context.Context, blockCtx *block.Context) error {
+	blkWithCtx, ok := bw.Block.(block.WithVerifyContext)
+	if !ok {
+		return fmt.Errorf("%w but got %T", errExpectedBlockWithVerifyContext, bw.Block)
+	}
+	if err := blkWithCtx.VerifyWithContext(ctx, blockCtx); err != nil {
+		return err
+	}
+
+	bw.verified()
+	return nil
+}
+
+// Options returns the options of the underlying block, wrapped and added to the
+// appropriate caching layer. Returns snowman.ErrNotOracle if the underlying
+// block isn't an oracle block.
+func (bw *BlockWrapper) Options(ctx context.Context) ([2]snowman.Block, error) {
+	oracleBlk, ok := bw.Block.(snowman.OracleBlock)
+	if !ok {
+		return [2]snowman.Block{}, snowman.ErrNotOracle
+	}
+	options, err := oracleBlk.Options(ctx)
+	if err != nil {
+		return [2]snowman.Block{}, err
+	}
+	for i, option := range options {
+		// The options may already be cached, such as if they were parsed
+		// before.
+		options[i], err = bw.state.addBuiltBlock(ctx, option)
+		if err != nil {
+			return [2]snowman.Block{}, err
+		}
+	}
+	return options, nil
+}
+
+// verified moves the block from the unverified block cache to
+// [cache.verifiedBlocks].
+func (bw *BlockWrapper) verified() {
 	blkID := bw.ID()
 	bw.state.unverifiedBlocks.Evict(blkID)
 	bw.state.verifiedBlocks[blkID] = bw
-	return nil
 }
 
 // Accept accepts the underlying block, removes it from verifiedBlocks, caches it as a decided
@@ -0,0 +1,82 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ava-labs/avalanchego/cache"
+	"github.com/ava-labs/avalanchego/cache/metercacher"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
+	"github.com/ava-labs/avalanchego/utils/hashing"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+)
+
+// newMeteredCache returns an LRU cache of [size] entries. Along with the hits
+// and misses of the cache, its evictions, its number of entries and the
+// number of bytes it holds, as reported by [sizeF], are reported under
+// [namespace].
+func newMeteredCache(
+	namespace string,
+	registerer prometheus.Registerer,
+	size int,
+	sizeF func(key, value interface{}) int,
+) (cache.Cacher, error) {
+	evictions := prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "evictions",
+		Help:      "# of entries evicted to make room for other entries",
+	})
+	lru := &cache.LRU{
+		Size:  size,
+		SizeF: sizeF,
+		OnEvict: func(interface{}, interface{}) {
+			evictions.Inc()
+		},
+	}
+
+	errs := wrappers.Errs{}
+	errs.Add(
+		registerer.Register(evictions),
+		registerer.Register(prometheus.NewGaugeFunc(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Name:      "len",
+				Help:      "# of entries in the cache",
+			},
+			func() float64 {
+				return float64(lru.Len())
+			},
+		)),
+		registerer.Register(prometheus.NewGaugeFunc(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Name:      "size_bytes",
+				Help:      "Approximate size, in bytes, of the entries in the cache",
+			},
+			func() float64 {
+				return float64(lru.Bytes())
+			},
+		)),
+	)
+	if errs.Errored() {
+		return nil, errs.Err
+	}
+	return metercacher.New(namespace, registerer, lru)
+}
+
+// blockEntrySize is the size of an entry from a block ID to a block.
+func blockEntrySize(_, value interface{}) int {
+	return hashing.HashLen + len(value.(snowman.Block).Bytes())
+}
+
+// idEntrySize is the size of an entry from a block ID to an empty struct.
+func idEntrySize(interface{}, interface{}) int {
+	return hashing.HashLen
+}
+
+// bytesToIDEntrySize is the size of an entry from block bytes to a block ID.
+func bytesToIDEntrySize(key, _ interface{}) int {
+	return len(key.(string)) + hashing.HashLen
+}
@@ -5,24 +5,31 @@ package chain
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/prometheus/client_golang/prometheus"
 
 	"github.com/ava-labs/avalanchego/cache"
-	"github.com/ava-labs/avalanchego/cache/metercacher"
 	"github.com/ava-labs/avalanchego/database"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/snow/choices"
 	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
+	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
 )
 
+var errBatchedGetBlockWrongNumberOfBlocks = errors.New("batchedGetBlock returned different number of blocks than expected")
+
 // State implements an efficient caching layer used to wrap a VM
 // implementation.
 type State struct {
 	// getBlock retrieves a block from the VM's storage. If getBlock returns
 	// a nil error, then the returned block must not have the status Unknown
 	getBlock func(context.Context, ids.ID) (snowman.Block, error)
+	// batchedGetBlock retrieves multiple blocks from the VM's storage. The
+	// i-th returned block corresponds to the i-th requested ID. If it couldn't
+	// be fetched, it's nil and the i-th returned error is why. May be nil.
+	batchedGetBlock func(context.Context, []ids.ID) ([]snowman.Block, []error, error)
 	// unmarshals [b] into a block
 	unmarshalBlock func(context.Context, []byte) (snowman.Block, error)
 	// buildBlock attempts to build a block on top of the currently preferred block
@@ -30,6 +37,9 @@ type State struct {
 	// create an unknown block, and building on top of the preferred block should never yield
 	// a block that has already been decided.
 	buildBlock func(context.Context) (snowman.Block, error)
+	// buildBlockWithContext is buildBlock given the P-Chain height provided by
+	// the proposervm. May be nil.
+	buildBlockWithContext func(context.Context, *block.Context) (snowman.Block, error)
 
 	// getStatus returns the status of the block
 	getStatus func(context.Context, snowman.Block) (choices.Status, error)
@@ -50,6 +60,10 @@ type State struct {
 	// string([byte repr. of block]) --> the block's ID
 	bytesToIDCache    cache.Cacher
 	lastAcceptedBlock *BlockWrapper
+
+	// callsAvoided counts the calls to [getBlock], [batchedGetBlock] and
+	// [unmarshalBlock] that were avoided by answering from the caches
+	callsAvoided prometheus.Counter
 }
 
 // Config defines all of the parameters necessary to initialize State
@@ -62,6 +76,14 @@ type Config struct {
 	UnmarshalBlock     func(context.Context, []byte) (snowman.Block, error)
 	BuildBlock         func(context.Context) (snowman.Block, error)
 	GetBlockIDAtHeight func(context.Context, uint64) (ids.ID, error)
+
+	// BatchedGetBlock is optional. If provided, it's used to fetch all the
+	// cache misses of a BatchedGetBlock call at once.
+	BatchedGetBlock func(context.Context, []ids.ID) ([]snowman.Block, []error, error)
+
+	// BuildBlockWithContext is optional. If provided, it's used to build
+	// blocks when the proposervm provides the P-Chain height.
+	BuildBlockWithContext func(context.Context, *block.Context) (snowman.Block, error)
 }
 
 // Block is an interface wrapping the normal snowman.Block interface to be used in
@@ -113,7 +135,9 @@ func produceGetStatus(s *State, getBlockIDAtHeight func(context.Context, uint64)
 func (s *State) initialize(config *Config) {
 	s.verifiedBlocks = make(map[ids.ID]*BlockWrapper)
 	s.getBlock = config.GetBlock
+	s.batchedGetBlock = config.BatchedGetBlock
 	s.buildBlock = config.BuildBlock
+	s.buildBlockWithContext = config.BuildBlockWithContext
 	s.unmarshalBlock = config.UnmarshalBlock
 	if config.GetBlockIDAtHeight == nil {
 		s.getStatus = func(_ context.Context, blk snowman.Block) (choices.Status, error) {
@@ -136,53 +160,69 @@ func NewState(config *Config) *State {
 		missingBlocks:    &cache.LRU{Size: config.MissingCacheSize},
 		unverifiedBlocks: &cache.LRU{Size: config.UnverifiedCacheSize},
 		bytesToIDCache:   &cache.LRU{Size: config.BytesToIDCacheSize},
+		callsAvoided:     prometheus.NewCounter(prometheus.CounterOpts{}),
 	}
 	c.initialize(config)
 	return c
 }
 
+// NewMeteredState returns a State that reports the effectiveness of its
+// caches. When the VM is a plugin, each call avoided by the caches is an RPC
+// that wasn't made.
 func NewMeteredState(
 	registerer prometheus.Registerer,
 	config *Config,
 ) (*State, error) {
-	decidedCache, err := metercacher.New(
+	decidedCache, err := newMeteredCache(
 		"decided_cache",
 		registerer,
-		&cache.LRU{Size: config.DecidedCacheSize},
+		config.DecidedCacheSize,
+		blockEntrySize,
 	)
 	if err != nil {
 		return nil, err
 	}
-	missingCache, err := metercacher.New(
+	missingCache, err := newMeteredCache(
 		"missing_cache",
 		registerer,
-		&cache.LRU{Size: config.MissingCacheSize},
+		config.MissingCacheSize,
+		idEntrySize,
 	)
 	if err != nil {
 		return nil, err
 	}
-	unverifiedCache, err := metercacher.New(
+	unverifiedCache, err := newMeteredCache(
 		"unverified_cache",
 		registerer,
-		&cache.LRU{Size: config.UnverifiedCacheSize},
+		config.UnverifiedCacheSize,
+		blockEntrySize,
 	)
 	if err != nil {
 		return nil, err
 	}
-	bytesToIDCache, err := metercacher.New(
+	bytesToIDCache, err := newMeteredCache(
 		"bytes_to_id_cache",
 		registerer,
-		&cache.LRU{Size: config.BytesToIDCacheSize},
+		config.BytesToIDCacheSize,
+		bytesToIDEntrySize,
 	)
 	if err != nil {
 		return nil, err
 	}
+	callsAvoided := prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "calls_avoided",
+		Help: "# of calls to the VM that were avoided by answering from the caches",
+	})
+	if err := registerer.Register(callsAvoided); err != nil {
+		return nil, err
+	}
 	c := &State{
 		verifiedBlocks:   make(map[ids.ID]*BlockWrapper),
 		decidedBlocks:    decidedCache,
 		missingBlocks:    missingCache,
 		unverifiedBlocks: unverifiedCache,
 		bytesToIDCache:   bytesToIDCache,
+		callsAvoided:     callsAvoided,
 	}
 	c.initialize(config)
 	return c, nil
@@ -226,10 +266,12 @@ func (s *State) Flush() {
 // GetBlock returns the BlockWrapper as snowman.Block corresponding to [blkID]
 func (s *State) GetBlock(ctx context.Context, blkID ids.ID) (snowman.Block, error) {
 	if blk, ok := s.getCachedBlock(blkID); ok {
+		s.callsAvoided.Inc()
 		return blk, nil
 	}
 
 	if _, ok := s.missingBlocks.Get(blkID); ok {
+		s.callsAvoided.Inc()
 		return nil, database.ErrNotFound
 	}
 
@@ -248,6 +290,83 @@ func (s *State) GetBlock(ctx context.Context, blkID ids.ID) (snowman.Block, erro
 	return s.addBlockOutsideConsensus(ctx, blk)
 }
 
+// BatchedGetBlock returns the BlockWrappers corresponding to [blkIDs]. The i-th
+// returned block corresponds to blkIDs[i]. If it couldn't be fetched, it's nil
+// and the i-th returned error is why, which is database.ErrNotFound if the
+// block isn't known. Cache misses are fetched from the VM in a single batch
+// when the VM supports it.
+func (s *State) BatchedGetBlock(ctx context.Context, blkIDs []ids.ID) ([]snowman.Block, []error, error) {
+	var (
+		blks = make([]snowman.Block, len(blkIDs))
+		errs = make([]error, len(blkIDs))
+		// indices of [blkIDs] that must be fetched from the VM
+		uncachedIndices []int
+		uncachedIDs     []ids.ID
+	)
+	for i, blkID := range blkIDs {
+		if blk, ok := s.getCachedBlock(blkID); ok {
+			blks[i] = blk
+			continue
+		}
+		if _, ok := s.missingBlocks.Get(blkID); ok {
+			errs[i] = database.ErrNotFound
+			continue
+		}
+		uncachedIndices = append(uncachedIndices, i)
+		uncachedIDs = append(uncachedIDs, blkID)
+	}
+	if len(uncachedIDs) == 0 {
+		s.callsAvoided.Inc()
+		return blks, errs, nil
+	}
+
+	if s.batchedGetBlock == nil {
+		// Each cached block avoided a call to [getBlock].
+		s.callsAvoided.Add(float64(len(blkIDs) - len(uncachedIDs)))
+		for _, i := range uncachedIndices {
+			blks[i], errs[i] = s.GetBlock(ctx, blkIDs[i])
+		}
+		return blks, errs, nil
+	}
+
+	fetchedBlks, fetchErrs, err := s.batchedGetBlock(ctx, uncachedIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(fetchedBlks) != len(uncachedIDs) || len(fetchErrs) != len(uncachedIDs) {
+		return nil, nil, errBatchedGetBlockWrongNumberOfBlocks
+	}
+	for j, blk := range fetchedBlks {
+		i := uncachedIndices[j]
+		blkID := blkIDs[i]
+		if err := fetchErrs[j]; err != nil {
+			// Only a block that doesn't exist is a cacheable miss.
+			if err == database.ErrNotFound {
+				s.missingBlocks.Put(blkID, struct{}{})
+			}
+			errs[i] = err
+			continue
+		}
+
+		// [blkIDs] may contain duplicates, in which case the block was
+		// already added to the cache by a previous iteration.
+		if cachedBlk, ok := s.getCachedBlock(blkID); ok {
+			blks[i] = cachedBlk
+			continue
+		}
+
+		// Since this block is not in consensus, addBlockOutsideConsensus
+		// is called to add [blk] to the correct cache.
+		wrappedBlk, err := s.addBlockOutsideConsensus(ctx, blk)
+		if err != nil {
+			errs[i] = err
+			continue
+		}
+		blks[i] = wrappedBlk
+	}
+	return blks, errs, nil
+}
+
 // getCachedBlock checks the caches for [blkID] by priority. Returning
 // true if [blkID] is found in one of the caches.
 func (s *State) getCachedBlock(blkID ids.ID) (snowman.Block, bool) {
@@ -285,6 +404,7 @@ func (s *State) ParseBlock(ctx context.Context, b []byte) (snowman.Block, error)
 		blkID := blkIDIntf.(ids.ID)
 		// See if we have this block cached
 		if cachedBlk, ok := s.getCachedBlock(blkID); ok {
+			s.callsAvoided.Inc()
 			return cachedBlk, nil
 		}
 	}
@@ -322,7 +442,28 @@ func (s *State) BuildBlock(ctx context.Context) (snowman.Block, error) {
 	if err != nil {
 		return nil, err
 	}
+	return s.addBuiltBlock(ctx, blk)
+}
 
+// BuildBlockWithContext attempts to build a new internal Block given the
+// P-Chain height provided by the proposervm, wraps it, and adds it to the
+// appropriate caching layer if successful. If the VM doesn't account for the
+// P-Chain height, the block is built as by BuildBlock.
+func (s *State) BuildBlockWithContext(ctx context.Context, blockCtx *block.Context) (snowman.Block, error) {
+	if s.buildBlockWithContext == nil {
+		return s.BuildBlock(ctx)
+	}
+
+	blk, err := s.buildBlockWithContext(ctx, blockCtx)
+	if err != nil {
+		return nil, err
+	}
+	return s.addBuiltBlock(ctx, blk)
+}
+
+// addBuiltBlock wraps [blk], which was built or is an option of an oracle
+// block, and adds it to the appropriate caching layer.
+func (s *State) addBuiltBlock(ctx context.Context, blk snowman.Block) (snowman.Block, error) {
 	blkID := blk.ID()
 	// Defensive: buildBlock should not return a block that has already been verified.
 	// If it does, make sure to return the existing reference to the block.
@@ -374,6 +515,16 @@ func (s *State) LastAcceptedBlock() *BlockWrapper {
 	return s.lastAcceptedBlock
 }
 
+// VerifiedBlocks returns the blocks that have been verified and are still
+// processing.
+func (s *State) VerifiedBlocks() []*BlockWrapper {
+	blks := make([]*BlockWrapper, 0, len(s.verifiedBlocks))
+	for _, blk := range s.verifiedBlocks {
+		blks = append(blks, blk)
+	}
+	return blks
+}
+
 // LastAcceptedBlockInternal returns the internal snowman.Block that was last accepted
 func (s *State) LastAcceptedBlockInternal() snowman.Block {
 	return s.LastAcceptedBlock().Block
@@ -673,6 +673,83 @@ func TestMeteredCache(t *testing.T) {
 	}
 }
 
+// gatheredValue returns the value of the counter or gauge [name] gathered
+// from [registry].
+func gatheredValue(t *testing.T, registry *prometheus.Registry, name string) float64 {
+	metrics, err := registry.Gather()
+	require.NoError(t, err)
+	for _, metric := range metrics {
+		if metric.GetName() != name {
+			continue
+		}
+		m := metric.GetMetric()[0]
+		if m.Counter != nil {
+			return m.Counter.GetValue()
+		}
+		return m.Gauge.GetValue()
+	}
+	require.FailNow(t, "missing metric", name)
+	return 0
+}
+
+func TestMeteredCacheEffectiveness(t *testing.T) {
+	require := require.New(t)
+
+	registry := prometheus.NewRegistry()
+
+	testBlks := NewTestBlocks(3)
+	genesisBlock := testBlks[0]
+	genesisBlock.SetStatus(choices.Accepted)
+	blk1 := testBlks[1]
+	blk2 := testBlks[2]
+
+	getBlock, parseBlock, getCanonicalBlockID := createInternalBlockFuncs(t, testBlks)
+	chainState, err := NewMeteredState(registry, &Config{
+		DecidedCacheSize:    2,
+		MissingCacheSize:    2,
+		UnverifiedCacheSize: 1,
+		BytesToIDCacheSize:  2,
+		LastAcceptedBlock:   genesisBlock,
+		GetBlock:            getBlock,
+		UnmarshalBlock:      parseBlock,
+		BuildBlock:          cantBuildBlock,
+		GetBlockIDAtHeight:  getCanonicalBlockID,
+	})
+	require.NoError(err)
+
+	// The last accepted block is answered from the decided cache.
+	_, err = chainState.GetBlock(context.Background(), genesisBlock.ID())
+	require.NoError(err)
+	require.Equal(1.0, gatheredValue(t, registry, "calls_avoided"))
+	require.Equal(1.0, gatheredValue(t, registry, "decided_cache_len"))
+	require.Equal(float64(hashing.HashLen+len(genesisBlock.Bytes())), gatheredValue(t, registry, "decided_cache_size_bytes"))
+
+	// A missing block is only requested once.
+	missingID := ids.GenerateTestID()
+	_, err = chainState.GetBlock(context.Background(), missingID)
+	require.ErrorIs(err, database.ErrNotFound)
+	_, err = chainState.GetBlock(context.Background(), missingID)
+	require.ErrorIs(err, database.ErrNotFound)
+	require.Equal(2.0, gatheredValue(t, registry, "calls_avoided"))
+	require.Equal(1.0, gatheredValue(t, registry, "missing_cache_hit"))
+	require.Equal(float64(hashing.HashLen), gatheredValue(t, registry, "missing_cache_size_bytes"))
+
+	// Parsing the same bytes twice unmarshals them once.
+	_, err = chainState.ParseBlock(context.Background(), blk1.Bytes())
+	require.NoError(err)
+	_, err = chainState.ParseBlock(context.Background(), blk1.Bytes())
+	require.NoError(err)
+	require.Equal(3.0, gatheredValue(t, registry, "calls_avoided"))
+	require.Equal(float64(len(blk1.Bytes())+hashing.HashLen), gatheredValue(t, registry, "bytes_to_id_cache_size_bytes"))
+
+	// The unverified cache only holds a single block.
+	_, err = chainState.ParseBlock(context.Background(), blk2.Bytes())
+	require.NoError(err)
+	require.Equal(1.0, gatheredValue(t, registry, "unverified_cache_evictions"))
+	require.Equal(1.0, gatheredValue(t, registry, "unverified_cache_len"))
+	require.Equal(0.0, gatheredValue(t, registry, "bytes_to_id_cache_evictions"))
+}
+
 // Test the bytesToIDCache
 func TestStateBytesToIDCache(t *testing.T) {
 	testBlks := NewTestBlocks(3)
@@ -887,3 +964,195 @@ func TestStateParseTransitivelyAcceptedBlock(t *testing.T) {
 		t.Fatalf("Parsed blk1 reported incorrect height. Expected %d got %d", blk1.Height(), parsedBlk1.Height())
 	}
 }
+
+func TestStateBatchedGetBlock(t *testing.T) {
+	require := require.New(t)
+
+	testBlks := NewTestBlocks(4)
+	genesisBlock := testBlks[0]
+	genesisBlock.SetStatus(choices.Accepted)
+	blk1 := testBlks[1]
+	blk1.SetStatus(choices.Processing)
+	blk2 := testBlks[2]
+	blk2.SetStatus(choices.Processing)
+	// blk3 is unknown
+
+	getBlock, parseBlock, getCanonicalBlockID := createInternalBlockFuncs(t, testBlks)
+	var (
+		requested [][]ids.ID
+		// Error the VM fails to fetch blk2 with
+		blk2Err error
+	)
+	batchedGetBlock := func(ctx context.Context, blkIDs []ids.ID) ([]snowman.Block, []error, error) {
+		requested = append(requested, blkIDs)
+		blks := make([]snowman.Block, len(blkIDs))
+		errs := make([]error, len(blkIDs))
+		for i, blkID := range blkIDs {
+			if blkID == blk2.ID() && blk2Err != nil {
+				errs[i] = blk2Err
+				continue
+			}
+			blks[i], errs[i] = getBlock(ctx, blkID)
+		}
+		return blks, errs, nil
+	}
+	chainState := NewState(&Config{
+		DecidedCacheSize:    2,
+		MissingCacheSize:    2,
+		UnverifiedCacheSize: 2,
+		BytesToIDCacheSize:  2,
+		LastAcceptedBlock:   genesisBlock,
+		GetBlock:            getBlock,
+		UnmarshalBlock:      parseBlock,
+		BuildBlock:          cantBuildBlock,
+		GetBlockIDAtHeight:  getCanonicalBlockID,
+		BatchedGetBlock:     batchedGetBlock,
+	})
+
+	blkIDs := []ids.ID{
+		genesisBlock.ID(),
+		blk1.ID(),
+		blk2.ID(),
+		testBlks[3].ID(),
+		blk1.ID(),
+	}
+	// A block that fails to be fetched doesn't fail the others.
+	blk2Err = errors.New("unexpected error")
+	blks, errs, err := chainState.BatchedGetBlock(context.Background(), blkIDs)
+	require.NoError(err)
+	require.Len(blks, len(blkIDs))
+	require.Len(errs, len(blkIDs))
+
+	// The genesis block is cached, so only the remaining blocks should have
+	// been requested, in a single batch.
+	require.Equal([][]ids.ID{blkIDs[1:]}, requested)
+
+	require.Equal(genesisBlock.ID(), blks[0].ID())
+	require.NoError(errs[0])
+	require.Equal(blk1.ID(), blks[1].ID())
+	require.NoError(errs[1])
+	require.Nil(blks[2])
+	require.ErrorIs(errs[2], blk2Err)
+	require.Nil(blks[3])
+	require.ErrorIs(errs[3], database.ErrNotFound)
+	// Duplicate requests should return the same wrapped block.
+	require.Equal(blks[1], blks[4])
+
+	checkProcessingBlock(t, chainState, blks[1])
+
+	// The failed block isn't cached as missing, so it's requested again.
+	blk2Err = nil
+	requested = nil
+	blks, errs, err = chainState.BatchedGetBlock(context.Background(), blkIDs)
+	require.NoError(err)
+	require.Equal([][]ids.ID{{blk2.ID()}}, requested)
+	require.Equal(blk2.ID(), blks[2].ID())
+	require.NoError(errs[2])
+	checkProcessingBlock(t, chainState, blks[2])
+
+	// Every block is now either cached or known to be missing.
+	requested = nil
+	blks, errs, err = chainState.BatchedGetBlock(context.Background(), blkIDs)
+	require.NoError(err)
+	require.Empty(requested)
+	require.Nil(blks[3])
+	require.ErrorIs(errs[3], database.ErrNotFound)
+}
+
+func TestStateBatchedGetBlockWithoutBatchedGetter(t *testing.T) {
+	require := require.New(t)
+
+	testBlks := NewTestBlocks(3)
+	genesisBlock := testBlks[0]
+	genesisBlock.SetStatus(choices.Accepted)
+	blk1 := testBlks[1]
+	blk1.SetStatus(choices.Processing)
+
+	getBlock, parseBlock, getCanonicalBlockID := createInternalBlockFuncs(t, testBlks)
+	chainState := NewState(&Config{
+		DecidedCacheSize:    2,
+		MissingCacheSize:    2,
+		UnverifiedCacheSize: 2,
+		BytesToIDCacheSize:  2,
+		LastAcceptedBlock:   genesisBlock,
+		GetBlock:            getBlock,
+		UnmarshalBlock:      parseBlock,
+		BuildBlock:          cantBuildBlock,
+		GetBlockIDAtHeight:  getCanonicalBlockID,
+	})
+
+	blks, errs, err := chainState.BatchedGetBlock(context.Background(), []ids.ID{
+		blk1.ID(),
+		testBlks[2].ID(),
+	})
+	require.NoError(err)
+	require.Equal(blk1.ID(), blks[0].ID())
+	require.NoError(errs[0])
+	require.Nil(blks[1])
+	require.ErrorIs(errs[1], database.ErrNotFound)
+
+	checkProcessingBlock(t, chainState, blks[0])
+}
+
+// testOracleBlock is a block whose Options returns [options].
+type testOracleBlock struct {
+	*TestBlock
+
+	options [2]snowman.Block
+}
+
+func (b *testOracleBlock) Options(context.Context) ([2]snowman.Block, error) {
+	return b.options, nil
+}
+
+func TestBlockWrapperOptions(t *testing.T) {
+	require := require.New(t)
+
+	testBlks := NewTestBlocks(4)
+	genesisBlock := testBlks[0]
+	genesisBlock.SetStatus(choices.Accepted)
+	oracleBlk := &testOracleBlock{
+		TestBlock: testBlks[1],
+		options: [2]snowman.Block{
+			NewTestBlock(2, testBlks[1].ID()),
+			NewTestBlock(3, testBlks[1].ID()),
+		},
+	}
+	for _, option := range oracleBlk.options {
+		option.(*TestBlock).SetStatus(choices.Processing)
+	}
+	oracleBlk.SetStatus(choices.Processing)
+
+	getBlock, _, getCanonicalBlockID := createInternalBlockFuncs(t, testBlks)
+	chainState := NewState(&Config{
+		DecidedCacheSize:    2,
+		MissingCacheSize:    2,
+		UnverifiedCacheSize: 2,
+		BytesToIDCacheSize:  2,
+		LastAcceptedBlock:   genesisBlock,
+		GetBlock:            getBlock,
+		UnmarshalBlock: func(context.Context, []byte) (snowman.Block, error) {
+			return oracleBlk, nil
+		},
+		BuildBlock:         cantBuildBlock,
+		GetBlockIDAtHeight: getCanonicalBlockID,
+	})
+
+	blk, err := chainState.ParseBlock(context.Background(), oracleBlk.Bytes())
+	require.NoError(err)
+	options, err := blk.(snowman.OracleBlock).Options(context.Background())
+	require.NoError(err)
+	for i, option := range options {
+		require.IsType(&BlockWrapper{}, option)
+		require.Equal(oracleBlk.options[i].ID(), option.ID())
+
+		// The options are cached, so that they are uniquified.
+		cachedOption, err := chainState.GetBlock(context.Background(), option.ID())
+		require.NoError(err)
+		require.Same(option, cachedOption)
+	}
+
+	// A block that isn't an oracle block has no options.
+	_, err = chainState.LastAcceptedBlock().Options(context.Background())
+	require.Equal(snowman.ErrNotOracle, err)
+}
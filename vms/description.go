@@ -0,0 +1,65 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vms
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/snow/engine/avalanche/vertex"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
+)
+
+var builtinInterfaces = []struct {
+	name       string
+	implements func(common.VM) bool
+}{
+	{"chainVM", func(vm common.VM) bool { _, ok := vm.(block.ChainVM); return ok }},
+	{"dagVM", func(vm common.VM) bool { _, ok := vm.(vertex.DAGVM); return ok }},
+	{"batchedChainVM", func(vm common.VM) bool { _, ok := vm.(block.BatchedChainVM); return ok }},
+	{"heightIndexed", func(vm common.VM) bool { _, ok := vm.(block.HeightIndexedChainVM); return ok }},
+	{"stateSyncable", func(vm common.VM) bool { _, ok := vm.(block.StateSyncableVM); return ok }},
+	{"decoder", func(vm common.VM) bool { _, ok := vm.(block.Decoder); return ok }},
+	{"stateDiffer", func(vm common.VM) bool { _, ok := vm.(block.StateDiffer); return ok }},
+	{"messageVerifier", func(vm common.VM) bool { _, ok := vm.(block.MessageVerifier); return ok }},
+	{"blockContext", func(vm common.VM) bool { _, ok := vm.(block.BuildBlockWithContextChainVM); return ok }},
+}
+
+// Description of how a registered VM is run
+type Description struct {
+	// Version reported by the VM
+	Version string
+	// Path of the plugin binary of the VM. Empty if the VM is built into the
+	// node.
+	Path string
+	// Version of the rpcchainvm protocol spoken by the plugin. 0 if the VM is
+	// built into the node, or if the plugin doesn't report it.
+	ProtocolVersion uint
+	// Names of the optional interfaces the VM supports
+	Interfaces []string
+}
+
+// Describer is implemented by VMs that describe themselves, such as the clients
+// of VM plugins, whose interfaces can't be inferred from their type. The
+// version of the VM doesn't need to be included in the description.
+type Describer interface {
+	Describe(context.Context) (Description, error)
+}
+
+// describe returns the description of [vm], without its version.
+func describe(ctx context.Context, vm common.VM) (Description, error) {
+	if describer, ok := vm.(Describer); ok {
+		return describer.Describe(ctx)
+	}
+
+	interfaces := []string{}
+	for _, builtin := range builtinInterfaces {
+		if builtin.implements(vm) {
+			interfaces = append(interfaces, builtin.name)
+		}
+	}
+	return Description{
+		Interfaces: interfaces,
+	}, nil
+}
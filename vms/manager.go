@@ -15,6 +15,10 @@ import (
 
 var (
 	ErrNotFound = errors.New("not found")
+	// ErrNotDescribed is returned by RegisterFactory if the VM was registered
+	// but couldn't be described. The VM is then described without its
+	// interfaces.
+	ErrNotDescribed = errors.New("couldn't describe vm")
 
 	_ Manager = (*manager)(nil)
 )
@@ -42,7 +46,8 @@ type Manager interface {
 	GetFactory(vmID ids.ID) (Factory, error)
 
 	// Map [vmID] to [factory]. [factory] creates new instances of the vm whose
-	// ID is [vmID]
+	// ID is [vmID]. If the vm is registered but can't be described,
+	// ErrNotDescribed is returned.
 	RegisterFactory(ctx context.Context, vmID ids.ID, factory Factory) error
 
 	// ListFactories returns all the IDs that have had factories registered.
@@ -51,6 +56,10 @@ type Manager interface {
 	// Versions returns the primary alias of the VM mapped to the reported
 	// version of the VM for all the registered VMs that reported versions.
 	Versions() (map[string]string, error)
+
+	// Descriptions returns the descriptions of all the registered VMs that
+	// reported versions.
+	Descriptions() (map[ids.ID]Description, error)
 }
 
 type manager struct {
@@ -65,14 +74,19 @@ type manager struct {
 	// Key: A VM's ID
 	// Value: version the VM returned
 	versions map[ids.ID]string
+
+	// Key: A VM's ID
+	// Value: description of the VM, including its version
+	descriptions map[ids.ID]Description
 }
 
 // NewManager returns an instance of a VM manager
 func NewManager() Manager {
 	return &manager{
-		Aliaser:   ids.NewAliaser(),
-		factories: make(map[ids.ID]Factory),
-		versions:  make(map[ids.ID]string),
+		Aliaser:      ids.NewAliaser(),
+		factories:    make(map[ids.ID]Factory),
+		versions:     make(map[ids.ID]string),
+		descriptions: make(map[ids.ID]Description),
 	}
 }
 
@@ -110,8 +124,18 @@ func (m *manager) RegisterFactory(ctx context.Context, vmID ids.ID, factory Fact
 		return err
 	}
 
+	description, describeErr := describe(ctx, commonVM)
+	description.Version = version
+
 	m.versions[vmID] = version
-	return commonVM.Shutdown(ctx)
+	m.descriptions[vmID] = description
+	if err := commonVM.Shutdown(ctx); err != nil {
+		return err
+	}
+	if describeErr != nil {
+		return fmt.Errorf("%w %q: %s", ErrNotDescribed, vmID, describeErr)
+	}
+	return nil
 }
 
 func (m *manager) ListFactories() ([]ids.ID, error) {
@@ -133,3 +157,11 @@ func (m *manager) Versions() (map[string]string, error) {
 	}
 	return versions, nil
 }
+
+func (m *manager) Descriptions() (map[ids.ID]Description, error) {
+	descriptions := make(map[ids.ID]Description, len(m.descriptions))
+	for vmID, description := range m.descriptions {
+		descriptions[vmID] = description
+	}
+	return descriptions, nil
+}
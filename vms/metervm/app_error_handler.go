@@ -0,0 +1,26 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package metervm
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+)
+
+var (
+	_ common.AppErrorHandler = (*blockVM)(nil)
+	_ common.AppErrorHandler = (*vertexVM)(nil)
+)
+
+// AppRequestFailedWithError is implemented explicitly, rather than promoted
+// like AppRequestFailed, as the wrapped VM may not implement AppErrorHandler.
+func (vm *blockVM) AppRequestFailedWithError(ctx context.Context, nodeID ids.NodeID, requestID uint32, appErr *common.AppError) error {
+	return common.NotifyAppRequestFailed(ctx, vm.ChainVM, nodeID, requestID, appErr)
+}
+
+func (vm *vertexVM) AppRequestFailedWithError(ctx context.Context, nodeID ids.NodeID, requestID uint32, appErr *common.AppError) error {
+	return common.NotifyAppRequestFailed(ctx, vm.DAGVM, nodeID, requestID, appErr)
+}
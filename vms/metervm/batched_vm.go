@@ -55,3 +55,26 @@ func (vm *blockVM) BatchedParseBlock(ctx context.Context, blks [][]byte) ([]snow
 	}
 	return wrappedBlocks, err
 }
+
+func (vm *blockVM) BatchedGetBlock(ctx context.Context, blkIDs []ids.ID) ([]snowman.Block, []error, error) {
+	if vm.bVM == nil {
+		return nil, nil, block.ErrRemoteVMNotImplemented
+	}
+
+	start := vm.clock.Time()
+	blocks, errs, err := vm.bVM.BatchedGetBlock(ctx, blkIDs)
+	end := vm.clock.Time()
+	vm.blockMetrics.batchedGetBlock.Observe(float64(end.Sub(start)))
+
+	wrappedBlocks := make([]snowman.Block, len(blocks))
+	for i, block := range blocks {
+		if block == nil {
+			continue
+		}
+		wrappedBlocks[i] = &meterBlock{
+			Block: block,
+			vm:    vm,
+		}
+	}
+	return wrappedBlocks, errs, err
+}
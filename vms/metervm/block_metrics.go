@@ -26,6 +26,7 @@ type blockMetrics struct {
 	// Batched metrics
 	getAncestors,
 	batchedParseBlock,
+	batchedGetBlock,
 	// Height metrics
 	verifyHeightIndex,
 	getBlockIDAtHeight,
@@ -36,13 +37,17 @@ type blockMetrics struct {
 	parseStateSummary,
 	parseStateSummaryErr,
 	getStateSummary,
-	getStateSummaryErr metric.Averager
+	getStateSummaryErr,
+	// Decoder metrics
+	decodeBlock,
+	decodeBlockErr metric.Averager
 }
 
 func (m *blockMetrics) Initialize(
 	supportsBatchedFetching bool,
 	supportsHeightIndexing bool,
 	supportsStateSync bool,
+	supportsDecoding bool,
 	namespace string,
 	reg prometheus.Registerer,
 ) error {
@@ -63,6 +68,7 @@ func (m *blockMetrics) Initialize(
 	if supportsBatchedFetching {
 		m.getAncestors = newAverager(namespace, "get_ancestors", reg, &errs)
 		m.batchedParseBlock = newAverager(namespace, "batched_parse_block", reg, &errs)
+		m.batchedGetBlock = newAverager(namespace, "batched_get_block", reg, &errs)
 	}
 	if supportsHeightIndexing {
 		m.verifyHeightIndex = newAverager(namespace, "verify_height_index", reg, &errs)
@@ -77,5 +83,9 @@ func (m *blockMetrics) Initialize(
 		m.getStateSummary = newAverager(namespace, "get_state_summary", reg, &errs)
 		m.getStateSummaryErr = newAverager(namespace, "get_state_summary_err", reg, &errs)
 	}
+	if supportsDecoding {
+		m.decodeBlock = newAverager(namespace, "decode_block", reg, &errs)
+		m.decodeBlockErr = newAverager(namespace, "decode_block_err", reg, &errs)
+	}
 	return errs.Err
 }
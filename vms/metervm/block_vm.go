@@ -23,6 +23,10 @@ var (
 	_ block.BatchedChainVM       = (*blockVM)(nil)
 	_ block.HeightIndexedChainVM = (*blockVM)(nil)
 	_ block.StateSyncableVM      = (*blockVM)(nil)
+	_ block.Decoder              = (*blockVM)(nil)
+	_ block.DecisionBatcher      = (*blockVM)(nil)
+	_ block.StateDiffer          = (*blockVM)(nil)
+	_ block.MessageVerifier      = (*blockVM)(nil)
 )
 
 type blockVM struct {
@@ -30,6 +34,10 @@ type blockVM struct {
 	bVM  block.BatchedChainVM
 	hVM  block.HeightIndexedChainVM
 	ssVM block.StateSyncableVM
+	dVM  block.Decoder
+	dbVM block.DecisionBatcher
+	sdVM block.StateDiffer
+	mvVM block.MessageVerifier
 
 	blockMetrics
 	clock mockable.Clock
@@ -39,11 +47,19 @@ func NewBlockVM(vm block.ChainVM) block.ChainVM {
 	bVM, _ := vm.(block.BatchedChainVM)
 	hVM, _ := vm.(block.HeightIndexedChainVM)
 	ssVM, _ := vm.(block.StateSyncableVM)
+	dVM, _ := vm.(block.Decoder)
+	dbVM, _ := vm.(block.DecisionBatcher)
+	sdVM, _ := vm.(block.StateDiffer)
+	mvVM, _ := vm.(block.MessageVerifier)
 	return &blockVM{
 		ChainVM: vm,
 		bVM:     bVM,
 		hVM:     hVM,
 		ssVM:    ssVM,
+		dVM:     dVM,
+		dbVM:    dbVM,
+		sdVM:    sdVM,
+		mvVM:    mvVM,
 	}
 }
 
@@ -63,6 +79,7 @@ func (vm *blockVM) Initialize(
 		vm.bVM != nil,
 		vm.hVM != nil,
 		vm.ssVM != nil,
+		vm.dVM != nil,
 		"",
 		registerer,
 	)
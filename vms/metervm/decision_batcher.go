@@ -0,0 +1,15 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package metervm
+
+import (
+	"context"
+)
+
+func (vm *blockVM) BatchDecisions(ctx context.Context, f func(context.Context) error) error {
+	if vm.dbVM == nil {
+		return f(ctx)
+	}
+	return vm.dbVM.BatchDecisions(ctx, f)
+}
@@ -0,0 +1,45 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package metervm
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/snow/engine/avalanche/vertex"
+	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
+)
+
+func (vm *blockVM) DecodeBlock(ctx context.Context, blkBytes []byte) ([]byte, error) {
+	if vm.dVM == nil {
+		return nil, block.ErrDecoderNotImplemented
+	}
+
+	start := vm.clock.Time()
+	decoded, err := vm.dVM.DecodeBlock(ctx, blkBytes)
+	end := vm.clock.Time()
+	duration := float64(end.Sub(start))
+	if err != nil {
+		vm.blockMetrics.decodeBlockErr.Observe(duration)
+		return nil, err
+	}
+	vm.blockMetrics.decodeBlock.Observe(duration)
+	return decoded, nil
+}
+
+func (vm *vertexVM) DecodeTx(ctx context.Context, txBytes []byte) ([]byte, error) {
+	if vm.dVM == nil {
+		return nil, vertex.ErrDecoderNotImplemented
+	}
+
+	start := vm.clock.Time()
+	decoded, err := vm.dVM.DecodeTx(ctx, txBytes)
+	end := vm.clock.Time()
+	duration := float64(end.Sub(start))
+	if err != nil {
+		vm.vertexMetrics.decodeErr.Observe(duration)
+		return nil, err
+	}
+	vm.vertexMetrics.decode.Observe(duration)
+	return decoded, nil
+}
@@ -0,0 +1,17 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package metervm
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
+)
+
+func (vm *blockVM) VerifyMessage(ctx context.Context, msg []byte) error {
+	if vm.mvVM == nil {
+		return block.ErrMessageVerifierNotImplemented
+	}
+	return vm.mvVM.VerifyMessage(ctx, msg)
+}
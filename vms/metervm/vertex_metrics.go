@@ -19,10 +19,14 @@ type vertexMetrics struct {
 	verify,
 	verifyErr,
 	accept,
-	reject metric.Averager
+	reject,
+	// Decoder metrics
+	decode,
+	decodeErr metric.Averager
 }
 
 func (m *vertexMetrics) Initialize(
+	supportsDecoding bool,
 	namespace string,
 	reg prometheus.Registerer,
 ) error {
@@ -36,5 +40,10 @@ func (m *vertexMetrics) Initialize(
 	m.verifyErr = newAverager(namespace, "verify_tx_err", reg, &errs)
 	m.accept = newAverager(namespace, "accept", reg, &errs)
 	m.reject = newAverager(namespace, "reject", reg, &errs)
+
+	if supportsDecoding {
+		m.decode = newAverager(namespace, "decode_tx", reg, &errs)
+		m.decodeErr = newAverager(namespace, "decode_tx_err", reg, &errs)
+	}
 	return errs.Err
 }
@@ -19,18 +19,22 @@ import (
 )
 
 var (
-	_ vertex.DAGVM = (*vertexVM)(nil)
-	_ snowstorm.Tx = (*meterTx)(nil)
+	_ vertex.DAGVM   = (*vertexVM)(nil)
+	_ vertex.Decoder = (*vertexVM)(nil)
+	_ snowstorm.Tx   = (*meterTx)(nil)
 )
 
 func NewVertexVM(vm vertex.DAGVM) vertex.DAGVM {
+	dVM, _ := vm.(vertex.Decoder)
 	return &vertexVM{
 		DAGVM: vm,
+		dVM:   dVM,
 	}
 }
 
 type vertexVM struct {
 	vertex.DAGVM
+	dVM vertex.Decoder
 	vertexMetrics
 	clock mockable.Clock
 }
@@ -47,7 +51,7 @@ func (vm *vertexVM) Initialize(
 	appSender common.AppSender,
 ) error {
 	registerer := prometheus.NewRegistry()
-	if err := vm.vertexMetrics.Initialize("", registerer); err != nil {
+	if err := vm.vertexMetrics.Initialize(vm.dVM != nil, "", registerer); err != nil {
 		return err
 	}
 
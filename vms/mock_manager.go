@@ -106,6 +106,21 @@ func (mr *MockManagerMockRecorder) Aliases(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Aliases", reflect.TypeOf((*MockManager)(nil).Aliases), arg0)
 }
 
+// Descriptions mocks base method.
+func (m *MockManager) Descriptions() (map[ids.ID]Description, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Descriptions")
+	ret0, _ := ret[0].(map[ids.ID]Description)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Descriptions indicates an expected call of Descriptions.
+func (mr *MockManagerMockRecorder) Descriptions() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Descriptions", reflect.TypeOf((*MockManager)(nil).Descriptions))
+}
+
 // GetFactory mocks base method.
 func (m *MockManager) GetFactory(arg0 ids.ID) (Factory, error) {
 	m.ctrl.T.Helper()
@@ -220,3 +235,18 @@ func (mr *MockManagerMockRecorder) Versions() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Versions", reflect.TypeOf((*MockManager)(nil).Versions))
 }
+
+// WatchAliases mocks base method.
+func (m *MockManager) WatchAliases(arg0 func(ids.AliasUpdate)) (func(), error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WatchAliases", arg0)
+	ret0, _ := ret[0].(func())
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WatchAliases indicates an expected call of WatchAliases.
+func (mr *MockManagerMockRecorder) WatchAliases(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WatchAliases", reflect.TypeOf((*MockManager)(nil).WatchAliases), arg0)
+}
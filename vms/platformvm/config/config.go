@@ -6,6 +6,7 @@ package config
 import (
 	"time"
 
+	"github.com/ava-labs/avalanchego/api/pagination"
 	"github.com/ava-labs/avalanchego/chains"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/snow/engine/common"
@@ -113,6 +114,22 @@ type Config struct {
 	// on recently created subnets (without this, users need to wait for
 	// [recentlyAcceptedWindowTTL] to pass for activation to occur).
 	UseCurrentHeight bool
+
+	// ValidatorSnapshotEpochLength is the number of blocks in an epoch. The
+	// validator sets of the tracked subnets are snapshotted at the first
+	// height of each epoch, so that historical validator sets are
+	// reconstructed from the closest snapshot rather than from the current
+	// validator set. If zero, validator sets aren't snapshotted.
+	ValidatorSnapshotEpochLength uint64
+
+	// StateDiffsEnabled records the changes made to the database at each
+	// height, so that the state diff between two heights can be exported.
+	// Diffs can only start at the height the recording started at.
+	StateDiffsEnabled bool
+
+	// MaxPageSizes overrides the maximum page size of the paginated API
+	// endpoints, e.g. {"platform.getUTXOs": 512}
+	MaxPageSizes pagination.Limits
 }
 
 func (c *Config) IsApricotPhase3Activated(timestamp time.Time) bool {
@@ -15,8 +15,10 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/ava-labs/avalanchego/api"
+	"github.com/ava-labs/avalanchego/api/pagination"
 	"github.com/ava-labs/avalanchego/database"
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/choices"
 	"github.com/ava-labs/avalanchego/utils/constants"
 	"github.com/ava-labs/avalanchego/utils/crypto"
 	"github.com/ava-labs/avalanchego/utils/formatting"
@@ -48,8 +50,16 @@ const (
 	// Minimum amount of delay to allow a transaction to be issued through the
 	// API
 	minAddStakerDelay = 2 * executor.SyncBound
+
+	getUTXOsEndpoint = "platform.getUTXOs"
 )
 
+// MaxPageSizeBounds bounds the pages of UTXOs platform.getUTXOs returns by the
+// number of UTXOs the tx builder fetches at once.
+var MaxPageSizeBounds = pagination.Limits{
+	getUTXOsEndpoint: builder.MaxPageSize,
+}
+
 var (
 	errMissingDecisionBlock     = errors.New("should have a decision block within the past two blocks")
 	errNoSubnetID               = errors.New("argument 'subnetID' not provided")
@@ -416,10 +426,10 @@ func (service *Service) GetUTXOs(_ *http.Request, args *api.GetUTXOsArgs, respon
 		endAddr   ids.ShortID
 		endUTXOID ids.ID
 	)
-	limit := int(args.Limit)
-	if limit <= 0 || builder.MaxPageSize < limit {
-		limit = builder.MaxPageSize
-	}
+	limit := int(pagination.PageSize(
+		uint64(args.Limit),
+		service.vm.MaxPageSizes.MaxPageSize(getUTXOsEndpoint, MaxPageSizeBounds[getUTXOsEndpoint]),
+	))
 	if sourceChain == service.vm.ctx.ChainID {
 		utxos, endAddr, endUTXOID, err = avax.GetPaginatedUTXOs(
 			service.vm.state,
@@ -1988,12 +1998,13 @@ func (service *Service) IssueTx(_ *http.Request, args *api.FormattedTx, response
 func (service *Service) GetTx(_ *http.Request, args *api.GetTxArgs, response *api.GetTxReply) error {
 	service.vm.ctx.Log.Debug("Platform: GetTx called")
 
-	tx, _, err := service.vm.state.GetTx(args.TxID)
+	tx, txStatus, err := service.vm.state.GetTx(args.TxID)
 	if err != nil {
 		return fmt.Errorf("couldn't get tx: %w", err)
 	}
 	txBytes := tx.Bytes()
 	response.Encoding = args.Encoding
+	response.Final = txStatus == status.Committed
 
 	if args.Encoding == formatting.JSON {
 		tx.Unsigned.InitCtx(service.vm.ctx)
@@ -2388,6 +2399,7 @@ func (service *Service) GetBlock(_ *http.Request, args *api.GetBlockArgs, respon
 		return fmt.Errorf("couldn't get block with id %s: %w", args.BlockID, err)
 	}
 	response.Encoding = args.Encoding
+	response.Final = service.vm.manager.NewBlock(block).Status() == choices.Accepted
 
 	if args.Encoding == formatting.JSON {
 		block.InitCtx(service.vm.ctx)
@@ -9,6 +9,9 @@ import (
 	"errors"
 	"fmt"
 	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -849,3 +852,57 @@ func TestGetBlock(t *testing.T) {
 		})
 	}
 }
+
+func TestGetBlockProcessingNotCached(t *testing.T) {
+	require := require.New(t)
+	service, _ := defaultService(t)
+	service.vm.ctx.Lock.Lock()
+	defer service.vm.ctx.Lock.Unlock()
+
+	handlers, err := service.vm.CreateHandlers(context.Background())
+	require.NoError(err)
+	handler := handlers[""]
+	require.Contains(handler.CachedMethods, "platform.getBlock")
+
+	tx, err := service.vm.txBuilder.NewCreateChainTx(
+		testSubnet1.ID(),
+		nil,
+		constants.AVMID,
+		nil,
+		"chain name",
+		[]*crypto.PrivateKeySECP256K1R{testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]},
+		keys[0].PublicKey().Address(), // change addr
+	)
+	require.NoError(err)
+
+	preferred, err := service.vm.Builder.Preferred()
+	require.NoError(err)
+
+	statelessBlock, err := blocks.NewBanffStandardBlock(
+		preferred.Timestamp(),
+		preferred.ID(),
+		preferred.Height()+1,
+		[]*txs.Tx{tx},
+	)
+	require.NoError(err)
+
+	block := service.vm.manager.NewBlock(statelessBlock)
+	require.NoError(block.Verify(context.Background()))
+
+	getBlock := func() *httptest.ResponseRecorder {
+		body := fmt.Sprintf(`{"jsonrpc":"2.0","method":"platform.getBlock","params":{"blockID":"%s","encoding":"hex"},"id":1}`, block.ID())
+		r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		r.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		handler.Handler.ServeHTTP(w, r)
+		require.Equal(http.StatusOK, w.Code)
+		return w
+	}
+
+	// The response about the processing block must not be cached, as the
+	// block may still be rejected.
+	require.Equal("no-store", getBlock().Header().Get("Cache-Control"))
+
+	require.NoError(block.Accept(context.Background()))
+	require.Empty(getBlock().Header().Get("Cache-Control"))
+}
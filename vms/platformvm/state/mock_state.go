@@ -408,6 +408,20 @@ func (mr *MockStateMockRecorder) GetStartTime(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStartTime", reflect.TypeOf((*MockState)(nil).GetStartTime), arg0)
 }
 
+// GetStateDiff mocks base method.
+func (m *MockState) GetStateDiff(arg0, arg1 uint64, arg2 func([]byte, []byte) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStateDiff", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// GetStateDiff indicates an expected call of GetStateDiff.
+func (mr *MockStateMockRecorder) GetStateDiff(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStateDiff", reflect.TypeOf((*MockState)(nil).GetStateDiff), arg0, arg1, arg2)
+}
+
 // GetStatelessBlock mocks base method.
 func (m *MockState) GetStatelessBlock(arg0 ids.ID) (blocks.Block, choices.Status, error) {
 	m.ctrl.T.Helper()
@@ -515,6 +529,21 @@ func (mr *MockStateMockRecorder) GetUptime(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUptime", reflect.TypeOf((*MockState)(nil).GetUptime), arg0)
 }
 
+// GetValidatorSnapshot mocks base method.
+func (m *MockState) GetValidatorSnapshot(arg0 uint64, arg1 ids.ID) (map[ids.NodeID]uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetValidatorSnapshot", arg0, arg1)
+	ret0, _ := ret[0].(map[ids.NodeID]uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetValidatorSnapshot indicates an expected call of GetValidatorSnapshot.
+func (mr *MockStateMockRecorder) GetValidatorSnapshot(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetValidatorSnapshot", reflect.TypeOf((*MockState)(nil).GetValidatorSnapshot), arg0, arg1)
+}
+
 // GetValidatorWeightDiffs mocks base method.
 func (m *MockState) GetValidatorWeightDiffs(arg0 uint64, arg1 ids.ID) (map[ids.NodeID]*ValidatorWeightDiff, error) {
 	m.ctrl.T.Helper()
@@ -4,8 +4,10 @@
 package state
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
+	"sort"
 	"time"
 
 	"github.com/google/btree"
@@ -38,12 +40,13 @@ import (
 )
 
 const (
-	validatorDiffsCacheSize = 2048
-	blockCacheSize          = 2048
-	txCacheSize             = 2048
-	rewardUTXOsCacheSize    = 2048
-	chainCacheSize          = 2048
-	chainDBCacheSize        = 2048
+	validatorDiffsCacheSize     = 2048
+	validatorSnapshotsCacheSize = 64
+	blockCacheSize              = 2048
+	txCacheSize                 = 2048
+	rewardUTXOsCacheSize        = 2048
+	chainCacheSize              = 2048
+	chainDBCacheSize            = 2048
 )
 
 var (
@@ -60,6 +63,7 @@ var (
 	subnetValidatorPrefix   = []byte("subnetValidator")
 	subnetDelegatorPrefix   = []byte("subnetDelegator")
 	validatorDiffsPrefix    = []byte("validatorDiffs")
+	validatorSnapshotPrefix = []byte("validatorSnapshots")
 	txPrefix                = []byte("tx")
 	rewardUTXOsPrefix       = []byte("rewardUTXOs")
 	utxoPrefix              = []byte("utxo")
@@ -68,11 +72,13 @@ var (
 	supplyPrefix            = []byte("supply")
 	chainPrefix             = []byte("chain")
 	singletonPrefix         = []byte("singleton")
+	stateDiffPrefix         = []byte("stateDiff")
 
 	timestampKey     = []byte("timestamp")
 	currentSupplyKey = []byte("current supply")
 	lastAcceptedKey  = []byte("last accepted")
 	initializedKey   = []byte("initialized")
+	stateDiffsKey    = []byte("state diffs")
 )
 
 // Chain collects all methods to manage the state of the chain for block
@@ -124,6 +130,11 @@ type State interface {
 
 	GetValidatorWeightDiffs(height uint64, subnetID ids.ID) (map[ids.NodeID]*ValidatorWeightDiff, error)
 
+	// GetValidatorSnapshot returns the validator set of [subnetID] at
+	// [height]. Returns database.ErrNotFound if the validator set wasn't
+	// snapshotted at [height].
+	GetValidatorSnapshot(height uint64, subnetID ids.ID) (map[ids.NodeID]uint64, error)
+
 	// Return the current validator set of [subnetID].
 	ValidatorSet(subnetID ids.ID) (validators.Set, error)
 
@@ -132,6 +143,13 @@ type State interface {
 	// Discard uncommitted changes to the database.
 	Abort()
 
+	// GetStateDiff calls [f] with each key of the database whose value
+	// changed between [startHeight] and [endHeight], along with its value at
+	// [endHeight], in increasing key order. A nil value means that the key was
+	// deleted. Returns an error if the changes made after [startHeight] weren't
+	// recorded.
+	GetStateDiff(startHeight, endHeight uint64, f func(key []byte, value []byte) error) error
+
 	// Commit changes to the base database.
 	Commit() error
 
@@ -177,10 +195,12 @@ type stateBlk struct {
  * | | '-. subnetDelegator
  * | |   '-. list
  * | |     '-- txID -> nil
- * | '-. diffs
- * |   '-. height+subnet
- * |     '-. list
- * |       '-- nodeID -> weightChange
+ * | |-. diffs
+ * | | '-. height+subnet
+ * | |   '-. list
+ * | |     '-- nodeID -> weightChange
+ * | '-. snapshots
+ * |   '-- height+subnet -> validator set
  * |-. blocks
  * | '-- blockID -> block bytes
  * |-. txs
@@ -198,11 +218,14 @@ type stateBlk struct {
  * | '-. subnetID
  * |   '-. list
  * |     '-- txID -> nil
- * '-. singletons
- *   |-- initializedKey -> nil
- *   |-- timestampKey -> timestamp
- *   |-- currentSupplyKey -> currentSupply
- *   '-- lastAcceptedKey -> lastAccepted
+ * |-. singletons
+ * | |-- initializedKey -> nil
+ * | |-- timestampKey -> timestamp
+ * | |-- currentSupplyKey -> currentSupply
+ * | |-- lastAcceptedKey -> lastAccepted
+ * | '-- stateDiffsKey -> first height of the recorded state diffs
+ * '-. stateDiffs
+ *   '-- height+key -> value or deletion
  */
 type state struct {
 	cfg     *config.Config
@@ -247,6 +270,9 @@ type state struct {
 	validatorDiffsCache cache.Cacher // cache of heightWithSubnet -> map[ids.ShortID]*ValidatorWeightDiff
 	validatorDiffsDB    database.Database
 
+	validatorSnapshotsCache cache.Cacher // cache of heightWithSubnet -> map[ids.NodeID]uint64
+	validatorSnapshotsDB    database.Database
+
 	addedTxs map[ids.ID]*txAndStatus // map of txID -> {*txs.Tx, Status}
 	txCache  cache.Cacher            // cache of txID -> {*txs.Tx, Status} if the entry is nil, it is not in the database
 	txDB     database.Database
@@ -283,6 +309,25 @@ type state struct {
 	// [lastAccepted] is the most recently accepted block.
 	lastAccepted, persistedLastAccepted ids.ID
 	singletonDB                         database.Database
+
+	// The changes committed to the database are recorded by height if state
+	// diffs are enabled. [stateDiffsHeight] is the height the recording
+	// started at, which is only valid if [recordingStateDiffs] is true.
+	recordingStateDiffs bool
+	stateDiffsHeight    uint64
+	stateDiffsDB        database.Database
+}
+
+// validatorSnapshot is the validator set of a subnet at the first height of an
+// epoch.
+type validatorSnapshot struct {
+	// Sorted by nodeID
+	Validators []snapshotValidator `serialize:"true"`
+}
+
+type snapshotValidator struct {
+	NodeID ids.NodeID `serialize:"true"`
+	Weight uint64     `serialize:"true"`
 }
 
 type ValidatorWeightDiff struct {
@@ -395,6 +440,7 @@ func new(
 	pendingSubnetDelegatorBaseDB := prefixdb.New(subnetDelegatorPrefix, pendingValidatorsDB)
 
 	validatorDiffsDB := prefixdb.New(validatorDiffsPrefix, validatorsDB)
+	validatorSnapshotsDB := prefixdb.New(validatorSnapshotPrefix, validatorsDB)
 
 	validatorDiffsCache, err := metercacher.New(
 		"validator_diffs_cache",
@@ -405,6 +451,15 @@ func new(
 		return nil, err
 	}
 
+	validatorSnapshotsCache, err := metercacher.New(
+		"validator_snapshots_cache",
+		metricsReg,
+		&cache.LRU{Size: validatorSnapshotsCacheSize},
+	)
+	if err != nil {
+		return nil, err
+	}
+
 	txCache, err := metercacher.New(
 		"tx_cache",
 		metricsReg,
@@ -506,6 +561,8 @@ func new(
 		pendingSubnetDelegatorList:   linkeddb.NewDefault(pendingSubnetDelegatorBaseDB),
 		validatorDiffsDB:             validatorDiffsDB,
 		validatorDiffsCache:          validatorDiffsCache,
+		validatorSnapshotsDB:         validatorSnapshotsDB,
+		validatorSnapshotsCache:      validatorSnapshotsCache,
 
 		addedTxs: make(map[ids.ID]*txAndStatus),
 		txDB:     prefixdb.New(txPrefix, baseDB),
@@ -536,6 +593,8 @@ func new(
 		chainDBCache: chainDBCache,
 
 		singletonDB: prefixdb.New(singletonPrefix, baseDB),
+
+		stateDiffsDB: prefixdb.New(stateDiffPrefix, baseDB),
 	}, nil
 }
 
@@ -908,6 +967,37 @@ func (s *state) SetCurrentSupply(subnetID ids.ID, cs uint64) {
 	}
 }
 
+func (s *state) GetValidatorSnapshot(height uint64, subnetID ids.ID) (map[ids.NodeID]uint64, error) {
+	key, err := blocks.GenesisCodec.Marshal(blocks.Version, heightWithSubnet{
+		Height:   height,
+		SubnetID: subnetID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	keyStr := string(key)
+
+	if vdrSetIntf, ok := s.validatorSnapshotsCache.Get(keyStr); ok {
+		return vdrSetIntf.(map[ids.NodeID]uint64), nil
+	}
+
+	snapshotBytes, err := s.validatorSnapshotsDB.Get(key)
+	if err != nil {
+		return nil, err
+	}
+	snapshot := validatorSnapshot{}
+	if _, err := blocks.GenesisCodec.Unmarshal(snapshotBytes, &snapshot); err != nil {
+		return nil, err
+	}
+
+	vdrSet := make(map[ids.NodeID]uint64, len(snapshot.Validators))
+	for _, vdr := range snapshot.Validators {
+		vdrSet[vdr.NodeID] = vdr.Weight
+	}
+	s.validatorSnapshotsCache.Put(keyStr, vdrSet)
+	return vdrSet, nil
+}
+
 func (s *state) GetValidatorWeightDiffs(height uint64, subnetID ids.ID) (map[ids.NodeID]*ValidatorWeightDiff, error) {
 	prefixStruct := heightWithSubnet{
 		Height:   height,
@@ -1067,6 +1157,22 @@ func (s *state) loadMetadata() error {
 	}
 	s.persistedLastAccepted = lastAccepted
 	s.lastAccepted = lastAccepted
+
+	lastAcceptedBlk, _, err := s.GetStatelessBlock(lastAccepted)
+	if err != nil {
+		return err
+	}
+	s.currentHeight = lastAcceptedBlk.Height()
+
+	stateDiffsHeight, err := database.GetUInt64(s.singletonDB, stateDiffsKey)
+	switch err {
+	case nil:
+		s.recordingStateDiffs = true
+		s.stateDiffsHeight = stateDiffsHeight
+	case database.ErrNotFound:
+	default:
+		return err
+	}
 	return nil
 }
 
@@ -1312,6 +1418,7 @@ func (s *state) write(height uint64) error {
 		s.writeBlocks(),
 		s.writeCurrentPrimaryNetworkStakers(height),
 		s.writeCurrentSubnetStakers(height),
+		s.writeValidatorSnapshots(height),
 		s.writePendingPrimaryNetworkStakers(),
 		s.writePendingSubnetStakers(),
 		s.writeUptimes(),
@@ -1350,6 +1457,7 @@ func (s *state) Close() error {
 		s.chainDB.Close(),
 		s.singletonDB.Close(),
 		s.blockDB.Close(),
+		s.stateDiffsDB.Close(),
 	)
 	return errs.Err
 }
@@ -1437,6 +1545,9 @@ func (s *state) CommitBatch() (database.Batch, error) {
 	if err := s.write(s.currentHeight); err != nil {
 		return nil, err
 	}
+	if err := s.writeStateDiff(s.currentHeight); err != nil {
+		return nil, err
+	}
 	return s.baseDB.CommitBatch()
 }
 
@@ -1689,6 +1800,60 @@ func (s *state) writeCurrentSubnetStakers(height uint64) error {
 	return nil
 }
 
+// writeValidatorSnapshots snapshots the validator sets of the tracked subnets
+// if [height] is the first height of an epoch.
+//
+// Assumes the validator sets were updated to [height].
+func (s *state) writeValidatorSnapshots(height uint64) error {
+	length := s.cfg.ValidatorSnapshotEpochLength
+	if length == 0 || height%length != 0 {
+		return nil
+	}
+
+	subnetIDs := ids.Set{}
+	subnetIDs.Add(constants.PrimaryNetworkID)
+	subnetIDs.Union(s.cfg.WhitelistedSubnets)
+	for subnetID := range subnetIDs {
+		vdrs, ok := s.cfg.Validators.GetValidators(subnetID)
+		if !ok {
+			continue
+		}
+
+		vdrList := vdrs.List()
+		snapshot := validatorSnapshot{
+			Validators: make([]snapshotValidator, len(vdrList)),
+		}
+		vdrSet := make(map[ids.NodeID]uint64, len(vdrList))
+		for i, vdr := range vdrList {
+			snapshot.Validators[i] = snapshotValidator{
+				NodeID: vdr.ID(),
+				Weight: vdr.Weight(),
+			}
+			vdrSet[vdr.ID()] = vdr.Weight()
+		}
+		sort.Slice(snapshot.Validators, func(i, j int) bool {
+			return bytes.Compare(snapshot.Validators[i].NodeID[:], snapshot.Validators[j].NodeID[:]) < 0
+		})
+
+		key, err := blocks.GenesisCodec.Marshal(blocks.Version, heightWithSubnet{
+			Height:   height,
+			SubnetID: subnetID,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create snapshot key: %w", err)
+		}
+		snapshotBytes, err := blocks.GenesisCodec.Marshal(blocks.Version, &snapshot)
+		if err != nil {
+			return fmt.Errorf("failed to serialize validator snapshot: %w", err)
+		}
+		if err := s.validatorSnapshotsDB.Put(key, snapshotBytes); err != nil {
+			return fmt.Errorf("failed to write validator snapshot: %w", err)
+		}
+		s.validatorSnapshotsCache.Put(string(key), vdrSet)
+	}
+	return nil
+}
+
 func writeCurrentDelegatorDiff(
 	currentDelegatorList linkeddb.LinkedDB,
 	weightDiff *ValidatorWeightDiff,
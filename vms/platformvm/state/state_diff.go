@@ -0,0 +1,160 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package state
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+)
+
+const (
+	stateDiffDeleted byte = iota
+	stateDiffPut
+)
+
+var (
+	_ database.KeyValueWriterDeleter = (*stateDiffRecorder)(nil)
+
+	errStateDiffsNotRecorded = errors.New("state diffs aren't recorded")
+	errStateDiffNotRecorded  = errors.New("state diff isn't recorded")
+	errHeightNotCommitted    = errors.New("height isn't committed")
+	errInvalidStateDiff      = errors.New("invalid state diff entry")
+)
+
+// GetStateDiff merges the changes recorded at the heights in
+// ([startHeight], [endHeight]]. Changes made while a height is the last
+// accepted height, such as uptime updates, are recorded at that height.
+func (s *state) GetStateDiff(startHeight, endHeight uint64, f func(key []byte, value []byte) error) error {
+	switch {
+	case !s.recordingStateDiffs:
+		return errStateDiffsNotRecorded
+	case startHeight < s.stateDiffsHeight:
+		return fmt.Errorf("%w: height %d is before height %d, where the recording started",
+			errStateDiffNotRecorded, startHeight, s.stateDiffsHeight)
+	case endHeight > s.currentHeight:
+		return fmt.Errorf("%w: height %d is after height %d",
+			errHeightNotCommitted, endHeight, s.currentHeight)
+	case startHeight >= endHeight:
+		return nil
+	}
+
+	// key -> value at [endHeight], or nil if the key was deleted
+	diff := make(map[string][]byte)
+	it := s.stateDiffsDB.NewIteratorWithStart(stateDiffHeightPrefix(startHeight + 1))
+	defer it.Release()
+	for it.Next() {
+		p := wrappers.Packer{Bytes: it.Key()}
+		height := p.UnpackLong()
+		if p.Err != nil {
+			return fmt.Errorf("%w: %s", errInvalidStateDiff, p.Err)
+		}
+		if height > endHeight {
+			break
+		}
+
+		key := string(p.Bytes[p.Offset:])
+		value := it.Value()
+		switch {
+		case len(value) == 1 && value[0] == stateDiffDeleted:
+			diff[key] = nil
+		case len(value) > 0 && value[0] == stateDiffPut:
+			// The value is copied, as the iterator may reuse it.
+			diff[key] = append(make([]byte, 0, len(value)-1), value[1:]...)
+		default:
+			return fmt.Errorf("%w: height %d", errInvalidStateDiff, height)
+		}
+	}
+	if err := it.Error(); err != nil {
+		return err
+	}
+
+	keys := make([]string, 0, len(diff))
+	for key := range diff {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if err := f([]byte(key), diff[key]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeStateDiff records the changes that are about to be committed as
+// changes made at [height], if state diffs are enabled.
+//
+// Assumes every other change of the commit was written.
+func (s *state) writeStateDiff(height uint64) error {
+	if !s.cfg.StateDiffsEnabled {
+		if !s.recordingStateDiffs {
+			return nil
+		}
+		// The recorded diffs are no longer complete once a change isn't
+		// recorded, so a later recording must start over.
+		s.recordingStateDiffs = false
+		if err := s.singletonDB.Delete(stateDiffsKey); err != nil {
+			return fmt.Errorf("failed to stop recording state diffs: %w", err)
+		}
+		return nil
+	}
+
+	batch, err := s.baseDB.CommitBatch()
+	if err != nil {
+		return err
+	}
+	if err := batch.Replay(&stateDiffRecorder{
+		db:     s.stateDiffsDB,
+		height: height,
+	}); err != nil {
+		return fmt.Errorf("failed to record state diff: %w", err)
+	}
+	if s.recordingStateDiffs {
+		return nil
+	}
+
+	// The changes committed at [height] before the recording started weren't
+	// recorded, so diffs can only start at [height].
+	s.recordingStateDiffs = true
+	s.stateDiffsHeight = height
+	if err := database.PutUInt64(s.singletonDB, stateDiffsKey, height); err != nil {
+		return fmt.Errorf("failed to start recording state diffs: %w", err)
+	}
+	return nil
+}
+
+// stateDiffRecorder writes the changes it's given to [db] as changes made at
+// [height].
+type stateDiffRecorder struct {
+	db     database.KeyValueWriter
+	height uint64
+}
+
+func (r *stateDiffRecorder) Put(key, value []byte) error {
+	recordedValue := make([]byte, 1+len(value))
+	recordedValue[0] = stateDiffPut
+	copy(recordedValue[1:], value)
+	return r.db.Put(stateDiffKey(r.height, key), recordedValue)
+}
+
+func (r *stateDiffRecorder) Delete(key []byte) error {
+	return r.db.Put(stateDiffKey(r.height, key), []byte{stateDiffDeleted})
+}
+
+func stateDiffHeightPrefix(height uint64) []byte {
+	p := wrappers.Packer{Bytes: make([]byte, wrappers.LongLen)}
+	p.PackLong(height)
+	return p.Bytes
+}
+
+func stateDiffKey(height uint64, key []byte) []byte {
+	p := wrappers.Packer{Bytes: make([]byte, wrappers.LongLen+len(key))}
+	p.PackLong(height)
+	p.PackFixedBytes(key)
+	return p.Bytes
+}
@@ -4,6 +4,7 @@
 package state
 
 import (
+	"bytes"
 	"math"
 	"testing"
 	"time"
@@ -16,8 +17,10 @@ import (
 	"github.com/ava-labs/avalanchego/database/memdb"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/choices"
 	"github.com/ava-labs/avalanchego/snow/validators"
 	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/hashing"
 	"github.com/ava-labs/avalanchego/utils/units"
 	"github.com/ava-labs/avalanchego/utils/wrappers"
 	"github.com/ava-labs/avalanchego/vms/components/avax"
@@ -26,6 +29,7 @@ import (
 	"github.com/ava-labs/avalanchego/vms/platformvm/genesis"
 	"github.com/ava-labs/avalanchego/vms/platformvm/metrics"
 	"github.com/ava-labs/avalanchego/vms/platformvm/reward"
+	"github.com/ava-labs/avalanchego/vms/platformvm/status"
 	"github.com/ava-labs/avalanchego/vms/platformvm/txs"
 	"github.com/ava-labs/avalanchego/vms/platformvm/validator"
 	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
@@ -486,3 +490,166 @@ func TestValidatorWeightDiff(t *testing.T) {
 		})
 	}
 }
+
+func TestValidatorSnapshots(t *testing.T) {
+	require := require.New(t)
+	stateIntf, _ := newInitializedState(require)
+	state := stateIntf.(*state)
+	state.cfg.ValidatorSnapshotEpochLength = 2
+
+	primaryValidators, ok := state.cfg.Validators.GetValidators(constants.PrimaryNetworkID)
+	require.True(ok)
+
+	expectedSnapshots := map[uint64]map[ids.NodeID]uint64{}
+	for height := uint64(1); height <= 4; height++ {
+		state.PutCurrentValidator(&Staker{
+			TxID:     ids.GenerateTestID(),
+			NodeID:   ids.GenerateTestNodeID(),
+			SubnetID: constants.PrimaryNetworkID,
+			Weight:   height,
+		})
+		state.SetHeight(height)
+		require.NoError(state.Commit())
+
+		if height%2 == 0 {
+			vdrSet := map[ids.NodeID]uint64{}
+			for _, vdr := range primaryValidators.List() {
+				vdrSet[vdr.ID()] = vdr.Weight()
+			}
+			expectedSnapshots[height] = vdrSet
+		}
+	}
+
+	for _, flush := range []bool{false, true} {
+		if flush {
+			state.validatorSnapshotsCache.Flush()
+		}
+		for height := uint64(1); height <= 4; height++ {
+			snapshot, err := state.GetValidatorSnapshot(height, constants.PrimaryNetworkID)
+			expectedSnapshot, ok := expectedSnapshots[height]
+			if !ok {
+				require.ErrorIs(err, database.ErrNotFound)
+				continue
+			}
+			require.NoError(err)
+			require.Equal(expectedSnapshot, snapshot)
+		}
+	}
+	require.Len(expectedSnapshots[4], len(expectedSnapshots[2])+2)
+
+	// Untracked subnets aren't snapshotted.
+	_, err := state.GetValidatorSnapshot(2, ids.GenerateTestID())
+	require.ErrorIs(err, database.ErrNotFound)
+}
+
+func TestStateDiffs(t *testing.T) {
+	require := require.New(t)
+	stateIntf, db := newInitializedState(require)
+	s := stateIntf.(*state)
+	s.cfg.StateDiffsEnabled = true
+
+	err := s.GetStateDiff(0, 0, nil)
+	require.ErrorIs(err, errStateDiffsNotRecorded)
+
+	// The contents of the database after each height, without the recorded
+	// diffs
+	stateDiffsDBPrefix := hashing.ComputeHash256(stateDiffPrefix)
+	snapshot := func() map[string][]byte {
+		it := db.NewIterator()
+		defer it.Release()
+
+		contents := make(map[string][]byte)
+		for it.Next() {
+			if bytes.HasPrefix(it.Key(), stateDiffsDBPrefix) {
+				continue
+			}
+			contents[string(it.Key())] = it.Value()
+		}
+		require.NoError(it.Error())
+		return contents
+	}
+
+	tx1 := &txs.Tx{Unsigned: &txs.AdvanceTimeTx{Time: 1}}
+	require.NoError(tx1.Sign(txs.Codec, nil))
+	tx2 := &txs.Tx{Unsigned: &txs.AdvanceTimeTx{Time: 2}}
+	require.NoError(tx2.Sign(txs.Codec, nil))
+	utxoID := avax.UTXOID{
+		TxID:        initialTxID,
+		OutputIndex: 0,
+	}
+
+	changes := []func(){
+		func() { s.AddTx(tx1, status.Committed) },
+		func() { s.AddTx(tx2, status.Committed) },
+		func() {
+			s.AddTx(tx1, status.Aborted)
+			s.DeleteUTXO(utxoID.InputID())
+		},
+		func() { s.SetTimestamp(initialTime.Add(time.Second)) },
+	}
+	snapshots := make([]map[string][]byte, len(changes)+1)
+	parentID := s.GetLastAccepted()
+	for i, change := range changes {
+		height := uint64(i + 1)
+		blk, err := blocks.NewApricotCommitBlock(parentID, height)
+		require.NoError(err)
+		parentID = blk.ID()
+
+		change()
+		s.AddStatelessBlock(blk, choices.Accepted)
+		s.SetLastAccepted(blk.ID())
+		s.SetHeight(height)
+		require.NoError(s.Commit())
+		snapshots[height] = snapshot()
+	}
+
+	// The recording started at height 1, and height 4 is the last committed
+	// height.
+	err = s.GetStateDiff(0, 4, nil)
+	require.ErrorIs(err, errStateDiffNotRecorded)
+	err = s.GetStateDiff(1, 5, nil)
+	require.ErrorIs(err, errHeightNotCommitted)
+
+	verifyDiffs := func(s State) {
+		for startHeight := 1; startHeight < len(snapshots); startHeight++ {
+			for endHeight := startHeight; endHeight < len(snapshots); endHeight++ {
+				start, end := snapshots[startHeight], snapshots[endHeight]
+				expectedDiff := make(map[string][]byte)
+				for key, value := range end {
+					if startValue, ok := start[key]; !ok || !bytes.Equal(startValue, value) {
+						expectedDiff[key] = value
+					}
+				}
+				for key := range start {
+					if _, ok := end[key]; !ok {
+						expectedDiff[key] = nil
+					}
+				}
+
+				diff := make(map[string][]byte)
+				var lastKey []byte
+				require.NoError(s.GetStateDiff(uint64(startHeight), uint64(endHeight), func(key []byte, value []byte) error {
+					require.Negative(bytes.Compare(lastKey, key))
+					lastKey = append(lastKey[:0], key...)
+					diff[string(key)] = append([]byte(nil), value...)
+					if value == nil {
+						diff[string(key)] = nil
+					}
+					return nil
+				}))
+				require.Equal(expectedDiff, diff, "diff between heights %d and %d", startHeight, endHeight)
+			}
+		}
+	}
+	verifyDiffs(s)
+
+	// The recording is resumed after a restart.
+	restartedState := newStateFromDB(require, db)
+	require.NoError(restartedState.(*state).load())
+	verifyDiffs(restartedState)
+
+	// Once a commit isn't recorded, the recorded diffs are no longer served.
+	require.NoError(restartedState.Commit())
+	err = restartedState.GetStateDiff(1, 4, nil)
+	require.ErrorIs(err, errStateDiffsNotRecorded)
+}
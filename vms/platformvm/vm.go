@@ -9,6 +9,8 @@ import (
 	"fmt"
 	"time"
 
+	stdjson "encoding/json"
+
 	"github.com/gorilla/rpc/v2"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -61,12 +63,15 @@ const (
 )
 
 var (
-	_ block.ChainVM    = (*VM)(nil)
-	_ secp256k1fx.VM   = (*VM)(nil)
-	_ validators.State = (*VM)(nil)
+	_ block.ChainVM     = (*VM)(nil)
+	_ block.Decoder     = (*VM)(nil)
+	_ block.StateDiffer = (*VM)(nil)
+	_ secp256k1fx.VM    = (*VM)(nil)
+	_ validators.State  = (*VM)(nil)
 
 	errWrongCacheType      = errors.New("unexpectedly cached type")
 	errMissingValidatorSet = errors.New("missing validator set")
+	errNoEpochs            = errors.New("validator sets aren't snapshotted")
 )
 
 type VM struct {
@@ -372,6 +377,15 @@ func (vm *VM) ParseBlock(_ context.Context, b []byte) (snowman.Block, error) {
 	return vm.manager.NewBlock(statelessBlk), nil
 }
 
+func (vm *VM) DecodeBlock(_ context.Context, b []byte) ([]byte, error) {
+	statelessBlk, err := blocks.Parse(blocks.Codec, b)
+	if err != nil {
+		return nil, err
+	}
+	statelessBlk.InitCtx(vm.ctx)
+	return stdjson.Marshal(statelessBlk)
+}
+
 func (vm *VM) GetBlock(_ context.Context, blkID ids.ID) (snowman.Block, error) {
 	return vm.manager.GetBlock(blkID)
 }
@@ -413,6 +427,10 @@ func (vm *VM) CreateHandlers(context.Context) (map[string]*common.HTTPHandler, e
 	return map[string]*common.HTTPHandler{
 		"": {
 			Handler: server,
+			// Committed transactions and accepted blocks never change, so
+			// they can be cached once they are fetched by ID.
+			CachedMethods: []string{"platform.getTx", "platform.getBlock"},
+			OpenAPI:       json.PaginatedOpenAPIPathItem("P-Chain API", "platform", &Service{}, vm.MaxPageSizes.Effective(MaxPageSizeBounds)),
 		},
 	}, nil
 }
@@ -432,6 +450,7 @@ func (*VM) CreateStaticHandlers(context.Context) (map[string]*common.HTTPHandler
 		"": {
 			LockOptions: common.NoLock,
 			Handler:     server,
+			OpenAPI:     json.OpenAPIPathItem("Static API of the platform VM", "platform", &api.StaticService{}),
 		},
 	}, nil
 }
@@ -479,18 +498,14 @@ func (vm *VM) GetValidatorSet(ctx context.Context, height uint64, subnetID ids.I
 	// get the start time to track metrics
 	startTime := vm.Clock().Time()
 
-	currentValidators, ok := vm.Validators.GetValidators(subnetID)
-	if !ok {
-		return nil, errMissingValidatorSet
-	}
-	currentValidatorList := currentValidators.List()
-
-	vdrSet := make(map[ids.NodeID]uint64, len(currentValidatorList))
-	for _, vdr := range currentValidatorList {
-		vdrSet[vdr.ID()] = vdr.Weight()
+	// The validator set is reconstructed from the closest validator set
+	// known at or above [height].
+	vdrSet, startHeight, err := vm.getBaseValidatorSet(height, lastAcceptedHeight, subnetID)
+	if err != nil {
+		return nil, err
 	}
 
-	for i := lastAcceptedHeight; i > height; i-- {
+	for i := startHeight; i > height; i-- {
 		diffs, err := vm.state.GetValidatorWeightDiffs(i, subnetID)
 		if err != nil {
 			return nil, err
@@ -526,10 +541,68 @@ func (vm *VM) GetValidatorSet(ctx context.Context, height uint64, subnetID ids.I
 	endTime := vm.Clock().Time()
 	vm.metrics.IncValidatorSetsCreated()
 	vm.metrics.AddValidatorSetsDuration(endTime.Sub(startTime))
-	vm.metrics.AddValidatorSetsHeightDiff(lastAcceptedHeight - height)
+	vm.metrics.AddValidatorSetsHeightDiff(startHeight - height)
 	return vdrSet, nil
 }
 
+// getBaseValidatorSet returns a copy of the validator set of [subnetID] at the
+// lowest height, at or above [height], that the validator set is known at
+// without reconstructing it, along with that height. That is the first
+// snapshot at or above [height], if it was taken, or the current validator
+// set otherwise.
+func (vm *VM) getBaseValidatorSet(height, lastAcceptedHeight uint64, subnetID ids.ID) (map[ids.NodeID]uint64, uint64, error) {
+	if length := vm.ValidatorSnapshotEpochLength; length > 0 {
+		snapshotHeight := (height + length - 1) / length * length
+		if snapshotHeight <= lastAcceptedHeight {
+			snapshot, err := vm.state.GetValidatorSnapshot(snapshotHeight, subnetID)
+			switch err {
+			case nil:
+				vdrSet := make(map[ids.NodeID]uint64, len(snapshot))
+				for nodeID, weight := range snapshot {
+					vdrSet[nodeID] = weight
+				}
+				return vdrSet, snapshotHeight, nil
+			case database.ErrNotFound:
+				// Snapshots are only taken while the subnet is tracked, and
+				// not before the node enabled them.
+			default:
+				return nil, 0, err
+			}
+		}
+	}
+
+	currentValidators, ok := vm.Validators.GetValidators(subnetID)
+	if !ok {
+		return nil, 0, errMissingValidatorSet
+	}
+	currentValidatorList := currentValidators.List()
+
+	vdrSet := make(map[ids.NodeID]uint64, len(currentValidatorList))
+	for _, vdr := range currentValidatorList {
+		vdrSet[vdr.ID()] = vdr.Weight()
+	}
+	return vdrSet, lastAcceptedHeight, nil
+}
+
+// GetEpochLength returns the number of blocks in an epoch of validator set
+// snapshots. Zero if validator sets aren't snapshotted.
+func (vm *VM) GetEpochLength(context.Context) (uint64, error) {
+	return vm.ValidatorSnapshotEpochLength, nil
+}
+
+// GetValidatorSetAtEpoch returns the validator set of [subnetID] at the first
+// height of [epoch].
+func (vm *VM) GetValidatorSetAtEpoch(ctx context.Context, epoch uint64, subnetID ids.ID) (map[ids.NodeID]uint64, error) {
+	if vm.ValidatorSnapshotEpochLength == 0 {
+		return nil, errNoEpochs
+	}
+	height, err := math.Mul64(epoch, vm.ValidatorSnapshotEpochLength)
+	if err != nil {
+		return nil, err
+	}
+	return vm.GetValidatorSet(ctx, height, subnetID)
+}
+
 // GetMinimumHeight returns the height of the most recent block beyond the
 // horizon of our recentlyAccepted window.
 //
@@ -579,6 +652,15 @@ func (vm *VM) GetCurrentHeight(context.Context) (uint64, error) {
 	return lastAccepted.Height(), nil
 }
 
+// GetStateDiff returns the changes made to the database between two heights
+// if state diffs are enabled.
+func (vm *VM) GetStateDiff(_ context.Context, startHeight, endHeight uint64, f func(key []byte, value []byte) error) error {
+	if !vm.StateDiffsEnabled {
+		return block.ErrStateDifferNotImplemented
+	}
+	return vm.state.GetStateDiff(startHeight, endHeight, f)
+}
+
 func (vm *VM) updateValidators() error {
 	primaryValidators, err := vm.state.ValidatorSet(constants.PrimaryNetworkID)
 	if err != nil {
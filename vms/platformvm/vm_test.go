@@ -11,6 +11,8 @@ import (
 	"testing"
 	"time"
 
+	"github.com/golang/mock/gomock"
+
 	"github.com/prometheus/client_golang/prometheus"
 
 	"github.com/stretchr/testify/require"
@@ -53,6 +55,7 @@ import (
 	"github.com/ava-labs/avalanchego/vms/platformvm/blocks"
 	"github.com/ava-labs/avalanchego/vms/platformvm/config"
 	"github.com/ava-labs/avalanchego/vms/platformvm/reward"
+	"github.com/ava-labs/avalanchego/vms/platformvm/state"
 	"github.com/ava-labs/avalanchego/vms/platformvm/status"
 	"github.com/ava-labs/avalanchego/vms/platformvm/txs"
 	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
@@ -1747,6 +1750,7 @@ func TestBootstrapPartiallyAccepted(t *testing.T) {
 			TimeoutCoefficient: 1.25,
 		},
 		benchlist,
+		0,
 		"",
 		prometheus.NewRegistry(),
 	)
@@ -1863,6 +1867,8 @@ func TestBootstrapPartiallyAccepted(t *testing.T) {
 		nil,
 		time.Hour,
 		cpuTracker,
+		0,
+		nil,
 	)
 	require.NoError(err)
 
@@ -2411,3 +2417,73 @@ func TestUptimeDisallowedAfterNeverConnecting(t *testing.T) {
 	)
 	require.ErrorIs(err, database.ErrNotFound)
 }
+
+func TestGetBaseValidatorSet(t *testing.T) {
+	require := require.New(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	currentNodeID := ids.GenerateTestNodeID()
+	currentValidators := validators.NewSet()
+	require.NoError(currentValidators.AddWeight(currentNodeID, 10))
+	vdrs := validators.NewManager()
+	require.NoError(vdrs.Set(constants.PrimaryNetworkID, currentValidators))
+
+	mockState := state.NewMockState(ctrl)
+	vm := &VM{
+		Factory: Factory{
+			Config: config.Config{
+				Validators:                   vdrs,
+				ValidatorSnapshotEpochLength: 4,
+			},
+		},
+		state: mockState,
+	}
+
+	snapshotNodeID := ids.GenerateTestNodeID()
+	snapshot := map[ids.NodeID]uint64{
+		snapshotNodeID: 5,
+	}
+
+	// The closest snapshot at or above the height is used.
+	mockState.EXPECT().GetValidatorSnapshot(uint64(8), constants.PrimaryNetworkID).Return(snapshot, nil)
+	vdrSet, startHeight, err := vm.getBaseValidatorSet(5, 10, constants.PrimaryNetworkID)
+	require.NoError(err)
+	require.Equal(uint64(8), startHeight)
+	require.Equal(snapshot, vdrSet)
+
+	// The returned set is a copy.
+	vdrSet[snapshotNodeID] = 6
+	require.Equal(uint64(5), snapshot[snapshotNodeID])
+
+	// A snapshot at the height itself requires no reconstruction.
+	mockState.EXPECT().GetValidatorSnapshot(uint64(8), constants.PrimaryNetworkID).Return(snapshot, nil)
+	_, startHeight, err = vm.getBaseValidatorSet(8, 10, constants.PrimaryNetworkID)
+	require.NoError(err)
+	require.Equal(uint64(8), startHeight)
+
+	// Snapshots above the last accepted height aren't taken yet.
+	vdrSet, startHeight, err = vm.getBaseValidatorSet(9, 10, constants.PrimaryNetworkID)
+	require.NoError(err)
+	require.Equal(uint64(10), startHeight)
+	require.Equal(map[ids.NodeID]uint64{currentNodeID: 10}, vdrSet)
+
+	// Missing snapshots fall back to the current validator set.
+	mockState.EXPECT().GetValidatorSnapshot(uint64(4), constants.PrimaryNetworkID).Return(nil, database.ErrNotFound)
+	vdrSet, startHeight, err = vm.getBaseValidatorSet(3, 10, constants.PrimaryNetworkID)
+	require.NoError(err)
+	require.Equal(uint64(10), startHeight)
+	require.Equal(map[ids.NodeID]uint64{currentNodeID: 10}, vdrSet)
+}
+
+func TestGetValidatorSetAtEpochWithoutSnapshots(t *testing.T) {
+	require := require.New(t)
+
+	vm := &VM{}
+	length, err := vm.GetEpochLength(context.Background())
+	require.NoError(err)
+	require.Zero(length)
+
+	_, err = vm.GetValidatorSetAtEpoch(context.Background(), 1, constants.PrimaryNetworkID)
+	require.ErrorIs(err, errNoEpochs)
+}
@@ -169,6 +169,52 @@ func (vm *VM) BatchedParseBlock(ctx context.Context, blks [][]byte) ([]snowman.B
 	return blocks, nil
 }
 
+func (vm *VM) BatchedGetBlock(ctx context.Context, blkIDs []ids.ID) ([]snowman.Block, []error, error) {
+	if vm.bVM == nil {
+		return nil, nil, block.ErrRemoteVMNotImplemented
+	}
+
+	var (
+		blocks = make([]snowman.Block, len(blkIDs))
+		errs   = make([]error, len(blkIDs))
+
+		// indices of [blkIDs] that aren't post fork blocks, which are fetched
+		// from the inner VM at once
+		preForkIndices []int
+		preForkIDs     []ids.ID
+	)
+	for i, blkID := range blkIDs {
+		// Post fork blocks are stored by the proposervm, so only the blocks
+		// it doesn't know of are requested from the inner VM.
+		blk, err := vm.getPostForkBlock(ctx, blkID)
+		if err != nil {
+			preForkIndices = append(preForkIndices, i)
+			preForkIDs = append(preForkIDs, blkID)
+			continue
+		}
+		blocks[i] = blk
+	}
+	if len(preForkIDs) == 0 {
+		return blocks, errs, nil
+	}
+
+	innerBlks, innerErrs, err := vm.bVM.BatchedGetBlock(ctx, preForkIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+	for j, i := range preForkIndices {
+		if err := innerErrs[j]; err != nil {
+			errs[i] = err
+			continue
+		}
+		blocks[i] = &preForkBlock{
+			Block: innerBlks[j],
+			vm:    vm,
+		}
+	}
+	return blocks, errs, nil
+}
+
 func (vm *VM) getStatelessBlk(blkID ids.ID) (statelessblock.Block, error) {
 	if currentBlk, exists := vm.verifiedBlocks[blkID]; exists {
 		return currentBlk.getStatelessBlk(), nil
@@ -13,6 +13,7 @@ import (
 
 	"github.com/stretchr/testify/require"
 
+	"github.com/ava-labs/avalanchego/database"
 	"github.com/ava-labs/avalanchego/database/manager"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/snow"
@@ -914,6 +915,37 @@ func TestBatchedParseBlockAtSnomanPlusPlusFork(t *testing.T) {
 	require.Equal(res[1].ID(), builtBlk3.ID())
 	require.Equal(res[2].ID(), builtBlk2.ID())
 	require.Equal(res[3].ID(), builtBlk1.ID())
+
+	// Only the blocks the proposervm doesn't know of are requested from the
+	// core VM, at once.
+	var requested [][]ids.ID
+	coreVM.BatchedGetBlockF = func(_ context.Context, blkIDs []ids.ID) ([]snowman.Block, []error, error) {
+		requested = append(requested, blkIDs)
+		blks := make([]snowman.Block, len(blkIDs))
+		errs := make([]error, len(blkIDs))
+		for i, blkID := range blkIDs {
+			if blkID == coreBlk2.ID() {
+				blks[i] = coreBlk2
+				continue
+			}
+			errs[i] = database.ErrNotFound
+		}
+		return blks, errs, nil
+	}
+	unknownID := ids.GenerateTestID()
+	blks, errs, err := proRemoteVM.BatchedGetBlock(context.Background(), []ids.ID{
+		builtBlk4.ID(),
+		builtBlk2.ID(),
+		unknownID,
+	})
+	require.NoError(err)
+	require.Equal([][]ids.ID{{builtBlk2.ID(), unknownID}}, requested)
+	require.Equal(builtBlk4.ID(), blks[0].ID())
+	require.NoError(errs[0])
+	require.Equal(builtBlk2.ID(), blks[1].ID())
+	require.NoError(errs[1])
+	require.Nil(blks[2])
+	require.ErrorIs(errs[2], database.ErrNotFound)
 }
 
 type TestRemoteProposerVM struct {
@@ -980,7 +1012,7 @@ func initTestRemoteProposerVM(
 		}
 	}
 
-	proVM := New(coreVM, proBlkStartTime, 0, DefaultMinBlockDelay)
+	proVM := New(coreVM, proBlkStartTime, 0, DefaultMinBlockDelay, nil)
 
 	valState := &validators.TestState{
 		T: t,
@@ -17,6 +17,8 @@ import (
 	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
 	"github.com/ava-labs/avalanchego/vms/proposervm/block"
 	"github.com/ava-labs/avalanchego/vms/proposervm/proposer"
+
+	smblock "github.com/ava-labs/avalanchego/snow/engine/snowman/block"
 )
 
 const (
@@ -116,6 +118,13 @@ func (p *postForkCommonComponents) Verify(
 
 	maxTimestamp := p.vm.Time().Add(maxSkew)
 	if childTimestamp.After(maxTimestamp) {
+		if networkTime, skew, ok := p.vm.networkTime(); ok && !childTimestamp.After(networkTime.Add(maxSkew)) {
+			p.vm.ctx.Log.Warn("block timestamp rejected because of clock skew",
+				zap.Stringer("blkID", child.ID()),
+				zap.Time("blockTimestamp", childTimestamp),
+				zap.Duration("clockSkew", skew),
+			)
+		}
 		return errTimeTooAdvanced
 	}
 
@@ -162,7 +171,13 @@ func (p *postForkCommonComponents) Verify(
 		)
 	}
 
-	return p.vm.verifyAndRecordInnerBlk(ctx, child)
+	return p.vm.verifyAndRecordInnerBlk(
+		ctx,
+		&smblock.Context{
+			PChainHeight: parentPChainHeight,
+		},
+		child,
+	)
 }
 
 // Return the child (a *postForkBlock) of this block
@@ -205,6 +220,17 @@ func (p *postForkCommonComponents) buildChild(
 				zap.Time("blockTimestamp", newTimestamp),
 			)
 
+			// If the local clock is behind, the window may have started
+			// according to the rest of the network.
+			if networkTime, skew, ok := p.vm.networkTime(); ok && networkTime.Sub(parentTimestamp) >= minDelay {
+				p.vm.ctx.Log.Warn("proposer window missed because of clock skew",
+					zap.Time("parentTimestamp", parentTimestamp),
+					zap.Duration("minDelay", minDelay),
+					zap.Time("blockTimestamp", newTimestamp),
+					zap.Duration("clockSkew", skew),
+				)
+			}
+
 			// In case the inner VM only issued one pendingTxs message, we
 			// should attempt to re-handle that once it is our turn to build the
 			// block.
@@ -213,7 +239,7 @@ func (p *postForkCommonComponents) buildChild(
 		}
 	}
 
-	innerBlock, err := p.vm.ChainVM.BuildBlock(ctx)
+	innerBlock, err := p.vm.buildInnerBlock(ctx, parentPChainHeight)
 	if err != nil {
 		return nil, err
 	}
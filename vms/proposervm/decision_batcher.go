@@ -0,0 +1,21 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package proposervm
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
+)
+
+var _ block.DecisionBatcher = (*VM)(nil)
+
+// BatchDecisions batches the decisions made on the inner blocks. The decisions
+// made on the outer blocks are persisted as they are made.
+func (vm *VM) BatchDecisions(ctx context.Context, f func(context.Context) error) error {
+	if vm.dbVM == nil {
+		return f(ctx)
+	}
+	return vm.dbVM.BatchDecisions(ctx, f)
+}
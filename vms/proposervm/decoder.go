@@ -0,0 +1,69 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package proposervm
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
+	"github.com/ava-labs/avalanchego/vms/types"
+
+	statelessblock "github.com/ava-labs/avalanchego/vms/proposervm/block"
+)
+
+var _ block.Decoder = (*VM)(nil)
+
+// decodedBlock describes a post fork block. Options don't specify a timestamp,
+// P-chain height, or proposer.
+type decodedBlock struct {
+	ID           ids.ID      `json:"id"`
+	ParentID     ids.ID      `json:"parentID"`
+	Timestamp    *time.Time  `json:"timestamp,omitempty"`
+	PChainHeight *uint64     `json:"pChainHeight,omitempty"`
+	Proposer     *ids.NodeID `json:"proposer,omitempty"`
+	// Block is the inner block as decoded by the inner VM. If the inner VM
+	// can't decode blocks, InnerBlockBytes is populated instead.
+	Block           json.RawMessage     `json:"block,omitempty"`
+	InnerBlockBytes types.JSONByteSlice `json:"innerBlockBytes,omitempty"`
+}
+
+func (vm *VM) DecodeBlock(ctx context.Context, b []byte) ([]byte, error) {
+	statelessBlock, err := statelessblock.Parse(b)
+	if err != nil {
+		// The block was built before the fork, so it is only understood by the
+		// inner VM.
+		if vm.dVM == nil {
+			return nil, block.ErrDecoderNotImplemented
+		}
+		return vm.dVM.DecodeBlock(ctx, b)
+	}
+
+	decoded := decodedBlock{
+		ID:       statelessBlock.ID(),
+		ParentID: statelessBlock.ParentID(),
+	}
+	if signedBlock, ok := statelessBlock.(statelessblock.SignedBlock); ok {
+		timestamp := signedBlock.Timestamp()
+		pChainHeight := signedBlock.PChainHeight()
+		proposer := signedBlock.Proposer()
+		decoded.Timestamp = &timestamp
+		decoded.PChainHeight = &pChainHeight
+		decoded.Proposer = &proposer
+	}
+
+	innerBlkBytes := statelessBlock.Block()
+	if vm.dVM == nil {
+		decoded.InnerBlockBytes = innerBlkBytes
+		return json.Marshal(decoded)
+	}
+
+	decoded.Block, err = vm.dVM.DecodeBlock(ctx, innerBlkBytes)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(decoded)
+}
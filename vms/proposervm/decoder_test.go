@@ -0,0 +1,88 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package proposervm
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
+
+	statelessblock "github.com/ava-labs/avalanchego/vms/proposervm/block"
+)
+
+type decoderVM struct {
+	*block.TestVM
+	*block.TestDecoder
+}
+
+func TestDecodeBlock(t *testing.T) {
+	require := require.New(t)
+
+	innerBlkBytes := []byte{1, 2, 3}
+	innerVM := &decoderVM{
+		TestVM: &block.TestVM{
+			TestVM: common.TestVM{T: t},
+		},
+		TestDecoder: &block.TestDecoder{
+			T: t,
+			DecodeBlockF: func(_ context.Context, b []byte) ([]byte, error) {
+				require.Equal(innerBlkBytes, b)
+				return []byte(`{"inner":true}`), nil
+			},
+		},
+	}
+	vm := New(innerVM, time.Time{}, 0, DefaultMinBlockDelay, nil)
+
+	parentID := ids.GenerateTestID()
+	timestamp := time.Unix(123, 0)
+	signedBlk, err := statelessblock.BuildUnsigned(parentID, timestamp, 7, innerBlkBytes)
+	require.NoError(err)
+
+	decodedBytes, err := vm.DecodeBlock(context.Background(), signedBlk.Bytes())
+	require.NoError(err)
+	decoded := decodedBlock{}
+	require.NoError(json.Unmarshal(decodedBytes, &decoded))
+	require.Equal(signedBlk.ID(), decoded.ID)
+	require.Equal(parentID, decoded.ParentID)
+	require.Equal(uint64(7), *decoded.PChainHeight)
+	require.True(timestamp.Equal(*decoded.Timestamp))
+	require.JSONEq(`{"inner":true}`, string(decoded.Block))
+
+	// Blocks built before the fork are decoded by the inner VM.
+	decodedBytes, err = vm.DecodeBlock(context.Background(), innerBlkBytes)
+	require.NoError(err)
+	require.JSONEq(`{"inner":true}`, string(decodedBytes))
+}
+
+func TestDecodeBlockWithoutInnerDecoder(t *testing.T) {
+	require := require.New(t)
+
+	innerVM := &block.TestVM{
+		TestVM: common.TestVM{T: t},
+	}
+	vm := New(innerVM, time.Time{}, 0, DefaultMinBlockDelay, nil)
+
+	innerBlkBytes := []byte{1, 2, 3}
+	option, err := statelessblock.BuildOption(ids.GenerateTestID(), innerBlkBytes)
+	require.NoError(err)
+
+	decodedBytes, err := vm.DecodeBlock(context.Background(), option.Bytes())
+	require.NoError(err)
+	decoded := map[string]interface{}{}
+	require.NoError(json.Unmarshal(decodedBytes, &decoded))
+	require.Equal(option.ID().String(), decoded["id"])
+	require.Equal("0x010203", decoded["innerBlockBytes"])
+	require.NotContains(decoded, "pChainHeight")
+	require.NotContains(decoded, "block")
+
+	_, err = vm.DecodeBlock(context.Background(), innerBlkBytes)
+	require.ErrorIs(err, block.ErrDecoderNotImplemented)
+}
@@ -0,0 +1,19 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package proposervm
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
+)
+
+var _ block.MessageVerifier = (*VM)(nil)
+
+func (vm *VM) VerifyMessage(ctx context.Context, msg []byte) error {
+	if vm.mvVM == nil {
+		return block.ErrMessageVerifierNotImplemented
+	}
+	return vm.mvVM.VerifyMessage(ctx, msg)
+}
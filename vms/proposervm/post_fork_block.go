@@ -148,7 +148,9 @@ func (b *postForkBlock) verifyPostForkOption(ctx context.Context, child *postFor
 		return errInnerParentMismatch
 	}
 
-	return child.vm.verifyAndRecordInnerBlk(ctx, child)
+	// Options aren't verified against the P-Chain, so they aren't provided a
+	// block context.
+	return child.vm.verifyAndRecordInnerBlk(ctx, nil, child)
 }
 
 // Return the child (a *postForkBlock) of this block
@@ -741,7 +741,7 @@ func TestOptionTimestampValidity(t *testing.T) {
 	// Restart the node.
 
 	ctx := proVM.ctx
-	proVM = New(coreVM, time.Time{}, 0, DefaultMinBlockDelay)
+	proVM = New(coreVM, time.Time{}, 0, DefaultMinBlockDelay, nil)
 
 	coreVM.InitializeF = func(
 		context.Context,
@@ -13,6 +13,8 @@ import (
 	"github.com/ava-labs/avalanchego/snow/choices"
 	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
 	"github.com/ava-labs/avalanchego/vms/proposervm/block"
+
+	smblock "github.com/ava-labs/avalanchego/snow/engine/snowman/block"
 )
 
 var _ Block = (*preForkBlock)(nil)
@@ -152,8 +154,16 @@ func (b *preForkBlock) verifyPostForkChild(ctx context.Context, child *postForkB
 		return err
 	}
 
-	// Verify the inner block and track it as verified
-	return b.vm.verifyAndRecordInnerBlk(ctx, child)
+	// Verify the inner block and track it as verified. The first post-fork
+	// block has no post-fork parent, so it is provided the minimum P-Chain
+	// height, as when it is built.
+	return b.vm.verifyAndRecordInnerBlk(
+		ctx,
+		&smblock.Context{
+			PChainHeight: b.vm.minimumPChainHeight,
+		},
+		child,
+	)
 }
 
 func (*preForkBlock) verifyPostForkOption(context.Context, *postForkOption) error {
@@ -196,7 +206,7 @@ func (b *preForkBlock) buildChild(ctx context.Context) (Block, error) {
 		return nil, err
 	}
 
-	innerBlock, err := b.vm.ChainVM.BuildBlock(ctx)
+	innerBlock, err := b.vm.buildInnerBlock(ctx, b.vm.minimumPChainHeight)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,26 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package proposervm
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
+)
+
+var _ block.StateDiffer = (*VM)(nil)
+
+// GetStateDiff returns the state diff of the inner VM. Blocks wrapped by the
+// proposervm have the height of the inner block they wrap.
+func (vm *VM) GetStateDiff(
+	ctx context.Context,
+	startHeight uint64,
+	endHeight uint64,
+	f func(key []byte, value []byte) error,
+) error {
+	if vm.sdVM == nil {
+		return block.ErrStateDifferNotImplemented
+	}
+	return vm.sdVM.GetStateDiff(ctx, startHeight, endHeight, f)
+}
@@ -95,7 +95,7 @@ func helperBuildStateSyncTestObjects(t *testing.T) (*fullVM, *VM) {
 	}
 
 	// createVM
-	vm := New(innerVM, time.Time{}, 0, DefaultMinBlockDelay)
+	vm := New(innerVM, time.Time{}, 0, DefaultMinBlockDelay, nil)
 
 	ctx := snow.DefaultContextTest()
 	ctx.NodeID = ids.NodeIDFromCert(pTestCert.Leaf)
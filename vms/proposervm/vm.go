@@ -26,6 +26,7 @@ import (
 	"github.com/ava-labs/avalanchego/snow/engine/common"
 	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
 	"github.com/ava-labs/avalanchego/utils"
+	"github.com/ava-labs/avalanchego/utils/clockskew"
 	"github.com/ava-labs/avalanchego/utils/math"
 	"github.com/ava-labs/avalanchego/utils/timer/mockable"
 	"github.com/ava-labs/avalanchego/vms/proposervm/indexer"
@@ -52,6 +53,8 @@ var (
 	_ block.HeightIndexedChainVM = (*VM)(nil)
 	_ block.StateSyncableVM      = (*VM)(nil)
 
+	_ common.AppErrorHandler = (*VM)(nil)
+
 	dbPrefix = []byte("proposervm")
 )
 
@@ -60,10 +63,16 @@ type VM struct {
 	bVM  block.BatchedChainVM
 	hVM  block.HeightIndexedChainVM
 	ssVM block.StateSyncableVM
+	dVM  block.Decoder
+	dbVM block.DecisionBatcher
+	sdVM block.StateDiffer
+	mvVM block.MessageVerifier
+	bbVM block.BuildBlockWithContextChainVM
 
 	activationTime      time.Time
 	minimumPChainHeight uint64
 	minBlkDelay         time.Duration
+	clockSkew           clockskew.Estimator
 
 	state.State
 	hIndexer                indexer.HeightIndexer
@@ -103,24 +112,39 @@ type VM struct {
 
 // New performs best when [minBlkDelay] is whole seconds. This is because block
 // timestamps are only specific to the second.
+//
+// If [clockSkew] is non-nil, proposer window misses caused by the skew of the
+// local clock are logged.
 func New(
 	vm block.ChainVM,
 	activationTime time.Time,
 	minimumPChainHeight uint64,
 	minBlkDelay time.Duration,
+	clockSkew clockskew.Estimator,
 ) *VM {
 	bVM, _ := vm.(block.BatchedChainVM)
 	hVM, _ := vm.(block.HeightIndexedChainVM)
 	ssVM, _ := vm.(block.StateSyncableVM)
+	dVM, _ := vm.(block.Decoder)
+	dbVM, _ := vm.(block.DecisionBatcher)
+	sdVM, _ := vm.(block.StateDiffer)
+	mvVM, _ := vm.(block.MessageVerifier)
+	bbVM, _ := vm.(block.BuildBlockWithContextChainVM)
 	return &VM{
 		ChainVM: vm,
 		bVM:     bVM,
 		hVM:     hVM,
 		ssVM:    ssVM,
+		dVM:     dVM,
+		dbVM:    dbVM,
+		sdVM:    sdVM,
+		mvVM:    mvVM,
+		bbVM:    bbVM,
 
 		activationTime:      activationTime,
 		minimumPChainHeight: minimumPChainHeight,
 		minBlkDelay:         minBlkDelay,
+		clockSkew:           clockSkew,
 	}
 }
 
@@ -240,6 +264,12 @@ func (vm *VM) SetState(ctx context.Context, newState snow.State) error {
 	return vm.setLastAcceptedMetadata(ctx)
 }
 
+// AppRequestFailedWithError forwards the reason a request failed to the inner
+// VM, if it implements AppErrorHandler.
+func (vm *VM) AppRequestFailedWithError(ctx context.Context, nodeID ids.NodeID, requestID uint32, appErr *common.AppError) error {
+	return common.NotifyAppRequestFailed(ctx, vm.ChainVM, nodeID, requestID, appErr)
+}
+
 func (vm *VM) BuildBlock(ctx context.Context) (snowman.Block, error) {
 	preferredBlock, err := vm.getBlock(ctx, vm.preferred)
 	if err != nil {
@@ -738,22 +768,65 @@ func (vm *VM) storePostForkBlock(blk PostForkBlock) error {
 	return vm.db.Commit()
 }
 
-func (vm *VM) verifyAndRecordInnerBlk(ctx context.Context, postFork PostForkBlock) error {
+// buildInnerBlock builds a block of the inner VM given that the P-Chain height
+// of the parent block is [parentPChainHeight].
+func (vm *VM) buildInnerBlock(ctx context.Context, parentPChainHeight uint64) (snowman.Block, error) {
+	if vm.bbVM == nil {
+		return vm.ChainVM.BuildBlock(ctx)
+	}
+	return vm.bbVM.BuildBlockWithContext(ctx, &block.Context{
+		PChainHeight: parentPChainHeight,
+	})
+}
+
+// verifyAndRecordInnerBlk verifies the inner block of [postFork]. If
+// [blockCtx] is non-nil and the inner block accounts for the P-Chain height,
+// the inner block is verified with [blockCtx].
+func (vm *VM) verifyAndRecordInnerBlk(ctx context.Context, blockCtx *block.Context, postFork PostForkBlock) error {
 	postForkID := postFork.ID()
-	// If inner block's Verify returned true, don't call it again.
-	//
-	// Note that if [innerBlk.Verify] returns nil, this method returns nil. This
-	// must always remain the case to maintain the inner block's invariant that
-	// if it's Verify() returns nil, it is eventually accepted or rejected.
 	currentInnerBlk := postFork.getInnerBlk()
-	if originalInnerBlk, contains := vm.Tree.Get(currentInnerBlk); !contains {
+	originalInnerBlk, previouslyVerified := vm.Tree.Get(currentInnerBlk)
+	if previouslyVerified {
+		currentInnerBlk = originalInnerBlk
+		postFork.setInnerBlk(originalInnerBlk)
+	}
+
+	var (
+		blkWithCtx          block.WithVerifyContext
+		shouldVerifyWithCtx bool
+	)
+	if blockCtx != nil {
+		blkWithCtx, shouldVerifyWithCtx = currentInnerBlk.(block.WithVerifyContext)
+		if shouldVerifyWithCtx {
+			var err error
+			shouldVerifyWithCtx, err = blkWithCtx.ShouldVerifyWithContext(ctx)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	// If inner block's Verify returned true, don't call it again. Blocks that
+	// are verified with a context are verified again, as they may be verified
+	// with a different context each time.
+	//
+	// Note that if the inner block's verification returns nil, this method
+	// returns nil. This must always remain the case to maintain the inner
+	// block's invariant that if it's verification returns nil, it is
+	// eventually accepted or rejected.
+	switch {
+	case shouldVerifyWithCtx:
+		if err := blkWithCtx.VerifyWithContext(ctx, blockCtx); err != nil {
+			return err
+		}
+	case !previouslyVerified:
 		if err := currentInnerBlk.Verify(ctx); err != nil {
 			return err
 		}
+	}
+	if !previouslyVerified {
 		vm.Tree.Add(currentInnerBlk)
 		vm.innerBlkCache.Put(postForkID, currentInnerBlk)
-	} else {
-		postFork.setInnerBlk(originalInnerBlk)
 	}
 	vm.verifiedBlocks[postForkID] = postFork
 	return nil
@@ -769,6 +842,19 @@ func (vm *VM) notifyInnerBlockReady() {
 	}
 }
 
+// networkTime returns the local time corrected by the estimated skew of the
+// local clock, along with the skew. Returns false if the skew is unknown.
+func (vm *VM) networkTime() (time.Time, time.Duration, bool) {
+	if vm.clockSkew == nil {
+		return time.Time{}, 0, false
+	}
+	skew, ok := vm.clockSkew.Skew()
+	if !ok {
+		return time.Time{}, 0, false
+	}
+	return vm.Time().Add(-skew), skew, true
+}
+
 func (vm *VM) optimalPChainHeight(ctx context.Context, minPChainHeight uint64) (uint64, error) {
 	minimumHeight, err := vm.ctx.ValidatorState.GetMinimumHeight(ctx)
 	if err != nil {
@@ -130,7 +130,7 @@ func initTestProposerVM(
 		}
 	}
 
-	proVM := New(coreVM, proBlkStartTime, minPChainHeight, DefaultMinBlockDelay)
+	proVM := New(coreVM, proBlkStartTime, minPChainHeight, DefaultMinBlockDelay, nil)
 
 	valState := &validators.TestState{
 		T: t,
@@ -912,7 +912,7 @@ func TestExpiredBuildBlock(t *testing.T) {
 		}
 	}
 
-	proVM := New(coreVM, time.Time{}, 0, DefaultMinBlockDelay)
+	proVM := New(coreVM, time.Time{}, 0, DefaultMinBlockDelay, nil)
 
 	valState := &validators.TestState{
 		T: t,
@@ -1263,7 +1263,7 @@ func TestInnerVMRollback(t *testing.T) {
 
 	dbManager := manager.NewMemDB(version.Semantic1_0_0)
 
-	proVM := New(coreVM, time.Time{}, 0, DefaultMinBlockDelay)
+	proVM := New(coreVM, time.Time{}, 0, DefaultMinBlockDelay, nil)
 
 	err := proVM.Initialize(
 		context.Background(),
@@ -1365,7 +1365,7 @@ func TestInnerVMRollback(t *testing.T) {
 
 	coreBlk.StatusV = choices.Processing
 
-	proVM = New(coreVM, time.Time{}, 0, DefaultMinBlockDelay)
+	proVM = New(coreVM, time.Time{}, 0, DefaultMinBlockDelay, nil)
 
 	err = proVM.Initialize(
 		context.Background(),
@@ -1916,7 +1916,7 @@ func TestRejectedHeightNotIndexed(t *testing.T) {
 		}
 	}
 
-	proVM := New(coreVM, time.Time{}, 0, DefaultMinBlockDelay)
+	proVM := New(coreVM, time.Time{}, 0, DefaultMinBlockDelay, nil)
 
 	valState := &validators.TestState{
 		T: t,
@@ -2114,7 +2114,7 @@ func TestRejectedOptionHeightNotIndexed(t *testing.T) {
 		}
 	}
 
-	proVM := New(coreVM, time.Time{}, 0, DefaultMinBlockDelay)
+	proVM := New(coreVM, time.Time{}, 0, DefaultMinBlockDelay, nil)
 
 	valState := &validators.TestState{
 		T: t,
@@ -2268,6 +2268,7 @@ func TestVMInnerBlkCache(t *testing.T) {
 		time.Time{}, // fork is active
 		0,           // minimum P-Chain height
 		DefaultMinBlockDelay,
+		nil,
 	)
 
 	dummyDBManager := manager.NewMemDB(version.Semantic1_0_0)
@@ -2371,10 +2372,101 @@ func TestVMInnerBlkCache(t *testing.T) {
 
 	// When we verify [blk] we see that the inner block isn't in the tree
 	// (hasn't been verified) so we verify it and put it in the cahce.
-	err = vm.verifyAndRecordInnerBlk(context.Background(), blk)
+	err = vm.verifyAndRecordInnerBlk(context.Background(), nil, blk)
 	require.NoError(err)
 
 	gotBlk, ok = vm.innerBlkCache.Get(blkNearTip.ID())
 	require.True(ok)
 	require.Equal(newInnerBlock, gotBlk)
 }
+
+type contextBlock struct {
+	*snowman.TestBlock
+
+	// Contexts the block was verified with
+	verifiedWith []block.Context
+}
+
+func (*contextBlock) ShouldVerifyWithContext(context.Context) (bool, error) {
+	return true, nil
+}
+
+func (b *contextBlock) VerifyWithContext(_ context.Context, blockCtx *block.Context) error {
+	b.verifiedWith = append(b.verifiedWith, *blockCtx)
+	return nil
+}
+
+type contextBuilderVM struct {
+	blk snowman.Block
+
+	// Contexts blocks were built with
+	builtWith []block.Context
+}
+
+func (vm *contextBuilderVM) BuildBlockWithContext(_ context.Context, blockCtx *block.Context) (snowman.Block, error) {
+	vm.builtWith = append(vm.builtWith, *blockCtx)
+	return vm.blk, nil
+}
+
+func TestBlockContextIsProvidedToInnerVM(t *testing.T) {
+	require := require.New(t)
+
+	minPChainHeight := uint64(5)
+	coreVM, _, proVM, coreGenBlk, _ := initTestProposerVM(t, time.Time{}, minPChainHeight) // enable ProBlks
+	builder := &contextBuilderVM{}
+	proVM.bbVM = builder
+	coreVM.BuildBlockF = func(context.Context) (snowman.Block, error) {
+		require.FailNow("BuildBlock called rather than BuildBlockWithContext")
+		return nil, nil
+	}
+
+	// The first post-fork block is built and verified with the minimum P-Chain
+	// height.
+	coreBlk1 := &contextBlock{
+		TestBlock: &snowman.TestBlock{
+			TestDecidable: choices.TestDecidable{
+				IDV:     ids.GenerateTestID(),
+				StatusV: choices.Processing,
+			},
+			BytesV:     []byte{1},
+			ParentV:    coreGenBlk.ID(),
+			HeightV:    coreGenBlk.Height() + 1,
+			TimestampV: coreGenBlk.Timestamp(),
+		},
+	}
+	builder.blk = coreBlk1
+	proBlk1, err := proVM.BuildBlock(context.Background())
+	require.NoError(err)
+	require.NoError(proBlk1.Verify(context.Background()))
+	require.Equal([]block.Context{{PChainHeight: minPChainHeight}}, builder.builtWith)
+	require.Equal([]block.Context{{PChainHeight: minPChainHeight}}, coreBlk1.verifiedWith)
+
+	coreVM.SetPreferenceF = func(context.Context, ids.ID) error {
+		return nil
+	}
+	require.NoError(proVM.SetPreference(context.Background(), proBlk1.ID()))
+
+	// The following blocks are built and verified with the P-Chain height of
+	// their parent.
+	coreBlk2 := &contextBlock{
+		TestBlock: &snowman.TestBlock{
+			TestDecidable: choices.TestDecidable{
+				IDV:     ids.GenerateTestID(),
+				StatusV: choices.Processing,
+			},
+			BytesV:     []byte{2},
+			ParentV:    coreBlk1.ID(),
+			HeightV:    coreBlk1.Height() + 1,
+			TimestampV: coreBlk1.Timestamp(),
+		},
+	}
+	builder.blk = coreBlk2
+	proVM.Set(proBlk1.Timestamp().Add(proposer.MaxDelay))
+	proBlk2, err := proVM.BuildBlock(context.Background())
+	require.NoError(err)
+	require.NoError(proBlk2.Verify(context.Background()))
+
+	parentPChainHeight := proBlk1.(*postForkBlock).PChainHeight()
+	require.Equal(block.Context{PChainHeight: parentPChainHeight}, builder.builtWith[1])
+	require.Equal([]block.Context{{PChainHeight: parentPChainHeight}}, coreBlk2.verifiedWith)
+}
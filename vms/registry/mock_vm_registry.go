@@ -9,6 +9,7 @@ package registry
 
 import (
 	context "context"
+	io "io"
 	reflect "reflect"
 
 	ids "github.com/ava-labs/avalanchego/ids"
@@ -38,6 +39,34 @@ func (m *MockVMRegistry) EXPECT() *MockVMRegistryMockRecorder {
 	return m.recorder
 }
 
+// Install mocks base method.
+func (m *MockVMRegistry) Install(arg0 context.Context, arg1 ids.ID, arg2 io.Reader, arg3 [32]byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Install", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Install indicates an expected call of Install.
+func (mr *MockVMRegistryMockRecorder) Install(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Install", reflect.TypeOf((*MockVMRegistry)(nil).Install), arg0, arg1, arg2, arg3)
+}
+
+// InstallWithReadLock mocks base method.
+func (m *MockVMRegistry) InstallWithReadLock(arg0 context.Context, arg1 ids.ID, arg2 io.Reader, arg3 [32]byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InstallWithReadLock", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InstallWithReadLock indicates an expected call of InstallWithReadLock.
+func (mr *MockVMRegistryMockRecorder) InstallWithReadLock(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InstallWithReadLock", reflect.TypeOf((*MockVMRegistry)(nil).InstallWithReadLock), arg0, arg1, arg2, arg3)
+}
+
 // Reload mocks base method.
 func (m *MockVMRegistry) Reload(arg0 context.Context) ([]ids.ID, map[ids.ID]error, error) {
 	m.ctrl.T.Helper()
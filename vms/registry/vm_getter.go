@@ -7,9 +7,11 @@ import (
 	"errors"
 	"fmt"
 	"path/filepath"
+	"time"
 
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/utils/filesystem"
+	"github.com/ava-labs/avalanchego/utils/gc"
 	"github.com/ava-labs/avalanchego/utils/resource"
 	"github.com/ava-labs/avalanchego/vms"
 	"github.com/ava-labs/avalanchego/vms/rpcchainvm"
@@ -38,6 +40,43 @@ type VMGetterConfig struct {
 	Manager         vms.Manager
 	PluginDirectory string
 	CPUTracker      resource.ProcessTracker
+	// GCConfigs tunes the garbage collection of the plugin processes
+	GCConfigs gc.ChainConfigs
+	// If true, the plugins connect to the servers of the node over a single
+	// connection
+	Multiplex bool
+	// If true, the node and the plugins authenticate each other with
+	// ephemeral certificates
+	MTLS bool
+	// If true, the node and the plugins communicate over unix domain sockets
+	UnixSockets bool
+	// Number of pages of elements the iterators of the plugins' databases
+	// fetch ahead of their consumer
+	DBIteratorPrefetch int
+	// Max size, in bytes, of the elements the plugins send in a single request
+	// when writing a batch to their databases
+	DBMaxBatchBytes int
+	// If true, the plugins send and request a checksum with each value
+	// written to and read from their databases
+	DBChecksums bool
+	// Max duration the pending requests of the plugins to the servers of the
+	// node are given to finish when the plugins shut down
+	ShutdownDrainTimeout time.Duration
+	// Retries the calls to the plugins that fail because the plugins are
+	// unreachable, if the calls don't change the state of the plugins
+	RPCRetry rpcchainvm.RetryConfig
+	// If set, the plugin process of a chain that has limits in ResourceLimits
+	// is placed in a cgroup named after the chain under this cgroup
+	CgroupRoot     string
+	ResourceLimits resource.ChainLimits
+	// The services in PluginServices enabled for a chain in
+	// ChainPluginServices are served to the chain's plugin
+	PluginServices      rpcchainvm.PluginServices
+	ChainPluginServices rpcchainvm.ChainPluginServices
+	// VM name or ID -> connection to the VM, which runs outside of the node
+	// rather than in a plugin process. An external VM takes precedence over
+	// a plugin of the same VM.
+	ExternalVMs map[string]rpcchainvm.ExternalConfig
 }
 
 type vmGetter struct {
@@ -74,13 +113,9 @@ func (getter *vmGetter) Get() (map[ids.ID]vms.Factory, map[ids.ID]vms.Factory, e
 			continue
 		}
 
-		vmID, err := getter.config.Manager.Lookup(name)
+		vmID, err := getter.lookup(name)
 		if err != nil {
-			// there is no alias with plugin name, try to use full vmID.
-			vmID, err = ids.FromString(name)
-			if err != nil {
-				return nil, nil, fmt.Errorf("%w: %q", errInvalidVMID, name)
-			}
+			return nil, nil, err
 		}
 
 		registeredFactory, err := getter.config.Manager.GetFactory(vmID)
@@ -99,8 +134,57 @@ func (getter *vmGetter) Get() (map[ids.ID]vms.Factory, map[ids.ID]vms.Factory, e
 
 		unregisteredVMs[vmID] = rpcchainvm.NewFactory(
 			filepath.Join(getter.config.PluginDirectory, file.Name()),
-			getter.config.CPUTracker,
+			rpcchainvm.FactoryConfig{
+				ProcessTracker:       getter.config.CPUTracker,
+				GCConfigs:            getter.config.GCConfigs,
+				Multiplex:            getter.config.Multiplex,
+				MTLS:                 getter.config.MTLS,
+				UnixSockets:          getter.config.UnixSockets,
+				DBIteratorPrefetch:   getter.config.DBIteratorPrefetch,
+				DBMaxBatchBytes:      getter.config.DBMaxBatchBytes,
+				DBChecksums:          getter.config.DBChecksums,
+				ShutdownDrainTimeout: getter.config.ShutdownDrainTimeout,
+				RPCRetry:             getter.config.RPCRetry,
+				CgroupRoot:           getter.config.CgroupRoot,
+				ResourceLimits:       getter.config.ResourceLimits,
+				PluginServices:       getter.config.PluginServices,
+				ChainPluginServices:  getter.config.ChainPluginServices,
+			},
 		)
 	}
+
+	for name, config := range getter.config.ExternalVMs {
+		vmID, err := getter.lookup(name)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		registeredFactory, err := getter.config.Manager.GetFactory(vmID)
+		if err == nil {
+			registeredVMs[vmID] = registeredFactory
+			delete(unregisteredVMs, vmID)
+			continue
+		}
+		if !errors.Is(err, vms.ErrNotFound) {
+			return nil, nil, err
+		}
+
+		unregisteredVMs[vmID] = rpcchainvm.NewExternalFactory(config)
+	}
 	return registeredVMs, unregisteredVMs, nil
 }
+
+// lookup returns the ID of the VM named [name], which is either an alias or
+// the ID of the VM.
+func (getter *vmGetter) lookup(name string) (ids.ID, error) {
+	vmID, err := getter.config.Manager.Lookup(name)
+	if err == nil {
+		return vmID, nil
+	}
+	// there is no alias with plugin name, try to use full vmID.
+	vmID, err = ids.FromString(name)
+	if err != nil {
+		return ids.Empty, fmt.Errorf("%w: %q", errInvalidVMID, name)
+	}
+	return vmID, nil
+}
@@ -17,6 +17,7 @@ import (
 	"github.com/ava-labs/avalanchego/utils/filesystem"
 	"github.com/ava-labs/avalanchego/utils/resource"
 	"github.com/ava-labs/avalanchego/vms"
+	"github.com/ava-labs/avalanchego/vms/rpcchainvm"
 )
 
 var (
@@ -131,6 +132,37 @@ func TestGet_Success(t *testing.T) {
 	require.NoError(err)
 }
 
+// Get should return the external VMs, which take precedence over the plugins
+// of the same VM.
+func TestGet_ExternalVMs(t *testing.T) {
+	require := require.New(t)
+
+	resources := initVMGetterTest(t)
+	defer resources.ctrl.Finish()
+
+	getter := NewVMGetter(VMGetterConfig{
+		FileReader:      resources.mockReader,
+		Manager:         resources.mockManager,
+		PluginDirectory: pluginDir,
+		CPUTracker:      resource.NewManager("", time.Hour, time.Hour, time.Hour),
+		ExternalVMs: map[string]rpcchainvm.ExternalConfig{
+			unregisteredVMName: {Addr: "127.0.0.1:9000"},
+		},
+	})
+
+	unregisteredVMId := ids.GenerateTestID()
+
+	resources.mockReader.EXPECT().ReadDir(pluginDir).Times(1).Return([]fs.DirEntry{unregisteredVM}, nil)
+	resources.mockManager.EXPECT().Lookup(unregisteredVMName).Times(2).Return(unregisteredVMId, nil)
+	resources.mockManager.EXPECT().GetFactory(unregisteredVMId).Times(2).Return(nil, vms.ErrNotFound)
+
+	registeredVMs, unregisteredVMs, err := getter.Get()
+	require.NoError(err)
+	require.Empty(registeredVMs)
+	require.Len(unregisteredVMs, 1)
+	require.Equal(rpcchainvm.NewExternalFactory(rpcchainvm.ExternalConfig{Addr: "127.0.0.1:9000"}), unregisteredVMs[unregisteredVMId])
+}
+
 type vmGetterTestResources struct {
 	ctrl        *gomock.Controller
 	mockReader  *filesystem.MockReader
@@ -5,6 +5,7 @@ package registry
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"path"
 	"sync"
@@ -61,7 +62,15 @@ func (r *vmRegisterer) RegisterWithReadLock(ctx context.Context, vmID ids.ID, fa
 }
 
 func (r *vmRegisterer) register(ctx context.Context, pathAdder server.PathAdder, vmID ids.ID, factory vms.Factory) error {
-	if err := r.config.VMManager.RegisterFactory(ctx, vmID, factory); err != nil {
+	err := r.config.VMManager.RegisterFactory(ctx, vmID, factory)
+	switch {
+	case errors.Is(err, vms.ErrNotDescribed):
+		// The VM is registered, so it is still served.
+		r.config.Log.Warn("failed to describe vm",
+			zap.Stringer("vmID", vmID),
+			zap.Error(err),
+		)
+	case err != nil:
 		return err
 	}
 	handlers, err := r.createStaticHandlers(ctx, vmID, factory)
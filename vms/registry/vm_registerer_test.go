@@ -85,6 +85,23 @@ func TestRegisterCreateHandlersFails(t *testing.T) {
 	require.ErrorIs(t, resources.registerer.Register(context.Background(), id, vmFactory), errOops)
 }
 
+// Tests Register if the VM is registered but can't be described
+func TestRegisterNotDescribed(t *testing.T) {
+	resources := initRegistererTest(t)
+	defer resources.ctrl.Finish()
+
+	vmFactory := vms.NewMockFactory(resources.ctrl)
+	vm := mocks.NewMockChainVM(resources.ctrl)
+
+	resources.mockManager.EXPECT().RegisterFactory(gomock.Any(), id, vmFactory).Times(1).Return(vms.ErrNotDescribed)
+	// The VM is still served
+	vmFactory.EXPECT().New(nil).Times(1).Return(vm, nil)
+	vm.EXPECT().CreateStaticHandlers(gomock.Any()).Return(nil, errOops).Times(1)
+	vm.EXPECT().Shutdown(gomock.Any()).Return(nil).Times(1)
+
+	require.ErrorIs(t, resources.registerer.Register(context.Background(), id, vmFactory), errOops)
+}
+
 // Tests Register if we fail to register the new endpoint on the server.
 func TestRegisterAddRouteFails(t *testing.T) {
 	resources := initRegistererTest(t)
@@ -4,12 +4,32 @@
 package registry
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
 
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/hashing"
+	"github.com/ava-labs/avalanchego/utils/perms"
 )
 
-var _ VMRegistry = (*vmRegistry)(nil)
+// maxPluginSize is the max size, in bytes, of an installed plugin binary
+const maxPluginSize = 1 << 30
+
+var (
+	errAlreadyInstalled = errors.New("vm is already installed")
+	errPluginTooLarge   = errors.New("plugin binary is too large")
+	errChecksumMismatch = errors.New("plugin binary doesn't match the checksum")
+	errNotInstallable   = errors.New("installed plugin isn't available to be registered")
+
+	_ VMRegistry = (*vmRegistry)(nil)
+)
 
 // VMRegistry defines functionality to get any new virtual machines on the node,
 // and install them if they're not already installed.
@@ -19,16 +39,29 @@ type VMRegistry interface {
 	// ReloadWithReadLock installs all non-installed vms on the node assuming
 	// the http read lock is currently held.
 	ReloadWithReadLock(ctx context.Context) ([]ids.ID, map[ids.ID]error, error)
+	// Install writes the plugin binary read from [plugin] into the plugin
+	// directory and installs it as the VM [vmID], if the SHA256 hash of the
+	// binary is [checksum]. The VM can then be used to create chains.
+	Install(ctx context.Context, vmID ids.ID, plugin io.Reader, checksum hashing.Hash256) error
+	// InstallWithReadLock installs the plugin assuming the http read lock is
+	// currently held.
+	InstallWithReadLock(ctx context.Context, vmID ids.ID, plugin io.Reader, checksum hashing.Hash256) error
 }
 
 // VMRegistryConfig defines configurations for VMRegistry
 type VMRegistryConfig struct {
 	VMGetter     VMGetter
 	VMRegisterer VMRegisterer
+	// Directory the installed plugins are written to. It must be the directory
+	// the plugins are read from by [VMGetter].
+	PluginDirectory string
 }
 
 type vmRegistry struct {
 	config VMRegistryConfig
+
+	// Prevents a VM from being registered by both a reload and an install
+	lock sync.Mutex
 }
 
 // NewVMRegistry returns a VMRegistry
@@ -49,6 +82,9 @@ func (r *vmRegistry) ReloadWithReadLock(ctx context.Context) ([]ids.ID, map[ids.
 }
 
 func (r *vmRegistry) reload(ctx context.Context, registerer registerer) ([]ids.ID, map[ids.ID]error, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
 	_, unregisteredVMs, err := r.config.VMGetter.Get()
 	if err != nil {
 		return nil, nil, err
@@ -67,3 +103,99 @@ func (r *vmRegistry) reload(ctx context.Context, registerer registerer) ([]ids.I
 	}
 	return registeredVms, failedVMs, nil
 }
+
+func (r *vmRegistry) Install(ctx context.Context, vmID ids.ID, plugin io.Reader, checksum hashing.Hash256) error {
+	return r.install(ctx, r.config.VMRegisterer, vmID, plugin, checksum)
+}
+
+func (r *vmRegistry) InstallWithReadLock(ctx context.Context, vmID ids.ID, plugin io.Reader, checksum hashing.Hash256) error {
+	return r.install(ctx, readRegisterer{
+		registerer: r.config.VMRegisterer,
+	}, vmID, plugin, checksum)
+}
+
+func (r *vmRegistry) install(
+	ctx context.Context,
+	registerer registerer,
+	vmID ids.ID,
+	plugin io.Reader,
+	checksum hashing.Hash256,
+) error {
+	// Reading the plugin may take a long time, such as when it's downloaded,
+	// so it's written and verified before the lock is grabbed.
+	tempPath, err := writeTempPlugin(r.config.PluginDirectory, plugin, checksum)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		// Once the file is renamed, removing the temporary file fails.
+		_ = os.Remove(tempPath)
+	}()
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	registeredVMs, _, err := r.config.VMGetter.Get()
+	if err != nil {
+		return err
+	}
+	if _, ok := registeredVMs[vmID]; ok {
+		return fmt.Errorf("%w: %s", errAlreadyInstalled, vmID)
+	}
+	pluginPath := filepath.Join(r.config.PluginDirectory, vmID.String())
+	if _, err := os.Stat(pluginPath); !errors.Is(err, os.ErrNotExist) {
+		// The plugin is already in the plugin directory, and is installed by
+		// reloading the VMs.
+		return fmt.Errorf("%w: %s exists", errAlreadyInstalled, pluginPath)
+	}
+
+	if err := os.Rename(tempPath, pluginPath); err != nil {
+		return err
+	}
+
+	_, unregisteredVMs, err := r.config.VMGetter.Get()
+	if err != nil {
+		_ = os.Remove(pluginPath)
+		return err
+	}
+	factory, ok := unregisteredVMs[vmID]
+	if !ok {
+		_ = os.Remove(pluginPath)
+		return fmt.Errorf("%w: %s", errNotInstallable, vmID)
+	}
+	if err := registerer.Register(ctx, vmID, factory); err != nil {
+		_ = os.Remove(pluginPath)
+		return err
+	}
+	return nil
+}
+
+// writeTempPlugin writes the binary read from [plugin] to a temporary file in
+// [pluginDir] if its SHA256 hash is [checksum], and returns the path of the
+// file. The file is hidden, so it's never mistaken for a plugin.
+func writeTempPlugin(pluginDir string, plugin io.Reader, checksum hashing.Hash256) (string, error) {
+	f, err := os.CreateTemp(pluginDir, ".install-*")
+	if err != nil {
+		return "", err
+	}
+
+	hasher := sha256.New()
+	n, err := io.Copy(io.MultiWriter(f, hasher), io.LimitReader(plugin, maxPluginSize+1))
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	switch hash := hasher.Sum(nil); {
+	case err != nil:
+	case n > maxPluginSize:
+		err = fmt.Errorf("%w: more than %d bytes", errPluginTooLarge, maxPluginSize)
+	case !bytes.Equal(hash, checksum[:]):
+		err = fmt.Errorf("%w: got %x, expected %x", errChecksumMismatch, hash, checksum)
+	default:
+		err = os.Chmod(f.Name(), perms.ReadWriteExecute)
+	}
+	if err != nil {
+		_ = os.Remove(f.Name())
+		return "", err
+	}
+	return f.Name(), nil
+}
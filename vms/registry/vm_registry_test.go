@@ -4,7 +4,11 @@
 package registry
 
 import (
+	"bytes"
 	"context"
+	"io"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/golang/mock/gomock"
@@ -12,6 +16,8 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/hashing"
+	"github.com/ava-labs/avalanchego/utils/perms"
 	"github.com/ava-labs/avalanchego/vms"
 )
 
@@ -215,6 +221,141 @@ type registryTestResources struct {
 	mockVMGetter     *MockVMGetter
 	mockVMRegisterer *MockVMRegisterer
 	vmRegistry       VMRegistry
+	pluginDir        string
+}
+
+// Tests that an installed plugin is written to the plugin directory and
+// registered.
+func TestInstall_Success(t *testing.T) {
+	require := require.New(t)
+
+	resources := initVMRegistryTest(t)
+	defer resources.ctrl.Finish()
+
+	plugin := []byte("plugin")
+	factory := vms.NewMockFactory(resources.ctrl)
+	gomock.InOrder(
+		resources.mockVMGetter.EXPECT().
+			Get().
+			Return(map[ids.ID]vms.Factory{}, map[ids.ID]vms.Factory{}, nil),
+		resources.mockVMGetter.EXPECT().
+			Get().
+			Return(map[ids.ID]vms.Factory{}, map[ids.ID]vms.Factory{id1: factory}, nil),
+	)
+	resources.mockVMRegisterer.EXPECT().
+		Register(gomock.Any(), id1, factory).
+		Times(1).
+		Return(nil)
+
+	err := resources.vmRegistry.Install(context.Background(), id1, bytes.NewReader(plugin), hashing.ComputeHash256Array(plugin))
+	require.NoError(err)
+
+	pluginPath := filepath.Join(resources.pluginDir, id1.String())
+	installed, err := os.ReadFile(pluginPath)
+	require.NoError(err)
+	require.Equal(plugin, installed)
+	info, err := os.Stat(pluginPath)
+	require.NoError(err)
+	require.Equal(os.FileMode(perms.ReadWriteExecute), info.Mode().Perm())
+}
+
+// Tests that a plugin that doesn't match its checksum isn't installed.
+func TestInstall_ChecksumMismatch(t *testing.T) {
+	require := require.New(t)
+
+	resources := initVMRegistryTest(t)
+	defer resources.ctrl.Finish()
+
+	// The plugin is verified before the registered VMs are read.
+	err := resources.vmRegistry.Install(context.Background(), id1, bytes.NewReader([]byte("plugin")), hashing.ComputeHash256Array([]byte("other")))
+	require.ErrorIs(err, errChecksumMismatch)
+
+	files, err := os.ReadDir(resources.pluginDir)
+	require.NoError(err)
+	require.Empty(files)
+}
+
+// lockCheckingReader fails the test if the lock of [registry] is held while
+// the plugin is read.
+type lockCheckingReader struct {
+	t        *testing.T
+	registry *vmRegistry
+	plugin   io.Reader
+}
+
+func (r *lockCheckingReader) Read(p []byte) (int, error) {
+	require.True(r.t, r.registry.lock.TryLock(), "plugin read while holding the lock")
+	r.registry.lock.Unlock()
+	return r.plugin.Read(p)
+}
+
+// Tests that the plugin is read without holding the lock of the registry.
+func TestInstall_ReadsWithoutLock(t *testing.T) {
+	resources := initVMRegistryTest(t)
+	defer resources.ctrl.Finish()
+
+	resources.mockVMGetter.EXPECT().
+		Get().
+		Times(1).
+		Return(map[ids.ID]vms.Factory{id1: vms.NewMockFactory(resources.ctrl)}, map[ids.ID]vms.Factory{}, nil)
+
+	plugin := []byte("plugin")
+	err := resources.vmRegistry.Install(context.Background(), id1, &lockCheckingReader{
+		t:        t,
+		registry: resources.vmRegistry.(*vmRegistry),
+		plugin:   bytes.NewReader(plugin),
+	}, hashing.ComputeHash256Array(plugin))
+	require.ErrorIs(t, err, errAlreadyInstalled)
+}
+
+// Tests that an installed VM isn't replaced.
+func TestInstall_AlreadyInstalled(t *testing.T) {
+	resources := initVMRegistryTest(t)
+	defer resources.ctrl.Finish()
+
+	resources.mockVMGetter.EXPECT().
+		Get().
+		Times(1).
+		Return(map[ids.ID]vms.Factory{id1: vms.NewMockFactory(resources.ctrl)}, map[ids.ID]vms.Factory{}, nil)
+
+	plugin := []byte("plugin")
+	err := resources.vmRegistry.Install(context.Background(), id1, bytes.NewReader(plugin), hashing.ComputeHash256Array(plugin))
+	require.ErrorIs(t, err, errAlreadyInstalled)
+
+	// The plugin that was read isn't left in the plugin directory.
+	files, err := os.ReadDir(resources.pluginDir)
+	require.NoError(t, err)
+	require.Empty(t, files)
+}
+
+// Tests that the plugin is removed if the VM can't be registered.
+func TestInstall_RegisterFails(t *testing.T) {
+	require := require.New(t)
+
+	resources := initVMRegistryTest(t)
+	defer resources.ctrl.Finish()
+
+	factory := vms.NewMockFactory(resources.ctrl)
+	gomock.InOrder(
+		resources.mockVMGetter.EXPECT().
+			Get().
+			Return(map[ids.ID]vms.Factory{}, map[ids.ID]vms.Factory{}, nil),
+		resources.mockVMGetter.EXPECT().
+			Get().
+			Return(map[ids.ID]vms.Factory{}, map[ids.ID]vms.Factory{id1: factory}, nil),
+	)
+	resources.mockVMRegisterer.EXPECT().
+		Register(gomock.Any(), id1, factory).
+		Times(1).
+		Return(errOops)
+
+	plugin := []byte("plugin")
+	err := resources.vmRegistry.Install(context.Background(), id1, bytes.NewReader(plugin), hashing.ComputeHash256Array(plugin))
+	require.ErrorIs(err, errOops)
+
+	files, err := os.ReadDir(resources.pluginDir)
+	require.NoError(err)
+	require.Empty(files)
 }
 
 func initVMRegistryTest(t *testing.T) *registryTestResources {
@@ -223,10 +364,12 @@ func initVMRegistryTest(t *testing.T) *registryTestResources {
 	mockVMGetter := NewMockVMGetter(ctrl)
 	mockVMRegisterer := NewMockVMRegisterer(ctrl)
 
+	pluginDir := t.TempDir()
 	vmRegistry := NewVMRegistry(
 		VMRegistryConfig{
-			VMGetter:     mockVMGetter,
-			VMRegisterer: mockVMRegisterer,
+			VMGetter:        mockVMGetter,
+			VMRegisterer:    mockVMRegisterer,
+			PluginDirectory: pluginDir,
 		},
 	)
 
@@ -235,5 +378,6 @@ func initVMRegistryTest(t *testing.T) *registryTestResources {
 		mockVMGetter:     mockVMGetter,
 		mockVMRegisterer: mockVMRegisterer,
 		vmRegistry:       vmRegistry,
+		pluginDir:        pluginDir,
 	}
 }
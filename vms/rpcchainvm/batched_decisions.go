@@ -0,0 +1,134 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpcchainvm
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/ava-labs/avalanchego/ids"
+
+	vmpb "github.com/ava-labs/avalanchego/proto/pb/vm"
+)
+
+// Maximum number of decisions sent to the plugin in a single request
+const maxDecisionBatchSize = 1024
+
+// decisionBatch is a run of blocks that are all accepted, or all rejected.
+type decisionBatch struct {
+	accept bool
+	ids    [][]byte
+}
+
+// BatchDecisions calls [f], and sends the blocks that [f] accepted and
+// rejected to the plugin once [f] returns, rather than one at a time. Each
+// run of accepted, or rejected, blocks is sent in a single request of at most
+// [maxDecisionBatchSize] blocks.
+func (vm *VMClient) BatchDecisions(ctx context.Context, f func(context.Context) error) error {
+	if vm.batchingDecisions {
+		return f(ctx)
+	}
+
+	vm.batchingDecisions = true
+	err := f(ctx)
+	vm.batchingDecisions = false
+
+	// The decisions made before [f] failed are still sent, as the blocks have
+	// already been marked as decided.
+	if flushErr := vm.flushDecisions(ctx); err == nil {
+		err = flushErr
+	}
+	return err
+}
+
+// decide accepts, or rejects, the block [blkID] in the plugin. During
+// BatchDecisions, the decision is queued instead.
+func (vm *VMClient) decide(ctx context.Context, blkID ids.ID, accept bool) error {
+	if !vm.batchingDecisions {
+		return vm.sendDecision(ctx, blkID[:], accept)
+	}
+
+	if len(vm.pendingDecisions.ids) > 0 && vm.pendingDecisions.accept != accept {
+		if err := vm.flushDecisions(ctx); err != nil {
+			return err
+		}
+	}
+	vm.pendingDecisions.accept = accept
+	vm.pendingDecisions.ids = append(vm.pendingDecisions.ids, blkID[:])
+	if len(vm.pendingDecisions.ids) >= maxDecisionBatchSize {
+		return vm.flushDecisions(ctx)
+	}
+	return nil
+}
+
+// flushDecisions sends the queued decisions to the plugin.
+func (vm *VMClient) flushDecisions(ctx context.Context) error {
+	batch := vm.pendingDecisions
+	vm.pendingDecisions = decisionBatch{}
+	if len(batch.ids) == 0 {
+		return nil
+	}
+
+	if vm.capabilities.Has(CapabilityBatchedDecisions) {
+		var err error
+		if batch.accept {
+			_, err = vm.client.BatchedBlockAccept(ctx, &vmpb.BatchedBlockAcceptRequest{
+				Ids: batch.ids,
+			})
+		} else {
+			_, err = vm.client.BatchedBlockReject(ctx, &vmpb.BatchedBlockRejectRequest{
+				Ids: batch.ids,
+			})
+		}
+		if status.Code(err) != codes.Unimplemented {
+			return err
+		}
+
+		// The plugin predates batched decisions.
+		vm.capabilities &^= CapabilityBatchedDecisions
+	}
+
+	for _, blkID := range batch.ids {
+		if err := vm.sendDecision(ctx, blkID, batch.accept); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (vm *VMClient) sendDecision(ctx context.Context, blkID []byte, accept bool) error {
+	var err error
+	if accept {
+		_, err = vm.client.BlockAccept(ctx, &vmpb.BlockAcceptRequest{
+			Id: blkID,
+		})
+	} else {
+		_, err = vm.client.BlockReject(ctx, &vmpb.BlockRejectRequest{
+			Id: blkID,
+		})
+	}
+	return err
+}
+
+func (vm *VMServer) BatchedBlockAccept(ctx context.Context, req *vmpb.BatchedBlockAcceptRequest) (*emptypb.Empty, error) {
+	for _, blkID := range req.Ids {
+		if _, err := vm.BlockAccept(ctx, &vmpb.BlockAcceptRequest{Id: blkID}); err != nil {
+			return nil, err
+		}
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (vm *VMServer) BatchedBlockReject(ctx context.Context, req *vmpb.BatchedBlockRejectRequest) (*emptypb.Empty, error) {
+	for _, blkID := range req.Ids {
+		if _, err := vm.BlockReject(ctx, &vmpb.BlockRejectRequest{Id: blkID}); err != nil {
+			return nil, err
+		}
+	}
+	return &emptypb.Empty{}, nil
+}
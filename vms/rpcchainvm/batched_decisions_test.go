@@ -0,0 +1,156 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpcchainvm
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
+	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
+
+	vmpb "github.com/ava-labs/avalanchego/proto/pb/vm"
+)
+
+// decisionServer is a plugin that records the decision requests it receives.
+// If [legacy] is set, it doesn't serve batched decisions.
+type decisionServer struct {
+	*VMServer
+
+	legacy bool
+
+	lock     sync.Mutex
+	requests []string
+}
+
+func (s *decisionServer) record(method string, blkIDs [][]byte) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	request := method
+	for _, blkID := range blkIDs {
+		request += fmt.Sprintf(" %d", blkID[0])
+	}
+	s.requests = append(s.requests, request)
+}
+
+func (s *decisionServer) BlockAccept(ctx context.Context, req *vmpb.BlockAcceptRequest) (*emptypb.Empty, error) {
+	s.record("accept", [][]byte{req.Id})
+	return s.VMServer.BlockAccept(ctx, req)
+}
+
+func (s *decisionServer) BlockReject(ctx context.Context, req *vmpb.BlockRejectRequest) (*emptypb.Empty, error) {
+	s.record("reject", [][]byte{req.Id})
+	return s.VMServer.BlockReject(ctx, req)
+}
+
+func (s *decisionServer) BatchedBlockAccept(ctx context.Context, req *vmpb.BatchedBlockAcceptRequest) (*emptypb.Empty, error) {
+	if s.legacy {
+		return nil, status.Error(codes.Unimplemented, "unimplemented")
+	}
+	s.record("batchedAccept", req.Ids)
+	return s.VMServer.BatchedBlockAccept(ctx, req)
+}
+
+func (s *decisionServer) BatchedBlockReject(ctx context.Context, req *vmpb.BatchedBlockRejectRequest) (*emptypb.Empty, error) {
+	if s.legacy {
+		return nil, status.Error(codes.Unimplemented, "unimplemented")
+	}
+	s.record("batchedReject", req.Ids)
+	return s.VMServer.BatchedBlockReject(ctx, req)
+}
+
+// setupDecisions returns a client of a plugin with 4 processing blocks, whose
+// IDs start with the bytes 0 to 3.
+func setupDecisions(t *testing.T, legacy bool) (*VMClient, *decisionServer, []*snowman.TestBlock, []*blockClient) {
+	blks := make([]*snowman.TestBlock, 4)
+	for i := range blks {
+		blks[i] = &snowman.TestBlock{TestDecidable: choices.TestDecidable{
+			IDV:     ids.ID{byte(i)},
+			StatusV: choices.Processing,
+		}}
+	}
+	chainVM := &block.TestVM{}
+	chainVM.T = t
+	chainVM.GetBlockF = func(_ context.Context, blkID ids.ID) (snowman.Block, error) {
+		return blks[blkID[0]], nil
+	}
+
+	server := &decisionServer{
+		VMServer: NewServer(chainVM),
+		legacy:   legacy,
+	}
+	vm := NewClient(serveVM(t, server))
+	clients := make([]*blockClient, len(blks))
+	for i, blk := range blks {
+		clients[i] = &blockClient{
+			vm:     vm,
+			id:     blk.ID(),
+			status: choices.Processing,
+		}
+	}
+	return vm, server, blks, clients
+}
+
+func TestBatchDecisions(t *testing.T) {
+	require := require.New(t)
+
+	vm, server, blks, clients := setupDecisions(t, false)
+	err := vm.BatchDecisions(context.Background(), func(ctx context.Context) error {
+		require.NoError(clients[0].Accept(ctx))
+		require.NoError(clients[1].Accept(ctx))
+		require.Empty(server.requests)
+
+		require.NoError(clients[2].Reject(ctx))
+		require.NoError(clients[3].Accept(ctx))
+		return nil
+	})
+	require.NoError(err)
+
+	// Each run of decisions is sent in a single request, in order.
+	require.Equal([]string{
+		"batchedAccept 0 1",
+		"batchedReject 2",
+		"batchedAccept 3",
+	}, server.requests)
+	require.Equal(choices.Accepted, blks[0].Status())
+	require.Equal(choices.Accepted, blks[1].Status())
+	require.Equal(choices.Rejected, blks[2].Status())
+	require.Equal(choices.Accepted, blks[3].Status())
+	require.Equal(choices.Rejected, clients[2].Status())
+
+	// Outside of BatchDecisions, decisions are sent as they are made.
+	vm, server, _, clients = setupDecisions(t, false)
+	require.NoError(clients[0].Accept(context.Background()))
+	require.Equal([]string{"accept 0"}, server.requests)
+	require.False(vm.batchingDecisions)
+}
+
+func TestBatchDecisionsLegacyPlugin(t *testing.T) {
+	require := require.New(t)
+
+	vm, server, blks, clients := setupDecisions(t, true)
+	err := vm.BatchDecisions(context.Background(), func(ctx context.Context) error {
+		require.NoError(clients[0].Accept(ctx))
+		require.NoError(clients[1].Reject(ctx))
+		return nil
+	})
+	require.NoError(err)
+
+	require.Equal([]string{"accept 0", "reject 1"}, server.requests)
+	require.Equal(choices.Accepted, blks[0].Status())
+	require.Equal(choices.Rejected, blks[1].Status())
+	require.False(vm.capabilities.Has(CapabilityBatchedDecisions))
+}
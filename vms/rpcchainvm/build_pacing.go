@@ -0,0 +1,39 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpcchainvm
+
+import (
+	"time"
+
+	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
+
+	vmpb "github.com/ava-labs/avalanchego/proto/pb/vm"
+)
+
+// SetBuildPacing forwards [pacing] to the plugin when it is initialized. The
+// plugin is given the pacing if its VM implements block.BuildPacingVM.
+func (vm *VMClient) SetBuildPacing(pacing block.BuildPacing) {
+	vm.buildPacing = &pacing
+}
+
+func buildPacingToProto(pacing *block.BuildPacing) *vmpb.BuildPacing {
+	if pacing == nil {
+		return nil
+	}
+	return &vmpb.BuildPacing{
+		MinInterval:   int64(pacing.MinInterval),
+		MinPendingTxs: pacing.MinPendingTxs,
+	}
+}
+
+func buildPacingFromProto(pacing *vmpb.BuildPacing) (*block.BuildPacing, error) {
+	if pacing == nil {
+		return nil, nil
+	}
+	p := &block.BuildPacing{
+		MinInterval:   time.Duration(pacing.MinInterval),
+		MinPendingTxs: pacing.MinPendingTxs,
+	}
+	return p, p.Verify()
+}
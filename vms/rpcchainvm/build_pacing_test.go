@@ -0,0 +1,39 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpcchainvm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
+
+	vmpb "github.com/ava-labs/avalanchego/proto/pb/vm"
+)
+
+func TestBuildPacingProto(t *testing.T) {
+	require := require.New(t)
+
+	vm := NewClient(nil)
+	vm.SetBuildPacing(block.BuildPacing{
+		MinInterval:   time.Second,
+		MinPendingTxs: 10,
+	})
+
+	// The plugin is given the same pacing.
+	pacing, err := buildPacingFromProto(buildPacingToProto(vm.buildPacing))
+	require.NoError(err)
+	require.Equal(vm.buildPacing, pacing)
+
+	pacing, err = buildPacingFromProto(buildPacingToProto(nil))
+	require.NoError(err)
+	require.Nil(pacing)
+
+	_, err = buildPacingFromProto(&vmpb.BuildPacing{
+		MinInterval: -1,
+	})
+	require.Error(err)
+}
@@ -0,0 +1,104 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpcchainvm
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	defaultDecidedCacheSize    = 2048
+	defaultMissingCacheSize    = 2048
+	defaultUnverifiedCacheSize = 2048
+	defaultBytesToIDCacheSize  = 2048
+)
+
+var errNegativeCacheSize = errors.New("cache size must be >= 0")
+
+// CacheConfig sizes the caches of the blocks of the chain that the node keeps
+// in front of the plugin. Each size left to 0 keeps its default.
+//
+// The cache sizes are set in the cache config file of the chain, next to its
+// chain config, rather than in the chain config, which only the plugin
+// interprets. For example:
+//
+//	{"decided": 8192, "missing": 512}
+type CacheConfig struct {
+	// Number of decided blocks cached
+	Decided int `json:"decided"`
+	// Number of IDs of blocks known to be missing cached
+	Missing int `json:"missing"`
+	// Number of verified blocks that aren't processing cached
+	Unverified int `json:"unverified"`
+	// Number of block bytes -> block ID mappings cached
+	BytesToID int `json:"bytesToID"`
+}
+
+func defaultCacheConfig() CacheConfig {
+	return CacheConfig{
+		Decided:    defaultDecidedCacheSize,
+		Missing:    defaultMissingCacheSize,
+		Unverified: defaultUnverifiedCacheSize,
+		BytesToID:  defaultBytesToIDCacheSize,
+	}
+}
+
+// SetCacheConfig sizes the caches of the blocks of the chain with
+// [configBytes], the JSON encoding of a CacheConfig.
+func (vm *VMClient) SetCacheConfig(configBytes []byte) error {
+	config, err := parseCacheConfig(configBytes)
+	if err != nil {
+		return err
+	}
+	vm.cacheConfig = &config
+	return nil
+}
+
+// parseCacheConfig returns the cache sizes set in the cache config
+// [configBytes], with the defaults for the sizes it doesn't set.
+func parseCacheConfig(configBytes []byte) (CacheConfig, error) {
+	config := defaultCacheConfig()
+
+	sizes := CacheConfig{}
+	if err := json.Unmarshal(configBytes, &sizes); err != nil {
+		return config, fmt.Errorf("couldn't parse cache config: %w", err)
+	}
+	for _, size := range []struct {
+		value    int
+		override *int
+	}{
+		{sizes.Decided, &config.Decided},
+		{sizes.Missing, &config.Missing},
+		{sizes.Unverified, &config.Unverified},
+		{sizes.BytesToID, &config.BytesToID},
+	} {
+		switch {
+		case size.value < 0:
+			return config, errNegativeCacheSize
+		case size.value > 0:
+			*size.override = size.value
+		}
+	}
+	return config, nil
+}
+
+// registerCacheSizes reports the sizes of the caches in [config] as metrics.
+func registerCacheSizes(registerer prometheus.Registerer, config CacheConfig) error {
+	cacheSizes := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "state_cache_max_size",
+			Help: "Max number of entries of each cache of the blocks of the chain",
+		},
+		[]string{"cache"},
+	)
+	cacheSizes.WithLabelValues("decided").Set(float64(config.Decided))
+	cacheSizes.WithLabelValues("missing").Set(float64(config.Missing))
+	cacheSizes.WithLabelValues("unverified").Set(float64(config.Unverified))
+	cacheSizes.WithLabelValues("bytes_to_id").Set(float64(config.BytesToID))
+	return registerer.Register(cacheSizes)
+}
@@ -0,0 +1,106 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpcchainvm
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCacheConfig(t *testing.T) {
+	tests := []struct {
+		name           string
+		configBytes    string
+		expectedConfig CacheConfig
+		expectedErr    bool
+	}{
+		{
+			name:           "unset",
+			configBytes:    `{}`,
+			expectedConfig: defaultCacheConfig(),
+		},
+		{
+			name:        "overrides",
+			configBytes: `{"decided":8192,"missing":512}`,
+			expectedConfig: CacheConfig{
+				Decided:    8192,
+				Missing:    512,
+				Unverified: defaultUnverifiedCacheSize,
+				BytesToID:  defaultBytesToIDCacheSize,
+			},
+		},
+		{
+			name:        "negative",
+			configBytes: `{"unverified":-1}`,
+			expectedErr: true,
+		},
+		{
+			name:        "invalid",
+			configBytes: `1`,
+			expectedErr: true,
+		},
+		{
+			name:        "not json",
+			configBytes: "key=value",
+			expectedErr: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require := require.New(t)
+
+			config, err := parseCacheConfig([]byte(test.configBytes))
+			if test.expectedErr {
+				require.Error(err)
+				return
+			}
+			require.NoError(err)
+			require.Equal(test.expectedConfig, config)
+		})
+	}
+}
+
+func TestSetCacheConfig(t *testing.T) {
+	require := require.New(t)
+
+	vm := NewClient(nil)
+	require.Nil(vm.cacheConfig)
+
+	require.NoError(vm.SetCacheConfig([]byte(`{"bytesToID":16}`)))
+	expectedConfig := defaultCacheConfig()
+	expectedConfig.BytesToID = 16
+	require.Equal(&expectedConfig, vm.cacheConfig)
+
+	require.ErrorIs(vm.SetCacheConfig([]byte(`{"decided":-1}`)), errNegativeCacheSize)
+}
+
+func TestRegisterCacheSizes(t *testing.T) {
+	require := require.New(t)
+
+	registry := prometheus.NewRegistry()
+	require.NoError(registerCacheSizes(registry, CacheConfig{
+		Decided:    1,
+		Missing:    2,
+		Unverified: 3,
+		BytesToID:  4,
+	}))
+
+	families, err := registry.Gather()
+	require.NoError(err)
+	require.Len(families, 1)
+
+	sizes := make(map[string]float64)
+	for _, metric := range families[0].GetMetric() {
+		sizes[metric.GetLabel()[0].GetValue()] = metric.GetGauge().GetValue()
+	}
+	require.Equal(map[string]float64{
+		"decided":     1,
+		"missing":     2,
+		"unverified":  3,
+		"bytes_to_id": 4,
+	}, sizes)
+}
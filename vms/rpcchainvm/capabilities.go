@@ -0,0 +1,246 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpcchainvm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/ava-labs/avalanchego/version"
+	"github.com/ava-labs/avalanchego/vms"
+	"github.com/ava-labs/avalanchego/vms/rpcchainvm/grpcutils"
+
+	vmpb "github.com/ava-labs/avalanchego/proto/pb/vm"
+)
+
+const (
+	// CapabilityBatchedChainVM is reported by plugins that serve
+	// BatchedParseBlock, BatchedGetBlock and GetAncestors.
+	CapabilityBatchedChainVM Capabilities = 1 << iota
+	// CapabilityHeightIndexed is reported by plugins whose VM implements
+	// block.HeightIndexedChainVM.
+	CapabilityHeightIndexed
+	// CapabilityStateSyncable is reported by plugins whose VM implements
+	// block.StateSyncableVM.
+	CapabilityStateSyncable
+	// CapabilityDecoder is reported by plugins whose VM implements
+	// block.Decoder.
+	CapabilityDecoder
+	// CapabilityGossipStream is reported by plugins that serve
+	// AppGossipStream.
+	CapabilityGossipStream
+	// CapabilityBatchedDecisions is reported by plugins that serve
+	// BatchedBlockAccept and BatchedBlockReject.
+	CapabilityBatchedDecisions
+	// CapabilityStateDiffer is reported by plugins whose VM implements
+	// block.StateDiffer.
+	CapabilityStateDiffer
+	// CapabilityVerifyByID is reported by plugins that verify the blocks they
+	// already parsed or built by ID, rather than by bytes.
+	CapabilityVerifyByID
+	// CapabilityCompression is reported by plugins that decompress the
+	// requests sent with grpcutils.GzipCompressorName.
+	CapabilityCompression
+	// CapabilityBlockContext is reported by plugins whose VM implements
+	// block.BuildBlockWithContextChainVM.
+	CapabilityBlockContext
+	// CapabilityOracleBlock is reported by plugins that report which of their
+	// blocks are oracle blocks, and serve BlockOptions. The options of a block
+	// are only requested if the plugin reports it as an oracle block.
+	CapabilityOracleBlock
+	// CapabilityZstdCompression is reported by plugins that decompress the
+	// requests sent with grpcutils.ZstdCompressorName. It's preferred to
+	// CapabilityCompression.
+	CapabilityZstdCompression
+	// CapabilityMessageVerifier is reported by plugins whose VM implements
+	// block.MessageVerifier.
+	CapabilityMessageVerifier
+
+	// Plugins that predate the handshake are assumed to have every capability,
+	// and the calls they don't support fall back as they are made.
+	// CapabilityVerifyByID is excluded, as a plugin that doesn't support it
+	// fails to verify the block rather than reporting the call as
+	// unimplemented. CapabilityCompression and CapabilityZstdCompression are
+	// excluded, as a plugin that doesn't support them fails every compressed
+	// call.
+	legacyCapabilities = CapabilityBatchedChainVM |
+		CapabilityHeightIndexed |
+		CapabilityStateSyncable |
+		CapabilityDecoder |
+		CapabilityGossipStream |
+		CapabilityBatchedDecisions |
+		CapabilityStateDiffer |
+		CapabilityMessageVerifier
+)
+
+var (
+	errIncompatibleProtocol = errors.New("incompatible rpcchainvm protocol")
+
+	_ vms.Describer = (*VMClient)(nil)
+
+	capabilityNames = []struct {
+		capability Capabilities
+		name       string
+	}{
+		{CapabilityBatchedChainVM, "batchedChainVM"},
+		{CapabilityHeightIndexed, "heightIndexed"},
+		{CapabilityStateSyncable, "stateSyncable"},
+		{CapabilityDecoder, "decoder"},
+		{CapabilityGossipStream, "gossipStream"},
+		{CapabilityBatchedDecisions, "batchedDecisions"},
+		{CapabilityStateDiffer, "stateDiffer"},
+		{CapabilityVerifyByID, "verifyByID"},
+		{CapabilityCompression, "compression"},
+		{CapabilityBlockContext, "blockContext"},
+		{CapabilityOracleBlock, "oracleBlock"},
+		{CapabilityZstdCompression, "zstdCompression"},
+		{CapabilityMessageVerifier, "messageVerifier"},
+	}
+)
+
+// Capabilities is a bitmap of the optional features of a plugin, reported in
+// the handshake. The node only uses the features the plugin reports.
+type Capabilities uint64
+
+// Has returns true if [c] includes all of [capabilities].
+func (c Capabilities) Has(capabilities Capabilities) bool {
+	return c&capabilities == capabilities
+}
+
+func (c Capabilities) String() string {
+	return strings.Join(c.names(), ",")
+}
+
+func (c Capabilities) names() []string {
+	names := make([]string, 0, len(capabilityNames))
+	for _, capability := range capabilityNames {
+		if c.Has(capability.capability) {
+			names = append(names, capability.name)
+		}
+	}
+	return names
+}
+
+// capabilitiesOf returns the capabilities of a plugin serving [vm].
+func capabilitiesOf(vm *VMServer) Capabilities {
+	capabilities := CapabilityBatchedChainVM |
+		CapabilityGossipStream |
+		CapabilityBatchedDecisions |
+		CapabilityVerifyByID |
+		CapabilityCompression |
+		CapabilityOracleBlock |
+		CapabilityZstdCompression
+	if vm.hVM != nil {
+		capabilities |= CapabilityHeightIndexed
+	}
+	if vm.ssVM != nil {
+		capabilities |= CapabilityStateSyncable
+	}
+	if vm.dVM != nil {
+		capabilities |= CapabilityDecoder
+	}
+	if vm.sdVM != nil {
+		capabilities |= CapabilityStateDiffer
+	}
+	if vm.mvVM != nil {
+		capabilities |= CapabilityMessageVerifier
+	}
+	if vm.bbVM != nil {
+		capabilities |= CapabilityBlockContext
+	}
+	return capabilities
+}
+
+// handshake verifies that the plugin speaks the protocol of the node, and
+// records the capabilities of the plugin. Requests to the plugin are compressed
+// if it supports it.
+func (vm *VMClient) handshake(ctx context.Context) error {
+	resp, err := vm.client.Handshake(ctx, &vmpb.HandshakeRequest{
+		ProtocolVersion: uint32(version.RPCChainVMProtocol),
+	})
+	switch status.Code(err) {
+	case codes.OK:
+	case codes.Unimplemented:
+		// The plugin predates the handshake.
+		vm.capabilities = legacyCapabilities
+		return nil
+	case codes.FailedPrecondition:
+		return fmt.Errorf("%w: %s", errIncompatibleProtocol, status.Convert(err).Message())
+	default:
+		return err
+	}
+
+	if uint(resp.ProtocolVersion) != version.RPCChainVMProtocol {
+		return fmt.Errorf(
+			"%w: the plugin speaks protocol version %d but the node speaks version %d, upgrade the plugin or the node so that they speak the same version",
+			errIncompatibleProtocol,
+			resp.ProtocolVersion,
+			version.RPCChainVMProtocol,
+		)
+	}
+	vm.capabilities = Capabilities(resp.Capabilities)
+	if vm.conn != nil {
+		vm.conn.compressor.SetValue(vm.compressor())
+	}
+	return nil
+}
+
+// compressor returns the name of the compressor the plugin decompresses, or an
+// empty string if requests to the plugin aren't compressed.
+func (vm *VMClient) compressor() string {
+	switch {
+	case vm.capabilities.Has(CapabilityZstdCompression):
+		return grpcutils.ZstdCompressorName
+	case vm.capabilities.Has(CapabilityCompression):
+		return grpcutils.GzipCompressorName
+	default:
+		return ""
+	}
+}
+
+func (vm *VMServer) Handshake(_ context.Context, req *vmpb.HandshakeRequest) (*vmpb.HandshakeResponse, error) {
+	if uint(req.ProtocolVersion) != version.RPCChainVMProtocol {
+		return nil, status.Errorf(
+			codes.FailedPrecondition,
+			"the node speaks protocol version %d but the plugin speaks version %d, upgrade the plugin or the node so that they speak the same version",
+			req.ProtocolVersion,
+			version.RPCChainVMProtocol,
+		)
+	}
+	return &vmpb.HandshakeResponse{
+		ProtocolVersion: uint32(version.RPCChainVMProtocol),
+		Capabilities:    uint64(capabilitiesOf(vm)),
+	}, nil
+}
+
+// Describe returns the path of the plugin, and the protocol version and the
+// capabilities it reports in the handshake. It doesn't fail if the plugin
+// speaks another protocol version, so that the plugin can still be listed.
+func (vm *VMClient) Describe(ctx context.Context) (vms.Description, error) {
+	description := vms.Description{
+		Path: vm.pluginPath,
+	}
+	resp, err := vm.client.Handshake(ctx, &vmpb.HandshakeRequest{
+		ProtocolVersion: uint32(version.RPCChainVMProtocol),
+	})
+	switch status.Code(err) {
+	case codes.OK:
+		description.ProtocolVersion = uint(resp.ProtocolVersion)
+		description.Interfaces = Capabilities(resp.Capabilities).names()
+	case codes.Unimplemented:
+		// The plugin predates the handshake.
+		description.Interfaces = legacyCapabilities.names()
+	case codes.FailedPrecondition:
+		// The plugin speaks another protocol version, which it doesn't report.
+		description.Interfaces = []string{}
+	default:
+		return vms.Description{}, err
+	}
+	return description, nil
+}
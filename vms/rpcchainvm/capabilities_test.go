@@ -0,0 +1,110 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpcchainvm
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
+	"github.com/ava-labs/avalanchego/version"
+	"github.com/ava-labs/avalanchego/vms"
+
+	vmpb "github.com/ava-labs/avalanchego/proto/pb/vm"
+)
+
+// handshakeServer is a plugin that answers the handshake with [resp], or with
+// [err] if set.
+type handshakeServer struct {
+	*VMServer
+
+	resp *vmpb.HandshakeResponse
+	err  error
+}
+
+func (s *handshakeServer) Handshake(context.Context, *vmpb.HandshakeRequest) (*vmpb.HandshakeResponse, error) {
+	return s.resp, s.err
+}
+
+func TestHandshakeCapabilities(t *testing.T) {
+	require := require.New(t)
+
+	vm := NewClient(serveVM(t, NewServer(&block.TestVM{})))
+	require.NoError(vm.handshake(context.Background()))
+	require.Equal(CapabilityBatchedChainVM|CapabilityGossipStream|CapabilityBatchedDecisions|CapabilityVerifyByID|CapabilityCompression|CapabilityOracleBlock|CapabilityZstdCompression, vm.capabilities)
+
+	// Capabilities the plugin doesn't have aren't requested from it.
+	require.ErrorIs(vm.VerifyHeightIndex(context.Background()), block.ErrHeightIndexedVMNotImplemented)
+	enabled, err := vm.StateSyncEnabled(context.Background())
+	require.NoError(err)
+	require.False(enabled)
+	_, err = vm.DecodeBlock(context.Background(), nil)
+	require.ErrorIs(err, block.ErrDecoderNotImplemented)
+}
+
+func TestHandshakeLegacyPlugin(t *testing.T) {
+	require := require.New(t)
+
+	vm := NewClient(serveVM(t, &handshakeServer{
+		VMServer: NewServer(&block.TestVM{}),
+		err:      status.Error(codes.Unimplemented, "unimplemented"),
+	}))
+	require.NoError(vm.handshake(context.Background()))
+	require.Equal(legacyCapabilities, vm.capabilities)
+}
+
+func TestHandshakeIncompatibleProtocol(t *testing.T) {
+	require := require.New(t)
+
+	vm := NewClient(serveVM(t, &handshakeServer{
+		VMServer: NewServer(&block.TestVM{}),
+		resp: &vmpb.HandshakeResponse{
+			ProtocolVersion: uint32(version.RPCChainVMProtocol + 1),
+		},
+	}))
+	require.ErrorIs(vm.handshake(context.Background()), errIncompatibleProtocol)
+
+	// The plugin rejects a node speaking another protocol.
+	server := NewServer(&block.TestVM{})
+	_, err := server.Handshake(context.Background(), &vmpb.HandshakeRequest{
+		ProtocolVersion: uint32(version.RPCChainVMProtocol + 1),
+	})
+	require.Equal(codes.FailedPrecondition, status.Code(err))
+}
+
+func TestCapabilitiesString(t *testing.T) {
+	require := require.New(t)
+
+	require.Equal("", Capabilities(0).String())
+	require.Equal("heightIndexed,gossipStream", (CapabilityHeightIndexed | CapabilityGossipStream).String())
+}
+
+func TestDescribe(t *testing.T) {
+	require := require.New(t)
+
+	vm := NewClient(serveVM(t, NewServer(&block.TestVM{})))
+	vm.pluginPath = "/plugins/vm"
+	description, err := vm.Describe(context.Background())
+	require.NoError(err)
+	require.Equal(vms.Description{
+		Path:            "/plugins/vm",
+		ProtocolVersion: version.RPCChainVMProtocol,
+		Interfaces:      []string{"batchedChainVM", "gossipStream", "batchedDecisions", "verifyByID", "compression", "oracleBlock", "zstdCompression"},
+	}, description)
+
+	// A plugin speaking another protocol is still described.
+	vm = NewClient(serveVM(t, &handshakeServer{
+		VMServer: NewServer(&block.TestVM{}),
+		err:      status.Error(codes.FailedPrecondition, "incompatible"),
+	}))
+	description, err = vm.Describe(context.Background())
+	require.NoError(err)
+	require.Zero(description.ProtocolVersion)
+	require.Empty(description.Interfaces)
+}
@@ -0,0 +1,99 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpcchainvm
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
+	"github.com/ava-labs/avalanchego/utils/units"
+	"github.com/ava-labs/avalanchego/vms/rpcchainvm/grpcutils"
+
+	vmpb "github.com/ava-labs/avalanchego/proto/pb/vm"
+)
+
+// compressionServer is a plugin that records the compressor of the last
+// ParseBlock request, and echoes its bytes as the ID of the block. If
+// [withoutZstd] is true, it doesn't report CapabilityZstdCompression.
+type compressionServer struct {
+	*handshakeServer
+
+	withoutZstd bool
+	compressor  string
+}
+
+func (s *compressionServer) ParseBlock(ctx context.Context, req *vmpb.ParseBlockRequest) (*vmpb.ParseBlockResponse, error) {
+	stream, ok := grpc.ServerTransportStreamFromContext(ctx).(interface{ RecvCompress() string })
+	if ok {
+		s.compressor = stream.RecvCompress()
+	}
+	return &vmpb.ParseBlockResponse{
+		Id: req.Bytes,
+	}, nil
+}
+
+func (s *compressionServer) Handshake(ctx context.Context, req *vmpb.HandshakeRequest) (*vmpb.HandshakeResponse, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	resp, err := s.VMServer.Handshake(ctx, req)
+	if err == nil && s.withoutZstd {
+		resp.Capabilities &^= uint64(CapabilityZstdCompression)
+	}
+	return resp, err
+}
+
+func TestCompression(t *testing.T) {
+	require := require.New(t)
+
+	blkBytes := bytes.Repeat([]byte{1}, units.MiB)
+	tests := []struct {
+		name               string
+		withoutZstd        bool
+		handshakeErr       error
+		expectedCompressor string
+	}{
+		{
+			name:               "zstd supported",
+			expectedCompressor: grpcutils.ZstdCompressorName,
+		},
+		{
+			name:               "gzip supported",
+			withoutZstd:        true,
+			expectedCompressor: grpcutils.GzipCompressorName,
+		},
+		{
+			name:         "legacy plugin",
+			handshakeErr: status.Error(codes.Unimplemented, "unimplemented"),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := &compressionServer{
+				handshakeServer: &handshakeServer{
+					VMServer: NewServer(&block.TestVM{}),
+					err:      test.handshakeErr,
+				},
+				withoutZstd: test.withoutZstd,
+			}
+			vm := newRestartableClient(dialVM(t, server))
+			require.NoError(vm.handshake(context.Background()))
+
+			resp, err := vm.client.ParseBlock(context.Background(), &vmpb.ParseBlockRequest{
+				Bytes: blkBytes,
+			})
+			require.NoError(err)
+			require.Equal(blkBytes, resp.Id)
+			require.Equal(test.expectedCompressor, server.compressor)
+		})
+	}
+}
@@ -0,0 +1,493 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package conformance verifies that a plugin implements the rpcchainvm
+// contract expected by the node.
+package conformance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/vms/rpcchainvm"
+)
+
+const defaultTimeout = 30 * time.Second
+
+var (
+	errWrongVM           = errors.New("plugin isn't a block.ChainVM")
+	errWrongBlock        = errors.New("unexpected block")
+	errWrongSummary      = errors.New("unexpected state summary")
+	errWrongStatus       = errors.New("unexpected block status")
+	errWrongLastAccept   = errors.New("last accepted block isn't the accepted block")
+	errNoHandlers        = errors.New("nil HTTP handler")
+	errHandlerFailed     = errors.New("HTTP handler failed")
+	errNoBuiltBlock      = errors.New("no block was built")
+	errNotInitialized    = errors.New("the VM wasn't initialized")
+	errStateSyncDisabled = errors.New("state sync isn't enabled")
+	errSkippedByConfig   = errors.New("skipped by the config")
+)
+
+// Capability is a part of the rpcchainvm contract verified by Run.
+type Capability string
+
+const (
+	// Initialize verifies that the VM initializes and transitions to
+	// bootstrapping and then to normal operations.
+	Initialize Capability = "initialize"
+	// Parse verifies that the last accepted block is returned by GetBlock and
+	// that parsing its bytes returns the same block.
+	Parse Capability = "parse"
+	// Build verifies that a block is built on top of the last accepted block.
+	Build Capability = "build"
+	// Accept verifies that the built block is verified and accepted, and that
+	// it becomes the last accepted block.
+	Accept Capability = "accept"
+	// StateSync verifies that the last state summary of a state syncable VM
+	// is parsed and returned by height.
+	StateSync Capability = "state-sync"
+	// HTTPHandlers verifies that the HTTP handlers of the VM serve requests.
+	HTTPHandlers Capability = "http-handlers"
+	// Health verifies that the VM reports its health.
+	Health Capability = "health"
+	// Shutdown verifies that the VM shuts down.
+	Shutdown Capability = "shutdown"
+)
+
+// Capabilities is the list of capabilities verified by Run, in order.
+var Capabilities = []Capability{
+	Initialize,
+	Parse,
+	Build,
+	Accept,
+	StateSync,
+	HTTPHandlers,
+	Health,
+	Shutdown,
+}
+
+type Status string
+
+const (
+	Passed  Status = "PASS"
+	Failed  Status = "FAIL"
+	Skipped Status = "SKIP"
+)
+
+// Result is the outcome of the verification of a capability.
+type Result struct {
+	Capability Capability
+	Status     Status
+	// Err is the reason the capability failed or was skipped.
+	Err error
+}
+
+func (r Result) String() string {
+	if r.Err == nil {
+		return fmt.Sprintf("%s %s", r.Status, r.Capability)
+	}
+	return fmt.Sprintf("%s %s: %s", r.Status, r.Capability, r.Err)
+}
+
+// Report is the list of the results of Run, in the order of [Capabilities].
+type Report []Result
+
+// Failed returns true if any capability failed.
+func (r Report) Failed() bool {
+	for _, result := range r {
+		if result.Status == Failed {
+			return true
+		}
+	}
+	return false
+}
+
+func (r Report) String() string {
+	sb := strings.Builder{}
+	for _, result := range r {
+		sb.WriteString(result.String())
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// Config configures how the plugin is launched and verified.
+type Config struct {
+	// Path of the plugin binary.
+	Path string
+	// Passed to Initialize.
+	Genesis, Upgrade, Config []byte
+	// If nil, the logs of the plugin are dropped.
+	Log logging.Logger
+	// If not empty, passed to the VM as the directory of the chain's data.
+	ChainDataDir string
+	// Called before building a block, once the VM is in normal operations.
+	// VMs that only build blocks with pending transactions should issue them
+	// here.
+	BeforeBuild func(ctx context.Context, vm block.ChainVM) error
+	// Capabilities not to verify. The capabilities that depend on them are
+	// skipped as well.
+	Skip []Capability
+	// Max duration of the verification of each capability. If 0, it defaults
+	// to 30s.
+	Timeout time.Duration
+}
+
+// Run launches the plugin at [config.Path] and verifies each of
+// [Capabilities]. The returned error is only non-nil if the plugin couldn't be
+// launched.
+func Run(ctx context.Context, config Config) (Report, error) {
+	if config.Log == nil {
+		config.Log = logging.NoLog{}
+	}
+	if config.Timeout == 0 {
+		config.Timeout = defaultTimeout
+	}
+
+	chainCtx := newContext(config)
+	factory := rpcchainvm.NewFactory(config.Path, rpcchainvm.FactoryConfig{
+		ProcessTracker: noOpProcessTracker{},
+	})
+	raw, err := factory.New(chainCtx)
+	if err != nil {
+		return nil, err
+	}
+	vm, ok := raw.(block.ChainVM)
+	if !ok {
+		return nil, errWrongVM
+	}
+
+	r := &runner{
+		config:   config,
+		chainCtx: chainCtx,
+		vm:       vm,
+		results:  make(map[Capability]Result, len(Capabilities)),
+	}
+	for _, capability := range config.Skip {
+		r.results[capability] = Result{
+			Capability: capability,
+			Status:     Skipped,
+			Err:        errSkippedByConfig,
+		}
+	}
+
+	checks := map[Capability]check{
+		Initialize:   r.initialize,
+		Parse:        r.parse,
+		Build:        r.build,
+		Accept:       r.accept,
+		StateSync:    r.stateSync,
+		HTTPHandlers: r.httpHandlers,
+		Health:       r.health,
+		Shutdown:     r.shutdown,
+	}
+	report := make(Report, 0, len(Capabilities))
+	for _, capability := range Capabilities {
+		if _, skipped := r.results[capability]; !skipped {
+			r.run(ctx, capability, checks[capability])
+		}
+		report = append(report, r.results[capability])
+	}
+
+	// A VM that wasn't shut down still runs in the plugin process.
+	if r.results[Shutdown].Status != Passed {
+		_ = vm.Shutdown(context.Background())
+	}
+	return report, nil
+}
+
+// RunTest runs the verification of the plugin in a subtest of [t] per
+// capability.
+func RunTest(t *testing.T, config Config) {
+	report, err := Run(context.Background(), config)
+	if err != nil {
+		t.Fatalf("failed to launch the plugin: %s", err)
+	}
+	for _, result := range report {
+		result := result
+		t.Run(string(result.Capability), func(t *testing.T) {
+			switch result.Status {
+			case Failed:
+				t.Fatal(result.Err)
+			case Skipped:
+				t.Skip(result.Err)
+			}
+		})
+	}
+}
+
+// check verifies a capability. A capability is skipped if the returned error
+// was created by skip.
+type check func(ctx context.Context) error
+
+type skipError struct {
+	err error
+}
+
+func (e skipError) Error() string {
+	return e.err.Error()
+}
+
+func skip(err error) error {
+	return skipError{err: err}
+}
+
+// runner holds the state shared by the verification of the capabilities.
+type runner struct {
+	config   Config
+	chainCtx *snow.Context
+	vm       block.ChainVM
+	results  map[Capability]Result
+
+	// lastAccepted is the last accepted block before [built] was built
+	lastAccepted snowman.Block
+	built        snowman.Block
+}
+
+func (r *runner) run(ctx context.Context, capability Capability, c check) {
+	ctx, cancel := context.WithTimeout(ctx, r.config.Timeout)
+	defer cancel()
+
+	result := Result{
+		Capability: capability,
+		Status:     Passed,
+	}
+	if err := c(ctx); err != nil {
+		result.Err = err
+		result.Status = Failed
+		var skipErr skipError
+		if errors.As(err, &skipErr) {
+			result.Err = skipErr.err
+			result.Status = Skipped
+		}
+	}
+	r.results[capability] = result
+}
+
+// requires skips the current capability if [capability] didn't pass.
+func (r *runner) requires(capability Capability) error {
+	if result := r.results[capability]; result.Status != Passed {
+		return skip(fmt.Errorf("requires %s", capability))
+	}
+	return nil
+}
+
+func (r *runner) initialize(ctx context.Context) error {
+	err := r.vm.Initialize(
+		ctx,
+		r.chainCtx,
+		newDBManager(),
+		r.config.Genesis,
+		r.config.Upgrade,
+		r.config.Config,
+		make(chan common.Message, 1),
+		nil,
+		noOpAppSender{},
+	)
+	if err != nil {
+		return err
+	}
+	if err := r.vm.SetState(ctx, snow.Bootstrapping); err != nil {
+		return err
+	}
+	return r.vm.SetState(ctx, snow.NormalOp)
+}
+
+func (r *runner) parse(ctx context.Context) error {
+	if err := r.requires(Initialize); err != nil {
+		return err
+	}
+
+	lastAcceptedID, err := r.vm.LastAccepted(ctx)
+	if err != nil {
+		return err
+	}
+	lastAccepted, err := r.vm.GetBlock(ctx, lastAcceptedID)
+	if err != nil {
+		return err
+	}
+	if lastAccepted.ID() != lastAcceptedID {
+		return fmt.Errorf("%w: GetBlock(%s) returned %s", errWrongBlock, lastAcceptedID, lastAccepted.ID())
+	}
+	if status := lastAccepted.Status(); status != choices.Accepted {
+		return fmt.Errorf("%w: last accepted block is %s", errWrongStatus, status)
+	}
+	if err := verifyParse(ctx, r.vm, lastAccepted); err != nil {
+		return err
+	}
+	r.lastAccepted = lastAccepted
+	return nil
+}
+
+func (r *runner) build(ctx context.Context) error {
+	if err := r.requires(Parse); err != nil {
+		return err
+	}
+
+	if err := r.vm.SetPreference(ctx, r.lastAccepted.ID()); err != nil {
+		return err
+	}
+	if r.config.BeforeBuild != nil {
+		if err := r.config.BeforeBuild(ctx, r.vm); err != nil {
+			return err
+		}
+	}
+	built, err := r.vm.BuildBlock(ctx)
+	if err != nil {
+		return err
+	}
+	if built == nil {
+		return errNoBuiltBlock
+	}
+	if parentID := built.Parent(); parentID != r.lastAccepted.ID() {
+		return fmt.Errorf("%w: built block's parent is %s instead of %s", errWrongBlock, parentID, r.lastAccepted.ID())
+	}
+	if height, expected := built.Height(), r.lastAccepted.Height()+1; height != expected {
+		return fmt.Errorf("%w: built block's height is %d instead of %d", errWrongBlock, height, expected)
+	}
+	if err := verifyParse(ctx, r.vm, built); err != nil {
+		return err
+	}
+	r.built = built
+	return nil
+}
+
+func (r *runner) accept(ctx context.Context) error {
+	if err := r.requires(Build); err != nil {
+		return err
+	}
+
+	if err := r.built.Verify(ctx); err != nil {
+		return err
+	}
+	if err := r.vm.SetPreference(ctx, r.built.ID()); err != nil {
+		return err
+	}
+	if err := r.built.Accept(ctx); err != nil {
+		return err
+	}
+
+	lastAcceptedID, err := r.vm.LastAccepted(ctx)
+	if err != nil {
+		return err
+	}
+	if lastAcceptedID != r.built.ID() {
+		return fmt.Errorf("%w: %s instead of %s", errWrongLastAccept, lastAcceptedID, r.built.ID())
+	}
+	accepted, err := r.vm.GetBlock(ctx, lastAcceptedID)
+	if err != nil {
+		return err
+	}
+	if status := accepted.Status(); status != choices.Accepted {
+		return fmt.Errorf("%w: accepted block is %s", errWrongStatus, status)
+	}
+	return nil
+}
+
+func (r *runner) stateSync(ctx context.Context) error {
+	if err := r.requires(Initialize); err != nil {
+		return err
+	}
+
+	ssVM, ok := r.vm.(block.StateSyncableVM)
+	if !ok {
+		return skip(errStateSyncDisabled)
+	}
+	enabled, err := ssVM.StateSyncEnabled(ctx)
+	if err != nil {
+		return err
+	}
+	if !enabled {
+		return skip(errStateSyncDisabled)
+	}
+
+	summary, err := ssVM.GetLastStateSummary(ctx)
+	if err != nil {
+		return err
+	}
+	parsed, err := ssVM.ParseStateSummary(ctx, summary.Bytes())
+	if err != nil {
+		return err
+	}
+	if parsed.ID() != summary.ID() || parsed.Height() != summary.Height() {
+		return fmt.Errorf("%w: parsed %s at height %d instead of %s at height %d",
+			errWrongSummary, parsed.ID(), parsed.Height(), summary.ID(), summary.Height())
+	}
+	byHeight, err := ssVM.GetStateSummary(ctx, summary.Height())
+	if err != nil {
+		return err
+	}
+	if byHeight.ID() != summary.ID() {
+		return fmt.Errorf("%w: GetStateSummary(%d) returned %s instead of %s",
+			errWrongSummary, summary.Height(), byHeight.ID(), summary.ID())
+	}
+	return nil
+}
+
+func (r *runner) httpHandlers(ctx context.Context) error {
+	if err := r.requires(Initialize); err != nil {
+		return err
+	}
+
+	handlers, err := r.vm.CreateHandlers(ctx)
+	if err != nil {
+		return err
+	}
+	for endpoint, handler := range handlers {
+		if handler == nil || handler.Handler == nil {
+			return fmt.Errorf("%w: %q", errNoHandlers, endpoint)
+		}
+
+		// The request isn't expected to be valid, but it must be answered.
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("{}"))
+		req = req.WithContext(ctx)
+		req.Header.Set("Content-Type", "application/json")
+		recorder := httptest.NewRecorder()
+		handler.Handler.ServeHTTP(recorder, req)
+		if recorder.Code >= http.StatusInternalServerError {
+			return fmt.Errorf("%w: %q responded with status %d", errHandlerFailed, endpoint, recorder.Code)
+		}
+	}
+	return nil
+}
+
+func (r *runner) health(ctx context.Context) error {
+	if err := r.requires(Initialize); err != nil {
+		return err
+	}
+
+	_, err := r.vm.HealthCheck(ctx)
+	return err
+}
+
+func (r *runner) shutdown(ctx context.Context) error {
+	if r.results[Initialize].Status != Passed {
+		return skip(errNotInitialized)
+	}
+	return r.vm.Shutdown(ctx)
+}
+
+// verifyParse verifies that parsing the bytes of [blk] returns the same block.
+func verifyParse(ctx context.Context, vm block.ChainVM, blk snowman.Block) error {
+	parsed, err := vm.ParseBlock(ctx, blk.Bytes())
+	if err != nil {
+		return err
+	}
+	if parsed.ID() != blk.ID() || parsed.Parent() != blk.Parent() || parsed.Height() != blk.Height() {
+		return fmt.Errorf("%w: parsed %s at height %d instead of %s at height %d",
+			errWrongBlock, parsed.ID(), parsed.Height(), blk.ID(), blk.Height())
+	}
+	return nil
+}
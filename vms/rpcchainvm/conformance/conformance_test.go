@@ -0,0 +1,211 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package conformance
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"net/http"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/database/manager"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
+	"github.com/ava-labs/avalanchego/utils/hashing"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+	"github.com/ava-labs/avalanchego/vms/rpcchainvm"
+)
+
+const (
+	// pluginEnvKey is set when the test binary is launched as a plugin. Its
+	// value is the name of the VM to serve.
+	pluginEnvKey = "CONFORMANCE_TEST_PLUGIN"
+
+	conformingVM = "conforming"
+	wrongParseVM = "wrong-parse"
+)
+
+var errUnknownBlock = errors.New("unknown block")
+
+func TestMain(m *testing.M) {
+	switch name := os.Getenv(pluginEnvKey); name {
+	case "":
+		os.Exit(m.Run())
+	default:
+		rpcchainvm.Serve(newTestVM(name == wrongParseVM))
+		os.Exit(0)
+	}
+}
+
+func TestRunConformingVM(t *testing.T) {
+	require := require.New(t)
+
+	t.Setenv(pluginEnvKey, conformingVM)
+	report, err := Run(context.Background(), Config{
+		Path: os.Args[0],
+	})
+	require.NoError(err)
+	require.False(report.Failed(), report.String())
+
+	require.Len(report, len(Capabilities))
+	for i, result := range report {
+		require.Equal(Capabilities[i], result.Capability)
+		if result.Capability == StateSync {
+			require.Equal(Skipped, result.Status)
+			require.ErrorIs(result.Err, errStateSyncDisabled)
+			continue
+		}
+		require.Equal(Passed, result.Status, result.String())
+	}
+}
+
+func TestRunReportsFailures(t *testing.T) {
+	require := require.New(t)
+
+	t.Setenv(pluginEnvKey, wrongParseVM)
+	report, err := Run(context.Background(), Config{
+		Path: os.Args[0],
+		Skip: []Capability{HTTPHandlers},
+	})
+	require.NoError(err)
+	require.True(report.Failed())
+
+	statuses := make(map[Capability]Status, len(report))
+	for _, result := range report {
+		statuses[result.Capability] = result.Status
+	}
+	require.Equal(map[Capability]Status{
+		Initialize: Passed,
+		Parse:      Failed,
+		// The capabilities that depend on a failed one are skipped.
+		Build:        Skipped,
+		Accept:       Skipped,
+		StateSync:    Skipped,
+		HTTPHandlers: Skipped,
+		Health:       Passed,
+		Shutdown:     Passed,
+	}, statuses)
+}
+
+// newTestVM returns a VM whose blocks only hold their parent and their height.
+// If [wrongParse] is true, parsing a block returns its child.
+func newTestVM(wrongParse bool) block.ChainVM {
+	var (
+		lock      sync.Mutex
+		blocks    = make(map[ids.ID]*snowman.TestBlock)
+		preferred ids.ID
+	)
+	newBlock := func(parentID ids.ID, height uint64) *snowman.TestBlock {
+		bytes := make([]byte, hashing.HashLen+wrappers.LongLen)
+		copy(bytes, parentID[:])
+		binary.BigEndian.PutUint64(bytes[hashing.HashLen:], height)
+		blk := &snowman.TestBlock{
+			TestDecidable: choices.TestDecidable{
+				IDV:     hashing.ComputeHash256Array(bytes),
+				StatusV: choices.Processing,
+			},
+			ParentV: parentID,
+			HeightV: height,
+			BytesV:  bytes,
+		}
+		if existing, ok := blocks[blk.ID()]; ok {
+			return existing
+		}
+		blocks[blk.ID()] = blk
+		return blk
+	}
+
+	vm := &block.TestVM{}
+	vm.InitializeF = func(context.Context, *snow.Context, manager.Manager, []byte, []byte, []byte, chan<- common.Message, []*common.Fx, common.AppSender) error {
+		lock.Lock()
+		defer lock.Unlock()
+
+		genesis := newBlock(ids.Empty, 0)
+		genesis.StatusV = choices.Accepted
+		preferred = genesis.ID()
+		return nil
+	}
+	vm.SetStateF = func(context.Context, snow.State) error {
+		return nil
+	}
+	vm.LastAcceptedF = func(context.Context) (ids.ID, error) {
+		lock.Lock()
+		defer lock.Unlock()
+
+		var lastAccepted *snowman.TestBlock
+		for _, blk := range blocks {
+			if blk.Status() == choices.Accepted && (lastAccepted == nil || blk.Height() > lastAccepted.Height()) {
+				lastAccepted = blk
+			}
+		}
+		return lastAccepted.ID(), nil
+	}
+	vm.GetBlockF = func(_ context.Context, blkID ids.ID) (snowman.Block, error) {
+		lock.Lock()
+		defer lock.Unlock()
+
+		blk, ok := blocks[blkID]
+		if !ok {
+			return nil, errUnknownBlock
+		}
+		return blk, nil
+	}
+	vm.ParseBlockF = func(_ context.Context, bytes []byte) (snowman.Block, error) {
+		lock.Lock()
+		defer lock.Unlock()
+
+		if len(bytes) != hashing.HashLen+wrappers.LongLen {
+			return nil, errUnknownBlock
+		}
+		parentID, err := ids.ToID(bytes[:hashing.HashLen])
+		if err != nil {
+			return nil, err
+		}
+		height := binary.BigEndian.Uint64(bytes[hashing.HashLen:])
+		blk := newBlock(parentID, height)
+		if wrongParse {
+			return newBlock(blk.ID(), height+1), nil
+		}
+		return blk, nil
+	}
+	vm.SetPreferenceF = func(_ context.Context, blkID ids.ID) error {
+		lock.Lock()
+		defer lock.Unlock()
+
+		preferred = blkID
+		return nil
+	}
+	vm.BuildBlockF = func(context.Context) (snowman.Block, error) {
+		lock.Lock()
+		defer lock.Unlock()
+
+		parent := blocks[preferred]
+		return newBlock(parent.ID(), parent.Height()+1), nil
+	}
+	vm.CreateHandlersF = func(context.Context) (map[string]*common.HTTPHandler, error) {
+		return map[string]*common.HTTPHandler{
+			"": {
+				Handler: http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+					w.WriteHeader(http.StatusBadRequest)
+				}),
+			},
+		}, nil
+	}
+	vm.HealthCheckF = func(context.Context) (interface{}, error) {
+		return nil, nil
+	}
+	vm.ShutdownF = func(context.Context) error {
+		return nil
+	}
+	return vm
+}
@@ -0,0 +1,125 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package conformance
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/api/keystore"
+	"github.com/ava-labs/avalanchego/api/metrics"
+	"github.com/ava-labs/avalanchego/chains/atomic"
+	"github.com/ava-labs/avalanchego/database/manager"
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/snow/validators"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/resource"
+	"github.com/ava-labs/avalanchego/version"
+)
+
+var (
+	_ snow.SubnetLookup       = subnetLookup{}
+	_ validators.State        = emptyValidatorState{}
+	_ common.AppSender        = noOpAppSender{}
+	_ resource.ProcessTracker = noOpProcessTracker{}
+)
+
+// newContext returns the context of a chain running alone on a local network,
+// with no validators.
+func newContext(config Config) *snow.Context {
+	chainID := ids.GenerateTestID()
+	subnetID := ids.GenerateTestID()
+	aliaser := ids.NewAliaser()
+	return &snow.Context{
+		NetworkID:      constants.LocalID,
+		SubnetID:       subnetID,
+		ChainID:        chainID,
+		NodeID:         ids.GenerateTestNodeID(),
+		XChainID:       ids.GenerateTestID(),
+		AVAXAssetID:    ids.GenerateTestID(),
+		Log:            config.Log,
+		Keystore:       keystore.New(config.Log, newDBManager()).NewBlockchainKeyStore(chainID),
+		SharedMemory:   atomic.NewMemory(memdb.New()).NewSharedMemory(chainID),
+		BCLookup:       aliaser,
+		SNLookup:       subnetLookup{subnetID: subnetID},
+		Metrics:        metrics.NewOptionalGatherer(),
+		ChainDataDir:   config.ChainDataDir,
+		ValidatorState: emptyValidatorState{},
+	}
+}
+
+func newDBManager() manager.Manager {
+	return manager.NewMemDB(version.Semantic1_0_0)
+}
+
+// subnetLookup reports that every chain is in [subnetID].
+type subnetLookup struct {
+	subnetID ids.ID
+}
+
+func (s subnetLookup) SubnetID(ids.ID) (ids.ID, error) {
+	return s.subnetID, nil
+}
+
+// emptyValidatorState reports a P-chain at height 0 with no validators.
+type emptyValidatorState struct{}
+
+func (emptyValidatorState) GetMinimumHeight(context.Context) (uint64, error) {
+	return 0, nil
+}
+
+func (emptyValidatorState) GetCurrentHeight(context.Context) (uint64, error) {
+	return 0, nil
+}
+
+func (emptyValidatorState) GetValidatorSet(context.Context, uint64, ids.ID) (map[ids.NodeID]uint64, error) {
+	return map[ids.NodeID]uint64{}, nil
+}
+
+func (emptyValidatorState) GetEpochLength(context.Context) (uint64, error) {
+	return 0, nil
+}
+
+func (emptyValidatorState) GetValidatorSetAtEpoch(context.Context, uint64, ids.ID) (map[ids.NodeID]uint64, error) {
+	return map[ids.NodeID]uint64{}, nil
+}
+
+// noOpAppSender drops all the messages sent by the VM, as it has no peers.
+type noOpAppSender struct{}
+
+func (noOpAppSender) SendAppRequest(context.Context, ids.NodeIDSet, uint32, []byte) error {
+	return nil
+}
+
+func (noOpAppSender) SendAppResponse(context.Context, ids.NodeID, uint32, []byte) error {
+	return nil
+}
+
+func (noOpAppSender) SendAppError(context.Context, ids.NodeID, uint32, int32, string) error {
+	return nil
+}
+
+func (noOpAppSender) SendAppGossip(context.Context, []byte) error {
+	return nil
+}
+
+func (noOpAppSender) SendAppGossipSpecific(context.Context, ids.NodeIDSet, []byte) error {
+	return nil
+}
+
+func (noOpAppSender) SendCrossChainAppRequest(context.Context, ids.ID, uint32, []byte) error {
+	return nil
+}
+
+func (noOpAppSender) SendCrossChainAppResponse(context.Context, ids.ID, uint32, []byte) error {
+	return nil
+}
+
+type noOpProcessTracker struct{}
+
+func (noOpProcessTracker) TrackProcess(int) {}
+
+func (noOpProcessTracker) UntrackProcess(int) {}
@@ -5,6 +5,7 @@ package rpcchainvm
 
 import (
 	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
 	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
 )
 
@@ -15,6 +16,10 @@ var (
 		3: block.ErrHeightIndexedVMNotImplemented,
 		4: block.ErrIndexIncomplete,
 		5: block.ErrStateSyncableVMNotImplemented,
+		6: block.ErrDecoderNotImplemented,
+		7: block.ErrStateDifferNotImplemented,
+		8: snowman.ErrNotOracle,
+		9: block.ErrMessageVerifierNotImplemented,
 	}
 	errorToErrCode = map[error]uint32{
 		database.ErrClosed:                     1,
@@ -22,6 +27,10 @@ var (
 		block.ErrHeightIndexedVMNotImplemented: 3,
 		block.ErrIndexIncomplete:               4,
 		block.ErrStateSyncableVMNotImplemented: 5,
+		block.ErrDecoderNotImplemented:         6,
+		block.ErrStateDifferNotImplemented:     7,
+		snowman.ErrNotOracle:                   8,
+		block.ErrMessageVerifierNotImplemented: 9,
 	}
 )
 
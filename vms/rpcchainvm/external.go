@@ -0,0 +1,126 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpcchainvm
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
+	"github.com/ava-labs/avalanchego/vms"
+	"github.com/ava-labs/avalanchego/vms/rpcchainvm/grpcutils"
+
+	vmpb "github.com/ava-labs/avalanchego/proto/pb/vm"
+)
+
+var (
+	errMissingTLSConfig = errors.New("missing TLS config")
+
+	_ vms.Factory = (*externalFactory)(nil)
+)
+
+// ExternalConfig configures the connection to a VM that runs outside of the
+// node, such as in another container or on another host, rather than in a
+// plugin process launched by the node.
+type ExternalConfig struct {
+	// Address the VM serves at, as given to ServeExternal
+	Addr string
+	// Host the servers of the node that the VM connects to listen on. It must
+	// be reachable by the VM.
+	ListenHost string
+	// Authenticates the node to the VM, and the VM to the node. It is used
+	// both to dial the VM and to serve the VM, so it must hold the
+	// certificate of the node and trust the certificate of the VM as both a
+	// server and a client.
+	TLSConfig *tls.Config
+}
+
+type externalFactory struct {
+	config ExternalConfig
+}
+
+// NewExternalFactory returns a factory that connects to the VM serving at
+// [config.Addr].
+func NewExternalFactory(config ExternalConfig) vms.Factory {
+	return &externalFactory{
+		config: config,
+	}
+}
+
+func (f *externalFactory) New(*snow.Context) (interface{}, error) {
+	if f.config.TLSConfig == nil {
+		return nil, errMissingTLSConfig
+	}
+
+	creds := credentials.NewTLS(f.config.TLSConfig)
+	dialOpts := make([]grpc.DialOption, 0, len(grpcutils.DefaultDialOptions)+1)
+	dialOpts = append(dialOpts, grpcutils.DefaultDialOptions...)
+	dialOpts = append(dialOpts, grpc.WithTransportCredentials(creds))
+	conn, err := grpcutils.Dial(f.config.Addr, dialOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("external vm: %q: %w", f.config.Addr, err)
+	}
+
+	// The connection is never replaced, as the client doesn't own the vm.
+	vm := newRestartableClient(conn)
+	vm.externalConn = conn
+	vm.listenHost = f.config.ListenHost
+	vm.dialOpts = dialOpts
+	vm.serverCreds = creds
+	return vm, nil
+}
+
+// ServeExternal serves [vm] at [addr] to a node that connects to it with
+// NewExternalFactory, rather than as a plugin launched by the node. As with a
+// plugin, the vm runs a single chain. The vm can be initialized with the
+// feature extensions created by [fxs]. [tlsConfig] must hold the certificate
+// of the vm and trust the certificate of the node as both a server and a
+// client. ServeExternal blocks until serving fails.
+func ServeExternal(addr string, vm block.ChainVM, fxs map[ids.ID]vms.Factory, tlsConfig *tls.Config) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	return serveExternal(listener, vm, fxs, tlsConfig)
+}
+
+func serveExternal(listener net.Listener, vm block.ChainVM, fxs map[ids.ID]vms.Factory, tlsConfig *tls.Config) error {
+	if tlsConfig == nil {
+		_ = listener.Close()
+		return errMissingTLSConfig
+	}
+
+	host, _, err := net.SplitHostPort(listener.Addr().String())
+	if err != nil {
+		_ = listener.Close()
+		return err
+	}
+
+	server := NewServerWithFxs(vm, fxs)
+	server.listenHost = host
+	server.creds = credentials.NewTLS(tlsConfig)
+	server.propagator = grpcutils.NewPropagator()
+
+	opts := append([]grpc.ServerOption{
+		grpc.Creds(server.creds),
+	}, server.propagator.ServerOptions()...)
+	grpcServer := grpcutils.NewDefaultServer(opts)
+	vmpb.RegisterVMServer(grpcServer, server)
+	return grpcServer.Serve(listener)
+}
+
+// listen returns a listener on a new address the node can reach.
+func (vm *VMServer) listen() (net.Listener, error) {
+	if vm.listenHost == "" {
+		return grpcutils.NewLocalListener(vm.unixSockets)
+	}
+	return net.Listen("tcp", net.JoinHostPort(vm.listenHost, "0"))
+}
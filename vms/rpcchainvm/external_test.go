@@ -0,0 +1,128 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpcchainvm
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"math/big"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/database/manager"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
+	"github.com/ava-labs/avalanchego/version"
+)
+
+// newTestTLSConfig returns a TLS config that trusts its own certificate, so
+// that it can be used by both ends of a connection.
+func newTestTLSConfig(t *testing.T) *tls.Config {
+	require := require.New(t)
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		IPAddresses:           []net.IP{net.IPv4(127, 0, 0, 1)},
+	}
+	certBytes, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(err)
+	cert, err := x509.ParseCertificate(certBytes)
+	require.NoError(err)
+
+	certPool := x509.NewCertPool()
+	certPool.AddCert(cert)
+	return &tls.Config{
+		Certificates: []tls.Certificate{{
+			Certificate: [][]byte{certBytes},
+			PrivateKey:  key,
+		}},
+		RootCAs:    certPool,
+		ClientCAs:  certPool,
+		ClientAuth: tls.RequireAndVerifyClientCert,
+		MinVersion: tls.VersionTLS12,
+	}
+}
+
+func TestExternalVM(t *testing.T) {
+	require := require.New(t)
+
+	genesis := &snowman.TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.GenerateTestID(),
+			StatusV: choices.Accepted,
+		},
+		TimestampV: time.Unix(1, 0),
+		BytesV:     []byte{0},
+	}
+	externalVM := &block.TestVM{}
+	externalVM.T = t
+	externalVM.InitializeF = func(context.Context, *snow.Context, manager.Manager, []byte, []byte, []byte, chan<- common.Message, []*common.Fx, common.AppSender) error {
+		return nil
+	}
+	externalVM.LastAcceptedF = func(context.Context) (ids.ID, error) {
+		return genesis.ID(), nil
+	}
+	externalVM.GetBlockF = func(context.Context, ids.ID) (snowman.Block, error) {
+		return genesis, nil
+	}
+	shutdown := make(chan struct{})
+	externalVM.ShutdownF = func(context.Context) error {
+		close(shutdown)
+		return nil
+	}
+
+	tlsConfig := newTestTLSConfig(t)
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(err)
+	go func() {
+		_ = serveExternal(listener, externalVM, nil, tlsConfig)
+	}()
+
+	factory := NewExternalFactory(ExternalConfig{
+		Addr:       listener.Addr().String(),
+		ListenHost: "127.0.0.1",
+		TLSConfig:  tlsConfig,
+	})
+	vmIntf, err := factory.New(nil)
+	require.NoError(err)
+	vm := vmIntf.(*VMClient)
+
+	ctx := snow.DefaultContextTest()
+	dbManager := manager.NewMemDB(version.Semantic1_0_0)
+	require.NoError(vm.Initialize(context.Background(), ctx, dbManager, nil, nil, nil, nil, nil, nil))
+	require.Equal(genesis.ID(), vm.State.LastAcceptedBlock().ID())
+
+	// The client doesn't own a process, so shutting it down only shuts down
+	// the external VM.
+	require.NoError(vm.Shutdown(context.Background()))
+	<-shutdown
+}
+
+func TestExternalVMRequiresTLS(t *testing.T) {
+	require := require.New(t)
+
+	_, err := NewExternalFactory(ExternalConfig{
+		Addr: "127.0.0.1:9650",
+	}).New(nil)
+	require.ErrorIs(err, errMissingTLSConfig)
+}
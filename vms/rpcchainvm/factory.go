@@ -8,12 +8,20 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/go-plugin"
 
+	"go.uber.org/zap"
+
+	"google.golang.org/grpc"
+
 	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/utils/gc"
+	"github.com/ava-labs/avalanchego/utils/logging"
 	"github.com/ava-labs/avalanchego/utils/resource"
 	"github.com/ava-labs/avalanchego/utils/subprocess"
 	"github.com/ava-labs/avalanchego/vms"
@@ -21,28 +29,205 @@ import (
 )
 
 var (
-	errWrongVM = errors.New("wrong vm type")
+	errWrongVM              = errors.New("wrong vm type")
+	errUnknownPluginService = errors.New("unknown plugin service")
 
 	_ vms.Factory = (*factory)(nil)
 )
 
+// FactoryConfig configures how a factory launches and connects to plugins.
+type FactoryConfig struct {
+	ProcessTracker resource.ProcessTracker
+	// If a chain has a config in GCConfigs, it is applied to the chain's
+	// plugin process.
+	GCConfigs gc.ChainConfigs
+	// If true, the plugin connects to the servers of the node over a single
+	// connection.
+	Multiplex bool
+	// If true, the node and the plugin authenticate each other with ephemeral
+	// certificates exchanged when the plugin is launched.
+	MTLS bool
+	// If true, the node and the plugin communicate over unix domain sockets
+	// rather than TCP loopback.
+	UnixSockets bool
+	// Number of pages of elements the iterators of the plugin's databases
+	// fetch ahead of their consumer. If 0, the iterators fetch each page with
+	// a separate request.
+	DBIteratorPrefetch int
+	// Max size, in bytes, of the elements the plugin sends in a single request
+	// when writing a batch to its databases. If 0, values are never split.
+	DBMaxBatchBytes int
+	// If true, the plugin sends and requests a checksum with each value
+	// written to and read from its databases.
+	DBChecksums bool
+	// Max duration the pending requests of the plugin to the servers of the
+	// node are given to finish on shutdown, before the plugin is killed. If
+	// 0, the pending requests are cancelled on shutdown.
+	ShutdownDrainTimeout time.Duration
+	// Retries the calls to the plugin that fail because the plugin is
+	// unreachable, if they don't change the state of the plugin.
+	RPCRetry RetryConfig
+	// If set, the plugin process of a chain that has limits in ResourceLimits
+	// is placed in a cgroup named after the chain under this cgroup.
+	CgroupRoot     string
+	ResourceLimits resource.ChainLimits
+	// The services in PluginServices enabled for a chain in
+	// ChainPluginServices are served to the chain's plugin.
+	PluginServices      PluginServices
+	ChainPluginServices ChainPluginServices
+}
+
 type factory struct {
-	path           string
-	processTracker resource.ProcessTracker
+	path   string
+	config FactoryConfig
 }
 
-func NewFactory(path string, processTracker resource.ProcessTracker) vms.Factory {
+// NewFactory returns a factory that launches the plugin at [path].
+func NewFactory(path string, config FactoryConfig) vms.Factory {
 	return &factory{
-		path:           path,
-		processTracker: processTracker,
+		path:   path,
+		config: config,
 	}
 }
 
 func (f *factory) New(ctx *snow.Context) (interface{}, error) {
+	pluginServices, err := f.pluginServices(ctx)
+	if err != nil {
+		return nil, err
+	}
+	group, err := f.newGroup(ctx)
+	if err != nil {
+		return nil, err
+	}
+	client, vm, err := f.launch(ctx, f.path)
+	if err != nil {
+		if group != nil {
+			_ = group.Close()
+		}
+		return nil, err
+	}
+	if err := limit(group, client); err != nil {
+		client.Kill()
+		_ = group.Close()
+		return nil, err
+	}
+
+	vm.SetProcess(ctx, client, f.config.ProcessTracker)
+	vm.multiplex = f.config.Multiplex
+	vm.mtls = f.config.MTLS
+	vm.unixSockets = f.config.UnixSockets
+	vm.dbIteratorPrefetch = f.config.DBIteratorPrefetch
+	vm.dbMaxBatchBytes = f.config.DBMaxBatchBytes
+	vm.dbChecksums = f.config.DBChecksums
+	vm.shutdownDrainTimeout = f.config.ShutdownDrainTimeout
+	vm.retryConfig = f.config.RPCRetry
+	vm.resourceGroup = group
+	vm.pluginServiceFactories = pluginServices
+	// If the plugin process exits, it is launched again and connected to
+	// this client. The plugin binary can be replaced by UpgradePlugin.
+	vm.pluginPath = f.path
+	vm.relaunch = func(path string) (grpc.ClientConnInterface, error) {
+		client, relaunched, err := f.launch(ctx, path)
+		if err != nil {
+			return nil, err
+		}
+		if err := limit(group, client); err != nil {
+			client.Kill()
+			return nil, err
+		}
+		vm.replaceProcess(client)
+		return relaunched.conn.get(), nil
+	}
+	return vm, nil
+}
+
+// pluginServices returns the factories of the plugin services enabled for the
+// chain of [ctx], by name.
+func (f *factory) pluginServices(ctx *snow.Context) (map[string]PluginServiceFactory, error) {
+	if ctx == nil || len(f.config.ChainPluginServices) == 0 {
+		return nil, nil
+	}
+	aliases, err := ctx.BCLookup.Aliases(ctx.ChainID)
+	if err != nil {
+		return nil, err
+	}
+	names, ok := f.config.ChainPluginServices.Get(ctx.ChainID, aliases)
+	if !ok {
+		return nil, nil
+	}
+	factories := make(map[string]PluginServiceFactory, len(names))
+	for _, name := range names {
+		factory, ok := f.config.PluginServices[name]
+		if !ok {
+			return nil, fmt.Errorf("%w: %q", errUnknownPluginService, name)
+		}
+		factories[name] = factory
+	}
+	ctx.Log.Info("serving plugin services",
+		zap.Strings("services", names),
+	)
+	return factories, nil
+}
+
+// newGroup returns the group that limits the resources of the plugin process of
+// the chain of [ctx], or nil if the chain has no limits.
+func (f *factory) newGroup(ctx *snow.Context) (resource.Group, error) {
+	if ctx == nil || f.config.CgroupRoot == "" {
+		return nil, nil
+	}
+	aliases, err := ctx.BCLookup.Aliases(ctx.ChainID)
+	if err != nil {
+		return nil, err
+	}
+	limits, ok := f.config.ResourceLimits.Get(ctx.ChainID, aliases)
+	if !ok {
+		return nil, nil
+	}
+	ctx.Log.Info("limiting plugin resources",
+		zap.Float64("cpu", limits.CPU),
+		zap.Uint64("memory", limits.Memory),
+	)
+	return resource.NewGroup(f.config.CgroupRoot, ctx.ChainID.String(), "plugin", limits)
+}
+
+// limit moves the process of [client] into [group], if set. The process runs
+// unlimited until it is moved.
+func limit(group resource.Group, client *plugin.Client) error {
+	if group == nil {
+		return nil
+	}
+	return group.Add(client.ReattachConfig().Pid)
+}
+
+// launch starts the plugin at [path] and returns the client connected to it.
+func (f *factory) launch(ctx *snow.Context, path string) (*plugin.Client, *VMClient, error) {
+	cmd := subprocess.New(path)
+	var env []string
+	if ctx != nil {
+		aliases, err := ctx.BCLookup.Aliases(ctx.ChainID)
+		if err != nil {
+			return nil, nil, err
+		}
+		if gcConfig, ok := f.config.GCConfigs.Get(ctx.ChainID, aliases); ok {
+			ctx.Log.Info("tuning plugin garbage collection",
+				zap.Int("gcPercent", gcConfig.GCPercent),
+				zap.Uint64("memoryLimit", gcConfig.MemoryLimit),
+			)
+			env = append(env, gcConfig.Env()...)
+		}
+	}
+	// The plugin uses the same transport as the node.
+	if f.config.UnixSockets {
+		env = append(env, unixSocketsEnvKey+"=true")
+	}
+	if len(env) > 0 {
+		cmd.Env = append(os.Environ(), env...)
+	}
+
 	config := &plugin.ClientConfig{
 		HandshakeConfig: Handshake,
 		Plugins:         PluginMap,
-		Cmd:             subprocess.New(f.path),
+		Cmd:             cmd,
 		AllowedProtocols: []plugin.Protocol{
 			plugin.ProtocolGRPC,
 		},
@@ -58,16 +243,23 @@ func (f *factory) New(ctx *snow.Context) (interface{}, error) {
 		// node shutdown to ensure every plugin subprocess is killed.
 		Managed:         true,
 		GRPCDialOptions: grpcutils.DefaultDialOptions,
+		// The certificates of the main connection are exchanged in the
+		// handshake, and the plugin is then given the certificates of the
+		// connections to the servers of the node over the main connection.
+		AutoMTLS: f.config.MTLS,
 	}
+	pluginName := filepath.Base(path)
 	// createStaticHandlers will send a nil ctx to disable logs
 	// TODO: create a separate log file and no-op ctx
 	if ctx != nil {
 		log.SetOutput(ctx.Log)
-		config.Stderr = ctx.Log
-		config.Logger = hclog.New(&hclog.LoggerOptions{
-			Output: ctx.Log,
-			Level:  hclog.Info,
-		})
+		// The output of the plugin is re-emitted through the chain's logger,
+		// keeping the level and the fields of structured entries.
+		pluginLog := newPluginLog(ctx.Log, ctx.ChainID, pluginName)
+		config.Stderr = pluginLog.writer(logging.Info)
+		config.SyncStdout = pluginLog.writer(logging.Info)
+		config.SyncStderr = pluginLog.writer(logging.Info)
+		config.Logger = pluginLog.newHCLogger()
 	} else {
 		log.SetOutput(io.Discard)
 		config.Stderr = io.Discard
@@ -77,7 +269,6 @@ func (f *factory) New(ctx *snow.Context) (interface{}, error) {
 	}
 	client := plugin.NewClient(config)
 
-	pluginName := filepath.Base(f.path)
 	pluginErr := func(err error) error {
 		return fmt.Errorf("plugin: %q: %w", pluginName, err)
 	}
@@ -85,21 +276,20 @@ func (f *factory) New(ctx *snow.Context) (interface{}, error) {
 	rpcClient, err := client.Client()
 	if err != nil {
 		client.Kill()
-		return nil, pluginErr(err)
+		return nil, nil, pluginErr(err)
 	}
 
 	raw, err := rpcClient.Dispense("vm")
 	if err != nil {
 		client.Kill()
-		return nil, pluginErr(err)
+		return nil, nil, pluginErr(err)
 	}
 
 	vm, ok := raw.(*VMClient)
 	if !ok {
 		client.Kill()
-		return nil, pluginErr(errWrongVM)
+		return nil, nil, pluginErr(errWrongVM)
 	}
 
-	vm.SetProcess(ctx, client, f.processTracker)
-	return vm, nil
+	return client, vm, nil
 }
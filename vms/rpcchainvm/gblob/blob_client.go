@@ -0,0 +1,94 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package gblob
+
+import (
+	"context"
+	"io"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+
+	blobpb "github.com/ava-labs/avalanchego/proto/pb/blob"
+)
+
+var _ snow.BlobService = (*Client)(nil)
+
+// Client is a blob service that talks over RPC.
+type Client struct {
+	client blobpb.BlobClient
+}
+
+// NewClient returns a blob service connected to a remote blob service
+func NewClient(client blobpb.BlobClient) *Client {
+	return &Client{client: client}
+}
+
+func (c *Client) Put(ctx context.Context, blob []byte) (ids.ID, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := c.client.Put(ctx)
+	if err != nil {
+		return ids.Empty, err
+	}
+	err = send(blob, stream.Send)
+	// If the server ended the stream, the reason is reported by CloseAndRecv.
+	if err != nil && err != io.EOF {
+		return ids.Empty, err
+	}
+	resp, err := stream.CloseAndRecv()
+	if err != nil {
+		return ids.Empty, err
+	}
+	return ids.ToID(resp.BlobId)
+}
+
+func (c *Client) Get(ctx context.Context, blobID ids.ID) ([]byte, error) {
+	stream, err := c.client.Get(ctx, &blobpb.GetRequest{
+		BlobId: blobID[:],
+	})
+	if err != nil {
+		return nil, err
+	}
+	return recv(stream.Recv)
+}
+
+func (c *Client) Fetch(ctx context.Context, blobID ids.ID, peers []ids.NodeID) ([]byte, error) {
+	req := &blobpb.FetchRequest{
+		BlobId:  blobID[:],
+		NodeIds: make([][]byte, len(peers)),
+	}
+	for i, nodeID := range peers {
+		nodeID := nodeID
+		req.NodeIds[i] = nodeID[:]
+	}
+	stream, err := c.client.Fetch(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return recv(stream.Recv)
+}
+
+// recv assembles the blob from the chunks returned by [recvChunk].
+func recv(recvChunk func() (*blobpb.BlobChunk, error)) ([]byte, error) {
+	var blob []byte
+	for {
+		msg, err := recvChunk()
+		if err == io.EOF {
+			return blob, nil
+		}
+		if status.Code(err) == codes.NotFound {
+			return nil, database.ErrNotFound
+		}
+		if err != nil {
+			return nil, err
+		}
+		blob = append(blob, msg.Chunk...)
+	}
+}
@@ -0,0 +1,112 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package gblob
+
+import (
+	"io"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+
+	blobpb "github.com/ava-labs/avalanchego/proto/pb/blob"
+)
+
+// maxChunkSize is the maximum number of bytes of a blob sent in a single
+// message.
+const maxChunkSize = 1024 * 1024
+
+var _ blobpb.BlobServer = (*Server)(nil)
+
+// Server is a blob service that is managed over RPC.
+type Server struct {
+	blobpb.UnsafeBlobServer
+	blobs snow.BlobService
+}
+
+// NewServer returns a blob service connected to a remote blob service
+func NewServer(blobs snow.BlobService) *Server {
+	return &Server{blobs: blobs}
+}
+
+func (s *Server) Put(stream blobpb.Blob_PutServer) error {
+	var blob []byte
+	for {
+		msg, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		blob = append(blob, msg.Chunk...)
+	}
+
+	blobID, err := s.blobs.Put(stream.Context(), blob)
+	if err != nil {
+		return err
+	}
+	return stream.SendAndClose(&blobpb.PutResponse{
+		BlobId: blobID[:],
+	})
+}
+
+func (s *Server) Get(req *blobpb.GetRequest, stream blobpb.Blob_GetServer) error {
+	blobID, err := ids.ToID(req.BlobId)
+	if err != nil {
+		return err
+	}
+	blob, err := s.blobs.Get(stream.Context(), blobID)
+	if err != nil {
+		return toRPCError(err)
+	}
+	return send(blob, stream.Send)
+}
+
+func (s *Server) Fetch(req *blobpb.FetchRequest, stream blobpb.Blob_FetchServer) error {
+	blobID, err := ids.ToID(req.BlobId)
+	if err != nil {
+		return err
+	}
+	peers := make([]ids.NodeID, len(req.NodeIds))
+	for i, nodeIDBytes := range req.NodeIds {
+		peers[i], err = ids.ToNodeID(nodeIDBytes)
+		if err != nil {
+			return err
+		}
+	}
+	blob, err := s.blobs.Fetch(stream.Context(), blobID, peers)
+	if err != nil {
+		return toRPCError(err)
+	}
+	return send(blob, stream.Send)
+}
+
+// send passes [blob] to [sendChunk] in chunks of at most [maxChunkSize]
+// bytes.
+func send(blob []byte, sendChunk func(*blobpb.BlobChunk) error) error {
+	for len(blob) > 0 {
+		n := len(blob)
+		if n > maxChunkSize {
+			n = maxChunkSize
+		}
+		if err := sendChunk(&blobpb.BlobChunk{Chunk: blob[:n]}); err != nil {
+			return err
+		}
+		blob = blob[n:]
+	}
+	return nil
+}
+
+// toRPCError reports database.ErrNotFound with the NOT_FOUND status code, so
+// that the client can tell it apart from other errors.
+func toRPCError(err error) error {
+	if err == database.ErrNotFound {
+		return status.Error(codes.NotFound, err.Error())
+	}
+	return err
+}
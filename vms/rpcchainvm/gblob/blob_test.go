@@ -0,0 +1,134 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package gblob
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/utils/hashing"
+	"github.com/ava-labs/avalanchego/vms/rpcchainvm/grpcutils"
+
+	blobpb "github.com/ava-labs/avalanchego/proto/pb/blob"
+)
+
+const bufSize = 1024 * 1024
+
+var _ snow.BlobService = (*testBlobService)(nil)
+
+// testBlobService stores blobs in memory and fetches them from [peers].
+type testBlobService struct {
+	blobs map[ids.ID][]byte
+	peers map[ids.NodeID]*testBlobService
+}
+
+func (s *testBlobService) Put(_ context.Context, blob []byte) (ids.ID, error) {
+	blobID := hashing.ComputeHash256Array(blob)
+	s.blobs[blobID] = blob
+	return blobID, nil
+}
+
+func (s *testBlobService) Get(_ context.Context, blobID ids.ID) ([]byte, error) {
+	blob, ok := s.blobs[blobID]
+	if !ok {
+		return nil, database.ErrNotFound
+	}
+	return blob, nil
+}
+
+func (s *testBlobService) Fetch(ctx context.Context, blobID ids.ID, peers []ids.NodeID) ([]byte, error) {
+	for _, nodeID := range peers {
+		blob, err := s.peers[nodeID].Get(ctx, blobID)
+		if err == nil {
+			s.blobs[blobID] = blob
+			return blob, nil
+		}
+	}
+	return nil, database.ErrNotFound
+}
+
+func TestBlobService(t *testing.T) {
+	require := require.New(t)
+
+	peerID := ids.GenerateTestNodeID()
+	peer := &testBlobService{
+		blobs: make(map[ids.ID][]byte),
+	}
+	service := &testBlobService{
+		blobs: make(map[ids.ID][]byte),
+		peers: map[ids.NodeID]*testBlobService{
+			peerID: peer,
+		},
+	}
+	client, closeFn := setupClient(require, service)
+	defer closeFn()
+
+	// The blobs span several messages.
+	blob := make([]byte, 2*maxChunkSize+1)
+	for i := range blob {
+		blob[i] = byte(i)
+	}
+	blobID, err := client.Put(context.Background(), blob)
+	require.NoError(err)
+	require.Equal(blob, service.blobs[blobID])
+
+	stored, err := client.Get(context.Background(), blobID)
+	require.NoError(err)
+	require.Equal(blob, stored)
+
+	peerBlob := []byte("peer blob")
+	peerBlobID, err := peer.Put(context.Background(), peerBlob)
+	require.NoError(err)
+
+	_, err = client.Get(context.Background(), peerBlobID)
+	require.ErrorIs(err, database.ErrNotFound)
+
+	fetched, err := client.Fetch(context.Background(), peerBlobID, []ids.NodeID{peerID})
+	require.NoError(err)
+	require.Equal(peerBlob, fetched)
+
+	_, err = client.Fetch(context.Background(), ids.GenerateTestID(), []ids.NodeID{peerID})
+	require.ErrorIs(err, database.ErrNotFound)
+}
+
+func setupClient(require *require.Assertions, blobs snow.BlobService) (*Client, func()) {
+	listener := bufconn.Listen(bufSize)
+	serverCloser := grpcutils.ServerCloser{}
+
+	serverFunc := func(opts []grpc.ServerOption) *grpc.Server {
+		server := grpc.NewServer(opts...)
+		blobpb.RegisterBlobServer(server, NewServer(blobs))
+		serverCloser.Add(server)
+		return server
+	}
+
+	go grpcutils.Serve(listener, serverFunc)
+
+	dialer := grpc.WithContextDialer(
+		func(context.Context, string) (net.Conn, error) {
+			return listener.Dial()
+		},
+	)
+
+	dopts := grpcutils.DefaultDialOptions
+	dopts = append(dopts, dialer)
+	conn, err := grpcutils.Dial("", dopts...)
+	require.NoError(err)
+
+	closeFn := func() {
+		serverCloser.Stop()
+		_ = conn.Close()
+		_ = listener.Close()
+	}
+	return NewClient(blobpb.NewBlobClient(conn)), closeFn
+}
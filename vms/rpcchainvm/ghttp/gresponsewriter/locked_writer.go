@@ -20,6 +20,7 @@ type lockedWriter struct {
 	lock          sync.Mutex
 	writer        http.ResponseWriter
 	headerWritten bool
+	hijacked      bool
 }
 
 func NewLockedWriter(w http.ResponseWriter) http.ResponseWriter {
@@ -37,6 +38,9 @@ func (lw *lockedWriter) Write(b []byte) (int, error) {
 	lw.lock.Lock()
 	defer lw.lock.Unlock()
 
+	if lw.hijacked {
+		return 0, http.ErrHijacked
+	}
 	lw.headerWritten = true
 	return lw.writer.Write(b)
 }
@@ -45,8 +49,9 @@ func (lw *lockedWriter) WriteHeader(statusCode int) {
 	lw.lock.Lock()
 	defer lw.lock.Unlock()
 
-	// Skip writing the header if it has already been written once.
-	if lw.headerWritten {
+	// Skip writing the header if it has already been written once or the
+	// connection was hijacked.
+	if lw.headerWritten || lw.hijacked {
 		return
 	}
 	lw.headerWritten = true
@@ -71,5 +76,9 @@ func (lw *lockedWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
 	if !ok {
 		return nil, nil, errUnsupportedHijacking
 	}
-	return hijacker.Hijack()
+	conn, readWriter, err := hijacker.Hijack()
+	if err == nil {
+		lw.hijacked = true
+	}
+	return conn, readWriter, err
 }
@@ -33,12 +33,16 @@ var (
 type Server struct {
 	responsewriterpb.UnsafeWriterServer
 	writer http.ResponseWriter
+	// If true, hijacked connections are served on unix domain sockets
+	unixSockets bool
 }
 
-// NewServer returns an http.ResponseWriter instance managed remotely
-func NewServer(writer http.ResponseWriter) *Server {
+// NewServer returns an http.ResponseWriter instance managed remotely. Hijacked
+// connections are served on unix domain sockets if [unixSockets] is true.
+func NewServer(writer http.ResponseWriter, unixSockets bool) *Server {
 	return &Server{
-		writer: writer,
+		writer:      writer,
+		unixSockets: unixSockets,
 	}
 }
 
@@ -97,7 +101,7 @@ func (s *Server) Hijack(context.Context, *emptypb.Empty) (*responsewriterpb.Hija
 		return nil, err
 	}
 
-	serverListener, err := grpcutils.NewListener()
+	serverListener, err := grpcutils.NewLocalListener(s.unixSockets)
 	if err != nil {
 		return nil, err
 	}
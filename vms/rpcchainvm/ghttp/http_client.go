@@ -4,11 +4,18 @@
 package ghttp
 
 import (
+	"bytes"
+	"context"
 	"io"
 	"net/http"
+	"sync"
+	"time"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
+	"github.com/ava-labs/avalanchego/utils"
 	"github.com/ava-labs/avalanchego/vms/rpcchainvm/ghttp/gresponsewriter"
 	"github.com/ava-labs/avalanchego/vms/rpcchainvm/grpcutils"
 
@@ -16,11 +23,25 @@ import (
 	responsewriterpb "github.com/ava-labs/avalanchego/proto/pb/http/responsewriter"
 )
 
-var _ http.Handler = (*Client)(nil)
+var (
+	_ http.Handler = (*Client)(nil)
+	_ io.Closer    = (*Client)(nil)
+)
 
 // Client is an http.Handler that talks over RPC.
 type Client struct {
 	client httppb.HTTPClient
+	// true once the handler reported that it doesn't serve HandleStream
+	streamUnsupported utils.AtomicBool
+
+	// If set, the connection to the remote handler, which is closed when the
+	// client is closed
+	conn      io.Closer
+	closeOnce sync.Once
+	closeErr  error
+
+	// If true, the response writers are served on unix domain sockets
+	unixSockets bool
 }
 
 // NewClient returns an HTTP handler database instance connected to a remote
@@ -31,23 +52,131 @@ func NewClient(client httppb.HTTPClient) *Client {
 	}
 }
 
+// NewConnClient returns an HTTP handler connected to a remote HTTP handler
+// over [conn]. The client owns [conn], which is closed when the client is
+// closed. The response writers are served to the remote handler on unix
+// domain sockets if [unixSockets] is true.
+func NewConnClient(conn *grpc.ClientConn, unixSockets bool) *Client {
+	return &Client{
+		client:      httppb.NewHTTPClient(conn),
+		conn:        conn,
+		unixSockets: unixSockets,
+	}
+}
+
+// Close closes the connection of the client, if the client owns it. The
+// requests being served are cancelled, and the requests served afterwards
+// fail. Close may be called multiple times.
+func (c *Client) Close() error {
+	if c.conn == nil {
+		return nil
+	}
+	c.closeOnce.Do(func() {
+		c.closeErr = c.conn.Close()
+	})
+	return c.closeErr
+}
+
 func (c *Client) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	// rfc2616#section-14.42: The Upgrade general-header allows the client
 	// to specify a communication protocols it supports and would like to
 	// use. Upgrade (e.g. websockets) is a more expensive transaction and
 	// if not required use the less expensive HTTPSimple.
 	if !isUpgradeRequest(r) {
-		c.serveHTTPSimple(w, r)
+		c.serveStream(w, r)
+		return
+	}
+	c.serveUpgrade(w, r)
+}
+
+// serveUpgrade serves a protocol upgrade request through a HandleUpgrade
+// stream. If the handler hijacks the connection, the connection is tunneled
+// through the stream until either side closes it.
+func (c *Client) serveUpgrade(w http.ResponseWriter, r *http.Request) {
+	req, err := getHTTPSimpleRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	stream, err := c.client.HandleUpgrade(ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	// If sending fails, the reason is reported when receiving.
+	_ = stream.Send(&httppb.UpgradeRequest{
+		Request:    req,
+		RemoteAddr: r.RemoteAddr,
+	})
+	resp, err := stream.Recv()
+	if status.Code(err) == codes.Unimplemented {
+		// The handler predates HandleUpgrade, so it can only be reached by
+		// having it dial back to this node.
+		r.Body = io.NopCloser(bytes.NewReader(req.Body))
+		c.serveHTTP(w, r)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if resp.Response != nil {
+		if err := convertWriteResponse(w, resp.Response); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+	if !resp.Hijacked {
+		http.Error(w, errUnexpectedResponse.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		http.Error(w, errUnsupportedHijacking.Error(), http.StatusInternalServerError)
+		return
+	}
+	conn, readWriter, err := hijacker.Hijack()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer conn.Close()
+
+	// The timeouts of the server apply to requests, not to the connections
+	// they're upgraded to.
+	if err := conn.SetDeadline(time.Time{}); err != nil {
 		return
 	}
 
+	// The reader may hold bytes the client sent after the request.
+	go forward(stream, readWriter.Reader)
+	for {
+		msg, err := stream.Recv()
+		if err != nil {
+			return
+		}
+		if _, err := conn.Write(msg.Data); err != nil {
+			return
+		}
+	}
+}
+
+// serveHTTP serves a protocol upgrade request through Handle, which requires
+// the handler to dial back to the response writer served by this node. Only
+// used for handlers that don't serve HandleUpgrade.
+func (c *Client) serveHTTP(w http.ResponseWriter, r *http.Request) {
 	closer := grpcutils.ServerCloser{}
 	defer closer.GracefulStop()
 
 	// Wrap [w] with a lock to ensure that it is accessed in a thread-safe manner.
 	w = gresponsewriter.NewLockedWriter(w)
 
-	serverListener, err := grpcutils.NewListener()
+	serverListener, err := grpcutils.NewLocalListener(c.unixSockets)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -61,7 +190,7 @@ func (c *Client) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		}
 		server := grpc.NewServer(opts...)
 		closer.Add(server)
-		responsewriterpb.RegisterWriterServer(server, gresponsewriter.NewServer(w))
+		responsewriterpb.RegisterWriterServer(server, gresponsewriter.NewServer(w, c.unixSockets))
 		return server
 	})
 
@@ -75,6 +204,7 @@ func (c *Client) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		ResponseWriter: &httppb.ResponseWriter{
 			ServerAddr: serverAddr,
 			Header:     make([]*httppb.Element, 0, len(r.Header)),
+			Compressor: grpcutils.ZstdCompressorName,
 		},
 		Request: &httppb.Request{
 			Method:           r.Method,
@@ -173,16 +303,66 @@ func (c *Client) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-// serveHTTPSimple converts an http request to a gRPC HTTPRequest and returns the
-// response to the client. Protocol upgrade requests (websockets) are not supported
-// and should use ServeHTTP. Based on https://www.weave.works/blog/turtles-way-http-grpc.
-func (c *Client) serveHTTPSimple(w http.ResponseWriter, r *http.Request) {
+// serveStream serves a request through a HandleStream stream. The response is
+// written, and flushed, as the handler flushes it, so that long-polling and
+// server-sent events endpoints work.
+func (c *Client) serveStream(w http.ResponseWriter, r *http.Request) {
 	req, err := getHTTPSimpleRequest(r)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
+	if c.streamUnsupported.GetValue() {
+		c.serveHTTPSimple(w, r, req)
+		return
+	}
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
 
+	stream, err := c.client.HandleStream(ctx, req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	resp, err := stream.Recv()
+	if status.Code(err) == codes.Unimplemented {
+		// The handler predates HandleStream, so the response can only be
+		// buffered.
+		c.streamUnsupported.SetValue(true)
+		c.serveHTTPSimple(w, r, req)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	grpcutils.MergeHTTPHeader(resp.Headers, w.Header())
+	w.WriteHeader(grpcutils.EnsureValidResponseCode(int(resp.Code)))
+
+	flusher, canFlush := w.(http.Flusher)
+	for {
+		if _, err := w.Write(resp.Body); err != nil {
+			return
+		}
+		if resp.Flush && canFlush {
+			flusher.Flush()
+		}
+
+		// Once the response was started, failures can't be reported to the
+		// client, so the response is cut short.
+		resp, err = stream.Recv()
+		if err != nil {
+			return
+		}
+	}
+}
+
+// serveHTTPSimple converts an http request to a gRPC HTTPRequest and returns the
+// response to the client. Protocol upgrade requests (websockets) are not supported
+// and should use ServeHTTP. Based on https://www.weave.works/blog/turtles-way-http-grpc.
+func (c *Client) serveHTTPSimple(w http.ResponseWriter, r *http.Request, req *httppb.HandleSimpleHTTPRequest) {
 	resp, err := c.client.HandleSimple(r.Context(), req)
 	if err != nil {
 		// Some errors will actually contain a valid resp, just need to unpack it
@@ -11,6 +11,7 @@ import (
 	"net/http"
 	"net/url"
 
+	"google.golang.org/grpc"
 	"google.golang.org/protobuf/types/known/emptypb"
 
 	"github.com/ava-labs/avalanchego/vms/rpcchainvm/ghttp/gresponsewriter"
@@ -23,6 +24,7 @@ import (
 var (
 	_ httppb.HTTPServer   = (*Server)(nil)
 	_ http.ResponseWriter = (*ResponseWriter)(nil)
+	_ http.Flusher        = (*ResponseWriter)(nil)
 )
 
 // Server is an http.Handler that is managed over RPC.
@@ -39,7 +41,11 @@ func NewServer(handler http.Handler) *Server {
 }
 
 func (s *Server) Handle(ctx context.Context, req *httppb.HTTPRequest) (*emptypb.Empty, error) {
-	clientConn, err := grpcutils.Dial(req.ResponseWriter.ServerAddr)
+	var dialOpts []grpc.DialOption
+	if grpcutils.IsCompressor(req.ResponseWriter.Compressor) {
+		dialOpts = grpcutils.WithCompression(req.ResponseWriter.Compressor)
+	}
+	clientConn, err := grpcutils.Dial(req.ResponseWriter.ServerAddr, dialOpts...)
 	if err != nil {
 		return nil, err
 	}
@@ -145,17 +151,11 @@ func (s *Server) Handle(ctx context.Context, req *httppb.HTTPRequest) (*emptypb.
 // HandleSimple handles http requests over http2 using a simple request response model.
 // Websockets are not supported. Based on https://www.weave.works/blog/turtles-way-http-grpc/
 func (s *Server) HandleSimple(ctx context.Context, r *httppb.HandleSimpleHTTPRequest) (*httppb.HandleSimpleHTTPResponse, error) {
-	req, err := http.NewRequest(r.Method, r.Url, bytes.NewBuffer(r.Body))
+	req, err := getHTTPRequest(ctx, r)
 	if err != nil {
 		return nil, err
 	}
 
-	grpcutils.MergeHTTPHeader(r.Headers, req.Header)
-
-	req = req.WithContext(ctx)
-	req.RequestURI = r.Url
-	req.ContentLength = int64(len(r.Body))
-
 	w := newResponseWriter()
 	s.handler.ServeHTTP(w, req)
 
@@ -171,6 +171,57 @@ func (s *Server) HandleSimple(ctx context.Context, r *httppb.HandleSimpleHTTPReq
 	return resp, nil
 }
 
+// HandleUpgrade handles http requests that ask to upgrade their connection to
+// another protocol, such as websockets. If the handler hijacks the connection,
+// the connection is tunneled through [stream] until either side closes it.
+func (s *Server) HandleUpgrade(stream httppb.HTTP_HandleUpgradeServer) error {
+	msg, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	if msg.Request == nil {
+		return errMissingRequest
+	}
+	req, err := getHTTPRequest(stream.Context(), msg.Request)
+	if err != nil {
+		return err
+	}
+	req.RemoteAddr = msg.RemoteAddr
+
+	w := newUpgradeResponseWriter(stream, req.RemoteAddr)
+	s.handler.ServeHTTP(w, req)
+	return w.finish()
+}
+
+// HandleStream handles http requests like HandleSimple, but sends the response
+// through [stream] every time the handler flushes it, so that streaming
+// handlers, such as server-sent events endpoints, can be served.
+func (s *Server) HandleStream(r *httppb.HandleSimpleHTTPRequest, stream httppb.HTTP_HandleStreamServer) error {
+	req, err := getHTTPRequest(stream.Context(), r)
+	if err != nil {
+		return err
+	}
+
+	w := newStreamResponseWriter(stream)
+	s.handler.ServeHTTP(w, req)
+	return w.finish()
+}
+
+// getHTTPRequest takes a gRPC HandleSimpleHTTPRequest as input and returns an
+// http request.
+func getHTTPRequest(ctx context.Context, r *httppb.HandleSimpleHTTPRequest) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, r.Method, r.Url, bytes.NewBuffer(r.Body))
+	if err != nil {
+		return nil, err
+	}
+
+	grpcutils.MergeHTTPHeader(r.Headers, req.Header)
+
+	req.RequestURI = r.Url
+	req.ContentLength = int64(len(r.Body))
+	return req, nil
+}
+
 type ResponseWriter struct {
 	body       *bytes.Buffer
 	header     http.Header
@@ -198,6 +249,10 @@ func (w *ResponseWriter) WriteHeader(code int) {
 	w.statusCode = code
 }
 
+// Flush is a no-op, as the response is only sent once the handler returns. It's
+// implemented so that streaming handlers degrade to buffered responses.
+func (*ResponseWriter) Flush() {}
+
 func (w *ResponseWriter) StatusCode() int {
 	return w.statusCode
 }
@@ -0,0 +1,99 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package ghttp
+
+import (
+	"bytes"
+	"net/http"
+
+	"github.com/ava-labs/avalanchego/utils/units"
+	"github.com/ava-labs/avalanchego/vms/rpcchainvm/grpcutils"
+
+	httppb "github.com/ava-labs/avalanchego/proto/pb/http"
+)
+
+// Number of buffered bytes of a streamed response after which they're sent
+// even if the handler didn't flush
+const streamBufferSize = 256 * units.KiB
+
+var (
+	_ http.ResponseWriter = (*streamResponseWriter)(nil)
+	_ http.Flusher        = (*streamResponseWriter)(nil)
+)
+
+// streamResponseWriter is the response writer of a request served through
+// HandleStream. Writes are buffered until the handler flushes the response,
+// at which point they're sent through the stream.
+type streamResponseWriter struct {
+	stream     httppb.HTTP_HandleStreamServer
+	header     http.Header
+	statusCode int
+	body       bytes.Buffer
+	// true once the status code and headers were sent
+	headerSent bool
+	// error that caused the stream to fail, if any
+	err error
+}
+
+func newStreamResponseWriter(stream httppb.HTTP_HandleStreamServer) *streamResponseWriter {
+	return &streamResponseWriter{
+		stream: stream,
+		header: make(http.Header),
+	}
+}
+
+func (w *streamResponseWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *streamResponseWriter) Write(buf []byte) (int, error) {
+	if w.err != nil {
+		return 0, w.err
+	}
+	n, _ := w.body.Write(buf)
+	if w.body.Len() >= streamBufferSize {
+		w.send(false)
+	}
+	return n, w.err
+}
+
+func (w *streamResponseWriter) WriteHeader(code int) {
+	// Like the http package, only the first status code is used.
+	if w.statusCode == 0 {
+		w.statusCode = code
+	}
+}
+
+func (w *streamResponseWriter) Flush() {
+	w.send(true)
+}
+
+// finish sends the rest of the response once the handler returned.
+func (w *streamResponseWriter) finish() error {
+	if !w.headerSent || w.body.Len() > 0 {
+		w.send(false)
+	}
+	return w.err
+}
+
+func (w *streamResponseWriter) send(flush bool) {
+	if w.err != nil {
+		return
+	}
+
+	msg := &httppb.HandleStreamResponse{
+		Body:  w.body.Bytes(),
+		Flush: flush,
+	}
+	if !w.headerSent {
+		if w.statusCode == 0 {
+			w.statusCode = http.StatusOK
+		}
+		msg.Code = int32(w.statusCode)
+		msg.Headers = grpcutils.GetHTTPHeader(w.header)
+		w.headerSent = true
+	}
+	w.err = w.stream.Send(msg)
+	w.body.Reset()
+}
@@ -0,0 +1,125 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package ghttp
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// eventsHandler sends [numEvents] server-sent events. Every event is only sent
+// once the client acknowledged the previous one through [acks].
+func eventsHandler(numEvents int, acks <-chan struct{}) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		flusher := w.(http.Flusher)
+		for i := 0; i < numEvents; i++ {
+			if i > 0 {
+				select {
+				case <-acks:
+				case <-r.Context().Done():
+					return
+				}
+			}
+			_, _ = fmt.Fprintf(w, "data: %d\n\n", i)
+			flusher.Flush()
+		}
+	})
+}
+
+func TestStreamFlushes(t *testing.T) {
+	require := require.New(t)
+
+	numEvents := 3
+	acks := make(chan struct{})
+	proxy := newProxy(t, NewServer(eventsHandler(numEvents, acks)), 0)
+
+	resp, err := http.Get(proxy.URL)
+	require.NoError(err)
+	defer resp.Body.Close()
+	require.Equal(http.StatusOK, resp.StatusCode)
+	require.Equal("text/event-stream", resp.Header.Get("Content-Type"))
+
+	// Every event must be received before the next one is written, which
+	// wouldn't be possible if the response was buffered.
+	reader := bufio.NewReader(resp.Body)
+	for i := 0; i < numEvents; i++ {
+		line, err := reader.ReadString('\n')
+		require.NoError(err)
+		require.Equal(fmt.Sprintf("data: %d\n", i), line)
+		_, err = reader.ReadString('\n')
+		require.NoError(err)
+
+		if i < numEvents-1 {
+			acks <- struct{}{}
+		}
+	}
+	_, err = reader.ReadByte()
+	require.ErrorIs(err, io.EOF)
+}
+
+func TestStreamLargeResponse(t *testing.T) {
+	require := require.New(t)
+
+	body := bytes.Repeat([]byte{'a'}, 3*streamBufferSize+1)
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+		_, _ = w.Write(body)
+	})
+	proxy := newProxy(t, NewServer(handler), 0)
+
+	resp, err := http.Get(proxy.URL)
+	require.NoError(err)
+	defer resp.Body.Close()
+	require.Equal(http.StatusAccepted, resp.StatusCode)
+
+	received, err := io.ReadAll(resp.Body)
+	require.NoError(err)
+	require.Equal(body, received)
+}
+
+func TestStreamEmptyResponse(t *testing.T) {
+	require := require.New(t)
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("foo", "bar")
+		w.WriteHeader(http.StatusNoContent)
+	})
+	proxy := newProxy(t, NewServer(handler), 0)
+
+	resp, err := http.Get(proxy.URL)
+	require.NoError(err)
+	defer resp.Body.Close()
+	require.Equal(http.StatusNoContent, resp.StatusCode)
+	require.Equal("bar", resp.Header.Get("foo"))
+}
+
+// Handlers that predate HandleStream are served through HandleSimple, so
+// their responses are buffered.
+func TestStreamLegacyHandler(t *testing.T) {
+	require := require.New(t)
+
+	acks := make(chan struct{}, 1)
+	acks <- struct{}{}
+	proxy := newProxy(t, &legacyServer{server: NewServer(eventsHandler(2, acks))}, 0)
+
+	for i := 0; i < 2; i++ {
+		resp, err := http.Get(proxy.URL)
+		require.NoError(err)
+
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(err)
+		require.NoError(resp.Body.Close())
+		require.Equal(http.StatusOK, resp.StatusCode)
+		require.Equal("data: 0\n\ndata: 1\n\n", string(body))
+
+		acks <- struct{}{}
+	}
+}
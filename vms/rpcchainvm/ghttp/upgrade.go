@@ -0,0 +1,202 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package ghttp
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+
+	"github.com/ava-labs/avalanchego/utils/units"
+	"github.com/ava-labs/avalanchego/vms/rpcchainvm/grpcutils"
+
+	httppb "github.com/ava-labs/avalanchego/proto/pb/http"
+)
+
+// Max number of bytes of a connection sent in a single message of an upgrade
+// stream
+const tunnelBufferSize = 32 * units.KiB
+
+var (
+	errMissingRequest       = errors.New("upgrade stream didn't start with a request")
+	errUnexpectedResponse   = errors.New("upgrade stream didn't start with a response")
+	errAlreadyHijacked      = errors.New("connection was already hijacked")
+	errHijackAfterWrite     = errors.New("connection can't be hijacked after the response was written")
+	errUnsupportedHijacking = errors.New("response writer doesn't support hijacking")
+
+	_ http.Hijacker = (*upgradeResponseWriter)(nil)
+	_ net.Conn      = (*tunneledConn)(nil)
+)
+
+// upgradeResponseWriter is the response writer of a request served through an
+// upgrade stream. The response is buffered and sent once the handler returns,
+// unless the handler hijacks the connection, in which case the connection is
+// tunneled through the stream.
+type upgradeResponseWriter struct {
+	*ResponseWriter
+
+	stream     httppb.HTTP_HandleUpgradeServer
+	remoteAddr string
+	written    bool
+	hijacked   bool
+	// Closed once the hijacked connection is no longer tunneled
+	done chan struct{}
+}
+
+func newUpgradeResponseWriter(stream httppb.HTTP_HandleUpgradeServer, remoteAddr string) *upgradeResponseWriter {
+	return &upgradeResponseWriter{
+		ResponseWriter: newResponseWriter(),
+		stream:         stream,
+		remoteAddr:     remoteAddr,
+		done:           make(chan struct{}),
+	}
+}
+
+func (w *upgradeResponseWriter) Write(buf []byte) (int, error) {
+	if w.hijacked {
+		return 0, http.ErrHijacked
+	}
+	w.written = true
+	return w.ResponseWriter.Write(buf)
+}
+
+func (w *upgradeResponseWriter) WriteHeader(code int) {
+	if w.hijacked {
+		return
+	}
+	w.written = true
+	w.ResponseWriter.WriteHeader(code)
+}
+
+// Hijack returns one end of an in-memory connection whose other end is
+// tunneled through the stream.
+func (w *upgradeResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if w.hijacked {
+		return nil, nil, errAlreadyHijacked
+	}
+	if w.written {
+		return nil, nil, errHijackAfterWrite
+	}
+	if err := w.stream.Send(&httppb.UpgradeResponse{Hijacked: true}); err != nil {
+		return nil, nil, err
+	}
+	w.hijacked = true
+
+	local, remote := net.Pipe()
+	go w.receive(remote)
+	go w.send(remote)
+
+	conn := &tunneledConn{
+		Conn: local,
+		remoteAddr: &addr{
+			network: "tcp",
+			str:     w.remoteAddr,
+		},
+	}
+	readWriter := bufio.NewReadWriter(
+		bufio.NewReader(conn),
+		bufio.NewWriter(conn),
+	)
+	return conn, readWriter, nil
+}
+
+// receive writes the bytes received through the stream to [conn] until the
+// stream ends.
+func (w *upgradeResponseWriter) receive(conn net.Conn) {
+	defer conn.Close()
+
+	for {
+		msg, err := w.stream.Recv()
+		if err != nil {
+			return
+		}
+		if _, err := conn.Write(msg.Data); err != nil {
+			return
+		}
+	}
+}
+
+// send sends the bytes written to [conn] through the stream until [conn] is
+// closed.
+func (w *upgradeResponseWriter) send(conn net.Conn) {
+	defer close(w.done)
+	defer conn.Close()
+
+	buf := make([]byte, tunnelBufferSize)
+	for {
+		n, err := conn.Read(buf)
+		if n > 0 {
+			if err := w.stream.Send(&httppb.UpgradeResponse{Data: buf[:n]}); err != nil {
+				return
+			}
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// finish sends the response if the connection wasn't hijacked. Otherwise,
+// waits for the hijacked connection to be closed, as the handler may keep
+// using it after returning.
+func (w *upgradeResponseWriter) finish() error {
+	if !w.hijacked {
+		return w.stream.Send(&httppb.UpgradeResponse{
+			Response: &httppb.HandleSimpleHTTPResponse{
+				Code:    int32(w.statusCode),
+				Headers: grpcutils.GetHTTPHeader(w.Header()),
+				Body:    w.body.Bytes(),
+			},
+		})
+	}
+	<-w.done
+	return nil
+}
+
+// tunneledConn is a hijacked connection that reports the address of the
+// client that sent the request.
+type tunneledConn struct {
+	net.Conn
+	remoteAddr net.Addr
+}
+
+func (c *tunneledConn) RemoteAddr() net.Addr {
+	return c.remoteAddr
+}
+
+type addr struct {
+	network string
+	str     string
+}
+
+func (a *addr) Network() string {
+	return a.network
+}
+
+func (a *addr) String() string {
+	return a.str
+}
+
+// forward sends the bytes read from [conn] through [stream] until [conn] is
+// closed, at which point the stream is closed for sending.
+func forward(stream httppb.HTTP_HandleUpgradeClient, conn io.Reader) {
+	defer func() {
+		_ = stream.CloseSend()
+	}()
+
+	buf := make([]byte, tunnelBufferSize)
+	for {
+		n, err := conn.Read(buf)
+		if n > 0 {
+			if err := stream.Send(&httppb.UpgradeRequest{Data: buf[:n]}); err != nil {
+				return
+			}
+		}
+		if err != nil {
+			return
+		}
+	}
+}
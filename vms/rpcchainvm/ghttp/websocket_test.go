@@ -0,0 +1,156 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package ghttp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/stretchr/testify/require"
+
+	"google.golang.org/grpc"
+
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/ava-labs/avalanchego/vms/rpcchainvm/grpcutils"
+
+	httppb "github.com/ava-labs/avalanchego/proto/pb/http"
+)
+
+// legacyServer is a Server that predates HandleUpgrade.
+type legacyServer struct {
+	httppb.UnimplementedHTTPServer
+	server *Server
+}
+
+func (s *legacyServer) Handle(ctx context.Context, req *httppb.HTTPRequest) (*emptypb.Empty, error) {
+	return s.server.Handle(ctx, req)
+}
+
+func (s *legacyServer) HandleSimple(ctx context.Context, req *httppb.HandleSimpleHTTPRequest) (*httppb.HandleSimpleHTTPResponse, error) {
+	return s.server.HandleSimple(ctx, req)
+}
+
+// echoHandler echoes the messages sent over a websocket connection. The first
+// message sent to the client is the address the request was sent from.
+func echoHandler(t *testing.T) http.Handler {
+	upgrader := websocket.Upgrader{}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Logf("failed to upgrade: %s", err)
+			return
+		}
+		defer conn.Close()
+
+		if err := conn.WriteMessage(websocket.TextMessage, []byte(r.RemoteAddr)); err != nil {
+			return
+		}
+		for {
+			messageType, message, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			if err := conn.WriteMessage(messageType, message); err != nil {
+				return
+			}
+		}
+	})
+}
+
+// newProxy returns a server that proxies its requests to [server] through a
+// Client.
+func newProxy(t *testing.T, server httppb.HTTPServer, timeout time.Duration) *httptest.Server {
+	require := require.New(t)
+
+	listener, err := grpcutils.NewListener()
+	require.NoError(err)
+
+	closer := grpcutils.ServerCloser{}
+	go grpcutils.Serve(listener, func(opts []grpc.ServerOption) *grpc.Server {
+		if len(opts) == 0 {
+			opts = append(opts, grpcutils.DefaultServerOptions...)
+		}
+		grpcServer := grpc.NewServer(opts...)
+		closer.Add(grpcServer)
+		httppb.RegisterHTTPServer(grpcServer, server)
+		return grpcServer
+	})
+
+	conn, err := grpcutils.Dial(listener.Addr().String())
+	require.NoError(err)
+
+	proxy := httptest.NewUnstartedServer(NewClient(httppb.NewHTTPClient(conn)))
+	proxy.Config.ReadTimeout = timeout
+	proxy.Config.WriteTimeout = timeout
+	proxy.Start()
+	t.Cleanup(func() {
+		proxy.Close()
+		_ = conn.Close()
+		closer.Stop()
+	})
+	return proxy
+}
+
+func requireEcho(t *testing.T, proxy *httptest.Server, wait time.Duration) {
+	require := require.New(t)
+
+	url := "ws" + strings.TrimPrefix(proxy.URL, "http")
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	require.NoError(err)
+	defer conn.Close()
+
+	_, remoteAddr, err := conn.ReadMessage()
+	require.NoError(err)
+	require.Equal(conn.LocalAddr().String(), string(remoteAddr))
+
+	time.Sleep(wait)
+
+	for _, message := range []string{"hello", "world"} {
+		require.NoError(conn.WriteMessage(websocket.TextMessage, []byte(message)))
+		messageType, echoed, err := conn.ReadMessage()
+		require.NoError(err)
+		require.Equal(websocket.TextMessage, messageType)
+		require.Equal(message, string(echoed))
+	}
+}
+
+func TestWebsocket(t *testing.T) {
+	proxy := newProxy(t, NewServer(echoHandler(t)), 0)
+	requireEcho(t, proxy, 0)
+}
+
+// Upgraded connections must outlive the timeouts the server applies to
+// requests.
+func TestWebsocketOutlivesRequestTimeouts(t *testing.T) {
+	timeout := 100 * time.Millisecond
+	proxy := newProxy(t, NewServer(echoHandler(t)), timeout)
+	requireEcho(t, proxy, 2*timeout)
+}
+
+func TestWebsocketLegacyHandler(t *testing.T) {
+	proxy := newProxy(t, &legacyServer{server: NewServer(echoHandler(t))}, 0)
+	requireEcho(t, proxy, 0)
+}
+
+func TestUpgradeRejected(t *testing.T) {
+	require := require.New(t)
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "upgrades aren't supported", http.StatusBadRequest)
+	})
+	proxy := newProxy(t, NewServer(handler), 0)
+
+	url := "ws" + strings.TrimPrefix(proxy.URL, "http")
+	_, resp, err := websocket.DefaultDialer.Dial(url, nil)
+	require.ErrorIs(err, websocket.ErrBadHandshake)
+	require.Equal(http.StatusBadRequest, resp.StatusCode)
+	_ = resp.Body.Close()
+}
@@ -0,0 +1,225 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpcchainvm
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/ava-labs/avalanchego/ids"
+
+	vmpb "github.com/ava-labs/avalanchego/proto/pb/vm"
+)
+
+const (
+	// Maximum number of gossip messages sent to the plugin in a single batch
+	maxGossipBatchSize = 64
+	// Maximum number of gossip messages waiting to be sent to the plugin.
+	// AppGossip blocks while this many messages are waiting.
+	gossipQueueSize = 1024
+	// Maximum number of batches sent to the plugin that it hasn't delivered
+	// yet. No batch is sent while this many batches are being delivered.
+	maxGossipBatchesInFlight = 16
+)
+
+var (
+	errGossipStreamClosed = errors.New("gossip stream closed")
+	errMissingGossipAck   = errors.New("gossip batch acknowledged without the message's result")
+)
+
+// pendingGossip is a gossip message that the plugin hasn't delivered yet.
+type pendingGossip struct {
+	msg *vmpb.AppGossipMsg
+	// Receives the result of delivering [msg]
+	result chan error
+}
+
+// gossipStream delivers the gossip messages sent to the plugin over a single
+// stream. The messages of concurrent callers are sent in batches, and each
+// message is delivered by the plugin as by AppGossip, in the order the messages
+// were queued.
+type gossipStream struct {
+	stream vmpb.VM_AppGossipStreamClient
+	cancel context.CancelFunc
+
+	queue chan *pendingGossip
+	// Holds the batches that the plugin hasn't acknowledged, in the order they
+	// were sent.
+	inFlight chan []*pendingGossip
+
+	closed  chan struct{}
+	closing sync.Once
+	done    sync.WaitGroup
+}
+
+// openGossipStream opens a gossip stream to the plugin, unless the plugin
+// reported that it doesn't support gossip streams.
+func (vm *VMClient) openGossipStream() (*gossipStream, error) {
+	if !vm.capabilities.Has(CapabilityGossipStream) {
+		return nil, nil
+	}
+	return newGossipStream(vm.client)
+}
+
+// newGossipStream opens a gossip stream to the plugin. If the plugin doesn't
+// support gossip streams, nil is returned without an error.
+func newGossipStream(client vmpb.VMClient) (*gossipStream, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	stream, err := client.AppGossipStream(ctx)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	// The plugin acknowledges the stream before any batch is sent.
+	if _, err := stream.Recv(); err != nil {
+		cancel()
+		if status.Code(err) == codes.Unimplemented {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	s := &gossipStream{
+		stream:   stream,
+		cancel:   cancel,
+		queue:    make(chan *pendingGossip, gossipQueueSize),
+		inFlight: make(chan []*pendingGossip, maxGossipBatchesInFlight),
+		closed:   make(chan struct{}),
+	}
+	s.done.Add(2)
+	go s.sendBatches()
+	go s.receiveAcks()
+	return s, nil
+}
+
+// AppGossip delivers [msg] to the plugin and returns the error returned by the
+// plugin's VM while delivering it. It blocks while the queue is full and until
+// the message is delivered, so that the VM handles the message before the
+// following calls to the VM.
+func (s *gossipStream) AppGossip(ctx context.Context, nodeID ids.NodeID, msg []byte) error {
+	pending := &pendingGossip{
+		msg: &vmpb.AppGossipMsg{
+			NodeId: nodeID[:],
+			Msg:    msg,
+		},
+		result: make(chan error, 1),
+	}
+	select {
+	case s.queue <- pending:
+	case <-s.closed:
+		return errGossipStreamClosed
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	select {
+	case err := <-pending.result:
+		return err
+	case <-s.closed:
+		return errGossipStreamClosed
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Closed returns true if the stream was closed, either by Close or because it
+// failed.
+func (s *gossipStream) Closed() bool {
+	select {
+	case <-s.closed:
+		return true
+	default:
+		return false
+	}
+}
+
+// Close the stream. Messages that haven't been delivered are dropped.
+func (s *gossipStream) Close() {
+	s.close()
+	s.done.Wait()
+}
+
+func (s *gossipStream) close() {
+	s.closing.Do(func() {
+		close(s.closed)
+		s.cancel()
+	})
+}
+
+func (s *gossipStream) sendBatches() {
+	defer s.done.Done()
+
+	for {
+		var pending *pendingGossip
+		select {
+		case pending = <-s.queue:
+		case <-s.closed:
+			return
+		}
+
+		batch := []*pendingGossip{pending}
+	batching:
+		for len(batch) < maxGossipBatchSize {
+			select {
+			case pending := <-s.queue:
+				batch = append(batch, pending)
+			default:
+				break batching
+			}
+		}
+
+		// Wait for the plugin to deliver enough batches before sending this
+		// one.
+		select {
+		case s.inFlight <- batch:
+		case <-s.closed:
+			return
+		}
+
+		msgs := make([]*vmpb.AppGossipMsg, len(batch))
+		for i, pending := range batch {
+			msgs[i] = pending.msg
+		}
+		if err := s.stream.Send(&vmpb.AppGossipBatch{Msgs: msgs}); err != nil {
+			s.close()
+			return
+		}
+	}
+}
+
+func (s *gossipStream) receiveAcks() {
+	defer s.done.Done()
+
+	for {
+		ack, err := s.stream.Recv()
+		if err != nil {
+			s.close()
+			return
+		}
+
+		var batch []*pendingGossip
+		select {
+		case batch = <-s.inFlight:
+		default:
+			// The plugin acknowledged a batch that wasn't sent.
+			s.close()
+			return
+		}
+		for i, pending := range batch {
+			switch {
+			case i >= len(ack.Errs):
+				pending.result <- errMissingGossipAck
+			case ack.Errs[i] != "":
+				pending.result <- errors.New(ack.Errs[i])
+			default:
+				pending.result <- nil
+			}
+		}
+	}
+}
@@ -0,0 +1,155 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpcchainvm
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
+	"github.com/ava-labs/avalanchego/vms/rpcchainvm/grpcutils"
+
+	vmpb "github.com/ava-labs/avalanchego/proto/pb/vm"
+)
+
+const bufSize = 1024 * 1024
+
+// serveVM serves [server] in memory and returns a client connected to it.
+func serveVM(t *testing.T, server vmpb.VMServer) vmpb.VMClient {
+	t.Helper()
+
+	return vmpb.NewVMClient(dialVM(t, server))
+}
+
+// dialVM serves [server] in memory and returns a connection to it.
+func dialVM(t *testing.T, server vmpb.VMServer) *grpc.ClientConn {
+	t.Helper()
+
+	listener := bufconn.Listen(bufSize)
+	serverCloser := grpcutils.ServerCloser{}
+	go grpcutils.Serve(listener, func(opts []grpc.ServerOption) *grpc.Server {
+		s := grpc.NewServer(opts...)
+		vmpb.RegisterVMServer(s, server)
+		serverCloser.Add(s)
+		return s
+	})
+
+	dialer := grpc.WithContextDialer(
+		func(context.Context, string) (net.Conn, error) {
+			return listener.Dial()
+		},
+	)
+	dopts := grpcutils.DefaultDialOptions
+	dopts = append(dopts, dialer)
+	conn, err := grpcutils.Dial("", dopts...)
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		serverCloser.Stop()
+		_ = conn.Close()
+		_ = listener.Close()
+	})
+	return conn
+}
+
+func TestGossipStream(t *testing.T) {
+	require := require.New(t)
+
+	const numMsgs = 10 * maxGossipBatchSize
+	errGossip := errors.New("gossip failed")
+	delivered := make(chan []byte, numMsgs)
+	vm := &block.TestVM{}
+	vm.AppGossipF = func(_ context.Context, _ ids.NodeID, msg []byte) error {
+		delivered <- msg
+		if len(msg) == 0 {
+			return errGossip
+		}
+		return nil
+	}
+
+	stream, err := newGossipStream(serveVM(t, NewServer(vm)))
+	require.NoError(err)
+	require.NotNil(stream)
+	defer stream.Close()
+
+	nodeID := ids.GenerateTestNodeID()
+	for i := 0; i < numMsgs; i++ {
+		msg := []byte{byte(i), byte(i >> 8)}
+		require.NoError(stream.AppGossip(context.Background(), nodeID, msg))
+
+		// The message is delivered before AppGossip returns.
+		require.Len(delivered, 1)
+		require.Equal(msg, <-delivered)
+	}
+
+	// Errors returned by the VM are returned for their own message only.
+	require.ErrorContains(stream.AppGossip(context.Background(), nodeID, nil), errGossip.Error())
+	require.Empty(<-delivered)
+	require.NoError(stream.AppGossip(context.Background(), nodeID, []byte{1}))
+	require.Equal([]byte{1}, <-delivered)
+}
+
+func TestGossipStreamConcurrentCalls(t *testing.T) {
+	require := require.New(t)
+
+	const numMsgs = 10 * maxGossipBatchSize
+	var (
+		lock       sync.Mutex
+		delivering bool
+		overlapped bool
+	)
+	vm := &block.TestVM{}
+	vm.AppGossipF = func(context.Context, ids.NodeID, []byte) error {
+		lock.Lock()
+		overlapped = overlapped || delivering
+		delivering = true
+		lock.Unlock()
+
+		time.Sleep(time.Microsecond)
+
+		lock.Lock()
+		delivering = false
+		lock.Unlock()
+		return nil
+	}
+
+	stream, err := newGossipStream(serveVM(t, NewServer(vm)))
+	require.NoError(err)
+	require.NotNil(stream)
+	defer stream.Close()
+
+	// The messages of concurrent calls are batched, but delivered to the VM
+	// one at a time.
+	nodeID := ids.GenerateTestNodeID()
+	errs := make(chan error, numMsgs)
+	for i := 0; i < numMsgs; i++ {
+		go func() {
+			errs <- stream.AppGossip(context.Background(), nodeID, []byte{1})
+		}()
+	}
+	for i := 0; i < numMsgs; i++ {
+		require.NoError(<-errs)
+	}
+	lock.Lock()
+	defer lock.Unlock()
+	require.False(overlapped)
+}
+
+func TestGossipStreamUnsupported(t *testing.T) {
+	require := require.New(t)
+
+	stream, err := newGossipStream(serveVM(t, &vmpb.UnimplementedVMServer{}))
+	require.NoError(err)
+	require.Nil(stream)
+}
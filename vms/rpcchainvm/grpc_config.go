@@ -0,0 +1,64 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpcchainvm
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/ava-labs/avalanchego/vms/rpcchainvm/grpcutils"
+
+	vmpb "github.com/ava-labs/avalanchego/proto/pb/vm"
+)
+
+// SetGRPCConfig tunes the connections between the node and the plugin with
+// [configBytes], the JSON encoding of a grpcutils.Config. The connection the
+// node uses to reach the plugin's vm server is created with the plugin, so it
+// keeps the defaults.
+func (vm *VMClient) SetGRPCConfig(configBytes []byte) error {
+	config := &grpcutils.Config{}
+	if err := json.Unmarshal(configBytes, config); err != nil {
+		return err
+	}
+	if err := config.Verify(); err != nil {
+		return err
+	}
+	vm.grpcConfig = config
+	return nil
+}
+
+func grpcConfigToProto(config *grpcutils.Config) *vmpb.GRPCConfig {
+	if config == nil {
+		return nil
+	}
+	return &vmpb.GRPCConfig{
+		MaxSendMsgSize:         int64(config.MaxSendMsgSize),
+		MaxRecvMsgSize:         int64(config.MaxRecvMsgSize),
+		WriteBufferSize:        int64(config.WriteBufferSize),
+		ReadBufferSize:         int64(config.ReadBufferSize),
+		ClientKeepAliveTime:    int64(config.ClientKeepAliveTime),
+		ClientKeepAliveTimeout: int64(config.ClientKeepAliveTimeout),
+		ServerKeepAliveMinTime: int64(config.ServerKeepAliveMinTime),
+		ServerKeepAliveTime:    int64(config.ServerKeepAliveTime),
+		ServerKeepAliveTimeout: int64(config.ServerKeepAliveTimeout),
+	}
+}
+
+func grpcConfigFromProto(config *vmpb.GRPCConfig) (*grpcutils.Config, error) {
+	if config == nil {
+		return nil, nil
+	}
+	c := &grpcutils.Config{
+		MaxSendMsgSize:         int(config.MaxSendMsgSize),
+		MaxRecvMsgSize:         int(config.MaxRecvMsgSize),
+		WriteBufferSize:        int(config.WriteBufferSize),
+		ReadBufferSize:         int(config.ReadBufferSize),
+		ClientKeepAliveTime:    time.Duration(config.ClientKeepAliveTime),
+		ClientKeepAliveTimeout: time.Duration(config.ClientKeepAliveTimeout),
+		ServerKeepAliveMinTime: time.Duration(config.ServerKeepAliveMinTime),
+		ServerKeepAliveTime:    time.Duration(config.ServerKeepAliveTime),
+		ServerKeepAliveTimeout: time.Duration(config.ServerKeepAliveTimeout),
+	}
+	return c, c.Verify()
+}
@@ -0,0 +1,36 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpcchainvm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/vms/rpcchainvm/grpcutils"
+)
+
+func TestSetGRPCConfig(t *testing.T) {
+	require := require.New(t)
+
+	vm := NewClient(nil)
+	require.NoError(vm.SetGRPCConfig([]byte(`{"maxRecvMsgSize":1048576,"clientKeepAliveTime":60000000000}`)))
+	require.Equal(&grpcutils.Config{
+		MaxRecvMsgSize:      1048576,
+		ClientKeepAliveTime: time.Minute,
+	}, vm.grpcConfig)
+
+	// The plugin is given the same config.
+	config, err := grpcConfigFromProto(grpcConfigToProto(vm.grpcConfig))
+	require.NoError(err)
+	require.Equal(vm.grpcConfig, config)
+
+	config, err = grpcConfigFromProto(grpcConfigToProto(nil))
+	require.NoError(err)
+	require.Nil(config)
+
+	require.Error(vm.SetGRPCConfig([]byte(`{"maxRecvMsgSize":-1}`)))
+	require.Error(vm.SetGRPCConfig([]byte(`not json`)))
+}
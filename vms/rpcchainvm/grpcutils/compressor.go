@@ -0,0 +1,238 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package grpcutils
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/encoding"
+
+	"github.com/ava-labs/avalanchego/utils/units"
+)
+
+const (
+	// GzipCompressorName is the name of the gRPC compressor that gzips the
+	// messages of at least [compressionThreshold] bytes, and sends smaller
+	// messages as they are. It is registered by this package, so both the node
+	// and the plugins can decompress it.
+	GzipCompressorName = "avalanchego-gzip"
+	// ZstdCompressorName is the name of the gRPC compressor that compresses the
+	// messages of at least [compressionThreshold] bytes with zstd, and sends
+	// smaller messages as they are. It is registered by this package as well.
+	ZstdCompressorName = "avalanchego-zstd"
+)
+
+const (
+	// Messages smaller than this aren't worth compressing
+	compressionThreshold = units.KiB
+
+	// The first byte of a message sent with either compressor tells how the
+	// rest of the message is encoded.
+	uncompressedMessage byte = 0
+	gzippedMessage      byte = 1
+	zstdMessage         byte = 2
+)
+
+var (
+	errUnknownMessageEncoding = errors.New("unknown message encoding")
+
+	// Readers are shared by both compressors, as either can decompress the
+	// messages sent by the other.
+	gzipReaders sync.Pool
+	zstdReaders sync.Pool
+
+	_ encoding.Compressor = (*thresholdCompressor)(nil)
+	_ io.WriteCloser      = (*thresholdWriter)(nil)
+)
+
+func init() {
+	encoding.RegisterCompressor(&thresholdCompressor{
+		name:   GzipCompressorName,
+		header: gzippedMessage,
+		newWriter: func(w io.Writer) (resettableWriter, error) {
+			return gzip.NewWriter(w), nil
+		},
+	})
+	encoding.RegisterCompressor(&thresholdCompressor{
+		name:   ZstdCompressorName,
+		header: zstdMessage,
+		newWriter: func(w io.Writer) (resettableWriter, error) {
+			return zstd.NewWriter(w, zstd.WithEncoderConcurrency(1))
+		},
+	})
+}
+
+// IsCompressor returns true if [name] is the name of a compressor registered
+// by this package.
+func IsCompressor(name string) bool {
+	return name == GzipCompressorName || name == ZstdCompressorName
+}
+
+// resettableWriter compresses what is written to it, and can be reused to
+// compress another message once it's closed.
+type resettableWriter interface {
+	io.WriteCloser
+	Reset(w io.Writer)
+}
+
+type thresholdCompressor struct {
+	name string
+	// First byte of the messages this compressor compresses
+	header    byte
+	newWriter func(io.Writer) (resettableWriter, error)
+	writers   sync.Pool
+}
+
+func (c *thresholdCompressor) Name() string {
+	return c.name
+}
+
+func (c *thresholdCompressor) Compress(w io.Writer) (io.WriteCloser, error) {
+	return &thresholdWriter{
+		compressor: c,
+		w:          w,
+	}, nil
+}
+
+func (*thresholdCompressor) Decompress(r io.Reader) (io.Reader, error) {
+	header := []byte{0}
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, err
+	}
+	switch header[0] {
+	case uncompressedMessage:
+		return r, nil
+	case gzippedMessage:
+		gzipReader, ok := gzipReaders.Get().(*gzip.Reader)
+		if ok {
+			if err := gzipReader.Reset(r); err != nil {
+				gzipReaders.Put(gzipReader)
+				return nil, err
+			}
+		} else {
+			var err error
+			gzipReader, err = gzip.NewReader(r)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return &pooledReader{
+			reader: gzipReader,
+			pool:   &gzipReaders,
+		}, nil
+	case zstdMessage:
+		zstdReader, ok := zstdReaders.Get().(*zstd.Decoder)
+		if ok {
+			if err := zstdReader.Reset(r); err != nil {
+				zstdReaders.Put(zstdReader)
+				return nil, err
+			}
+		} else {
+			var err error
+			zstdReader, err = zstd.NewReader(r, zstd.WithDecoderConcurrency(1))
+			if err != nil {
+				return nil, err
+			}
+		}
+		return &pooledReader{
+			reader: zstdReader,
+			pool:   &zstdReaders,
+		}, nil
+	default:
+		return nil, fmt.Errorf("%w: %d", errUnknownMessageEncoding, header[0])
+	}
+}
+
+// thresholdWriter buffers the message until it reaches the compression
+// threshold, and then compresses it.
+type thresholdWriter struct {
+	compressor       *thresholdCompressor
+	w                io.Writer
+	buffer           []byte
+	compressedWriter resettableWriter
+}
+
+func (w *thresholdWriter) Write(p []byte) (int, error) {
+	if w.compressedWriter != nil {
+		return w.compressedWriter.Write(p)
+	}
+
+	w.buffer = append(w.buffer, p...)
+	if len(w.buffer) < compressionThreshold {
+		return len(p), nil
+	}
+
+	if _, err := w.w.Write([]byte{w.compressor.header}); err != nil {
+		return 0, err
+	}
+	compressedWriter, ok := w.compressor.writers.Get().(resettableWriter)
+	if ok {
+		compressedWriter.Reset(w.w)
+	} else {
+		var err error
+		compressedWriter, err = w.compressor.newWriter(w.w)
+		if err != nil {
+			return 0, err
+		}
+	}
+	w.compressedWriter = compressedWriter
+	if _, err := compressedWriter.Write(w.buffer); err != nil {
+		return 0, err
+	}
+	w.buffer = nil
+	return len(p), nil
+}
+
+func (w *thresholdWriter) Close() error {
+	if w.compressedWriter == nil {
+		if _, err := w.w.Write([]byte{uncompressedMessage}); err != nil {
+			return err
+		}
+		_, err := w.w.Write(w.buffer)
+		return err
+	}
+
+	err := w.compressedWriter.Close()
+	w.compressor.writers.Put(w.compressedWriter)
+	w.compressedWriter = nil
+	return err
+}
+
+// pooledReader returns its decompressing reader to the pool once the message
+// has been read.
+type pooledReader struct {
+	reader io.Reader
+	pool   *sync.Pool
+}
+
+func (r *pooledReader) Read(p []byte) (int, error) {
+	if r.reader == nil {
+		return 0, io.EOF
+	}
+	n, err := r.reader.Read(p)
+	if err == io.EOF {
+		r.pool.Put(r.reader)
+		r.reader = nil
+	}
+	return n, err
+}
+
+// WithCompression returns [opts] along with the option to compress requests
+// with the compressor named [compressor]. If [opts] is empty, the default dial
+// options are used.
+func WithCompression(compressor string, opts ...grpc.DialOption) []grpc.DialOption {
+	if len(opts) == 0 {
+		opts = DefaultDialOptions
+	}
+	compressedOpts := make([]grpc.DialOption, len(opts), len(opts)+1)
+	copy(compressedOpts, opts)
+	return append(compressedOpts, grpc.WithDefaultCallOptions(grpc.UseCompressor(compressor)))
+}
@@ -0,0 +1,84 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package grpcutils
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"google.golang.org/grpc/encoding"
+)
+
+// compress returns [msg] as sent by [compressor], written in two parts.
+func compress(t *testing.T, compressor encoding.Compressor, msg []byte) []byte {
+	buf := &bytes.Buffer{}
+	w, err := compressor.Compress(buf)
+	require.NoError(t, err)
+	_, err = w.Write(msg[:len(msg)/2])
+	require.NoError(t, err)
+	_, err = w.Write(msg[len(msg)/2:])
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+func decompress(t *testing.T, compressor encoding.Compressor, msg []byte) []byte {
+	r, err := compressor.Decompress(bytes.NewReader(msg))
+	require.NoError(t, err)
+	decompressed, err := io.ReadAll(r)
+	require.NoError(t, err)
+	return decompressed
+}
+
+func TestThresholdCompressor(t *testing.T) {
+	gzipCompressor := encoding.GetCompressor(GzipCompressorName)
+	require.NotNil(t, gzipCompressor)
+	zstdCompressor := encoding.GetCompressor(ZstdCompressorName)
+	require.NotNil(t, zstdCompressor)
+
+	tests := []struct {
+		compressor     encoding.Compressor
+		other          encoding.Compressor
+		expectedHeader byte
+	}{
+		{
+			compressor:     gzipCompressor,
+			other:          zstdCompressor,
+			expectedHeader: gzippedMessage,
+		},
+		{
+			compressor:     zstdCompressor,
+			other:          gzipCompressor,
+			expectedHeader: zstdMessage,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.compressor.Name(), func(t *testing.T) {
+			require := require.New(t)
+
+			// Small messages aren't compressed.
+			small := []byte("small message")
+			sent := compress(t, test.compressor, small)
+			require.Equal(append([]byte{uncompressedMessage}, small...), sent)
+			require.Equal(small, decompress(t, test.compressor, sent))
+
+			// Large messages are, and the compressor can be reused. Either
+			// compressor decompresses them.
+			large := bytes.Repeat([]byte{1, 2, 3, 4}, compressionThreshold)
+			for i := 0; i < 2; i++ {
+				sent = compress(t, test.compressor, large)
+				require.Equal(test.expectedHeader, sent[0])
+				require.Less(len(sent), len(large))
+				require.Equal(large, decompress(t, test.compressor, sent))
+				require.Equal(large, decompress(t, test.other, sent))
+			}
+
+			_, err := test.compressor.Decompress(bytes.NewReader([]byte{3}))
+			require.ErrorIs(err, errUnknownMessageEncoding)
+		})
+	}
+}
@@ -0,0 +1,147 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package grpcutils
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/keepalive"
+)
+
+var errNegativeGRPCConfig = errors.New("gRPC config values must be >= 0")
+
+// Config tunes the gRPC connections between the node and a plugin. Each value
+// left to 0 keeps its default.
+type Config struct {
+	// Max size, in bytes, of the messages sent and received
+	MaxSendMsgSize int `json:"maxSendMsgSize"`
+	MaxRecvMsgSize int `json:"maxRecvMsgSize"`
+
+	// Size, in bytes, of the write and read buffers of each connection
+	WriteBufferSize int `json:"writeBufferSize"`
+	ReadBufferSize  int `json:"readBufferSize"`
+
+	// Time after which a client pings the server if it saw no activity, and
+	// time it then waits for activity before closing the connection
+	ClientKeepAliveTime    time.Duration `json:"clientKeepAliveTime"`
+	ClientKeepAliveTimeout time.Duration `json:"clientKeepAliveTimeout"`
+
+	// Min time a client must wait between two pings
+	ServerKeepAliveMinTime time.Duration `json:"serverKeepAliveMinTime"`
+	// Time after which a server pings the client if it saw no activity, and
+	// time it then waits for activity before closing the connection
+	ServerKeepAliveTime    time.Duration `json:"serverKeepAliveTime"`
+	ServerKeepAliveTimeout time.Duration `json:"serverKeepAliveTimeout"`
+}
+
+// Verify returns an error if [c] holds a negative value.
+func (c *Config) Verify() error {
+	for _, value := range []int64{
+		int64(c.MaxSendMsgSize),
+		int64(c.MaxRecvMsgSize),
+		int64(c.WriteBufferSize),
+		int64(c.ReadBufferSize),
+		int64(c.ClientKeepAliveTime),
+		int64(c.ClientKeepAliveTimeout),
+		int64(c.ServerKeepAliveMinTime),
+		int64(c.ServerKeepAliveTime),
+		int64(c.ServerKeepAliveTimeout),
+	} {
+		if value < 0 {
+			return fmt.Errorf("%w: %d", errNegativeGRPCConfig, value)
+		}
+	}
+	return nil
+}
+
+// DialOptions returns [opts], or the default dial options if [opts] is empty,
+// overridden by [c]. [c] may be nil.
+func (c *Config) DialOptions(opts ...grpc.DialOption) []grpc.DialOption {
+	if len(opts) == 0 {
+		opts = DefaultDialOptions
+	}
+	dialOpts := make([]grpc.DialOption, len(opts))
+	copy(dialOpts, opts)
+	if c == nil {
+		return dialOpts
+	}
+
+	// Later options take precedence over the defaults.
+	var callOpts []grpc.CallOption
+	if c.MaxSendMsgSize > 0 {
+		callOpts = append(callOpts, grpc.MaxCallSendMsgSize(c.MaxSendMsgSize))
+	}
+	if c.MaxRecvMsgSize > 0 {
+		callOpts = append(callOpts, grpc.MaxCallRecvMsgSize(c.MaxRecvMsgSize))
+	}
+	if len(callOpts) > 0 {
+		dialOpts = append(dialOpts, grpc.WithDefaultCallOptions(callOpts...))
+	}
+	if c.WriteBufferSize > 0 {
+		dialOpts = append(dialOpts, grpc.WithWriteBufferSize(c.WriteBufferSize))
+	}
+	if c.ReadBufferSize > 0 {
+		dialOpts = append(dialOpts, grpc.WithReadBufferSize(c.ReadBufferSize))
+	}
+	if c.ClientKeepAliveTime > 0 || c.ClientKeepAliveTimeout > 0 {
+		dialOpts = append(dialOpts, grpc.WithKeepaliveParams(keepalive.ClientParameters{
+			Time:                valueOr(c.ClientKeepAliveTime, defaultClientKeepAliveTime),
+			Timeout:             valueOr(c.ClientKeepAliveTimeout, defaultClientKeepAliveTimeOut),
+			PermitWithoutStream: defaultPermitWithoutStream,
+		}))
+	}
+	return dialOpts
+}
+
+// ServerOptions returns [opts], or the default server options if [opts] is
+// empty, overridden by [c]. [c] may be nil.
+func (c *Config) ServerOptions(opts ...grpc.ServerOption) []grpc.ServerOption {
+	if len(opts) == 0 {
+		opts = DefaultServerOptions
+	}
+	serverOpts := make([]grpc.ServerOption, len(opts))
+	copy(serverOpts, opts)
+	if c == nil {
+		return serverOpts
+	}
+
+	// Later options take precedence over the defaults.
+	if c.MaxSendMsgSize > 0 {
+		serverOpts = append(serverOpts, grpc.MaxSendMsgSize(c.MaxSendMsgSize))
+	}
+	if c.MaxRecvMsgSize > 0 {
+		serverOpts = append(serverOpts, grpc.MaxRecvMsgSize(c.MaxRecvMsgSize))
+	}
+	if c.WriteBufferSize > 0 {
+		serverOpts = append(serverOpts, grpc.WriteBufferSize(c.WriteBufferSize))
+	}
+	if c.ReadBufferSize > 0 {
+		serverOpts = append(serverOpts, grpc.ReadBufferSize(c.ReadBufferSize))
+	}
+	if c.ServerKeepAliveMinTime > 0 {
+		serverOpts = append(serverOpts, grpc.KeepaliveEnforcementPolicy(keepalive.EnforcementPolicy{
+			MinTime:             c.ServerKeepAliveMinTime,
+			PermitWithoutStream: defaultPermitWithoutStream,
+		}))
+	}
+	if c.ServerKeepAliveTime > 0 || c.ServerKeepAliveTimeout > 0 {
+		serverOpts = append(serverOpts, grpc.KeepaliveParams(keepalive.ServerParameters{
+			Time:                  valueOr(c.ServerKeepAliveTime, defaultServerKeepAliveInterval),
+			Timeout:               valueOr(c.ServerKeepAliveTimeout, defaultServerKeepAliveTimeout),
+			MaxConnectionAge:      defaultServerMaxConnectionAge,
+			MaxConnectionAgeGrace: defaultServerMaxConnectionAgeGrace,
+		}))
+	}
+	return serverOpts
+}
+
+func valueOr(value, defaultValue time.Duration) time.Duration {
+	if value == 0 {
+		return defaultValue
+	}
+	return value
+}
@@ -0,0 +1,81 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package grpcutils
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/status"
+
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func TestConfigVerify(t *testing.T) {
+	require := require.New(t)
+
+	config := &Config{
+		MaxRecvMsgSize:      1024,
+		ClientKeepAliveTime: time.Minute,
+	}
+	require.NoError(config.Verify())
+
+	config.ServerKeepAliveTimeout = -time.Second
+	require.ErrorIs(config.Verify(), errNegativeGRPCConfig)
+}
+
+func TestConfigOptions(t *testing.T) {
+	require := require.New(t)
+
+	// A nil config keeps the defaults.
+	var config *Config
+	require.Len(config.DialOptions(), len(DefaultDialOptions))
+	require.Len(config.ServerOptions(), len(DefaultServerOptions))
+
+	// The options of the config are applied after the given options.
+	config = &Config{
+		MaxSendMsgSize:         1024,
+		MaxRecvMsgSize:         1024,
+		WriteBufferSize:        1024,
+		ServerKeepAliveMinTime: time.Second,
+	}
+	dialOpts := config.DialOptions(grpc.WithBlock())
+	require.Len(dialOpts, 3)
+	serverOpts := config.ServerOptions()
+	require.Len(serverOpts, len(DefaultServerOptions)+4)
+}
+
+func TestConfigMaxRecvMsgSize(t *testing.T) {
+	require := require.New(t)
+
+	listener, err := NewListener()
+	require.NoError(err)
+
+	config := &Config{
+		MaxRecvMsgSize: 8,
+	}
+	server := grpc.NewServer(config.ServerOptions()...)
+	defer server.Stop()
+	healthpb.RegisterHealthServer(server, health.NewServer())
+	go func() {
+		_ = server.Serve(listener)
+	}()
+
+	conn, err := Dial(listener.Addr().String())
+	require.NoError(err)
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	_, err = healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{
+		Service: "a service name longer than the max message size",
+	})
+	require.Equal(codes.ResourceExhausted, status.Code(err))
+}
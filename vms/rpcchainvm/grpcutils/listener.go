@@ -5,17 +5,95 @@ package grpcutils
 
 import (
 	"net"
+	"os"
+	"path/filepath"
+	"strings"
 
 	"google.golang.org/grpc"
 )
 
+// Prefix of the addresses of unix domain sockets, as understood by gRPC
+const unixScheme = "unix://"
+
+var (
+	_ net.Listener = (*unixListener)(nil)
+	_ net.Addr     = unixAddr("")
+)
+
+// NewListener returns a listener on a new TCP loopback address, which can be
+// passed to Dial as is.
 func NewListener() (net.Listener, error) {
 	return net.Listen("tcp", "127.0.0.1:")
 }
 
+// NewLocalListener returns a listener on a new local address, which can be
+// passed to Dial as is. The listener is on a unix domain socket if
+// [unixSockets] is true, and on TCP loopback otherwise.
+func NewLocalListener(unixSockets bool) (net.Listener, error) {
+	if unixSockets {
+		return NewUnixListener()
+	}
+	return NewListener()
+}
+
+// NewUnixListener returns a listener on a new unix domain socket, which can be
+// passed to Dial as is.
+func NewUnixListener() (net.Listener, error) {
+	dir, err := os.MkdirTemp("", "grpc")
+	if err != nil {
+		return nil, err
+	}
+	listener, err := net.Listen("unix", filepath.Join(dir, "grpc.sock"))
+	if err != nil {
+		_ = os.RemoveAll(dir)
+		return nil, err
+	}
+	return &unixListener{
+		Listener: listener,
+		dir:      dir,
+	}, nil
+}
+
 func Dial(addr string, opts ...grpc.DialOption) (*grpc.ClientConn, error) {
 	if len(opts) == 0 {
 		opts = append(opts, DefaultDialOptions...)
 	}
 	return createClientConn(addr, opts...)
 }
+
+// splitAddr returns the network and the address to pass to net.Dial to reach
+// [addr].
+func splitAddr(addr string) (string, string) {
+	if path := strings.TrimPrefix(addr, unixScheme); path != addr {
+		return "unix", path
+	}
+	return "tcp", addr
+}
+
+// unixListener is a listener on a unix domain socket, created in a directory
+// of its own that is removed when the listener is closed.
+type unixListener struct {
+	net.Listener
+	dir string
+}
+
+func (l *unixListener) Close() error {
+	err := l.Listener.Close()
+	_ = os.RemoveAll(l.dir)
+	return err
+}
+
+func (l *unixListener) Addr() net.Addr {
+	return unixAddr(l.Listener.Addr().String())
+}
+
+// unixAddr is the path of a unix domain socket, formatted as a gRPC address.
+type unixAddr string
+
+func (unixAddr) Network() string {
+	return "unix"
+}
+
+func (a unixAddr) String() string {
+	return unixScheme + string(a)
+}
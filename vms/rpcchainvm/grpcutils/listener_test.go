@@ -0,0 +1,60 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package grpcutils
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func TestUnixSockets(t *testing.T) {
+	require := require.New(t)
+
+	listener, err := NewLocalListener(true)
+	require.NoError(err)
+	addr := listener.Addr().String()
+	require.True(strings.HasPrefix(addr, unixScheme))
+
+	serverCloser := ServerCloser{}
+	go Serve(listener, func(opts []grpc.ServerOption) *grpc.Server {
+		server := grpc.NewServer(opts...)
+		healthpb.RegisterHealthServer(server, health.NewServer())
+		serverCloser.Add(server)
+		return server
+	})
+
+	conn, err := Dial(addr)
+	require.NoError(err)
+	resp, err := healthpb.NewHealthClient(conn).Check(context.Background(), &healthpb.HealthCheckRequest{})
+	require.NoError(err)
+	require.Equal(healthpb.HealthCheckResponse_SERVING, resp.Status)
+	require.NoError(conn.Close())
+
+	// The directory of the socket is removed once the listener is closed.
+	serverCloser.Stop()
+	_, err = os.Stat(filepath.Dir(strings.TrimPrefix(addr, unixScheme)))
+	require.True(os.IsNotExist(err))
+}
+
+func TestSplitAddr(t *testing.T) {
+	require := require.New(t)
+
+	network, addr := splitAddr("127.0.0.1:9650")
+	require.Equal("tcp", network)
+	require.Equal("127.0.0.1:9650", addr)
+
+	network, addr = splitAddr("unix:///tmp/grpc/grpc.sock")
+	require.Equal("unix", network)
+	require.Equal("/tmp/grpc/grpc.sock", addr)
+}
@@ -0,0 +1,260 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package grpcutils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/yamux"
+
+	"google.golang.org/grpc"
+)
+
+const (
+	maxMuxNameLen = 255
+	// muxDialTimeout is the maximum duration DialMux waits for the connection
+	// to the mux to be established.
+	muxDialTimeout = 10 * time.Second
+)
+
+var (
+	errMuxNameTooLong = errors.New("mux name too long")
+	errMuxClosed      = errors.New("mux closed")
+)
+
+// Mux serves several listeners over the connections accepted by a single
+// listener. Each accepted connection is a yamux session, whose streams start
+// with the name of the listener they are for.
+type Mux struct {
+	listener net.Listener
+
+	lock      sync.Mutex
+	closed    bool
+	listeners map[string]*muxListener
+	sessions  []*yamux.Session
+}
+
+// NewMux returns a mux over the connections accepted by [listener].
+func NewMux(listener net.Listener) *Mux {
+	return &Mux{
+		listener:  listener,
+		listeners: make(map[string]*muxListener),
+	}
+}
+
+// Addr returns the address of the underlying listener.
+func (m *Mux) Addr() net.Addr {
+	return m.listener.Addr()
+}
+
+// Listen returns the listener of the streams named [name].
+func (m *Mux) Listen(name string) net.Listener {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	l := &muxListener{
+		addr:   m.listener.Addr(),
+		conns:  make(chan net.Conn),
+		closed: make(chan struct{}),
+	}
+	if m.closed {
+		l.Close()
+	}
+	m.listeners[name] = l
+	return l
+}
+
+// Serve accepts connections until the mux is closed.
+func (m *Mux) Serve() error {
+	for {
+		conn, err := m.listener.Accept()
+		if err != nil {
+			return err
+		}
+		go m.serveConn(conn)
+	}
+}
+
+// Close the underlying listener, every session and every listener returned by
+// Listen.
+func (m *Mux) Close() error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if m.closed {
+		return nil
+	}
+	m.closed = true
+
+	err := m.listener.Close()
+	for _, session := range m.sessions {
+		_ = session.Close()
+	}
+	for _, l := range m.listeners {
+		_ = l.Close()
+	}
+	return err
+}
+
+func (m *Mux) serveConn(conn net.Conn) {
+	session, err := yamux.Server(conn, muxConfig())
+	if err != nil {
+		_ = conn.Close()
+		return
+	}
+
+	m.lock.Lock()
+	if m.closed {
+		m.lock.Unlock()
+		_ = session.Close()
+		return
+	}
+	m.sessions = append(m.sessions, session)
+	m.lock.Unlock()
+
+	for {
+		stream, err := session.Accept()
+		if err != nil {
+			return
+		}
+		go m.route(stream)
+	}
+}
+
+// route [stream] to the listener it is for.
+func (m *Mux) route(stream net.Conn) {
+	name, err := readMuxName(stream)
+	if err != nil {
+		_ = stream.Close()
+		return
+	}
+
+	m.lock.Lock()
+	l, ok := m.listeners[name]
+	m.lock.Unlock()
+	if !ok || !l.deliver(stream) {
+		_ = stream.Close()
+	}
+}
+
+// MuxDialer opens connections to the listeners of a Mux over a single
+// connection.
+type MuxDialer struct {
+	session *yamux.Session
+}
+
+// DialMux connects to the Mux listening on [addr]. Fails if the connection
+// isn't established within [muxDialTimeout].
+func DialMux(addr string) (*MuxDialer, error) {
+	network, address := splitAddr(addr)
+	conn, err := net.DialTimeout(network, address, muxDialTimeout)
+	if err != nil {
+		return nil, err
+	}
+	session, err := yamux.Client(conn, muxConfig())
+	if err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	return &MuxDialer{session: session}, nil
+}
+
+// Dial returns a client connection to the server of the listener named
+// [name].
+func (d *MuxDialer) Dial(name string, opts ...grpc.DialOption) (*grpc.ClientConn, error) {
+	if len(name) > maxMuxNameLen {
+		return nil, fmt.Errorf("%w: %q", errMuxNameTooLong, name)
+	}
+	if len(opts) == 0 {
+		opts = DefaultDialOptions
+	}
+	// Copy the options so that the dialer isn't added to the caller's slice.
+	opts = append(opts[:len(opts):len(opts)], grpc.WithContextDialer(
+		func(context.Context, string) (net.Conn, error) {
+			stream, err := d.session.Open()
+			if err != nil {
+				return nil, err
+			}
+			if err := writeMuxName(stream, name); err != nil {
+				_ = stream.Close()
+				return nil, err
+			}
+			return stream, nil
+		},
+	))
+	return createClientConn(name, opts...)
+}
+
+// Close the connection to the Mux, and every client connection that uses it.
+func (d *MuxDialer) Close() error {
+	return d.session.Close()
+}
+
+func muxConfig() *yamux.Config {
+	config := yamux.DefaultConfig()
+	config.LogOutput = io.Discard
+	return config
+}
+
+func writeMuxName(w io.Writer, name string) error {
+	_, err := w.Write(append([]byte{byte(len(name))}, name...))
+	return err
+}
+
+func readMuxName(r io.Reader) (string, error) {
+	var nameLen [1]byte
+	if _, err := io.ReadFull(r, nameLen[:]); err != nil {
+		return "", err
+	}
+	name := make([]byte, nameLen[0])
+	if _, err := io.ReadFull(r, name); err != nil {
+		return "", err
+	}
+	return string(name), nil
+}
+
+// muxListener is a listener of the streams of a Mux with a given name.
+type muxListener struct {
+	addr    net.Addr
+	conns   chan net.Conn
+	closed  chan struct{}
+	closing sync.Once
+}
+
+func (l *muxListener) Accept() (net.Conn, error) {
+	select {
+	case conn := <-l.conns:
+		return conn, nil
+	case <-l.closed:
+		return nil, errMuxClosed
+	}
+}
+
+func (l *muxListener) Close() error {
+	l.closing.Do(func() {
+		close(l.closed)
+	})
+	return nil
+}
+
+func (l *muxListener) Addr() net.Addr {
+	return l.addr
+}
+
+// deliver [conn] to the caller of Accept. It returns false if the listener is
+// closed.
+func (l *muxListener) deliver(conn net.Conn) bool {
+	select {
+	case l.conns <- conn:
+		return true
+	case <-l.closed:
+		return false
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package grpcutils
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// serveHealth serves a health server over [mux] as [name], that only reports
+// [name] as serving.
+func serveHealth(mux *Mux, name string, serverCloser *ServerCloser) {
+	go Serve(mux.Listen(name), func(opts []grpc.ServerOption) *grpc.Server {
+		server := grpc.NewServer(opts...)
+		grpcHealth := health.NewServer()
+		grpcHealth.SetServingStatus(name, healthpb.HealthCheckResponse_SERVING)
+		healthpb.RegisterHealthServer(server, grpcHealth)
+		serverCloser.Add(server)
+		return server
+	})
+}
+
+func TestMux(t *testing.T) {
+	require := require.New(t)
+
+	listener, err := NewListener()
+	require.NoError(err)
+	mux := NewMux(listener)
+	go func() {
+		_ = mux.Serve()
+	}()
+	defer func() {
+		require.NoError(mux.Close())
+	}()
+
+	serverCloser := ServerCloser{}
+	defer serverCloser.Stop()
+	serveHealth(mux, "a", &serverCloser)
+	serveHealth(mux, "b", &serverCloser)
+
+	dialer, err := DialMux(mux.Addr().String())
+	require.NoError(err)
+	defer dialer.Close()
+
+	for _, name := range []string{"a", "b"} {
+		conn, err := dialer.Dial(name)
+		require.NoError(err)
+
+		resp, err := healthpb.NewHealthClient(conn).Check(context.Background(), &healthpb.HealthCheckRequest{
+			Service: name,
+		})
+		require.NoError(err)
+		require.Equal(healthpb.HealthCheckResponse_SERVING, resp.Status)
+		require.NoError(conn.Close())
+	}
+
+	// Streams for unknown servers are closed.
+	conn, err := dialer.Dial("c")
+	require.NoError(err)
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_, err = healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{})
+	require.Error(err)
+}
+
+func TestMuxNameTooLong(t *testing.T) {
+	require := require.New(t)
+
+	dialer := &MuxDialer{}
+	_, err := dialer.Dial(string(make([]byte, maxMuxNameLen+1)))
+	require.ErrorIs(err, errMuxNameTooLong)
+}
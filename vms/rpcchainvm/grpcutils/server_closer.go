@@ -4,7 +4,9 @@
 package grpcutils
 
 import (
+	"context"
 	"sync"
+	"time"
 
 	"google.golang.org/grpc"
 )
@@ -13,6 +15,75 @@ type ServerCloser struct {
 	lock    sync.Mutex
 	closed  bool
 	servers []*grpc.Server
+
+	streamsLock sync.Mutex
+	// true once the streams are cancelled, so that new streams are cancelled
+	// as soon as they start
+	streamsCancelled bool
+	nextStreamID     uint64
+	// Stream ID -> cancels the context of the stream
+	streams map[uint64]context.CancelFunc
+}
+
+// StreamServerOption returns the option that lets Drain cancel the streams
+// served by a server, so that long-lived streams don't hold up the drain.
+func (s *ServerCloser) StreamServerOption() grpc.ServerOption {
+	return grpc.ChainStreamInterceptor(s.interceptStream)
+}
+
+func (s *ServerCloser) interceptStream(
+	srv interface{},
+	ss grpc.ServerStream,
+	_ *grpc.StreamServerInfo,
+	handler grpc.StreamHandler,
+) error {
+	ctx, cancel := context.WithCancel(ss.Context())
+	defer cancel()
+
+	s.streamsLock.Lock()
+	if s.streamsCancelled {
+		cancel()
+	}
+	if s.streams == nil {
+		s.streams = make(map[uint64]context.CancelFunc)
+	}
+	streamID := s.nextStreamID
+	s.nextStreamID++
+	s.streams[streamID] = cancel
+	s.streamsLock.Unlock()
+
+	defer func() {
+		s.streamsLock.Lock()
+		delete(s.streams, streamID)
+		s.streamsLock.Unlock()
+	}()
+	return handler(srv, &cancellableServerStream{
+		ServerStream: ss,
+		ctx:          ctx,
+	})
+}
+
+// cancelStreams cancels the contexts of the streams served with
+// StreamServerOption, including the streams that start afterwards.
+func (s *ServerCloser) cancelStreams() {
+	s.streamsLock.Lock()
+	defer s.streamsLock.Unlock()
+
+	s.streamsCancelled = true
+	for _, cancel := range s.streams {
+		cancel()
+	}
+}
+
+// cancellableServerStream is a server stream whose context can be cancelled
+// by the ServerCloser.
+type cancellableServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *cancellableServerStream) Context() context.Context {
+	return s.ctx
 }
 
 func (s *ServerCloser) Add(server *grpc.Server) {
@@ -47,3 +118,46 @@ func (s *ServerCloser) GracefulStop() {
 	s.closed = true
 	s.servers = nil
 }
+
+// Drain stops the servers from accepting new RPCs and waits for at most
+// [timeout] for their pending RPCs to finish. The streams served with
+// StreamServerOption are cancelled first, as they may never finish on their
+// own. The RPCs still pending after [timeout] are cancelled. Returns true if
+// the pending RPCs finished in time.
+func (s *ServerCloser) Drain(timeout time.Duration) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.cancelStreams()
+
+	var wg sync.WaitGroup
+	for _, server := range s.servers {
+		wg.Add(1)
+		go func(server *grpc.Server) {
+			defer wg.Done()
+			server.GracefulStop()
+		}(server)
+	}
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	drained := true
+	select {
+	case <-done:
+	case <-timer.C:
+		drained = false
+		for _, server := range s.servers {
+			server.Stop()
+		}
+		<-done
+	}
+	s.closed = true
+	s.servers = nil
+	return drained
+}
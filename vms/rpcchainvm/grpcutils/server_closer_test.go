@@ -0,0 +1,143 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package grpcutils
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// blockingHealthServer answers the health checks once [release] is closed.
+type blockingHealthServer struct {
+	healthpb.UnimplementedHealthServer
+
+	started chan struct{}
+	release chan struct{}
+}
+
+func (s *blockingHealthServer) Check(context.Context, *healthpb.HealthCheckRequest) (*healthpb.HealthCheckResponse, error) {
+	s.started <- struct{}{}
+	<-s.release
+	return &healthpb.HealthCheckResponse{
+		Status: healthpb.HealthCheckResponse_SERVING,
+	}, nil
+}
+
+// Watch streams the health of the server until the stream is cancelled.
+func (*blockingHealthServer) Watch(_ *healthpb.HealthCheckRequest, stream healthpb.Health_WatchServer) error {
+	if err := stream.Send(&healthpb.HealthCheckResponse{
+		Status: healthpb.HealthCheckResponse_SERVING,
+	}); err != nil {
+		return err
+	}
+	<-stream.Context().Done()
+	return stream.Context().Err()
+}
+
+// serveBlocking returns a connection to a server whose health checks block,
+// and the closer of the server.
+func serveBlocking(t *testing.T, healthServer *blockingHealthServer) (*grpc.ClientConn, *ServerCloser) {
+	t.Helper()
+
+	listener, err := NewListener()
+	require.NoError(t, err)
+
+	serverCloser := &ServerCloser{}
+	added := make(chan struct{})
+	go Serve(listener, func(opts []grpc.ServerOption) *grpc.Server {
+		opts = append(opts, serverCloser.StreamServerOption())
+		server := grpc.NewServer(opts...)
+		healthpb.RegisterHealthServer(server, healthServer)
+		serverCloser.Add(server)
+		close(added)
+		return server
+	})
+	<-added
+
+	conn, err := Dial(listener.Addr().String())
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = conn.Close()
+	})
+	return conn, serverCloser
+}
+
+func TestDrainWaitsForPendingRPCs(t *testing.T) {
+	require := require.New(t)
+
+	healthServer := &blockingHealthServer{
+		started: make(chan struct{}, 1),
+		release: make(chan struct{}),
+	}
+	conn, serverCloser := serveBlocking(t, healthServer)
+
+	errs := make(chan error, 1)
+	go func() {
+		_, err := healthpb.NewHealthClient(conn).Check(context.Background(), &healthpb.HealthCheckRequest{})
+		errs <- err
+	}()
+	<-healthServer.started
+
+	drained := make(chan bool, 1)
+	go func() {
+		drained <- serverCloser.Drain(time.Minute)
+	}()
+
+	// The server isn't stopped while the RPC is pending.
+	select {
+	case <-drained:
+		require.FailNow("drained with a pending RPC")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	close(healthServer.release)
+	require.True(<-drained)
+	require.NoError(<-errs)
+}
+
+func TestDrainCancelsPendingRPCsAfterTimeout(t *testing.T) {
+	require := require.New(t)
+
+	healthServer := &blockingHealthServer{
+		started: make(chan struct{}, 1),
+		release: make(chan struct{}),
+	}
+	defer close(healthServer.release)
+	conn, serverCloser := serveBlocking(t, healthServer)
+
+	errs := make(chan error, 1)
+	go func() {
+		_, err := healthpb.NewHealthClient(conn).Check(context.Background(), &healthpb.HealthCheckRequest{})
+		errs <- err
+	}()
+	<-healthServer.started
+
+	require.False(serverCloser.Drain(100 * time.Millisecond))
+	require.Equal(codes.Unavailable, status.Code(<-errs))
+}
+
+func TestDrainCancelsStreams(t *testing.T) {
+	require := require.New(t)
+
+	conn, serverCloser := serveBlocking(t, &blockingHealthServer{})
+
+	stream, err := healthpb.NewHealthClient(conn).Watch(context.Background(), &healthpb.HealthCheckRequest{})
+	require.NoError(err)
+	_, err = stream.Recv()
+	require.NoError(err)
+
+	// The stream would never finish on its own.
+	require.True(serverCloser.Drain(time.Minute))
+	_, err = stream.Recv()
+	require.Error(err)
+}
@@ -0,0 +1,98 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package grpcutils
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"math/big"
+	"time"
+)
+
+// Validity of the ephemeral certificates, which only live as long as the
+// processes that use them
+const ephemeralCertValidity = 100 * 365 * 24 * time.Hour
+
+var errUnexpectedPeerCert = errors.New("unexpected peer certificate")
+
+// NewEphemeralCert returns a new self-signed certificate, meant to
+// authenticate a single connection between the node and a plugin.
+func NewEphemeralCert() (tls.Certificate, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	serialNumber, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber: serialNumber,
+		Subject:      pkix.Name{CommonName: "localhost"},
+		NotBefore:    now.Add(-time.Minute),
+		NotAfter:     now.Add(ephemeralCertValidity),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage: []x509.ExtKeyUsage{
+			x509.ExtKeyUsageServerAuth,
+			x509.ExtKeyUsageClientAuth,
+		},
+	}
+	certBytes, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	return tls.Certificate{
+		Certificate: [][]byte{certBytes},
+		PrivateKey:  key,
+	}, nil
+}
+
+// ParseEphemeralCert returns the certificate encoded as [certBytes], in DER,
+// with the PKCS #8 private key [keyBytes].
+func ParseEphemeralCert(certBytes, keyBytes []byte) (tls.Certificate, error) {
+	key, err := x509.ParsePKCS8PrivateKey(keyBytes)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	return tls.Certificate{
+		Certificate: [][]byte{certBytes},
+		PrivateKey:  key,
+	}, nil
+}
+
+// MarshalEphemeralKey returns the PKCS #8 encoding of the private key of
+// [cert].
+func MarshalEphemeralKey(cert tls.Certificate) ([]byte, error) {
+	return x509.MarshalPKCS8PrivateKey(cert.PrivateKey)
+}
+
+// NewPinnedTLSConfig returns a TLS config that authenticates with [cert], and
+// only accepts peers that authenticate with the certificate [peerCert], in
+// DER. The config is used by both the client and the server of a connection,
+// which must both present a certificate.
+func NewPinnedTLSConfig(cert tls.Certificate, peerCert []byte) *tls.Config {
+	verify := func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+		if len(rawCerts) == 0 || !bytes.Equal(rawCerts[0], peerCert) {
+			return errUnexpectedPeerCert
+		}
+		return nil
+	}
+	return &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		ClientAuth:   tls.RequireAnyClientCert,
+		// The peer certificate is self-signed, so it is verified by
+		// VerifyPeerCertificate rather than against a certificate authority.
+		// #nosec G402
+		InsecureSkipVerify:    true,
+		VerifyPeerCertificate: verify,
+		MinVersion:            tls.VersionTLS13,
+	}
+}
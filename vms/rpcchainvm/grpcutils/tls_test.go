@@ -0,0 +1,74 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package grpcutils
+
+import (
+	"context"
+	"crypto/tls"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// checkHealth returns the error of a health check of the server at [addr],
+// authenticated by [cert] and expecting [serverCert].
+func checkHealth(addr string, cert tls.Certificate, serverCert []byte) error {
+	tlsConfig := NewPinnedTLSConfig(cert, serverCert)
+	conn, err := Dial(addr, grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)))
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	_, err = healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{})
+	return err
+}
+
+func TestPinnedTLSConfig(t *testing.T) {
+	require := require.New(t)
+
+	serverCert, err := NewEphemeralCert()
+	require.NoError(err)
+	clientCert, err := NewEphemeralCert()
+	require.NoError(err)
+	otherCert, err := NewEphemeralCert()
+	require.NoError(err)
+
+	// The client certificate survives being sent to the plugin.
+	clientKey, err := MarshalEphemeralKey(clientCert)
+	require.NoError(err)
+	parsedClientCert, err := ParseEphemeralCert(clientCert.Certificate[0], clientKey)
+	require.NoError(err)
+
+	listener, err := NewListener()
+	require.NoError(err)
+	serverCloser := ServerCloser{}
+	defer serverCloser.Stop()
+	go Serve(listener, func(opts []grpc.ServerOption) *grpc.Server {
+		tlsConfig := NewPinnedTLSConfig(serverCert, clientCert.Certificate[0])
+		opts = append(opts, grpc.Creds(credentials.NewTLS(tlsConfig)))
+		server := grpc.NewServer(opts...)
+		healthpb.RegisterHealthServer(server, health.NewServer())
+		serverCloser.Add(server)
+		return server
+	})
+	addr := listener.Addr().String()
+
+	require.NoError(checkHealth(addr, parsedClientCert, serverCert.Certificate[0]))
+
+	// The server rejects unknown clients.
+	require.Error(checkHealth(addr, otherCert, serverCert.Certificate[0]))
+
+	// The client rejects unknown servers.
+	require.Error(checkHealth(addr, parsedClientCert, otherCert.Certificate[0]))
+}
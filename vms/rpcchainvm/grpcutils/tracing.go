@@ -0,0 +1,326 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package grpcutils
+
+import (
+	"context"
+	"sync"
+
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+var (
+	// The trace context of a call is sent in its metadata as a W3C
+	// traceparent.
+	propagatorOption = otelgrpc.WithPropagators(propagation.TraceContext{})
+
+	_ trace.TracerProvider = tracerProvider{}
+)
+
+// TracingDialOptions returns the options that trace the calls made over a
+// connection with [tracer]. The spans of the calls are given [attrs]. Returns
+// nil if [tracer] is nil.
+func TracingDialOptions(tracer trace.Tracer, attrs ...attribute.KeyValue) []grpc.DialOption {
+	if tracer == nil {
+		return nil
+	}
+	return []grpc.DialOption{
+		grpc.WithChainUnaryInterceptor(TracingUnaryClientInterceptor(tracer, attrs...)),
+		grpc.WithChainStreamInterceptor(TracingStreamClientInterceptor(tracer, attrs...)),
+	}
+}
+
+// TracingServerOptions returns the options that trace the calls served by a
+// server with [tracer]. The spans of the calls are given [attrs]. Returns nil
+// if [tracer] is nil.
+//
+// Only calls made as part of a trace are traced, as the peer would otherwise
+// start a new trace for each of its background calls.
+func TracingServerOptions(tracer trace.Tracer, attrs ...attribute.KeyValue) []grpc.ServerOption {
+	if tracer == nil {
+		return nil
+	}
+	return []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(tracingUnaryServerInterceptor(tracer, attrs...)),
+		grpc.ChainStreamInterceptor(tracingStreamServerInterceptor(tracer, attrs...)),
+	}
+}
+
+// TracingUnaryClientInterceptor traces unary calls with [tracer], and sends
+// the trace context of the calls to the server.
+func TracingUnaryClientInterceptor(tracer trace.Tracer, attrs ...attribute.KeyValue) grpc.UnaryClientInterceptor {
+	interceptor := otelgrpc.UnaryClientInterceptor(tracingOptions(tracer)...)
+	return func(
+		ctx context.Context,
+		method string,
+		req, reply interface{},
+		cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker,
+		opts ...grpc.CallOption,
+	) error {
+		return interceptor(ctx, method, req, reply, cc, func(
+			ctx context.Context,
+			method string,
+			req, reply interface{},
+			cc *grpc.ClientConn,
+			opts ...grpc.CallOption,
+		) error {
+			trace.SpanFromContext(ctx).SetAttributes(attrs...)
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}, opts...)
+	}
+}
+
+// TracingStreamClientInterceptor traces streaming calls with [tracer], and
+// sends the trace context of the calls to the server. The span of a call ends
+// once the stream is done.
+func TracingStreamClientInterceptor(tracer trace.Tracer, attrs ...attribute.KeyValue) grpc.StreamClientInterceptor {
+	interceptor := otelgrpc.StreamClientInterceptor(tracingOptions(tracer)...)
+	return func(
+		ctx context.Context,
+		desc *grpc.StreamDesc,
+		cc *grpc.ClientConn,
+		method string,
+		streamer grpc.Streamer,
+		opts ...grpc.CallOption,
+	) (grpc.ClientStream, error) {
+		return interceptor(ctx, desc, cc, method, func(
+			ctx context.Context,
+			desc *grpc.StreamDesc,
+			cc *grpc.ClientConn,
+			method string,
+			opts ...grpc.CallOption,
+		) (grpc.ClientStream, error) {
+			trace.SpanFromContext(ctx).SetAttributes(attrs...)
+			return streamer(ctx, desc, cc, method, opts...)
+		}, opts...)
+	}
+}
+
+func tracingUnaryServerInterceptor(tracer trace.Tracer, attrs ...attribute.KeyValue) grpc.UnaryServerInterceptor {
+	interceptor := otelgrpc.UnaryServerInterceptor(tracingOptions(tracer)...)
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		if !isTraced(ctx) {
+			return handler(ctx, req)
+		}
+		return interceptor(ctx, req, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+			trace.SpanFromContext(ctx).SetAttributes(attrs...)
+			return handler(ctx, req)
+		})
+	}
+}
+
+func tracingStreamServerInterceptor(tracer trace.Tracer, attrs ...attribute.KeyValue) grpc.StreamServerInterceptor {
+	interceptor := otelgrpc.StreamServerInterceptor(tracingOptions(tracer)...)
+	return func(
+		srv interface{},
+		stream grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		if !isTraced(stream.Context()) {
+			return handler(srv, stream)
+		}
+		return interceptor(srv, stream, info, func(srv interface{}, stream grpc.ServerStream) error {
+			trace.SpanFromContext(stream.Context()).SetAttributes(attrs...)
+			return handler(srv, stream)
+		})
+	}
+}
+
+// tracingOptions returns the options that make the otelgrpc interceptors
+// record their spans with [tracer].
+func tracingOptions(tracer trace.Tracer) []otelgrpc.Option {
+	return []otelgrpc.Option{
+		otelgrpc.WithTracerProvider(tracerProvider{tracer: tracer}),
+		propagatorOption,
+	}
+}
+
+// tracerProvider provides the tracer of the node to the otelgrpc
+// interceptors, whatever instrumentation they name.
+type tracerProvider struct {
+	tracer trace.Tracer
+}
+
+func (p tracerProvider) Tracer(string, ...trace.TracerOption) trace.Tracer {
+	return p.tracer
+}
+
+// isTraced returns true if the incoming call of [ctx] was made as part of a
+// trace.
+func isTraced(ctx context.Context) bool {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return false
+	}
+	_, spanContext := otelgrpc.Extract(ctx, &md, propagatorOption)
+	return spanContext.IsValid()
+}
+
+// Propagator forwards the trace context of the calls a server serves to the
+// calls it makes, without recording spans of its own. This lets the peer
+// that traces the calls attribute the calls made back to it to the call that
+// caused them.
+//
+// Calls made without a context that carries a trace context are attributed
+// to the unary call being served, if only one is being served.
+type Propagator struct {
+	lock sync.Mutex
+	// ID -> trace context of a unary call being served
+	inFlight map[uint64]trace.SpanContext
+	nextID   uint64
+}
+
+func NewPropagator() *Propagator {
+	return &Propagator{
+		inFlight: make(map[uint64]trace.SpanContext),
+	}
+}
+
+// ServerOptions returns the options that extract the trace context of the
+// calls served by a server. Returns nil if [p] is nil.
+func (p *Propagator) ServerOptions() []grpc.ServerOption {
+	if p == nil {
+		return nil
+	}
+	return []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(p.unaryServerInterceptor),
+		grpc.ChainStreamInterceptor(p.streamServerInterceptor),
+	}
+}
+
+// DialOptions returns the options that send the trace context of the calls
+// made over a connection. Returns nil if [p] is nil.
+func (p *Propagator) DialOptions() []grpc.DialOption {
+	if p == nil {
+		return nil
+	}
+	return []grpc.DialOption{
+		grpc.WithChainUnaryInterceptor(p.unaryClientInterceptor),
+		grpc.WithChainStreamInterceptor(p.streamClientInterceptor),
+	}
+}
+
+func (p *Propagator) unaryServerInterceptor(
+	ctx context.Context,
+	req interface{},
+	_ *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (interface{}, error) {
+	ctx = extractTraceContext(ctx)
+	spanContext := trace.SpanContextFromContext(ctx)
+	if !spanContext.IsValid() {
+		return handler(ctx, req)
+	}
+
+	p.lock.Lock()
+	id := p.nextID
+	p.nextID++
+	p.inFlight[id] = spanContext
+	p.lock.Unlock()
+
+	defer func() {
+		p.lock.Lock()
+		delete(p.inFlight, id)
+		p.lock.Unlock()
+	}()
+	return handler(ctx, req)
+}
+
+func (*Propagator) streamServerInterceptor(
+	srv interface{},
+	stream grpc.ServerStream,
+	_ *grpc.StreamServerInfo,
+	handler grpc.StreamHandler,
+) error {
+	return handler(srv, &contextServerStream{
+		ServerStream: stream,
+		ctx:          extractTraceContext(stream.Context()),
+	})
+}
+
+func (p *Propagator) unaryClientInterceptor(
+	ctx context.Context,
+	method string,
+	req, reply interface{},
+	cc *grpc.ClientConn,
+	invoker grpc.UnaryInvoker,
+	opts ...grpc.CallOption,
+) error {
+	return invoker(p.injectTraceContext(ctx), method, req, reply, cc, opts...)
+}
+
+func (p *Propagator) streamClientInterceptor(
+	ctx context.Context,
+	desc *grpc.StreamDesc,
+	cc *grpc.ClientConn,
+	method string,
+	streamer grpc.Streamer,
+	opts ...grpc.CallOption,
+) (grpc.ClientStream, error) {
+	return streamer(p.injectTraceContext(ctx), desc, cc, method, opts...)
+}
+
+func (p *Propagator) injectTraceContext(ctx context.Context) context.Context {
+	if trace.SpanContextFromContext(ctx).IsValid() {
+		return injectTraceContext(ctx)
+	}
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if len(p.inFlight) != 1 {
+		return ctx
+	}
+	for _, spanContext := range p.inFlight {
+		ctx = trace.ContextWithRemoteSpanContext(ctx, spanContext)
+	}
+	return injectTraceContext(ctx)
+}
+
+type contextServerStream struct {
+	grpc.ServerStream
+
+	ctx context.Context
+}
+
+func (s *contextServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func injectTraceContext(ctx context.Context) context.Context {
+	md, ok := metadata.FromOutgoingContext(ctx)
+	if ok {
+		md = md.Copy()
+	} else {
+		md = metadata.MD{}
+	}
+	otelgrpc.Inject(ctx, &md, propagatorOption)
+	return metadata.NewOutgoingContext(ctx, md)
+}
+
+func extractTraceContext(ctx context.Context) context.Context {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ctx
+	}
+	_, spanContext := otelgrpc.Extract(ctx, &md, propagatorOption)
+	if !spanContext.IsValid() {
+		return ctx
+	}
+	return trace.ContextWithRemoteSpanContext(ctx, spanContext)
+}
@@ -0,0 +1,146 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package grpcutils
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func newTestTracer() (trace.Tracer, *tracetest.SpanRecorder) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	return provider.Tracer("test"), recorder
+}
+
+// serveTracedHealth serves [server] with [opts] and returns a connection to it.
+func serveTracedHealth(t *testing.T, server healthpb.HealthServer, opts []grpc.ServerOption, dialOpts ...grpc.DialOption) *grpc.ClientConn {
+	listener, err := NewListener()
+	require.NoError(t, err)
+
+	grpcServer := grpc.NewServer(opts...)
+	t.Cleanup(grpcServer.Stop)
+	healthpb.RegisterHealthServer(grpcServer, server)
+	go func() {
+		_ = grpcServer.Serve(listener)
+	}()
+
+	conn, err := Dial(listener.Addr().String(), dialOpts...)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = conn.Close()
+	})
+	return conn
+}
+
+// forwardingHealthServer checks the health of another server when its health
+// is checked, without passing on the context of the call.
+type forwardingHealthServer struct {
+	healthpb.UnimplementedHealthServer
+
+	client healthpb.HealthClient
+}
+
+func (s *forwardingHealthServer) Check(context.Context, *healthpb.HealthCheckRequest) (*healthpb.HealthCheckResponse, error) {
+	return s.client.Check(context.Background(), &healthpb.HealthCheckRequest{})
+}
+
+func TestTracingInterceptors(t *testing.T) {
+	require := require.New(t)
+
+	tracer, recorder := newTestTracer()
+	chainAttr := attribute.String("chainID", "test")
+	conn := serveTracedHealth(t,
+		health.NewServer(),
+		TracingServerOptions(tracer, chainAttr),
+		TracingDialOptions(tracer, chainAttr)...,
+	)
+
+	_, err := healthpb.NewHealthClient(conn).Check(context.Background(), &healthpb.HealthCheckRequest{})
+	require.NoError(err)
+
+	spans := recorder.Ended()
+	require.Len(spans, 2)
+	serverSpan, clientSpan := spans[0], spans[1]
+	require.Equal(trace.SpanKindServer, serverSpan.SpanKind())
+	require.Equal(trace.SpanKindClient, clientSpan.SpanKind())
+	require.Equal("grpc.health.v1.Health/Check", clientSpan.Name())
+	require.Contains(clientSpan.Attributes(), chainAttr)
+	require.Contains(clientSpan.Attributes(), attribute.String("rpc.method", "Check"))
+
+	// The server span is a child of the client span.
+	require.Equal(clientSpan.SpanContext().TraceID(), serverSpan.SpanContext().TraceID())
+	require.Equal(clientSpan.SpanContext().SpanID(), serverSpan.Parent().SpanID())
+}
+
+func TestTracingServerOnlyTracesTracedCalls(t *testing.T) {
+	require := require.New(t)
+
+	tracer, recorder := newTestTracer()
+	conn := serveTracedHealth(t, health.NewServer(), TracingServerOptions(tracer))
+
+	_, err := healthpb.NewHealthClient(conn).Check(context.Background(), &healthpb.HealthCheckRequest{})
+	require.NoError(err)
+	require.Empty(recorder.Ended())
+}
+
+func TestPropagator(t *testing.T) {
+	require := require.New(t)
+
+	tracer, recorder := newTestTracer()
+
+	// The node serves calls from the plugin.
+	nodeConn := serveTracedHealth(t, health.NewServer(), TracingServerOptions(tracer))
+
+	// The plugin serves calls from the node, and calls the node back without
+	// passing on the context of the call it serves.
+	// Only the node records spans.
+	propagator := NewPropagator()
+	pluginToNode, err := Dial(nodeConn.Target(), propagator.DialOptions()...)
+	require.NoError(err)
+	defer pluginToNode.Close()
+
+	nodeToPlugin := serveTracedHealth(t,
+		&forwardingHealthServer{
+			client: healthpb.NewHealthClient(pluginToNode),
+		},
+		propagator.ServerOptions(),
+		TracingDialOptions(tracer)...,
+	)
+
+	_, err = healthpb.NewHealthClient(nodeToPlugin).Check(context.Background(), &healthpb.HealthCheckRequest{})
+	require.NoError(err)
+
+	spans := recorder.Ended()
+	require.Len(spans, 2)
+	nodeServerSpan, nodeClientSpan := spans[0], spans[1]
+	require.Equal(trace.SpanKindServer, nodeServerSpan.SpanKind())
+	require.Equal(trace.SpanKindClient, nodeClientSpan.SpanKind())
+
+	// The call back to the node is attributed to the call the plugin served.
+	require.Equal(nodeClientSpan.SpanContext().TraceID(), nodeServerSpan.SpanContext().TraceID())
+	require.Equal(nodeClientSpan.SpanContext().SpanID(), nodeServerSpan.Parent().SpanID())
+}
+
+func TestNilPropagator(t *testing.T) {
+	require := require.New(t)
+
+	var propagator *Propagator
+	require.Nil(propagator.ServerOptions())
+	require.Nil(propagator.DialOptions())
+	require.Nil(TracingDialOptions(nil))
+	require.Nil(TracingServerOptions(nil))
+}
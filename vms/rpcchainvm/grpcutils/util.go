@@ -72,13 +72,18 @@ const (
 	// when there are no active RPCs, Time and Timeout will be ignored and no
 	// keepalive pings will be sent. grpc-go default false
 	defaultPermitWithoutStream = true
+
+	// Max size of the messages sent and received. gRPC reads one byte past the
+	// max size of a compressed message to detect oversized messages, so the
+	// max size must be below math.MaxInt.
+	defaultMaxMessageSize = math.MaxInt - 1
 )
 
 var (
 	DefaultDialOptions = []grpc.DialOption{
 		grpc.WithDefaultCallOptions(
-			grpc.MaxCallRecvMsgSize(math.MaxInt),
-			grpc.MaxCallSendMsgSize(math.MaxInt),
+			grpc.MaxCallRecvMsgSize(defaultMaxMessageSize),
+			grpc.MaxCallSendMsgSize(defaultMaxMessageSize),
 			grpc.WaitForReady(true),
 		),
 		grpc.WithKeepaliveParams(keepalive.ClientParameters{
@@ -89,8 +94,8 @@ var (
 	}
 
 	DefaultServerOptions = []grpc.ServerOption{
-		grpc.MaxRecvMsgSize(math.MaxInt),
-		grpc.MaxSendMsgSize(math.MaxInt),
+		grpc.MaxRecvMsgSize(defaultMaxMessageSize),
+		grpc.MaxSendMsgSize(defaultMaxMessageSize),
 		grpc.MaxConcurrentStreams(math.MaxUint32),
 		grpc.KeepaliveEnforcementPolicy(keepalive.EnforcementPolicy{
 			MinTime:             defaultServerKeepAliveMinTime,
@@ -193,7 +198,8 @@ func Serve(listener net.Listener, grpcServerFunc func([]grpc.ServerOption) *grpc
 }
 
 func createClientConn(addr string, opts ...grpc.DialOption) (*grpc.ClientConn, error) {
-	opts = append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	// Transport credentials given in [opts] take precedence.
+	opts = append([]grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}, opts...)
 	return grpc.Dial(addr, opts...)
 }
 
@@ -201,9 +207,9 @@ func createClientConn(addr string, opts ...grpc.DialOption) (*grpc.ClientConn, e
 // defaults. This should always be passed to GRPCServer field of
 // plugin.ServeConfig.
 func NewDefaultServer(opts []grpc.ServerOption) *grpc.Server {
-	if len(opts) == 0 {
-		opts = append(opts, DefaultServerOptions...)
-	}
+	// The options given by the plugin framework, such as its transport
+	// credentials, are applied on top of the defaults.
+	opts = append(DefaultServerOptions[:len(DefaultServerOptions):len(DefaultServerOptions)], opts...)
 	return grpc.NewServer(opts...)
 }
 
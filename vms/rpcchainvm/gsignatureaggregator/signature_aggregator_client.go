@@ -0,0 +1,64 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package gsignatureaggregator
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/utils/crypto/bls"
+
+	signatureaggregatorpb "github.com/ava-labs/avalanchego/proto/pb/signatureaggregator"
+)
+
+var _ snow.SignatureAggregator = (*Client)(nil)
+
+// Client is a signature aggregator that talks over RPC.
+type Client struct {
+	client signatureaggregatorpb.SignatureAggregatorClient
+}
+
+// NewClient returns a signature aggregator connected to a remote signature
+// aggregator
+func NewClient(client signatureaggregatorpb.SignatureAggregatorClient) *Client {
+	return &Client{client: client}
+}
+
+func (c *Client) AggregateSignatures(
+	ctx context.Context,
+	msg []byte,
+	vdrs []*snow.SigningValidator,
+	quorumNum uint64,
+	quorumDen uint64,
+) (*snow.AggregateSignature, error) {
+	req := &signatureaggregatorpb.AggregateSignaturesRequest{
+		Message:    msg,
+		Validators: make([]*signatureaggregatorpb.Validator, len(vdrs)),
+		QuorumNum:  quorumNum,
+		QuorumDen:  quorumDen,
+	}
+	for i, vdr := range vdrs {
+		nodeID := vdr.NodeID
+		req.Validators[i] = &signatureaggregatorpb.Validator{
+			NodeId:    nodeID[:],
+			PublicKey: bls.PublicKeyToBytes(vdr.PublicKey),
+			Weight:    vdr.Weight,
+		}
+	}
+	resp, err := c.client.AggregateSignatures(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	signature, err := bls.SignatureFromBytes(resp.Signature)
+	if err != nil {
+		return nil, err
+	}
+	return &snow.AggregateSignature{
+		Signature:    signature,
+		Signers:      resp.Signers,
+		SignedWeight: resp.SignedWeight,
+		TotalWeight:  resp.TotalWeight,
+	}, nil
+}
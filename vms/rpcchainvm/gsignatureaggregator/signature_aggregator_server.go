@@ -0,0 +1,61 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package gsignatureaggregator
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/utils/crypto/bls"
+
+	signatureaggregatorpb "github.com/ava-labs/avalanchego/proto/pb/signatureaggregator"
+)
+
+var _ signatureaggregatorpb.SignatureAggregatorServer = (*Server)(nil)
+
+// Server is a signature aggregator that is managed over RPC.
+type Server struct {
+	signatureaggregatorpb.UnsafeSignatureAggregatorServer
+	aggregator snow.SignatureAggregator
+}
+
+// NewServer returns a signature aggregator connected to a remote signature
+// aggregator
+func NewServer(aggregator snow.SignatureAggregator) *Server {
+	return &Server{aggregator: aggregator}
+}
+
+func (s *Server) AggregateSignatures(
+	ctx context.Context,
+	req *signatureaggregatorpb.AggregateSignaturesRequest,
+) (*signatureaggregatorpb.AggregateSignaturesResponse, error) {
+	vdrs := make([]*snow.SigningValidator, len(req.Validators))
+	for i, vdr := range req.Validators {
+		nodeID, err := ids.ToNodeID(vdr.NodeId)
+		if err != nil {
+			return nil, err
+		}
+		publicKey, err := bls.PublicKeyFromBytes(vdr.PublicKey)
+		if err != nil {
+			return nil, err
+		}
+		vdrs[i] = &snow.SigningValidator{
+			NodeID:    nodeID,
+			PublicKey: publicKey,
+			Weight:    vdr.Weight,
+		}
+	}
+
+	signature, err := s.aggregator.AggregateSignatures(ctx, req.Message, vdrs, req.QuorumNum, req.QuorumDen)
+	if err != nil {
+		return nil, err
+	}
+	return &signatureaggregatorpb.AggregateSignaturesResponse{
+		Signature:    bls.SignatureToBytes(signature.Signature),
+		Signers:      signature.Signers,
+		SignedWeight: signature.SignedWeight,
+		TotalWeight:  signature.TotalWeight,
+	}, nil
+}
@@ -0,0 +1,225 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpcchainvm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+
+	"github.com/ava-labs/avalanchego/vms/rpcchainvm/grpcutils"
+
+	vmpb "github.com/ava-labs/avalanchego/proto/pb/vm"
+)
+
+const (
+	// Maximum duration to wait for a component to report its health
+	healthProbeTimeout = 5 * time.Second
+
+	// Status of a component that doesn't serve a health service, such as the
+	// handlers of a plugin that predates health probing
+	healthStatusNotProbed = "NOT_PROBED"
+	// Status of a component whose health service couldn't be reached
+	healthStatusUnreachable = "UNREACHABLE"
+
+	servicesComponent = "services"
+	// Component reported if the plugin fails to report the health of its
+	// connections to the node
+	connectionsComponent = "connections"
+)
+
+var errUnhealthyComponents = errors.New("unhealthy components")
+
+// healthReport is the health of a plugin, along with the health of each
+// component the node and the plugin communicate through.
+type healthReport struct {
+	// Health reported by the plugin, or null if it failed to report it
+	VM         json.RawMessage            `json:"vm"`
+	Components map[string]componentHealth `json:"components"`
+}
+
+type componentHealth struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+func (h componentHealth) healthy() bool {
+	return h.Status == healthpb.HealthCheckResponse_SERVING.String() ||
+		h.Status == healthStatusNotProbed
+}
+
+func (h componentHealth) String() string {
+	if h.Error == "" {
+		return h.Status
+	}
+	return fmt.Sprintf("%s: %s", h.Status, h.Error)
+}
+
+// newServingHealth returns a health service that reports the server as
+// serving.
+func newServingHealth() *health.Server {
+	grpcHealth := health.NewServer()
+	// The server should use an empty string as the key for server's overall
+	// health status.
+	// See https://github.com/grpc/grpc/blob/master/doc/health-checking.md
+	grpcHealth.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
+	return grpcHealth
+}
+
+// serveWithHealth serves the server returned by [serverFunc] on [listener],
+// along with a health service the plugin probes over its connection to the
+// server.
+func serveWithHealth(listener net.Listener, serverFunc func([]grpc.ServerOption) *grpc.Server) {
+	grpcutils.Serve(listener, func(opts []grpc.ServerOption) *grpc.Server {
+		server := serverFunc(opts)
+		healthpb.RegisterHealthServer(server, newServingHealth())
+		return server
+	})
+}
+
+// healthProbe checks the health of a component over a connection to it.
+type healthProbe struct {
+	component string
+	client    healthpb.HealthClient
+}
+
+// healthProbes checks the health of the components the node and the plugin
+// communicate through.
+type healthProbes struct {
+	lock   sync.RWMutex
+	probes []healthProbe
+}
+
+// add records that the health of [component] is probed over [conn]. The
+// caller remains responsible for closing [conn].
+func (h *healthProbes) add(component string, conn grpc.ClientConnInterface) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	h.probes = append(h.probes, healthProbe{
+		component: component,
+		client:    healthpb.NewHealthClient(conn),
+	})
+}
+
+// probe returns the health of each component, probed concurrently.
+func (h *healthProbes) probe(ctx context.Context) map[string]componentHealth {
+	h.lock.RLock()
+	probes := h.probes
+	h.lock.RUnlock()
+
+	var (
+		lock    sync.Mutex
+		wg      sync.WaitGroup
+		results = make(map[string]componentHealth, len(probes))
+	)
+	for _, probe := range probes {
+		wg.Add(1)
+		go func(probe healthProbe) {
+			defer wg.Done()
+
+			result := probe.check(ctx)
+
+			lock.Lock()
+			results[probe.component] = result
+			lock.Unlock()
+		}(probe)
+	}
+	wg.Wait()
+	return results
+}
+
+func (p healthProbe) check(ctx context.Context) componentHealth {
+	ctx, cancel := context.WithTimeout(ctx, healthProbeTimeout)
+	defer cancel()
+
+	resp, err := p.client.Check(ctx, &healthpb.HealthCheckRequest{})
+	switch {
+	case status.Code(err) == codes.Unimplemented:
+		return componentHealth{Status: healthStatusNotProbed}
+	case err != nil:
+		return componentHealth{
+			Status: healthStatusUnreachable,
+			Error:  err.Error(),
+		}
+	default:
+		return componentHealth{Status: resp.Status.String()}
+	}
+}
+
+// probeComponents returns the health of each component the node and the
+// plugin communicate through. The node probes the handlers of the plugin over
+// its connections to them, and the plugin probes the servers of the node over
+// its connections to them.
+func (vm *VMClient) probeComponents(ctx context.Context) map[string]componentHealth {
+	components := vm.healthProbes.probe(ctx)
+
+	resp, err := vm.client.ConnectionHealth(ctx, &emptypb.Empty{})
+	switch {
+	case status.Code(err) == codes.Unimplemented:
+		// The plugin predates health probing, so the servers of the node
+		// aren't probed.
+	case err != nil:
+		components[connectionsComponent] = componentHealth{
+			Status: healthStatusUnreachable,
+			Error:  err.Error(),
+		}
+	default:
+		for _, component := range resp.Components {
+			components[component.Component] = componentHealth{
+				Status: component.Status,
+				Error:  component.Error,
+			}
+		}
+	}
+	return components
+}
+
+func (vm *VMServer) ConnectionHealth(ctx context.Context, _ *emptypb.Empty) (*vmpb.ConnectionHealthResponse, error) {
+	components := vm.connHealth.probe(ctx)
+	resp := &vmpb.ConnectionHealthResponse{
+		Components: make([]*vmpb.ComponentHealth, 0, len(components)),
+	}
+	for component, health := range components {
+		resp.Components = append(resp.Components, &vmpb.ComponentHealth{
+			Component: component,
+			Status:    health.Status,
+			Error:     health.Error,
+		})
+	}
+	sort.Slice(resp.Components, func(i, j int) bool {
+		return resp.Components[i].Component < resp.Components[j].Component
+	})
+	return resp, nil
+}
+
+// unhealthyComponentsErr returns an error describing the health of each
+// unhealthy component of [components], if any.
+func unhealthyComponentsErr(components map[string]componentHealth) error {
+	var unhealthy []string
+	for component, health := range components {
+		if !health.healthy() {
+			unhealthy = append(unhealthy, fmt.Sprintf("%s (%s)", component, health))
+		}
+	}
+	if len(unhealthy) == 0 {
+		return nil
+	}
+	sort.Strings(unhealthy)
+	return fmt.Errorf("%w: %s", errUnhealthyComponents, strings.Join(unhealthy, ", "))
+}
@@ -0,0 +1,198 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpcchainvm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+
+	"github.com/ava-labs/avalanchego/vms/rpcchainvm/grpcutils"
+
+	vmpb "github.com/ava-labs/avalanchego/proto/pb/vm"
+)
+
+// healthVMClient reports [details] as the health of the plugin, and
+// [components] as the health of its connections to the node. If [err] is set,
+// the plugin fails to report its health.
+type healthVMClient struct {
+	vmpb.VMClient
+
+	details    []byte
+	err        error
+	components []*vmpb.ComponentHealth
+}
+
+func (c *healthVMClient) Health(context.Context, *emptypb.Empty, ...grpc.CallOption) (*vmpb.HealthResponse, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	return &vmpb.HealthResponse{
+		Details: c.details,
+	}, nil
+}
+
+func (c *healthVMClient) ConnectionHealth(context.Context, *emptypb.Empty, ...grpc.CallOption) (*vmpb.ConnectionHealthResponse, error) {
+	return &vmpb.ConnectionHealthResponse{
+		Components: c.components,
+	}, nil
+}
+
+// dialComponent returns a connection to a server that reports [status] as its
+// health. If [status] is nil, the server doesn't serve a health service.
+func dialComponent(t *testing.T, status *healthpb.HealthCheckResponse_ServingStatus) *grpc.ClientConn {
+	t.Helper()
+
+	listener := bufconn.Listen(bufSize)
+	serverCloser := grpcutils.ServerCloser{}
+	go grpcutils.Serve(listener, func(opts []grpc.ServerOption) *grpc.Server {
+		s := grpc.NewServer(opts...)
+		if status != nil {
+			grpcHealth := health.NewServer()
+			grpcHealth.SetServingStatus("", *status)
+			healthpb.RegisterHealthServer(s, grpcHealth)
+		}
+		serverCloser.Add(s)
+		return s
+	})
+
+	dialer := grpc.WithContextDialer(
+		func(context.Context, string) (net.Conn, error) {
+			return listener.Dial()
+		},
+	)
+	conn, err := grpcutils.Dial("", dialer)
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		serverCloser.Stop()
+		_ = conn.Close()
+		_ = listener.Close()
+	})
+	return conn
+}
+
+func TestHealthCheckReport(t *testing.T) {
+	require := require.New(t)
+
+	serving := healthpb.HealthCheckResponse_SERVING
+	notServing := healthpb.HealthCheckResponse_NOT_SERVING
+
+	vm := NewClient(&healthVMClient{
+		details: []byte(`{"database":{"v1.4.5":null},"health":null}`),
+		components: []*vmpb.ComponentHealth{
+			{Component: "database/v1.4.5", Status: serving.String()},
+			{Component: servicesComponent, Status: serving.String()},
+		},
+	})
+	vm.healthProbes.add("handler/rpc", dialComponent(t, &serving))
+	// Handlers of plugins that predate health probing aren't probed.
+	vm.healthProbes.add("handler/legacy", dialComponent(t, nil))
+
+	details, err := vm.HealthCheck(context.Background())
+	require.NoError(err)
+	detailsJSON, err := json.Marshal(details)
+	require.NoError(err)
+	require.JSONEq(`{
+		"vm": {"database":{"v1.4.5":null},"health":null},
+		"components": {
+			"database/v1.4.5": {"status": "SERVING"},
+			"services": {"status": "SERVING"},
+			"handler/rpc": {"status": "SERVING"},
+			"handler/legacy": {"status": "NOT_PROBED"}
+		}
+	}`, string(detailsJSON))
+
+	vm.healthProbes.add("staticHandler/static", dialComponent(t, &notServing))
+	details, err = vm.HealthCheck(context.Background())
+	require.ErrorIs(err, errUnhealthyComponents)
+	require.Contains(err.Error(), "staticHandler/static (NOT_SERVING)")
+	detailsJSON, err = json.Marshal(details)
+	require.NoError(err)
+	require.JSONEq(`{
+		"vm": {"database":{"v1.4.5":null},"health":null},
+		"components": {
+			"database/v1.4.5": {"status": "SERVING"},
+			"services": {"status": "SERVING"},
+			"handler/rpc": {"status": "SERVING"},
+			"handler/legacy": {"status": "NOT_PROBED"},
+			"staticHandler/static": {"status": "NOT_SERVING"}
+		}
+	}`, string(detailsJSON))
+}
+
+func TestHealthCheckReportPluginUnhealthy(t *testing.T) {
+	require := require.New(t)
+
+	errPlugin := errors.New("plugin unhealthy")
+	vm := NewClient(&healthVMClient{
+		err: errPlugin,
+		components: []*vmpb.ComponentHealth{
+			{Component: servicesComponent, Status: healthStatusUnreachable, Error: "connection refused"},
+		},
+	})
+	serving := healthpb.HealthCheckResponse_SERVING
+	vm.healthProbes.add("handler/rpc", dialComponent(t, &serving))
+
+	// The components are reported even though the plugin failed to report
+	// its health.
+	details, err := vm.HealthCheck(context.Background())
+	require.ErrorIs(err, errPlugin)
+	detailsJSON, err := json.Marshal(details)
+	require.NoError(err)
+	require.JSONEq(`{
+		"vm": null,
+		"components": {
+			"services": {"status": "UNREACHABLE", "error": "connection refused"},
+			"handler/rpc": {"status": "SERVING"}
+		}
+	}`, string(detailsJSON))
+}
+
+func TestHealthCheckUnreachableComponent(t *testing.T) {
+	require := require.New(t)
+
+	vm := NewClient(&healthVMClient{
+		details: []byte(`{}`),
+	})
+	conn := dialComponent(t, nil)
+	require.NoError(conn.Close())
+	vm.healthProbes.add("handler/rpc", conn)
+
+	_, err := vm.HealthCheck(context.Background())
+	require.ErrorIs(err, errUnhealthyComponents)
+	require.Contains(err.Error(), "handler/rpc ("+healthStatusUnreachable+": ")
+}
+
+func TestConnectionHealth(t *testing.T) {
+	require := require.New(t)
+
+	vm := &VMServer{}
+	serving := healthpb.HealthCheckResponse_SERVING
+	vm.connHealth.add("database/v1.4.5", dialComponent(t, &serving))
+	conn := dialComponent(t, &serving)
+	require.NoError(conn.Close())
+	vm.connHealth.add(servicesComponent, conn)
+
+	// The plugin probes the servers of the node over its connections to them.
+	resp, err := vm.ConnectionHealth(context.Background(), &emptypb.Empty{})
+	require.NoError(err)
+	require.Len(resp.Components, 2)
+	require.Equal("database/v1.4.5", resp.Components[0].Component)
+	require.Equal(serving.String(), resp.Components[0].Status)
+	require.Equal(servicesComponent, resp.Components[1].Component)
+	require.Equal(healthStatusUnreachable, resp.Components[1].Status)
+	require.NotEmpty(resp.Components[1].Error)
+}
@@ -0,0 +1,45 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpcchainvm
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
+
+	vmpb "github.com/ava-labs/avalanchego/proto/pb/vm"
+)
+
+func (vm *VMClient) VerifyMessage(ctx context.Context, msg []byte) error {
+	if !vm.capabilities.Has(CapabilityMessageVerifier) {
+		return block.ErrMessageVerifierNotImplemented
+	}
+
+	resp, err := vm.client.VerifyMessage(ctx, &vmpb.VerifyMessageRequest{
+		Message: msg,
+	})
+	if status.Code(err) == codes.Unimplemented {
+		// The plugin predates VerifyMessage.
+		return block.ErrMessageVerifierNotImplemented
+	}
+	if err != nil {
+		return err
+	}
+	return errCodeToError[resp.Err]
+}
+
+func (vm *VMServer) VerifyMessage(ctx context.Context, req *vmpb.VerifyMessageRequest) (*vmpb.VerifyMessageResponse, error) {
+	if vm.mvVM == nil {
+		return &vmpb.VerifyMessageResponse{
+			Err: errorToErrCode[block.ErrMessageVerifierNotImplemented],
+		}, nil
+	}
+	err := vm.mvVM.VerifyMessage(ctx, req.Message)
+	return &vmpb.VerifyMessageResponse{
+		Err: errorToErrCode[err],
+	}, errorToRPCError(err)
+}
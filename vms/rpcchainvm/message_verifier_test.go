@@ -0,0 +1,64 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpcchainvm
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
+)
+
+var errUnapprovedMessage = errors.New("unapproved message")
+
+type messageVerifierVM struct {
+	*block.TestVM
+	*block.TestMessageVerifier
+}
+
+func TestVerifyMessage(t *testing.T) {
+	require := require.New(t)
+
+	approved := []byte("approved")
+	vm := NewClient(serveVM(t, NewServer(&messageVerifierVM{
+		TestVM: &block.TestVM{},
+		TestMessageVerifier: &block.TestMessageVerifier{
+			T: t,
+			VerifyMessageF: func(_ context.Context, msg []byte) error {
+				if !bytes.Equal(msg, approved) {
+					return errUnapprovedMessage
+				}
+				return nil
+			},
+		},
+	})))
+	require.NoError(vm.handshake(context.Background()))
+	require.True(vm.capabilities.Has(CapabilityMessageVerifier))
+
+	require.NoError(vm.VerifyMessage(context.Background(), approved))
+
+	err := vm.VerifyMessage(context.Background(), []byte("unapproved"))
+	require.ErrorContains(err, errUnapprovedMessage.Error())
+}
+
+func TestVerifyMessageNotImplemented(t *testing.T) {
+	require := require.New(t)
+
+	vm := NewClient(serveVM(t, NewServer(&block.TestVM{})))
+	require.NoError(vm.handshake(context.Background()))
+	require.False(vm.capabilities.Has(CapabilityMessageVerifier))
+
+	err := vm.VerifyMessage(context.Background(), []byte("message"))
+	require.ErrorIs(err, block.ErrMessageVerifierNotImplemented)
+
+	// A plugin that predates the handshake is assumed to implement it, and
+	// reports that its VM doesn't.
+	vm.capabilities = legacyCapabilities
+	err = vm.VerifyMessage(context.Background(), []byte("message"))
+	require.ErrorIs(err, block.ErrMessageVerifierNotImplemented)
+}
@@ -23,7 +23,8 @@ func NewClient(client messengerpb.MessengerClient) *Client {
 
 func (c *Client) Notify(msg common.Message) error {
 	_, err := c.client.Notify(context.Background(), &messengerpb.NotifyRequest{
-		Message: uint32(msg),
+		Message:  uint32(msg),
+		Priority: defaultPriority(msg),
 	})
 	return err
 }
@@ -6,12 +6,20 @@ package messenger
 import (
 	"context"
 	"errors"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
 
 	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
 
 	messengerpb "github.com/ava-labs/avalanchego/proto/pb/messenger"
 )
 
+// maxWaitingHighPriority is the number of high priority notifications that
+// can wait for room in the queue before new ones are dropped.
+const maxWaitingHighPriority = 16
+
 var (
 	errFullQueue = errors.New("full message queue")
 
@@ -19,22 +27,169 @@ var (
 )
 
 // Server is a messenger that is managed over RPC.
+//
+// Notify never blocks on a full queue. Notifications that don't fit in the
+// queue wait for room, and the waiting high priority notifications are
+// delivered, in order, ahead of the waiting low priority ones. A low priority
+// notification received while an identical one is waiting is coalesced into
+// it. High priority notifications received while [maxWaitingHighPriority] are
+// waiting are dropped.
 type Server struct {
 	messengerpb.UnsafeMessengerServer
 	messenger chan<- common.Message
+
+	lock sync.Mutex
+	// high and low are the notifications waiting for room in the queue
+	high, low []common.Message
+	// delivering is true while the waiting notifications are being delivered
+	delivering bool
+	// wakeup is signaled when a high priority notification starts waiting
+	wakeup chan struct{}
+	closed chan struct{}
+
+	dropped   prometheus.Counter
+	coalesced prometheus.Counter
 }
 
 // NewServer returns a messenger connected to a remote channel
-func NewServer(messenger chan<- common.Message) *Server {
-	return &Server{messenger: messenger}
+func NewServer(messenger chan<- common.Message, registerer prometheus.Registerer) (*Server, error) {
+	s := &Server{
+		messenger: messenger,
+		wakeup:    make(chan struct{}, 1),
+		closed:    make(chan struct{}),
+		dropped: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "messenger_dropped",
+			Help: "Number of high priority notifications from the VM dropped because too many were waiting for room in the engine's queue",
+		}),
+		coalesced: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "messenger_coalesced",
+			Help: "Number of low priority notifications from the VM coalesced into a notification waiting to be delivered",
+		}),
+	}
+
+	errs := wrappers.Errs{}
+	errs.Add(
+		registerer.Register(s.dropped),
+		registerer.Register(s.coalesced),
+	)
+	return s, errs.Err
 }
 
 func (s *Server) Notify(_ context.Context, req *messengerpb.NotifyRequest) (*messengerpb.NotifyResponse, error) {
 	msg := common.Message(req.Message)
-	select {
-	case s.messenger <- msg:
+	priority := req.Priority
+	if priority == messengerpb.Priority_PRIORITY_UNSPECIFIED {
+		// Plugins built before priorities were introduced don't set them.
+		priority = defaultPriority(msg)
+	}
+	highPriority := priority == messengerpb.Priority_PRIORITY_HIGH
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if !highPriority && contains(s.low, msg) {
+		s.coalesced.Inc()
 		return &messengerpb.NotifyResponse{}, nil
-	default:
-		return nil, errFullQueue
 	}
+
+	// Waiting notifications must not be overtaken by notifications of the
+	// same or lower priority.
+	if len(s.high) == 0 && (highPriority || len(s.low) == 0) {
+		select {
+		case s.messenger <- msg:
+			return &messengerpb.NotifyResponse{}, nil
+		default:
+		}
+	}
+
+	if highPriority {
+		if len(s.high) >= maxWaitingHighPriority {
+			s.dropped.Inc()
+			return nil, errFullQueue
+		}
+		s.high = append(s.high, msg)
+		select {
+		case s.wakeup <- struct{}{}:
+		default:
+		}
+	} else {
+		s.low = append(s.low, msg)
+	}
+
+	if !s.delivering {
+		s.delivering = true
+		go s.deliver()
+	}
+	return &messengerpb.NotifyResponse{}, nil
+}
+
+// Close stops the delivery of the notifications waiting for room in the queue.
+func (s *Server) Close() {
+	close(s.closed)
+}
+
+// deliver sends the waiting notifications to the queue until none are left.
+func (s *Server) deliver() {
+	for {
+		s.lock.Lock()
+		var msg common.Message
+		switch {
+		case len(s.high) > 0:
+			msg = s.high[0]
+		case len(s.low) > 0:
+			msg = s.low[0]
+		default:
+			s.delivering = false
+			s.lock.Unlock()
+			return
+		}
+		highPriority := len(s.high) > 0
+		s.lock.Unlock()
+
+		// Prefer picking again over sending, as both may be possible.
+		select {
+		case <-s.wakeup:
+			continue
+		default:
+		}
+
+		select {
+		case s.messenger <- msg:
+		case <-s.wakeup:
+			// A high priority notification may now be waiting, so the next
+			// notification to deliver is picked again.
+			continue
+		case <-s.closed:
+			return
+		}
+
+		// Only this goroutine removes waiting notifications, so [msg] is still
+		// the first one of its queue.
+		s.lock.Lock()
+		if highPriority {
+			s.high = s.high[1:]
+		} else {
+			s.low = s.low[1:]
+		}
+		s.lock.Unlock()
+	}
+}
+
+// defaultPriority returns the priority of [msg] when the plugin doesn't set
+// one. Pending transactions are reported repeatedly, so they can wait behind
+// other notifications.
+func defaultPriority(msg common.Message) messengerpb.Priority {
+	if msg == common.PendingTxs {
+		return messengerpb.Priority_PRIORITY_LOW
+	}
+	return messengerpb.Priority_PRIORITY_HIGH
+}
+
+func contains(msgs []common.Message, msg common.Message) bool {
+	for _, m := range msgs {
+		if m == msg {
+			return true
+		}
+	}
+	return false
 }
@@ -0,0 +1,125 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package messenger
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	dto "github.com/prometheus/client_model/go"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+
+	messengerpb "github.com/ava-labs/avalanchego/proto/pb/messenger"
+)
+
+func counterValue(t *testing.T, counter prometheus.Counter) float64 {
+	metric := &dto.Metric{}
+	require.NoError(t, counter.Write(metric))
+	return metric.Counter.GetValue()
+}
+
+func notifyFunc(require *require.Assertions, s *Server) func(common.Message, messengerpb.Priority) error {
+	return func(msg common.Message, priority messengerpb.Priority) error {
+		_, err := s.Notify(context.Background(), &messengerpb.NotifyRequest{
+			Message:  uint32(msg),
+			Priority: priority,
+		})
+		return err
+	}
+}
+
+func requireDelivered(require *require.Assertions, s *Server) {
+	require.Eventually(func() bool {
+		s.lock.Lock()
+		defer s.lock.Unlock()
+		return !s.delivering
+	}, time.Second, time.Millisecond)
+}
+
+func TestServerCoalescesPendingTxs(t *testing.T) {
+	require := require.New(t)
+
+	toEngine := make(chan common.Message, 1)
+	s, err := NewServer(toEngine, prometheus.NewRegistry())
+	require.NoError(err)
+	defer s.Close()
+
+	notify := notifyFunc(require, s)
+
+	// The first notification fills the queue, and the second one waits for
+	// room in the queue.
+	require.NoError(notify(common.PendingTxs, messengerpb.Priority_PRIORITY_UNSPECIFIED))
+	require.NoError(notify(common.PendingTxs, messengerpb.Priority_PRIORITY_UNSPECIFIED))
+	require.NoError(notify(common.PendingTxs, messengerpb.Priority_PRIORITY_LOW))
+	require.NoError(notify(common.PendingTxs, messengerpb.Priority_PRIORITY_UNSPECIFIED))
+	require.Equal(2.0, counterValue(t, s.coalesced))
+
+	// The waiting notification is delivered once there is room in the queue.
+	require.Equal(common.PendingTxs, <-toEngine)
+	require.Equal(common.PendingTxs, <-toEngine)
+	requireDelivered(require, s)
+	require.NoError(notify(common.StateSyncDone, messengerpb.Priority_PRIORITY_UNSPECIFIED))
+	require.Equal(common.StateSyncDone, <-toEngine)
+}
+
+func TestServerDeliversHighPriorityFirst(t *testing.T) {
+	require := require.New(t)
+
+	toEngine := make(chan common.Message, 1)
+	s, err := NewServer(toEngine, prometheus.NewRegistry())
+	require.NoError(err)
+	defer s.Close()
+
+	notify := notifyFunc(require, s)
+
+	// The first notification fills the queue, and the others wait for room
+	// in the queue.
+	require.NoError(notify(common.PendingTxs, messengerpb.Priority_PRIORITY_UNSPECIFIED))
+	require.NoError(notify(common.PendingTxs, messengerpb.Priority_PRIORITY_UNSPECIFIED))
+	require.NoError(notify(common.StateSyncDone, messengerpb.Priority_PRIORITY_UNSPECIFIED))
+	require.NoError(notify(common.PendingTxs, messengerpb.Priority_PRIORITY_HIGH))
+	// Wait for the high priority notifications to be picked up.
+	require.Eventually(func() bool {
+		return len(s.wakeup) == 0
+	}, time.Second, time.Millisecond)
+
+	// The waiting high priority notifications are delivered in order, ahead
+	// of the waiting low priority one.
+	require.Equal(common.PendingTxs, <-toEngine)
+	require.Equal(common.StateSyncDone, <-toEngine)
+	require.Equal(common.PendingTxs, <-toEngine)
+	require.Equal(common.PendingTxs, <-toEngine)
+	requireDelivered(require, s)
+	require.Zero(counterValue(t, s.coalesced))
+}
+
+func TestServerDropsHighPriorityWhenTooManyWait(t *testing.T) {
+	require := require.New(t)
+
+	toEngine := make(chan common.Message, 1)
+	s, err := NewServer(toEngine, prometheus.NewRegistry())
+	require.NoError(err)
+	defer s.Close()
+
+	notify := notifyFunc(require, s)
+
+	// The first notification fills the queue.
+	require.NoError(notify(common.StateSyncDone, messengerpb.Priority_PRIORITY_UNSPECIFIED))
+	for i := 0; i < maxWaitingHighPriority; i++ {
+		require.NoError(notify(common.StateSyncDone, messengerpb.Priority_PRIORITY_UNSPECIFIED))
+	}
+	require.ErrorIs(notify(common.StateSyncDone, messengerpb.Priority_PRIORITY_UNSPECIFIED), errFullQueue)
+	require.Equal(1.0, counterValue(t, s.dropped))
+
+	for i := 0; i <= maxWaitingHighPriority; i++ {
+		require.Equal(common.StateSyncDone, <-toEngine)
+	}
+	requireDelivered(require, s)
+}
@@ -0,0 +1,99 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpcchainvm
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
+	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
+)
+
+// oracleTestBlock is a block whose Options returns [options], or [err] if set.
+type oracleTestBlock struct {
+	*snowman.TestBlock
+
+	options [2]snowman.Block
+	err     error
+}
+
+func (b *oracleTestBlock) Options(context.Context) ([2]snowman.Block, error) {
+	return b.options, b.err
+}
+
+// newTestBlock returns a processing block whose ID, height and bytes are
+// derived from [i].
+func newTestBlock(i byte, parentID ids.ID) *snowman.TestBlock {
+	return &snowman.TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.ID{i},
+			StatusV: choices.Processing,
+		},
+		ParentV: parentID,
+		HeightV: uint64(i),
+		BytesV:  []byte{i},
+	}
+}
+
+func TestBlockOptions(t *testing.T) {
+	require := require.New(t)
+
+	oracleBlk := &oracleTestBlock{
+		TestBlock: newTestBlock(1, ids.Empty),
+	}
+	oracleBlk.options = [2]snowman.Block{
+		newTestBlock(2, oracleBlk.ID()),
+		newTestBlock(3, oracleBlk.ID()),
+	}
+	notOracleBlk := &oracleTestBlock{
+		TestBlock: newTestBlock(4, ids.Empty),
+		err:       snowman.ErrNotOracle,
+	}
+	plainBlk := newTestBlock(5, ids.Empty)
+
+	vm := &block.TestVM{}
+	vm.GetBlockF = func(_ context.Context, blkID ids.ID) (snowman.Block, error) {
+		for _, blk := range []snowman.Block{oracleBlk, notOracleBlk, plainBlk} {
+			if blk.ID() == blkID {
+				return blk, nil
+			}
+		}
+		return nil, database.ErrNotFound
+	}
+	client := NewClient(serveVM(t, NewServer(vm)))
+
+	blk, err := client.getBlock(context.Background(), oracleBlk.ID())
+	require.NoError(err)
+	options, err := blk.(snowman.OracleBlock).Options(context.Background())
+	require.NoError(err)
+	for i, option := range options {
+		expected := oracleBlk.options[i]
+		require.Equal(expected.ID(), option.ID())
+		require.Equal(oracleBlk.ID(), option.Parent())
+		require.Equal(expected.Height(), option.Height())
+		require.Equal(expected.Bytes(), option.Bytes())
+		require.Equal(choices.Processing, option.Status())
+	}
+
+	// A block that only turns out not to be an oracle block once its options
+	// are requested.
+	blk, err = client.getBlock(context.Background(), notOracleBlk.ID())
+	require.NoError(err)
+	_, err = blk.(snowman.OracleBlock).Options(context.Background())
+	require.Equal(snowman.ErrNotOracle, err)
+
+	// The options of a block that the plugin doesn't report as an oracle block
+	// aren't requested.
+	blk, err = client.getBlock(context.Background(), plainBlk.ID())
+	require.NoError(err)
+	require.False(blk.(*blockClient).oracle)
+	_, err = blk.(snowman.OracleBlock).Options(context.Background())
+	require.Equal(snowman.ErrNotOracle, err)
+}
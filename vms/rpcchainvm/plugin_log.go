@@ -0,0 +1,267 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpcchainvm
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/go-hclog"
+
+	"go.uber.org/zap"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+// Lines of plugin output longer than this are emitted in pieces
+const maxPluginLogLineSize = 64 * 1024
+
+var (
+	// Keys of structured log lines that hold the level of the entry, in the
+	// formats of zap, hclog and logrus
+	pluginLevelKeys = []string{"level", "@level"}
+	// Keys of structured log lines that hold the message of the entry
+	pluginMessageKeys = []string{"msg", "@message", "message"}
+	// Keys of structured log lines that aren't reported as fields, because
+	// the node's logger records the time of the entry itself
+	pluginDroppedKeys = map[string]bool{
+		"ts":         true,
+		"time":       true,
+		"timestamp":  true,
+		"@timestamp": true,
+	}
+
+	_ hclog.SinkAdapter = (*pluginLog)(nil)
+	_ io.Writer         = (*pluginLogWriter)(nil)
+)
+
+// pluginLog re-emits the output of a plugin process through the logger of the
+// chain the plugin runs, so that the output keeps its level and fields and is
+// attributed to the chain.
+type pluginLog struct {
+	log        logging.Logger
+	pluginName string
+	// Fields added to every entry
+	fields []zap.Field
+}
+
+func newPluginLog(log logging.Logger, chainID ids.ID, pluginName string) *pluginLog {
+	return &pluginLog{
+		log:        log,
+		pluginName: pluginName,
+		fields: []zap.Field{
+			zap.Stringer("chainID", chainID),
+			zap.String("plugin", pluginName),
+		},
+	}
+}
+
+// newHCLogger returns the logger go-plugin reports the events of the plugin
+// process with.
+func (l *pluginLog) newHCLogger() hclog.Logger {
+	logger := hclog.NewInterceptLogger(&hclog.LoggerOptions{
+		Output: io.Discard,
+		Level:  hclog.Trace,
+	})
+	logger.RegisterSink(l)
+	return logger
+}
+
+// Accept implements hclog.SinkAdapter.
+func (l *pluginLog) Accept(name string, level hclog.Level, msg string, args ...interface{}) {
+	// go-plugin reports each line the plugin writes to its stderr under the
+	// name of the plugin. Those lines are emitted by the stderr writer, which
+	// also sees the lines go-plugin can't parse.
+	if name == l.pluginName {
+		return
+	}
+
+	fields := make([]zap.Field, 0, len(l.fields)+len(args)/2+1)
+	fields = append(fields, l.fields...)
+	if name != "" {
+		fields = append(fields, zap.String("logger", name))
+	}
+	for i := 0; i < len(args); i += 2 {
+		key := fmt.Sprint(args[i])
+		if i+1 == len(args) {
+			fields = append(fields, zap.Any("extraValue", args[i]))
+			break
+		}
+		if pluginDroppedKeys[key] {
+			continue
+		}
+		fields = append(fields, zap.Any(key, args[i+1]))
+	}
+	l.emit(hclogLevel(level), msg, fields)
+}
+
+// writer returns a writer that emits each line written to it. Lines that
+// don't specify their level are emitted at [defaultLevel].
+func (l *pluginLog) writer(defaultLevel logging.Level) io.Writer {
+	return &pluginLogWriter{
+		log:          l,
+		defaultLevel: defaultLevel,
+	}
+}
+
+// emitLine emits a line of the plugin's output. Lines that are JSON objects
+// are treated as structured log entries.
+func (l *pluginLog) emitLine(line []byte, defaultLevel logging.Level) {
+	line = bytes.TrimRight(line, "\r")
+	if len(bytes.TrimSpace(line)) == 0 {
+		return
+	}
+
+	fields := make([]zap.Field, len(l.fields), len(l.fields)+4)
+	copy(fields, l.fields)
+
+	entry := map[string]interface{}{}
+	if line[0] != '{' || json.Unmarshal(line, &entry) != nil {
+		level, msg := parsePluginLinePrefix(string(line), defaultLevel)
+		l.emit(level, msg, fields)
+		return
+	}
+
+	level := defaultLevel
+	for _, key := range pluginLevelKeys {
+		if value, ok := entry[key].(string); ok {
+			if parsed, ok := parsePluginLevel(value); ok {
+				level = parsed
+			}
+			delete(entry, key)
+		}
+	}
+	msg := ""
+	for _, key := range pluginMessageKeys {
+		if value, ok := entry[key].(string); ok && msg == "" {
+			msg = value
+			delete(entry, key)
+		}
+	}
+
+	keys := make([]string, 0, len(entry))
+	for key := range entry {
+		if !pluginDroppedKeys[key] {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fields = append(fields, zap.Any(key, entry[key]))
+	}
+	l.emit(level, msg, fields)
+}
+
+func (l *pluginLog) emit(level logging.Level, msg string, fields []zap.Field) {
+	switch level {
+	case logging.Verbo:
+		l.log.Verbo(msg, fields...)
+	case logging.Debug:
+		l.log.Debug(msg, fields...)
+	case logging.Trace:
+		l.log.Trace(msg, fields...)
+	case logging.Info:
+		l.log.Info(msg, fields...)
+	case logging.Warn:
+		l.log.Warn(msg, fields...)
+	case logging.Error:
+		l.log.Error(msg, fields...)
+	default:
+		l.log.Fatal(msg, fields...)
+	}
+}
+
+// pluginLogWriter splits the output of a plugin into lines.
+type pluginLogWriter struct {
+	log          *pluginLog
+	defaultLevel logging.Level
+
+	lock sync.Mutex
+	// Output that doesn't end with a newline yet
+	partial []byte
+}
+
+func (w *pluginLogWriter) Write(p []byte) (int, error) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	w.partial = append(w.partial, p...)
+	for {
+		i := bytes.IndexByte(w.partial, '\n')
+		if i < 0 {
+			break
+		}
+		w.log.emitLine(w.partial[:i], w.defaultLevel)
+		w.partial = w.partial[i+1:]
+	}
+	if len(w.partial) >= maxPluginLogLineSize {
+		w.log.emitLine(w.partial, w.defaultLevel)
+		w.partial = nil
+	}
+	// Don't retain the backing array of large writes.
+	w.partial = append([]byte(nil), w.partial...)
+	return len(p), nil
+}
+
+// parsePluginLinePrefix returns the level of an unstructured line, inferred
+// from the prefixes commonly used to specify it, and the rest of the line.
+func parsePluginLinePrefix(line string, defaultLevel logging.Level) (logging.Level, string) {
+	switch {
+	case strings.HasPrefix(line, "panic: "), strings.HasPrefix(line, "fatal error: "):
+		return logging.Error, line
+	case strings.HasPrefix(line, "["):
+		end := strings.IndexByte(line, ']')
+		if end < 0 {
+			return defaultLevel, line
+		}
+		level, ok := parsePluginLevel(line[1:end])
+		if !ok {
+			return defaultLevel, line
+		}
+		return level, strings.TrimSpace(line[end+1:])
+	default:
+		return defaultLevel, line
+	}
+}
+
+// parsePluginLevel parses the levels of zap, hclog, logrus and the node.
+func parsePluginLevel(level string) (logging.Level, bool) {
+	switch strings.ToLower(level) {
+	case "trace", "verbo":
+		return logging.Verbo, true
+	case "debug":
+		return logging.Debug, true
+	case "info":
+		return logging.Info, true
+	case "warn", "warning":
+		return logging.Warn, true
+	case "error", "dpanic", "panic":
+		return logging.Error, true
+	case "fatal":
+		return logging.Fatal, true
+	default:
+		return 0, false
+	}
+}
+
+func hclogLevel(level hclog.Level) logging.Level {
+	switch level {
+	case hclog.Trace:
+		return logging.Verbo
+	case hclog.Debug:
+		return logging.Debug
+	case hclog.Warn:
+		return logging.Warn
+	case hclog.Error:
+		return logging.Error
+	default:
+		return logging.Info
+	}
+}
@@ -0,0 +1,140 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpcchainvm
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+type nopCloser struct {
+	*bytes.Buffer
+}
+
+func (nopCloser) Close() error {
+	return nil
+}
+
+// newTestPluginLog returns a plugin log whose entries are written as JSON to
+// the returned buffer.
+func newTestPluginLog(chainID ids.ID) (*pluginLog, *bytes.Buffer) {
+	buf := &bytes.Buffer{}
+	log := logging.NewLogger("", logging.NewWrappedCore(logging.Verbo, nopCloser{buf}, logging.JSON.FileEncoder()))
+	return newPluginLog(log, chainID, "plugin-name"), buf
+}
+
+func readEntries(t *testing.T, buf *bytes.Buffer) []map[string]interface{} {
+	t.Helper()
+
+	entries := []map[string]interface{}{}
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		entry := map[string]interface{}{}
+		require.NoError(t, json.Unmarshal([]byte(line), &entry))
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func TestPluginLogWriter(t *testing.T) {
+	require := require.New(t)
+
+	chainID := ids.GenerateTestID()
+	l, buf := newTestPluginLog(chainID)
+	w := l.writer(logging.Info)
+
+	// A zap entry, written in two pieces
+	_, err := w.Write([]byte(`{"level":"warn","ts":1665000000.1,"msg":"slow block",`))
+	require.NoError(err)
+	_, err = w.Write([]byte(`"height":12}` + "\n"))
+	require.NoError(err)
+	// An hclog entry
+	_, err = w.Write([]byte(`{"@level":"error","@message":"failed","@timestamp":"2022-10-05T00:00:00Z","err":"oops"}` + "\n"))
+	require.NoError(err)
+	// Unstructured lines
+	_, err = w.Write([]byte("[DEBUG] starting\nhello\n\npanic: boom\n"))
+	require.NoError(err)
+
+	entries := readEntries(t, buf)
+	require.Len(entries, 5)
+	for _, entry := range entries {
+		require.Equal(chainID.String(), entry["chainID"])
+		require.Equal("plugin-name", entry["plugin"])
+		require.NotContains(entry, "ts")
+		require.NotContains(entry, "@timestamp")
+	}
+
+	require.Equal("warn", entries[0]["level"])
+	require.Equal("slow block", entries[0]["msg"])
+	require.Equal(float64(12), entries[0]["height"])
+
+	require.Equal("error", entries[1]["level"])
+	require.Equal("failed", entries[1]["msg"])
+	require.Equal("oops", entries[1]["err"])
+
+	require.Equal("debug", entries[2]["level"])
+	require.Equal("starting", entries[2]["msg"])
+
+	require.Equal("info", entries[3]["level"])
+	require.Equal("hello", entries[3]["msg"])
+
+	require.Equal("error", entries[4]["level"])
+	require.Equal("panic: boom", entries[4]["msg"])
+}
+
+func TestPluginLogHCLogger(t *testing.T) {
+	require := require.New(t)
+
+	chainID := ids.GenerateTestID()
+	l, buf := newTestPluginLog(chainID)
+	logger := l.newHCLogger()
+
+	logger.Named("stdio").Warn("stream closed", "attempt", 2)
+	// Lines of the plugin's stderr are emitted by the stderr writer instead.
+	logger.Named("plugin-name").Error("from stderr")
+	logger.Log(hclog.Debug, "plugin started", "pid", 12, "timestamp", "now")
+
+	entries := readEntries(t, buf)
+	require.Len(entries, 2)
+
+	require.Equal("warn", entries[0]["level"])
+	require.Equal("stream closed", entries[0]["msg"])
+	require.Equal("stdio", entries[0]["logger"])
+	require.Equal(float64(2), entries[0]["attempt"])
+	require.Equal(chainID.String(), entries[0]["chainID"])
+
+	require.Equal("debug", entries[1]["level"])
+	require.Equal(float64(12), entries[1]["pid"])
+	// The node's logger records the time of the entry itself.
+	require.NotEqual("now", entries[1]["timestamp"])
+}
+
+func TestParsePluginLevel(t *testing.T) {
+	tests := map[string]logging.Level{
+		"trace":   logging.Verbo,
+		"DEBUG":   logging.Debug,
+		"info":    logging.Info,
+		"warning": logging.Warn,
+		"dpanic":  logging.Error,
+		"fatal":   logging.Fatal,
+	}
+	for level, expected := range tests {
+		parsed, ok := parsePluginLevel(level)
+		require.True(t, ok, level)
+		require.Equal(t, expected, parsed, level)
+	}
+	_, ok := parsePluginLevel("loud")
+	require.False(t, ok)
+}
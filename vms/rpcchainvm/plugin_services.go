@@ -0,0 +1,108 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpcchainvm
+
+import (
+	"fmt"
+
+	"google.golang.org/grpc"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+
+	aliasreaderpb "github.com/ava-labs/avalanchego/proto/pb/aliasreader"
+	appsenderpb "github.com/ava-labs/avalanchego/proto/pb/appsender"
+	blobpb "github.com/ava-labs/avalanchego/proto/pb/blob"
+	keystorepb "github.com/ava-labs/avalanchego/proto/pb/keystore"
+	messengerpb "github.com/ava-labs/avalanchego/proto/pb/messenger"
+	sharedmemorypb "github.com/ava-labs/avalanchego/proto/pb/sharedmemory"
+	signatureaggregatorpb "github.com/ava-labs/avalanchego/proto/pb/signatureaggregator"
+	subnetlookuppb "github.com/ava-labs/avalanchego/proto/pb/subnetlookup"
+	validatorstatepb "github.com/ava-labs/avalanchego/proto/pb/validatorstate"
+)
+
+// initServiceNames are the names of the services every plugin is served by
+// the node, which plugin services can't replace.
+var initServiceNames = map[string]struct{}{
+	messengerpb.Messenger_ServiceDesc.ServiceName:                     {},
+	keystorepb.Keystore_ServiceDesc.ServiceName:                       {},
+	sharedmemorypb.SharedMemory_ServiceDesc.ServiceName:               {},
+	aliasreaderpb.AliasReader_ServiceDesc.ServiceName:                 {},
+	subnetlookuppb.SubnetLookup_ServiceDesc.ServiceName:               {},
+	appsenderpb.AppSender_ServiceDesc.ServiceName:                     {},
+	healthpb.Health_ServiceDesc.ServiceName:                           {},
+	validatorstatepb.ValidatorState_ServiceDesc.ServiceName:           {},
+	signatureaggregatorpb.SignatureAggregator_ServiceDesc.ServiceName: {},
+	blobpb.Blob_ServiceDesc.ServiceName:                               {},
+}
+
+// PluginService is a gRPC service the node serves to a plugin, alongside the
+// services every plugin is served.
+type PluginService struct {
+	Desc   *grpc.ServiceDesc
+	Server interface{}
+}
+
+// PluginServiceFactory returns the service served to the plugin of the chain
+// of [ctx].
+type PluginServiceFactory func(ctx *snow.Context) (PluginService, error)
+
+// PluginServices maps the name of a plugin service to its factory.
+type PluginServices map[string]PluginServiceFactory
+
+// ChainPluginServices maps the ID or an alias of a chain to the names of the
+// plugin services served to the chain's plugin.
+type ChainPluginServices map[string][]string
+
+// Get returns the names of the plugin services of the chain with ID [chainID]
+// and aliases [aliases]. The services registered for the chain's ID take
+// precedence over the services registered for its aliases. Returns false if
+// no services were registered for the chain.
+func (c ChainPluginServices) Get(chainID ids.ID, aliases []string) ([]string, bool) {
+	if names, ok := c[chainID.String()]; ok {
+		return names, true
+	}
+	for _, alias := range aliases {
+		if names, ok := c[alias]; ok {
+			return names, true
+		}
+	}
+	return nil, false
+}
+
+// PluginServicesVM is a VM running in a plugin that calls the plugin services
+// the node serves to it.
+type PluginServicesVM interface {
+	// SetPluginServicesConn gives the VM the connection over which the node
+	// serves its plugin services. The node serves no more than the services
+	// enabled for the chain of the VM.
+	//
+	// SetPluginServicesConn is called before the VM is initialized.
+	SetPluginServicesConn(conn grpc.ClientConnInterface)
+}
+
+// newPluginServices returns the services created by [factories] for the chain
+// of [ctx].
+func newPluginServices(ctx *snow.Context, factories map[string]PluginServiceFactory) ([]PluginService, error) {
+	services := make([]PluginService, 0, len(factories))
+	serviceNames := make(map[string]string, len(factories))
+	for name, factory := range factories {
+		service, err := factory(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't create plugin service %q: %w", name, err)
+		}
+		serviceName := service.Desc.ServiceName
+		if _, ok := initServiceNames[serviceName]; ok {
+			return nil, fmt.Errorf("plugin service %q replaces the %s service", name, serviceName)
+		}
+		if other, ok := serviceNames[serviceName]; ok {
+			return nil, fmt.Errorf("plugin services %q and %q both serve the %s service", other, name, serviceName)
+		}
+		serviceNames[serviceName] = name
+		services = append(services, service)
+	}
+	return services, nil
+}
@@ -0,0 +1,117 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpcchainvm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"google.golang.org/grpc"
+
+	"github.com/ava-labs/avalanchego/database/manager"
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/database/rpcdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
+	"github.com/ava-labs/avalanchego/version"
+
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+
+	rpcdbpb "github.com/ava-labs/avalanchego/proto/pb/rpcdb"
+)
+
+// pluginServicesTestVM is a plugin VM that keeps the connection to its plugin
+// services.
+type pluginServicesTestVM struct {
+	*block.TestVM
+
+	conn grpc.ClientConnInterface
+}
+
+func (vm *pluginServicesTestVM) SetPluginServicesConn(conn grpc.ClientConnInterface) {
+	vm.conn = conn
+}
+
+func newPluginServicesTestVM(t *testing.T) *pluginServicesTestVM {
+	genesis := &snowman.TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.GenerateTestID(),
+			StatusV: choices.Accepted,
+		},
+		TimestampV: time.Unix(1, 0),
+		BytesV:     []byte{0},
+	}
+	vm := &block.TestVM{}
+	vm.T = t
+	vm.InitializeF = func(context.Context, *snow.Context, manager.Manager, []byte, []byte, []byte, chan<- common.Message, []*common.Fx, common.AppSender) error {
+		return nil
+	}
+	vm.LastAcceptedF = func(context.Context) (ids.ID, error) {
+		return genesis.ID(), nil
+	}
+	vm.GetBlockF = func(context.Context, ids.ID) (snowman.Block, error) {
+		return genesis, nil
+	}
+	return &pluginServicesTestVM{
+		TestVM: vm,
+	}
+}
+
+func TestPluginServices(t *testing.T) {
+	require := require.New(t)
+
+	// The node serves a database to the plugin, such as a database shared by
+	// the chains of a subnet.
+	db := memdb.New()
+	require.NoError(db.Put([]byte("key"), []byte("value")))
+
+	var serviceCtx *snow.Context
+	pluginVM := newPluginServicesTestVM(t)
+	vm := NewClient(serveVM(t, NewServer(pluginVM)))
+	vm.pluginServiceFactories = map[string]PluginServiceFactory{
+		"db": func(ctx *snow.Context) (PluginService, error) {
+			serviceCtx = ctx
+			return PluginService{
+				Desc:   &rpcdbpb.Database_ServiceDesc,
+				Server: rpcdb.NewServer(db),
+			}, nil
+		},
+	}
+
+	ctx := snow.DefaultContextTest()
+	dbManager := manager.NewMemDB(version.Semantic1_0_0)
+	require.NoError(vm.Initialize(context.Background(), ctx, dbManager, nil, nil, nil, nil, nil, nil))
+	require.Same(ctx, serviceCtx)
+
+	require.NotNil(pluginVM.conn)
+	value, err := rpcdb.NewClient(rpcdbpb.NewDatabaseClient(pluginVM.conn)).Get([]byte("key"))
+	require.NoError(err)
+	require.Equal([]byte("value"), value)
+}
+
+func TestPluginServicesCantReplaceInitServices(t *testing.T) {
+	require := require.New(t)
+
+	vm := NewClient(serveVM(t, NewServer(newPluginServicesTestVM(t))))
+	vm.pluginServiceFactories = map[string]PluginServiceFactory{
+		"health": func(*snow.Context) (PluginService, error) {
+			return PluginService{
+				Desc:   &healthpb.Health_ServiceDesc,
+				Server: newServingHealth(),
+			}, nil
+		},
+	}
+
+	ctx := snow.DefaultContextTest()
+	dbManager := manager.NewMemDB(version.Semantic1_0_0)
+	err := vm.Initialize(context.Background(), ctx, dbManager, nil, nil, nil, nil, nil, nil)
+	require.ErrorContains(err, healthpb.Health_ServiceDesc.ServiceName)
+}
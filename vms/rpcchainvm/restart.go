@@ -0,0 +1,533 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpcchainvm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-plugin"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"go.uber.org/zap"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/utils"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+	"github.com/ava-labs/avalanchego/vms/rpcchainvm/grpcutils"
+
+	vmpb "github.com/ava-labs/avalanchego/proto/pb/vm"
+)
+
+const (
+	// Frequency at which the plugin process is checked for having exited
+	processCheckFrequency = time.Second
+	// Delays between two attempts to restart the plugin
+	minRestartDelay = time.Second
+	maxRestartDelay = time.Minute
+)
+
+var (
+	errRecovering           = errors.New("plugin is restarting")
+	errLastAcceptedMismatch = errors.New("restarted plugin has a different last accepted block")
+
+	// lockedMethods are the methods of the plugin's vm server that are called
+	// while the context lock is held. If one of them fails because the plugin
+	// process exited, the plugin is restarted before the call returns.
+	lockedMethods = map[string]struct{}{
+		"/vm.VM/SetState":                   {},
+		"/vm.VM/Connected":                  {},
+		"/vm.VM/Disconnected":               {},
+		"/vm.VM/BuildBlock":                 {},
+		"/vm.VM/BuildBlockWithContext":      {},
+		"/vm.VM/ParseBlock":                 {},
+		"/vm.VM/GetBlock":                   {},
+		"/vm.VM/SetPreference":              {},
+		"/vm.VM/AppRequest":                 {},
+		"/vm.VM/AppRequestFailed":           {},
+		"/vm.VM/AppResponse":                {},
+		"/vm.VM/AppGossip":                  {},
+		"/vm.VM/CrossChainAppRequest":       {},
+		"/vm.VM/CrossChainAppRequestFailed": {},
+		"/vm.VM/CrossChainAppResponse":      {},
+		"/vm.VM/GetAncestors":               {},
+		"/vm.VM/BatchedParseBlock":          {},
+		"/vm.VM/BlockVerify":                {},
+		"/vm.VM/BlockVerifyWithContext":     {},
+		"/vm.VM/BlockAccept":                {},
+		"/vm.VM/BlockReject":                {},
+		"/vm.VM/BatchedBlockAccept":         {},
+		"/vm.VM/BatchedBlockReject":         {},
+		"/vm.VM/BlockOptions":               {},
+	}
+
+	_ grpc.ClientConnInterface = (*pluginConn)(nil)
+	_ common.FailableVM        = (*VMClient)(nil)
+)
+
+// pluginConn is the connection to the plugin. It is replaced by a connection
+// to the new plugin process when the plugin is restarted.
+type pluginConn struct {
+	lock sync.RWMutex
+	conn grpc.ClientConnInterface
+
+	// Name of the compressor requests are compressed with, if non-empty
+	compressor utils.AtomicInterface
+
+	// Intercept the calls made over the connection, outermost first
+	unaryInterceptors  []grpc.UnaryClientInterceptor
+	streamInterceptors []grpc.StreamClientInterceptor
+
+	// If non-nil, called when a call fails. Returns true if the plugin was
+	// restarted and the call can be made again.
+	recover func(ctx context.Context, method string, err error) bool
+}
+
+func (c *pluginConn) get() grpc.ClientConnInterface {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	return c.conn
+}
+
+// set replaces the connection with [conn] and closes the previous
+// connection.
+func (c *pluginConn) set(conn grpc.ClientConnInterface) error {
+	c.lock.Lock()
+	previous := c.conn
+	c.conn = conn
+	c.lock.Unlock()
+
+	if closer, ok := previous.(io.Closer); ok && previous != conn {
+		return closer.Close()
+	}
+	return nil
+}
+
+// addInterceptors intercepts the calls made over the connection with [unary]
+// and [stream], after the interceptors that were previously added. Nil
+// interceptors are ignored.
+func (c *pluginConn) addInterceptors(unary grpc.UnaryClientInterceptor, stream grpc.StreamClientInterceptor) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if unary != nil {
+		c.unaryInterceptors = append(c.unaryInterceptors, unary)
+	}
+	if stream != nil {
+		c.streamInterceptors = append(c.streamInterceptors, stream)
+	}
+}
+
+func (c *pluginConn) Invoke(ctx context.Context, method string, args interface{}, reply interface{}, opts ...grpc.CallOption) error {
+	err := c.invoke(ctx, method, args, reply, opts...)
+	if err != nil && c.recover != nil && c.recover(ctx, method, err) {
+		err = c.invoke(ctx, method, args, reply, opts...)
+	}
+	return err
+}
+
+func (c *pluginConn) invoke(ctx context.Context, method string, args interface{}, reply interface{}, opts ...grpc.CallOption) error {
+	c.lock.RLock()
+	conn, interceptors := c.conn, c.unaryInterceptors
+	c.lock.RUnlock()
+
+	invoker := func(ctx context.Context, method string, args, reply interface{}, _ *grpc.ClientConn, opts ...grpc.CallOption) error {
+		return conn.Invoke(ctx, method, args, reply, opts...)
+	}
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		interceptor, next := interceptors[i], invoker
+		invoker = func(ctx context.Context, method string, args, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+			return interceptor(ctx, method, args, reply, cc, next, opts...)
+		}
+	}
+	return invoker(ctx, method, args, reply, nil, c.callOptions(opts)...)
+}
+
+func (c *pluginConn) NewStream(ctx context.Context, desc *grpc.StreamDesc, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	c.lock.RLock()
+	conn, interceptors := c.conn, c.streamInterceptors
+	c.lock.RUnlock()
+
+	streamer := func(ctx context.Context, desc *grpc.StreamDesc, _ *grpc.ClientConn, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return conn.NewStream(ctx, desc, method, opts...)
+	}
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		interceptor, next := interceptors[i], streamer
+		streamer = func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+			return interceptor(ctx, desc, cc, method, next, opts...)
+		}
+	}
+	return streamer(ctx, desc, nil, method, c.callOptions(opts)...)
+}
+
+func (c *pluginConn) callOptions(opts []grpc.CallOption) []grpc.CallOption {
+	compressor, _ := c.compressor.GetValue().(string)
+	if compressor == "" {
+		return opts
+	}
+	return append(opts, grpc.UseCompressor(compressor))
+}
+
+// newRestartableClient returns a VM connected to a plugin over [conn], which
+// is replaced if the plugin is restarted.
+func newRestartableClient(conn grpc.ClientConnInterface) *VMClient {
+	pluginConn := &pluginConn{conn: conn}
+	vm := NewClient(vmpb.NewVMClient(pluginConn))
+	vm.conn = pluginConn
+	pluginConn.recover = vm.recoverCall
+	return vm
+}
+
+type restartMetrics struct {
+	restarts   prometheus.Counter
+	upgrades   prometheus.Counter
+	recovering prometheus.Gauge
+}
+
+func (m *restartMetrics) Initialize(registerer prometheus.Registerer) error {
+	m.restarts = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "plugin_restarts",
+		Help: "Number of times the plugin was restarted after its process exited",
+	})
+	m.upgrades = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "plugin_upgrades",
+		Help: "Number of times the plugin was replaced by a new plugin binary",
+	})
+	m.recovering = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "plugin_recovering",
+		Help: "1 if the plugin is being restarted, 0 otherwise",
+	})
+
+	errs := wrappers.Errs{}
+	errs.Add(
+		registerer.Register(m.restarts),
+		registerer.Register(m.upgrades),
+		registerer.Register(m.recovering),
+	)
+	return errs.Err
+}
+
+// replaceProcess gives ownership of the process of a restarted plugin to the
+// client, and kills the previous process.
+func (vm *VMClient) replaceProcess(proc *plugin.Client) {
+	vm.proc.Kill()
+	vm.processTracker.UntrackProcess(vm.pid)
+	vm.SetProcess(vm.ctx, proc, vm.processTracker)
+}
+
+// monitor restarts the plugin whenever its process exits, until the client
+// is shut down.
+func (vm *VMClient) monitor() {
+	ticker := time.NewTicker(processCheckFrequency)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-vm.closed:
+			return
+		}
+
+		// The process is replaced under the context lock when the plugin is
+		// upgraded.
+		vm.ctx.Lock.Lock()
+		exited, pid, restarts := vm.proc.Exited(), vm.pid, vm.restarts
+		vm.ctx.Lock.Unlock()
+		if exited {
+			vm.ctx.Log.Warn("plugin process exited, restarting it",
+				zap.Int("pid", pid),
+			)
+			vm.recoverProcess(restarts)
+		}
+	}
+}
+
+// recoverProcess restarts the plugin until it succeeds, the client is shut
+// down or the restarted plugin can't resume the chain. [restarts] is the number
+// of times the plugin was restarted when it was found to need a restart; if
+// the plugin was restarted since then, it isn't restarted again. The chain is
+// reported unhealthy while the plugin is being restarted.
+func (vm *VMClient) recoverProcess(restarts uint64) {
+	vm.recovering.SetValue(true)
+	vm.restartMetrics.recovering.Set(1)
+	defer func() {
+		vm.recovering.SetValue(false)
+		vm.restartMetrics.recovering.Set(0)
+	}()
+
+	delay := minRestartDelay
+	for {
+		vm.ctx.Lock.Lock()
+		select {
+		case <-vm.closed:
+			vm.ctx.Lock.Unlock()
+			return
+		default:
+		}
+		if vm.restarts != restarts {
+			// The plugin was restarted by a call that failed while its
+			// process was exiting.
+			vm.ctx.Lock.Unlock()
+			return
+		}
+		vm.restartMetrics.restarts.Inc()
+		err := vm.restart(context.Background())
+		if errors.Is(err, errLastAcceptedMismatch) {
+			vm.fail(err)
+			vm.ctx.Lock.Unlock()
+			return
+		}
+		vm.ctx.Lock.Unlock()
+
+		if err == nil {
+			vm.ctx.Log.Info("restarted plugin",
+				zap.Int("pid", vm.pid),
+			)
+			return
+		}
+		vm.ctx.Log.Error("failed to restart plugin",
+			zap.Duration("retryIn", delay),
+			zap.Error(err),
+		)
+
+		select {
+		case <-time.After(delay):
+		case <-vm.closed:
+			return
+		}
+		delay *= 2
+		if delay > maxRestartDelay {
+			delay = maxRestartDelay
+		}
+	}
+}
+
+// recoverCall restarts the plugin if [err] was returned by a call made while
+// the context lock is held because the plugin process exited, before the
+// monitor noticed that the process exited. Returns true if the plugin was
+// restarted, so that the call can be made again.
+func (vm *VMClient) recoverCall(ctx context.Context, method string, err error) bool {
+	if _, ok := lockedMethods[method]; !ok || status.Code(err) != codes.Unavailable {
+		return false
+	}
+	// The calls made while restarting the plugin aren't recovered: the
+	// restart fails instead.
+	if vm.restarting || vm.proc == nil || !vm.proc.Exited() {
+		return false
+	}
+
+	vm.ctx.Log.Warn("plugin process exited during a call, restarting it",
+		zap.String("method", method),
+		zap.Int("pid", vm.pid),
+	)
+	vm.restartMetrics.restarts.Inc()
+	if err := vm.restart(ctx); err != nil {
+		if errors.Is(err, errLastAcceptedMismatch) {
+			vm.fail(err)
+		} else {
+			vm.ctx.Log.Error("failed to restart plugin",
+				zap.Error(err),
+			)
+		}
+		return false
+	}
+	vm.ctx.Log.Info("restarted plugin",
+		zap.Int("pid", vm.pid),
+	)
+	return true
+}
+
+func (vm *VMClient) SetOnFailed(onFailed func(err error)) {
+	vm.onFailed.SetValue(onFailed)
+}
+
+// fail stops the plugin after it was restarted with a state the chain can't
+// resume from. The chain is reported unhealthy until it is bootstrapped again,
+// and the chain's handler is stopped through the callback registered with
+// SetOnFailed.
+//
+// Assumes the context lock is held.
+func (vm *VMClient) fail(err error) {
+	vm.ctx.Log.Error("restarted plugin can't resume the chain, the chain must be bootstrapped again",
+		zap.Error(err),
+	)
+	vm.failed.SetValue(true)
+	if vm.proc != nil {
+		vm.proc.Kill()
+	}
+	if onFailed, ok := vm.onFailed.GetValue().(func(error)); ok {
+		onFailed(err)
+	}
+}
+
+// restart relaunches the plugin and brings it back to the state of the
+// previous plugin: it is initialized with the same request, put in the same
+// state, given the blocks that are processing, and told about the preferred
+// block and the connected peers. The servers of the node are left running, so
+// the restarted plugin connects to the same servers.
+//
+// The restarted plugin must resume from the last accepted block of the chain.
+// Otherwise, errLastAcceptedMismatch is returned: the chain isn't rewound to
+// the block the plugin resumes from, as the blocks accepted after it were
+// already reported to the rest of the node.
+//
+// Assumes the context lock is held.
+func (vm *VMClient) restart(ctx context.Context) error {
+	vm.restarting = true
+	defer func() {
+		vm.restarting = false
+	}()
+
+	if vm.gossip != nil {
+		vm.gossip.Close()
+		vm.gossip = nil
+	}
+
+	conn, err := vm.relaunch(vm.pluginPath)
+	if err != nil {
+		return err
+	}
+	if err := vm.conn.set(conn); err != nil {
+		vm.ctx.Log.Debug("failed to close the connection to the previous plugin",
+			zap.Error(err),
+		)
+	}
+
+	// The restarted plugin may be a different build of the plugin.
+	if err := vm.handshake(ctx); err != nil {
+		return err
+	}
+
+	initResp, err := vm.client.Initialize(ctx, vm.initRequest)
+	if err != nil {
+		return err
+	}
+	lastAcceptedBlk, err := vm.newLastAcceptedBlock(
+		initResp.LastAcceptedId,
+		initResp.LastAcceptedParentId,
+		initResp.Height,
+		initResp.Bytes,
+		initResp.Timestamp,
+		initResp.LastAcceptedOracle,
+	)
+	if err != nil {
+		return err
+	}
+
+	if vm.stateSet {
+		stateResp, err := vm.client.SetState(ctx, &vmpb.SetStateRequest{
+			State: uint32(vm.state),
+		})
+		if err != nil {
+			return err
+		}
+		lastAcceptedBlk, err = vm.newLastAcceptedBlock(
+			stateResp.LastAcceptedId,
+			stateResp.LastAcceptedParentId,
+			stateResp.Height,
+			stateResp.Bytes,
+			stateResp.Timestamp,
+			stateResp.LastAcceptedOracle,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	// The processing blocks build on the last accepted block of the chain,
+	// which the plugin must agree on.
+	if lastAcceptedBlk.id != vm.State.LastAcceptedBlock().ID() {
+		return fmt.Errorf("%w: %s != %s",
+			errLastAcceptedMismatch,
+			lastAcceptedBlk.id,
+			vm.State.LastAcceptedBlock().ID(),
+		)
+	}
+
+	processingBlks := vm.State.VerifiedBlocks()
+
+	// Parents are verified before their children.
+	sort.Slice(processingBlks, func(i, j int) bool {
+		return processingBlks[i].Height() < processingBlks[j].Height()
+	})
+	for _, blk := range processingBlks {
+		// The restarted plugin never parsed the block, so it can't be verified
+		// by ID.
+		if _, err := vm.client.BlockVerify(ctx, &vmpb.BlockVerifyRequest{
+			Bytes: blk.Bytes(),
+		}); err != nil {
+			return fmt.Errorf("failed to verify processing block %s: %w", blk.ID(), err)
+		}
+	}
+
+	if vm.preferred != ids.Empty {
+		if _, err := vm.client.SetPreference(ctx, &vmpb.SetPreferenceRequest{
+			Id: vm.preferred[:],
+		}); err != nil {
+			return err
+		}
+	}
+
+	for nodeID, nodeVersion := range vm.peers {
+		if _, err := vm.client.Connected(ctx, &vmpb.ConnectedRequest{
+			NodeId:  nodeID[:],
+			Version: nodeVersion.String(),
+		}); err != nil {
+			return err
+		}
+	}
+
+	vm.gossip, err = vm.openGossipStream()
+	if err != nil {
+		return err
+	}
+	vm.restarts++
+	return nil
+}
+
+func (vm *VMClient) newLastAcceptedBlock(
+	idBytes []byte,
+	parentIDBytes []byte,
+	height uint64,
+	bytes []byte,
+	timestamp *timestamppb.Timestamp,
+	oracle bool,
+) (*blockClient, error) {
+	id, err := ids.ToID(idBytes)
+	if err != nil {
+		return nil, err
+	}
+	parentID, err := ids.ToID(parentIDBytes)
+	if err != nil {
+		return nil, err
+	}
+	time, err := grpcutils.TimestampAsTime(timestamp)
+	if err != nil {
+		return nil, err
+	}
+	return &blockClient{
+		vm:       vm,
+		id:       id,
+		parentID: parentID,
+		status:   choices.Accepted,
+		bytes:    bytes,
+		height:   height,
+		time:     time,
+		oracle:   oracle,
+	}, nil
+}
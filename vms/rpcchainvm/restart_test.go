@@ -0,0 +1,361 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpcchainvm
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	grpc_prometheus "github.com/grpc-ecosystem/go-grpc-prometheus"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/stretchr/testify/require"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/ava-labs/avalanchego/database/manager"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
+	"github.com/ava-labs/avalanchego/version"
+
+	vmpb "github.com/ava-labs/avalanchego/proto/pb/vm"
+)
+
+// restartTestVM is a plugin that records the requests it is given.
+type restartTestVM struct {
+	block.TestVM
+
+	initialized bool
+	state       snow.State
+	verified    []ids.ID
+	preferred   ids.ID
+	connected   []ids.NodeID
+}
+
+func newRestartTestVM(t *testing.T, blks ...*snowman.TestBlock) *restartTestVM {
+	vm := &restartTestVM{}
+	vm.T = t
+	vm.InitializeF = func(context.Context, *snow.Context, manager.Manager, []byte, []byte, []byte, chan<- common.Message, []*common.Fx, common.AppSender) error {
+		vm.initialized = true
+		return nil
+	}
+	vm.SetStateF = func(_ context.Context, state snow.State) error {
+		vm.state = state
+		return nil
+	}
+	vm.LastAcceptedF = func(context.Context) (ids.ID, error) {
+		return blks[0].ID(), nil
+	}
+	vm.GetBlockF = func(_ context.Context, blkID ids.ID) (snowman.Block, error) {
+		for _, blk := range blks {
+			if blk.ID() == blkID {
+				return blk, nil
+			}
+		}
+		return nil, errors.New("unknown block")
+	}
+	vm.ParseBlockF = func(_ context.Context, b []byte) (snowman.Block, error) {
+		for _, blk := range blks {
+			if string(blk.Bytes()) == string(b) {
+				blk := *blk
+				blk.VerifyV = nil
+				vm.verified = append(vm.verified, blk.ID())
+				return &blk, nil
+			}
+		}
+		return nil, errors.New("unknown block")
+	}
+	vm.SetPreferenceF = func(_ context.Context, blkID ids.ID) error {
+		vm.preferred = blkID
+		return nil
+	}
+	vm.ConnectedF = func(_ context.Context, nodeID ids.NodeID, _ *version.Application) error {
+		vm.connected = append(vm.connected, nodeID)
+		return nil
+	}
+	return vm
+}
+
+func TestRestart(t *testing.T) {
+	require := require.New(t)
+
+	genesis := &snowman.TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.GenerateTestID(),
+			StatusV: choices.Accepted,
+		},
+		TimestampV: time.Unix(1, 0),
+		BytesV:     []byte{0},
+	}
+	child := &snowman.TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.GenerateTestID(),
+			StatusV: choices.Processing,
+		},
+		ParentV:    genesis.ID(),
+		HeightV:    1,
+		TimestampV: time.Unix(2, 0),
+		BytesV:     []byte{1},
+	}
+	grandChild := &snowman.TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.GenerateTestID(),
+			StatusV: choices.Processing,
+		},
+		ParentV:    child.ID(),
+		HeightV:    2,
+		TimestampV: time.Unix(3, 0),
+		BytesV:     []byte{2},
+	}
+
+	pluginVM := newRestartTestVM(t, genesis, child, grandChild)
+	conn := dialVM(t, NewServer(pluginVM))
+	vm := newRestartableClient(conn)
+	restartedVM := newRestartTestVM(t, genesis, child, grandChild)
+	vm.relaunch = func(string) (grpc.ClientConnInterface, error) {
+		return dialVM(t, NewServer(restartedVM)), nil
+	}
+
+	ctx := snow.DefaultContextTest()
+	dbManager := manager.NewMemDB(version.Semantic1_0_0)
+	toEngine := make(chan common.Message, 1)
+	require.NoError(vm.Initialize(context.Background(), ctx, dbManager, nil, nil, nil, toEngine, nil, nil))
+	defer vm.serverCloser.Stop()
+
+	require.NoError(vm.SetState(context.Background(), snow.NormalOp))
+	for _, blk := range []*snowman.TestBlock{grandChild, child} {
+		parsedBlk, err := vm.ParseBlock(context.Background(), blk.Bytes())
+		require.NoError(err)
+		require.NoError(parsedBlk.Verify(context.Background()))
+	}
+	require.NoError(vm.SetPreference(context.Background(), grandChild.ID()))
+	nodeID := ids.GenerateTestNodeID()
+	require.NoError(vm.Connected(context.Background(), nodeID, version.CurrentApp))
+
+	require.NoError(vm.restart(context.Background()))
+
+	// The restarted plugin is brought back to the state of the previous one.
+	require.True(restartedVM.initialized)
+	require.EqualValues(snow.NormalOp, restartedVM.state)
+	require.Equal([]ids.ID{child.ID(), grandChild.ID()}, restartedVM.verified)
+	require.Equal(grandChild.ID(), restartedVM.preferred)
+	require.Equal([]ids.NodeID{nodeID}, restartedVM.connected)
+	require.Equal(genesis.ID(), vm.State.LastAcceptedBlock().ID())
+	require.Equal(uint64(1), vm.restarts)
+
+	// The connection to the previous plugin is closed.
+	require.Equal(connectivity.Shutdown, conn.GetState())
+
+	// The client now talks to the restarted plugin.
+	restartedVM.preferred = ids.Empty
+	require.NoError(vm.SetPreference(context.Background(), child.ID()))
+	require.Equal(child.ID(), restartedVM.preferred)
+
+	vm.recovering.SetValue(true)
+	_, err := vm.HealthCheck(context.Background())
+	require.ErrorIs(err, errRecovering)
+}
+
+func TestRestartLastAcceptedMismatch(t *testing.T) {
+	require := require.New(t)
+
+	genesis := &snowman.TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.GenerateTestID(),
+			StatusV: choices.Accepted,
+		},
+		TimestampV: time.Unix(1, 0),
+		BytesV:     []byte{0},
+	}
+	accepted := &snowman.TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.GenerateTestID(),
+			StatusV: choices.Accepted,
+		},
+		ParentV:    genesis.ID(),
+		HeightV:    1,
+		TimestampV: time.Unix(2, 0),
+		BytesV:     []byte{1},
+	}
+
+	pluginVM := newRestartTestVM(t, accepted, genesis)
+	vm := newRestartableClient(dialVM(t, NewServer(pluginVM)))
+
+	// The restarted plugin didn't persist the last accepted block.
+	restartedVM := newRestartTestVM(t, genesis, accepted)
+	vm.relaunch = func(string) (grpc.ClientConnInterface, error) {
+		return dialVM(t, NewServer(restartedVM)), nil
+	}
+
+	ctx := snow.DefaultContextTest()
+	dbManager := manager.NewMemDB(version.Semantic1_0_0)
+	toEngine := make(chan common.Message, 1)
+	require.NoError(vm.Initialize(context.Background(), ctx, dbManager, nil, nil, nil, toEngine, nil, nil))
+	defer vm.serverCloser.Stop()
+
+	err := vm.restart(context.Background())
+	require.ErrorIs(err, errLastAcceptedMismatch)
+
+	// The chain isn't rewound.
+	require.Equal(accepted.ID(), vm.State.LastAcceptedBlock().ID())
+	require.Zero(vm.restarts)
+}
+
+func TestPluginConnRecover(t *testing.T) {
+	require := require.New(t)
+
+	pluginVM := &block.TestVM{}
+	pluginVM.VersionF = func(context.Context) (string, error) {
+		return "v1.0.0", nil
+	}
+	vm := newRestartableClient(dialVM(t, NewServer(pluginVM)))
+
+	var calls int
+	vm.conn.addInterceptors(
+		func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+			calls++
+			if calls == 1 {
+				return status.Error(codes.Unavailable, "plugin exited")
+			}
+			return invoker(ctx, method, req, reply, cc, opts...)
+		},
+		nil,
+	)
+
+	// The call isn't made again if the plugin isn't restarted.
+	vm.conn.recover = func(context.Context, string, error) bool {
+		return false
+	}
+	_, err := vm.client.Version(context.Background(), &emptypb.Empty{})
+	require.Equal(codes.Unavailable, status.Code(err))
+	require.Equal(1, calls)
+
+	// The call is made again once the plugin is restarted.
+	calls = 0
+	var recovered []string
+	vm.conn.recover = func(_ context.Context, method string, err error) bool {
+		recovered = append(recovered, method)
+		return status.Code(err) == codes.Unavailable
+	}
+	_, err = vm.client.Version(context.Background(), &emptypb.Empty{})
+	require.NoError(err)
+	require.Equal(2, calls)
+	require.Equal([]string{"/vm.VM/Version"}, recovered)
+}
+
+func TestPluginConnInterceptors(t *testing.T) {
+	require := require.New(t)
+
+	server := &compressionServer{
+		handshakeServer: &handshakeServer{
+			VMServer: NewServer(&block.TestVM{}),
+		},
+	}
+	vm := newRestartableClient(dialVM(t, server))
+
+	var calls []string
+	record := func(name string) grpc.UnaryClientInterceptor {
+		return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+			calls = append(calls, name)
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}
+	}
+	vm.conn.addInterceptors(record("first"), nil)
+	vm.conn.addInterceptors(record("second"), nil)
+
+	clientMetrics := grpc_prometheus.NewClientMetrics()
+	clientMetrics.EnableClientHandlingTimeHistogram()
+	registry := prometheus.NewRegistry()
+	require.NoError(registry.Register(clientMetrics))
+	vm.conn.addInterceptors(clientMetrics.UnaryClientInterceptor(), nil)
+
+	_, err := vm.client.ParseBlock(context.Background(), &vmpb.ParseBlockRequest{
+		Bytes: []byte{1},
+	})
+	require.NoError(err)
+	require.Equal([]string{"first", "second"}, calls)
+
+	// The latency of the call is reported per method.
+	families, err := registry.Gather()
+	require.NoError(err)
+	var count uint64
+	for _, family := range families {
+		if family.GetName() != "grpc_client_handling_seconds" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			for _, label := range metric.GetLabel() {
+				if label.GetName() == "grpc_method" && label.GetValue() == "ParseBlock" {
+					count += metric.GetHistogram().GetSampleCount()
+				}
+			}
+		}
+	}
+	require.Equal(uint64(1), count)
+}
+
+func TestRecoverProcessFailure(t *testing.T) {
+	require := require.New(t)
+
+	genesis := &snowman.TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.GenerateTestID(),
+			StatusV: choices.Accepted,
+		},
+		TimestampV: time.Unix(1, 0),
+		BytesV:     []byte{0},
+	}
+	accepted := &snowman.TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.GenerateTestID(),
+			StatusV: choices.Accepted,
+		},
+		ParentV:    genesis.ID(),
+		HeightV:    1,
+		TimestampV: time.Unix(2, 0),
+		BytesV:     []byte{1},
+	}
+
+	pluginVM := newRestartTestVM(t, accepted, genesis)
+	vm := newRestartableClient(dialVM(t, NewServer(pluginVM)))
+
+	// The restarted plugin didn't persist the last accepted block.
+	restartedVM := newRestartTestVM(t, genesis, accepted)
+	relaunches := 0
+	vm.relaunch = func(string) (grpc.ClientConnInterface, error) {
+		relaunches++
+		return dialVM(t, NewServer(restartedVM)), nil
+	}
+	var failure error
+	vm.SetOnFailed(func(err error) {
+		failure = err
+	})
+
+	ctx := snow.DefaultContextTest()
+	dbManager := manager.NewMemDB(version.Semantic1_0_0)
+	toEngine := make(chan common.Message, 1)
+	require.NoError(vm.Initialize(context.Background(), ctx, dbManager, nil, nil, nil, toEngine, nil, nil))
+	defer vm.serverCloser.Stop()
+
+	// The restart isn't retried, and the chain is told that the plugin
+	// failed, so that its handler is stopped.
+	vm.recoverProcess(0)
+	require.Equal(1, relaunches)
+	require.ErrorIs(failure, errLastAcceptedMismatch)
+	require.False(vm.recovering.GetValue())
+
+	_, err := vm.HealthCheck(context.Background())
+	require.ErrorIs(err, errLastAcceptedMismatch)
+}
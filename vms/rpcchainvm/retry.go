@@ -0,0 +1,132 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpcchainvm
+
+import (
+	"context"
+	"errors"
+	"path"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	errInvalidRetryBackoff = errors.New("retry backoff must be > 0")
+	errInvalidMaxBackoff   = errors.New("max retry backoff must be >= the initial backoff")
+
+	// retriedMethods are the methods of the plugin's vm server that don't
+	// change the state of the plugin, so that they can be called again if a
+	// call fails.
+	retriedMethods = map[string]struct{}{
+		"/vm.VM/GetBlock":   {},
+		"/vm.VM/ParseBlock": {},
+		"/vm.VM/Gather":     {},
+	}
+)
+
+// RetryConfig configures how the calls to the plugin that fail because the
+// plugin is unreachable, such as during a long garbage collection pause of the
+// plugin, are retried. Only the calls that don't change the state of the
+// plugin are retried.
+type RetryConfig struct {
+	// Max number of times a call is made. If <= 1, calls aren't retried.
+	MaxAttempts int `json:"maxAttempts"`
+	// Delay before the first retry of a call. The delay doubles with each
+	// retry of the call.
+	InitialBackoff time.Duration `json:"initialBackoff"`
+	// Max delay before a retry of a call.
+	MaxBackoff time.Duration `json:"maxBackoff"`
+}
+
+func (c RetryConfig) Verify() error {
+	switch {
+	case c.MaxAttempts <= 1:
+		return nil
+	case c.InitialBackoff <= 0:
+		return errInvalidRetryBackoff
+	case c.MaxBackoff < c.InitialBackoff:
+		return errInvalidMaxBackoff
+	default:
+		return nil
+	}
+}
+
+// backoff returns the delay before the [retry]th retry of a call, starting
+// from 1.
+func (c RetryConfig) backoff(retry int) time.Duration {
+	backoff := c.InitialBackoff
+	for i := 1; i < retry && backoff < c.MaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > c.MaxBackoff {
+		return c.MaxBackoff
+	}
+	return backoff
+}
+
+type retryMetrics struct {
+	retries  *prometheus.CounterVec
+	failures *prometheus.CounterVec
+}
+
+func (m *retryMetrics) Initialize(registerer prometheus.Registerer) error {
+	m.retries = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "plugin_rpc_retries",
+		Help: "Number of times a call to the plugin was retried after the plugin was unreachable",
+	}, []string{"method"})
+	m.failures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "plugin_rpc_retries_exhausted",
+		Help: "Number of calls to the plugin that failed after every retry",
+	}, []string{"method"})
+
+	if err := registerer.Register(m.retries); err != nil {
+		return err
+	}
+	return registerer.Register(m.failures)
+}
+
+// retryUnaryClientInterceptor retries the calls to [retriedMethods] that fail
+// because the plugin is unreachable, as configured by [config].
+func retryUnaryClientInterceptor(config RetryConfig, metrics *retryMetrics) grpc.UnaryClientInterceptor {
+	return func(
+		ctx context.Context,
+		method string,
+		req, reply interface{},
+		cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker,
+		opts ...grpc.CallOption,
+	) error {
+		if _, ok := retriedMethods[method]; !ok {
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}
+
+		name := path.Base(method)
+		for attempt := 1; ; attempt++ {
+			err := invoker(ctx, method, req, reply, cc, opts...)
+			if status.Code(err) != codes.Unavailable {
+				return err
+			}
+			if attempt >= config.MaxAttempts {
+				metrics.failures.WithLabelValues(name).Inc()
+				return err
+			}
+
+			timer := time.NewTimer(config.backoff(attempt))
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				timer.Stop()
+				// The error of the last attempt is more helpful than the
+				// cancellation of the context.
+				return err
+			}
+			metrics.retries.WithLabelValues(name).Inc()
+		}
+	}
+}
@@ -0,0 +1,187 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpcchainvm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	dto "github.com/prometheus/client_model/go"
+
+	"github.com/stretchr/testify/require"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func counterValue(t *testing.T, counter prometheus.Counter) float64 {
+	metric := &dto.Metric{}
+	require.NoError(t, counter.Write(metric))
+	return metric.Counter.GetValue()
+}
+
+func TestRetryConfigVerify(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      RetryConfig
+		expectedErr error
+	}{
+		{
+			name:   "disabled",
+			config: RetryConfig{MaxAttempts: 1},
+		},
+		{
+			name: "valid",
+			config: RetryConfig{
+				MaxAttempts:    3,
+				InitialBackoff: time.Millisecond,
+				MaxBackoff:     time.Second,
+			},
+		},
+		{
+			name: "no backoff",
+			config: RetryConfig{
+				MaxAttempts: 3,
+				MaxBackoff:  time.Second,
+			},
+			expectedErr: errInvalidRetryBackoff,
+		},
+		{
+			name: "max backoff below initial backoff",
+			config: RetryConfig{
+				MaxAttempts:    3,
+				InitialBackoff: time.Second,
+				MaxBackoff:     time.Millisecond,
+			},
+			expectedErr: errInvalidMaxBackoff,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.ErrorIs(t, test.config.Verify(), test.expectedErr)
+		})
+	}
+}
+
+func TestRetryConfigBackoff(t *testing.T) {
+	require := require.New(t)
+
+	config := RetryConfig{
+		InitialBackoff: 100 * time.Millisecond,
+		MaxBackoff:     time.Second,
+	}
+	require.Equal(100*time.Millisecond, config.backoff(1))
+	require.Equal(200*time.Millisecond, config.backoff(2))
+	require.Equal(400*time.Millisecond, config.backoff(3))
+	require.Equal(800*time.Millisecond, config.backoff(4))
+	require.Equal(time.Second, config.backoff(5))
+	require.Equal(time.Second, config.backoff(100))
+}
+
+func TestRetryUnaryClientInterceptor(t *testing.T) {
+	tests := []struct {
+		name             string
+		method           string
+		errs             []error
+		expectedCode     codes.Code
+		expectedCalls    int
+		expectedRetries  float64
+		expectedFailures float64
+	}{
+		{
+			name:   "retried until success",
+			method: "/vm.VM/GetBlock",
+			errs: []error{
+				status.Error(codes.Unavailable, "paused"),
+				status.Error(codes.Unavailable, "paused"),
+				nil,
+			},
+			expectedCode:    codes.OK,
+			expectedCalls:   3,
+			expectedRetries: 2,
+		},
+		{
+			name:   "retries exhausted",
+			method: "/vm.VM/ParseBlock",
+			errs: []error{
+				status.Error(codes.Unavailable, "paused"),
+				status.Error(codes.Unavailable, "paused"),
+				status.Error(codes.Unavailable, "paused"),
+			},
+			expectedCode:     codes.Unavailable,
+			expectedCalls:    3,
+			expectedRetries:  2,
+			expectedFailures: 1,
+		},
+		{
+			name:   "other errors aren't retried",
+			method: "/vm.VM/Gather",
+			errs: []error{
+				status.Error(codes.Internal, "failed"),
+			},
+			expectedCode:  codes.Internal,
+			expectedCalls: 1,
+		},
+		{
+			name:   "methods changing the state aren't retried",
+			method: "/vm.VM/BuildBlock",
+			errs: []error{
+				status.Error(codes.Unavailable, "paused"),
+			},
+			expectedCode:  codes.Unavailable,
+			expectedCalls: 1,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require := require.New(t)
+
+			metrics := &retryMetrics{}
+			require.NoError(metrics.Initialize(prometheus.NewRegistry()))
+			interceptor := retryUnaryClientInterceptor(RetryConfig{
+				MaxAttempts:    3,
+				InitialBackoff: time.Millisecond,
+				MaxBackoff:     time.Millisecond,
+			}, metrics)
+
+			calls := 0
+			invoker := func(context.Context, string, interface{}, interface{}, *grpc.ClientConn, ...grpc.CallOption) error {
+				err := test.errs[calls]
+				calls++
+				return err
+			}
+			err := interceptor(context.Background(), test.method, nil, nil, nil, invoker)
+			require.Equal(test.expectedCode, status.Code(err))
+			require.Equal(test.expectedCalls, calls)
+
+			name := test.method[len("/vm.VM/"):]
+			require.Equal(test.expectedRetries, counterValue(t, metrics.retries.WithLabelValues(name)))
+			require.Equal(test.expectedFailures, counterValue(t, metrics.failures.WithLabelValues(name)))
+		})
+	}
+}
+
+func TestRetryUnaryClientInterceptorStopsWithContext(t *testing.T) {
+	require := require.New(t)
+
+	metrics := &retryMetrics{}
+	require.NoError(metrics.Initialize(prometheus.NewRegistry()))
+	interceptor := retryUnaryClientInterceptor(RetryConfig{
+		MaxAttempts:    3,
+		InitialBackoff: time.Hour,
+		MaxBackoff:     time.Hour,
+	}, metrics)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	invoker := func(context.Context, string, interface{}, interface{}, *grpc.ClientConn, ...grpc.CallOption) error {
+		cancel()
+		return status.Error(codes.Unavailable, "paused")
+	}
+	err := interceptor(ctx, "/vm.VM/GetBlock", nil, nil, nil, invoker)
+	require.Equal(codes.Unavailable, status.Code(err))
+}
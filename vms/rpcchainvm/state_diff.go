@@ -0,0 +1,145 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpcchainvm
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
+	"github.com/ava-labs/avalanchego/utils"
+	"github.com/ava-labs/avalanchego/utils/units"
+
+	vmpb "github.com/ava-labs/avalanchego/proto/pb/vm"
+)
+
+// Size, in bytes, above which the entries of a state diff are sent to the
+// node in a new response
+const stateDiffChunkSize = units.MiB
+
+var (
+	errStateDiffDigestMismatch = errors.New("state diff digest mismatch")
+	errStateDiffTruncated      = errors.New("state diff ended without a digest")
+)
+
+// GetStateDiff streams the state diff from the plugin. The digest the plugin
+// computed is checked against the entries that were received, so a diff that
+// was reordered or truncated is reported as an error after [f] was called
+// with the entries received so far.
+func (vm *VMClient) GetStateDiff(
+	ctx context.Context,
+	startHeight uint64,
+	endHeight uint64,
+	f func(key []byte, value []byte) error,
+) error {
+	if !vm.capabilities.Has(CapabilityStateDiffer) {
+		return block.ErrStateDifferNotImplemented
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := vm.client.GetStateDiff(ctx, &vmpb.GetStateDiffRequest{
+		StartHeight: startHeight,
+		EndHeight:   endHeight,
+	})
+	if err != nil {
+		return err
+	}
+
+	digest := block.NewStateDiffDigest()
+	for {
+		resp, err := stream.Recv()
+		if status.Code(err) == codes.Unimplemented {
+			// The plugin predates GetStateDiff.
+			return block.ErrStateDifferNotImplemented
+		}
+		if err == io.EOF {
+			return errStateDiffTruncated
+		}
+		if err != nil {
+			return err
+		}
+		if errCode := resp.Err; errCode != 0 {
+			return errCodeToError[errCode]
+		}
+
+		for _, entry := range resp.Entries {
+			value := entry.Value
+			if entry.Deleted {
+				value = nil
+			} else if value == nil {
+				value = []byte{}
+			}
+			if err := digest.Add(entry.Key, value); err != nil {
+				return err
+			}
+			if err := f(entry.Key, value); err != nil {
+				return err
+			}
+		}
+
+		if len(resp.Digest) == 0 {
+			continue
+		}
+		if resp.NumEntries != digest.NumEntries() || !bytes.Equal(resp.Digest, digest.Sum()) {
+			return errStateDiffDigestMismatch
+		}
+		return nil
+	}
+}
+
+func (vm *VMServer) GetStateDiff(req *vmpb.GetStateDiffRequest, stream vmpb.VM_GetStateDiffServer) error {
+	if vm.sdVM == nil {
+		return stream.Send(&vmpb.GetStateDiffResponse{
+			Err: errorToErrCode[block.ErrStateDifferNotImplemented],
+		})
+	}
+
+	var (
+		digest    = block.NewStateDiffDigest()
+		entries   []*vmpb.StateDiffEntry
+		chunkSize int
+	)
+	err := vm.sdVM.GetStateDiff(stream.Context(), req.StartHeight, req.EndHeight, func(key []byte, value []byte) error {
+		if err := digest.Add(key, value); err != nil {
+			return err
+		}
+		// The VM may reuse [key] and [value] once [f] returns.
+		entries = append(entries, &vmpb.StateDiffEntry{
+			Key:     utils.CopyBytes(key),
+			Value:   utils.CopyBytes(value),
+			Deleted: value == nil,
+		})
+		chunkSize += len(key) + len(value)
+		if chunkSize < stateDiffChunkSize {
+			return nil
+		}
+
+		resp := &vmpb.GetStateDiffResponse{
+			Entries: entries,
+		}
+		entries = nil
+		chunkSize = 0
+		return stream.Send(resp)
+	})
+	if err != nil {
+		if errCode, ok := errorToErrCode[err]; ok {
+			return stream.Send(&vmpb.GetStateDiffResponse{
+				Err: errCode,
+			})
+		}
+		return err
+	}
+	return stream.Send(&vmpb.GetStateDiffResponse{
+		Entries:    entries,
+		Digest:     digest.Sum(),
+		NumEntries: digest.NumEntries(),
+	})
+}
@@ -0,0 +1,155 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpcchainvm
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
+	"github.com/ava-labs/avalanchego/utils/units"
+
+	vmpb "github.com/ava-labs/avalanchego/proto/pb/vm"
+)
+
+type stateDifferVM struct {
+	*block.TestVM
+	*block.TestStateDiffer
+}
+
+// diffEntry is an entry of a state diff. A nil value means that the key was
+// deleted.
+type diffEntry struct {
+	key   []byte
+	value []byte
+}
+
+func newStateDifferClient(t *testing.T, getStateDiff func(context.Context, uint64, uint64, func([]byte, []byte) error) error) *VMClient {
+	vm := NewClient(serveVM(t, NewServer(&stateDifferVM{
+		TestVM: &block.TestVM{},
+		TestStateDiffer: &block.TestStateDiffer{
+			T:             t,
+			GetStateDiffF: getStateDiff,
+		},
+	})))
+	require.NoError(t, vm.handshake(context.Background()))
+	return vm
+}
+
+func collectStateDiff(vm block.StateDiffer, startHeight, endHeight uint64) ([]diffEntry, error) {
+	var entries []diffEntry
+	err := vm.GetStateDiff(context.Background(), startHeight, endHeight, func(key []byte, value []byte) error {
+		entries = append(entries, diffEntry{
+			key:   key,
+			value: value,
+		})
+		return nil
+	})
+	return entries, err
+}
+
+func TestGetStateDiff(t *testing.T) {
+	require := require.New(t)
+
+	// The large value spans the entries over several responses.
+	largeValue := make([]byte, stateDiffChunkSize)
+	expected := []diffEntry{
+		{key: []byte{1}, value: []byte{1}},
+		{key: []byte{2}, value: nil},
+		{key: []byte{3}, value: largeValue},
+		{key: []byte{4}, value: []byte{}},
+	}
+	vm := newStateDifferClient(t, func(_ context.Context, startHeight, endHeight uint64, f func([]byte, []byte) error) error {
+		require.EqualValues(2, startHeight)
+		require.EqualValues(5, endHeight)
+		for _, entry := range expected {
+			if err := f(entry.key, entry.value); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	require.True(vm.capabilities.Has(CapabilityStateDiffer))
+
+	entries, err := collectStateDiff(vm, 2, 5)
+	require.NoError(err)
+	require.Equal(expected, entries)
+}
+
+func TestGetStateDiffError(t *testing.T) {
+	require := require.New(t)
+
+	vm := newStateDifferClient(t, func(context.Context, uint64, uint64, func([]byte, []byte) error) error {
+		return block.ErrStateDifferNotImplemented
+	})
+	_, err := collectStateDiff(vm, 0, 1)
+	require.ErrorIs(err, block.ErrStateDifferNotImplemented)
+
+	// Keys must be exported in increasing order.
+	vm = newStateDifferClient(t, func(_ context.Context, _, _ uint64, f func([]byte, []byte) error) error {
+		if err := f([]byte{2}, nil); err != nil {
+			return err
+		}
+		return f([]byte{1}, nil)
+	})
+	_, err = collectStateDiff(vm, 0, 1)
+	require.Error(err)
+}
+
+func TestGetStateDiffNotImplemented(t *testing.T) {
+	require := require.New(t)
+
+	vm := NewClient(serveVM(t, NewServer(&block.TestVM{})))
+	require.NoError(vm.handshake(context.Background()))
+	require.False(vm.capabilities.Has(CapabilityStateDiffer))
+
+	_, err := collectStateDiff(vm, 0, 1)
+	require.ErrorIs(err, block.ErrStateDifferNotImplemented)
+}
+
+// truncatedStream drops the digest of the state diff and adds an entry that
+// the plugin didn't export.
+type truncatedStream struct {
+	vmpb.VM_GetStateDiffServer
+}
+
+func (s *truncatedStream) Send(resp *vmpb.GetStateDiffResponse) error {
+	if len(resp.Digest) != 0 {
+		resp.Entries = append(resp.Entries, &vmpb.StateDiffEntry{
+			Key:     []byte{5},
+			Deleted: true,
+		})
+	}
+	return s.VM_GetStateDiffServer.Send(resp)
+}
+
+type tamperingServer struct {
+	*VMServer
+}
+
+func (s *tamperingServer) GetStateDiff(req *vmpb.GetStateDiffRequest, stream vmpb.VM_GetStateDiffServer) error {
+	return s.VMServer.GetStateDiff(req, &truncatedStream{VM_GetStateDiffServer: stream})
+}
+
+func TestGetStateDiffDigestMismatch(t *testing.T) {
+	require := require.New(t)
+
+	vm := NewClient(serveVM(t, &tamperingServer{
+		VMServer: NewServer(&stateDifferVM{
+			TestVM: &block.TestVM{},
+			TestStateDiffer: &block.TestStateDiffer{
+				GetStateDiffF: func(_ context.Context, _, _ uint64, f func([]byte, []byte) error) error {
+					return f([]byte{1}, make([]byte, units.KiB))
+				},
+			},
+		}),
+	}))
+	require.NoError(vm.handshake(context.Background()))
+
+	entries, err := collectStateDiff(vm, 0, 1)
+	require.ErrorIs(err, errStateDiffDigestMismatch)
+	require.Len(entries, 2)
+}
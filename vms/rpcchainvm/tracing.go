@@ -0,0 +1,50 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpcchainvm
+
+import (
+	"go.opentelemetry.io/otel/attribute"
+
+	"google.golang.org/grpc"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/trace"
+	"github.com/ava-labs/avalanchego/vms/rpcchainvm/grpcutils"
+)
+
+// SetTracer traces the calls between the node and the plugin with [tracer].
+// The node records the spans: the plugin only forwards the trace context of
+// the calls it serves to the calls it makes back to the node.
+func (vm *VMClient) SetTracer(tracer trace.Tracer) {
+	vm.tracer = tracer
+}
+
+// traceConn starts tracing the calls to the plugin's vm server, if tracing is
+// enabled.
+func (vm *VMClient) traceConn(chainID ids.ID) {
+	if vm.tracer == nil {
+		return
+	}
+	vm.tracingAttrs = []attribute.KeyValue{
+		attribute.Stringer("chainID", chainID),
+	}
+	if vm.conn != nil {
+		vm.conn.addInterceptors(
+			grpcutils.TracingUnaryClientInterceptor(vm.tracer, vm.tracingAttrs...),
+			grpcutils.TracingStreamClientInterceptor(vm.tracer, vm.tracingAttrs...),
+		)
+	}
+}
+
+// tracingServerOptions returns the options that trace the calls the plugin
+// makes to the servers of the node.
+func (vm *VMClient) tracingServerOptions() []grpc.ServerOption {
+	return grpcutils.TracingServerOptions(vm.tracer, vm.tracingAttrs...)
+}
+
+// tracingDialOptions returns the options that trace the calls the node makes
+// to the servers of the plugin.
+func (vm *VMClient) tracingDialOptions() []grpc.DialOption {
+	return grpcutils.TracingDialOptions(vm.tracer, vm.tracingAttrs...)
+}
@@ -0,0 +1,93 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpcchainvm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+)
+
+var (
+	errNotUpgradable = errors.New("plugin wasn't launched by the node")
+	errShutdown      = errors.New("plugin was shut down")
+
+	_ common.UpgradableVM = (*VMClient)(nil)
+)
+
+// UpgradePlugin replaces the plugin with the plugin at [path] without stopping
+// the chain. The chain is quiesced by holding the context lock while the
+// previous plugin is shut down and the new plugin is launched and brought
+// back to the state of the previous one, as when the plugin is restarted.
+//
+// If the new plugin can't be brought back to the state of the previous one,
+// the previous plugin binary is launched again and an error is returned.
+//
+// Assumes the context lock isn't held.
+func (vm *VMClient) UpgradePlugin(ctx context.Context, path string) error {
+	if vm.relaunch == nil {
+		return errNotUpgradable
+	}
+
+	vm.ctx.Lock.Lock()
+	defer vm.ctx.Lock.Unlock()
+
+	select {
+	case <-vm.closed:
+		return errShutdown
+	default:
+	}
+
+	vm.recovering.SetValue(true)
+	vm.restartMetrics.recovering.Set(1)
+	defer func() {
+		vm.recovering.SetValue(false)
+		vm.restartMetrics.recovering.Set(0)
+	}()
+
+	previousPath := vm.pluginPath
+	vm.ctx.Log.Info("upgrading plugin",
+		zap.String("previousPath", previousPath),
+		zap.String("path", path),
+	)
+
+	// The previous plugin is given the chance to persist its state before its
+	// process is killed.
+	if _, err := vm.client.Shutdown(ctx, &emptypb.Empty{}); err != nil {
+		vm.ctx.Log.Warn("failed to shut down the previous plugin",
+			zap.Error(err),
+		)
+	}
+
+	vm.pluginPath = path
+	err := vm.restart(ctx)
+	if err == nil {
+		vm.restartMetrics.upgrades.Inc()
+		vm.ctx.Log.Info("upgraded plugin",
+			zap.String("path", path),
+			zap.Int("pid", vm.pid),
+		)
+		return nil
+	}
+
+	vm.ctx.Log.Error("failed to upgrade plugin, launching the previous plugin",
+		zap.String("path", path),
+		zap.Error(err),
+	)
+	vm.pluginPath = previousPath
+	if rollbackErr := vm.restart(ctx); rollbackErr != nil {
+		vm.ctx.Log.Error("failed to launch the previous plugin",
+			zap.Error(rollbackErr),
+		)
+		// The plugin is restarted once the context lock is released.
+		go vm.recoverProcess(vm.restarts)
+	}
+	return fmt.Errorf("failed to upgrade plugin: %w", err)
+}
@@ -0,0 +1,110 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpcchainvm
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"google.golang.org/grpc"
+
+	"github.com/ava-labs/avalanchego/database/manager"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/version"
+)
+
+func TestUpgradePlugin(t *testing.T) {
+	require := require.New(t)
+
+	genesis := &snowman.TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.GenerateTestID(),
+			StatusV: choices.Accepted,
+		},
+		TimestampV: time.Unix(1, 0),
+		BytesV:     []byte{0},
+	}
+	child := &snowman.TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.GenerateTestID(),
+			StatusV: choices.Processing,
+		},
+		ParentV:    genesis.ID(),
+		HeightV:    1,
+		TimestampV: time.Unix(2, 0),
+		BytesV:     []byte{1},
+	}
+
+	pluginVM := newRestartTestVM(t, genesis, child)
+	shutdown := false
+	pluginVM.ShutdownF = func(context.Context) error {
+		shutdown = true
+		return nil
+	}
+	vm := newRestartableClient(dialVM(t, NewServer(pluginVM)))
+	vm.pluginPath = "v1"
+
+	errLaunch := errors.New("failed to launch")
+	plugins := map[string]*restartTestVM{
+		"v1": newRestartTestVM(t, genesis, child),
+		"v2": newRestartTestVM(t, genesis, child),
+	}
+	var launched []string
+	vm.relaunch = func(path string) (grpc.ClientConnInterface, error) {
+		launched = append(launched, path)
+		pluginVM, ok := plugins[path]
+		if !ok {
+			return nil, errLaunch
+		}
+		return dialVM(t, NewServer(pluginVM)), nil
+	}
+
+	ctx := snow.DefaultContextTest()
+	dbManager := manager.NewMemDB(version.Semantic1_0_0)
+	toEngine := make(chan common.Message, 1)
+	require.NoError(vm.Initialize(context.Background(), ctx, dbManager, nil, nil, nil, toEngine, nil, nil))
+	defer vm.serverCloser.Stop()
+
+	require.NoError(vm.SetState(context.Background(), snow.NormalOp))
+	parsedBlk, err := vm.ParseBlock(context.Background(), child.Bytes())
+	require.NoError(err)
+	require.NoError(parsedBlk.Verify(context.Background()))
+	require.NoError(vm.SetPreference(context.Background(), child.ID()))
+
+	// The previous plugin is shut down, and the new plugin is brought to its
+	// state.
+	require.NoError(vm.UpgradePlugin(context.Background(), "v2"))
+	require.True(shutdown)
+	upgradedVM := plugins["v2"]
+	require.True(upgradedVM.initialized)
+	require.EqualValues(snow.NormalOp, upgradedVM.state)
+	require.Equal([]ids.ID{child.ID()}, upgradedVM.verified)
+	require.Equal(child.ID(), upgradedVM.preferred)
+	require.False(vm.recovering.GetValue())
+
+	// If the new plugin can't be launched, the previous plugin is launched
+	// again.
+	err = vm.UpgradePlugin(context.Background(), "v3")
+	require.ErrorIs(err, errLaunch)
+	require.Equal([]string{"v2", "v3", "v2"}, launched)
+	require.Equal("v2", vm.pluginPath)
+
+	upgradedVM.preferred = ids.Empty
+	require.NoError(vm.SetPreference(context.Background(), genesis.ID()))
+	require.Equal(genesis.ID(), upgradedVM.preferred)
+}
+
+func TestUpgradePluginNotLaunchedByNode(t *testing.T) {
+	vm := NewClient(nil)
+	err := vm.UpgradePlugin(context.Background(), "v2")
+	require.ErrorIs(t, err, errNotUpgradable)
+}
@@ -5,18 +5,25 @@ package rpcchainvm
 
 import (
 	"context"
+	"os"
 
 	"google.golang.org/grpc"
 
 	"github.com/hashicorp/go-plugin"
 
+	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
 	"github.com/ava-labs/avalanchego/version"
+	"github.com/ava-labs/avalanchego/vms"
 	"github.com/ava-labs/avalanchego/vms/rpcchainvm/grpcutils"
 
 	vmpb "github.com/ava-labs/avalanchego/proto/pb/vm"
 )
 
+// If set to "true" in the environment of a plugin, the plugin and the node
+// communicate over unix domain sockets rather than TCP loopback.
+const unixSocketsEnvKey = "VM_PLUGIN_UNIX_SOCKETS"
+
 var (
 	// Handshake is a common handshake that is shared by plugin and host.
 	Handshake = plugin.HandshakeConfig{
@@ -39,6 +46,13 @@ type vmPlugin struct {
 	// Concrete implementation, written in Go. This is only used for plugins
 	// that are written in Go.
 	vm block.ChainVM
+	// Feature extensions the vm can be initialized with
+	fxs map[ids.ID]vms.Factory
+	// Forwards the trace context of the calls the vm serves to the calls it
+	// makes to the node
+	propagator *grpcutils.Propagator
+	// If true, the servers of the vm listen on unix domain sockets
+	unixSockets bool
 }
 
 // New will be called by the server side of the plugin to pass into the server
@@ -49,23 +63,43 @@ func New(vm block.ChainVM) plugin.Plugin {
 
 // GRPCServer registers a new GRPC server.
 func (p *vmPlugin) GRPCServer(_ *plugin.GRPCBroker, s *grpc.Server) error {
-	vmpb.RegisterVMServer(s, NewServer(p.vm))
+	server := NewServerWithFxs(p.vm, p.fxs)
+	server.propagator = p.propagator
+	server.unixSockets = p.unixSockets
+	vmpb.RegisterVMServer(s, server)
 	return nil
 }
 
 // GRPCClient returns a new GRPC client
 func (*vmPlugin) GRPCClient(_ context.Context, _ *plugin.GRPCBroker, c *grpc.ClientConn) (interface{}, error) {
-	return NewClient(vmpb.NewVMClient(c)), nil
+	return newRestartableClient(c), nil
 }
 
 // Serve serves a ChainVM plugin using sane gRPC server defaults.
 func Serve(vm block.ChainVM) {
+	ServeWithFxs(vm, nil)
+}
+
+// ServeWithFxs serves a ChainVM plugin that can be initialized with the
+// feature extensions created by [fxs]. The feature extensions run in the
+// plugin process, so the node only sends the IDs of the feature extensions of
+// the chain.
+func ServeWithFxs(vm block.ChainVM, fxs map[ids.ID]vms.Factory) {
+	propagator := grpcutils.NewPropagator()
 	plugin.Serve(&plugin.ServeConfig{
 		HandshakeConfig: Handshake,
 		Plugins: map[string]plugin.Plugin{
-			"vm": New(vm),
+			"vm": &vmPlugin{
+				vm:         vm,
+				fxs:        fxs,
+				propagator: propagator,
+				// The node launched the plugin with the transport it uses.
+				unixSockets: os.Getenv(unixSocketsEnvKey) == "true",
+			},
 		},
 		// ensure proper defaults
-		GRPCServer: grpcutils.NewDefaultServer,
+		GRPCServer: func(opts []grpc.ServerOption) *grpc.Server {
+			return grpcutils.NewDefaultServer(append(opts, propagator.ServerOptions()...))
+		},
 	})
 }
@@ -5,9 +5,9 @@ package rpcchainvm
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
+	"net"
 	"time"
 
 	grpc_prometheus "github.com/grpc-ecosystem/go-grpc-prometheus"
@@ -18,17 +18,20 @@ import (
 
 	dto "github.com/prometheus/client_model/go"
 
+	"go.opentelemetry.io/otel/attribute"
+
 	"go.uber.org/zap"
 
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/emptypb"
 
-	healthpb "google.golang.org/grpc/health/grpc_health_v1"
-
 	"github.com/ava-labs/avalanchego/api/keystore/gkeystore"
 	"github.com/ava-labs/avalanchego/api/metrics"
 	"github.com/ava-labs/avalanchego/chains/atomic/gsharedmemory"
+	"github.com/ava-labs/avalanchego/database"
 	"github.com/ava-labs/avalanchego/database/manager"
 	"github.com/ava-labs/avalanchego/database/rpcdb"
 	"github.com/ava-labs/avalanchego/ids"
@@ -40,45 +43,65 @@ import (
 	"github.com/ava-labs/avalanchego/snow/engine/common/appsender"
 	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
 	"github.com/ava-labs/avalanchego/snow/validators/gvalidators"
+	"github.com/ava-labs/avalanchego/trace"
+	"github.com/ava-labs/avalanchego/utils"
 	"github.com/ava-labs/avalanchego/utils/resource"
 	"github.com/ava-labs/avalanchego/utils/wrappers"
 	"github.com/ava-labs/avalanchego/version"
 	"github.com/ava-labs/avalanchego/vms/components/chain"
+	"github.com/ava-labs/avalanchego/vms/rpcchainvm/gblob"
 	"github.com/ava-labs/avalanchego/vms/rpcchainvm/ghttp"
 	"github.com/ava-labs/avalanchego/vms/rpcchainvm/grpcutils"
+	"github.com/ava-labs/avalanchego/vms/rpcchainvm/gsignatureaggregator"
 	"github.com/ava-labs/avalanchego/vms/rpcchainvm/gsubnetlookup"
 	"github.com/ava-labs/avalanchego/vms/rpcchainvm/messenger"
 
 	aliasreaderpb "github.com/ava-labs/avalanchego/proto/pb/aliasreader"
 	appsenderpb "github.com/ava-labs/avalanchego/proto/pb/appsender"
-	httppb "github.com/ava-labs/avalanchego/proto/pb/http"
+	blobpb "github.com/ava-labs/avalanchego/proto/pb/blob"
 	keystorepb "github.com/ava-labs/avalanchego/proto/pb/keystore"
 	messengerpb "github.com/ava-labs/avalanchego/proto/pb/messenger"
 	rpcdbpb "github.com/ava-labs/avalanchego/proto/pb/rpcdb"
 	sharedmemorypb "github.com/ava-labs/avalanchego/proto/pb/sharedmemory"
+	signatureaggregatorpb "github.com/ava-labs/avalanchego/proto/pb/signatureaggregator"
 	subnetlookuppb "github.com/ava-labs/avalanchego/proto/pb/subnetlookup"
 	validatorstatepb "github.com/ava-labs/avalanchego/proto/pb/validatorstate"
 	vmpb "github.com/ava-labs/avalanchego/proto/pb/vm"
 )
 
 const (
-	decidedCacheSize    = 2048
-	missingCacheSize    = 2048
-	unverifiedCacheSize = 2048
-	bytesToIDCacheSize  = 2048
+	// Names of the servers the plugin connects to when they are multiplexed
+	dbMuxNamePrefix = "db/"
+	servicesMuxName = "services"
 )
 
 var (
 	errUnsupportedFXs                       = errors.New("unsupported feature extensions")
 	errBatchedParseBlockWrongNumberOfBlocks = errors.New("BatchedParseBlock returned different number of blocks than expected")
+	errBatchedGetBlockWrongNumberOfBlocks   = errors.New("BatchedGetBlock returned different number of blocks than expected")
+	errWrongNumberOfOptions                 = errors.New("BlockOptions returned a number of options other than 2")
 
 	_ block.ChainVM              = (*VMClient)(nil)
 	_ block.BatchedChainVM       = (*VMClient)(nil)
 	_ block.HeightIndexedChainVM = (*VMClient)(nil)
 	_ block.StateSyncableVM      = (*VMClient)(nil)
+	_ block.Decoder              = (*VMClient)(nil)
+	_ block.DecisionBatcher      = (*VMClient)(nil)
+	_ block.StateDiffer          = (*VMClient)(nil)
+	_ block.MessageVerifier      = (*VMClient)(nil)
+	_ block.BuildPacingVM        = (*VMClient)(nil)
 	_ prometheus.Gatherer        = (*VMClient)(nil)
+	_ common.ConfigSchemaVM      = (*VMClient)(nil)
+	_ common.GRPCConfigurableVM  = (*VMClient)(nil)
+	_ common.CacheConfigurableVM = (*VMClient)(nil)
+	_ common.TraceableVM         = (*VMClient)(nil)
+	_ common.AppErrorHandler     = (*VMClient)(nil)
+
+	_ block.BuildBlockWithContextChainVM = (*VMClient)(nil)
 
-	_ snowman.Block = (*blockClient)(nil)
+	_ snowman.Block           = (*blockClient)(nil)
+	_ block.WithVerifyContext = (*blockClient)(nil)
+	_ snowman.OracleBlock     = (*blockClient)(nil)
 
 	_ block.StateSummary = (*summaryClient)(nil)
 )
@@ -90,6 +113,9 @@ type VMClient struct {
 	proc           *plugin.Client
 	pid            int
 	processTracker resource.ProcessTracker
+	// Reports the resource usage of the plugin's process. Nil if the client
+	// doesn't own the plugin's process.
+	processMetrics resource.ProcessCollector
 
 	messenger            *messenger.Server
 	keystore             *gkeystore.Server
@@ -98,11 +124,121 @@ type VMClient struct {
 	snLookup             *gsubnetlookup.Server
 	appSender            *appsender.Server
 	validatorStateServer *gvalidators.Server
+	// nil if the node doesn't serve the chain a signature aggregator
+	signatureAggregator *gsignatureaggregator.Server
+	// nil if the node doesn't serve the chain a blob service
+	blobs *gblob.Server
 
 	serverCloser grpcutils.ServerCloser
 	conns        []*grpc.ClientConn
+	// Clients of the HTTP handlers of the plugin, which own their connections
+	handlers []*ghttp.Client
+
+	// Health services of the handlers of the plugin, probed by HealthCheck
+	healthProbes healthProbes
+
+	// If true, the servers the plugin connects to are multiplexed over a
+	// single connection
+	multiplex bool
+	mux       *grpcutils.Mux
+
+	// If true, the servers the plugin connects to only accept connections
+	// authenticated by an ephemeral certificate given to the plugin
+	mtls        bool
+	serverCreds credentials.TransportCredentials
+
+	// If true, the servers the plugin connects to listen on unix domain
+	// sockets rather than TCP loopback
+	unixSockets bool
+
+	// Number of pages of elements the iterators of the plugin's databases
+	// fetch ahead of their consumer. If 0, the iterators fetch each page with
+	// a separate request.
+	dbIteratorPrefetch int
+	// Max size, in bytes, of the elements the plugin sends in a single request
+	// when writing a batch to its databases. If 0, values are never split.
+	dbMaxBatchBytes int
+	// If true, the plugin sends and requests a checksum with each value
+	// written to and read from its databases.
+	dbChecksums bool
+	// Max duration the pending requests of the plugin to the servers of the
+	// node are given to finish on shutdown, before the plugin is killed. If
+	// 0, the pending requests are cancelled on shutdown.
+	shutdownDrainTimeout time.Duration
+	// Retries the calls to the plugin that fail because the plugin is
+	// unreachable, if they don't change the state of the plugin.
+	retryConfig  RetryConfig
+	retryMetrics retryMetrics
+	// If set, the resources of the plugin process are limited by this group,
+	// which is removed on shutdown.
+	resourceGroup resource.Group
+	// Factories of the services served to the plugin alongside the services
+	// every plugin is served, which are created when the VM is initialized.
+	pluginServiceFactories map[string]PluginServiceFactory
+	pluginServices         []PluginService
+
+	// If set, the plugin is managed externally: the client neither owns its
+	// process nor shares a host with it. The servers the plugin connects to
+	// listen on [listenHost], and the connections between the node and the
+	// plugin use [dialOpts] and [serverCreds].
+	externalConn *grpc.ClientConn
+	listenHost   string
+	dialOpts     []grpc.DialOption
+
+	// If set, tunes the connections between the node and the plugin
+	grpcConfig *grpcutils.Config
+	// If set, sizes the caches of the blocks of the chain
+	cacheConfig *CacheConfig
+
+	// If set, the pacing of the blocks the plugin is asked to build
+	buildPacing *block.BuildPacing
+
+	// If set, the calls between the node and the plugin are traced, and their
+	// spans are given [tracingAttrs]
+	tracer       trace.Tracer
+	tracingAttrs []attribute.KeyValue
+
+	// Delivers gossip messages to the plugin. Nil if the plugin doesn't
+	// support gossip streams.
+	gossip *gossipStream
 
 	grpcServerMetrics *grpc_prometheus.ServerMetrics
+	// Latency of the calls made to the plugin's vm server, such as
+	// BuildBlock, ParseBlock and GetBlock
+	grpcClientMetrics *grpc_prometheus.ClientMetrics
+
+	// Optional features of the plugin, reported in the handshake. Every
+	// feature is assumed until the handshake.
+	capabilities Capabilities
+
+	// Decisions made on blocks during BatchDecisions that haven't been sent to
+	// the plugin yet
+	batchingDecisions bool
+	pendingDecisions  decisionBatch
+
+	// If set, the plugin is restarted when its process exits. The connection
+	// to the plugin is then replaced, and the requests the plugin was given
+	// are replayed.
+	conn           *pluginConn
+	pluginPath     string
+	relaunch       func(path string) (grpc.ClientConnInterface, error)
+	closed         chan struct{}
+	recovering     utils.AtomicBool
+	restartMetrics restartMetrics
+	initRequest    *vmpb.InitializeRequest
+	state          snow.State
+	stateSet       bool
+	preferred      ids.ID
+	peers          map[ids.NodeID]*version.Application
+
+	// Number of times the plugin was restarted and whether it's being
+	// restarted. Accessed under the context lock.
+	restarts   uint64
+	restarting bool
+	// Set if a restarted plugin couldn't resume the chain
+	failed utils.AtomicBool
+	// Called with the reason the plugin failed, if set
+	onFailed utils.AtomicInterface
 
 	ctx *snow.Context
 }
@@ -110,7 +246,8 @@ type VMClient struct {
 // NewClient returns a VM connected to a remote VM
 func NewClient(client vmpb.VMClient) *VMClient {
 	return &VMClient{
-		client: client,
+		client:       client,
+		capabilities: legacyCapabilities,
 	}
 }
 
@@ -121,6 +258,11 @@ func (vm *VMClient) SetProcess(ctx *snow.Context, proc *plugin.Client, processTr
 	vm.processTracker = processTracker
 	vm.pid = proc.ReattachConfig().Pid
 	processTracker.TrackProcess(vm.pid)
+
+	if vm.processMetrics == nil {
+		vm.processMetrics = resource.NewProcessCollector("plugin")
+	}
+	vm.processMetrics.SetProcess(vm.pid)
 }
 
 func (vm *VMClient) Initialize(
@@ -134,8 +276,21 @@ func (vm *VMClient) Initialize(
 	fxs []*common.Fx,
 	appSender common.AppSender,
 ) error {
-	if len(fxs) != 0 {
-		return errUnsupportedFXs
+	vm.traceConn(chainCtx.ChainID)
+	if err := vm.handshake(ctx); err != nil {
+		return err
+	}
+	chainCtx.Log.Info("plugin handshake completed",
+		zap.Stringer("capabilities", vm.capabilities),
+	)
+
+	if err := vm.verifyFxs(ctx, fxs); err != nil {
+		return err
+	}
+	fxIDs := make([][]byte, len(fxs))
+	for i, fx := range fxs {
+		fxID := fx.ID
+		fxIDs[i] = fxID[:]
 	}
 
 	vm.ctx = chainCtx
@@ -143,10 +298,61 @@ func (vm *VMClient) Initialize(
 	// Register metrics
 	registerer := prometheus.NewRegistry()
 	multiGatherer := metrics.NewMultiGatherer()
-	vm.grpcServerMetrics = grpc_prometheus.NewServerMetrics()
+	// The gRPC metrics of every chain have the same names, so they are
+	// labeled with the chain to be told apart once aggregated.
+	chainLabels := grpcMetricsLabels(chainCtx)
+	vm.grpcServerMetrics = grpc_prometheus.NewServerMetrics(
+		grpc_prometheus.WithConstLabels(chainLabels),
+	)
 	if err := registerer.Register(vm.grpcServerMetrics); err != nil {
 		return err
 	}
+	vm.grpcClientMetrics = grpc_prometheus.NewClientMetrics(
+		grpc_prometheus.WithConstLabels(chainLabels),
+	)
+	vm.grpcClientMetrics.EnableClientHandlingTimeHistogram(
+		grpc_prometheus.WithHistogramConstLabels(chainLabels),
+	)
+	if err := registerer.Register(vm.grpcClientMetrics); err != nil {
+		return err
+	}
+	if vm.conn != nil && vm.retryConfig.MaxAttempts > 1 {
+		if err := vm.retryMetrics.Initialize(registerer); err != nil {
+			return err
+		}
+		// Every attempt of a retried call is measured by the interceptors
+		// added after this one.
+		vm.conn.addInterceptors(
+			retryUnaryClientInterceptor(vm.retryConfig, &vm.retryMetrics),
+			nil,
+		)
+	}
+	if vm.conn != nil {
+		vm.conn.addInterceptors(
+			vm.grpcClientMetrics.UnaryClientInterceptor(),
+			vm.grpcClientMetrics.StreamClientInterceptor(),
+		)
+	}
+	if err := vm.restartMetrics.Initialize(registerer); err != nil {
+		return err
+	}
+	if vm.processMetrics != nil {
+		if err := registerer.Register(vm.processMetrics); err != nil {
+			return err
+		}
+	}
+	if vm.resourceGroup != nil {
+		if err := registerer.Register(vm.resourceGroup); err != nil {
+			return err
+		}
+	}
+	cacheConfig := defaultCacheConfig()
+	if vm.cacheConfig != nil {
+		cacheConfig = *vm.cacheConfig
+	}
+	if err := registerCacheSizes(registerer, cacheConfig); err != nil {
+		return err
+	}
 	if err := multiGatherer.Register("rpcchainvm", registerer); err != nil {
 		return err
 	}
@@ -154,20 +360,54 @@ func (vm *VMClient) Initialize(
 		return err
 	}
 
+	// If enabled, only accept connections from the plugin
+	var tlsServerCert, tlsClientCert, tlsClientKey []byte
+	if vm.mtls {
+		var err error
+		tlsServerCert, tlsClientCert, tlsClientKey, err = vm.initServerCreds()
+		if err != nil {
+			return err
+		}
+	}
+
+	// If enabled, serve every server over a single listener
+	var muxAddr string
+	if vm.multiplex {
+		muxListener, err := vm.listen()
+		if err != nil {
+			return err
+		}
+		vm.mux = grpcutils.NewMux(muxListener)
+		muxAddr = vm.mux.Addr().String()
+
+		go func() {
+			// There is nothing to do with the error when the mux is closed.
+			_ = vm.mux.Serve()
+		}()
+		vm.ctx.Log.Info("grpc: multiplexing vm servers",
+			zap.String("address", muxAddr),
+		)
+	}
+
 	// Initialize and serve each database and construct the db manager
 	// initialize request parameters
 	versionedDBs := dbManager.GetDatabases()
 	versionedDBServers := make([]*vmpb.VersionedDBServer, len(versionedDBs))
+	checksumMismatches, err := rpcdb.NewChecksumMismatchCounter(registerer)
+	if err != nil {
+		return err
+	}
 	for i, semDB := range versionedDBs {
-		db := rpcdb.NewServer(semDB.Database)
+		db := rpcdb.NewServerWithConfig(semDB.Database, rpcdb.ServerConfig{
+			ChecksumMismatches: checksumMismatches,
+		})
 		dbVersion := semDB.Version.String()
-		serverListener, err := grpcutils.NewListener()
+		serverListener, serverAddr, err := vm.newListener(dbMuxNamePrefix + dbVersion)
 		if err != nil {
 			return err
 		}
-		serverAddr := serverListener.Addr().String()
 
-		go grpcutils.Serve(serverListener, vm.getDBServerFunc(db))
+		go serveWithHealth(serverListener, vm.getDBServerFunc(db))
 		vm.ctx.Log.Info("grpc: serving database",
 			zap.String("version", dbVersion),
 			zap.String("address", serverAddr),
@@ -179,41 +419,68 @@ func (vm *VMClient) Initialize(
 		}
 	}
 
-	vm.messenger = messenger.NewServer(toEngine)
-	vm.keystore = gkeystore.NewServer(chainCtx.Keystore)
+	messengerServer, err := messenger.NewServer(toEngine, registerer)
+	if err != nil {
+		return err
+	}
+	vm.messenger = messengerServer
+	vm.keystore = gkeystore.NewServer(chainCtx.Keystore, vm.unixSockets)
 	vm.sharedMemory = gsharedmemory.NewServer(chainCtx.SharedMemory, dbManager.Current().Database)
 	vm.bcLookup = galiasreader.NewServer(chainCtx.BCLookup)
 	vm.snLookup = gsubnetlookup.NewServer(chainCtx.SNLookup)
 	vm.appSender = appsender.NewServer(appSender)
 	vm.validatorStateServer = gvalidators.NewServer(chainCtx.ValidatorState)
+	if chainCtx.SignatureAggregator != nil {
+		vm.signatureAggregator = gsignatureaggregator.NewServer(chainCtx.SignatureAggregator)
+	}
+	if chainCtx.Blobs != nil {
+		vm.blobs = gblob.NewServer(chainCtx.Blobs)
+	}
+	vm.pluginServices, err = newPluginServices(chainCtx, vm.pluginServiceFactories)
+	if err != nil {
+		return err
+	}
 
-	serverListener, err := grpcutils.NewListener()
+	serverListener, serverAddr, err := vm.newListener(servicesMuxName)
 	if err != nil {
 		return err
 	}
-	serverAddr := serverListener.Addr().String()
 
-	go grpcutils.Serve(serverListener, vm.getInitServer)
+	go serveWithHealth(serverListener, vm.getInitServer)
 	vm.ctx.Log.Info("grpc: serving vm services",
 		zap.String("address", serverAddr),
 	)
 
-	resp, err := vm.client.Initialize(ctx, &vmpb.InitializeRequest{
-		NetworkId:    chainCtx.NetworkID,
-		SubnetId:     chainCtx.SubnetID[:],
-		ChainId:      chainCtx.ChainID[:],
-		NodeId:       chainCtx.NodeID.Bytes(),
-		XChainId:     chainCtx.XChainID[:],
-		AvaxAssetId:  chainCtx.AVAXAssetID[:],
-		GenesisBytes: genesisBytes,
-		UpgradeBytes: upgradeBytes,
-		ConfigBytes:  configBytes,
-		DbServers:    versionedDBServers,
-		ServerAddr:   serverAddr,
-	})
+	initRequest := &vmpb.InitializeRequest{
+		NetworkId:          chainCtx.NetworkID,
+		SubnetId:           chainCtx.SubnetID[:],
+		ChainId:            chainCtx.ChainID[:],
+		NodeId:             chainCtx.NodeID.Bytes(),
+		XChainId:           chainCtx.XChainID[:],
+		AvaxAssetId:        chainCtx.AVAXAssetID[:],
+		GenesisBytes:       genesisBytes,
+		UpgradeBytes:       upgradeBytes,
+		ConfigBytes:        configBytes,
+		DbServers:          versionedDBServers,
+		ServerAddr:         serverAddr,
+		ChainDataDir:       chainCtx.ChainDataDir,
+		FxIds:              fxIDs,
+		MuxAddr:            muxAddr,
+		TlsServerCert:      tlsServerCert,
+		TlsClientCert:      tlsClientCert,
+		TlsClientKey:       tlsClientKey,
+		Compressor:         grpcutils.ZstdCompressorName,
+		GrpcConfig:         grpcConfigToProto(vm.grpcConfig),
+		BuildPacing:        buildPacingToProto(vm.buildPacing),
+		DbIteratorPrefetch: uint32(vm.dbIteratorPrefetch),
+		DbMaxBatchBytes:    uint32(vm.dbMaxBatchBytes),
+		DbChecksums:        vm.dbChecksums,
+	}
+	resp, err := vm.client.Initialize(ctx, initRequest)
 	if err != nil {
 		return err
 	}
+	vm.initRequest = initRequest
 
 	id, err := ids.ToID(resp.LastAcceptedId)
 	if err != nil {
@@ -237,19 +504,23 @@ func (vm *VMClient) Initialize(
 		bytes:    resp.Bytes,
 		height:   resp.Height,
 		time:     time,
+		oracle:   resp.LastAcceptedOracle,
 	}
 
 	chainState, err := chain.NewMeteredState(
 		registerer,
 		&chain.Config{
-			DecidedCacheSize:    decidedCacheSize,
-			MissingCacheSize:    missingCacheSize,
-			UnverifiedCacheSize: unverifiedCacheSize,
-			BytesToIDCacheSize:  bytesToIDCacheSize,
+			DecidedCacheSize:    cacheConfig.Decided,
+			MissingCacheSize:    cacheConfig.Missing,
+			UnverifiedCacheSize: cacheConfig.Unverified,
+			BytesToIDCacheSize:  cacheConfig.BytesToID,
 			LastAcceptedBlock:   lastAcceptedBlk,
 			GetBlock:            vm.getBlock,
+			BatchedGetBlock:     vm.batchedGetBlock,
 			UnmarshalBlock:      vm.parseBlock,
 			BuildBlock:          vm.buildBlock,
+
+			BuildBlockWithContext: vm.buildBlockWithContext,
 		},
 	)
 	if err != nil {
@@ -257,33 +528,129 @@ func (vm *VMClient) Initialize(
 	}
 	vm.State = chainState
 
+	vm.gossip, err = vm.openGossipStream()
+	if err != nil {
+		return err
+	}
+
+	if vm.relaunch != nil && vm.proc != nil {
+		vm.closed = make(chan struct{})
+		go vm.monitor()
+	}
+
 	return vm.ctx.Metrics.Register(multiGatherer)
 }
 
+// verifyFxs returns an error if the plugin can't be initialized with [fxs].
+// The plugin creates its own instance of each of the feature extensions.
+func (vm *VMClient) verifyFxs(ctx context.Context, fxs []*common.Fx) error {
+	if len(fxs) == 0 {
+		return nil
+	}
+
+	resp, err := vm.client.SupportedFxs(ctx, &emptypb.Empty{})
+	if status.Code(err) == codes.Unimplemented {
+		// The plugin predates feature extension support.
+		return errUnsupportedFXs
+	}
+	if err != nil {
+		return err
+	}
+
+	supportedFxs := ids.NewSet(len(resp.FxIds))
+	for _, fxIDBytes := range resp.FxIds {
+		fxID, err := ids.ToID(fxIDBytes)
+		if err != nil {
+			return err
+		}
+		supportedFxs.Add(fxID)
+	}
+	for _, fx := range fxs {
+		if !supportedFxs.Contains(fx.ID) {
+			return fmt.Errorf("%w: %s", errUnsupportedFXs, fx.ID)
+		}
+	}
+	return nil
+}
+
+// ConfigSchema returns nil if the plugin doesn't describe its chain configs.
+func (vm *VMClient) ConfigSchema(ctx context.Context) ([]byte, error) {
+	resp, err := vm.client.ConfigSchema(ctx, &emptypb.Empty{})
+	if status.Code(err) == codes.Unimplemented {
+		// The plugin predates chain config schemas.
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return resp.Schema, nil
+}
+
+// initServerCreds generates the ephemeral certificates of the servers of the
+// node and of the plugin, and requires the plugin to authenticate with its
+// certificate to connect to the servers of the node. It returns the server
+// certificate, the plugin certificate and the private key of the plugin
+// certificate.
+func (vm *VMClient) initServerCreds() ([]byte, []byte, []byte, error) {
+	serverCert, err := grpcutils.NewEphemeralCert()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	clientCert, err := grpcutils.NewEphemeralCert()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	clientKey, err := grpcutils.MarshalEphemeralKey(clientCert)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	tlsConfig := grpcutils.NewPinnedTLSConfig(serverCert, clientCert.Certificate[0])
+	vm.serverCreds = credentials.NewTLS(tlsConfig)
+	return serverCert.Certificate[0], clientCert.Certificate[0], clientKey, nil
+}
+
+// newListener returns a listener for a server named [name] and the address
+// the plugin reaches it at. If the servers are multiplexed, the address is the
+// name of the server.
+func (vm *VMClient) newListener(name string) (net.Listener, string, error) {
+	if vm.mux != nil {
+		return vm.mux.Listen(name), name, nil
+	}
+	listener, err := vm.listen()
+	if err != nil {
+		return nil, "", err
+	}
+	return listener, listener.Addr().String(), nil
+}
+
+// listen returns a listener on a new address the plugin can reach.
+func (vm *VMClient) listen() (net.Listener, error) {
+	if vm.listenHost == "" {
+		return grpcutils.NewLocalListener(vm.unixSockets)
+	}
+	return net.Listen("tcp", net.JoinHostPort(vm.listenHost, "0"))
+}
+
 func (vm *VMClient) getDBServerFunc(db rpcdbpb.DatabaseServer) func(opts []grpc.ServerOption) *grpc.Server { // #nolint
 	return func(opts []grpc.ServerOption) *grpc.Server {
-		if len(opts) == 0 {
-			opts = append(opts, grpcutils.DefaultServerOptions...)
-		}
+		opts = vm.grpcConfig.ServerOptions(opts...)
 
 		// Collect gRPC serving metrics
 		opts = append(opts, grpc.UnaryInterceptor(vm.grpcServerMetrics.UnaryServerInterceptor()))
 		opts = append(opts, grpc.StreamInterceptor(vm.grpcServerMetrics.StreamServerInterceptor()))
+		opts = append(opts, vm.serverCloser.StreamServerOption())
+		opts = append(opts, vm.tracingServerOptions()...)
+		if vm.serverCreds != nil {
+			opts = append(opts, grpc.Creds(vm.serverCreds))
+		}
 
 		server := grpc.NewServer(opts...)
 
-		grpcHealth := health.NewServer()
-		// The server should use an empty string as the key for server's overall
-		// health status.
-		// See https://github.com/grpc/grpc/blob/master/doc/health-checking.md
-		grpcHealth.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
-
 		vm.serverCloser.Add(server)
 
 		// register database service
 		rpcdbpb.RegisterDatabaseServer(server, db)
-		// register health service
-		healthpb.RegisterHealthServer(server, grpcHealth)
 
 		// Ensure metric counters are zeroed on restart
 		grpc_prometheus.Register(server)
@@ -292,23 +659,34 @@ func (vm *VMClient) getDBServerFunc(db rpcdbpb.DatabaseServer) func(opts []grpc.
 	}
 }
 
-func (vm *VMClient) getInitServer(opts []grpc.ServerOption) *grpc.Server {
-	if len(opts) == 0 {
-		opts = append(opts, grpcutils.DefaultServerOptions...)
+// grpcMetricsLabels returns the labels of the gRPC metrics of the chain of
+// [ctx]. The alias of a chain without aliases is its ID.
+func grpcMetricsLabels(ctx *snow.Context) prometheus.Labels {
+	chainID := ctx.ChainID.String()
+	alias, err := ctx.BCLookup.PrimaryAlias(ctx.ChainID)
+	if err != nil {
+		alias = chainID
+	}
+	return prometheus.Labels{
+		"chainID":    chainID,
+		"chainAlias": alias,
 	}
+}
+
+func (vm *VMClient) getInitServer(opts []grpc.ServerOption) *grpc.Server {
+	opts = vm.grpcConfig.ServerOptions(opts...)
 
 	// Collect gRPC serving metrics
 	opts = append(opts, grpc.UnaryInterceptor(vm.grpcServerMetrics.UnaryServerInterceptor()))
 	opts = append(opts, grpc.StreamInterceptor(vm.grpcServerMetrics.StreamServerInterceptor()))
+	opts = append(opts, vm.serverCloser.StreamServerOption())
+	opts = append(opts, vm.tracingServerOptions()...)
+	if vm.serverCreds != nil {
+		opts = append(opts, grpc.Creds(vm.serverCreds))
+	}
 
 	server := grpc.NewServer(opts...)
 
-	grpcHealth := health.NewServer()
-	// The server should use an empty string as the key for server's overall
-	// health status.
-	// See https://github.com/grpc/grpc/blob/master/doc/health-checking.md
-	grpcHealth.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
-
 	vm.serverCloser.Add(server)
 
 	// register the services
@@ -318,8 +696,16 @@ func (vm *VMClient) getInitServer(opts []grpc.ServerOption) *grpc.Server {
 	aliasreaderpb.RegisterAliasReaderServer(server, vm.bcLookup)
 	subnetlookuppb.RegisterSubnetLookupServer(server, vm.snLookup)
 	appsenderpb.RegisterAppSenderServer(server, vm.appSender)
-	healthpb.RegisterHealthServer(server, grpcHealth)
 	validatorstatepb.RegisterValidatorStateServer(server, vm.validatorStateServer)
+	if vm.signatureAggregator != nil {
+		signatureaggregatorpb.RegisterSignatureAggregatorServer(server, vm.signatureAggregator)
+	}
+	if vm.blobs != nil {
+		blobpb.RegisterBlobServer(server, vm.blobs)
+	}
+	for _, service := range vm.pluginServices {
+		server.RegisterService(service.Desc, service.Server)
+	}
 
 	// Ensure metric counters are zeroed on restart
 	grpc_prometheus.Register(server)
@@ -334,6 +720,8 @@ func (vm *VMClient) SetState(ctx context.Context, state snow.State) error {
 	if err != nil {
 		return err
 	}
+	vm.state = state
+	vm.stateSet = true
 
 	id, err := ids.ToID(resp.LastAcceptedId)
 	if err != nil {
@@ -358,21 +746,60 @@ func (vm *VMClient) SetState(ctx context.Context, state snow.State) error {
 		bytes:    resp.Bytes,
 		height:   resp.Height,
 		time:     time,
+		oracle:   resp.LastAcceptedOracle,
 	})
 }
 
 func (vm *VMClient) Shutdown(ctx context.Context) error {
+	if vm.closed != nil {
+		close(vm.closed)
+	}
+	if vm.gossip != nil {
+		vm.gossip.Close()
+	}
+	if vm.messenger != nil {
+		vm.messenger.Close()
+	}
+
 	errs := wrappers.Errs{}
 	_, err := vm.client.Shutdown(ctx, &emptypb.Empty{})
 	errs.Add(err)
 
-	vm.serverCloser.Stop()
+	// The plugin may still be writing to its databases, such as if it didn't
+	// shut down in time, so its pending requests are given the chance to
+	// finish rather than being interrupted mid-write.
+	if vm.shutdownDrainTimeout > 0 {
+		if !vm.serverCloser.Drain(vm.shutdownDrainTimeout) && vm.ctx != nil {
+			vm.ctx.Log.Warn("cancelled pending requests of the plugin",
+				zap.Duration("drainTimeout", vm.shutdownDrainTimeout),
+			)
+		}
+	} else {
+		vm.serverCloser.Stop()
+	}
+	if vm.mux != nil {
+		errs.Add(vm.mux.Close())
+	}
 	for _, conn := range vm.conns {
 		errs.Add(conn.Close())
 	}
+	for _, handler := range vm.handlers {
+		errs.Add(handler.Close())
+	}
 
-	vm.proc.Kill()
-	vm.processTracker.UntrackProcess(vm.pid)
+	if vm.externalConn != nil {
+		errs.Add(vm.externalConn.Close())
+	}
+
+	// Externally managed plugins aren't owned by the client.
+	if vm.proc != nil {
+		vm.proc.Kill()
+		vm.processTracker.UntrackProcess(vm.pid)
+		vm.processMetrics.SetProcess(0)
+	}
+	if vm.resourceGroup != nil {
+		errs.Add(vm.resourceGroup.Close())
+	}
 	return errs.Err
 }
 
@@ -383,21 +810,37 @@ func (vm *VMClient) CreateHandlers(ctx context.Context) (map[string]*common.HTTP
 	}
 
 	handlers := make(map[string]*common.HTTPHandler, len(resp.Handlers))
+	dialOpts := vm.handlerDialOpts()
 	for _, handler := range resp.Handlers {
-		clientConn, err := grpcutils.Dial(handler.ServerAddr)
+		clientConn, err := grpcutils.Dial(handler.ServerAddr, dialOpts...)
 		if err != nil {
 			return nil, err
 		}
 
-		vm.conns = append(vm.conns, clientConn)
+		handlerClient := ghttp.NewConnClient(clientConn, vm.unixSockets)
+		vm.handlers = append(vm.handlers, handlerClient)
+		vm.healthProbes.add("handler/"+handler.Prefix, clientConn)
 		handlers[handler.Prefix] = &common.HTTPHandler{
-			LockOptions: common.LockOption(handler.LockOptions),
-			Handler:     ghttp.NewClient(httppb.NewHTTPClient(clientConn)),
+			LockOptions:   common.LockOption(handler.LockOptions),
+			Handler:       handlerClient,
+			CachedMethods: handler.CachedMethods,
+			OpenAPI:       handler.Openapi,
 		}
 	}
 	return handlers, nil
 }
 
+// handlerDialOpts returns the options to dial the HTTP handlers of the plugin.
+func (vm *VMClient) handlerDialOpts() []grpc.DialOption {
+	dialOpts := vm.grpcConfig.DialOptions(vm.dialOpts...)
+	dialOpts = append(dialOpts, vm.tracingDialOptions()...)
+	compressor := vm.compressor()
+	if compressor == "" {
+		return dialOpts
+	}
+	return grpcutils.WithCompression(compressor, dialOpts...)
+}
+
 func (vm *VMClient) CreateStaticHandlers(ctx context.Context) (map[string]*common.HTTPHandler, error) {
 	resp, err := vm.client.CreateStaticHandlers(ctx, &emptypb.Empty{})
 	if err != nil {
@@ -405,16 +848,21 @@ func (vm *VMClient) CreateStaticHandlers(ctx context.Context) (map[string]*commo
 	}
 
 	handlers := make(map[string]*common.HTTPHandler, len(resp.Handlers))
+	dialOpts := vm.handlerDialOpts()
 	for _, handler := range resp.Handlers {
-		clientConn, err := grpcutils.Dial(handler.ServerAddr)
+		clientConn, err := grpcutils.Dial(handler.ServerAddr, dialOpts...)
 		if err != nil {
 			return nil, err
 		}
 
-		vm.conns = append(vm.conns, clientConn)
+		handlerClient := ghttp.NewConnClient(clientConn, vm.unixSockets)
+		vm.handlers = append(vm.handlers, handlerClient)
+		vm.healthProbes.add("staticHandler/"+handler.Prefix, clientConn)
 		handlers[handler.Prefix] = &common.HTTPHandler{
-			LockOptions: common.LockOption(handler.LockOptions),
-			Handler:     ghttp.NewClient(httppb.NewHTTPClient(clientConn)),
+			LockOptions:   common.LockOption(handler.LockOptions),
+			Handler:       handlerClient,
+			CachedMethods: handler.CachedMethods,
+			OpenAPI:       handler.Openapi,
 		}
 	}
 	return handlers, nil
@@ -425,14 +873,27 @@ func (vm *VMClient) Connected(ctx context.Context, nodeID ids.NodeID, nodeVersio
 		NodeId:  nodeID[:],
 		Version: nodeVersion.String(),
 	})
-	return err
+	if err != nil {
+		return err
+	}
+
+	if vm.peers == nil {
+		vm.peers = make(map[ids.NodeID]*version.Application)
+	}
+	vm.peers[nodeID] = nodeVersion
+	return nil
 }
 
 func (vm *VMClient) Disconnected(ctx context.Context, nodeID ids.NodeID) error {
 	_, err := vm.client.Disconnected(ctx, &vmpb.DisconnectedRequest{
 		NodeId: nodeID[:],
 	})
-	return err
+	if err != nil {
+		return err
+	}
+
+	delete(vm.peers, nodeID)
+	return nil
 }
 
 func (vm *VMClient) buildBlock(ctx context.Context) (snowman.Block, error) {
@@ -440,7 +901,24 @@ func (vm *VMClient) buildBlock(ctx context.Context) (snowman.Block, error) {
 	if err != nil {
 		return nil, err
 	}
+	return vm.newBlockFromBuildResponse(resp)
+}
+
+func (vm *VMClient) buildBlockWithContext(ctx context.Context, blockCtx *block.Context) (snowman.Block, error) {
+	if !vm.capabilities.Has(CapabilityBlockContext) {
+		return vm.buildBlock(ctx)
+	}
+
+	resp, err := vm.client.BuildBlockWithContext(ctx, &vmpb.BuildBlockWithContextRequest{
+		PChainHeight: blockCtx.PChainHeight,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return vm.newBlockFromBuildResponse(resp)
+}
 
+func (vm *VMClient) newBlockFromBuildResponse(resp *vmpb.BuildBlockResponse) (snowman.Block, error) {
 	id, err := ids.ToID(resp.Id)
 	if err != nil {
 		return nil, err
@@ -453,13 +931,15 @@ func (vm *VMClient) buildBlock(ctx context.Context) (snowman.Block, error) {
 
 	time, err := grpcutils.TimestampAsTime(resp.Timestamp)
 	return &blockClient{
-		vm:       vm,
-		id:       id,
-		parentID: parentID,
-		status:   choices.Processing,
-		bytes:    resp.Bytes,
-		height:   resp.Height,
-		time:     time,
+		vm:                vm,
+		id:                id,
+		parentID:          parentID,
+		status:            choices.Processing,
+		bytes:             resp.Bytes,
+		height:            resp.Height,
+		time:              time,
+		verifyWithContext: resp.VerifyWithContext,
+		oracle:            resp.Oracle,
 	}, err
 }
 
@@ -488,13 +968,15 @@ func (vm *VMClient) parseBlock(ctx context.Context, bytes []byte) (snowman.Block
 
 	time, err := grpcutils.TimestampAsTime(resp.Timestamp)
 	return &blockClient{
-		vm:       vm,
-		id:       id,
-		parentID: parentID,
-		status:   status,
-		bytes:    bytes,
-		height:   resp.Height,
-		time:     time,
+		vm:                vm,
+		id:                id,
+		parentID:          parentID,
+		status:            status,
+		bytes:             bytes,
+		height:            resp.Height,
+		time:              time,
+		verifyWithContext: resp.VerifyWithContext,
+		oracle:            resp.Oracle,
 	}, err
 }
 
@@ -508,7 +990,81 @@ func (vm *VMClient) getBlock(ctx context.Context, blkID ids.ID) (snowman.Block,
 	if errCode := resp.Err; errCode != 0 {
 		return nil, errCodeToError[errCode]
 	}
+	return vm.newBlockFromResponse(blkID, resp)
+}
+
+// batchedGetBlock fetches [blkIDs] with a single request. If blkIDs[i]
+// couldn't be fetched, the i-th returned block is nil and the i-th returned
+// error is why.
+func (vm *VMClient) batchedGetBlock(ctx context.Context, blkIDs []ids.ID) ([]snowman.Block, []error, error) {
+	if !vm.capabilities.Has(CapabilityBatchedChainVM) {
+		blks, errs := vm.getBlocks(ctx, blkIDs)
+		return blks, errs, nil
+	}
+
+	blkIDsBytes := make([][]byte, len(blkIDs))
+	for i, blkID := range blkIDs {
+		blkID := blkID
+		blkIDsBytes[i] = blkID[:]
+	}
+	resp, err := vm.client.BatchedGetBlock(ctx, &vmpb.BatchedGetBlockRequest{
+		Ids: blkIDsBytes,
+	})
+	if status.Code(err) == codes.Unimplemented {
+		// The plugin predates BatchedGetBlock, so request the blocks one at a
+		// time.
+		blks, errs := vm.getBlocks(ctx, blkIDs)
+		return blks, errs, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(blkIDs) != len(resp.Response) || len(resp.Errors) != 0 && len(blkIDs) != len(resp.Errors) {
+		return nil, nil, errBatchedGetBlockWrongNumberOfBlocks
+	}
+
+	var (
+		blks = make([]snowman.Block, len(blkIDs))
+		errs = make([]error, len(blkIDs))
+	)
+	for i, blkResp := range resp.Response {
+		if len(resp.Errors) != 0 && resp.Errors[i] != "" {
+			errs[i] = errors.New(resp.Errors[i])
+			continue
+		}
+		if errCode := blkResp.Err; errCode != 0 {
+			errs[i] = errCodeToError[errCode]
+			continue
+		}
+		blk, err := vm.newBlockFromResponse(blkIDs[i], blkResp)
+		if err != nil {
+			errs[i] = err
+			continue
+		}
+		blks[i] = blk
+	}
+	return blks, errs, nil
+}
+
+// getBlocks fetches [blkIDs] one at a time, with the results of
+// batchedGetBlock.
+func (vm *VMClient) getBlocks(ctx context.Context, blkIDs []ids.ID) ([]snowman.Block, []error) {
+	var (
+		blks = make([]snowman.Block, len(blkIDs))
+		errs = make([]error, len(blkIDs))
+	)
+	for i, blkID := range blkIDs {
+		blk, err := vm.getBlock(ctx, blkID)
+		if err != nil {
+			errs[i] = err
+			continue
+		}
+		blks[i] = blk
+	}
+	return blks, errs
+}
 
+func (vm *VMClient) newBlockFromResponse(blkID ids.ID, resp *vmpb.GetBlockResponse) (snowman.Block, error) {
 	parentID, err := ids.ToID(resp.ParentId)
 	if err != nil {
 		return nil, err
@@ -521,13 +1077,15 @@ func (vm *VMClient) getBlock(ctx context.Context, blkID ids.ID) (snowman.Block,
 
 	time, err := grpcutils.TimestampAsTime(resp.Timestamp)
 	return &blockClient{
-		vm:       vm,
-		id:       blkID,
-		parentID: parentID,
-		status:   status,
-		bytes:    resp.Bytes,
-		height:   resp.Height,
-		time:     time,
+		vm:                vm,
+		id:                blkID,
+		parentID:          parentID,
+		status:            status,
+		bytes:             resp.Bytes,
+		height:            resp.Height,
+		time:              time,
+		verifyWithContext: resp.VerifyWithContext,
+		oracle:            resp.Oracle,
 	}, err
 }
 
@@ -535,16 +1093,35 @@ func (vm *VMClient) SetPreference(ctx context.Context, blkID ids.ID) error {
 	_, err := vm.client.SetPreference(ctx, &vmpb.SetPreferenceRequest{
 		Id: blkID[:],
 	})
-	return err
+	if err != nil {
+		return err
+	}
+
+	vm.preferred = blkID
+	return nil
 }
 
+// HealthCheck returns the health reported by the plugin, along with the health
+// of each component the node and the plugin communicate through. The
+// components are probed even if the plugin fails to report its health.
 func (vm *VMClient) HealthCheck(ctx context.Context) (interface{}, error) {
+	report := healthReport{
+		Components: vm.probeComponents(ctx),
+	}
+	if vm.failed.GetValue() {
+		return report, fmt.Errorf("health check failed, the chain must be bootstrapped again: %w", errLastAcceptedMismatch)
+	}
+	if vm.recovering.GetValue() {
+		return report, fmt.Errorf("health check failed: %w", errRecovering)
+	}
+
 	health, err := vm.client.Health(ctx, &emptypb.Empty{})
 	if err != nil {
-		return nil, fmt.Errorf("health check failed: %w", err)
+		return report, fmt.Errorf("health check failed: %w", err)
 	}
 
-	return json.RawMessage(health.Details), nil
+	report.VM = health.Details
+	return report, unhealthyComponentsErr(report.Components)
 }
 
 func (vm *VMClient) Version(ctx context.Context) (string, error) {
@@ -617,17 +1194,28 @@ func (vm *VMClient) AppResponse(ctx context.Context, nodeID ids.NodeID, requestI
 }
 
 func (vm *VMClient) AppRequestFailed(ctx context.Context, nodeID ids.NodeID, requestID uint32) error {
+	return vm.AppRequestFailedWithError(ctx, nodeID, requestID, common.ErrUndefined)
+}
+
+func (vm *VMClient) AppRequestFailedWithError(ctx context.Context, nodeID ids.NodeID, requestID uint32, appErr *common.AppError) error {
 	_, err := vm.client.AppRequestFailed(
 		ctx,
 		&vmpb.AppRequestFailedMsg{
-			NodeId:    nodeID[:],
-			RequestId: requestID,
+			NodeId:       nodeID[:],
+			RequestId:    requestID,
+			ErrorCode:    appErr.Code,
+			ErrorMessage: appErr.Message,
 		},
 	)
 	return err
 }
 
 func (vm *VMClient) AppGossip(ctx context.Context, nodeID ids.NodeID, msg []byte) error {
+	// If the gossip stream failed, messages are delivered with AppGossip.
+	if vm.gossip != nil && !vm.gossip.Closed() {
+		return vm.gossip.AppGossip(ctx, nodeID, msg)
+	}
+
 	_, err := vm.client.AppGossip(
 		ctx,
 		&vmpb.AppGossipMsg{
@@ -653,6 +1241,9 @@ func (vm *VMClient) GetAncestors(
 	maxBlocksSize int,
 	maxBlocksRetrivalTime time.Duration,
 ) ([][]byte, error) {
+	if !vm.capabilities.Has(CapabilityBatchedChainVM) {
+		return nil, block.ErrRemoteVMNotImplemented
+	}
 	resp, err := vm.client.GetAncestors(ctx, &vmpb.GetAncestorsRequest{
 		BlkId:                 blkID[:],
 		MaxBlocksNum:          int32(maxBlocksNum),
@@ -666,6 +1257,9 @@ func (vm *VMClient) GetAncestors(
 }
 
 func (vm *VMClient) BatchedParseBlock(ctx context.Context, blksBytes [][]byte) ([]snowman.Block, error) {
+	if !vm.capabilities.Has(CapabilityBatchedChainVM) {
+		return nil, block.ErrRemoteVMNotImplemented
+	}
 	resp, err := vm.client.BatchedParseBlock(ctx, &vmpb.BatchedParseBlockRequest{
 		Request: blksBytes,
 	})
@@ -699,13 +1293,15 @@ func (vm *VMClient) BatchedParseBlock(ctx context.Context, blksBytes [][]byte) (
 		}
 
 		res = append(res, &blockClient{
-			vm:       vm,
-			id:       id,
-			parentID: parentID,
-			status:   status,
-			bytes:    blksBytes[idx],
-			height:   blkResp.Height,
-			time:     time,
+			vm:                vm,
+			id:                id,
+			parentID:          parentID,
+			status:            status,
+			bytes:             blksBytes[idx],
+			height:            blkResp.Height,
+			time:              time,
+			verifyWithContext: blkResp.VerifyWithContext,
+			oracle:            blkResp.Oracle,
 		})
 	}
 
@@ -713,6 +1309,9 @@ func (vm *VMClient) BatchedParseBlock(ctx context.Context, blksBytes [][]byte) (
 }
 
 func (vm *VMClient) VerifyHeightIndex(ctx context.Context) error {
+	if !vm.capabilities.Has(CapabilityHeightIndexed) {
+		return block.ErrHeightIndexedVMNotImplemented
+	}
 	resp, err := vm.client.VerifyHeightIndex(ctx, &emptypb.Empty{})
 	if err != nil {
 		return err
@@ -721,6 +1320,9 @@ func (vm *VMClient) VerifyHeightIndex(ctx context.Context) error {
 }
 
 func (vm *VMClient) GetBlockIDAtHeight(ctx context.Context, height uint64) (ids.ID, error) {
+	if !vm.capabilities.Has(CapabilityHeightIndexed) {
+		return ids.Empty, block.ErrHeightIndexedVMNotImplemented
+	}
 	resp, err := vm.client.GetBlockIDAtHeight(
 		ctx,
 		&vmpb.GetBlockIDAtHeightRequest{Height: height},
@@ -735,6 +1337,9 @@ func (vm *VMClient) GetBlockIDAtHeight(ctx context.Context, height uint64) (ids.
 }
 
 func (vm *VMClient) StateSyncEnabled(ctx context.Context) (bool, error) {
+	if !vm.capabilities.Has(CapabilityStateSyncable) {
+		return false, nil
+	}
 	resp, err := vm.client.StateSyncEnabled(ctx, &emptypb.Empty{})
 	if err != nil {
 		return false, err
@@ -747,6 +1352,9 @@ func (vm *VMClient) StateSyncEnabled(ctx context.Context) (bool, error) {
 }
 
 func (vm *VMClient) GetOngoingSyncStateSummary(ctx context.Context) (block.StateSummary, error) {
+	if !vm.capabilities.Has(CapabilityStateSyncable) {
+		return nil, block.ErrStateSyncableVMNotImplemented
+	}
 	resp, err := vm.client.GetOngoingSyncStateSummary(ctx, &emptypb.Empty{})
 	if err != nil {
 		return nil, err
@@ -765,6 +1373,9 @@ func (vm *VMClient) GetOngoingSyncStateSummary(ctx context.Context) (block.State
 }
 
 func (vm *VMClient) GetLastStateSummary(ctx context.Context) (block.StateSummary, error) {
+	if !vm.capabilities.Has(CapabilityStateSyncable) {
+		return nil, block.ErrStateSyncableVMNotImplemented
+	}
 	resp, err := vm.client.GetLastStateSummary(ctx, &emptypb.Empty{})
 	if err != nil {
 		return nil, err
@@ -783,6 +1394,9 @@ func (vm *VMClient) GetLastStateSummary(ctx context.Context) (block.StateSummary
 }
 
 func (vm *VMClient) ParseStateSummary(ctx context.Context, summaryBytes []byte) (block.StateSummary, error) {
+	if !vm.capabilities.Has(CapabilityStateSyncable) {
+		return nil, block.ErrStateSyncableVMNotImplemented
+	}
 	resp, err := vm.client.ParseStateSummary(
 		ctx,
 		&vmpb.ParseStateSummaryRequest{
@@ -806,6 +1420,9 @@ func (vm *VMClient) ParseStateSummary(ctx context.Context, summaryBytes []byte)
 }
 
 func (vm *VMClient) GetStateSummary(ctx context.Context, summaryHeight uint64) (block.StateSummary, error) {
+	if !vm.capabilities.Has(CapabilityStateSyncable) {
+		return nil, block.ErrStateSyncableVMNotImplemented
+	}
 	resp, err := vm.client.GetStateSummary(
 		ctx,
 		&vmpb.GetStateSummaryRequest{
@@ -828,6 +1445,26 @@ func (vm *VMClient) GetStateSummary(ctx context.Context, summaryHeight uint64) (
 	}, err
 }
 
+func (vm *VMClient) DecodeBlock(ctx context.Context, blkBytes []byte) ([]byte, error) {
+	if !vm.capabilities.Has(CapabilityDecoder) {
+		return nil, block.ErrDecoderNotImplemented
+	}
+	resp, err := vm.client.DecodeBlock(ctx, &vmpb.DecodeBlockRequest{
+		Bytes: blkBytes,
+	})
+	if status.Code(err) == codes.Unimplemented {
+		// The plugin predates DecodeBlock.
+		return nil, block.ErrDecoderNotImplemented
+	}
+	if err != nil {
+		return nil, err
+	}
+	if errCode := resp.Err; errCode != 0 {
+		return nil, errCodeToError[errCode]
+	}
+	return resp.Decoded, nil
+}
+
 type blockClient struct {
 	vm *VMClient
 
@@ -837,6 +1474,13 @@ type blockClient struct {
 	bytes    []byte
 	height   uint64
 	time     time.Time
+
+	// verifyWithContext is true if the plugin verifies the block with the
+	// block context provided by the proposervm
+	verifyWithContext bool
+	// oracle is true if the plugin reports the block as an oracle block,
+	// whose options are requested from the plugin
+	oracle bool
 }
 
 func (b *blockClient) ID() ids.ID {
@@ -845,18 +1489,12 @@ func (b *blockClient) ID() ids.ID {
 
 func (b *blockClient) Accept(ctx context.Context) error {
 	b.status = choices.Accepted
-	_, err := b.vm.client.BlockAccept(ctx, &vmpb.BlockAcceptRequest{
-		Id: b.id[:],
-	})
-	return err
+	return b.vm.decide(ctx, b.id, true)
 }
 
 func (b *blockClient) Reject(ctx context.Context) error {
 	b.status = choices.Rejected
-	_, err := b.vm.client.BlockReject(ctx, &vmpb.BlockRejectRequest{
-		Id: b.id[:],
-	})
-	return err
+	return b.vm.decide(ctx, b.id, false)
 }
 
 func (b *blockClient) Status() choices.Status {
@@ -868,17 +1506,94 @@ func (b *blockClient) Parent() ids.ID {
 }
 
 func (b *blockClient) Verify(ctx context.Context) error {
-	resp, err := b.vm.client.BlockVerify(ctx, &vmpb.BlockVerifyRequest{
-		Bytes: b.bytes,
-	})
+	return b.verify(ctx, nil)
+}
+
+func (b *blockClient) ShouldVerifyWithContext(context.Context) (bool, error) {
+	return b.verifyWithContext, nil
+}
+
+func (b *blockClient) VerifyWithContext(ctx context.Context, blockCtx *block.Context) error {
+	return b.verify(ctx, blockCtx)
+}
+
+// verify verifies the block with [blockCtx], or without a block context if
+// [blockCtx] is nil.
+func (b *blockClient) verify(ctx context.Context, blockCtx *block.Context) error {
+	// If the plugin already parsed or built this block, its bytes don't need
+	// to be sent again.
+	resp, err := b.sendVerify(ctx, blockCtx, b.vm.capabilities.Has(CapabilityVerifyByID))
 	if err != nil {
 		return err
 	}
+	if errCode := resp.Err; errCode != 0 {
+		if err := errCodeToError[errCode]; err != database.ErrNotFound {
+			return err
+		}
+		// The plugin no longer has the block, so it is parsed again.
+		resp, err = b.sendVerify(ctx, blockCtx, false)
+		if err != nil {
+			return err
+		}
+	}
 
 	b.time, err = grpcutils.TimestampAsTime(resp.Timestamp)
 	return err
 }
 
+// sendVerify requests the plugin to verify the block. If [byID] is true, the
+// block is identified by its ID rather than by its bytes.
+func (b *blockClient) sendVerify(ctx context.Context, blockCtx *block.Context, byID bool) (*vmpb.BlockVerifyResponse, error) {
+	var blkID, blkBytes []byte
+	if byID {
+		blkID = b.id[:]
+	} else {
+		blkBytes = b.bytes
+	}
+	if blockCtx == nil {
+		return b.vm.client.BlockVerify(ctx, &vmpb.BlockVerifyRequest{
+			Bytes: blkBytes,
+			Id:    blkID,
+		})
+	}
+	return b.vm.client.BlockVerifyWithContext(ctx, &vmpb.BlockVerifyWithContextRequest{
+		Bytes:        blkBytes,
+		Id:           blkID,
+		PChainHeight: blockCtx.PChainHeight,
+	})
+}
+
+// Options returns the options of the block, as reported by the plugin. If the
+// plugin didn't report the block as an oracle block, snowman.ErrNotOracle is
+// returned without requesting the options.
+func (b *blockClient) Options(ctx context.Context) ([2]snowman.Block, error) {
+	if !b.oracle {
+		return [2]snowman.Block{}, snowman.ErrNotOracle
+	}
+
+	resp, err := b.vm.client.BlockOptions(ctx, &vmpb.BlockOptionsRequest{
+		Id: b.id[:],
+	})
+	if err != nil {
+		return [2]snowman.Block{}, err
+	}
+	if errCode := resp.Err; errCode != 0 {
+		return [2]snowman.Block{}, errCodeToError[errCode]
+	}
+	if len(resp.Options) != 2 {
+		return [2]snowman.Block{}, fmt.Errorf("%w: got %d options", errWrongNumberOfOptions, len(resp.Options))
+	}
+
+	var options [2]snowman.Block
+	for i, option := range resp.Options {
+		options[i], err = b.vm.newBlockFromBuildResponse(option)
+		if err != nil {
+			return [2]snowman.Block{}, err
+		}
+	}
+	return options, nil
+}
+
 func (b *blockClient) Bytes() []byte {
 	return b.bytes
 }
@@ -7,6 +7,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"time"
 
 	grpc_prometheus "github.com/grpc-ecosystem/go-grpc-prometheus"
@@ -15,36 +16,47 @@ import (
 	"github.com/prometheus/client_golang/prometheus/collectors"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/protobuf/types/known/emptypb"
 
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+
 	"github.com/ava-labs/avalanchego/api/keystore/gkeystore"
 	"github.com/ava-labs/avalanchego/api/metrics"
 	"github.com/ava-labs/avalanchego/chains/atomic/gsharedmemory"
+	"github.com/ava-labs/avalanchego/database"
 	"github.com/ava-labs/avalanchego/database/corruptabledb"
 	"github.com/ava-labs/avalanchego/database/manager"
 	"github.com/ava-labs/avalanchego/database/rpcdb"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/ids/galiasreader"
 	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
 	"github.com/ava-labs/avalanchego/snow/engine/common"
 	"github.com/ava-labs/avalanchego/snow/engine/common/appsender"
 	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
 	"github.com/ava-labs/avalanchego/snow/validators/gvalidators"
+	"github.com/ava-labs/avalanchego/utils/gc"
 	"github.com/ava-labs/avalanchego/utils/logging"
 	"github.com/ava-labs/avalanchego/utils/wrappers"
 	"github.com/ava-labs/avalanchego/version"
+	"github.com/ava-labs/avalanchego/vms"
+	"github.com/ava-labs/avalanchego/vms/rpcchainvm/gblob"
 	"github.com/ava-labs/avalanchego/vms/rpcchainvm/ghttp"
 	"github.com/ava-labs/avalanchego/vms/rpcchainvm/grpcutils"
+	"github.com/ava-labs/avalanchego/vms/rpcchainvm/gsignatureaggregator"
 	"github.com/ava-labs/avalanchego/vms/rpcchainvm/gsubnetlookup"
 	"github.com/ava-labs/avalanchego/vms/rpcchainvm/messenger"
 
 	aliasreaderpb "github.com/ava-labs/avalanchego/proto/pb/aliasreader"
 	appsenderpb "github.com/ava-labs/avalanchego/proto/pb/appsender"
+	blobpb "github.com/ava-labs/avalanchego/proto/pb/blob"
 	httppb "github.com/ava-labs/avalanchego/proto/pb/http"
 	keystorepb "github.com/ava-labs/avalanchego/proto/pb/keystore"
 	messengerpb "github.com/ava-labs/avalanchego/proto/pb/messenger"
 	rpcdbpb "github.com/ava-labs/avalanchego/proto/pb/rpcdb"
 	sharedmemorypb "github.com/ava-labs/avalanchego/proto/pb/sharedmemory"
+	signatureaggregatorpb "github.com/ava-labs/avalanchego/proto/pb/signatureaggregator"
 	subnetlookuppb "github.com/ava-labs/avalanchego/proto/pb/subnetlookup"
 	validatorstatepb "github.com/ava-labs/avalanchego/proto/pb/validatorstate"
 	vmpb "github.com/ava-labs/avalanchego/proto/pb/vm"
@@ -59,12 +71,40 @@ type VMServer struct {
 	vm   block.ChainVM
 	hVM  block.HeightIndexedChainVM
 	ssVM block.StateSyncableVM
+	dVM  block.Decoder
+	sdVM block.StateDiffer
+	mvVM block.MessageVerifier
+	bbVM block.BuildBlockWithContextChainVM
+
+	// Fx ID -> factory of the feature extension
+	fxs map[ids.ID]vms.Factory
 
 	processMetrics prometheus.Gatherer
 	dbManager      manager.Manager
 
 	serverCloser grpcutils.ServerCloser
 	connCloser   wrappers.Closer
+	// Health services of the servers of the node, probed over the
+	// connections of the vm to them
+	connHealth healthProbes
+
+	// If set, the vm is managed externally rather than by the node: the
+	// servers of the vm listen on [listenHost], and the connections between
+	// the node and the vm are secured with [creds].
+	listenHost string
+	creds      credentials.TransportCredentials
+
+	// If true, the servers of the vm listen on unix domain sockets rather
+	// than TCP loopback
+	unixSockets bool
+
+	// If set, tunes the connections of the vm to the node and the servers
+	// of its handlers
+	grpcConfig *grpcutils.Config
+
+	// If set, forwards the trace context of the calls the vm serves to the
+	// calls it makes to the node
+	propagator *grpcutils.Propagator
 
 	ctx    *snow.Context
 	closed chan struct{}
@@ -72,12 +112,27 @@ type VMServer struct {
 
 // NewServer returns a vm instance connected to a remote vm instance
 func NewServer(vm block.ChainVM) *VMServer {
+	return NewServerWithFxs(vm, nil)
+}
+
+// NewServerWithFxs returns a vm instance connected to a remote vm instance.
+// The vm can be initialized with the feature extensions created by [fxs].
+func NewServerWithFxs(vm block.ChainVM, fxs map[ids.ID]vms.Factory) *VMServer {
 	hVM, _ := vm.(block.HeightIndexedChainVM)
 	ssVM, _ := vm.(block.StateSyncableVM)
+	dVM, _ := vm.(block.Decoder)
+	sdVM, _ := vm.(block.StateDiffer)
+	mvVM, _ := vm.(block.MessageVerifier)
+	bbVM, _ := vm.(block.BuildBlockWithContextChainVM)
 	return &VMServer{
 		vm:   vm,
 		hVM:  hVM,
 		ssVM: ssVM,
+		dVM:  dVM,
+		sdVM: sdVM,
+		mvVM: mvVM,
+		bbVM: bbVM,
+		fxs:  fxs,
 	}
 }
 
@@ -102,6 +157,10 @@ func (vm *VMServer) Initialize(ctx context.Context, req *vmpb.InitializeRequest)
 	if err != nil {
 		return nil, err
 	}
+	vm.grpcConfig, err = grpcConfigFromProto(req.GrpcConfig)
+	if err != nil {
+		return nil, err
+	}
 
 	registerer := prometheus.NewRegistry()
 
@@ -117,6 +176,11 @@ func (vm *VMServer) Initialize(ctx context.Context, req *vmpb.InitializeRequest)
 		return nil, err
 	}
 
+	// Duration of the most recent garbage collection pause
+	if err := registerer.Register(gc.NewCollector()); err != nil {
+		return nil, err
+	}
+
 	// gRPC client metrics
 	grpcClientMetrics := grpc_prometheus.NewClientMetrics()
 	if err := registerer.Register(grpcClientMetrics); err != nil {
@@ -126,6 +190,46 @@ func (vm *VMServer) Initialize(ctx context.Context, req *vmpb.InitializeRequest)
 	// Register metrics for each Go plugin processes
 	vm.processMetrics = registerer
 
+	// If the servers are multiplexed, connect to all of them over a single
+	// connection
+	dial := grpcutils.Dial
+	if req.MuxAddr != "" {
+		muxDialer, err := grpcutils.DialMux(req.MuxAddr)
+		if err != nil {
+			return nil, err
+		}
+		vm.connCloser.Add(muxDialer)
+		dial = muxDialer.Dial
+	}
+
+	dialOpts := grpcutils.DialOptsWithMetrics(grpcClientMetrics)
+	if vm.creds != nil {
+		dialOpts = append(dialOpts, grpc.WithTransportCredentials(vm.creds))
+	}
+	if len(req.TlsServerCert) > 0 {
+		clientCert, err := grpcutils.ParseEphemeralCert(req.TlsClientCert, req.TlsClientKey)
+		if err != nil {
+			// Ignore closing errors to return the original error
+			_ = vm.connCloser.Close()
+			return nil, err
+		}
+		tlsConfig := grpcutils.NewPinnedTLSConfig(clientCert, req.TlsServerCert)
+		dialOpts = append(dialOpts, grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)))
+	}
+	dialOpts = vm.grpcConfig.DialOptions(dialOpts...)
+	dialOpts = append(dialOpts, vm.propagator.DialOptions()...)
+	// Compress the requests to the servers of the node if they support it
+	if grpcutils.IsCompressor(req.Compressor) {
+		dialOpts = grpcutils.WithCompression(req.Compressor, dialOpts...)
+	}
+
+	checksumMismatches, err := rpcdb.NewChecksumMismatchCounter(registerer)
+	if err != nil {
+		// Ignore closing errors to return the original error
+		_ = vm.connCloser.Close()
+		return nil, err
+	}
+
 	// Dial each database in the request and construct the database manager
 	versionedDBs := make([]*manager.VersionedDatabase, len(req.DbServers))
 	for i, vDBReq := range req.DbServers {
@@ -136,14 +240,23 @@ func (vm *VMServer) Initialize(ctx context.Context, req *vmpb.InitializeRequest)
 			return nil, err
 		}
 
-		clientConn, err := grpcutils.Dial(vDBReq.ServerAddr, grpcutils.DialOptsWithMetrics(grpcClientMetrics)...)
+		clientConn, err := dial(vDBReq.ServerAddr, dialOpts...)
 		if err != nil {
 			// Ignore closing errors to return the original error
 			_ = vm.connCloser.Close()
 			return nil, err
 		}
 		vm.connCloser.Add(clientConn)
-		db := rpcdb.NewClient(rpcdbpb.NewDatabaseClient(clientConn))
+		vm.connHealth.add("database/"+vDBReq.Version, clientConn)
+		// Nodes that serve streamed iterators, split values and verify
+		// checksums set the prefetch window, the max batch size and whether
+		// to send checksums.
+		db := rpcdb.NewClientWithConfig(rpcdbpb.NewDatabaseClient(clientConn), rpcdb.ClientConfig{
+			IteratorPrefetch:   int(req.DbIteratorPrefetch),
+			MaxBatchSize:       int(req.DbMaxBatchBytes),
+			Checksums:          req.DbChecksums,
+			ChecksumMismatches: checksumMismatches,
+		})
 		versionedDBs[i] = &manager.VersionedDatabase{
 			Database: corruptabledb.New(db),
 			Version:  version,
@@ -157,7 +270,7 @@ func (vm *VMServer) Initialize(ctx context.Context, req *vmpb.InitializeRequest)
 	}
 	vm.dbManager = dbManager
 
-	clientConn, err := grpcutils.Dial(req.ServerAddr, grpcutils.DialOptsWithMetrics(grpcClientMetrics)...)
+	clientConn, err := dial(req.ServerAddr, dialOpts...)
 	if err != nil {
 		// Ignore closing errors to return the original error
 		_ = vm.connCloser.Close()
@@ -165,6 +278,7 @@ func (vm *VMServer) Initialize(ctx context.Context, req *vmpb.InitializeRequest)
 	}
 
 	vm.connCloser.Add(clientConn)
+	vm.connHealth.add(servicesComponent, clientConn)
 
 	msgClient := messenger.NewClient(messengerpb.NewMessengerClient(clientConn))
 	keystoreClient := gkeystore.NewClient(keystorepb.NewKeystoreClient(clientConn))
@@ -173,6 +287,8 @@ func (vm *VMServer) Initialize(ctx context.Context, req *vmpb.InitializeRequest)
 	snLookupClient := gsubnetlookup.NewClient(subnetlookuppb.NewSubnetLookupClient(clientConn))
 	appSenderClient := appsender.NewClient(appsenderpb.NewAppSenderClient(clientConn))
 	validatorStateClient := gvalidators.NewClient(validatorstatepb.NewValidatorStateClient(clientConn))
+	signatureAggregatorClient := gsignatureaggregator.NewClient(signatureaggregatorpb.NewSignatureAggregatorClient(clientConn))
+	blobClient := gblob.NewClient(blobpb.NewBlobClient(clientConn))
 
 	toEngine := make(chan common.Message, 1)
 	vm.closed = make(chan struct{})
@@ -206,12 +322,38 @@ func (vm *VMServer) Initialize(ctx context.Context, req *vmpb.InitializeRequest)
 		BCLookup:     bcLookupClient,
 		SNLookup:     snLookupClient,
 		Metrics:      metrics.NewOptionalGatherer(),
+		ChainDataDir: req.ChainDataDir,
 
 		ValidatorState: validatorStateClient,
 		// TODO: support remaining snowman++ fields
+
+		SignatureAggregator: signatureAggregatorClient,
+		Blobs:               blobClient,
 	}
 
-	if err := vm.vm.Initialize(ctx, vm.ctx, dbManager, req.GenesisBytes, req.UpgradeBytes, req.ConfigBytes, toEngine, nil, appSenderClient); err != nil {
+	fxs, err := vm.newFxs(req.FxIds)
+	if err != nil {
+		// Ignore errors closing resources to return the original error
+		_ = vm.connCloser.Close()
+		close(vm.closed)
+		return nil, err
+	}
+
+	buildPacing, err := buildPacingFromProto(req.BuildPacing)
+	if err != nil {
+		// Ignore errors closing resources to return the original error
+		_ = vm.connCloser.Close()
+		close(vm.closed)
+		return nil, err
+	}
+	if pacedVM, ok := vm.vm.(block.BuildPacingVM); ok && buildPacing != nil {
+		pacedVM.SetBuildPacing(*buildPacing)
+	}
+	if servicesVM, ok := vm.vm.(PluginServicesVM); ok {
+		servicesVM.SetPluginServicesConn(clientConn)
+	}
+
+	if err := vm.vm.Initialize(ctx, vm.ctx, dbManager, req.GenesisBytes, req.UpgradeBytes, req.ConfigBytes, toEngine, fxs, appSenderClient); err != nil {
 		// Ignore errors closing resources to return the original error
 		_ = vm.connCloser.Close()
 		close(vm.closed)
@@ -242,9 +384,52 @@ func (vm *VMServer) Initialize(ctx context.Context, req *vmpb.InitializeRequest)
 		Height:               blk.Height(),
 		Bytes:                blk.Bytes(),
 		Timestamp:            grpcutils.TimestampFromTime(blk.Timestamp()),
+		LastAcceptedOracle:   isOracle(blk),
 	}, nil
 }
 
+func (vm *VMServer) SupportedFxs(context.Context, *emptypb.Empty) (*vmpb.SupportedFxsResponse, error) {
+	fxIDs := make([][]byte, 0, len(vm.fxs))
+	for fxID := range vm.fxs {
+		fxID := fxID
+		fxIDs = append(fxIDs, fxID[:])
+	}
+	return &vmpb.SupportedFxsResponse{FxIds: fxIDs}, nil
+}
+
+func (vm *VMServer) ConfigSchema(ctx context.Context, _ *emptypb.Empty) (*vmpb.ConfigSchemaResponse, error) {
+	schemaVM, ok := vm.vm.(common.ConfigSchemaVM)
+	if !ok {
+		return &vmpb.ConfigSchemaResponse{}, nil
+	}
+	schema, err := schemaVM.ConfigSchema(ctx)
+	return &vmpb.ConfigSchemaResponse{Schema: schema}, err
+}
+
+// newFxs creates the feature extensions [fxIDs] for the vm being initialized.
+func (vm *VMServer) newFxs(fxIDs [][]byte) ([]*common.Fx, error) {
+	fxs := make([]*common.Fx, len(fxIDs))
+	for i, fxIDBytes := range fxIDs {
+		fxID, err := ids.ToID(fxIDBytes)
+		if err != nil {
+			return nil, err
+		}
+		factory, ok := vm.fxs[fxID]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", errUnsupportedFXs, fxID)
+		}
+		fx, err := factory.New(vm.ctx)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't create fx %s: %w", fxID, err)
+		}
+		fxs[i] = &common.Fx{
+			ID: fxID,
+			Fx: fx,
+		}
+	}
+	return fxs, nil
+}
+
 func (vm *VMServer) SetState(ctx context.Context, stateReq *vmpb.SetStateRequest) (*vmpb.SetStateResponse, error) {
 	err := vm.vm.SetState(ctx, snow.State(stateReq.State))
 	if err != nil {
@@ -268,6 +453,7 @@ func (vm *VMServer) SetState(ctx context.Context, stateReq *vmpb.SetStateRequest
 		Height:               blk.Height(),
 		Bytes:                blk.Bytes(),
 		Timestamp:            grpcutils.TimestampFromTime(blk.Timestamp()),
+		LastAcceptedOracle:   isOracle(blk),
 	}, nil
 }
 
@@ -292,7 +478,7 @@ func (vm *VMServer) CreateHandlers(ctx context.Context, _ *emptypb.Empty) (*vmpb
 	for prefix, h := range handlers {
 		handler := h
 
-		serverListener, err := grpcutils.NewListener()
+		serverListener, err := vm.listen()
 		if err != nil {
 			return nil, err
 		}
@@ -300,19 +486,25 @@ func (vm *VMServer) CreateHandlers(ctx context.Context, _ *emptypb.Empty) (*vmpb
 
 		// Start the gRPC server which serves the HTTP service
 		go grpcutils.Serve(serverListener, func(opts []grpc.ServerOption) *grpc.Server {
-			if len(opts) == 0 {
-				opts = append(opts, grpcutils.DefaultServerOptions...)
+			opts = vm.grpcConfig.ServerOptions(opts...)
+			opts = append(opts, vm.propagator.ServerOptions()...)
+			if vm.creds != nil {
+				opts = append(opts, grpc.Creds(vm.creds))
 			}
 			server := grpc.NewServer(opts...)
 			vm.serverCloser.Add(server)
 			httppb.RegisterHTTPServer(server, ghttp.NewServer(handler.Handler))
+			// Allows the node to probe the health of the handler
+			healthpb.RegisterHealthServer(server, newServingHealth())
 			return server
 		})
 
 		resp.Handlers = append(resp.Handlers, &vmpb.Handler{
-			Prefix:      prefix,
-			LockOptions: uint32(handler.LockOptions),
-			ServerAddr:  serverAddr,
+			Prefix:        prefix,
+			LockOptions:   uint32(handler.LockOptions),
+			ServerAddr:    serverAddr,
+			CachedMethods: handler.CachedMethods,
+			Openapi:       handler.OpenAPI,
 		})
 	}
 	return resp, nil
@@ -327,7 +519,7 @@ func (vm *VMServer) CreateStaticHandlers(ctx context.Context, _ *emptypb.Empty)
 	for prefix, h := range handlers {
 		handler := h
 
-		serverListener, err := grpcutils.NewListener()
+		serverListener, err := vm.listen()
 		if err != nil {
 			return nil, err
 		}
@@ -335,19 +527,25 @@ func (vm *VMServer) CreateStaticHandlers(ctx context.Context, _ *emptypb.Empty)
 
 		// Start the gRPC server which serves the HTTP service
 		go grpcutils.Serve(serverListener, func(opts []grpc.ServerOption) *grpc.Server {
-			if len(opts) == 0 {
-				opts = append(opts, grpcutils.DefaultServerOptions...)
+			opts = vm.grpcConfig.ServerOptions(opts...)
+			opts = append(opts, vm.propagator.ServerOptions()...)
+			if vm.creds != nil {
+				opts = append(opts, grpc.Creds(vm.creds))
 			}
 			server := grpc.NewServer(opts...)
 			vm.serverCloser.Add(server)
 			httppb.RegisterHTTPServer(server, ghttp.NewServer(handler.Handler))
+			// Allows the node to probe the health of the handler
+			healthpb.RegisterHealthServer(server, newServingHealth())
 			return server
 		})
 
 		resp.Handlers = append(resp.Handlers, &vmpb.Handler{
-			Prefix:      prefix,
-			LockOptions: uint32(handler.LockOptions),
-			ServerAddr:  serverAddr,
+			Prefix:        prefix,
+			LockOptions:   uint32(handler.LockOptions),
+			ServerAddr:    serverAddr,
+			CachedMethods: handler.CachedMethods,
+			Openapi:       handler.OpenAPI,
 		})
 	}
 	return resp, nil
@@ -380,14 +578,37 @@ func (vm *VMServer) BuildBlock(ctx context.Context, _ *emptypb.Empty) (*vmpb.Bui
 	if err != nil {
 		return nil, err
 	}
+	return buildBlockResponse(ctx, blk)
+}
+
+func (vm *VMServer) BuildBlockWithContext(ctx context.Context, req *vmpb.BuildBlockWithContextRequest) (*vmpb.BuildBlockResponse, error) {
+	if vm.bbVM == nil {
+		return vm.BuildBlock(ctx, &emptypb.Empty{})
+	}
+	blk, err := vm.bbVM.BuildBlockWithContext(ctx, &block.Context{
+		PChainHeight: req.PChainHeight,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return buildBlockResponse(ctx, blk)
+}
+
+func buildBlockResponse(ctx context.Context, blk snowman.Block) (*vmpb.BuildBlockResponse, error) {
+	verifyWithContext, err := shouldVerifyWithContext(ctx, blk)
+	if err != nil {
+		return nil, err
+	}
 	blkID := blk.ID()
 	parentID := blk.Parent()
 	return &vmpb.BuildBlockResponse{
-		Id:        blkID[:],
-		ParentId:  parentID[:],
-		Bytes:     blk.Bytes(),
-		Height:    blk.Height(),
-		Timestamp: grpcutils.TimestampFromTime(blk.Timestamp()),
+		Id:                blkID[:],
+		ParentId:          parentID[:],
+		Bytes:             blk.Bytes(),
+		Height:            blk.Height(),
+		Timestamp:         grpcutils.TimestampFromTime(blk.Timestamp()),
+		VerifyWithContext: verifyWithContext,
+		Oracle:            isOracle(blk),
 	}, nil
 }
 
@@ -396,14 +617,20 @@ func (vm *VMServer) ParseBlock(ctx context.Context, req *vmpb.ParseBlockRequest)
 	if err != nil {
 		return nil, err
 	}
+	verifyWithContext, err := shouldVerifyWithContext(ctx, blk)
+	if err != nil {
+		return nil, err
+	}
 	blkID := blk.ID()
 	parentID := blk.Parent()
 	return &vmpb.ParseBlockResponse{
-		Id:        blkID[:],
-		ParentId:  parentID[:],
-		Status:    uint32(blk.Status()),
-		Height:    blk.Height(),
-		Timestamp: grpcutils.TimestampFromTime(blk.Timestamp()),
+		Id:                blkID[:],
+		ParentId:          parentID[:],
+		Status:            uint32(blk.Status()),
+		Height:            blk.Height(),
+		Timestamp:         grpcutils.TimestampFromTime(blk.Timestamp()),
+		VerifyWithContext: verifyWithContext,
+		Oracle:            isOracle(blk),
 	}, nil
 }
 
@@ -419,13 +646,19 @@ func (vm *VMServer) GetBlock(ctx context.Context, req *vmpb.GetBlockRequest) (*v
 		}, errorToRPCError(err)
 	}
 
+	verifyWithContext, err := shouldVerifyWithContext(ctx, blk)
+	if err != nil {
+		return nil, err
+	}
 	parentID := blk.Parent()
 	return &vmpb.GetBlockResponse{
-		ParentId:  parentID[:],
-		Bytes:     blk.Bytes(),
-		Status:    uint32(blk.Status()),
-		Height:    blk.Height(),
-		Timestamp: grpcutils.TimestampFromTime(blk.Timestamp()),
+		ParentId:          parentID[:],
+		Bytes:             blk.Bytes(),
+		Status:            uint32(blk.Status()),
+		Height:            blk.Height(),
+		Timestamp:         grpcutils.TimestampFromTime(blk.Timestamp()),
+		VerifyWithContext: verifyWithContext,
+		Oracle:            isOracle(blk),
 	}, nil
 }
 
@@ -525,7 +758,11 @@ func (vm *VMServer) AppRequestFailed(ctx context.Context, req *vmpb.AppRequestFa
 	if err != nil {
 		return nil, err
 	}
-	return &emptypb.Empty{}, vm.vm.AppRequestFailed(ctx, nodeID, req.RequestId)
+	appErr := &common.AppError{
+		Code:    req.ErrorCode,
+		Message: req.ErrorMessage,
+	}
+	return &emptypb.Empty{}, common.NotifyAppRequestFailed(ctx, vm.vm, nodeID, req.RequestId, appErr)
 }
 
 func (vm *VMServer) AppResponse(ctx context.Context, req *vmpb.AppResponseMsg) (*emptypb.Empty, error) {
@@ -544,6 +781,39 @@ func (vm *VMServer) AppGossip(ctx context.Context, req *vmpb.AppGossipMsg) (*emp
 	return &emptypb.Empty{}, vm.vm.AppGossip(ctx, nodeID, req.Msg)
 }
 
+func (vm *VMServer) AppGossipStream(stream vmpb.VM_AppGossipStreamServer) error {
+	// Acknowledge the stream, so the client knows that gossip streams are
+	// supported.
+	if err := stream.Send(&vmpb.AppGossipBatchAck{}); err != nil {
+		return err
+	}
+
+	ctx := stream.Context()
+	for {
+		batch, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		// Each message is delivered as if it was sent with AppGossip, and
+		// failing to deliver a message doesn't affect the other messages.
+		ack := &vmpb.AppGossipBatchAck{
+			Errs: make([]string, len(batch.Msgs)),
+		}
+		for i, msg := range batch.Msgs {
+			if _, err := vm.AppGossip(ctx, msg); err != nil {
+				ack.Errs[i] = err.Error()
+			}
+		}
+		if err := stream.Send(ack); err != nil {
+			return err
+		}
+	}
+}
+
 func (vm *VMServer) Gather(context.Context, *emptypb.Empty) (*vmpb.GatherResponse, error) {
 	// Gather metrics registered to snow context Gatherer. These
 	// metrics are defined by the underlying vm implementation.
@@ -604,6 +874,32 @@ func (vm *VMServer) BatchedParseBlock(
 	}, nil
 }
 
+func (vm *VMServer) BatchedGetBlock(
+	ctx context.Context,
+	req *vmpb.BatchedGetBlockRequest,
+) (*vmpb.BatchedGetBlockResponse, error) {
+	resp := &vmpb.BatchedGetBlockResponse{
+		Response: make([]*vmpb.GetBlockResponse, len(req.Ids)),
+	}
+	for i, blkID := range req.Ids {
+		// A block that couldn't be fetched doesn't fail the whole batch. Its
+		// error is reported through the response's error code if it has one,
+		// and through the errors of the batch otherwise.
+		block, err := vm.GetBlock(ctx, &vmpb.GetBlockRequest{
+			Id: blkID,
+		})
+		if err != nil {
+			if resp.Errors == nil {
+				resp.Errors = make([]string, len(req.Ids))
+			}
+			resp.Errors[i] = err.Error()
+			block = &vmpb.GetBlockResponse{}
+		}
+		resp.Response[i] = block
+	}
+	return resp, nil
+}
+
 func (vm *VMServer) VerifyHeightIndex(ctx context.Context, _ *emptypb.Empty) (*vmpb.VerifyHeightIndexResponse, error) {
 	var err error
 	if vm.hVM != nil {
@@ -759,10 +1055,30 @@ func (vm *VMServer) GetStateSummary(
 	}, nil
 }
 
+func (vm *VMServer) DecodeBlock(
+	ctx context.Context,
+	req *vmpb.DecodeBlockRequest,
+) (*vmpb.DecodeBlockResponse, error) {
+	var (
+		decoded []byte
+		err     error
+	)
+	if vm.dVM != nil {
+		decoded, err = vm.dVM.DecodeBlock(ctx, req.Bytes)
+	} else {
+		err = block.ErrDecoderNotImplemented
+	}
+
+	return &vmpb.DecodeBlockResponse{
+		Decoded: decoded,
+		Err:     errorToErrCode[err],
+	}, errorToRPCError(err)
+}
+
 func (vm *VMServer) BlockVerify(ctx context.Context, req *vmpb.BlockVerifyRequest) (*vmpb.BlockVerifyResponse, error) {
-	blk, err := vm.vm.ParseBlock(ctx, req.Bytes)
-	if err != nil {
-		return nil, err
+	blk, resp, err := vm.blockToVerify(ctx, req.Id, req.Bytes)
+	if blk == nil {
+		return resp, err
 	}
 	if err := blk.Verify(ctx); err != nil {
 		return nil, err
@@ -772,6 +1088,111 @@ func (vm *VMServer) BlockVerify(ctx context.Context, req *vmpb.BlockVerifyReques
 	}, nil
 }
 
+func (vm *VMServer) BlockVerifyWithContext(ctx context.Context, req *vmpb.BlockVerifyWithContextRequest) (*vmpb.BlockVerifyResponse, error) {
+	blk, resp, err := vm.blockToVerify(ctx, req.Id, req.Bytes)
+	if blk == nil {
+		return resp, err
+	}
+	blkWithCtx, ok := blk.(block.WithVerifyContext)
+	if ok {
+		err = blkWithCtx.VerifyWithContext(ctx, &block.Context{
+			PChainHeight: req.PChainHeight,
+		})
+	} else {
+		err = blk.Verify(ctx)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &vmpb.BlockVerifyResponse{
+		Timestamp: grpcutils.TimestampFromTime(blk.Timestamp()),
+	}, nil
+}
+
+// blockToVerify returns the block with ID [blkIDBytes] if it is set, or parses
+// [blkBytes] otherwise. If the block isn't returned, the response to the
+// verification request is returned instead.
+func (vm *VMServer) blockToVerify(ctx context.Context, blkIDBytes []byte, blkBytes []byte) (snowman.Block, *vmpb.BlockVerifyResponse, error) {
+	if len(blkIDBytes) == 0 {
+		blk, err := vm.vm.ParseBlock(ctx, blkBytes)
+		if err != nil {
+			return nil, nil, err
+		}
+		return blk, nil, nil
+	}
+
+	blkID, err := ids.ToID(blkIDBytes)
+	if err != nil {
+		return nil, nil, err
+	}
+	blk, err := vm.vm.GetBlock(ctx, blkID)
+	if err == database.ErrNotFound {
+		// The node resends the bytes of the block, so that it can be parsed
+		// again.
+		return nil, &vmpb.BlockVerifyResponse{
+			Err: errorToErrCode[database.ErrNotFound],
+		}, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return blk, nil, nil
+}
+
+// shouldVerifyWithContext returns true if [blk] is verified with the block
+// context provided by the proposervm.
+func shouldVerifyWithContext(ctx context.Context, blk snowman.Block) (bool, error) {
+	blkWithCtx, ok := blk.(block.WithVerifyContext)
+	if !ok {
+		return false, nil
+	}
+	return blkWithCtx.ShouldVerifyWithContext(ctx)
+}
+
+// isOracle returns true if [blk] may be an oracle block. The node requests the
+// options of the block, which it learns isn't an oracle if Options returns
+// snowman.ErrNotOracle.
+func isOracle(blk snowman.Block) bool {
+	_, ok := blk.(snowman.OracleBlock)
+	return ok
+}
+
+func (vm *VMServer) BlockOptions(ctx context.Context, req *vmpb.BlockOptionsRequest) (*vmpb.BlockOptionsResponse, error) {
+	blkID, err := ids.ToID(req.Id)
+	if err != nil {
+		return nil, err
+	}
+	blk, err := vm.vm.GetBlock(ctx, blkID)
+	if err != nil {
+		return &vmpb.BlockOptionsResponse{
+			Err: errorToErrCode[err],
+		}, errorToRPCError(err)
+	}
+	oracleBlk, ok := blk.(snowman.OracleBlock)
+	if !ok {
+		return &vmpb.BlockOptionsResponse{
+			Err: errorToErrCode[snowman.ErrNotOracle],
+		}, nil
+	}
+	options, err := oracleBlk.Options(ctx)
+	if err != nil {
+		return &vmpb.BlockOptionsResponse{
+			Err: errorToErrCode[err],
+		}, errorToRPCError(err)
+	}
+
+	resp := &vmpb.BlockOptionsResponse{
+		Options: make([]*vmpb.BuildBlockResponse, len(options)),
+	}
+	for i, option := range options {
+		resp.Options[i], err = buildBlockResponse(ctx, option)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return resp, nil
+}
+
 func (vm *VMServer) BlockAccept(ctx context.Context, req *vmpb.BlockAcceptRequest) (*emptypb.Empty, error) {
 	id, err := ids.ToID(req.Id)
 	if err != nil {
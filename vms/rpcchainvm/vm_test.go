@@ -6,6 +6,7 @@ package rpcchainvm
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net"
@@ -14,6 +15,7 @@ import (
 	"reflect"
 	"sort"
 	"testing"
+	"time"
 
 	stdjson "encoding/json"
 
@@ -27,15 +29,30 @@ import (
 	hclog "github.com/hashicorp/go-hclog"
 	plugin "github.com/hashicorp/go-plugin"
 
+	"github.com/prometheus/client_golang/prometheus"
+
 	"github.com/stretchr/testify/require"
 
 	"google.golang.org/grpc"
 	"google.golang.org/protobuf/types/known/emptypb"
 
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/manager"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
 	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
 	"github.com/ava-labs/avalanchego/utils/json"
+	"github.com/ava-labs/avalanchego/version"
+	"github.com/ava-labs/avalanchego/vms"
+	"github.com/ava-labs/avalanchego/vms/nftfx"
 	"github.com/ava-labs/avalanchego/vms/rpcchainvm/ghttp"
 	"github.com/ava-labs/avalanchego/vms/rpcchainvm/grpcutils"
+	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
+
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 
 	httppb "github.com/ava-labs/avalanchego/proto/pb/http"
 	vmpb "github.com/ava-labs/avalanchego/proto/pb/vm"
@@ -416,3 +433,362 @@ func websocketEchoHandler(msgCount int) http.Handler {
 		}
 	})
 }
+
+func TestSupportedFxs(t *testing.T) {
+	require := require.New(t)
+
+	server := NewServerWithFxs(&block.TestVM{}, map[ids.ID]vms.Factory{
+		secp256k1fx.ID: &secp256k1fx.Factory{},
+	})
+	client := NewClient(serveVM(t, server))
+
+	secpFx := &common.Fx{ID: secp256k1fx.ID}
+	nftFx := &common.Fx{ID: nftfx.ID}
+	require.NoError(client.verifyFxs(context.Background(), nil))
+	require.NoError(client.verifyFxs(context.Background(), []*common.Fx{secpFx}))
+	err := client.verifyFxs(context.Background(), []*common.Fx{secpFx, nftFx})
+	require.ErrorIs(err, errUnsupportedFXs)
+
+	// The plugin creates its own instance of each feature extension.
+	fxs, err := server.newFxs([][]byte{secp256k1fx.ID[:]})
+	require.NoError(err)
+	require.Len(fxs, 1)
+	require.Equal(secp256k1fx.ID, fxs[0].ID)
+	require.IsType(&secp256k1fx.Fx{}, fxs[0].Fx)
+
+	_, err = server.newFxs([][]byte{nftfx.ID[:]})
+	require.ErrorIs(err, errUnsupportedFXs)
+
+	// Plugins that predate feature extension support can't be initialized
+	// with feature extensions.
+	client = NewClient(serveVM(t, &vmpb.UnimplementedVMServer{}))
+	require.NoError(client.verifyFxs(context.Background(), nil))
+	err = client.verifyFxs(context.Background(), []*common.Fx{secpFx})
+	require.ErrorIs(err, errUnsupportedFXs)
+}
+
+// schemaVM is a VM that describes its chain configs.
+type schemaVM struct {
+	block.TestVM
+	schema []byte
+}
+
+func (vm *schemaVM) ConfigSchema(context.Context) ([]byte, error) {
+	return vm.schema, nil
+}
+
+func TestConfigSchema(t *testing.T) {
+	require := require.New(t)
+
+	schema := []byte(`{"type":"object"}`)
+	client := NewClient(serveVM(t, NewServer(&schemaVM{schema: schema})))
+	returnedSchema, err := client.ConfigSchema(context.Background())
+	require.NoError(err)
+	require.Equal(schema, returnedSchema)
+
+	// VMs that don't describe their chain configs don't return a schema.
+	client = NewClient(serveVM(t, NewServer(&block.TestVM{})))
+	returnedSchema, err = client.ConfigSchema(context.Background())
+	require.NoError(err)
+	require.Empty(returnedSchema)
+
+	client = NewClient(serveVM(t, &vmpb.UnimplementedVMServer{}))
+	returnedSchema, err = client.ConfigSchema(context.Background())
+	require.NoError(err)
+	require.Empty(returnedSchema)
+}
+
+func TestCreateHandlersMetadata(t *testing.T) {
+	require := require.New(t)
+
+	openAPI := []byte(`{"post":{"summary":"test"}}`)
+	chainVM := &block.TestVM{}
+	chainVM.CreateHandlersF = func(context.Context) (map[string]*common.HTTPHandler, error) {
+		return map[string]*common.HTTPHandler{
+			"": {
+				Handler:       http.NotFoundHandler(),
+				CachedMethods: []string{"test.get"},
+				OpenAPI:       openAPI,
+			},
+		}, nil
+	}
+	vm := NewClient(serveVM(t, NewServer(chainVM)))
+
+	handlers, err := vm.CreateHandlers(context.Background())
+	require.NoError(err)
+	require.Contains(handlers, "")
+	require.Equal([]string{"test.get"}, handlers[""].CachedMethods)
+	require.Equal(openAPI, handlers[""].OpenAPI)
+}
+
+// verifyServer is a plugin that records the bytes of the verify requests it
+// receives.
+type verifyServer struct {
+	*VMServer
+
+	requests [][]byte
+}
+
+func (s *verifyServer) BlockVerify(ctx context.Context, req *vmpb.BlockVerifyRequest) (*vmpb.BlockVerifyResponse, error) {
+	s.requests = append(s.requests, req.Bytes)
+	return s.VMServer.BlockVerify(ctx, req)
+}
+
+func TestBlockVerifyByID(t *testing.T) {
+	require := require.New(t)
+
+	blk := &snowman.TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.GenerateTestID(),
+			StatusV: choices.Processing,
+		},
+		BytesV:     []byte{1, 2, 3},
+		TimestampV: time.Unix(123, 0).UTC(),
+	}
+	var (
+		cached = true
+		getErr error
+	)
+	chainVM := &block.TestVM{
+		GetBlockF: func(_ context.Context, blkID ids.ID) (snowman.Block, error) {
+			if getErr != nil {
+				return nil, getErr
+			}
+			if !cached || blkID != blk.ID() {
+				return nil, database.ErrNotFound
+			}
+			return blk, nil
+		},
+		ParseBlockF: func(_ context.Context, b []byte) (snowman.Block, error) {
+			require.Equal(blk.BytesV, b)
+			return blk, nil
+		},
+	}
+	server := &verifyServer{
+		VMServer: NewServer(chainVM),
+	}
+	vm := NewClient(serveVM(t, server))
+	require.NoError(vm.handshake(context.Background()))
+	require.True(vm.capabilities.Has(CapabilityVerifyByID))
+
+	client := &blockClient{
+		vm:    vm,
+		id:    blk.ID(),
+		bytes: blk.Bytes(),
+	}
+
+	// The plugin verifies the block it already parsed without its bytes.
+	require.NoError(client.Verify(context.Background()))
+	require.Equal([][]byte{nil}, server.requests)
+	require.Equal(blk.TimestampV, client.Timestamp())
+
+	// The bytes are resent if the plugin no longer has the block.
+	cached = false
+	server.requests = nil
+	require.NoError(client.Verify(context.Background()))
+	require.Equal([][]byte{nil, blk.BytesV}, server.requests)
+
+	// Other errors fail the verification instead of resending the bytes.
+	getErr = errors.New("database closed")
+	server.requests = nil
+	require.Error(client.Verify(context.Background()))
+	require.Equal([][]byte{nil}, server.requests)
+
+	// Plugins that predate the handshake are sent the bytes.
+	vm.capabilities = legacyCapabilities
+	server.requests = nil
+	require.NoError(client.Verify(context.Background()))
+	require.Equal([][]byte{blk.BytesV}, server.requests)
+}
+
+func TestBatchedGetBlock(t *testing.T) {
+	require := require.New(t)
+
+	blk := &snowman.TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.GenerateTestID(),
+			StatusV: choices.Processing,
+		},
+		BytesV:     []byte{1, 2, 3},
+		TimestampV: time.Unix(123, 0).UTC(),
+	}
+	var (
+		missingID = ids.GenerateTestID()
+		failingID = ids.GenerateTestID()
+		errFailed = errors.New("failed to read the block")
+	)
+	chainVM := &block.TestVM{
+		GetBlockF: func(_ context.Context, blkID ids.ID) (snowman.Block, error) {
+			switch blkID {
+			case blk.ID():
+				return blk, nil
+			case failingID:
+				return nil, errFailed
+			default:
+				return nil, database.ErrNotFound
+			}
+		},
+	}
+	vm := NewClient(serveVM(t, NewServer(chainVM)))
+	require.NoError(vm.handshake(context.Background()))
+
+	blkIDs := []ids.ID{failingID, blk.ID(), missingID}
+	for _, capabilities := range []Capabilities{
+		vm.capabilities,
+		// Plugins that don't serve BatchedGetBlock are sent a request per
+		// block.
+		vm.capabilities &^ CapabilityBatchedChainVM,
+	} {
+		vm.capabilities = capabilities
+
+		// A block that fails to be fetched doesn't fail the others.
+		blks, errs, err := vm.batchedGetBlock(context.Background(), blkIDs)
+		require.NoError(err)
+		require.Len(blks, len(blkIDs))
+		require.Len(errs, len(blkIDs))
+
+		require.Nil(blks[0])
+		require.ErrorContains(errs[0], errFailed.Error())
+		require.NoError(errs[1])
+		require.Equal(blk.ID(), blks[1].ID())
+		require.Equal(blk.BytesV, blks[1].Bytes())
+		require.Nil(blks[2])
+		require.ErrorIs(errs[2], database.ErrNotFound)
+	}
+}
+
+type contextBlock struct {
+	*snowman.TestBlock
+
+	// Contexts the block was verified with
+	verifiedWith []block.Context
+}
+
+func (*contextBlock) ShouldVerifyWithContext(context.Context) (bool, error) {
+	return true, nil
+}
+
+func (b *contextBlock) VerifyWithContext(_ context.Context, blockCtx *block.Context) error {
+	b.verifiedWith = append(b.verifiedWith, *blockCtx)
+	return nil
+}
+
+type contextVM struct {
+	*block.TestVM
+
+	blk *contextBlock
+	// Contexts blocks were built with
+	builtWith []block.Context
+}
+
+func (vm *contextVM) BuildBlockWithContext(_ context.Context, blockCtx *block.Context) (snowman.Block, error) {
+	vm.builtWith = append(vm.builtWith, *blockCtx)
+	return vm.blk, nil
+}
+
+func TestBlockContext(t *testing.T) {
+	require := require.New(t)
+
+	blk := &contextBlock{
+		TestBlock: &snowman.TestBlock{
+			TestDecidable: choices.TestDecidable{
+				IDV:     ids.GenerateTestID(),
+				StatusV: choices.Processing,
+			},
+			BytesV:     []byte{1, 2, 3},
+			TimestampV: time.Unix(123, 0).UTC(),
+		},
+	}
+	chainVM := &contextVM{
+		TestVM: &block.TestVM{
+			GetBlockF: func(_ context.Context, blkID ids.ID) (snowman.Block, error) {
+				if blkID != blk.ID() {
+					return nil, database.ErrNotFound
+				}
+				return blk, nil
+			},
+		},
+		blk: blk,
+	}
+	vm := NewClient(serveVM(t, NewServer(chainVM)))
+	require.NoError(vm.handshake(context.Background()))
+	require.True(vm.capabilities.Has(CapabilityBlockContext))
+
+	builtBlk, err := vm.buildBlockWithContext(context.Background(), &block.Context{
+		PChainHeight: 5,
+	})
+	require.NoError(err)
+	require.Equal([]block.Context{{PChainHeight: 5}}, chainVM.builtWith)
+	require.Equal(blk.ID(), builtBlk.ID())
+
+	blkWithCtx := builtBlk.(block.WithVerifyContext)
+	shouldVerifyWithCtx, err := blkWithCtx.ShouldVerifyWithContext(context.Background())
+	require.NoError(err)
+	require.True(shouldVerifyWithCtx)
+
+	// The block may be verified again with a different context.
+	require.NoError(blkWithCtx.VerifyWithContext(context.Background(), &block.Context{
+		PChainHeight: 5,
+	}))
+	require.NoError(blkWithCtx.VerifyWithContext(context.Background(), &block.Context{
+		PChainHeight: 6,
+	}))
+	require.Equal([]block.Context{{PChainHeight: 5}, {PChainHeight: 6}}, blk.verifiedWith)
+	require.Equal(blk.TimestampV, builtBlk.Timestamp())
+
+	// Plugins that don't report the capability are asked to build blocks
+	// without a context.
+	vm.capabilities &^= CapabilityBlockContext
+	chainVM.BuildBlockF = func(context.Context) (snowman.Block, error) {
+		return blk, nil
+	}
+	_, err = vm.buildBlockWithContext(context.Background(), &block.Context{
+		PChainHeight: 7,
+	})
+	require.NoError(err)
+	require.Len(chainVM.builtWith, 1)
+}
+
+func TestGRPCMetricsLabels(t *testing.T) {
+	require := require.New(t)
+
+	pluginVM := newPluginServicesTestVM(t)
+	vm := NewClient(serveVM(t, NewServer(pluginVM)))
+
+	ctx := snow.DefaultContextTest()
+	aliaser := ids.NewAliaser()
+	require.NoError(aliaser.Alias(ctx.ChainID, "X"))
+	ctx.BCLookup = aliaser
+	dbManager := manager.NewMemDB(version.Semantic1_0_0)
+	require.NoError(vm.Initialize(context.Background(), ctx, dbManager, nil, nil, nil, nil, nil, nil))
+
+	// A call made by the plugin to the servers of the node
+	_, err := healthpb.NewHealthClient(pluginVM.conn).Check(context.Background(), &healthpb.HealthCheckRequest{})
+	require.NoError(err)
+
+	families, err := ctx.Metrics.Gather()
+	require.NoError(err)
+	for _, family := range families {
+		if family.GetName() != "rpcchainvm_grpc_server_handled_total" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			labels := make(map[string]string)
+			for _, label := range metric.GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+			require.Equal(ctx.ChainID.String(), labels["chainID"])
+			require.Equal("X", labels["chainAlias"])
+		}
+		return
+	}
+	require.FailNow("missing gRPC server metrics")
+}
+
+func TestGRPCMetricsLabelsWithoutAlias(t *testing.T) {
+	ctx := snow.DefaultContextTest()
+	require.Equal(t, prometheus.Labels{
+		"chainID":    ctx.ChainID.String(),
+		"chainAlias": ctx.ChainID.String(),
+	}, grpcMetricsLabels(ctx))
+}
@@ -68,3 +68,31 @@ func (vm *blockVM) BatchedParseBlock(ctx context.Context, blks [][]byte) ([]snow
 	}
 	return wrappedBlocks, nil
 }
+
+func (vm *blockVM) BatchedGetBlock(ctx context.Context, blkIDs []ids.ID) ([]snowman.Block, []error, error) {
+	if vm.bVM == nil {
+		return nil, nil, block.ErrRemoteVMNotImplemented
+	}
+
+	ctx, span := vm.tracer.Start(ctx, "blockVM.BatchedGetBlock", oteltrace.WithAttributes(
+		attribute.Int("numBlocks", len(blkIDs)),
+	))
+	defer span.End()
+
+	blocks, errs, err := vm.bVM.BatchedGetBlock(ctx, blkIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	wrappedBlocks := make([]snowman.Block, len(blocks))
+	for i, block := range blocks {
+		if block == nil {
+			continue
+		}
+		wrappedBlocks[i] = &tracedBlock{
+			Block: block,
+			vm:    vm,
+		}
+	}
+	return wrappedBlocks, errs, nil
+}
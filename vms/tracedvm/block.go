@@ -5,17 +5,23 @@ package tracedvm
 
 import (
 	"context"
+	"errors"
+	"fmt"
 
 	"go.opentelemetry.io/otel/attribute"
 
 	oteltrace "go.opentelemetry.io/otel/trace"
 
 	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
+	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
 )
 
 var (
-	_ snowman.Block       = (*tracedBlock)(nil)
-	_ snowman.OracleBlock = (*tracedBlock)(nil)
+	errExpectedBlockWithVerifyContext = errors.New("expected block.WithVerifyContext")
+
+	_ snowman.Block           = (*tracedBlock)(nil)
+	_ snowman.OracleBlock     = (*tracedBlock)(nil)
+	_ block.WithVerifyContext = (*tracedBlock)(nil)
 )
 
 type tracedBlock struct {
@@ -34,6 +40,37 @@ func (b *tracedBlock) Verify(ctx context.Context) error {
 	return b.Block.Verify(ctx)
 }
 
+func (b *tracedBlock) ShouldVerifyWithContext(ctx context.Context) (bool, error) {
+	blkWithCtx, ok := b.Block.(block.WithVerifyContext)
+	if !ok {
+		return false, nil
+	}
+
+	ctx, span := b.vm.tracer.Start(ctx, b.vm.shouldVerifyWithContextTag, oteltrace.WithAttributes(
+		attribute.Stringer("blkID", b.ID()),
+		attribute.Int64("height", int64(b.Height())),
+	))
+	defer span.End()
+
+	return blkWithCtx.ShouldVerifyWithContext(ctx)
+}
+
+func (b *tracedBlock) VerifyWithContext(ctx context.Context, blockCtx *block.Context) error {
+	blkWithCtx, ok := b.Block.(block.WithVerifyContext)
+	if !ok {
+		return fmt.Errorf("%w but got %T", errExpectedBlockWithVerifyContext, b.Block)
+	}
+
+	ctx, span := b.vm.tracer.Start(ctx, b.vm.verifyWithContextTag, oteltrace.WithAttributes(
+		attribute.Stringer("blkID", b.ID()),
+		attribute.Int64("height", int64(b.Height())),
+		attribute.Int64("pChainHeight", int64(blockCtx.PChainHeight)),
+	))
+	defer span.End()
+
+	return blkWithCtx.VerifyWithContext(ctx, blockCtx)
+}
+
 func (b *tracedBlock) Accept(ctx context.Context) error {
 	ctx, span := b.vm.tracer.Start(ctx, b.vm.acceptTag, oteltrace.WithAttributes(
 		attribute.Stringer("blkID", b.ID()),
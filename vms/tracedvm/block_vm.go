@@ -25,6 +25,12 @@ var (
 	_ block.BatchedChainVM       = (*blockVM)(nil)
 	_ block.HeightIndexedChainVM = (*blockVM)(nil)
 	_ block.StateSyncableVM      = (*blockVM)(nil)
+	_ block.Decoder              = (*blockVM)(nil)
+	_ block.DecisionBatcher      = (*blockVM)(nil)
+	_ block.StateDiffer          = (*blockVM)(nil)
+	_ block.MessageVerifier      = (*blockVM)(nil)
+
+	_ block.BuildBlockWithContextChainVM = (*blockVM)(nil)
 )
 
 type blockVM struct {
@@ -32,6 +38,11 @@ type blockVM struct {
 	bVM              block.BatchedChainVM
 	hVM              block.HeightIndexedChainVM
 	ssVM             block.StateSyncableVM
+	dVM              block.Decoder
+	dbVM             block.DecisionBatcher
+	sdVM             block.StateDiffer
+	mvVM             block.MessageVerifier
+	bbVM             block.BuildBlockWithContextChainVM
 	initializeTag    string
 	buildBlockTag    string
 	parseBlockTag    string
@@ -42,18 +53,33 @@ type blockVM struct {
 	acceptTag        string
 	rejectTag        string
 	optionsTag       string
-	tracer           trace.Tracer
+
+	buildBlockWithContextTag   string
+	shouldVerifyWithContextTag string
+	verifyWithContextTag       string
+
+	tracer trace.Tracer
 }
 
 func NewBlockVM(vm block.ChainVM, name string, tracer trace.Tracer) block.ChainVM {
 	bVM, _ := vm.(block.BatchedChainVM)
 	hVM, _ := vm.(block.HeightIndexedChainVM)
 	ssVM, _ := vm.(block.StateSyncableVM)
+	dVM, _ := vm.(block.Decoder)
+	dbVM, _ := vm.(block.DecisionBatcher)
+	sdVM, _ := vm.(block.StateDiffer)
+	mvVM, _ := vm.(block.MessageVerifier)
+	bbVM, _ := vm.(block.BuildBlockWithContextChainVM)
 	return &blockVM{
 		ChainVM:          vm,
 		bVM:              bVM,
 		hVM:              hVM,
 		ssVM:             ssVM,
+		dVM:              dVM,
+		dbVM:             dbVM,
+		sdVM:             sdVM,
+		mvVM:             mvVM,
+		bbVM:             bbVM,
 		initializeTag:    fmt.Sprintf("%s.initialize", name),
 		buildBlockTag:    fmt.Sprintf("%s.buildBlock", name),
 		parseBlockTag:    fmt.Sprintf("%s.parseBlock", name),
@@ -64,7 +90,12 @@ func NewBlockVM(vm block.ChainVM, name string, tracer trace.Tracer) block.ChainV
 		acceptTag:        fmt.Sprintf("%s.accept", name),
 		rejectTag:        fmt.Sprintf("%s.reject", name),
 		optionsTag:       fmt.Sprintf("%s.options", name),
-		tracer:           tracer,
+
+		buildBlockWithContextTag:   fmt.Sprintf("%s.buildBlockWithContext", name),
+		shouldVerifyWithContextTag: fmt.Sprintf("%s.shouldVerifyWithContext", name),
+		verifyWithContextTag:       fmt.Sprintf("%s.verifyWithContext", name),
+
+		tracer: tracer,
 	}
 }
 
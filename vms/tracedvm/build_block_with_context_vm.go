@@ -0,0 +1,32 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package tracedvm
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	oteltrace "go.opentelemetry.io/otel/trace"
+
+	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
+	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
+)
+
+func (vm *blockVM) BuildBlockWithContext(ctx context.Context, blockCtx *block.Context) (snowman.Block, error) {
+	if vm.bbVM == nil {
+		return vm.BuildBlock(ctx)
+	}
+
+	ctx, span := vm.tracer.Start(ctx, vm.buildBlockWithContextTag, oteltrace.WithAttributes(
+		attribute.Int64("pChainHeight", int64(blockCtx.PChainHeight)),
+	))
+	defer span.End()
+
+	blk, err := vm.bbVM.BuildBlockWithContext(ctx, blockCtx)
+	return &tracedBlock{
+		Block: blk,
+		vm:    vm,
+	}, err
+}
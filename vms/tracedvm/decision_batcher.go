@@ -0,0 +1,19 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package tracedvm
+
+import (
+	"context"
+)
+
+func (vm *blockVM) BatchDecisions(ctx context.Context, f func(context.Context) error) error {
+	if vm.dbVM == nil {
+		return f(ctx)
+	}
+
+	ctx, span := vm.tracer.Start(ctx, "blockVM.BatchDecisions")
+	defer span.End()
+
+	return vm.dbVM.BatchDecisions(ctx, f)
+}
@@ -0,0 +1,41 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package tracedvm
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	oteltrace "go.opentelemetry.io/otel/trace"
+
+	"github.com/ava-labs/avalanchego/snow/engine/avalanche/vertex"
+	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
+)
+
+func (vm *blockVM) DecodeBlock(ctx context.Context, blkBytes []byte) ([]byte, error) {
+	if vm.dVM == nil {
+		return nil, block.ErrDecoderNotImplemented
+	}
+
+	ctx, span := vm.tracer.Start(ctx, "blockVM.DecodeBlock", oteltrace.WithAttributes(
+		attribute.Int("blockLen", len(blkBytes)),
+	))
+	defer span.End()
+
+	return vm.dVM.DecodeBlock(ctx, blkBytes)
+}
+
+func (vm *vertexVM) DecodeTx(ctx context.Context, txBytes []byte) ([]byte, error) {
+	if vm.dVM == nil {
+		return nil, vertex.ErrDecoderNotImplemented
+	}
+
+	ctx, span := vm.tracer.Start(ctx, "vertexVM.DecodeTx", oteltrace.WithAttributes(
+		attribute.Int("txLen", len(txBytes)),
+	))
+	defer span.End()
+
+	return vm.dVM.DecodeTx(ctx, txBytes)
+}
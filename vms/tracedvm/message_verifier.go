@@ -0,0 +1,27 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package tracedvm
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
+
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+func (vm *blockVM) VerifyMessage(ctx context.Context, msg []byte) error {
+	if vm.mvVM == nil {
+		return block.ErrMessageVerifierNotImplemented
+	}
+
+	ctx, span := vm.tracer.Start(ctx, "blockVM.VerifyMessage", oteltrace.WithAttributes(
+		attribute.Int("messageLen", len(msg)),
+	))
+	defer span.End()
+
+	return vm.mvVM.VerifyMessage(ctx, msg)
+}
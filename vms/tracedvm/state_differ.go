@@ -0,0 +1,33 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package tracedvm
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
+
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+func (vm *blockVM) GetStateDiff(
+	ctx context.Context,
+	startHeight uint64,
+	endHeight uint64,
+	f func(key []byte, value []byte) error,
+) error {
+	if vm.sdVM == nil {
+		return block.ErrStateDifferNotImplemented
+	}
+
+	ctx, span := vm.tracer.Start(ctx, "blockVM.GetStateDiff", oteltrace.WithAttributes(
+		attribute.Int64("startHeight", int64(startHeight)),
+		attribute.Int64("endHeight", int64(endHeight)),
+	))
+	defer span.End()
+
+	return vm.sdVM.GetStateDiff(ctx, startHeight, endHeight, f)
+}
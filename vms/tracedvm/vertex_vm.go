@@ -19,16 +19,22 @@ import (
 	"github.com/ava-labs/avalanchego/trace"
 )
 
-var _ vertex.DAGVM = (*vertexVM)(nil)
+var (
+	_ vertex.DAGVM   = (*vertexVM)(nil)
+	_ vertex.Decoder = (*vertexVM)(nil)
+)
 
 type vertexVM struct {
 	vertex.DAGVM
+	dVM    vertex.Decoder
 	tracer trace.Tracer
 }
 
 func NewVertexVM(vm vertex.DAGVM, tracer trace.Tracer) vertex.DAGVM {
+	dVM, _ := vm.(vertex.Decoder)
 	return &vertexVM{
 		DAGVM:  vm,
+		dVM:    dVM,
 		tracer: tracer,
 	}
 }